@@ -0,0 +1,86 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/p2p/allowlist"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/spf13/cobra"
+)
+
+func (c *command) initAllowlistAttestCmd() (err error) {
+	cmd := &cobra.Command{
+		Use:   "allowlist-attest <overlay...>",
+		Short: "Attest the overlay addresses of the nodes of an allowlisted swarm",
+		Long: `Attest the overlay addresses of the nodes of an allowlisted swarm
+
+Signs the overlay addresses given as arguments with the node swarm key,
+which acts as the authority of the swarm, and prints the attestations.
+A node advertises its attestation with the --allowlist-attestation option
+and is accepted by the nodes which set the Ethereum address of the
+authority with the --allowlist-authority option.`,
+		Example: `
+$> bee allowlist-attest --network-id 1 36b7efd913ca4cf880b8eeac5093fa27b0825906c600685b6abdd6566e6cfe8f`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+
+			overlays := make([]swarm.Address, 0, len(args))
+			for _, a := range args {
+				overlay, err := swarm.ParseHexAddress(a)
+				if err != nil {
+					return fmt.Errorf("invalid overlay address %q: %w", a, err)
+				}
+				overlays = append(overlays, overlay)
+			}
+
+			networkID := defaultTestNetworkID
+			if c.config.IsSet(optionNameNetworkID) {
+				networkID = c.config.GetUint64(optionNameNetworkID)
+			} else if c.config.GetBool(optionNameMainNet) {
+				networkID = defaultMainNetworkID
+			}
+
+			v := strings.ToLower(c.config.GetString(optionNameVerbosity))
+			logger, err := newLogger(cmd, v)
+			if err != nil {
+				return fmt.Errorf("new logger: %w", err)
+			}
+			signerConfig, err := c.configureSigner(cmd, logger)
+			if err != nil {
+				return err
+			}
+
+			authority, err := signerConfig.signer.EthereumAddress()
+			if err != nil {
+				return err
+			}
+			cmd.Println("authority:", authority.Hex())
+
+			for _, overlay := range overlays {
+				attestation, err := allowlist.Attest(signerConfig.signer, overlay, networkID)
+				if err != nil {
+					return fmt.Errorf("attest %s: %w", overlay, err)
+				}
+				cmd.Println(overlay.String() + "\t" + hex.EncodeToString(attestation))
+			}
+			return nil
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return c.bindAllFlags(cmd)
+		},
+	}
+
+	c.setAllFlags(cmd)
+
+	cmd.SetOut(c.root.OutOrStdout())
+	c.root.AddCommand(cmd)
+	return nil
+}
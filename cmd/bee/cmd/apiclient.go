@@ -0,0 +1,85 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const optionNameAPIEndpoint = "endpoint"
+
+// apiClient is a minimal client of the node HTTP API
+// used by the commands which operate on a running node.
+type apiClient struct {
+	endpoint string
+	client   *http.Client
+}
+
+func newAPIClient(endpoint string, client *http.Client) *apiClient {
+	return &apiClient{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   client,
+	}
+}
+
+// apiError is the error returned by the node API.
+type apiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *apiError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.Code)
+	}
+	return fmt.Sprintf("%d %s", e.Code, msg)
+}
+
+// do sends the request and returns the response if the status code is
+// successful. Otherwise the response body is closed and an *apiError
+// is returned.
+func (c *apiClient) do(ctx context.Context, method, path string, body io.Reader, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		e := &apiError{Code: resp.StatusCode}
+		_ = json.NewDecoder(resp.Body).Decode(e)
+		return nil, e
+	}
+	return resp, nil
+}
+
+// requestJSON sends the request and decodes the response body into v, if not nil.
+func (c *apiClient) requestJSON(ctx context.Context, method, path string, body io.Reader, header http.Header, v interface{}) error {
+	resp, err := c.do(ctx, method, path, body, header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (c *apiClient) getJSON(ctx context.Context, path string, v interface{}) error {
+	return c.requestJSON(ctx, http.MethodGet, path, nil, nil, v)
+}
@@ -13,9 +13,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/challenge"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/node"
+	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/ethersphere/bee/pkg/replica"
+	"github.com/ethersphere/bee/pkg/shaping"
+	"github.com/ethersphere/bee/pkg/spam"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology/depthmonitor"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -23,6 +30,7 @@ import (
 const (
 	optionNameDataDir                    = "data-dir"
 	optionNameCacheCapacity              = "cache-capacity"
+	optionNameExpiredBatchRetention      = "expired-batch-retention"
 	optionNameDBOpenFilesLimit           = "db-open-files-limit"
 	optionNameDBBlockCacheCapacity       = "db-block-cache-capacity"
 	optionNameDBWriteBufferSize          = "db-write-buffer-size"
@@ -83,6 +91,90 @@ const (
 	optionNameAdminPasswordHash          = "admin-password"
 	optionNameUsePostageSnapshot         = "use-postage-snapshot"
 	optionNameStorageIncentivesEnable    = "storage-incentives-enable"
+	optionNameTopologySnapshotDir        = "topology-snapshot-dir"
+	optionNameTopologySnapshotInterval   = "topology-snapshot-interval"
+	optionNameProfile                    = "profile"
+	optionNameSystemdNotify              = "systemd-notify"
+	optionNameLogJournald                = "log-journald"
+	optionNameReserveBootstrap           = "reserve-bootstrap"
+	optionNameReserveSnapshotServe       = "reserve-snapshot-serve"
+	optionNamePinExpiryWebhooks          = "pin-expiry-webhook"
+	optionNamePostageEventWebhooks       = "postage-event-webhook"
+	optionNamePostageExpiryWarning       = "postage-expiry-warning"
+	optionNamePssMinDelay                = "pss-min-delay"
+	optionNamePssMaxDelay                = "pss-max-delay"
+	optionNamePssCoverInterval           = "pss-cover-interval"
+	optionNamePssCoverBudget             = "pss-cover-budget"
+	optionNameAllowlistOverlays          = "allowlist-overlays"
+	optionNameAllowlistUnderlays         = "allowlist-underlays"
+	optionNameAllowlistAuthority         = "allowlist-authority"
+	optionNameAllowlistAttestation       = "allowlist-attestation"
+	optionNameAuditLog                   = "audit-log"
+	optionNameAuditLogMaxSize            = "audit-log-max-size"
+	optionNameAuditLogMaxBackups         = "audit-log-max-backups"
+	optionNameAuditLogRedact             = "audit-log-redact"
+	optionNameAuditLogTrustedProxies     = "audit-log-trusted-proxies"
+	optionNameProfilingEndpoint          = "profiling-endpoint"
+	optionNameProfilingTypes             = "profiling-types"
+	optionNameProfilingInterval          = "profiling-interval"
+	optionNameProfilingLabels            = "profiling-labels"
+	optionNameSharedCache                = "shared-cache"
+	optionNameSharedCacheTTL             = "shared-cache-ttl"
+	optionNameSharedStateStore           = "shared-state-store"
+	optionNameImageTransform             = "image-transform"
+	optionNameTransformCacheSize         = "transform-cache-size"
+	optionNameRetrievalFallbackGateways  = "retrieval-fallback-gateways"
+	optionNameRetrievalFallbackBudget    = "retrieval-fallback-budget"
+	optionNameInteractiveWorkers         = "retrieval-interactive-workers"
+	optionNameInteractiveBudget          = "retrieval-interactive-budget"
+	optionNameBackgroundWorkers          = "retrieval-background-workers"
+	optionNameBackgroundBudget           = "retrieval-background-budget"
+	optionNameIPFSGateway                = "ipfs-gateway"
+	optionNameChallengeMode              = "challenge-mode"
+	optionNameChallengeDifficulty        = "challenge-difficulty"
+	optionNameChallengeTokenTTL          = "challenge-token-ttl"
+	optionNameChallengeRate              = "challenge-rate"
+	optionNameChallengeBurst             = "challenge-burst"
+	optionNameChallengeRoutes            = "challenge-routes"
+	optionNameChallengeExempt            = "challenge-exempt"
+	optionNameChallengeSecret            = "challenge-secret"
+	optionNameMaxConcurrentDownloads     = "max-concurrent-downloads"
+	optionNameClientConcurrentDownloads  = "client-concurrent-downloads"
+	optionNameRefConcurrentDownloads     = "reference-concurrent-downloads"
+	optionNameDownloadQueueSize          = "download-queue-size"
+	optionNameDownloadQueueTimeout       = "download-queue-timeout"
+	optionNameDownloadClientBandwidth    = "download-client-bandwidth"
+	optionNameDownloadClientBurst        = "download-client-burst"
+	optionNameMaxDownloadJoiners         = "max-download-joiners"
+	optionNameBatchPruneInterval         = "batch-prune-interval"
+	optionNameSpamDetection              = "spam-detection"
+	optionNameSpamWindow                 = "spam-window"
+	optionNameSpamMaxRate                = "spam-max-rate"
+	optionNameSpamMaxSkew                = "spam-max-skew"
+	optionNameSpamDeprioritize           = "spam-deprioritize"
+	optionNameDepthDecreaseThreshold     = "depth-decrease-threshold"
+	optionNameDepthCooldown              = "depth-cooldown"
+	optionNameDepthConfirmations         = "depth-confirmations"
+	optionNameReplicaPrimaryAPI          = "replica-primary-api"
+	optionNameReplicaInterval            = "replica-interval"
+	optionNameReplicaToken               = "replica-token"
+	optionNameAPILookahead               = "api-lookahead"
+	optionNameAPICompressionTypes        = "api-compression-types"
+	optionNameAPICompressionMinSize      = "api-compression-min-size"
+	optionNameMemoryBudget               = "memory-budget"
+	optionNameContainerLimits            = "container-limits"
+	optionNameLowPower                   = "low-power"
+	optionNameMaintenanceWindows         = "maintenance-windows"
+	optionNameDBCompactionInterval       = "db-compaction-interval"
+	optionNameCashoutInterval            = "cashout-interval"
+	optionNameCashoutMinAmount           = "cashout-min-amount"
+	optionNameGraphQLEnable              = "graphql-enable"
+	optionNameDNSLinkProvider            = "dnslink-provider"
+	optionNameDNSLinkFeeds               = "dnslink-feeds"
+	optionNameDNSLinkInterval            = "dnslink-interval"
+	optionNameEnableDirListing           = "enable-dir-listing"
+	optionNameGatewayDomain              = "gateway-domain"
+	optionNameGatewaySubdomainOnly       = "gateway-subdomain-only"
 )
 
 // nolint:gochecknoinits
@@ -152,8 +244,44 @@ func newCommand(opts ...option) (c *command, err error) {
 		return nil, err
 	}
 
+	if err := c.initDNSTreeCmd(); err != nil {
+		return nil, err
+	}
+
+	if err := c.initAllowlistAttestCmd(); err != nil {
+		return nil, err
+	}
+
+	if err := c.initTestVectorsCmd(); err != nil {
+		return nil, err
+	}
+
+	if err := c.initMonitorCmd(); err != nil {
+		return nil, err
+	}
+
+	if err := c.initUploadCmd(); err != nil {
+		return nil, err
+	}
+
+	if err := c.initDownloadCmd(); err != nil {
+		return nil, err
+	}
+
+	if err := c.initExportCmd(); err != nil {
+		return nil, err
+	}
+
+	if err := c.initDoctorCmd(); err != nil {
+		return nil, err
+	}
+
 	c.initVersionCmd()
 	c.initDBCmd()
+	c.initStampsCmd()
+	c.initKeysCmd()
+	c.initOverlayCmd()
+	c.initServiceCmd()
 
 	if err := c.initConfigurateOptionsCmd(); err != nil {
 		return nil, err
@@ -238,6 +366,7 @@ func (c *command) setHomeDir() (err error) {
 func (c *command) setAllFlags(cmd *cobra.Command) {
 	cmd.Flags().String(optionNameDataDir, filepath.Join(c.homeDir, ".bee"), "data directory")
 	cmd.Flags().Uint64(optionNameCacheCapacity, 1000000, fmt.Sprintf("cache capacity in chunks, multiply by %d to get approximate capacity in bytes", swarm.ChunkSize))
+	cmd.Flags().Duration(optionNameExpiredBatchRetention, 0, "period to retain the chunks of the expired batches in the cache, zero disables the retention")
 	cmd.Flags().Uint64(optionNameDBOpenFilesLimit, 200, "number of open files allowed by database")
 	cmd.Flags().Uint64(optionNameDBBlockCacheCapacity, 32*1024*1024, "size of block cache of the database in bytes")
 	cmd.Flags().Uint64(optionNameDBWriteBufferSize, 32*1024*1024, "size of the database write buffer in bytes")
@@ -248,7 +377,7 @@ func (c *command) setAllFlags(cmd *cobra.Command) {
 	cmd.Flags().String(optionNameP2PAddr, ":1634", "P2P listen address")
 	cmd.Flags().String(optionNameNATAddr, "", "NAT exposed address")
 	cmd.Flags().Bool(optionNameP2PWSEnable, false, "enable P2P WebSocket transport")
-	cmd.Flags().StringSlice(optionNameBootnodes, []string{""}, "initial nodes to connect to")
+	cmd.Flags().StringSlice(optionNameBootnodes, []string{""}, "initial nodes to connect to, multiaddresses or DNS discovery tree URLs (enrtree://<key>@<domain>)")
 	cmd.Flags().Bool(optionNameDebugAPIEnable, false, "enable debug HTTP API")
 	cmd.Flags().String(optionNameDebugAPIAddr, ":1635", "debug HTTP API listen address")
 	cmd.Flags().Uint64(optionNameNetworkID, 1, "ID of the Swarm network")
@@ -296,9 +425,93 @@ func (c *command) setAllFlags(cmd *cobra.Command) {
 	cmd.Flags().String(optionNameAdminPasswordHash, "", "bcrypt hash of the admin password to get the security token")
 	cmd.Flags().Bool(optionNameUsePostageSnapshot, false, "bootstrap node using postage snapshot from the network")
 	cmd.Flags().Bool(optionNameStorageIncentivesEnable, true, "enable storage incentives feature")
+	cmd.Flags().String(optionNameTopologySnapshotDir, "", "directory to periodically dump topology graph snapshots to, disabled if empty")
+	cmd.Flags().Duration(optionNameTopologySnapshotInterval, 10*time.Minute, "interval between topology graph snapshots")
+	cmd.Flags().String(optionNameProfile, "", fmt.Sprintf("profile of option defaults: %s", strings.Join(profileNames(), ", ")))
+	cmd.Flags().Bool(optionNameSystemdNotify, false, "notify systemd about the node state and send watchdog pings")
+	cmd.Flags().Bool(optionNameLogJournald, false, "send structured logs to the systemd journal")
+	cmd.Flags().Bool(optionNameReserveBootstrap, false, "bootstrap an empty reserve from the snapshot of a neighbor")
+	cmd.Flags().Bool(optionNameReserveSnapshotServe, false, "serve the reserve snapshots to the bootstrapping neighbors")
+	cmd.Flags().StringSlice(optionNamePinExpiryWebhooks, []string{}, "URLs to post the expiry events of the pinned references to")
+	cmd.Flags().StringSlice(optionNamePostageEventWebhooks, []string{}, "URLs to post the lifecycle events of the postage batches of the node to")
+	cmd.Flags().Duration(optionNamePostageExpiryWarning, 24*time.Hour, "remaining time to live of the postage batches of the node at which the nearing expiry event is emitted")
+	cmd.Flags().Duration(optionNamePssMinDelay, 0, "minimal random delay of the delivery of the sent pss messages")
+	cmd.Flags().Duration(optionNamePssMaxDelay, 0, "maximal random delay of the delivery of the sent pss messages")
+	cmd.Flags().Duration(optionNamePssCoverInterval, 0, "mean interval of the pss cover messages, disabled if zero")
+	cmd.Flags().Bool(optionNameSpamDetection, false, "flag the batches with anomalous chunk ingress into the reserve")
+	cmd.Flags().Duration(optionNameSpamWindow, spam.DefaultWindow, "window of the chunk ingress of the batches")
+	cmd.Flags().Float64(optionNameSpamMaxRate, spam.DefaultMaxRate, "chunk ingress of a batch in chunks per second above which the batch is flagged")
+	cmd.Flags().Float64(optionNameSpamMaxSkew, spam.DefaultMaxSkew, "share of the chunks of a batch in a single bucket above the uniform share of the buckets of the neighbourhood, normalised to 1, above which the batch is flagged")
+	cmd.Flags().Bool(optionNameSpamDeprioritize, false, "garbage collect the cached chunks of the flagged batches first")
+	cmd.Flags().Float64(optionNameDepthDecreaseThreshold, depthmonitor.DefaultDecreaseThreshold, "share of the reserve capacity below which the storage depth is decreased")
+	cmd.Flags().Duration(optionNameDepthCooldown, 0, "time after a change of the storage depth during which the depth is not decreased")
+	cmd.Flags().Int(optionNameDepthConfirmations, 1, "number of the consecutive checks of the reserve needed to decrease the storage depth")
+	cmd.Flags().String(optionNameReplicaPrimaryAPI, "", "debug API URL of the primary node whose pins, aliases and tags are replicated in the standby mode")
+	cmd.Flags().Duration(optionNameReplicaInterval, replica.DefaultInterval, "interval of the replication of the primary node")
+	cmd.Flags().String(optionNameReplicaToken, "", "bearer token of the restricted debug API of the primary node")
+	cmd.Flags().String(optionNameAPILookahead, "", "lookahead buffer sizes of the downloads as comma separated content-type[:max-size]=buffer-size rules, where the buffer size is in bytes or adaptive")
+	cmd.Flags().String(optionNameAPICompressionTypes, api.DefaultCompressionContentTypes, "comma separated content types or type prefixes of the downloads compressed on the fly with gzip, empty to disable the compression")
+	cmd.Flags().Int64(optionNameAPICompressionMinSize, api.DefaultCompressionMinSize, "size in bytes of the smallest download compressed on the fly")
+	cmd.Flags().Uint64(optionNameMemoryBudget, 0, "memory budget in bytes shared by the database caches, the download and upload buffers and pss, derived from the cgroup memory limit if zero")
+	cmd.Flags().Bool(optionNameContainerLimits, true, "size the threads, the garbage collector target and the database caches to the cpu and memory limits of the container")
+	cmd.Flags().Bool(optionNameLowPower, false, "reduce the pull-sync concurrency, the sampling frequency, the hashing parallelism and the connections for the low-power hardware")
+	cmd.Flags().String(optionNameMaintenanceWindows, "", "semicolon separated windows of the heavy background jobs in the local time as [days ]HH:MM-HH:MM, such as mon-fri 01:00-05:00;sat,sun 00:00-08:00, any time if empty")
+	cmd.Flags().Duration(optionNameDBCompactionInterval, 0, "interval of the compaction of the database within the maintenance windows, disabled if zero")
+	cmd.Flags().Duration(optionNameCashoutInterval, 0, "interval of the cashout of the received cheques within the maintenance windows, disabled if zero")
+	cmd.Flags().String(optionNameCashoutMinAmount, "0", "minimum uncashed amount of the cheques of a peer which is cashed out")
+	cmd.Flags().Bool(optionNameGraphQLEnable, false, "enable the GraphQL queries of the node status, peers, pins, stamps and tags on the debug API")
+	cmd.Flags().Int(optionNamePssCoverBudget, pss.DefaultCoverBudget, "maximum number of the pss cover messages sent in an hour")
+	cmd.Flags().StringSlice(optionNameAllowlistOverlays, []string{}, "overlay addresses of the only peers the node connects to, together with the other allowlist options")
+	cmd.Flags().StringSlice(optionNameAllowlistUnderlays, []string{}, "IP addresses or CIDR networks of the only peers the node connects to, together with the other allowlist options")
+	cmd.Flags().String(optionNameAllowlistAuthority, "", "ethereum address of the authority whose attestations of the peer overlay addresses are accepted")
+	cmd.Flags().String(optionNameAllowlistAttestation, "", "hex encoded authority attestation of the node overlay address advertised to the peers")
+	cmd.Flags().String(optionNameAuditLog, "", "file path or HTTP URL of the audit log of the API operations, disabled if empty")
+	cmd.Flags().Int64(optionNameAuditLogMaxSize, 100, "size in megabytes at which the audit log file is rotated")
+	cmd.Flags().Int(optionNameAuditLogMaxBackups, 5, "number of the rotated audit log files to keep")
+	cmd.Flags().StringSlice(optionNameAuditLogRedact, []string{}, "fields redacted from the audit log: caller, reference, batch")
+	cmd.Flags().StringSlice(optionNameAuditLogTrustedProxies, []string{}, "IP addresses or CIDR ranges of the reverse proxies whose X-Forwarded-For header is trusted for the caller recorded in the audit log")
+	cmd.Flags().String(optionNameProfilingEndpoint, "", "URL of the continuous profiling server to push the profiles to, disabled if empty")
+	cmd.Flags().StringSlice(optionNameProfilingTypes, []string{"cpu", "heap", "mutex"}, "profiles pushed to the profiling server: cpu, heap, mutex")
+	cmd.Flags().Duration(optionNameProfilingInterval, 15*time.Second, "interval of the profiles pushed to the profiling server")
+	cmd.Flags().StringSlice(optionNameProfilingLabels, []string{}, "additional key=value labels of the pushed profiles")
+	cmd.Flags().String(optionNameSharedCache, "", "redis:// or memcache:// URL of the chunk cache shared by the gateway nodes, disabled if empty")
+	cmd.Flags().Duration(optionNameSharedCacheTTL, 24*time.Hour, "expiration time of the chunks in the shared cache")
+	cmd.Flags().String(optionNameSharedStateStore, "", "redis:// URL of the store of the tags and idempotent responses shared by the API frontends, disabled if empty")
+	cmd.Flags().Bool(optionNameImageTransform, false, "resize and convert the images served from manifests by the w, h, format and quality query parameters")
+	cmd.Flags().Uint64(optionNameTransformCacheSize, 256*1024*1024, "size of the cache of the transformed content in bytes")
+	cmd.Flags().StringSlice(optionNameRetrievalFallbackGateways, []string{}, "https:// URLs of the trusted gateways the chunks are fetched from when the retrieval from the network fails, disabled if empty")
+	cmd.Flags().Duration(optionNameRetrievalFallbackBudget, 10*time.Second, "time the retrieval from the network is given before falling back to the gateways")
+	cmd.Flags().Int(optionNameInteractiveWorkers, 0, "number of the requests to the peers made at once by the interactive retrievals, unlimited if zero")
+	cmd.Flags().Uint64(optionNameInteractiveBudget, 0, "sum of the prices of the chunks reserved at once by the interactive retrievals, unlimited if zero")
+	cmd.Flags().Int(optionNameBackgroundWorkers, 0, "number of the requests to the peers made at once by the background retrievals, unlimited if zero")
+	cmd.Flags().Uint64(optionNameBackgroundBudget, 0, "sum of the prices of the chunks reserved at once by the background retrievals, unlimited if zero")
+	cmd.Flags().String(optionNameIPFSGateway, "", "http(s):// URL of the IPFS gateway the content is imported from, disabled if empty")
+	cmd.Flags().String(optionNameChallengeMode, "", "challenge of the anonymous downloads, pow or token, disabled if empty")
+	cmd.Flags().Uint(optionNameChallengeDifficulty, challenge.DefaultDifficulty, "number of the leading zero bits of the proof of work challenges")
+	cmd.Flags().Duration(optionNameChallengeTokenTTL, challenge.DefaultTokenTTL, "time the token of a passed challenge is valid")
+	cmd.Flags().Float64(optionNameChallengeRate, challenge.DefaultRate, "number of the downloads per second with a challenge token")
+	cmd.Flags().Int(optionNameChallengeBurst, challenge.DefaultBurst, "number of the downloads at once with a challenge token")
+	cmd.Flags().StringSlice(optionNameChallengeRoutes, []string{"bytes", "bzz", "chunks", "feeds"}, "download endpoints protected by the challenges")
+	cmd.Flags().StringSlice(optionNameChallengeExempt, []string{}, "IP addresses and CIDR networks of the clients which are not challenged")
+	cmd.Flags().String(optionNameChallengeSecret, "", "hex encoded secret of the challenges shared by the API frontends, random if empty")
+	cmd.Flags().Int(optionNameMaxConcurrentDownloads, 0, "number of the downloads served at once, unlimited if zero")
+	cmd.Flags().Int(optionNameClientConcurrentDownloads, 0, "number of the downloads of a client served at once, unlimited if zero")
+	cmd.Flags().Int(optionNameRefConcurrentDownloads, 0, "number of the downloads of a reference served at once, unlimited if zero")
+	cmd.Flags().Int(optionNameDownloadQueueSize, shaping.DefaultQueueSize, "number of the downloads waiting for their turn")
+	cmd.Flags().Duration(optionNameDownloadQueueTimeout, shaping.DefaultQueueTimeout, "time a download waits for its turn")
+	cmd.Flags().Int64(optionNameDownloadClientBandwidth, 0, "number of the bytes per second sent to a client, unlimited if zero")
+	cmd.Flags().Int64(optionNameDownloadClientBurst, 0, "number of the bytes sent to a client at once, the client bandwidth if zero")
+	cmd.Flags().Int(optionNameMaxDownloadJoiners, 0, "number of the downloads retrieving their content at once, the others are rejected, unlimited if zero")
+	cmd.Flags().Duration(optionNameBatchPruneInterval, time.Hour, "interval of the pruning of the expired batches within the maintenance windows, disabled if zero")
+	cmd.Flags().String(optionNameDNSLinkProvider, "", "cloudflare://, route53:// or rfc2136:// URL of the DNS provider the DNSLink TXT records of the domains bound to the feeds are updated through, disabled if empty")
+	cmd.Flags().StringSlice(optionNameDNSLinkFeeds, []string{}, "feeds bound to the domains whose DNSLink records point at their latest content as owner:topic=domain")
+	cmd.Flags().Duration(optionNameDNSLinkInterval, 0, "interval of the lookup of the feeds bound to the domains to follow the updates made through other nodes, disabled if zero")
+	cmd.Flags().Bool(optionNameEnableDirListing, false, "serve the listings of the directories of the collections without the index documents as html or json")
+	cmd.Flags().String(optionNameGatewayDomain, api.DefaultGatewayDomain, "base domain of the gateway which serves the bzz content with the reference, cid or ens name as the subdomain")
+	cmd.Flags().Bool(optionNameGatewaySubdomainOnly, false, "serve the bzz content only on the subdomains of the gateway domain, isolating the origins of the websites")
 }
 
-func newLogger(cmd *cobra.Command, verbosity string) (log.Logger, error) {
+func newLogger(cmd *cobra.Command, verbosity string, opts ...log.Option) (log.Logger, error) {
 	var (
 		sink   = cmd.OutOrStdout()
 		vLevel = log.VerbosityNone
@@ -328,8 +541,10 @@ func newLogger(cmd *cobra.Command, verbosity string) (log.Logger, error) {
 
 	return log.NewLogger(
 		node.LoggerName,
-		log.WithSink(sink),
-		log.WithVerbosity(vLevel),
+		append([]log.Option{
+			log.WithSink(sink),
+			log.WithVerbosity(vLevel),
+		}, opts...)...,
 	).Register(), nil
 }
 
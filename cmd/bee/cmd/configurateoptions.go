@@ -44,7 +44,7 @@ func (c *command) initConfigurateOptionsCmd() (err error) {
 			return nil
 		},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			return c.config.BindPFlags(cmd.Flags())
+			return c.bindAllFlags(cmd)
 		},
 	}
 
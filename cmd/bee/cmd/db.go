@@ -5,6 +5,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -14,7 +15,9 @@ import (
 	"time"
 
 	"github.com/ethersphere/bee/pkg/localstore"
+	"github.com/ethersphere/bee/pkg/postage/batchstore"
 	"github.com/ethersphere/bee/pkg/statestore/leveldb"
+	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/spf13/cobra"
 )
 
@@ -33,6 +36,7 @@ func (c *command) initDBCmd() {
 	dbImportCmd(cmd)
 	dbNukeCmd(cmd)
 	dbIndicesCmd(cmd)
+	dbPruneBatchesCmd(cmd)
 
 	c.root.AddCommand(cmd)
 }
@@ -89,6 +93,83 @@ func dbIndicesCmd(cmd *cobra.Command) {
 	cmd.AddCommand(c)
 }
 
+func dbPruneBatchesCmd(cmd *cobra.Command) {
+	c := &cobra.Command{
+		Use:   "prune-batches",
+		Short: "Removes the expired batches and their chunks and rebuilds the batch value index",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			start := time.Now()
+			v, err := cmd.Flags().GetString(optionNameVerbosity)
+			if err != nil {
+				return fmt.Errorf("get verbosity: %w", err)
+			}
+			v = strings.ToLower(v)
+			logger, err := newLogger(cmd, v)
+			if err != nil {
+				return fmt.Errorf("new logger: %w", err)
+			}
+
+			dataDir, err := cmd.Flags().GetString(optionNameDataDir)
+			if err != nil {
+				return fmt.Errorf("get data-dir: %w", err)
+			}
+			if dataDir == "" {
+				return errors.New("no data-dir provided")
+			}
+
+			logger.Info("pruning batches with data-dir", "path", dataDir)
+
+			stateStore, err := leveldb.NewStateStore(filepath.Join(dataDir, "statestore"), logger)
+			if err != nil {
+				return fmt.Errorf("new statestore: %w", err)
+			}
+			defer stateStore.Close()
+
+			storer, err := localstore.New(filepath.Join(dataDir, "localstore"), nil, nil, nil, logger)
+			if err != nil {
+				return fmt.Errorf("localstore: %w", err)
+			}
+			defer storer.Close()
+
+			// the chunks are evicted once the batch store is pruned,
+			// as the eviction must not hold the batch store lock
+			var expired [][]byte
+			evictFn := func(id []byte) error {
+				expired = append(expired, id)
+				return nil
+			}
+
+			batchStore, err := batchstore.New(stateStore, evictFn, swarm.ZeroAddress, logger)
+			if err != nil {
+				return fmt.Errorf("batchstore: %w", err)
+			}
+
+			p, err := batchStore.(batchstore.Pruner).Prune(context.Background(), func(p batchstore.PruneProgress) {
+				if p.Checked%1000 == 0 {
+					logger.Info("pruning batches", "checked", p.Checked, "expired", p.Expired, "rebuilt", p.Rebuilt)
+				}
+			})
+			if err != nil {
+				return fmt.Errorf("prune batches: %w", err)
+			}
+
+			for i, id := range expired {
+				if err := storer.EvictBatchNow(id); err != nil {
+					return fmt.Errorf("evict batch %x: %w", id, err)
+				}
+				logger.Info("evicted batch", "batch_id", swarm.NewAddress(id), "progress", fmt.Sprintf("%d/%d", i+1, len(expired)))
+			}
+
+			logger.Info("done", "checked", p.Checked, "expired", p.Expired, "stale", p.Stale, "rebuilt", p.Rebuilt, "elapsed", time.Since(start))
+
+			return nil
+		},
+	}
+	c.Flags().String(optionNameDataDir, "", "data directory")
+	c.Flags().String(optionNameVerbosity, "info", "verbosity level")
+	cmd.AddCommand(c)
+}
+
 func dbExportCmd(cmd *cobra.Command) {
 	c := &cobra.Command{
 		Use:   "export <filename>",
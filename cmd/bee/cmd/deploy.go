@@ -110,7 +110,7 @@ func (c *command) initDeployCmd() error {
 			return err
 		},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			return c.config.BindPFlags(cmd.Flags())
+			return c.bindAllFlags(cmd)
 		},
 	}
 
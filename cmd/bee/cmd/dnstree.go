@@ -0,0 +1,105 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/p2p/dnsdisc"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/spf13/cobra"
+)
+
+const (
+	optionNameDNSTreeDomain = "domain"
+	optionNameDNSTreeSeq    = "seq"
+	optionNameDNSTreeLink   = "link"
+	optionNameDNSTreeTTL    = "ttl"
+)
+
+func (c *command) initDNSTreeCmd() (err error) {
+	cmd := &cobra.Command{
+		Use:   "dns-tree [multiaddr...]",
+		Short: "Generate a signed DNS discovery tree of bootnodes",
+		Long: `Generate a signed DNS discovery tree of bootnodes
+
+Takes the bootnode underlay multiaddresses as arguments and prints the
+DNS TXT records, in the zone file format, which have to be published
+under the given domain. The tree root is signed with the node swarm key.
+Nodes use the tree by setting the printed enrtree:// URL as a bootnode.`,
+		Example: `
+$> bee dns-tree --domain nodes.example.org --seq 1 /dnsaddr/bootnode.example.org`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			domain := c.config.GetString(optionNameDNSTreeDomain)
+			if domain == "" {
+				return errors.New("no domain provided")
+			}
+
+			addrs := make([]ma.Multiaddr, 0, len(args))
+			for _, a := range args {
+				addr, err := ma.NewMultiaddr(a)
+				if err != nil {
+					return fmt.Errorf("invalid multiaddress %q: %w", a, err)
+				}
+				addrs = append(addrs, addr)
+			}
+
+			tree, err := dnsdisc.MakeTree(c.config.GetUint(optionNameDNSTreeSeq), addrs, c.config.GetStringSlice(optionNameDNSTreeLink))
+			if err != nil {
+				return fmt.Errorf("make tree: %w", err)
+			}
+
+			v := strings.ToLower(c.config.GetString(optionNameVerbosity))
+			logger, err := newLogger(cmd, v)
+			if err != nil {
+				return fmt.Errorf("new logger: %w", err)
+			}
+			signerConfig, err := c.configureSigner(cmd, logger)
+			if err != nil {
+				return err
+			}
+
+			url, err := tree.Sign(signerConfig.signer, domain)
+			if err != nil {
+				return err
+			}
+			records, err := tree.Records(domain)
+			if err != nil {
+				return err
+			}
+
+			names := make([]string, 0, len(records))
+			for name := range records {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			ttl := strconv.Itoa(c.config.GetInt(optionNameDNSTreeTTL))
+			for _, name := range names {
+				cmd.Println(name + ".\t" + ttl + "\tIN\tTXT\t" + strconv.Quote(records[name]))
+			}
+			cmd.Println()
+			cmd.Println("; tree url:", url)
+			return nil
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return c.bindAllFlags(cmd)
+		},
+	}
+
+	c.setAllFlags(cmd)
+	cmd.Flags().String(optionNameDNSTreeDomain, "", "domain under which the tree is published")
+	cmd.Flags().Uint(optionNameDNSTreeSeq, 1, "tree sequence number, must be increased on every update")
+	cmd.Flags().StringSlice(optionNameDNSTreeLink, nil, "links to other trees in the enrtree://<key>@<domain> form")
+	cmd.Flags().Int(optionNameDNSTreeTTL, 3600, "time to live of the records in seconds")
+
+	cmd.SetOut(c.root.OutOrStdout())
+	c.root.AddCommand(cmd)
+	return nil
+}
@@ -0,0 +1,449 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethersphere/bee/pkg/postage/batchstore"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/spf13/cobra"
+)
+
+const optionNameDoctorNTPServer = "ntp-server"
+
+const (
+	doctorCheckTimeout  = 10 * time.Second
+	doctorDiskTestSize  = 32 * 1024 * 1024
+	doctorMinOpenFiles  = 4096
+	doctorMaxClockSkew  = time.Second
+	doctorSlowRPC       = 2 * time.Second
+	doctorSlowDiskSpeed = 50 * 1024 * 1024 // bytes per second
+)
+
+// errNotSupported is returned by the checks which are not
+// supported on the operating system.
+var errNotSupported = errors.New("not supported on this operating system")
+
+func (c *command) initDoctorCmd() (err error) {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the environment of a node before starting it",
+		Long: `Check the environment of a node before starting it
+
+Runs a set of diagnostics with the same options as the start command: the
+blockchain RPC endpoint and its chain ID, the clock skew against an NTP server,
+the reachability of the P2P port through the NAT address, the free space and
+the write speed of the data directory, and the open files limit. A fix is
+suggested for every problem found and the command fails if any check fails.
+The P2P port is checked by listening on it, so the node must not be running.`,
+		PersistentPreRunE: c.CheckUnknownParams,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return cmd.Help()
+			}
+
+			networkID := defaultTestNetworkID
+			if c.config.IsSet(optionNameNetworkID) {
+				networkID = c.config.GetUint64(optionNameNetworkID)
+			} else if c.config.GetBool(optionNameMainNet) {
+				networkID = defaultMainNetworkID
+			}
+			rpcEndpoint := c.config.GetString(optionNameBlockchainRpcEndpoint)
+			if e := c.config.GetString(optionNameSwapEndpoint); e != "" {
+				rpcEndpoint = e
+			}
+
+			d := &doctor{
+				rpcEndpoint:   rpcEndpoint,
+				chainRequired: c.config.GetBool(optionNameSwapEnable) || c.config.GetBool(optionNameFullNode),
+				chainID:       getConfigByNetworkID(networkID, 0).chainID,
+				networkID:     networkID,
+				ntpServer:     c.config.GetString(optionNameDoctorNTPServer),
+				p2pAddr:       c.config.GetString(optionNameP2PAddr),
+				natAddr:       c.config.GetString(optionNameNATAddr),
+				dataDir:       c.config.GetString(optionNameDataDir),
+				cacheCapacity: c.config.GetUint64(optionNameCacheCapacity),
+				fullNode:      c.config.GetBool(optionNameFullNode),
+			}
+
+			failed := 0
+			for _, check := range d.checks() {
+				ctx, cancel := context.WithTimeout(cmd.Context(), doctorCheckTimeout)
+				r := check.run(ctx)
+				cancel()
+
+				cmd.Printf("%-4s  %s: %s\n", r.status, check.name, r.message)
+				if r.fix != "" {
+					cmd.Printf("      fix: %s\n", r.fix)
+				}
+				if r.status == doctorFail {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d checks failed", failed)
+			}
+			return nil
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return c.bindAllFlags(cmd)
+		},
+	}
+
+	c.setAllFlags(cmd)
+	cmd.Flags().String(optionNameDoctorNTPServer, "pool.ntp.org:123", "NTP server to measure the clock skew against")
+
+	cmd.SetOut(c.root.OutOrStdout())
+	c.root.AddCommand(cmd)
+	return nil
+}
+
+type doctorStatus string
+
+const (
+	doctorOK   doctorStatus = "OK"
+	doctorWarn doctorStatus = "WARN"
+	doctorFail doctorStatus = "FAIL"
+	doctorSkip doctorStatus = "SKIP"
+)
+
+type doctorResult struct {
+	status  doctorStatus
+	message string
+	fix     string
+}
+
+type doctorCheck struct {
+	name string
+	run  func(ctx context.Context) doctorResult
+}
+
+type doctor struct {
+	rpcEndpoint   string
+	chainRequired bool
+	chainID       int64
+	networkID     uint64
+	ntpServer     string
+	p2pAddr       string
+	natAddr       string
+	dataDir       string
+	cacheCapacity uint64
+	fullNode      bool
+}
+
+func (d *doctor) checks() []doctorCheck {
+	return []doctorCheck{
+		{name: "blockchain rpc", run: d.checkRPC},
+		{name: "clock skew", run: d.checkClock},
+		{name: "p2p port", run: d.checkP2PPort},
+		{name: "disk space", run: d.checkDiskSpace},
+		{name: "disk speed", run: d.checkDiskSpeed},
+		{name: "open files limit", run: d.checkOpenFiles},
+	}
+}
+
+func (d *doctor) checkRPC(ctx context.Context) doctorResult {
+	if d.rpcEndpoint == "" {
+		if !d.chainRequired {
+			return doctorResult{status: doctorSkip, message: "no endpoint, the node runs without the blockchain"}
+		}
+		return doctorResult{
+			status:  doctorFail,
+			message: "no endpoint configured",
+			fix:     fmt.Sprintf("set --%s to the endpoint of a node of the Gnosis Chain", optionNameBlockchainRpcEndpoint),
+		}
+	}
+
+	start := time.Now()
+	client, err := rpc.DialContext(ctx, d.rpcEndpoint)
+	if err != nil {
+		return doctorResult{
+			status:  doctorFail,
+			message: fmt.Sprintf("dial %s: %v", d.rpcEndpoint, err),
+			fix:     fmt.Sprintf("check the --%s value and that the endpoint is reachable", optionNameBlockchainRpcEndpoint),
+		}
+	}
+	defer client.Close()
+	ethClient := ethclient.NewClient(client)
+
+	chainID, err := ethClient.ChainID(ctx)
+	if err != nil {
+		return doctorResult{
+			status:  doctorFail,
+			message: fmt.Sprintf("get chain ID: %v", err),
+			fix:     fmt.Sprintf("check the --%s value and that the endpoint is reachable", optionNameBlockchainRpcEndpoint),
+		}
+	}
+	latency := time.Since(start)
+	if d.chainID != -1 && chainID.Int64() != d.chainID {
+		return doctorResult{
+			status:  doctorFail,
+			message: fmt.Sprintf("chain ID %d, network %d expects %d", chainID, d.networkID, d.chainID),
+			fix:     fmt.Sprintf("use an endpoint of the chain with ID %d or change --%s", d.chainID, optionNameNetworkID),
+		}
+	}
+
+	progress, err := ethClient.SyncProgress(ctx)
+	if err != nil {
+		return doctorResult{status: doctorFail, message: fmt.Sprintf("get sync progress: %v", err)}
+	}
+	if progress != nil {
+		return doctorResult{
+			status:  doctorWarn,
+			message: fmt.Sprintf("chain ID %d, syncing block %d of %d", chainID, progress.CurrentBlock, progress.HighestBlock),
+			fix:     "wait until the blockchain node is synced or use another endpoint",
+		}
+	}
+	if latency > doctorSlowRPC {
+		return doctorResult{
+			status:  doctorWarn,
+			message: fmt.Sprintf("chain ID %d, slow response in %s", chainID, latency.Round(time.Millisecond)),
+			fix:     "use an endpoint closer to the node",
+		}
+	}
+	return doctorResult{status: doctorOK, message: fmt.Sprintf("chain ID %d, response in %s", chainID, latency.Round(time.Millisecond))}
+}
+
+func (d *doctor) checkClock(ctx context.Context) doctorResult {
+	if d.ntpServer == "" {
+		return doctorResult{status: doctorSkip, message: "no NTP server"}
+	}
+	offset, err := ntpOffset(ctx, d.ntpServer)
+	if err != nil {
+		return doctorResult{
+			status:  doctorWarn,
+			message: fmt.Sprintf("query %s: %v", d.ntpServer, err),
+			fix:     fmt.Sprintf("allow outgoing UDP traffic to port 123 or set --%s", optionNameDoctorNTPServer),
+		}
+	}
+	if offset < 0 {
+		offset = -offset
+	}
+	if offset > doctorMaxClockSkew {
+		return doctorResult{
+			status:  doctorFail,
+			message: fmt.Sprintf("local clock is off by %s", offset.Round(time.Millisecond)),
+			fix:     "enable the time synchronization of the system, e.g. with systemd-timesyncd or chrony",
+		}
+	}
+	return doctorResult{status: doctorOK, message: fmt.Sprintf("local clock is off by %s", offset.Round(time.Millisecond))}
+}
+
+// ntpEpochOffset is the number of seconds between the NTP and the Unix epochs.
+const ntpEpochOffset = 2208988800
+
+// ntpOffset returns the offset of the local clock
+// from the clock of the NTP server.
+func ntpOffset(ctx context.Context, server string) (time.Duration, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "123")
+	}
+	conn, err := new(net.Dialer).DialContext(ctx, "udp", server)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return 0, err
+		}
+	}
+
+	req := make([]byte, 48)
+	req[0] = 0x1b // version 3, client mode
+	t1 := time.Now()
+	if _, err := conn.Write(req); err != nil {
+		return 0, err
+	}
+	resp := make([]byte, 48)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return 0, err
+	}
+	t4 := time.Now()
+
+	t2, t3 := ntpTime(resp[32:40]), ntpTime(resp[40:48])
+	return (t2.Sub(t1) + t3.Sub(t4)) / 2, nil
+}
+
+func ntpTime(b []byte) time.Time {
+	sec := binary.BigEndian.Uint32(b[:4])
+	frac := binary.BigEndian.Uint32(b[4:])
+	nsec := uint64(frac) * 1e9 >> 32
+	return time.Unix(int64(sec)-ntpEpochOffset, int64(nsec))
+}
+
+func (d *doctor) checkP2PPort(ctx context.Context) doctorResult {
+	ln, err := net.Listen("tcp", d.p2pAddr)
+	if err != nil {
+		return doctorResult{
+			status:  doctorFail,
+			message: fmt.Sprintf("listen on %s: %v", d.p2pAddr, err),
+			fix:     fmt.Sprintf("stop the process which uses the port or change --%s", optionNameP2PAddr),
+		}
+	}
+	defer ln.Close()
+
+	if d.natAddr == "" {
+		return doctorResult{
+			status:  doctorWarn,
+			message: fmt.Sprintf("listening on %s, reachability from outside not checked", ln.Addr()),
+			fix:     fmt.Sprintf("set --%s to the public address of the node to check it", optionNameNATAddr),
+		}
+	}
+
+	// a random token is sent through the public address
+	// to make sure that it is received by this listener
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return doctorResult{status: doctorFail, message: err.Error()}
+	}
+	received := make(chan struct{})
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			b := make([]byte, len(token))
+			_ = conn.SetReadDeadline(time.Now().Add(doctorCheckTimeout))
+			_, err = io.ReadFull(conn, b)
+			conn.Close()
+			if err == nil && bytes.Equal(b, token) {
+				close(received)
+				return
+			}
+		}
+	}()
+
+	unreachable := doctorResult{
+		status:  doctorFail,
+		message: fmt.Sprintf("%s is not reachable", d.natAddr),
+		fix:     fmt.Sprintf("forward the TCP port to %s in the router and the firewall", d.p2pAddr),
+	}
+	conn, err := new(net.Dialer).DialContext(ctx, "tcp", d.natAddr)
+	if err != nil {
+		unreachable.message = fmt.Sprintf("%s is not reachable: %v", d.natAddr, err)
+		return unreachable
+	}
+	defer conn.Close()
+	if _, err := conn.Write(token); err != nil {
+		return unreachable
+	}
+	select {
+	case <-received:
+		return doctorResult{status: doctorOK, message: fmt.Sprintf("%s is reachable", d.natAddr)}
+	case <-ctx.Done():
+		return unreachable
+	}
+}
+
+func (d *doctor) checkDiskSpace(_ context.Context) doctorResult {
+	if d.dataDir == "" {
+		return doctorResult{status: doctorSkip, message: "no data directory, the data is kept in memory"}
+	}
+	if err := os.MkdirAll(d.dataDir, 0o700); err != nil {
+		return doctorResult{status: doctorFail, message: err.Error(), fix: fmt.Sprintf("check the --%s value", optionNameDataDir)}
+	}
+	free, err := diskFree(d.dataDir)
+	if errors.Is(err, errNotSupported) {
+		return doctorResult{status: doctorSkip, message: err.Error()}
+	}
+	if err != nil {
+		return doctorResult{status: doctorFail, message: err.Error()}
+	}
+
+	chunks := d.cacheCapacity
+	if d.fullNode {
+		chunks += uint64(batchstore.Capacity)
+	}
+	required := chunks * swarm.ChunkWithSpanSize
+	message := fmt.Sprintf("%s free, up to %s required", byteSize(float64(free)), byteSize(float64(required)))
+	if free < required {
+		return doctorResult{
+			status:  doctorWarn,
+			message: message,
+			fix:     fmt.Sprintf("free up space in %s or lower --%s", d.dataDir, optionNameCacheCapacity),
+		}
+	}
+	return doctorResult{status: doctorOK, message: message}
+}
+
+func (d *doctor) checkDiskSpeed(_ context.Context) doctorResult {
+	if d.dataDir == "" {
+		return doctorResult{status: doctorSkip, message: "no data directory, the data is kept in memory"}
+	}
+	if err := os.MkdirAll(d.dataDir, 0o700); err != nil {
+		return doctorResult{status: doctorFail, message: err.Error(), fix: fmt.Sprintf("check the --%s value", optionNameDataDir)}
+	}
+	speed, err := diskWriteSpeed(d.dataDir, doctorDiskTestSize)
+	if err != nil {
+		return doctorResult{status: doctorFail, message: err.Error(), fix: fmt.Sprintf("make sure that %s is writable", d.dataDir)}
+	}
+	message := fmt.Sprintf("synchronous writes at %s/s", byteSize(speed))
+	if speed < doctorSlowDiskSpeed {
+		return doctorResult{
+			status:  doctorWarn,
+			message: message,
+			fix:     "keep the data directory on an SSD, network and USB drives are too slow for a node",
+		}
+	}
+	return doctorResult{status: doctorOK, message: message}
+}
+
+// diskWriteSpeed returns the speed of synchronous
+// writes to a temporary file in the directory.
+func diskWriteSpeed(dir string, size int) (float64, error) {
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	block := make([]byte, 1024*1024)
+	if _, err := rand.Read(block); err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	for written := 0; written < size; written += len(block) {
+		if _, err := f.Write(block); err != nil {
+			return 0, err
+		}
+		if err := f.Sync(); err != nil {
+			return 0, err
+		}
+	}
+	return float64(size) / time.Since(start).Seconds(), nil
+}
+
+func (d *doctor) checkOpenFiles(_ context.Context) doctorResult {
+	limit, err := openFilesLimit()
+	if errors.Is(err, errNotSupported) {
+		return doctorResult{status: doctorSkip, message: err.Error()}
+	}
+	if err != nil {
+		return doctorResult{status: doctorFail, message: err.Error()}
+	}
+	if limit < doctorMinOpenFiles {
+		return doctorResult{
+			status:  doctorWarn,
+			message: fmt.Sprintf("%d, at least %d recommended", limit, doctorMinOpenFiles),
+			fix:     fmt.Sprintf("raise the limit with ulimit -n %d or LimitNOFILE=%d in the systemd service", 4*doctorMinOpenFiles, 4*doctorMinOpenFiles),
+		}
+	}
+	return doctorResult{status: doctorOK, message: fmt.Sprintf("%d", limit)}
+}
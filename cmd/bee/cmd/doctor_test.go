@@ -0,0 +1,181 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/cmd/bee/cmd"
+)
+
+func newRPCServer(t *testing.T, chainID int64) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var result string
+		switch req.Method {
+		case "eth_chainId":
+			result = fmt.Sprintf("%q", fmt.Sprintf("0x%x", chainID))
+		case "eth_syncing":
+			result = "false"
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, req.ID, result)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newNTPServer starts a server which responds with the time shifted by the offset.
+func newNTPServer(t *testing.T, offset time.Duration) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		b := make([]byte, 48)
+		for {
+			_, addr, err := conn.ReadFrom(b)
+			if err != nil {
+				return
+			}
+			now := time.Now().Add(offset)
+			resp := make([]byte, 48)
+			resp[0] = 0x1c // version 3, server mode
+			binary.BigEndian.PutUint32(resp[32:], uint32(now.Unix()+2208988800))
+			binary.BigEndian.PutUint32(resp[36:], uint32((uint64(now.Nanosecond())<<32)/1e9))
+			copy(resp[40:], resp[32:40])
+			_, _ = conn.WriteTo(resp, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func freeTCPAddr(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return ln.Addr().String()
+}
+
+func TestDoctorCmd(t *testing.T) {
+	t.Parallel()
+
+	rpc := newRPCServer(t, 100)
+	p2pAddr := freeTCPAddr(t)
+
+	for _, tc := range []struct {
+		name    string
+		args    []string
+		wantErr bool
+		want    []string
+		// skew is the clock skew expected in the output, as
+		// measured within a second of the offset of the server
+		skew time.Duration
+	}{
+		{
+			name: "ok",
+			args: []string{"--blockchain-rpc-endpoint", rpc.URL, "--ntp-server", newNTPServer(t, 0), "--nat-addr", p2pAddr},
+			want: []string{
+				"OK    blockchain rpc: chain ID 100",
+				"OK    clock skew: local clock is off by",
+				"OK    p2p port: " + p2pAddr + " is reachable",
+				"disk space:",
+				"disk speed:",
+				"open files limit:",
+			},
+		},
+		{
+			name:    "problems",
+			args:    []string{"--blockchain-rpc-endpoint", rpc.URL, "--network-id", "10", "--ntp-server", newNTPServer(t, time.Minute)},
+			wantErr: true,
+			want: []string{
+				"FAIL  blockchain rpc: chain ID 100, network 10 expects 5",
+				"      fix: use an endpoint of the chain with ID 5 or change --network-id",
+				"FAIL  clock skew: local clock is off by ",
+				"WARN  p2p port: listening on " + p2pAddr + ", reachability from outside not checked",
+			},
+			skew: time.Minute,
+		},
+		{
+			name: "no blockchain",
+			args: []string{"--swap-enable=false", "--ntp-server", ""},
+			want: []string{
+				"SKIP  blockchain rpc: no endpoint, the node runs without the blockchain",
+				"SKIP  clock skew: no NTP server",
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// the p2p port is shared, so the cases run sequentially
+
+			var out bytes.Buffer
+			args := append([]string{"doctor", "--data-dir", t.TempDir(), "--p2p-addr", p2pAddr}, tc.args...)
+			err := newCommand(t, cmd.WithArgs(args...), cmd.WithOutput(&out)).Execute()
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("got error %v, want error %t\n%s", err, tc.wantErr, out.String())
+			}
+			for _, w := range tc.want {
+				if !strings.Contains(out.String(), w) {
+					t.Errorf("output does not contain %q:\n%s", w, out.String())
+				}
+			}
+			if tc.skew != 0 {
+				if got := clockSkew(t, out.String()); got < tc.skew-time.Second || got > tc.skew+time.Second {
+					t.Errorf("got clock skew %s, want %s", got, tc.skew)
+				}
+			}
+		})
+	}
+}
+
+// clockSkew returns the clock skew reported in the output of the doctor.
+func clockSkew(t *testing.T, out string) time.Duration {
+	t.Helper()
+
+	const prefix = "clock skew: local clock is off by "
+	for _, line := range strings.Split(out, "\n") {
+		i := strings.Index(line, prefix)
+		if i < 0 {
+			continue
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(line[i+len(prefix):]))
+		if err != nil {
+			t.Fatalf("parse clock skew %q: %v", line, err)
+		}
+		return d
+	}
+	t.Fatalf("output does not report the clock skew:\n%s", out)
+	return 0
+}
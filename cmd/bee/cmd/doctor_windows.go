@@ -0,0 +1,31 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+
+package cmd
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// diskFree returns the number of bytes available
+// to the user on the disk of the path.
+func diskFree(path string) (uint64, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var free uint64
+	if err := windows.GetDiskFreeSpaceEx(p, &free, nil, nil); err != nil {
+		return 0, err
+	}
+	return free, nil
+}
+
+// openFilesLimit is not applicable on Windows, where
+// the number of open handles is not limited per process.
+func openFilesLimit() (uint64, error) {
+	return 0, errNotSupported
+}
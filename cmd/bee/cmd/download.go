@@ -0,0 +1,294 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+const optionNameDownloadSegmentSize = "segment-size"
+
+const (
+	defaultDownloadSegmentSize = 4 * 1024 * 1024
+	downloadPartSuffix         = ".part"
+	downloadStateSuffix        = ".part.json"
+)
+
+func (c *command) initDownloadCmd() (err error) {
+	cmd := &cobra.Command{
+		Use:   "download <reference[/path]> [output]",
+		Short: "Download a file from a running node",
+		Long: `Download a file from a running node
+
+Downloads the file with the swarm reference, optionally followed by a path
+in its manifest, through the API of a running node. The file is downloaded
+in segments which are fetched concurrently. The progress is kept next to the
+output file, so an interrupted download is resumed by running the same
+command again. If the output is not given, the file name from the manifest
+is used.`,
+		Example: `
+$> bee download 36b7...1bd2 photo.jpg
+$> bee download 36b7...1bd2/docs/index.html`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 || len(args) > 2 {
+				return cmd.Help()
+			}
+			parallel := c.config.GetInt(optionNameTransferParallel)
+			if parallel <= 0 {
+				return errors.New("parallel must be positive")
+			}
+			segmentSize := c.config.GetInt64(optionNameDownloadSegmentSize)
+			if segmentSize <= 0 {
+				return errors.New("segment size must be positive")
+			}
+
+			d := &downloader{
+				api:         newAPIClient(c.config.GetString(optionNameAPIEndpoint), http.DefaultClient),
+				path:        "/bzz/" + strings.TrimPrefix(args[0], "/"),
+				parallel:    parallel,
+				segmentSize: segmentSize,
+			}
+			if !strings.Contains(strings.TrimPrefix(args[0], "/"), "/") {
+				d.path += "/"
+			}
+
+			size, name, err := d.stat(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			output := name
+			if len(args) == 2 {
+				output = args[1]
+			}
+			if output == "" {
+				output = path.Base(strings.TrimSuffix(args[0], "/"))
+			}
+
+			var w io.Writer
+			if !c.config.GetBool(optionNameTransferNoProgress) {
+				w = cmd.ErrOrStderr()
+			}
+			p := newProgress(w, "downloading", size)
+			err = d.download(cmd.Context(), output, size, p)
+			p.Done()
+			if err != nil {
+				return err
+			}
+
+			cmd.Println(output)
+			return nil
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return c.config.BindPFlags(cmd.Flags())
+		},
+	}
+
+	cmd.Flags().String(optionNameAPIEndpoint, defaultAPIEndpoint, "API endpoint of the node")
+	cmd.Flags().Int(optionNameTransferParallel, 4, "number of concurrently downloaded segments")
+	cmd.Flags().Int64(optionNameDownloadSegmentSize, defaultDownloadSegmentSize, "size of the downloaded segments in bytes")
+	cmd.Flags().Bool(optionNameTransferNoProgress, false, "do not show the progress")
+
+	cmd.SetOut(c.root.OutOrStdout())
+	c.root.AddCommand(cmd)
+	return nil
+}
+
+type downloader struct {
+	api         *apiClient
+	path        string
+	parallel    int
+	segmentSize int64
+}
+
+// downloadState is the progress of a download which is kept in a file
+// next to the partially downloaded file to be able to resume it.
+type downloadState struct {
+	Path        string  `json:"path"`
+	Size        int64   `json:"size"`
+	SegmentSize int64   `json:"segmentSize"`
+	Done        []int64 `json:"done"`
+}
+
+// stat returns the size and the file name of the content.
+func (d *downloader) stat(ctx context.Context) (size int64, name string, err error) {
+	resp, err := d.api.do(ctx, http.MethodGet, d.path, nil, http.Header{"Range": {"bytes=0-0"}})
+	if err != nil {
+		var apiErr *apiError
+		// the empty content can not satisfy any range
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusRequestedRangeNotSatisfiable {
+			return 0, "", nil
+		}
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		name = path.Base(params["filename"])
+	}
+
+	if resp.StatusCode != http.StatusPartialContent {
+		size, err = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+		if err != nil {
+			return 0, "", errors.New("unknown content size")
+		}
+		return size, name, nil
+	}
+
+	cr := resp.Header.Get("Content-Range")
+	i := strings.LastIndex(cr, "/")
+	if i < 0 {
+		return 0, "", fmt.Errorf("invalid content range %q", cr)
+	}
+	size, err = strconv.ParseInt(cr[i+1:], 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid content range %q", cr)
+	}
+	return size, name, nil
+}
+
+// download fetches the missing segments of the content to the output file.
+func (d *downloader) download(ctx context.Context, output string, size int64, p *progress) error {
+	partPath, statePath := output+downloadPartSuffix, output+downloadStateSuffix
+
+	state := d.loadState(statePath, size)
+	f, err := os.OpenFile(partPath, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if len(state.Done) == 0 {
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+	}
+
+	done := make(map[int64]bool, len(state.Done))
+	for _, s := range state.Done {
+		done[s] = true
+		p.Add(d.segmentLength(s, size))
+	}
+
+	var mu sync.Mutex
+	eg, egCtx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, d.parallel)
+	segments := (size + d.segmentSize - 1) / d.segmentSize
+loop:
+	for s := int64(0); s < segments; s++ {
+		if done[s] {
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-egCtx.Done():
+			break loop
+		}
+		s := s
+		eg.Go(func() error {
+			defer func() { <-sem }()
+			if err := d.fetchSegment(egCtx, f, s, size, p); err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			state.Done = append(state.Done, s)
+			return saveDownloadState(statePath, state)
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(partPath, output); err != nil {
+		return err
+	}
+	if err := os.Remove(statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (d *downloader) segmentLength(s, size int64) int64 {
+	if end := (s + 1) * d.segmentSize; end < size {
+		return d.segmentSize
+	}
+	return size - s*d.segmentSize
+}
+
+func (d *downloader) fetchSegment(ctx context.Context, f *os.File, s, size int64, p *progress) error {
+	start := s * d.segmentSize
+	end := start + d.segmentLength(s, size) - 1
+	resp, err := d.api.do(ctx, http.MethodGet, d.path, nil, http.Header{"Range": {fmt.Sprintf("bytes=%d-%d", start, end)}})
+	if err != nil {
+		return fmt.Errorf("segment %d: %w", s, err)
+	}
+	defer resp.Body.Close()
+
+	// the whole content is returned if the range is not supported
+	if resp.StatusCode != http.StatusPartialContent && start > 0 {
+		return fmt.Errorf("segment %d: range requests not supported", s)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(p.reader(resp.Body), end-start+1))
+	if err != nil {
+		return fmt.Errorf("segment %d: %w", s, err)
+	}
+	if int64(len(b)) != end-start+1 {
+		return fmt.Errorf("segment %d: short read", s)
+	}
+	_, err = f.WriteAt(b, start)
+	return err
+}
+
+// loadState returns the saved progress of the download or a new one
+// if there is none or it belongs to a different download.
+func (d *downloader) loadState(statePath string, size int64) *downloadState {
+	fresh := &downloadState{Path: d.path, Size: size, SegmentSize: d.segmentSize}
+
+	b, err := os.ReadFile(statePath)
+	if err != nil {
+		return fresh
+	}
+	var s downloadState
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fresh
+	}
+	if s.Path != fresh.Path || s.Size != fresh.Size || s.SegmentSize != fresh.SegmentSize {
+		return fresh
+	}
+	return &s
+}
+
+func saveDownloadState(statePath string, s *downloadState) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp := statePath + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, statePath)
+}
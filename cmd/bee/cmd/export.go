@@ -0,0 +1,300 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/manifest/mantaray"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+func (c *command) initExportCmd() (err error) {
+	cmd := &cobra.Command{
+		Use:   "export <reference> <dir>",
+		Short: "Export a collection to a local directory",
+		Long: `Export a collection to a local directory
+
+Downloads all the files of the manifest with the swarm reference through the
+API of a running node to the directory, preserving their paths, which is the
+inverse of the directory upload. The modification times of the files are
+restored if they are present in the manifest metadata. Every downloaded file
+is verified against its swarm reference, unless the content is encrypted.
+The files which are already exported are verified and skipped, and the
+partially downloaded files are resumed, so an interrupted export is resumed
+by running the same command again.`,
+		Example: `
+$> bee export 36b7...1bd2 ./website`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return cmd.Help()
+			}
+			parallel := c.config.GetInt(optionNameTransferParallel)
+			if parallel <= 0 {
+				return errors.New("parallel must be positive")
+			}
+			segmentSize := c.config.GetInt64(optionNameDownloadSegmentSize)
+			if segmentSize <= 0 {
+				return errors.New("segment size must be positive")
+			}
+			reference, err := swarm.ParseHexAddress(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid reference: %w", err)
+			}
+
+			e := &exporter{
+				api:         newAPIClient(c.config.GetString(optionNameAPIEndpoint), http.DefaultClient),
+				dir:         args[1],
+				parallel:    parallel,
+				segmentSize: segmentSize,
+			}
+			files, err := e.list(cmd.Context(), reference)
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer
+			if !c.config.GetBool(optionNameTransferNoProgress) {
+				w = cmd.ErrOrStderr()
+			}
+			if err := e.export(cmd.Context(), files, w); err != nil {
+				return err
+			}
+
+			cmd.Println(e.dir)
+			return nil
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return c.config.BindPFlags(cmd.Flags())
+		},
+	}
+
+	cmd.Flags().String(optionNameAPIEndpoint, defaultAPIEndpoint, "API endpoint of the node")
+	cmd.Flags().Int(optionNameTransferParallel, 4, "number of concurrently downloaded files")
+	cmd.Flags().Int64(optionNameDownloadSegmentSize, defaultDownloadSegmentSize, "size of the downloaded segments in bytes")
+	cmd.Flags().Bool(optionNameTransferNoProgress, false, "do not show the progress")
+
+	cmd.SetOut(c.root.OutOrStdout())
+	c.root.AddCommand(cmd)
+	return nil
+}
+
+type exporter struct {
+	api         *apiClient
+	dir         string
+	parallel    int
+	segmentSize int64
+}
+
+// exportFile is a file of the exported manifest.
+type exportFile struct {
+	path      string // slash separated path in the manifest
+	reference swarm.Address
+	modTime   time.Time
+	size      int64
+}
+
+// list returns the files of the manifest with the reference.
+func (e *exporter) list(ctx context.Context, reference swarm.Address) ([]*exportFile, error) {
+	var files []*exportFile
+	err := mantaray.NewNodeRef(reference.Bytes()).WalkNode(ctx, []byte{}, &apiLoader{api: e.api}, func(p []byte, node *mantaray.Node, err error) error {
+		if err != nil {
+			return err
+		}
+		if !node.IsValueType() || len(node.Entry()) == 0 {
+			return nil
+		}
+		if bytes.Equal(node.Entry(), make([]byte, len(node.Entry()))) {
+			// the root metadata of the website
+			return nil
+		}
+
+		// the paths must not point outside of the directory
+		if !filepath.IsLocal(filepath.FromSlash(string(p))) {
+			return fmt.Errorf("invalid path %q in manifest", p)
+		}
+		f := &exportFile{path: string(p), reference: swarm.NewAddress(node.Entry())}
+		if v, ok := node.Metadata()[manifest.EntryMetadataModTimeKey]; ok {
+			if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+				f.modTime = time.Unix(sec, 0)
+			}
+		}
+		files = append(files, f)
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, mantaray.ErrTooShort) || errors.Is(err, mantaray.ErrInvalidVersionHash) {
+			return nil, errors.New("reference is not a manifest")
+		}
+		return nil, fmt.Errorf("list manifest: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no files in manifest")
+	}
+	return files, nil
+}
+
+// export downloads the files which are not exported yet.
+func (e *exporter) export(ctx context.Context, files []*exportFile, w io.Writer) error {
+	var (
+		pending []*exportFile
+		total   int64
+	)
+	for _, f := range files {
+		done, err := e.exported(ctx, f)
+		if err != nil {
+			return err
+		}
+		if done {
+			continue
+		}
+		d := e.downloader(f)
+		if f.size, _, err = d.stat(ctx); err != nil {
+			return fmt.Errorf("%s: %w", f.path, err)
+		}
+		pending = append(pending, f)
+		total += f.size
+	}
+
+	p := newProgress(w, "exporting", total)
+	defer p.Done()
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, e.parallel)
+loop:
+	for _, f := range pending {
+		select {
+		case sem <- struct{}{}:
+		case <-egCtx.Done():
+			break loop
+		}
+		f := f
+		eg.Go(func() error {
+			defer func() { <-sem }()
+			if err := e.download(egCtx, f, p); err != nil {
+				return fmt.Errorf("%s: %w", f.path, err)
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+func (e *exporter) output(f *exportFile) string {
+	return filepath.Join(e.dir, filepath.FromSlash(f.path))
+}
+
+// downloader returns the downloader of the file content. The files are
+// downloaded one segment at a time, as many of them are downloaded at once.
+func (e *exporter) downloader(f *exportFile) *downloader {
+	return &downloader{
+		api:         e.api,
+		path:        "/bytes/" + f.reference.String(),
+		parallel:    1,
+		segmentSize: e.segmentSize,
+	}
+}
+
+// exported reports whether the file was already exported and verified.
+func (e *exporter) exported(ctx context.Context, f *exportFile) (bool, error) {
+	output := e.output(f)
+	if _, err := os.Stat(output); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := verifyExport(ctx, output, f.reference); err != nil {
+		return false, nil
+	}
+	return true, setModTime(output, f.modTime)
+}
+
+func (e *exporter) download(ctx context.Context, f *exportFile, p *progress) error {
+	output := e.output(f)
+	if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+		return err
+	}
+	if err := e.downloader(f).download(ctx, output, f.size, p); err != nil {
+		return err
+	}
+	if err := verifyExport(ctx, output, f.reference); err != nil {
+		_ = os.Remove(output)
+		return err
+	}
+	return setModTime(output, f.modTime)
+}
+
+// verifyExport verifies that the content of the file has the reference.
+// The references of the encrypted content can not be computed, as the
+// encryption keys are random, so only the plain content is verified.
+func verifyExport(ctx context.Context, path string, reference swarm.Address) error {
+	if len(reference.Bytes()) != swarm.HashSize {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	pipe := builder.NewPipelineBuilder(ctx, discardPutter{}, storage.ModePutUpload, false)
+	got, err := builder.FeedPipeline(ctx, pipe, f)
+	if err != nil {
+		return err
+	}
+	if !got.Equal(reference) {
+		return fmt.Errorf("verification failed: got reference %s, want %s", got, reference)
+	}
+	return nil
+}
+
+func setModTime(path string, t time.Time) error {
+	if t.IsZero() {
+		return nil
+	}
+	return os.Chtimes(path, t, t)
+}
+
+// discardPutter discards the chunks, so that the
+// pipeline only computes the reference of the content.
+type discardPutter struct{}
+
+func (discardPutter) Put(_ context.Context, _ storage.ModePut, chs ...swarm.Chunk) ([]bool, error) {
+	return make([]bool, len(chs)), nil
+}
+
+// apiLoader loads the manifest nodes through the node API.
+type apiLoader struct {
+	api *apiClient
+}
+
+func (l *apiLoader) Load(ctx context.Context, reference []byte) ([]byte, error) {
+	resp, err := l.api.do(ctx, http.MethodGet, "/bytes/"+hex.EncodeToString(reference), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
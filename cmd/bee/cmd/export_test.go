@@ -13,7 +13,8 @@ type (
 )
 
 var (
-	NewCommand = newCommand
+	NewCommand       = newCommand
+	ServiceArguments = serviceArguments
 
 	// avoid unused lint errors until the functions are used
 	_ = WithCfgFile
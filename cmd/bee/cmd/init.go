@@ -42,7 +42,7 @@ func (c *command) initInitCmd() (err error) {
 			return nil
 		},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			return c.config.BindPFlags(cmd.Flags())
+			return c.bindAllFlags(cmd)
 		},
 	}
 
@@ -0,0 +1,484 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/keystore"
+	filekeystore "github.com/ethersphere/bee/pkg/keystore/file"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/node"
+	"github.com/ethersphere/bee/pkg/statestore/leveldb"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/spf13/cobra"
+)
+
+const (
+	optionNameKeysRaw         = "raw"
+	optionNameKeysKeyPassword = "key-password"
+	optionNameKeysForce       = "force"
+	optionNameKeysNewPassword = "new-password"
+	optionNameNonceMine       = "nonce-mine"
+	optionNameOverlayWrite    = "write"
+)
+
+// nodeKeys are the names of the keys in the keystore of a node
+// with their encodings. The libp2p key is kept by older versions.
+var nodeKeys = []struct {
+	name string
+	edg  keystore.EDG
+}{
+	{name: "swarm", edg: crypto.EDGSecp256_K1},
+	{name: "libp2p_v2", edg: crypto.EDGSecp256_R1},
+	{name: "pss", edg: crypto.EDGSecp256_K1},
+	{name: "libp2p", edg: crypto.EDGSecp256_K1},
+}
+
+func nodeKeyEDG(name string) (keystore.EDG, error) {
+	for _, k := range nodeKeys {
+		if k.name == name {
+			return k.edg, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key %q", name)
+}
+
+func (c *command) initKeysCmd() {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Manage the keys in the keystore of a node",
+		Long: `Manage the keys in the keystore of a node
+
+The commands operate on the keystore in the data directory, so the node does
+not need to be running. The keys are swarm, which determines the overlay and
+the ethereum address, libp2p_v2 and pss.`,
+	}
+
+	cmd.PersistentFlags().String(optionNameDataDir, "", "data directory")
+	cmd.PersistentFlags().String(optionNamePassword, "", "password for decrypting keys")
+	cmd.PersistentFlags().String(optionNamePasswordFile, "", "path to a file that contains password for decrypting keys")
+
+	keysInspectCmd(cmd, c.passwordReader)
+	keysExportCmd(cmd, c.passwordReader)
+	keysImportCmd(cmd, c.passwordReader)
+	keysRotatePasswordCmd(cmd, c.passwordReader)
+
+	c.root.AddCommand(cmd)
+}
+
+func keysInspectCmd(cmd *cobra.Command, r passwordReader) {
+	c := &cobra.Command{
+		Use:   "inspect",
+		Short: "Print the public keys and addresses of the keys",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, err := keysKeystore(cmd)
+			if err != nil {
+				return err
+			}
+			password, err := keysPassword(cmd, r)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tPUBLIC KEY\tETHEREUM ADDRESS")
+			found := false
+			for _, k := range nodeKeys {
+				pk, err := loadKey(ks, k.name, password, k.edg)
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				found = true
+				ethAddress := "-"
+				if pk.Curve != elliptic.P256() {
+					a, err := crypto.NewEthereumAddress(pk.PublicKey)
+					if err != nil {
+						return err
+					}
+					ethAddress = common.BytesToAddress(a).String()
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", k.name, encodePublicKey(&pk.PublicKey), ethAddress)
+			}
+			if !found {
+				return errors.New("no keys in the keystore")
+			}
+			return w.Flush()
+		},
+	}
+	cmd.AddCommand(c)
+}
+
+func keysExportCmd(cmd *cobra.Command, r passwordReader) {
+	c := &cobra.Command{
+		Use:   "export <name> [file]",
+		Short: "Export a key to a file or to the standard output",
+		Long: `Export a key to a file or to the standard output
+
+The key is exported in the Ethereum JSON v3 key file format, encrypted with
+the keystore password or with --key-password if provided. With --raw the
+unencrypted private key is exported in hex, which must be kept secret.`,
+		Example: `
+$> bee keys export swarm swarm.json --data-dir ~/.bee
+$> bee keys export swarm --raw --data-dir ~/.bee`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			edg, err := nodeKeyEDG(args[0])
+			if err != nil {
+				return err
+			}
+			ks, err := keysKeystore(cmd)
+			if err != nil {
+				return err
+			}
+			password, err := keysPassword(cmd, r)
+			if err != nil {
+				return err
+			}
+			pk, err := loadKey(ks, args[0], password, edg)
+			if err != nil {
+				return err
+			}
+
+			var data []byte
+			if raw, _ := cmd.Flags().GetBool(optionNameKeysRaw); raw {
+				d, err := edg.Encode(pk)
+				if err != nil {
+					return err
+				}
+				data = []byte(hex.EncodeToString(d) + "\n")
+			} else {
+				if p, _ := cmd.Flags().GetString(optionNameKeysKeyPassword); p != "" {
+					password = p
+				}
+				if data, err = filekeystore.EncryptKey(pk, password, edg); err != nil {
+					return err
+				}
+			}
+
+			if len(args) == 1 {
+				_, err = cmd.OutOrStdout().Write(data)
+				return err
+			}
+			return os.WriteFile(args[1], data, 0o600)
+		},
+	}
+	c.Flags().Bool(optionNameKeysRaw, false, "export the unencrypted private key in hex")
+	c.Flags().String(optionNameKeysKeyPassword, "", "password for encrypting the exported key, defaults to the keystore password")
+	cmd.AddCommand(c)
+}
+
+func keysImportCmd(cmd *cobra.Command, r passwordReader) {
+	c := &cobra.Command{
+		Use:   "import <name> <file>",
+		Short: "Import a key from a file",
+		Long: `Import a key from a file
+
+The file contains a key in the Ethereum JSON v3 key file format, decrypted with
+--key-password or with the keystore password, or an unencrypted private key in
+hex. The key is stored encrypted with the keystore password. Importing the swarm
+key changes the overlay and the ethereum address of the node.`,
+		Example: `
+$> bee keys import swarm swarm.json --data-dir ~/.bee`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			edg, err := nodeKeyEDG(args[0])
+			if err != nil {
+				return err
+			}
+			ks, err := keysKeystore(cmd)
+			if err != nil {
+				return err
+			}
+			if force, _ := cmd.Flags().GetBool(optionNameKeysForce); !force {
+				exists, err := ks.Exists(args[0])
+				if err != nil {
+					return err
+				}
+				if exists {
+					return fmt.Errorf("key %q already exists, use --%s to replace it", args[0], optionNameKeysForce)
+				}
+			}
+			data, err := os.ReadFile(args[1])
+			if err != nil {
+				return err
+			}
+			password, err := keysPassword(cmd, r)
+			if err != nil {
+				return err
+			}
+
+			var pk *ecdsa.PrivateKey
+			data = bytes.TrimSpace(data)
+			if bytes.HasPrefix(data, []byte("{")) {
+				keyPassword := password
+				if p, _ := cmd.Flags().GetString(optionNameKeysKeyPassword); p != "" {
+					keyPassword = p
+				}
+				if pk, err = filekeystore.DecryptKey(data, keyPassword, edg); err != nil {
+					return fmt.Errorf("decrypt key: %w", err)
+				}
+			} else {
+				d, err := hex.DecodeString(strings.TrimPrefix(string(data), "0x"))
+				if err != nil {
+					return fmt.Errorf("decode key: %w", err)
+				}
+				if pk, err = edg.Decode(d); err != nil {
+					return fmt.Errorf("decode key: %w", err)
+				}
+			}
+
+			if err := ks.Import(args[0], password, pk, edg); err != nil {
+				return err
+			}
+			cmd.Printf("imported %s key %s\n", args[0], encodePublicKey(&pk.PublicKey))
+			return nil
+		},
+	}
+	c.Flags().String(optionNameKeysKeyPassword, "", "password for decrypting the imported key, defaults to the keystore password")
+	c.Flags().Bool(optionNameKeysForce, false, "replace the existing key")
+	cmd.AddCommand(c)
+}
+
+func keysRotatePasswordCmd(cmd *cobra.Command, r passwordReader) {
+	c := &cobra.Command{
+		Use:   "rotate-password",
+		Short: "Encrypt all the keys with a new password",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, err := keysKeystore(cmd)
+			if err != nil {
+				return err
+			}
+			password, err := keysPassword(cmd, r)
+			if err != nil {
+				return err
+			}
+
+			// all keys are decrypted before any is written, so that
+			// the keys are not left encrypted with different passwords
+			keys := make(map[string]*ecdsa.PrivateKey)
+			for _, k := range nodeKeys {
+				pk, err := loadKey(ks, k.name, password, k.edg)
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				keys[k.name] = pk
+			}
+			if len(keys) == 0 {
+				return errors.New("no keys in the keystore")
+			}
+
+			newPassword, _ := cmd.Flags().GetString(optionNameKeysNewPassword)
+			if newPassword == "" {
+				if r == nil {
+					return errors.New("new password not provided")
+				}
+				p1, err := terminalPromptPassword(cmd, r, "New password")
+				if err != nil {
+					return err
+				}
+				p2, err := terminalPromptPassword(cmd, r, "Confirm new password")
+				if err != nil {
+					return err
+				}
+				if p1 != p2 {
+					return errors.New("passwords are not the same")
+				}
+				newPassword = p1
+			}
+
+			for _, k := range nodeKeys {
+				pk, ok := keys[k.name]
+				if !ok {
+					continue
+				}
+				if err := ks.Import(k.name, newPassword, pk, k.edg); err != nil {
+					return fmt.Errorf("%s key: %w", k.name, err)
+				}
+			}
+			cmd.Printf("rotated the password of %d keys\n", len(keys))
+			return nil
+		},
+	}
+	c.Flags().String(optionNameKeysNewPassword, "", "new password for encrypting keys")
+	cmd.AddCommand(c)
+}
+
+func (c *command) initOverlayCmd() {
+	cmd := &cobra.Command{
+		Use:   "overlay",
+		Short: "Print or mine the overlay address of a node",
+		Long: `Print or mine the overlay address of a node
+
+Prints the overlay address which is derived from the swarm key in the data
+directory, the network ID and the overlay nonce. With --nonce-mine a nonce is
+searched for, which gives an overlay address with the provided prefix of bits,
+so that the node joins the targeted neighborhood. Every bit of the prefix
+doubles the expected search time. The mined nonce is saved with --write, which
+is only possible before the node is started for the first time.`,
+		Example: `
+$> bee overlay --data-dir ~/.bee
+$> bee overlay --nonce-mine 0110 --write --data-dir ~/.bee`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, err := keysKeystore(cmd)
+			if err != nil {
+				return err
+			}
+			password, err := keysPassword(cmd, c.passwordReader)
+			if err != nil {
+				return err
+			}
+			pk, err := loadKey(ks, "swarm", password, crypto.EDGSecp256_K1)
+			if err != nil {
+				return err
+			}
+			networkID, err := cmd.Flags().GetUint64(optionNameNetworkID)
+			if err != nil {
+				return fmt.Errorf("get network-id: %w", err)
+			}
+			dataDir, _ := cmd.Flags().GetString(optionNameDataDir)
+
+			prefix, _ := cmd.Flags().GetString(optionNameNonceMine)
+			if prefix == "" {
+				nonce := make([]byte, 32)
+				if _, err := os.Stat(filepath.Join(dataDir, "statestore")); err == nil {
+					stateStore, err := leveldb.NewStateStore(filepath.Join(dataDir, "statestore"), log.Noop)
+					if err != nil {
+						return fmt.Errorf("statestore: %w", err)
+					}
+					nonce, _, err = node.OverlayNonceExists(stateStore)
+					_ = stateStore.Close()
+					if err != nil {
+						return fmt.Errorf("statestore: %w", err)
+					}
+				}
+				overlay, err := crypto.NewOverlayAddress(pk.PublicKey, networkID, nonce)
+				if err != nil {
+					return err
+				}
+				printOverlay(cmd, overlay, nonce)
+				return nil
+			}
+
+			overlay, nonce, err := crypto.MineOverlay(cmd.Context(), pk.PublicKey, networkID, prefix)
+			if err != nil {
+				return err
+			}
+			printOverlay(cmd, overlay, nonce)
+
+			if write, _ := cmd.Flags().GetBool(optionNameOverlayWrite); !write {
+				return nil
+			}
+			stateStore, err := leveldb.NewStateStore(filepath.Join(dataDir, "statestore"), log.Noop)
+			if err != nil {
+				return fmt.Errorf("statestore: %w", err)
+			}
+			defer stateStore.Close()
+			if _, exists, err := node.OverlayNonceExists(stateStore); err != nil {
+				return fmt.Errorf("statestore: %w", err)
+			} else if exists {
+				return errors.New("the overlay of the node is already set")
+			}
+			if err := node.SetOverlayNonce(stateStore, nonce); err != nil {
+				return fmt.Errorf("statestore: save overlay nonce: %w", err)
+			}
+			if err := node.SetOverlayInStore(overlay, stateStore); err != nil {
+				return fmt.Errorf("statestore: save overlay: %w", err)
+			}
+			cmd.Println("saved the overlay nonce")
+			return nil
+		},
+	}
+
+	cmd.Flags().String(optionNameDataDir, "", "data directory")
+	cmd.Flags().String(optionNamePassword, "", "password for decrypting keys")
+	cmd.Flags().String(optionNamePasswordFile, "", "path to a file that contains password for decrypting keys")
+	cmd.Flags().Uint64(optionNameNetworkID, 1, "ID of the Swarm network")
+	cmd.Flags().String(optionNameNonceMine, "", "mine a nonce for an overlay with the prefix of bits")
+	cmd.Flags().Bool(optionNameOverlayWrite, false, "save the mined nonce to the statestore")
+
+	cmd.SetOut(c.root.OutOrStdout())
+	c.root.AddCommand(cmd)
+}
+
+func printOverlay(cmd *cobra.Command, overlay swarm.Address, nonce []byte) {
+	cmd.Printf("overlay: %s\n", overlay)
+	cmd.Printf("nonce:   %s\n", hex.EncodeToString(nonce))
+}
+
+// keysKeystore returns the keystore in the data directory.
+func keysKeystore(cmd *cobra.Command) (*filekeystore.Service, error) {
+	dataDir, err := cmd.Flags().GetString(optionNameDataDir)
+	if err != nil {
+		return nil, fmt.Errorf("get data-dir: %w", err)
+	}
+	if dataDir == "" {
+		return nil, errors.New("no data-dir provided")
+	}
+	return filekeystore.New(filepath.Join(dataDir, "keys")), nil
+}
+
+// keysPassword returns the keystore password from the flags or prompts for it
+// if the password reader is not nil.
+func keysPassword(cmd *cobra.Command, r passwordReader) (string, error) {
+	if p, _ := cmd.Flags().GetString(optionNamePassword); p != "" {
+		return p, nil
+	}
+	if pf, _ := cmd.Flags().GetString(optionNamePasswordFile); pf != "" {
+		b, err := os.ReadFile(pf)
+		if err != nil {
+			return "", err
+		}
+		return string(bytes.Trim(b, "\n")), nil
+	}
+	if r == nil {
+		return "", errors.New("password not provided")
+	}
+	return terminalPromptPassword(cmd, r, "Password")
+}
+
+// loadKey decrypts the existing key with the name. An error wrapping
+// os.ErrNotExist is returned if the key does not exist.
+func loadKey(ks *filekeystore.Service, name, password string, edg keystore.EDG) (*ecdsa.PrivateKey, error) {
+	exists, err := ks.Exists(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s key: %w", name, os.ErrNotExist)
+	}
+	pk, _, err := ks.Key(name, password, edg)
+	if err != nil {
+		return nil, fmt.Errorf("%s key: %w", name, err)
+	}
+	return pk, nil
+}
+
+func encodePublicKey(k *ecdsa.PublicKey) string {
+	if k.Curve == elliptic.P256() {
+		return hex.EncodeToString(elliptic.MarshalCompressed(k.Curve, k.X, k.Y))
+	}
+	return hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(k))
+}
@@ -0,0 +1,146 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/cmd/bee/cmd"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const testSwarmKey = "634fb5a872396d9693e5c9f9d7233cfa93f395c093371017ff44aa9ae6564cdd"
+
+func runCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	var out bytes.Buffer
+	err := newCommand(t, cmd.WithArgs(args...), cmd.WithOutput(&out)).Execute()
+	return out.String(), err
+}
+
+func TestKeysCmd(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	keyFile := filepath.Join(t.TempDir(), "swarm.key")
+	if err := os.WriteFile(keyFile, []byte("0x"+testSwarmKey+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCmd(t, "keys", "import", "swarm", keyFile, "--data-dir", dataDir, "--password", "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, "imported swarm key ") {
+		t.Fatalf("got output %q", out)
+	}
+
+	if _, err := runCmd(t, "keys", "import", "swarm", keyFile, "--data-dir", dataDir, "--password", "old"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("got error %v, want key already exists", err)
+	}
+
+	out, err = runCmd(t, "keys", "inspect", "--data-dir", dataDir, "--password", "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got output %q", out)
+	}
+	if f := strings.Fields(lines[1]); len(f) != 3 || f[0] != "swarm" || !strings.HasPrefix(f[2], "0x") {
+		t.Fatalf("got output %q", out)
+	}
+
+	if _, err := runCmd(t, "keys", "inspect", "--data-dir", dataDir, "--password", "wrong"); err == nil {
+		t.Fatal("expected error for the wrong password")
+	}
+
+	if _, err := runCmd(t, "keys", "rotate-password", "--data-dir", dataDir, "--password", "old", "--new-password", "new"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runCmd(t, "keys", "export", "swarm", "--raw", "--data-dir", dataDir, "--password", "old"); err == nil {
+		t.Fatal("expected error for the rotated password")
+	}
+	out, err = runCmd(t, "keys", "export", "swarm", "--raw", "--data-dir", dataDir, "--password", "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(out); got != testSwarmKey {
+		t.Fatalf("got exported key %q, want %q", got, testSwarmKey)
+	}
+
+	// the key exported in the key file format is imported to another keystore
+	exported := filepath.Join(t.TempDir(), "swarm.json")
+	if _, err := runCmd(t, "keys", "export", "swarm", exported, "--key-password", "file", "--data-dir", dataDir, "--password", "new"); err != nil {
+		t.Fatal(err)
+	}
+	otherDataDir := t.TempDir()
+	if _, err := runCmd(t, "keys", "import", "swarm", exported, "--key-password", "file", "--data-dir", otherDataDir, "--password", "other"); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runCmd(t, "keys", "export", "swarm", "--raw", "--data-dir", otherDataDir, "--password", "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(out); got != testSwarmKey {
+		t.Fatalf("got exported key %q, want %q", got, testSwarmKey)
+	}
+}
+
+func TestOverlayCmd(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	keyFile := filepath.Join(t.TempDir(), "swarm.key")
+	if err := os.WriteFile(keyFile, []byte(testSwarmKey), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCmd(t, "keys", "import", "swarm", keyFile, "--data-dir", dataDir, "--password", "pass"); err != nil {
+		t.Fatal(err)
+	}
+
+	overlay := func(out string) swarm.Address {
+		t.Helper()
+
+		for _, l := range strings.Split(out, "\n") {
+			if v, ok := strings.CutPrefix(l, "overlay:"); ok {
+				a, err := swarm.ParseHexAddress(strings.TrimSpace(v))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return a
+			}
+		}
+		t.Fatalf("no overlay in output %q", out)
+		return swarm.ZeroAddress
+	}
+
+	out, err := runCmd(t, "overlay", "--nonce-mine", "10110", "--write", "--network-id", "10", "--data-dir", dataDir, "--password", "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mined := overlay(out)
+	if b := mined.Bytes()[0] >> 3; b != 0b10110 {
+		t.Fatalf("got overlay %s, want prefix 10110", mined)
+	}
+
+	out, err = runCmd(t, "overlay", "--network-id", "10", "--data-dir", dataDir, "--password", "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := overlay(out); !got.Equal(mined) {
+		t.Fatalf("got overlay %s, want %s", got, mined)
+	}
+
+	if _, err := runCmd(t, "overlay", "--nonce-mine", "0", "--write", "--network-id", "10", "--data-dir", dataDir, "--password", "pass"); err == nil {
+		t.Fatal("expected error for the already set overlay")
+	}
+}
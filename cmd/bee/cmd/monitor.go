@@ -0,0 +1,328 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/bigint"
+	"github.com/ethersphere/bee/pkg/topology"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const (
+	optionNameMonitorInterval = "interval"
+	optionNameMonitorOnce     = "once"
+)
+
+const (
+	monitorRequestTimeout = 10 * time.Second
+	monitorMaxBarWidth    = 40
+
+	metricStreamReceivedBytes = "bee_libp2p_stream_received_bytes"
+	metricStreamSentBytes     = "bee_libp2p_stream_sent_bytes"
+)
+
+func (c *command) initMonitorCmd() (err error) {
+	cmd := &cobra.Command{
+		Use:   "monitor",
+		Short: "Show a live status dashboard of a running node",
+		Long: `Show a live status dashboard of a running node
+
+Connects to the debug API of a running node and renders the peers per bin,
+the reserve, the bandwidth, the settlements and the redistribution rounds,
+refreshing them every interval. Press 'q' or Ctrl-C to quit.`,
+		Example: `
+$> bee monitor --endpoint http://localhost:1635 --interval 2s`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			interval := c.config.GetDuration(optionNameMonitorInterval)
+			if interval <= 0 {
+				return errors.New("interval must be positive")
+			}
+			m := &monitor{
+				api: newAPIClient(c.config.GetString(optionNameAPIEndpoint), &http.Client{Timeout: monitorRequestTimeout}),
+			}
+
+			if c.config.GetBool(optionNameMonitorOnce) {
+				renderMonitor(cmd.OutOrStdout(), m.api.endpoint, m.fetch(cmd.Context()), nil, "\n")
+				return nil
+			}
+			return m.run(cmd, interval)
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return c.config.BindPFlags(cmd.Flags())
+		},
+	}
+
+	cmd.Flags().String(optionNameAPIEndpoint, "http://localhost:1635", "debug API endpoint of the node")
+	cmd.Flags().Duration(optionNameMonitorInterval, 2*time.Second, "refresh interval")
+	cmd.Flags().Bool(optionNameMonitorOnce, false, "print the status once and exit")
+
+	cmd.SetOut(c.root.OutOrStdout())
+	c.root.AddCommand(cmd)
+	return nil
+}
+
+// monitorSnapshot is the state of the node at a point in time.
+// The sections which could not be fetched are nil and the
+// reason is recorded in the errors.
+type monitorSnapshot struct {
+	time           time.Time
+	topology       *topology.KadParams
+	status         *monitorStatus
+	reserve        *monitorReserve
+	settlements    *monitorSettlements
+	redistribution *monitorRedistribution
+	receivedBytes  float64
+	sentBytes      float64
+	hasBandwidth   bool
+	errors         []string
+}
+
+type monitorStatus struct {
+	ReserveSize   uint64  `json:"reserveSize"`
+	PullsyncRate  float64 `json:"pullsyncRate"`
+	StorageRadius uint8   `json:"storageRadius"`
+}
+
+type monitorReserve struct {
+	Radius     uint8 `json:"radius"`
+	Commitment int64 `json:"commitment"`
+}
+
+type monitorSettlements struct {
+	TotalReceived *bigint.BigInt `json:"totalReceived"`
+	TotalSent     *bigint.BigInt `json:"totalSent"`
+	Settlements   []struct {
+		Peer string `json:"peer"`
+	} `json:"settlements"`
+}
+
+type monitorRedistribution struct {
+	IsFrozen        bool   `json:"isFrozen"`
+	IsFullySynced   bool   `json:"isFullySynced"`
+	Phase           string `json:"phase"`
+	Round           uint64 `json:"round"`
+	LastWonRound    uint64 `json:"lastWonRound"`
+	LastPlayedRound uint64 `json:"lastPlayedRound"`
+}
+
+type monitor struct {
+	api *apiClient
+}
+
+// run refreshes the dashboard until the context is canceled or the user quits.
+func (m *monitor) run(cmd *cobra.Command, interval time.Duration) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	newline := "\n"
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("terminal raw mode: %w", err)
+		}
+		defer func() { _ = term.Restore(fd, state) }()
+		// the carriage return is not implied in the raw mode
+		newline = "\r\n"
+
+		go func() {
+			b := make([]byte, 1)
+			for {
+				if _, err := os.Stdin.Read(b); err != nil {
+					return
+				}
+				// the interrupt signal is not raised in the raw mode
+				if b[0] == 'q' || b[0] == 0x03 {
+					stop()
+					return
+				}
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var prev *monitorSnapshot
+	for {
+		cur := m.fetch(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		// clear the screen and move the cursor to the top
+		cmd.Print("\033[H\033[2J")
+		renderMonitor(cmd.OutOrStdout(), m.api.endpoint, cur, prev, newline)
+		prev = cur
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// fetch collects the state of the node from the debug API.
+func (m *monitor) fetch(ctx context.Context) *monitorSnapshot {
+	s := &monitorSnapshot{time: time.Now()}
+
+	get := func(path string, v interface{}) bool {
+		if err := m.api.getJSON(ctx, path, v); err != nil {
+			s.errors = append(s.errors, fmt.Sprintf("%s: %v", path, err))
+			return false
+		}
+		return true
+	}
+
+	if v := new(topology.KadParams); get("/topology", v) {
+		s.topology = v
+	}
+	if v := new(monitorStatus); get("/status", v) {
+		s.status = v
+	}
+	if v := new(monitorReserve); get("/reservestate", v) {
+		s.reserve = v
+	}
+	if v := new(monitorSettlements); get("/settlements", v) {
+		s.settlements = v
+	}
+	if v := new(monitorRedistribution); get("/redistributionstate", v) {
+		s.redistribution = v
+	}
+
+	metrics, err := m.metrics(ctx)
+	if err != nil {
+		s.errors = append(s.errors, fmt.Sprintf("/metrics: %v", err))
+	} else {
+		s.receivedBytes, s.sentBytes = metrics[metricStreamReceivedBytes], metrics[metricStreamSentBytes]
+		s.hasBandwidth = true
+	}
+
+	return s
+}
+
+// metrics returns the values of the metrics without labels
+// from the prometheus text exposition format.
+func (m *monitor) metrics(ctx context.Context) (map[string]float64, error) {
+	resp, err := m.api.do(ctx, http.MethodGet, "/metrics", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	values := make(map[string]float64)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") || strings.Contains(line, "{") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		v, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			continue
+		}
+		values[fields[0]] = v
+	}
+	return values, scanner.Err()
+}
+
+// renderMonitor writes the dashboard of the current snapshot. The previous
+// snapshot, if any, is used to calculate the rates.
+func renderMonitor(w io.Writer, endpoint string, cur, prev *monitorSnapshot, newline string) {
+	line := func(format string, a ...interface{}) {
+		fmt.Fprintf(w, format+newline, a...)
+	}
+
+	line("bee monitor  %s  %s", endpoint, cur.time.Format(time.RFC1123))
+	line("")
+
+	if t := cur.topology; t != nil {
+		line("Topology     depth %d  connected %d/%d  reachability %s  network %s",
+			t.Depth, t.Connected, t.Population, t.Reachability, t.NetworkAvailability)
+		line("  %5s  %9s  %10s", "bin", "connected", "population")
+		for i, b := range t.Bins.BinInfos() {
+			if b.BinPopulation == 0 {
+				continue
+			}
+			bar := int(b.BinConnected)
+			if bar > monitorMaxBarWidth {
+				bar = monitorMaxBarWidth
+			}
+			line("  %5d  %9d  %10d  %s", i, b.BinConnected, b.BinPopulation, strings.Repeat("#", bar))
+		}
+		line("  %5s  %9d  %10d", "light", t.LightNodes.BinConnected, t.LightNodes.BinPopulation)
+		line("")
+	}
+
+	if cur.status != nil || cur.reserve != nil {
+		var b strings.Builder
+		b.WriteString("Reserve     ")
+		if s := cur.status; s != nil {
+			fmt.Fprintf(&b, " size %d  storage radius %d  pullsync rate %.2f/s", s.ReserveSize, s.StorageRadius, s.PullsyncRate)
+		}
+		if r := cur.reserve; r != nil {
+			fmt.Fprintf(&b, "  radius %d  commitment %d", r.Radius, r.Commitment)
+		}
+		line("%s", b.String())
+	}
+
+	if cur.hasBandwidth {
+		if prev != nil && prev.hasBandwidth && cur.time.After(prev.time) {
+			dt := cur.time.Sub(prev.time).Seconds()
+			line("Bandwidth    in %s/s  out %s/s  (total in %s  out %s)",
+				byteSize((cur.receivedBytes-prev.receivedBytes)/dt), byteSize((cur.sentBytes-prev.sentBytes)/dt),
+				byteSize(cur.receivedBytes), byteSize(cur.sentBytes))
+		} else {
+			line("Bandwidth    total in %s  out %s", byteSize(cur.receivedBytes), byteSize(cur.sentBytes))
+		}
+	}
+
+	if s := cur.settlements; s != nil {
+		line("Settlements  received %s  sent %s  peers %d", s.TotalReceived, s.TotalSent, len(s.Settlements))
+	}
+
+	if r := cur.redistribution; r != nil {
+		line("Rounds       round %d  phase %s  last played %d  last won %d  frozen %t  fully synced %t",
+			r.Round, r.Phase, r.LastPlayedRound, r.LastWonRound, r.IsFrozen, r.IsFullySynced)
+	}
+
+	if len(cur.errors) > 0 {
+		line("")
+		for _, e := range cur.errors {
+			line("unavailable  %s", e)
+		}
+	}
+}
+
+// byteSize formats the number of bytes in the binary units.
+func byteSize(b float64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%.0f B", b)
+	}
+	div, exp := float64(unit), 0
+	for n := b / unit; n >= unit && exp < 4; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", b/div, "KMGTP"[exp])
+}
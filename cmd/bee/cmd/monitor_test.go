@@ -0,0 +1,60 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/cmd/bee/cmd"
+)
+
+func TestMonitorCmd(t *testing.T) {
+	t.Parallel()
+
+	responses := map[string]string{
+		"/topology":     `{"depth":3,"connected":5,"population":20,"reachability":"Public","networkAvailability":"Available","bins":{"bin_0":{"population":12,"connected":3},"bin_1":{"population":8,"connected":2}},"lightNodes":{"population":1,"connected":1}}`,
+		"/status":       `{"reserveSize":1024,"pullsyncRate":1.5,"storageRadius":3}`,
+		"/reservestate": `{"radius":4,"storageRadius":3,"commitment":2048}`,
+		"/settlements":  `{"totalReceived":"100","totalSent":"50","settlements":[{"peer":"a","received":"100","sent":"50"}]}`,
+		"/metrics":      "# TYPE bee_libp2p_stream_sent_bytes counter\nbee_libp2p_stream_sent_bytes 2048\nbee_libp2p_stream_received_bytes 512\n",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	var outputBuf bytes.Buffer
+	if err := newCommand(t,
+		cmd.WithArgs("monitor", "--once", "--endpoint", srv.URL),
+		cmd.WithOutput(&outputBuf),
+	).Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := outputBuf.String()
+	for _, want := range []string{
+		"depth 3  connected 5/20  reachability Public  network Available",
+		"      0          3          12  ###",
+		"      1          2           8  ##",
+		"size 1024  storage radius 3  pullsync rate 1.50/s  radius 4  commitment 2048",
+		"total in 512 B  out 2.0 KiB",
+		"received 100  sent 50  peers 1",
+		"unavailable  /redistributionstate: 404 Not Found",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+}
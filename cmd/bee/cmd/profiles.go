@@ -0,0 +1,129 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	chaincfg "github.com/ethersphere/bee/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// profile is a named set of coherent option defaults. The options which are
+// provided by the user in the flags, the environment or the config file take
+// precedence over the defaults of the profile.
+type profile struct {
+	defaults map[string]interface{}
+	// required are the options which the profile
+	// can not default, so they must be provided
+	required []string
+}
+
+var profiles = map[string]profile{
+	"mainnet-full": {
+		defaults: withDefaults(chainDefaults(chaincfg.Mainnet), map[string]interface{}{
+			optionNameMainNet:                 true,
+			optionNameNetworkID:               defaultMainNetworkID,
+			optionNameBootnodes:               []string{"/dnsaddr/mainnet.ethswarm.org"},
+			optionNameBlockTime:               5,
+			optionNameFullNode:                true,
+			optionNameSwapEnable:              true,
+			optionNameStorageIncentivesEnable: true,
+		}),
+	},
+	"mainnet-light": {
+		defaults: withDefaults(chainDefaults(chaincfg.Mainnet), map[string]interface{}{
+			optionNameMainNet:                 true,
+			optionNameNetworkID:               defaultMainNetworkID,
+			optionNameBootnodes:               []string{"/dnsaddr/mainnet.ethswarm.org"},
+			optionNameBlockTime:               5,
+			optionNameFullNode:                false,
+			optionNameSwapEnable:              true,
+			optionNameStorageIncentivesEnable: false,
+		}),
+	},
+	"testnet": {
+		defaults: withDefaults(chainDefaults(chaincfg.Testnet), map[string]interface{}{
+			optionNameMainNet:                 false,
+			optionNameNetworkID:               defaultTestNetworkID,
+			optionNameBootnodes:               []string{"/dnsaddr/testnet.ethswarm.org"},
+			optionNameBlockTime:               15,
+			optionNameFullNode:                true,
+			optionNameSwapEnable:              true,
+			optionNameStorageIncentivesEnable: true,
+		}),
+	},
+	"private": {
+		defaults: map[string]interface{}{
+			optionNameMainNet:                 false,
+			optionNameBootnodes:               []string{},
+			optionNameFullNode:                true,
+			optionNameSwapEnable:              false,
+			optionNameChequebookEnable:        false,
+			optionNameStorageIncentivesEnable: false,
+			optionNameAllowPrivateCIDRs:       true,
+		},
+		required: []string{optionNameNetworkID},
+	},
+}
+
+// chainDefaults returns the contract options of the chain.
+func chainDefaults(cfg chaincfg.ChainConfig) map[string]interface{} {
+	return map[string]interface{}{
+		optionNamePostageContractAddress:    cfg.PostageStampAddress.String(),
+		optionNamePostageContractStartBlock: cfg.PostageStampStartBlock,
+		optionNamePriceOracleAddress:        cfg.SwapPriceOracleAddress.String(),
+		optionNameRedistributionAddress:     cfg.RedistributionAddress.String(),
+		optionNameStakingAddress:            cfg.StakingAddress.String(),
+		optionNameSwapFactoryAddress:        cfg.CurrentFactoryAddress.String(),
+	}
+}
+
+func withDefaults(defaults ...map[string]interface{}) map[string]interface{} {
+	m := make(map[string]interface{})
+	for _, d := range defaults {
+		for k, v := range d {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+func profileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// bindAllFlags binds the flags set by setAllFlags to the configuration
+// and layers the defaults of the selected profile below them.
+func (c *command) bindAllFlags(cmd *cobra.Command) error {
+	if err := c.config.BindPFlags(cmd.Flags()); err != nil {
+		return err
+	}
+
+	name := c.config.GetString(optionNameProfile)
+	if name == "" {
+		return nil
+	}
+	p, ok := profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q, available profiles: %s", name, strings.Join(profileNames(), ", "))
+	}
+	for _, k := range p.required {
+		if !c.config.IsSet(k) {
+			return fmt.Errorf("profile %s requires the %s option", name, k)
+		}
+	}
+	for k, v := range p.defaults {
+		c.config.SetDefault(k, v)
+	}
+	return nil
+}
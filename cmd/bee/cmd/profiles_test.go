@@ -0,0 +1,125 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/cmd/bee/cmd"
+	chaincfg "github.com/ethersphere/bee/pkg/config"
+	"gopkg.in/yaml.v2"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Parallel()
+
+	configFile := filepath.Join(t.TempDir(), "bee.yaml")
+	if err := os.WriteFile(configFile, []byte("profile: testnet\nblock-time: 7\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		args    []string
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{
+			name: "no profile",
+			args: []string{},
+			want: map[string]interface{}{
+				"full-node":             false,
+				"mainnet":               true,
+				"postage-stamp-address": "",
+			},
+		},
+		{
+			name: "mainnet full",
+			args: []string{"--profile", "mainnet-full"},
+			want: map[string]interface{}{
+				"full-node":                 true,
+				"network-id":                1,
+				"block-time":                5,
+				"storage-incentives-enable": true,
+				"bootnode":                  []interface{}{"/dnsaddr/mainnet.ethswarm.org"},
+				"postage-stamp-address":     chaincfg.Mainnet.PostageStampAddress.String(),
+			},
+		},
+		{
+			name: "mainnet light with overrides",
+			args: []string{"--profile", "mainnet-light", "--bootnode", "/ip4/127.0.0.1/tcp/1634", "--block-time", "6"},
+			want: map[string]interface{}{
+				"full-node":                 false,
+				"storage-incentives-enable": false,
+				"block-time":                6,
+				"bootnode":                  []interface{}{"/ip4/127.0.0.1/tcp/1634"},
+			},
+		},
+		{
+			name: "config file",
+			args: []string{"--config", configFile},
+			want: map[string]interface{}{
+				"mainnet":               false,
+				"network-id":            10,
+				"block-time":            7,
+				"postage-stamp-address": chaincfg.Testnet.PostageStampAddress.String(),
+			},
+		},
+		{
+			name: "private",
+			args: []string{"--profile", "private", "--network-id", "1234"},
+			want: map[string]interface{}{
+				"network-id":        1234,
+				"swap-enable":       false,
+				"chequebook-enable": false,
+				"bootnode":          []interface{}{},
+			},
+		},
+		{
+			name:    "private without network id",
+			args:    []string{"--profile", "private"},
+			wantErr: "profile private requires the network-id option",
+		},
+		{
+			name:    "unknown",
+			args:    []string{"--profile", "devnet"},
+			wantErr: `unknown profile "devnet"`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			c := newCommand(t, cmd.WithArgs(append([]string{"printconfig"}, tc.args...)...), cmd.WithOutput(&out))
+			err := c.Execute()
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make(map[string]interface{})
+			if err := yaml.Unmarshal(out.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			// the values of the provided flags are printed as strings
+			for k, v := range tc.want {
+				if g, w := fmt.Sprint(got[k]), fmt.Sprint(v); g != w {
+					t.Errorf("%s: got %s, want %s", k, g, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	progressBarWidth       = 30
+	progressRenderInterval = 200 * time.Millisecond
+)
+
+// progress renders a single line progress bar of a transfer.
+type progress struct {
+	w       io.Writer
+	label   string
+	total   int64
+	current atomic.Int64
+	start   time.Time
+	quit    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// newProgress starts rendering the progress to the writer. A nil
+// writer disables the rendering but the progress is still counted.
+func newProgress(w io.Writer, label string, total int64) *progress {
+	p := &progress{
+		w:     w,
+		label: label,
+		total: total,
+		start: time.Now(),
+		quit:  make(chan struct{}),
+	}
+	if w == nil {
+		return p
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(progressRenderInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.quit:
+				return
+			case <-ticker.C:
+				p.render()
+			}
+		}
+	}()
+	return p
+}
+
+// Add adds the number of transferred bytes.
+func (p *progress) Add(n int64) {
+	p.current.Add(n)
+}
+
+// reader counts the bytes read from r as transferred.
+func (p *progress) reader(r io.Reader) io.Reader {
+	return &progressReader{r: r, p: p}
+}
+
+func (p *progress) render() {
+	cur := p.current.Load()
+	if p.total > 0 && cur > p.total {
+		// the transferred data may include framing, as in the tar archives
+		cur = p.total
+	}
+	rate := float64(cur) / time.Since(p.start).Seconds()
+
+	var bar, pct string
+	if p.total > 0 {
+		done := int(float64(progressBarWidth) * float64(cur) / float64(p.total))
+		if done > progressBarWidth {
+			done = progressBarWidth
+		}
+		bar = "[" + strings.Repeat("#", done) + strings.Repeat(" ", progressBarWidth-done) + "] "
+		pct = fmt.Sprintf("%3.0f%% ", 100*float64(cur)/float64(p.total))
+		fmt.Fprintf(p.w, "\r%s %s%s%s/%s %s/s ", p.label, bar, pct, byteSize(float64(cur)), byteSize(float64(p.total)), byteSize(rate))
+		return
+	}
+	fmt.Fprintf(p.w, "\r%s %s %s/s ", p.label, byteSize(float64(cur)), byteSize(rate))
+}
+
+// Done stops the rendering and prints the final state.
+func (p *progress) Done() {
+	if p.w == nil {
+		return
+	}
+	close(p.quit)
+	p.wg.Wait()
+	p.render()
+	fmt.Fprintln(p.w)
+}
+
+type progressReader struct {
+	r io.Reader
+	p *progress
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.p.Add(int64(n))
+	return n, err
+}
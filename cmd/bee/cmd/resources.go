@@ -0,0 +1,82 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"os"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/ethersphere/bee/pkg/cgroup"
+	"github.com/ethersphere/bee/pkg/log"
+)
+
+const (
+	// gcMemoryLimitShare is the share of the memory limit of the container
+	// which is set as the soft memory limit of the garbage collector.
+	gcMemoryLimitShare = 0.9
+	// dbCacheShare is the inverse of the share of the memory limit of the
+	// container which is used by each of the database block cache and write
+	// buffer.
+	dbCacheShare = 16
+	// minDBCacheSize is the smallest size of the database block cache and
+	// write buffer sized to the memory limit of the container.
+	minDBCacheSize = 4 * 1024 * 1024
+)
+
+// configureContainerLimits sizes the resources of the node to the CPU and
+// memory limits of the container, as the defaults otherwise assume the whole
+// machine. The number of the threads running the goroutines, which bounds the
+// worker pools sized to it, follows the CPU limit. The target of the garbage
+// collector and the database caches follow the memory limit. The options which
+// are provided by the user and the GOMAXPROCS and GOMEMLIMIT environment
+// variables take precedence.
+func (c *command) configureContainerLimits(logger log.Logger) {
+	if !c.config.GetBool(optionNameContainerLimits) {
+		return
+	}
+
+	cpus, err := cgroup.CPULimit()
+	if err != nil {
+		logger.Warning("unable to read the cpu limit of the cgroup", "error", err)
+	}
+	if procs := int(cpus); cpus > 0 && os.Getenv("GOMAXPROCS") == "" {
+		if procs < 1 {
+			procs = 1
+		}
+		if procs < runtime.GOMAXPROCS(0) {
+			runtime.GOMAXPROCS(procs)
+			logger.Info("threads limited to the cpu limit of the container", "cpus", cpus, "gomaxprocs", procs)
+		}
+	}
+
+	memory, err := cgroup.MemoryLimit()
+	if err != nil {
+		logger.Warning("unable to read the memory limit of the cgroup", "error", err)
+	}
+	if memory <= 0 {
+		return
+	}
+	if os.Getenv("GOMEMLIMIT") == "" {
+		limit := int64(gcMemoryLimitShare * float64(memory))
+		debug.SetMemoryLimit(limit)
+		logger.Info("garbage collector target set to the memory limit of the container", "memory_limit", memory, "gc_limit", limit)
+	}
+	for _, name := range []string{optionNameDBBlockCacheCapacity, optionNameDBWriteBufferSize} {
+		if size := dbCacheSize(memory); size < c.config.GetUint64(name) {
+			c.config.SetDefault(name, size)
+		}
+	}
+}
+
+// dbCacheSize returns the size of the database block cache
+// and write buffer sized to the memory limit of the container.
+func dbCacheSize(memory int64) uint64 {
+	size := uint64(memory) / dbCacheShare
+	if size < minDBCacheSize {
+		size = minDBCacheSize
+	}
+	return size
+}
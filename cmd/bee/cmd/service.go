@@ -0,0 +1,153 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kardianos/service"
+	"github.com/spf13/cobra"
+)
+
+// serviceConfig returns the configuration of the system service which
+// runs the start command with the arguments.
+func serviceConfig(args []string) *service.Config {
+	return &service.Config{
+		Name:        serviceName,
+		DisplayName: "Bee",
+		Description: "Bee, Swarm client.",
+		Arguments:   append([]string{"start"}, args...),
+		Option: service.KeyValue{
+			// wait for the network to be up after the system boot
+			"DelayedAutoStart": true,
+		},
+	}
+}
+
+// serviceArguments returns the arguments of the start command for the
+// service. The service does not run in the home directory of the user
+// and it can not prompt for the password, so the absolute path of the
+// config file is required.
+func serviceArguments(cfgFile string, extra []string) ([]string, error) {
+	if cfgFile == "" {
+		return nil, errors.New("config file not provided")
+	}
+	path, err := filepath.Abs(cfgFile)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("config file: %w", err)
+	}
+	return append([]string{"--config", path}, extra...), nil
+}
+
+func (c *command) initServiceCmd() {
+	cmd := &cobra.Command{
+		Use:   "service",
+		Short: "Manage the system service of the node",
+		Long: `Manage the system service of the node
+
+Registers the node as a system service, which is started with the system and
+restarted if it fails, so that no third-party service wrappers are required.
+On Windows the service logs to the Windows Event Log. The service runs the start
+command with the config file, which must provide all the options, including the
+password file and the data directory, as the service can not prompt for them.
+Managing the service requires administrator privileges.`,
+		Example: `
+$> bee service install --config C:\bee\bee.yaml
+$> bee service start`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "install [-- start flags...]",
+		Short: "Install the service with the config file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			startArgs, err := serviceArguments(c.cfgFile, args)
+			if err != nil {
+				return err
+			}
+			if err := serviceControl(startArgs, "install"); err != nil {
+				return err
+			}
+			if err := setServiceRecovery(serviceName); err != nil {
+				return fmt.Errorf("set service recovery: %w", err)
+			}
+			cmd.Printf("service %s installed\n", serviceName)
+			return nil
+		},
+	})
+
+	for _, action := range []struct {
+		name  string
+		short string
+		done  string
+	}{
+		{name: "uninstall", short: "Uninstall the service", done: "uninstalled"},
+		{name: "start", short: "Start the service", done: "started"},
+		{name: "stop", short: "Stop the service", done: "stopped"},
+		{name: "restart", short: "Restart the service", done: "restarted"},
+	} {
+		action := action
+		cmd.AddCommand(&cobra.Command{
+			Use:   action.name,
+			Short: action.short,
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if err := serviceControl(nil, action.name); err != nil {
+					return err
+				}
+				cmd.Printf("service %s %s\n", serviceName, action.done)
+				return nil
+			},
+		})
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Print the status of the service",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := service.New(new(program), serviceConfig(nil))
+			if err != nil {
+				return err
+			}
+			status, err := s.Status()
+			if errors.Is(err, service.ErrNotInstalled) {
+				cmd.Println("not installed")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			switch status {
+			case service.StatusRunning:
+				cmd.Println("running")
+			case service.StatusStopped:
+				cmd.Println("stopped")
+			default:
+				cmd.Println("unknown")
+			}
+			return nil
+		},
+	})
+
+	cmd.SetOut(c.root.OutOrStdout())
+	c.root.AddCommand(cmd)
+}
+
+func serviceControl(args []string, action string) error {
+	s, err := service.New(new(program), serviceConfig(args))
+	if err != nil {
+		return err
+	}
+	if err := service.Control(s, action); err != nil {
+		return fmt.Errorf("%s service: %w", action, err)
+	}
+	return nil
+}
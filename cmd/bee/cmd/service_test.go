@@ -0,0 +1,38 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethersphere/bee/cmd/bee/cmd"
+)
+
+func TestServiceArguments(t *testing.T) {
+	t.Parallel()
+
+	configFile := filepath.Join(t.TempDir(), "bee.yaml")
+	if err := os.WriteFile(configFile, []byte("data-dir: /var/lib/bee\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := cmd.ServiceArguments(configFile, []string{"--verbosity", "debug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"--config", configFile, "--verbosity", "debug"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	_, err = cmd.ServiceArguments(filepath.Join(t.TempDir(), "missing.yaml"), nil)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got error %v, want %v", err, os.ErrNotExist)
+	}
+}
@@ -0,0 +1,514 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/bigint"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/spf13/cobra"
+)
+
+const (
+	optionNameStampsYes       = "yes"
+	optionNameStampsLabel     = "label"
+	optionNameStampsImmutable = "immutable"
+	optionNameStampsGasPrice  = "gas-price"
+	optionNameStampsDepth     = "depth"
+	optionNameStampsAmount    = "amount"
+	optionNameStampsSize      = "size"
+	optionNameStampsTTL       = "ttl"
+)
+
+const defaultDebugAPIEndpoint = "http://localhost:1635"
+
+// plurPerBZZ is the number of the smallest token units in one BZZ.
+var plurPerBZZ = big.NewInt(1e16)
+
+func (c *command) initStampsCmd() {
+	cmd := &cobra.Command{
+		Use:   "stamps",
+		Short: "Manage the postage batches of a running node",
+		Long: `Manage the postage batches of a running node
+
+The commands talk to the debug API of a running node. The amounts are given
+in PLUR per chunk, as in the API, while the costs are shown in BZZ together
+with the resulting capacity and time to live of the batches. The commands
+which spend funds ask for a confirmation unless --yes is given.`,
+	}
+
+	cmd.PersistentFlags().String(optionNameAPIEndpoint, defaultDebugAPIEndpoint, "debug API endpoint of the node")
+	cmd.PersistentFlags().Uint64(optionNameBlockTime, 15, "chain block time in seconds")
+
+	stampsListCmd(cmd)
+	stampsBuyCmd(cmd)
+	stampsTopupCmd(cmd)
+	stampsDiluteCmd(cmd)
+	stampsForecastCmd(cmd)
+
+	c.root.AddCommand(cmd)
+}
+
+func stampsListCmd(cmd *cobra.Command) {
+	c := &cobra.Command{
+		Use:   "list",
+		Short: "List the postage batches of the node",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := stampsAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+			stamps, err := listStamps(cmd.Context(), client)
+			if err != nil {
+				return err
+			}
+			if len(stamps) == 0 {
+				cmd.Println("no postage batches")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "BATCH ID\tLABEL\tDEPTH\tCAPACITY\tUTILIZATION\tTTL\tUSABLE\tIMMUTABLE")
+			for _, s := range stamps {
+				ttl := "expired"
+				if !s.Expired {
+					ttl = formatTTL(time.Duration(s.BatchTTL) * time.Second)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%.1f%%\t%s\t%t\t%t\n",
+					s.BatchID, s.Label, s.Depth, byteSize(batchCapacity(s.Depth)),
+					batchUtilization(s.Utilization, s.Depth, s.BucketDepth), ttl, s.Usable, s.Immutable)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.AddCommand(c)
+}
+
+func stampsBuyCmd(cmd *cobra.Command) {
+	c := &cobra.Command{
+		Use:   "buy <amount> <depth>",
+		Short: "Buy a new postage batch",
+		Example: `
+$> bee stamps buy 100000000 20 --label photos`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			amount, ok := new(big.Int).SetString(args[0], 10)
+			if !ok || amount.Sign() <= 0 {
+				return fmt.Errorf("invalid amount %q", args[0])
+			}
+			depth, err := strconv.ParseUint(args[1], 10, 8)
+			if err != nil || depth <= postage.BucketDepth {
+				return fmt.Errorf("invalid depth %q, must be greater than %d", args[1], postage.BucketDepth)
+			}
+			client, err := stampsAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+			state, err := getChainState(cmd.Context(), client)
+			if err != nil {
+				return err
+			}
+			blockTime, _ := cmd.Flags().GetUint64(optionNameBlockTime)
+
+			cmd.Printf("Buying a batch of depth %d with the capacity of %s.\n", depth, byteSize(batchCapacity(uint8(depth))))
+			cmd.Printf("Cost: %s BZZ, time to live: %s.\n", formatBZZ(batchCost(amount, uint8(depth))), formatTTL(amountTTL(amount, state.CurrentPrice.Int, blockTime)))
+			if ok, err := confirm(cmd, "Buy the batch?"); err != nil || !ok {
+				return err
+			}
+
+			path := fmt.Sprintf("/stamps/%s/%d", amount, depth)
+			if label, _ := cmd.Flags().GetString(optionNameStampsLabel); label != "" {
+				path += "?label=" + url.QueryEscape(label)
+			}
+			header := http.Header{}
+			if immutable, _ := cmd.Flags().GetBool(optionNameStampsImmutable); immutable {
+				header.Set("Immutable", "true")
+			}
+			if err := setGasPrice(cmd, header); err != nil {
+				return err
+			}
+
+			var resp struct {
+				BatchID string `json:"batchID"`
+				TxHash  string `json:"txHash"`
+			}
+			if err := client.requestJSON(cmd.Context(), http.MethodPost, path, nil, header, &resp); err != nil {
+				return fmt.Errorf("buy batch: %w", err)
+			}
+			cmd.Printf("Batch ID: %s\nTransaction: %s\n", resp.BatchID, resp.TxHash)
+			return nil
+		},
+	}
+	c.Flags().String(optionNameStampsLabel, "", "label of the batch")
+	c.Flags().Bool(optionNameStampsImmutable, false, "create an immutable batch")
+	c.Flags().String(optionNameStampsGasPrice, "", "gas price of the transaction in wei")
+	c.Flags().Bool(optionNameStampsYes, false, "do not ask for a confirmation")
+	cmd.AddCommand(c)
+}
+
+func stampsTopupCmd(cmd *cobra.Command) {
+	c := &cobra.Command{
+		Use:   "topup <batch-id> <amount>",
+		Short: "Top up a postage batch to extend its time to live",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			amount, ok := new(big.Int).SetString(args[1], 10)
+			if !ok || amount.Sign() <= 0 {
+				return fmt.Errorf("invalid amount %q", args[1])
+			}
+			client, err := stampsAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+			batch, err := getStamp(cmd.Context(), client, args[0])
+			if err != nil {
+				return err
+			}
+			state, err := getChainState(cmd.Context(), client)
+			if err != nil {
+				return err
+			}
+			blockTime, _ := cmd.Flags().GetUint64(optionNameBlockTime)
+
+			extension := amountTTL(amount, state.CurrentPrice.Int, blockTime)
+			cmd.Printf("Topping up the batch %s of depth %d.\n", batch.BatchID, batch.Depth)
+			cmd.Printf("Cost: %s BZZ, time to live: %s -> %s.\n", formatBZZ(batchCost(amount, batch.Depth)),
+				formatTTL(time.Duration(batch.BatchTTL)*time.Second), formatTTL(time.Duration(batch.BatchTTL)*time.Second+extension))
+			if ok, err := confirm(cmd, "Top up the batch?"); err != nil || !ok {
+				return err
+			}
+
+			header := http.Header{}
+			if err := setGasPrice(cmd, header); err != nil {
+				return err
+			}
+			var resp struct {
+				TxHash string `json:"txHash"`
+			}
+			if err := client.requestJSON(cmd.Context(), http.MethodPatch, fmt.Sprintf("/stamps/topup/%s/%s", batch.BatchID, amount), nil, header, &resp); err != nil {
+				return fmt.Errorf("top up batch: %w", err)
+			}
+			cmd.Printf("Transaction: %s\n", resp.TxHash)
+			return nil
+		},
+	}
+	c.Flags().String(optionNameStampsGasPrice, "", "gas price of the transaction in wei")
+	c.Flags().Bool(optionNameStampsYes, false, "do not ask for a confirmation")
+	cmd.AddCommand(c)
+}
+
+func stampsDiluteCmd(cmd *cobra.Command) {
+	c := &cobra.Command{
+		Use:   "dilute <batch-id> <depth>",
+		Short: "Increase the depth of a postage batch at the expense of its time to live",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			depth, err := strconv.ParseUint(args[1], 10, 8)
+			if err != nil {
+				return fmt.Errorf("invalid depth %q", args[1])
+			}
+			client, err := stampsAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+			batch, err := getStamp(cmd.Context(), client, args[0])
+			if err != nil {
+				return err
+			}
+			if uint8(depth) <= batch.Depth {
+				return fmt.Errorf("new depth %d must be greater than the current depth %d", depth, batch.Depth)
+			}
+
+			// every additional depth halves the time to live
+			ttl := time.Duration(batch.BatchTTL) * time.Second >> (uint8(depth) - batch.Depth)
+			cmd.Printf("Diluting the batch %s from depth %d to %d.\n", batch.BatchID, batch.Depth, depth)
+			cmd.Printf("Capacity: %s -> %s, time to live: %s -> %s.\n",
+				byteSize(batchCapacity(batch.Depth)), byteSize(batchCapacity(uint8(depth))),
+				formatTTL(time.Duration(batch.BatchTTL)*time.Second), formatTTL(ttl))
+			if ok, err := confirm(cmd, "Dilute the batch?"); err != nil || !ok {
+				return err
+			}
+
+			header := http.Header{}
+			if err := setGasPrice(cmd, header); err != nil {
+				return err
+			}
+			var resp struct {
+				TxHash string `json:"txHash"`
+			}
+			if err := client.requestJSON(cmd.Context(), http.MethodPatch, fmt.Sprintf("/stamps/dilute/%s/%d", batch.BatchID, depth), nil, header, &resp); err != nil {
+				return fmt.Errorf("dilute batch: %w", err)
+			}
+			cmd.Printf("Transaction: %s\n", resp.TxHash)
+			return nil
+		},
+	}
+	c.Flags().String(optionNameStampsGasPrice, "", "gas price of the transaction in wei")
+	c.Flags().Bool(optionNameStampsYes, false, "do not ask for a confirmation")
+	cmd.AddCommand(c)
+}
+
+func stampsForecastCmd(cmd *cobra.Command) {
+	c := &cobra.Command{
+		Use:   "forecast",
+		Short: "Estimate the cost, capacity and time to live of a batch",
+		Long: `Estimate the cost, capacity and time to live of a batch
+
+The depth is given directly or derived from the size of the data to store, and
+the amount is given directly or derived from the required time to live. The
+estimate uses the current storage price of the chain.`,
+		Example: `
+$> bee stamps forecast --size 5GB --ttl 30d
+$> bee stamps forecast --depth 20 --amount 100000000`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			depth, _ := cmd.Flags().GetUint8(optionNameStampsDepth)
+			if size, _ := cmd.Flags().GetString(optionNameStampsSize); size != "" {
+				bytes, err := parseByteSize(size)
+				if err != nil {
+					return err
+				}
+				depth = depthForSize(bytes)
+			}
+			if depth <= postage.BucketDepth {
+				return fmt.Errorf("either --%s or --%s greater than %d is required", optionNameStampsSize, optionNameStampsDepth, postage.BucketDepth)
+			}
+
+			client, err := stampsAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+			state, err := getChainState(cmd.Context(), client)
+			if err != nil {
+				return err
+			}
+			blockTime, _ := cmd.Flags().GetUint64(optionNameBlockTime)
+
+			var amount *big.Int
+			if s, _ := cmd.Flags().GetString(optionNameStampsTTL); s != "" {
+				ttl, err := parseTTL(s)
+				if err != nil {
+					return err
+				}
+				amount = ttlAmount(ttl, state.CurrentPrice.Int, blockTime)
+			} else {
+				s, _ := cmd.Flags().GetString(optionNameStampsAmount)
+				var ok bool
+				if amount, ok = new(big.Int).SetString(s, 10); !ok || amount.Sign() <= 0 {
+					return fmt.Errorf("either --%s or a positive --%s is required", optionNameStampsTTL, optionNameStampsAmount)
+				}
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "Depth\t%d\n", depth)
+			fmt.Fprintf(w, "Capacity\t%s\n", byteSize(batchCapacity(depth)))
+			fmt.Fprintf(w, "Amount\t%s PLUR per chunk\n", amount)
+			fmt.Fprintf(w, "Cost\t%s BZZ\n", formatBZZ(batchCost(amount, depth)))
+			fmt.Fprintf(w, "Time to live\t%s\n", formatTTL(amountTTL(amount, state.CurrentPrice.Int, blockTime)))
+			fmt.Fprintf(w, "Price\t%s PLUR per chunk per block\n", state.CurrentPrice)
+			return w.Flush()
+		},
+	}
+	c.Flags().Uint8(optionNameStampsDepth, 0, "depth of the batch")
+	c.Flags().String(optionNameStampsSize, "", "size of the data to store, like 500MB or 2GiB")
+	c.Flags().String(optionNameStampsAmount, "", "amount in PLUR per chunk")
+	c.Flags().String(optionNameStampsTTL, "", "required time to live, like 720h or 30d")
+	cmd.AddCommand(c)
+}
+
+func stampsAPIClient(cmd *cobra.Command) (*apiClient, error) {
+	endpoint, err := cmd.Flags().GetString(optionNameAPIEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	return newAPIClient(endpoint, http.DefaultClient), nil
+}
+
+func getStamp(ctx context.Context, client *apiClient, batchID string) (*stampInfo, error) {
+	var s stampInfo
+	if err := client.getJSON(ctx, "/stamps/"+url.PathEscape(batchID), &s); err != nil {
+		return nil, fmt.Errorf("get batch: %w", err)
+	}
+	return &s, nil
+}
+
+type chainState struct {
+	ChainTip     uint64         `json:"chainTip"`
+	TotalAmount  *bigint.BigInt `json:"totalAmount"`
+	CurrentPrice *bigint.BigInt `json:"currentPrice"`
+}
+
+func getChainState(ctx context.Context, client *apiClient) (*chainState, error) {
+	var s chainState
+	if err := client.getJSON(ctx, "/chainstate", &s); err != nil {
+		return nil, fmt.Errorf("get chain state: %w", err)
+	}
+	if s.CurrentPrice == nil || s.CurrentPrice.Int == nil {
+		s.CurrentPrice = bigint.Wrap(new(big.Int))
+	}
+	return &s, nil
+}
+
+func setGasPrice(cmd *cobra.Command, header http.Header) error {
+	gasPrice, _ := cmd.Flags().GetString(optionNameStampsGasPrice)
+	if gasPrice == "" {
+		return nil
+	}
+	if _, ok := new(big.Int).SetString(gasPrice, 10); !ok {
+		return fmt.Errorf("invalid gas price %q", gasPrice)
+	}
+	header.Set("Gas-Price", gasPrice)
+	return nil
+}
+
+// confirm asks the user to confirm the action unless it is confirmed by the flag.
+func confirm(cmd *cobra.Command, question string) (bool, error) {
+	if yes, _ := cmd.Flags().GetBool(optionNameStampsYes); yes {
+		return true, nil
+	}
+	cmd.Print(question + " [y/N]: ")
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, fmt.Errorf("read confirmation: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	cmd.Println("Aborted.")
+	return false, nil
+}
+
+// batchCapacity returns the theoretical number of bytes
+// which can be stamped by a batch of the given depth.
+func batchCapacity(depth uint8) float64 {
+	return math.Pow(2, float64(depth)) * swarm.ChunkSize
+}
+
+// batchUtilization returns the fill level of the fullest bucket in percents.
+func batchUtilization(utilization uint32, depth, bucketDepth uint8) float64 {
+	if depth <= bucketDepth {
+		return 0
+	}
+	return 100 * float64(utilization) / math.Pow(2, float64(depth-bucketDepth))
+}
+
+// batchCost returns the cost in PLUR of a batch with the given amount per chunk.
+func batchCost(amount *big.Int, depth uint8) *big.Int {
+	return new(big.Int).Lsh(amount, uint(depth))
+}
+
+// amountTTL returns the time for which the amount per chunk pays at the price.
+func amountTTL(amount, price *big.Int, blockTime uint64) time.Duration {
+	if price.Sign() <= 0 {
+		return -1
+	}
+	blocks := new(big.Int).Div(amount, price)
+	seconds := blocks.Mul(blocks, new(big.Int).SetUint64(blockTime))
+	if !seconds.IsInt64() || seconds.Int64() > math.MaxInt64/int64(time.Second) {
+		return math.MaxInt64
+	}
+	return time.Duration(seconds.Int64()) * time.Second
+}
+
+// ttlAmount returns the amount per chunk which pays for the time at the price.
+func ttlAmount(ttl time.Duration, price *big.Int, blockTime uint64) *big.Int {
+	if blockTime == 0 {
+		blockTime = 1
+	}
+	blocks := (uint64(ttl/time.Second) + blockTime - 1) / blockTime
+	return new(big.Int).Mul(new(big.Int).SetUint64(blocks), price)
+}
+
+// depthForSize returns the smallest valid depth of a batch
+// with the theoretical capacity of at least the given size.
+func depthForSize(size uint64) uint8 {
+	chunks := (size + swarm.ChunkSize - 1) / swarm.ChunkSize
+	depth := uint8(postage.BucketDepth + 1)
+	for depth < 64 && uint64(1)<<depth < chunks {
+		depth++
+	}
+	return depth
+}
+
+// formatBZZ formats the PLUR amount in BZZ without the trailing zeros.
+func formatBZZ(plur *big.Int) string {
+	s := new(big.Rat).SetFrac(plur, plurPerBZZ).FloatString(16)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+// formatTTL formats the duration in days, hours and minutes.
+func formatTTL(d time.Duration) string {
+	switch {
+	case d < 0:
+		return "unknown"
+	case d == math.MaxInt64:
+		return "unlimited"
+	}
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
+
+// parseTTL parses a duration which may also be given in days, like 30d.
+func parseTTL(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.ParseUint(days, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time to live %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid time to live %q", s)
+	}
+	return d, nil
+}
+
+var byteSizeUnits = []struct {
+	suffix string
+	size   uint64
+}{
+	// the longer suffixes first, so that KiB is not matched as B
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30}, {"TiB", 1 << 40},
+	{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12},
+	{"B", 1},
+}
+
+// parseByteSize parses a size with an optional decimal or binary unit.
+func parseByteSize(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	multiplier := uint64(1)
+	for _, u := range byteSizeUnits {
+		if strings.HasSuffix(strings.ToUpper(s), strings.ToUpper(u.suffix)) {
+			s, multiplier = strings.TrimSpace(s[:len(s)-len(u.suffix)]), u.size
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n <= 0 {
+		return 0, errors.New("invalid size")
+	}
+	return uint64(n * float64(multiplier)), nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethersphere/bee/cmd/bee/cmd"
+)
+
+const testBatchID = "7a9a0b6b42c2c29c5a8d0ff6f4b41e5c3a1c1b2a0e1d2c3b4a5968778695e36e"
+
+func newStampsServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/chainstate":
+			fmt.Fprint(w, `{"chainTip":100,"block":90,"totalAmount":"1000","currentPrice":"10"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/stamps":
+			fmt.Fprintf(w, `{"stamps":[{"batchID":%q,"label":"photos","depth":20,"bucketDepth":16,"utilization":4,"usable":true,"amount":"1000","batchTTL":90000}]}`, testBatchID)
+		case r.Method == http.MethodGet && r.URL.Path == "/stamps/"+testBatchID:
+			fmt.Fprintf(w, `{"batchID":%q,"depth":20,"bucketDepth":16,"batchTTL":172800}`, testBatchID)
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/stamps/"):
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"batchID":%q,"txHash":"0x01"}`, testBatchID)
+		case r.Method == http.MethodPatch:
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprintf(w, `{"batchID":%q,"txHash":"0x02"}`, testBatchID)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requests...)
+	}
+}
+
+func TestStampsCmd(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		args        []string
+		input       string
+		wantOutput  []string
+		wantRequest string
+	}{
+		{
+			name:       "list",
+			args:       []string{"list"},
+			wantOutput: []string{testBatchID, "photos", "4.0 GiB", "25.0%", "1d 1h 0m"},
+		},
+		{
+			name:        "buy",
+			args:        []string{"buy", "--yes", "--label", "my photos", "--immutable", "72000", "20"},
+			wantOutput:  []string{"capacity of 4.0 GiB", "Cost: 0.0000075497472 BZZ, time to live: 1d 6h 0m", "Batch ID: " + testBatchID},
+			wantRequest: "POST /stamps/72000/20?label=my+photos",
+		},
+		{
+			name:       "buy aborted",
+			args:       []string{"buy", "72000", "20"},
+			input:      "n\n",
+			wantOutput: []string{"Buy the batch? [y/N]: Aborted."},
+		},
+		{
+			name:        "topup confirmed",
+			args:        []string{"topup", testBatchID, "7200"},
+			input:       "y\n",
+			wantOutput:  []string{"time to live: 2d 0h 0m -> 2d 3h 0m", "Transaction: 0x02"},
+			wantRequest: "PATCH /stamps/topup/" + testBatchID + "/7200",
+		},
+		{
+			name:        "dilute",
+			args:        []string{"dilute", "--yes", testBatchID, "22"},
+			wantOutput:  []string{"Capacity: 4.0 GiB -> 16.0 GiB, time to live: 2d 0h 0m -> 12h 0m"},
+			wantRequest: "PATCH /stamps/dilute/" + testBatchID + "/22",
+		},
+		{
+			name:       "forecast",
+			args:       []string{"forecast", "--size", "5GB", "--ttl", "1d"},
+			wantOutput: []string{"Depth         21", "Capacity      8.0 GiB", "Amount        57600 PLUR per chunk", "Time to live  1d 0h 0m"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv, requests := newStampsServer(t)
+
+			var outputBuf bytes.Buffer
+			if err := newCommand(t,
+				cmd.WithArgs(append(append([]string{"stamps"}, tc.args...), "--endpoint", srv.URL)...),
+				cmd.WithInput(strings.NewReader(tc.input)),
+				cmd.WithOutput(&outputBuf),
+			).Execute(); err != nil {
+				t.Fatal(err)
+			}
+
+			got := outputBuf.String()
+			for _, want := range tc.wantOutput {
+				if !strings.Contains(got, want) {
+					t.Errorf("output does not contain %q:\n%s", want, got)
+				}
+			}
+
+			var found bool
+			for _, r := range requests() {
+				if strings.HasPrefix(r, http.MethodPost) || strings.HasPrefix(r, http.MethodPatch) {
+					if r != tc.wantRequest {
+						t.Errorf("got request %q, want %q", r, tc.wantRequest)
+					}
+					found = true
+				}
+			}
+			if !found && tc.wantRequest != "" {
+				t.Errorf("request %q not sent", tc.wantRequest)
+			}
+		})
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/ethersphere/bee/pkg/resolver/multiresolver"
 	"github.com/ethersphere/bee/pkg/spinlock"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/systemd"
 	"github.com/kardianos/service"
 	"github.com/spf13/cobra"
 )
@@ -64,7 +65,16 @@ func (c *command) initStartCmd() (err error) {
 
 			v := strings.ToLower(c.config.GetString(optionNameVerbosity))
 
-			logger, err := newLogger(cmd, v)
+			var logOpts []log.Option
+			if c.config.GetBool(optionNameLogJournald) {
+				sink, err := systemd.NewJournalWriter()
+				if err != nil {
+					return err
+				}
+				logOpts = append(logOpts, log.WithSink(sink), log.WithJSONOutput())
+			}
+
+			logger, err := newLogger(cmd, v, logOpts...)
 			if err != nil {
 				return fmt.Errorf("new logger: %w", err)
 			}
@@ -80,6 +90,8 @@ func (c *command) initStartCmd() (err error) {
 			fmt.Printf("\n\nversion: %v - planned to be supported until %v, please follow https://ethswarm.org/\n\n", bee.Version, endSupportDate())
 			logger.Info("bee version", "version", bee.Version)
 
+			c.configureContainerLimits(logger)
+
 			go startTimeBomb(logger)
 
 			// ctx is global context of bee node; which is canceled after interrupt signal is received.
@@ -99,6 +111,12 @@ func (c *command) initStartCmd() (err error) {
 			// Building bee node can take up some time (because node.NewBee(...) is compute have function )
 			// Because of this we need to do it in background so that program could be terminated when interrupt singal is received
 			// while bee node is being constructed.
+			var notifier *systemd.Notifier
+			if c.config.GetBool(optionNameSystemdNotify) {
+				notifier = systemd.NewNotifier(logger)
+				notifier.Status("starting")
+			}
+
 			respC := buildBeeNodeAsync(ctx, c, cmd, logger)
 			var beeNode atomic.Value
 
@@ -109,6 +127,11 @@ func (c *command) initStartCmd() (err error) {
 					case resp := <-respC:
 						if resp.err != nil {
 							logger.Error(resp.err, "failed to build bee node")
+							if c.isWindowsService {
+								// exit with the failure so that the
+								// service manager restarts the service
+								os.Exit(1)
+							}
 							return
 						}
 						beeNode.Store(resp.bee)
@@ -116,6 +139,17 @@ func (c *command) initStartCmd() (err error) {
 						return
 					}
 
+					if notifier != nil {
+						notifier.Status("warming up")
+						go func(b *node.Bee) {
+							select {
+							case <-b.Ready():
+								notifier.Ready()
+							case <-ctx.Done():
+							}
+						}(beeNode.Load().(*node.Bee))
+					}
+
 					// Bee has fully started at this point, from now on we
 					// block main goroutine until it is interrupted or stopped
 					select {
@@ -127,6 +161,11 @@ func (c *command) initStartCmd() (err error) {
 					logger.Info("shutting down...")
 				},
 				stop: func() {
+					if notifier != nil {
+						notifier.Stopping()
+						defer notifier.Close()
+					}
+
 					// Whenever program is being stopped we need to cancel main context
 					// beforehand so that node could be stopped via Shutdown method
 					cancel()
@@ -158,11 +197,7 @@ func (c *command) initStartCmd() (err error) {
 			}
 
 			if c.isWindowsService {
-				s, err := service.New(p, &service.Config{
-					Name:        serviceName,
-					DisplayName: "Bee",
-					Description: "Bee, Swarm client.",
-				})
+				s, err := service.New(p, serviceConfig(nil))
 				if err != nil {
 					return err
 				}
@@ -179,7 +214,7 @@ func (c *command) initStartCmd() (err error) {
 			return nil
 		},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			return c.config.BindPFlags(cmd.Flags())
+			return c.bindAllFlags(cmd)
 		},
 	}
 
@@ -286,6 +321,17 @@ func buildBeeNode(ctx context.Context, c *command, cmd *cobra.Command, logger lo
 		return nil, errors.New("static nodes can only be configured on bootnodes")
 	}
 
+	allowlistOverlaysOpt := c.config.GetStringSlice(optionNameAllowlistOverlays)
+	allowlistOverlays := make([]swarm.Address, 0, len(allowlistOverlaysOpt))
+	for _, p := range allowlistOverlaysOpt {
+		addr, err := swarm.ParseHexAddress(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid swarm address %q configured for allowlist", p)
+		}
+
+		allowlistOverlays = append(allowlistOverlays, addr)
+	}
+
 	swapEndpoint := c.config.GetString(optionNameSwapEndpoint)
 	blockchainRpcEndpoint := c.config.GetString(optionNameBlockchainRpcEndpoint)
 	if swapEndpoint != "" {
@@ -295,6 +341,7 @@ func buildBeeNode(ctx context.Context, c *command, cmd *cobra.Command, logger lo
 	b, err := node.NewBee(ctx, c.config.GetString(optionNameP2PAddr), signerConfig.publicKey, signerConfig.signer, networkID, logger, signerConfig.libp2pPrivateKey, signerConfig.pssPrivateKey, &node.Options{
 		DataDir:                       c.config.GetString(optionNameDataDir),
 		CacheCapacity:                 c.config.GetUint64(optionNameCacheCapacity),
+		ExpiredBatchRetention:         c.config.GetDuration(optionNameExpiredBatchRetention),
 		DBOpenFilesLimit:              c.config.GetUint64(optionNameDBOpenFilesLimit),
 		DBBlockCacheCapacity:          c.config.GetUint64(optionNameDBBlockCacheCapacity),
 		DBWriteBufferSize:             c.config.GetUint64(optionNameDBWriteBufferSize),
@@ -343,6 +390,86 @@ func buildBeeNode(ctx context.Context, c *command, cmd *cobra.Command, logger lo
 		AdminPasswordHash:             c.config.GetString(optionNameAdminPasswordHash),
 		UsePostageSnapshot:            c.config.GetBool(optionNameUsePostageSnapshot),
 		EnableStorageIncentives:       c.config.GetBool(optionNameStorageIncentivesEnable),
+		TopologySnapshotDir:           c.config.GetString(optionNameTopologySnapshotDir),
+		TopologySnapshotInterval:      c.config.GetDuration(optionNameTopologySnapshotInterval),
+		ReserveBootstrap:              c.config.GetBool(optionNameReserveBootstrap),
+		ReserveSnapshotServe:          c.config.GetBool(optionNameReserveSnapshotServe),
+		PinExpiryWebhooks:             c.config.GetStringSlice(optionNamePinExpiryWebhooks),
+		PostageEventWebhooks:          c.config.GetStringSlice(optionNamePostageEventWebhooks),
+		PostageExpiryWarning:          c.config.GetDuration(optionNamePostageExpiryWarning),
+		PssMinDelay:                   c.config.GetDuration(optionNamePssMinDelay),
+		PssMaxDelay:                   c.config.GetDuration(optionNamePssMaxDelay),
+		PssCoverInterval:              c.config.GetDuration(optionNamePssCoverInterval),
+		PssCoverBudget:                c.config.GetInt(optionNamePssCoverBudget),
+		AllowlistOverlays:             allowlistOverlays,
+		AllowlistUnderlays:            c.config.GetStringSlice(optionNameAllowlistUnderlays),
+		AllowlistAuthority:            c.config.GetString(optionNameAllowlistAuthority),
+		AllowlistAttestation:          c.config.GetString(optionNameAllowlistAttestation),
+		AuditLog:                      c.config.GetString(optionNameAuditLog),
+		AuditLogMaxSize:               c.config.GetInt64(optionNameAuditLogMaxSize) * 1024 * 1024,
+		AuditLogMaxBackups:            c.config.GetInt(optionNameAuditLogMaxBackups),
+		AuditLogRedact:                c.config.GetStringSlice(optionNameAuditLogRedact),
+		AuditLogTrustedProxies:        c.config.GetStringSlice(optionNameAuditLogTrustedProxies),
+		ProfilingEndpoint:             c.config.GetString(optionNameProfilingEndpoint),
+		ProfilingTypes:                c.config.GetStringSlice(optionNameProfilingTypes),
+		ProfilingInterval:             c.config.GetDuration(optionNameProfilingInterval),
+		ProfilingLabels:               c.config.GetStringSlice(optionNameProfilingLabels),
+		SharedCache:                   c.config.GetString(optionNameSharedCache),
+		SharedCacheTTL:                c.config.GetDuration(optionNameSharedCacheTTL),
+		SharedStateStore:              c.config.GetString(optionNameSharedStateStore),
+		ImageTransform:                c.config.GetBool(optionNameImageTransform),
+		TransformCacheSize:            c.config.GetUint64(optionNameTransformCacheSize),
+		RetrievalFallbackGateways:     c.config.GetStringSlice(optionNameRetrievalFallbackGateways),
+		RetrievalFallbackBudget:       c.config.GetDuration(optionNameRetrievalFallbackBudget),
+		RetrievalInteractiveWorkers:   c.config.GetInt(optionNameInteractiveWorkers),
+		RetrievalInteractiveBudget:    c.config.GetUint64(optionNameInteractiveBudget),
+		RetrievalBackgroundWorkers:    c.config.GetInt(optionNameBackgroundWorkers),
+		RetrievalBackgroundBudget:     c.config.GetUint64(optionNameBackgroundBudget),
+		IPFSGateway:                   c.config.GetString(optionNameIPFSGateway),
+		ChallengeMode:                 c.config.GetString(optionNameChallengeMode),
+		ChallengeDifficulty:           c.config.GetUint(optionNameChallengeDifficulty),
+		ChallengeTokenTTL:             c.config.GetDuration(optionNameChallengeTokenTTL),
+		ChallengeRate:                 c.config.GetFloat64(optionNameChallengeRate),
+		ChallengeBurst:                c.config.GetInt(optionNameChallengeBurst),
+		ChallengeRoutes:               c.config.GetStringSlice(optionNameChallengeRoutes),
+		ChallengeExempt:               c.config.GetStringSlice(optionNameChallengeExempt),
+		ChallengeSecret:               c.config.GetString(optionNameChallengeSecret),
+		MaxConcurrentDownloads:        c.config.GetInt(optionNameMaxConcurrentDownloads),
+		ClientConcurrentDownloads:     c.config.GetInt(optionNameClientConcurrentDownloads),
+		ReferenceConcurrentDownloads:  c.config.GetInt(optionNameRefConcurrentDownloads),
+		DownloadQueueSize:             c.config.GetInt(optionNameDownloadQueueSize),
+		DownloadQueueTimeout:          c.config.GetDuration(optionNameDownloadQueueTimeout),
+		DownloadClientBandwidth:       c.config.GetInt64(optionNameDownloadClientBandwidth),
+		DownloadClientBurst:           c.config.GetInt64(optionNameDownloadClientBurst),
+		MaxDownloadJoiners:            c.config.GetInt(optionNameMaxDownloadJoiners),
+		BatchPruneInterval:            c.config.GetDuration(optionNameBatchPruneInterval),
+		SpamDetection:                 c.config.GetBool(optionNameSpamDetection),
+		SpamWindow:                    c.config.GetDuration(optionNameSpamWindow),
+		SpamMaxRate:                   c.config.GetFloat64(optionNameSpamMaxRate),
+		SpamMaxSkew:                   c.config.GetFloat64(optionNameSpamMaxSkew),
+		SpamDeprioritize:              c.config.GetBool(optionNameSpamDeprioritize),
+		DepthDecreaseThreshold:        c.config.GetFloat64(optionNameDepthDecreaseThreshold),
+		DepthCooldown:                 c.config.GetDuration(optionNameDepthCooldown),
+		DepthConfirmations:            c.config.GetInt(optionNameDepthConfirmations),
+		ReplicaPrimaryAPI:             c.config.GetString(optionNameReplicaPrimaryAPI),
+		ReplicaInterval:               c.config.GetDuration(optionNameReplicaInterval),
+		ReplicaToken:                  c.config.GetString(optionNameReplicaToken),
+		APILookahead:                  c.config.GetString(optionNameAPILookahead),
+		APICompressionTypes:           c.config.GetString(optionNameAPICompressionTypes),
+		APICompressionMinSize:         c.config.GetInt64(optionNameAPICompressionMinSize),
+		MemoryBudget:                  c.config.GetUint64(optionNameMemoryBudget),
+		LowPower:                      c.config.GetBool(optionNameLowPower),
+		MaintenanceWindows:            strings.Split(c.config.GetString(optionNameMaintenanceWindows), ";"),
+		DBCompactionInterval:          c.config.GetDuration(optionNameDBCompactionInterval),
+		CashoutInterval:               c.config.GetDuration(optionNameCashoutInterval),
+		CashoutMinAmount:              c.config.GetString(optionNameCashoutMinAmount),
+		GraphQLEnable:                 c.config.GetBool(optionNameGraphQLEnable),
+		DNSLinkProvider:               c.config.GetString(optionNameDNSLinkProvider),
+		DNSLinkFeeds:                  c.config.GetStringSlice(optionNameDNSLinkFeeds),
+		DNSLinkInterval:               c.config.GetDuration(optionNameDNSLinkInterval),
+		EnableDirListing:              c.config.GetBool(optionNameEnableDirListing),
+		GatewayDomain:                 c.config.GetString(optionNameGatewayDomain),
+		GatewaySubdomainOnly:          c.config.GetBool(optionNameGatewaySubdomainOnly),
 	})
 
 	return b, err
@@ -19,3 +19,7 @@ func isWindowsService() (bool, error) {
 func createWindowsEventLogger(_ string, _ log.Logger) (log.Logger, error) {
 	return nil, errors.New("cannot create Windows event logger")
 }
+
+func setServiceRecovery(_ string) error {
+	return nil
+}
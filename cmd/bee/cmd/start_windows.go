@@ -8,10 +8,12 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"golang.org/x/sys/windows/svc"
 	"golang.org/x/sys/windows/svc/debug"
 	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
 
 	"github.com/ethersphere/bee/pkg/log"
 )
@@ -20,6 +22,28 @@ func isWindowsService() (bool, error) {
 	return svc.IsWindowsService()
 }
 
+// setServiceRecovery configures the service manager to restart
+// the service when it fails.
+func setServiceRecovery(svcName string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = m.Disconnect() }()
+
+	s, err := m.OpenService(svcName)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	return s.SetRecoveryActions([]mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: 10 * time.Second},
+		{Type: mgr.ServiceRestart, Delay: 30 * time.Second},
+		{Type: mgr.ServiceRestart, Delay: time.Minute},
+	}, uint32((24 * time.Hour).Seconds()))
+}
+
 func createWindowsEventLogger(svcName string, logger log.Logger) (log.Logger, error) {
 	el, err := eventlog.Open(svcName)
 	if err != nil {
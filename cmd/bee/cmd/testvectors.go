@@ -0,0 +1,55 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ethersphere/bee/pkg/testvectors"
+	"github.com/spf13/cobra"
+)
+
+const optionNameTestVectorsOutput = "output"
+
+func (c *command) initTestVectorsCmd() (err error) {
+	cmd := &cobra.Command{
+		Use:   "test-vectors",
+		Short: "Generate manifest and feed test vectors",
+		Long: `Generate manifest and feed test vectors
+
+Prints the deterministic test vectors of the mantaray manifest encoding and
+the feed update construction in the JSON format. Client library authors can
+use them to verify byte-level compatibility of their implementations.`,
+		Example: `
+$> bee test-vectors --output vectors.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v, err := testvectors.Generate(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("generate test vectors: %w", err)
+			}
+			b, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				return fmt.Errorf("marshal test vectors: %w", err)
+			}
+
+			if output := c.config.GetString(optionNameTestVectorsOutput); output != "" {
+				return os.WriteFile(output, append(b, '\n'), 0o644)
+			}
+			cmd.Println(string(b))
+			return nil
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return c.config.BindPFlags(cmd.Flags())
+		},
+	}
+
+	cmd.Flags().String(optionNameTestVectorsOutput, "", "file to write the test vectors to instead of the standard output")
+
+	cmd.SetOut(c.root.OutOrStdout())
+	c.root.AddCommand(cmd)
+	return nil
+}
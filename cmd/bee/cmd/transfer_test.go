@@ -0,0 +1,375 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/cmd/bee/cmd"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestUploadCmd(t *testing.T) {
+	t.Parallel()
+
+	const (
+		batchShort = "1111111111111111111111111111111111111111111111111111111111111111"
+		batchLong  = "2222222222222222222222222222222222222222222222222222222222222222"
+		fileRef    = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		dirRef     = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	)
+
+	var (
+		mu    sync.Mutex
+		files []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/stamps":
+			fmt.Fprintf(w, `{"stamps":[{"batchID":%q,"usable":true,"batchTTL":10},{"batchID":%q,"usable":true,"batchTTL":100},{"batchID":"33","usable":false,"batchTTL":1000}]}`, batchShort, batchLong)
+		case r.Method == http.MethodPost && r.URL.Path == "/bzz":
+			if got := r.Header.Get(api.SwarmPostageBatchIdHeader); got != batchLong {
+				http.Error(w, "wrong batch "+got, http.StatusBadRequest)
+				return
+			}
+			ref := fileRef
+			if r.Header.Get(api.SwarmCollectionHeader) == "true" {
+				ref = dirRef
+				tr := tar.NewReader(r.Body)
+				for {
+					h, err := tr.Next()
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					if err != nil {
+						http.Error(w, err.Error(), http.StatusBadRequest)
+						return
+					}
+					mu.Lock()
+					files = append(files, h.Name)
+					mu.Unlock()
+				}
+			} else if r.URL.Query().Get("name") != "hello.txt" {
+				http.Error(w, "wrong name", http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"reference":%q}`, ref)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "hello.txt")
+	if err := os.WriteFile(file, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	site := filepath.Join(dir, "site")
+	if err := os.MkdirAll(filepath.Join(site, "img"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"index.html", "img/logo.png"} {
+		if err := os.WriteFile(filepath.Join(site, name), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var outputBuf bytes.Buffer
+	if err := newCommand(t,
+		cmd.WithArgs("upload", "--no-progress", "--endpoint", srv.URL, file, site),
+		cmd.WithOutput(&outputBuf),
+	).Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := fileRef + "\t" + file + "\n" + dirRef + "\t" + site + "\n"
+	if got := outputBuf.String(); got != want {
+		t.Errorf("got output %q, want %q", got, want)
+	}
+	sort.Strings(files)
+	if got, want := strings.Join(files, ","), "img/logo.png,index.html"; got != want {
+		t.Errorf("got uploaded files %q, want %q", got, want)
+	}
+}
+
+func newDownloadServer(t *testing.T, content []byte) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var (
+		mu     sync.Mutex
+		ranges []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bzz/36b7/" {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		mu.Unlock()
+		w.Header().Set("Content-Disposition", `inline; filename="data.bin"`)
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &ranges
+}
+
+func TestDownloadCmd(t *testing.T) {
+	t.Parallel()
+
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	srv, ranges := newDownloadServer(t, content)
+	output := filepath.Join(t.TempDir(), "out.bin")
+
+	if err := newCommand(t,
+		cmd.WithArgs("download", "--no-progress", "--endpoint", srv.URL, "--segment-size", "1024", "--parallel", "3", "36b7", output),
+		cmd.WithOutput(io.Discard),
+	).Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatal("downloaded content mismatch")
+	}
+	// one request for the size and one per segment
+	if got, want := len(*ranges), 1+10; got != want {
+		t.Fatalf("got %d requests, want %d", got, want)
+	}
+	if _, err := os.Stat(output + ".part.json"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("download state not removed: %v", err)
+	}
+}
+
+func TestDownloadCmdResume(t *testing.T) {
+	t.Parallel()
+
+	content := bytes.Repeat([]byte("abcdefghij"), 300)
+	srv, ranges := newDownloadServer(t, content)
+	output := filepath.Join(t.TempDir(), "out.bin")
+
+	// the first two segments are already downloaded
+	if err := os.WriteFile(output+".part", content[:2048], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	state, err := json.Marshal(map[string]interface{}{
+		"path":        "/bzz/36b7/",
+		"size":        len(content),
+		"segmentSize": 1024,
+		"done":        []int{0, 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(output+".part.json", state, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := newCommand(t,
+		cmd.WithArgs("download", "--no-progress", "--endpoint", srv.URL, "--segment-size", "1024", "36b7", output),
+		cmd.WithOutput(io.Discard),
+	).Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatal("downloaded content mismatch")
+	}
+	want := []string{"bytes=0-0", "bytes=2048-2999"}
+	if !equalStrings(*ranges, want) {
+		t.Fatalf("got requested ranges %v, want %v", *ranges, want)
+	}
+}
+
+// newExportServer stores the files with the manifest and serves their
+// content through the bytes endpoint, counting the requests of the files.
+func newExportServer(t *testing.T, files map[string][]byte, modTime time.Time) (*httptest.Server, swarm.Address, func(path string) int) {
+	t.Helper()
+
+	ctx := context.Background()
+	storer := mock.NewStorer()
+	pipelineFn := func() pipeline.Interface {
+		return builder.NewPipelineBuilder(ctx, storer, storage.ModePutUpload, false)
+	}
+
+	m, err := manifest.NewDefaultManifest(loadsave.New(storer, pipelineFn), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs := make(map[string]string)
+	for p, data := range files {
+		ref, err := builder.FeedPipeline(ctx, pipelineFn(), bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		refs[ref.String()] = p
+		metadata := map[string]string{manifest.EntryMetadataModTimeKey: strconv.FormatInt(modTime.Unix(), 10)}
+		if err := m.Add(ctx, p, manifest.NewEntry(ref, metadata)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Add(ctx, manifest.RootPath, manifest.NewEntry(swarm.ZeroAddress, map[string]string{
+		manifest.WebsiteIndexDocumentSuffixKey: "index.html",
+	})); err != nil {
+		t.Fatal(err)
+	}
+	root, err := m.Store(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu       sync.Mutex
+		requests = make(map[string]int)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addr, err := swarm.ParseHexAddress(strings.TrimPrefix(r.URL.Path, "/bytes/"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		j, _, err := joiner.New(r.Context(), storer, addr)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		data, err := io.ReadAll(j)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if p, ok := refs[addr.String()]; ok {
+			mu.Lock()
+			requests[p]++
+			mu.Unlock()
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, root, func(p string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests[p]
+	}
+}
+
+func TestExportCmd(t *testing.T) {
+	t.Parallel()
+
+	files := map[string][]byte{
+		"index.html":       []byte("<html></html>"),
+		"img/logo.png":     bytes.Repeat([]byte{0x89, 'P', 'N', 'G'}, 3000),
+		"docs/a/empty.txt": {},
+	}
+	modTime := time.Unix(1672531200, 0)
+	srv, root, requests := newExportServer(t, files, modTime)
+
+	dir := t.TempDir()
+	// the logo is already exported and the index is exported with a different content
+	if err := os.MkdirAll(filepath.Join(dir, "img"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "img", "logo.png"), files["img/logo.png"], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte("stale"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := newCommand(t,
+		cmd.WithArgs("export", "--no-progress", "--endpoint", srv.URL, "--segment-size", "1024", root.String(), dir),
+		cmd.WithOutput(io.Discard),
+	).Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	for p, data := range files {
+		name := filepath.Join(dir, filepath.FromSlash(p))
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("file %s: content mismatch", p)
+		}
+		fi, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !fi.ModTime().Equal(modTime) {
+			t.Fatalf("file %s: got modification time %v, want %v", p, fi.ModTime(), modTime)
+		}
+	}
+	if n := requests("img/logo.png"); n != 0 {
+		t.Fatalf("got %d requests of the exported file, want 0", n)
+	}
+	if n := requests("index.html"); n == 0 {
+		t.Fatal("stale file not exported")
+	}
+}
+
+func TestExportCmdNotManifest(t *testing.T) {
+	t.Parallel()
+
+	srv, _, _ := newExportServer(t, map[string][]byte{"a.txt": []byte("a")}, time.Now())
+	ref, err := builder.FeedPipeline(context.Background(), builder.NewPipelineBuilder(context.Background(), mock.NewStorer(), storage.ModePutUpload, false), strings.NewReader("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = newCommand(t,
+		cmd.WithArgs("export", "--no-progress", "--endpoint", srv.URL, ref.String(), t.TempDir()),
+		cmd.WithOutput(io.Discard),
+	).Execute()
+	if err == nil || err.Error() != "reference is not a manifest" {
+		t.Fatalf("got error %v, want not a manifest", err)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
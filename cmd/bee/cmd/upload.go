@@ -0,0 +1,303 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	optionNameUploadStamp         = "stamp"
+	optionNameUploadPin           = "pin"
+	optionNameUploadEncrypt       = "encrypt"
+	optionNameUploadDeferred      = "deferred"
+	optionNameUploadIndexDocument = "index-document"
+	optionNameUploadErrorDocument = "error-document"
+	optionNameTransferParallel    = "parallel"
+	optionNameTransferNoProgress  = "no-progress"
+)
+
+const defaultAPIEndpoint = "http://localhost:1633"
+
+func (c *command) initUploadCmd() (err error) {
+	cmd := &cobra.Command{
+		Use:   "upload <file|dir>...",
+		Short: "Upload files and directories to a running node",
+		Long: `Upload files and directories to a running node
+
+Uploads every file or directory given as an argument through the API of a
+running node and prints its swarm reference. Directories are uploaded as a
+collection with a manifest of all the files in them. The postage batch is
+selected automatically if it is not provided: the usable batch with the
+longest time to live is used. Uploads are idempotent, so an interrupted
+upload can be resumed by running the same command again.`,
+		Example: `
+$> bee upload --stamp 7a9a...e36e photo.jpg
+$> bee upload --index-document index.html ./website`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			parallel := c.config.GetInt(optionNameTransferParallel)
+			if parallel <= 0 {
+				return errors.New("parallel must be positive")
+			}
+
+			client := newAPIClient(c.config.GetString(optionNameAPIEndpoint), http.DefaultClient)
+			stamp := c.config.GetString(optionNameUploadStamp)
+			if stamp == "" {
+				if stamp, err = selectStamp(cmd.Context(), client); err != nil {
+					return err
+				}
+			}
+
+			header := http.Header{}
+			header.Set(api.SwarmPostageBatchIdHeader, stamp)
+			header.Set(api.SwarmPinHeader, strconv.FormatBool(c.config.GetBool(optionNameUploadPin)))
+			header.Set(api.SwarmEncryptHeader, strconv.FormatBool(c.config.GetBool(optionNameUploadEncrypt)))
+			header.Set(api.SwarmDeferredUploadHeader, strconv.FormatBool(c.config.GetBool(optionNameUploadDeferred)))
+
+			u := &uploader{
+				api:           client,
+				header:        header,
+				indexDocument: c.config.GetString(optionNameUploadIndexDocument),
+				errorDocument: c.config.GetString(optionNameUploadErrorDocument),
+			}
+
+			var total int64
+			for _, path := range args {
+				size, err := pathSize(path)
+				if err != nil {
+					return err
+				}
+				total += size
+			}
+			var w io.Writer
+			if !c.config.GetBool(optionNameTransferNoProgress) {
+				w = cmd.ErrOrStderr()
+			}
+			p := newProgress(w, "uploading", total)
+
+			var mu sync.Mutex
+			refs := make([]swarm.Address, len(args))
+			eg, ctx := errgroup.WithContext(cmd.Context())
+			sem := make(chan struct{}, parallel)
+			for i, path := range args {
+				i, path := i, path
+				sem <- struct{}{}
+				eg.Go(func() error {
+					defer func() { <-sem }()
+					ref, err := u.upload(ctx, path, p)
+					if err != nil {
+						return fmt.Errorf("upload %s: %w", path, err)
+					}
+					mu.Lock()
+					refs[i] = ref
+					mu.Unlock()
+					return nil
+				})
+			}
+			err = eg.Wait()
+			p.Done()
+			if err != nil {
+				return err
+			}
+
+			for i, path := range args {
+				cmd.Printf("%s\t%s\n", refs[i], path)
+			}
+			return nil
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return c.config.BindPFlags(cmd.Flags())
+		},
+	}
+
+	cmd.Flags().String(optionNameAPIEndpoint, defaultAPIEndpoint, "API endpoint of the node")
+	cmd.Flags().String(optionNameUploadStamp, "", "postage batch ID, selected automatically if empty")
+	cmd.Flags().Bool(optionNameUploadPin, false, "pin the uploaded content")
+	cmd.Flags().Bool(optionNameUploadEncrypt, false, "encrypt the uploaded content")
+	cmd.Flags().Bool(optionNameUploadDeferred, true, "return before the content is synced to the network")
+	cmd.Flags().String(optionNameUploadIndexDocument, "", "index document of the uploaded directories")
+	cmd.Flags().String(optionNameUploadErrorDocument, "", "error document of the uploaded directories")
+	cmd.Flags().Int(optionNameTransferParallel, 2, "number of concurrent uploads")
+	cmd.Flags().Bool(optionNameTransferNoProgress, false, "do not show the progress")
+
+	cmd.SetOut(c.root.OutOrStdout())
+	c.root.AddCommand(cmd)
+	return nil
+}
+
+type uploader struct {
+	api           *apiClient
+	header        http.Header
+	indexDocument string
+	errorDocument string
+}
+
+// upload uploads a single file or a directory as a collection.
+func (u *uploader) upload(ctx context.Context, path string, p *progress) (swarm.Address, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+
+	header := u.header.Clone()
+	var body io.Reader
+	query := ""
+	if fi.IsDir() {
+		header.Set("Content-Type", "application/x-tar")
+		header.Set(api.SwarmCollectionHeader, "true")
+		if u.indexDocument != "" {
+			header.Set(api.SwarmIndexDocumentHeader, u.indexDocument)
+		}
+		if u.errorDocument != "" {
+			header.Set(api.SwarmErrorDocumentHeader, u.errorDocument)
+		}
+		pr, pw := io.Pipe()
+		go func() {
+			_ = pw.CloseWithError(writeTar(pw, path))
+		}()
+		defer pr.Close()
+		body = pr
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return swarm.ZeroAddress, err
+		}
+		defer f.Close()
+
+		contentType := mime.TypeByExtension(filepath.Ext(path))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header.Set("Content-Type", contentType)
+		query = "?name=" + url.QueryEscape(fi.Name())
+		body = f
+	}
+
+	var resp struct {
+		Reference swarm.Address `json:"reference"`
+	}
+	if err := u.api.requestJSON(ctx, http.MethodPost, "/bzz"+query, p.reader(body), header, &resp); err != nil {
+		return swarm.ZeroAddress, err
+	}
+	return resp.Reference, nil
+}
+
+// writeTar writes all the regular files of the directory to the tar archive.
+func writeTar(w io.Writer, dir string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name: filepath.ToSlash(rel),
+			Mode: 0o600,
+			Size: fi.Size(),
+		}); err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// pathSize returns the size of the file or the total size
+// of all the regular files in the directory.
+func pathSize(path string) (size int64, err error) {
+	err = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += fi.Size()
+		return nil
+	})
+	return size, err
+}
+
+// stampInfo is a postage batch as returned by the node API.
+type stampInfo struct {
+	BatchID     string `json:"batchID"`
+	Utilization uint32 `json:"utilization"`
+	Usable      bool   `json:"usable"`
+	Label       string `json:"label"`
+	Depth       uint8  `json:"depth"`
+	BucketDepth uint8  `json:"bucketDepth"`
+	Amount      string `json:"amount"`
+	Immutable   bool   `json:"immutableFlag"`
+	BatchTTL    int64  `json:"batchTTL"`
+	Expired     bool   `json:"expired"`
+}
+
+func listStamps(ctx context.Context, client *apiClient) ([]stampInfo, error) {
+	var resp struct {
+		Stamps []stampInfo `json:"stamps"`
+	}
+	if err := client.getJSON(ctx, "/stamps", &resp); err != nil {
+		return nil, fmt.Errorf("list stamps: %w", err)
+	}
+	return resp.Stamps, nil
+}
+
+// selectStamp returns the usable postage batch with the longest time to live.
+func selectStamp(ctx context.Context, client *apiClient) (string, error) {
+	stamps, err := listStamps(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	var selected *stampInfo
+	for i, s := range stamps {
+		if !s.Usable || s.Expired {
+			continue
+		}
+		if selected == nil || s.BatchTTL > selected.BatchTTL {
+			selected = &stamps[i]
+		}
+	}
+	if selected == nil {
+		return "", errors.New("no usable postage batch, buy one first or provide it with --stamp")
+	}
+	return selected.BatchID, nil
+}
@@ -0,0 +1,359 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package accesscontrol implements the access control trie (ACT) of the
+// uploads shared with the list of the grantees.
+//
+// The reference of the encrypted content is encrypted with the random access
+// key, which is wrapped for the publisher and for each grantee with the key
+// derived from the ECDH shared secret of the publisher and the grantee. The
+// wrapped access keys are stored in the access manifest under the lookup keys
+// derived from the same secrets, so that only the publisher and the grantees
+// can find and unwrap their access key, and nobody else learns the grantees.
+// The secrets are salted with the random nonce of the access manifest, so
+// that the access keys of the revoked grantees do not reveal the new ones.
+package accesscontrol
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/encryption"
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// The root metadata keys of the access manifest.
+const (
+	// PublisherKey is the hex encoded compressed public key of the publisher.
+	PublisherKey = "swarm-act-publisher"
+	// NonceKey is the hex encoded random nonce of the access manifest.
+	NonceKey = "swarm-act-nonce"
+	// ReferenceKey is the hex encoded reference encrypted with the access key.
+	ReferenceKey = "swarm-act-reference"
+	// GranteesKey is the reference of the list of the grantees
+	// which is encrypted for the publisher.
+	GranteesKey = "swarm-act-grantees"
+)
+
+// accessKeyMetadataKey is the metadata key of the wrapped access
+// key of the entry of the access manifest.
+const accessKeyMetadataKey = "swarm-act-key"
+
+const nonceSize = 32
+
+// salts of the keys derived from the shared secrets
+const (
+	lookupKeySalt byte = iota
+	accessKeyDecryptionKeySalt
+	granteesKeySalt
+)
+
+var (
+	// ErrNotAccessManifest is returned if the manifest is not an access manifest.
+	ErrNotAccessManifest = errors.New("not an access manifest")
+	// ErrInvalidAccessManifest is returned if the metadata of the access
+	// manifest can not be decoded.
+	ErrInvalidAccessManifest = errors.New("invalid access manifest")
+	// ErrAccessDenied is returned if the node is not granted the access.
+	ErrAccessDenied = errors.New("access denied")
+	// ErrNotPublisher is returned if the grantees are read or updated
+	// by another node than the publisher of the access manifest.
+	ErrNotPublisher = errors.New("not the publisher")
+)
+
+// Controller creates and opens the access manifests with the key of the node.
+type Controller struct {
+	key *ecdsa.PrivateKey
+	dh  crypto.DH
+}
+
+// New returns the controller of the access manifests of the node with the key.
+func New(key *ecdsa.PrivateKey) *Controller {
+	return &Controller{
+		key: key,
+		dh:  crypto.NewDH(key),
+	}
+}
+
+// PublicKey returns the public key the node publishes and is granted with.
+func (c *Controller) PublicKey() *ecdsa.PublicKey {
+	return &c.key.PublicKey
+}
+
+// Create stores the access manifest of the reference, which is granted to
+// the publisher and the grantees, and returns the reference of the manifest.
+func (c *Controller) Create(ctx context.Context, ls file.LoadSaver, reference swarm.Address, grantees []*ecdsa.PublicKey) (swarm.Address, error) {
+	m, err := manifest.NewDefaultManifest(ls, false)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+
+	nonce := encryption.GenerateRandomKey(nonceSize)
+	accessKey := encryption.GenerateRandomKey(encryption.KeyLength)
+
+	grantees = dedup(append([]*ecdsa.PublicKey{c.PublicKey()}, grantees...))
+
+	// only the publisher can read the list of the grantees
+	granteesKey, err := c.dh.SharedKey(c.PublicKey(), salt(nonce, granteesKeySalt))
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	list := make([]byte, 0, len(grantees)*btcec.PubKeyBytesLenCompressed)
+	for _, grantee := range grantees[1:] {
+		list = append(list, crypto.EncodeSecp256k1PublicKey(grantee)...)
+	}
+	encryptedList, err := newCipher(granteesKey).Encrypt(list)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	listReference, err := ls.Save(ctx, encryptedList)
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("save grantees: %w", err)
+	}
+
+	// the entries of the lookup keys refer to the list of the grantees,
+	// as the manifest entries must refer to the stored content
+	for _, grantee := range grantees {
+		lookupKey, err := c.dh.SharedKey(grantee, salt(nonce, lookupKeySalt))
+		if err != nil {
+			return swarm.ZeroAddress, err
+		}
+		decryptionKey, err := c.dh.SharedKey(grantee, salt(nonce, accessKeyDecryptionKeySalt))
+		if err != nil {
+			return swarm.ZeroAddress, err
+		}
+		wrapped := make([]byte, len(accessKey))
+		for i := range accessKey {
+			wrapped[i] = accessKey[i] ^ decryptionKey[i]
+		}
+		err = m.Add(ctx, hex.EncodeToString(lookupKey), manifest.NewEntry(swarm.NewAddress(listReference), map[string]string{
+			accessKeyMetadataKey: hex.EncodeToString(wrapped),
+		}))
+		if err != nil {
+			return swarm.ZeroAddress, fmt.Errorf("add grantee: %w", err)
+		}
+	}
+
+	encryptedReference, err := newCipher(accessKey).Encrypt(reference.Bytes())
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+
+	err = m.Add(ctx, manifest.RootPath, manifest.NewEntry(swarm.ZeroAddress, map[string]string{
+		PublisherKey: hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(c.PublicKey())),
+		NonceKey:     hex.EncodeToString(nonce),
+		ReferenceKey: hex.EncodeToString(encryptedReference),
+		GranteesKey:  swarm.NewAddress(listReference).String(),
+	}))
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("add metadata: %w", err)
+	}
+
+	return m.Store(ctx)
+}
+
+// Open returns the reference of the content of the access manifest if the
+// node is the publisher or one of the grantees. ErrNotAccessManifest is
+// returned if the manifest is not an access manifest.
+func (c *Controller) Open(ctx context.Context, m manifest.Interface) (swarm.Address, error) {
+	a, err := load(ctx, m)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	accessKey, err := c.accessKey(ctx, m, a)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	reference, err := newCipher(accessKey).Decrypt(a.reference)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	return swarm.NewAddress(reference), nil
+}
+
+// Grantees returns the grantees of the access manifest with the reference,
+// which can be read only by the publisher.
+func (c *Controller) Grantees(ctx context.Context, ls file.LoadSaver, address swarm.Address) ([]*ecdsa.PublicKey, error) {
+	m, err := manifest.NewDefaultManifestReference(address, ls)
+	if err != nil {
+		return nil, err
+	}
+	a, err := load(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	return c.grantees(ctx, ls, a)
+}
+
+// Update stores the new access manifest of the content of the access
+// manifest with the reference, with the grantees added and revoked, and
+// returns its reference. The access key is renewed, so that the revoked
+// grantees can not open the new access manifest. The content itself is not
+// re-encrypted: the revoked grantees can still open the old access manifest
+// and retrieve the content with the reference they already learned. To
+// revoke the access to the content, it has to be uploaded again and shared
+// with a new access manifest.
+func (c *Controller) Update(ctx context.Context, ls file.LoadSaver, address swarm.Address, add, revoke []*ecdsa.PublicKey) (swarm.Address, error) {
+	m, err := manifest.NewDefaultManifestReference(address, ls)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	a, err := load(ctx, m)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	grantees, err := c.grantees(ctx, ls, a)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	reference, err := c.Open(ctx, m)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+
+	revoked := make(map[string]bool, len(revoke))
+	for _, k := range revoke {
+		revoked[string(crypto.EncodeSecp256k1PublicKey(k))] = true
+	}
+	updated := make([]*ecdsa.PublicKey, 0, len(grantees)+len(add))
+	for _, k := range append(grantees, add...) {
+		if !revoked[string(crypto.EncodeSecp256k1PublicKey(k))] {
+			updated = append(updated, k)
+		}
+	}
+
+	return c.Create(ctx, ls, reference, updated)
+}
+
+// accessManifest holds the decoded root metadata of the access manifest.
+type accessManifest struct {
+	publisher *ecdsa.PublicKey
+	nonce     []byte
+	reference []byte
+	grantees  swarm.Address
+}
+
+func load(ctx context.Context, m manifest.Interface) (*accessManifest, error) {
+	e, err := m.Lookup(ctx, manifest.RootPath)
+	if err != nil {
+		if errors.Is(err, manifest.ErrNotFound) {
+			return nil, ErrNotAccessManifest
+		}
+		return nil, err
+	}
+	metadata := e.Metadata()
+	if _, ok := metadata[ReferenceKey]; !ok {
+		return nil, ErrNotAccessManifest
+	}
+
+	var a accessManifest
+	publisher, err := hex.DecodeString(metadata[PublisherKey])
+	if err != nil {
+		return nil, fmt.Errorf("%w: publisher: %v", ErrInvalidAccessManifest, err)
+	}
+	pub, err := btcec.ParsePubKey(publisher, btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("%w: publisher: %v", ErrInvalidAccessManifest, err)
+	}
+	a.publisher = (*ecdsa.PublicKey)(pub)
+	if a.nonce, err = hex.DecodeString(metadata[NonceKey]); err != nil || len(a.nonce) != nonceSize {
+		return nil, fmt.Errorf("%w: invalid nonce", ErrInvalidAccessManifest)
+	}
+	a.reference, err = hex.DecodeString(metadata[ReferenceKey])
+	if err != nil || (len(a.reference) != swarm.HashSize && len(a.reference) != encryption.ReferenceSize) {
+		return nil, fmt.Errorf("%w: invalid reference", ErrInvalidAccessManifest)
+	}
+	if a.grantees, err = swarm.ParseHexAddress(metadata[GranteesKey]); err != nil {
+		return nil, fmt.Errorf("%w: grantees: %v", ErrInvalidAccessManifest, err)
+	}
+	return &a, nil
+}
+
+// accessKey looks up and unwraps the access key of the node.
+func (c *Controller) accessKey(ctx context.Context, m manifest.Interface, a *accessManifest) ([]byte, error) {
+	lookupKey, err := c.dh.SharedKey(a.publisher, salt(a.nonce, lookupKeySalt))
+	if err != nil {
+		return nil, err
+	}
+	e, err := m.Lookup(ctx, hex.EncodeToString(lookupKey))
+	if err != nil {
+		if errors.Is(err, manifest.ErrNotFound) {
+			return nil, ErrAccessDenied
+		}
+		return nil, err
+	}
+	wrapped, err := hex.DecodeString(e.Metadata()[accessKeyMetadataKey])
+	if err != nil || len(wrapped) != encryption.KeyLength {
+		return nil, fmt.Errorf("%w: invalid access key", ErrInvalidAccessManifest)
+	}
+	decryptionKey, err := c.dh.SharedKey(a.publisher, salt(a.nonce, accessKeyDecryptionKeySalt))
+	if err != nil {
+		return nil, err
+	}
+	for i := range wrapped {
+		wrapped[i] ^= decryptionKey[i]
+	}
+	return wrapped, nil
+}
+
+// grantees loads and decrypts the list of the grantees for the publisher.
+func (c *Controller) grantees(ctx context.Context, ls file.LoadSaver, a *accessManifest) ([]*ecdsa.PublicKey, error) {
+	if !bytes.Equal(crypto.EncodeSecp256k1PublicKey(a.publisher), crypto.EncodeSecp256k1PublicKey(c.PublicKey())) {
+		return nil, ErrNotPublisher
+	}
+	granteesKey, err := c.dh.SharedKey(c.PublicKey(), salt(a.nonce, granteesKeySalt))
+	if err != nil {
+		return nil, err
+	}
+	encryptedList, err := ls.Load(ctx, a.grantees.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("load grantees: %w", err)
+	}
+	list, err := newCipher(granteesKey).Decrypt(encryptedList)
+	if err != nil {
+		return nil, err
+	}
+	if len(list)%btcec.PubKeyBytesLenCompressed != 0 {
+		return nil, fmt.Errorf("%w: invalid grantees", ErrInvalidAccessManifest)
+	}
+	grantees := make([]*ecdsa.PublicKey, 0, len(list)/btcec.PubKeyBytesLenCompressed)
+	for i := 0; i < len(list); i += btcec.PubKeyBytesLenCompressed {
+		pub, err := btcec.ParsePubKey(list[i:i+btcec.PubKeyBytesLenCompressed], btcec.S256())
+		if err != nil {
+			return nil, fmt.Errorf("%w: grantees: %v", ErrInvalidAccessManifest, err)
+		}
+		grantees = append(grantees, (*ecdsa.PublicKey)(pub))
+	}
+	return grantees, nil
+}
+
+func newCipher(key []byte) encryption.Interface {
+	return encryption.New(key, 0, 0, swarm.NewHasher)
+}
+
+func salt(nonce []byte, b byte) []byte {
+	return append(append(make([]byte, 0, len(nonce)+1), nonce...), b)
+}
+
+// dedup removes the repeated public keys, keeping the first ones.
+func dedup(keys []*ecdsa.PublicKey) []*ecdsa.PublicKey {
+	seen := make(map[string]bool, len(keys))
+	unique := keys[:0]
+	for _, k := range keys {
+		b := string(crypto.EncodeSecp256k1PublicKey(k))
+		if !seen[b] {
+			seen[b] = true
+			unique = append(unique, k)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,146 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package accesscontrol_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/accesscontrol"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func newLoadSaver() file.LoadSaver {
+	storer := mock.NewStorer()
+	return loadsave.New(storer, func() pipeline.Interface {
+		return builder.NewPipelineBuilder(context.Background(), storer, storage.ModePutUpload, false)
+	})
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func open(t *testing.T, ls file.LoadSaver, c *accesscontrol.Controller, address swarm.Address) (swarm.Address, error) {
+	t.Helper()
+
+	m, err := manifest.NewDefaultManifestReference(address, ls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.Open(context.Background(), m)
+}
+
+func TestAccessControl(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		ls        = newLoadSaver()
+		publisher = accesscontrol.New(newKey(t))
+		alice     = accesscontrol.New(newKey(t))
+		bob       = accesscontrol.New(newKey(t))
+		carol     = accesscontrol.New(newKey(t))
+		reference = swarm.MustParseHexAddress("3b1a5cc8f4a6f47d2b06d3b8bcb0f2f3b96c8b9f0d5e3b74f1a5e7d8a9c0b1e2" +
+			"c4d9e1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e")
+	)
+
+	address, err := publisher.Create(ctx, ls, reference, []*ecdsa.PublicKey{alice.PublicKey(), bob.PublicKey(), alice.PublicKey()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []*accesscontrol.Controller{publisher, alice, bob} {
+		got, err := open(t, ls, c, address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(reference) {
+			t.Fatalf("got reference %s, want %s", got, reference)
+		}
+	}
+	if _, err := open(t, ls, carol, address); !errors.Is(err, accesscontrol.ErrAccessDenied) {
+		t.Fatalf("got error %v, want %v", err, accesscontrol.ErrAccessDenied)
+	}
+
+	grantees, err := publisher.Grantees(ctx, ls, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(grantees) != 2 || !grantees[0].Equal(alice.PublicKey()) || !grantees[1].Equal(bob.PublicKey()) {
+		t.Fatalf("got %d grantees, want alice and bob", len(grantees))
+	}
+	if _, err := alice.Grantees(ctx, ls, address); !errors.Is(err, accesscontrol.ErrNotPublisher) {
+		t.Fatalf("got error %v, want %v", err, accesscontrol.ErrNotPublisher)
+	}
+
+	t.Run("update", func(t *testing.T) {
+		t.Parallel()
+
+		updated, err := publisher.Update(ctx, ls, address, []*ecdsa.PublicKey{carol.PublicKey()}, []*ecdsa.PublicKey{bob.PublicKey()})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, c := range []*accesscontrol.Controller{publisher, alice, carol} {
+			got, err := open(t, ls, c, updated)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(reference) {
+				t.Fatalf("got reference %s, want %s", got, reference)
+			}
+		}
+		if _, err := open(t, ls, bob, updated); !errors.Is(err, accesscontrol.ErrAccessDenied) {
+			t.Fatalf("got error %v, want %v", err, accesscontrol.ErrAccessDenied)
+		}
+
+		grantees, err := publisher.Grantees(ctx, ls, updated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(grantees) != 2 || !grantees[0].Equal(alice.PublicKey()) || !grantees[1].Equal(carol.PublicKey()) {
+			t.Fatalf("got %d grantees, want alice and carol", len(grantees))
+		}
+		if _, err := alice.Update(ctx, ls, updated, nil, nil); !errors.Is(err, accesscontrol.ErrNotPublisher) {
+			t.Fatalf("got error %v, want %v", err, accesscontrol.ErrNotPublisher)
+		}
+	})
+
+	t.Run("not access manifest", func(t *testing.T) {
+		t.Parallel()
+
+		m, err := manifest.NewDefaultManifest(ls, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Add(ctx, "file", manifest.NewEntry(swarm.MustParseHexAddress("e3b1a5cc8f4a6f47d2b06d3b8bcb0f2f3b96c8b9f0d5e3b74f1a5e7d8a9c0b1e"), nil)); err != nil {
+			t.Fatal(err)
+		}
+		address, err := m.Store(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := open(t, ls, publisher, address); !errors.Is(err, accesscontrol.ErrNotAccessManifest) {
+			t.Fatalf("got error %v, want %v", err, accesscontrol.ErrNotAccessManifest)
+		}
+	})
+}
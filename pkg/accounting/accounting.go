@@ -95,6 +95,17 @@ type PayFunc func(context.Context, swarm.Address, *big.Int)
 // RefreshFunc is the function used for sync time-based settlement
 type RefreshFunc func(context.Context, swarm.Address, *big.Int)
 
+// SettlementFunc is the function notified of the completed settlements
+type SettlementFunc func(peer swarm.Address, kind string, amount *big.Int)
+
+// The kinds of the settlements passed to the SettlementFunc.
+const (
+	SettlementPaymentSent         = "payment-sent"
+	SettlementPaymentReceived     = "payment-received"
+	SettlementRefreshmentSent     = "refreshment-sent"
+	SettlementRefreshmentReceived = "refreshment-received"
+)
+
 // Mutex is a drop in replacement for the sync.Mutex
 // it will not lock if the context is expired
 type Mutex struct {
@@ -168,6 +179,8 @@ type Accounting struct {
 	payFunction PayFunc
 	// function used for time settlement
 	refreshFunction RefreshFunc
+	// function notified of the completed settlements
+	settlementFunction SettlementFunc
 	// allowance based on time used in pseudo settle
 	refreshRate      *big.Int
 	lightRefreshRate *big.Int
@@ -1007,6 +1020,7 @@ func (a *Accounting) NotifyPaymentSent(peer swarm.Address, amount *big.Int, rece
 		a.logger.Warning("notify payment sent; failed to decrease originated balance", "error", err)
 	}
 
+	a.notifySettlement(peer, SettlementPaymentSent, amount)
 }
 
 // NotifyPaymentThreshold should be called to notify accounting of changes in the payment threshold
@@ -1058,6 +1072,7 @@ func (a *Accounting) NotifyPaymentReceived(peer swarm.Address, amount *big.Int)
 			return fmt.Errorf("failed to persist surplus balance: %w", err)
 		}
 
+		a.notifySettlement(peer, SettlementPaymentReceived, amount)
 		return nil
 	}
 
@@ -1099,6 +1114,7 @@ func (a *Accounting) NotifyPaymentReceived(peer swarm.Address, amount *big.Int)
 		}
 	}
 
+	a.notifySettlement(peer, SettlementPaymentReceived, amount)
 	return nil
 }
 
@@ -1187,6 +1203,7 @@ func (a *Accounting) NotifyRefreshmentSent(peer swarm.Address, attemptedAmount,
 		a.logger.Warning("accounting: notifyrefreshmentsent failed to decrease originated balance", "error", err)
 	}
 
+	a.notifySettlement(peer, SettlementRefreshmentSent, amount)
 }
 
 // NotifyRefreshmentReceived is called by pseudosettle when we receive a time based settlement.
@@ -1223,6 +1240,7 @@ func (a *Accounting) NotifyRefreshmentReceived(peer swarm.Address, amount *big.I
 
 	accountingPeer.refreshReceivedTimestamp = timestamp
 
+	a.notifySettlement(peer, SettlementRefreshmentReceived, amount)
 	return nil
 }
 
@@ -1544,6 +1562,18 @@ func (a *Accounting) SetPayFunc(f PayFunc) {
 	a.payFunction = f
 }
 
+// SetSettlementFunc sets the function notified of the completed settlements,
+// it is called with the peer lock held and must not block.
+func (a *Accounting) SetSettlementFunc(f SettlementFunc) {
+	a.settlementFunction = f
+}
+
+func (a *Accounting) notifySettlement(peer swarm.Address, kind string, amount *big.Int) {
+	if a.settlementFunction != nil {
+		a.settlementFunction(peer, kind, new(big.Int).Set(amount))
+	}
+}
+
 // Close hangs up running websockets on shutdown.
 func (a *Accounting) Close() error {
 	a.wg.Wait()
@@ -1251,6 +1251,51 @@ func TestAccountingNotifyPaymentReceived(t *testing.T) {
 	}
 }
 
+func TestAccountingSettlementFunc(t *testing.T) {
+	t.Parallel()
+
+	store := mock.NewStateStore()
+	defer store.Close()
+
+	acc, err := accounting.NewAccounting(testPaymentThreshold, testPaymentTolerance, testPaymentEarly, log.Noop, store, &pricingMock{}, big.NewInt(testRefreshRate), testLightFactor, p2pmock.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type settlement struct {
+		peer   swarm.Address
+		kind   string
+		amount *big.Int
+	}
+	var got []settlement
+	acc.SetSettlementFunc(func(peer swarm.Address, kind string, amount *big.Int) {
+		got = append(got, settlement{peer, kind, amount})
+	})
+
+	peer := swarm.RandAddress(t)
+	acc.Connect(peer, true)
+
+	if err := acc.NotifyPaymentReceived(peer, big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	if err := acc.NotifyRefreshmentReceived(peer, big.NewInt(50), time.Now().Unix()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []settlement{
+		{peer, accounting.SettlementPaymentReceived, big.NewInt(100)},
+		{peer, accounting.SettlementRefreshmentReceived, big.NewInt(50)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d settlements, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].peer.Equal(want[i].peer) || got[i].kind != want[i].kind || got[i].amount.Cmp(want[i].amount) != 0 {
+			t.Fatalf("got settlement %+v, want %+v", got[i], want[i])
+		}
+	}
+}
+
 type pricingMock struct {
 	called           bool
 	peer             swarm.Address
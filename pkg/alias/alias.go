@@ -0,0 +1,167 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package alias provides the registry of the human-readable names of the
+// references and the feeds, which is kept in the local state store. The
+// names are resolved in the API paths with the ~ prefix, as in /bzz/~site/.
+package alias
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// Prefix marks the aliases in the API paths.
+const Prefix = "~"
+
+const keyPrefix = "alias_"
+
+var (
+	// ErrNotFound is returned if there is no alias with the name.
+	ErrNotFound = errors.New("alias not found")
+	// ErrInvalidName is returned if the name is not a valid alias name.
+	ErrInvalidName = errors.New("invalid alias name")
+	// ErrInvalidTarget is returned if the alias refers to both
+	// or neither of a reference and a feed.
+	ErrInvalidTarget = errors.New("alias must refer to either a reference or a feed")
+	// ErrNoFeedUpdate is returned if the feed of the alias has no updates.
+	ErrNoFeedUpdate = errors.New("no feed update")
+)
+
+// the names are used in the paths, so that they are restricted
+// to the characters which do not need to be escaped
+var nameRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Feed is the sequence feed an alias refers to.
+type Feed struct {
+	Owner common.Address `json:"owner"`
+	Topic []byte         `json:"topic"`
+}
+
+// Alias is the name of a reference or of the latest update of a feed.
+type Alias struct {
+	Name      string        `json:"name"`
+	Reference swarm.Address `json:"reference"`
+	Feed      *Feed         `json:"feed,omitempty"`
+}
+
+// Registry stores the aliases.
+type Registry struct {
+	stateStore  storage.StateStorer
+	feedFactory feeds.Factory
+}
+
+// NewRegistry returns the registry of the aliases kept in the state store.
+// The aliases of the feeds are resolved with the lookups of the factory.
+func NewRegistry(stateStore storage.StateStorer, feedFactory feeds.Factory) *Registry {
+	return &Registry{
+		stateStore:  stateStore,
+		feedFactory: feedFactory,
+	}
+}
+
+// Set stores the alias, replacing the alias with the same name.
+func (r *Registry) Set(a Alias) error {
+	if !nameRegexp.MatchString(a.Name) {
+		return ErrInvalidName
+	}
+	if a.Reference.IsZero() == (a.Feed == nil) {
+		return ErrInvalidTarget
+	}
+	if a.Feed != nil && len(a.Feed.Topic) == 0 {
+		return ErrInvalidTarget
+	}
+	return r.stateStore.Put(keyPrefix+a.Name, a)
+}
+
+// Get returns the alias with the name.
+func (r *Registry) Get(name string) (Alias, error) {
+	var a Alias
+	err := r.stateStore.Get(keyPrefix+name, &a)
+	if errors.Is(err, storage.ErrNotFound) {
+		return Alias{}, ErrNotFound
+	}
+	if err != nil {
+		return Alias{}, err
+	}
+	return a, nil
+}
+
+// Delete removes the alias with the name.
+func (r *Registry) Delete(name string) error {
+	if _, err := r.Get(name); err != nil {
+		return err
+	}
+	return r.stateStore.Delete(keyPrefix + name)
+}
+
+// List returns all aliases sorted by name.
+func (r *Registry) List() ([]Alias, error) {
+	var list []Alias
+	err := r.stateStore.Iterate(keyPrefix, func(_, value []byte) (bool, error) {
+		var a Alias
+		if err := json.Unmarshal(value, &a); err != nil {
+			return true, err
+		}
+		list = append(list, a)
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// Resolve returns the reference of the alias with the name, which is
+// the reference in the payload of the latest update for the feeds.
+func (r *Registry) Resolve(ctx context.Context, name string) (swarm.Address, error) {
+	a, err := r.Get(name)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	if a.Feed == nil {
+		return a.Reference, nil
+	}
+
+	lookup, err := r.feedFactory.NewLookup(feeds.Sequence, feeds.New(a.Feed.Topic, a.Feed.Owner))
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("feed lookup: %w", err)
+	}
+	ch, _, _, err := lookup.At(ctx, time.Now().Unix(), 0)
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("feed lookup: %w", err)
+	}
+	if ch == nil {
+		return swarm.ZeroAddress, ErrNoFeedUpdate
+	}
+	_, payload, err := feeds.FromChunk(ch)
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("feed update: %w", err)
+	}
+	// the payload is the plain or the encrypted reference
+	if len(payload) != swarm.HashSize && len(payload) != 2*swarm.HashSize {
+		return swarm.ZeroAddress, fmt.Errorf("feed update: invalid reference length %d", len(payload))
+	}
+	return swarm.NewAddress(payload), nil
+}
+
+// Name returns the alias name in the string if it has the Prefix.
+func Name(s string) (string, bool) {
+	if !strings.HasPrefix(s, Prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(s, Prefix), true
+}
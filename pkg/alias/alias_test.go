@@ -0,0 +1,143 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package alias_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/alias"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/factory"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		storer   = smock.NewStorer()
+		registry = alias.NewRegistry(statestore.NewStateStore(), factory.New(storer))
+		ref      = swarm.MustParseHexAddress("aa00000000000000000000000000000000000000000000000000000000000000")
+		updated  = swarm.MustParseHexAddress("bb00000000000000000000000000000000000000000000000000000000000000")
+		topic    = []byte("site")
+	)
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			alias alias.Alias
+			err   error
+		}{
+			{alias: alias.Alias{Name: "", Reference: ref}, err: alias.ErrInvalidName},
+			{alias: alias.Alias{Name: "a/b", Reference: ref}, err: alias.ErrInvalidName},
+			{alias: alias.Alias{Name: "~site", Reference: ref}, err: alias.ErrInvalidName},
+			{alias: alias.Alias{Name: "site"}, err: alias.ErrInvalidTarget},
+			{alias: alias.Alias{Name: "site", Reference: ref, Feed: &alias.Feed{Owner: owner, Topic: topic}}, err: alias.ErrInvalidTarget},
+			{alias: alias.Alias{Name: "site", Feed: &alias.Feed{Owner: owner}}, err: alias.ErrInvalidTarget},
+		} {
+			if err := registry.Set(tc.alias); !errors.Is(err, tc.err) {
+				t.Errorf("set %+v: got error %v, want %v", tc.alias, err, tc.err)
+			}
+		}
+	})
+
+	t.Run("reference", func(t *testing.T) {
+		t.Parallel()
+
+		if err := registry.Set(alias.Alias{Name: "docs", Reference: ref}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := registry.Resolve(ctx, "docs")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(ref) {
+			t.Fatalf("got %s, want %s", got, ref)
+		}
+
+		if err := registry.Delete("docs"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := registry.Resolve(ctx, "docs"); !errors.Is(err, alias.ErrNotFound) {
+			t.Fatalf("got error %v, want %v", err, alias.ErrNotFound)
+		}
+		if err := registry.Delete("docs"); !errors.Is(err, alias.ErrNotFound) {
+			t.Fatalf("got error %v, want %v", err, alias.ErrNotFound)
+		}
+	})
+
+	t.Run("feed", func(t *testing.T) {
+		t.Parallel()
+
+		if err := registry.Set(alias.Alias{Name: "site.eth", Feed: &alias.Feed{Owner: owner, Topic: topic}}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := registry.Resolve(ctx, "site.eth"); !errors.Is(err, alias.ErrNoFeedUpdate) {
+			t.Fatalf("got error %v, want %v", err, alias.ErrNoFeedUpdate)
+		}
+
+		putter, err := feeds.NewPutter(storer, signer, topic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the alias follows the latest update of the feed
+		for i, want := range []swarm.Address{ref, updated} {
+			if err := putter.Put(ctx, sequence.NewIndex(uint64(i)), time.Now().Unix(), want.Bytes()); err != nil {
+				t.Fatal(err)
+			}
+			got, err := registry.Resolve(ctx, "site.eth")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(want) {
+				t.Fatalf("update %d: got %s, want %s", i, got, want)
+			}
+		}
+
+		list, err := registry.List()
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, a := range list {
+			if a.Name == "site.eth" && a.Feed != nil && a.Feed.Owner == owner && string(a.Feed.Topic) == string(topic) {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("alias not listed in %+v", list)
+		}
+	})
+}
+
+func TestName(t *testing.T) {
+	t.Parallel()
+
+	if name, ok := alias.Name("~site"); !ok || name != "site" {
+		t.Fatalf("got %q, %v, want site, true", name, ok)
+	}
+	if _, ok := alias.Name("aa00"); ok {
+		t.Fatal("got alias name of a reference")
+	}
+}
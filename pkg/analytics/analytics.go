@@ -0,0 +1,187 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package analytics counts the requests and the bytes served per reference
+// in rolling windows, so that the publishers running their own gateway can
+// see which content is popular.
+package analytics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultMaxReferences is the default number of the tracked references.
+const DefaultMaxReferences = 1000
+
+// Counts are the numbers of the requests and the bytes served.
+type Counts struct {
+	Requests int64
+	Bytes    int64
+}
+
+func (c *Counts) add(o Counts) {
+	c.Requests += o.Requests
+	c.Bytes += o.Bytes
+}
+
+// Stats are the counts of a reference in the rolling windows.
+type Stats struct {
+	Reference string
+	LastHour  Counts
+	LastDay   Counts
+	// Total are the counts since the reference is tracked.
+	Total Counts
+}
+
+// Tracker counts the requests per reference. The number of the tracked
+// references is bounded, and when the limit is reached, the least requested
+// reference of the last day is replaced by the new one.
+type Tracker struct {
+	mu      sync.Mutex
+	refs    map[string]*entry
+	max     int
+	now     func() time.Time
+	metrics metrics
+}
+
+// New returns a new Tracker which tracks at most maxReferences
+// references or DefaultMaxReferences if it is not positive.
+func New(maxReferences int) *Tracker {
+	if maxReferences <= 0 {
+		maxReferences = DefaultMaxReferences
+	}
+	return &Tracker{
+		refs:    make(map[string]*entry),
+		max:     maxReferences,
+		now:     time.Now,
+		metrics: newMetrics(),
+	}
+}
+
+// Record counts a request for the reference which served the given bytes.
+func (t *Tracker) Record(reference string, bytes int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	e, ok := t.refs[reference]
+	if !ok {
+		if len(t.refs) >= t.max {
+			t.evict(now)
+		}
+		e = newEntry()
+		t.refs[reference] = e
+		t.metrics.TrackedReferences.Set(float64(len(t.refs)))
+	}
+
+	c := Counts{Requests: 1, Bytes: bytes}
+	e.hour.add(now, c)
+	e.day.add(now, c)
+	e.total.add(c)
+}
+
+// Stats returns the counts of the tracked references, sorted by
+// the bytes served in the last day in the descending order.
+func (t *Tracker) Stats() []Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	stats := make([]Stats, 0, len(t.refs))
+	for ref, e := range t.refs {
+		stats = append(stats, Stats{
+			Reference: ref,
+			LastHour:  e.hour.sum(now),
+			LastDay:   e.day.sum(now),
+			Total:     e.total,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].LastDay.Bytes != stats[j].LastDay.Bytes {
+			return stats[i].LastDay.Bytes > stats[j].LastDay.Bytes
+		}
+		if stats[i].LastDay.Requests != stats[j].LastDay.Requests {
+			return stats[i].LastDay.Requests > stats[j].LastDay.Requests
+		}
+		return stats[i].Reference < stats[j].Reference
+	})
+	return stats
+}
+
+// evict removes the reference with the least requests in the last day.
+func (t *Tracker) evict(now time.Time) {
+	var (
+		minRef   string
+		minCount Counts
+		found    bool
+	)
+	for ref, e := range t.refs {
+		c := e.day.sum(now)
+		if !found || c.Requests < minCount.Requests || (c.Requests == minCount.Requests && c.Bytes < minCount.Bytes) {
+			minRef, minCount, found = ref, c, true
+		}
+	}
+	if found {
+		delete(t.refs, minRef)
+		t.metrics.EvictedReferences.Inc()
+	}
+}
+
+type entry struct {
+	hour  *ring // minute buckets of the last hour
+	day   *ring // hour buckets of the last day
+	total Counts
+}
+
+func newEntry() *entry {
+	return &entry{
+		hour: newRing(time.Minute, 60),
+		day:  newRing(time.Hour, 24),
+	}
+}
+
+// ring is a rolling window of the counts in buckets of a fixed resolution.
+type ring struct {
+	res     time.Duration
+	buckets []Counts
+	last    int64 // the sequence number of the most recent bucket
+}
+
+func newRing(res time.Duration, n int) *ring {
+	return &ring{res: res, buckets: make([]Counts, n)}
+}
+
+func (r *ring) add(now time.Time, c Counts) {
+	i := r.advance(now)
+	r.buckets[i%int64(len(r.buckets))].add(c)
+}
+
+func (r *ring) sum(now time.Time) (c Counts) {
+	r.advance(now)
+	for _, b := range r.buckets {
+		c.add(b)
+	}
+	return c
+}
+
+// advance clears the buckets which fell out of the window
+// and returns the sequence number of the current bucket.
+func (r *ring) advance(now time.Time) int64 {
+	i := now.UnixNano() / int64(r.res)
+	if i <= r.last {
+		return r.last
+	}
+	n := int64(len(r.buckets))
+	from := r.last + 1
+	if i-from >= n {
+		from = i - n + 1
+	}
+	for j := from; j <= i; j++ {
+		r.buckets[j%n] = Counts{}
+	}
+	r.last = i
+	return i
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/analytics"
+)
+
+func TestWindows(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	tr := analytics.New(0)
+	tr.SetNow(func() time.Time { return now })
+
+	tr.Record("ref", 100)
+	tr.Record("ref", 50)
+
+	for _, tc := range []struct {
+		name     string
+		after    time.Duration
+		lastHour analytics.Counts
+		lastDay  analytics.Counts
+	}{
+		{
+			name:     "now",
+			lastHour: analytics.Counts{Requests: 2, Bytes: 150},
+			lastDay:  analytics.Counts{Requests: 2, Bytes: 150},
+		},
+		{
+			name:     "within hour",
+			after:    30 * time.Minute,
+			lastHour: analytics.Counts{Requests: 2, Bytes: 150},
+			lastDay:  analytics.Counts{Requests: 2, Bytes: 150},
+		},
+		{
+			name:    "within day",
+			after:   2 * time.Hour,
+			lastDay: analytics.Counts{Requests: 2, Bytes: 150},
+		},
+		{
+			name:  "after day",
+			after: 25 * time.Hour,
+		},
+	} {
+		now = now.Add(tc.after)
+
+		stats := tr.Stats()
+		if len(stats) != 1 {
+			t.Fatalf("%s: got %d references, want 1", tc.name, len(stats))
+		}
+		s := stats[0]
+		if s.Reference != "ref" {
+			t.Fatalf("%s: got reference %q, want %q", tc.name, s.Reference, "ref")
+		}
+		if s.LastHour != tc.lastHour {
+			t.Fatalf("%s: got last hour %+v, want %+v", tc.name, s.LastHour, tc.lastHour)
+		}
+		if s.LastDay != tc.lastDay {
+			t.Fatalf("%s: got last day %+v, want %+v", tc.name, s.LastDay, tc.lastDay)
+		}
+		if want := (analytics.Counts{Requests: 2, Bytes: 150}); s.Total != want {
+			t.Fatalf("%s: got total %+v, want %+v", tc.name, s.Total, want)
+		}
+	}
+
+	// the old buckets are cleared when the window rolls over
+	tr.Record("ref", 10)
+	s := tr.Stats()[0]
+	if want := (analytics.Counts{Requests: 1, Bytes: 10}); s.LastHour != want || s.LastDay != want {
+		t.Fatalf("got last hour %+v and last day %+v, want %+v", s.LastHour, s.LastDay, want)
+	}
+}
+
+func TestBoundedReferences(t *testing.T) {
+	t.Parallel()
+
+	tr := analytics.New(2)
+
+	tr.Record("popular", 10)
+	tr.Record("popular", 10)
+	tr.Record("rare", 1000)
+	tr.Record("new", 1)
+
+	stats := tr.Stats()
+	if len(stats) != 2 {
+		t.Fatalf("got %d references, want 2", len(stats))
+	}
+	// sorted by the bytes of the last day
+	if stats[0].Reference != "popular" || stats[1].Reference != "new" {
+		t.Fatalf("got references %q and %q, want %q and %q", stats[0].Reference, stats[1].Reference, "popular", "new")
+	}
+}
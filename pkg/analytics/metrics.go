@@ -0,0 +1,39 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analytics
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	TrackedReferences prometheus.Gauge
+	EvictedReferences prometheus.Counter
+}
+
+func newMetrics() metrics {
+	subsystem := "analytics"
+
+	return metrics{
+		TrackedReferences: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "tracked_references",
+			Help:      "Number of the references with tracked requests.",
+		}),
+		EvictedReferences: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "evicted_references_total",
+			Help:      "Total number of the references evicted to bound the number of the tracked references.",
+		}),
+	}
+}
+
+// Metrics returns the prometheus collectors of the tracker.
+func (t *Tracker) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(t.metrics)
+}
@@ -0,0 +1,246 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/accesscontrol"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
+)
+
+var errInvalidGrantees = errors.New("invalid grantees")
+
+type actGranteesResponse struct {
+	Grantees []string `json:"grantees"`
+}
+
+type actGranteesPatchRequest struct {
+	Add    []string `json:"add"`
+	Revoke []string `json:"revoke"`
+}
+
+type actGranteesPatchResponse struct {
+	Reference swarm.Address `json:"reference"`
+}
+
+// requestAct reports whether the upload is granted only to the publisher
+// and the grantees of its access manifest.
+func requestAct(r *http.Request) bool {
+	return strings.ToLower(r.Header.Get(SwarmActHeader)) == boolHeaderSetValue
+}
+
+// requestActGrantees returns the public keys of the grantees of the upload,
+// which are given as the comma separated compressed public keys in hex.
+func requestActGrantees(r *http.Request) ([]*ecdsa.PublicKey, error) {
+	var grantees []string
+	for _, g := range strings.Split(r.Header.Get(SwarmActGranteesHeader), ",") {
+		if g = strings.TrimSpace(g); g != "" {
+			grantees = append(grantees, g)
+		}
+	}
+	return parseGrantees(grantees)
+}
+
+func parseGrantees(grantees []string) ([]*ecdsa.PublicKey, error) {
+	keys := make([]*ecdsa.PublicKey, 0, len(grantees))
+	for _, g := range grantees {
+		key, err := pss.ParseRecipient(g)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", errInvalidGrantees, g, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// actLoadSaver returns the load saver of the access manifests, which are
+// not encrypted, as their content is protected by the access control.
+func actLoadSaver(ctx context.Context, putter storage.Storer, r *http.Request) file.LoadSaver {
+	mode := requestModePut(r)
+	return loadsave.New(putter, func() pipeline.Interface {
+		return builder.NewPipelineBuilder(ctx, putter, mode, false)
+	})
+}
+
+// storeAccessManifest stores the access manifest of the uploaded reference
+// for the grantees of the request and returns its reference, which is
+// pinned too if the upload is pinned.
+func (s *Service) storeAccessManifest(ctx context.Context, r *http.Request, putter storage.Storer, reference swarm.Address) (swarm.Address, error) {
+	grantees, err := requestActGrantees(r)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	address, err := s.accessControl.Create(ctx, actLoadSaver(ctx, putter, r), reference, grantees)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	if requestPin(r) {
+		if err := s.pinning.CreatePin(ctx, address, false); err != nil {
+			return swarm.ZeroAddress, fmt.Errorf("pin access manifest: %w", err)
+		}
+	}
+	return address, nil
+}
+
+func (s *Service) actGranteesGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("get_act_grantees").Build())
+
+	paths := struct {
+		Address swarm.Address `map:"address,resolve" validate:"required"`
+	}{}
+	if response := s.mapStructureContext(r.Context(), mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	if s.accessControl == nil {
+		jsonhttp.NotImplemented(w, "access control not enabled")
+		return
+	}
+
+	grantees, err := s.accessControl.Grantees(r.Context(), loadsave.NewReadonly(s.storer), paths.Address)
+	if err != nil {
+		logger.Debug("get grantees failed", "address", paths.Address, "error", err)
+		logger.Error(nil, "get grantees failed")
+		actErrorResponse(w, err)
+		return
+	}
+
+	resp := actGranteesResponse{Grantees: make([]string, 0, len(grantees))}
+	for _, g := range grantees {
+		resp.Grantees = append(resp.Grantees, hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(g)))
+	}
+	jsonhttp.OK(w, resp)
+}
+
+// actGranteesPatchHandler adds and revokes the grantees of the access
+// manifest and responds with the reference of the new access manifest.
+// The access key is renewed, so that the revoked grantees can not open
+// the new access manifest, which is to be shared instead of the old one.
+// The content is not re-encrypted, so it stays accessible to the revoked
+// grantees through the old access manifest.
+func (s *Service) actGranteesPatchHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("patch_act_grantees").Build())
+
+	paths := struct {
+		Address swarm.Address `map:"address,resolve" validate:"required"`
+	}{}
+	if response := s.mapStructureContext(r.Context(), mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	if s.accessControl == nil {
+		jsonhttp.NotImplemented(w, "access control not enabled")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		if jsonhttp.HandleBodyReadError(err, w) {
+			return
+		}
+		logger.Debug("read request body failed", "error", err)
+		logger.Error(nil, "read request body failed")
+		jsonhttp.InternalServerError(w, "cannot read request")
+		return
+	}
+	var req actGranteesPatchRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		logger.Debug("unmarshal body failed", "error", err)
+		logger.Error(nil, "unmarshal body failed")
+		jsonhttp.BadRequest(w, "invalid request body")
+		return
+	}
+	add, err := parseGrantees(req.Add)
+	if err != nil {
+		logger.Debug("invalid grantees", "error", err)
+		jsonhttp.BadRequest(w, errInvalidGrantees)
+		return
+	}
+	revoke, err := parseGrantees(req.Revoke)
+	if err != nil {
+		logger.Debug("invalid grantees", "error", err)
+		jsonhttp.BadRequest(w, errInvalidGrantees)
+		return
+	}
+
+	putter, wait, err := s.newStamperPutter(r)
+	if err != nil {
+		logger.Debug("get putter failed", "error", err)
+		logger.Error(nil, "get putter failed")
+		switch {
+		case errors.Is(err, errBatchUnusable) || errors.Is(err, postage.ErrNotUsable):
+			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+		case errors.Is(err, postage.ErrNotFound):
+			jsonhttp.NotFound(w, "batch with id not found")
+		case errors.Is(err, errInvalidPostageBatch):
+			jsonhttp.BadRequest(w, "invalid batch id")
+		case errors.Is(err, errUnsupportedDevNodeOperation):
+			jsonhttp.BadRequest(w, errUnsupportedDevNodeOperation)
+		default:
+			jsonhttp.BadRequest(w, nil)
+		}
+		return
+	}
+
+	ctx := r.Context()
+	reference, err := s.accessControl.Update(ctx, actLoadSaver(ctx, putter, r), paths.Address, add, revoke)
+	if err != nil {
+		logger.Debug("update grantees failed", "address", paths.Address, "error", err)
+		logger.Error(nil, "update grantees failed")
+		actErrorResponse(w, err)
+		return
+	}
+	if err = wait(); err != nil {
+		logger.Debug("sync chunks failed", "error", err)
+		logger.Error(nil, "sync chunks failed")
+		jsonhttp.InternalServerError(w, "sync chunks failed")
+		return
+	}
+
+	jsonhttp.Created(w, actGranteesPatchResponse{
+		Reference: reference,
+	})
+}
+
+func actErrorResponse(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, accesscontrol.ErrNotPublisher):
+		jsonhttp.Forbidden(w, "not the publisher of the access manifest")
+	case errors.Is(err, accesscontrol.ErrAccessDenied):
+		jsonhttp.Forbidden(w, "access denied")
+	case errors.Is(err, accesscontrol.ErrNotAccessManifest):
+		jsonhttp.BadRequest(w, "not an access manifest")
+	case errors.Is(err, accesscontrol.ErrInvalidAccessManifest):
+		jsonhttp.BadRequest(w, "invalid access manifest")
+	case errors.Is(err, storage.ErrNotFound):
+		jsonhttp.NotFound(w, "access manifest not found")
+	case errors.Is(err, postage.ErrBucketFull):
+		jsonhttp.PaymentRequired(w, "batch is overissued")
+	default:
+		jsonhttp.InternalServerError(w, "access control failed")
+	}
+}
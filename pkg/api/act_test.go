@@ -0,0 +1,191 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/accesscontrol"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestAct(t *testing.T) {
+	t.Parallel()
+
+	storer := mock.NewStorer()
+	newNode := func(t *testing.T) (*http.Client, string) {
+		t.Helper()
+
+		key, err := crypto.GenerateSecp256k1Key()
+		if err != nil {
+			t.Fatal(err)
+		}
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			Storer:        storer,
+			Tags:          tags.NewTags(statestore.NewStateStore(), log.Noop),
+			Logger:        log.Noop,
+			Post:          mockpost.New(mockpost.WithAcceptAll()),
+			AccessControl: accesscontrol.New(key),
+		})
+		return client, hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(&key.PublicKey))
+	}
+
+	var (
+		publisher, _    = newNode(t)
+		alice, aliceKey = newNode(t)
+		bob, bobKey     = newNode(t)
+		stranger, _     = newNode(t)
+		data            = []byte("private data")
+		index           = []byte("<h1>private site</h1>")
+		html            = http.Header{"Content-Type": {"text/html; charset=utf-8"}}
+		accessDenied    = jsonhttp.StatusResponse{Message: "access denied", Code: http.StatusForbidden}
+		upload          = func(t *testing.T, grantees string, opts ...jsonhttptest.Option) api.BzzUploadResponse {
+			t.Helper()
+
+			var resp api.BzzUploadResponse
+			jsonhttptest.Request(t, publisher, http.MethodPost, "/bzz?name=private.txt", http.StatusCreated, append([]jsonhttptest.Option{
+				jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+				jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+				jsonhttptest.WithRequestHeader(api.SwarmActHeader, "true"),
+				jsonhttptest.WithRequestHeader(api.SwarmActGranteesHeader, grantees),
+				jsonhttptest.WithUnmarshalJSONResponse(&resp),
+			}, opts...)...)
+			return resp
+		}
+	)
+
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+
+		resp := upload(t, aliceKey,
+			jsonhttptest.WithRequestHeader("Content-Type", "text/plain"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+		)
+		if len(resp.Reference.Bytes()) != 32 {
+			t.Fatalf("got reference %s, want the access manifest reference", resp.Reference)
+		}
+
+		for _, client := range []*http.Client{publisher, alice} {
+			jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+resp.Reference.String()+"/", http.StatusOK,
+				jsonhttptest.WithExpectedResponse(data),
+			)
+		}
+		jsonhttptest.Request(t, stranger, http.MethodGet, "/bzz/"+resp.Reference.String()+"/", http.StatusForbidden,
+			jsonhttptest.WithExpectedJSONResponse(accessDenied),
+		)
+	})
+
+	t.Run("collection", func(t *testing.T) {
+		t.Parallel()
+
+		resp := upload(t, aliceKey+", "+bobKey,
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmIndexDocumentHeader, "index.html"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestBody(tarFiles(t, []f{{data: index, name: "index.html", header: html}})),
+		)
+
+		for _, client := range []*http.Client{alice, bob} {
+			jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+resp.Reference.String()+"/", http.StatusOK,
+				jsonhttptest.WithExpectedResponse(index),
+			)
+		}
+		jsonhttptest.Request(t, stranger, http.MethodGet, "/bzz/"+resp.Reference.String()+"/index.html", http.StatusForbidden,
+			jsonhttptest.WithExpectedJSONResponse(accessDenied),
+		)
+	})
+
+	t.Run("grantees", func(t *testing.T) {
+		t.Parallel()
+
+		resp := upload(t, aliceKey,
+			jsonhttptest.WithRequestHeader("Content-Type", "text/plain"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+		)
+		path := "/act/grantees/" + resp.Reference.String()
+
+		jsonhttptest.Request(t, publisher, http.MethodGet, path, http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.ActGranteesResponse{Grantees: []string{aliceKey}}),
+		)
+		jsonhttptest.Request(t, alice, http.MethodGet, path, http.StatusForbidden,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "not the publisher of the access manifest",
+				Code:    http.StatusForbidden,
+			}),
+		)
+
+		var patched api.ActGranteesPatchResponse
+		jsonhttptest.Request(t, publisher, http.MethodPatch, path, http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithJSONRequestBody(api.ActGranteesPatchRequest{
+				Add:    []string{bobKey},
+				Revoke: []string{aliceKey},
+			}),
+			jsonhttptest.WithUnmarshalJSONResponse(&patched),
+		)
+
+		jsonhttptest.Request(t, publisher, http.MethodGet, "/act/grantees/"+patched.Reference.String(), http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.ActGranteesResponse{Grantees: []string{bobKey}}),
+		)
+		jsonhttptest.Request(t, bob, http.MethodGet, "/bzz/"+patched.Reference.String()+"/", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(data),
+		)
+		jsonhttptest.Request(t, alice, http.MethodGet, "/bzz/"+patched.Reference.String()+"/", http.StatusForbidden,
+			jsonhttptest.WithExpectedJSONResponse(accessDenied),
+		)
+	})
+
+	t.Run("invalid grantees", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, publisher, http.MethodPost, "/bzz?name=private.txt", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmActHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmActGranteesHeader, "abcd"),
+			jsonhttptest.WithRequestHeader("Content-Type", "text/plain"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid grantees",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			Storer: storer,
+			Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+			Logger: log.Noop,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+		})
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=private.txt", http.StatusNotImplemented,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmActHeader, "true"),
+			jsonhttptest.WithRequestHeader("Content-Type", "text/plain"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+		)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, publisher, http.MethodGet, "/act/grantees/"+strings.Repeat("ab", 32), http.StatusNotFound)
+	})
+}
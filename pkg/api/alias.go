@@ -0,0 +1,189 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/alias"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+)
+
+type aliasFeed struct {
+	Owner string `json:"owner"`
+	Topic string `json:"topic"`
+}
+
+type aliasRequest struct {
+	Reference *swarm.Address `json:"reference,omitempty"`
+	Feed      *aliasFeed     `json:"feed,omitempty"`
+}
+
+type aliasResponse struct {
+	Name      string         `json:"name"`
+	Reference *swarm.Address `json:"reference,omitempty"`
+	Feed      *aliasFeed     `json:"feed,omitempty"`
+}
+
+type aliasesResponse struct {
+	Aliases []aliasResponse `json:"aliases"`
+}
+
+// aliasesGetHandler returns all aliases.
+func (s *Service) aliasesGetHandler(w http.ResponseWriter, _ *http.Request) {
+	logger := s.logger.WithName("get_aliases").Build()
+
+	if s.aliases == nil {
+		jsonhttp.NotImplemented(w, "aliases are not available")
+		return
+	}
+
+	list, err := s.aliases.List()
+	if err != nil {
+		logger.Debug("list aliases failed", "error", err)
+		logger.Error(nil, "list aliases failed")
+		jsonhttp.InternalServerError(w, "list aliases failed")
+		return
+	}
+
+	res := aliasesResponse{Aliases: make([]aliasResponse, 0, len(list))}
+	for _, a := range list {
+		res.Aliases = append(res.Aliases, newAliasResponse(a))
+	}
+	jsonhttp.OK(w, res)
+}
+
+// aliasGetHandler returns the alias with the name.
+func (s *Service) aliasGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_alias").Build()
+
+	if s.aliases == nil {
+		jsonhttp.NotImplemented(w, "aliases are not available")
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	a, err := s.aliases.Get(name)
+	if err != nil {
+		if errors.Is(err, alias.ErrNotFound) {
+			jsonhttp.NotFound(w, "alias not found")
+			return
+		}
+		logger.Debug("get alias failed", "name", name, "error", err)
+		logger.Error(nil, "get alias failed")
+		jsonhttp.InternalServerError(w, "get alias failed")
+		return
+	}
+
+	jsonhttp.OK(w, newAliasResponse(a))
+}
+
+// aliasPutHandler sets the alias with the name to the
+// reference or the feed given in the request body.
+func (s *Service) aliasPutHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("put_alias").Build()
+
+	if s.aliases == nil {
+		jsonhttp.NotImplemented(w, "aliases are not available")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		if jsonhttp.HandleBodyReadError(err, w) {
+			return
+		}
+		logger.Debug("read request body failed", "error", err)
+		logger.Error(nil, "read request body failed")
+		jsonhttp.InternalServerError(w, "cannot read request")
+		return
+	}
+
+	var req aliasRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		logger.Debug("unmarshal alias failed", "error", err)
+		logger.Error(nil, "unmarshal alias failed")
+		jsonhttp.BadRequest(w, "invalid alias")
+		return
+	}
+
+	a := alias.Alias{Name: mux.Vars(r)["name"]}
+	if req.Reference != nil {
+		a.Reference = *req.Reference
+	}
+	if req.Feed != nil {
+		owner, err := hex.DecodeString(strings.TrimPrefix(req.Feed.Owner, "0x"))
+		if err != nil || len(owner) != common.AddressLength {
+			jsonhttp.BadRequest(w, "invalid feed owner")
+			return
+		}
+		topic, err := hex.DecodeString(req.Feed.Topic)
+		if err != nil {
+			jsonhttp.BadRequest(w, "invalid feed topic")
+			return
+		}
+		a.Feed = &alias.Feed{Owner: common.BytesToAddress(owner), Topic: topic}
+	}
+
+	if err := s.aliases.Set(a); err != nil {
+		logger.Debug("set alias failed", "name", a.Name, "error", err)
+		logger.Error(nil, "set alias failed")
+		switch {
+		case errors.Is(err, alias.ErrInvalidName), errors.Is(err, alias.ErrInvalidTarget):
+			jsonhttp.BadRequest(w, err.Error())
+		default:
+			jsonhttp.InternalServerError(w, "set alias failed")
+		}
+		return
+	}
+
+	jsonhttp.OK(w, newAliasResponse(a))
+}
+
+// aliasDeleteHandler removes the alias with the name.
+func (s *Service) aliasDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("delete_alias").Build()
+
+	if s.aliases == nil {
+		jsonhttp.NotImplemented(w, "aliases are not available")
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	if err := s.aliases.Delete(name); err != nil {
+		if errors.Is(err, alias.ErrNotFound) {
+			jsonhttp.NotFound(w, "alias not found")
+			return
+		}
+		logger.Debug("delete alias failed", "name", name, "error", err)
+		logger.Error(nil, "delete alias failed")
+		jsonhttp.InternalServerError(w, "delete alias failed")
+		return
+	}
+
+	jsonhttp.OK(w, nil)
+}
+
+func newAliasResponse(a alias.Alias) aliasResponse {
+	res := aliasResponse{Name: a.Name}
+	if a.Feed != nil {
+		res.Feed = &aliasFeed{
+			Owner: hex.EncodeToString(a.Feed.Owner.Bytes()),
+			Topic: hex.EncodeToString(a.Feed.Topic),
+		}
+	} else {
+		ref := a.Reference
+		res.Reference = &ref
+	}
+	return res
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/alias"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestAliases(t *testing.T) {
+	t.Parallel()
+
+	storer := smock.NewStorer()
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:  storer,
+		Tags:    tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger:  log.Noop,
+		Post:    mockpost.New(mockpost.WithAcceptAll()),
+		Aliases: alias.NewRegistry(statestore.NewStateStore(), nil),
+	})
+
+	content := []byte("<h1>docs</h1>")
+	var upload api.BzzUploadResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=index.html", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+		jsonhttptest.WithRequestHeader("Content-Type", "text/html"),
+		jsonhttptest.WithUnmarshalJSONResponse(&upload),
+	)
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/aliases/docs", http.StatusNotFound,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "alias not found",
+			Code:    http.StatusNotFound,
+		}),
+	)
+
+	ref := upload.Reference
+	jsonhttptest.Request(t, client, http.MethodPut, "/aliases/docs", http.StatusOK,
+		jsonhttptest.WithJSONRequestBody(api.AliasRequest{Reference: &ref}),
+		jsonhttptest.WithExpectedJSONResponse(api.AliasResponse{Name: "docs", Reference: &ref}),
+	)
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/bzz/~docs/", http.StatusOK,
+		jsonhttptest.WithExpectedResponse(content),
+	)
+
+	feed := &api.AliasFeed{Owner: "8d3766440f0d7b949a5e32995d09619a7f86e632", Topic: "cafe"}
+	jsonhttptest.Request(t, client, http.MethodPut, "/aliases/site", http.StatusOK,
+		jsonhttptest.WithJSONRequestBody(api.AliasRequest{Feed: feed}),
+		jsonhttptest.WithExpectedJSONResponse(api.AliasResponse{Name: "site", Feed: feed}),
+	)
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/aliases", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.AliasesResponse{Aliases: []api.AliasResponse{
+			{Name: "docs", Reference: &ref},
+			{Name: "site", Feed: feed},
+		}}),
+	)
+
+	// the invalid aliases are rejected
+	jsonhttptest.Request(t, client, http.MethodPut, "/aliases/docs", http.StatusBadRequest,
+		jsonhttptest.WithJSONRequestBody(api.AliasRequest{Reference: &ref, Feed: feed}),
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: alias.ErrInvalidTarget.Error(),
+			Code:    http.StatusBadRequest,
+		}),
+	)
+	jsonhttptest.Request(t, client, http.MethodPut, "/aliases/docs", http.StatusBadRequest,
+		jsonhttptest.WithJSONRequestBody(api.AliasRequest{Feed: &api.AliasFeed{Owner: "abc", Topic: "cafe"}}),
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "invalid feed owner",
+			Code:    http.StatusBadRequest,
+		}),
+	)
+	jsonhttptest.Request(t, client, http.MethodPut, "/aliases/a.b~c", http.StatusBadRequest,
+		jsonhttptest.WithJSONRequestBody(api.AliasRequest{Reference: &ref}),
+	)
+
+	jsonhttptest.Request(t, client, http.MethodDelete, "/aliases/docs", http.StatusOK)
+	jsonhttptest.Request(t, client, http.MethodDelete, "/aliases/docs", http.StatusNotFound)
+	jsonhttptest.Request(t, client, http.MethodGet, "/bzz/~docs/", http.StatusBadRequest)
+}
+
+// blockingLookup is the feed lookup which waits until its context is done.
+type blockingLookup struct{}
+
+func (blockingLookup) At(ctx context.Context, _, _ int64) (swarm.Chunk, feeds.Index, feeds.Index, error) {
+	<-ctx.Done()
+	return nil, nil, nil, ctx.Err()
+}
+
+func TestAliasResolveContext(t *testing.T) {
+	t.Parallel()
+
+	registry := alias.NewRegistry(statestore.NewStateStore(), newMockFactory(blockingLookup{}))
+	if err := registry.Set(alias.Alias{Name: "site", Feed: &alias.Feed{Topic: []byte{0xca, 0xfe}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	pk, _ := crypto.GenerateSecp256k1Key()
+	s := api.New(pk.PublicKey, pk.PublicKey, common.Address{}, log.Noop, nil, nil, 1, false, false, nil, []string{"*"})
+	s.Configure(crypto.NewDefaultSigner(pk), nil, nil, api.Options{}, api.ExtraOptions{Aliases: registry}, 1, nil)
+
+	// the lookup of the feed ends with the request
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.ResolveNameOrAddress(ctx, alias.Prefix+"site"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/analytics"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/gorilla/mux"
+)
+
+type analyticsCounts struct {
+	Requests int64 `json:"requests"`
+	Bytes    int64 `json:"bytes"`
+}
+
+type analyticsReference struct {
+	Reference string          `json:"reference"`
+	LastHour  analyticsCounts `json:"lastHour"`
+	LastDay   analyticsCounts `json:"lastDay"`
+	Total     analyticsCounts `json:"total"`
+}
+
+type analyticsResponse struct {
+	References []analyticsReference `json:"references"`
+}
+
+// analyticsHandler records the requests and the bytes served per top-level
+// reference of the download endpoints. Only the successful requests are
+// recorded, so that the requests for the unknown references do not replace
+// the popular ones in the tracker.
+func (s *Service) analyticsHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.analytics == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		wrapper := newResponseWriter(w)
+		h.ServeHTTP(wrapper, r)
+
+		if wrapper.statusCode >= http.StatusBadRequest {
+			return
+		}
+		if ref := mux.Vars(r)["address"]; ref != "" {
+			s.analytics.Record(ref, wrapper.size)
+		}
+	})
+}
+
+// analyticsGetHandler returns the most requested references
+// sorted by the bytes served in the last day.
+func (s *Service) analyticsGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_analytics").Build()
+
+	if s.analytics == nil {
+		jsonhttp.NotImplemented(w, "analytics are not available")
+		return
+	}
+
+	queries := struct {
+		Limit int `map:"limit"`
+	}{
+		Limit: 100, // Default limit.
+	}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	stats := s.analytics.Stats()
+	if queries.Limit > 0 && queries.Limit < len(stats) {
+		stats = stats[:queries.Limit]
+	}
+
+	res := analyticsResponse{References: make([]analyticsReference, 0, len(stats))}
+	for _, st := range stats {
+		res.References = append(res.References, analyticsReference{
+			Reference: st.Reference,
+			LastHour:  newAnalyticsCounts(st.LastHour),
+			LastDay:   newAnalyticsCounts(st.LastDay),
+			Total:     newAnalyticsCounts(st.Total),
+		})
+	}
+	jsonhttp.OK(w, res)
+}
+
+func newAnalyticsCounts(c analytics.Counts) analyticsCounts {
+	return analyticsCounts{Requests: c.Requests, Bytes: c.Bytes}
+}
@@ -0,0 +1,63 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/analytics"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/storage"
+	mockstorer "github.com/ethersphere/bee/pkg/storage/mock"
+	testingc "github.com/ethersphere/bee/pkg/storage/testing"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestAnalytics(t *testing.T) {
+	t.Parallel()
+
+	var (
+		storer  = mockstorer.NewStorer()
+		tracker = analytics.New(0)
+		chunk   = testingc.GenerateTestRandomChunk()
+	)
+	if _, err := storer.Put(context.Background(), storage.ModePutUpload, chunk); err != nil {
+		t.Fatal(err)
+	}
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:    storer,
+		Logger:    log.Noop,
+		Analytics: tracker,
+	})
+	debugClient, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:    storer,
+		Logger:    log.Noop,
+		Analytics: tracker,
+		DebugAPI:  true,
+	})
+
+	for i := 0; i < 2; i++ {
+		jsonhttptest.Request(t, client, http.MethodGet, "/chunks/"+chunk.Address().String(), http.StatusOK)
+	}
+	// the requests for the missing content are not recorded
+	jsonhttptest.Request(t, client, http.MethodGet, "/chunks/"+swarm.RandAddress(t).String(), http.StatusNotFound)
+
+	want := api.AnalyticsCounts{Requests: 2, Bytes: int64(2 * len(chunk.Data()))}
+	jsonhttptest.Request(t, debugClient, http.MethodGet, "/debug/analytics", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.AnalyticsResponse{
+			References: []api.AnalyticsReference{{
+				Reference: chunk.Address().String(),
+				LastHour:  want,
+				LastDay:   want,
+				Total:     want,
+			}},
+		}),
+	)
+}
@@ -18,8 +18,10 @@ import (
 	"math"
 	"math/big"
 	"mime"
+	"net"
 	"net/http"
 	"reflect"
+	"runtime/pprof"
 	"strconv"
 	"strings"
 	"sync"
@@ -27,28 +29,51 @@ import (
 	"unicode/utf8"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/accesscontrol"
 	"github.com/ethersphere/bee/pkg/accounting"
+	"github.com/ethersphere/bee/pkg/alias"
+	"github.com/ethersphere/bee/pkg/analytics"
+	"github.com/ethersphere/bee/pkg/auditlog"
 	"github.com/ethersphere/bee/pkg/auth"
+	"github.com/ethersphere/bee/pkg/broadcast"
+	"github.com/ethersphere/bee/pkg/challenge"
 	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/deploy"
+	"github.com/ethersphere/bee/pkg/dnslink"
+	"github.com/ethersphere/bee/pkg/eventlog"
+	"github.com/ethersphere/bee/pkg/faults"
 	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/crdt"
 	"github.com/ethersphere/bee/pkg/file/pipeline"
 	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/ipfs"
+	"github.com/ethersphere/bee/pkg/jobs"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/maintenance"
+	"github.com/ethersphere/bee/pkg/membudget"
 	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/ethersphere/bee/pkg/pingpong"
 	"github.com/ethersphere/bee/pkg/pinning"
 	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/postage/events"
 	"github.com/ethersphere/bee/pkg/postage/postagecontract"
+	"github.com/ethersphere/bee/pkg/profiling"
 	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/ethersphere/bee/pkg/pss/session"
 	"github.com/ethersphere/bee/pkg/pusher"
+	"github.com/ethersphere/bee/pkg/replica"
 	"github.com/ethersphere/bee/pkg/resolver"
+	"github.com/ethersphere/bee/pkg/resolver/cidv1"
 	"github.com/ethersphere/bee/pkg/resolver/client/ens"
+	"github.com/ethersphere/bee/pkg/retrieval"
 	"github.com/ethersphere/bee/pkg/sctx"
 	"github.com/ethersphere/bee/pkg/settlement"
 	"github.com/ethersphere/bee/pkg/settlement/swap"
 	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook"
 	"github.com/ethersphere/bee/pkg/settlement/swap/erc20"
+	"github.com/ethersphere/bee/pkg/shaping"
+	"github.com/ethersphere/bee/pkg/spam"
 	"github.com/ethersphere/bee/pkg/status"
 	"github.com/ethersphere/bee/pkg/steward"
 	"github.com/ethersphere/bee/pkg/storage"
@@ -57,10 +82,13 @@ import (
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
 	"github.com/ethersphere/bee/pkg/topology"
+	"github.com/ethersphere/bee/pkg/topology/depthmonitor"
 	"github.com/ethersphere/bee/pkg/topology/lightnode"
 	"github.com/ethersphere/bee/pkg/tracing"
 	"github.com/ethersphere/bee/pkg/transaction"
+	"github.com/ethersphere/bee/pkg/transform"
 	"github.com/ethersphere/bee/pkg/traversal"
+	"github.com/ethersphere/bee/pkg/warmer"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
 	"github.com/hashicorp/go-multierror"
@@ -72,24 +100,50 @@ import (
 // loggerName is the tree path name of the logger for this package.
 const loggerName = "api"
 
+// DefaultGatewayDomain is the base domain of the subdomain gateway
+// if none is configured.
+const DefaultGatewayDomain = "swarm.localhost"
+
 const (
-	SwarmPinHeader            = "Swarm-Pin"
-	SwarmTagHeader            = "Swarm-Tag"
-	SwarmEncryptHeader        = "Swarm-Encrypt"
-	SwarmIndexDocumentHeader  = "Swarm-Index-Document"
-	SwarmErrorDocumentHeader  = "Swarm-Error-Document"
-	SwarmFeedIndexHeader      = "Swarm-Feed-Index"
-	SwarmFeedIndexNextHeader  = "Swarm-Feed-Index-Next"
-	SwarmCollectionHeader     = "Swarm-Collection"
-	SwarmPostageBatchIdHeader = "Swarm-Postage-Batch-Id"
-	SwarmDeferredUploadHeader = "Swarm-Deferred-Upload"
+	SwarmPinHeader               = "Swarm-Pin"
+	SwarmTagHeader               = "Swarm-Tag"
+	SwarmTagNameHeader           = "Swarm-Tag-Name"
+	SwarmEncryptHeader           = "Swarm-Encrypt"
+	SwarmIndexDocumentHeader     = "Swarm-Index-Document"
+	SwarmErrorDocumentHeader     = "Swarm-Error-Document"
+	SwarmWebsiteRedirectsHeader  = "Swarm-Website-Redirects"
+	SwarmFeedIndexHeader         = "Swarm-Feed-Index"
+	SwarmFeedIndexNextHeader     = "Swarm-Feed-Index-Next"
+	SwarmCollectionHeader        = "Swarm-Collection"
+	SwarmPostageBatchIdHeader    = "Swarm-Postage-Batch-Id"
+	SwarmDeferredUploadHeader    = "Swarm-Deferred-Upload"
+	SwarmRetrievalModeHeader     = "Swarm-Retrieval-Mode"
+	SwarmRetrievalPriorityHeader = "Swarm-Retrieval-Priority"
+	SwarmReadYourWritesHeader    = "Swarm-Read-Your-Writes"
+	SwarmDiagnosticsHeader       = "Swarm-Diagnostics"
+	SwarmContentTypeHeader       = "Swarm-Content-Type"
+	SwarmFilenameHeader          = "Swarm-Filename"
+	SwarmActHeader               = "Swarm-Act"
+	SwarmActGranteesHeader       = "Swarm-Act-Grantees"
+	SwarmCompressionHeader       = "Swarm-Compression"
+	SwarmPreserveMtimeHeader     = "Swarm-Preserve-Mtime"
+
+	SwarmDiagnosticsChunksTrailer        = "Swarm-Diagnostics-Chunks"
+	SwarmDiagnosticsCacheHitRatioTrailer = "Swarm-Diagnostics-Cache-Hit-Ratio"
+	SwarmDiagnosticsPeersTrailer         = "Swarm-Diagnostics-Peers"
+	SwarmDiagnosticsRetriesTrailer       = "Swarm-Diagnostics-Retries"
+	SwarmDiagnosticsRetrievalTimeTrailer = "Swarm-Diagnostics-Retrieval-Time"
+
+	SwarmChallengeHeader           = "Swarm-Challenge"
+	SwarmChallengeDifficultyHeader = "Swarm-Challenge-Difficulty"
+	SwarmChallengeTokenHeader      = "Swarm-Challenge-Token"
 )
 
 // The size of buffer used for prefetching content with Langos.
 // Warning: This value influences the number of chunk requests and chunker join goroutines
 // per file request.
 // Recommended value is 8 or 16 times the io.Copy default buffer value which is 32kB, depending
-// on the file size. Use lookaheadBufferSize() to get the correct buffer size for the request.
+// on the file size. The LookaheadPolicy selects the buffer size for the request.
 const (
 	smallFileBufferSize = 8 * 32 * 1024
 	largeFileBufferSize = 16 * 32 * 1024
@@ -97,6 +151,17 @@ const (
 	largeBufferFilesizeThreshold = 10 * 1000000 // ten megs
 
 	uploadSem = 50
+
+	// stampBatchSize is the largest number of the chunks pushed to
+	// the network whose stamps are signed at once.
+	stampBatchSize = 64
+
+	// aliasResolveTimeout bounds the lookup of the feed of an alias.
+	aliasResolveTimeout = 30 * time.Second
+
+	// defaultCheckExistingMaxSize is the largest body of the upload
+	// checking the existing content that is spooled to the disk.
+	defaultCheckExistingMaxSize = 1 << 30 // one gigabyte
 )
 
 const (
@@ -117,6 +182,7 @@ var (
 	errBatchUnusable                    = errors.New("batch not usable")
 	errUnsupportedDevNodeOperation      = errors.New("operation not supported in dev mode")
 	errOperationSupportedOnlyInFullMode = errors.New("operation is supported only in full mode")
+	errNotReadable                      = errors.New("content not readable locally")
 )
 
 type Service struct {
@@ -125,9 +191,23 @@ type Service struct {
 	storer          storage.Storer
 	resolver        resolver.Interface
 	pss             pss.Interface
+	pssSessions     *session.Service
+	broadcast       *broadcast.Service
 	traversal       traversal.Traverser
 	pinning         pinning.Interface
+	pinExpiry       pinning.ExpirySubscriber
+	batchEvents     events.Subscriber
 	steward         steward.Interface
+	warmer          *warmer.Service
+	deploy          *deploy.Service
+	crdt            *crdt.Service
+	aliases         *alias.Registry
+	analytics       *analytics.Tracker
+	transform       *transform.Service
+	ipfs            *ipfs.Gateway
+	challenge       *challenge.Service
+	shaping         *shaping.Scheduler
+	downloadLimiter *DownloadLimiter
 	logger          log.Logger
 	loggerV1        log.Logger
 	tracer          *tracing.Tracer
@@ -136,10 +216,22 @@ type Service struct {
 	post            postage.Service
 	postageContract postagecontract.Interface
 	chunkPushC      chan *pusher.Op
+	batchQueues     *batchQueues
 	probe           *Probe
+	faults          *faults.Injector
 	metricsRegistry *prometheus.Registry
 	stakingContract staking.Contract
 	indexDebugger   StorageIndexDebugger
+	reserveEvicter  ReserveEvicter
+	spam            *spam.Detector
+	depthMonitor    *depthmonitor.Service
+	replica         *replica.Replicator
+	memoryBudget    *membudget.Budget
+	maintenance     *maintenance.Scheduler
+	jobs            *jobs.Manager
+	events          *eventlog.Log
+	dnsLink         *dnslink.Hook
+	accessControl   *accesscontrol.Controller
 	Options
 
 	http.Handler
@@ -150,6 +242,23 @@ type Service struct {
 	wsWg sync.WaitGroup // wait for all websockets to close on exit
 	quit chan struct{}
 
+	// the chunk download streams open at once
+	downloadStreams chan struct{}
+
+	// the responses of the requests with the idempotency key
+	// are stored in the state store
+	stateStore          storage.StateStorer
+	idempotencyMu       sync.Mutex
+	idempotencyInFlight map[string]struct{}
+	idempotencySwept    time.Time
+
+	// the upload sessions which are appended to or committed
+	uploadSessionsMu       sync.Mutex
+	uploadSessionsInFlight map[string]struct{}
+	uploadSessionsSwept    time.Time
+
+	auditLog *auditlog.Logger
+
 	// from debug API
 	overlay           *swarm.Address
 	publicKey         ecdsa.PublicKey
@@ -164,6 +273,7 @@ type Service struct {
 	chequebook     chequebook.Service
 	pseudosettle   settlement.Interface
 	pingpong       pingpong.Interface
+	peerRetriever  retrieval.PeerRetriever
 
 	batchStore postage.Storer
 	syncStatus func() (bool, error)
@@ -210,13 +320,22 @@ func (s *Service) SetRedistributionAgent(redistributionAgent *storageincentives.
 }
 
 type Options struct {
-	CORSAllowedOrigins []string
-	WsPingPeriod       time.Duration
-	Restricted         bool
+	CORSAllowedOrigins   []string
+	WsPingPeriod         time.Duration
+	Restricted           bool
+	Lookahead            *LookaheadPolicy
+	Compression          *CompressionPolicy
+	GraphQL              bool
+	DirListing           bool
+	GatewayDomain        string
+	GatewaySubdomainOnly bool
+	TrustedProxies       []*net.IPNet
+	CheckExistingMaxSize int64
 }
 
 type ExtraOptions struct {
 	Pingpong         pingpong.Interface
+	PeerRetriever    retrieval.PeerRetriever
 	TopologyDriver   topology.Driver
 	LightNodes       *lightnode.Container
 	Accounting       accounting.Interface
@@ -226,18 +345,44 @@ type ExtraOptions struct {
 	BlockTime        time.Duration
 	Tags             *tags.Tags
 	Storer           storage.Storer
+	StateStore       storage.StateStorer
 	Resolver         resolver.Interface
 	Pss              pss.Interface
+	PssSessions      *session.Service
+	Broadcast        *broadcast.Service
 	TraversalService traversal.Traverser
 	Pinning          pinning.Interface
+	PinExpiry        pinning.ExpirySubscriber
+	BatchEvents      events.Subscriber
 	FeedFactory      feeds.Factory
 	Post             postage.Service
 	PostageContract  postagecontract.Interface
 	Staking          staking.Contract
 	Steward          steward.Interface
+	Warmer           *warmer.Service
+	Deploy           *deploy.Service
+	Crdt             *crdt.Service
+	Aliases          *alias.Registry
+	Analytics        *analytics.Tracker
+	Transform        *transform.Service
+	IPFS             *ipfs.Gateway
+	Challenge        *challenge.Service
+	Shaping          *shaping.Scheduler
+	DownloadLimiter  *DownloadLimiter
 	SyncStatus       func() (bool, error)
 	IndexDebugger    StorageIndexDebugger
+	ReserveEvicter   ReserveEvicter
+	Spam             *spam.Detector
+	DepthMonitor     *depthmonitor.Service
+	Replica          *replica.Replicator
+	MemoryBudget     *membudget.Budget
+	Maintenance      *maintenance.Scheduler
+	Jobs             *jobs.Manager
+	Events           *eventlog.Log
+	DNSLink          *dnslink.Hook
+	AccessControl    *accesscontrol.Controller
 	NodeStatus       *status.Service
+	AuditLog         *auditlog.Logger
 }
 
 func New(publicKey, pssPublicKey ecdsa.PublicKey, ethereumAddress common.Address, logger log.Logger, transaction transaction.Service, batchStore postage.Storer, beeMode BeeNodeMode, chequebookEnabled, swapEnabled bool, chainBackend transaction.Backend, cors []string) *Service {
@@ -256,6 +401,10 @@ func New(publicKey, pssPublicKey ecdsa.PublicKey, ethereumAddress common.Address
 	s.batchStore = batchStore
 	s.chainBackend = chainBackend
 	s.metricsRegistry = newDebugMetrics()
+	s.downloadStreams = make(chan struct{}, maxChunkDownloadStreams)
+	if faults.Enabled {
+		s.faults = faults.Default
+	}
 	s.preMapHooks = map[string]func(v string) (string, error){
 		"mimeMediaType": func(v string) (string, error) {
 			typ, _, err := mime.ParseMediaType(v)
@@ -281,6 +430,7 @@ func New(publicKey, pssPublicKey ecdsa.PublicKey, ethereumAddress common.Address
 func (s *Service) Configure(signer crypto.Signer, auth auth.Authenticator, tracer *tracing.Tracer, o Options, e ExtraOptions, chainID int64, erc20 erc20.Service) <-chan *pusher.Op {
 	s.auth = auth
 	s.chunkPushC = make(chan *pusher.Op)
+	s.batchQueues = newBatchQueues(s.chunkPushC)
 	s.signer = signer
 	s.Options = o
 	s.tracer = tracer
@@ -292,16 +442,46 @@ func (s *Service) Configure(signer crypto.Signer, auth auth.Authenticator, trace
 	s.storer = e.Storer
 	s.resolver = e.Resolver
 	s.pss = e.Pss
+	s.pssSessions = e.PssSessions
+	s.broadcast = e.Broadcast
 	s.traversal = e.TraversalService
 	s.pinning = e.Pinning
+	s.pinExpiry = e.PinExpiry
+	s.batchEvents = e.BatchEvents
+	s.stateStore = e.StateStore
+	s.auditLog = e.AuditLog
 	s.feedFactory = e.FeedFactory
 	s.post = e.Post
 	s.postageContract = e.PostageContract
 	s.steward = e.Steward
+	s.warmer = e.Warmer
+	s.deploy = e.Deploy
+	s.crdt = e.Crdt
+	s.aliases = e.Aliases
+	s.analytics = e.Analytics
+	s.transform = e.Transform
+	s.ipfs = e.IPFS
+	s.challenge = e.Challenge
+	s.shaping = e.Shaping
+	s.downloadLimiter = e.DownloadLimiter
+	if s.downloadLimiter == nil {
+		s.downloadLimiter = NewDownloadLimiter(DownloadLimits{})
+	}
 	s.stakingContract = e.Staking
 	s.indexDebugger = e.IndexDebugger
+	s.reserveEvicter = e.ReserveEvicter
+	s.spam = e.Spam
+	s.depthMonitor = e.DepthMonitor
+	s.replica = e.Replica
+	s.memoryBudget = e.MemoryBudget
+	s.maintenance = e.Maintenance
+	s.jobs = e.Jobs
+	s.events = e.Events
+	s.dnsLink = e.DNSLink
+	s.accessControl = e.AccessControl
 
 	s.pingpong = e.Pingpong
+	s.peerRetriever = e.PeerRetriever
 	s.topologyDriver = e.TopologyDriver
 	s.accounting = e.Accounting
 	s.chequebook = e.Chequebook
@@ -321,17 +501,6 @@ func (s *Service) Configure(signer crypto.Signer, auth auth.Authenticator, trace
 
 	s.statusService = e.NodeStatus
 
-	s.preMapHooks["resolve"] = func(v string) (string, error) {
-		switch addr, err := s.resolveNameOrAddress(v); {
-		case err == nil:
-			return addr.String(), nil
-		case errors.Is(err, ens.ErrNotImplemented):
-			return v, nil
-		default:
-			return "", err
-		}
-	}
-
 	return s.chunkPushC
 }
 
@@ -339,6 +508,12 @@ func (s *Service) SetProbe(probe *Probe) {
 	s.probe = probe
 }
 
+// SetFaultInjector sets the injector controlled through the faults
+// endpoints. The endpoints are mounted only if the injector is set.
+func (s *Service) SetFaultInjector(i *faults.Injector) {
+	s.faults = i
+}
+
 // Close hangs up running websockets on shutdown.
 func (s *Service) Close() error {
 	s.logger.Info("api shutting down")
@@ -360,11 +535,11 @@ func (s *Service) Close() error {
 }
 
 // getOrCreateTag attempts to get the tag if an id is supplied, and returns an error if it does not exist.
-// If no id is supplied, it will attempt to create a new tag with a generated name and return it.
-func (s *Service) getOrCreateTag(tagUid string) (*tags.Tag, bool, error) {
+// If no id is supplied, it will attempt to create a new tag with the given name and return it.
+func (s *Service) getOrCreateTag(tagUid, tagName string) (*tags.Tag, bool, error) {
 	// if tag ID is not supplied, create a new tag
 	if tagUid == "" {
-		tag, err := s.tags.Create(0)
+		tag, err := s.tags.CreateNamed(0, tagName, nil)
 		if err != nil {
 			return nil, false, fmt.Errorf("cannot create tag: %w", err)
 		}
@@ -382,7 +557,22 @@ func (s *Service) getTag(tagUid string) (*tags.Tag, error) {
 	return s.tags.Get(uint32(uid))
 }
 
-func (s *Service) resolveNameOrAddress(str string) (swarm.Address, error) {
+func (s *Service) resolveNameOrAddress(ctx context.Context, str string) (swarm.Address, error) {
+	// Try and resolve the locally registered alias.
+	if name, ok := alias.Name(str); ok {
+		if s.aliases == nil {
+			return swarm.ZeroAddress, errNoResolver
+		}
+		ctx, cancel := context.WithTimeout(ctx, aliasResolveTimeout)
+		defer cancel()
+		addr, err := s.aliases.Resolve(ctx, name)
+		if err != nil {
+			return swarm.ZeroAddress, fmt.Errorf("%w: %w", errInvalidNameOrAddress, err)
+		}
+		s.loggerV1.Debug("resolve name: alias resolved successfully", "string", str, "address", addr)
+		return addr, nil
+	}
+
 	// Try and mapStructure the name as a bzz address.
 	addr, err := swarm.ParseHexAddress(str)
 	if err == nil {
@@ -390,6 +580,13 @@ func (s *Service) resolveNameOrAddress(str string) (swarm.Address, error) {
 		return addr, nil
 	}
 
+	// Try and parse the name as the CID of the swarm reference, which fits
+	// into the subdomain label unlike the hex encoded reference.
+	if addr, err := (cidv1.Resolver{}).Resolve(str); err == nil {
+		s.loggerV1.Debug("resolve name: parsing cid successful", "string", str, "address", addr)
+		return addr, nil
+	}
+
 	// If no resolver is not available, return an error.
 	if s.resolver == nil {
 		return swarm.ZeroAddress, errNoResolver
@@ -422,6 +619,24 @@ func requestEncrypt(r *http.Request) bool {
 	return strings.ToLower(r.Header.Get(SwarmEncryptHeader)) == boolHeaderSetValue
 }
 
+// requestPreserveMtime reports whether the modification times
+// of the files of the uploaded collection are stored.
+func requestPreserveMtime(r *http.Request) bool {
+	return strings.ToLower(r.Header.Get(SwarmPreserveMtimeHeader)) == boolHeaderSetValue
+}
+
+// requestReadYourWrites reports whether the upload response is returned only
+// once the uploaded content is servable from the local store of the node.
+func requestReadYourWrites(r *http.Request) bool {
+	return strings.ToLower(r.Header.Get(SwarmReadYourWritesHeader)) == boolHeaderSetValue
+}
+
+// requestDiagnostics reports whether the diagnostics of the
+// download are sent in the trailers of the response.
+func requestDiagnostics(r *http.Request) bool {
+	return r.Method == http.MethodGet && strings.ToLower(r.Header.Get(SwarmDiagnosticsHeader)) == boolHeaderSetValue
+}
+
 func requestDeferred(r *http.Request) (bool, error) {
 	if h := strings.ToLower(r.Header.Get(SwarmDeferredUploadHeader)); h != "" {
 		return strconv.ParseBool(h)
@@ -429,6 +644,45 @@ func requestDeferred(r *http.Request) (bool, error) {
 	return true, nil
 }
 
+// requestRespondAsync reports whether the client prefers the response to the
+// deferred upload before its chunks are pushed to the network, which is
+// requested by the respond-async preference of the Prefer header.
+func requestRespondAsync(r *http.Request) bool {
+	deferred, err := requestDeferred(r)
+	if err != nil || !deferred {
+		return false
+	}
+	for _, v := range r.Header.Values("Prefer") {
+		for _, p := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(p), "respond-async") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// respondUploaded responds with the reference of the uploaded content. The
+// deferred uploads which prefer the asynchronous response are accepted, the
+// progress of pushing their chunks to the network is polled from the tag in
+// the Location header.
+func respondUploaded(w http.ResponseWriter, r *http.Request, tag *tags.Tag, response interface{}) {
+	w.Header().Set(SwarmTagHeader, fmt.Sprint(tag.Uid))
+	w.Header().Add("Access-Control-Expose-Headers", SwarmTagHeader)
+	if !requestRespondAsync(r) {
+		jsonhttp.Created(w, response)
+		return
+	}
+	location := fmt.Sprintf("/tags/%d", tag.Uid)
+	if strings.HasPrefix(r.URL.Path, rootPath+"/") {
+		location = rootPath + location
+	}
+	w.Header().Set("Location", location)
+	w.Header().Set("Preference-Applied", "respond-async")
+	w.Header().Add("Access-Control-Expose-Headers", "Location")
+	jsonhttp.Accepted(w, response)
+}
+
 func requestPostageBatchId(r *http.Request) ([]byte, error) {
 	if h := strings.ToLower(r.Header.Get(SwarmPostageBatchIdHeader)); h != "" {
 		if len(h) != 64 {
@@ -575,7 +829,16 @@ func (s *Service) newTracingHandler(spanName string) func(h http.Handler) http.H
 				// ignore
 			}
 
-			h.ServeHTTP(w, r.WithContext(ctx))
+			// expose the tracing context, so that the clients can
+			// look up the trace of the request
+			if err := s.tracer.AddContextHTTPHeader(ctx, w.Header()); err == nil {
+				w.Header().Add("Access-Control-Expose-Headers", tracing.TraceContextHeaderName)
+			}
+
+			// label the profiles of the handler with the span name
+			pprof.Do(ctx, profiling.Labels("api", "handler", spanName), func(ctx context.Context) {
+				h.ServeHTTP(w, r.WithContext(ctx))
+			})
 		})
 	}
 }
@@ -634,20 +897,13 @@ func (s *Service) gasConfigMiddleware(handlerName string) func(h http.Handler) h
 	}
 }
 
-func lookaheadBufferSize(size int64) int {
-	if size <= largeBufferFilesizeThreshold {
-		return smallFileBufferSize
-	}
-	return largeFileBufferSize
-}
-
 // corsHandler sets CORS headers to HTTP response if allowed origins are configured.
 func (s *Service) corsHandler(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if o := r.Header.Get("Origin"); o != "" && s.checkOrigin(r) {
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
 			w.Header().Set("Access-Control-Allow-Origin", o)
-			w.Header().Set("Access-Control-Allow-Headers", "User-Agent, Origin, Accept, Authorization, Content-Type, X-Requested-With, Decompressed-Content-Length, Access-Control-Request-Headers, Access-Control-Request-Method, Swarm-Tag, Swarm-Pin, Swarm-Encrypt, Swarm-Index-Document, Swarm-Error-Document, Swarm-Collection, Swarm-Postage-Batch-Id, Swarm-Deferred-Upload, Gas-Price, Range, Accept-Ranges, Content-Encoding")
+			w.Header().Set("Access-Control-Allow-Headers", "User-Agent, Origin, Accept, Authorization, Content-Type, X-Requested-With, Decompressed-Content-Length, Access-Control-Request-Headers, Access-Control-Request-Method, Swarm-Tag, Swarm-Tag-Name, Swarm-Pin, Swarm-Encrypt, Swarm-Index-Document, Swarm-Error-Document, Swarm-Collection, Swarm-Postage-Batch-Id, Swarm-Deferred-Upload, Swarm-Retrieval-Mode, Swarm-Retrieval-Priority, Swarm-Content-Type, Swarm-Filename, Swarm-Challenge-Token, Swarm-Preserve-Mtime, Idempotency-Key, Upload-Offset, Prefer, Gas-Price, Range, Accept-Ranges, Content-Encoding, X-Request-Id, Traceparent, Swarm-Trace-Id")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS, POST, PUT, DELETE")
 			w.Header().Set("Access-Control-Max-Age", "3600")
 		}
@@ -655,6 +911,87 @@ func (s *Service) corsHandler(h http.Handler) http.Handler {
 	})
 }
 
+// retrievalModeHandler sets the retrieval mode from the request header to the
+// request context, so that the chunks which are not found locally are retrieved
+// from the network in that mode.
+func (s *Service) retrievalModeHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := r.Header.Get(SwarmRetrievalModeHeader)
+		if v == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+		mode, err := retrieval.ParseMode(v)
+		if err != nil {
+			s.logger.Debug("invalid retrieval mode", "value", v, "error", err)
+			jsonhttp.BadRequest(w, "invalid retrieval mode")
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(retrieval.WithMode(r.Context(), mode)))
+	})
+}
+
+// retrievalPriorityHandler sets the retrieval priority from the request header
+// to the context of the request, so that the chunks are retrieved by the workers
+// and within the accounting budget of the priority class.
+// It is chained only on the download routes, the only ones the header applies to.
+func (s *Service) retrievalPriorityHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := r.Header.Get(SwarmRetrievalPriorityHeader)
+		if v == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+		priority, err := retrieval.ParsePriority(v)
+		if err != nil {
+			s.logger.Debug("invalid retrieval priority", "value", v, "error", err)
+			jsonhttp.BadRequest(w, "invalid retrieval priority")
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(retrieval.WithPriority(r.Context(), priority)))
+	})
+}
+
+// memoryBudgetHandler sets the memory budget to the request context, so that
+// the memory of the downloaded and uploaded content is acquired from it and
+// the requests wait while the budget is exhausted.
+func (s *Service) memoryBudgetHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(membudget.WithBudget(r.Context(), s.memoryBudget)))
+	})
+}
+
+// maxRequestIDLength is the maximum length of the client supplied request id.
+const maxRequestIDLength = 128
+
+// requestIDHandler adds the client supplied request id to the request context,
+// so that it is logged and propagated with the tracing spans of the request,
+// and echoes it in the response.
+func (s *Service) requestIDHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := r.Header.Get(tracing.RequestIDHeaderName); isValidRequestID(id) {
+			w.Header().Set(tracing.RequestIDHeaderName, id)
+			w.Header().Add("Access-Control-Expose-Headers", tracing.RequestIDHeaderName)
+			r = r.WithContext(tracing.WithRequestID(r.Context(), id))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// isValidRequestID returns true if the request id is not empty, not too long
+// and consists only of the visible ASCII characters.
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
 // checkOrigin returns true if the origin is not set or is equal to the request host.
 func (s *Service) checkOrigin(r *http.Request) bool {
 	origin := r.Header["Origin"]
@@ -691,6 +1028,31 @@ func (e *validationError) Error() string {
 // It's a helper method for the handlers, which reduces the chattiness
 // of the code.
 func (s *Service) mapStructure(input, output interface{}) func(string, log.Logger, http.ResponseWriter) {
+	return s.mapStructureHooks(input, output, s.preMapHooks)
+}
+
+// mapStructureContext is like mapStructure, but it also resolves the names
+// of the fields with the resolve hook to the addresses, within the context
+// of the request.
+func (s *Service) mapStructureContext(ctx context.Context, input, output interface{}) func(string, log.Logger, http.ResponseWriter) {
+	hooks := make(map[string]func(v string) (string, error), len(s.preMapHooks)+1)
+	for tag, hook := range s.preMapHooks {
+		hooks[tag] = hook
+	}
+	hooks["resolve"] = func(v string) (string, error) {
+		switch addr, err := s.resolveNameOrAddress(ctx, v); {
+		case err == nil:
+			return addr.String(), nil
+		case errors.Is(err, ens.ErrNotImplemented):
+			return v, nil
+		default:
+			return "", err
+		}
+	}
+	return s.mapStructureHooks(input, output, hooks)
+}
+
+func (s *Service) mapStructureHooks(input, output interface{}, hooks map[string]func(v string) (string, error)) func(string, log.Logger, http.ResponseWriter) {
 	// response unifies the response format for parsing and validation errors.
 	response := func(err error) func(string, log.Logger, http.ResponseWriter) {
 		return func(msg string, logger log.Logger, w http.ResponseWriter) {
@@ -729,7 +1091,7 @@ func (s *Service) mapStructure(input, output interface{}) func(string, log.Logge
 		}
 	}
 
-	if err := mapStructure(input, output, s.preMapHooks); err != nil {
+	if err := mapStructure(input, output, hooks); err != nil {
 		return response(err)
 	}
 
@@ -816,10 +1178,11 @@ func (s *Service) newStamperPutter(r *http.Request) (storage.Storer, func() erro
 	}
 
 	if deferred {
-		p := newStoringStamperPutter(s.storer, issuer, s.signer)
+		p := newStoringStamperPutter(s.storer, s.newStamper(issuer))
 		return p, save, nil
 	}
-	p := newPushStamperPutter(s.storer, issuer, s.signer, s.chunkPushC)
+	p := newPushStamperPutter(s.storer, s.newStamper(issuer), s.batchQueues)
+	p.cache = requestReadYourWrites(r)
 
 	wait := func() error {
 		if err := save(); err != nil {
@@ -831,24 +1194,46 @@ func (s *Service) newStamperPutter(r *http.Request) (storage.Storer, func() erro
 	return p, wait, err
 }
 
-type pushStamperPutter struct {
+// newStampedPutter returns the putter of the chunks which are already
+// stamped by the client, as opposed to newStamperPutter.
+func (s *Service) newStampedPutter(r *http.Request) (storage.Storer, func() error, error) {
+	deferred, err := requestDeferred(r)
+	if err != nil {
+		return nil, noopWaitFn, fmt.Errorf("request deferred: %w", err)
+	}
+
+	if !deferred && s.beeMode == DevMode {
+		return nil, noopWaitFn, errUnsupportedDevNodeOperation
+	}
+
+	if deferred {
+		return s.storer, noopWaitFn, nil
+	}
+	p := newPushPutter(s.storer, s.batchQueues)
+	p.cache = requestReadYourWrites(r)
+	return p, p.Wait, nil
+}
+
+// pushPutter pushes the stamped chunks directly to the network.
+type pushPutter struct {
 	storage.Storer
-	stamper postage.Stamper
-	eg      errgroup.Group
-	c       chan *pusher.Op
-	sem     chan struct{}
+	eg     errgroup.Group
+	queues *batchQueues
+	sem    chan struct{}
+	cache  bool // store the pushed chunks in the local cache
 }
 
-func newPushStamperPutter(s storage.Storer, i *postage.StampIssuer, signer crypto.Signer, cc chan *pusher.Op) *pushStamperPutter {
-	stamper := postage.NewStamper(i, signer)
-	return &pushStamperPutter{Storer: s, stamper: stamper, c: cc, sem: make(chan struct{}, uploadSem)}
+// newPushPutter returns the pushPutter which pushes at once up to uploadSem
+// chunks, in turns with the uploads to the other batches.
+func newPushPutter(s storage.Storer, queues *batchQueues) *pushPutter {
+	return &pushPutter{Storer: s, queues: queues, sem: make(chan struct{}, uploadSem)}
 }
 
-func (p *pushStamperPutter) Wait() error {
+func (p *pushPutter) Wait() error {
 	return p.eg.Wait()
 }
 
-func (p *pushStamperPutter) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk) (exists []bool, err error) {
+func (p *pushPutter) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk) (exists []bool, err error) {
 	exists = make([]bool, len(chs))
 
 	for i, c := range chs {
@@ -861,17 +1246,122 @@ func (p *pushStamperPutter) Put(ctx context.Context, mode storage.ModePut, chs .
 			exists[i] = true
 			continue
 		}
-		stamp, err := p.stamper.Stamp(c.Address())
+
+		p.putChunk(ctx, c)
+	}
+	return exists, nil
+}
+
+// pushStamperPutter stamps the chunks and pushes them to the network. The
+// stamps are reserved as the chunks are put, and are signed together before
+// the chunks are pushed at the end of each put, so that the chunks of a put
+// are pushed once it returns. The restamped chunks are signed in batches.
+type pushStamperPutter struct {
+	*pushPutter
+	stamper postage.BatchStamper
+
+	mu      sync.Mutex
+	ctx     context.Context
+	pending []swarm.Chunk
+	stamps  []*postage.Stamp
+}
+
+func newPushStamperPutter(s storage.Storer, stamper postage.BatchStamper, queues *batchQueues) *pushStamperPutter {
+	return &pushStamperPutter{pushPutter: newPushPutter(s, queues), stamper: stamper}
+}
+
+func (p *pushStamperPutter) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk) (exists []bool, err error) {
+	exists = make([]bool, len(chs))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, c := range chs {
+		// skips chunk we already know about
+		has, err := p.Storer.Has(ctx, c.Address())
 		if err != nil {
 			return nil, err
 		}
+		if has || swarm.ContainsChunkWithAddress(chs[:i], c.Address()) || swarm.ContainsChunkWithAddress(p.pending, c.Address()) {
+			exists[i] = true
+			continue
+		}
+		if err := p.stampLocked(ctx, c); err != nil {
+			p.stamper.Release(p.stamps)
+			p.pending, p.stamps = nil, nil
+			return nil, err
+		}
+	}
 
-		p.putChunk(ctx, c.WithStamp(stamp))
+	// the chunks are pushed before the upload acknowledges them
+	if err := p.flush(); err != nil {
+		return nil, err
 	}
 	return exists, nil
 }
 
-func (p *pushStamperPutter) putChunk(ctx context.Context, ch swarm.Chunk) {
+// stamp reserves the stamp of the chunk, which is pushed after the stamps
+// of the pending chunks are signed.
+func (p *pushStamperPutter) stamp(ctx context.Context, c swarm.Chunk) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stampLocked(ctx, c)
+}
+
+// stampLocked is stamp called with the mutex locked.
+func (p *pushStamperPutter) stampLocked(ctx context.Context, c swarm.Chunk) error {
+	stamp, err := p.stamper.Reserve(c.Address())
+	if err != nil {
+		return err
+	}
+	p.pending = append(p.pending, c)
+	p.stamps = append(p.stamps, stamp)
+
+	// the pending chunks are pushed with the context of the last one
+	p.ctx = ctx
+	if len(p.pending) >= stampBatchSize {
+		return p.flush()
+	}
+	return nil
+}
+
+// flush signs the stamps of the pending chunks and pushes them. The stamps
+// are released if they are not signed. It must be called with the mutex
+// locked.
+func (p *pushStamperPutter) flush() error {
+	if len(p.pending) == 0 {
+		return nil
+	}
+	addrs := make([]swarm.Address, len(p.pending))
+	for i, c := range p.pending {
+		addrs[i] = c.Address()
+	}
+	if err := p.stamper.Sign(addrs, p.stamps); err != nil {
+		p.stamper.Release(p.stamps)
+		p.pending, p.stamps = nil, nil
+		return err
+	}
+	// the stamped copies are pushed, as the chunks
+	// may be shared with their other readers
+	for i, c := range p.pending {
+		p.putChunk(p.ctx, swarm.NewChunk(c.Address(), c.Data()).WithStamp(p.stamps[i]))
+	}
+	p.pending, p.stamps = nil, nil
+	return nil
+}
+
+// Wait pushes the pending chunks and waits until all the chunks are pushed.
+func (p *pushStamperPutter) Wait() error {
+	p.mu.Lock()
+	err := p.flush()
+	p.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return p.pushPutter.Wait()
+}
+
+func (p *pushPutter) putChunk(ctx context.Context, ch swarm.Chunk) {
 	p.sem <- struct{}{}
 	p.eg.Go(func() error {
 		defer func() {
@@ -880,7 +1370,10 @@ func (p *pushStamperPutter) putChunk(ctx context.Context, ch swarm.Chunk) {
 
 		for {
 			errc := make(chan error, 1)
-			p.c <- &pusher.Op{Chunk: ch, Err: errc, Direct: true}
+			err := p.queues.push(ctx, ch.Stamp().BatchID(), &pusher.Op{Chunk: ch, Err: errc, Direct: true})
+			if err != nil {
+				return err
+			}
 
 			select {
 			case err := <-errc:
@@ -890,6 +1383,10 @@ func (p *pushStamperPutter) putChunk(ctx context.Context, ch swarm.Chunk) {
 					return err
 				}
 				if err == nil {
+					if p.cache {
+						_, err := p.Storer.Put(ctx, storage.ModePutRequestCache, ch)
+						return err
+					}
 					return nil
 				}
 
@@ -902,18 +1399,19 @@ func (p *pushStamperPutter) putChunk(ctx context.Context, ch swarm.Chunk) {
 
 type stamperPutter struct {
 	storage.Storer
-	stamper postage.Stamper
+	stamper postage.BatchStamper
 }
 
-func newStoringStamperPutter(s storage.Storer, i *postage.StampIssuer, signer crypto.Signer) *stamperPutter {
-	stamper := postage.NewStamper(i, signer)
+func newStoringStamperPutter(s storage.Storer, stamper postage.BatchStamper) *stamperPutter {
 	return &stamperPutter{Storer: s, stamper: stamper}
 }
 
 func (p *stamperPutter) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk) (exists []bool, err error) {
 	var (
-		ctp = make([]swarm.Chunk, 0, len(chs))
-		idx = make([]int, 0, len(chs))
+		ctp    = make([]swarm.Chunk, 0, len(chs))
+		idx    = make([]int, 0, len(chs))
+		addrs  = make([]swarm.Address, 0, len(chs))
+		stamps = make([]*postage.Stamp, 0, len(chs))
 	)
 	exists = make([]bool, len(chs))
 
@@ -926,15 +1424,26 @@ func (p *stamperPutter) Put(ctx context.Context, mode storage.ModePut, chs ...sw
 			exists[i] = true
 			continue
 		}
-		stamp, err := p.stamper.Stamp(c.Address())
+		stamp, err := p.stamper.Reserve(c.Address())
 		if err != nil {
+			p.stamper.Release(stamps)
 			return nil, err
 		}
-		chs[i] = c.WithStamp(stamp)
-		ctp = append(ctp, chs[i])
+		addrs = append(addrs, c.Address())
+		stamps = append(stamps, stamp)
 		idx = append(idx, i)
 	}
 
+	// the chunks put at once are signed together
+	if err := p.stamper.Sign(addrs, stamps); err != nil {
+		p.stamper.Release(stamps)
+		return nil, err
+	}
+	for j, i := range idx {
+		chs[i] = chs[i].WithStamp(stamps[j])
+		ctp = append(ctp, chs[i])
+	}
+
 	exists2, err := p.Storer.Put(ctx, mode, ctp...)
 	if err != nil {
 		return nil, err
@@ -945,6 +1454,34 @@ func (p *stamperPutter) Put(ctx context.Context, mode storage.ModePut, chs ...sw
 	return exists, nil
 }
 
+// localStore reads only the chunks which are in the local store, without
+// retrieving the missing chunks from the network.
+type localStore struct {
+	storage.Storer
+}
+
+func (l localStore) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	has, err := l.Storer.Has(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, storage.ErrNotFound
+	}
+	return l.Storer.Get(ctx, mode, addr)
+}
+
+// checkReadable checks that all the chunks of the uploaded content, with its
+// manifests, are in the local store, so that the content is servable by the
+// node without the retrieval from the network.
+func (s *Service) checkReadable(ctx context.Context, reference swarm.Address) error {
+	err := traversal.New(localStore{s.storer}).Traverse(ctx, reference, func(swarm.Address) error { return nil })
+	if err != nil {
+		return fmt.Errorf("%w: %v", errNotReadable, err)
+	}
+	return nil
+}
+
 type pipelineFunc func(context.Context, io.Reader) (swarm.Address, error)
 
 func requestPipelineFn(s storage.Putter, r *http.Request) pipelineFunc {
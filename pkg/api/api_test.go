@@ -18,6 +18,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -31,31 +32,53 @@ import (
 	"github.com/ethersphere/bee/pkg/util/testutil"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/accesscontrol"
 	accountingmock "github.com/ethersphere/bee/pkg/accounting/mock"
+	"github.com/ethersphere/bee/pkg/alias"
+	"github.com/ethersphere/bee/pkg/analytics"
 	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/auditlog"
 	"github.com/ethersphere/bee/pkg/auth"
 	mockauth "github.com/ethersphere/bee/pkg/auth/mock"
+	"github.com/ethersphere/bee/pkg/broadcast"
+	"github.com/ethersphere/bee/pkg/challenge"
 	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/deploy"
+	"github.com/ethersphere/bee/pkg/dnslink"
+	"github.com/ethersphere/bee/pkg/eventlog"
+	"github.com/ethersphere/bee/pkg/faults"
 	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/crdt"
 	"github.com/ethersphere/bee/pkg/file/pipeline"
 	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/ipfs"
+	"github.com/ethersphere/bee/pkg/jobs"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/maintenance"
+	"github.com/ethersphere/bee/pkg/membudget"
 	p2pmock "github.com/ethersphere/bee/pkg/p2p/mock"
 	"github.com/ethersphere/bee/pkg/pingpong"
 	"github.com/ethersphere/bee/pkg/pinning"
 	"github.com/ethersphere/bee/pkg/postage"
 	mockbatchstore "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
+	"github.com/ethersphere/bee/pkg/postage/events"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
 	"github.com/ethersphere/bee/pkg/postage/postagecontract"
 	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/ethersphere/bee/pkg/pss/session"
 	"github.com/ethersphere/bee/pkg/pusher"
+	"github.com/ethersphere/bee/pkg/replica"
 	"github.com/ethersphere/bee/pkg/resolver"
 	resolverMock "github.com/ethersphere/bee/pkg/resolver/mock"
+	"github.com/ethersphere/bee/pkg/retrieval"
 	"github.com/ethersphere/bee/pkg/settlement/pseudosettle"
 	chequebookmock "github.com/ethersphere/bee/pkg/settlement/swap/chequebook/mock"
 	erc20mock "github.com/ethersphere/bee/pkg/settlement/swap/erc20/mock"
 	swapmock "github.com/ethersphere/bee/pkg/settlement/swap/mock"
+	"github.com/ethersphere/bee/pkg/shaping"
+	"github.com/ethersphere/bee/pkg/spam"
 	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/steward"
 	"github.com/ethersphere/bee/pkg/storage"
@@ -64,12 +87,15 @@ import (
 	"github.com/ethersphere/bee/pkg/storageincentives/staking"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/topology/depthmonitor"
 	"github.com/ethersphere/bee/pkg/topology/lightnode"
 	topologymock "github.com/ethersphere/bee/pkg/topology/mock"
 	"github.com/ethersphere/bee/pkg/tracing"
 	"github.com/ethersphere/bee/pkg/transaction/backendmock"
 	transactionmock "github.com/ethersphere/bee/pkg/transaction/mock"
+	"github.com/ethersphere/bee/pkg/transform"
 	"github.com/ethersphere/bee/pkg/traversal"
+	"github.com/ethersphere/bee/pkg/warmer"
 	"github.com/gorilla/websocket"
 	"resenje.org/web"
 )
@@ -89,30 +115,63 @@ func init() {
 }
 
 type testServerOptions struct {
-	Storer             storage.Storer
-	StateStorer        storage.StateStorer
-	Resolver           resolver.Interface
-	Pss                pss.Interface
-	Traversal          traversal.Traverser
-	Pinning            pinning.Interface
-	WsPath             string
-	Tags               *tags.Tags
-	WsPingPeriod       time.Duration
-	Logger             log.Logger
-	PreventRedirect    bool
-	Feeds              feeds.Factory
-	CORSAllowedOrigins []string
-	PostageContract    postagecontract.Interface
-	StakingContract    staking.Contract
-	Post               postage.Service
-	Steward            steward.Interface
-	WsHeaders          http.Header
-	Authenticator      auth.Authenticator
-	DebugAPI           bool
-	Restricted         bool
-	DirectUpload       bool
-	Probe              *api.Probe
-	IndexDebugger      api.StorageIndexDebugger
+	Storer               storage.Storer
+	StateStorer          storage.StateStorer
+	Resolver             resolver.Interface
+	Pss                  pss.Interface
+	PssSessions          *session.Service
+	Broadcast            *broadcast.Service
+	Traversal            traversal.Traverser
+	Pinning              pinning.Interface
+	PinExpiry            pinning.ExpirySubscriber
+	BatchEvents          events.Subscriber
+	AuditLog             *auditlog.Logger
+	WsPath               string
+	Tags                 *tags.Tags
+	WsPingPeriod         time.Duration
+	Logger               log.Logger
+	PreventRedirect      bool
+	Feeds                feeds.Factory
+	CORSAllowedOrigins   []string
+	PostageContract      postagecontract.Interface
+	StakingContract      staking.Contract
+	Post                 postage.Service
+	Steward              steward.Interface
+	Warmer               *warmer.Service
+	Deploy               *deploy.Service
+	Crdt                 *crdt.Service
+	Aliases              *alias.Registry
+	Analytics            *analytics.Tracker
+	Transform            *transform.Service
+	IPFS                 *ipfs.Gateway
+	Challenge            *challenge.Service
+	Shaping              *shaping.Scheduler
+	DownloadLimiter      *api.DownloadLimiter
+	WsHeaders            http.Header
+	Authenticator        auth.Authenticator
+	DebugAPI             bool
+	Restricted           bool
+	GraphQL              bool
+	DirListing           bool
+	GatewayDomain        string
+	GatewaySubdomainOnly bool
+	TrustedProxies       []*net.IPNet
+	CheckExistingMaxSize int64
+	Compression          *api.CompressionPolicy
+	DirectUpload         bool
+	Probe                *api.Probe
+	FaultInjector        *faults.Injector
+	IndexDebugger        api.StorageIndexDebugger
+	ReserveEvicter       api.ReserveEvicter
+	Spam                 *spam.Detector
+	DepthMonitor         *depthmonitor.Service
+	Replica              *replica.Replicator
+	MemoryBudget         *membudget.Budget
+	Maintenance          *maintenance.Scheduler
+	Jobs                 *jobs.Manager
+	Events               *eventlog.Log
+	DNSLink              *dnslink.Hook
+	AccessControl        *accesscontrol.Controller
 
 	Overlay         swarm.Address
 	PublicKey       ecdsa.PublicKey
@@ -121,6 +180,7 @@ type testServerOptions struct {
 	BlockTime       time.Duration
 	P2P             *p2pmock.Service
 	Pingpong        pingpong.Interface
+	PeerRetriever   retrieval.PeerRetriever
 	TopologyOpts    []topologymock.Option
 	AccountingOpts  []accountingmock.Option
 	ChequebookOpts  []chequebookmock.Option
@@ -195,20 +255,47 @@ func newTestServer(t *testing.T, o testServerOptions) (*http.Client, *websocket.
 		Swap:             settlement,
 		Chequebook:       chequebook,
 		Pingpong:         o.Pingpong,
+		PeerRetriever:    o.PeerRetriever,
 		BlockTime:        o.BlockTime,
 		Tags:             o.Tags,
 		Storer:           o.Storer,
 		Resolver:         o.Resolver,
 		Pss:              o.Pss,
+		PssSessions:      o.PssSessions,
+		Broadcast:        o.Broadcast,
 		TraversalService: o.Traversal,
 		Pinning:          o.Pinning,
+		StateStore:       o.StateStorer,
+		AuditLog:         o.AuditLog,
+		PinExpiry:        o.PinExpiry,
+		BatchEvents:      o.BatchEvents,
 		FeedFactory:      o.Feeds,
 		Post:             o.Post,
 		PostageContract:  o.PostageContract,
 		Steward:          o.Steward,
+		Warmer:           o.Warmer,
+		Deploy:           o.Deploy,
+		Crdt:             o.Crdt,
+		Aliases:          o.Aliases,
+		Analytics:        o.Analytics,
+		Transform:        o.Transform,
+		IPFS:             o.IPFS,
+		Challenge:        o.Challenge,
+		Shaping:          o.Shaping,
+		DownloadLimiter:  o.DownloadLimiter,
 		SyncStatus:       o.SyncStatus,
 		Staking:          o.StakingContract,
 		IndexDebugger:    o.IndexDebugger,
+		ReserveEvicter:   o.ReserveEvicter,
+		Spam:             o.Spam,
+		DepthMonitor:     o.DepthMonitor,
+		Replica:          o.Replica,
+		MemoryBudget:     o.MemoryBudget,
+		Maintenance:      o.Maintenance,
+		Jobs:             o.Jobs,
+		Events:           o.Events,
+		DNSLink:          o.DNSLink,
+		AccessControl:    o.AccessControl,
 		NodeStatus:       o.NodeStatus,
 	}
 
@@ -230,6 +317,9 @@ func newTestServer(t *testing.T, o testServerOptions) (*http.Client, *websocket.
 
 	s.SetSwarmAddress(&o.Overlay)
 	s.SetProbe(o.Probe)
+	if o.FaultInjector != nil {
+		s.SetFaultInjector(o.FaultInjector)
+	}
 
 	noOpTracer, tracerCloser, _ := tracing.NewTracer(&tracing.Options{
 		Enabled: false,
@@ -237,9 +327,16 @@ func newTestServer(t *testing.T, o testServerOptions) (*http.Client, *websocket.
 	testutil.CleanupCloser(t, tracerCloser)
 
 	chC := s.Configure(signer, o.Authenticator, noOpTracer, api.Options{
-		CORSAllowedOrigins: o.CORSAllowedOrigins,
-		WsPingPeriod:       o.WsPingPeriod,
-		Restricted:         o.Restricted,
+		CORSAllowedOrigins:   o.CORSAllowedOrigins,
+		WsPingPeriod:         o.WsPingPeriod,
+		Restricted:           o.Restricted,
+		Compression:          o.Compression,
+		GraphQL:              o.GraphQL,
+		DirListing:           o.DirListing,
+		GatewayDomain:        o.GatewayDomain,
+		GatewaySubdomainOnly: o.GatewaySubdomainOnly,
+		TrustedProxies:       o.TrustedProxies,
+		CheckExistingMaxSize: o.CheckExistingMaxSize,
 	}, extraOpts, 1, erc20)
 
 	if o.DebugAPI {
@@ -410,7 +507,7 @@ func TestParseName(t *testing.T) {
 		t.Run(tC.desc, func(t *testing.T) {
 			t.Parallel()
 
-			got, err := s.ResolveNameOrAddress(tC.name)
+			got, err := s.ResolveNameOrAddress(context.Background(), tC.name)
 			if tC.wantErr != nil && !errors.Is(err, tC.wantErr) {
 				t.Fatalf("bad error: %v", err)
 			}
@@ -573,6 +670,161 @@ func TestOptions(t *testing.T) {
 	}
 }
 
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{})
+
+	for _, tc := range []struct {
+		name      string
+		requestID string
+		want      string
+	}{
+		{
+			name:      "echoed",
+			requestID: "a3c9f1e2-7b4d-4e8a-9c6f-1d2e3f4a5b6c",
+			want:      "a3c9f1e2-7b4d-4e8a-9c6f-1d2e3f4a5b6c",
+		},
+		{
+			name:      "too long",
+			requestID: strings.Repeat("a", 129),
+		},
+		{
+			name:      "invalid characters",
+			requestID: "request id",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := jsonhttptest.Request(t, client, http.MethodGet, "/health", http.StatusOK,
+				jsonhttptest.WithRequestHeader(tracing.RequestIDHeaderName, tc.requestID),
+			)
+
+			if got := header.Get(tracing.RequestIDHeaderName); got != tc.want {
+				t.Fatalf("got request id %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// modeRecordingStorer records the retrieval mode of the chunk requests.
+type modeRecordingStorer struct {
+	storage.Storer
+	modes chan retrieval.Mode
+}
+
+func (s *modeRecordingStorer) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	s.modes <- retrieval.ModeFromContext(ctx)
+	return s.Storer.Get(ctx, mode, addr)
+}
+
+func TestRetrievalModeHeader(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		header string
+		want   retrieval.Mode
+	}{
+		{header: "", want: retrieval.ModeDefault},
+		{header: "privacy", want: retrieval.ModePrivacy},
+		{header: "performance", want: retrieval.ModePerformance},
+	} {
+		tc := tc
+		t.Run(tc.want.String(), func(t *testing.T) {
+			t.Parallel()
+
+			storer := &modeRecordingStorer{Storer: mock.NewStorer(), modes: make(chan retrieval.Mode, 1)}
+			client, _, _, _ := newTestServer(t, testServerOptions{Storer: storer})
+
+			var opts []jsonhttptest.Option
+			if tc.header != "" {
+				opts = append(opts, jsonhttptest.WithRequestHeader(api.SwarmRetrievalModeHeader, tc.header))
+			}
+			jsonhttptest.Request(t, client, http.MethodGet, "/chunks/"+swarm.RandAddress(t).String(), http.StatusNotFound, opts...)
+
+			if got := <-storer.modes; got != tc.want {
+				t.Fatalf("got retrieval mode %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{})
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/chunks/"+swarm.RandAddress(t).String(), http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmRetrievalModeHeader, "fast"),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusBadRequest,
+				Message: "invalid retrieval mode",
+			}),
+		)
+	})
+}
+
+// priorityRecordingStorer records the retrieval priority of the chunk requests.
+type priorityRecordingStorer struct {
+	storage.Storer
+	priorities chan retrieval.Priority
+}
+
+func (s *priorityRecordingStorer) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	s.priorities <- retrieval.PriorityFromContext(ctx)
+	return s.Storer.Get(ctx, mode, addr)
+}
+
+func TestRetrievalPriorityHeader(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		header string
+		want   retrieval.Priority
+	}{
+		{header: "", want: retrieval.PriorityInteractive},
+		{header: "background", want: retrieval.PriorityBackground},
+	} {
+		tc := tc
+		t.Run(tc.want.String(), func(t *testing.T) {
+			t.Parallel()
+
+			storer := &priorityRecordingStorer{Storer: mock.NewStorer(), priorities: make(chan retrieval.Priority, 1)}
+			client, _, _, _ := newTestServer(t, testServerOptions{Storer: storer})
+
+			var opts []jsonhttptest.Option
+			if tc.header != "" {
+				opts = append(opts, jsonhttptest.WithRequestHeader(api.SwarmRetrievalPriorityHeader, tc.header))
+			}
+			jsonhttptest.Request(t, client, http.MethodGet, "/chunks/"+swarm.RandAddress(t).String(), http.StatusNotFound, opts...)
+
+			if got := <-storer.priorities; got != tc.want {
+				t.Fatalf("got retrieval priority %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{})
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/chunks/"+swarm.RandAddress(t).String(), http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmRetrievalPriorityHeader, "urgent"),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusBadRequest,
+				Message: "invalid retrieval priority",
+			}),
+		)
+
+		// the header is not validated on the routes which do not download
+		jsonhttptest.Request(t, client, http.MethodGet, "/", http.StatusOK,
+			jsonhttptest.WithRequestHeader(api.SwarmRetrievalPriorityHeader, "urgent"),
+		)
+	})
+}
+
 // TestPostageDirectAndDeferred_FLAKY tests that incorrect postage batch ids
 // provided to the api correct the appropriate error code.
 func TestPostageDirectAndDeferred_FLAKY(t *testing.T) {
@@ -657,6 +909,87 @@ func TestPostageDirectAndDeferred_FLAKY(t *testing.T) {
 	}
 }
 
+// TestReadYourWrites tests that the direct uploads with the read your writes
+// header are servable by the node as soon as the upload response is returned.
+func TestReadYourWrites(t *testing.T) {
+	t.Parallel()
+
+	storer := mock.NewStorer()
+	client, _, _, chanStorer := newTestServer(t, testServerOptions{
+		Storer:       storer,
+		Tags:         tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger:       log.Noop,
+		Post:         mockpost.New(mockpost.WithAcceptAll()),
+		DirectUpload: true,
+	})
+
+	content := []byte("<h1>read your writes</h1>")
+	var upload api.BzzUploadResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=index.html", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "false"),
+		jsonhttptest.WithRequestHeader(api.SwarmReadYourWritesHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.ContentTypeHeader, "text/html"),
+		jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+		jsonhttptest.WithUnmarshalJSONResponse(&upload),
+	)
+
+	if found, _ := chanStorer.Has(context.Background(), upload.Reference); !found {
+		t.Fatal("manifest not received through the direct channel")
+	}
+	if found, _ := storer.Has(context.Background(), upload.Reference); !found {
+		t.Fatal("manifest not found in the store")
+	}
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+upload.Reference.String()+"/", http.StatusOK,
+		jsonhttptest.WithExpectedResponse(content),
+	)
+}
+
+func TestMemoryBudget(t *testing.T) {
+	t.Parallel()
+
+	budget := membudget.New(1 << 20)
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:       mock.NewStorer(),
+		Tags:         tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger:       log.Noop,
+		Post:         mockpost.New(mockpost.WithAcceptAll()),
+		MemoryBudget: budget,
+	})
+
+	content := bytes.Repeat([]byte("memory budget"), 10000)
+	var upload api.BytesPostResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+		jsonhttptest.WithUnmarshalJSONResponse(&upload),
+	)
+	jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+upload.Reference.String(), http.StatusOK,
+		jsonhttptest.WithExpectedResponse(content),
+	)
+	if used := budget.Used(); used != 0 {
+		t.Fatalf("got used memory %d, want 0", used)
+	}
+
+	// the uploads wait while the budget is exhausted
+	if err := budget.Acquire(context.Background(), budget.Limit()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/bytes", bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(api.SwarmPostageBatchIdHeader, batchOkStr)
+	if resp, err := client.Do(req); err == nil {
+		resp.Body.Close()
+		t.Fatalf("got response %s, want the request waiting for the memory", resp.Status)
+	}
+	budget.Release(budget.Limit())
+}
+
 type chanStorer struct {
 	lock   sync.Mutex
 	chunks map[string]struct{}
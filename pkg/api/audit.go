@@ -0,0 +1,208 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/auditlog"
+	"github.com/gorilla/mux"
+)
+
+// auditBodyLimit is the size of the beginning of the created responses
+// which is kept to look up the reference of the uploaded content.
+const auditBodyLimit = 512
+
+var errInvalidTrustedProxy = errors.New("invalid trusted proxy")
+
+// ParseTrustedProxies parses the IP addresses or the CIDR ranges of the
+// reverse proxies whose X-Forwarded-For header is trusted.
+func ParseTrustedProxies(addrs []string) ([]*net.IPNet, error) {
+	proxies := make([]*net.IPNet, 0, len(addrs))
+	for _, v := range addrs {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if _, ipNet, err := net.ParseCIDR(v); err == nil {
+			proxies = append(proxies, ipNet)
+			continue
+		}
+		ip := net.ParseIP(v)
+		if ip == nil {
+			return nil, fmt.Errorf("%w: %q", errInvalidTrustedProxy, v)
+		}
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return proxies, nil
+}
+
+// auditHandler records the API operations to the audit log. It is
+// a router middleware, so that the path variables are available.
+func (s *Service) auditHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		body := &countingReadCloser{ReadCloser: r.Body}
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = body
+		}
+		rec := &auditRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		h.ServeHTTP(rec, r)
+
+		route := r.URL.Path
+		if cr := mux.CurrentRoute(r); cr != nil {
+			if tpl, err := cr.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+
+		vars := mux.Vars(r)
+		reference := vars["address"]
+		if reference == "" {
+			reference = vars["reference"]
+		}
+		if reference == "" && rec.status == http.StatusCreated {
+			reference = rec.reference()
+		}
+		batchID := r.Header.Get(SwarmPostageBatchIdHeader)
+		if batchID == "" {
+			batchID = vars["batch_id"]
+		}
+
+		s.auditLog.Log(auditlog.Record{
+			Time:      start,
+			Caller:    s.auditCaller(r),
+			Method:    r.Method,
+			Route:     route,
+			Reference: reference,
+			BatchID:   batchID,
+			Status:    rec.status,
+			BytesIn:   body.n,
+			BytesOut:  rec.size,
+			Duration:  time.Since(start).Seconds(),
+		})
+	})
+}
+
+// auditCaller returns the IP address of the client. The X-Forwarded-For
+// header is honoured only if the request comes from a trusted proxy, and
+// its addresses are walked from the right, skipping the trusted proxies,
+// so that the client can not forge the address that is recorded.
+func (s *Service) auditCaller(r *http.Request) string {
+	caller, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		caller = r.RemoteAddr
+	}
+	if !s.trustedProxy(caller) {
+		return caller
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		caller = hop
+		if !s.trustedProxy(hop) {
+			break
+		}
+	}
+	return caller
+}
+
+// trustedProxy reports whether the address is one of the trusted proxies.
+func (s *Service) trustedProxy(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range s.TrustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+type countingReadCloser struct {
+	io.ReadCloser
+	n int64
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// auditRecorder records the status code and the size of the response
+// and keeps the beginning of the response body.
+type auditRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	size        int64
+	head        []byte
+}
+
+func (ar *auditRecorder) WriteHeader(code int) {
+	if ar.wroteHeader {
+		return
+	}
+	ar.status = code
+	ar.wroteHeader = true
+	ar.ResponseWriter.WriteHeader(code)
+}
+
+func (ar *auditRecorder) Write(b []byte) (int, error) {
+	ar.wroteHeader = true
+	if n := auditBodyLimit - len(ar.head); n > 0 {
+		if n > len(b) {
+			n = len(b)
+		}
+		ar.head = append(ar.head, b[:n]...)
+	}
+	n, err := ar.ResponseWriter.Write(b)
+	ar.size += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher.
+func (ar *auditRecorder) Flush() {
+	if f, ok := ar.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker, which is needed by the websockets.
+func (ar *auditRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	ar.status = http.StatusSwitchingProtocols
+	return ar.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+// reference returns the reference from the JSON response of an upload.
+func (ar *auditRecorder) reference() string {
+	var res struct {
+		Reference string `json:"reference"`
+	}
+	if err := json.Unmarshal(ar.head, &res); err != nil {
+		return ""
+	}
+	return res.Reference
+}
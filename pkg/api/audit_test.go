@@ -0,0 +1,191 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/analytics"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/auditlog"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+type auditSink struct {
+	mtx     sync.Mutex
+	records []auditlog.Record
+}
+
+func (s *auditSink) Write(r auditlog.Record) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.records = append(s.records, r)
+	return nil
+}
+
+func (s *auditSink) Close() error { return nil }
+
+func TestAuditLog(t *testing.T) {
+	t.Parallel()
+
+	sink := new(auditSink)
+	auditLog, err := auditlog.New(sink, auditlog.Options{}, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trustedProxies, err := api.ParseTrustedProxies([]string{"127.0.0.0/8", "10.0.0.2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:         mock.NewStorer(),
+		Tags:           tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Post:           mockpost.New(mockpost.WithAcceptAll()),
+		AuditLog:       auditLog,
+		TrustedProxies: trustedProxies,
+	})
+
+	const content = "hello audit"
+	var res api.BytesPostResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader("X-Forwarded-For", "10.0.0.1, 10.0.0.2"),
+		jsonhttptest.WithRequestBody(strings.NewReader(content)),
+		jsonhttptest.WithUnmarshalJSONResponse(&res),
+	)
+	jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+res.Reference.String(), http.StatusOK)
+
+	if err := auditLog.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sink.records) != 2 {
+		t.Fatalf("got %d records, want 2", len(sink.records))
+	}
+	upload, download := sink.records[0], sink.records[1]
+	if upload.Route != "/bytes" || upload.Method != http.MethodPost || upload.Status != http.StatusCreated {
+		t.Fatalf("got upload record %+v", upload)
+	}
+	if upload.Caller != "10.0.0.1" {
+		t.Fatalf("got caller %s, want 10.0.0.1", upload.Caller)
+	}
+	if upload.Reference != res.Reference.String() || upload.BatchID != batchOkStr {
+		t.Fatalf("got reference %s and batch %s, want %s and %s", upload.Reference, upload.BatchID, res.Reference, batchOkStr)
+	}
+	if upload.BytesIn != int64(len(content)) {
+		t.Fatalf("got %d bytes in, want %d", upload.BytesIn, len(content))
+	}
+	if download.Route != "/bytes/{address}" || download.Reference != res.Reference.String() || download.BytesOut != int64(len(content)) {
+		t.Fatalf("got download record %+v", download)
+	}
+}
+
+// TestAuditLogUntrustedProxy tests that the X-Forwarded-For header
+// is ignored if the request does not come from a trusted proxy.
+func TestAuditLogUntrustedProxy(t *testing.T) {
+	t.Parallel()
+
+	sink := new(auditSink)
+	auditLog, err := auditlog.New(sink, auditlog.Options{}, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trustedProxies, err := api.ParseTrustedProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:         mock.NewStorer(),
+		Tags:           tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Post:           mockpost.New(mockpost.WithAcceptAll()),
+		AuditLog:       auditLog,
+		TrustedProxies: trustedProxies,
+	})
+
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader("X-Forwarded-For", "10.0.0.1"),
+		jsonhttptest.WithRequestBody(strings.NewReader("hello audit")),
+	)
+
+	if err := auditLog.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sink.records) != 1 {
+		t.Fatalf("got %d records, want 1", len(sink.records))
+	}
+	if caller := sink.records[0].Caller; caller != "127.0.0.1" {
+		t.Fatalf("got caller %s, want 127.0.0.1", caller)
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	proxies, err := api.ParseTrustedProxies([]string{"10.0.0.0/8", " 192.168.1.1", "::1", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proxies) != 3 {
+		t.Fatalf("got %d proxies, want 3", len(proxies))
+	}
+	if got := proxies[1].String(); got != "192.168.1.1/32" {
+		t.Fatalf("got proxy %s, want 192.168.1.1/32", got)
+	}
+	if got := proxies[2].String(); got != "::1/128" {
+		t.Fatalf("got proxy %s, want ::1/128", got)
+	}
+
+	if _, err := api.ParseTrustedProxies([]string{"proxy"}); !errors.Is(err, api.ErrInvalidTrustedProxy) {
+		t.Fatalf("got error %v, want %v", err, api.ErrInvalidTrustedProxy)
+	}
+}
+
+// TestAuditLogAnalytics tests the downloads recorded by both the
+// audit log and the analytics, which wrap the audit log writer.
+func TestAuditLogAnalytics(t *testing.T) {
+	t.Parallel()
+
+	auditLog, err := auditlog.New(new(auditSink), auditlog.Options{}, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = auditLog.Close() })
+	tracker := analytics.New(0)
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:    mock.NewStorer(),
+		Tags:      tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Post:      mockpost.New(mockpost.WithAcceptAll()),
+		AuditLog:  auditLog,
+		Analytics: tracker,
+	})
+
+	const content = "hello audit"
+	var res api.BytesPostResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(strings.NewReader(content)),
+		jsonhttptest.WithUnmarshalJSONResponse(&res),
+	)
+	jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+res.Reference.String(), http.StatusOK,
+		jsonhttptest.WithExpectedResponse([]byte(content)),
+	)
+
+	if stats := tracker.Stats(); len(stats) != 1 {
+		t.Fatalf("got %d recorded references, want 1", len(stats))
+	}
+}
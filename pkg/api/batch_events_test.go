@@ -0,0 +1,70 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage/events"
+)
+
+type batchEventSubscriber chan events.Event
+
+func (s batchEventSubscriber) SubscribeBatchEvents() (<-chan events.Event, func()) {
+	return s, func() {}
+}
+
+func TestBatchEventsWebsocket(t *testing.T) {
+	t.Parallel()
+
+	t.Run("events", func(t *testing.T) {
+		t.Parallel()
+
+		subscriber := make(batchEventSubscriber, 1)
+		_, cl, _, _ := newTestServer(t, testServerOptions{
+			BatchEvents:  subscriber,
+			DebugAPI:     true,
+			WsPath:       "/stamps/events/subscribe",
+			Logger:       log.Noop,
+			WsPingPeriod: 10 * time.Second,
+		})
+
+		want := events.Event{
+			Type:      events.TypeDiluted,
+			BatchID:   batchOkStr,
+			Depth:     21,
+			Timestamp: time.Now().Unix(),
+		}
+		subscriber <- want
+
+		if err := cl.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		var got events.Event
+		if err := cl.ReadJSON(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("got event %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("not available", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{DebugAPI: true})
+		jsonhttptest.Request(t, client, http.MethodGet, "/stamps/events/subscribe", http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "batch event notifications are not available",
+				Code:    http.StatusNotImplemented,
+			}),
+		)
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/pusher"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// batchQueues share the channel of the chunks pushed to the network by the
+// uploads fairly between the postage batches. At most one chunk of a batch
+// waits on the channel at once, so that the pusher takes the chunks of the
+// batches in turns and the uploads of a batch which push many chunks do
+// not delay the uploads of the other batches. The stamps are issued per
+// batch already, so the pushes are the only stage shared by the batches.
+type batchQueues struct {
+	c chan *pusher.Op
+
+	mu     sync.Mutex
+	queues map[string]*batchQueue
+}
+
+// batchQueue is the turn of the chunks of a batch to be pushed.
+type batchQueue struct {
+	turn  chan struct{}
+	users int // the number of the chunks waiting for the turn
+}
+
+func newBatchQueues(c chan *pusher.Op) *batchQueues {
+	return &batchQueues{
+		c:      c,
+		queues: make(map[string]*batchQueue),
+	}
+}
+
+// push sends the op of the chunk of the batch to the pusher once it is the
+// turn of the batch.
+func (q *batchQueues) push(ctx context.Context, batchID []byte, op *pusher.Op) error {
+	bq := q.acquire(batchID)
+	defer q.release(batchID, bq)
+
+	select {
+	case bq.turn <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-bq.turn }()
+
+	select {
+	case q.c <- op:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *batchQueues) acquire(batchID []byte) *batchQueue {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	bq, ok := q.queues[string(batchID)]
+	if !ok {
+		bq = &batchQueue{turn: make(chan struct{}, 1)}
+		q.queues[string(batchID)] = bq
+	}
+	bq.users++
+	return bq
+}
+
+// release removes the queue of the batch once no chunk waits for its turn.
+func (q *batchQueues) release(batchID []byte, bq *batchQueue) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	bq.users--
+	if bq.users == 0 {
+		delete(q.queues, string(batchID))
+	}
+}
+
+// len returns the number of the batches with the chunks waiting to be pushed.
+func (q *batchQueues) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.queues)
+}
+
+// meteredStamper records the stamp throughput of its batch.
+type meteredStamper struct {
+	postage.BatchStamper
+	batchID string
+	metrics *metrics
+}
+
+// newStamper returns the stamper of the uploads to the batch of the issuer.
+func (s *Service) newStamper(issuer *postage.StampIssuer) postage.BatchStamper {
+	return &meteredStamper{
+		BatchStamper: postage.NewStamper(issuer, s.signer).(postage.BatchStamper),
+		batchID:      hex.EncodeToString(issuer.ID()),
+		metrics:      &s.metrics,
+	}
+}
+
+func (st *meteredStamper) Stamp(addr swarm.Address) (*postage.Stamp, error) {
+	start := time.Now()
+	stamp, err := st.BatchStamper.Stamp(addr)
+	st.record(err, 1, time.Since(start))
+	return stamp, err
+}
+
+func (st *meteredStamper) Reserve(addr swarm.Address) (*postage.Stamp, error) {
+	stamp, err := st.BatchStamper.Reserve(addr)
+	if err != nil {
+		st.record(err, 1, 0)
+	}
+	return stamp, err
+}
+
+func (st *meteredStamper) Sign(addrs []swarm.Address, stamps []*postage.Stamp) error {
+	start := time.Now()
+	err := st.BatchStamper.Sign(addrs, stamps)
+	st.record(err, len(addrs), time.Since(start))
+	return err
+}
+
+// record records the result of the stamping of n chunks which took d.
+func (st *meteredStamper) record(err error, n int, d time.Duration) {
+	switch {
+	case errors.Is(err, postage.ErrBucketFull):
+		st.metrics.BatchBucketFull.WithLabelValues(st.batchID).Inc()
+	case err == nil && n > 0:
+		st.metrics.BatchStampedChunks.WithLabelValues(st.batchID).Add(float64(n))
+		perChunk := d.Seconds() / float64(n)
+		for i := 0; i < n; i++ {
+			st.metrics.BatchStampDuration.WithLabelValues(st.batchID).Observe(perChunk)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/postage"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+	"github.com/ethersphere/bee/pkg/pusher"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	testingc "github.com/ethersphere/bee/pkg/storage/testing"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestBatchQueues(t *testing.T) {
+	t.Parallel()
+
+	var (
+		c           = make(chan *pusher.Op)
+		q           = api.NewBatchQueues(c)
+		storer      = mock.NewStorer()
+		batchA      = postagetesting.MustNewID()
+		batchB      = postagetesting.MustNewID()
+		putA, waitA = api.NewPushPutter(storer, q)
+		putB, waitB = api.NewPushPutter(storer, q)
+	)
+
+	put := func(p storage.Putter, batch []byte, n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			ch := testingc.GenerateTestRandomChunk().WithStamp(postagetesting.MustNewBatchStamp(batch))
+			if _, err := p.Put(context.Background(), storage.ModePutUpload, ch); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	receive := func() *pusher.Op {
+		t.Helper()
+		select {
+		case op := <-c:
+			return op
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the pushed chunk")
+		}
+		return nil
+	}
+
+	// the upload to the batch A pushes many chunks, which wait on the pusher
+	put(putA, batchA, 10)
+	time.Sleep(50 * time.Millisecond)
+	put(putB, batchB, 1)
+	time.Sleep(50 * time.Millisecond)
+
+	// the chunk of the batch B is pushed in its turn, before the other
+	// chunks of the batch A
+	var (
+		pushedA int
+		pushedB bool
+	)
+	for i := 0; i < 2 && !pushedB; i++ {
+		op := receive()
+		if bytes.Equal(op.Chunk.Stamp().BatchID(), batchB) {
+			pushedB = true
+		} else {
+			pushedA++
+		}
+		op.Err <- nil
+	}
+	if !pushedB {
+		t.Fatal("chunk of the batch B waited for the chunks of the batch A")
+	}
+	if err := waitB(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the queues of the batches are removed once their chunks are pushed
+	for ; pushedA < 10; pushedA++ {
+		receive().Err <- nil
+	}
+	if err := waitA(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; q.Len() != 0; i++ {
+		if i == 100 {
+			t.Fatalf("got %d queues, want none", q.Len())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// failingSigner fails to sign the reserved stamps.
+type failingSigner struct {
+	postage.BatchStamper
+}
+
+func (failingSigner) Sign([]swarm.Address, []*postage.Stamp) error {
+	return errors.New("sign failed")
+}
+
+func TestPushStamperPutter(t *testing.T) {
+	t.Parallel()
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	batch := postagetesting.MustNewBatch()
+	newIssuer := func() *postage.StampIssuer {
+		return postage.NewStampIssuer("label", "keyID", batch.ID, batch.Value, batch.Depth, batch.BucketDepth, 1000, true)
+	}
+
+	t.Run("pushed on put", func(t *testing.T) {
+		t.Parallel()
+
+		c := make(chan *pusher.Op)
+		stamper := postage.NewStamper(newIssuer(), signer).(postage.BatchStamper)
+		putter, wait := api.NewPushStamperPutter(mock.NewStorer(), stamper, api.NewBatchQueues(c))
+
+		ch := testingc.GenerateTestRandomChunk()
+		if _, err := putter.Put(context.Background(), storage.ModePutUpload, ch); err != nil {
+			t.Fatal(err)
+		}
+
+		// the chunk is pushed once it is put, before the upload is waited on
+		select {
+		case op := <-c:
+			if !op.Chunk.Address().Equal(ch.Address()) {
+				t.Fatalf("got chunk %s, want %s", op.Chunk.Address(), ch.Address())
+			}
+			op.Err <- nil
+		case <-time.After(time.Second):
+			t.Fatal("chunk not pushed")
+		}
+		if err := wait(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("released on failed sign", func(t *testing.T) {
+		t.Parallel()
+
+		issuer := newIssuer()
+		stamper := failingSigner{postage.NewStamper(issuer, signer).(postage.BatchStamper)}
+		putter, _ := api.NewPushStamperPutter(mock.NewStorer(), stamper, api.NewBatchQueues(make(chan *pusher.Op)))
+
+		if _, err := putter.Put(context.Background(), storage.ModePutUpload, testingc.GenerateTestRandomChunks(3)...); err == nil {
+			t.Fatal("expected error")
+		}
+		for i, n := range issuer.Buckets() {
+			if n != 0 {
+				t.Fatalf("got %d stamps issued from bucket %d, want none", n, i)
+			}
+		}
+	})
+}
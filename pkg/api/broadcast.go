@@ -0,0 +1,128 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/broadcast"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+type broadcastResponse struct {
+	ID    string `json:"id"`
+	Peers int    `json:"peers"`
+}
+
+// broadcastPostHandler sends the request body to the neighbors
+// of the node, which forward it up to the ttl number of hops.
+func (s *Service) broadcastPostHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_broadcast").Build()
+
+	if s.broadcast == nil {
+		jsonhttp.NotImplemented(w, "broadcast is not available")
+		return
+	}
+
+	paths := struct {
+		Topic string `map:"topic" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	queries := struct {
+		TTL *uint8 `map:"ttl"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+	ttl := uint8(broadcast.DefaultTTL)
+	if queries.TTL != nil {
+		ttl = *queries.TTL
+	}
+
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		if jsonhttp.HandleBodyReadError(err, w) {
+			return
+		}
+		logger.Debug("read body failed", "error", err)
+		logger.Error(nil, "read body failed")
+		jsonhttp.InternalServerError(w, "broadcast failed")
+		return
+	}
+
+	id, peers, err := s.broadcast.Broadcast(r.Context(), broadcast.NewTopic(paths.Topic), payload, ttl)
+	if err != nil {
+		logger.Debug("broadcast failed", "topic", paths.Topic, "error", err)
+		logger.Error(nil, "broadcast failed")
+		switch {
+		case errors.Is(err, broadcast.ErrPayloadTooLarge):
+			jsonhttp.RequestEntityTooLarge(w, "payload too large")
+		case errors.Is(err, broadcast.ErrInvalidTTL):
+			jsonhttp.BadRequest(w, "invalid ttl")
+		case errors.Is(err, broadcast.ErrNoNeighbors):
+			jsonhttp.ServiceUnavailable(w, "no connected neighbors")
+		default:
+			jsonhttp.InternalServerError(w, "broadcast failed")
+		}
+		return
+	}
+
+	jsonhttp.Created(w, broadcastResponse{
+		ID:    id.String(),
+		Peers: peers,
+	})
+}
+
+// broadcastWsHandler writes the payloads of the messages of
+// the topic received from the neighborhood to the websocket.
+func (s *Service) broadcastWsHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("broadcast_subscribe").Build()
+
+	if s.broadcast == nil {
+		jsonhttp.NotImplemented(w, "broadcast is not available")
+		return
+	}
+
+	paths := struct {
+		Topic string `map:"topic" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  swarm.ChunkSize,
+		WriteBufferSize: swarm.ChunkSize,
+		CheckOrigin:     s.checkOrigin,
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Debug("upgrade failed", "error", err)
+		logger.Error(nil, "upgrade failed")
+		jsonhttp.InternalServerError(w, "upgrade failed")
+		return
+	}
+
+	topic := broadcast.NewTopic(paths.Topic)
+	s.wsWg.Add(1)
+	go s.pumpWs(conn, func(h func(context.Context, []byte)) func() {
+		return s.broadcast.Register(topic, func(ctx context.Context, m broadcast.Message) {
+			h(ctx, m.Payload)
+		})
+	})
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/broadcast"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/streamtest"
+	"github.com/ethersphere/bee/pkg/swarm"
+	topologymock "github.com/ethersphere/bee/pkg/topology/mock"
+)
+
+func TestBroadcast(t *testing.T) {
+	t.Parallel()
+
+	var (
+		topic       = "announcements"
+		nodeAddr    = swarm.RandAddress(t)
+		peerAddr    = swarm.RandAddress(t)
+		nodeStreams = make(map[string]p2p.ProtocolSpec)
+		peerStreams = make(map[string]p2p.ProtocolSpec)
+	)
+
+	newService := func(t *testing.T, addr swarm.Address, streams map[string]p2p.ProtocolSpec, peers ...swarm.Address) *broadcast.Service {
+		t.Helper()
+
+		recorder := streamtest.New(streamtest.WithBaseAddr(addr), streamtest.WithPeerProtocols(streams))
+		svc, err := broadcast.New(recorder, topologymock.NewTopologyDriver(topologymock.WithPeers(peers...)), addr, log.Noop)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = svc.Close() })
+		return svc
+	}
+
+	node := newService(t, nodeAddr, nodeStreams, peerAddr)
+	peer := newService(t, peerAddr, peerStreams, nodeAddr)
+	nodeStreams[peerAddr.String()] = peer.Protocol()
+	peerStreams[nodeAddr.String()] = node.Protocol()
+
+	client, conn, _, _ := newTestServer(t, testServerOptions{
+		Logger:       log.Noop,
+		Broadcast:    node,
+		WsPath:       "/broadcast/subscribe/" + topic,
+		WsPingPeriod: 10 * time.Second,
+	})
+
+	t.Run("post", func(t *testing.T) {
+		received := make(chan broadcast.Message, 1)
+		cleanup := peer.Register(broadcast.NewTopic(topic), func(_ context.Context, m broadcast.Message) { received <- m })
+		defer cleanup()
+
+		var res api.BroadcastResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/broadcast/"+topic+"?ttl=1", http.StatusCreated,
+			jsonhttptest.WithRequestBody(bytes.NewReader([]byte("hello"))),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if res.Peers != 1 {
+			t.Fatalf("got %d peers, want 1", res.Peers)
+		}
+
+		select {
+		case m := <-received:
+			if m.ID.String() != res.ID || !m.Origin.Equal(nodeAddr) || !bytes.Equal(m.Payload, []byte("hello")) {
+				t.Fatalf("got message %+v, want id %s from %s", m, res.ID, nodeAddr)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("message not received")
+		}
+	})
+
+	t.Run("subscribe", func(t *testing.T) {
+		// the subscription of the websocket is registered
+		// asynchronously, so the message is broadcast until received
+		done, stopped := make(chan struct{}), make(chan struct{})
+		defer func() {
+			close(done)
+			<-stopped
+		}()
+		go func() {
+			defer close(stopped)
+			ticker := time.NewTicker(50 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				_, _, _ = peer.Broadcast(context.Background(), broadcast.NewTopic(topic), []byte("ping"), 1)
+				select {
+				case <-ticker.C:
+				case <-done:
+					return
+				}
+			}
+		}()
+
+		if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(msg, []byte("ping")) {
+			t.Fatalf("got message %q, want %q", msg, "ping")
+		}
+	})
+
+	t.Run("invalid ttl", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/broadcast/"+topic+"?ttl=0", http.StatusBadRequest,
+			jsonhttptest.WithRequestBody(bytes.NewReader([]byte("hello"))),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid ttl",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("no neighbors", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			Logger:    log.Noop,
+			Broadcast: newService(t, swarm.RandAddress(t), nil),
+		})
+		jsonhttptest.Request(t, client, http.MethodPost, "/broadcast/"+topic, http.StatusServiceUnavailable,
+			jsonhttptest.WithRequestBody(bytes.NewReader([]byte("hello"))),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "no connected neighbors",
+				Code:    http.StatusServiceUnavailable,
+			}),
+		)
+	})
+
+	t.Run("not available", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{})
+		jsonhttptest.Request(t, client, http.MethodPost, "/broadcast/"+topic, http.StatusNotImplemented)
+	})
+}
@@ -5,13 +5,15 @@
 package api
 
 import (
-	"encoding/binary"
+	"bytes"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/file/wrapper"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/sctx"
@@ -31,15 +33,42 @@ func (s *Service) bytesUploadHandler(w http.ResponseWriter, r *http.Request) {
 	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("post_bytes").Build())
 
 	headers := struct {
-		ContentType string `map:"Content-Type" validate:"excludes=multipart/form-data"`
-		SwarmTag    string `map:"Swarm-Tag"`
+		ContentType      string `map:"Content-Type" validate:"excludes=multipart/form-data"`
+		SwarmTag         string `map:"Swarm-Tag"`
+		SwarmTagName     string `map:"Swarm-Tag-Name"`
+		SwarmContentType string `map:"Swarm-Content-Type"`
+		SwarmFilename    string `map:"Swarm-Filename"`
 	}{}
 	if response := s.mapStructure(r.Header, &headers); response != nil {
 		response("invalid header params", logger, w)
 		return
 	}
 
-	putter, wait, err := s.newStamperPutter(r)
+	queries := struct {
+		DryRun bool `map:"dry-run"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	// the bytes are wrapped only if their content type or file name is given,
+	// so that the references of the plain bytes uploads stay the same
+	var wrap *wrapper.Wrapper
+	if headers.SwarmContentType != "" || headers.SwarmFilename != "" {
+		wrap = &wrapper.Wrapper{
+			ContentType: headers.SwarmContentType,
+			Filename:    headers.SwarmFilename,
+		}
+		if err := wrap.Validate(); err != nil {
+			logger.Debug("invalid bytes metadata", "content_type", headers.SwarmContentType, "filename", headers.SwarmFilename, "error", err)
+			logger.Error(nil, "invalid bytes metadata")
+			jsonhttp.BadRequest(w, err.Error())
+			return
+		}
+	}
+
+	putter, wait, w, err := s.newUploadPutter(w, r, queries.DryRun)
 	if err != nil {
 		logger.Debug("get putter failed", "error", err)
 		logger.Error(nil, "get putter failed")
@@ -58,13 +87,15 @@ func (s *Service) bytesUploadHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tag, created, err := s.getOrCreateTag(headers.SwarmTag)
+	tag, created, err := s.getOrCreateUploadTag(putter, headers.SwarmTag, headers.SwarmTagName)
 	if err != nil {
 		logger.Debug("get or create tag failed", "error", err)
 		logger.Error(nil, "get or create tag failed")
 		switch {
 		case errors.Is(err, tags.ErrNotFound):
 			jsonhttp.NotFound(w, "tag not found")
+		case errors.Is(err, tags.ErrNameTooLong):
+			jsonhttp.BadRequest(w, "invalid tag name")
 		default:
 			jsonhttp.InternalServerError(w, "cannot get or create tag")
 		}
@@ -99,6 +130,28 @@ func (s *Service) bytesUploadHandler(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
+	if wrap != nil {
+		wrap.Reference = address
+		data, err := wrap.MarshalBinary()
+		if err != nil {
+			logger.Debug("marshal wrapper failed", "error", err)
+			logger.Error(nil, "marshal wrapper failed")
+			jsonhttp.InternalServerError(w, "marshal wrapper failed")
+			return
+		}
+		address, err = p(ctx, bytes.NewReader(data))
+		if err != nil {
+			logger.Debug("split write wrapper failed", "error", err)
+			logger.Error(nil, "split write wrapper failed")
+			switch {
+			case errors.Is(err, postage.ErrBucketFull):
+				jsonhttp.PaymentRequired(w, "batch is overissued")
+			default:
+				jsonhttp.InternalServerError(w, "split write wrapper failed")
+			}
+			return
+		}
+	}
 	if err = wait(); err != nil {
 		logger.Debug("sync chunks failed", "error", err)
 		logger.Error(nil, "sync chunks failed")
@@ -125,9 +178,16 @@ func (s *Service) bytesUploadHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	w.Header().Set(SwarmTagHeader, fmt.Sprint(tag.Uid))
-	w.Header().Set("Access-Control-Expose-Headers", SwarmTagHeader)
-	jsonhttp.Created(w, bytesPostResponse{
+	if requestReadYourWrites(r) {
+		if err := s.checkReadable(ctx, address); err != nil {
+			logger.Debug("read your writes check failed", "address", address, "error", err)
+			logger.Error(nil, "read your writes check failed")
+			jsonhttp.InternalServerError(w, errNotReadable)
+			return
+		}
+	}
+
+	respondUploaded(w, r, tag, bytesPostResponse{
 		Reference: address,
 	})
 }
@@ -139,29 +199,76 @@ func (s *Service) bytesGetHandler(w http.ResponseWriter, r *http.Request) {
 	paths := struct {
 		Address swarm.Address `map:"address,resolve" validate:"required"`
 	}{}
-	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+	if response := s.mapStructureContext(r.Context(), mux.Vars(r), &paths); response != nil {
 		response("invalid path params", logger, w)
 		return
 	}
 
+	queries := struct {
+		Unwrap bool `map:"unwrap"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
 	additionalHeaders := http.Header{
 		"Content-Type": {"application/octet-stream"},
 	}
 
-	s.downloadHandler(logger, w, r, paths.Address, additionalHeaders, true)
+	// the content is unwrapped only on request, as any bytes
+	// may start with the magic prefix of the wrapper
+	reference := paths.Address
+	if queries.Unwrap {
+		switch wrap, err := wrapper.Load(r.Context(), s.storer, paths.Address); {
+		case err == nil:
+			reference = wrap.Reference
+			setBytesMetadataHeaders(additionalHeaders, wrap)
+		case !errors.Is(err, wrapper.ErrNotWrapper):
+			// the errors of the retrieval are reported by the downloadHandler
+			logger.Debug("load wrapper failed", "address", paths.Address, "error", err)
+		}
+	}
+	s.setGatewayBytesHeaders(additionalHeaders)
+
+	s.downloadHandler(logger, w, r, reference, additionalHeaders)
 }
 
+// bytesHeadHandler responds with the headers of the bytes, such as
+// their size, type and etag, without retrieving the content.
 func (s *Service) bytesHeadHandler(w http.ResponseWriter, r *http.Request) {
 	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("head_bytes_by_address").Build())
 
 	paths := struct {
 		Address swarm.Address `map:"address,resolve" validate:"required"`
 	}{}
-	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+	if response := s.mapStructureContext(r.Context(), mux.Vars(r), &paths); response != nil {
 		response("invalid path params", logger, w)
 		return
 	}
 
+	queries := struct {
+		Unwrap bool `map:"unwrap"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	additionalHeaders := http.Header{
+		"Content-Type": {"application/octet-stream"},
+	}
+
+	if queries.Unwrap {
+		if wrap, err := wrapper.Load(r.Context(), s.storer, paths.Address); err == nil {
+			setBytesMetadataHeaders(additionalHeaders, wrap)
+			s.setGatewayBytesHeaders(additionalHeaders)
+			s.downloadHandler(logger, w, r, wrap.Reference, additionalHeaders)
+			return
+		}
+	}
+	s.setGatewayBytesHeaders(additionalHeaders)
+
 	ch, err := s.storer.Get(r.Context(), storage.ModeGetRequest, paths.Address)
 	if err != nil {
 		logger.Debug("get root chunk failed", "chunk_address", paths.Address, "error", err)
@@ -169,16 +276,31 @@ func (s *Service) bytesHeadHandler(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
-	w.Header().Add("Access-Control-Expose-Headers", "Accept-Ranges, Content-Encoding")
-	w.Header().Add("Content-Type", "application/octet-stream")
-	var span int64
-
 	if cac.Valid(ch) {
-		span = int64(binary.LittleEndian.Uint64(ch.Data()[:swarm.SpanSize]))
-	} else {
-		// soc
-		span = int64(len(ch.Data()))
+		s.downloadHandler(logger, w, r, paths.Address, additionalHeaders)
+		return
+	}
+
+	// soc
+	w.Header().Add("Access-Control-Expose-Headers", "Accept-Ranges, Content-Encoding, Content-Disposition")
+	for name, values := range additionalHeaders {
+		w.Header().Set(name, strings.Join(values, "; "))
 	}
-	w.Header().Set("Content-Length", strconv.FormatInt(span, 10))
+	w.Header().Set("Content-Length", strconv.Itoa(len(ch.Data())))
 	w.WriteHeader(http.StatusOK) // HEAD requests do not write a body
 }
+
+// setBytesMetadataHeaders sets the headers of the metadata of the wrapped bytes.
+// The content type is chosen by the uploader, so the bytes are served as an
+// attachment and the browsers are told not to sniff it.
+func setBytesMetadataHeaders(h http.Header, wrap *wrapper.Wrapper) {
+	if wrap.ContentType != "" {
+		h.Set("Content-Type", wrap.ContentType)
+	}
+	if wrap.Filename != "" {
+		h.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", wrap.Filename))
+	} else {
+		h.Set("Content-Disposition", "attachment")
+	}
+	h.Set("X-Content-Type-Options", "nosniff")
+}
@@ -8,14 +8,17 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/ethersphere/bee/pkg/api"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/netstore"
 	pinning "github.com/ethersphere/bee/pkg/pinning/mock"
 	mockbatchstore "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
@@ -23,6 +26,7 @@ import (
 	"github.com/ethersphere/bee/pkg/storage/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/util/testutil"
 	"gitlab.com/nolash/go-mockbytes"
 )
 
@@ -144,6 +148,255 @@ func TestBytes(t *testing.T) {
 	})
 }
 
+// TestBytesDiagnostics tests that the diagnostics of the
+// download are sent in the trailers of the response.
+func TestBytesDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	storerMock := mock.NewStorer()
+	ns := netstore.New(storerMock, func(c swarm.Chunk, _ []byte) (swarm.Chunk, error) { return c, nil }, nil, nil, log.Noop)
+	testutil.CleanupCloser(t, ns)
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: ns,
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	content := make([]byte, swarm.ChunkSize*2)
+	var upload api.BytesPostResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+		jsonhttptest.WithUnmarshalJSONResponse(&upload),
+	)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/bytes/"+upload.Reference.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(api.SwarmDiagnosticsHeader, "true")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Fatal("downloaded content mismatch")
+	}
+
+	// the root and both data chunks are read from the local store
+	for trailer, want := range map[string]string{
+		api.SwarmDiagnosticsChunksTrailer:        "3",
+		api.SwarmDiagnosticsCacheHitRatioTrailer: "1.00",
+		api.SwarmDiagnosticsPeersTrailer:         "0",
+		api.SwarmDiagnosticsRetriesTrailer:       "0",
+		api.SwarmDiagnosticsRetrievalTimeTrailer: "0s",
+	} {
+		if got := resp.Trailer.Get(trailer); got != want {
+			t.Errorf("got trailer %s %q, want %q", trailer, got, want)
+		}
+	}
+}
+
+// TestBytesWrapper tests that the content type and the file name
+// of the wrapped bytes are served along with the bytes.
+func TestBytesWrapper(t *testing.T) {
+	t.Parallel()
+
+	storerMock := mock.NewStorer()
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: storerMock,
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	content := []byte("<h1>Swarm</h1>")
+
+	var plain api.BytesPostResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+		jsonhttptest.WithUnmarshalJSONResponse(&plain),
+	)
+
+	for _, encrypt := range []string{"false", "true"} {
+		encrypt := encrypt
+		t.Run("encrypt="+encrypt, func(t *testing.T) {
+			t.Parallel()
+
+			var wrapped api.BytesPostResponse
+			jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+				jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+				jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+				jsonhttptest.WithRequestHeader(api.SwarmEncryptHeader, encrypt),
+				jsonhttptest.WithRequestHeader(api.SwarmContentTypeHeader, "text/html; charset=utf-8"),
+				jsonhttptest.WithRequestHeader(api.SwarmFilenameHeader, "index.html"),
+				jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+				jsonhttptest.WithUnmarshalJSONResponse(&wrapped),
+			)
+			if wrapped.Reference.Equal(plain.Reference) {
+				t.Fatal("wrapped bytes reference equals the plain bytes reference")
+			}
+
+			jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+wrapped.Reference.String()+"?unwrap=true", http.StatusOK,
+				jsonhttptest.WithExpectedContentLength(len(content)),
+				jsonhttptest.WithExpectedResponseHeader("Content-Type", "text/html; charset=utf-8"),
+				jsonhttptest.WithExpectedResponseHeader("Content-Disposition", `attachment; filename="index.html"`),
+				jsonhttptest.WithExpectedResponseHeader("X-Content-Type-Options", "nosniff"),
+				jsonhttptest.WithExpectedResponse(content),
+			)
+			jsonhttptest.Request(t, client, http.MethodHead, "/bytes/"+wrapped.Reference.String()+"?unwrap=true", http.StatusOK,
+				jsonhttptest.WithExpectedContentLength(len(content)),
+				jsonhttptest.WithExpectedResponseHeader("Content-Type", "text/html; charset=utf-8"),
+				jsonhttptest.WithExpectedResponseHeader("Content-Disposition", `attachment; filename="index.html"`),
+			)
+
+			// the wrapper is served as is without the unwrap flag
+			header := jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+wrapped.Reference.String(), http.StatusOK,
+				jsonhttptest.WithExpectedResponseHeader("Content-Type", "application/octet-stream"),
+			)
+			if v := header.Get("Content-Disposition"); v != "" {
+				t.Fatalf("got content disposition %q of the wrapper, want none", v)
+			}
+			if encrypt == "false" {
+				jsonhttptest.Request(t, client, http.MethodHead, "/bytes/"+wrapped.Reference.String(), http.StatusOK,
+					jsonhttptest.WithExpectedResponseHeader("Content-Type", "application/octet-stream"),
+				)
+			}
+		})
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+plain.Reference.String(), http.StatusOK,
+			jsonhttptest.WithExpectedResponseHeader("Content-Type", "application/octet-stream"),
+			jsonhttptest.WithExpectedResponse(content),
+		)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			header, value, message string
+		}{
+			{api.SwarmFilenameHeader, "../index.html", "invalid filename"},
+			{api.SwarmFilenameHeader, `index".html`, "invalid filename"},
+			{api.SwarmContentTypeHeader, "text/html;;", "invalid content-type"},
+			{api.SwarmFilenameHeader, strings.Repeat("a", 256), "wrapper field too long"},
+		} {
+			jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusBadRequest,
+				jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+				jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+				jsonhttptest.WithRequestHeader(tc.header, tc.value),
+				jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+				jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+					Message: tc.message,
+					Code:    http.StatusBadRequest,
+				}),
+			)
+		}
+	})
+}
+
+// TestBytesDryRun tests that the dry-run upload returns the reference and
+// the chunk count of the content without storing it.
+func TestBytesDryRun(t *testing.T) {
+	t.Parallel()
+
+	storerMock := mock.NewStorer()
+	tagsSvc := tags.NewTags(statestore.NewStateStore(), log.Noop)
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: storerMock,
+		Tags:   tagsSvc,
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	// 129 distinct data chunks, an intermediate chunk of the first 128 and the root chunk
+	content := make([]byte, 129*swarm.ChunkSize)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+
+	var dryRun api.BytesPostResponse
+	header := jsonhttptest.Request(t, client, http.MethodPost, "/bytes?dry-run=true", http.StatusOK,
+		jsonhttptest.WithRequestHeader(api.SwarmPinHeader, "true"),
+		jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+		jsonhttptest.WithExpectedResponseHeader(api.SwarmChunkCountHeader, "131"),
+		jsonhttptest.WithUnmarshalJSONResponse(&dryRun),
+	)
+	if has, _ := storerMock.Has(context.Background(), dryRun.Reference); has {
+		t.Fatal("dry-run upload stored the content")
+	}
+	if uid := header.Get(api.SwarmTagHeader); uid != "" {
+		t.Fatalf("dry-run upload responded with tag %s", uid)
+	}
+	if n, err := tagsSvc.Count(); err != nil || n != 0 {
+		t.Fatalf("got %d tags, error %v, want none", n, err)
+	}
+
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+		jsonhttptest.WithExpectedJSONResponse(dryRun),
+	)
+}
+
+// TestBytesRespondAsync tests that the deferred upload preferring the
+// asynchronous response is accepted with the location of its tag.
+func TestBytesRespondAsync(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: mock.NewStorer(),
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	content := make([]byte, 3*swarm.ChunkSize)
+
+	rcvdHeader := jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusAccepted,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader("Prefer", "respond-async"),
+		jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+		jsonhttptest.WithExpectedResponseHeader("Preference-Applied", "respond-async"),
+	)
+	uid := rcvdHeader.Get(api.SwarmTagHeader)
+	if got, want := rcvdHeader.Get("Location"), "/tags/"+uid; got != want {
+		t.Fatalf("got location %q, want %q", got, want)
+	}
+
+	var tag api.TagResponse
+	jsonhttptest.Request(t, client, http.MethodGet, "/tags/"+uid, http.StatusOK,
+		jsonhttptest.WithUnmarshalJSONResponse(&tag),
+	)
+	if tag.Total == 0 || tag.Processed != tag.Total {
+		t.Fatalf("got %d processed of %d total chunks", tag.Processed, tag.Total)
+	}
+
+	// the synchronous response is preferred by default
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+	)
+}
+
 // nolint:paralleltest,tparallel
 func TestBytesInvalidStamp(t *testing.T) {
 	t.Parallel()
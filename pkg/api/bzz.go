@@ -20,18 +20,19 @@ import (
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/gorilla/mux"
 
+	"github.com/ethersphere/bee/pkg/accesscontrol"
 	"github.com/ethersphere/bee/pkg/feeds"
 	"github.com/ethersphere/bee/pkg/file/joiner"
 	"github.com/ethersphere/bee/pkg/file/loadsave"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/manifest"
 	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/retrieval"
 	"github.com/ethersphere/bee/pkg/sctx"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
 	"github.com/ethersphere/bee/pkg/tracing"
-	"github.com/ethersphere/langos"
 )
 
 func (s *Service) bzzUploadHandler(w http.ResponseWriter, r *http.Request) {
@@ -45,7 +46,47 @@ func (s *Service) bzzUploadHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	putter, wait, err := s.newStamperPutter(r)
+	queries := struct {
+		DryRun        bool `map:"dry-run"`
+		CheckExisting bool `map:"check-existing"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	if requestAct(r) {
+		if s.accessControl == nil {
+			jsonhttp.NotImplemented(w, "access control not enabled")
+			return
+		}
+		if _, err := requestActGrantees(r); err != nil {
+			logger.Debug("invalid grantees", "error", err)
+			logger.Error(nil, "invalid grantees")
+			jsonhttp.BadRequest(w, errInvalidGrantees)
+			return
+		}
+		// the content key is a part of the reference which is
+		// encrypted for the grantees, so the upload is encrypted
+		r.Header.Set(SwarmEncryptHeader, boolHeaderSetValue)
+	}
+
+	if _, err := requestCompression(r); err != nil {
+		logger.Debug("invalid compression", "error", err)
+		logger.Error(nil, "invalid compression")
+		jsonhttp.BadRequest(w, errUnsupportedCompression)
+		return
+	}
+
+	if queries.CheckExisting && !queries.DryRun {
+		cleanup, served := s.checkExistingUpload(logger, w, r, headers.ContentType)
+		defer cleanup()
+		if served {
+			return
+		}
+	}
+
+	putter, wait, w, err := s.newUploadPutter(w, r, queries.DryRun)
 	if err != nil {
 		logger.Debug("putter failed", "error", err)
 		logger.Error(nil, "putter failed")
@@ -64,8 +105,14 @@ func (s *Service) bzzUploadHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.bzzStore(logger, w, r, headers.ContentType, putter, wait)
+}
+
+// bzzStore uploads the collection or the file of the request by its
+// content type with the putter.
+func (s *Service) bzzStore(logger log.Logger, w http.ResponseWriter, r *http.Request, contentType string, putter storage.Storer, wait func() error) {
 	isDir := r.Header.Get(SwarmCollectionHeader)
-	if strings.ToLower(isDir) == "true" || headers.ContentType == multiPartFormData {
+	if strings.ToLower(isDir) == "true" || contentType == multiPartFormData {
 		s.dirUploadHandler(logger, w, r, putter, wait)
 		return
 	}
@@ -75,6 +122,9 @@ func (s *Service) bzzUploadHandler(w http.ResponseWriter, r *http.Request) {
 // fileUploadResponse is returned when an HTTP request to upload a file is successful
 type bzzUploadResponse struct {
 	Reference swarm.Address `json:"reference"`
+	// AlreadyStored is set if the upload checking the existing content
+	// was not stored, as its content was already found.
+	AlreadyStored bool `json:"alreadyStored,omitempty"`
 }
 
 // fileUploadHandler uploads the file and its metadata supplied in the file body and
@@ -88,13 +138,15 @@ func (s *Service) fileUploadHandler(logger log.Logger, w http.ResponseWriter, r
 		return
 	}
 
-	tag, created, err := s.getOrCreateTag(r.Header.Get(SwarmTagHeader))
+	tag, created, err := s.getOrCreateUploadTag(storer, r.Header.Get(SwarmTagHeader), r.Header.Get(SwarmTagNameHeader))
 	if err != nil {
 		logger.Debug("get or create tag failed", "error", err)
 		logger.Error(nil, "get or create tag failed")
 		switch {
 		case errors.Is(err, tags.ErrNotFound):
 			jsonhttp.NotFound(w, "tag not found")
+		case errors.Is(err, tags.ErrNameTooLong):
+			jsonhttp.BadRequest(w, "invalid tag name")
 		default:
 			jsonhttp.InternalServerError(w, "cannot get or create tag")
 		}
@@ -116,7 +168,9 @@ func (s *Service) fileUploadHandler(logger log.Logger, w http.ResponseWriter, r
 
 	// Add the tag to the context
 	ctx := sctx.SetTag(r.Context(), tag)
-	p := requestPipelineFn(storer, r)
+	// the coding was already validated by the caller
+	compression, _ := requestCompression(r)
+	p := compressedPipelineFn(requestPipelineFn(storer, r), compression)
 
 	// first store the file and get its reference
 	fr, err := p(ctx, r.Body)
@@ -187,6 +241,9 @@ func (s *Service) fileUploadHandler(logger log.Logger, w http.ResponseWriter, r
 		manifest.EntryMetadataContentTypeKey: r.Header.Get(contentTypeHeader), // Content-Type has already been validated.
 		manifest.EntryMetadataFilenameKey:    queries.FileName,
 	}
+	if compression != "" {
+		fileMtdt[manifest.EntryMetadataContentEncodingKey] = compression
+	}
 
 	err = m.Add(ctx, queries.FileName, manifest.NewEntry(fr, fileMtdt))
 	if err != nil {
@@ -246,6 +303,16 @@ func (s *Service) fileUploadHandler(logger log.Logger, w http.ResponseWriter, r
 		}
 	}
 
+	if requestAct(r) {
+		manifestReference, err = s.storeAccessManifest(ctx, r, storer, manifestReference)
+		if err != nil {
+			logger.Debug("access manifest store failed", "file_name", queries.FileName, "error", err)
+			logger.Error(nil, "access manifest store failed", "file_name", queries.FileName)
+			actErrorResponse(w, err)
+			return
+		}
+	}
+
 	if err = waitFn(); err != nil {
 		logger.Debug("sync chunks failed", "error", err)
 		logger.Error(nil, "sync chunks failed")
@@ -253,10 +320,17 @@ func (s *Service) fileUploadHandler(logger log.Logger, w http.ResponseWriter, r
 		return
 	}
 
+	if requestReadYourWrites(r) {
+		if err := s.checkReadable(ctx, manifestReference); err != nil {
+			logger.Debug("read your writes check failed", "manifest_reference", manifestReference, "error", err)
+			logger.Error(nil, "read your writes check failed")
+			jsonhttp.InternalServerError(w, errNotReadable)
+			return
+		}
+	}
+
 	w.Header().Set("ETag", fmt.Sprintf("%q", manifestReference.String()))
-	w.Header().Set(SwarmTagHeader, fmt.Sprint(tag.Uid))
-	w.Header().Set("Access-Control-Expose-Headers", SwarmTagHeader)
-	jsonhttp.Created(w, bzzUploadResponse{
+	respondUploaded(w, r, tag, bzzUploadResponse{
 		Reference: manifestReference,
 	})
 }
@@ -268,7 +342,7 @@ func (s *Service) bzzDownloadHandler(w http.ResponseWriter, r *http.Request) {
 		Address swarm.Address `map:"address,resolve" validate:"required"`
 		Path    string        `map:"path"`
 	}{}
-	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+	if response := s.mapStructureContext(r.Context(), mux.Vars(r), &paths); response != nil {
 		response("invalid path params", logger, w)
 		return
 	}
@@ -285,6 +359,7 @@ func (s *Service) serveReference(logger log.Logger, address swarm.Address, pathV
 
 	ls := loadsave.NewReadonly(s.storer)
 	feedDereferenced := false
+	actOpened := false
 
 	ctx := r.Context()
 
@@ -301,6 +376,28 @@ FETCH:
 		return
 	}
 
+	// the access manifest is opened transparently if the node is granted
+	// the access, otherwise the access is denied
+	if s.accessControl != nil && !actOpened {
+		switch ref, err := s.accessControl.Open(ctx, m); {
+		case err == nil:
+			address = ref
+			actOpened = true
+			goto FETCH
+		case errors.Is(err, accesscontrol.ErrNotAccessManifest):
+		case errors.Is(err, accesscontrol.ErrAccessDenied):
+			logger.Debug("bzz download: access denied", "address", address)
+			logger.Error(nil, "bzz download: access denied")
+			jsonhttp.Forbidden(w, "access denied")
+			return
+		default:
+			logger.Debug("bzz download: open access manifest failed", "address", address, "error", err)
+			logger.Error(nil, "bzz download: open access manifest failed")
+			jsonhttp.NotFound(w, nil)
+			return
+		}
+	}
+
 	// there's a possible ambiguity here, right now the data which was
 	// read can be an entry.Entry or a mantaray feed manifest. Try to
 	// unmarshal as mantaray first and possibly resolve the feed, otherwise
@@ -343,11 +440,16 @@ FETCH:
 			// we should implement an append functionality for this specific header,
 			// since different parts of handlers might be overriding others' values
 			// resulting in inconsistent headers in the response.
-			w.Header().Set("Access-Control-Expose-Headers", SwarmFeedIndexHeader)
+			w.Header().Add("Access-Control-Expose-Headers", SwarmFeedIndexHeader)
 			goto FETCH
 		}
 	}
 
+	if pathVar == "" && acceptsTar(r) {
+		s.serveTar(logger, w, r, address, ls)
+		return
+	}
+
 	if pathVar == "" {
 		loggerV1.Debug("bzz download: handle empty path", "address", address)
 
@@ -362,6 +464,13 @@ FETCH:
 				return
 			}
 		}
+		if s.serveWebsiteRedirect(ctx, logger, w, r, m, pathVar, !feedDereferenced) {
+			return
+		}
+		if s.DirListing {
+			s.serveDirListing(logger, w, r, address, ls, pathVar)
+			return
+		}
 		logger.Debug("bzz download: address not found or incorrect", "address", address, "path", pathVar)
 		logger.Error(nil, "address not found or incorrect")
 		jsonhttp.NotFound(w, "address not found or incorrect")
@@ -408,6 +517,19 @@ FETCH:
 				}
 			}
 
+			// evaluate the redirect and rewrite rules
+			if s.serveWebsiteRedirect(ctx, logger, w, r, m, pathVar, !feedDereferenced) {
+				return
+			}
+
+			// list the directory without the index document
+			if s.DirListing && strings.HasSuffix(pathVar, "/") {
+				if exists, err := m.HasPrefix(ctx, pathVar); err == nil && exists {
+					s.serveDirListing(logger, w, r, address, ls, pathVar)
+					return
+				}
+			}
+
 			// check if error document is to be shown
 			if errorDocumentPath, ok := manifestMetadataLoad(ctx, m, manifest.RootPath, manifest.WebsiteErrorDocumentPathKey); ok {
 				if pathVar != errorDocumentPath {
@@ -433,30 +555,94 @@ FETCH:
 	s.serveManifestEntry(logger, w, r, me, !feedDereferenced)
 }
 
+// serveManifestEntry serves the content of the manifest entry. The
+// modification time of the entry is served only if lastModified is true,
+// as it is not meaningful for the content dereferenced from a feed, whose
+// updates may carry an earlier modification time than the cached content.
 func (s *Service) serveManifestEntry(
 	logger log.Logger,
 	w http.ResponseWriter,
 	r *http.Request,
 	manifestEntry manifest.Entry,
-	etag bool,
+	lastModified bool,
 ) {
 	additionalHeaders := http.Header{}
 	mtdt := manifestEntry.Metadata()
-	if fname, ok := mtdt[manifest.EntryMetadataFilenameKey]; ok {
+	fname, ok := mtdt[manifest.EntryMetadataFilenameKey]
+	if ok {
 		fname = filepath.Base(fname) // only keep the file name
 		additionalHeaders["Content-Disposition"] =
 			[]string{fmt.Sprintf("inline; filename=\"%s\"", fname)}
 	}
-	if mimeType, ok := mtdt[manifest.EntryMetadataContentTypeKey]; ok {
+	mimeType, ok := mtdt[manifest.EntryMetadataContentTypeKey]
+	if ok {
 		additionalHeaders["Content-Type"] = []string{mimeType}
 	}
+	// the players reject the playlists and segments of the streamed
+	// media which are not served with their proper content types
+	if ct, ok := mediaContentType(fname, mimeType); ok {
+		additionalHeaders["Content-Type"] = []string{ct}
+	}
+	if v, ok := mtdt[manifest.EntryMetadataModTimeKey]; ok && lastModified {
+		if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+			additionalHeaders["Last-Modified"] = []string{time.Unix(sec, 0).UTC().Format(http.TimeFormat)}
+		}
+	}
 
-	s.downloadHandler(logger, w, r, manifestEntry.Reference(), additionalHeaders, etag)
+	// the content stored with a content coding is served as it is to the
+	// clients which accept the coding and decoded for the other ones
+	coding := mtdt[manifest.EntryMetadataContentEncodingKey]
+
+	if s.transform != nil && coding == "" && s.serveTransformed(logger, w, r, manifestEntry.Reference(), additionalHeaders) {
+		return
+	}
+
+	reference := manifestEntry.Reference()
+	if hasPrecompressedVariants(mtdt) {
+		// the response depends on the accepted encodings, so that
+		// the caches must not serve it to the other clients
+		additionalHeaders["Vary"] = []string{"Accept-Encoding"}
+		if ref, variant, ok := precompressedVariant(r, mtdt); ok {
+			reference = ref
+			additionalHeaders["Content-Encoding"] = []string{variant}
+			s.downloadHandler(logger, w, r, reference, additionalHeaders)
+			return
+		}
+	}
+	if coding != "" {
+		additionalHeaders["Vary"] = []string{"Accept-Encoding"}
+		if !acceptsCoding(r, coding) {
+			s.serveDecoded(logger, w, r, reference, additionalHeaders)
+			return
+		}
+		additionalHeaders["Content-Encoding"] = []string{coding}
+	}
+
+	s.downloadHandler(logger, w, r, reference, additionalHeaders)
 }
 
 // downloadHandler contains common logic for dowloading Swarm file from API
-func (s *Service) downloadHandler(logger log.Logger, w http.ResponseWriter, r *http.Request, reference swarm.Address, additionalHeaders http.Header, etag bool) {
-	reader, l, err := joiner.New(r.Context(), s.storer, reference)
+func (s *Service) downloadHandler(logger log.Logger, w http.ResponseWriter, r *http.Request, reference swarm.Address, additionalHeaders http.Header) {
+	// the content is immutable, so that the etag of the reference, or of its
+	// compressed representation, is enough to tell that the client has it
+	modTime, _ := http.ParseTime(additionalHeaders.Get("Last-Modified"))
+	if vary := additionalHeaders.Get("Vary"); vary != "" {
+		w.Header().Set("Vary", vary)
+	}
+	if serveNotModified(w, r, []string{fmt.Sprintf("%q", reference), gzipETag(reference)}, modTime) {
+		return
+	}
+
+	release, ok := s.acquireDownload(w)
+	if !ok {
+		return
+	}
+	defer release()
+
+	diagnostics := retrieval.NewDiagnostics()
+	ctx := retrieval.WithDiagnostics(r.Context(), diagnostics)
+
+	reader, l, err := joiner.New(ctx, s.storer, reference)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			logger.Debug("api download: not found ", "address", reference, "error", err)
@@ -474,12 +660,121 @@ func (s *Service) downloadHandler(logger log.Logger, w http.ResponseWriter, r *h
 	for name, values := range additionalHeaders {
 		w.Header().Set(name, strings.Join(values, "; "))
 	}
-	if etag {
-		w.Header().Set("ETag", fmt.Sprintf("%q", reference))
+	w.Header().Set("ETag", fmt.Sprintf("%q", reference))
+	w.Header().Add("Access-Control-Expose-Headers", "Content-Disposition")
+
+	// the HEAD request is answered with the headers of the content
+	// without retrieving any of its chunks beyond the root one
+	head := r.Method == http.MethodHead
+
+	trailers := !head && requestDiagnostics(r)
+	if trailers {
+		w.Header().Set("Trailer", strings.Join(diagnosticsTrailers, ", "))
+		w.Header().Add("Access-Control-Expose-Headers", strings.Join(diagnosticsTrailers, ", "))
+		w = &trailerWriter{ResponseWriter: w}
+	} else {
+		w.Header().Set("Content-Length", strconv.FormatInt(l, 10))
+	}
+
+	contentType := additionalHeaders.Get("Content-Type")
+	compress := false
+	if s.Compression.Compresses(contentType, l) && additionalHeaders.Get("Content-Encoding") == "" {
+		// the response depends on the accepted encodings, so that
+		// the caches must not serve it to the other clients
+		if w.Header().Get("Vary") == "" {
+			w.Header().Set("Vary", "Accept-Encoding")
+		}
+		// the ranges of the original content are served uncompressed,
+		// so that the downloads are resumed and the media is seeked
+		if compress = acceptsGzip(r) && r.Header.Get("Range") == ""; compress {
+			// the compressed content differs from
+			// the original one served with the etag
+			w.Header().Set("ETag", gzipETag(reference))
+			w.Header().Add("Access-Control-Expose-Headers", "Content-Encoding")
+		}
+	}
+	if isStreamingMedia(contentType) {
+		limitOpenEndedRange(r, l)
+		// the players in the browsers read the ranges cross-origin
+		w.Header().Add("Access-Control-Expose-Headers", "Accept-Ranges, Content-Range, Content-Length")
+	}
+	if head {
+		if compress {
+			// the size of the compressed content is not known in advance
+			w.Header().Del("Content-Length")
+			w.Header().Set("Content-Encoding", "gzip")
+		} else {
+			w.Header().Set("Accept-Ranges", "bytes")
+		}
+		if !modTime.IsZero() {
+			w.Header().Set("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if ranges, ok := multiRanges(r, w.Header().Get("ETag"), l); ok {
+		s.metrics.MultiRangeRequests.Inc()
+		if err := serveRanges(w, reader, ranges, l); err != nil {
+			logger.Debug("api download: serve ranges failed", "address", reference, "error", err)
+			logger.Error(nil, "api download: serve ranges failed")
+		}
+	} else if compress {
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		http.ServeContent(gw, r, "", modTime, s.Lookahead.reader(reader, contentType, l))
+		if err := gw.Close(); err != nil {
+			logger.Debug("api download: compression failed", "address", reference, "error", err)
+			logger.Error(nil, "api download: compression failed")
+		}
+	} else {
+		http.ServeContent(w, r, "", modTime, s.Lookahead.reader(reader, contentType, l))
+	}
+
+	report := diagnostics.Report()
+	if report.Chunks > 0 {
+		s.metrics.DownloadChunks.Observe(float64(report.Chunks))
+		s.metrics.DownloadCacheHitRatio.Observe(report.CacheHitRatio())
+		s.metrics.DownloadRetries.Observe(float64(report.Retries))
+		s.metrics.DownloadRetrievalTime.Observe(report.RetrievalTime.Seconds())
+	}
+
+	if trailers {
+		w.Header().Set(SwarmDiagnosticsChunksTrailer, strconv.Itoa(report.Chunks))
+		w.Header().Set(SwarmDiagnosticsCacheHitRatioTrailer, strconv.FormatFloat(report.CacheHitRatio(), 'f', 2, 64))
+		w.Header().Set(SwarmDiagnosticsPeersTrailer, strconv.Itoa(report.Peers))
+		w.Header().Set(SwarmDiagnosticsRetriesTrailer, strconv.Itoa(report.Retries))
+		w.Header().Set(SwarmDiagnosticsRetrievalTimeTrailer, report.RetrievalTime.String())
+	}
+}
+
+// diagnosticsTrailers are the trailers of the download diagnostics.
+var diagnosticsTrailers = []string{
+	SwarmDiagnosticsChunksTrailer,
+	SwarmDiagnosticsCacheHitRatioTrailer,
+	SwarmDiagnosticsPeersTrailer,
+	SwarmDiagnosticsRetriesTrailer,
+	SwarmDiagnosticsRetrievalTimeTrailer,
+}
+
+// trailerWriter removes the content length of the response, so that the
+// response is sent chunked and the trailers are sent after the body.
+type trailerWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (tw *trailerWriter) WriteHeader(code int) {
+	if !tw.wroteHeader {
+		tw.wroteHeader = true
+		tw.Header().Del("Content-Length")
+	}
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+func (tw *trailerWriter) Write(b []byte) (int, error) {
+	if !tw.wroteHeader {
+		tw.WriteHeader(http.StatusOK)
 	}
-	w.Header().Set("Content-Length", strconv.FormatInt(l, 10))
-	w.Header().Set("Access-Control-Expose-Headers", "Content-Disposition")
-	http.ServeContent(w, r, "", time.Now(), langos.NewBufferedLangos(reader, lookaheadBufferSize(l)))
+	return tw.ResponseWriter.Write(b)
 }
 
 // manifestMetadataLoad returns the value for a key stored in the metadata of
@@ -5,6 +5,7 @@
 package api_test
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
 	"errors"
@@ -16,6 +17,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/api"
 	"github.com/ethersphere/bee/pkg/file/loadsave"
@@ -504,6 +506,194 @@ func parseRangeParts(t *testing.T, contentType string, body []byte) (parts [][]b
 	return parts
 }
 
+func TestBzzFilesMultiRangeRequests(t *testing.T) {
+	t.Parallel()
+
+	data := make([]byte, 10*swarm.ChunkSize+100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: smock.NewStorer(),
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	var resp api.BzzUploadResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+		jsonhttptest.WithRequestHeader("Content-Type", "application/octet-stream"),
+		jsonhttptest.WithUnmarshalJSONResponse(&resp),
+	)
+	downloadPath := "/bzz/" + resp.Reference.String()
+
+	t.Run("ranges across chunks", func(t *testing.T) {
+		t.Parallel()
+
+		rangeHeader, want := createRangeHeader(data, [][2]int{{4000, 4200}, {8 * swarm.ChunkSize, 9*swarm.ChunkSize + 10}, {10*swarm.ChunkSize + 50, -1}})
+
+		var body []byte
+		respHeaders := jsonhttptest.Request(t, client, http.MethodGet, downloadPath, http.StatusPartialContent,
+			jsonhttptest.WithRequestHeader("Range", rangeHeader),
+			jsonhttptest.WithPutResponseBody(&body),
+		)
+		if cl := respHeaders.Get("Content-Length"); cl != strconv.Itoa(len(body)) {
+			t.Fatalf("got content length %s, want %d", cl, len(body))
+		}
+
+		got := parseRangeParts(t, respHeaders.Get("Content-Type"), body)
+		if len(got) != len(want) {
+			t.Fatalf("got %v parts, want %v parts", len(got), len(want))
+		}
+		for i := range want {
+			if !bytes.Equal(got[i], want[i]) {
+				t.Errorf("part %v: got %d bytes, want %d bytes", i, len(got[i]), len(want[i]))
+			}
+		}
+	})
+
+	t.Run("if-range mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		rangeHeader, _ := createRangeHeader(data, [][2]int{{0, 10}, {100, 110}})
+
+		var body []byte
+		jsonhttptest.Request(t, client, http.MethodGet, downloadPath, http.StatusOK,
+			jsonhttptest.WithRequestHeader("Range", rangeHeader),
+			jsonhttptest.WithRequestHeader("If-Range", `"other"`),
+			jsonhttptest.WithPutResponseBody(&body),
+		)
+		if !bytes.Equal(body, data) {
+			t.Fatalf("got %d bytes, want the whole content of %d bytes", len(body), len(data))
+		}
+	})
+}
+
+// TestBzzDryRun tests that the dry-run upload of a file returns
+// the reference of its manifest without storing it.
+func TestBzzDryRun(t *testing.T) {
+	t.Parallel()
+
+	storerMock := smock.NewStorer()
+	tagsSvc := tags.NewTags(statestore.NewStateStore(), log.Noop)
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: storerMock,
+		Tags:   tagsSvc,
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	content := []byte("<h1>Swarm</h1>")
+
+	var dryRun api.BzzUploadResponse
+	header := jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=index.html&dry-run=true", http.StatusOK,
+		jsonhttptest.WithRequestHeader("Content-Type", "text/html"),
+		jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+		jsonhttptest.WithUnmarshalJSONResponse(&dryRun),
+	)
+	if has, _ := storerMock.Has(context.Background(), dryRun.Reference); has {
+		t.Fatal("dry-run upload stored the manifest")
+	}
+	if uid := header.Get(api.SwarmTagHeader); uid != "" {
+		t.Fatalf("dry-run upload responded with tag %s", uid)
+	}
+
+	// the collections are not tagged either
+	header = jsonhttptest.Request(t, client, http.MethodPost, "/bzz?dry-run=true", http.StatusOK,
+		jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+		jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+		jsonhttptest.WithRequestBody(tarFiles(t, []f{{data: content, name: "index.html"}})),
+	)
+	if uid := header.Get(api.SwarmTagHeader); uid != "" {
+		t.Fatalf("dry-run upload responded with tag %s", uid)
+	}
+	if n, err := tagsSvc.Count(); err != nil || n != 0 {
+		t.Fatalf("got %d tags, error %v, want none", n, err)
+	}
+
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=index.html", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader("Content-Type", "text/html"),
+		jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+		jsonhttptest.WithExpectedJSONResponse(dryRun),
+	)
+}
+
+// TestBzzTar tests that the collection is streamed
+// as a tar archive when it is requested so.
+func TestBzzTar(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: smock.NewStorer(),
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	files := []f{
+		{data: []byte("<h1>Swarm</h1>"), name: "index.html"},
+		{data: []byte("body { color: red; }"), name: "style.css", dir: "css"},
+		{data: bytes.Repeat([]byte{1}, 3*swarm.ChunkSize+7), name: "data.json", dir: "a/b"},
+	}
+
+	var resp api.BzzUploadResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+		jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+		jsonhttptest.WithRequestBody(tarFiles(t, files)),
+		jsonhttptest.WithUnmarshalJSONResponse(&resp),
+	)
+
+	var body []byte
+	jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+resp.Reference.String()+"/", http.StatusOK,
+		jsonhttptest.WithRequestHeader("Accept", api.ContentTypeTar),
+		jsonhttptest.WithExpectedResponseHeader("Content-Type", api.ContentTypeTar),
+		jsonhttptest.WithPutResponseBody(&body),
+	)
+
+	want := []struct {
+		name        string
+		data        []byte
+		contentType string
+	}{
+		{name: "a/b/data.json", data: files[2].data, contentType: "application/json"},
+		{name: "css/style.css", data: files[1].data, contentType: "text/css; charset=utf-8"},
+		{name: "index.html", data: files[0].data, contentType: "text/html; charset=utf-8"},
+	}
+
+	tr := tar.NewReader(bytes.NewReader(body))
+	for _, w := range want {
+		hdr, err := tr.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Name != w.name {
+			t.Fatalf("got name %q, want %q", hdr.Name, w.name)
+		}
+		if got := hdr.PAXRecords[api.TarPAXContentTypeKey]; got != w.contentType {
+			t.Fatalf("%s: got content type %q, want %q", w.name, got, w.contentType)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, w.data) {
+			t.Fatalf("%s: got %d bytes, want %d bytes", w.name, len(data), len(w.data))
+		}
+	}
+	if _, err := tr.Next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("got error %v, want %v", err, io.EOF)
+	}
+}
+
 func TestFeedIndirection(t *testing.T) {
 	t.Parallel()
 
@@ -521,12 +711,14 @@ func TestFeedIndirection(t *testing.T) {
 		})
 	)
 	// tar all the test case files
+	modTime := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
 	tarReader := tarFiles(t, []f{
 		{
 			data:     updateData,
 			name:     "index.html",
 			dir:      "",
 			filePath: "./index.html",
+			modTime:  modTime,
 		},
 	})
 
@@ -538,6 +730,7 @@ func TestFeedIndirection(t *testing.T) {
 		jsonhttptest.WithRequestBody(tarReader),
 		jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
 		jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "True"),
+		jsonhttptest.WithRequestHeader(api.SwarmPreserveMtimeHeader, "true"),
 		jsonhttptest.WithUnmarshalJSONResponse(&resp),
 		jsonhttptest.WithRequestHeader(api.SwarmIndexDocumentHeader, "index.html"),
 	}
@@ -594,10 +787,41 @@ func TestFeedIndirection(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	jsonhttptest.Request(t, client, http.MethodGet, bzzDownloadResource(manifRef.String(), ""), http.StatusOK,
+	header := jsonhttptest.Request(t, client, http.MethodGet, bzzDownloadResource(manifRef.String(), ""), http.StatusOK,
 		jsonhttptest.WithExpectedResponse(updateData),
 		jsonhttptest.WithExpectedContentLength(len(updateData)),
 	)
+
+	// the etag is of the content of the resolved update
+	etag := header.Get("ETag")
+	if etag == "" {
+		t.Fatal("missing etag of the feed update")
+	}
+	header = jsonhttptest.Request(t, client, http.MethodGet, bzzDownloadResource(manifRef.String(), ""), http.StatusNotModified,
+		jsonhttptest.WithRequestHeader("If-None-Match", etag),
+		jsonhttptest.WithExpectedResponseHeader("ETag", etag),
+	)
+	if header.Get(api.SwarmFeedIndexHeader) == "" {
+		t.Fatal("missing feed index of the not modified response")
+	}
+
+	// the modification time of the content of the update is not evaluated,
+	// as the later updates may carry an earlier modification time
+	header = jsonhttptest.Request(t, client, http.MethodGet, bzzDownloadResource(manifRef.String(), ""), http.StatusOK,
+		jsonhttptest.WithRequestHeader("If-Modified-Since", modTime.Format(http.TimeFormat)),
+		jsonhttptest.WithExpectedResponse(updateData),
+	)
+	if v := header.Get("Last-Modified"); v != "" {
+		t.Fatalf("got last modified %q of the feed update, want none", v)
+	}
+
+	header = jsonhttptest.Request(t, client, http.MethodHead, bzzDownloadResource(manifRef.String(), ""), http.StatusOK,
+		jsonhttptest.WithExpectedContentLength(len(updateData)),
+		jsonhttptest.WithExpectedResponseHeader("ETag", etag),
+	)
+	if header.Get(api.SwarmFeedIndexHeader) == "" {
+		t.Fatal("missing feed index of the head response")
+	}
 }
 
 func Test_bzzDownloadHandler_invalidInputs(t *testing.T) {
@@ -0,0 +1,174 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/challenge"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+)
+
+// challengeTokenCookie is the name of the cookie with the pass token,
+// so that the browsers pass the challenge once for all the requests.
+const challengeTokenCookie = "swarm-challenge-token"
+
+type challengeResponse struct {
+	Challenge  string    `json:"challenge"`
+	Difficulty uint8     `json:"difficulty"`
+	Expires    time.Time `json:"expires"`
+}
+
+type challengeRequest struct {
+	Challenge string `json:"challenge"`
+	Solution  string `json:"solution"`
+}
+
+type challengeTokenResponse struct {
+	Token   string    `json:"token"`
+	Expires time.Time `json:"expires"`
+}
+
+// challengeHandler challenges the anonymous requests of the route with the
+// name if the route is protected. The requests are served only with a valid
+// pass token in the header or in the cookie, and only at the rate of the
+// token, otherwise a new challenge is returned in the response headers.
+func (s *Service) challengeHandler(route string) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if s.challenge == nil || !s.challenge.Protects(route) || s.challenge.Exempt(remoteIP(r)) {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			token := r.Header.Get(SwarmChallengeTokenHeader)
+			if token == "" {
+				if c, err := r.Cookie(challengeTokenCookie); err == nil {
+					token = c.Value
+				}
+			}
+
+			err := s.challenge.Allow(token)
+			if err == nil {
+				h.ServeHTTP(w, r)
+				return
+			}
+			if errors.Is(err, challenge.ErrRateLimited) {
+				jsonhttp.TooManyRequests(w, "rate limited")
+				return
+			}
+
+			c, err := s.challenge.Issue()
+			if err != nil {
+				s.logger.Debug("issue challenge failed", "error", err)
+				s.logger.Error(nil, "issue challenge failed")
+				jsonhttp.InternalServerError(w, "issue challenge failed")
+				return
+			}
+			w.Header().Set(SwarmChallengeHeader, c.Value)
+			w.Header().Set(SwarmChallengeDifficultyHeader, strconv.Itoa(int(c.Difficulty)))
+			w.Header().Add("Access-Control-Expose-Headers", SwarmChallengeHeader+", "+SwarmChallengeDifficultyHeader)
+			jsonhttp.Forbidden(w, "challenge required")
+		})
+	}
+}
+
+// challengeGetHandler issues a new challenge.
+func (s *Service) challengeGetHandler(w http.ResponseWriter, _ *http.Request) {
+	logger := s.logger.WithName("get_challenge").Build()
+
+	if s.challenge == nil {
+		jsonhttp.NotImplemented(w, "challenges are not enabled")
+		return
+	}
+
+	c, err := s.challenge.Issue()
+	if err != nil {
+		logger.Debug("issue challenge failed", "error", err)
+		logger.Error(nil, "issue challenge failed")
+		jsonhttp.InternalServerError(w, "issue challenge failed")
+		return
+	}
+
+	jsonhttp.OK(w, challengeResponse{
+		Challenge:  c.Value,
+		Difficulty: c.Difficulty,
+		Expires:    c.Expires,
+	})
+}
+
+// challengePostHandler redeems the solved challenge for a pass token,
+// which is returned in the response body and in the cookie.
+func (s *Service) challengePostHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_challenge").Build()
+
+	if s.challenge == nil {
+		jsonhttp.NotImplemented(w, "challenges are not enabled")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		if jsonhttp.HandleBodyReadError(err, w) {
+			return
+		}
+		logger.Debug("read request body failed", "error", err)
+		logger.Error(nil, "read request body failed")
+		jsonhttp.InternalServerError(w, "cannot read request")
+		return
+	}
+
+	var req challengeRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		logger.Debug("unmarshal challenge failed", "error", err)
+		logger.Error(nil, "unmarshal challenge failed")
+		jsonhttp.BadRequest(w, "invalid challenge")
+		return
+	}
+
+	token, expires, err := s.challenge.Redeem(req.Challenge, req.Solution)
+	if err != nil {
+		logger.Debug("redeem challenge failed", "error", err)
+		switch {
+		case errors.Is(err, challenge.ErrInvalidChallenge):
+			jsonhttp.BadRequest(w, "invalid challenge")
+		case errors.Is(err, challenge.ErrInsufficientWork):
+			jsonhttp.BadRequest(w, "insufficient work")
+		default:
+			logger.Error(nil, "redeem challenge failed")
+			jsonhttp.InternalServerError(w, "redeem challenge failed")
+		}
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     challengeTokenCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	jsonhttp.Created(w, challengeTokenResponse{
+		Token:   token,
+		Expires: expires,
+	})
+}
+
+// remoteIP returns the IP address of the client connection. The forwarding
+// headers are not trusted, as they are set by the clients themselves.
+func remoteIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
@@ -0,0 +1,180 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/challenge"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestChallenge(t *testing.T) {
+	t.Parallel()
+
+	newClient := func(t *testing.T, o challenge.Options) *http.Client {
+		t.Helper()
+
+		o.Routes = []string{"bytes"}
+		c, err := challenge.New(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			Storer:    mock.NewStorer(),
+			Tags:      tags.NewTags(statestore.NewStateStore(), log.Noop),
+			Logger:    log.Noop,
+			Post:      mockpost.New(mockpost.WithAcceptAll()),
+			Challenge: c,
+		})
+		return client
+	}
+
+	upload := func(t *testing.T, client *http.Client, data []byte) string {
+		t.Helper()
+
+		var res api.BytesPostResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		return "/bytes/" + res.Reference.String()
+	}
+
+	redeem := func(t *testing.T, client *http.Client) (string, http.Header) {
+		t.Helper()
+
+		var c api.ChallengeResponse
+		jsonhttptest.Request(t, client, http.MethodGet, "/challenge", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&c),
+		)
+		solution, err := challenge.Solve(context.Background(), c.Challenge, c.Difficulty)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var res api.ChallengeTokenResponse
+		header := jsonhttptest.Request(t, client, http.MethodPost, "/challenge", http.StatusCreated,
+			jsonhttptest.WithJSONRequestBody(api.ChallengeRequest{Challenge: c.Challenge, Solution: solution}),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		return res.Token, header
+	}
+
+	t.Run("proof of work", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t, challenge.Options{Mode: challenge.ModePoW, Difficulty: 8})
+		data := []byte("protected content")
+		path := upload(t, client, data)
+
+		header := jsonhttptest.Request(t, client, http.MethodGet, path, http.StatusForbidden,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "challenge required",
+				Code:    http.StatusForbidden,
+			}),
+			jsonhttptest.WithExpectedResponseHeader(api.SwarmChallengeDifficultyHeader, "8"),
+		)
+		difficulty, err := strconv.Atoi(header.Get(api.SwarmChallengeDifficultyHeader))
+		if err != nil {
+			t.Fatal(err)
+		}
+		solution, err := challenge.Solve(context.Background(), header.Get(api.SwarmChallengeHeader), uint8(difficulty))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var res api.ChallengeTokenResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/challenge", http.StatusCreated,
+			jsonhttptest.WithJSONRequestBody(api.ChallengeRequest{Challenge: header.Get(api.SwarmChallengeHeader), Solution: solution}),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, path, http.StatusOK,
+			jsonhttptest.WithRequestHeader(api.SwarmChallengeTokenHeader, res.Token),
+			jsonhttptest.WithExpectedResponse(data),
+		)
+	})
+
+	t.Run("cookie", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t, challenge.Options{Mode: challenge.ModeToken})
+		data := []byte("content for browsers")
+		path := upload(t, client, data)
+
+		_, header := redeem(t, client)
+		cookie := (&http.Response{Header: header}).Cookies()
+		if len(cookie) != 1 {
+			t.Fatalf("got %d cookies, want 1", len(cookie))
+		}
+		jsonhttptest.Request(t, client, http.MethodGet, path, http.StatusOK,
+			jsonhttptest.WithRequestHeader("Cookie", cookie[0].String()),
+			jsonhttptest.WithExpectedResponse(data),
+		)
+	})
+
+	t.Run("insufficient work", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t, challenge.Options{Mode: challenge.ModePoW, Difficulty: 24})
+
+		var c api.ChallengeResponse
+		jsonhttptest.Request(t, client, http.MethodGet, "/challenge", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&c),
+		)
+		jsonhttptest.Request(t, client, http.MethodPost, "/challenge", http.StatusBadRequest,
+			jsonhttptest.WithJSONRequestBody(api.ChallengeRequest{Challenge: c.Challenge, Solution: "00"}),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "insufficient work",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t, challenge.Options{Mode: challenge.ModeToken, Rate: 0.001, Burst: 1})
+		path := upload(t, client, []byte("limited content"))
+
+		token, _ := redeem(t, client)
+		jsonhttptest.Request(t, client, http.MethodGet, path, http.StatusOK,
+			jsonhttptest.WithRequestHeader(api.SwarmChallengeTokenHeader, token),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, path, http.StatusTooManyRequests,
+			jsonhttptest.WithRequestHeader(api.SwarmChallengeTokenHeader, token),
+		)
+	})
+
+	t.Run("exempt", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t, challenge.Options{Mode: challenge.ModePoW, Exempt: []string{"127.0.0.0/8", "::1"}})
+		data := []byte("exempt content")
+		path := upload(t, client, data)
+
+		jsonhttptest.Request(t, client, http.MethodGet, path, http.StatusOK,
+			jsonhttptest.WithExpectedResponse(data),
+		)
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{})
+		jsonhttptest.Request(t, client, http.MethodGet, "/challenge", http.StatusNotImplemented)
+	})
+}
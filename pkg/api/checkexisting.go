@@ -0,0 +1,148 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+// checkExistingUpload hashes the upload without storing it, as in the dry
+// run and without a tag, and responds with its reference if its root chunk
+// is already stored locally or in the network, so that the content is
+// neither stamped nor stored again. Otherwise the body of the request is replaced with its copy
+// to be uploaded. The served result is true if the response was written.
+// The returned cleanup function must be called after the upload.
+func (s *Service) checkExistingUpload(logger log.Logger, w http.ResponseWriter, r *http.Request, contentType string) (cleanup func(), served bool) {
+	cleanup = func() {}
+
+	// the references of the encrypted uploads differ for the same content
+	if requestEncrypt(r) {
+		logger.Debug("check existing: encrypted upload")
+		logger.Error(nil, "check existing: encrypted upload")
+		jsonhttp.BadRequest(w, "check existing not supported for encrypted uploads")
+		return cleanup, true
+	}
+
+	// the body is read twice, so that it is spooled to a temporary file
+	// up to the size limit, as it takes the disk of the node
+	spool, err := os.CreateTemp("", "bee-upload-*")
+	if err != nil {
+		logger.Debug("check existing: create temporary file failed", "error", err)
+		logger.Error(nil, "check existing: create temporary file failed")
+		jsonhttp.InternalServerError(w, "check existing failed")
+		return cleanup, true
+	}
+	cleanup = func() {
+		_ = spool.Close()
+		_ = os.Remove(spool.Name())
+	}
+	maxSize := s.CheckExistingMaxSize
+	if maxSize <= 0 {
+		maxSize = defaultCheckExistingMaxSize
+	}
+	if _, err := io.Copy(spool, http.MaxBytesReader(w, r.Body, maxSize)); err != nil {
+		if jsonhttp.HandleBodyReadError(err, w) {
+			return cleanup, true
+		}
+		logger.Debug("check existing: read request body failed", "error", err)
+		logger.Error(nil, "check existing: read request body failed")
+		jsonhttp.InternalServerError(w, "cannot read request")
+		return cleanup, true
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		logger.Debug("check existing: rewind temporary file failed", "error", err)
+		logger.Error(nil, "check existing: rewind temporary file failed")
+		jsonhttp.InternalServerError(w, "check existing failed")
+		return cleanup, true
+	}
+
+	dr := r.Clone(r.Context())
+	dr.Body = io.NopCloser(spool)
+	// the progress of the dry run is not sent to the client
+	dr.Header.Del("Accept")
+
+	rec := &bufferedResponseWriter{header: make(http.Header)}
+	putter, wait, dw, err := s.newUploadPutter(rec, dr, true)
+	if err != nil {
+		logger.Debug("check existing: putter failed", "error", err)
+		logger.Error(nil, "check existing: putter failed")
+		jsonhttp.InternalServerError(w, "check existing failed")
+		return cleanup, true
+	}
+	s.bzzStore(logger, dw, dr, contentType, putter, wait)
+
+	if rec.status != http.StatusOK {
+		rec.writeTo(w)
+		return cleanup, true
+	}
+	var resp bzzUploadResponse
+	if err := json.Unmarshal(rec.body.Bytes(), &resp); err != nil {
+		logger.Debug("check existing: unmarshal dry run response failed", "error", err)
+		logger.Error(nil, "check existing: unmarshal dry run response failed")
+		jsonhttp.InternalServerError(w, "check existing failed")
+		return cleanup, true
+	}
+
+	// the root chunk is stored the last, so that the content is
+	// considered stored if its root chunk is found
+	if _, err := s.storer.Get(r.Context(), storage.ModeGetRequest, resp.Reference); err != nil {
+		logger.Debug("check existing: root chunk not found", "address", resp.Reference, "error", err)
+		if _, err := spool.Seek(0, io.SeekStart); err != nil {
+			logger.Debug("check existing: rewind temporary file failed", "error", err)
+			logger.Error(nil, "check existing: rewind temporary file failed")
+			jsonhttp.InternalServerError(w, "check existing failed")
+			return cleanup, true
+		}
+		r.Body = io.NopCloser(spool)
+		return cleanup, false
+	}
+
+	jsonhttp.OK(w, bzzUploadResponse{
+		Reference:     resp.Reference,
+		AlreadyStored: true,
+	})
+	return cleanup, true
+}
+
+// bufferedResponseWriter buffers the response instead of writing it.
+type bufferedResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (bw *bufferedResponseWriter) Header() http.Header {
+	return bw.header
+}
+
+func (bw *bufferedResponseWriter) WriteHeader(code int) {
+	if bw.status == 0 {
+		bw.status = code
+	}
+}
+
+func (bw *bufferedResponseWriter) Write(b []byte) (int, error) {
+	if bw.status == 0 {
+		bw.status = http.StatusOK
+	}
+	return bw.body.Write(b)
+}
+
+// writeTo writes the buffered response to w.
+func (bw *bufferedResponseWriter) writeTo(w http.ResponseWriter) {
+	for name, values := range bw.header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(bw.status)
+	_, _ = w.Write(bw.body.Bytes())
+}
@@ -0,0 +1,188 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestBzzCheckExisting(t *testing.T) {
+	t.Parallel()
+
+	storer := smock.NewStorer()
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: storer,
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte("content uploaded twice")
+
+		// the content is uploaded if it is not stored yet
+		var first api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=file.txt&check-existing=true", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader("Content-Type", "text/plain"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+			jsonhttptest.WithUnmarshalJSONResponse(&first),
+		)
+		if first.AlreadyStored {
+			t.Fatal("got already stored on the first upload")
+		}
+		if _, err := storer.Get(context.Background(), storage.ModeGetRequest, first.Reference); err != nil {
+			t.Fatalf("root chunk not stored: %v", err)
+		}
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+first.Reference.String()+"/", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(data),
+		)
+
+		// the existing content is neither stamped nor stored
+		// again, so that no postage batch is needed
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=file.txt&check-existing=true", http.StatusOK,
+			jsonhttptest.WithRequestHeader("Content-Type", "text/plain"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+			jsonhttptest.WithExpectedJSONResponse(api.BzzUploadResponse{
+				Reference:     first.Reference,
+				AlreadyStored: true,
+			}),
+		)
+
+		// the content not stored yet is uploaded, which needs the batch
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=other.txt&check-existing=true", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader("Content-Type", "text/plain"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+		)
+	})
+
+	t.Run("collection", func(t *testing.T) {
+		t.Parallel()
+
+		files := []f{
+			{data: []byte("<h1>Swarm"), name: "index.html", header: http.Header{"Content-Type": {"text/html; charset=utf-8"}}},
+			{data: []byte("body {}"), name: "style.css", dir: "css", header: http.Header{"Content-Type": {"text/css"}}},
+		}
+
+		var first api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?check-existing=true", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestBody(tarFiles(t, files)),
+			jsonhttptest.WithUnmarshalJSONResponse(&first),
+		)
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?check-existing=true", http.StatusOK,
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestBody(tarFiles(t, files)),
+			jsonhttptest.WithExpectedJSONResponse(api.BzzUploadResponse{
+				Reference:     first.Reference,
+				AlreadyStored: true,
+			}),
+		)
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=file.txt&check-existing=true", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmEncryptHeader, "true"),
+			jsonhttptest.WithRequestHeader("Content-Type", "text/plain"),
+			jsonhttptest.WithRequestBody(bytes.NewReader([]byte("encrypted"))),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "check existing not supported for encrypted uploads",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("dry run error", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?check-existing=true", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestBody(bytes.NewReader(nil)),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: api.EmptyDir.Error(),
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
+
+func TestBzzCheckExistingTag(t *testing.T) {
+	t.Parallel()
+
+	tagsSvc := tags.NewTags(statestore.NewStateStore(), log.Noop)
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: smock.NewStorer(),
+		Tags:   tagsSvc,
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	data := []byte("content checked without a tag")
+	for _, status := range []int{http.StatusCreated, http.StatusOK} {
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=file.txt&check-existing=true", status,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader("Content-Type", "text/plain"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+		)
+	}
+
+	// only the upload of the content not stored yet has a tag
+	count, err := tagsSvc.Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("got %d tags, want 1", count)
+	}
+}
+
+func TestBzzCheckExistingMaxSize(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:               smock.NewStorer(),
+		Tags:                 tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger:               log.Noop,
+		Post:                 mockpost.New(mockpost.WithAcceptAll()),
+		CheckExistingMaxSize: 8,
+	})
+
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=file.txt&check-existing=true", http.StatusRequestEntityTooLarge,
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader("Content-Type", "text/plain"),
+		jsonhttptest.WithRequestBody(bytes.NewReader([]byte("larger than the limit"))),
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: http.StatusText(http.StatusRequestEntityTooLarge),
+			Code:    http.StatusRequestEntityTooLarge,
+		}),
+	)
+}
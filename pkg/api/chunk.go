@@ -33,18 +33,9 @@ func (s *Service) processUploadRequest(
 	logger log.Logger, r *http.Request,
 ) (ctx context.Context, tag *tags.Tag, putter storage.Putter, waitFn func() error, err error) {
 
-	if str := r.Header.Get(SwarmTagHeader); str != "" {
-		tag, err = s.getTag(str)
-		if err != nil {
-			logger.Debug("get tag failed", "string", str, "error", err)
-			logger.Error(nil, "get tag failed", "string", str)
-			return nil, nil, nil, nil, errors.New("cannot get tag")
-		}
-
-		// add the tag to the context if it exists
-		ctx = sctx.SetTag(r.Context(), tag)
-	} else {
-		ctx = r.Context()
+	ctx, tag, err = s.processUploadTag(logger, r)
+	if err != nil {
+		return nil, nil, nil, nil, err
 	}
 
 	putter, wait, err := s.newStamperPutter(r)
@@ -57,6 +48,36 @@ func (s *Service) processUploadRequest(
 	return ctx, tag, putter, wait, nil
 }
 
+// processUploadTag returns the tag of the upload request, if there is one,
+// and the request context with the tag. The tag is created if only its name
+// is given.
+func (s *Service) processUploadTag(logger log.Logger, r *http.Request) (context.Context, *tags.Tag, error) {
+	str := r.Header.Get(SwarmTagHeader)
+	if str == "" {
+		name := r.Header.Get(SwarmTagNameHeader)
+		if name == "" {
+			return r.Context(), nil, nil
+		}
+		tag, err := s.tags.CreateNamed(0, name, nil)
+		if err != nil {
+			logger.Debug("create tag failed", "name", name, "error", err)
+			logger.Error(nil, "create tag failed")
+			return nil, nil, fmt.Errorf("cannot create tag: %w", err)
+		}
+		return sctx.SetTag(r.Context(), tag), tag, nil
+	}
+
+	tag, err := s.getTag(str)
+	if err != nil {
+		logger.Debug("get tag failed", "string", str, "error", err)
+		logger.Error(nil, "get tag failed", "string", str)
+		return nil, nil, errors.New("cannot get tag")
+	}
+
+	// add the tag to the context if it exists
+	return sctx.SetTag(r.Context(), tag), tag, nil
+}
+
 func (s *Service) chunkUploadHandler(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.WithName("post_chunk").Build()
 
@@ -175,7 +196,7 @@ func (s *Service) chunkUploadHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Access-Control-Expose-Headers", SwarmTagHeader)
+	w.Header().Add("Access-Control-Expose-Headers", SwarmTagHeader)
 	jsonhttp.Created(w, chunkAddressResponse{Reference: chunk.Address()})
 }
 
@@ -186,11 +207,17 @@ func (s *Service) chunkGetHandler(w http.ResponseWriter, r *http.Request) {
 	paths := struct {
 		Address swarm.Address `map:"address,resolve" validate:"required"`
 	}{}
-	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+	if response := s.mapStructureContext(r.Context(), mux.Vars(r), &paths); response != nil {
 		response("invalid path params", logger, w)
 		return
 	}
 
+	release, ok := s.acquireDownload(w)
+	if !ok {
+		return
+	}
+	defer release()
+
 	chunk, err := s.storer.Get(r.Context(), storage.ModeGetRequest, paths.Address)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
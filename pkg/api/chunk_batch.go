@@ -0,0 +1,183 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const (
+	// maxChunkBatchSize is the maximum number of the addresses in a batch request.
+	maxChunkBatchSize = 256
+	// maxChunkBatchBodySize is the limit of the batch request body,
+	// which fits maxChunkBatchSize hex encoded addresses.
+	maxChunkBatchBodySize = 32 * 1024
+	// chunkBatchConcurrency is the number of the chunks of a batch
+	// which are retrieved at the same time.
+	chunkBatchConcurrency = 16
+)
+
+// The flags of the frame of a chunk in the batch response.
+const (
+	chunkBatchFlagFound byte = 1 << iota
+	chunkBatchFlagStamp
+)
+
+type chunkBatchRequest struct {
+	Addresses []swarm.Address `json:"addresses"`
+}
+
+type chunkBatchResult struct {
+	chunk swarm.Chunk
+	err   error
+}
+
+// chunkBatchGetHandler streams the chunks with the addresses from the request
+// body in the same order. Every chunk is written as a frame of:
+//
+//	address (32 bytes) | flags (1 byte) |
+//	[ data length (4 bytes, big endian) | data ] if the chunk is found |
+//	[ stamp (113 bytes) ] if the stamp is included
+//
+// The missing chunks are written with the found flag unset, so that the
+// client can fall back to the other sources for them.
+func (s *Service) chunkBatchGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_chunks_batch").Build()
+	loggerV1 := logger.V(1).Build()
+
+	queries := struct {
+		Stamps bool `map:"stamps"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		if jsonhttp.HandleBodyReadError(err, w) {
+			return
+		}
+		logger.Debug("read request body failed", "error", err)
+		logger.Error(nil, "read request body failed")
+		jsonhttp.InternalServerError(w, "cannot read request")
+		return
+	}
+
+	var req chunkBatchRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		logger.Debug("unmarshal addresses failed", "error", err)
+		logger.Error(nil, "unmarshal addresses failed")
+		jsonhttp.BadRequest(w, "invalid addresses")
+		return
+	}
+	switch n := len(req.Addresses); {
+	case n == 0:
+		jsonhttp.BadRequest(w, "no addresses")
+		return
+	case n > maxChunkBatchSize:
+		jsonhttp.BadRequest(w, "too many addresses")
+		return
+	}
+	for _, addr := range req.Addresses {
+		if len(addr.Bytes()) != swarm.HashSize {
+			jsonhttp.BadRequest(w, "invalid address length")
+			return
+		}
+	}
+
+	release, ok := s.acquireDownload(w)
+	if !ok {
+		return
+	}
+	defer release()
+
+	ctx := r.Context()
+	results := make([]chan chunkBatchResult, len(req.Addresses))
+	for i := range results {
+		results[i] = make(chan chunkBatchResult, 1)
+	}
+	go func() {
+		sem := make(chan struct{}, chunkBatchConcurrency)
+		for i, addr := range req.Addresses {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func(i int, addr swarm.Address) {
+				defer func() { <-sem }()
+				ch, err := s.storer.Get(ctx, storage.ModeGetRequest, addr)
+				results[i] <- chunkBatchResult{chunk: ch, err: err}
+			}(i, addr)
+		}
+	}()
+
+	w.Header().Set("Content-Type", "binary/octet-stream")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	for i, addr := range req.Addresses {
+		var res chunkBatchResult
+		select {
+		case res = <-results[i]:
+		case <-ctx.Done():
+			return
+		}
+
+		if res.err != nil && !errors.Is(res.err, storage.ErrNotFound) {
+			logger.Debug("read chunk failed", "chunk_address", addr, "error", res.err)
+		} else if res.err != nil {
+			loggerV1.Debug("chunk not found", "chunk_address", addr)
+		}
+
+		frame, err := chunkBatchFrame(addr, res, queries.Stamps)
+		if err != nil {
+			logger.Debug("marshal stamp failed", "chunk_address", addr, "error", err)
+			logger.Error(nil, "marshal stamp failed")
+			return
+		}
+		if _, err := w.Write(frame); err != nil {
+			loggerV1.Debug("write chunk failed", "chunk_address", addr, "error", err)
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// chunkBatchFrame returns the frame of the chunk retrieval result.
+func chunkBatchFrame(addr swarm.Address, res chunkBatchResult, withStamp bool) ([]byte, error) {
+	frame := append(make([]byte, 0, swarm.HashSize+1), addr.Bytes()...)
+	if res.err != nil || res.chunk == nil {
+		return append(frame, 0), nil
+	}
+
+	flags := chunkBatchFlagFound
+	var stamp []byte
+	if withStamp && res.chunk.Stamp() != nil {
+		b, err := res.chunk.Stamp().MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		flags |= chunkBatchFlagStamp
+		stamp = b
+	}
+
+	data := res.chunk.Data()
+	frame = append(frame, flags)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(len(data)))
+	frame = append(frame, data...)
+	return append(frame, stamp...), nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	testingc "github.com/ethersphere/bee/pkg/storage/testing"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestChunkBatch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		storer          = mock.NewStorer()
+		client, _, _, _ = newTestServer(t, testServerOptions{Storer: storer})
+		chunks          = testingc.GenerateTestRandomChunks(2)
+		missing         = testingc.GenerateTestRandomChunk()
+	)
+	if _, err := storer.Put(context.Background(), storage.ModePutUpload, chunks...); err != nil {
+		t.Fatal(err)
+	}
+
+	body := func(t *testing.T, addrs ...swarm.Address) []byte {
+		t.Helper()
+
+		b, err := json.Marshal(struct {
+			Addresses []swarm.Address `json:"addresses"`
+		}{addrs})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	frame := func(t *testing.T, ch swarm.Chunk, found, withStamp bool) []byte {
+		t.Helper()
+
+		b := append([]byte(nil), ch.Address().Bytes()...)
+		if !found {
+			return append(b, 0)
+		}
+		flags := byte(1)
+		if withStamp {
+			flags |= 2
+		}
+		b = append(b, flags)
+		b = binary.BigEndian.AppendUint32(b, uint32(len(ch.Data())))
+		b = append(b, ch.Data()...)
+		if withStamp {
+			stamp, err := ch.Stamp().MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			b = append(b, stamp...)
+		}
+		return b
+	}
+
+	t.Run("chunks", func(t *testing.T) {
+		t.Parallel()
+
+		want := bytes.Join([][]byte{
+			frame(t, chunks[0], true, false),
+			frame(t, missing, false, false),
+			frame(t, chunks[1], true, false),
+		}, nil)
+		jsonhttptest.Request(t, client, http.MethodPost, "/chunks/batch", http.StatusOK,
+			jsonhttptest.WithRequestBody(bytes.NewReader(body(t, chunks[0].Address(), missing.Address(), chunks[1].Address()))),
+			jsonhttptest.WithExpectedResponse(want),
+		)
+	})
+
+	t.Run("with stamps", func(t *testing.T) {
+		t.Parallel()
+
+		want := bytes.Join([][]byte{
+			frame(t, chunks[1], true, true),
+			frame(t, missing, false, true),
+		}, nil)
+		jsonhttptest.Request(t, client, http.MethodPost, "/chunks/batch?stamps=true", http.StatusOK,
+			jsonhttptest.WithRequestBody(bytes.NewReader(body(t, chunks[1].Address(), missing.Address()))),
+			jsonhttptest.WithExpectedResponse(want),
+		)
+	})
+
+	t.Run("no addresses", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/chunks/batch", http.StatusBadRequest,
+			jsonhttptest.WithRequestBody(bytes.NewReader(body(t))),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "no addresses",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("too many addresses", func(t *testing.T) {
+		t.Parallel()
+
+		addrs := make([]swarm.Address, api.MaxChunkBatchSize+1)
+		for i := range addrs {
+			addrs[i] = chunks[0].Address()
+		}
+		jsonhttptest.Request(t, client, http.MethodPost, "/chunks/batch", http.StatusBadRequest,
+			jsonhttptest.WithRequestBody(bytes.NewReader(body(t, addrs...))),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "too many addresses",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/chunks/batch", http.StatusBadRequest,
+			jsonhttptest.WithRequestBody(bytes.NewReader([]byte(`{"addresses":["abcd"]}`))),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid address length",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
@@ -8,16 +8,20 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/cac"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/retrieval"
 	"github.com/ethersphere/bee/pkg/sctx"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
 	"github.com/gorilla/websocket"
+	"golang.org/x/time/rate"
 )
 
 const streamReadTimeout = 15 * time.Minute
@@ -27,7 +31,26 @@ var successWsMsg = []byte{}
 func (s *Service) chunkUploadStreamHandler(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.WithName("chunks_stream").Build()
 
-	_, tag, putter, wait, err := s.processUploadRequest(logger, r)
+	setStreamQueryHeaders(r)
+
+	// without the postage batch, the client sends the chunks
+	// which it has already stamped
+	var (
+		tag        *tags.Tag
+		putter     storage.Putter
+		wait       func() error
+		validStamp postage.ValidStampFn
+		err        error
+	)
+	if r.Header.Get(SwarmPostageBatchIdHeader) == "" {
+		_, tag, err = s.processUploadTag(logger, r)
+		if err == nil {
+			putter, wait, err = s.newStampedPutter(r)
+		}
+		validStamp = postage.ValidStamp(s.batchStore)
+	} else {
+		_, tag, putter, wait, err = s.processUploadRequest(logger, r)
+	}
 	if err != nil {
 		jsonhttp.BadRequest(w, err.Error())
 		return
@@ -61,9 +84,35 @@ func (s *Service) chunkUploadStreamHandler(w http.ResponseWriter, r *http.Reques
 		requestModePut(r),
 		requestPin(r),
 		wait,
+		validStamp,
 	)
 }
 
+// streamQueryHeaders are the headers of the upload stream request which
+// can also be given as the query parameters, as the browsers can not set
+// the headers of the WebSocket requests.
+var streamQueryHeaders = []string{
+	SwarmPostageBatchIdHeader,
+	SwarmTagHeader,
+	SwarmTagNameHeader,
+	SwarmPinHeader,
+	SwarmDeferredUploadHeader,
+}
+
+// setStreamQueryHeaders sets the headers of the request which are
+// given only as the query parameters.
+func setStreamQueryHeaders(r *http.Request) {
+	query := r.URL.Query()
+	for _, h := range streamQueryHeaders {
+		if r.Header.Get(h) != "" {
+			continue
+		}
+		if v := query.Get(strings.ToLower(h)); v != "" {
+			r.Header.Set(h, v)
+		}
+	}
+}
+
 func (s *Service) handleUploadStream(
 	ctx context.Context,
 	conn *websocket.Conn,
@@ -72,6 +121,7 @@ func (s *Service) handleUploadStream(
 	mode storage.ModePut,
 	pin bool,
 	wait func() error,
+	validStamp postage.ValidStampFn,
 ) {
 	defer s.wsWg.Done()
 
@@ -161,9 +211,22 @@ func (s *Service) handleUploadStream(
 			}
 		}
 
+		// the stamped chunks are prefixed with their stamps
+		var stamp []byte
+		if validStamp != nil {
+			if len(msg) < postage.StampSize {
+				s.logger.Debug("chunk upload stream: insufficient data")
+				s.logger.Error(nil, "chunk upload stream: insufficient data")
+				sendErrorClose(websocket.CloseUnsupportedData, "insufficient data")
+				return
+			}
+			stamp, msg = msg[:postage.StampSize], msg[postage.StampSize:]
+		}
+
 		if len(msg) < swarm.SpanSize {
 			s.logger.Debug("chunk upload stream: insufficient data")
 			s.logger.Error(nil, "chunk upload stream: insufficient data")
+			sendErrorClose(websocket.CloseUnsupportedData, "insufficient data")
 			return
 		}
 
@@ -171,9 +234,21 @@ func (s *Service) handleUploadStream(
 		if err != nil {
 			s.logger.Debug("chunk upload stream: create chunk failed", "error", err)
 			s.logger.Error(nil, "chunk upload stream: create chunk failed")
+			sendErrorClose(websocket.CloseUnsupportedData, "invalid chunk")
 			return
 		}
 
+		if validStamp != nil {
+			stamped, err := validStamp(chunk, stamp)
+			if err != nil {
+				s.logger.Debug("chunk upload stream: invalid stamp", "address", chunk.Address(), "error", err)
+				s.logger.Error(nil, "chunk upload stream: invalid stamp")
+				sendErrorClose(websocket.CloseUnsupportedData, "invalid stamp")
+				return
+			}
+			chunk = stamped
+		}
+
 		seen, err := putter.Put(ctx, mode, chunk)
 		if err != nil {
 			s.logger.Debug("chunk upload stream: write chunk failed", "address", chunk.Address(), "error", err)
@@ -230,3 +305,214 @@ func (s *Service) handleUploadStream(
 		}
 	}
 }
+
+// chunkDownloadStreamInflight is the number of the requested chunks of the
+// download stream which are not sent yet. The addresses are not read from
+// the connection while as many chunks are waited for, which throttles the
+// client to the pace at which it reads the chunks.
+const chunkDownloadStreamInflight = 64
+
+// maxChunkDownloadStreams is the number of the download streams which are
+// open on the node at once.
+const maxChunkDownloadStreams = 64
+
+// chunkDownloadStreamHandler streams the chunks with the addresses which the
+// client sends on the WebSocket connection. Each binary message holds one or
+// more addresses of 32 bytes, and each chunk is sent back as a binary message
+// of the chunk batch frame, in the order of the requested addresses. The
+// stream takes the place of a download for as long as it is open, and the
+// chunks are sent within the bandwidth of the client.
+func (s *Service) chunkDownloadStreamHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("chunks_stream_download").Build()
+
+	queries := struct {
+		Stamps bool `map:"stamps"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	select {
+	case s.downloadStreams <- struct{}{}:
+	default:
+		s.metrics.LimitedDownloads.Inc()
+		w.Header().Set("Retry-After", strconv.Itoa(int(downloadRetryAfter.Seconds())))
+		jsonhttp.TooManyRequests(w, "too many download streams")
+		return
+	}
+	releaseStream := func() { <-s.downloadStreams }
+
+	release, ok := s.acquireDownload(w)
+	if !ok {
+		releaseStream()
+		return
+	}
+
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  swarm.ChunkSize,
+		WriteBufferSize: swarm.ChunkSize,
+		CheckOrigin:     s.checkOrigin,
+	}
+
+	c, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		release()
+		releaseStream()
+		logger.Debug("chunk download: upgrade failed", "error", err)
+		logger.Error(nil, "chunk download: upgrade failed")
+		jsonhttp.BadRequest(w, "upgrade failed")
+		return
+	}
+
+	// the retrievals outlive the request, keeping its retrieval classes,
+	// and are canceled when the stream is closed
+	cctx := retrieval.WithMode(context.Background(), retrieval.ModeFromContext(r.Context()))
+	cctx = retrieval.WithPriority(cctx, retrieval.PriorityFromContext(r.Context()))
+	limiter := s.downloadLimiter.client(remoteIP(r).String())
+
+	s.wsWg.Add(1)
+	go func() {
+		defer releaseStream()
+		defer release()
+		s.handleDownloadStream(cctx, c, limiter, queries.Stamps)
+	}()
+}
+
+// downloadStreamRequest is a requested chunk of the download stream.
+type downloadStreamRequest struct {
+	addr   swarm.Address
+	result chan chunkBatchResult
+}
+
+func (s *Service) handleDownloadStream(ctx context.Context, conn *websocket.Conn, limiter *rate.Limiter, withStamps bool) {
+	defer s.wsWg.Done()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer func() { _ = conn.Close() }()
+
+	loggerV1 := s.logger.V(1).Build()
+
+	sendErrorClose := func(code int, errmsg string) {
+		err := conn.WriteControl(
+			websocket.CloseMessage,
+			websocket.FormatCloseMessage(code, errmsg),
+			time.Now().Add(writeDeadline),
+		)
+		if err != nil {
+			s.logger.Error(err, "chunk download stream: failed sending close message")
+		}
+	}
+
+	// the requests are read and retrieved by the reader goroutine
+	// and sent in their order by the writer loop below
+	requests := make(chan downloadStreamRequest, chunkDownloadStreamInflight)
+	go func() {
+		defer close(requests)
+
+		sem := make(chan struct{}, chunkBatchConcurrency)
+		for {
+			if err := conn.SetReadDeadline(time.Now().Add(streamReadTimeout)); err != nil {
+				s.logger.Debug("chunk download stream: set read deadline failed", "error", err)
+				s.logger.Error(nil, "chunk download stream: set read deadline failed")
+				return
+			}
+
+			mt, msg, err := conn.ReadMessage()
+			if err != nil {
+				if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+					s.logger.Debug("chunk download stream: read message failed", "error", err)
+					s.logger.Error(nil, "chunk download stream: read message failed")
+				}
+				return
+			}
+
+			if mt != websocket.BinaryMessage || len(msg) == 0 || len(msg)%swarm.HashSize != 0 {
+				s.logger.Debug("chunk download stream: unexpected message received from client", "message_type", mt, "size", len(msg))
+				s.logger.Error(nil, "chunk download stream: unexpected message received from client")
+				sendErrorClose(websocket.CloseUnsupportedData, "invalid message")
+				cancel()
+				return
+			}
+
+			for ; len(msg) > 0; msg = msg[swarm.HashSize:] {
+				req := downloadStreamRequest{
+					addr:   swarm.NewAddress(append([]byte(nil), msg[:swarm.HashSize]...)),
+					result: make(chan chunkBatchResult, 1),
+				}
+				select {
+				case requests <- req:
+				case <-ctx.Done():
+					return
+				}
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				go func() {
+					defer func() { <-sem }()
+					ch, err := s.storer.Get(ctx, storage.ModeGetRequest, req.addr)
+					req.result <- chunkBatchResult{chunk: ch, err: err}
+				}()
+			}
+		}
+	}()
+
+	for {
+		var (
+			req downloadStreamRequest
+			res chunkBatchResult
+			ok  bool
+		)
+		select {
+		case req, ok = <-requests:
+			if !ok {
+				// client gone
+				return
+			}
+		case <-ctx.Done():
+			// invalid message
+			return
+		case <-s.quit:
+			sendErrorClose(websocket.CloseGoingAway, "node shutting down")
+			return
+		}
+		select {
+		case res = <-req.result:
+		case <-ctx.Done():
+			return
+		case <-s.quit:
+			sendErrorClose(websocket.CloseGoingAway, "node shutting down")
+			return
+		}
+
+		if res.err != nil && !errors.Is(res.err, storage.ErrNotFound) {
+			s.logger.Debug("chunk download stream: read chunk failed", "chunk_address", req.addr, "error", res.err)
+		} else if res.err != nil {
+			loggerV1.Debug("chunk download stream: chunk not found", "chunk_address", req.addr)
+		}
+
+		frame, err := chunkBatchFrame(req.addr, res, withStamps)
+		if err != nil {
+			s.logger.Debug("chunk download stream: marshal stamp failed", "chunk_address", req.addr, "error", err)
+			s.logger.Error(nil, "chunk download stream: marshal stamp failed")
+			sendErrorClose(websocket.CloseInternalServerErr, "marshal stamp failed")
+			return
+		}
+
+		if err := waitBandwidth(ctx, limiter, len(frame)); err != nil {
+			loggerV1.Debug("chunk download stream: wait for bandwidth failed", "error", err)
+			return
+		}
+		if err := conn.SetWriteDeadline(time.Now().Add(writeDeadline)); err != nil {
+			loggerV1.Debug("chunk download stream: set write deadline failed", "error", err)
+			return
+		}
+		if err := conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+			loggerV1.Debug("chunk download stream: sending chunk failed", "chunk_address", req.addr, "error", err)
+			return
+		}
+	}
+}
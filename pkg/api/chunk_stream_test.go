@@ -7,14 +7,23 @@ package api_test
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
+	"math/big"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/log"
 	pinning "github.com/ethersphere/bee/pkg/pinning/mock"
+	"github.com/ethersphere/bee/pkg/postage"
+	mockbatchstore "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
 	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/storage/mock"
@@ -118,3 +127,267 @@ func TestChunkUploadStream(t *testing.T) {
 		}
 	})
 }
+
+func TestChunkUploadStreamStamped(t *testing.T) {
+	t.Parallel()
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		batch         = postagetesting.MustNewBatch(postagetesting.WithOwner(owner.Bytes()))
+		storerMock    = mock.NewStorer()
+		_, _, addr, _ = newTestServer(t, testServerOptions{
+			Storer:     storerMock,
+			Tags:       tags.NewTags(statestore.NewStateStore(), log.Noop),
+			BatchStore: mockbatchstore.New(mockbatchstore.WithBatch(batch), mockbatchstore.WithAcceptAllExistsFunc()),
+		})
+		issuer  = postage.NewStampIssuer("label", "keyID", batch.ID, big.NewInt(3), batch.Depth, batch.BucketDepth, 1000, true)
+		stamper = postage.NewStamper(issuer, signer)
+	)
+
+	dial := func(t *testing.T) *websocket.Conn {
+		t.Helper()
+
+		// the headers are given as the query parameters, as by the browsers
+		u := url.URL{Scheme: "ws", Host: addr, Path: "/chunks/stream", RawQuery: "swarm-deferred-upload=true"}
+		conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = conn.Close() })
+		return conn
+	}
+
+	send := func(t *testing.T, conn *websocket.Conn, stamp *postage.Stamp, ch swarm.Chunk) (int, []byte, error) {
+		t.Helper()
+
+		b, err := stamp.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.SetWriteDeadline(time.Now().Add(time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.WriteMessage(websocket.BinaryMessage, append(b, ch.Data()...)); err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		return conn.ReadMessage()
+	}
+
+	t.Run("upload and verify", func(t *testing.T) {
+		t.Parallel()
+
+		conn := dial(t)
+		for i := 0; i < 5; i++ {
+			ch := testingc.GenerateTestRandomChunk()
+			stamp, err := stamper.Stamp(ch.Address())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			mt, msg, err := send(t, conn, stamp, ch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mt != websocket.BinaryMessage || !bytes.Equal(msg, api.SuccessWsMsg) {
+				t.Fatal("invalid response", mt, string(msg))
+			}
+
+			got, err := storerMock.Get(context.Background(), storage.ModeGetRequest, ch.Address())
+			if err != nil {
+				t.Fatal("failed to get chunk after upload", err)
+			}
+			if !bytes.Equal(got.Data(), ch.Data()) || !bytes.Equal(got.Stamp().Sig(), stamp.Sig()) {
+				t.Fatal("invalid chunk read")
+			}
+		}
+	})
+
+	t.Run("close on invalid stamp", func(t *testing.T) {
+		t.Parallel()
+
+		ch := testingc.GenerateTestRandomChunk()
+		stamp, err := stamper.Stamp(ch.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the stamp is not valid for the other chunk
+		_, _, err = send(t, dial(t), stamp, testingc.GenerateTestRandomChunk())
+		// nolint:errorlint
+		if cerr, ok := err.(*websocket.CloseError); !ok {
+			t.Fatalf("got error %v, want close error", err)
+		} else if cerr.Text != "invalid stamp" {
+			t.Fatalf("incorrect response on error, exp: (invalid stamp) got (%s)", cerr.Text)
+		}
+	})
+}
+
+func TestChunkDownloadStream(t *testing.T) {
+	t.Parallel()
+
+	var (
+		storerMock    = mock.NewStorer()
+		_, _, addr, _ = newTestServer(t, testServerOptions{
+			Storer: storerMock,
+			Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		})
+		chunks = []swarm.Chunk{
+			testingc.GenerateTestRandomChunk(),
+			testingc.GenerateTestRandomChunk(),
+		}
+		missing = testingc.GenerateTestRandomChunk().Address()
+	)
+	for _, ch := range chunks {
+		if _, err := storerMock.Put(context.Background(), storage.ModePutUpload, ch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dial := func(t *testing.T) *websocket.Conn {
+		t.Helper()
+
+		u := url.URL{Scheme: "ws", Host: addr, Path: "/chunks/stream/download"}
+		conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = conn.Close() })
+		return conn
+	}
+
+	send := func(t *testing.T, conn *websocket.Conn, mt int, msg []byte) {
+		t.Helper()
+
+		if err := conn.SetWriteDeadline(time.Now().Add(time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.WriteMessage(mt, msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("download in order", func(t *testing.T) {
+		t.Parallel()
+
+		conn := dial(t)
+		// the addresses are sent one per message and several in one message
+		send(t, conn, websocket.BinaryMessage, chunks[1].Address().Bytes())
+		send(t, conn, websocket.BinaryMessage, append(append([]byte(nil), missing.Bytes()...), chunks[0].Address().Bytes()...))
+
+		want := []struct {
+			addr  swarm.Address
+			chunk swarm.Chunk
+		}{
+			{chunks[1].Address(), chunks[1]},
+			{missing, nil},
+			{chunks[0].Address(), chunks[0]},
+		}
+		for _, w := range want {
+			if err := conn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+				t.Fatal(err)
+			}
+			mt, msg, err := conn.ReadMessage()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mt != websocket.BinaryMessage {
+				t.Fatalf("got message type %d, want binary", mt)
+			}
+
+			wantFrame := append(append([]byte(nil), w.addr.Bytes()...), 0)
+			if w.chunk != nil {
+				wantFrame = append(append([]byte(nil), w.addr.Bytes()...), 1)
+				wantFrame = binary.BigEndian.AppendUint32(wantFrame, uint32(len(w.chunk.Data())))
+				wantFrame = append(wantFrame, w.chunk.Data()...)
+			}
+			if !bytes.Equal(msg, wantFrame) {
+				t.Fatalf("invalid frame of chunk %s", w.addr)
+			}
+		}
+	})
+
+	t.Run("close on invalid address", func(t *testing.T) {
+		t.Parallel()
+
+		conn := dial(t)
+		send(t, conn, websocket.BinaryMessage, []byte("short address"))
+
+		if err := conn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		_, _, err := conn.ReadMessage()
+		// nolint:errorlint
+		if cerr, ok := err.(*websocket.CloseError); !ok {
+			t.Fatalf("got error %v, want close error", err)
+		} else if cerr.Text != "invalid message" {
+			t.Fatalf("incorrect response on error, exp: (invalid message) got (%s)", cerr.Text)
+		}
+	})
+}
+
+func TestChunkDownloadStreamLimits(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chunk              = testingc.GenerateTestRandomChunk()
+		body               = `{"addresses":["` + chunk.Address().String() + `"]}`
+		client, _, addr, _ = newTestServer(t, testServerOptions{
+			Storer:          mock.NewStorer(),
+			Tags:            tags.NewTags(statestore.NewStateStore(), log.Noop),
+			DownloadLimiter: api.NewDownloadLimiter(api.DownloadLimits{MaxDownloads: 1}),
+		})
+		u = url.URL{Scheme: "ws", Host: addr, Path: "/chunks/stream/download"}
+	)
+
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the open stream takes the place of a download
+	_, resp, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err == nil {
+		t.Fatal("stream opened over the download limit")
+	}
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("got response %v, want status %d", resp, http.StatusTooManyRequests)
+	}
+	jsonhttptest.Request(t, client, http.MethodPost, "/chunks/batch", http.StatusTooManyRequests,
+		jsonhttptest.WithRequestBody(strings.NewReader(body)),
+	)
+
+	// the download is released once the stream is closed
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; ; i++ {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/chunks/batch", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
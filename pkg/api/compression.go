@@ -0,0 +1,137 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// DefaultCompressionContentTypes are the text-like content types of the
+// static websites which are compressed by default.
+const DefaultCompressionContentTypes = "text/,application/javascript,application/json,application/xml,application/wasm,image/svg+xml"
+
+// DefaultCompressionMinSize is the default size of the smallest compressed
+// content, as the smaller one hardly gets any smaller with the compression.
+const DefaultCompressionMinSize = 1024
+
+var errInvalidCompressionContentType = errors.New("invalid compression content type")
+
+// CompressionPolicy selects the downloads which are compressed on the fly
+// with the gzip content coding, if the client accepts it. The range requests
+// are served uncompressed. The brotli content coding is served only for the
+// precompressed variants uploaded with the collections, as the compression
+// on the fly needs a brotli encoder which is not a dependency of the node.
+type CompressionPolicy struct {
+	// ContentTypes are the media types, such as text/html, or the type
+	// prefixes, such as text/, of the compressed content.
+	ContentTypes []string
+	// MinSize is the size of the smallest compressed content.
+	MinSize int64
+}
+
+// ParseCompressionPolicy parses the comma separated content types, which
+// are the media types or the type prefixes ending with a slash, as in
+// text/,application/json. The nil policy, which compresses nothing, is
+// returned for the empty content types.
+func ParseCompressionPolicy(contentTypes string, minSize int64) (*CompressionPolicy, error) {
+	if minSize < 0 {
+		return nil, fmt.Errorf("invalid compression min size %d", minSize)
+	}
+	var p *CompressionPolicy
+	for _, v := range strings.Split(contentTypes, ",") {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v == "" {
+			continue
+		}
+		if !strings.Contains(v, "/") {
+			return nil, fmt.Errorf("%w: %q", errInvalidCompressionContentType, v)
+		}
+		if p == nil {
+			p = &CompressionPolicy{MinSize: minSize}
+		}
+		p.ContentTypes = append(p.ContentTypes, v)
+	}
+	return p, nil
+}
+
+// Compresses reports whether the content with the content type and size
+// is compressed.
+func (p *CompressionPolicy) Compresses(contentType string, size int64) bool {
+	if p == nil || size < p.MinSize {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	for _, t := range p.ContentTypes {
+		if strings.HasSuffix(t, "/") && strings.HasPrefix(mediaType, t) || mediaType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsGzip reports whether the client accepts the gzip content coding.
+func acceptsGzip(r *http.Request) bool {
+	accepted := parseAcceptEncoding(r.Header.Get("Accept-Encoding"))
+	q, ok := accepted["gzip"]
+	if !ok {
+		q = accepted["*"]
+	}
+	return q > 0
+}
+
+// gzipETag returns the etag of the compressed representation of the content,
+// which differs from the etag of the original one.
+func gzipETag(reference swarm.Address) string {
+	return fmt.Sprintf("\"%s-gzip\"", reference)
+}
+
+// gzipResponseWriter compresses the body of the successful response. The
+// content length is removed, as the size of the compressed body is not
+// known in advance, and the response is sent chunked.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz       *gzip.Writer
+	compress bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if code == http.StatusOK {
+		w.compress = true
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Del("Content-Length")
+		w.Header().Del("Accept-Ranges")
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	if !w.compress {
+		return w.ResponseWriter.Write(p)
+	}
+	if w.gz == nil {
+		gz, err := gzip.NewWriterLevel(w.ResponseWriter, gzip.BestSpeed)
+		if err != nil {
+			return 0, err
+		}
+		w.gz = gz
+	}
+	return w.gz.Write(p)
+}
+
+// Close flushes the compressed body. The body of the HEAD
+// request is never written, so that there is nothing to flush.
+func (w *gzipResponseWriter) Close() error {
+	if w.gz == nil {
+		return nil
+	}
+	return w.gz.Close()
+}
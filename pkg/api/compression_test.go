@@ -0,0 +1,170 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestCompressionPolicy(t *testing.T) {
+	t.Parallel()
+
+	p, err := api.ParseCompressionPolicy("text/, application/json", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		contentType string
+		size        int64
+		want        bool
+	}{
+		{"text/html; charset=utf-8", 100, true},
+		{"text/css", 1 << 20, true},
+		{"application/json", 1000, true},
+		{"application/json", 99, false},
+		{"application/javascript", 1000, false},
+		{"image/png", 1000, false},
+	} {
+		if got := p.Compresses(tc.contentType, tc.size); got != tc.want {
+			t.Errorf("compresses %s of size %d: got %v, want %v", tc.contentType, tc.size, got, tc.want)
+		}
+	}
+
+	// the compression is disabled without the content types
+	p, err = api.ParseCompressionPolicy("", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Compresses("text/html", 1000) {
+		t.Error("compresses with the disabled compression")
+	}
+
+	if _, err := api.ParseCompressionPolicy("text", 100); !errors.Is(err, api.ErrInvalidCompressionContentType) {
+		t.Errorf("got error %v, want %v", err, api.ErrInvalidCompressionContentType)
+	}
+}
+
+func TestBzzCompression(t *testing.T) {
+	t.Parallel()
+
+	compression, err := api.ParseCompressionPolicy(api.DefaultCompressionContentTypes, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:      smock.NewStorer(),
+		Tags:        tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger:      log.Noop,
+		Post:        mockpost.New(mockpost.WithAcceptAll()),
+		Compression: compression,
+	})
+
+	var (
+		html  = []byte("<html>" + strings.Repeat("<p>swarm</p>", 100) + "</html>")
+		small = []byte("<html></html>")
+		png   = bytes.Repeat([]byte{1}, 1000)
+	)
+	tr := tarFiles(t, []f{
+		{data: html, name: "index.html", header: http.Header{"Content-Type": {"text/html; charset=utf-8"}}},
+		{data: small, name: "small.html", header: http.Header{"Content-Type": {"text/html; charset=utf-8"}}},
+		{data: png, name: "image.png", header: http.Header{"Content-Type": {"image/png"}}},
+	})
+
+	var resp api.BzzUploadResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+		jsonhttptest.WithRequestBody(tr),
+		jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+		jsonhttptest.WithUnmarshalJSONResponse(&resp),
+	)
+	root := "/bzz/" + resp.Reference.String() + "/"
+
+	t.Run("compressed", func(t *testing.T) {
+		t.Parallel()
+
+		var body []byte
+		jsonhttptest.Request(t, client, http.MethodGet, root+"index.html", http.StatusOK,
+			jsonhttptest.WithRequestHeader("Accept-Encoding", "br;q=0, gzip"),
+			jsonhttptest.WithPutResponseBody(&body),
+			jsonhttptest.WithExpectedResponseHeader("Content-Encoding", "gzip"),
+			jsonhttptest.WithExpectedResponseHeader("Vary", "Accept-Encoding"),
+		)
+		if len(body) >= len(html) {
+			t.Errorf("got compressed size %d, want less than %d", len(body), len(html))
+		}
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(gz)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, html) {
+			t.Fatalf("got decompressed content %q, want %q", got, html)
+		}
+	})
+
+	t.Run("range not compressed", func(t *testing.T) {
+		t.Parallel()
+
+		header := jsonhttptest.Request(t, client, http.MethodGet, root+"index.html", http.StatusPartialContent,
+			jsonhttptest.WithRequestHeader("Accept-Encoding", "gzip"),
+			jsonhttptest.WithRequestHeader("Range", "bytes=0-10"),
+			jsonhttptest.WithExpectedResponse(html[:11]),
+			jsonhttptest.WithExpectedResponseHeader("Content-Range", fmt.Sprintf("bytes 0-10/%d", len(html))),
+		)
+		if v := header.Get("Content-Encoding"); v != "" {
+			t.Fatalf("got content encoding %q, want none", v)
+		}
+	})
+
+	for _, tc := range []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		want           []byte
+		wantVary       bool
+	}{
+		{name: "not accepted", path: "index.html", acceptEncoding: "identity", want: html, wantVary: true},
+		{name: "rejected", path: "index.html", acceptEncoding: "gzip;q=0", want: html, wantVary: true},
+		{name: "too small", path: "small.html", acceptEncoding: "gzip", want: small},
+		{name: "content type", path: "image.png", acceptEncoding: "gzip", want: png},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := jsonhttptest.Request(t, client, http.MethodGet, root+tc.path, http.StatusOK,
+				jsonhttptest.WithRequestHeader("Accept-Encoding", tc.acceptEncoding),
+				jsonhttptest.WithExpectedResponse(tc.want),
+			)
+			if got := header.Get("Content-Encoding"); got != "" {
+				t.Errorf("got content encoding %q, want none", got)
+			}
+			if got := header.Get("Vary") == "Accept-Encoding"; got != tc.wantVary {
+				t.Errorf("got vary header %q, want vary %v", header.Get("Vary"), tc.wantVary)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// serveNotModified responds with the Not Modified status to the conditional
+// GET or HEAD request if the client has the current representation of the
+// content, which is identified by any of the etags or which was not modified
+// since the given time. The If-Modified-Since header is evaluated only without
+// the If-None-Match header and with the known modification time. It is meant
+// to be called before the content is retrieved, so that the unchanged content
+// is not downloaded from the network.
+func serveNotModified(w http.ResponseWriter, r *http.Request, etags []string, modTime time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		etag, ok := matchETag(inm, etags)
+		if !ok {
+			return false
+		}
+		w.Header().Set("ETag", etag)
+		writeNotModified(w, modTime)
+		return true
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || modTime.IsZero() {
+		return false
+	}
+	t, err := http.ParseTime(ims)
+	if err != nil || modTime.Truncate(time.Second).After(t) {
+		return false
+	}
+	writeNotModified(w, modTime)
+	return true
+}
+
+// matchETag returns the etag which matches the If-None-Match header with
+// the weak comparison, as in RFC 7232, section 3.2.
+func matchETag(header string, etags []string) (string, bool) {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		for _, etag := range etags {
+			if v == "*" || v == strings.TrimPrefix(etag, "W/") {
+				return etag, true
+			}
+		}
+	}
+	return "", false
+}
+
+// writeNotModified writes the Not Modified status without the
+// headers of the content, as in RFC 7232, section 4.1.
+func writeNotModified(w http.ResponseWriter, modTime time.Time) {
+	h := w.Header()
+	h.Del("Content-Type")
+	h.Del("Content-Length")
+	h.Del("Content-Encoding")
+	if !modTime.IsZero() {
+		h.Set("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+	}
+	w.WriteHeader(http.StatusNotModified)
+}
@@ -0,0 +1,136 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestBzzConditionalGet(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: smock.NewStorer(),
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	var (
+		data    = []byte("<h1>Swarm</h1>")
+		modTime = time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+	)
+	tr := tarFiles(t, []f{
+		{data: data, name: "index.html", modTime: modTime},
+	})
+	var resp api.BzzUploadResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPreserveMtimeHeader, "true"),
+		jsonhttptest.WithRequestBody(tr),
+		jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+		jsonhttptest.WithUnmarshalJSONResponse(&resp),
+	)
+	resource := "/bzz/" + resp.Reference.String() + "/index.html"
+
+	header := jsonhttptest.Request(t, client, http.MethodGet, resource, http.StatusOK,
+		jsonhttptest.WithExpectedResponse(data),
+		jsonhttptest.WithExpectedResponseHeader("Last-Modified", modTime.Format(http.TimeFormat)),
+	)
+	etag := header.Get("ETag")
+
+	for _, tc := range []struct {
+		name    string
+		header  http.Header
+		want    int
+		wantTag string
+	}{
+		{name: "etag", header: http.Header{"If-None-Match": {etag}}, want: http.StatusNotModified, wantTag: etag},
+		{name: "weak etag in list", header: http.Header{"If-None-Match": {`"other", W/` + etag}}, want: http.StatusNotModified, wantTag: etag},
+		{name: "any etag", header: http.Header{"If-None-Match": {"*"}}, want: http.StatusNotModified, wantTag: etag},
+		{name: "other etag", header: http.Header{"If-None-Match": {`"other"`}}, want: http.StatusOK, wantTag: etag},
+		{name: "not modified since", header: http.Header{"If-Modified-Since": {modTime.Format(http.TimeFormat)}}, want: http.StatusNotModified},
+		{name: "modified since", header: http.Header{"If-Modified-Since": {modTime.Add(-time.Hour).Format(http.TimeFormat)}}, want: http.StatusOK, wantTag: etag},
+		{
+			name: "etag precedes modification time",
+			header: http.Header{
+				"If-None-Match":     {`"other"`},
+				"If-Modified-Since": {modTime.Format(http.TimeFormat)},
+			},
+			want:    http.StatusOK,
+			wantTag: etag,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := []jsonhttptest.Option{
+				jsonhttptest.WithExpectedResponseHeader("Last-Modified", modTime.Format(http.TimeFormat)),
+			}
+			for name, values := range tc.header {
+				opts = append(opts, jsonhttptest.WithRequestHeader(name, values[0]))
+			}
+			if tc.want == http.StatusNotModified {
+				opts = append(opts, jsonhttptest.WithNoResponseBody())
+			} else {
+				opts = append(opts, jsonhttptest.WithExpectedResponse(data))
+			}
+			if tc.wantTag != "" {
+				opts = append(opts, jsonhttptest.WithExpectedResponseHeader("ETag", tc.wantTag))
+			}
+			jsonhttptest.Request(t, client, http.MethodGet, resource, tc.want, opts...)
+		})
+	}
+
+	// the modification times are stored only on request
+	t.Run("mtime not preserved", func(t *testing.T) {
+		t.Parallel()
+
+		var resp api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestBody(tarFiles(t, []f{{data: data, name: "index.html", modTime: modTime}})),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		header := jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+resp.Reference.String()+"/index.html", http.StatusOK,
+			jsonhttptest.WithRequestHeader("If-Modified-Since", modTime.Format(http.TimeFormat)),
+			jsonhttptest.WithExpectedResponse(data),
+		)
+		if v := header.Get("Last-Modified"); v != "" {
+			t.Fatalf("got last modified %q, want none", v)
+		}
+	})
+
+	// the unchanged content is not retrieved, so that
+	// even the missing content is not modified
+	t.Run("not retrieved", func(t *testing.T) {
+		t.Parallel()
+
+		etag := fmt.Sprintf("%q", swarm.RandAddress(t))
+		jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+etag[1:len(etag)-1], http.StatusNotModified,
+			jsonhttptest.WithRequestHeader("If-None-Match", etag),
+			jsonhttptest.WithExpectedResponseHeader("ETag", etag),
+			jsonhttptest.WithNoResponseBody(),
+		)
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdCoding is the content coding of the file payloads which are
+// compressed before they are chunked, if it is requested on upload.
+const zstdCoding = "zstd"
+
+var errUnsupportedCompression = errors.New("unsupported compression")
+
+// requestCompression returns the content coding of the file payloads
+// of the upload, which is empty if the payloads are stored as they are.
+func requestCompression(r *http.Request) (string, error) {
+	switch coding := strings.ToLower(strings.TrimSpace(r.Header.Get(SwarmCompressionHeader))); coding {
+	case "", "identity":
+		return "", nil
+	case zstdCoding:
+		return coding, nil
+	default:
+		return "", fmt.Errorf("%w: %q", errUnsupportedCompression, coding)
+	}
+}
+
+// compressedPipelineFn returns the pipeline function which compresses
+// the payload with the content coding before it is chunked by p. The
+// payload is not read anymore once the pipeline function returns.
+func compressedPipelineFn(p pipelineFunc, coding string) pipelineFunc {
+	if coding == "" {
+		return p
+	}
+	return func(ctx context.Context, r io.Reader) (swarm.Address, error) {
+		pr, pw := io.Pipe()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			enc, err := zstd.NewWriter(pw, zstd.WithEncoderConcurrency(1))
+			if err != nil {
+				_ = pw.CloseWithError(err)
+				return
+			}
+			if _, err := io.Copy(enc, r); err != nil {
+				enc.Close()
+				_ = pw.CloseWithError(err)
+				return
+			}
+			_ = pw.CloseWithError(enc.Close())
+		}()
+		addr, err := p(ctx, pr)
+		// unblock the compression if the pipeline stopped reading early
+		_ = pr.CloseWithError(io.ErrClosedPipe)
+		<-done
+		return addr, err
+	}
+}
+
+// acceptsCoding reports whether the client accepts the content coding.
+func acceptsCoding(r *http.Request, coding string) bool {
+	accepted := parseAcceptEncoding(r.Header.Get("Accept-Encoding"))
+	q, ok := accepted[coding]
+	if !ok {
+		q = accepted["*"]
+	}
+	return q > 0
+}
+
+// decodedETag returns the etag of the decoded representation of the
+// content stored with a content coding.
+func decodedETag(reference swarm.Address) string {
+	return fmt.Sprintf("\"%s-identity\"", reference)
+}
+
+// serveDecoded serves the content stored with the zstd content coding
+// decoded, for the clients which do not accept the coding. The decoded
+// content is served as a whole, as its size is not known in advance.
+func (s *Service) serveDecoded(logger log.Logger, w http.ResponseWriter, r *http.Request, reference swarm.Address, additionalHeaders http.Header) {
+	modTime, _ := http.ParseTime(additionalHeaders.Get("Last-Modified"))
+	w.Header().Set("Vary", "Accept-Encoding")
+	if serveNotModified(w, r, []string{decodedETag(reference)}, modTime) {
+		return
+	}
+
+	release, ok := s.acquireDownload(w)
+	if !ok {
+		return
+	}
+	defer release()
+
+	reader, _, err := joiner.New(r.Context(), s.storer, reference)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			logger.Debug("api download: not found ", "address", reference, "error", err)
+			logger.Error(nil, "not found")
+			jsonhttp.NotFound(w, nil)
+			return
+		}
+		logger.Debug("api download: unexpected error", "address", reference, "error", err)
+		logger.Error(nil, "api download: unexpected error")
+		jsonhttp.InternalServerError(w, "joiner failed")
+		return
+	}
+
+	for name, values := range additionalHeaders {
+		w.Header().Set(name, strings.Join(values, "; "))
+	}
+	w.Header().Set("ETag", decodedETag(reference))
+	w.Header().Add("Access-Control-Expose-Headers", "Content-Disposition")
+	if !modTime.IsZero() {
+		w.Header().Set("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+	}
+	if r.Method == http.MethodHead {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	dec, err := zstd.NewReader(reader, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		logger.Debug("api download: decoding failed", "address", reference, "error", err)
+		logger.Error(nil, "api download: decoding failed")
+		jsonhttp.InternalServerError(w, "decoding failed")
+		return
+	}
+	defer dec.Close()
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, dec); err != nil {
+		logger.Debug("api download: decoding failed", "address", reference, "error", err)
+		logger.Error(nil, "api download: decoding failed")
+	}
+}
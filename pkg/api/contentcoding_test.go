@@ -0,0 +1,241 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestBzzUploadCompression(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: smock.NewStorer(),
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	text := []byte(strings.Repeat("the quick brown fox jumps over the lazy dog\n", 1000))
+
+	decode := func(t *testing.T, b []byte) []byte {
+		t.Helper()
+
+		dec, err := zstd.NewReader(bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer dec.Close()
+		got, err := io.ReadAll(dec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+
+		var resp api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=text.txt", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCompressionHeader, "zstd"),
+			jsonhttptest.WithRequestHeader("Content-Type", "text/plain"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(text)),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		path := "/bzz/" + resp.Reference.String() + "/"
+
+		// the clients accepting the coding get the stored content
+		var compressed []byte
+		header := jsonhttptest.Request(t, client, http.MethodGet, path, http.StatusOK,
+			jsonhttptest.WithRequestHeader("Accept-Encoding", "gzip, zstd"),
+			jsonhttptest.WithPutResponseBody(&compressed),
+			jsonhttptest.WithExpectedResponseHeader("Content-Encoding", "zstd"),
+			jsonhttptest.WithExpectedResponseHeader("Content-Type", "text/plain"),
+			jsonhttptest.WithExpectedResponseHeader("Vary", "Accept-Encoding"),
+		)
+		if len(compressed) >= len(text) {
+			t.Fatalf("got compressed size %d, want less than %d", len(compressed), len(text))
+		}
+		if !bytes.Equal(decode(t, compressed), text) {
+			t.Fatal("decoded content differs from the uploaded one")
+		}
+		if got := header.Get("Content-Length"); got == "" {
+			t.Fatal("missing content length of the stored content")
+		}
+
+		// the other clients get the decoded content
+		header = jsonhttptest.Request(t, client, http.MethodGet, path, http.StatusOK,
+			jsonhttptest.WithRequestHeader("Accept-Encoding", "gzip"),
+			jsonhttptest.WithExpectedResponse(text),
+			jsonhttptest.WithExpectedResponseHeader("Content-Type", "text/plain"),
+			jsonhttptest.WithExpectedResponseHeader("Vary", "Accept-Encoding"),
+		)
+		if got := header.Get("Content-Encoding"); got != "" {
+			t.Fatalf("got content encoding %q, want none", got)
+		}
+		etag := header.Get("ETag")
+		if !strings.HasSuffix(etag, "-identity\"") {
+			t.Fatalf("got etag %s, want one of the decoded content", etag)
+		}
+
+		jsonhttptest.Request(t, client, http.MethodGet, path, http.StatusNotModified,
+			jsonhttptest.WithRequestHeader("Accept-Encoding", "identity"),
+			jsonhttptest.WithRequestHeader("If-None-Match", etag),
+		)
+		jsonhttptest.Request(t, client, http.MethodHead, path, http.StatusOK,
+			jsonhttptest.WithRequestHeader("Accept-Encoding", "zstd;q=0"),
+			jsonhttptest.WithExpectedResponseHeader("ETag", etag),
+		)
+	})
+
+	t.Run("collection", func(t *testing.T) {
+		t.Parallel()
+
+		gz := []byte("gzip compressed text")
+		tr := tarFiles(t, []f{
+			{data: text, name: "index.txt", header: http.Header{"Content-Type": {"text/plain"}}},
+			{data: gz, name: "index.txt.gz", header: http.Header{"Content-Type": {"application/gzip"}}},
+		})
+
+		var resp api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmCompressionHeader, "zstd"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestBody(tr),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		root := "/bzz/" + resp.Reference.String() + "/"
+
+		var compressed []byte
+		jsonhttptest.Request(t, client, http.MethodGet, root+"index.txt", http.StatusOK,
+			jsonhttptest.WithRequestHeader("Accept-Encoding", "zstd"),
+			jsonhttptest.WithPutResponseBody(&compressed),
+			jsonhttptest.WithExpectedResponseHeader("Content-Encoding", "zstd"),
+		)
+		if !bytes.Equal(decode(t, compressed), text) {
+			t.Fatal("decoded content differs from the uploaded one")
+		}
+		jsonhttptest.Request(t, client, http.MethodGet, root+"index.txt", http.StatusOK,
+			jsonhttptest.WithRequestHeader("Accept-Encoding", "identity"),
+			jsonhttptest.WithExpectedResponse(text),
+		)
+
+		// the precompressed variant is preferred and is not compressed again
+		jsonhttptest.Request(t, client, http.MethodGet, root+"index.txt", http.StatusOK,
+			jsonhttptest.WithRequestHeader("Accept-Encoding", "gzip, zstd;q=0.5"),
+			jsonhttptest.WithExpectedResponse(gz),
+			jsonhttptest.WithExpectedResponseHeader("Content-Encoding", "gzip"),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, root+"index.txt.gz", http.StatusOK,
+			jsonhttptest.WithRequestHeader("Accept-Encoding", "identity"),
+			jsonhttptest.WithExpectedResponse(gz),
+		)
+
+		// the compressed files are exported with their content coding
+		var archive []byte
+		jsonhttptest.Request(t, client, http.MethodGet, root, http.StatusOK,
+			jsonhttptest.WithRequestHeader("Accept", api.ContentTypeTar),
+			jsonhttptest.WithPutResponseBody(&archive),
+		)
+		ar := tar.NewReader(bytes.NewReader(archive))
+		for {
+			hdr, err := ar.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hdr.Name != "index.txt" {
+				continue
+			}
+			if got := hdr.PAXRecords[api.TarPAXContentEncodingKey]; got != "zstd" {
+				t.Fatalf("got content coding %q, want zstd", got)
+			}
+			data, err := io.ReadAll(ar)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(decode(t, data), text) {
+				t.Fatal("decoded content differs from the uploaded one")
+			}
+			break
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=text.txt", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCompressionHeader, "lz4"),
+			jsonhttptest.WithRequestHeader("Content-Type", "text/plain"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(text)),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "unsupported compression",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
+
+// slowReader records the reads of the payload after the upload returned.
+type slowReader struct {
+	returned  atomic.Bool
+	lateReads atomic.Int32
+}
+
+func (r *slowReader) Read(b []byte) (int, error) {
+	time.Sleep(time.Millisecond)
+	if r.returned.Load() {
+		r.lateReads.Add(1)
+	}
+	return len(b), nil
+}
+
+func TestCompressedPipelineFnStopsReading(t *testing.T) {
+	t.Parallel()
+
+	errFailed := errors.New("failed")
+	failing := func(ctx context.Context, r io.Reader) (swarm.Address, error) {
+		return swarm.ZeroAddress, errFailed
+	}
+
+	body := new(slowReader)
+	_, err := api.CompressedPipelineFn(failing, "zstd")(context.Background(), body)
+	body.returned.Store(true)
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("got error %v, want %v", err, errFailed)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if n := body.lateReads.Load(); n > 0 {
+		t.Fatalf("payload read %d times after the upload returned", n)
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/feeds/crdt"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/gorilla/mux"
+)
+
+// crdtWriterQuery is the repeated query parameter
+// with the addresses of the writers of a data type.
+const crdtWriterQuery = "writer"
+
+var errMissingWriters = errors.New("missing writers")
+
+type crdtRegisterResponse struct {
+	Topic     string `json:"topic"`
+	Writer    string `json:"writer"`
+	Timestamp uint64 `json:"timestamp"`
+	Value     []byte `json:"value"`
+}
+
+type crdtCount struct {
+	Writer string `json:"writer"`
+	Count  uint64 `json:"count"`
+}
+
+type crdtCounterResponse struct {
+	Topic  string      `json:"topic"`
+	Value  uint64      `json:"value"`
+	Counts []crdtCount `json:"counts"`
+}
+
+type crdtIncrementResponse struct {
+	Topic  string `json:"topic"`
+	Writer string `json:"writer"`
+	Count  uint64 `json:"count"`
+}
+
+// crdtRegisterGetHandler returns the merged value
+// of the register written by the writers.
+func (s *Service) crdtRegisterGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_crdt_register").Build()
+
+	topic, writers, ok := s.crdtRequest(logger, w, r, true)
+	if !ok {
+		return
+	}
+
+	reg, err := s.crdt.Register(r.Context(), topic, writers)
+	if err != nil {
+		logger.Debug("get register failed", "topic", hex.EncodeToString(topic), "error", err)
+		logger.Error(nil, "get register failed")
+		jsonhttp.InternalServerError(w, "get register failed")
+		return
+	}
+	if reg.Timestamp == 0 {
+		jsonhttp.NotFound(w, "register not written")
+		return
+	}
+
+	jsonhttp.OK(w, newCrdtRegisterResponse(topic, reg))
+}
+
+// crdtRegisterPostHandler writes the request body as the value of the
+// register to the feed of the node. The value wins over the values of
+// the writers given in the query.
+func (s *Service) crdtRegisterPostHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_crdt_register").Build()
+
+	topic, writers, ok := s.crdtRequest(logger, w, r, false)
+	if !ok {
+		return
+	}
+
+	value, err := io.ReadAll(r.Body)
+	if err != nil {
+		if jsonhttp.HandleBodyReadError(err, w) {
+			return
+		}
+		logger.Debug("read request body failed", "error", err)
+		logger.Error(nil, "read request body failed")
+		jsonhttp.InternalServerError(w, "cannot read request")
+		return
+	}
+
+	putter, wait, ok := s.deployPutter(logger, w, r)
+	if !ok {
+		return
+	}
+
+	reg, err := s.crdt.SetRegister(r.Context(), putter, topic, value, writers)
+	if err != nil {
+		logger.Debug("set register failed", "topic", hex.EncodeToString(topic), "error", err)
+		logger.Error(nil, "set register failed")
+		if errors.Is(err, crdt.ErrValueTooLarge) {
+			jsonhttp.RequestEntityTooLarge(w, "value too large")
+			return
+		}
+		jsonhttp.InternalServerError(w, "set register failed")
+		return
+	}
+
+	if err := wait(); err != nil {
+		logger.Debug("sync feed update failed", "error", err)
+		logger.Error(nil, "sync feed update failed")
+		jsonhttp.InternalServerError(w, "sync feed update failed")
+		return
+	}
+
+	jsonhttp.Created(w, newCrdtRegisterResponse(topic, reg))
+}
+
+// crdtCounterGetHandler returns the merged value of the
+// counter incremented by the writers and their counts.
+func (s *Service) crdtCounterGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_crdt_counter").Build()
+
+	topic, writers, ok := s.crdtRequest(logger, w, r, true)
+	if !ok {
+		return
+	}
+
+	counter, err := s.crdt.Counter(r.Context(), topic, writers)
+	if err != nil {
+		logger.Debug("get counter failed", "topic", hex.EncodeToString(topic), "error", err)
+		logger.Error(nil, "get counter failed")
+		if errors.Is(err, crdt.ErrOverflow) {
+			jsonhttp.Conflict(w, "counter overflow")
+			return
+		}
+		jsonhttp.InternalServerError(w, "get counter failed")
+		return
+	}
+
+	res := crdtCounterResponse{
+		Topic:  hex.EncodeToString(topic),
+		Value:  counter.Value,
+		Counts: make([]crdtCount, 0, len(counter.Counts)),
+	}
+	for _, c := range counter.Counts {
+		res.Counts = append(res.Counts, crdtCount{
+			Writer: hex.EncodeToString(c.Writer.Bytes()),
+			Count:  c.Count,
+		})
+	}
+	jsonhttp.OK(w, res)
+}
+
+// crdtCounterPostHandler increments the count of the node
+// of the counter by the delta, which is one by default.
+func (s *Service) crdtCounterPostHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_crdt_counter").Build()
+
+	topic, _, ok := s.crdtRequest(logger, w, r, false)
+	if !ok {
+		return
+	}
+
+	queries := struct {
+		Delta *uint64 `map:"delta"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+	delta := uint64(1)
+	if queries.Delta != nil {
+		delta = *queries.Delta
+	}
+
+	putter, wait, ok := s.deployPutter(logger, w, r)
+	if !ok {
+		return
+	}
+
+	c, err := s.crdt.Increment(r.Context(), putter, topic, delta)
+	if err != nil {
+		logger.Debug("increment counter failed", "topic", hex.EncodeToString(topic), "error", err)
+		logger.Error(nil, "increment counter failed")
+		switch {
+		case errors.Is(err, crdt.ErrInvalidDelta):
+			jsonhttp.BadRequest(w, "invalid delta")
+		case errors.Is(err, crdt.ErrOverflow):
+			jsonhttp.Conflict(w, "counter overflow")
+		default:
+			jsonhttp.InternalServerError(w, "increment counter failed")
+		}
+		return
+	}
+
+	if err := wait(); err != nil {
+		logger.Debug("sync feed update failed", "error", err)
+		logger.Error(nil, "sync feed update failed")
+		jsonhttp.InternalServerError(w, "sync feed update failed")
+		return
+	}
+
+	jsonhttp.Created(w, crdtIncrementResponse{
+		Topic:  hex.EncodeToString(topic),
+		Writer: hex.EncodeToString(c.Writer.Bytes()),
+		Count:  c.Count,
+	})
+}
+
+// crdtRequest returns the topic and the writers of the request. The error
+// response is written if the ok result is false.
+func (s *Service) crdtRequest(logger log.Logger, w http.ResponseWriter, r *http.Request, writersRequired bool) (topic []byte, writers []common.Address, ok bool) {
+	if s.crdt == nil {
+		jsonhttp.NotImplemented(w, "crdt is not available")
+		return nil, nil, false
+	}
+
+	paths := struct {
+		Topic []byte `map:"topic" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return nil, nil, false
+	}
+
+	writers, err := parseCrdtWriters(r, writersRequired)
+	if err != nil {
+		logger.Debug("invalid writers", "error", err)
+		logger.Error(nil, "invalid writers")
+		jsonhttp.BadRequest(w, jsonhttp.StatusResponse{
+			Message: "invalid query params",
+			Code:    http.StatusBadRequest,
+			Reasons: []jsonhttp.Reason{{
+				Field: crdtWriterQuery,
+				Error: err.Error(),
+			}},
+		})
+		return nil, nil, false
+	}
+
+	return paths.Topic, writers, true
+}
+
+func parseCrdtWriters(r *http.Request, required bool) ([]common.Address, error) {
+	values := r.URL.Query()[crdtWriterQuery]
+	if required && len(values) == 0 {
+		return nil, errMissingWriters
+	}
+	writers := make([]common.Address, 0, len(values))
+	for _, v := range values {
+		if !common.IsHexAddress(v) {
+			return nil, fmt.Errorf("invalid writer %q", v)
+		}
+		writers = append(writers, common.HexToAddress(v))
+	}
+	return writers, nil
+}
+
+func newCrdtRegisterResponse(topic []byte, reg crdt.Register) crdtRegisterResponse {
+	return crdtRegisterResponse{
+		Topic:     hex.EncodeToString(topic),
+		Writer:    hex.EncodeToString(reg.Writer.Bytes()),
+		Timestamp: reg.Timestamp,
+		Value:     reg.Value,
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds/crdt"
+	"github.com/ethersphere/bee/pkg/feeds/factory"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestCrdt(t *testing.T) {
+	t.Parallel()
+
+	newService := func(storer *smock.MockStorer) *crdt.Service {
+		t.Helper()
+
+		pk, err := crypto.GenerateSecp256k1Key()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return crdt.New(factory.New(storer), crypto.NewDefaultSigner(pk))
+	}
+
+	var (
+		storer          = smock.NewStorer()
+		node            = newService(storer)
+		other           = newService(storer)
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer: storer,
+			Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+			Logger: log.Noop,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+			Crdt:   node,
+		})
+		topic = "cafe"
+	)
+
+	nodeAddr, err := node.Owner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherAddr, err := other.Owner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeWriter := hex.EncodeToString(nodeAddr.Bytes())
+	otherWriter := hex.EncodeToString(otherAddr.Bytes())
+	writers := "?writer=" + nodeWriter + "&writer=" + otherWriter
+
+	t.Run("register", func(t *testing.T) {
+		t.Parallel()
+
+		resource := "/crdt/registers/" + topic
+
+		jsonhttptest.Request(t, client, http.MethodGet, resource+writers, http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "register not written",
+				Code:    http.StatusNotFound,
+			}),
+		)
+
+		var set api.CrdtRegisterResponse
+		jsonhttptest.Request(t, client, http.MethodPost, resource+writers, http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader([]byte("v1"))),
+			jsonhttptest.WithUnmarshalJSONResponse(&set),
+		)
+		if set.Writer != nodeWriter || string(set.Value) != "v1" {
+			t.Fatalf("got register %+v, want value v1 of %s", set, nodeWriter)
+		}
+
+		jsonhttptest.Request(t, client, http.MethodGet, resource+writers, http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(set),
+		)
+
+		// the write of the other writer which observed the node wins
+		b, _ := hex.DecodeString(topic)
+		reg, err := other.SetRegister(context.Background(), storer, b, []byte("v2"), []common.Address{nodeAddr})
+		if err != nil {
+			t.Fatal(err)
+		}
+		jsonhttptest.Request(t, client, http.MethodGet, resource+writers, http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.CrdtRegisterResponse{
+				Topic:     topic,
+				Writer:    otherWriter,
+				Timestamp: reg.Timestamp,
+				Value:     []byte("v2"),
+			}),
+		)
+
+		// the readers which trust only the node see its value
+		jsonhttptest.Request(t, client, http.MethodGet, resource+"?writer="+nodeWriter, http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(set),
+		)
+
+		jsonhttptest.Request(t, client, http.MethodPost, resource, http.StatusRequestEntityTooLarge,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(make([]byte, crdt.MaxValueSize+1))),
+		)
+	})
+
+	t.Run("counter", func(t *testing.T) {
+		t.Parallel()
+
+		resource := "/crdt/counters/" + topic
+
+		jsonhttptest.Request(t, client, http.MethodPost, resource, http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithExpectedJSONResponse(api.CrdtIncrementResponse{
+				Topic:  topic,
+				Writer: nodeWriter,
+				Count:  1,
+			}),
+		)
+		jsonhttptest.Request(t, client, http.MethodPost, resource+"?delta=4", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithExpectedJSONResponse(api.CrdtIncrementResponse{
+				Topic:  topic,
+				Writer: nodeWriter,
+				Count:  5,
+			}),
+		)
+
+		b, _ := hex.DecodeString(topic)
+		if _, err := other.Increment(context.Background(), storer, b, 3); err != nil {
+			t.Fatal(err)
+		}
+
+		jsonhttptest.Request(t, client, http.MethodGet, resource+writers, http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.CrdtCounterResponse{
+				Topic: topic,
+				Value: 8,
+				Counts: []api.CrdtCount{
+					{Writer: nodeWriter, Count: 5},
+					{Writer: otherWriter, Count: 3},
+				},
+			}),
+		)
+
+		jsonhttptest.Request(t, client, http.MethodPost, resource+"?delta=0", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid delta",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("invalid writers", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/crdt/counters/"+topic, http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid query params",
+				Code:    http.StatusBadRequest,
+				Reasons: []jsonhttp.Reason{{
+					Field: "writer",
+					Error: "missing writers",
+				}},
+			}),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/crdt/registers/"+topic+"?writer=zz", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid query params",
+				Code:    http.StatusBadRequest,
+				Reasons: []jsonhttp.Reason{{
+					Field: "writer",
+					Error: `invalid writer "zz"`,
+				}},
+			}),
+		)
+	})
+
+	t.Run("not available", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{})
+		jsonhttptest.Request(t, client, http.MethodGet, "/crdt/counters/"+topic+writers, http.StatusNotImplemented)
+	})
+}
@@ -0,0 +1,246 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/deploy"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
+)
+
+type deployRecord struct {
+	Reference swarm.Address `json:"reference"`
+	Index     uint64        `json:"index"`
+	Timestamp int64         `json:"timestamp"`
+}
+
+type deployResponse struct {
+	Owner     string        `json:"owner"`
+	Topic     string        `json:"topic"`
+	Reference swarm.Address `json:"reference"`
+	Index     uint64        `json:"index"`
+	Timestamp int64         `json:"timestamp"`
+}
+
+type deployHistoryResponse struct {
+	Owner   string         `json:"owner"`
+	Topic   string         `json:"topic"`
+	History []deployRecord `json:"history"`
+}
+
+// deployPostHandler uploads the collection of the website and publishes it
+// to the feed of the node with the topic once all of its chunks are
+// retrievable from the network.
+func (s *Service) deployPostHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("post_deploy").Build())
+
+	if s.deploy == nil {
+		jsonhttp.NotImplemented(w, "deploy is not available")
+		return
+	}
+
+	paths := struct {
+		Topic []byte `map:"topic" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	headers := struct {
+		ContentType string `map:"Content-Type,mimeMediaType" validate:"required"`
+	}{}
+	if response := s.mapStructure(r.Header, &headers); response != nil {
+		response("invalid header params", logger, w)
+		return
+	}
+
+	putter, wait, ok := s.deployPutter(logger, w, r)
+	if !ok {
+		return
+	}
+
+	reference, _, ok := s.storeDirRequest(logger, w, r, putter, nil)
+	if !ok {
+		return
+	}
+
+	if requestPin(r) {
+		if err := s.pinning.CreatePin(r.Context(), reference, false); err != nil {
+			logger.Debug("pin creation failed", "address", reference, "error", err)
+			logger.Error(nil, "pin creation failed")
+			jsonhttp.InternalServerError(w, "create pin failed")
+			return
+		}
+	}
+
+	// the content is synced before the check of its retrievability
+	if err := wait(); err != nil {
+		logger.Debug("sync chunks failed", "error", err)
+		logger.Error(nil, "sync chunks failed")
+		jsonhttp.InternalServerError(w, "sync chunks failed")
+		return
+	}
+
+	rec, err := s.deploy.Publish(r.Context(), putter, paths.Topic, reference)
+	if err != nil {
+		logger.Debug("publish failed", "reference", reference, "error", err)
+		logger.Error(nil, "publish failed")
+		if errors.Is(err, deploy.ErrNotRetrievable) {
+			jsonhttp.Conflict(w, "content is not retrievable, not published")
+			return
+		}
+		jsonhttp.InternalServerError(w, "publish failed")
+		return
+	}
+
+	s.writeDeployResponse(logger, w, http.StatusCreated, paths.Topic, rec, wait)
+}
+
+// deployRollbackHandler publishes the previous version of the website
+// to the feed of the node with the topic.
+func (s *Service) deployRollbackHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("post_deploy_rollback").Build())
+
+	if s.deploy == nil {
+		jsonhttp.NotImplemented(w, "deploy is not available")
+		return
+	}
+
+	paths := struct {
+		Topic []byte `map:"topic" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	putter, wait, ok := s.deployPutter(logger, w, r)
+	if !ok {
+		return
+	}
+
+	rec, err := s.deploy.Rollback(r.Context(), putter, paths.Topic)
+	if err != nil {
+		logger.Debug("rollback failed", "topic", hex.EncodeToString(paths.Topic), "error", err)
+		logger.Error(nil, "rollback failed")
+		switch {
+		case errors.Is(err, deploy.ErrNoPrevious):
+			jsonhttp.NotFound(w, "no previous version")
+		case errors.Is(err, deploy.ErrNotRetrievable):
+			jsonhttp.Conflict(w, "previous version is not retrievable, not published")
+		default:
+			jsonhttp.InternalServerError(w, "rollback failed")
+		}
+		return
+	}
+
+	s.writeDeployResponse(logger, w, http.StatusOK, paths.Topic, rec, wait)
+}
+
+// deployGetHandler returns the recorded versions of the website
+// published to the feed of the node with the topic.
+func (s *Service) deployGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_deploy").Build()
+
+	if s.deploy == nil {
+		jsonhttp.NotImplemented(w, "deploy is not available")
+		return
+	}
+
+	paths := struct {
+		Topic []byte `map:"topic" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	owner, err := s.deploy.Owner()
+	if err != nil {
+		logger.Debug("get owner failed", "error", err)
+		logger.Error(nil, "get owner failed")
+		jsonhttp.InternalServerError(w, "get owner failed")
+		return
+	}
+
+	history, err := s.deploy.History(paths.Topic)
+	if err != nil {
+		logger.Debug("get history failed", "topic", hex.EncodeToString(paths.Topic), "error", err)
+		logger.Error(nil, "get history failed")
+		jsonhttp.InternalServerError(w, "get history failed")
+		return
+	}
+
+	res := deployHistoryResponse{
+		Owner:   hex.EncodeToString(owner.Bytes()),
+		Topic:   hex.EncodeToString(paths.Topic),
+		History: make([]deployRecord, 0, len(history)),
+	}
+	for _, rec := range history {
+		res.History = append(res.History, deployRecord(rec))
+	}
+	jsonhttp.OK(w, res)
+}
+
+// deployPutter returns the stamping putter of the request. The error
+// response is written if the ok result is false.
+func (s *Service) deployPutter(logger log.Logger, w http.ResponseWriter, r *http.Request) (storage.Storer, func() error, bool) {
+	putter, wait, err := s.newStamperPutter(r)
+	if err != nil {
+		logger.Debug("putter failed", "error", err)
+		logger.Error(nil, "putter failed")
+		switch {
+		case errors.Is(err, errBatchUnusable) || errors.Is(err, postage.ErrNotUsable):
+			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+		case errors.Is(err, postage.ErrNotFound):
+			jsonhttp.NotFound(w, "batch with id not found")
+		case errors.Is(err, errInvalidPostageBatch):
+			jsonhttp.BadRequest(w, "invalid batch id")
+		case errors.Is(err, errUnsupportedDevNodeOperation):
+			jsonhttp.BadRequest(w, errUnsupportedDevNodeOperation)
+		default:
+			jsonhttp.BadRequest(w, nil)
+		}
+		return nil, nil, false
+	}
+	return putter, wait, true
+}
+
+// writeDeployResponse waits for the feed update to be synced
+// and writes the published version.
+func (s *Service) writeDeployResponse(logger log.Logger, w http.ResponseWriter, status int, topic []byte, rec deploy.Record, wait func() error) {
+	if err := wait(); err != nil {
+		logger.Debug("sync feed update failed", "error", err)
+		logger.Error(nil, "sync feed update failed")
+		jsonhttp.InternalServerError(w, "sync feed update failed")
+		return
+	}
+
+	owner, err := s.deploy.Owner()
+	if err != nil {
+		logger.Debug("get owner failed", "error", err)
+		logger.Error(nil, "get owner failed")
+		jsonhttp.InternalServerError(w, "get owner failed")
+		return
+	}
+
+	jsonhttp.Respond(w, status, deployResponse{
+		Owner:     hex.EncodeToString(owner.Bytes()),
+		Topic:     hex.EncodeToString(topic),
+		Reference: rec.Reference,
+		Index:     rec.Index,
+		Timestamp: rec.Timestamp,
+	})
+}
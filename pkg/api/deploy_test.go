@@ -0,0 +1,154 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/deploy"
+	"github.com/ethersphere/bee/pkg/feeds/factory"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+// retrievalChecker reports all content as retrievable unless it is disabled.
+type retrievalChecker struct {
+	mu       sync.Mutex
+	disabled bool
+}
+
+func (c *retrievalChecker) IsRetrievable(context.Context, swarm.Address) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.disabled, nil
+}
+
+func (c *retrievalChecker) setDisabled(v bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.disabled = v
+}
+
+func TestDeploy(t *testing.T) {
+	t.Parallel()
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		storer          = smock.NewStorer()
+		checker         = new(retrievalChecker)
+		deployService   = deploy.New(statestore.NewStateStore(), checker, crypto.NewDefaultSigner(pk), factory.New(storer))
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer: storer,
+			Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+			Logger: log.Noop,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+			Deploy: deployService,
+		})
+		topic    = "cafe"
+		resource = "/deploys/" + topic
+	)
+
+	owner, err := deployService.Owner()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deploySite := func(t *testing.T, content string, status int, opts ...jsonhttptest.Option) {
+		t.Helper()
+
+		tr := tarFiles(t, []f{{
+			data:   []byte(content),
+			name:   "index.html",
+			header: http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+		}})
+
+		jsonhttptest.Request(t, client, http.MethodPost, resource, status, append([]jsonhttptest.Option{
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmIndexDocumentHeader, "index.html"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestBody(tr),
+		}, opts...)...)
+	}
+
+	history := func(t *testing.T, want ...swarm.Address) {
+		t.Helper()
+
+		var resp api.DeployHistoryResponse
+		jsonhttptest.Request(t, client, http.MethodGet, resource, http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		if want := hex.EncodeToString(owner.Bytes()); resp.Owner != want {
+			t.Fatalf("got owner %s, want %s", resp.Owner, want)
+		}
+		if len(resp.History) != len(want) {
+			t.Fatalf("got %d versions, want %d", len(resp.History), len(want))
+		}
+		for i := range want {
+			if !resp.History[i].Reference.Equal(want[i]) {
+				t.Fatalf("version %d: got %s, want %s", i, resp.History[i].Reference, want[i])
+			}
+		}
+	}
+
+	jsonhttptest.Request(t, client, http.MethodPost, resource+"/rollback", http.StatusNotFound,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "no previous version",
+			Code:    http.StatusNotFound,
+		}),
+	)
+
+	var v1, v2 api.DeployResponse
+	deploySite(t, "<h1>version 1</h1>", http.StatusCreated, jsonhttptest.WithUnmarshalJSONResponse(&v1))
+	deploySite(t, "<h1>version 2</h1>", http.StatusCreated, jsonhttptest.WithUnmarshalJSONResponse(&v2))
+	if v1.Index != 0 || v2.Index != 1 || v1.Topic != topic {
+		t.Fatalf("got versions %+v and %+v, want indexes 0 and 1 of topic %s", v1, v2, topic)
+	}
+	history(t, v1.Reference, v2.Reference)
+
+	// the content which is not retrievable is not published
+	checker.setDisabled(true)
+	deploySite(t, "<h1>version 3</h1>", http.StatusConflict, jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+		Message: "content is not retrievable, not published",
+		Code:    http.StatusConflict,
+	}))
+	checker.setDisabled(false)
+	history(t, v1.Reference, v2.Reference)
+
+	var rolledBack api.DeployResponse
+	jsonhttptest.Request(t, client, http.MethodPost, resource+"/rollback", http.StatusOK,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithUnmarshalJSONResponse(&rolledBack),
+	)
+	if !rolledBack.Reference.Equal(v1.Reference) || rolledBack.Index != 2 {
+		t.Fatalf("got rollback %+v, want %s at index 2", rolledBack, v1.Reference)
+	}
+	history(t, v1.Reference)
+
+	// the deployed site is served
+	jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+v1.Reference.String()+"/", http.StatusOK,
+		jsonhttptest.WithExpectedResponse([]byte("<h1>version 1</h1>")),
+	)
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/deploys/not-hex", http.StatusBadRequest)
+}
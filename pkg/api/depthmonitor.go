@@ -0,0 +1,57 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+)
+
+type depthMonitorResponse struct {
+	StorageRadius   uint8     `json:"storageRadius"`
+	ReserveRadius   uint8     `json:"reserveRadius"`
+	ReserveSize     uint64    `json:"reserveSize"`
+	ReserveCapacity uint64    `json:"reserveCapacity"`
+	TargetSize      uint64    `json:"targetSize"`
+	Commitment      int64     `json:"commitment"`
+	ConnectedPeers  int       `json:"connectedPeers"`
+	SyncRate        float64   `json:"syncRate"`
+	LastChange      time.Time `json:"lastChange"`
+	Confirmations   int       `json:"confirmations"`
+}
+
+// depthMonitorHandler returns the storage depth with
+// the inputs of its computation.
+func (s *Service) depthMonitorHandler(w http.ResponseWriter, _ *http.Request) {
+	logger := s.logger.WithName("get_depthmonitor").Build()
+
+	if s.depthMonitor == nil {
+		jsonhttp.NotImplemented(w, "depth monitor not available")
+		return
+	}
+
+	status, err := s.depthMonitor.Status()
+	if err != nil {
+		logger.Debug("depth monitor status failed", "error", err)
+		logger.Error(nil, "depth monitor status failed")
+		jsonhttp.InternalServerError(w, "depth monitor status failed")
+		return
+	}
+
+	jsonhttp.OK(w, depthMonitorResponse{
+		StorageRadius:   status.StorageRadius,
+		ReserveRadius:   status.ReserveRadius,
+		ReserveSize:     status.ReserveSize,
+		ReserveCapacity: status.ReserveCapacity,
+		TargetSize:      status.TargetSize,
+		Commitment:      status.Commitment,
+		ConnectedPeers:  status.ConnectedPeers,
+		SyncRate:        status.SyncRate,
+		LastChange:      status.LastChange,
+		Confirmations:   status.Confirmations,
+	})
+}
@@ -0,0 +1,86 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	mockbatchstore "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+	"github.com/ethersphere/bee/pkg/topology"
+	"github.com/ethersphere/bee/pkg/topology/depthmonitor"
+)
+
+type depthMonitorTopology struct{}
+
+func (depthMonitorTopology) SetStorageRadius(uint8)         {}
+func (depthMonitorTopology) PeersCount(topology.Filter) int { return 0 }
+
+type depthMonitorSyncer struct{}
+
+func (depthMonitorSyncer) SyncRate() float64 { return 0 }
+
+type depthMonitorReserve struct{}
+
+func (depthMonitorReserve) ComputeReserveSize(uint8) (uint64, error) { return 0, nil }
+func (depthMonitorReserve) ReserveCapacity() uint64                  { return 1000 }
+
+func TestDepthMonitor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("status", func(t *testing.T) {
+		t.Parallel()
+
+		bs := mockbatchstore.New(
+			mockbatchstore.WithReserveState(&postage.ReserveState{Radius: 5, StorageRadius: 4}),
+			mockbatchstore.WithBatch(postagetesting.MustNewBatch(postagetesting.WithDepth(10))),
+		)
+		svc := depthmonitor.New(depthMonitorTopology{}, depthMonitorSyncer{}, depthMonitorReserve{}, bs, log.Noop, time.Hour, time.Hour, false, depthmonitor.Options{
+			DecreaseThreshold: 0.5,
+		})
+		t.Cleanup(func() { _ = svc.Close() })
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI:     true,
+			DepthMonitor: svc,
+		})
+
+		var got api.DepthMonitorResponse
+		jsonhttptest.Request(t, ts, http.MethodGet, "/depthmonitor", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&got),
+		)
+		if got.StorageRadius != 4 || got.ReserveRadius != 5 {
+			t.Fatalf("got radius %d and storage radius %d, want 5 and 4", got.ReserveRadius, got.StorageRadius)
+		}
+		if got.ReserveCapacity != 1000 || got.TargetSize != 500 {
+			t.Fatalf("got capacity %d and target size %d, want 1000 and 500", got.ReserveCapacity, got.TargetSize)
+		}
+		if got.Commitment != 1024 {
+			t.Fatalf("got commitment %d, want 1024", got.Commitment)
+		}
+	})
+
+	t.Run("not available", func(t *testing.T) {
+		t.Parallel()
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+		})
+
+		jsonhttptest.Request(t, ts, http.MethodGet, "/depthmonitor", http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "depth monitor not available",
+				Code:    http.StatusNotImplemented,
+			}),
+		)
+	})
+}
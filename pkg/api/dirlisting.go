@@ -0,0 +1,142 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// The types of the entries of the directory listing.
+const (
+	dirListingTypeFile      = "file"
+	dirListingTypeDirectory = "directory"
+)
+
+type dirListingEntry struct {
+	Name        string         `json:"name"`
+	Type        string         `json:"type"`
+	Size        int64          `json:"size,omitempty"`
+	ContentType string         `json:"contentType,omitempty"`
+	Reference   *swarm.Address `json:"reference,omitempty"`
+}
+
+type dirListingResponse struct {
+	Path    string            `json:"path"`
+	Entries []dirListingEntry `json:"entries"`
+}
+
+var dirListingTemplate = template.Must(template.New("listing").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Index of /{{.Path}}</title>
+</head>
+<body>
+<h1>Index of /{{.Path}}</h1>
+<table>
+<tr><th>Name</th><th>Size</th><th>Type</th></tr>
+{{- if .Path}}
+<tr><td><a href="../">../</a></td><td></td><td></td></tr>
+{{- end}}
+{{- range .Entries}}
+{{- if eq .Type "directory"}}
+<tr><td><a href="{{.Name}}/">{{.Name}}/</a></td><td></td><td></td></tr>
+{{- else}}
+<tr><td><a href="{{.Name}}">{{.Name}}</a></td><td>{{.Size}}</td><td>{{.ContentType}}</td></tr>
+{{- end}}
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// acceptsJSON reports whether the directory listing is requested as json.
+func acceptsJSON(r *http.Request) bool {
+	return parseAcceptEncoding(r.Header.Get("Accept"))["application/json"] > 0
+}
+
+// serveDirListing serves the files and the subdirectories of the directory
+// of the collection, which is the path ending with the slash or the empty
+// path of the root directory, as json if requested by the Accept header, or
+// as a simple html page otherwise. The sizes of the files are read from their
+// root chunks. The manifest is not walked for the HEAD requests.
+func (s *Service) serveDirListing(logger log.Logger, w http.ResponseWriter, r *http.Request, address swarm.Address, ls file.LoadSaver, dir string) {
+	ctx := r.Context()
+
+	if r.Method == http.MethodHead {
+		if acceptsJSON(r) {
+			w.Header().Set("Content-Type", jsonhttp.DefaultContentTypeHeader)
+		} else {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	release, ok := s.acquireDownload(w)
+	if !ok {
+		return
+	}
+	defer release()
+
+	entries, err := tarEntries(ctx, address, ls)
+	if err != nil {
+		logger.Debug("bzz download: list directory failed", "address", address, "path", dir, "error", err)
+		logger.Error(nil, "bzz download: list directory failed")
+		jsonhttp.NotFound(w, "path address not found")
+		return
+	}
+
+	res := dirListingResponse{Path: dir, Entries: []dirListingEntry{}}
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if !strings.HasPrefix(e.path, dir) || e.path == dir {
+			continue
+		}
+		name := e.path[len(dir):]
+		if i := strings.IndexByte(name, '/'); i >= 0 {
+			if name = name[:i]; !seen[name] {
+				seen[name] = true
+				res.Entries = append(res.Entries, dirListingEntry{Name: name, Type: dirListingTypeDirectory})
+			}
+			continue
+		}
+
+		ref := e.reference
+		entry := dirListingEntry{
+			Name:        name,
+			Type:        dirListingTypeFile,
+			ContentType: e.metadata[manifest.EntryMetadataContentTypeKey],
+			Reference:   &ref,
+		}
+		if _, size, err := joiner.New(ctx, s.storer, ref); err == nil {
+			entry.Size = size
+		} else {
+			logger.Debug("bzz download: list directory: file size unknown", "address", ref, "error", err)
+		}
+		res.Entries = append(res.Entries, entry)
+	}
+
+	if acceptsJSON(r) {
+		jsonhttp.OK(w, res)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := dirListingTemplate.Execute(w, res); err != nil {
+		logger.Debug("bzz download: render directory listing failed", "address", address, "path", dir, "error", err)
+		logger.Error(nil, "bzz download: render directory listing failed")
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestBzzDirListing(t *testing.T) {
+	t.Parallel()
+
+	files := []f{
+		{data: []byte("body { color: red; }"), name: "style.css", dir: "css"},
+		{data: []byte("{}"), name: "data.json", dir: "a/b"},
+		{data: []byte("<h1>Swarm</h1>"), name: "about.html", dir: "a"},
+		{data: []byte("swarm"), name: "readme.txt"},
+	}
+
+	upload := func(t *testing.T, client *http.Client) string {
+		t.Helper()
+
+		var resp api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestBody(tarFiles(t, files)),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		return "/bzz/" + resp.Reference.String() + "/"
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			Storer: smock.NewStorer(),
+			Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+			Logger: log.Noop,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+		})
+		root := upload(t, client)
+
+		jsonhttptest.Request(t, client, http.MethodGet, root, http.StatusNotFound)
+		jsonhttptest.Request(t, client, http.MethodGet, root+"a/", http.StatusNotFound)
+	})
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:     smock.NewStorer(),
+		Tags:       tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger:     log.Noop,
+		Post:       mockpost.New(mockpost.WithAcceptAll()),
+		DirListing: true,
+	})
+	root := upload(t, client)
+
+	t.Run("root json", func(t *testing.T) {
+		t.Parallel()
+
+		var res api.DirListingResponse
+		jsonhttptest.Request(t, client, http.MethodGet, root, http.StatusOK,
+			jsonhttptest.WithRequestHeader("Accept", "application/json"),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if res.Path != "" {
+			t.Fatalf("got path %q, want root", res.Path)
+		}
+		want := []struct {
+			name, typ   string
+			size        int64
+			contentType string
+		}{
+			{"a", "directory", 0, ""},
+			{"css", "directory", 0, ""},
+			{"readme.txt", "file", 5, "text/plain; charset=utf-8"},
+		}
+		if len(res.Entries) != len(want) {
+			t.Fatalf("got %d entries, want %d: %+v", len(res.Entries), len(want), res.Entries)
+		}
+		for i, w := range want {
+			e := res.Entries[i]
+			if e.Name != w.name || e.Type != w.typ || e.Size != w.size || e.ContentType != w.contentType {
+				t.Errorf("entry %d: got %+v, want %+v", i, e, w)
+			}
+			if got := e.Reference != nil; got != (w.typ == "file") {
+				t.Errorf("entry %d: got reference %v", i, e.Reference)
+			}
+		}
+	})
+
+	t.Run("subdirectory json", func(t *testing.T) {
+		t.Parallel()
+
+		var res api.DirListingResponse
+		jsonhttptest.Request(t, client, http.MethodGet, root+"a/", http.StatusOK,
+			jsonhttptest.WithRequestHeader("Accept", "application/json"),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if res.Path != "a/" || len(res.Entries) != 2 ||
+			res.Entries[0].Name != "about.html" || res.Entries[0].Size != 14 ||
+			res.Entries[1].Name != "b" || res.Entries[1].Type != "directory" {
+			t.Fatalf("got listing %+v", res)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		t.Parallel()
+
+		var body []byte
+		jsonhttptest.Request(t, client, http.MethodGet, root+"a/", http.StatusOK,
+			jsonhttptest.WithExpectedResponseHeader("Content-Type", "text/html; charset=utf-8"),
+			jsonhttptest.WithPutResponseBody(&body),
+		)
+		for _, want := range []string{
+			"<title>Index of /a/</title>",
+			`<a href="../">../</a>`,
+			`<a href="about.html">about.html</a>`,
+			`<a href="b/">b/</a>`,
+		} {
+			if !strings.Contains(string(body), want) {
+				t.Errorf("listing does not contain %q:\n%s", want, body)
+			}
+		}
+	})
+
+	t.Run("directory redirect", func(t *testing.T) {
+		t.Parallel()
+
+		// the client follows the redirect to the listing
+		var res api.DirListingResponse
+		jsonhttptest.Request(t, client, http.MethodGet, root+"a", http.StatusOK,
+			jsonhttptest.WithRequestHeader("Accept", "application/json"),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if res.Path != "a/" {
+			t.Fatalf("got path %q, want a/", res.Path)
+		}
+	})
+
+	t.Run("not directory", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, root+"missing/", http.StatusNotFound)
+	})
+}
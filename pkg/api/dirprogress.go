@@ -0,0 +1,127 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// contentTypeEventStream is the media type of the server-sent events.
+const contentTypeEventStream = "text/event-stream"
+
+// dirFileFunc is called with each file of the directory after it is stored.
+type dirFileFunc func(path string, reference swarm.Address, size int64)
+
+// requestEventStream reports whether the client accepts the server-sent events.
+func requestEventStream(r *http.Request) bool {
+	for _, v := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(v); err == nil && mediaType == contentTypeEventStream {
+			return true
+		}
+	}
+	return false
+}
+
+// fullDuplexContextKey is used to reference whether the request body can be
+// read after the response is started as context value.
+type fullDuplexContextKey struct{}
+
+// fullDuplexHandler lets the handlers of the requests accepting the server-sent
+// events read the request body after the response is started, which the HTTP/1.x
+// server does not allow by default, discarding the rest of the body. It is the
+// first handler of the chain, as the server response writer is not unwrapped.
+func fullDuplexHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestEventStream(r) && (r.ProtoMajor >= 2 || enableFullDuplex(w) == nil) {
+			r = r.WithContext(context.WithValue(r.Context(), fullDuplexContextKey{}, true))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// fullDuplex reports whether the request body can be read after the response
+// is started, which the events of the uploads require.
+func fullDuplex(r *http.Request) bool {
+	v, _ := r.Context().Value(fullDuplexContextKey{}).(bool)
+	return v
+}
+
+// dirFileEvent is the data of the event sent for each stored file.
+type dirFileEvent struct {
+	Path      string        `json:"path"`
+	Reference swarm.Address `json:"reference"`
+	Bytes     int64         `json:"bytes"`
+}
+
+// eventStreamWriter sends the progress of the directory upload as the
+// server-sent events. The events stream starts with the first stored file,
+// so that the errors before it get their usual responses. Afterwards the
+// final JSON response is sent as the done event, or the error event if
+// its status code is not successful.
+type eventStreamWriter struct {
+	http.ResponseWriter
+	started bool
+	code    int
+}
+
+// file sends the event of the stored file.
+func (ew *eventStreamWriter) file(path string, reference swarm.Address, size int64) {
+	data, err := json.Marshal(dirFileEvent{Path: path, Reference: reference, Bytes: size})
+	if err != nil {
+		return
+	}
+	ew.event("file", data)
+}
+
+// event sends the event with the JSON encoded data.
+func (ew *eventStreamWriter) event(name string, data []byte) {
+	if !ew.started {
+		ew.started = true
+		h := ew.ResponseWriter.Header()
+		h.Set(contentTypeHeader, contentTypeEventStream)
+		h.Set("Cache-Control", "no-cache")
+		h.Del("Content-Length")
+		ew.ResponseWriter.WriteHeader(http.StatusOK)
+	}
+	_, _ = fmt.Fprintf(ew.ResponseWriter, "event: %s\ndata: %s\n\n", name, bytes.TrimSpace(data))
+	if f, ok := ew.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (ew *eventStreamWriter) WriteHeader(code int) {
+	if !ew.started {
+		ew.ResponseWriter.WriteHeader(code)
+		return
+	}
+	ew.code = code
+}
+
+func (ew *eventStreamWriter) Write(b []byte) (int, error) {
+	if !ew.started {
+		return ew.ResponseWriter.Write(b)
+	}
+	name := "done"
+	if ew.code >= http.StatusBadRequest {
+		name = "error"
+	}
+	ew.event(name, b)
+	return len(b), nil
+}
+
+// Flush implements http.Flusher.
+func (ew *eventStreamWriter) Flush() {
+	if f, ok := ew.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/file"
 	"github.com/ethersphere/bee/pkg/file/loadsave"
@@ -33,8 +34,71 @@ import (
 
 var errEmptyDir = errors.New("no files in root directory")
 
-// dirUploadHandler uploads a directory supplied as a tar in an HTTP request
+// dirUploadHandler uploads a directory supplied as a tar in an HTTP request.
+// The clients accepting the server-sent events get an event per stored file.
 func (s *Service) dirUploadHandler(logger log.Logger, w http.ResponseWriter, r *http.Request, storer storage.Storer, waitFn func() error) {
+	var onFile dirFileFunc
+	if requestEventStream(r) {
+		if !fullDuplex(r) {
+			logger.Debug("dir upload: event stream over half duplex connection")
+			jsonhttp.NotAcceptable(w, "event stream requires HTTP/2 or a full duplex HTTP/1.x server")
+			return
+		}
+		ew := &eventStreamWriter{ResponseWriter: w}
+		w, onFile = ew, ew.file
+	}
+
+	reference, tag, ok := s.storeDirRequest(logger, w, r, storer, onFile)
+	if !ok {
+		return
+	}
+
+	if requestPin(r) {
+		if err := s.pinning.CreatePin(r.Context(), reference, false); err != nil {
+			logger.Debug("pin creation failed", "address", reference, "error", err)
+			logger.Error(nil, "pin creation failed")
+			jsonhttp.InternalServerError(w, "create pin failed")
+			return
+		}
+	}
+
+	if requestAct(r) {
+		var err error
+		reference, err = s.storeAccessManifest(r.Context(), r, storer, reference)
+		if err != nil {
+			logger.Debug("access manifest store failed", "error", err)
+			logger.Error(nil, "access manifest store failed")
+			actErrorResponse(w, err)
+			return
+		}
+	}
+
+	if err := waitFn(); err != nil {
+		logger.Debug("sync chunks failed", "error", err)
+		logger.Error(nil, "sync chunks failed")
+		jsonhttp.InternalServerError(w, "sync chunks failed")
+		return
+	}
+
+	if requestReadYourWrites(r) {
+		if err := s.checkReadable(r.Context(), reference); err != nil {
+			logger.Debug("read your writes check failed", "reference", reference, "error", err)
+			logger.Error(nil, "read your writes check failed")
+			jsonhttp.InternalServerError(w, errNotReadable)
+			return
+		}
+	}
+
+	respondUploaded(w, r, tag, bzzUploadResponse{
+		Reference: reference,
+	})
+}
+
+// storeDirRequest stores the directory supplied as a tar or multipart in
+// the HTTP request with the storer and returns the reference of its manifest
+// and the upload tag. The onFile function, if not nil, is called with each stored
+// file. The error response is written if the ok result is false.
+func (s *Service) storeDirRequest(logger log.Logger, w http.ResponseWriter, r *http.Request, storer storage.Storer, onFile dirFileFunc) (reference swarm.Address, tag *tags.Tag, ok bool) {
 	if r.Body == http.NoBody {
 		logger.Error(nil, "request has no body")
 		jsonhttp.BadRequest(w, errInvalidRequest)
@@ -47,7 +111,7 @@ func (s *Service) dirUploadHandler(logger log.Logger, w http.ResponseWriter, r *
 	var dReader dirReader
 	switch mediaType {
 	case contentTypeTar:
-		dReader = &tarReader{r: tar.NewReader(r.Body), logger: s.logger}
+		dReader = &tarReader{r: tar.NewReader(r.Body), logger: s.logger, modTime: requestPreserveMtime(r)}
 	case multiPartFormData:
 		dReader = &multipartReader{r: multipart.NewReader(r.Body, params["boundary"])}
 	default:
@@ -57,32 +121,41 @@ func (s *Service) dirUploadHandler(logger log.Logger, w http.ResponseWriter, r *
 	}
 	defer r.Body.Close()
 
-	tag, created, err := s.getOrCreateTag(r.Header.Get(SwarmTagHeader))
+	tag, created, err := s.getOrCreateUploadTag(storer, r.Header.Get(SwarmTagHeader), r.Header.Get(SwarmTagNameHeader))
 	if err != nil {
 		logger.Debug("get or create tag failed", "error", err)
 		logger.Error(nil, "get or create tag failed")
 		switch {
 		case errors.Is(err, tags.ErrNotFound):
 			jsonhttp.NotFound(w, "tag not found")
+		case errors.Is(err, tags.ErrNameTooLong):
+			jsonhttp.BadRequest(w, "invalid tag name")
 		default:
 			jsonhttp.InternalServerError(w, "cannot get or create tag")
 		}
+		return
 	}
 
 	// Add the tag to the context
 	ctx := sctx.SetTag(r.Context(), tag)
 
-	reference, err := storeDir(
+	// the coding was already validated by the caller
+	compression, _ := requestCompression(r)
+
+	reference, err = storeDir(
 		ctx,
 		requestEncrypt(r),
 		dReader,
 		s.logger,
 		requestPipelineFn(storer, r),
+		compression,
 		loadsave.New(storer, requestPipelineFactory(ctx, storer, r)),
 		r.Header.Get(SwarmIndexDocumentHeader),
 		r.Header.Get(SwarmErrorDocumentHeader),
+		r.Header.Get(SwarmWebsiteRedirectsHeader),
 		tag,
 		created,
+		onFile,
 	)
 	if err != nil {
 		logger.Debug("store dir failed", "error", err)
@@ -94,6 +167,8 @@ func (s *Service) dirUploadHandler(logger log.Logger, w http.ResponseWriter, r *
 			jsonhttp.BadRequest(w, errEmptyDir)
 		case errors.Is(err, tar.ErrHeader):
 			jsonhttp.BadRequest(w, "invalid filename in tar archive")
+		case errors.Is(err, errInvalidWebsiteRedirects):
+			jsonhttp.BadRequest(w, errInvalidWebsiteRedirects)
 		default:
 			jsonhttp.InternalServerError(w, errDirectoryStore)
 		}
@@ -109,42 +184,26 @@ func (s *Service) dirUploadHandler(logger log.Logger, w http.ResponseWriter, r *
 		}
 	}
 
-	if requestPin(r) {
-		if err := s.pinning.CreatePin(r.Context(), reference, false); err != nil {
-			logger.Debug("pin creation failed", "address", reference, "error", err)
-			logger.Error(nil, "pin creation failed")
-			jsonhttp.InternalServerError(w, "create pin failed")
-			return
-		}
-	}
-
-	if err = waitFn(); err != nil {
-		logger.Debug("sync chunks failed", "error", err)
-		logger.Error(nil, "sync chunks failed")
-		jsonhttp.InternalServerError(w, "sync chunks failed")
-		return
-	}
-
-	w.Header().Set("Access-Control-Expose-Headers", SwarmTagHeader)
-	w.Header().Set(SwarmTagHeader, fmt.Sprint(tag.Uid))
-	jsonhttp.Created(w, bzzUploadResponse{
-		Reference: reference,
-	})
+	return reference, tag, true
 }
 
 // storeDir stores all files recursively contained in the directory given as a tar/multipart
-// it returns the hash for the uploaded manifest corresponding to the uploaded dir
+// it returns the hash for the uploaded manifest corresponding to the uploaded dir.
+// The onFile function, if not nil, is called with each file after it is stored.
 func storeDir(
 	ctx context.Context,
 	encrypt bool,
 	reader dirReader,
 	log log.Logger,
 	p pipelineFunc,
+	compression string,
 	ls file.LoadSaver,
 	indexFilename,
-	errorFilename string,
+	errorFilename,
+	redirects string,
 	tag *tags.Tag,
 	tagCreated bool,
+	onFile dirFileFunc,
 ) (swarm.Address, error) {
 	logger := tracing.NewLoggerWithTraceID(ctx, log)
 	loggerV1 := logger.V(1).Build()
@@ -157,8 +216,20 @@ func storeDir(
 	if indexFilename != "" && strings.ContainsRune(indexFilename, '/') {
 		return swarm.ZeroAddress, errors.New("index document suffix must not include slash character")
 	}
+	if redirects != "" {
+		var err error
+		if _, redirects, err = parseWebsiteRedirects(redirects); err != nil {
+			return swarm.ZeroAddress, err
+		}
+	}
 
-	filesAdded := 0
+	// the entries are added to the manifest after all files are stored,
+	// so that the precompressed variants can be linked to their originals
+	// regardless of the order of the files
+	var (
+		paths   []string
+		entries = make(map[string]manifest.Entry)
+	)
 
 	// iterate through the files in the supplied tar
 	for {
@@ -180,32 +251,54 @@ func storeDir(
 			}
 		}
 
-		fileReference, err := p(ctx, fileInfo.Reader)
+		// the precompressed variants are not compressed again
+		fileCompression := compression
+		if isPrecompressedVariant(fileInfo.Path) {
+			fileCompression = ""
+		}
+		body := &countingReader{r: fileInfo.Reader}
+		fileReference, err := compressedPipelineFn(p, fileCompression)(ctx, body)
 		if err != nil {
 			return swarm.ZeroAddress, fmt.Errorf("store dir file: %w", err)
 		}
 		loggerV1.Debug("bzz upload dir: file dir uploaded", "file_path", fileInfo.Path, "address", fileReference)
+		if onFile != nil {
+			onFile(fileInfo.Path, fileReference, body.n)
+		}
 
 		fileMtdt := map[string]string{
 			manifest.EntryMetadataContentTypeKey: fileInfo.ContentType,
 			manifest.EntryMetadataFilenameKey:    fileInfo.Name,
 		}
-		// add file entry to dir manifest
-		err = dirManifest.Add(ctx, fileInfo.Path, manifest.NewEntry(fileReference, fileMtdt))
-		if err != nil {
-			return swarm.ZeroAddress, fmt.Errorf("add to manifest: %w", err)
+		if fileCompression != "" {
+			fileMtdt[manifest.EntryMetadataContentEncodingKey] = fileCompression
 		}
-
-		filesAdded++
+		if fileInfo.ModTime.Unix() > 0 {
+			fileMtdt[manifest.EntryMetadataModTimeKey] = strconv.FormatInt(fileInfo.ModTime.Unix(), 10)
+		}
+		if _, ok := entries[fileInfo.Path]; !ok {
+			paths = append(paths, fileInfo.Path)
+		}
+		entries[fileInfo.Path] = manifest.NewEntry(fileReference, fileMtdt)
 	}
 
 	// check if files were uploaded through the manifest
-	if filesAdded == 0 {
+	if len(paths) == 0 {
 		return swarm.ZeroAddress, errEmptyDir
 	}
 
+	linkPrecompressedVariants(entries)
+
+	// add file entries to dir manifest
+	for _, path := range paths {
+		err = dirManifest.Add(ctx, path, entries[path])
+		if err != nil {
+			return swarm.ZeroAddress, fmt.Errorf("add to manifest: %w", err)
+		}
+	}
+
 	// store website information
-	if indexFilename != "" || errorFilename != "" {
+	if indexFilename != "" || errorFilename != "" || redirects != "" {
 		metadata := map[string]string{}
 		if indexFilename != "" {
 			metadata[manifest.WebsiteIndexDocumentSuffixKey] = indexFilename
@@ -213,6 +306,9 @@ func storeDir(
 		if errorFilename != "" {
 			metadata[manifest.WebsiteErrorDocumentPathKey] = errorFilename
 		}
+		if redirects != "" {
+			metadata[manifest.WebsiteRedirectsKey] = redirects
+		}
 		rootManifestEntry := manifest.NewEntry(swarm.ZeroAddress, metadata)
 		err = dirManifest.Add(ctx, manifest.RootPath, rootManifestEntry)
 		if err != nil {
@@ -250,6 +346,7 @@ type FileInfo struct {
 	Name        string
 	ContentType string
 	Size        int64
+	ModTime     time.Time
 	Reader      io.Reader
 }
 
@@ -258,8 +355,9 @@ type dirReader interface {
 }
 
 type tarReader struct {
-	r      *tar.Reader
-	logger log.Logger
+	r       *tar.Reader
+	logger  log.Logger
+	modTime bool // the modification times of the files are kept
 }
 
 func (t *tarReader) Next() (*FileInfo, error) {
@@ -288,13 +386,17 @@ func (t *tarReader) Next() (*FileInfo, error) {
 			continue
 		}
 
-		return &FileInfo{
+		fi := &FileInfo{
 			Path:        filePath,
 			Name:        fileName,
 			ContentType: contentType,
 			Size:        fileSize,
 			Reader:      t.r,
-		}, nil
+		}
+		if t.modTime {
+			fi.ModTime = fileHeader.ModTime
+		}
+		return fi, nil
 	}
 }
 
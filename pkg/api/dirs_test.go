@@ -8,6 +8,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -15,7 +16,9 @@ import (
 	"net/textproto"
 	"path"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/api"
 	"github.com/ethersphere/bee/pkg/file/loadsave"
@@ -505,6 +508,167 @@ func TestDirs(t *testing.T) {
 	})
 }
 
+func TestDirsEventStream(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: mock.NewStorer(),
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	files := []f{
+		{data: []byte("<h1>Swarm"), name: "index.html"},
+		{data: bytes.Repeat([]byte("a"), 5000), name: "data.bin", dir: "files"},
+	}
+
+	if !api.FullDuplexSupported {
+		// the HTTP/1.x server discards the rest of the
+		// request body once the first event is sent
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusNotAcceptable,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestHeader("Accept", "text/event-stream"),
+			jsonhttptest.WithRequestBody(tarFiles(t, files)),
+		)
+		return
+	}
+
+	type event struct {
+		name, data string
+	}
+	parseEvents := func(t *testing.T, body []byte) []event {
+		t.Helper()
+
+		var events []event
+		for _, block := range strings.Split(strings.TrimSpace(string(body)), "\n\n") {
+			var e event
+			for _, line := range strings.Split(block, "\n") {
+				switch {
+				case strings.HasPrefix(line, "event: "):
+					e.name = strings.TrimPrefix(line, "event: ")
+				case strings.HasPrefix(line, "data: "):
+					e.data = strings.TrimPrefix(line, "data: ")
+				}
+			}
+			events = append(events, e)
+		}
+		return events
+	}
+
+	t.Run("progress", func(t *testing.T) {
+		t.Parallel()
+
+		var want api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestBody(tarFiles(t, files)),
+			jsonhttptest.WithUnmarshalJSONResponse(&want),
+		)
+
+		var body []byte
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusOK,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestHeader("Accept", "text/event-stream"),
+			jsonhttptest.WithRequestBody(tarFiles(t, files)),
+			jsonhttptest.WithExpectedResponseHeader("Content-Type", "text/event-stream"),
+			jsonhttptest.WithPutResponseBody(&body),
+		)
+
+		events := parseEvents(t, body)
+		if len(events) != len(files)+1 {
+			t.Fatalf("got %d events, want %d: %s", len(events), len(files)+1, body)
+		}
+		for i, file := range files {
+			if events[i].name != "file" {
+				t.Fatalf("got event %q, want file", events[i].name)
+			}
+			var got api.DirFileEvent
+			if err := json.Unmarshal([]byte(events[i].data), &got); err != nil {
+				t.Fatal(err)
+			}
+			if p := path.Join(file.dir, file.name); got.Path != p {
+				t.Fatalf("got path %q, want %q", got.Path, p)
+			}
+			if got.Bytes != int64(len(file.data)) {
+				t.Fatalf("got %d bytes, want %d", got.Bytes, len(file.data))
+			}
+			if got.Reference.IsZero() {
+				t.Fatal("got zero file reference")
+			}
+		}
+
+		done := events[len(files)]
+		if done.name != "done" {
+			t.Fatalf("got event %q, want done", done.name)
+		}
+		var got api.BzzUploadResponse
+		if err := json.Unmarshal([]byte(done.data), &got); err != nil {
+			t.Fatal(err)
+		}
+		if !got.Reference.Equal(want.Reference) {
+			t.Fatalf("got reference %s, want %s", got.Reference, want.Reference)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		// the second file is cut short
+		tr := tarFiles(t, files).Bytes()
+		tr = tr[:len(tr)-3*1024]
+
+		var body []byte
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusOK,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestHeader("Accept", "text/event-stream"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(tr)),
+			jsonhttptest.WithPutResponseBody(&body),
+		)
+
+		events := parseEvents(t, body)
+		if len(events) != 2 || events[0].name != "file" || events[1].name != "error" {
+			t.Fatalf("got events %v, want file and error", events)
+		}
+		var got jsonhttp.StatusResponse
+		if err := json.Unmarshal([]byte(events[1].data), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Code != http.StatusInternalServerError {
+			t.Fatalf("got error code %d, want %d", got.Code, http.StatusInternalServerError)
+		}
+	})
+
+	t.Run("no files", func(t *testing.T) {
+		t.Parallel()
+
+		// the errors before the first file get their usual responses
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestHeader("Accept", "text/event-stream"),
+			jsonhttptest.WithRequestBody(tarEmptyDir(t)),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: api.EmptyDir.Error(),
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
+
 func TestEmtpyDir(t *testing.T) {
 	t.Parallel()
 
@@ -553,9 +717,10 @@ func tarFiles(t *testing.T, files []f) *bytes.Buffer {
 
 		// create tar header and write it
 		hdr := &tar.Header{
-			Name: filePath,
-			Mode: 0600,
-			Size: int64(len(file.data)),
+			Name:    filePath,
+			Mode:    0600,
+			Size:    int64(len(file.data)),
+			ModTime: file.modTime,
 		}
 		if err := tw.WriteHeader(hdr); err != nil {
 			t.Fatal(err)
@@ -646,4 +811,5 @@ type f struct {
 	dir      string
 	filePath string
 	header   http.Header
+	modTime  time.Time
 }
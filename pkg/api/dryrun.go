@@ -0,0 +1,108 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+// SwarmChunkCountHeader is the header of the number of the chunks
+// of the content, which is set on the responses of the dry-run uploads.
+const SwarmChunkCountHeader = "Swarm-Chunk-Count"
+
+// dryRunPutter discards the chunks of the dry-run uploads, which are only
+// hashed, and counts them. The chunks are neither stamped nor stored.
+type dryRunPutter struct {
+	storage.Storer
+	mu     sync.Mutex
+	chunks map[string]struct{}
+}
+
+func newDryRunPutter(s storage.Storer) *dryRunPutter {
+	return &dryRunPutter{Storer: s, chunks: make(map[string]struct{})}
+}
+
+func (p *dryRunPutter) Put(_ context.Context, _ storage.ModePut, chs ...swarm.Chunk) (exists []bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	exists = make([]bool, len(chs))
+	for i, ch := range chs {
+		key := ch.Address().ByteString()
+		_, exists[i] = p.chunks[key]
+		p.chunks[key] = struct{}{}
+	}
+	return exists, nil
+}
+
+// count returns the number of the distinct chunks, each
+// of which would consume a stamp of the actual upload.
+func (p *dryRunPutter) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.chunks)
+}
+
+// dryRunResponseWriter sets the chunk count on the response of the dry-run
+// upload, which is successful with the OK status as nothing is created. The
+// tag of the upload is not exposed, as it is not saved.
+type dryRunResponseWriter struct {
+	http.ResponseWriter
+	putter *dryRunPutter
+}
+
+func (w *dryRunResponseWriter) WriteHeader(code int) {
+	if code == http.StatusCreated || code == http.StatusAccepted {
+		h := w.Header()
+		h.Del(SwarmTagHeader)
+		exposed := h.Values("Access-Control-Expose-Headers")
+		h.Del("Access-Control-Expose-Headers")
+		for _, v := range exposed {
+			if v != SwarmTagHeader {
+				h.Add("Access-Control-Expose-Headers", v)
+			}
+		}
+		h.Set(SwarmChunkCountHeader, strconv.Itoa(w.putter.count()))
+		h.Add("Access-Control-Expose-Headers", SwarmChunkCountHeader)
+		code = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// getOrCreateUploadTag returns the tag of the upload with the putter as
+// getOrCreateTag does, except for the dry-run uploads, which store nothing
+// and get a new tag which is neither saved nor exposed.
+func (s *Service) getOrCreateUploadTag(putter storage.Storer, tagUid, tagName string) (*tags.Tag, bool, error) {
+	if _, ok := putter.(*dryRunPutter); ok {
+		return tags.NewTag(context.Background(), 0, 0, nil, nil, s.logger), true, nil
+	}
+	return s.getOrCreateTag(tagUid, tagName)
+}
+
+// newUploadPutter returns the putter and the response writer of the upload,
+// which only hashes the content if the dry run is requested. The pinning and
+// the readability check of the content are skipped in the dry run, as the
+// chunks are not stored.
+func (s *Service) newUploadPutter(w http.ResponseWriter, r *http.Request, dryRun bool) (storage.Storer, func() error, http.ResponseWriter, error) {
+	if !dryRun {
+		putter, wait, err := s.newStamperPutter(r)
+		return putter, wait, w, err
+	}
+
+	r.Header.Del(SwarmPinHeader)
+	r.Header.Del(SwarmReadYourWritesHeader)
+	r.Header.Del("Prefer")
+
+	p := newDryRunPutter(s.storer)
+	return p, noopWaitFn, &dryRunResponseWriter{ResponseWriter: w, putter: p}, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/resolver"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+)
+
+type ensPublishResponse struct {
+	Name      string        `json:"name"`
+	Reference swarm.Address `json:"reference"`
+	TxHash    string        `json:"txHash"`
+}
+
+// ensPublishHandler sets the content hash record of the ENS name to the
+// reference, such as of the feed manifest of the deployed website, with
+// the transaction signed by the node, which must own the name. The
+// transaction is sent to the ENS endpoint of the name resolver.
+func (s *Service) ensPublishHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_ens").Build()
+
+	paths := struct {
+		Name    string        `map:"name" validate:"required"`
+		Address swarm.Address `map:"address" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	publisher, ok := s.resolver.(resolver.Publisher)
+	if !ok {
+		logger.Debug("ens publish: no publisher", "name", paths.Name)
+		logger.Error(nil, "ens publish: no publisher")
+		jsonhttp.NotImplemented(w, "no name publisher")
+		return
+	}
+
+	txHash, err := publisher.Publish(r.Context(), s.signer, paths.Name, paths.Address)
+	if err != nil {
+		logger.Debug("ens publish: publish failed", "name", paths.Name, "address", paths.Address, "error", err)
+		logger.Error(nil, "ens publish: publish failed")
+		switch {
+		case errors.Is(err, resolver.ErrNoPublisher):
+			jsonhttp.NotImplemented(w, "no name publisher")
+		case errors.Is(err, resolver.ErrNotOwner):
+			jsonhttp.Forbidden(w, "name not owned by the node")
+		case errors.Is(err, resolver.ErrServiceNotAvailable):
+			jsonhttp.ServiceUnavailable(w, "name service not available")
+		default:
+			jsonhttp.InternalServerError(w, "publish failed")
+		}
+		return
+	}
+
+	jsonhttp.Accepted(w, ensPublishResponse{
+		Name:      paths.Name,
+		Reference: paths.Address,
+		TxHash:    txHash.String(),
+	})
+}
@@ -0,0 +1,106 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/resolver"
+	resolverMock "github.com/ethersphere/bee/pkg/resolver/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestEnsPublish(t *testing.T) {
+	t.Parallel()
+
+	var (
+		reference = swarm.RandAddress(t)
+		txHash    = common.HexToHash("0x1234")
+	)
+
+	publish := func(_ crypto.Signer, name string, addr resolver.Address) (common.Hash, error) {
+		switch name {
+		case "website.eth":
+			if !addr.Equal(reference) {
+				return common.Hash{}, errors.New("unexpected reference")
+			}
+			return txHash, nil
+		case "other.eth":
+			return common.Hash{}, fmt.Errorf("owner: %w", resolver.ErrNotOwner)
+		case "website.none":
+			return common.Hash{}, resolver.ErrNoPublisher
+		default:
+			return common.Hash{}, fmt.Errorf("dial: %w", resolver.ErrServiceNotAvailable)
+		}
+	}
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI: true,
+		Resolver: resolverMock.NewResolver(resolverMock.WithPublishFunc(publish)),
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/ens/website.eth/"+reference.String(), http.StatusAccepted,
+			jsonhttptest.WithExpectedJSONResponse(api.EnsPublishResponse{
+				Name:      "website.eth",
+				Reference: reference,
+				TxHash:    txHash.String(),
+			}),
+		)
+	})
+
+	for _, tc := range []struct {
+		name string
+		want jsonhttp.StatusResponse
+	}{
+		{
+			name: "other.eth",
+			want: jsonhttp.StatusResponse{Code: http.StatusForbidden, Message: "name not owned by the node"},
+		},
+		{
+			name: "website.none",
+			want: jsonhttp.StatusResponse{Code: http.StatusNotImplemented, Message: "no name publisher"},
+		},
+		{
+			name: "unavailable.eth",
+			want: jsonhttp.StatusResponse{Code: http.StatusServiceUnavailable, Message: "name service not available"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			jsonhttptest.Request(t, client, http.MethodPost, "/ens/"+tc.name+"/"+reference.String(), tc.want.Code,
+				jsonhttptest.WithExpectedJSONResponse(tc.want),
+			)
+		})
+	}
+
+	t.Run("invalid address", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/ens/website.eth/123", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusBadRequest,
+				Message: "invalid path params",
+				Reasons: []jsonhttp.Reason{
+					{
+						Field: "address",
+						Error: api.ErrHexLength.Error(),
+					},
+				},
+			}),
+		)
+	})
+}
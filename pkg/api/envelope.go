@@ -0,0 +1,123 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+)
+
+type postEnvelopeResponse struct {
+	Issuer    string `json:"issuer"`
+	BatchID   string `json:"batchID"`
+	Index     string `json:"index"`
+	Timestamp string `json:"timestamp"`
+	Signature string `json:"signature"`
+	Stamp     string `json:"stamp"`
+}
+
+// envelopePostHandler issues the postage stamp of the batch of the node for
+// the chunk address without the upload of the chunk, so that the chunk can be
+// uploaded with the stamp later by anyone.
+func (s *Service) envelopePostHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_envelope").Build()
+
+	paths := struct {
+		Address swarm.Address `map:"address" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+	if len(paths.Address.Bytes()) != swarm.HashSize {
+		jsonhttp.BadRequest(w, "invalid address length")
+		return
+	}
+
+	batch, err := requestPostageBatchId(r)
+	if err != nil {
+		logger.Debug("invalid batch id", "error", err)
+		jsonhttp.BadRequest(w, "invalid batch id")
+		return
+	}
+	hexBatchID := hex.EncodeToString(batch)
+
+	exists, err := s.batchStore.Exists(batch)
+	if err != nil {
+		logger.Debug("exist check failed", "batch_id", hexBatchID, "error", err)
+		logger.Error(nil, "exist check failed")
+		jsonhttp.InternalServerError(w, "batch exists check failed")
+		return
+	}
+
+	issuer, save, err := s.post.GetStampIssuer(batch)
+	if err != nil {
+		logger.Debug("get issuer failed", "batch_id", hexBatchID, "error", err)
+		logger.Error(nil, "get issuer failed")
+		switch {
+		case errors.Is(err, postage.ErrNotUsable):
+			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+		case errors.Is(err, postage.ErrNotFound):
+			jsonhttp.NotFound(w, "batch with id not found")
+		default:
+			jsonhttp.InternalServerError(w, "get issuer failed")
+		}
+		return
+	}
+	defer func() {
+		if err := save(); err != nil {
+			logger.Debug("stamp issuer save", "batch_id", hexBatchID, "error", err)
+		}
+	}()
+
+	if usable := exists && s.post.IssuerUsable(issuer); !usable {
+		jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+		return
+	}
+
+	owner, err := s.signer.EthereumAddress()
+	if err != nil {
+		logger.Debug("get owner failed", "error", err)
+		logger.Error(nil, "get owner failed")
+		jsonhttp.InternalServerError(w, "get owner failed")
+		return
+	}
+
+	stamp, err := s.newStamper(issuer).Stamp(paths.Address)
+	if err != nil {
+		logger.Debug("stamp failed", "batch_id", hexBatchID, "address", paths.Address, "error", err)
+		logger.Error(nil, "stamp failed")
+		switch {
+		case errors.Is(err, postage.ErrBucketFull):
+			jsonhttp.PaymentRequired(w, "batch is overissued")
+		default:
+			jsonhttp.InternalServerError(w, "stamp failed")
+		}
+		return
+	}
+
+	b, err := stamp.MarshalBinary()
+	if err != nil {
+		logger.Debug("marshal stamp failed", "error", err)
+		logger.Error(nil, "marshal stamp failed")
+		jsonhttp.InternalServerError(w, "marshal stamp failed")
+		return
+	}
+
+	jsonhttp.Created(w, postEnvelopeResponse{
+		Issuer:    hex.EncodeToString(owner.Bytes()),
+		BatchID:   hexBatchID,
+		Index:     hex.EncodeToString(stamp.Index()),
+		Timestamp: hex.EncodeToString(stamp.Timestamp()),
+		Signature: hex.EncodeToString(stamp.Sig()),
+		Stamp:     hex.EncodeToString(b),
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/postage"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestPostEnvelope(t *testing.T) {
+	t.Parallel()
+
+	var (
+		addr     = swarm.MustParseHexAddress("7351ce957ff75417ca7d4f758e3eb627d4f40cd52d53967b855c4e52fea8bfaf")
+		resource = "/envelope/" + addr.String()
+	)
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			Post: mockpost.New(mockpost.WithAcceptAll()),
+		})
+
+		var res api.PostEnvelopeResponse
+		jsonhttptest.Request(t, client, http.MethodPost, resource, http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if res.BatchID != batchOkStr {
+			t.Fatalf("got batch id %s, want %s", res.BatchID, batchOkStr)
+		}
+
+		b, err := hex.DecodeString(res.Stamp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stamp := new(postage.Stamp)
+		if err := stamp.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(stamp.Sig()); got != res.Signature {
+			t.Fatalf("got stamp signature %s, want %s", got, res.Signature)
+		}
+		if got := hex.EncodeToString(stamp.Index()); got != res.Index {
+			t.Fatalf("got stamp index %s, want %s", got, res.Index)
+		}
+
+		issuer, err := hex.DecodeString(res.Issuer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the depths of the stamp issuer of the postage mock
+		if err := stamp.Valid(addr, issuer, 24, 6, true); err != nil {
+			t.Fatalf("invalid stamp: %v", err)
+		}
+	})
+
+	t.Run("batch not found", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			Post: mockpost.New(),
+		})
+
+		jsonhttptest.Request(t, client, http.MethodPost, resource, http.StatusNotFound,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "batch with id not found",
+				Code:    http.StatusNotFound,
+			}),
+		)
+	})
+
+	t.Run("invalid batch id", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			Post: mockpost.New(mockpost.WithAcceptAll()),
+		})
+
+		jsonhttptest.Request(t, client, http.MethodPost, resource, http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid batch id",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			Post: mockpost.New(mockpost.WithAcceptAll()),
+		})
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/envelope/abcd", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		)
+	})
+}
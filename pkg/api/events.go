@@ -0,0 +1,56 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/eventlog"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+)
+
+const maxEventsLimit = 1000 // the maximal number of events in a response
+
+type eventsResponse struct {
+	Events    []eventlog.Event `json:"events"`
+	Cursor    uint64           `json:"cursor"`
+	Truncated bool             `json:"truncated"`
+}
+
+// eventsHandler returns the events of the node following the cursor given
+// in the query, the cursor of the response is used for the next request.
+func (s *Service) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_events").Build()
+
+	if s.events == nil {
+		jsonhttp.NotImplemented(w, "events are not available")
+		return
+	}
+
+	queries := struct {
+		Cursor uint64 `map:"cursor"`
+		Limit  int    `map:"limit"`
+	}{
+		Limit: 100, // Default limit.
+	}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+	if queries.Limit <= 0 || queries.Limit > maxEventsLimit {
+		queries.Limit = maxEventsLimit
+	}
+
+	events, cursor, truncated := s.events.Events(queries.Cursor, queries.Limit)
+	if events == nil {
+		events = []eventlog.Event{}
+	}
+
+	jsonhttp.OK(w, eventsResponse{
+		Events:    events,
+		Cursor:    cursor,
+		Truncated: truncated,
+	})
+}
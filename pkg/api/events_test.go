@@ -0,0 +1,76 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/eventlog"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+)
+
+func TestEvents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not available", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+		})
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/events", http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "events are not available",
+				Code:    http.StatusNotImplemented,
+			}),
+		)
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		t.Parallel()
+
+		l, err := eventlog.New(statestore.NewStateStore(), 0, log.Noop)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = l.Close() })
+		for i := 1; i <= 3; i++ {
+			l.Append(eventlog.TypeTopology, eventlog.Topology{Connected: i})
+		}
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+			Events:   l,
+		})
+
+		var got api.EventsResponse
+		jsonhttptest.Request(t, client, http.MethodGet, "/events?limit=2", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&got),
+		)
+		if len(got.Events) != 2 || got.Events[0].Seq != 1 || got.Cursor != 2 || got.Truncated {
+			t.Fatalf("got response %+v", got)
+		}
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/events?cursor=2", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&got),
+		)
+		if len(got.Events) != 1 || got.Events[0].Seq != 3 || got.Events[0].Type != eventlog.TypeTopology || got.Cursor != 3 {
+			t.Fatalf("got response %+v", got)
+		}
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/events?cursor=3", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.EventsResponse{
+				Events: []eventlog.Event{},
+				Cursor: 3,
+			}),
+		)
+	})
+}
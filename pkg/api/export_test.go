@@ -5,22 +5,66 @@
 package api
 
 import (
+	"context"
+	"time"
+
 	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/pusher"
+	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/langos"
 )
 
 type (
-	BytesPostResponse     = bytesPostResponse
-	ChunkAddressResponse  = chunkAddressResponse
-	SocPostResponse       = socPostResponse
-	FeedReferenceResponse = feedReferenceResponse
-	BzzUploadResponse     = bzzUploadResponse
-	DebugTagResponse      = debugTagResponse
-	TagRequest            = tagRequest
-	ListTagsResponse      = listTagsResponse
-	IsRetrievableResponse = isRetrievableResponse
-	SecurityTokenResponse = securityTokenRsp
-	SecurityTokenRequest  = securityTokenReq
+	BytesPostResponse          = bytesPostResponse
+	HashResponse               = hashResponse
+	ChunkAddressResponse       = chunkAddressResponse
+	SocPostResponse            = socPostResponse
+	SocVerifyResponse          = socVerifyResponse
+	PostEnvelopeResponse       = postEnvelopeResponse
+	PostageVerifyStampResponse = postageVerifyStampResponse
+	FeedReferenceResponse      = feedReferenceResponse
+	BzzUploadResponse          = bzzUploadResponse
+	DebugTagResponse           = debugTagResponse
+	TagRequest                 = tagRequest
+	ListTagsResponse           = listTagsResponse
+	IsRetrievableResponse      = isRetrievableResponse
+	WarmJobResponse            = warmJobResponse
+	JobResponse                = jobResponse
+	JobsResponse               = jobsResponse
+	DeployResponse             = deployResponse
+	DeployHistoryResponse      = deployHistoryResponse
+	CrdtRegisterResponse       = crdtRegisterResponse
+	CrdtCounterResponse        = crdtCounterResponse
+	CrdtCount                  = crdtCount
+	CrdtIncrementResponse      = crdtIncrementResponse
+	PssSessionResponse         = pssSessionResponse
+	PssSessionsResponse        = pssSessionsResponse
+	BroadcastResponse          = broadcastResponse
+	IpfsImportResponse         = ipfsImportResponse
+	LegacyImportResponse       = legacyImportResponse
+	ActGranteesResponse        = actGranteesResponse
+	ActGranteesPatchRequest    = actGranteesPatchRequest
+	ActGranteesPatchResponse   = actGranteesPatchResponse
+	ChallengeResponse          = challengeResponse
+	ChallengeRequest           = challengeRequest
+	ChallengeTokenResponse     = challengeTokenResponse
+	AliasFeed                  = aliasFeed
+	AliasRequest               = aliasRequest
+	AliasResponse              = aliasResponse
+	AliasesResponse            = aliasesResponse
+	AnalyticsResponse          = analyticsResponse
+	AnalyticsReference         = analyticsReference
+	AnalyticsCounts            = analyticsCounts
+	SecurityTokenResponse      = securityTokenRsp
+	SecurityTokenRequest       = securityTokenReq
+	FaultRule                  = faultRule
+	FaultRulesResponse         = faultRulesResponse
+	DownloadLimitsRequest      = downloadLimitsRequest
+	DownloadLimitsResponse     = downloadLimitsResponse
+	DirListingResponse         = dirListingResponse
+	ManifestNodeResponse       = manifestNodeResponse
 )
 
 var (
@@ -31,8 +75,10 @@ var (
 )
 
 var (
-	ContentTypeTar    = contentTypeTar
-	ContentTypeHeader = contentTypeHeader
+	ContentTypeTar           = contentTypeTar
+	ContentTypeHeader        = contentTypeHeader
+	TarPAXContentTypeKey     = tarPAXContentTypeKey
+	TarPAXContentEncodingKey = tarPAXContentEncodingKey
 )
 
 var (
@@ -55,8 +101,8 @@ var (
 	ToFileSizeBucket      = toFileSizeBucket
 )
 
-func (s *Service) ResolveNameOrAddress(str string) (swarm.Address, error) {
-	return s.resolveNameOrAddress(str)
+func (s *Service) ResolveNameOrAddress(ctx context.Context, str string) (swarm.Address, error) {
+	return s.resolveNameOrAddress(ctx, str)
 }
 
 func CalculateNumberOfChunks(contentLength int64, isEncrypted bool) int64 {
@@ -67,8 +113,10 @@ type (
 	HealthStatusResponse              = healthStatusResponse
 	NodeResponse                      = nodeResponse
 	PingpongResponse                  = pingpongResponse
+	RetrievalPeerResponse             = retrievalPeerResponse
 	PeerConnectResponse               = peerConnectResponse
 	PeersResponse                     = peersResponse
+	PinsResponse                      = pinsResponse
 	AddressesResponse                 = addressesResponse
 	WelcomeMessageRequest             = welcomeMessageRequest
 	WelcomeMessageResponse            = welcomeMessageResponse
@@ -92,13 +140,22 @@ type (
 	TransactionHashResponse           = transactionHashResponse
 	TagResponse                       = tagResponse
 	ReserveStateResponse              = reserveStateResponse
+	ReserveEvictResponse              = reserveEvictResponse
+	DepthMonitorResponse              = depthMonitorResponse
+	ReplicaStatusResponse             = replicaStatusResponse
+	MaintenanceResponse               = maintenanceResponse
+	EventsResponse                    = eventsResponse
 	ChainStateResponse                = chainStateResponse
+	PostageEstimateResponse           = postageEstimateResponse
+	EnsPublishResponse                = ensPublishResponse
 	PostageCreateResponse             = postageCreateResponse
 	PostageStampResponse              = postageStampResponse
 	PostageStampsResponse             = postageStampsResponse
 	PostageBatchResponse              = postageBatchResponse
 	PostageStampBucketsResponse       = postageStampBucketsResponse
 	BucketData                        = bucketData
+	PostageRestampResponse            = postageRestampResponse
+	UploadSessionResponse             = uploadSessionResponse
 	WalletResponse                    = walletResponse
 	GetStakeResponse                  = getStakeResponse
 	WithdrawAllStakeResponse          = withdrawAllStakeResponse
@@ -135,6 +192,78 @@ func ReplaceLogSetVerbosityByExp(fn LogSetVerbosityByExpFn) { logSetVerbosityByE
 
 var ErrHexLength = errHexLength
 
+const MaxOpenEndedRangeSize = maxOpenEndedRangeSize
+
+const (
+	MinAdaptiveBufferSize  = minAdaptiveBufferSize
+	MaxAdaptiveBufferSize  = maxAdaptiveBufferSize
+	MediaSegmentBufferSize = mediaSegmentBufferSize
+	SmallFileBufferSize    = smallFileBufferSize
+	LargeFileBufferSize    = largeFileBufferSize
+)
+
+var ErrInvalidLookaheadRule = errInvalidLookaheadRule
+
+var ErrInvalidCompressionContentType = errInvalidCompressionContentType
+
+var ErrInvalidTrustedProxy = errInvalidTrustedProxy
+
+func (d *DownloadLimiter) Acquire() (func(), bool) { return d.acquire() }
+
+type DirFileEvent = dirFileEvent
+
+type (
+	UploadSession     = uploadSession
+	UploadSessionPart = uploadSessionPart
+)
+
+const UploadSessionTTL = uploadSessionTTL
+
+var (
+	UploadSessionStoreKey     = uploadSessionStoreKey
+	UploadSessionPartStoreKey = uploadSessionPartStoreKey
+)
+
+type IdempotentResponse = idempotentResponse
+
+const (
+	IdempotencyStorePrefix = idempotencyStorePrefix
+	IdempotencyTTL         = idempotencyTTL
+)
+
+const (
+	FullDuplexSupported  = fullDuplexSupported
+	MaxLegacyArchiveSize = maxLegacyArchiveSize
+)
+
+var CompressedPipelineFn = compressedPipelineFn
+
+type BatchQueues = batchQueues
+
+func NewBatchQueues(c chan *pusher.Op) *BatchQueues { return newBatchQueues(c) }
+
+func (q *batchQueues) Len() int { return q.len() }
+
+func NewPushPutter(s storage.Storer, q *BatchQueues) (storage.Putter, func() error) {
+	p := newPushPutter(s, q)
+	return p, p.Wait
+}
+
+func NewPushStamperPutter(s storage.Storer, stamper postage.BatchStamper, q *BatchQueues) (storage.Putter, func() error) {
+	p := newPushStamperPutter(s, stamper, q)
+	return p, p.Wait
+}
+
+type AdaptiveLookahead = adaptiveLookahead
+
+func NewAdaptiveLookahead(r langos.Reader, now func() time.Time) *AdaptiveLookahead {
+	return newAdaptiveLookahead(r, now)
+}
+
+func (a *adaptiveLookahead) BufferSize() int { return a.size }
+
+const MaxChunkBatchSize = maxChunkBatchSize
+
 type HexInvalidByteError = hexInvalidByteError
 
 func MapStructure(input, output interface{}, hooks map[string]func(v string) (string, error)) error {
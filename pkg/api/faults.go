@@ -0,0 +1,111 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/faults"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/gorilla/mux"
+)
+
+const faultRuleMaxRequestSize = 1024
+
+type faultRule struct {
+	Point       string  `json:"point"`
+	Latency     string  `json:"latency,omitempty"`
+	ErrorRate   float64 `json:"errorRate"`
+	CorruptRate float64 `json:"corruptRate"`
+}
+
+type faultRulesResponse struct {
+	Rules []faultRule `json:"rules"`
+}
+
+func (s *Service) faultRulesGetHandler(w http.ResponseWriter, _ *http.Request) {
+	rules := s.faults.Rules()
+	resp := faultRulesResponse{Rules: make([]faultRule, 0, len(rules))}
+	for _, r := range rules {
+		fr := faultRule{
+			Point:       string(r.Point),
+			ErrorRate:   r.ErrorRate,
+			CorruptRate: r.CorruptRate,
+		}
+		if r.Latency > 0 {
+			fr.Latency = r.Latency.String()
+		}
+		resp.Rules = append(resp.Rules, fr)
+	}
+	jsonhttp.OK(w, resp)
+}
+
+func (s *Service) faultRulePutHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("put_fault").Build()
+
+	paths := struct {
+		Point string `map:"point" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	var data faultRule
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		logger.Debug("failed to read body", "error", err)
+		jsonhttp.BadRequest(w, err)
+		return
+	}
+
+	rule := faults.Rule{
+		Point:       faults.Point(paths.Point),
+		ErrorRate:   data.ErrorRate,
+		CorruptRate: data.CorruptRate,
+	}
+	if data.Latency != "" {
+		latency, err := time.ParseDuration(data.Latency)
+		if err != nil || latency < 0 {
+			logger.Debug("invalid latency", "latency", data.Latency, "error", err)
+			jsonhttp.BadRequest(w, "invalid latency")
+			return
+		}
+		rule.Latency = latency
+	}
+
+	if err := s.faults.Set(rule); err != nil {
+		logger.Debug("set fault rule failed", "point", paths.Point, "error", err)
+		if errors.Is(err, faults.ErrUnknownPoint) {
+			jsonhttp.NotFound(w, "unknown injection point")
+			return
+		}
+		jsonhttp.BadRequest(w, err)
+		return
+	}
+	jsonhttp.OK(w, nil)
+}
+
+func (s *Service) faultRuleDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("delete_fault").Build()
+
+	paths := struct {
+		Point string `map:"point" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	s.faults.Remove(faults.Point(paths.Point))
+	jsonhttp.OK(w, nil)
+}
+
+func (s *Service) faultRulesDeleteHandler(w http.ResponseWriter, _ *http.Request) {
+	s.faults.Reset()
+	jsonhttp.OK(w, nil)
+}
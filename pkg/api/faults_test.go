@@ -0,0 +1,97 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/faults"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+)
+
+func TestFaults(t *testing.T) {
+	t.Parallel()
+
+	injector := faults.NewInjector(1)
+	srv, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI:      true,
+		FaultInjector: injector,
+	})
+
+	jsonhttptest.Request(t, srv, http.MethodPut, "/faults/storer-get", http.StatusOK,
+		jsonhttptest.WithJSONRequestBody(api.FaultRule{Latency: "10ms", ErrorRate: 0.5}),
+	)
+	jsonhttptest.Request(t, srv, http.MethodPut, "/faults/stream-read", http.StatusOK,
+		jsonhttptest.WithJSONRequestBody(api.FaultRule{CorruptRate: 0.1}),
+	)
+
+	jsonhttptest.Request(t, srv, http.MethodGet, "/faults", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.FaultRulesResponse{
+			Rules: []api.FaultRule{
+				{Point: "storer-get", Latency: "10ms", ErrorRate: 0.5},
+				{Point: "stream-read", CorruptRate: 0.1},
+			},
+		}),
+	)
+
+	jsonhttptest.Request(t, srv, http.MethodDelete, "/faults/storer-get", http.StatusOK)
+	if rules := injector.Rules(); len(rules) != 1 || rules[0].Point != faults.StreamRead {
+		t.Fatalf("unexpected rules %v", rules)
+	}
+
+	jsonhttptest.Request(t, srv, http.MethodDelete, "/faults", http.StatusOK)
+	if rules := injector.Rules(); len(rules) != 0 {
+		t.Fatalf("unexpected rules %v", rules)
+	}
+
+	t.Run("unknown point", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, srv, http.MethodPut, "/faults/unknown", http.StatusNotFound,
+			jsonhttptest.WithJSONRequestBody(api.FaultRule{ErrorRate: 1}),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusNotFound,
+				Message: "unknown injection point",
+			}),
+		)
+	})
+
+	t.Run("invalid rate", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, srv, http.MethodPut, "/faults/storer-put", http.StatusBadRequest,
+			jsonhttptest.WithJSONRequestBody(api.FaultRule{ErrorRate: 2}),
+		)
+	})
+
+	t.Run("invalid latency", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, srv, http.MethodPut, "/faults/storer-put", http.StatusBadRequest,
+			jsonhttptest.WithJSONRequestBody(api.FaultRule{Latency: "soon"}),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusBadRequest,
+				Message: "invalid latency",
+			}),
+		)
+	})
+}
+
+func TestFaultsDisabled(t *testing.T) {
+	t.Parallel()
+
+	if faults.Enabled {
+		t.Skip("built with the fault injection hooks")
+	}
+
+	srv, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI: true,
+	})
+
+	jsonhttptest.Request(t, srv, http.MethodGet, "/faults", http.StatusNotFound)
+}
@@ -116,7 +116,7 @@ func (s *Service) feedGetHandler(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set(SwarmFeedIndexHeader, hex.EncodeToString(curBytes))
 	w.Header().Set(SwarmFeedIndexNextHeader, hex.EncodeToString(nextBytes))
-	w.Header().Set("Access-Control-Expose-Headers", fmt.Sprintf("%s, %s", SwarmFeedIndexHeader, SwarmFeedIndexNextHeader))
+	w.Header().Add("Access-Control-Expose-Headers", fmt.Sprintf("%s, %s", SwarmFeedIndexHeader, SwarmFeedIndexNextHeader))
 
 	jsonhttp.OK(w, feedReferenceResponse{Reference: ref})
 }
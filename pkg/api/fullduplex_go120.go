@@ -0,0 +1,20 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !go1.21
+
+package api
+
+import (
+	"errors"
+	"net/http"
+)
+
+// fullDuplexSupported is true if the HTTP/1.x server lets the handlers
+// read the request body after the response is started.
+const fullDuplexSupported = false
+
+func enableFullDuplex(http.ResponseWriter) error {
+	return errors.New("full duplex not supported")
+}
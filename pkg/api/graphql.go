@@ -0,0 +1,264 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// graphqlMaxDepth is the maximal depth of the graphql queries.
+const graphqlMaxDepth = 8
+
+// graphqlSchema is the schema of the graphql queries over the
+// node status, peers, pins, stamps and tags. The counters which
+// do not fit into the 32 bit graphql Int are of the type Float.
+const graphqlSchema = `
+schema {
+	query: Query
+}
+
+type Query {
+	status: Status!
+	peers: [Peer!]!
+	pins: [String!]!
+	stamps(all: Boolean = false): [Stamp!]!
+	tags(offset: Int = 0, limit: Int = 100): [Tag!]!
+}
+
+type Status {
+	overlay: String!
+	beeMode: String!
+	reserveSize: Float!
+	pullsyncRate: Float!
+	storageRadius: Int!
+	connectedPeers: Int!
+}
+
+type Peer {
+	address: String!
+	fullNode: Boolean!
+}
+
+type Stamp {
+	batchID: String!
+	label: String!
+	depth: Int!
+	bucketDepth: Int!
+	amount: String!
+	utilization: Float!
+	usable: Boolean!
+	exists: Boolean!
+	immutable: Boolean!
+	blockNumber: Float!
+	batchTTL: Float!
+	expired: Boolean!
+}
+
+type Tag {
+	uid: Float!
+	name: String!
+	startedAt: String!
+	total: Float!
+	processed: Float!
+	sent: Float!
+	synced: Float!
+}
+`
+
+var errGraphqlNotAvailable = errors.New("not available")
+
+type graphqlStatus struct {
+	Overlay        string
+	BeeMode        string
+	ReserveSize    float64
+	PullsyncRate   float64
+	StorageRadius  int32
+	ConnectedPeers int32
+}
+
+type graphqlPeer struct {
+	Address  string
+	FullNode bool
+}
+
+type graphqlStamp struct {
+	BatchID     string
+	Label       string
+	Depth       int32
+	BucketDepth int32
+	Amount      string
+	Utilization float64
+	Usable      bool
+	Exists      bool
+	Immutable   bool
+	BlockNumber float64
+	BatchTTL    float64
+	Expired     bool
+}
+
+type graphqlTag struct {
+	Uid       float64
+	Name      string
+	StartedAt string
+	Total     float64
+	Processed float64
+	Sent      float64
+	Synced    float64
+}
+
+// graphqlResolver resolves the graphql queries, only the
+// fields requested in the query are resolved.
+type graphqlResolver struct {
+	s *Service
+}
+
+func (r *graphqlResolver) Status() (*graphqlStatus, error) {
+	if r.s.statusService == nil {
+		return nil, fmt.Errorf("status: %w", errGraphqlNotAvailable)
+	}
+	ss := r.s.statusService.LocalSnapshot()
+	status := &graphqlStatus{
+		BeeMode:       r.s.beeMode.String(),
+		ReserveSize:   float64(ss.ReserveSize),
+		PullsyncRate:  ss.PullsyncRate,
+		StorageRadius: int32(ss.StorageRadius),
+	}
+	if r.s.overlay != nil {
+		status.Overlay = r.s.overlay.String()
+	}
+	if r.s.p2p != nil {
+		status.ConnectedPeers = int32(len(r.s.p2p.Peers()))
+	}
+	return status, nil
+}
+
+func (r *graphqlResolver) Peers() ([]*graphqlPeer, error) {
+	if r.s.p2p == nil {
+		return nil, fmt.Errorf("peers: %w", errGraphqlNotAvailable)
+	}
+	peers := r.s.p2p.Peers()
+	res := make([]*graphqlPeer, 0, len(peers))
+	for _, p := range peers {
+		res = append(res, &graphqlPeer{Address: p.Address.String(), FullNode: p.FullNode})
+	}
+	return res, nil
+}
+
+func (r *graphqlResolver) Pins() ([]string, error) {
+	if r.s.pinning == nil {
+		return nil, fmt.Errorf("pins: %w", errGraphqlNotAvailable)
+	}
+	pins, err := r.s.pinning.Pins()
+	if err != nil {
+		return nil, fmt.Errorf("list pins: %w", err)
+	}
+	res := make([]string, 0, len(pins))
+	for _, p := range pins {
+		res = append(res, p.String())
+	}
+	return res, nil
+}
+
+func (r *graphqlResolver) Stamps(args struct{ All bool }) ([]*graphqlStamp, error) {
+	if r.s.post == nil {
+		return nil, fmt.Errorf("stamps: %w", errGraphqlNotAvailable)
+	}
+	issuers := r.s.post.StampIssuers()
+	res := make([]*graphqlStamp, 0, len(issuers))
+	for _, v := range issuers {
+		exists, err := r.s.batchStore.Exists(v.ID())
+		if err != nil {
+			return nil, fmt.Errorf("check batch %s: %w", hex.EncodeToString(v.ID()), err)
+		}
+		if !args.All && !exists {
+			continue
+		}
+		batchTTL, err := r.s.estimateBatchTTLFromID(v.ID())
+		if err != nil {
+			return nil, fmt.Errorf("estimate batch %s expiration: %w", hex.EncodeToString(v.ID()), err)
+		}
+		res = append(res, &graphqlStamp{
+			BatchID:     hex.EncodeToString(v.ID()),
+			Label:       v.Label(),
+			Depth:       int32(v.Depth()),
+			BucketDepth: int32(v.BucketDepth()),
+			Amount:      v.Amount().String(),
+			Utilization: float64(v.Utilization()),
+			Usable:      exists && r.s.post.IssuerUsable(v),
+			Exists:      exists,
+			Immutable:   v.ImmutableFlag(),
+			BlockNumber: float64(v.BlockNumber()),
+			BatchTTL:    float64(batchTTL),
+			Expired:     v.Expired(),
+		})
+	}
+	return res, nil
+}
+
+func (r *graphqlResolver) Tags(ctx context.Context, args struct{ Offset, Limit int32 }) ([]*graphqlTag, error) {
+	if r.s.tags == nil {
+		return nil, fmt.Errorf("tags: %w", errGraphqlNotAvailable)
+	}
+	tags, err := r.s.tags.ListAll(ctx, int(args.Offset), int(args.Limit))
+	if err != nil {
+		return nil, fmt.Errorf("list tags: %w", err)
+	}
+	res := make([]*graphqlTag, 0, len(tags))
+	for _, t := range tags {
+		res = append(res, &graphqlTag{
+			Uid:       float64(t.Uid),
+			Name:      t.Name,
+			StartedAt: t.StartedAt.Format(time.RFC3339),
+			Total:     float64(t.Total),
+			Processed: float64(t.Stored),
+			Sent:      float64(t.Sent),
+			Synced:    float64(t.Seen + t.Synced),
+		})
+	}
+	return res, nil
+}
+
+// graphqlHandler returns the handler of the graphql queries, so that the
+// dashboards can fetch the fields they need in a single request.
+func (s *Service) graphqlHandler() http.HandlerFunc {
+	schema := graphql.MustParseSchema(graphqlSchema, &graphqlResolver{s: s},
+		graphql.UseFieldResolvers(),
+		graphql.MaxDepth(graphqlMaxDepth),
+	)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.WithName("post_graphql").Build()
+
+		var req struct {
+			Query         string                 `json:"query"`
+			OperationName string                 `json:"operationName"`
+			Variables     map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			if jsonhttp.HandleBodyReadError(err, w) {
+				return
+			}
+			logger.Debug("unmarshal body failed", "error", err)
+			logger.Error(nil, "unmarshal body failed")
+			jsonhttp.BadRequest(w, "invalid request body")
+			return
+		}
+
+		res := schema.Exec(r.Context(), req.Query, req.OperationName, req.Variables)
+		if len(res.Errors) > 0 {
+			logger.Debug("graphql query failed", "errors", res.Errors)
+		}
+		jsonhttp.OK(w, res)
+	}
+}
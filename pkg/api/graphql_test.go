@@ -0,0 +1,121 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/p2p"
+	p2pmock "github.com/ethersphere/bee/pkg/p2p/mock"
+	pinningmock "github.com/ethersphere/bee/pkg/pinning/mock"
+	"github.com/ethersphere/bee/pkg/postage"
+	mockbatchstore "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestGraphQL(t *testing.T) {
+	t.Parallel()
+
+	peer := swarm.RandAddress(t)
+	pin := swarm.RandAddress(t)
+	pins := pinningmock.NewServiceMock()
+	if err := pins.CreatePin(context.Background(), pin, false); err != nil {
+		t.Fatal(err)
+	}
+
+	b := postagetesting.MustNewBatch(postagetesting.WithValue(20))
+	si := postage.NewStampIssuer("label", "", b.ID, big.NewInt(3), 11, 10, 1000, true)
+	cs := &postage.ChainState{Block: 10, TotalAmount: big.NewInt(5), CurrentPrice: big.NewInt(2)}
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI: true,
+		GraphQL:  true,
+		Pinning:  pins,
+		Post:     mockpost.New(mockpost.WithIssuer(si)),
+		BatchStore: mockbatchstore.New(
+			mockbatchstore.WithChainState(cs),
+			mockbatchstore.WithBatch(b),
+		),
+		BlockTime: 2 * time.Second,
+		P2P: p2pmock.New(p2pmock.WithPeersFunc(func() []p2p.Peer {
+			return []p2p.Peer{{Address: peer, FullNode: true}}
+		})),
+	})
+
+	type response struct {
+		Data struct {
+			Peers []struct {
+				Address  string `json:"address"`
+				FullNode bool   `json:"fullNode"`
+			} `json:"peers"`
+			Pins   []string `json:"pins"`
+			Stamps []struct {
+				BatchID string `json:"batchID"`
+				Label   string `json:"label"`
+				Depth   int    `json:"depth"`
+			} `json:"stamps"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	t.Run("query", func(t *testing.T) {
+		t.Parallel()
+
+		var got response
+		jsonhttptest.Request(t, client, http.MethodPost, "/graphql", http.StatusOK,
+			jsonhttptest.WithJSONRequestBody(map[string]string{
+				"query": "{ peers { address fullNode } pins stamps { batchID label depth } }",
+			}),
+			jsonhttptest.WithUnmarshalJSONResponse(&got),
+		)
+		if len(got.Errors) > 0 {
+			t.Fatalf("got errors %+v", got.Errors)
+		}
+		if len(got.Data.Peers) != 1 || got.Data.Peers[0].Address != peer.String() || !got.Data.Peers[0].FullNode {
+			t.Fatalf("got peers %+v", got.Data.Peers)
+		}
+		if len(got.Data.Pins) != 1 || got.Data.Pins[0] != pin.String() {
+			t.Fatalf("got pins %+v", got.Data.Pins)
+		}
+		if len(got.Data.Stamps) != 1 || got.Data.Stamps[0].BatchID != hex.EncodeToString(b.ID) ||
+			got.Data.Stamps[0].Label != "label" || got.Data.Stamps[0].Depth != 11 {
+			t.Fatalf("got stamps %+v", got.Data.Stamps)
+		}
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		t.Parallel()
+
+		var got response
+		jsonhttptest.Request(t, client, http.MethodPost, "/graphql", http.StatusOK,
+			jsonhttptest.WithJSONRequestBody(map[string]string{
+				"query": "{ unknown }",
+			}),
+			jsonhttptest.WithUnmarshalJSONResponse(&got),
+		)
+		if len(got.Errors) == 0 {
+			t.Fatal("expected errors")
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+		})
+		jsonhttptest.Request(t, client, http.MethodPost, "/graphql", http.StatusNotFound)
+	})
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+)
+
+// maxHashBodySize is the size of the largest content which is hashed, as
+// the addresses of its chunks are kept to count the distinct ones.
+const maxHashBodySize = 1024 * 1024 * 1024
+
+type hashResponse struct {
+	Reference swarm.Address `json:"reference"`
+	Chunks    int           `json:"chunks"`
+	Span      int64         `json:"span"`
+}
+
+// hashHandler streams the request body through the splitter and responds
+// with the reference it would have as the bytes upload, the number of its
+// distinct chunks and its span. The chunks are neither stamped nor stored,
+// so the node acts as the local hashing service for the clients which do
+// not implement the BMT hashing themselves.
+func (s *Service) hashHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("post_hash").Build())
+
+	ctx := r.Context()
+	putter := newDryRunPutter(nil)
+	body := &countingReader{r: r.Body}
+
+	reference, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, putter, storage.ModePutUpload, false), body)
+	if body.err != nil {
+		if jsonhttp.HandleBodyReadError(body.err, w) {
+			return
+		}
+		logger.Debug("hash: read request body failed", "error", body.err)
+		logger.Error(nil, "hash: read request body failed")
+		jsonhttp.BadRequest(w, "cannot read request")
+		return
+	}
+	if err != nil {
+		logger.Debug("hash: split failed", "error", err)
+		logger.Error(nil, "hash: split failed")
+		jsonhttp.InternalServerError(w, "hashing failed")
+		return
+	}
+
+	jsonhttp.OK(w, hashResponse{
+		Reference: reference,
+		Chunks:    putter.count(),
+		Span:      body.n,
+	})
+}
+
+// countingReader counts the bytes read from the reader
+// and keeps the read error other than the end of the input.
+type countingReader struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	if err != nil && !errors.Is(err, io.EOF) {
+		c.err = err
+	}
+	return n, err
+}
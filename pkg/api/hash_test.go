@@ -0,0 +1,103 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestHash(t *testing.T) {
+	t.Parallel()
+
+	storer := mock.NewStorer()
+	client, _, addr, _ := newTestServer(t, testServerOptions{
+		Storer: storer,
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	for _, tc := range []struct {
+		name string
+		size int
+	}{
+		{"empty", 0},
+		{"single chunk", 100},
+		{"intermediate chunk", 130 * swarm.ChunkSize},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			data := make([]byte, tc.size)
+			for i := range data {
+				data[i] = byte(i % 253)
+			}
+
+			var res api.HashResponse
+			jsonhttptest.Request(t, client, http.MethodPost, "/hash", http.StatusOK,
+				jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+				jsonhttptest.WithUnmarshalJSONResponse(&res),
+			)
+			if res.Span != int64(tc.size) {
+				t.Fatalf("got span %d, want %d", res.Span, tc.size)
+			}
+			if _, err := storer.Get(context.Background(), storage.ModeGetRequest, res.Reference); !errors.Is(err, storage.ErrNotFound) {
+				t.Fatalf("got error %v, want the chunk not stored", err)
+			}
+
+			// the reference and the chunk count are the ones of the bytes upload
+			header := jsonhttptest.Request(t, client, http.MethodPost, "/bytes?dry-run=true", http.StatusOK,
+				jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+				jsonhttptest.WithExpectedJSONResponse(api.BytesPostResponse{Reference: res.Reference}),
+			)
+			if want := header.Get(api.SwarmChunkCountHeader); strconv.Itoa(res.Chunks) != want {
+				t.Fatalf("got %d chunks, want %s", res.Chunks, want)
+			}
+		})
+	}
+
+	t.Run("invalid body", func(t *testing.T) {
+		t.Parallel()
+
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		// the chunked encoding of the body is broken after the first chunk
+		_, err = io.WriteString(conn, "POST /hash HTTP/1.1\r\nHost: "+addr+"\r\nTransfer-Encoding: chunked\r\n\r\n4\r\ndata\r\nzz\r\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusBadRequest)
+		}
+	})
+}
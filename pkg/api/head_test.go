@@ -0,0 +1,171 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+// getRecordingStorer records the addresses of the retrieved chunks.
+type getRecordingStorer struct {
+	storage.Storer
+	mu   sync.Mutex
+	gets map[string]int
+}
+
+func (s *getRecordingStorer) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	s.mu.Lock()
+	s.gets[addr.ByteString()]++
+	s.mu.Unlock()
+	return s.Storer.Get(ctx, mode, addr)
+}
+
+func (s *getRecordingStorer) retrievedCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.gets)
+}
+
+func (s *getRecordingStorer) retrieved(addr swarm.Address) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.gets[addr.ByteString()] > 0
+}
+
+// TestBzzHead tests that the HEAD requests are answered with the size and
+// the metadata of the content without retrieving its data chunks.
+func TestBzzHead(t *testing.T) {
+	t.Parallel()
+
+	storer := &getRecordingStorer{Storer: smock.NewStorer(), gets: make(map[string]int)}
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: storer,
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	data := bytes.Repeat([]byte{1, 2, 3}, swarm.ChunkSize+5)
+	index := []byte("<h1>Swarm</h1>")
+	tr := tarFiles(t, []f{
+		{data: index, name: "index.html", header: http.Header{"Content-Type": {"text/html; charset=utf-8"}}},
+		{data: data, name: "data.json", dir: "files", header: http.Header{"Content-Type": {"application/json"}}},
+	})
+
+	var resp api.BzzUploadResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmIndexDocumentHeader, "index.html"),
+		jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+		jsonhttptest.WithRequestBody(tr),
+		jsonhttptest.WithUnmarshalJSONResponse(&resp),
+	)
+	root := "/bzz/" + resp.Reference.String() + "/"
+
+	// the manifest is not walked for the tar archive, which is checked
+	// before the subtests retrieve the other chunks
+	jsonhttptest.Request(t, client, http.MethodHead, root, http.StatusOK,
+		jsonhttptest.WithRequestHeader("Accept", api.ContentTypeTar),
+		jsonhttptest.WithExpectedResponseHeader("Content-Type", api.ContentTypeTar),
+	)
+	if n := storer.retrievedCount(); n > 2 {
+		t.Fatalf("got %d chunks retrieved, want the root node of the manifest only", n)
+	}
+
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+
+		var body []byte
+		header := jsonhttptest.Request(t, client, http.MethodHead, root+"files/data.json", http.StatusOK,
+			jsonhttptest.WithExpectedContentLength(len(data)),
+			jsonhttptest.WithExpectedResponseHeader("Content-Type", "application/json"),
+			jsonhttptest.WithExpectedResponseHeader("Content-Disposition", `inline; filename="data.json"`),
+			jsonhttptest.WithExpectedResponseHeader("Accept-Ranges", "bytes"),
+			jsonhttptest.WithPutResponseBody(&body),
+		)
+		if len(body) != 0 {
+			t.Fatalf("got body of %d bytes, want none", len(body))
+		}
+		etag := header.Get("ETag")
+		if etag == "" {
+			t.Fatal("missing etag")
+		}
+
+		// none of the data chunks of the file is retrieved
+		for i := 0; i < len(data); i += swarm.ChunkSize {
+			end := i + swarm.ChunkSize
+			if end > len(data) {
+				end = len(data)
+			}
+			ch, err := cac.New(data[i:end])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if storer.retrieved(ch.Address()) {
+				t.Fatalf("data chunk %d retrieved", i/swarm.ChunkSize)
+			}
+		}
+
+		// the etag is the same as of the download
+		jsonhttptest.Request(t, client, http.MethodGet, root+"files/data.json", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(data),
+			jsonhttptest.WithExpectedResponseHeader("ETag", etag),
+		)
+		jsonhttptest.Request(t, client, http.MethodHead, root+"files/data.json", http.StatusNotModified,
+			jsonhttptest.WithRequestHeader("If-None-Match", etag),
+		)
+	})
+
+	t.Run("index document", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodHead, root, http.StatusOK,
+			jsonhttptest.WithExpectedContentLength(len(index)),
+			jsonhttptest.WithExpectedResponseHeader("Content-Type", "text/html; charset=utf-8"),
+		)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodHead, root+"missing.txt", http.StatusNotFound)
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		t.Parallel()
+
+		var bytesResp api.BytesPostResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+			jsonhttptest.WithUnmarshalJSONResponse(&bytesResp),
+		)
+
+		jsonhttptest.Request(t, client, http.MethodHead, "/bytes/"+bytesResp.Reference.String(), http.StatusOK,
+			jsonhttptest.WithExpectedResponseHeader("Content-Length", strconv.Itoa(len(data))),
+			jsonhttptest.WithExpectedResponseHeader("ETag", strconv.Quote(bytesResp.Reference.String())),
+			jsonhttptest.WithExpectedResponseHeader("Accept-Ranges", "bytes"),
+		)
+	})
+}
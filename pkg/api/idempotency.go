@@ -0,0 +1,288 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+const (
+	// IdempotencyKeyHeader is the header of the client provided key, which
+	// identifies the retries of the same request.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on the responses which are replayed
+	// from the responses stored for the idempotency key.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	idempotencyStorePrefix  = "api-idempotency-"
+	idempotencyKeyMaxLength = 255
+	// idempotencyTTL is the period for which the responses are replayed.
+	idempotencyTTL = 24 * time.Hour
+	// idempotencySweepInterval is the minimal time between
+	// the sweeps of the expired responses.
+	idempotencySweepInterval = time.Hour
+)
+
+// idempotentResponse is the stored response of a request with the
+// idempotency key, with the hash of the body of the request, so that
+// the key which is reused for a different request is detected.
+type idempotentResponse struct {
+	Status    int         `json:"status"`
+	Header    http.Header `json:"header"`
+	Body      []byte      `json:"body"`
+	BodyHash  []byte      `json:"bodyHash"`
+	Timestamp int64       `json:"timestamp"`
+}
+
+func (r idempotentResponse) expired(now time.Time) bool {
+	return now.Sub(time.Unix(0, r.Timestamp)) >= idempotencyTTL
+}
+
+// idempotencyStoreKey returns the state store key of the response. The key
+// is scoped to the method and the path of the request, which also carries
+// the parameters of the stamps and chequebook endpoints.
+func idempotencyStoreKey(r *http.Request, key string) string {
+	h := sha256.New()
+	_, _ = h.Write([]byte(r.Method))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(r.URL.Path))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(key))
+	return idempotencyStorePrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+// idempotencyHandler replays the stored response of the request with the
+// same idempotency key, so that the retries of the clients after timeouts
+// do not upload the content or buy the batches twice. The server errors are
+// not stored, so the requests which failed can be retried. The concurrent
+// requests with the same key are rejected, and so are the requests whose
+// body differs from the body of the request with the stored response.
+func (s *Service) idempotencyHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.WithName("idempotency").Build()
+
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || s.stateStore == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > idempotencyKeyMaxLength {
+			jsonhttp.BadRequest(w, "invalid idempotency key")
+			return
+		}
+
+		storeKey := idempotencyStoreKey(r, key)
+		if !s.acquireIdempotencyKey(storeKey) {
+			jsonhttp.Conflict(w, "request with the same idempotency key is in progress")
+			return
+		}
+		defer s.releaseIdempotencyKey(storeKey)
+		s.sweepIdempotentResponses(logger)
+
+		var res idempotentResponse
+		switch err := s.stateStore.Get(storeKey, &res); {
+		case err == nil && !res.expired(time.Now()):
+			bodyHash, err := hashBody(r.Body)
+			if err != nil {
+				logger.Debug("read request body failed", "error", err)
+				if !jsonhttp.HandleBodyReadError(err, w) {
+					jsonhttp.BadRequest(w, "cannot read request")
+				}
+				return
+			}
+			if !bytes.Equal(bodyHash, res.BodyHash) {
+				jsonhttp.UnprocessableEntity(w, "idempotency key reused with a different request body")
+				return
+			}
+			for k, v := range res.Header {
+				w.Header()[k] = v
+			}
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			w.WriteHeader(res.Status)
+			if _, err := w.Write(res.Body); err != nil {
+				logger.Debug("replay response failed", "error", err)
+			}
+			return
+		case err == nil, errors.Is(err, storage.ErrNotFound):
+			// the stored response has expired or there is none
+		default:
+			logger.Debug("get stored response failed", "error", err)
+			logger.Error(nil, "get stored response failed")
+			jsonhttp.InternalServerError(w, "idempotency key lookup failed")
+			return
+		}
+
+		body := &hashingReader{r: r.Body, h: sha256.New()}
+		if r.Body != nil {
+			r.Body = body
+		}
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+		if rec.status >= http.StatusInternalServerError {
+			return
+		}
+		// the part of the body which is not read by the handler is hashed
+		// too, the response is not stored if the body can not be read
+		bodyHash, err := body.sum()
+		if err != nil {
+			logger.Debug("read request body failed", "error", err)
+			return
+		}
+
+		res = idempotentResponse{
+			Status:    rec.status,
+			Header:    w.Header().Clone(),
+			Body:      rec.body.Bytes(),
+			BodyHash:  bodyHash,
+			Timestamp: time.Now().UnixNano(),
+		}
+		if err := s.stateStore.Put(storeKey, res); err != nil {
+			logger.Debug("store response failed", "error", err)
+			logger.Error(nil, "store response failed")
+		}
+	})
+}
+
+// sweepIdempotentResponses deletes the expired responses which are not
+// in use, at most once in the sweep interval.
+func (s *Service) sweepIdempotentResponses(logger log.Logger) {
+	now := time.Now()
+	s.idempotencyMu.Lock()
+	if now.Sub(s.idempotencySwept) < idempotencySweepInterval {
+		s.idempotencyMu.Unlock()
+		return
+	}
+	s.idempotencySwept = now
+	s.idempotencyMu.Unlock()
+
+	var expired []string
+	err := s.stateStore.Iterate(idempotencyStorePrefix, func(key, value []byte) (bool, error) {
+		var res idempotentResponse
+		if err := json.Unmarshal(value, &res); err != nil {
+			return false, fmt.Errorf("unmarshal stored response %s: %w", key, err)
+		}
+		if res.expired(now) {
+			expired = append(expired, string(key))
+		}
+		return false, nil
+	})
+	if err != nil {
+		logger.Debug("iterate stored responses failed", "error", err)
+		logger.Error(nil, "iterate stored responses failed")
+		return
+	}
+
+	for _, key := range expired {
+		if !s.acquireIdempotencyKey(key) {
+			continue
+		}
+		if err := s.stateStore.Delete(key); err != nil {
+			logger.Debug("delete expired response failed", "key", key, "error", err)
+			logger.Error(nil, "delete expired response failed")
+		}
+		s.releaseIdempotencyKey(key)
+	}
+}
+
+func (s *Service) acquireIdempotencyKey(key string) bool {
+	s.idempotencyMu.Lock()
+	defer s.idempotencyMu.Unlock()
+
+	if s.idempotencyInFlight == nil {
+		s.idempotencyInFlight = make(map[string]struct{})
+	}
+	if _, ok := s.idempotencyInFlight[key]; ok {
+		return false
+	}
+	s.idempotencyInFlight[key] = struct{}{}
+	return true
+}
+
+func (s *Service) releaseIdempotencyKey(key string) {
+	s.idempotencyMu.Lock()
+	defer s.idempotencyMu.Unlock()
+
+	delete(s.idempotencyInFlight, key)
+}
+
+// hashBody returns the hash of the request body.
+func hashBody(body io.Reader) ([]byte, error) {
+	h := sha256.New()
+	if body != nil {
+		if _, err := io.Copy(h, body); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// hashingReader hashes the request body as it is read by the handler.
+type hashingReader struct {
+	r    io.ReadCloser
+	h    hash.Hash
+	done bool
+	err  error
+}
+
+func (hr *hashingReader) Read(p []byte) (int, error) {
+	n, err := hr.r.Read(p)
+	_, _ = hr.h.Write(p[:n])
+	return n, err
+}
+
+// Close hashes the rest of the body before it is closed by the handler.
+func (hr *hashingReader) Close() error {
+	_, _ = hr.sum()
+	return hr.r.Close()
+}
+
+// sum reads the rest of the body and returns its hash.
+func (hr *hashingReader) sum() ([]byte, error) {
+	if !hr.done && hr.r != nil {
+		_, hr.err = io.Copy(hr.h, hr.r)
+	}
+	hr.done = true
+	if hr.err != nil {
+		return nil, hr.err
+	}
+	return hr.h.Sum(nil), nil
+}
+
+// responseRecorder writes the response through
+// and records its status code and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(code int) {
+	if rr.wroteHeader {
+		return
+	}
+	rr.status = code
+	rr.wroteHeader = true
+	rr.ResponseWriter.WriteHeader(code)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.wroteHeader = true
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	contractMock "github.com/ethersphere/bee/pkg/postage/postagecontract/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	var (
+		batchID = []byte{1, 2, 3, 4}
+		txHash  = common.HexToHash("0x1234")
+		calls   int32
+		fail    atomic.Bool
+	)
+	contract := contractMock.New(
+		contractMock.WithCreateBatchFunc(func(context.Context, *big.Int, uint8, bool, string) (common.Hash, []byte, error) {
+			atomic.AddInt32(&calls, 1)
+			if fail.Load() {
+				return common.Hash{}, nil, errors.New("create batch failed")
+			}
+			return txHash, batchID, nil
+		}),
+	)
+	ts, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI:        true,
+		PostageContract: contract,
+	})
+
+	want := &api.PostageCreateResponse{
+		BatchID: batchID,
+		TxHash:  txHash.String(),
+	}
+	create := func(key string, status int, replayed bool) {
+		t.Helper()
+
+		var opts []jsonhttptest.Option
+		if replayed {
+			opts = append(opts, jsonhttptest.WithExpectedResponseHeader(api.IdempotentReplayedHeader, "true"))
+		}
+		if key != "" {
+			opts = append(opts, jsonhttptest.WithRequestHeader(api.IdempotencyKeyHeader, key))
+		}
+		if status == http.StatusCreated {
+			opts = append(opts, jsonhttptest.WithExpectedJSONResponse(want))
+		}
+		jsonhttptest.Request(t, ts, http.MethodPost, "/stamps/1000/17", status, opts...)
+	}
+	checkCalls := func(want int32) {
+		t.Helper()
+
+		if got := atomic.LoadInt32(&calls); got != want {
+			t.Fatalf("got %d create batch calls, want %d", got, want)
+		}
+	}
+
+	create("key-1", http.StatusCreated, false)
+	create("key-1", http.StatusCreated, true)
+	checkCalls(1)
+
+	create("key-2", http.StatusCreated, false)
+	checkCalls(2)
+
+	create("", http.StatusCreated, false)
+	create("", http.StatusCreated, false)
+	checkCalls(4)
+
+	// the server errors are not stored, so the request can be retried
+	fail.Store(true)
+	create("key-3", http.StatusInternalServerError, false)
+	fail.Store(false)
+	create("key-3", http.StatusCreated, false)
+	create("key-3", http.StatusCreated, true)
+	checkCalls(6)
+}
+
+func TestIdempotencyKeyBody(t *testing.T) {
+	t.Parallel()
+
+	stateStore := statestore.NewStateStore()
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:      mock.NewStorer(),
+		StateStorer: stateStore,
+		Tags:        tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Post:        mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	// the expired response is swept on the first request with a key
+	const expiredKey = api.IdempotencyStorePrefix + "expired"
+	if err := stateStore.Put(expiredKey, api.IdempotentResponse{
+		Status:    http.StatusCreated,
+		Timestamp: time.Now().Add(-api.IdempotencyTTL - time.Minute).UnixNano(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	upload := func(body string, status int, opts ...jsonhttptest.Option) {
+		t.Helper()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=hello.txt", status, append([]jsonhttptest.Option{
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.ContentTypeHeader, "text/plain"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.IdempotencyKeyHeader, "key"),
+			jsonhttptest.WithRequestBody(strings.NewReader(body)),
+		}, opts...)...)
+	}
+
+	upload("hello", http.StatusCreated)
+	upload("hello", http.StatusCreated,
+		jsonhttptest.WithExpectedResponseHeader(api.IdempotentReplayedHeader, "true"),
+	)
+	upload("hello world", http.StatusUnprocessableEntity,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "idempotency key reused with a different request body",
+			Code:    http.StatusUnprocessableEntity,
+		}),
+	)
+
+	var res api.IdempotentResponse
+	if err := stateStore.Get(expiredKey, &res); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+}
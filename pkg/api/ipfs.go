@@ -0,0 +1,225 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/ipfs"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/sctx"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
+	"github.com/ipfs/go-cid"
+)
+
+type ipfsImportResponse struct {
+	Reference swarm.Address `json:"reference"`
+	Manifest  bool          `json:"manifest"`
+}
+
+// ipfsImportHandler imports the UnixFS DAG with the CID from the IPFS gateway.
+// The file content is stored as the bytes, and the directories are stored
+// with the manifest of their files, preserving the directory structure.
+func (s *Service) ipfsImportHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("post_ipfs_import").Build())
+
+	if s.ipfs == nil {
+		jsonhttp.NotImplemented(w, "ipfs import is not available")
+		return
+	}
+
+	paths := struct {
+		CID string `map:"cid" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+	root, err := cid.Decode(paths.CID)
+	if err != nil {
+		logger.Debug("decode cid failed", "cid", paths.CID, "error", err)
+		logger.Error(nil, "decode cid failed")
+		jsonhttp.BadRequest(w, "invalid cid")
+		return
+	}
+
+	headers := struct {
+		SwarmTag     string `map:"Swarm-Tag"`
+		SwarmTagName string `map:"Swarm-Tag-Name"`
+	}{}
+	if response := s.mapStructure(r.Header, &headers); response != nil {
+		response("invalid header params", logger, w)
+		return
+	}
+
+	putter, wait, err := s.newStamperPutter(r)
+	if err != nil {
+		logger.Debug("get putter failed", "error", err)
+		logger.Error(nil, "get putter failed")
+		switch {
+		case errors.Is(err, errBatchUnusable) || errors.Is(err, postage.ErrNotUsable):
+			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+		case errors.Is(err, postage.ErrNotFound):
+			jsonhttp.NotFound(w, "batch with id not found")
+		case errors.Is(err, errInvalidPostageBatch):
+			jsonhttp.BadRequest(w, "invalid batch id")
+		case errors.Is(err, errUnsupportedDevNodeOperation):
+			jsonhttp.BadRequest(w, errUnsupportedDevNodeOperation)
+		default:
+			jsonhttp.BadRequest(w, nil)
+		}
+		return
+	}
+
+	tag, created, err := s.getOrCreateTag(headers.SwarmTag, headers.SwarmTagName)
+	if err != nil {
+		logger.Debug("get or create tag failed", "error", err)
+		logger.Error(nil, "get or create tag failed")
+		switch {
+		case errors.Is(err, tags.ErrNotFound):
+			jsonhttp.NotFound(w, "tag not found")
+		case errors.Is(err, tags.ErrNameTooLong):
+			jsonhttp.BadRequest(w, "invalid tag name")
+		default:
+			jsonhttp.InternalServerError(w, "cannot get or create tag")
+		}
+		return
+	}
+
+	// Add the tag to the context
+	ctx := sctx.SetTag(r.Context(), tag)
+
+	walker, err := s.ipfs.Walk(ctx, root)
+	if err != nil {
+		logger.Debug("ipfs import failed", "cid", root, "error", err)
+		logger.Error(nil, "ipfs import failed")
+		ipfsImportErrorResponse(w, err)
+		return
+	}
+
+	reference, err := s.storeIPFS(ctx, walker, r, putter, tag, created)
+	if err != nil {
+		logger.Debug("ipfs import failed", "cid", root, "error", err)
+		logger.Error(nil, "ipfs import failed")
+		ipfsImportErrorResponse(w, err)
+		return
+	}
+	if err = wait(); err != nil {
+		logger.Debug("sync chunks failed", "error", err)
+		logger.Error(nil, "sync chunks failed")
+		jsonhttp.InternalServerError(w, "sync chunks failed")
+		return
+	}
+
+	if created {
+		if _, err = tag.DoneSplit(reference); err != nil {
+			logger.Debug("done split failed", "error", err)
+			logger.Error(nil, "done split failed")
+			jsonhttp.InternalServerError(w, "done split failed")
+			return
+		}
+	}
+
+	if requestPin(r) {
+		if err := s.pinning.CreatePin(ctx, reference, false); err != nil {
+			logger.Debug("pin creation failed", "address", reference, "error", err)
+			logger.Error(nil, "pin creation failed")
+			jsonhttp.InternalServerError(w, "create pin failed")
+			return
+		}
+	}
+
+	w.Header().Set(SwarmTagHeader, fmt.Sprint(tag.Uid))
+	w.Header().Add("Access-Control-Expose-Headers", SwarmTagHeader)
+	jsonhttp.Created(w, ipfsImportResponse{
+		Reference: reference,
+		Manifest:  walker.Dir(),
+	})
+}
+
+// storeIPFS stores the files of the walked DAG with the putter and returns
+// the reference of the file content or of the manifest of the directory.
+func (s *Service) storeIPFS(ctx context.Context, walker *ipfs.Walker, r *http.Request, putter storage.Storer, tag *tags.Tag, tagCreated bool) (swarm.Address, error) {
+	if walker.Dir() {
+		return storeDir(
+			ctx,
+			requestEncrypt(r),
+			&ipfsDirReader{w: walker},
+			s.logger,
+			requestPipelineFn(putter, r),
+			"",
+			loadsave.New(putter, requestPipelineFactory(ctx, putter, r)),
+			r.Header.Get(SwarmIndexDocumentHeader),
+			r.Header.Get(SwarmErrorDocumentHeader),
+			r.Header.Get(SwarmWebsiteRedirectsHeader),
+			tag,
+			tagCreated,
+			nil,
+		)
+	}
+
+	f, err := walker.Next()
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	if !tagCreated {
+		// only in the case when tag is sent via header (i.e. not created by this request)
+		if estimatedTotalChunks := calculateNumberOfChunks(f.Size, requestEncrypt(r)); estimatedTotalChunks > 0 {
+			if err := tag.IncN(tags.TotalChunks, estimatedTotalChunks); err != nil {
+				return swarm.ZeroAddress, fmt.Errorf("increment tag: %w", err)
+			}
+		}
+	}
+	return requestPipelineFn(putter, r)(ctx, f.Reader)
+}
+
+func ipfsImportErrorResponse(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ipfs.ErrNotFound):
+		jsonhttp.NotFound(w, "content not found on the gateway")
+	case errors.Is(err, ipfs.ErrUnsupported):
+		jsonhttp.BadRequest(w, "unsupported ipfs dag")
+	case errors.Is(err, ipfs.ErrInvalidBlock):
+		jsonhttp.BadGateway(w, "invalid block from the gateway")
+	case errors.Is(err, errEmptyDir):
+		jsonhttp.BadRequest(w, errEmptyDir)
+	case errors.Is(err, errInvalidWebsiteRedirects):
+		jsonhttp.BadRequest(w, errInvalidWebsiteRedirects)
+	case errors.Is(err, postage.ErrBucketFull):
+		jsonhttp.PaymentRequired(w, "batch is overissued")
+	default:
+		jsonhttp.InternalServerError(w, "ipfs import failed")
+	}
+}
+
+// ipfsDirReader reads the files of the imported directory.
+type ipfsDirReader struct {
+	w *ipfs.Walker
+}
+
+func (d *ipfsDirReader) Next() (*FileInfo, error) {
+	f, err := d.w.Next()
+	if err != nil {
+		return nil, err
+	}
+	return &FileInfo{
+		Path:        f.Path,
+		Name:        f.Name,
+		ContentType: mime.TypeByExtension(path.Ext(f.Name)),
+		Size:        f.Size,
+		Reader:      f.Reader,
+	}, nil
+}
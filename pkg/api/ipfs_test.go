@@ -0,0 +1,140 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/ipfs"
+	"github.com/ethersphere/bee/pkg/ipfs/ipfstest"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestIPFSImport(t *testing.T) {
+	t.Parallel()
+
+	srv := ipfstest.NewGateway(t)
+	gateway, err := ipfs.New(srv.URL, ipfs.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: mock.NewStorer(),
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+		IPFS:   gateway,
+	})
+
+	var (
+		page = []byte("<html><body>swarm</body></html>")
+		logo = bytes.Repeat([]byte{0x89, 'P', 'N', 'G'}, 2000)
+	)
+
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+
+		root := srv.AddFile(logo, 1024)
+
+		var res api.IpfsImportResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/ipfs/"+root.String(), http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if res.Manifest {
+			t.Fatal("got manifest, want bytes reference")
+		}
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+res.Reference.String(), http.StatusOK,
+			jsonhttptest.WithExpectedResponse(logo),
+		)
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		t.Parallel()
+
+		root := srv.AddDir(
+			ipfstest.Link{Name: "img", CID: srv.AddDir(ipfstest.Link{Name: "logo.png", CID: srv.AddFile(logo, 1024)})},
+			ipfstest.Link{Name: "index.html", CID: srv.AddFile(page, 1024)},
+		)
+
+		var res api.IpfsImportResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/ipfs/"+root.String(), http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmIndexDocumentHeader, "index.html"),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if !res.Manifest {
+			t.Fatal("got bytes reference, want manifest")
+		}
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+res.Reference.String()+"/img/logo.png", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(logo),
+			jsonhttptest.WithExpectedResponseHeader(api.ContentTypeHeader, "image/png"),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+res.Reference.String()+"/", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(page),
+		)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		root := srv.AddRaw([]byte("removed"))
+		srv.Remove(root)
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/ipfs/"+root.String(), http.StatusNotFound,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "content not found on the gateway",
+				Code:    http.StatusNotFound,
+			}),
+		)
+	})
+
+	t.Run("invalid block", func(t *testing.T) {
+		t.Parallel()
+
+		root := srv.AddRaw([]byte("original"))
+		srv.Put(root, []byte("tampered"))
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/ipfs/"+root.String(), http.StatusBadGateway,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid block from the gateway",
+				Code:    http.StatusBadGateway,
+			}),
+		)
+	})
+
+	t.Run("invalid cid", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/ipfs/not-a-cid", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid cid",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("not available", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{})
+		jsonhttptest.Request(t, client, http.MethodPost, "/ipfs/"+srv.AddRaw([]byte("data")).String(), http.StatusNotImplemented)
+	})
+}
@@ -0,0 +1,177 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jobs"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/gorilla/mux"
+)
+
+type jobResponse struct {
+	ID         uint64          `json:"id"`
+	Kind       string          `json:"kind"`
+	Params     json.RawMessage `json:"params"`
+	Status     string          `json:"status"`
+	Done       int64           `json:"done"`
+	Total      int64           `json:"total"`
+	State      json.RawMessage `json:"state,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Resumed    int             `json:"resumed"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	StartedAt  time.Time       `json:"startedAt"`
+	FinishedAt *time.Time      `json:"finishedAt,omitempty"`
+}
+
+type jobsResponse struct {
+	Jobs []jobResponse `json:"jobs"`
+}
+
+// listJobsHandler returns the jobs of the kind given
+// in the query, or all jobs if there is none.
+func (s *Service) listJobsHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_jobs").Build()
+
+	if s.jobs == nil {
+		jsonhttp.NotImplemented(w, "jobs are not available")
+		return
+	}
+
+	queries := struct {
+		Kind string `map:"kind"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	all := s.jobs.Jobs(queries.Kind)
+	res := jobsResponse{Jobs: make([]jobResponse, 0, len(all))}
+	for _, j := range all {
+		res.Jobs = append(res.Jobs, newJobResponse(j))
+	}
+
+	jsonhttp.OK(w, res)
+}
+
+// getJobHandler returns the progress of the job.
+func (s *Service) getJobHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_job").Build()
+
+	if s.jobs == nil {
+		jsonhttp.NotImplemented(w, "jobs are not available")
+		return
+	}
+
+	paths := struct {
+		ID uint64 `map:"id" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	job, err := s.jobs.Job(paths.ID)
+	if err != nil {
+		if errors.Is(err, jobs.ErrNotFound) {
+			logger.Debug("job not found", "id", paths.ID)
+			jsonhttp.NotFound(w, "job not found")
+			return
+		}
+		logger.Debug("get job failed", "id", paths.ID, "error", err)
+		logger.Error(nil, "get job failed")
+		jsonhttp.InternalServerError(w, "get job failed")
+		return
+	}
+
+	jsonhttp.OK(w, newJobResponse(job))
+}
+
+// cancelJobHandler cancels the running job.
+func (s *Service) cancelJobHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("delete_job").Build()
+
+	if s.jobs == nil {
+		jsonhttp.NotImplemented(w, "jobs are not available")
+		return
+	}
+
+	paths := struct {
+		ID uint64 `map:"id" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	switch err := s.jobs.Cancel(paths.ID); {
+	case errors.Is(err, jobs.ErrNotFound):
+		logger.Debug("job not found", "id", paths.ID)
+		jsonhttp.NotFound(w, "job not found")
+		return
+	case errors.Is(err, jobs.ErrNotRunning):
+		logger.Debug("job not running", "id", paths.ID)
+		jsonhttp.Conflict(w, "job not running")
+		return
+	case err != nil:
+		logger.Debug("cancel job failed", "id", paths.ID, "error", err)
+		logger.Error(nil, "cancel job failed")
+		jsonhttp.InternalServerError(w, "cancel job failed")
+		return
+	}
+
+	jsonhttp.OK(w, nil)
+}
+
+// submitJob starts the job of the kind in the background and responds
+// with its initial state, it is used by the handlers of the features which
+// run as jobs on request.
+func (s *Service) submitJob(logger log.Logger, w http.ResponseWriter, kind string, params interface{}) {
+	if s.jobs == nil {
+		jsonhttp.NotImplemented(w, "jobs are not available")
+		return
+	}
+
+	job, err := s.jobs.Submit(kind, params)
+	if err != nil {
+		if errors.Is(err, jobs.ErrTooManyJobs) {
+			logger.Debug("too many jobs", "kind", kind)
+			jsonhttp.TooManyRequests(w, "too many running jobs")
+			return
+		}
+		logger.Debug("submit job failed", "kind", kind, "error", err)
+		logger.Error(nil, "submit job failed")
+		jsonhttp.InternalServerError(w, "submit job failed")
+		return
+	}
+
+	jsonhttp.Accepted(w, newJobResponse(job))
+}
+
+func newJobResponse(j jobs.Job) jobResponse {
+	res := jobResponse{
+		ID:        j.ID,
+		Kind:      j.Kind,
+		Params:    j.Params,
+		Status:    string(j.Status),
+		Done:      j.Done,
+		Total:     j.Total,
+		State:     j.State,
+		Error:     j.Error,
+		Resumed:   j.Resumed,
+		CreatedAt: j.CreatedAt,
+		StartedAt: j.StartedAt,
+	}
+	if !j.FinishedAt.IsZero() {
+		res.FinishedAt = &j.FinishedAt
+	}
+	return res
+}
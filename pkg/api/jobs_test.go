@@ -0,0 +1,154 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jobs"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/pinning"
+	pinningmock "github.com/ethersphere/bee/pkg/pinning/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/steward"
+	stewardmock "github.com/ethersphere/bee/pkg/steward/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestJobs(t *testing.T) {
+	t.Parallel()
+
+	pins := pinningmock.NewServiceMock()
+	stewardMock := &stewardmock.Steward{}
+
+	m, err := jobs.New(statestore.NewStateStore(), log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = m.Close() })
+	m.Register(pinning.JobKind, 0, pinning.NewJob(pins))
+	m.Register(steward.JobKind, 0, steward.NewJob(stewardMock))
+	m.Register("block", 0, func(ctx context.Context, _ *jobs.Task) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:  smock.NewStorer(),
+		Pinning: pins,
+		Steward: stewardMock,
+		Jobs:    m,
+	})
+
+	waitJob := func(t *testing.T, id uint64) api.JobResponse {
+		t.Helper()
+
+		var res api.JobResponse
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			jsonhttptest.Request(t, client, http.MethodGet, "/jobs/"+strconv.FormatUint(id, 10), http.StatusOK,
+				jsonhttptest.WithUnmarshalJSONResponse(&res),
+			)
+			if res.Status != string(jobs.StatusRunning) {
+				return res
+			}
+		}
+		t.Fatal("timed out waiting for the job")
+		return res
+	}
+
+	t.Run("pin", func(t *testing.T) {
+		t.Parallel()
+
+		ref := swarm.RandAddress(t)
+		var res api.JobResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/pins/"+ref.String()+"?async=true", http.StatusAccepted,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if res.Kind != pinning.JobKind {
+			t.Fatalf("got kind %q, want %q", res.Kind, pinning.JobKind)
+		}
+		if res = waitJob(t, res.ID); res.Status != string(jobs.StatusDone) {
+			t.Fatalf("got status %q, want %q", res.Status, jobs.StatusDone)
+		}
+		if has, _ := pins.HasPin(ref); !has {
+			t.Fatal("reference not pinned")
+		}
+
+		var list api.JobsResponse
+		jsonhttptest.Request(t, client, http.MethodGet, "/jobs?kind="+pinning.JobKind, http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&list),
+		)
+		if len(list.Jobs) != 1 || list.Jobs[0].ID != res.ID {
+			t.Fatalf("got jobs %+v", list.Jobs)
+		}
+
+		jsonhttptest.Request(t, client, http.MethodDelete, "/jobs/"+strconv.FormatUint(res.ID, 10), http.StatusConflict,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "job not running",
+				Code:    http.StatusConflict,
+			}),
+		)
+	})
+
+	t.Run("stewardship", func(t *testing.T) {
+		t.Parallel()
+
+		ref := swarm.RandAddress(t)
+		var res api.JobResponse
+		jsonhttptest.Request(t, client, http.MethodPut, "/stewardship/"+ref.String()+"?async=true", http.StatusAccepted,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if res = waitJob(t, res.ID); res.Status != string(jobs.StatusDone) {
+			t.Fatalf("got status %q, want %q", res.Status, jobs.StatusDone)
+		}
+		if !stewardMock.LastAddress().Equal(ref) {
+			t.Fatalf("got address %s, want %s", stewardMock.LastAddress(), ref)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		t.Parallel()
+
+		j, err := m.Submit("block", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		jsonhttptest.Request(t, client, http.MethodDelete, "/jobs/"+strconv.FormatUint(j.ID, 10), http.StatusOK)
+		if res := waitJob(t, j.ID); res.Status != string(jobs.StatusCancelled) {
+			t.Fatalf("got status %q, want %q", res.Status, jobs.StatusCancelled)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/jobs/1000", http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "job not found",
+				Code:    http.StatusNotFound,
+			}),
+		)
+	})
+
+	t.Run("not available", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{})
+		jsonhttptest.Request(t, client, http.MethodGet, "/jobs", http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "jobs are not available",
+				Code:    http.StatusNotImplemented,
+			}),
+		)
+	})
+}
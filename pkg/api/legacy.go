@@ -0,0 +1,239 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/legacy"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/sctx"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
+)
+
+// maxLegacyArchiveSize is the size of the largest archive which is imported,
+// as all its chunks are read into the memory before they are walked.
+const maxLegacyArchiveSize = 128 * 1024 * 1024
+
+type legacyImportResponse struct {
+	Reference swarm.Address `json:"reference"`
+	Manifest  bool          `json:"manifest"`
+}
+
+// legacyImportHandler imports the Swarm 0.x content with the reference from
+// the export of the chunks in the request body. The legacy manifests are
+// converted to the mantaray manifests with the default entry as the index
+// document, and the files are stored again with the postage batch.
+func (s *Service) legacyImportHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("post_legacy_import").Build())
+
+	paths := struct {
+		Address swarm.Address `map:"address" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	headers := struct {
+		SwarmTag     string `map:"Swarm-Tag"`
+		SwarmTagName string `map:"Swarm-Tag-Name"`
+	}{}
+	if response := s.mapStructure(r.Header, &headers); response != nil {
+		response("invalid header params", logger, w)
+		return
+	}
+
+	putter, wait, err := s.newStamperPutter(r)
+	if err != nil {
+		logger.Debug("get putter failed", "error", err)
+		logger.Error(nil, "get putter failed")
+		switch {
+		case errors.Is(err, errBatchUnusable) || errors.Is(err, postage.ErrNotUsable):
+			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+		case errors.Is(err, postage.ErrNotFound):
+			jsonhttp.NotFound(w, "batch with id not found")
+		case errors.Is(err, errInvalidPostageBatch):
+			jsonhttp.BadRequest(w, "invalid batch id")
+		case errors.Is(err, errUnsupportedDevNodeOperation):
+			jsonhttp.BadRequest(w, errUnsupportedDevNodeOperation)
+		default:
+			jsonhttp.BadRequest(w, nil)
+		}
+		return
+	}
+
+	archive, err := legacy.ReadArchive(r.Body)
+	if err != nil {
+		logger.Debug("legacy import: read archive failed", "error", err)
+		logger.Error(nil, "legacy import: read archive failed")
+		legacyImportErrorResponse(w, err)
+		return
+	}
+
+	tag, created, err := s.getOrCreateTag(headers.SwarmTag, headers.SwarmTagName)
+	if err != nil {
+		logger.Debug("get or create tag failed", "error", err)
+		logger.Error(nil, "get or create tag failed")
+		switch {
+		case errors.Is(err, tags.ErrNotFound):
+			jsonhttp.NotFound(w, "tag not found")
+		case errors.Is(err, tags.ErrNameTooLong):
+			jsonhttp.BadRequest(w, "invalid tag name")
+		default:
+			jsonhttp.InternalServerError(w, "cannot get or create tag")
+		}
+		return
+	}
+
+	// Add the tag to the context
+	ctx := sctx.SetTag(r.Context(), tag)
+
+	walker, err := archive.Walk(ctx, paths.Address)
+	if err != nil {
+		logger.Debug("legacy import failed", "address", paths.Address, "chunks", archive.Len(), "error", err)
+		logger.Error(nil, "legacy import failed")
+		legacyImportErrorResponse(w, err)
+		return
+	}
+
+	reference, err := s.storeLegacy(ctx, walker, r, putter, tag, created)
+	if err != nil {
+		logger.Debug("legacy import failed", "address", paths.Address, "error", err)
+		logger.Error(nil, "legacy import failed")
+		legacyImportErrorResponse(w, err)
+		return
+	}
+	if err = wait(); err != nil {
+		logger.Debug("sync chunks failed", "error", err)
+		logger.Error(nil, "sync chunks failed")
+		jsonhttp.InternalServerError(w, "sync chunks failed")
+		return
+	}
+
+	if created {
+		if _, err = tag.DoneSplit(reference); err != nil {
+			logger.Debug("done split failed", "error", err)
+			logger.Error(nil, "done split failed")
+			jsonhttp.InternalServerError(w, "done split failed")
+			return
+		}
+	}
+
+	if requestPin(r) {
+		if err := s.pinning.CreatePin(ctx, reference, false); err != nil {
+			logger.Debug("pin creation failed", "address", reference, "error", err)
+			logger.Error(nil, "pin creation failed")
+			jsonhttp.InternalServerError(w, "create pin failed")
+			return
+		}
+	}
+
+	w.Header().Set(SwarmTagHeader, fmt.Sprint(tag.Uid))
+	w.Header().Add("Access-Control-Expose-Headers", SwarmTagHeader)
+	jsonhttp.Created(w, legacyImportResponse{
+		Reference: reference,
+		Manifest:  walker.Dir(),
+	})
+}
+
+// storeLegacy stores the files of the legacy content with the putter and
+// returns the reference of the file content or of the converted manifest.
+func (s *Service) storeLegacy(ctx context.Context, walker *legacy.Walker, r *http.Request, putter storage.Storer, tag *tags.Tag, tagCreated bool) (swarm.Address, error) {
+	if walker.Dir() {
+		index := r.Header.Get(SwarmIndexDocumentHeader)
+		if index == "" {
+			index = walker.Index()
+		}
+		return storeDir(
+			ctx,
+			requestEncrypt(r),
+			&legacyDirReader{w: walker},
+			s.logger,
+			requestPipelineFn(putter, r),
+			"",
+			loadsave.New(putter, requestPipelineFactory(ctx, putter, r)),
+			index,
+			r.Header.Get(SwarmErrorDocumentHeader),
+			r.Header.Get(SwarmWebsiteRedirectsHeader),
+			tag,
+			tagCreated,
+			nil,
+		)
+	}
+
+	f, err := walker.Next()
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	if !tagCreated {
+		// only in the case when tag is sent via header (i.e. not created by this request)
+		if estimatedTotalChunks := calculateNumberOfChunks(f.Size, requestEncrypt(r)); estimatedTotalChunks > 0 {
+			if err := tag.IncN(tags.TotalChunks, estimatedTotalChunks); err != nil {
+				return swarm.ZeroAddress, fmt.Errorf("increment tag: %w", err)
+			}
+		}
+	}
+	return requestPipelineFn(putter, r)(ctx, f.Reader)
+}
+
+func legacyImportErrorResponse(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		jsonhttp.RequestEntityTooLarge(w, "archive too large")
+	case errors.Is(err, legacy.ErrInvalidArchive):
+		jsonhttp.BadRequest(w, "invalid archive")
+	case errors.Is(err, legacy.ErrInvalidChunk):
+		jsonhttp.BadRequest(w, "invalid chunk in the archive")
+	case errors.Is(err, legacy.ErrMissingChunk):
+		jsonhttp.BadRequest(w, "chunk missing in the archive")
+	case errors.Is(err, legacy.ErrUnsupported):
+		jsonhttp.BadRequest(w, "unsupported legacy manifest")
+	case errors.Is(err, errEmptyDir):
+		jsonhttp.BadRequest(w, errEmptyDir)
+	case errors.Is(err, errInvalidWebsiteRedirects):
+		jsonhttp.BadRequest(w, errInvalidWebsiteRedirects)
+	case errors.Is(err, postage.ErrBucketFull):
+		jsonhttp.PaymentRequired(w, "batch is overissued")
+	default:
+		jsonhttp.InternalServerError(w, "legacy import failed")
+	}
+}
+
+// legacyDirReader reads the files of the imported collection.
+type legacyDirReader struct {
+	w *legacy.Walker
+}
+
+func (d *legacyDirReader) Next() (*FileInfo, error) {
+	f, err := d.w.Next()
+	if err != nil {
+		return nil, err
+	}
+	contentType := f.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(f.Name))
+	}
+	return &FileInfo{
+		Path:        f.Path,
+		Name:        f.Name,
+		ContentType: contentType,
+		Size:        f.Size,
+		ModTime:     f.ModTime,
+		Reader:      f.Reader,
+	}, nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/legacy"
+	"github.com/ethersphere/bee/pkg/legacy/legacytest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestLegacyImport(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: mock.NewStorer(),
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	var (
+		page = []byte("<html><body>swarm</body></html>")
+		logo = bytes.Repeat([]byte{0x89, 'P', 'N', 'G'}, 2000)
+	)
+
+	t.Run("collection", func(t *testing.T) {
+		t.Parallel()
+
+		e := legacytest.NewExport()
+		pageRef := e.AddFile(t, page, true)
+		root := e.AddManifest(t, []legacytest.Entry{
+			{Hash: pageRef.String(), ContentType: "text/html"},
+			{Hash: pageRef.String(), Path: "index.html", ContentType: "text/html"},
+			{Hash: e.AddManifest(t, []legacytest.Entry{
+				{Hash: e.AddFile(t, logo, true).String(), Path: "logo.png", ContentType: "image/png"},
+			}, true).String(), Path: "img/", ContentType: legacy.ManifestContentType},
+		}, true)
+
+		var res api.LegacyImportResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/legacy/"+root.String(), http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(e.Archive(t))),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if !res.Manifest {
+			t.Fatal("got bytes reference, want manifest")
+		}
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+res.Reference.String()+"/img/logo.png", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(logo),
+			jsonhttptest.WithExpectedResponseHeader(api.ContentTypeHeader, "image/png"),
+		)
+		// the default entry is the index document
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+res.Reference.String()+"/", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(page),
+		)
+	})
+
+	t.Run("raw", func(t *testing.T) {
+		t.Parallel()
+
+		e := legacytest.NewExport()
+		root := e.AddFile(t, logo, false)
+
+		var res api.LegacyImportResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/legacy/"+root.String(), http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(e.Archive(t))),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if res.Manifest {
+			t.Fatal("got manifest, want bytes reference")
+		}
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+res.Reference.String(), http.StatusOK,
+			jsonhttptest.WithExpectedResponse(logo),
+		)
+	})
+
+	t.Run("missing chunk", func(t *testing.T) {
+		t.Parallel()
+
+		e := legacytest.NewExport()
+		file := e.AddFile(t, page, false)
+		root := e.AddManifest(t, []legacytest.Entry{{Hash: file.String(), Path: "index.html"}}, false)
+		e.Remove(file)
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/legacy/"+root.String(), http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(e.Archive(t))),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "chunk missing in the archive",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("invalid archive", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/legacy/"+legacytest.NewExport().AddFile(t, page, false).String(), http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader([]byte("not an archive"))),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid archive",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		t.Parallel()
+
+		// the archive is streamed, with a skipped file over the limit
+		pr, pw := io.Pipe()
+		go func() {
+			tw := tar.NewWriter(pw)
+			size := int64(api.MaxLegacyArchiveSize) + 1
+			err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: ".swarm-export-version", Size: size, Mode: 0644})
+			if err == nil {
+				_, err = io.CopyN(tw, zeroReader{}, size)
+			}
+			if err == nil {
+				err = tw.Close()
+			}
+			_ = pw.CloseWithError(err)
+		}()
+		t.Cleanup(func() { _ = pr.Close() })
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/legacy/"+legacytest.NewExport().AddFile(t, page, false).String(), http.StatusRequestEntityTooLarge,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(pr),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "archive too large",
+				Code:    http.StatusRequestEntityTooLarge,
+			}),
+		)
+	})
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = 0
+	}
+	return len(b), nil
+}
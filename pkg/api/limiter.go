@@ -0,0 +1,340 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// downloadLimitsMaxRequestSize is the size of the largest request
+	// which sets the download limits.
+	downloadLimitsMaxRequestSize = 1024
+	// downloadRetryAfter is the time after which the downloads rejected
+	// over the limit of the downloads served at once are retried.
+	downloadRetryAfter = time.Second
+	// clientLimiterIdleTimeout is the time after which the bandwidth
+	// limiter of the client without any downloads is forgotten.
+	clientLimiterIdleTimeout = time.Minute
+)
+
+// DownloadLimits are the limits of the downloads of the API. The zero
+// limits are unlimited.
+type DownloadLimits struct {
+	// ClientBandwidth is the number of the bytes per second which
+	// are sent to a client, by its remote IP address.
+	ClientBandwidth int64
+	// ClientBurst is the number of the bytes which are sent to a client
+	// at once, the client bandwidth if zero.
+	ClientBurst int64
+	// MaxDownloads is the number of the downloads which retrieve
+	// their content at once.
+	MaxDownloads int
+}
+
+// clientLimiter is the bandwidth limiter of a client.
+type clientLimiter struct {
+	*rate.Limiter
+	lastSeen time.Time
+}
+
+// DownloadLimiter throttles the bandwidth of the downloads of the clients
+// with the token buckets and limits the number of the downloads which
+// retrieve their content at once. The limits are changed at runtime, so
+// that the limiter is shared by the API and the debug API services.
+type DownloadLimiter struct {
+	mu        sync.Mutex
+	limits    DownloadLimits
+	active    int
+	clients   map[string]*clientLimiter
+	lastPrune time.Time
+}
+
+// NewDownloadLimiter returns a new DownloadLimiter with the limits.
+func NewDownloadLimiter(l DownloadLimits) *DownloadLimiter {
+	d := &DownloadLimiter{
+		clients: make(map[string]*clientLimiter),
+	}
+	d.SetLimits(l)
+	return d
+}
+
+// Limits returns the current limits.
+func (d *DownloadLimiter) Limits() DownloadLimits {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.limits
+}
+
+// Active returns the number of the downloads retrieving their content.
+func (d *DownloadLimiter) Active() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.active
+}
+
+// SetLimits changes the limits, which apply to the bandwidth limiters of
+// the known clients too. The downloads over the lowered limit of the
+// downloads at once are not interrupted.
+func (d *DownloadLimiter) SetLimits(l DownloadLimits) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.limits = l
+	if l.ClientBandwidth <= 0 {
+		d.clients = make(map[string]*clientLimiter)
+		return
+	}
+	now := time.Now()
+	for _, c := range d.clients {
+		c.SetLimitAt(now, rate.Limit(l.ClientBandwidth))
+		c.SetBurstAt(now, burst(l))
+	}
+}
+
+// acquire takes the place of a download retrieving its content and returns
+// the function which frees it. The ok result is false if there is no place.
+func (d *DownloadLimiter) acquire() (release func(), ok bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.limits.MaxDownloads > 0 && d.active >= d.limits.MaxDownloads {
+		return nil, false
+	}
+	d.active++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			d.mu.Lock()
+			d.active--
+			d.mu.Unlock()
+		})
+	}, true
+}
+
+// client returns the bandwidth limiter of the client, which is nil if the
+// bandwidth is not limited.
+func (d *DownloadLimiter) client(ip string) *rate.Limiter {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.limits.ClientBandwidth <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	if now.Sub(d.lastPrune) > clientLimiterIdleTimeout {
+		// the limiters idle for long enough have their buckets full
+		// again, so that forgetting them does not change the limits
+		for k, c := range d.clients {
+			if now.Sub(c.lastSeen) > clientLimiterIdleTimeout {
+				delete(d.clients, k)
+			}
+		}
+		d.lastPrune = now
+	}
+
+	c, ok := d.clients[ip]
+	if !ok {
+		c = &clientLimiter{Limiter: rate.NewLimiter(rate.Limit(d.limits.ClientBandwidth), burst(d.limits))}
+		d.clients[ip] = c
+	}
+	c.lastSeen = now
+	return c.Limiter
+}
+
+// burst returns the size of the token buckets of the clients.
+func burst(l DownloadLimits) int {
+	b := l.ClientBurst
+	if b <= 0 {
+		b = l.ClientBandwidth
+	}
+	if b > math.MaxInt32 {
+		b = math.MaxInt32
+	}
+	return int(b)
+}
+
+// throttledWriter writes the body of the response within the bandwidth of
+// the client.
+type throttledWriter struct {
+	http.ResponseWriter
+	ctx     context.Context
+	limiter *rate.Limiter
+}
+
+func (tw *throttledWriter) Write(b []byte) (int, error) {
+	var written int
+	for len(b) > 0 {
+		n := len(b)
+		if burst := tw.limiter.Burst(); n > burst {
+			n = burst
+		}
+		if err := tw.limiter.WaitN(tw.ctx, n); err != nil {
+			return written, err
+		}
+		m, err := tw.ResponseWriter.Write(b[:n])
+		written += m
+		if err != nil {
+			return written, err
+		}
+		b = b[n:]
+	}
+	return written, nil
+}
+
+// Flush sends the buffered data to the client.
+func (tw *throttledWriter) Flush() {
+	if f, ok := tw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack lets the WebSocket connections be upgraded, which are throttled
+// by the handlers on their own.
+func (tw *throttledWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := tw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	return h.Hijack()
+}
+
+// waitBandwidth waits until n bytes can be sent within the bandwidth of the
+// client, which is not limited if the limiter is nil.
+func waitBandwidth(ctx context.Context, limiter *rate.Limiter, n int) error {
+	if limiter == nil {
+		return nil
+	}
+	for n > 0 {
+		m := n
+		if burst := limiter.Burst(); m > burst {
+			m = burst
+		}
+		if err := limiter.WaitN(ctx, m); err != nil {
+			return err
+		}
+		n -= m
+	}
+	return nil
+}
+
+// downloadLimitHandler rejects the download requests of the clients which
+// exhausted their bandwidth, with the time after which they should be
+// retried, and throttles the responses of the other ones.
+func (s *Service) downloadLimitHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limiter := s.downloadLimiter.client(remoteIP(r).String())
+		if limiter == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		// the download is admitted if the client has the bandwidth
+		// for a chunk at least, which is not taken until it is sent
+		n := swarm.ChunkSize
+		if b := limiter.Burst(); n > b {
+			n = b
+		}
+		now := time.Now()
+		reservation := limiter.ReserveN(now, n)
+		delay := reservation.DelayFrom(now)
+		reservation.CancelAt(now)
+		if !reservation.OK() || delay > 0 {
+			s.metrics.LimitedDownloads.Inc()
+			retryAfter := int(math.Ceil(delay.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			jsonhttp.TooManyRequests(w, "download bandwidth exceeded")
+			return
+		}
+
+		h.ServeHTTP(&throttledWriter{ResponseWriter: w, ctx: r.Context(), limiter: limiter}, r)
+	})
+}
+
+// acquireDownload takes the place of the download retrieving its content.
+// The too many requests response is written if the ok result is false.
+func (s *Service) acquireDownload(w http.ResponseWriter) (release func(), ok bool) {
+	release, ok = s.downloadLimiter.acquire()
+	if !ok {
+		s.metrics.LimitedDownloads.Inc()
+		w.Header().Set("Retry-After", strconv.Itoa(int(downloadRetryAfter.Seconds())))
+		jsonhttp.TooManyRequests(w, "too many downloads")
+	}
+	return release, ok
+}
+
+type downloadLimitsRequest struct {
+	ClientBandwidth int64 `json:"clientBandwidth"`
+	ClientBurst     int64 `json:"clientBurst"`
+	MaxDownloads    int   `json:"maxDownloads"`
+}
+
+type downloadLimitsResponse struct {
+	ClientBandwidth int64 `json:"clientBandwidth"`
+	ClientBurst     int64 `json:"clientBurst"`
+	MaxDownloads    int   `json:"maxDownloads"`
+	ActiveDownloads int   `json:"activeDownloads"`
+}
+
+func (s *Service) downloadLimitsResponse() downloadLimitsResponse {
+	l := s.downloadLimiter.Limits()
+	return downloadLimitsResponse{
+		ClientBandwidth: l.ClientBandwidth,
+		ClientBurst:     l.ClientBurst,
+		MaxDownloads:    l.MaxDownloads,
+		ActiveDownloads: s.downloadLimiter.Active(),
+	}
+}
+
+func (s *Service) downloadLimitsGetHandler(w http.ResponseWriter, _ *http.Request) {
+	jsonhttp.OK(w, s.downloadLimitsResponse())
+}
+
+// downloadLimitsPutHandler replaces the download limits, which
+// applies to the downloads in progress too.
+func (s *Service) downloadLimitsPutHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("put_download_limits").Build()
+
+	var req downloadLimitsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if jsonhttp.HandleBodyReadError(err, w) {
+			return
+		}
+		logger.Debug("decode request body failed", "error", err)
+		jsonhttp.BadRequest(w, "invalid request body")
+		return
+	}
+	if req.ClientBandwidth < 0 || req.ClientBurst < 0 || req.MaxDownloads < 0 {
+		logger.Debug("invalid download limits", "limits", req)
+		jsonhttp.BadRequest(w, "invalid download limits")
+		return
+	}
+
+	s.downloadLimiter.SetLimits(DownloadLimits{
+		ClientBandwidth: req.ClientBandwidth,
+		ClientBurst:     req.ClientBurst,
+		MaxDownloads:    req.MaxDownloads,
+	})
+	logger.Info("download limits changed", "client_bandwidth", req.ClientBandwidth, "client_burst", req.ClientBurst, "max_downloads", req.MaxDownloads)
+
+	jsonhttp.OK(w, s.downloadLimitsResponse())
+}
@@ -0,0 +1,194 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/analytics"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestDownloadLimits(t *testing.T) {
+	t.Parallel()
+
+	t.Run("client bandwidth", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			Storer:          mock.NewStorer(),
+			Tags:            tags.NewTags(statestore.NewStateStore(), log.Noop),
+			Logger:          log.Noop,
+			Post:            mockpost.New(mockpost.WithAcceptAll()),
+			DownloadLimiter: api.NewDownloadLimiter(api.DownloadLimits{ClientBandwidth: 2000, ClientBurst: 500}),
+		})
+
+		data := bytes.Repeat([]byte("a"), 1000)
+		var res api.BytesPostResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+
+		// the content is sent within the bandwidth, which is
+		// exhausted for the next request right after it
+		jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+res.Reference.String(), http.StatusOK,
+			jsonhttptest.WithExpectedResponse(data),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+res.Reference.String(), http.StatusTooManyRequests,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "download bandwidth exceeded",
+				Code:    http.StatusTooManyRequests,
+			}),
+			jsonhttptest.WithExpectedResponseHeader("Retry-After", "1"),
+		)
+		// the HEAD requests are limited as well
+		jsonhttptest.Request(t, client, http.MethodHead, "/bytes/"+res.Reference.String(), http.StatusTooManyRequests,
+			jsonhttptest.WithExpectedResponseHeader("Retry-After", "1"),
+		)
+	})
+
+	t.Run("max downloads", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := api.NewDownloadLimiter(api.DownloadLimits{MaxDownloads: 1})
+
+		release, ok := limiter.Acquire()
+		if !ok {
+			t.Fatal("first download not acquired")
+		}
+		if _, ok := limiter.Acquire(); ok {
+			t.Fatal("download acquired over the limit")
+		}
+		if got := limiter.Active(); got != 1 {
+			t.Fatalf("got %d active downloads, want 1", got)
+		}
+
+		// the raised limit applies at once
+		limiter.SetLimits(api.DownloadLimits{MaxDownloads: 2})
+		other, ok := limiter.Acquire()
+		if !ok {
+			t.Fatal("download not acquired within the raised limit")
+		}
+
+		release()
+		release()
+		other()
+		if got := limiter.Active(); got != 0 {
+			t.Fatalf("got %d active downloads, want 0", got)
+		}
+	})
+
+	t.Run("client bandwidth with analytics", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			Storer:          mock.NewStorer(),
+			Tags:            tags.NewTags(statestore.NewStateStore(), log.Noop),
+			Logger:          log.Noop,
+			Post:            mockpost.New(mockpost.WithAcceptAll()),
+			Analytics:       analytics.New(0),
+			DownloadLimiter: api.NewDownloadLimiter(api.DownloadLimits{ClientBandwidth: 1 << 20}),
+		})
+
+		data := []byte("throttled and recorded")
+		var bytesRes api.BytesPostResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+			jsonhttptest.WithUnmarshalJSONResponse(&bytesRes),
+		)
+		var bzzRes api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=file.txt", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.ContentTypeHeader, "text/plain"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+			jsonhttptest.WithUnmarshalJSONResponse(&bzzRes),
+		)
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+bytesRes.Reference.String(), http.StatusOK,
+			jsonhttptest.WithExpectedResponse(data),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/chunks/"+bytesRes.Reference.String(), http.StatusOK)
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+bzzRes.Reference.String()+"/", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(data),
+		)
+	})
+
+	t.Run("max downloads of all endpoints", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := api.NewDownloadLimiter(api.DownloadLimits{MaxDownloads: 1})
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			Storer:          mock.NewStorer(),
+			Tags:            tags.NewTags(statestore.NewStateStore(), log.Noop),
+			Logger:          log.Noop,
+			Post:            mockpost.New(mockpost.WithAcceptAll()),
+			DownloadLimiter: limiter,
+		})
+
+		var res api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=file.txt", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.ContentTypeHeader, "text/plain"),
+			jsonhttptest.WithRequestBody(bytes.NewReader([]byte("limited"))),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+
+		release, ok := limiter.Acquire()
+		if !ok {
+			t.Fatal("download not acquired")
+		}
+		defer release()
+
+		tooMany := jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "too many downloads",
+			Code:    http.StatusTooManyRequests,
+		})
+		jsonhttptest.Request(t, client, http.MethodGet, "/chunks/"+res.Reference.String(), http.StatusTooManyRequests, tooMany)
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+res.Reference.String()+"/", http.StatusTooManyRequests, tooMany,
+			jsonhttptest.WithRequestHeader("Accept", "application/x-tar"),
+		)
+	})
+
+	t.Run("debug endpoints", func(t *testing.T) {
+		t.Parallel()
+
+		srv, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI:        true,
+			DownloadLimiter: api.NewDownloadLimiter(api.DownloadLimits{MaxDownloads: 10}),
+		})
+
+		jsonhttptest.Request(t, srv, http.MethodGet, "/download-limits", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.DownloadLimitsResponse{MaxDownloads: 10}),
+		)
+
+		jsonhttptest.Request(t, srv, http.MethodPut, "/download-limits", http.StatusOK,
+			jsonhttptest.WithJSONRequestBody(api.DownloadLimitsRequest{ClientBandwidth: 1 << 20, MaxDownloads: 100}),
+			jsonhttptest.WithExpectedJSONResponse(api.DownloadLimitsResponse{ClientBandwidth: 1 << 20, MaxDownloads: 100}),
+		)
+		jsonhttptest.Request(t, srv, http.MethodGet, "/download-limits", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.DownloadLimitsResponse{ClientBandwidth: 1 << 20, MaxDownloads: 100}),
+		)
+
+		jsonhttptest.Request(t, srv, http.MethodPut, "/download-limits", http.StatusBadRequest,
+			jsonhttptest.WithJSONRequestBody(api.DownloadLimitsRequest{MaxDownloads: -1}),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid download limits",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
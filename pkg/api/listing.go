@@ -0,0 +1,176 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+)
+
+// maxListLimit is the largest page of the list endpoints.
+const maxListLimit = 1000
+
+var (
+	errInvalidCursor = errors.New("invalid cursor")
+	errInvalidSort   = errors.New("invalid sort")
+)
+
+// listQuery is the pagination of the list endpoints. The items are sorted by
+// the Sort field, prefixed with - for the descending order, and the page of
+// at most Limit items follows the item of the Cursor, which is the nextCursor
+// of the previous page. The cursor stays valid if the items change between
+// the requests, as it holds the position in the order, not the offset. The
+// Offset skips the items following the cursor for the offset pagination.
+type listQuery struct {
+	Cursor string `map:"cursor"`
+	Offset int    `map:"offset"`
+	Limit  int    `map:"limit"`
+	Sort   string `map:"sort"`
+}
+
+// listValue is the value of a sort field of an item,
+// the numbers are compared before the strings.
+type listValue struct {
+	N int64  `json:"n,omitempty"`
+	S string `json:"s,omitempty"`
+}
+
+func (v listValue) compare(o listValue) int {
+	switch {
+	case v.N < o.N:
+		return -1
+	case v.N > o.N:
+		return 1
+	}
+	return strings.Compare(v.S, o.S)
+}
+
+// listItem is an item of a list endpoint with its unique
+// key and the values of the fields by which it is sorted.
+type listItem struct {
+	key    string
+	values map[string]listValue
+}
+
+type listCursor struct {
+	Sort  string    `json:"sort"`
+	Value listValue `json:"value"`
+	Key   string    `json:"key"`
+}
+
+// parseListQuery parses the pagination query parameters, it responds
+// with the error and returns false if they are not valid.
+func (s *Service) parseListQuery(logger log.Logger, w http.ResponseWriter, r *http.Request) (listQuery, bool) {
+	var q listQuery
+	if response := s.mapStructure(r.URL.Query(), &q); response != nil {
+		response("invalid query params", logger, w)
+		return q, false
+	}
+	return q, true
+}
+
+// paginate returns the indexes of the items of the page, the total number of
+// the items and the cursor of the next page, which is empty on the last page.
+// The items are sorted by one of the fields, the first one is the default.
+func paginate(items []listItem, q listQuery, defaultLimit int, fields ...string) (page []int, total int, next string, err error) {
+	sortBy := q.Sort
+	if sortBy == "" {
+		sortBy = fields[0]
+	}
+	field, desc := strings.TrimPrefix(sortBy, "-"), strings.HasPrefix(sortBy, "-")
+	if !containsField(fields, field) {
+		return nil, 0, "", fmt.Errorf("%w: %s", errInvalidSort, sortBy)
+	}
+
+	// compare orders the item relative to the value and key in the direction of the sort
+	compare := func(i int, v listValue, key string) int {
+		c := items[i].values[field].compare(v)
+		if c == 0 {
+			c = strings.Compare(items[i].key, key)
+		}
+		if desc {
+			c = -c
+		}
+		return c
+	}
+
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, k int) bool {
+		return compare(order[i], items[order[k]].values[field], items[order[k]].key) < 0
+	})
+
+	start := 0
+	if q.Cursor != "" {
+		c, err := decodeListCursor(q.Cursor)
+		if err != nil || c.Sort != sortBy {
+			return nil, 0, "", errInvalidCursor
+		}
+		start = sort.Search(len(order), func(i int) bool { return compare(order[i], c.Value, c.Key) > 0 })
+	}
+	if q.Offset > 0 {
+		start += q.Offset
+	}
+	if start > len(order) {
+		start = len(order)
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	end := start + limit
+	if end >= len(order) {
+		end = len(order)
+	} else {
+		last := items[order[end-1]]
+		next = encodeListCursor(listCursor{Sort: sortBy, Value: last.values[field], Key: last.key})
+	}
+
+	return order[start:end], len(items), next, nil
+}
+
+// respondListError responds with the error of the paginate.
+func respondListError(logger log.Logger, w http.ResponseWriter, err error) {
+	logger.Debug("invalid list query", "error", err)
+	logger.Error(nil, "invalid list query")
+	jsonhttp.BadRequest(w, err.Error())
+}
+
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func encodeListCursor(c listCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeListCursor(s string) (c listCursor, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(b, &c)
+	return c, err
+}
@@ -0,0 +1,173 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"math/big"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/transaction"
+	"github.com/ethersphere/bee/pkg/transaction/mock"
+)
+
+func TestListPagination(t *testing.T) {
+	t.Parallel()
+
+	// the nonces are ascending and the creation times descending,
+	// the transactions to the odd nonces are sent to the other address
+	var (
+		hashes []common.Hash
+		stored = make(map[common.Hash]*transaction.StoredTransaction)
+		to     = common.HexToAddress("fffe")
+		other  = common.HexToAddress("fffd")
+	)
+	for i := 0; i < 5; i++ {
+		hash := common.BigToHash(big.NewInt(int64(i + 1)))
+		recipient := to
+		if i%2 == 1 {
+			recipient = other
+		}
+		hashes = append(hashes, hash)
+		stored[hash] = &transaction.StoredTransaction{
+			To:       &recipient,
+			Nonce:    uint64(i),
+			Created:  int64(1000 - i),
+			GasPrice: big.NewInt(1),
+			Value:    big.NewInt(1),
+		}
+	}
+	sortedHashes := func(less func(a, b *transaction.StoredTransaction) bool) []common.Hash {
+		s := append([]common.Hash(nil), hashes...)
+		sort.Slice(s, func(i, j int) bool { return less(stored[s[i]], stored[s[j]]) })
+		return s
+	}
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI: true,
+		TransactionOpts: []mock.Option{
+			mock.WithPendingTransactionsFunc(func() ([]common.Hash, error) {
+				return hashes, nil
+			}),
+			mock.WithStoredTransactionFunc(func(txHash common.Hash) (*transaction.StoredTransaction, error) {
+				return stored[txHash], nil
+			}),
+		},
+	})
+
+	// list follows the cursors of the pages of the given size
+	list := func(t *testing.T, query string) (got []common.Hash) {
+		t.Helper()
+
+		cursor := ""
+		for {
+			var res api.TransactionPendingList
+			jsonhttptest.Request(t, client, http.MethodGet, "/transactions?limit=2&"+query+"&cursor="+cursor, http.StatusOK,
+				jsonhttptest.WithUnmarshalJSONResponse(&res),
+			)
+			if res.Total != 5 {
+				t.Fatalf("got total %d, want 5", res.Total)
+			}
+			if len(res.PendingTransactions) > 2 {
+				t.Fatalf("got %d transactions, want at most 2", len(res.PendingTransactions))
+			}
+			for _, tx := range res.PendingTransactions {
+				got = append(got, tx.TransactionHash)
+			}
+			if res.NextCursor == "" {
+				return got
+			}
+			cursor = res.NextCursor
+		}
+	}
+
+	t.Run("default sort", func(t *testing.T) {
+		t.Parallel()
+
+		want := sortedHashes(func(a, b *transaction.StoredTransaction) bool { return a.Nonce < b.Nonce })
+		if got := list(t, ""); !equalHashes(got, want) {
+			t.Fatalf("got transactions %v, want %v", got, want)
+		}
+	})
+
+	t.Run("descending sort", func(t *testing.T) {
+		t.Parallel()
+
+		want := sortedHashes(func(a, b *transaction.StoredTransaction) bool { return a.Nonce > b.Nonce })
+		if got := list(t, "sort=-nonce"); !equalHashes(got, want) {
+			t.Fatalf("got transactions %v, want %v", got, want)
+		}
+	})
+
+	t.Run("sort by created", func(t *testing.T) {
+		t.Parallel()
+
+		want := sortedHashes(func(a, b *transaction.StoredTransaction) bool { return a.Created < b.Created })
+		if got := list(t, "sort=created"); !equalHashes(got, want) {
+			t.Fatalf("got transactions %v, want %v", got, want)
+		}
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		t.Parallel()
+
+		var res api.TransactionPendingList
+		jsonhttptest.Request(t, client, http.MethodGet, "/transactions?to="+other.Hex(), http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if res.Total != 2 || len(res.PendingTransactions) != 2 || res.NextCursor != "" {
+			t.Fatalf("got %+v", res)
+		}
+		for _, tx := range res.PendingTransactions {
+			if *tx.To != other {
+				t.Fatalf("got transaction to %s, want %s", tx.To, other)
+			}
+		}
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/transactions?sort=size", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusBadRequest,
+				Message: "invalid sort: size",
+			}),
+		)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		t.Parallel()
+
+		var res api.TransactionPendingList
+		jsonhttptest.Request(t, client, http.MethodGet, "/transactions?limit=2", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		// the cursor is bound to the sort of its page
+		jsonhttptest.Request(t, client, http.MethodGet, "/transactions?sort=created&cursor="+res.NextCursor, http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusBadRequest,
+				Message: "invalid cursor",
+			}),
+		)
+	})
+}
+
+func equalHashes(a, b []common.Hash) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
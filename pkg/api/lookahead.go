@@ -0,0 +1,235 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/langos"
+)
+
+const (
+	// lookaheadAdaptive is the buffer size of the lookahead rules
+	// which adapt the buffer size to the read rate of the client.
+	lookaheadAdaptive = "adaptive"
+
+	// minAdaptiveBufferSize and maxAdaptiveBufferSize
+	// bound the buffer size of the adaptive lookahead.
+	minAdaptiveBufferSize = 2 * 32 * 1024
+	maxAdaptiveBufferSize = 128 * 32 * 1024
+
+	// adaptiveInterval is the interval in which the read
+	// rate of the client is measured.
+	adaptiveInterval = 500 * time.Millisecond
+	// adaptiveWindow is the duration of the reads of the
+	// client at the measured rate which is buffered.
+	adaptiveWindow = 2 * time.Second
+)
+
+var errInvalidLookaheadRule = errors.New("invalid lookahead rule")
+
+// LookaheadRule is the lookahead buffer size of the downloads of the content
+// with the content type and size.
+type LookaheadRule struct {
+	// ContentType is the media type, such as video/mp2t, or the type prefix,
+	// such as video/, of the content. The rule without it applies to any content.
+	ContentType string
+	// MaxSize is the largest size of the content the rule applies to.
+	// The rule without it applies to the content of any size.
+	MaxSize int64
+	// BufferSize is the lookahead buffer size. The buffer size of the
+	// rule without it adapts to the read rate of the client.
+	BufferSize int
+}
+
+func (r LookaheadRule) matches(mediaType string, size int64) bool {
+	if r.MaxSize > 0 && size > r.MaxSize {
+		return false
+	}
+	switch {
+	case r.ContentType == "":
+		return true
+	case strings.HasSuffix(r.ContentType, "/"):
+		return strings.HasPrefix(mediaType, r.ContentType)
+	default:
+		return mediaType == r.ContentType
+	}
+}
+
+// LookaheadPolicy selects the lookahead buffer size of the downloads
+// by the first rule which matches the content type and size.
+type LookaheadPolicy struct {
+	Rules []LookaheadRule
+}
+
+// defaultLookaheadRules prefetch the segments of the streamed media as a
+// whole and use the larger buffer for the large files. They are applied
+// after the configured rules.
+var defaultLookaheadRules = []LookaheadRule{
+	{ContentType: "video/mp2t", BufferSize: mediaSegmentBufferSize},
+	{ContentType: "video/iso.segment", BufferSize: mediaSegmentBufferSize},
+	{MaxSize: largeBufferFilesizeThreshold, BufferSize: smallFileBufferSize},
+	{BufferSize: largeFileBufferSize},
+}
+
+// ParseLookaheadPolicy parses the comma separated lookahead rules in the form
+// content-type[:max-size]=buffer-size, where the content type is the media
+// type, the type prefix ending with a slash, or * for any content, the sizes
+// are in bytes, and the buffer size is adaptive for the buffer size adapted
+// to the read rate of the client, as in
+// video/=adaptive,application/json:65536=32768. The default rules apply to
+// the content which is not matched by any rule.
+func ParseLookaheadPolicy(s string) (*LookaheadPolicy, error) {
+	p := new(LookaheadPolicy)
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		match, buffer, ok := strings.Cut(v, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", errInvalidLookaheadRule, v)
+		}
+
+		var r LookaheadRule
+		contentType, maxSize, ok := strings.Cut(match, ":")
+		if ok {
+			size, err := strconv.ParseInt(maxSize, 10, 64)
+			if err != nil || size <= 0 {
+				return nil, fmt.Errorf("%w: %q: invalid max size", errInvalidLookaheadRule, v)
+			}
+			r.MaxSize = size
+		}
+		if contentType = strings.ToLower(strings.TrimSpace(contentType)); contentType != "*" {
+			if !strings.Contains(contentType, "/") {
+				return nil, fmt.Errorf("%w: %q: invalid content type", errInvalidLookaheadRule, v)
+			}
+			r.ContentType = contentType
+		}
+		if buffer = strings.TrimSpace(buffer); buffer != lookaheadAdaptive {
+			size, err := strconv.Atoi(buffer)
+			if err != nil || size <= 0 {
+				return nil, fmt.Errorf("%w: %q: invalid buffer size", errInvalidLookaheadRule, v)
+			}
+			r.BufferSize = size
+		}
+		p.Rules = append(p.Rules, r)
+	}
+	return p, nil
+}
+
+// BufferSize returns the lookahead buffer size of the content with the
+// content type and size. The zero size is returned for the adaptive buffer.
+func (p *LookaheadPolicy) BufferSize(contentType string, size int64) int {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if p != nil {
+		for _, r := range p.Rules {
+			if r.matches(mediaType, size) {
+				return r.BufferSize
+			}
+		}
+	}
+	for _, r := range defaultLookaheadRules {
+		if r.matches(mediaType, size) {
+			return r.BufferSize
+		}
+	}
+	return largeFileBufferSize
+}
+
+// reader returns the lookahead reader of the content.
+func (p *LookaheadPolicy) reader(r langos.Reader, contentType string, size int64) io.ReadSeeker {
+	bufferSize := p.BufferSize(contentType, size)
+	if bufferSize == 0 {
+		return newAdaptiveLookahead(r, time.Now)
+	}
+	return langos.NewBufferedLangos(r, bufferSize)
+}
+
+// adaptiveLookahead is the lookahead reader whose buffer size follows the read
+// rate of the client, so that the buffer holds the data the client reads in
+// the adaptiveWindow. The lookahead is replaced by the one with the new buffer
+// size at the read position of the client when the rate changes at least
+// twice.
+type adaptiveLookahead struct {
+	r      langos.Reader
+	l      *langos.Langos
+	b      langos.BufferedReadSeeker
+	size   int
+	offset int64 // read position of the client
+	start  time.Time
+	read   int64 // bytes read since start
+	now    func() time.Time
+}
+
+func newAdaptiveLookahead(r langos.Reader, now func() time.Time) *adaptiveLookahead {
+	a := &adaptiveLookahead{
+		r:     r,
+		size:  minAdaptiveBufferSize,
+		start: now(),
+		now:   now,
+	}
+	a.l = langos.NewLangos(r, a.size)
+	a.b = langos.NewBufferedReadSeeker(a.l, a.size)
+	return a
+}
+
+func (a *adaptiveLookahead) Read(p []byte) (int, error) {
+	n, err := a.b.Read(p)
+	a.offset += int64(n)
+	a.read += int64(n)
+	if err == nil {
+		err = a.adapt()
+	}
+	return n, err
+}
+
+func (a *adaptiveLookahead) Seek(offset int64, whence int) (int64, error) {
+	n, err := a.b.Seek(offset, whence)
+	if err == nil {
+		a.offset = n
+	}
+	return n, err
+}
+
+func (a *adaptiveLookahead) ReadAt(p []byte, off int64) (int, error) {
+	return a.r.ReadAt(p, off)
+}
+
+// adapt replaces the lookahead if the read rate of the client
+// needs a buffer size which differs at least twice.
+func (a *adaptiveLookahead) adapt() error {
+	now := a.now()
+	elapsed := now.Sub(a.start)
+	if elapsed < adaptiveInterval {
+		return nil
+	}
+	rate := float64(a.read) / elapsed.Seconds()
+	a.start, a.read = now, 0
+
+	size := int(rate * adaptiveWindow.Seconds())
+	if size < minAdaptiveBufferSize {
+		size = minAdaptiveBufferSize
+	}
+	if size > maxAdaptiveBufferSize {
+		size = maxAdaptiveBufferSize
+	}
+	if size < 2*a.size && 2*size > a.size {
+		return nil
+	}
+
+	_ = a.l.Close()
+	a.size = size
+	a.l = langos.NewLangos(a.r, a.size)
+	a.b = langos.NewBufferedReadSeeker(a.l, a.size)
+	_, err := a.b.Seek(a.offset, io.SeekStart)
+	return err
+}
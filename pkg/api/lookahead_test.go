@@ -0,0 +1,116 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+)
+
+func TestLookaheadPolicy(t *testing.T) {
+	t.Parallel()
+
+	p, err := api.ParseLookaheadPolicy("video/=adaptive, application/json:65536=32768, *:1000=4096")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		contentType string
+		size        int64
+		want        int
+	}{
+		{"video/mp4", 100 << 20, 0},
+		{"video/mp2t", 1 << 20, 0},
+		{"application/json; charset=utf-8", 1024, 32768},
+		{"application/json", 1 << 20, api.SmallFileBufferSize},
+		{"text/html", 1000, 4096},
+		{"text/html", 1 << 20, api.SmallFileBufferSize},
+		{"text/html", 100 << 20, api.LargeFileBufferSize},
+	} {
+		if got := p.BufferSize(tc.contentType, tc.size); got != tc.want {
+			t.Errorf("buffer size of %s of size %d: got %d, want %d", tc.contentType, tc.size, got, tc.want)
+		}
+	}
+
+	// the default rules apply without the configured ones
+	var def *api.LookaheadPolicy
+	if got := def.BufferSize("video/mp2t", 1<<20); got != api.MediaSegmentBufferSize {
+		t.Errorf("got buffer size %d, want %d", got, api.MediaSegmentBufferSize)
+	}
+
+	for _, s := range []string{
+		"video/",
+		"video=1024",
+		"video/:x=1024",
+		"video/:0=1024",
+		"video/=fast",
+		"video/=-1",
+	} {
+		if _, err := api.ParseLookaheadPolicy(s); !errors.Is(err, api.ErrInvalidLookaheadRule) {
+			t.Errorf("parse %q: got error %v, want %v", s, err, api.ErrInvalidLookaheadRule)
+		}
+	}
+}
+
+func TestAdaptiveLookahead(t *testing.T) {
+	t.Parallel()
+
+	data := make([]byte, 8<<20)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+
+	// read reads the data in 32KiB reads, which are
+	// step apart, and returns the last buffer size
+	read := func(t *testing.T, step time.Duration) int {
+		t.Helper()
+
+		now := time.Unix(0, 0)
+		a := api.NewAdaptiveLookahead(bytes.NewReader(data), func() time.Time {
+			now = now.Add(step)
+			return now
+		})
+
+		var got bytes.Buffer
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := a.Read(buf)
+			got.Write(buf[:n])
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if !bytes.Equal(got.Bytes(), data) {
+			t.Fatal("read data mismatch")
+		}
+		return a.BufferSize()
+	}
+
+	t.Run("fast client", func(t *testing.T) {
+		t.Parallel()
+
+		if got := read(t, 10*time.Millisecond); got != api.MaxAdaptiveBufferSize {
+			t.Fatalf("got buffer size %d, want %d", got, api.MaxAdaptiveBufferSize)
+		}
+	})
+
+	t.Run("slow client", func(t *testing.T) {
+		t.Parallel()
+
+		if got := read(t, time.Second); got != api.MinAdaptiveBufferSize {
+			t.Fatalf("got buffer size %d, want %d", got, api.MinAdaptiveBufferSize)
+		}
+	})
+}
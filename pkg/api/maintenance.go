@@ -0,0 +1,55 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+)
+
+type maintenanceJobResponse struct {
+	Name         string    `json:"name"`
+	Interval     int64     `json:"interval"` // duration in seconds
+	NextRun      time.Time `json:"nextRun"`
+	LastRun      time.Time `json:"lastRun"`
+	LastDuration int64     `json:"lastDuration"` // duration in milliseconds
+	LastError    string    `json:"lastError,omitempty"`
+	Running      bool      `json:"running"`
+}
+
+type maintenanceResponse struct {
+	Windows []string                 `json:"windows"`
+	Jobs    []maintenanceJobResponse `json:"jobs"`
+}
+
+// maintenanceHandler returns the maintenance windows and
+// the next runs of the scheduled background jobs.
+func (s *Service) maintenanceHandler(w http.ResponseWriter, _ *http.Request) {
+	if s.maintenance == nil {
+		jsonhttp.NotImplemented(w, "maintenance scheduler not available")
+		return
+	}
+
+	jobs := s.maintenance.Jobs()
+	resp := maintenanceResponse{
+		Windows: s.maintenance.Windows().Strings(),
+		Jobs:    make([]maintenanceJobResponse, 0, len(jobs)),
+	}
+	for _, j := range jobs {
+		resp.Jobs = append(resp.Jobs, maintenanceJobResponse{
+			Name:         j.Name,
+			Interval:     int64(j.Interval / time.Second),
+			NextRun:      j.NextRun,
+			LastRun:      j.LastRun,
+			LastDuration: j.LastDuration.Milliseconds(),
+			LastError:    j.LastError,
+			Running:      j.Running,
+		})
+	}
+
+	jsonhttp.OK(w, resp)
+}
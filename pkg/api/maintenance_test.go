@@ -0,0 +1,65 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/maintenance"
+)
+
+func TestMaintenance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not available", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+		})
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/maintenance", http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "maintenance scheduler not available",
+				Code:    http.StatusNotImplemented,
+			}),
+		)
+	})
+
+	t.Run("jobs", func(t *testing.T) {
+		t.Parallel()
+
+		windows, err := maintenance.ParseWindows([]string{"sat,sun 01:00-05:00"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		scheduler := maintenance.New(windows, log.Noop)
+		t.Cleanup(func() { _ = scheduler.Close() })
+		scheduler.Register("db_compaction", 24*time.Hour, func(context.Context) error { return nil })
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI:    true,
+			Maintenance: scheduler,
+		})
+
+		var got api.MaintenanceResponse
+		jsonhttptest.Request(t, client, http.MethodGet, "/maintenance", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&got),
+		)
+		if len(got.Windows) != 1 || got.Windows[0] != "sat,sun 01:00-05:00" {
+			t.Fatalf("got windows %v", got.Windows)
+		}
+		if len(got.Jobs) != 1 || got.Jobs[0].Name != "db_compaction" || got.Jobs[0].Interval != 86400 {
+			t.Fatalf("got jobs %+v", got.Jobs)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/manifest/mantaray"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+)
+
+type manifestNodeType struct {
+	Value             bool `json:"value"`
+	Edge              bool `json:"edge"`
+	WithPathSeparator bool `json:"withPathSeparator"`
+	WithMetadata      bool `json:"withMetadata"`
+}
+
+type manifestFork struct {
+	Prefix    string            `json:"prefix"`
+	Reference swarm.Address     `json:"reference"`
+	Type      manifestNodeType  `json:"type"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+type manifestNodeResponse struct {
+	Path           string            `json:"path"`
+	Reference      swarm.Address     `json:"reference"`
+	ObfuscationKey string            `json:"obfuscationKey"`
+	Type           manifestNodeType  `json:"type"`
+	Entry          *swarm.Address    `json:"entry,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	Forks          []manifestFork    `json:"forks"`
+}
+
+func newManifestNodeType(n *mantaray.Node) manifestNodeType {
+	return manifestNodeType{
+		Value:             n.IsValueType(),
+		Edge:              n.IsEdgeType(),
+		WithPathSeparator: n.IsWithPathSeparatorType(),
+		WithMetadata:      n.IsWithMetadataType(),
+	}
+}
+
+// manifestNodeHandler decodes the mantaray node of the manifest on the path,
+// which must end on the boundary of a fork prefix, with its forks for the
+// debugging of the manifests. The nodes of the forks are not retrieved, their
+// types and metadata are the ones stored in the forks.
+func (s *Service) manifestNodeHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_manifest_node").Build()
+
+	paths := struct {
+		Address swarm.Address `map:"address,resolve" validate:"required"`
+	}{}
+	if response := s.mapStructureContext(r.Context(), mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	queries := struct {
+		Path string `map:"path"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	ctx := r.Context()
+	ls := loadsave.NewReadonly(s.storer)
+
+	var forks []mantaray.Fork
+	node, err := mantaray.NewNodeRef(paths.Address.Bytes()).LookupNode(ctx, []byte(queries.Path), ls)
+	if err == nil {
+		// the node on the path is loaded with its forks
+		forks, err = node.Forks(ctx, ls)
+	}
+	if err != nil {
+		logger.Debug("get manifest node: lookup failed", "address", paths.Address, "path", queries.Path, "error", err)
+		logger.Error(nil, "get manifest node: lookup failed")
+		switch {
+		case errors.Is(err, mantaray.ErrNotFound):
+			jsonhttp.NotFound(w, "path not found")
+		case errors.Is(err, storage.ErrNotFound):
+			jsonhttp.NotFound(w, "manifest node not found")
+		case errors.Is(err, mantaray.ErrTooShort),
+			errors.Is(err, mantaray.ErrInvalidVersionHash),
+			errors.Is(err, mantaray.ErrInvalidManifest):
+			jsonhttp.BadRequest(w, err.Error())
+		default:
+			jsonhttp.InternalServerError(w, "manifest node lookup failed")
+		}
+		return
+	}
+
+	res := manifestNodeResponse{
+		Path:           queries.Path,
+		Reference:      swarm.NewAddress(node.Reference()),
+		ObfuscationKey: hex.EncodeToString(node.ObfuscationKey()),
+		Type:           newManifestNodeType(node),
+		Metadata:       node.Metadata(),
+		Forks:          make([]manifestFork, 0, len(forks)),
+	}
+	if len(node.Entry()) > 0 {
+		entry := swarm.NewAddress(node.Entry())
+		res.Entry = &entry
+	}
+	for _, f := range forks {
+		res.Forks = append(res.Forks, manifestFork{
+			Prefix:    string(f.Prefix),
+			Reference: swarm.NewAddress(f.Node.Reference()),
+			Type:      newManifestNodeType(f.Node),
+			Metadata:  f.Node.Metadata(),
+		})
+	}
+
+	jsonhttp.OK(w, res)
+}
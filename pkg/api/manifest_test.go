@@ -0,0 +1,130 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestManifestNode(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: smock.NewStorer(),
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	var resp api.BzzUploadResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmIndexDocumentHeader, "index.html"),
+		jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+		jsonhttptest.WithRequestBody(tarFiles(t, []f{
+			{data: []byte("<h1>Swarm</h1>"), name: "index.html", header: http.Header{"Content-Type": {"text/html; charset=utf-8"}}},
+			{data: []byte("1"), name: "1.png", dir: "img"},
+			{data: []byte("2"), name: "2.png", dir: "img"},
+		})),
+		jsonhttptest.WithUnmarshalJSONResponse(&resp),
+	)
+	nodeURL := "/manifests/" + resp.Reference.String() + "/node"
+
+	t.Run("root", func(t *testing.T) {
+		t.Parallel()
+
+		var res api.ManifestNodeResponse
+		jsonhttptest.Request(t, client, http.MethodGet, nodeURL, http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if !res.Reference.Equal(resp.Reference) {
+			t.Fatalf("got reference %s, want %s", res.Reference, resp.Reference)
+		}
+		if !res.Type.Edge || res.ObfuscationKey != strings.Repeat("0", 64) {
+			t.Fatalf("got node %+v", res)
+		}
+		var prefixes []string
+		for _, f := range res.Forks {
+			prefixes = append(prefixes, f.Prefix)
+			if f.Reference.IsZero() {
+				t.Errorf("fork %q: missing reference", f.Prefix)
+			}
+		}
+		if got := strings.Join(prefixes, ","); got != "/,i" {
+			t.Fatalf("got fork prefixes %s, want /,i", got)
+		}
+		// the root metadata is stored in the fork of the root path
+		if root := res.Forks[0]; !root.Type.WithMetadata || root.Metadata["website-index-document"] != "index.html" {
+			t.Fatalf("got root fork %+v", root)
+		}
+	})
+
+	t.Run("path", func(t *testing.T) {
+		t.Parallel()
+
+		var res api.ManifestNodeResponse
+		jsonhttptest.Request(t, client, http.MethodGet, nodeURL+"?path=img/", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if res.Path != "img/" || len(res.Forks) != 2 || res.Forks[0].Prefix != "1.png" || res.Forks[1].Prefix != "2.png" {
+			t.Fatalf("got node %+v", res)
+		}
+		if !res.Forks[0].Type.Value || res.Forks[0].Metadata["Content-Type"] == "" {
+			t.Fatalf("got fork %+v", res.Forks[0])
+		}
+
+		jsonhttptest.Request(t, client, http.MethodGet, nodeURL+"?path=img/1.png", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if !res.Type.Value || res.Entry == nil || res.Entry.IsZero() || len(res.Forks) != 0 {
+			t.Fatalf("got node %+v", res)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, nodeURL+"?path=missing", http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "path not found",
+				Code:    http.StatusNotFound,
+			}),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/manifests/"+swarm.RandAddress(t).String()+"/node", http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "manifest node not found",
+				Code:    http.StatusNotFound,
+			}),
+		)
+	})
+
+	t.Run("not manifest", func(t *testing.T) {
+		t.Parallel()
+
+		var bytesResp api.BytesPostResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(bytes.Repeat([]byte{1}, 200))),
+			jsonhttptest.WithUnmarshalJSONResponse(&bytesResp),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/manifests/"+bytesResp.Reference.String()+"/node", http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// mediaSegmentBufferSize is the lookahead buffer size for the segments
+	// of the streamed media, which are read by the players as a whole, so
+	// a larger prefetch window lowers the time to the first frame.
+	mediaSegmentBufferSize = 64 * 32 * 1024
+
+	// maxOpenEndedRangeSize is the largest response to an open-ended range
+	// request of the media content. The players request the rest of the
+	// file from the playback position and abort the response on seek, so
+	// serving the whole remainder would retrieve chunks which are never read.
+	maxOpenEndedRangeSize = 8 * 1024 * 1024
+)
+
+// the content types of the adaptive streaming playlists and segments,
+// which are often uploaded without a content type or with a wrong one
+var mediaContentTypes = map[string]string{
+	".m3u8": "application/vnd.apple.mpegurl",
+	".m3u":  "audio/mpegurl",
+	".mpd":  "application/dash+xml",
+	".ts":   "video/mp2t",
+	".m4s":  "video/iso.segment",
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".m4a":  "audio/mp4",
+	".webm": "video/webm",
+	".aac":  "audio/aac",
+	".mp3":  "audio/mpeg",
+}
+
+// the content types which are replaced by the media content type
+// determined from the file name extension
+var genericContentTypes = map[string]bool{
+	"":                            true,
+	"application/octet-stream":    true,
+	"text/plain":                  true,
+	"text/vnd.trolltech.linguist": true, // the .ts files in the mime.types of many systems
+}
+
+// mediaContentType returns the content type of the streaming media file
+// with the name if the stored content type is missing or generic.
+func mediaContentType(name, contentType string) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = ""
+	}
+	if !genericContentTypes[mediaType] {
+		return "", false
+	}
+	ct, ok := mediaContentTypes[strings.ToLower(path.Ext(name))]
+	return ct, ok
+}
+
+// isStreamingMedia reports whether the content is the audio or video.
+func isStreamingMedia(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return strings.HasPrefix(mediaType, "video/") || strings.HasPrefix(mediaType, "audio/")
+}
+
+// limitOpenEndedRange rewrites the open-ended byte range of the request,
+// such as bytes=1000-, to cover at most maxOpenEndedRangeSize bytes of the
+// content of the given size. The players continue with the next range
+// request after the partial content is read.
+func limitOpenEndedRange(r *http.Request, size int64) {
+	rng := r.Header.Get("Range")
+	if !strings.HasPrefix(rng, "bytes=") || !strings.HasSuffix(rng, "-") {
+		return
+	}
+	start, err := strconv.ParseInt(strings.TrimSpace(rng[len("bytes="):len(rng)-1]), 10, 64)
+	if err != nil || start < 0 || size-start <= maxOpenEndedRangeSize {
+		return
+	}
+	r.Header.Set("Range", "bytes="+strconv.FormatInt(start, 10)+"-"+strconv.FormatInt(start+maxOpenEndedRangeSize-1, 10))
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestBzzMedia(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: smock.NewStorer(),
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	upload := func(t *testing.T, name, contentType string, data []byte) string {
+		t.Helper()
+
+		var resp api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name="+name, http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+			jsonhttptest.WithRequestHeader("Content-Type", contentType),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		return "/bzz/" + resp.Reference.String() + "/"
+	}
+
+	t.Run("content types", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name        string
+			contentType string
+			want        string
+		}{
+			{name: "index.m3u8", contentType: "application/octet-stream", want: "application/vnd.apple.mpegurl"},
+			{name: "manifest.mpd", contentType: "text/plain; charset=utf-8", want: "application/dash+xml"},
+			{name: "segment0.ts", contentType: "text/vnd.trolltech.linguist", want: "video/mp2t"},
+			{name: "segment0.m4s", contentType: "application/octet-stream", want: "video/iso.segment"},
+			{name: "script.ts", contentType: "application/typescript", want: "application/typescript"},
+			{name: "notes.txt", contentType: "text/plain", want: "text/plain"},
+		} {
+			data := []byte("content of " + tc.name)
+			jsonhttptest.Request(t, client, http.MethodGet, upload(t, tc.name, tc.contentType, data), http.StatusOK,
+				jsonhttptest.WithExpectedResponseHeader("Content-Type", tc.want),
+				jsonhttptest.WithExpectedResponse(data),
+			)
+		}
+	})
+
+	t.Run("open-ended range", func(t *testing.T) {
+		t.Parallel()
+
+		size := api.MaxOpenEndedRangeSize + 1000
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i)
+		}
+		video := upload(t, "movie.mp4", "video/mp4", data)
+
+		jsonhttptest.Request(t, client, http.MethodGet, video, http.StatusPartialContent,
+			jsonhttptest.WithRequestHeader("Range", "bytes=100-"),
+			jsonhttptest.WithExpectedResponseHeader("Accept-Ranges", "bytes"),
+			jsonhttptest.WithExpectedResponseHeader("Content-Range", fmt.Sprintf("bytes 100-%d/%d", 100+api.MaxOpenEndedRangeSize-1, size)),
+			jsonhttptest.WithExpectedResponse(data[100:100+api.MaxOpenEndedRangeSize]),
+		)
+
+		// the remainder within the limit is served as requested
+		start := size - 5000
+		jsonhttptest.Request(t, client, http.MethodGet, video, http.StatusPartialContent,
+			jsonhttptest.WithRequestHeader("Range", fmt.Sprintf("bytes=%d-", start)),
+			jsonhttptest.WithExpectedResponseHeader("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, size-1, size)),
+			jsonhttptest.WithExpectedResponse(data[start:]),
+		)
+	})
+}
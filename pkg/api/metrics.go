@@ -5,12 +5,16 @@
 package api
 
 import (
+	"bufio"
+	"net"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/ethersphere/bee"
 	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 )
@@ -23,40 +27,53 @@ type metrics struct {
 	// all metrics fields must be exported
 	// to be able to return them by Metrics()
 	// using reflection
-	RequestCount       prometheus.Counter
-	ResponseDuration   prometheus.Histogram
-	PingRequestCount   prometheus.Counter
-	ResponseCodeCounts *prometheus.CounterVec
+	PingRequestCount  prometheus.Counter
+	RouteDuration     *prometheus.HistogramVec
+	RouteRequestSize  *prometheus.HistogramVec
+	RouteResponseSize *prometheus.HistogramVec
 
 	ContentApiDuration prometheus.HistogramVec
+
+	DownloadChunks        prometheus.Histogram
+	DownloadCacheHitRatio prometheus.Histogram
+	DownloadRetries       prometheus.Histogram
+	DownloadRetrievalTime prometheus.Histogram
+	MultiRangeRequests    prometheus.Counter
+	LimitedDownloads      prometheus.Counter
+
+	BatchStampedChunks *prometheus.CounterVec
+	BatchStampDuration *prometheus.HistogramVec
+	BatchBucketFull    *prometheus.CounterVec
 }
 
 func newMetrics() metrics {
 	subsystem := "api"
 
+	// sizes from 256B to 64MB
+	sizeBuckets := prometheus.ExponentialBuckets(256, 4, 10)
+
 	return metrics{
-		RequestCount: prometheus.NewCounter(prometheus.CounterOpts{
+		RouteDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: m.Namespace,
 			Subsystem: subsystem,
-			Name:      "request_count",
-			Help:      "Number of API requests.",
-		}),
-		ResponseDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:      "route_duration_seconds",
+			Help:      "Histogram of API response durations per route and status code.",
+			Buckets:   []float64{0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"route", "method", "code"}),
+		RouteRequestSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: m.Namespace,
 			Subsystem: subsystem,
-			Name:      "response_duration_seconds",
-			Help:      "Histogram of API response durations.",
-			Buckets:   []float64{0.01, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
-		}),
-		ResponseCodeCounts: prometheus.NewCounterVec(
-			prometheus.CounterOpts{
-				Namespace: m.Namespace,
-				Subsystem: subsystem,
-				Name:      "response_code_count",
-				Help:      "Response count grouped by status code",
-			},
-			[]string{"code", "method"},
-		),
+			Name:      "route_request_size_bytes",
+			Help:      "Histogram of API request body sizes per route and status code.",
+			Buckets:   sizeBuckets,
+		}, []string{"route", "method", "code"}),
+		RouteResponseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "route_response_size_bytes",
+			Help:      "Histogram of API response body sizes per route and status code.",
+			Buckets:   sizeBuckets,
+		}, []string{"route", "method", "code"}),
 		ContentApiDuration: *prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: m.Namespace,
 			Subsystem: subsystem,
@@ -64,6 +81,65 @@ func newMetrics() metrics {
 			Help:      "Histogram of file upload API response durations.",
 			Buckets:   []float64{0.5, 1, 2.5, 5, 10, 30, 60},
 		}, []string{"filesize", "method"}),
+		DownloadChunks: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "download_chunks",
+			Help:      "Histogram of the number of the chunks read by the downloads.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 10),
+		}),
+		DownloadCacheHitRatio: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "download_cache_hit_ratio",
+			Help:      "Histogram of the share of the chunks of the downloads which were not retrieved from the network.",
+			Buckets:   prometheus.LinearBuckets(0.1, 0.1, 10),
+		}),
+		DownloadRetries: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "download_retries",
+			Help:      "Histogram of the number of the repeated retrieval requests of the downloads.",
+			Buckets:   []float64{0, 1, 2, 5, 10, 50, 100, 500},
+		}),
+		DownloadRetrievalTime: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "download_retrieval_seconds",
+			Help:      "Histogram of the total time of the retrievals from the network of the downloads.",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60},
+		}),
+		MultiRangeRequests: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "multi_range_requests_total",
+			Help:      "Total number of the downloads served as the multipart responses of the requested ranges.",
+		}),
+		LimitedDownloads: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "limited_downloads_total",
+			Help:      "Total number of the downloads rejected over the bandwidth of the client or the number of the downloads at once.",
+		}),
+		BatchStampedChunks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "batch_stamped_chunks_total",
+			Help:      "Total number of the chunks of the uploads stamped per postage batch.",
+		}, []string{"batch_id"}),
+		BatchStampDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "batch_stamp_duration_seconds",
+			Help:      "Histogram of the durations of the stamping of the chunks of the uploads per postage batch.",
+			Buckets:   []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1},
+		}, []string{"batch_id"}),
+		BatchBucketFull: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "batch_bucket_full_total",
+			Help:      "Total number of the chunks of the uploads not stamped as their bucket was full per postage batch.",
+		}, []string{"batch_id"}),
 	}
 }
 
@@ -82,26 +158,55 @@ func (s *Service) Metrics() []prometheus.Collector {
 	return m.PrometheusCollectorsFromFields(s.metrics)
 }
 
-func (s *Service) pageviewMetricsHandler(h http.Handler) http.Handler {
+// routeMetricsHandler observes the duration and the body sizes of the
+// requests per route and status code. It is a router middleware, so that
+// the path template of the matched route is available. The trace ID of the
+// request is attached to the observations as an exemplar, so that a slow
+// request can be looked up in the tracing backend.
+func (s *Service) routeMetricsHandler(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
-		s.metrics.RequestCount.Inc()
-		h.ServeHTTP(w, r)
-		s.metrics.ResponseDuration.Observe(time.Since(start).Seconds())
-	})
-}
 
-func (s *Service) responseCodeMetricsHandler(h http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the handlers check for http.NoBody to detect empty requests
+		body := &countingReadCloser{ReadCloser: r.Body}
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = body
+		}
 		wrapper := newResponseWriter(w)
+
 		h.ServeHTTP(wrapper, r)
-		s.metrics.ResponseCodeCounts.WithLabelValues(
-			strconv.Itoa(wrapper.statusCode),
-			r.Method,
-		).Inc()
+
+		route := "unknown"
+		if cr := mux.CurrentRoute(r); cr != nil {
+			if tpl, err := cr.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+		labels := []string{route, r.Method, strconv.Itoa(wrapper.statusCode)}
+
+		// the tracing handlers of the routes inject
+		// the span context into the request headers
+		var exemplar prometheus.Labels
+		if sc, err := s.tracer.FromHTTPHeaders(r.Header); err == nil {
+			if traceID := tracing.TraceID(sc); traceID != "" {
+				exemplar = prometheus.Labels{"trace_id": traceID}
+			}
+		}
+
+		observe(s.metrics.RouteDuration.WithLabelValues(labels...), time.Since(start).Seconds(), exemplar)
+		observe(s.metrics.RouteRequestSize.WithLabelValues(labels...), float64(body.n), exemplar)
+		observe(s.metrics.RouteResponseSize.WithLabelValues(labels...), float64(wrapper.size), exemplar)
 	})
 }
 
+func observe(o prometheus.Observer, v float64, exemplar prometheus.Labels) {
+	if eo, ok := o.(prometheus.ExemplarObserver); ok && exemplar != nil {
+		eo.ObserveWithExemplar(v, exemplar)
+		return
+	}
+	o.Observe(v)
+}
+
 // UpgradedResponseWriter adds more functionality on top of ResponseWriter
 type UpgradedResponseWriter interface {
 	http.ResponseWriter
@@ -117,12 +222,52 @@ type responseWriter struct {
 	UpgradedResponseWriter
 	statusCode  int
 	wroteHeader bool
+	size        int64
 }
 
 func newResponseWriter(w http.ResponseWriter) *responseWriter {
+	uw, ok := w.(UpgradedResponseWriter)
+	if !ok {
+		// the writer of another middleware
+		uw = upgradedResponseWriter{ResponseWriter: w}
+	}
 	// StatusOK is called by default if nothing else is called
-	uw := w.(UpgradedResponseWriter)
-	return &responseWriter{uw, http.StatusOK, false}
+	return &responseWriter{UpgradedResponseWriter: uw, statusCode: http.StatusOK}
+}
+
+// upgradedResponseWriter upgrades the response writers of the middlewares
+// which do not implement all of the UpgradedResponseWriter interfaces, by
+// delegating to the ones which they implement.
+type upgradedResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w upgradedResponseWriter) Push(target string, opts *http.PushOptions) error {
+	if p, ok := w.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+func (w upgradedResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+func (w upgradedResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// nolint:staticcheck
+func (w upgradedResponseWriter) CloseNotify() <-chan bool {
+	if cn, ok := w.ResponseWriter.(http.CloseNotifier); ok {
+		return cn.CloseNotify()
+	}
+	return make(chan bool)
 }
 
 func (rw *responseWriter) Status() int {
@@ -138,6 +283,19 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.wroteHeader = true
 }
 
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	n, err := rw.UpgradedResponseWriter.Write(b)
+	rw.size += int64(n)
+	return n, err
+}
+
+// Hijack implements http.Hijacker, which is needed by the websockets.
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	rw.statusCode = http.StatusSwitchingProtocols
+	return rw.UpgradedResponseWriter.Hijack()
+}
+
 func newDebugMetrics() (r *prometheus.Registry) {
 	r = prometheus.NewRegistry()
 
@@ -5,9 +5,15 @@
 package api_test
 
 import (
+	"crypto/ecdsa"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func TestToFileSizeBucket(t *testing.T) {
@@ -31,3 +37,56 @@ func TestToFileSizeBucket(t *testing.T) {
 		t.Fatalf("bucket should be the last bucket")
 	}
 }
+
+func TestRouteMetrics(t *testing.T) {
+	t.Parallel()
+
+	s := api.New(ecdsa.PublicKey{}, ecdsa.PublicKey{}, common.Address{}, log.Noop, nil, nil, api.FullMode, false, false, nil, nil)
+	_ = s.Configure(nil, nil, nil, api.Options{}, api.ExtraOptions{}, 1, nil)
+	s.MountAPI()
+
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+
+	for _, path := range []string{"/robots.txt", "/robots.txt", "/v1/bytes/invalid"} {
+		res, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = res.Body.Close()
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(s.Metrics()...)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make(map[string]uint64)
+	for _, f := range families {
+		if f.GetName() != "bee_api_route_duration_seconds" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			counts[labels["method"]+" "+labels["route"]+" "+labels["code"]] = m.GetHistogram().GetSampleCount()
+		}
+	}
+
+	want := map[string]uint64{
+		"GET /robots.txt 200":         2,
+		"GET /v1/bytes/{address} 400": 1,
+	}
+	if len(counts) != len(want) {
+		t.Fatalf("got route metrics %v, want %v", counts, want)
+	}
+	for k, v := range want {
+		if counts[k] != v {
+			t.Fatalf("got %d observations of %q, want %d", counts[k], k, v)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 
 	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/gorilla/mux"
@@ -84,16 +85,18 @@ type Peer struct {
 }
 
 type peersResponse struct {
-	Peers []Peer `json:"peers"`
+	Peers      []Peer `json:"peers"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
-func (s *Service) peersHandler(w http.ResponseWriter, _ *http.Request) {
-	jsonhttp.OK(w, peersResponse{
-		Peers: mapPeers(s.p2p.Peers()),
-	})
+func (s *Service) peersHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_peers").Build()
+
+	s.listPeers(logger, w, r, s.p2p.Peers())
 }
 
-func (s *Service) blocklistedPeersHandler(w http.ResponseWriter, _ *http.Request) {
+func (s *Service) blocklistedPeersHandler(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.WithValues("get_blocklist").Build()
 
 	peers, err := s.p2p.BlocklistedPeers()
@@ -103,18 +106,54 @@ func (s *Service) blocklistedPeersHandler(w http.ResponseWriter, _ *http.Request
 		return
 	}
 
-	jsonhttp.OK(w, peersResponse{
-		Peers: mapPeers(peers),
-	})
+	s.listPeers(logger, w, r, peers)
 }
 
-func mapPeers(peers []p2p.Peer) (out []Peer) {
-	out = make([]Peer, 0, len(peers))
+// listPeers responds with the page of the peers
+// filtered by the fullNode query parameter.
+func (s *Service) listPeers(logger log.Logger, w http.ResponseWriter, r *http.Request, peers []p2p.Peer) {
+	list, ok := s.parseListQuery(logger, w, r)
+	if !ok {
+		return
+	}
+	queries := struct {
+		FullNode *bool `map:"fullNode"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	var (
+		filtered []p2p.Peer
+		items    []listItem
+	)
 	for _, peer := range peers {
-		out = append(out, Peer{
-			Address:  peer.Address,
-			FullNode: peer.FullNode,
+		if queries.FullNode != nil && peer.FullNode != *queries.FullNode {
+			continue
+		}
+		filtered = append(filtered, peer)
+		items = append(items, listItem{
+			key:    peer.Address.String(),
+			values: map[string]listValue{"address": {S: peer.Address.String()}},
+		})
+	}
+	page, total, next, err := paginate(items, list, maxListLimit, "address")
+	if err != nil {
+		respondListError(logger, w, err)
+		return
+	}
+
+	res := peersResponse{
+		Peers:      make([]Peer, 0, len(page)),
+		Total:      total,
+		NextCursor: next,
+	}
+	for _, i := range page {
+		res.Peers = append(res.Peers, Peer{
+			Address:  filtered[i].Address,
+			FullNode: filtered[i].FullNode,
 		})
 	}
-	return
+	jsonhttp.OK(w, res)
 }
@@ -178,6 +178,7 @@ func TestPeer(t *testing.T) {
 		jsonhttptest.Request(t, testServer, http.MethodGet, "/peers", http.StatusOK,
 			jsonhttptest.WithExpectedJSONResponse(api.PeersResponse{
 				Peers: []api.Peer{{Address: overlay}},
+				Total: 1,
 			}),
 		)
 	})
@@ -197,6 +198,7 @@ func TestBlocklistedPeers(t *testing.T) {
 	jsonhttptest.Request(t, testServer, http.MethodGet, "/blocklist", http.StatusOK,
 		jsonhttptest.WithExpectedJSONResponse(api.PeersResponse{
 			Peers: []api.Peer{{Address: overlay}},
+			Total: 1,
 		}),
 	)
 }
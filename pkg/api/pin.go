@@ -9,6 +9,7 @@ import (
 	"net/http"
 
 	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/pinning"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/gorilla/mux"
@@ -26,6 +27,14 @@ func (s *Service) pinRootHash(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	queries := struct {
+		Async bool `map:"async"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
 	has, err := s.pinning.HasPin(paths.Reference)
 	if err != nil {
 		logger.Debug("pin root hash: has pin failed", "chunk_address", paths.Reference, "error", err)
@@ -38,6 +47,11 @@ func (s *Service) pinRootHash(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if queries.Async {
+		s.submitJob(logger, w, pinning.JobKind, pinning.JobParams{Reference: paths.Reference})
+		return
+	}
+
 	switch err = s.pinning.CreatePin(r.Context(), paths.Reference, true); {
 	case errors.Is(err, storage.ErrNotFound):
 		jsonhttp.NotFound(w, nil)
@@ -118,10 +132,21 @@ func (s *Service) getPinnedRootHash(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// listPinnedRootHashes lists all the references of the pinned root hashes.
+type pinsResponse struct {
+	References []swarm.Address `json:"references"`
+	Total      int             `json:"total"`
+	NextCursor string          `json:"nextCursor,omitempty"`
+}
+
+// listPinnedRootHashes lists the references of the pinned root hashes.
 func (s *Service) listPinnedRootHashes(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.WithName("get_pins").Build()
 
+	list, ok := s.parseListQuery(logger, w, r)
+	if !ok {
+		return
+	}
+
 	pinned, err := s.pinning.Pins()
 	if err != nil {
 		logger.Debug("list pinned root references: unable to list references", "error", err)
@@ -130,9 +155,26 @@ func (s *Service) listPinnedRootHashes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonhttp.OK(w, struct {
-		References []swarm.Address `json:"references"`
-	}{
-		References: pinned,
-	})
+	items := make([]listItem, 0, len(pinned))
+	for _, ref := range pinned {
+		items = append(items, listItem{
+			key:    ref.String(),
+			values: map[string]listValue{"reference": {S: ref.String()}},
+		})
+	}
+	page, total, next, err := paginate(items, list, maxListLimit, "reference")
+	if err != nil {
+		respondListError(logger, w, err)
+		return
+	}
+
+	res := pinsResponse{
+		References: make([]swarm.Address, 0, len(page)),
+		Total:      total,
+		NextCursor: next,
+	}
+	for _, i := range page {
+		res.References = append(res.References, pinned[i])
+	}
+	jsonhttp.OK(w, res)
 }
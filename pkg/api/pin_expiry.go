@@ -0,0 +1,108 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/gorilla/websocket"
+)
+
+// pinExpiryWsHandler streams the expiry events of the pinned references
+// to the websocket client, so the references can be re-stamped before
+// their chunks leave the reserve of the network.
+func (s *Service) pinExpiryWsHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("pin_expiry_subscribe").Build()
+
+	if s.pinExpiry == nil {
+		jsonhttp.NotImplemented(w, "pin expiry notifications are not available")
+		return
+	}
+
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     s.checkOrigin,
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Debug("upgrade failed", "error", err)
+		logger.Error(nil, "upgrade failed")
+		jsonhttp.InternalServerError(w, "upgrade failed")
+		return
+	}
+
+	s.wsWg.Add(1)
+	go s.pumpPinExpiryWs(conn)
+}
+
+func (s *Service) pumpPinExpiryWs(conn *websocket.Conn) {
+	defer s.wsWg.Done()
+
+	var (
+		gone   = make(chan struct{})
+		ticker = time.NewTicker(s.WsPingPeriod)
+		err    error
+	)
+	defer func() {
+		ticker.Stop()
+		_ = conn.Close()
+	}()
+
+	events, cleanup := s.pinExpiry.SubscribeExpiry()
+	defer cleanup()
+
+	conn.SetCloseHandler(func(code int, text string) error {
+		s.logger.Debug("pin expiry ws: client gone", "code", code, "message", text)
+		close(gone)
+		return nil
+	})
+
+	for {
+		select {
+		case ev := <-events:
+			err = conn.SetWriteDeadline(time.Now().Add(writeDeadline))
+			if err != nil {
+				s.logger.Debug("pin expiry ws: set write deadline failed", "error", err)
+				return
+			}
+
+			err = conn.WriteJSON(ev)
+			if err != nil {
+				s.logger.Debug("pin expiry ws: write message failed", "error", err)
+				return
+			}
+
+		case <-s.quit:
+			// shutdown
+			err = conn.SetWriteDeadline(time.Now().Add(writeDeadline))
+			if err != nil {
+				s.logger.Debug("pin expiry ws: set write deadline failed", "error", err)
+				return
+			}
+			err = conn.WriteMessage(websocket.CloseMessage, []byte{})
+			if err != nil {
+				s.logger.Debug("pin expiry ws: write close message failed", "error", err)
+			}
+			return
+		case <-gone:
+			// client gone
+			return
+		case <-ticker.C:
+			err = conn.SetWriteDeadline(time.Now().Add(writeDeadline))
+			if err != nil {
+				s.logger.Debug("pin expiry ws: set write deadline failed", "error", err)
+				return
+			}
+			if err = conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				// error encountered while pinging client. client probably gone
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/pinning"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+type expirySubscriber chan pinning.ExpiryEvent
+
+func (s expirySubscriber) SubscribeExpiry() (<-chan pinning.ExpiryEvent, func()) {
+	return s, func() {}
+}
+
+func TestPinExpiryWebsocket(t *testing.T) {
+	t.Parallel()
+
+	t.Run("events", func(t *testing.T) {
+		t.Parallel()
+
+		events := make(expirySubscriber, 1)
+		_, cl, _, _ := newTestServer(t, testServerOptions{
+			PinExpiry:    events,
+			WsPath:       "/pins/expiry/subscribe",
+			Logger:       log.Noop,
+			WsPingPeriod: 10 * time.Second,
+		})
+
+		want := pinning.ExpiryEvent{
+			BatchID:    batchOkStr,
+			References: []swarm.Address{swarm.RandAddress(t)},
+		}
+		events <- want
+
+		if err := cl.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		var got pinning.ExpiryEvent
+		if err := cl.ReadJSON(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got.BatchID != want.BatchID {
+			t.Fatalf("got batch ID %s, want %s", got.BatchID, want.BatchID)
+		}
+		if len(got.References) != 1 || !got.References[0].Equal(want.References[0]) {
+			t.Fatalf("got references %v, want %v", got.References, want.References)
+		}
+	})
+
+	t.Run("not available", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{})
+		jsonhttptest.Request(t, client, http.MethodGet, "/pins/expiry/subscribe", http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "pin expiry notifications are not available",
+				Code:    http.StatusNotImplemented,
+			}),
+		)
+	})
+}
@@ -62,10 +62,9 @@ func checkPinHandlers(t *testing.T, client *http.Client, rootHash string, create
 	)
 
 	jsonhttptest.Request(t, client, http.MethodGet, pinsBasePath, http.StatusOK,
-		jsonhttptest.WithExpectedJSONResponse(struct {
-			References []swarm.Address `json:"references"`
-		}{
+		jsonhttptest.WithExpectedJSONResponse(api.PinsResponse{
 			References: []swarm.Address{swarm.MustParseHexAddress(rootHash)},
+			Total:      1,
 		}),
 	)
 
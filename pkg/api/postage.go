@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
 	"net/http"
@@ -20,7 +21,9 @@ import (
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/postage/postagecontract"
 	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/ethersphere/bee/pkg/traversal"
 	"github.com/gorilla/mux"
 )
 
@@ -145,7 +148,9 @@ type postageStampResponse struct {
 }
 
 type postageStampsResponse struct {
-	Stamps []postageStampResponse `json:"stamps"`
+	Stamps     []postageStampResponse `json:"stamps"`
+	Total      int                    `json:"total"`
+	NextCursor string                 `json:"nextCursor,omitempty"`
 }
 
 type postageBatchResponse struct {
@@ -175,16 +180,24 @@ type bucketData struct {
 func (s *Service) postageGetStampsHandler(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.WithName("get_stamps").Build()
 
+	list, ok := s.parseListQuery(logger, w, r)
+	if !ok {
+		return
+	}
 	queries := struct {
-		All bool `map:"all"`
+		All    bool   `map:"all"`
+		Label  string `map:"label"`
+		Usable *bool  `map:"usable"`
 	}{}
 	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
 		response("invalid query params", logger, w)
 		return
 	}
 
-	resp := postageStampsResponse{}
-	resp.Stamps = make([]postageStampResponse, 0, len(s.post.StampIssuers()))
+	var (
+		stamps []postageStampResponse
+		items  []listItem
+	)
 	for _, v := range s.post.StampIssuers() {
 		exists, err := s.batchStore.Exists(v.ID())
 		if err != nil {
@@ -201,22 +214,56 @@ func (s *Service) postageGetStampsHandler(w http.ResponseWriter, r *http.Request
 			jsonhttp.InternalServerError(w, "unable to estimate batch expiration")
 			return
 		}
-		if queries.All || exists {
-			resp.Stamps = append(resp.Stamps, postageStampResponse{
-				BatchID:       v.ID(),
-				Utilization:   v.Utilization(),
-				Usable:        exists && s.post.IssuerUsable(v),
-				Label:         v.Label(),
-				Depth:         v.Depth(),
-				Amount:        bigint.Wrap(v.Amount()),
-				BucketDepth:   v.BucketDepth(),
-				BlockNumber:   v.BlockNumber(),
-				ImmutableFlag: v.ImmutableFlag(),
-				Exists:        exists,
-				BatchTTL:      batchTTL,
-				Expired:       v.Expired(),
-			})
+		if !queries.All && !exists {
+			continue
+		}
+		usable := exists && s.post.IssuerUsable(v)
+		if queries.Label != "" && v.Label() != queries.Label {
+			continue
 		}
+		if queries.Usable != nil && usable != *queries.Usable {
+			continue
+		}
+
+		stamps = append(stamps, postageStampResponse{
+			BatchID:       v.ID(),
+			Utilization:   v.Utilization(),
+			Usable:        usable,
+			Label:         v.Label(),
+			Depth:         v.Depth(),
+			Amount:        bigint.Wrap(v.Amount()),
+			BucketDepth:   v.BucketDepth(),
+			BlockNumber:   v.BlockNumber(),
+			ImmutableFlag: v.ImmutableFlag(),
+			Exists:        exists,
+			BatchTTL:      batchTTL,
+			Expired:       v.Expired(),
+		})
+		items = append(items, listItem{
+			key: hex.EncodeToString(v.ID()),
+			values: map[string]listValue{
+				"batchID":     {S: hex.EncodeToString(v.ID())},
+				"label":       {S: v.Label()},
+				"depth":       {N: int64(v.Depth())},
+				"utilization": {N: int64(v.Utilization())},
+				"batchTTL":    {N: batchTTL},
+			},
+		})
+	}
+
+	page, total, next, err := paginate(items, list, maxListLimit, "batchID", "label", "depth", "utilization", "batchTTL")
+	if err != nil {
+		respondListError(logger, w, err)
+		return
+	}
+
+	resp := postageStampsResponse{
+		Stamps:     make([]postageStampResponse, 0, len(page)),
+		Total:      total,
+		NextCursor: next,
+	}
+	for _, i := range page {
+		resp.Stamps = append(resp.Stamps, stamps[i])
 	}
 
 	jsonhttp.OK(w, resp)
@@ -421,6 +468,82 @@ func (s *Service) chainStateHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+type postageEstimateResponse struct {
+	Chunks       uint64         `json:"chunks"`       // The number of the chunks of the payload.
+	Depth        uint8          `json:"depth"`        // The depth of the batch with enough capacity.
+	Amount       *bigint.BigInt `json:"amount"`       // The amount per chunk lasting for the TTL.
+	TotalAmount  *bigint.BigInt `json:"totalAmount"`  // The cost of the batch.
+	CurrentPrice *bigint.BigInt `json:"currentPrice"` // Bzz/chunk/block normalised price.
+	BatchTTL     int64          `json:"batchTTL"`     // The TTL of the batch in seconds.
+}
+
+// postageEstimateHandler quotes the depth and the amount of the batch
+// storing the payload of the given size for the given TTL at the current
+// price of the chain. The capacity of the depth assumes that the chunks
+// fill the buckets evenly, so the actual utilization may be lower.
+func (s *Service) postageEstimateHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_stamp_estimate").Build()
+
+	queries := struct {
+		Size uint64 `map:"size" validate:"required"`
+		TTL  int64  `map:"ttl" validate:"required,min=1"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	state := s.batchStore.GetChainState()
+	if state.CurrentPrice == nil || state.CurrentPrice.Sign() == 0 {
+		logger.Debug("estimate: price unavailable")
+		logger.Error(nil, "estimate: price unavailable")
+		jsonhttp.ServiceUnavailable(w, "price unavailable")
+		return
+	}
+	blockTime := int64(s.blockTime / time.Second)
+	if blockTime <= 0 {
+		logger.Debug("estimate: invalid block time", "block_time", s.blockTime)
+		logger.Error(nil, "estimate: invalid block time")
+		jsonhttp.InternalServerError(w, "invalid block time")
+		return
+	}
+
+	chunks := estimateChunkCount(queries.Size)
+	depth := postagecontract.BucketDepth + 1
+	for uint64(1)<<depth < chunks {
+		depth++
+	}
+
+	// the amount covers the blocks of the whole TTL
+	blocks := (queries.TTL + blockTime - 1) / blockTime
+	amount := new(big.Int).Mul(big.NewInt(blocks), state.CurrentPrice)
+	totalAmount := new(big.Int).Lsh(amount, uint(depth))
+
+	jsonhttp.OK(w, postageEstimateResponse{
+		Chunks:       chunks,
+		Depth:        depth,
+		Amount:       bigint.Wrap(amount),
+		TotalAmount:  bigint.Wrap(totalAmount),
+		CurrentPrice: bigint.Wrap(new(big.Int).Set(state.CurrentPrice)),
+		BatchTTL:     blocks * blockTime,
+	})
+}
+
+// estimateChunkCount returns the number of the data chunks and the
+// intermediate chunks of the unencrypted payload of the given size.
+func estimateChunkCount(size uint64) uint64 {
+	n := (size + swarm.ChunkSize - 1) / swarm.ChunkSize
+	if n == 0 {
+		n = 1
+	}
+	total := n
+	for n > 1 {
+		n = (n + swarm.Branches - 1) / swarm.Branches
+		total += n
+	}
+	return total
+}
+
 // estimateBatchTTL estimates the time remaining until the batch expires.
 // The -1 signals that the batch never expires.
 func (s *Service) estimateBatchTTLFromID(id []byte) (int64, error) {
@@ -532,3 +655,213 @@ func (s *Service) postageDiluteHandler(w http.ResponseWriter, r *http.Request) {
 		TxHash:  txHash.String(),
 	})
 }
+
+type postageVerifyStampRequest struct {
+	Address swarm.Address `json:"address"`
+	Stamp   string        `json:"stamp"`
+}
+
+type postageVerifyStampResponse struct {
+	Valid            bool    `json:"valid"`
+	Reason           string  `json:"reason,omitempty"`
+	BatchID          hexByte `json:"batchID"`
+	Exists           bool    `json:"exists"`
+	Usable           bool    `json:"usable"`
+	Expired          bool    `json:"expired"`
+	BucketID         uint32  `json:"bucketID"`
+	BucketIndex      uint32  `json:"bucketIndex"`
+	BucketUpperBound uint32  `json:"bucketUpperBound"`
+	BucketCollisions *uint32 `json:"bucketCollisions,omitempty"`
+	BucketFull       bool    `json:"bucketFull"`
+}
+
+// postageVerifyStampHandler checks the validity of the serialized stamp for
+// the chunk address and reports the state of its batch. The usability, the
+// expiry and the bucket collisions are only known for the batches of the node.
+func (s *Service) postageVerifyStampHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_stamp_verify").Build()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		if jsonhttp.HandleBodyReadError(err, w) {
+			return
+		}
+		logger.Debug("read request body failed", "error", err)
+		logger.Error(nil, "read request body failed")
+		jsonhttp.InternalServerError(w, "cannot read request")
+		return
+	}
+
+	var req postageVerifyStampRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		logger.Debug("unmarshal request failed", "error", err)
+		logger.Error(nil, "unmarshal request failed")
+		jsonhttp.BadRequest(w, "invalid request")
+		return
+	}
+	if len(req.Address.Bytes()) != swarm.HashSize {
+		jsonhttp.BadRequest(w, "invalid address")
+		return
+	}
+
+	stamp := new(postage.Stamp)
+	b, err := hex.DecodeString(req.Stamp)
+	if err == nil {
+		err = stamp.UnmarshalBinary(b)
+	}
+	if err != nil {
+		logger.Debug("invalid stamp", "error", err)
+		jsonhttp.BadRequest(w, "invalid stamp")
+		return
+	}
+
+	res := postageVerifyStampResponse{BatchID: stamp.BatchID()}
+	res.BucketID, res.BucketIndex = stamp.BucketIndex()
+
+	var issuer *postage.StampIssuer
+	for _, stampIssuer := range s.post.StampIssuers() {
+		if bytes.Equal(stamp.BatchID(), stampIssuer.ID()) {
+			issuer = stampIssuer
+			break
+		}
+	}
+	if issuer != nil {
+		res.BucketUpperBound = issuer.BucketUpperBound()
+		if buckets := issuer.Buckets(); int(res.BucketID) < len(buckets) {
+			collisions := buckets[res.BucketID]
+			res.BucketCollisions = &collisions
+			res.BucketFull = collisions >= res.BucketUpperBound
+		}
+	}
+
+	batch, err := s.batchStore.Get(stamp.BatchID())
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		res.Reason = "batch not found"
+	case err != nil:
+		logger.Debug("get batch failed", "batch_id", hex.EncodeToString(stamp.BatchID()), "error", err)
+		logger.Error(nil, "get batch failed")
+		jsonhttp.InternalServerError(w, "get batch failed")
+		return
+	default:
+		res.Exists = true
+		res.BucketUpperBound = 1 << (batch.Depth - batch.BucketDepth)
+		if err := stamp.Valid(req.Address, batch.Owner, batch.Depth, batch.BucketDepth, batch.Immutable); err != nil {
+			res.Reason = err.Error()
+		} else {
+			res.Valid = true
+		}
+	}
+
+	// the expired batches are removed from the batch store
+	if issuer != nil {
+		res.Expired = issuer.Expired() || !res.Exists
+		res.Usable = res.Exists && !res.Expired && s.post.IssuerUsable(issuer)
+	}
+
+	jsonhttp.OK(w, res)
+}
+
+type postageRestampResponse struct {
+	BatchID   hexByte       `json:"batchID"`
+	Reference swarm.Address `json:"reference"`
+	Chunks    int           `json:"chunks"`
+}
+
+// postageRestampHandler stamps all the chunks of the local content of the
+// reference with the batch and pushes them to the network, so that the
+// content outlives the batch with which it was uploaded.
+func (s *Service) postageRestampHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_stamp_restamp").Build()
+
+	paths := struct {
+		BatchID []byte        `map:"batch_id" validate:"required,len=32"`
+		Address swarm.Address `map:"address,resolve" validate:"required"`
+	}{}
+	if response := s.mapStructureContext(r.Context(), mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+	hexBatchID := hex.EncodeToString(paths.BatchID)
+
+	if s.beeMode == DevMode {
+		jsonhttp.BadRequest(w, errUnsupportedDevNodeOperation)
+		return
+	}
+
+	exists, err := s.batchStore.Exists(paths.BatchID)
+	if err != nil {
+		logger.Debug("restamp: batch exists check failed", "batch_id", hexBatchID, "error", err)
+		logger.Error(nil, "restamp: batch exists check failed")
+		jsonhttp.InternalServerError(w, "batch exists check failed")
+		return
+	}
+
+	issuer, save, err := s.post.GetStampIssuer(paths.BatchID)
+	if err != nil {
+		logger.Debug("restamp: get issuer failed", "batch_id", hexBatchID, "error", err)
+		logger.Error(nil, "restamp: get issuer failed")
+		switch {
+		case errors.Is(err, postage.ErrNotUsable):
+			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+		case errors.Is(err, postage.ErrNotFound):
+			jsonhttp.NotFound(w, "batch with id not found")
+		default:
+			jsonhttp.InternalServerError(w, "get issuer failed")
+		}
+		return
+	}
+	defer func() {
+		if err := save(); err != nil {
+			logger.Debug("restamp: stamp issuer save failed", "batch_id", hexBatchID, "error", err)
+		}
+	}()
+
+	if !exists || !s.post.IssuerUsable(issuer) {
+		jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+		return
+	}
+
+	ctx := r.Context()
+	p := newPushStamperPutter(s.storer, s.newStamper(issuer), s.batchQueues)
+	store := localStore{s.storer}
+	// the traversal yields the repeated chunks of the content every time,
+	// but each of them is stamped and pushed once
+	seen := make(map[string]struct{})
+	err = traversal.New(store).Traverse(ctx, paths.Address, func(addr swarm.Address) error {
+		if _, ok := seen[addr.ByteString()]; ok {
+			return nil
+		}
+		seen[addr.ByteString()] = struct{}{}
+
+		ch, err := store.Get(ctx, storage.ModeGetRequest, addr)
+		if err != nil {
+			return err
+		}
+		// the stored chunk keeps its previous stamp,
+		// so that it is always stamped again
+		return p.stamp(ctx, ch)
+	})
+	if waitErr := p.Wait(); err == nil {
+		err = waitErr
+	}
+	if err != nil {
+		logger.Debug("restamp: restamp failed", "batch_id", hexBatchID, "address", paths.Address, "error", err)
+		logger.Error(nil, "restamp: restamp failed")
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			jsonhttp.NotFound(w, "content not found locally")
+		case errors.Is(err, postage.ErrBucketFull):
+			jsonhttp.PaymentRequired(w, "batch is overissued")
+		default:
+			jsonhttp.InternalServerError(w, "restamp failed")
+		}
+		return
+	}
+
+	jsonhttp.OK(w, postageRestampResponse{
+		BatchID:   paths.BatchID,
+		Reference: paths.Address,
+		Chunks:    len(seen),
+	})
+}
@@ -19,6 +19,8 @@ import (
 
 	"github.com/ethersphere/bee/pkg/api"
 	"github.com/ethersphere/bee/pkg/bigint"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/postage"
@@ -28,7 +30,11 @@ import (
 	contractMock "github.com/ethersphere/bee/pkg/postage/postagecontract/mock"
 	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
 	"github.com/ethersphere/bee/pkg/sctx"
+	"github.com/ethersphere/bee/pkg/storage"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/transaction/backendmock"
+	"github.com/ethersphere/bee/pkg/traversal"
 )
 
 func TestPostageCreateStamp(t *testing.T) {
@@ -288,6 +294,7 @@ func TestPostageGetStamps(t *testing.T) {
 						Expired:       false,
 					},
 				},
+				Total: 1,
 			}),
 		)
 	})
@@ -319,6 +326,7 @@ func TestPostageGetStamps(t *testing.T) {
 						BatchTTL:      -1,
 					},
 				},
+				Total: 1,
 			}),
 		)
 	})
@@ -353,6 +361,7 @@ func TestPostageGetStamps(t *testing.T) {
 						Expired:       true,
 					},
 				},
+				Total: 1,
 			}),
 		)
 	})
@@ -495,6 +504,127 @@ func TestPostageGetBuckets(t *testing.T) {
 
 }
 
+func TestPostageVerifyStamp(t *testing.T) {
+	t.Parallel()
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		addr  = swarm.MustParseHexAddress("7351ce957ff75417ca7d4f758e3eb627d4f40cd52d53967b855c4e52fea8bfaf")
+		si    = postage.NewStampIssuer("", "", batchOk, big.NewInt(3), 11, 10, 1000, true)
+		batch = postagetesting.MustNewBatch(postagetesting.WithOwner(owner.Bytes()))
+	)
+	batch.ID, batch.Depth, batch.BucketDepth = batchOk, 11, 10
+
+	stamp, err := postage.NewStamper(si, signer).Stamp(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := stamp.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := func(addr swarm.Address, stamp string) jsonhttptest.Option {
+		return jsonhttptest.WithJSONRequestBody(struct {
+			Address swarm.Address `json:"address"`
+			Stamp   string        `json:"stamp"`
+		}{addr, stamp})
+	}
+	bucket, _ := stamp.BucketIndex()
+	collisions := uint32(1)
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			Post:       mockpost.New(mockpost.WithIssuer(si)),
+			BatchStore: mock.New(mock.WithBatch(batch), mock.WithAcceptAllExistsFunc()),
+			DebugAPI:   true,
+		})
+
+		jsonhttptest.Request(t, ts, http.MethodPost, "/stamps/verify", http.StatusOK,
+			body(addr, hex.EncodeToString(b)),
+			jsonhttptest.WithExpectedJSONResponse(api.PostageVerifyStampResponse{
+				Valid:            true,
+				BatchID:          batchOk,
+				Exists:           true,
+				Usable:           true,
+				BucketID:         bucket,
+				BucketUpperBound: 2,
+				BucketCollisions: &collisions,
+			}),
+		)
+	})
+
+	t.Run("other address", func(t *testing.T) {
+		t.Parallel()
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			Post:       mockpost.New(mockpost.WithIssuer(si)),
+			BatchStore: mock.New(mock.WithBatch(batch), mock.WithAcceptAllExistsFunc()),
+			DebugAPI:   true,
+		})
+
+		other := swarm.MustParseHexAddress("0051ce957ff75417ca7d4f758e3eb627d4f40cd52d53967b855c4e52fea8bfaf")
+		jsonhttptest.Request(t, ts, http.MethodPost, "/stamps/verify", http.StatusOK,
+			body(other, hex.EncodeToString(b)),
+			jsonhttptest.WithExpectedJSONResponse(api.PostageVerifyStampResponse{
+				Reason:           postage.ErrBucketMismatch.Error(),
+				BatchID:          batchOk,
+				Exists:           true,
+				Usable:           true,
+				BucketID:         bucket,
+				BucketUpperBound: 2,
+				BucketCollisions: &collisions,
+			}),
+		)
+	})
+
+	t.Run("batch not found", func(t *testing.T) {
+		t.Parallel()
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			Post:       mockpost.New(),
+			BatchStore: mock.New(),
+			DebugAPI:   true,
+		})
+
+		jsonhttptest.Request(t, ts, http.MethodPost, "/stamps/verify", http.StatusOK,
+			body(addr, hex.EncodeToString(b)),
+			jsonhttptest.WithExpectedJSONResponse(api.PostageVerifyStampResponse{
+				Reason:   "batch not found",
+				BatchID:  batchOk,
+				BucketID: bucket,
+			}),
+		)
+	})
+
+	t.Run("invalid stamp", func(t *testing.T) {
+		t.Parallel()
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			Post:     mockpost.New(),
+			DebugAPI: true,
+		})
+
+		jsonhttptest.Request(t, ts, http.MethodPost, "/stamps/verify", http.StatusBadRequest,
+			body(addr, "abcd"),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid stamp",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
+
 func TestReserveState(t *testing.T) {
 	t.Parallel()
 
@@ -555,6 +685,81 @@ func TestChainState(t *testing.T) {
 
 }
 
+func TestPostageEstimate(t *testing.T) {
+	t.Parallel()
+
+	cs := &postage.ChainState{Block: 10, TotalAmount: big.NewInt(5), CurrentPrice: big.NewInt(2)}
+	ts, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI:   true,
+		BatchStore: mock.New(mock.WithChainState(cs)),
+		BlockTime:  2 * time.Second,
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		// 2560 data chunks, 20 intermediate chunks and the root chunk
+		jsonhttptest.Request(t, ts, http.MethodGet, "/stamps/estimate?size=10485760&ttl=9", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(&api.PostageEstimateResponse{
+				Chunks:       2581,
+				Depth:        17,
+				Amount:       bigint.Wrap(big.NewInt(10)),      // ceil(ttl/blockTime)*price=5*2.
+				TotalAmount:  bigint.Wrap(big.NewInt(1310720)), // amount*2^depth.
+				CurrentPrice: bigint.Wrap(big.NewInt(2)),
+				BatchTTL:     10,
+			}),
+		)
+	})
+
+	t.Run("depth above minimum", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, ts, http.MethodGet, "/stamps/estimate?size=1073741824&ttl=2", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(&api.PostageEstimateResponse{
+				Chunks:       264209,
+				Depth:        19,
+				Amount:       bigint.Wrap(big.NewInt(2)),
+				TotalAmount:  bigint.Wrap(big.NewInt(1048576)),
+				CurrentPrice: bigint.Wrap(big.NewInt(2)),
+				BatchTTL:     2,
+			}),
+		)
+	})
+
+	t.Run("invalid ttl", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, ts, http.MethodGet, "/stamps/estimate?size=1024&ttl=-1", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusBadRequest,
+				Message: "invalid query params",
+				Reasons: []jsonhttp.Reason{
+					{
+						Field: "ttl",
+						Error: "want min:1",
+					},
+				},
+			}),
+		)
+	})
+
+	t.Run("price unavailable", func(t *testing.T) {
+		t.Parallel()
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI:   true,
+			BatchStore: mock.New(mock.WithChainState(&postage.ChainState{CurrentPrice: big.NewInt(0)})),
+			BlockTime:  2 * time.Second,
+		})
+		jsonhttptest.Request(t, ts, http.MethodGet, "/stamps/estimate?size=1024&ttl=10", http.StatusServiceUnavailable,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusServiceUnavailable,
+				Message: "price unavailable",
+			}),
+		)
+	})
+}
+
 func TestPostageTopUpStamp(t *testing.T) {
 	t.Parallel()
 
@@ -826,6 +1031,75 @@ func TestPostageDiluteStamp(t *testing.T) {
 	})
 }
 
+func TestPostageRestamp(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storer := smock.NewStorer()
+	reference, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, storer, storage.ModePutUpload, false), bytes.NewReader(make([]byte, 2*swarm.ChunkSize+1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the zero-filled data chunks repeat, but they are restamped once
+	var addrs []swarm.Address
+	if err := traversal.New(storer).Traverse(ctx, reference, func(addr swarm.Address) error {
+		if !swarm.ContainsAddress(addrs, addr) {
+			addrs = append(addrs, addr)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	si := postage.NewStampIssuer("", "", batchOk, big.NewInt(3), 11, 10, 1000, true)
+	ts, _, _, chanStorer := newTestServer(t, testServerOptions{
+		Storer:       storer,
+		Post:         mockpost.New(mockpost.WithIssuer(si)),
+		DebugAPI:     true,
+		DirectUpload: true,
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, ts, http.MethodPost, "/stamps/"+batchOkStr+"/restamp/"+reference.String(), http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(&api.PostageRestampResponse{
+				BatchID:   batchOk,
+				Reference: reference,
+				Chunks:    len(addrs),
+			}),
+		)
+		for _, addr := range addrs {
+			if has, _ := chanStorer.Has(ctx, addr); !has {
+				t.Fatalf("chunk %s was not pushed", addr)
+			}
+		}
+	})
+
+	t.Run("not found locally", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, ts, http.MethodPost, "/stamps/"+batchOkStr+"/restamp/"+swarm.RandAddress(t).String(), http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(&jsonhttp.StatusResponse{
+				Code:    http.StatusNotFound,
+				Message: "content not found locally",
+			}),
+		)
+	})
+
+	t.Run("batch not found", func(t *testing.T) {
+		t.Parallel()
+
+		tsNotFound, _, _, _ := newTestServer(t, testServerOptions{
+			Storer:   storer,
+			Post:     mockpost.New(),
+			DebugAPI: true,
+		})
+
+		jsonhttptest.Request(t, tsNotFound, http.MethodPost, "/stamps/"+batchOkStr+"/restamp/"+reference.String(), http.StatusNotFound)
+	})
+}
+
 // Tests the postageAccessHandler middleware for any set of operations that are guarded
 // by the postage semaphore
 func TestPostageAccessHandler(t *testing.T) {
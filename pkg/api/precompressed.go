@@ -0,0 +1,120 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// precompressedEncodings are the content codings of the precompressed
+// variants by the file name extension, in the order of preference.
+var precompressedEncodings = []struct {
+	ext    string
+	coding string
+}{
+	{ext: ".br", coding: "br"},
+	{ext: ".gz", coding: "gzip"},
+}
+
+// linkPrecompressedVariants adds the references of the precompressed
+// variants, such as index.html.br and index.html.gz, to the metadata
+// of the original entries, such as index.html, of the same directory.
+func linkPrecompressedVariants(entries map[string]manifest.Entry) {
+	for path, entry := range entries {
+		for _, e := range precompressedEncodings {
+			if !strings.HasSuffix(path, e.ext) {
+				continue
+			}
+			original, ok := entries[strings.TrimSuffix(path, e.ext)]
+			if !ok {
+				continue
+			}
+			original.Metadata()[manifest.EntryMetadataEncodingKeyPrefix+e.coding] = entry.Reference().String()
+		}
+	}
+}
+
+// isPrecompressedVariant reports whether the path is of a precompressed
+// variant by its file name extension.
+func isPrecompressedVariant(path string) bool {
+	for _, e := range precompressedEncodings {
+		if strings.HasSuffix(path, e.ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// precompressedVariant returns the reference and the content coding of the
+// precompressed variant of the entry which is the most preferred by the
+// Accept-Encoding header of the request. The ok result is false if the
+// original content is to be served.
+func precompressedVariant(r *http.Request, metadata map[string]string) (reference swarm.Address, coding string, ok bool) {
+	accepted := parseAcceptEncoding(r.Header.Get("Accept-Encoding"))
+
+	var q float64
+	for _, e := range precompressedEncodings {
+		v, has := metadata[manifest.EntryMetadataEncodingKeyPrefix+e.coding]
+		if !has {
+			continue
+		}
+		eq, has := accepted[e.coding]
+		if !has {
+			eq = accepted["*"]
+		}
+		if eq <= q {
+			continue
+		}
+		ref, err := swarm.ParseHexAddress(v)
+		if err != nil {
+			continue
+		}
+		reference, coding, q = ref, e.coding, eq
+	}
+	return reference, coding, q > 0
+}
+
+// hasPrecompressedVariants reports whether the entry links any
+// precompressed variant of its content.
+func hasPrecompressedVariants(metadata map[string]string) bool {
+	for _, e := range precompressedEncodings {
+		if _, ok := metadata[manifest.EntryMetadataEncodingKeyPrefix+e.coding]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAcceptEncoding returns the quality values of the
+// content codings listed in the Accept-Encoding header.
+func parseAcceptEncoding(header string) map[string]float64 {
+	accepted := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(param, "=")
+			if strings.TrimSpace(name) != "q" {
+				continue
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				v = 0
+			}
+			q = v
+		}
+		accepted[coding] = q
+	}
+	return accepted
+}
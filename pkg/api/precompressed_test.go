@@ -0,0 +1,101 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestBzzPrecompressedVariants(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: smock.NewStorer(),
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	var (
+		html   = []byte("<html>original</html>")
+		htmlBr = []byte("brotli compressed html")
+		htmlGz = []byte("gzip compressed html")
+		js     = []byte("console.log('original')")
+		jsGz   = []byte("gzip compressed js")
+	)
+
+	// the variants precede the originals, so that
+	// the linking does not depend on the order
+	tr := tarFiles(t, []f{
+		{data: htmlBr, name: "index.html.br", header: http.Header{"Content-Type": {"application/octet-stream"}}},
+		{data: html, name: "index.html", header: http.Header{"Content-Type": {"text/html; charset=utf-8"}}},
+		{data: htmlGz, name: "index.html.gz", header: http.Header{"Content-Type": {"application/gzip"}}},
+		{data: jsGz, name: "app.js.gz", dir: "js", header: http.Header{"Content-Type": {"application/gzip"}}},
+		{data: js, name: "app.js", dir: "js", header: http.Header{"Content-Type": {"text/javascript"}}},
+		{data: []byte("plain"), name: "plain.txt", header: http.Header{"Content-Type": {"text/plain"}}},
+	})
+
+	var resp api.BzzUploadResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+		jsonhttptest.WithRequestBody(tr),
+		jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+		jsonhttptest.WithUnmarshalJSONResponse(&resp),
+	)
+	root := "/bzz/" + resp.Reference.String() + "/"
+
+	for _, tc := range []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		want           []byte
+		wantEncoding   string
+		wantVary       bool
+	}{
+		{name: "brotli preferred", path: "index.html", acceptEncoding: "gzip, deflate, br", want: htmlBr, wantEncoding: "br", wantVary: true},
+		{name: "gzip by quality", path: "index.html", acceptEncoding: "br;q=0.5, gzip", want: htmlGz, wantEncoding: "gzip", wantVary: true},
+		{name: "gzip only", path: "index.html", acceptEncoding: "gzip", want: htmlGz, wantEncoding: "gzip", wantVary: true},
+		{name: "wildcard", path: "index.html", acceptEncoding: "*", want: htmlBr, wantEncoding: "br", wantVary: true},
+		{name: "rejected", path: "index.html", acceptEncoding: "br;q=0, gzip;q=0", want: html, wantVary: true},
+		{name: "identity", path: "index.html", acceptEncoding: "identity", want: html, wantVary: true},
+		{name: "missing variant", path: "js/app.js", acceptEncoding: "br", want: js, wantVary: true},
+		{name: "single variant", path: "js/app.js", acceptEncoding: "br, gzip", want: jsGz, wantEncoding: "gzip", wantVary: true},
+		{name: "variant directly", path: "index.html.br", acceptEncoding: "br", want: htmlBr},
+		{name: "no variants", path: "plain.txt", acceptEncoding: "br, gzip", want: []byte("plain")},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := jsonhttptest.Request(t, client, http.MethodGet, root+tc.path, http.StatusOK,
+				jsonhttptest.WithRequestHeader("Accept-Encoding", tc.acceptEncoding),
+				jsonhttptest.WithExpectedResponse(tc.want),
+			)
+			if got := header.Get("Content-Encoding"); got != tc.wantEncoding {
+				t.Errorf("got content encoding %q, want %q", got, tc.wantEncoding)
+			}
+			if got := header.Get("Vary") == "Accept-Encoding"; got != tc.wantVary {
+				t.Errorf("got vary header %q, want vary %v", header.Get("Vary"), tc.wantVary)
+			}
+		})
+	}
+
+	// the content type of the original is kept for the variant
+	jsonhttptest.Request(t, client, http.MethodGet, root+"index.html", http.StatusOK,
+		jsonhttptest.WithRequestHeader("Accept-Encoding", "br"),
+		jsonhttptest.WithExpectedResponseHeader("Content-Type", "text/html; charset=utf-8"),
+	)
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/pss"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -71,12 +72,38 @@ func (s *Service) pssPostHandler(w http.ResponseWriter, r *http.Request) {
 		jsonhttp.InternalServerError(w, "pss send failed")
 		return
 	}
+	stamper, save, ok := s.pssStamper(logger, w, r)
+	if !ok {
+		return
+	}
+	defer save()
+
+	err = s.pss.Send(r.Context(), topic, payload, stamper, queries.Recipient, targets)
+	if err != nil {
+		logger.Debug("send payload failed", "topic", paths.Topic, "error", err)
+		logger.Error(nil, "send payload failed")
+		switch {
+		case errors.Is(err, postage.ErrBucketFull):
+			jsonhttp.PaymentRequired(w, "batch is overissued")
+		default:
+			jsonhttp.InternalServerError(w, "pss send failed")
+		}
+		return
+	}
+
+	jsonhttp.Created(w, nil)
+}
+
+// pssStamper returns the stamper of the postage batch of the request and the
+// function which saves the stamp issuer. The error response is written if
+// the ok result is false.
+func (s *Service) pssStamper(logger log.Logger, w http.ResponseWriter, r *http.Request) (postage.Stamper, func(), bool) {
 	batch, err := requestPostageBatchId(r)
 	if err != nil {
 		logger.Debug("decode postage batch id failed", "error", err)
 		logger.Error(nil, "decode postage batch id failed")
 		jsonhttp.BadRequest(w, "invalid postage batch id")
-		return
+		return nil, nil, false
 	}
 	i, save, err := s.post.GetStampIssuer(batch)
 	if err != nil {
@@ -90,30 +117,13 @@ func (s *Service) pssPostHandler(w http.ResponseWriter, r *http.Request) {
 		default:
 			jsonhttp.BadRequest(w, "postage stamp issuer")
 		}
-		return
+		return nil, nil, false
 	}
-	defer func() {
+	return s.newStamper(i), func() {
 		if err := save(); err != nil {
 			s.logger.Debug("stamp issuer save", "error", err)
 		}
-	}()
-
-	stamper := postage.NewStamper(i, s.signer)
-
-	err = s.pss.Send(r.Context(), topic, payload, stamper, queries.Recipient, targets)
-	if err != nil {
-		logger.Debug("send payload failed", "topic", paths.Topic, "error", err)
-		logger.Error(nil, "send payload failed")
-		switch {
-		case errors.Is(err, postage.ErrBucketFull):
-			jsonhttp.PaymentRequired(w, "batch is overissued")
-		default:
-			jsonhttp.InternalServerError(w, "pss send failed")
-		}
-		return
-	}
-
-	jsonhttp.Created(w, nil)
+	}, true
 }
 
 func (s *Service) pssWsHandler(w http.ResponseWriter, r *http.Request) {
@@ -141,17 +151,21 @@ func (s *Service) pssWsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	topic := pss.NewTopic(paths.Topic)
 	s.wsWg.Add(1)
-	go s.pumpWs(conn, paths.Topic)
+	go s.pumpWs(conn, func(h func(context.Context, []byte)) func() {
+		return s.pss.Register(topic, h)
+	})
 }
 
-func (s *Service) pumpWs(conn *websocket.Conn, t string) {
+// pumpWs writes the messages received by the handler registered
+// with the subscribe function to the websocket connection.
+func (s *Service) pumpWs(conn *websocket.Conn, subscribe func(func(context.Context, []byte)) func()) {
 	defer s.wsWg.Done()
 
 	var (
 		dataC  = make(chan []byte)
 		gone   = make(chan struct{})
-		topic  = pss.NewTopic(t)
 		ticker = time.NewTicker(s.WsPingPeriod)
 		err    error
 	)
@@ -159,7 +173,7 @@ func (s *Service) pumpWs(conn *websocket.Conn, t string) {
 		ticker.Stop()
 		_ = conn.Close()
 	}()
-	cleanup := s.pss.Register(topic, func(ctx context.Context, m []byte) {
+	cleanup := subscribe(func(ctx context.Context, m []byte) {
 		select {
 		case dataC <- m:
 		case <-ctx.Done():
@@ -0,0 +1,309 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/ethersphere/bee/pkg/pss/session"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+type pssSessionResponse struct {
+	ID        string    `json:"id"`
+	Peer      string    `json:"peer"`
+	Initiator bool      `json:"initiator"`
+	State     string    `json:"state"`
+	Created   time.Time `json:"created"`
+}
+
+type pssSessionsResponse struct {
+	Sessions []pssSessionResponse `json:"sessions"`
+}
+
+func newPssSessionResponse(info session.Info) pssSessionResponse {
+	return pssSessionResponse{
+		ID:        info.ID.String(),
+		Peer:      hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(info.Peer)),
+		Initiator: info.Initiator,
+		State:     info.State.String(),
+		Created:   info.Created,
+	}
+}
+
+// pssSessionPostHandler initiates a session with the recipient, which
+// is established once the recipient accepts it.
+func (s *Service) pssSessionPostHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_pss_session").Build()
+
+	if s.pssSessions == nil {
+		jsonhttp.NotImplemented(w, "pss sessions are not available")
+		return
+	}
+
+	queries := struct {
+		Recipient *ecdsa.PublicKey `map:"recipient" validate:"required"`
+		Targets   string           `map:"targets" validate:"required"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	var targets pss.Targets
+	for _, v := range strings.Split(queries.Targets, ",") {
+		target := struct {
+			Val []byte `map:"target" validate:"required,max=3"`
+		}{}
+		if response := s.mapStructure(map[string]string{"target": v}, &target); response != nil {
+			response("invalid query params", logger, w)
+			return
+		}
+		targets = append(targets, target.Val)
+	}
+
+	stamper, save, ok := s.pssStamper(logger, w, r)
+	if !ok {
+		return
+	}
+	defer save()
+
+	info, err := s.pssSessions.Initiate(r.Context(), stamper, queries.Recipient, targets)
+	if err != nil {
+		logger.Debug("initiate session failed", "error", err)
+		logger.Error(nil, "initiate session failed")
+		writePssSessionError(w, err, "initiate session failed")
+		return
+	}
+
+	jsonhttp.Created(w, newPssSessionResponse(info))
+}
+
+// pssSessionsGetHandler returns all sessions of the node.
+func (s *Service) pssSessionsGetHandler(w http.ResponseWriter, _ *http.Request) {
+	if s.pssSessions == nil {
+		jsonhttp.NotImplemented(w, "pss sessions are not available")
+		return
+	}
+
+	infos := s.pssSessions.Sessions()
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Created.Before(infos[j].Created)
+	})
+
+	res := pssSessionsResponse{Sessions: make([]pssSessionResponse, 0, len(infos))}
+	for _, info := range infos {
+		res.Sessions = append(res.Sessions, newPssSessionResponse(info))
+	}
+	jsonhttp.OK(w, res)
+}
+
+// pssSessionGetHandler returns the session.
+func (s *Service) pssSessionGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_pss_session").Build()
+
+	id, ok := s.pssSessionID(logger, w, r)
+	if !ok {
+		return
+	}
+
+	info, err := s.pssSessions.Session(id)
+	if err != nil {
+		logger.Debug("get session failed", "session_id", id, "error", err)
+		logger.Error(nil, "get session failed")
+		writePssSessionError(w, err, "get session failed")
+		return
+	}
+
+	jsonhttp.OK(w, newPssSessionResponse(info))
+}
+
+// pssSessionDeleteHandler discards the session with its keys.
+func (s *Service) pssSessionDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("delete_pss_session").Build()
+
+	id, ok := s.pssSessionID(logger, w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.pssSessions.Remove(id); err != nil {
+		logger.Debug("remove session failed", "session_id", id, "error", err)
+		logger.Error(nil, "remove session failed")
+		writePssSessionError(w, err, "remove session failed")
+		return
+	}
+
+	jsonhttp.OK(w, nil)
+}
+
+// pssSessionAcceptHandler accepts the session requested by the peer.
+func (s *Service) pssSessionAcceptHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_pss_session_accept").Build()
+
+	id, ok := s.pssSessionID(logger, w, r)
+	if !ok {
+		return
+	}
+
+	stamper, save, ok := s.pssStamper(logger, w, r)
+	if !ok {
+		return
+	}
+	defer save()
+
+	info, err := s.pssSessions.Accept(r.Context(), stamper, id)
+	if err != nil {
+		logger.Debug("accept session failed", "session_id", id, "error", err)
+		logger.Error(nil, "accept session failed")
+		writePssSessionError(w, err, "accept session failed")
+		return
+	}
+
+	jsonhttp.OK(w, newPssSessionResponse(info))
+}
+
+// pssSessionMessagePostHandler sends the request body as
+// an encrypted message of the established session.
+func (s *Service) pssSessionMessagePostHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_pss_session_message").Build()
+
+	id, ok := s.pssSessionID(logger, w, r)
+	if !ok {
+		return
+	}
+
+	message, err := io.ReadAll(r.Body)
+	if err != nil {
+		if jsonhttp.HandleBodyReadError(err, w) {
+			return
+		}
+		logger.Debug("read body failed", "error", err)
+		logger.Error(nil, "read body failed")
+		jsonhttp.InternalServerError(w, "send message failed")
+		return
+	}
+
+	stamper, save, ok := s.pssStamper(logger, w, r)
+	if !ok {
+		return
+	}
+	defer save()
+
+	if err := s.pssSessions.Send(r.Context(), stamper, id, message); err != nil {
+		logger.Debug("send message failed", "session_id", id, "error", err)
+		logger.Error(nil, "send message failed")
+		writePssSessionError(w, err, "send message failed")
+		return
+	}
+
+	jsonhttp.Created(w, nil)
+}
+
+// pssSessionWsHandler writes the decrypted messages
+// of the session to the websocket connection.
+func (s *Service) pssSessionWsHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("pss_session_subscribe").Build()
+
+	id, ok := s.pssSessionID(logger, w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := s.pssSessions.Session(id); err != nil {
+		logger.Debug("get session failed", "session_id", id, "error", err)
+		logger.Error(nil, "get session failed")
+		writePssSessionError(w, err, "get session failed")
+		return
+	}
+
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  swarm.ChunkSize,
+		WriteBufferSize: swarm.ChunkSize,
+		CheckOrigin:     s.checkOrigin,
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Debug("upgrade failed", "error", err)
+		logger.Error(nil, "upgrade failed")
+		jsonhttp.InternalServerError(w, "upgrade failed")
+		return
+	}
+
+	s.wsWg.Add(1)
+	go s.pumpWs(conn, func(h func(context.Context, []byte)) func() {
+		cleanup, err := s.pssSessions.Subscribe(id, h)
+		if err != nil {
+			// the session was removed in the meantime
+			return func() {}
+		}
+		return cleanup
+	})
+}
+
+// pssSessionID returns the session id of the request path. The error
+// response is written if the ok result is false.
+func (s *Service) pssSessionID(logger log.Logger, w http.ResponseWriter, r *http.Request) (session.ID, bool) {
+	if s.pssSessions == nil {
+		jsonhttp.NotImplemented(w, "pss sessions are not available")
+		return session.ID{}, false
+	}
+
+	paths := struct {
+		ID string `map:"id" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return session.ID{}, false
+	}
+	id, err := session.ParseID(paths.ID)
+	if err != nil {
+		logger.Debug("invalid session id", "id", paths.ID, "error", err)
+		logger.Error(nil, "invalid session id")
+		jsonhttp.BadRequest(w, jsonhttp.StatusResponse{
+			Message: "invalid path params",
+			Code:    http.StatusBadRequest,
+			Reasons: []jsonhttp.Reason{{
+				Field: "id",
+				Error: err.Error(),
+			}},
+		})
+		return session.ID{}, false
+	}
+	return id, true
+}
+
+func writePssSessionError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, session.ErrNotFound):
+		jsonhttp.NotFound(w, "session not found")
+	case errors.Is(err, session.ErrInvalidState):
+		jsonhttp.Conflict(w, "invalid session state")
+	case errors.Is(err, session.ErrMessageTooLarge):
+		jsonhttp.RequestEntityTooLarge(w, "message too large")
+	case errors.Is(err, pss.ErrEmptyTargets), errors.Is(err, pss.ErrVarLenTargets):
+		jsonhttp.BadRequest(w, "invalid targets")
+	case errors.Is(err, postage.ErrBucketFull):
+		jsonhttp.PaymentRequired(w, "batch is overissued")
+	default:
+		jsonhttp.InternalServerError(w, msg)
+	}
+}
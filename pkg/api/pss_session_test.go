@@ -0,0 +1,236 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/ethersphere/bee/pkg/pss/session"
+	"github.com/ethersphere/bee/pkg/pushsync"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/websocket"
+)
+
+// sessionPss is the pss of a node which queues the sent
+// messages until they are delivered to the peer node.
+type sessionPss struct {
+	mu      sync.Mutex
+	peer    *sessionPss
+	handler pss.Handler
+	queue   [][]byte
+}
+
+func (p *sessionPss) Send(_ context.Context, _ pss.Topic, payload []byte, _ postage.Stamper, _ *ecdsa.PublicKey, _ pss.Targets) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queue = append(p.queue, append([]byte(nil), payload...))
+	return nil
+}
+
+func (p *sessionPss) Register(_ pss.Topic, h pss.Handler) func() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handler = h
+	return func() {}
+}
+
+// flush delivers the queued messages to the peer node.
+func (p *sessionPss) flush() {
+	p.mu.Lock()
+	queue := p.queue
+	p.queue = nil
+	p.mu.Unlock()
+
+	p.peer.mu.Lock()
+	h := p.peer.handler
+	p.peer.mu.Unlock()
+	for _, m := range queue {
+		h(context.Background(), m)
+	}
+}
+
+func (p *sessionPss) TryUnwrap(swarm.Chunk)             {}
+func (p *sessionPss) SetPushSyncer(pushsync.PushSyncer) {}
+func (p *sessionPss) Close() error                      { return nil }
+
+func TestPssSession(t *testing.T) {
+	t.Parallel()
+
+	newKey := func() *ecdsa.PrivateKey {
+		t.Helper()
+		key, err := crypto.GenerateSecp256k1Key()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+
+	var (
+		nodeKey, peerKey = newKey(), newKey()
+		nodePss, peerPss = new(sessionPss), new(sessionPss)
+		node             = session.New(nodeKey, nodePss, swarm.RandAddress(t), log.Noop)
+		peer             = session.New(peerKey, peerPss, swarm.RandAddress(t), log.Noop)
+		stamper          = mockpost.NewStamper()
+		recipient        = hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(&peerKey.PublicKey))
+	)
+	nodePss.peer, peerPss.peer = peerPss, nodePss
+	t.Cleanup(func() {
+		_ = node.Close()
+		_ = peer.Close()
+	})
+
+	client, _, listener, _ := newTestServer(t, testServerOptions{
+		Post:        mockpost.New(mockpost.WithAcceptAll()),
+		Logger:      log.Noop,
+		PssSessions: node,
+	})
+
+	var initiated api.PssSessionResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/pss/sessions?recipient="+recipient+"&targets=01", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithUnmarshalJSONResponse(&initiated),
+	)
+	if initiated.Peer != recipient || !initiated.Initiator || initiated.State != session.StateInitiated.String() {
+		t.Fatalf("got initiated session %+v", initiated)
+	}
+	resource := "/pss/sessions/" + initiated.ID
+
+	jsonhttptest.Request(t, client, http.MethodPost, resource+"/accept", http.StatusConflict,
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "invalid session state",
+			Code:    http.StatusConflict,
+		}),
+	)
+
+	id, err := session.ParseID(initiated.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodePss.flush()
+	if _, err := peer.Accept(context.Background(), stamper, id); err != nil {
+		t.Fatal(err)
+	}
+	peerPss.flush()
+
+	established := initiated
+	established.State = session.StateEstablished.String()
+	jsonhttptest.Request(t, client, http.MethodGet, resource, http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(established),
+	)
+	jsonhttptest.Request(t, client, http.MethodGet, "/pss/sessions", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.PssSessionsResponse{
+			Sessions: []api.PssSessionResponse{established},
+		}),
+	)
+
+	t.Run("messages", func(t *testing.T) {
+		u := url.URL{Scheme: "ws", Host: listener, Path: resource + "/subscribe"}
+		conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = conn.Close() })
+
+		// the subscription of the websocket is registered asynchronously
+		for i := 0; ; i++ {
+			if err := peer.Send(context.Background(), stamper, id, []byte("ping")); err != nil {
+				t.Fatal(err)
+			}
+			peerPss.flush()
+
+			err := conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, msg, err := conn.ReadMessage()
+			if err == nil {
+				if !bytes.Equal(msg, []byte("ping")) {
+					t.Fatalf("got message %q, want %q", msg, "ping")
+				}
+				break
+			}
+			if i == 20 {
+				t.Fatal(err)
+			}
+			// a timed out websocket connection is not readable anymore
+			_ = conn.Close()
+			if conn, _, err = websocket.DefaultDialer.Dial(u.String(), nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		received := make(chan []byte, 1)
+		cleanup, err := peer.Subscribe(id, func(_ context.Context, m []byte) { received <- m })
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cleanup()
+
+		jsonhttptest.Request(t, client, http.MethodPost, resource+"/messages", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader([]byte("pong"))),
+		)
+		nodePss.flush()
+		select {
+		case m := <-received:
+			if !bytes.Equal(m, []byte("pong")) {
+				t.Fatalf("got message %q, want %q", m, "pong")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("message not received")
+		}
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, "/pss/sessions/0102", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid path params",
+				Code:    http.StatusBadRequest,
+				Reasons: []jsonhttp.Reason{{
+					Field: "id",
+					Error: "invalid session id length",
+				}},
+			}),
+		)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodDelete, resource, http.StatusOK)
+		jsonhttptest.Request(t, client, http.MethodGet, resource, http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "session not found",
+				Code:    http.StatusNotFound,
+			}),
+		)
+		jsonhttptest.Request(t, client, http.MethodPost, resource+"/messages", http.StatusNotFound,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader([]byte("gone"))),
+		)
+	})
+
+	t.Run("not available", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{})
+		jsonhttptest.Request(t, client, http.MethodGet, "/pss/sessions", http.StatusNotImplemented)
+	})
+}
@@ -375,7 +375,7 @@ func newPssTest(t *testing.T, o opts) (pss.Interface, *ecdsa.PublicKey, *websock
 		t.Fatal(err)
 	}
 
-	pss := pss.New(privkey, log.Noop)
+	pss := pss.New(privkey, log.Noop, pss.Options{})
 	testutil.CleanupCloser(t, pss)
 
 	if o.pingPeriod == 0 {
@@ -0,0 +1,176 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// maxRanges is the largest number of the ranges of a request which are
+// served as the multipart response by serveRanges, the requests with more
+// ranges are served by the http.ServeContent.
+const maxRanges = 64
+
+var errInvalidRange = errors.New("invalid range")
+
+// byteRange is the range of the content of a multi-range request.
+type byteRange struct {
+	start, length int64
+}
+
+func (r byteRange) mimeHeader(contentType string, size int64) textproto.MIMEHeader {
+	h := textproto.MIMEHeader{
+		"Content-Range": {fmt.Sprintf("bytes %d-%d/%d", r.start, r.start+r.length-1, size)},
+	}
+	if contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+	return h
+}
+
+// multiRanges returns the ranges of the multi-range request for the content
+// of the given size and etag, ok is false if the request is not served by
+// serveRanges. The single-range, the conditional and the invalid requests are
+// left to the http.ServeContent, which also serves the whole content if the
+// ranges overlap so much that they are larger than the content.
+func multiRanges(r *http.Request, etag string, size int64) (ranges []byteRange, ok bool) {
+	if r.Method != http.MethodGet {
+		return nil, false
+	}
+	if r.Header.Get("If-Match") != "" || r.Header.Get("If-None-Match") != "" {
+		return nil, false
+	}
+	if ir := r.Header.Get("If-Range"); ir != "" && ir != etag {
+		return nil, false
+	}
+
+	ranges, err := parseRanges(r.Header.Get("Range"), size)
+	if err != nil || len(ranges) < 2 || len(ranges) > maxRanges {
+		return nil, false
+	}
+	var sum int64
+	for _, ra := range ranges {
+		sum += ra.length
+	}
+	if sum > size {
+		return nil, false
+	}
+	return ranges, true
+}
+
+// parseRanges parses the Range header of the content of the given size.
+// The ranges which start after the end of the content are ignored.
+func parseRanges(s string, size int64) ([]byteRange, error) {
+	const prefix = "bytes="
+	if !strings.HasPrefix(s, prefix) {
+		return nil, errInvalidRange
+	}
+
+	var ranges []byteRange
+	for _, ra := range strings.Split(s[len(prefix):], ",") {
+		ra = textproto.TrimString(ra)
+		if ra == "" {
+			continue
+		}
+		i := strings.Index(ra, "-")
+		if i < 0 {
+			return nil, errInvalidRange
+		}
+		start, end := textproto.TrimString(ra[:i]), textproto.TrimString(ra[i+1:])
+
+		var r byteRange
+		if start == "" {
+			// suffix range, such as -500, of the last bytes
+			n, err := strconv.ParseInt(end, 10, 64)
+			if err != nil || n < 0 {
+				return nil, errInvalidRange
+			}
+			if n > size {
+				n = size
+			}
+			r.start, r.length = size-n, n
+		} else {
+			i, err := strconv.ParseInt(start, 10, 64)
+			if err != nil || i < 0 {
+				return nil, errInvalidRange
+			}
+			if i >= size {
+				continue
+			}
+			r.start = i
+			if end == "" {
+				r.length = size - i
+			} else {
+				j, err := strconv.ParseInt(end, 10, 64)
+				if err != nil || j < i {
+					return nil, errInvalidRange
+				}
+				if j >= size {
+					j = size - 1
+				}
+				r.length = j - i + 1
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// serveRanges writes the ranges of the content as the multipart/byteranges
+// response. The ranges are read from the reader at their offsets, so that
+// only the chunks which cover the ranges are retrieved.
+func serveRanges(w http.ResponseWriter, reader io.ReaderAt, ranges []byteRange, size int64) error {
+	contentType := w.Header().Get("Content-Type")
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+
+	w.Header().Set("Content-Type", "multipart/byteranges; boundary="+boundary)
+	w.Header().Set("Accept-Ranges", "bytes")
+	if w.Header().Get("Content-Length") != "" {
+		w.Header().Set("Content-Length", strconv.FormatInt(rangesMIMESize(ranges, contentType, boundary, size), 10))
+	}
+	w.WriteHeader(http.StatusPartialContent)
+
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return err
+	}
+	for _, ra := range ranges {
+		part, err := mw.CreatePart(ra.mimeHeader(contentType, size))
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, io.NewSectionReader(reader, ra.start, ra.length)); err != nil {
+			return fmt.Errorf("range %d-%d: %w", ra.start, ra.start+ra.length-1, err)
+		}
+	}
+	return mw.Close()
+}
+
+// rangesMIMESize returns the size of the multipart response of the ranges.
+func rangesMIMESize(ranges []byteRange, contentType, boundary string, size int64) int64 {
+	var w countingWriter
+	mw := multipart.NewWriter(&w)
+	_ = mw.SetBoundary(boundary)
+	for _, ra := range ranges {
+		_, _ = mw.CreatePart(ra.mimeHeader(contentType, size))
+		w += countingWriter(ra.length)
+	}
+	_ = mw.Close()
+	return int64(w)
+}
+
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/manifest"
+)
+
+var errInvalidWebsiteRedirects = errors.New("invalid website redirects")
+
+// websiteRedirect is a rule of the website redirects of the collection. The
+// From path matches the request path, or its prefix if it ends with the *
+// wildcard, which is then substituted for the * of the To path. The rules
+// with the status 200 rewrite the request to the To path of the collection,
+// the other ones redirect to it or to the absolute URL.
+type websiteRedirect struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Status int    `json:"status,omitempty"`
+}
+
+// parseWebsiteRedirects parses and validates the JSON list of the rules
+// and returns them with the default status set, in the compact form.
+func parseWebsiteRedirects(s string) ([]websiteRedirect, string, error) {
+	var rules []websiteRedirect
+	if err := json.Unmarshal([]byte(s), &rules); err != nil {
+		return nil, "", fmt.Errorf("%w: %v", errInvalidWebsiteRedirects, err)
+	}
+	for i, rule := range rules {
+		if rule.From == "" || rule.To == "" {
+			return nil, "", fmt.Errorf("%w: rule %d: from and to paths are required", errInvalidWebsiteRedirects, i)
+		}
+		if strings.Contains(strings.TrimSuffix(rule.From, "*"), "*") || strings.Count(rule.To, "*") > 1 {
+			return nil, "", fmt.Errorf("%w: rule %d: misplaced wildcard", errInvalidWebsiteRedirects, i)
+		}
+		switch rule.Status {
+		case 0:
+			rules[i].Status = http.StatusMovedPermanently
+		case http.StatusOK:
+			if isAbsoluteURL(rule.To) {
+				return nil, "", fmt.Errorf("%w: rule %d: rewrite to url", errInvalidWebsiteRedirects, i)
+			}
+		case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+			http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		default:
+			return nil, "", fmt.Errorf("%w: rule %d: unsupported status %d", errInvalidWebsiteRedirects, i, rule.Status)
+		}
+	}
+	b, err := json.Marshal(rules)
+	if err != nil {
+		return nil, "", err
+	}
+	return rules, string(b), nil
+}
+
+func isAbsoluteURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// matchWebsiteRedirect returns the target and the status of the first rule
+// which matches the path of the collection.
+func matchWebsiteRedirect(rules []websiteRedirect, p string) (string, int, bool) {
+	p = "/" + p
+	for _, rule := range rules {
+		from := "/" + strings.TrimPrefix(rule.From, "/")
+		if prefix, ok := strings.CutSuffix(from, "*"); ok {
+			if rest, ok := strings.CutPrefix(p, prefix); ok {
+				return strings.Replace(rule.To, "*", rest, 1), rule.Status, true
+			}
+			continue
+		}
+		if p == from {
+			return rule.To, rule.Status, true
+		}
+	}
+	return "", 0, false
+}
+
+// serveWebsiteRedirect evaluates the website redirects of the collection for
+// the path which is not found in the manifest. It redirects or serves the
+// rewritten path and returns true if a rule matches and its target exists.
+func (s *Service) serveWebsiteRedirect(ctx context.Context, logger log.Logger, w http.ResponseWriter, r *http.Request, m manifest.Interface, pathVar string, lastModified bool) bool {
+	redirects, ok := manifestMetadataLoad(ctx, m, manifest.RootPath, manifest.WebsiteRedirectsKey)
+	if !ok {
+		return false
+	}
+	rules, _, err := parseWebsiteRedirects(redirects)
+	if err != nil {
+		logger.Debug("bzz download: invalid website redirects", "error", err)
+		return false
+	}
+	target, status, ok := matchWebsiteRedirect(rules, pathVar)
+	if !ok {
+		return false
+	}
+
+	if status != http.StatusOK {
+		if !isAbsoluteURL(target) {
+			// the path of the root of the collection in the request url,
+			// which can be served on the subdomain
+			base := "/"
+			if strings.HasSuffix(r.URL.Path, pathVar) {
+				base = r.URL.Path[:len(r.URL.Path)-len(pathVar)]
+			}
+			target = base + strings.TrimPrefix(target, "/")
+		}
+		logger.Debug("bzz download: website redirect", "path", pathVar, "target", target, "status", status)
+		http.Redirect(w, r, target, status)
+		return true
+	}
+
+	target = strings.TrimPrefix(target, "/")
+	if target == "" || strings.HasSuffix(target, "/") {
+		if index, ok := manifestMetadataLoad(ctx, m, manifest.RootPath, manifest.WebsiteIndexDocumentSuffixKey); ok {
+			target = path.Join(target, index)
+		}
+	}
+	e, err := m.Lookup(ctx, target)
+	if err != nil {
+		logger.Debug("bzz download: website rewrite target not found", "path", pathVar, "target", target, "error", err)
+		return false
+	}
+	logger.Debug("bzz download: serving path", "path", target)
+	s.serveManifestEntry(logger, w, r, e, lastModified)
+	return true
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestWebsiteRedirects(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:          mock.NewStorer(),
+		Tags:            tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger:          log.Noop,
+		PreventRedirect: true,
+		Post:            mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	var (
+		index    = []byte("<h1>index</h1>")
+		notFound = []byte("<h1>not found</h1>")
+		post     = []byte("<h1>post</h1>")
+		html     = http.Header{"Content-Type": {"text/html; charset=utf-8"}}
+	)
+	upload := func(t *testing.T, redirects string) string {
+		t.Helper()
+
+		var resp api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmIndexDocumentHeader, "index.html"),
+			jsonhttptest.WithRequestHeader(api.SwarmErrorDocumentHeader, "404.html"),
+			jsonhttptest.WithRequestHeader(api.SwarmWebsiteRedirectsHeader, redirects),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestBody(tarFiles(t, []f{
+				{data: index, name: "index.html", header: html},
+				{data: notFound, name: "404.html", header: html},
+				{data: post, name: "post.html", dir: "blog/2023", header: html},
+			})),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		return "/bzz/" + resp.Reference.String() + "/"
+	}
+
+	root := upload(t, `[`+
+		`{"from": "/old.html", "to": "/index.html"},`+
+		`{"from": "/moved", "to": "https://example.org/moved", "status": 302},`+
+		`{"from": "/posts/*", "to": "/blog/2023/*", "status": 307},`+
+		`{"from": "app/*", "to": "/", "status": 200},`+
+		`{"from": "/broken", "to": "/missing.html", "status": 200}`+
+		`]`)
+
+	t.Run("redirect", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, root+"old.html", http.StatusMovedPermanently,
+			jsonhttptest.WithExpectedResponseHeader("Location", root+"index.html"),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, root+"moved", http.StatusFound,
+			jsonhttptest.WithExpectedResponseHeader("Location", "https://example.org/moved"),
+		)
+	})
+
+	t.Run("wildcard", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, root+"posts/post.html", http.StatusTemporaryRedirect,
+			jsonhttptest.WithExpectedResponseHeader("Location", root+"blog/2023/post.html"),
+		)
+	})
+
+	t.Run("rewrite", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, root+"app/users/1", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(index),
+		)
+	})
+
+	t.Run("existing path", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, root+"index.html", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(index),
+		)
+	})
+
+	t.Run("error document", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, root+"broken", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(notFound),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, root+"unknown.html", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(notFound),
+		)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		for _, redirects := range []string{
+			`{"from": "/a", "to": "/b"}`,
+			`[{"from": "/a"}]`,
+			`[{"from": "/a*/b", "to": "/b"}]`,
+			`[{"from": "/a", "to": "/b", "status": 404}]`,
+			`[{"from": "/a", "to": "https://example.org", "status": 200}]`,
+		} {
+			jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusBadRequest,
+				jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+				jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+				jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+				jsonhttptest.WithRequestHeader(api.SwarmWebsiteRedirectsHeader, redirects),
+				jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+				jsonhttptest.WithRequestBody(tarFiles(t, []f{{data: index, name: "index.html", header: html}})),
+				jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+					Message: "invalid website redirects",
+					Code:    http.StatusBadRequest,
+				}),
+			)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/replica"
+)
+
+type replicaStatusResponse struct {
+	Standby   bool      `json:"standby"`
+	Primary   string    `json:"primary"`
+	LastSync  time.Time `json:"lastSync"`
+	LastError string    `json:"lastError,omitempty"`
+	Pins      int       `json:"pins"`
+	Aliases   int       `json:"aliases"`
+	Tags      int       `json:"tags"`
+}
+
+// replicaStateHandler returns the state of the node which is
+// replicated by its standby nodes.
+func (s *Service) replicaStateHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_replica_state").Build()
+
+	if s.pinning == nil || s.aliases == nil || s.tags == nil {
+		jsonhttp.NotImplemented(w, "replica state not available")
+		return
+	}
+
+	state, err := replica.Snapshot(r.Context(), s.pinning, s.aliases, s.tags)
+	if err != nil {
+		logger.Debug("snapshot failed", "error", err)
+		logger.Error(nil, "snapshot failed")
+		jsonhttp.InternalServerError(w, "snapshot failed")
+		return
+	}
+
+	jsonhttp.OK(w, state)
+}
+
+// replicaStatusHandler returns the status of the replication
+// of the primary node.
+func (s *Service) replicaStatusHandler(w http.ResponseWriter, _ *http.Request) {
+	if s.replica == nil {
+		jsonhttp.NotImplemented(w, "standby mode not enabled")
+		return
+	}
+
+	st := s.replica.Status()
+	jsonhttp.OK(w, replicaStatusResponse{
+		Standby:   st.Standby,
+		Primary:   st.Primary,
+		LastSync:  st.LastSync,
+		LastError: st.LastError,
+		Pins:      st.Pins,
+		Aliases:   st.Aliases,
+		Tags:      st.Tags,
+	})
+}
+
+// replicaPromoteHandler stops the replication of the primary
+// node and ends the standby mode of the node.
+func (s *Service) replicaPromoteHandler(w http.ResponseWriter, _ *http.Request) {
+	if s.replica == nil {
+		jsonhttp.NotImplemented(w, "standby mode not enabled")
+		return
+	}
+
+	s.replica.Promote()
+
+	jsonhttp.OK(w, nil)
+}
+
+// standbyHandler rejects the requests which change the state of the node
+// while it is in the standby mode, as the state is replicated from the
+// primary node.
+func (s *Service) standbyHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.replica == nil || r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if r.URL.Path == "/replica/promote" || !s.replica.Standby() {
+			h.ServeHTTP(w, r)
+			return
+		}
+		jsonhttp.ServiceUnavailable(w, "node in standby mode")
+	})
+}
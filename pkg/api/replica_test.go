@@ -0,0 +1,139 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/alias"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	pinning "github.com/ethersphere/bee/pkg/pinning/mock"
+	"github.com/ethersphere/bee/pkg/replica"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestReplica(t *testing.T) {
+	t.Parallel()
+
+	ref := swarm.MustParseHexAddress("aa00000000000000000000000000000000000000000000000000000000000000")
+
+	t.Run("state", func(t *testing.T) {
+		t.Parallel()
+
+		pins := pinning.NewServiceMock()
+		if err := pins.CreatePin(context.Background(), ref, false); err != nil {
+			t.Fatal(err)
+		}
+		aliases := alias.NewRegistry(statestore.NewStateStore(), nil)
+		if err := aliases.Set(alias.Alias{Name: "site", Reference: ref}); err != nil {
+			t.Fatal(err)
+		}
+		ts := tags.NewTags(statestore.NewStateStore(), log.Noop)
+		tag, err := ts.CreateNamed(3, "release", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+			Pinning:  pins,
+			Aliases:  aliases,
+			Tags:     ts,
+		})
+
+		var got replica.State
+		jsonhttptest.Request(t, client, http.MethodGet, "/replica/state", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&got),
+		)
+		if len(got.Pins) != 1 || !got.Pins[0].Equal(ref) {
+			t.Fatalf("got pins %v, want %v", got.Pins, []swarm.Address{ref})
+		}
+		if len(got.Aliases) != 1 || got.Aliases[0].Name != "site" {
+			t.Fatalf("got aliases %v", got.Aliases)
+		}
+		if len(got.Tags) != 1 || got.Tags[0].Uid != tag.Uid || got.Tags[0].Name != "release" || got.Tags[0].Total != 3 {
+			t.Fatalf("got tags %+v", got.Tags)
+		}
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+		})
+
+		for _, tc := range []struct{ method, url string }{
+			{http.MethodGet, "/replica"},
+			{http.MethodPost, "/replica/promote"},
+		} {
+			jsonhttptest.Request(t, client, tc.method, tc.url, http.StatusNotImplemented,
+				jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+					Message: "standby mode not enabled",
+					Code:    http.StatusNotImplemented,
+				}),
+			)
+		}
+	})
+
+	t.Run("promote", func(t *testing.T) {
+		t.Parallel()
+
+		r := replica.New(pinning.NewServiceMock(), alias.NewRegistry(statestore.NewStateStore(), nil), tags.NewTags(statestore.NewStateStore(), log.Noop), replica.Options{PrimaryURL: "http://primary:1635"}, log.Noop)
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+			Replica:  r,
+		})
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/replica", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.ReplicaStatusResponse{
+				Standby: true,
+				Primary: "http://primary:1635",
+			}),
+		)
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/replica/promote", http.StatusOK)
+
+		if r.Standby() {
+			t.Fatal("expected promoted node")
+		}
+	})
+
+	t.Run("standby", func(t *testing.T) {
+		t.Parallel()
+
+		r := replica.New(pinning.NewServiceMock(), alias.NewRegistry(statestore.NewStateStore(), nil), tags.NewTags(statestore.NewStateStore(), log.Noop), replica.Options{PrimaryURL: "http://primary:1635"}, log.Noop)
+		aliases := alias.NewRegistry(statestore.NewStateStore(), nil)
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			Aliases: aliases,
+			Replica: r,
+		})
+
+		// the writes are rejected while the node is in the standby mode
+		jsonhttptest.Request(t, client, http.MethodPut, "/aliases/site", http.StatusServiceUnavailable,
+			jsonhttptest.WithJSONRequestBody(api.AliasRequest{Reference: &ref}),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "node in standby mode",
+				Code:    http.StatusServiceUnavailable,
+			}),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/aliases", http.StatusOK)
+
+		r.Promote()
+
+		jsonhttptest.Request(t, client, http.MethodPut, "/aliases/site", http.StatusOK,
+			jsonhttptest.WithJSONRequestBody(api.AliasRequest{Reference: &ref}),
+		)
+	})
+}
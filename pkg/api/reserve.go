@@ -0,0 +1,90 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/hex"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/gorilla/mux"
+)
+
+// ReserveEvicter evicts the chunks of the batches from the reserve.
+type ReserveEvicter interface {
+	// ReserveBatchSize returns the number of the reserve chunks of the
+	// batch and their size in bytes.
+	ReserveBatchSize(id []byte) (chunks, size uint64, err error)
+	// EvictReserveBatch evicts the chunks of the batch from the reserve
+	// and returns the number of the evicted chunks and their size.
+	EvictReserveBatch(id []byte) (chunks, size uint64, err error)
+}
+
+type reserveEvictResponse struct {
+	BatchID hexByte `json:"batchID"`
+	Chunks  uint64  `json:"chunks"`
+	Size    uint64  `json:"size"`
+	DryRun  bool    `json:"dryRun"`
+}
+
+// reserveEvictHandler evicts all reserve chunks of the batch, so that the
+// space taken by a batch identified as spam is reclaimed by the garbage
+// collection. In the dry-run mode only the space which would be reclaimed is
+// reported.
+func (s *Service) reserveEvictHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("delete_reserve").Build()
+
+	paths := struct {
+		BatchID []byte `map:"batch_id" validate:"required,len=32"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	queries := struct {
+		DryRun bool `map:"dry-run"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	if s.reserveEvicter == nil {
+		jsonhttp.NotImplemented(w, "reserve eviction not available")
+		return
+	}
+
+	hexBatchID := hex.EncodeToString(paths.BatchID)
+
+	var chunks, size uint64
+	if queries.DryRun {
+		var err error
+		chunks, size, err = s.reserveEvicter.ReserveBatchSize(paths.BatchID)
+		if err != nil {
+			logger.Debug("reserve batch size failed", "batch_id", hexBatchID, "error", err)
+			logger.Error(nil, "reserve batch size failed")
+			jsonhttp.InternalServerError(w, "reserve batch size failed")
+			return
+		}
+	} else {
+		var err error
+		chunks, size, err = s.reserveEvicter.EvictReserveBatch(paths.BatchID)
+		if err != nil {
+			logger.Debug("evict reserve batch failed", "batch_id", hexBatchID, "error", err)
+			logger.Error(nil, "evict reserve batch failed")
+			jsonhttp.InternalServerError(w, "evict reserve batch failed")
+			return
+		}
+		logger.Info("reserve batch evicted", "batch_id", hexBatchID, "chunks", chunks, "size", size)
+	}
+
+	jsonhttp.OK(w, reserveEvictResponse{
+		BatchID: paths.BatchID,
+		Chunks:  chunks,
+		Size:    size,
+		DryRun:  queries.DryRun,
+	})
+}
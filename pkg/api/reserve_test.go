@@ -0,0 +1,141 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+)
+
+type testReserveEvicter struct {
+	batchID []byte
+	chunks  uint64
+	size    uint64
+	evicted bool
+	err     error
+}
+
+// the pinned chunks of the batch are not evicted
+const testReserveEvictedSize = 36864
+
+var _ api.ReserveEvicter = (*testReserveEvicter)(nil)
+
+func (t *testReserveEvicter) ReserveBatchSize(id []byte) (uint64, uint64, error) {
+	if !bytes.Equal(id, t.batchID) {
+		return 0, 0, nil
+	}
+	return t.chunks, t.size, nil
+}
+
+func (t *testReserveEvicter) EvictReserveBatch(id []byte) (uint64, uint64, error) {
+	if t.err != nil {
+		return 0, 0, t.err
+	}
+	if !bytes.Equal(id, t.batchID) {
+		return 0, 0, nil
+	}
+	t.evicted = true
+	return t.chunks - 1, testReserveEvictedSize, nil
+}
+
+func TestReserveEvict(t *testing.T) {
+	t.Parallel()
+
+	batchID := postagetesting.MustNewID()
+	path := "/reserve/" + hex.EncodeToString(batchID)
+
+	t.Run("dry run", func(t *testing.T) {
+		t.Parallel()
+
+		evicter := &testReserveEvicter{batchID: batchID, chunks: 10, size: 40960}
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI:       true,
+			ReserveEvicter: evicter,
+		})
+
+		jsonhttptest.Request(t, ts, http.MethodDelete, path+"?dry-run=true", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.ReserveEvictResponse{
+				BatchID: batchID,
+				Chunks:  10,
+				Size:    40960,
+				DryRun:  true,
+			}),
+		)
+		if evicter.evicted {
+			t.Fatal("batch evicted in dry run")
+		}
+	})
+
+	t.Run("evict", func(t *testing.T) {
+		t.Parallel()
+
+		evicter := &testReserveEvicter{batchID: batchID, chunks: 10, size: 40960}
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI:       true,
+			ReserveEvicter: evicter,
+		})
+
+		jsonhttptest.Request(t, ts, http.MethodDelete, path, http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.ReserveEvictResponse{
+				BatchID: batchID,
+				Chunks:  9,
+				Size:    testReserveEvictedSize,
+			}),
+		)
+		if !evicter.evicted {
+			t.Fatal("batch not evicted")
+		}
+	})
+
+	t.Run("evict error", func(t *testing.T) {
+		t.Parallel()
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI:       true,
+			ReserveEvicter: &testReserveEvicter{err: errors.New("dummy error")},
+		})
+
+		jsonhttptest.Request(t, ts, http.MethodDelete, path, http.StatusInternalServerError,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "evict reserve batch failed",
+				Code:    http.StatusInternalServerError,
+			}),
+		)
+	})
+
+	t.Run("invalid batch id", func(t *testing.T) {
+		t.Parallel()
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI:       true,
+			ReserveEvicter: &testReserveEvicter{},
+		})
+
+		jsonhttptest.Request(t, ts, http.MethodDelete, "/reserve/abcd", http.StatusBadRequest)
+	})
+
+	t.Run("not implemented", func(t *testing.T) {
+		t.Parallel()
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+		})
+
+		jsonhttptest.Request(t, ts, http.MethodDelete, path, http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "reserve eviction not available",
+				Code:    http.StatusNotImplemented,
+			}),
+		)
+	})
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+)
+
+type retrievalPeerResponse struct {
+	Address    swarm.Address `json:"address"`
+	Peer       swarm.Address `json:"peer"`
+	RTT        string        `json:"rtt"`
+	Size       int           `json:"size"`
+	Valid      bool          `json:"valid"`
+	StampValid bool          `json:"stampValid"`
+}
+
+// retrievalPeerHandler retrieves the chunk directly from the peer, bypassing
+// the selection of the closest peer, and reports the round trip time and the
+// validity of the delivered chunk and its postage stamp. The peer may forward
+// the request if it does not store the chunk.
+func (s *Service) retrievalPeerHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_retrieval_peer").Build()
+
+	paths := struct {
+		Address swarm.Address `map:"address" validate:"required"`
+		Peer    swarm.Address `map:"peer" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+	if len(paths.Address.Bytes()) != swarm.HashSize {
+		jsonhttp.BadRequest(w, "invalid address length")
+		return
+	}
+
+	if s.peerRetriever == nil {
+		jsonhttp.NotImplemented(w, "retrieval from peer not available")
+		return
+	}
+
+	ctx := r.Context()
+	span, logger, ctx := s.tracer.StartSpanFromContext(ctx, "retrieval-peer-api", logger)
+	defer span.Finish()
+
+	chunk, rtt, err := s.peerRetriever.RetrieveChunkFromPeer(ctx, paths.Address, paths.Peer)
+	if err != nil && !errors.Is(err, swarm.ErrInvalidChunk) {
+		logger.Debug("retrieve chunk from peer failed", "chunk_address", paths.Address, "peer_address", paths.Peer, "error", err)
+		if errors.Is(err, p2p.ErrPeerNotFound) {
+			jsonhttp.NotFound(w, "peer not found")
+			return
+		}
+		jsonhttp.NotFound(w, "chunk not retrieved from peer")
+		return
+	}
+	valid := err == nil
+
+	stampValid := false
+	if stamp, err := chunk.Stamp().MarshalBinary(); err == nil {
+		_, err = postage.ValidStamp(s.batchStore)(chunk, stamp)
+		stampValid = err == nil
+	}
+
+	jsonhttp.OK(w, retrievalPeerResponse{
+		Address:    paths.Address,
+		Peer:       paths.Peer,
+		RTT:        rtt.String(),
+		Size:       len(chunk.Data()),
+		Valid:      valid,
+		StampValid: stampValid,
+	})
+}
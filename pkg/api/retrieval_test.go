@@ -0,0 +1,130 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/p2p"
+	mockbatchstore "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+	retrievalmock "github.com/ethersphere/bee/pkg/retrieval/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	testingc "github.com/ethersphere/bee/pkg/storage/testing"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestRetrievalPeer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rtt          = 25 * time.Millisecond
+		chunk        = testingc.GenerateTestRandomChunk()
+		invalidChunk = testingc.GenerateTestRandomInvalidChunk()
+		missing      = testingc.GenerateTestRandomChunk().Address()
+		peer         = swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+		unknownPeer  = swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59e")
+	)
+
+	retriever := retrievalmock.NewPeerRetriever(func(_ context.Context, chunkAddr, p swarm.Address) (swarm.Chunk, time.Duration, error) {
+		if !p.Equal(peer) {
+			return nil, 0, p2p.ErrPeerNotFound
+		}
+		switch {
+		case chunkAddr.Equal(chunk.Address()):
+			return chunk, rtt, nil
+		case chunkAddr.Equal(invalidChunk.Address()):
+			return invalidChunk, rtt, swarm.ErrInvalidChunk
+		}
+		return nil, rtt, storage.ErrNotFound
+	})
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI:      true,
+		PeerRetriever: retriever,
+		// the random stamps of the chunks are not valid for the batch
+		BatchStore: mockbatchstore.New(mockbatchstore.WithBatch(postagetesting.MustNewBatch())),
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/retrieval/"+chunk.Address().String()+"/peers/"+peer.String(), http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.RetrievalPeerResponse{
+				Address: chunk.Address(),
+				Peer:    peer,
+				RTT:     rtt.String(),
+				Size:    len(chunk.Data()),
+				Valid:   true,
+			}),
+		)
+	})
+
+	t.Run("invalid chunk", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/retrieval/"+invalidChunk.Address().String()+"/peers/"+peer.String(), http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.RetrievalPeerResponse{
+				Address: invalidChunk.Address(),
+				Peer:    peer,
+				RTT:     rtt.String(),
+				Size:    len(invalidChunk.Data()),
+				Valid:   false,
+			}),
+		)
+	})
+
+	t.Run("peer not found", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/retrieval/"+chunk.Address().String()+"/peers/"+unknownPeer.String(), http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusNotFound,
+				Message: "peer not found",
+			}),
+		)
+	})
+
+	t.Run("chunk not retrieved", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/retrieval/"+missing.String()+"/peers/"+peer.String(), http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusNotFound,
+				Message: "chunk not retrieved from peer",
+			}),
+		)
+	})
+
+	t.Run("invalid address length", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/retrieval/abcd/peers/"+peer.String(), http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusBadRequest,
+				Message: "invalid address length",
+			}),
+		)
+	})
+
+	t.Run("not implemented", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{DebugAPI: true})
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/retrieval/"+chunk.Address().String()+"/peers/"+peer.String(), http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusNotImplemented,
+				Message: "retrieval from peer not available",
+			}),
+		)
+	})
+}
@@ -12,8 +12,11 @@ import (
 	"strings"
 
 	"github.com/ethersphere/bee/pkg/auth"
+	"github.com/ethersphere/bee/pkg/broadcast"
+	"github.com/ethersphere/bee/pkg/feeds/crdt"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/log/httpaccess"
+	"github.com/ethersphere/bee/pkg/pss/session"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
@@ -34,6 +37,7 @@ func (s *Service) MountTechnicalDebug() {
 	s.mountTechnicalDebug()
 
 	s.Handler = web.ChainHandlers(
+		s.requestIDHandler,
 		httpaccess.NewHTTPAccessLogHandler(s.logger, s.tracer, "debug api access"),
 		handlers.CompressHandler,
 		s.corsHandler,
@@ -46,6 +50,7 @@ func (s *Service) MountDebug(restricted bool) {
 	s.mountBusinessDebug(restricted)
 
 	s.Handler = web.ChainHandlers(
+		s.requestIDHandler,
 		httpaccess.NewHTTPAccessLogHandler(s.logger, s.tracer, "debug api access"),
 		handlers.CompressHandler,
 		s.corsHandler,
@@ -62,6 +67,16 @@ func (s *Service) MountAPI() {
 
 	s.mountAPI()
 
+	s.router.Use(s.routeMetricsHandler)
+	s.router.Use(s.retrievalModeHandler)
+	s.router.Use(s.standbyHandler)
+	if s.memoryBudget != nil {
+		s.router.Use(s.memoryBudgetHandler)
+	}
+	if s.auditLog != nil {
+		s.router.Use(s.auditHandler)
+	}
+
 	compressHandler := func(h http.Handler) http.Handler {
 		downloadEndpoints := []string{
 			"/bzz",
@@ -95,10 +110,10 @@ func (s *Service) MountAPI() {
 	}
 
 	s.Handler = web.ChainHandlers(
+		fullDuplexHandler,
+		s.requestIDHandler,
 		httpaccess.NewHTTPAccessLogHandler(s.logger, s.tracer, "api access"),
 		compressHandler,
-		s.responseCodeMetricsHandler,
-		s.pageviewMetricsHandler,
 		s.corsHandler,
 		web.FinalHandler(s.router),
 	)
@@ -121,7 +136,10 @@ func (s *Service) mountTechnicalDebug() {
 		httpaccess.NewHTTPAccessSuppressLogHandler(),
 		web.FinalHandler(promhttp.InstrumentMetricHandler(
 			s.metricsRegistry,
-			promhttp.HandlerFor(s.metricsRegistry, promhttp.HandlerOpts{}),
+			promhttp.HandlerFor(s.metricsRegistry, promhttp.HandlerOpts{
+				// exemplars are exposed only in the OpenMetrics format
+				EnableOpenMetrics: true,
+			}),
 		)),
 	))
 
@@ -176,10 +194,26 @@ func (s *Service) mountTechnicalDebug() {
 }
 
 func (s *Service) mountAPI() {
-	subdomainRouter := s.router.Host("{subdomain:.*}.swarm.localhost").Subrouter()
+	gatewayDomain := s.GatewayDomain
+	if gatewayDomain == "" {
+		gatewayDomain = DefaultGatewayDomain
+	}
+	subdomainRouter := s.router.Host("{subdomain:.*}." + gatewayDomain).Subrouter()
 
 	subdomainRouter.Handle("/{path:.*}", jsonhttp.MethodHandler{
 		"GET": web.ChainHandlers(
+			s.challengeHandler("bzz"),
+			s.shapingHandler,
+			s.retrievalPriorityHandler,
+			s.downloadLimitHandler,
+			web.FinalHandlerFunc(s.subdomainHandler),
+		),
+		"HEAD": web.ChainHandlers(
+			s.challengeHandler("bzz"),
+			s.shapingHandler,
+			s.retrievalPriorityHandler,
+			s.downloadLimitHandler,
+			s.newTracingHandler("subdomain-head"),
 			web.FinalHandlerFunc(s.subdomainHandler),
 		),
 	})
@@ -198,6 +232,14 @@ func (s *Service) mountAPI() {
 		s.router.Handle(rootPath+path, handler)
 	}
 
+	handle("/challenge", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.challengeGetHandler),
+		"POST": web.ChainHandlers(
+			jsonhttp.NewMaxBodyBytesHandler(1024),
+			web.FinalHandlerFunc(s.challengePostHandler),
+		),
+	})
+
 	handle("/bytes", jsonhttp.MethodHandler{
 		"POST": web.ChainHandlers(
 			s.contentLengthMetricMiddleware(),
@@ -206,18 +248,52 @@ func (s *Service) mountAPI() {
 		),
 	})
 
+	handle("/hash", jsonhttp.MethodHandler{
+		"POST": web.ChainHandlers(
+			s.contentLengthMetricMiddleware(),
+			s.newTracingHandler("hash"),
+			jsonhttp.NewMaxBodyBytesHandler(maxHashBodySize),
+			web.FinalHandlerFunc(s.hashHandler),
+		),
+	})
+
 	handle("/bytes/{address}", jsonhttp.MethodHandler{
 		"GET": web.ChainHandlers(
+			s.challengeHandler("bytes"),
+			s.shapingHandler,
+			s.retrievalPriorityHandler,
+			s.downloadLimitHandler,
 			s.contentLengthMetricMiddleware(),
+			s.analyticsHandler,
 			s.newTracingHandler("bytes-download"),
 			web.FinalHandlerFunc(s.bytesGetHandler),
 		),
 		"HEAD": web.ChainHandlers(
+			s.challengeHandler("bytes"),
+			s.shapingHandler,
+			s.retrievalPriorityHandler,
+			s.downloadLimitHandler,
 			s.newTracingHandler("bytes-head"),
 			web.FinalHandlerFunc(s.bytesHeadHandler),
 		),
 	})
 
+	handle("/ipfs/{cid}", jsonhttp.MethodHandler{
+		"POST": web.ChainHandlers(
+			s.newTracingHandler("ipfs-import"),
+			web.FinalHandlerFunc(s.ipfsImportHandler),
+		),
+	})
+
+	handle("/legacy/{address}", jsonhttp.MethodHandler{
+		"POST": web.ChainHandlers(
+			s.contentLengthMetricMiddleware(),
+			s.newTracingHandler("legacy-import"),
+			jsonhttp.NewMaxBodyBytesHandler(maxLegacyArchiveSize),
+			web.FinalHandlerFunc(s.legacyImportHandler),
+		),
+	})
+
 	handle("/chunks", jsonhttp.MethodHandler{
 		"POST": web.ChainHandlers(
 			jsonhttp.NewMaxBodyBytesHandler(swarm.ChunkWithSpanSize),
@@ -230,12 +306,47 @@ func (s *Service) mountAPI() {
 		web.FinalHandlerFunc(s.chunkUploadStreamHandler),
 	))
 
+	handle("/chunks/stream/download", web.ChainHandlers(
+		s.challengeHandler("chunks"),
+		s.shapingHandler,
+		s.retrievalPriorityHandler,
+		s.downloadLimitHandler,
+		s.newTracingHandler("chunks-stream-download"),
+		web.FinalHandlerFunc(s.chunkDownloadStreamHandler),
+	))
+
+	handle("/chunks/batch", jsonhttp.MethodHandler{
+		"POST": web.ChainHandlers(
+			s.challengeHandler("chunks"),
+			s.shapingHandler,
+			s.retrievalPriorityHandler,
+			s.downloadLimitHandler,
+			s.newTracingHandler("chunks-batch-download"),
+			jsonhttp.NewMaxBodyBytesHandler(maxChunkBatchBodySize),
+			web.FinalHandlerFunc(s.chunkBatchGetHandler),
+		),
+	})
+
 	handle("/chunks/{address}", jsonhttp.MethodHandler{
-		"GET":    http.HandlerFunc(s.chunkGetHandler),
+		"GET": web.ChainHandlers(
+			s.challengeHandler("chunks"),
+			s.shapingHandler,
+			s.retrievalPriorityHandler,
+			s.downloadLimitHandler,
+			s.analyticsHandler,
+			web.FinalHandlerFunc(s.chunkGetHandler),
+		),
 		"HEAD":   http.HandlerFunc(s.hasChunkHandler),
 		"DELETE": http.HandlerFunc(s.removeChunk),
 	})
 
+	handle("/soc/verify", web.ChainHandlers(
+		jsonhttp.NewMaxBodyBytesHandler(swarm.SocMaxChunkSize),
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"POST": http.HandlerFunc(s.socVerifyHandler),
+		})),
+	)
+
 	handle("/soc/{owner}/{id}", jsonhttp.MethodHandler{
 		"POST": web.ChainHandlers(
 			jsonhttp.NewMaxBodyBytesHandler(swarm.ChunkWithSpanSize),
@@ -243,8 +354,15 @@ func (s *Service) mountAPI() {
 		),
 	})
 
+	handle("/envelope/{address}", jsonhttp.MethodHandler{
+		"POST": http.HandlerFunc(s.envelopePostHandler),
+	})
+
 	handle("/feeds/{owner}/{topic}", jsonhttp.MethodHandler{
-		"GET": http.HandlerFunc(s.feedGetHandler),
+		"GET": web.ChainHandlers(
+			s.challengeHandler("feeds"),
+			web.FinalHandlerFunc(s.feedGetHandler),
+		),
 		"POST": web.ChainHandlers(
 			jsonhttp.NewMaxBodyBytesHandler(swarm.ChunkWithSpanSize),
 			web.FinalHandlerFunc(s.feedPostHandler),
@@ -253,24 +371,86 @@ func (s *Service) mountAPI() {
 
 	handle("/bzz", jsonhttp.MethodHandler{
 		"POST": web.ChainHandlers(
+			s.idempotencyHandler,
 			s.contentLengthMetricMiddleware(),
 			s.newTracingHandler("bzz-upload"),
 			web.FinalHandlerFunc(s.bzzUploadHandler),
 		),
 	})
 
-	handle("/bzz/{address}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		u := r.URL
-		u.Path += "/"
-		http.Redirect(w, r, u.String(), http.StatusPermanentRedirect)
-	}))
+	handle("/bzz/sessions", jsonhttp.MethodHandler{
+		"POST": web.ChainHandlers(
+			s.idempotencyHandler,
+			web.FinalHandlerFunc(s.uploadSessionCreateHandler),
+		),
+	})
+
+	handle("/bzz/sessions/{id}", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.uploadSessionGetHandler),
+		"PATCH": web.ChainHandlers(
+			s.contentLengthMetricMiddleware(),
+			web.FinalHandlerFunc(s.uploadSessionAppendHandler),
+		),
+		"DELETE": http.HandlerFunc(s.uploadSessionDeleteHandler),
+	})
+
+	handle("/bzz/sessions/{id}/commit", jsonhttp.MethodHandler{
+		"POST": web.ChainHandlers(
+			s.idempotencyHandler,
+			s.newTracingHandler("bzz-session-commit"),
+			web.FinalHandlerFunc(s.uploadSessionCommitHandler),
+		),
+	})
+
+	handle("/bzz/{address}", web.ChainHandlers(
+		s.gatewaySubdomainOnlyHandler,
+		web.FinalHandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			u := r.URL
+			u.Path += "/"
+			http.Redirect(w, r, u.String(), http.StatusPermanentRedirect)
+		}),
+	))
 
 	handle("/bzz/{address}/{path:.*}", jsonhttp.MethodHandler{
 		"GET": web.ChainHandlers(
+			s.gatewaySubdomainOnlyHandler,
+			s.challengeHandler("bzz"),
+			s.shapingHandler,
+			s.retrievalPriorityHandler,
+			s.downloadLimitHandler,
 			s.contentLengthMetricMiddleware(),
+			s.analyticsHandler,
 			s.newTracingHandler("bzz-download"),
 			web.FinalHandlerFunc(s.bzzDownloadHandler),
 		),
+		"HEAD": web.ChainHandlers(
+			s.gatewaySubdomainOnlyHandler,
+			s.challengeHandler("bzz"),
+			s.shapingHandler,
+			s.retrievalPriorityHandler,
+			s.downloadLimitHandler,
+			s.newTracingHandler("bzz-head"),
+			web.FinalHandlerFunc(s.bzzDownloadHandler),
+		),
+	})
+
+	handle("/act/grantees/{address}", jsonhttp.MethodHandler{
+		"GET": web.ChainHandlers(
+			s.newTracingHandler("act-grantees-get"),
+			web.FinalHandlerFunc(s.actGranteesGetHandler),
+		),
+		"PATCH": web.ChainHandlers(
+			jsonhttp.NewMaxBodyBytesHandler(1<<20),
+			s.newTracingHandler("act-grantees-patch"),
+			web.FinalHandlerFunc(s.actGranteesPatchHandler),
+		),
+	})
+
+	handle("/manifests/{address}/node", jsonhttp.MethodHandler{
+		"GET": web.ChainHandlers(
+			s.newTracingHandler("manifest-node"),
+			web.FinalHandlerFunc(s.manifestNodeHandler),
+		),
 	})
 
 	handle("/pss/send/{topic}/{targets}", web.ChainHandlers(
@@ -286,11 +466,57 @@ func (s *Service) mountAPI() {
 		web.FinalHandlerFunc(s.pssWsHandler),
 	))
 
+	handle("/pss/sessions", web.ChainHandlers(
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"GET":  http.HandlerFunc(s.pssSessionsGetHandler),
+			"POST": http.HandlerFunc(s.pssSessionPostHandler),
+		})),
+	)
+
+	handle("/pss/sessions/{id}", web.ChainHandlers(
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"GET":    http.HandlerFunc(s.pssSessionGetHandler),
+			"DELETE": http.HandlerFunc(s.pssSessionDeleteHandler),
+		})),
+	)
+
+	handle("/pss/sessions/{id}/accept", web.ChainHandlers(
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"POST": http.HandlerFunc(s.pssSessionAcceptHandler),
+		})),
+	)
+
+	handle("/pss/sessions/{id}/messages", web.ChainHandlers(
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"POST": web.ChainHandlers(
+				jsonhttp.NewMaxBodyBytesHandler(session.MaxMessageSize),
+				web.FinalHandlerFunc(s.pssSessionMessagePostHandler),
+			),
+		})),
+	)
+
+	handle("/pss/sessions/{id}/subscribe", web.ChainHandlers(
+		web.FinalHandlerFunc(s.pssSessionWsHandler),
+	))
+
+	handle("/broadcast/{topic}", web.ChainHandlers(
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"POST": web.ChainHandlers(
+				jsonhttp.NewMaxBodyBytesHandler(broadcast.MaxPayloadSize),
+				web.FinalHandlerFunc(s.broadcastPostHandler),
+			),
+		})),
+	)
+
+	handle("/broadcast/subscribe/{topic}", web.ChainHandlers(
+		web.FinalHandlerFunc(s.broadcastWsHandler),
+	))
+
 	handle("/tags", web.ChainHandlers(
 		web.FinalHandler(jsonhttp.MethodHandler{
 			"GET": http.HandlerFunc(s.listTagsHandler),
 			"POST": web.ChainHandlers(
-				jsonhttp.NewMaxBodyBytesHandler(1024),
+				jsonhttp.NewMaxBodyBytesHandler(8192),
 				web.FinalHandlerFunc(s.createTagHandler),
 			),
 		})),
@@ -313,6 +539,10 @@ func (s *Service) mountAPI() {
 		})),
 	)
 
+	handle("/pins/expiry/subscribe", web.ChainHandlers(
+		web.FinalHandlerFunc(s.pinExpiryWsHandler),
+	))
+
 	handle("/pins/{reference}", web.ChainHandlers(
 		web.FinalHandler(jsonhttp.MethodHandler{
 			"GET":    http.HandlerFunc(s.getPinnedRootHash),
@@ -330,6 +560,81 @@ func (s *Service) mountAPI() {
 		),
 	})
 
+	handle("/warm/jobs/{id}", web.ChainHandlers(
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.getWarmJobHandler),
+		})),
+	)
+
+	handle("/warm/{reference}", web.ChainHandlers(
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"POST": http.HandlerFunc(s.warmHandler),
+		})),
+	)
+
+	handle("/jobs", web.ChainHandlers(
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.listJobsHandler),
+		})),
+	)
+
+	handle("/jobs/{id}", web.ChainHandlers(
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"GET":    http.HandlerFunc(s.getJobHandler),
+			"DELETE": http.HandlerFunc(s.cancelJobHandler),
+		})),
+	)
+
+	handle("/deploys/{topic}", jsonhttp.MethodHandler{
+		"GET": web.ChainHandlers(
+			web.FinalHandlerFunc(s.deployGetHandler),
+		),
+		"POST": web.ChainHandlers(
+			s.contentLengthMetricMiddleware(),
+			s.newTracingHandler("deploy-upload"),
+			web.FinalHandlerFunc(s.deployPostHandler),
+		),
+	})
+
+	handle("/deploys/{topic}/rollback", web.ChainHandlers(
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"POST": http.HandlerFunc(s.deployRollbackHandler),
+		})),
+	)
+
+	handle("/crdt/registers/{topic}", jsonhttp.MethodHandler{
+		"GET": web.ChainHandlers(
+			web.FinalHandlerFunc(s.crdtRegisterGetHandler),
+		),
+		"POST": web.ChainHandlers(
+			jsonhttp.NewMaxBodyBytesHandler(crdt.MaxValueSize),
+			web.FinalHandlerFunc(s.crdtRegisterPostHandler),
+		),
+	})
+
+	handle("/crdt/counters/{topic}", jsonhttp.MethodHandler{
+		"GET": web.ChainHandlers(
+			web.FinalHandlerFunc(s.crdtCounterGetHandler),
+		),
+		"POST": web.ChainHandlers(
+			web.FinalHandlerFunc(s.crdtCounterPostHandler),
+		),
+	})
+
+	handle("/aliases", web.ChainHandlers(
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.aliasesGetHandler),
+		})),
+	)
+
+	handle("/aliases/{name}", web.ChainHandlers(
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"GET":    http.HandlerFunc(s.aliasGetHandler),
+			"PUT":    http.HandlerFunc(s.aliasPutHandler),
+			"DELETE": http.HandlerFunc(s.aliasDeleteHandler),
+		})),
+	)
+
 	handle("/readiness", web.ChainHandlers(
 		httpaccess.NewHTTPAccessSuppressLogHandler(),
 		web.FinalHandlerFunc(s.readinessHandler),
@@ -388,14 +693,60 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 		"GET": http.HandlerFunc(s.peersHandler),
 	})
 
+	handle("/debug/analytics", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.analyticsGetHandler),
+	})
+
 	handle("/pingpong/{address}", jsonhttp.MethodHandler{
 		"POST": http.HandlerFunc(s.pingpongHandler),
 	})
 
+	handle("/retrieval/{address}/peers/{peer}", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.retrievalPeerHandler),
+	})
+
 	handle("/reservestate", jsonhttp.MethodHandler{
 		"GET": http.HandlerFunc(s.reserveStateHandler),
 	})
 
+	handle("/reserve/{batch_id}", jsonhttp.MethodHandler{
+		"DELETE": http.HandlerFunc(s.reserveEvictHandler),
+	})
+
+	handle("/spam/batches", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.spamBatchesHandler),
+	})
+
+	handle("/depthmonitor", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.depthMonitorHandler),
+	})
+
+	handle("/replica", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.replicaStatusHandler),
+	})
+
+	handle("/replica/state", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.replicaStateHandler),
+	})
+
+	handle("/replica/promote", jsonhttp.MethodHandler{
+		"POST": http.HandlerFunc(s.replicaPromoteHandler),
+	})
+
+	handle("/maintenance", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.maintenanceHandler),
+	})
+
+	handle("/events", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.eventsHandler),
+	})
+
+	if s.GraphQL {
+		handle("/graphql", jsonhttp.MethodHandler{
+			"POST": s.graphqlHandler(),
+		})
+	}
+
 	handle("/connect/{multi-address:.+}", jsonhttp.MethodHandler{
 		"POST": http.HandlerFunc(s.peerConnectHandler),
 	})
@@ -417,6 +768,33 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 		"GET": http.HandlerFunc(s.topologyHandler),
 	})
 
+	handle("/topology/graph", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.topologyGraphHandler),
+	})
+
+	if s.faults != nil {
+		handle("/faults", jsonhttp.MethodHandler{
+			"GET":    http.HandlerFunc(s.faultRulesGetHandler),
+			"DELETE": http.HandlerFunc(s.faultRulesDeleteHandler),
+		})
+
+		handle("/faults/{point}", jsonhttp.MethodHandler{
+			"PUT": web.ChainHandlers(
+				jsonhttp.NewMaxBodyBytesHandler(faultRuleMaxRequestSize),
+				web.FinalHandler(http.HandlerFunc(s.faultRulePutHandler)),
+			),
+			"DELETE": http.HandlerFunc(s.faultRuleDeleteHandler),
+		})
+	}
+
+	handle("/download-limits", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.downloadLimitsGetHandler),
+		"PUT": web.ChainHandlers(
+			jsonhttp.NewMaxBodyBytesHandler(downloadLimitsMaxRequestSize),
+			web.FinalHandlerFunc(s.downloadLimitsPutHandler),
+		),
+	})
+
 	handle("/welcome-message", jsonhttp.MethodHandler{
 		"GET": http.HandlerFunc(s.getWelcomeMessageHandler),
 		"POST": web.ChainHandlers(
@@ -465,6 +843,7 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 		handle("/chequebook/cashout/{peer}", jsonhttp.MethodHandler{
 			"GET": http.HandlerFunc(s.swapCashoutStatusHandler),
 			"POST": web.ChainHandlers(
+				s.idempotencyHandler,
 				s.gasConfigMiddleware("swap cashout"),
 				web.FinalHandlerFunc(s.swapCashoutHandler),
 			),
@@ -482,6 +861,7 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 
 		handle("/chequebook/deposit", jsonhttp.MethodHandler{
 			"POST": web.ChainHandlers(
+				s.idempotencyHandler,
 				s.gasConfigMiddleware("chequebook deposit"),
 				web.FinalHandlerFunc(s.chequebookDepositHandler),
 			),
@@ -489,6 +869,7 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 
 		handle("/chequebook/withdraw", jsonhttp.MethodHandler{
 			"POST": web.ChainHandlers(
+				s.idempotencyHandler,
 				s.gasConfigMiddleware("chequebook withdraw"),
 				web.FinalHandlerFunc(s.chequebookWithdrawHandler),
 			),
@@ -508,6 +889,25 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 		})),
 	)
 
+	handle("/stamps/verify", web.ChainHandlers(
+		s.postageSyncStatusCheckHandler,
+		jsonhttp.NewMaxBodyBytesHandler(1024),
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"POST": http.HandlerFunc(s.postageVerifyStampHandler),
+		})),
+	)
+
+	handle("/stamps/estimate", web.ChainHandlers(
+		s.postageSyncStatusCheckHandler,
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.postageEstimateHandler),
+		})),
+	)
+
+	handle("/stamps/events/subscribe", web.ChainHandlers(
+		web.FinalHandlerFunc(s.batchEventsWsHandler),
+	))
+
 	handle("/stamps/{batch_id}", web.ChainHandlers(
 		s.postageSyncStatusCheckHandler,
 		web.FinalHandler(jsonhttp.MethodHandler{
@@ -522,7 +922,15 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 		})),
 	)
 
+	handle("/stamps/{batch_id}/restamp/{address}", web.ChainHandlers(
+		s.postageSyncStatusCheckHandler,
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"POST": http.HandlerFunc(s.postageRestampHandler),
+		})),
+	)
+
 	handle("/stamps/{amount}/{depth}", web.ChainHandlers(
+		s.idempotencyHandler,
 		s.postageAccessHandler,
 		s.postageSyncStatusCheckHandler,
 		s.gasConfigMiddleware("create batch"),
@@ -531,7 +939,16 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 		})),
 	)
 
+	handle("/ens/{name}/{address}", web.ChainHandlers(
+		s.postageAccessHandler,
+		s.gasConfigMiddleware("publish ens"),
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"POST": http.HandlerFunc(s.ensPublishHandler),
+		})),
+	)
+
 	handle("/stamps/topup/{batch_id}/{amount}", web.ChainHandlers(
+		s.idempotencyHandler,
 		s.postageAccessHandler,
 		s.postageSyncStatusCheckHandler,
 		s.gasConfigMiddleware("topup batch"),
@@ -541,6 +958,7 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 	)
 
 	handle("/stamps/dilute/{batch_id}/{depth}", web.ChainHandlers(
+		s.idempotencyHandler,
 		s.postageAccessHandler,
 		s.postageSyncStatusCheckHandler,
 		s.gasConfigMiddleware("dilute batch"),
@@ -0,0 +1,49 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/shaping"
+	"github.com/gorilla/mux"
+)
+
+// shapingHandler admits the download requests through the scheduler, so
+// that the requests over the per-client and per-reference concurrency
+// limits wait for their turn. The requests which are not admitted are
+// rejected with the time after which they should be retried.
+func (s *Service) shapingHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.shaping == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		vars := mux.Vars(r)
+		reference := vars["address"]
+		if reference == "" {
+			reference = vars["subdomain"]
+		}
+
+		release, err := s.shaping.Acquire(r.Context(), remoteIP(r).String(), reference)
+		if err != nil {
+			if errors.Is(err, shaping.ErrQueueFull) || errors.Is(err, shaping.ErrQueueTimeout) {
+				retryAfter := int(math.Ceil(s.shaping.RetryAfter().Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				jsonhttp.TooManyRequests(w, "too many concurrent requests")
+			}
+			// otherwise the request was canceled by the client
+			return
+		}
+		defer release()
+
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/shaping"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestShaping(t *testing.T) {
+	t.Parallel()
+
+	scheduler := shaping.New(shaping.Options{PerReference: 1, QueueTimeout: 100 * time.Millisecond})
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:  mock.NewStorer(),
+		Tags:    tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger:  log.Noop,
+		Post:    mockpost.New(mockpost.WithAcceptAll()),
+		Shaping: scheduler,
+	})
+
+	data := []byte("hot content")
+	var res api.BytesPostResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+		jsonhttptest.WithUnmarshalJSONResponse(&res),
+	)
+
+	// the only place of the reference is taken by another client
+	release, err := scheduler.Acquire(context.Background(), "other", res.Reference.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+res.Reference.String(), http.StatusTooManyRequests,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "too many concurrent requests",
+			Code:    http.StatusTooManyRequests,
+		}),
+		jsonhttptest.WithExpectedResponseHeader("Retry-After", "1"),
+	)
+
+	release()
+	jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+res.Reference.String(), http.StatusOK,
+		jsonhttptest.WithExpectedResponse(data),
+	)
+}
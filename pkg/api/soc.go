@@ -5,12 +5,17 @@
 package api
 
 import (
+	"bytes"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"io"
 	"net/http"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/soc"
@@ -140,7 +145,7 @@ func (s *Service) socUploadHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	stamper := postage.NewStamper(i, s.signer)
+	stamper := s.newStamper(i)
 	stamp, err := stamper.Stamp(sch.Address())
 	if err != nil {
 		logger.Debug("stamp failed", "chunk_address", sch.Address(), "error", err)
@@ -171,5 +176,124 @@ func (s *Service) socUploadHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// the chunk may be an update of the feeds bound to the domains
+	if s.dnsLink != nil {
+		s.dnsLink.Notify(common.BytesToAddress(paths.Owner))
+	}
+
 	jsonhttp.Created(w, chunkAddressResponse{Reference: sch.Address()})
 }
+
+type socVerifyResponse struct {
+	Valid            bool          `json:"valid"`
+	Reasons          []string      `json:"reasons,omitempty"`
+	Address          swarm.Address `json:"address"`
+	Owner            string        `json:"owner"`
+	Identifier       string        `json:"identifier"`
+	Signature        string        `json:"signature"`
+	WrappedReference swarm.Address `json:"wrappedReference"`
+	WrappedSpan      uint64        `json:"wrappedSpan"`
+}
+
+// socVerifyHandler decodes the serialized single-owner chunk, which is
+// the identifier, the signature and the wrapped chunk with its span, and
+// reports whether its signature is valid, and whether it matches the
+// optional address, owner and feed update given in the query.
+func (s *Service) socVerifyHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_soc_verify").Build()
+
+	queries := struct {
+		Address swarm.Address `map:"address"`
+		Owner   []byte        `map:"owner"`
+		Topic   []byte        `map:"topic"`
+		Index   uint64        `map:"index"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		if jsonhttp.HandleBodyReadError(err, w) {
+			return
+		}
+		logger.Debug("read body failed", "error", err)
+		logger.Error(nil, "read body failed")
+		jsonhttp.InternalServerError(w, "cannot read chunk data")
+		return
+	}
+
+	if len(data) < swarm.SocMinChunkSize {
+		logger.Debug("chunk data too short", "size", len(data))
+		logger.Error(nil, "chunk data too short")
+		jsonhttp.BadRequest(w, "short chunk data")
+		return
+	}
+	if len(data) > swarm.SocMaxChunkSize {
+		logger.Debug("chunk data exceeds required length", "required_length", swarm.SocMaxChunkSize)
+		logger.Error(nil, "chunk data exceeds required length")
+		jsonhttp.RequestEntityTooLarge(w, "payload too large")
+		return
+	}
+
+	cursor := swarm.HashSize + swarm.SocSignatureSize
+	wrapped, err := cac.NewWithDataSpan(data[cursor:])
+	if err != nil {
+		logger.Debug("create content addressed chunk failed", "error", err)
+		logger.Error(nil, "create content addressed chunk failed")
+		jsonhttp.BadRequest(w, "invalid wrapped chunk")
+		return
+	}
+
+	id := data[:swarm.HashSize]
+	res := socVerifyResponse{
+		Valid:            true,
+		Identifier:       hex.EncodeToString(id),
+		Signature:        hex.EncodeToString(data[swarm.HashSize:cursor]),
+		WrappedReference: wrapped.Address(),
+		WrappedSpan:      binary.LittleEndian.Uint64(data[cursor : cursor+swarm.SpanSize]),
+	}
+	invalid := func(reason string) {
+		res.Valid = false
+		res.Reasons = append(res.Reasons, reason)
+	}
+
+	// the owner is recovered from the signature of the identifier
+	// and the wrapped chunk address
+	ss, err := soc.FromChunk(swarm.NewChunk(swarm.ZeroAddress, data))
+	if err != nil {
+		invalid("signature: " + err.Error())
+		jsonhttp.OK(w, res)
+		return
+	}
+	res.Owner = hex.EncodeToString(ss.OwnerAddress())
+	res.Address, err = soc.CreateAddress(ss.ID(), ss.OwnerAddress())
+	if err != nil {
+		logger.Debug("create soc address failed", "error", err)
+		logger.Error(nil, "create soc address failed")
+		jsonhttp.InternalServerError(w, "create soc address failed")
+		return
+	}
+
+	if !queries.Address.IsZero() && !queries.Address.Equal(res.Address) {
+		invalid("address does not match the owner and the identifier")
+	}
+	if len(queries.Owner) > 0 && !bytes.Equal(queries.Owner, ss.OwnerAddress()) {
+		invalid("owner does not match the signature")
+	}
+	if len(queries.Topic) > 0 {
+		feedID, err := feeds.Id(queries.Topic, sequence.NewIndex(queries.Index))
+		if err != nil {
+			logger.Debug("create feed update id failed", "error", err)
+			logger.Error(nil, "create feed update id failed")
+			jsonhttp.InternalServerError(w, "create feed update id failed")
+			return
+		}
+		if !bytes.Equal(feedID, id) {
+			invalid("identifier does not match the sequence feed update of the topic and the index")
+		}
+	}
+
+	jsonhttp.OK(w, res)
+}
@@ -6,19 +6,28 @@ package api_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"fmt"
 	"io"
 	"math/big"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/dnslink"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/factory"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/postage"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/soc"
 	testingsoc "github.com/ethersphere/bee/pkg/soc/testing"
 	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/storage/mock"
@@ -149,3 +158,165 @@ func TestSOC(t *testing.T) {
 		})
 	})
 }
+
+func TestSOCVerify(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: mock.NewStorer(),
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Post:   mockpost.New(),
+	})
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	topic := []byte("topic")
+	id, err := feeds.Id(topic, sequence.NewIndex(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped, err := cac.New([]byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, err := soc.New(id, wrapped).Sign(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := ch.Data()
+
+	valid := api.SocVerifyResponse{
+		Valid:            true,
+		Address:          ch.Address(),
+		Owner:            hex.EncodeToString(owner.Bytes()),
+		Identifier:       hex.EncodeToString(id),
+		Signature:        hex.EncodeToString(data[swarm.HashSize : swarm.HashSize+swarm.SocSignatureSize]),
+		WrappedReference: wrapped.Address(),
+		WrappedSpan:      3,
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		query := fmt.Sprintf("?address=%s&owner=%x&topic=%x&index=3", ch.Address(), owner.Bytes(), topic)
+		jsonhttptest.Request(t, client, http.MethodPost, "/soc/verify"+query, http.StatusOK,
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+			jsonhttptest.WithExpectedJSONResponse(valid),
+		)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		other := "8d3766440f0d7b949a5e32995d09619a7f86e632"
+		want := valid
+		want.Valid = false
+		want.Reasons = []string{
+			"address does not match the owner and the identifier",
+			"owner does not match the signature",
+			"identifier does not match the sequence feed update of the topic and the index",
+		}
+		query := fmt.Sprintf("?address=%s&owner=%s&topic=%x&index=4", wrapped.Address(), other, topic)
+		jsonhttptest.Request(t, client, http.MethodPost, "/soc/verify"+query, http.StatusOK,
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+			jsonhttptest.WithExpectedJSONResponse(want),
+		)
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		t.Parallel()
+
+		tampered := append([]byte(nil), data...)
+		tampered[len(tampered)-1] ^= 0xff
+
+		var res api.SocVerifyResponse
+		jsonhttptest.Request(t, client, http.MethodPost, fmt.Sprintf("/soc/verify?owner=%x", owner.Bytes()), http.StatusOK,
+			jsonhttptest.WithRequestBody(bytes.NewReader(tampered)),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if res.Valid || res.Owner == valid.Owner {
+			t.Fatalf("got valid %v with owner %s, want invalid with other owner", res.Valid, res.Owner)
+		}
+	})
+
+	t.Run("short data", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/soc/verify", http.StatusBadRequest,
+			jsonhttptest.WithRequestBody(bytes.NewReader(data[:swarm.SocMinChunkSize-1])),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "short chunk data",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
+
+type dnsLinkProvider chan string
+
+func (p dnsLinkProvider) SetTXT(_ context.Context, name, value string) error {
+	p <- name + " " + value
+	return nil
+}
+
+func TestSOCDNSLink(t *testing.T) {
+	t.Parallel()
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic := []byte("website")
+
+	storer := mock.NewStorer()
+	provider := make(dnsLinkProvider, 1)
+	hook := dnslink.NewHook(provider, dnslink.FeedResolver(factory.New(storer)), []dnslink.Binding{
+		{Owner: owner, Topic: topic, Domain: "example.org"},
+	}, 0, log.Noop)
+	t.Cleanup(func() { _ = hook.Close() })
+	hook.Start()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:  storer,
+		Tags:    tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Post:    mockpost.New(mockpost.WithAcceptAll()),
+		DNSLink: hook,
+	})
+
+	ref := swarm.RandAddress(t)
+	ch, err := feeds.NewSignedUpdate(signer, topic, sequence.NewIndex(0), time.Now().Unix(), ref.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := ch.Data()
+	id := data[:swarm.HashSize]
+	sig := data[swarm.HashSize : swarm.HashSize+swarm.SocSignatureSize]
+
+	jsonhttptest.Request(t, client, http.MethodPost, fmt.Sprintf("/soc/%x/%x?sig=%x", owner.Bytes(), id, sig), http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader(data[swarm.HashSize+swarm.SocSignatureSize:])),
+	)
+
+	select {
+	case got := <-provider:
+		if want := "_dnslink.example.org dnslink=/swarm/" + ref.String(); got != want {
+			t.Fatalf("got record %q, want %q", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the record update")
+	}
+}
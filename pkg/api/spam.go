@@ -0,0 +1,51 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+)
+
+type spamBatchResponse struct {
+	BatchID  hexByte   `json:"batchID"`
+	Chunks   uint64    `json:"chunks"`
+	Rate     float64   `json:"rate"`
+	Skew     float64   `json:"skew"`
+	Reasons  []string  `json:"reasons"`
+	Flagged  time.Time `json:"flagged"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+type spamBatchesResponse struct {
+	Batches []spamBatchResponse `json:"batches"`
+}
+
+// spamBatchesHandler lists the batches flagged for the anomalous
+// chunk ingress into the reserve.
+func (s *Service) spamBatchesHandler(w http.ResponseWriter, _ *http.Request) {
+	if s.spam == nil {
+		jsonhttp.NotImplemented(w, "spam detection not enabled")
+		return
+	}
+
+	reports := s.spam.Flagged()
+	batches := make([]spamBatchResponse, 0, len(reports))
+	for _, r := range reports {
+		batches = append(batches, spamBatchResponse{
+			BatchID:  r.BatchID,
+			Chunks:   r.Chunks,
+			Rate:     r.Rate,
+			Skew:     r.Skew,
+			Reasons:  r.Reasons,
+			Flagged:  r.Flagged,
+			LastSeen: r.LastSeen,
+		})
+	}
+
+	jsonhttp.OK(w, spamBatchesResponse{Batches: batches})
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"encoding/hex"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+	"github.com/ethersphere/bee/pkg/spam"
+)
+
+func TestSpamBatches(t *testing.T) {
+	t.Parallel()
+
+	t.Run("flagged", func(t *testing.T) {
+		t.Parallel()
+
+		detector := spam.New(spam.Options{Window: time.Minute, MaxRate: 1})
+		id := postagetesting.MustNewID()
+		for i := 0; i < 61; i++ {
+			detector.Record(id, postagetesting.MustNewStamp().Index())
+		}
+		detector.Record(postagetesting.MustNewID(), postagetesting.MustNewStamp().Index())
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+			Spam:     detector,
+		})
+
+		// the batch IDs are hex encoded
+		var got struct {
+			Batches []struct {
+				BatchID string   `json:"batchID"`
+				Chunks  uint64   `json:"chunks"`
+				Reasons []string `json:"reasons"`
+			} `json:"batches"`
+		}
+		jsonhttptest.Request(t, ts, http.MethodGet, "/spam/batches", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&got),
+		)
+		if len(got.Batches) != 1 {
+			t.Fatalf("got %d flagged batches, want 1", len(got.Batches))
+		}
+		b := got.Batches[0]
+		if b.BatchID != hex.EncodeToString(id) {
+			t.Fatalf("got batch %x, want %x", b.BatchID, id)
+		}
+		if b.Chunks != 61 {
+			t.Fatalf("got %d chunks, want 61", b.Chunks)
+		}
+		if len(b.Reasons) != 1 || b.Reasons[0] != spam.ReasonRate {
+			t.Fatalf("got reasons %v, want %v", b.Reasons, []string{spam.ReasonRate})
+		}
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		t.Parallel()
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+		})
+
+		jsonhttptest.Request(t, ts, http.MethodGet, "/spam/batches", http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "spam detection not enabled",
+				Code:    http.StatusNotImplemented,
+			}),
+		)
+	})
+}
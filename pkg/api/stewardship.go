@@ -7,6 +7,7 @@ package api
 import (
 	"net/http"
 
+	"github.com/ethersphere/bee/pkg/steward"
 	"github.com/ethersphere/bee/pkg/swarm"
 
 	"github.com/ethersphere/bee/pkg/jsonhttp"
@@ -20,11 +21,24 @@ func (s *Service) stewardshipPutHandler(w http.ResponseWriter, r *http.Request)
 	paths := struct {
 		Address swarm.Address `map:"address,resolve" validate:"required"`
 	}{}
-	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+	if response := s.mapStructureContext(r.Context(), mux.Vars(r), &paths); response != nil {
 		response("invalid path params", logger, w)
 		return
 	}
 
+	queries := struct {
+		Async bool `map:"async"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	if queries.Async {
+		s.submitJob(logger, w, steward.JobKind, steward.JobParams{Reference: paths.Address})
+		return
+	}
+
 	err := s.steward.Reupload(r.Context(), paths.Address)
 	if err != nil {
 		logger.Debug("re-upload failed", "chunk_address", paths.Address, "error", err)
@@ -46,7 +60,7 @@ func (s *Service) stewardshipGetHandler(w http.ResponseWriter, r *http.Request)
 	paths := struct {
 		Address swarm.Address `map:"address,resolve" validate:"required"`
 	}{}
-	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+	if response := s.mapStructureContext(r.Context(), mux.Vars(r), &paths); response != nil {
 		response("invalid path params", logger, w)
 		return
 	}
@@ -5,9 +5,12 @@
 package api
 
 import (
+	"fmt"
+	"mime"
 	"net/http"
 	"strings"
 
+	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tracing"
 	"github.com/gorilla/mux"
@@ -20,7 +23,7 @@ func (s *Service) subdomainHandler(w http.ResponseWriter, r *http.Request) {
 		Subdomain swarm.Address `map:"subdomain,resolve" validate:"required"`
 		Path      string        `map:"path"`
 	}{}
-	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+	if response := s.mapStructureContext(r.Context(), mux.Vars(r), &paths); response != nil {
 		response("invalid path params", logger, w)
 		return
 	}
@@ -30,3 +33,33 @@ func (s *Service) subdomainHandler(w http.ResponseWriter, r *http.Request) {
 
 	s.serveReference(logger, paths.Subdomain, paths.Path, w, r)
 }
+
+// gatewaySubdomainOnlyHandler rejects the path-based access to the bzz
+// content if the content is served only on the subdomains of the gateway
+// domain, so that the websites can not share their origin in the browsers.
+// The bytes are still served on the gateway origin, but only as attachments,
+// see setGatewayBytesHeaders.
+func (s *Service) gatewaySubdomainOnlyHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.GatewaySubdomainOnly {
+			jsonhttp.NotFound(w, "path-based access disabled, use the subdomain gateway")
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// setGatewayBytesHeaders forces the bytes to be downloaded as an attachment
+// instead of being rendered inline on the gateway origin if the content is
+// served only on the subdomains of the gateway domain.
+func (s *Service) setGatewayBytesHeaders(h http.Header) {
+	if !s.GatewaySubdomainOnly {
+		return
+	}
+	disposition := "attachment"
+	if _, params, err := mime.ParseMediaType(h.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		disposition = fmt.Sprintf("attachment; filename=\"%s\"", params["filename"])
+	}
+	h.Set("Content-Disposition", disposition)
+	h.Set("X-Content-Type-Options", "nosniff")
+}
@@ -5,20 +5,26 @@
 package api_test
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
 	"path"
 	"testing"
 
 	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/log"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/resolver"
+	"github.com/ethersphere/bee/pkg/resolver/cidv1"
 	resolverMock "github.com/ethersphere/bee/pkg/resolver/mock"
 	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/storage/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
 )
 
 func TestSubdomains(t *testing.T) {
@@ -178,3 +184,125 @@ func TestSubdomains(t *testing.T) {
 		})
 	}
 }
+
+func TestGatewaySubdomain(t *testing.T) {
+	t.Parallel()
+
+	var (
+		index = []byte("<h1>index</h1>")
+		page  = []byte("<h1>page</h1>")
+		html  = http.Header{"Content-Type": {"text/html; charset=utf-8"}}
+	)
+	upload := func(t *testing.T, client *http.Client) swarm.Address {
+		t.Helper()
+
+		var resp api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmIndexDocumentHeader, "index.html"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestBody(tarFiles(t, []f{
+				{data: index, name: "index.html", header: html},
+				{data: page, name: "page.html", dir: "pages", header: html},
+			})),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		return resp.Reference
+	}
+	newClient := func(t *testing.T, subdomainOnly bool, reference *swarm.Address) *http.Client {
+		t.Helper()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			Storer:               mock.NewStorer(),
+			Tags:                 tags.NewTags(statestore.NewStateStore(), log.Noop),
+			Logger:               log.Noop,
+			PreventRedirect:      true,
+			Post:                 mockpost.New(mockpost.WithAcceptAll()),
+			GatewayDomain:        "bzz.example.org",
+			GatewaySubdomainOnly: subdomainOnly,
+			Resolver: resolverMock.NewResolver(
+				resolverMock.WithResolveFunc(func(name string) (swarm.Address, error) {
+					if name != "site.eth" {
+						return swarm.ZeroAddress, resolver.ErrNotFound
+					}
+					return *reference, nil
+				}),
+			),
+		})
+		return client
+	}
+	cidOf := func(t *testing.T, reference swarm.Address) string {
+		t.Helper()
+
+		mh, err := multihash.Encode(reference.Bytes(), multihash.KECCAK_256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cid.NewCidV1(cidv1.SwarmManifestCodec, mh).String()
+	}
+
+	t.Run("subdomain", func(t *testing.T) {
+		t.Parallel()
+
+		var reference swarm.Address
+		client := newClient(t, false, &reference)
+		reference = upload(t, client)
+
+		for _, name := range []string{reference.String(), cidOf(t, reference), "site.eth"} {
+			jsonhttptest.Request(t, client, http.MethodGet, "http://"+name+".bzz.example.org/", http.StatusOK,
+				jsonhttptest.WithExpectedResponse(index),
+			)
+			jsonhttptest.Request(t, client, http.MethodGet, "http://"+name+".bzz.example.org:1633/pages/page.html", http.StatusOK,
+				jsonhttptest.WithExpectedResponse(page),
+			)
+		}
+		jsonhttptest.Request(t, client, http.MethodHead, "http://site.eth.bzz.example.org/pages/page.html", http.StatusOK,
+			jsonhttptest.WithExpectedContentLength(len(page)),
+		)
+
+		// the default domain is not served
+		jsonhttptest.Request(t, client, http.MethodGet, "http://site.eth.swarm.localhost/", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("Ethereum Swarm Bee\n")),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+reference.String()+"/", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(index),
+		)
+	})
+
+	t.Run("subdomain only", func(t *testing.T) {
+		t.Parallel()
+
+		var reference swarm.Address
+		client := newClient(t, true, &reference)
+		reference = upload(t, client)
+
+		jsonhttptest.Request(t, client, http.MethodGet, "http://"+cidOf(t, reference)+".bzz.example.org/pages/page.html", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(page),
+		)
+		for _, method := range []string{http.MethodGet, http.MethodHead} {
+			jsonhttptest.Request(t, client, method, "/bzz/"+reference.String()+"/pages/page.html", http.StatusNotFound)
+		}
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+reference.String(), http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "path-based access disabled, use the subdomain gateway",
+				Code:    http.StatusNotFound,
+			}),
+		)
+
+		var resp api.BytesPostResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(page)),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		for _, method := range []string{http.MethodGet, http.MethodHead} {
+			jsonhttptest.Request(t, client, method, "/bytes/"+resp.Reference.String(), http.StatusOK,
+				jsonhttptest.WithExpectedResponseHeader("Content-Disposition", "attachment"),
+				jsonhttptest.WithExpectedResponseHeader("X-Content-Type-Options", "nosniff"),
+			)
+		}
+	})
+}
@@ -18,27 +18,36 @@ import (
 )
 
 type tagRequest struct {
-	Address swarm.Address `json:"address,omitempty"`
+	Address  swarm.Address   `json:"address,omitempty"`
+	Name     string          `json:"name,omitempty"`
+	Metadata json.RawMessage `json:"metadata,omitempty"`
 }
 
 type tagResponse struct {
-	Uid       uint32    `json:"uid"`
-	StartedAt time.Time `json:"startedAt"`
-	Total     int64     `json:"total"`
-	Processed int64     `json:"processed"`
-	Synced    int64     `json:"synced"`
+	Uid       uint32          `json:"uid"`
+	Name      string          `json:"name,omitempty"`
+	Metadata  json.RawMessage `json:"metadata,omitempty"`
+	StartedAt time.Time       `json:"startedAt"`
+	Total     int64           `json:"total"`
+	Processed int64           `json:"processed"`
+	Sent      int64           `json:"sent"`
+	Synced    int64           `json:"synced"`
 }
 
 type listTagsResponse struct {
-	Tags []tagResponse `json:"tags"`
+	Tags  []tagResponse `json:"tags"`
+	Total int           `json:"total"`
 }
 
 func newTagResponse(tag *tags.Tag) tagResponse {
 	return tagResponse{
 		Uid:       tag.Uid,
+		Name:      tag.Name,
+		Metadata:  tag.Metadata,
 		StartedAt: tag.StartedAt,
 		Total:     tag.Total,
 		Processed: tag.Stored,
+		Sent:      tag.Sent,
 		Synced:    tag.Seen + tag.Synced,
 	}
 }
@@ -68,11 +77,16 @@ func (s *Service) createTagHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	tag, err := s.tags.Create(0)
+	tag, err := s.tags.CreateNamed(0, tagr.Name, tagr.Metadata)
 	if err != nil {
 		logger.Debug("create tag failed", "error", err)
 		logger.Error(nil, "create tag failed")
-		jsonhttp.InternalServerError(w, "cannot create tag")
+		switch {
+		case errors.Is(err, tags.ErrNameTooLong), errors.Is(err, tags.ErrMetadataTooLarge):
+			jsonhttp.BadRequest(w, err)
+		default:
+			jsonhttp.InternalServerError(w, "cannot create tag")
+		}
 		return
 	}
 	w.Header().Set("Cache-Control", "no-cache, private, max-age=0")
@@ -197,9 +211,11 @@ func (s *Service) doneSplitHandler(w http.ResponseWriter, r *http.Request) {
 func (s *Service) listTagsHandler(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.WithName("get_tags").Build()
 
+	// the tags are paged in the store, the limit is not
+	// capped silently as the larger pages are rejected
 	queries := struct {
-		Offset int `map:"offset"`
-		Limit  int `map:"limit"`
+		Offset int `map:"offset" validate:"min=0"`
+		Limit  int `map:"limit" validate:"min=1,max=1000"`
 	}{
 		Limit: 100, // Default limit.
 	}
@@ -215,6 +231,13 @@ func (s *Service) listTagsHandler(w http.ResponseWriter, r *http.Request) {
 		jsonhttp.InternalServerError(w, err)
 		return
 	}
+	total, err := s.tags.Count()
+	if err != nil {
+		logger.Debug("count failed", "error", err)
+		logger.Error(nil, "count failed")
+		jsonhttp.InternalServerError(w, err)
+		return
+	}
 
 	tags := make([]tagResponse, len(tagList))
 	for i, t := range tagList {
@@ -222,6 +245,7 @@ func (s *Service) listTagsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	jsonhttp.OK(w, listTagsResponse{
-		Tags: tags,
+		Tags:  tags,
+		Total: total,
 	})
 }
@@ -5,6 +5,7 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"time"
@@ -16,15 +17,17 @@ import (
 )
 
 type debugTagResponse struct {
-	Total     int64         `json:"total"`
-	Split     int64         `json:"split"`
-	Seen      int64         `json:"seen"`
-	Stored    int64         `json:"stored"`
-	Sent      int64         `json:"sent"`
-	Synced    int64         `json:"synced"`
-	Uid       uint32        `json:"uid"`
-	Address   swarm.Address `json:"address"`
-	StartedAt time.Time     `json:"startedAt"`
+	Total     int64           `json:"total"`
+	Split     int64           `json:"split"`
+	Seen      int64           `json:"seen"`
+	Stored    int64           `json:"stored"`
+	Sent      int64           `json:"sent"`
+	Synced    int64           `json:"synced"`
+	Uid       uint32          `json:"uid"`
+	Name      string          `json:"name,omitempty"`
+	Metadata  json.RawMessage `json:"metadata,omitempty"`
+	Address   swarm.Address   `json:"address"`
+	StartedAt time.Time       `json:"startedAt"`
 }
 
 func newDebugTagResponse(tag *tags.Tag) debugTagResponse {
@@ -36,6 +39,8 @@ func newDebugTagResponse(tag *tags.Tag) debugTagResponse {
 		Sent:      tag.Sent,
 		Synced:    tag.Synced,
 		Uid:       tag.Uid,
+		Name:      tag.Name,
+		Metadata:  tag.Metadata,
 		Address:   tag.Address,
 		StartedAt: tag.StartedAt,
 	}
@@ -6,11 +6,13 @@ package api_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -73,6 +75,54 @@ func TestTags(t *testing.T) {
 		)
 	})
 
+	t.Run("create named tag", func(t *testing.T) {
+		metadata := json.RawMessage(`{"pipeline":"deploy","build":42}`)
+
+		tr := api.TagResponse{}
+		jsonhttptest.Request(t, client, http.MethodPost, tagsResource, http.StatusCreated,
+			jsonhttptest.WithJSONRequestBody(api.TagRequest{
+				Name:     "release",
+				Metadata: metadata,
+			}),
+			jsonhttptest.WithUnmarshalJSONResponse(&tr),
+		)
+		if tr.Name != "release" {
+			t.Fatalf("got tag name %q, want %q", tr.Name, "release")
+		}
+
+		got := api.TagResponse{}
+		jsonhttptest.Request(t, client, http.MethodGet, tagsWithIdResource(tr.Uid), http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&got),
+		)
+		if got.Name != "release" {
+			t.Fatalf("got tag name %q, want %q", got.Name, "release")
+		}
+		if !bytes.Equal(got.Metadata, metadata) {
+			t.Fatalf("got tag metadata %s, want %s", got.Metadata, metadata)
+		}
+	})
+
+	t.Run("create named tag invalid", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, tagsResource, http.StatusBadRequest,
+			jsonhttptest.WithJSONRequestBody(api.TagRequest{
+				Name: strings.Repeat("a", tags.MaxNameLength+1),
+			}),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: tags.ErrNameTooLong.Error(),
+				Code:    http.StatusBadRequest,
+			}),
+		)
+		jsonhttptest.Request(t, client, http.MethodPost, tagsResource, http.StatusBadRequest,
+			jsonhttptest.WithJSONRequestBody(api.TagRequest{
+				Metadata: json.RawMessage(`"` + strings.Repeat("a", tags.MaxMetadataSize) + `"`),
+			}),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: tags.ErrMetadataTooLarge.Error(),
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
 	t.Run("create tag with invalid id", func(t *testing.T) {
 		jsonhttptest.Request(t, client, http.MethodPost, chunksResource, http.StatusBadRequest,
 			jsonhttptest.WithRequestBody(bytes.NewReader(chunk.Data())),
@@ -199,11 +249,34 @@ func TestTags(t *testing.T) {
 		// check if listing returns expected tags
 		jsonhttptest.Request(t, client, http.MethodGet, tagsResource, http.StatusOK,
 			jsonhttptest.WithExpectedJSONResponse(api.ListTagsResponse{
-				Tags: expectedTags,
+				Tags:  expectedTags,
+				Total: len(expectedTags),
 			}),
 		)
 	})
 
+	t.Run("list tags pages", func(t *testing.T) {
+		var all api.ListTagsResponse
+		jsonhttptest.Request(t, client, http.MethodGet, tagsResource, http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&all),
+		)
+		if all.Total < 2 || len(all.Tags) != all.Total {
+			t.Fatalf("got %d tags of total %d", len(all.Tags), all.Total)
+		}
+
+		// the page is taken from the store, the total counts all the tags
+		var page api.ListTagsResponse
+		jsonhttptest.Request(t, client, http.MethodGet, tagsResource+"?offset=1&limit=1", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&page),
+		)
+		if page.Total != all.Total || len(page.Tags) != 1 || page.Tags[0].Uid != all.Tags[1].Uid {
+			t.Fatalf("got page %+v, want the second of %+v", page, all)
+		}
+
+		// the pages larger than the store serves are rejected, not truncated
+		jsonhttptest.Request(t, client, http.MethodGet, tagsResource+"?limit=1001", http.StatusBadRequest)
+	})
+
 	t.Run("delete non-existent tag", func(t *testing.T) {
 		// try to delete non-existent tag
 		jsonhttptest.Request(t, client, http.MethodDelete, tagsWithIdResource(uint32(333)), http.StatusNotFound,
@@ -341,6 +414,44 @@ func TestTags(t *testing.T) {
 		tagValueTest(t, uint32(tagId), 3, 3, 0, 0, 0, 3, expectedHash, client)
 	})
 
+	t.Run("named tag created by upload", func(t *testing.T) {
+		for _, tc := range []struct {
+			name     string
+			resource string
+			data     []byte
+		}{
+			{name: "bytes", resource: bytesResource, data: []byte("named bytes")},
+			{name: "chunk", resource: chunksResource, data: chunk.Data()},
+		} {
+			respHeaders := jsonhttptest.Request(t, client, http.MethodPost, tc.resource, http.StatusCreated,
+				jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+				jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+				jsonhttptest.WithRequestHeader(api.SwarmTagNameHeader, "upload-"+tc.name),
+				jsonhttptest.WithRequestBody(bytes.NewReader(tc.data)),
+			)
+			id := isTagFoundInResponse(t, respHeaders, nil)
+
+			got := api.TagResponse{}
+			jsonhttptest.Request(t, client, http.MethodGet, tagsWithIdResource(id), http.StatusOK,
+				jsonhttptest.WithUnmarshalJSONResponse(&got),
+			)
+			if got.Name != "upload-"+tc.name {
+				t.Fatalf("%s: got tag name %q, want %q", tc.name, got.Name, "upload-"+tc.name)
+			}
+		}
+
+		jsonhttptest.Request(t, client, http.MethodPost, bytesResource, http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmTagNameHeader, strings.Repeat("a", tags.MaxNameLength+1)),
+			jsonhttptest.WithRequestBody(bytes.NewReader([]byte("named bytes"))),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid tag name",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
 	t.Run("bytes tags", func(t *testing.T) {
 		// create a tag using the API
 		tr := api.TagResponse{}
@@ -0,0 +1,172 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/manifest/mantaray"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const (
+	// tarPAXContentTypeKey is the PAX record of the
+	// content type of the entries of the tar archive.
+	tarPAXContentTypeKey = "SWARM.content-type"
+	// tarPAXContentEncodingKey is the PAX record of the content coding
+	// of the entries which are stored compressed. Such entries are
+	// written as they are stored, as their decoded size is not known.
+	tarPAXContentEncodingKey = "SWARM.content-encoding"
+)
+
+// tarEntry is a file of the collection streamed as a tar archive.
+type tarEntry struct {
+	path      string
+	reference swarm.Address
+	metadata  map[string]string
+}
+
+// acceptsTar reports whether the collection is requested as a tar archive.
+func acceptsTar(r *http.Request) bool {
+	return parseAcceptEncoding(r.Header.Get("Accept"))[contentTypeTar] > 0
+}
+
+// serveTar streams all the files of the collection manifest as a tar archive,
+// preserving their paths, and their content types and codings in the PAX
+// records. The
+// manifest is not walked for the HEAD requests.
+func (s *Service) serveTar(logger log.Logger, w http.ResponseWriter, r *http.Request, address swarm.Address, ls file.LoadSaver) {
+	ctx := r.Context()
+
+	w.Header().Set("Content-Type", contentTypeTar)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.tar\"", address))
+	if r.Method == http.MethodHead {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	release, ok := s.acquireDownload(w)
+	if !ok {
+		return
+	}
+	defer release()
+
+	entries, err := tarEntries(ctx, address, ls)
+	if err != nil {
+		logger.Debug("bzz download: tar entries failed", "address", address, "error", err)
+		logger.Error(nil, "bzz download: tar entries failed")
+		w.Header().Del("Content-Disposition")
+		jsonhttp.NotFound(w, "collection not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	tw := tar.NewWriter(w)
+	for _, e := range entries {
+		if err := s.writeTarEntry(ctx, tw, e); err != nil {
+			// the status is already sent, so that the client
+			// detects the failure by the truncated archive
+			logger.Debug("bzz download: write tar entry failed", "address", address, "path", e.path, "error", err)
+			logger.Error(nil, "bzz download: write tar entry failed")
+			return
+		}
+	}
+	if err := tw.Close(); err != nil {
+		logger.Debug("bzz download: close tar failed", "address", address, "error", err)
+		logger.Error(nil, "bzz download: close tar failed")
+	}
+}
+
+// writeTarEntry writes the header and the content of the entry to the archive.
+func (s *Service) writeTarEntry(ctx context.Context, tw *tar.Writer, e tarEntry) error {
+	reader, size, err := joiner.New(ctx, s.storer, e.reference)
+	if err != nil {
+		return err
+	}
+
+	hdr := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     e.path,
+		Size:     size,
+		Mode:     0644,
+		Format:   tar.FormatPAX,
+	}
+	if v, ok := e.metadata[manifest.EntryMetadataModTimeKey]; ok {
+		if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+			hdr.ModTime = time.Unix(sec, 0)
+		}
+	}
+	if v, ok := e.metadata[manifest.EntryMetadataContentTypeKey]; ok {
+		hdr.PAXRecords = map[string]string{tarPAXContentTypeKey: v}
+	}
+	if v, ok := e.metadata[manifest.EntryMetadataContentEncodingKey]; ok {
+		if hdr.PAXRecords == nil {
+			hdr.PAXRecords = make(map[string]string)
+		}
+		hdr.PAXRecords[tarPAXContentEncodingKey] = v
+	}
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, reader)
+	return err
+}
+
+// tarEntries returns the files of the collection manifest sorted by their paths.
+func tarEntries(ctx context.Context, address swarm.Address, ls file.LoadSaver) ([]tarEntry, error) {
+	var entries []tarEntry
+	emptyAddr := swarm.NewAddress([]byte{31: 0})
+	walker := func(p []byte, node *mantaray.Node, err error) error {
+		if err != nil {
+			return err
+		}
+		if node == nil || !node.IsValueType() || len(node.Entry()) == 0 {
+			return nil
+		}
+		entry := swarm.NewAddress(node.Entry())
+		if entry.Equal(emptyAddr) {
+			// the root metadata of the collection
+			return nil
+		}
+		name := path.Clean("/" + string(p))
+		if name == manifest.RootPath {
+			return nil
+		}
+		entries = append(entries, tarEntry{
+			path:      strings.TrimPrefix(name, "/"),
+			reference: entry,
+			metadata:  node.Metadata(),
+		})
+		return nil
+	}
+
+	err := mantaray.NewNodeRef(address.Bytes()).WalkNode(ctx, []byte{}, ls, walker)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("empty collection")
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].path < entries[j].path })
+	return entries, nil
+}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 
 	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/topology"
 )
 
 func (s *Service) topologyHandler(w http.ResponseWriter, _ *http.Request) {
@@ -29,3 +30,11 @@ func (s *Service) topologyHandler(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", jsonhttp.DefaultContentTypeHeader)
 	_, _ = io.Copy(w, bytes.NewBuffer(b))
 }
+
+func (s *Service) topologyGraphHandler(w http.ResponseWriter, _ *http.Request) {
+	params := s.topologyDriver.Snapshot()
+
+	params.LightNodes = s.lightNodes.PeerInfo()
+
+	jsonhttp.OK(w, topology.NewGraph(params))
+}
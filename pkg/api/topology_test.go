@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/topology"
 )
 
 func TestTopologyOK(t *testing.T) {
@@ -24,3 +25,21 @@ func TestTopologyOK(t *testing.T) {
 		t.Error("empty response")
 	}
 }
+
+func TestTopologyGraph(t *testing.T) {
+	t.Parallel()
+
+	testServer, _, _, _ := newTestServer(t, testServerOptions{DebugAPI: true})
+
+	var graph topology.Graph
+	jsonhttptest.Request(t, testServer, http.MethodGet, "/topology/graph", http.StatusOK,
+		jsonhttptest.WithUnmarshalJSONResponse(&graph),
+	)
+
+	if len(graph.Nodes) != 1 || !graph.Nodes[0].Self {
+		t.Fatalf("want only the base node, got %+v", graph.Nodes)
+	}
+	if len(graph.Edges) != 0 {
+		t.Fatalf("want no edges, got %+v", graph.Edges)
+	}
+}
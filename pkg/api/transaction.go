@@ -43,11 +43,25 @@ type transactionInfo struct {
 
 type transactionPendingList struct {
 	PendingTransactions []transactionInfo `json:"pendingTransactions"`
+	Total               int               `json:"total"`
+	NextCursor          string            `json:"nextCursor,omitempty"`
 }
 
-func (s *Service) transactionListHandler(w http.ResponseWriter, _ *http.Request) {
+func (s *Service) transactionListHandler(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.WithName("get_transactions").Build()
 
+	list, ok := s.parseListQuery(logger, w, r)
+	if !ok {
+		return
+	}
+	queries := struct {
+		To *common.Address `map:"to"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
 	txHashes, err := s.transaction.PendingTransactions()
 	if err != nil {
 		logger.Debug("get pending transactions failed", "error", err)
@@ -56,7 +70,10 @@ func (s *Service) transactionListHandler(w http.ResponseWriter, _ *http.Request)
 		return
 	}
 
-	transactionInfos := make([]transactionInfo, 0, len(txHashes))
+	var (
+		transactionInfos []transactionInfo
+		items            []listItem
+	)
 	for _, txHash := range txHashes {
 		storedTransaction, err := s.transaction.StoredTransaction(txHash)
 		if err != nil {
@@ -65,6 +82,9 @@ func (s *Service) transactionListHandler(w http.ResponseWriter, _ *http.Request)
 			jsonhttp.InternalServerError(w, errCantGetTransaction)
 			return
 		}
+		if queries.To != nil && (storedTransaction.To == nil || *storedTransaction.To != *queries.To) {
+			continue
+		}
 
 		transactionInfos = append(transactionInfos, transactionInfo{
 			TransactionHash: txHash,
@@ -80,12 +100,30 @@ func (s *Service) transactionListHandler(w http.ResponseWriter, _ *http.Request)
 			Description:     storedTransaction.Description,
 			Value:           bigint.Wrap(storedTransaction.Value),
 		})
+		items = append(items, listItem{
+			key: txHash.String(),
+			values: map[string]listValue{
+				"nonce":   {N: int64(storedTransaction.Nonce)},
+				"created": {N: storedTransaction.Created},
+			},
+		})
+	}
 
+	page, total, next, err := paginate(items, list, maxListLimit, "nonce", "created")
+	if err != nil {
+		respondListError(logger, w, err)
+		return
 	}
 
-	jsonhttp.OK(w, transactionPendingList{
-		PendingTransactions: transactionInfos,
-	})
+	res := transactionPendingList{
+		PendingTransactions: make([]transactionInfo, 0, len(page)),
+		Total:               total,
+		NextCursor:          next,
+	}
+	for _, i := range page {
+		res.PendingTransactions = append(res.PendingTransactions, transactionInfos[i])
+	}
+	jsonhttp.OK(w, res)
 }
 
 func (s *Service) transactionDetailHandler(w http.ResponseWriter, r *http.Request) {
@@ -198,6 +198,7 @@ func TestTransactionList(t *testing.T) {
 					Value:           bigint.Wrap(storedTransactions[txHash2].Value),
 				},
 			},
+			Total: 2,
 		}),
 	)
 }
@@ -0,0 +1,99 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/transform"
+)
+
+// serveTransformed serves the content of the manifest entry derived by the
+// transformer which applies to the query parameters of the request. It
+// returns false if no transformer applies and nothing was written. The
+// content is not transformed for the HEAD requests, which are answered with
+// the headers of the cached derived content only.
+func (s *Service) serveTransformed(logger log.Logger, w http.ResponseWriter, r *http.Request, reference swarm.Address, additionalHeaders http.Header) bool {
+	var (
+		res *transform.Result
+		err error
+	)
+	if r.Method == http.MethodHead {
+		res, err = s.transform.Cached(reference, additionalHeaders.Get("Content-Type"), r.URL.Query())
+		if errors.Is(err, transform.ErrNotCached) {
+			for name, values := range additionalHeaders {
+				if name != "Content-Type" {
+					w.Header().Set(name, strings.Join(values, "; "))
+				}
+			}
+			w.WriteHeader(http.StatusOK)
+			return true
+		}
+	} else {
+		release, ok := s.acquireDownload(w)
+		if !ok {
+			return true
+		}
+		defer release()
+
+		src := func() (io.Reader, int64, error) {
+			return joiner.New(r.Context(), s.storer, reference)
+		}
+		res, err = s.transform.Transform(r.Context(), reference, additionalHeaders.Get("Content-Type"), r.URL.Query(), src)
+		if err == nil && res == nil {
+			// the content is served by the download handler, which
+			// acquires the download on its own
+			release()
+		}
+	}
+
+	switch {
+	case err == nil && res == nil:
+		return false
+	case errors.Is(err, transform.ErrInvalidParams):
+		logger.Debug("transform: invalid params", "address", reference, "error", err)
+		jsonhttp.BadRequest(w, err.Error())
+		return true
+	case errors.Is(err, transform.ErrSourceTooLarge):
+		logger.Debug("transform: source too large", "address", reference, "error", err)
+		jsonhttp.BadRequest(w, "content too large to transform")
+		return true
+	case errors.Is(err, storage.ErrNotFound):
+		logger.Debug("transform: not found", "address", reference, "error", err)
+		logger.Error(nil, "not found")
+		jsonhttp.NotFound(w, nil)
+		return true
+	case err != nil:
+		logger.Debug("transform failed", "address", reference, "error", err)
+		logger.Error(nil, "transform failed")
+		jsonhttp.InternalServerError(w, "transform failed")
+		return true
+	}
+
+	// include additional headers, the content type is of the derived content
+	for name, values := range additionalHeaders {
+		if name != "Content-Type" {
+			w.Header().Set(name, strings.Join(values, "; "))
+		}
+	}
+	w.Header().Set("Content-Type", res.ContentType)
+	w.Header().Set("ETag", fmt.Sprintf("%q", res.Key))
+	w.Header().Set("Content-Length", strconv.Itoa(len(res.Data)))
+	w.Header().Add("Access-Control-Expose-Headers", "Content-Disposition")
+	http.ServeContent(w, r, "", time.Now(), bytes.NewReader(res.Data))
+	return true
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/transform"
+)
+
+func TestBzzTransform(t *testing.T) {
+	t.Parallel()
+
+	cache, err := transform.NewDiskCache(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:    smock.NewStorer(),
+		Tags:      tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger:    log.Noop,
+		Post:      mockpost.New(mockpost.WithAcceptAll()),
+		Transform: transform.New(cache, 1<<20, transform.NewImage()),
+	})
+
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewNRGBA(image.Rect(0, 0, 64, 32))); err != nil {
+		t.Fatal(err)
+	}
+
+	var resp api.BzzUploadResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=image.png", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader(img.Bytes())),
+		jsonhttptest.WithRequestHeader("Content-Type", "image/png"),
+		jsonhttptest.WithUnmarshalJSONResponse(&resp),
+	)
+
+	t.Run("original", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+resp.Reference.String()+"/", http.StatusOK,
+			jsonhttptest.WithExpectedResponseHeader("Content-Type", "image/png"),
+			jsonhttptest.WithExpectedResponse(img.Bytes()),
+		)
+	})
+
+	t.Run("resized", func(t *testing.T) {
+		t.Parallel()
+
+		for i := 0; i < 2; i++ {
+			var body []byte
+			jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+resp.Reference.String()+"/?w=16&format=jpeg", http.StatusOK,
+				jsonhttptest.WithExpectedResponseHeader("Content-Type", "image/jpeg"),
+				jsonhttptest.WithPutResponseBody(&body),
+			)
+
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != "jpeg" || cfg.Width != 16 || cfg.Height != 8 {
+				t.Fatalf("got %s image of %dx%d, want jpeg of 16x8", format, cfg.Width, cfg.Height)
+			}
+		}
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+resp.Reference.String()+"/?format=webp", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid transform parameters: format must be one of jpeg, png and gif",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
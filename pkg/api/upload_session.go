@@ -0,0 +1,488 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// UploadOffsetHeader is the header of the size of the data appended to
+	// the upload session so far, at which the next data is appended.
+	UploadOffsetHeader = "Upload-Offset"
+
+	uploadSessionStorePrefix     = "api-upload-session-"
+	uploadSessionPartStorePrefix = "api-upload-session-part-"
+	// uploadSessionPartSize is the maximal size of the stored parts of the
+	// appended data. The data is stored part by part, so that the data
+	// received before a connection breaks is kept in the session.
+	uploadSessionPartSize = 1 << 20
+	// uploadSessionTTL is the time after the last append after which
+	// the abandoned upload sessions are deleted with their data.
+	uploadSessionTTL = 7 * 24 * time.Hour
+	// uploadSessionSweepInterval is the minimal time between the
+	// sweeps of the expired upload sessions.
+	uploadSessionSweepInterval = time.Hour
+)
+
+var errUploadSessionOffset = errors.New("upload offset mismatch")
+
+// uploadSession is the state of a resumable upload, which is persisted in
+// the state store, together with the parts of the appended data, until the
+// session is committed.
+type uploadSession struct {
+	ID          string `json:"id"`
+	BatchID     string `json:"batchID"`
+	ContentType string `json:"contentType"`
+	Name        string `json:"name,omitempty"`
+	Encrypt     bool   `json:"encrypt,omitempty"`
+	Pin         bool   `json:"pin,omitempty"`
+	Deferred    bool   `json:"deferred"`
+	Size        int64  `json:"size"`
+	Parts       int    `json:"parts"`
+	CreatedAt   int64  `json:"createdAt"`
+	UpdatedAt   int64  `json:"updatedAt,omitempty"`
+}
+
+// expired reports whether the session was not appended to for the TTL.
+func (u uploadSession) expired(now time.Time) bool {
+	last := u.UpdatedAt
+	if last < u.CreatedAt {
+		last = u.CreatedAt
+	}
+	return now.Sub(time.Unix(last, 0)) > uploadSessionTTL
+}
+
+// uploadSessionPart is a part of the data appended to the upload session.
+type uploadSessionPart []byte
+
+func (p uploadSessionPart) MarshalBinary() ([]byte, error) { return p, nil }
+
+func (p *uploadSessionPart) UnmarshalBinary(data []byte) error {
+	*p = append((*p)[:0], data...)
+	return nil
+}
+
+func uploadSessionStoreKey(id string) string {
+	return uploadSessionStorePrefix + id
+}
+
+func uploadSessionPartStoreKey(id string, part int) string {
+	return fmt.Sprintf("%s%s-%010d", uploadSessionPartStorePrefix, id, part)
+}
+
+type uploadSessionResponse struct {
+	ID     string `json:"id"`
+	Offset int64  `json:"offset"`
+}
+
+// uploadSessionCreateHandler creates the upload session with the parameters
+// of the upload, which are given as for the file uploads to the bzz endpoint.
+func (s *Service) uploadSessionCreateHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("post_bzz_session").Build())
+
+	headers := struct {
+		ContentType string `map:"Content-Type,mimeMediaType" validate:"required"`
+		BatchID     []byte `map:"Swarm-Postage-Batch-Id" validate:"required,len=32"`
+		Encrypt     bool   `map:"Swarm-Encrypt"`
+		Pin         bool   `map:"Swarm-Pin"`
+		Deferred    *bool  `map:"Swarm-Deferred-Upload"`
+	}{}
+	if response := s.mapStructure(r.Header, &headers); response != nil {
+		response("invalid header params", logger, w)
+		return
+	}
+	queries := struct {
+		FileName string `map:"name" validate:"startsnotwith=/"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		logger.Debug("generate session id failed", "error", err)
+		logger.Error(nil, "generate session id failed")
+		jsonhttp.InternalServerError(w, "create upload session failed")
+		return
+	}
+
+	session := uploadSession{
+		ID:          hex.EncodeToString(id),
+		BatchID:     hex.EncodeToString(headers.BatchID),
+		ContentType: headers.ContentType,
+		Name:        queries.FileName,
+		Encrypt:     headers.Encrypt,
+		Pin:         headers.Pin,
+		Deferred:    headers.Deferred == nil || *headers.Deferred,
+		CreatedAt:   time.Now().Unix(),
+	}
+	s.sweepUploadSessions(logger)
+
+	if err := s.stateStore.Put(uploadSessionStoreKey(session.ID), session); err != nil {
+		logger.Debug("store upload session failed", "error", err)
+		logger.Error(nil, "store upload session failed")
+		jsonhttp.InternalServerError(w, "create upload session failed")
+		return
+	}
+
+	w.Header().Set(UploadOffsetHeader, "0")
+	w.Header().Add("Access-Control-Expose-Headers", UploadOffsetHeader)
+	jsonhttp.Created(w, uploadSessionResponse{ID: session.ID})
+}
+
+// uploadSessionGetHandler returns the offset of the upload session,
+// at which the interrupted upload is resumed.
+func (s *Service) uploadSessionGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_bzz_session").Build()
+
+	id, ok := s.uploadSessionID(logger, w, r)
+	if !ok {
+		return
+	}
+	session, ok := s.loadUploadSession(logger, w, id)
+	if !ok {
+		return
+	}
+
+	w.Header().Set(UploadOffsetHeader, strconv.FormatInt(session.Size, 10))
+	w.Header().Add("Access-Control-Expose-Headers", UploadOffsetHeader)
+	jsonhttp.OK(w, uploadSessionResponse{ID: session.ID, Offset: session.Size})
+}
+
+// uploadSessionAppendHandler appends the request body to the data of the
+// upload session. The Upload-Offset header has to match the size of the
+// data appended so far, so that no data is appended twice or skipped.
+func (s *Service) uploadSessionAppendHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("patch_bzz_session").Build())
+
+	headers := struct {
+		Offset *int64 `map:"Upload-Offset" validate:"required"`
+	}{}
+	if response := s.mapStructure(r.Header, &headers); response != nil {
+		response("invalid header params", logger, w)
+		return
+	}
+
+	// the session is loaded once it is acquired, so that
+	// it is not changed by the other requests meanwhile
+	id, ok := s.uploadSessionID(logger, w, r)
+	if !ok {
+		return
+	}
+	if !s.acquireUploadSession(id) {
+		jsonhttp.Conflict(w, "upload session is in use")
+		return
+	}
+	defer s.releaseUploadSession(id)
+
+	session, ok := s.loadUploadSession(logger, w, id)
+	if !ok {
+		return
+	}
+
+	w.Header().Add("Access-Control-Expose-Headers", UploadOffsetHeader)
+	if *headers.Offset != session.Size {
+		w.Header().Set(UploadOffsetHeader, strconv.FormatInt(session.Size, 10))
+		jsonhttp.Conflict(w, errUploadSessionOffset)
+		return
+	}
+
+	err := s.appendUploadSession(&session, r.Body)
+	w.Header().Set(UploadOffsetHeader, strconv.FormatInt(session.Size, 10))
+	if err != nil {
+		logger.Debug("append to upload session failed", "id", session.ID, "offset", session.Size, "error", err)
+		logger.Error(nil, "append to upload session failed")
+		if jsonhttp.HandleBodyReadError(err, w) {
+			return
+		}
+		jsonhttp.InternalServerError(w, "append to upload session failed")
+		return
+	}
+
+	jsonhttp.OK(w, uploadSessionResponse{ID: session.ID, Offset: session.Size})
+}
+
+// appendUploadSession stores the data as the parts of the session, the
+// session is updated after each part, so that the data read before an error
+// is kept.
+func (s *Service) appendUploadSession(session *uploadSession, r io.Reader) error {
+	for {
+		buf := make([]byte, uploadSessionPartSize)
+		n, rerr := io.ReadFull(r, buf)
+		if n > 0 {
+			if err := s.stateStore.Put(uploadSessionPartStoreKey(session.ID, session.Parts), uploadSessionPart(buf[:n])); err != nil {
+				return fmt.Errorf("store part: %w", err)
+			}
+			next := *session
+			next.Parts++
+			next.Size += int64(n)
+			next.UpdatedAt = time.Now().Unix()
+			if err := s.stateStore.Put(uploadSessionStoreKey(session.ID), next); err != nil {
+				return fmt.Errorf("store session: %w", err)
+			}
+			*session = next
+		}
+		switch {
+		case errors.Is(rerr, io.EOF), errors.Is(rerr, io.ErrUnexpectedEOF):
+			return nil
+		case rerr != nil:
+			return rerr
+		}
+	}
+}
+
+// uploadSessionCommitHandler uploads the data of the upload session as a
+// file, as if it were uploaded in the body of a single request, and deletes
+// the session once the upload succeeds.
+func (s *Service) uploadSessionCommitHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("post_bzz_session_commit").Build())
+
+	// the session is loaded once it is acquired, so that
+	// it is not changed by the other requests meanwhile
+	id, ok := s.uploadSessionID(logger, w, r)
+	if !ok {
+		return
+	}
+	if !s.acquireUploadSession(id) {
+		jsonhttp.Conflict(w, "upload session is in use")
+		return
+	}
+	defer s.releaseUploadSession(id)
+
+	session, ok := s.loadUploadSession(logger, w, id)
+	if !ok {
+		return
+	}
+
+	// the upload parameters of the session are
+	// passed to the upload as the request headers
+	ur := r.Clone(r.Context())
+	ur.Header.Set(contentTypeHeader, session.ContentType)
+	ur.Header.Set(SwarmPostageBatchIdHeader, session.BatchID)
+	ur.Header.Set(SwarmEncryptHeader, strconv.FormatBool(session.Encrypt))
+	ur.Header.Set(SwarmPinHeader, strconv.FormatBool(session.Pin))
+	ur.Header.Set(SwarmDeferredUploadHeader, strconv.FormatBool(session.Deferred))
+	ur.ContentLength = session.Size
+	query := url.Values{}
+	if session.Name != "" {
+		query.Set("name", session.Name)
+	}
+	ur.URL.RawQuery = query.Encode()
+	ur.Body = io.NopCloser(&uploadSessionReader{store: s.stateStore, session: session})
+
+	putter, wait, err := s.newStamperPutter(ur)
+	if err != nil {
+		logger.Debug("putter failed", "error", err)
+		logger.Error(nil, "putter failed")
+		switch {
+		case errors.Is(err, errBatchUnusable) || errors.Is(err, postage.ErrNotUsable):
+			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+		case errors.Is(err, postage.ErrNotFound):
+			jsonhttp.NotFound(w, "batch with id not found")
+		case errors.Is(err, errInvalidPostageBatch):
+			jsonhttp.BadRequest(w, "invalid batch id")
+		case errors.Is(err, errUnsupportedDevNodeOperation):
+			jsonhttp.BadRequest(w, errUnsupportedDevNodeOperation)
+		default:
+			jsonhttp.BadRequest(w, nil)
+		}
+		return
+	}
+
+	rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+	s.fileUploadHandler(logger, rec, ur, putter, wait)
+	if rec.status != http.StatusCreated {
+		return
+	}
+
+	if err := s.deleteUploadSession(session); err != nil {
+		logger.Debug("delete upload session failed", "id", session.ID, "error", err)
+		logger.Error(nil, "delete upload session failed")
+	}
+}
+
+// uploadSessionDeleteHandler aborts the upload session
+// and deletes the data appended to it.
+func (s *Service) uploadSessionDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("delete_bzz_session").Build()
+
+	// the session is loaded once it is acquired, so that
+	// it is not changed by the other requests meanwhile
+	id, ok := s.uploadSessionID(logger, w, r)
+	if !ok {
+		return
+	}
+	if !s.acquireUploadSession(id) {
+		jsonhttp.Conflict(w, "upload session is in use")
+		return
+	}
+	defer s.releaseUploadSession(id)
+
+	session, ok := s.loadUploadSession(logger, w, id)
+	if !ok {
+		return
+	}
+
+	if err := s.deleteUploadSession(session); err != nil {
+		logger.Debug("delete upload session failed", "id", session.ID, "error", err)
+		logger.Error(nil, "delete upload session failed")
+		jsonhttp.InternalServerError(w, "delete upload session failed")
+		return
+	}
+	jsonhttp.OK(w, nil)
+}
+
+// uploadSessionID returns the id path parameter of the upload session,
+// it responds with the error and returns false if it is invalid.
+func (s *Service) uploadSessionID(logger log.Logger, w http.ResponseWriter, r *http.Request) (string, bool) {
+	paths := struct {
+		ID string `map:"id" validate:"required,hexadecimal,len=32"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return "", false
+	}
+	return paths.ID, true
+}
+
+// loadUploadSession returns the upload session with the id, it responds
+// with the error and returns false if there is none.
+func (s *Service) loadUploadSession(logger log.Logger, w http.ResponseWriter, id string) (uploadSession, bool) {
+	var session uploadSession
+	switch err := s.stateStore.Get(uploadSessionStoreKey(id), &session); {
+	case errors.Is(err, storage.ErrNotFound):
+		jsonhttp.NotFound(w, "upload session not found")
+		return uploadSession{}, false
+	case err != nil:
+		logger.Debug("get upload session failed", "id", id, "error", err)
+		logger.Error(nil, "get upload session failed")
+		jsonhttp.InternalServerError(w, "get upload session failed")
+		return uploadSession{}, false
+	}
+	return session, true
+}
+
+// deleteUploadSession deletes the session before its parts,
+// so that no session refers to the deleted parts.
+func (s *Service) deleteUploadSession(session uploadSession) error {
+	if err := s.stateStore.Delete(uploadSessionStoreKey(session.ID)); err != nil {
+		return err
+	}
+	for i := 0; i < session.Parts; i++ {
+		if err := s.stateStore.Delete(uploadSessionPartStoreKey(session.ID, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sweepUploadSessions deletes the expired upload sessions which are not in
+// use, at most once in the sweep interval.
+func (s *Service) sweepUploadSessions(logger log.Logger) {
+	now := time.Now()
+	s.uploadSessionsMu.Lock()
+	if now.Sub(s.uploadSessionsSwept) < uploadSessionSweepInterval {
+		s.uploadSessionsMu.Unlock()
+		return
+	}
+	s.uploadSessionsSwept = now
+	s.uploadSessionsMu.Unlock()
+
+	var expired []uploadSession
+	err := s.stateStore.Iterate(uploadSessionStorePrefix, func(key, value []byte) (bool, error) {
+		if strings.HasPrefix(string(key), uploadSessionPartStorePrefix) {
+			return false, nil
+		}
+		var session uploadSession
+		if err := json.Unmarshal(value, &session); err != nil {
+			return false, fmt.Errorf("unmarshal upload session %s: %w", key, err)
+		}
+		if session.expired(now) {
+			expired = append(expired, session)
+		}
+		return false, nil
+	})
+	if err != nil {
+		logger.Debug("iterate upload sessions failed", "error", err)
+		logger.Error(nil, "iterate upload sessions failed")
+		return
+	}
+
+	for _, session := range expired {
+		if !s.acquireUploadSession(session.ID) {
+			continue
+		}
+		if err := s.deleteUploadSession(session); err != nil {
+			logger.Debug("delete expired upload session failed", "id", session.ID, "error", err)
+			logger.Error(nil, "delete expired upload session failed")
+		}
+		s.releaseUploadSession(session.ID)
+	}
+}
+
+func (s *Service) acquireUploadSession(id string) bool {
+	s.uploadSessionsMu.Lock()
+	defer s.uploadSessionsMu.Unlock()
+
+	if s.uploadSessionsInFlight == nil {
+		s.uploadSessionsInFlight = make(map[string]struct{})
+	}
+	if _, ok := s.uploadSessionsInFlight[id]; ok {
+		return false
+	}
+	s.uploadSessionsInFlight[id] = struct{}{}
+	return true
+}
+
+func (s *Service) releaseUploadSession(id string) {
+	s.uploadSessionsMu.Lock()
+	defer s.uploadSessionsMu.Unlock()
+
+	delete(s.uploadSessionsInFlight, id)
+}
+
+// uploadSessionReader reads the data of the upload session part by part.
+type uploadSessionReader struct {
+	store   storage.StateStorer
+	session uploadSession
+	part    int
+	buf     []byte
+}
+
+func (r *uploadSessionReader) Read(b []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.part >= r.session.Parts {
+			return 0, io.EOF
+		}
+		var p uploadSessionPart
+		if err := r.store.Get(uploadSessionPartStoreKey(r.session.ID, r.part), &p); err != nil {
+			return 0, fmt.Errorf("get part %d: %w", r.part, err)
+		}
+		r.buf = p
+		r.part++
+	}
+	n := copy(b, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
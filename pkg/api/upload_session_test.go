@@ -0,0 +1,174 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestUploadSession(t *testing.T) {
+	t.Parallel()
+
+	var (
+		storer     = mock.NewStorer()
+		stateStore = statestore.NewStateStore()
+		options    = testServerOptions{
+			Storer:      storer,
+			StateStorer: stateStore,
+			Tags:        tags.NewTags(statestore.NewStateStore(), log.Noop),
+			Logger:      log.Noop,
+			Post:        mockpost.New(mockpost.WithAcceptAll()),
+		}
+		data  = bytes.Repeat([]byte("swarm"), 300000)
+		split = 1<<20 + 10 // the first part spans two stored parts
+	)
+
+	client, _, _, _ := newTestServer(t, options)
+
+	var session api.UploadSessionResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz/sessions?name=swarm.txt", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader(api.ContentTypeHeader, "text/plain"),
+		jsonhttptest.WithExpectedResponseHeader(api.UploadOffsetHeader, "0"),
+		jsonhttptest.WithUnmarshalJSONResponse(&session),
+	)
+	sessionResource := "/bzz/sessions/" + session.ID
+
+	jsonhttptest.Request(t, client, http.MethodPatch, sessionResource, http.StatusOK,
+		jsonhttptest.WithRequestHeader(api.UploadOffsetHeader, "0"),
+		jsonhttptest.WithRequestBody(bytes.NewReader(data[:split])),
+		jsonhttptest.WithExpectedJSONResponse(api.UploadSessionResponse{ID: session.ID, Offset: int64(split)}),
+	)
+
+	// the data is not appended at the other offsets
+	jsonhttptest.Request(t, client, http.MethodPatch, sessionResource, http.StatusConflict,
+		jsonhttptest.WithRequestHeader(api.UploadOffsetHeader, "0"),
+		jsonhttptest.WithRequestBody(bytes.NewReader(data[split:])),
+		jsonhttptest.WithExpectedResponseHeader(api.UploadOffsetHeader, strconv.Itoa(split)),
+	)
+
+	// the session survives the restart of the node
+	client, _, _, _ = newTestServer(t, options)
+
+	jsonhttptest.Request(t, client, http.MethodGet, sessionResource, http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.UploadSessionResponse{ID: session.ID, Offset: int64(split)}),
+	)
+	jsonhttptest.Request(t, client, http.MethodPatch, sessionResource, http.StatusOK,
+		jsonhttptest.WithRequestHeader(api.UploadOffsetHeader, strconv.Itoa(split)),
+		jsonhttptest.WithRequestBody(bytes.NewReader(data[split:])),
+		jsonhttptest.WithExpectedJSONResponse(api.UploadSessionResponse{ID: session.ID, Offset: int64(len(data))}),
+	)
+
+	var upload api.BzzUploadResponse
+	jsonhttptest.Request(t, client, http.MethodPost, sessionResource+"/commit", http.StatusCreated,
+		jsonhttptest.WithUnmarshalJSONResponse(&upload),
+	)
+
+	// the committed content is the same as the content uploaded at once
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=swarm.txt", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader(api.ContentTypeHeader, "text/plain"),
+		jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+		jsonhttptest.WithExpectedJSONResponse(upload),
+	)
+	jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+upload.Reference.String()+"/", http.StatusOK,
+		jsonhttptest.WithExpectedContentLength(len(data)),
+		jsonhttptest.WithExpectedResponse(data),
+	)
+
+	jsonhttptest.Request(t, client, http.MethodGet, sessionResource, http.StatusNotFound,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "upload session not found",
+			Code:    http.StatusNotFound,
+		}),
+	)
+}
+
+func TestUploadSessionDelete(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: mock.NewStorer(),
+		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	var session api.UploadSessionResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz/sessions", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader(api.ContentTypeHeader, "text/plain"),
+		jsonhttptest.WithUnmarshalJSONResponse(&session),
+	)
+	sessionResource := "/bzz/sessions/" + session.ID
+
+	jsonhttptest.Request(t, client, http.MethodPatch, sessionResource, http.StatusOK,
+		jsonhttptest.WithRequestHeader(api.UploadOffsetHeader, "0"),
+		jsonhttptest.WithRequestBody(bytes.NewReader([]byte("swarm"))),
+	)
+	jsonhttptest.Request(t, client, http.MethodDelete, sessionResource, http.StatusOK)
+	jsonhttptest.Request(t, client, http.MethodPost, sessionResource+"/commit", http.StatusNotFound)
+}
+
+func TestUploadSessionExpiry(t *testing.T) {
+	t.Parallel()
+
+	stateStore := statestore.NewStateStore()
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:      mock.NewStorer(),
+		StateStorer: stateStore,
+		Tags:        tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Logger:      log.Noop,
+		Post:        mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	// the abandoned session was last appended to before the ttl
+	const id = "00112233445566778899aabbccddeeff"
+	old := time.Now().Add(-api.UploadSessionTTL - time.Minute).Unix()
+	if err := stateStore.Put(api.UploadSessionStoreKey(id), api.UploadSession{
+		ID:        id,
+		BatchID:   batchOkStr,
+		Size:      5,
+		Parts:     1,
+		CreatedAt: old,
+		UpdatedAt: old,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := stateStore.Put(api.UploadSessionPartStoreKey(id, 0), api.UploadSessionPart("swarm")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the expired sessions are swept when a session is created
+	var session api.UploadSessionResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz/sessions", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader(api.ContentTypeHeader, "text/plain"),
+		jsonhttptest.WithUnmarshalJSONResponse(&session),
+	)
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/bzz/sessions/"+id, http.StatusNotFound)
+	var part api.UploadSessionPart
+	if err := stateStore.Get(api.UploadSessionPartStoreKey(id, 0), &part); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+	jsonhttptest.Request(t, client, http.MethodGet, "/bzz/sessions/"+session.ID, http.StatusOK)
+}
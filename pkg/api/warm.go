@@ -0,0 +1,129 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/warmer"
+	"github.com/gorilla/mux"
+)
+
+type warmJobResponse struct {
+	ID         uint64        `json:"id"`
+	Reference  swarm.Address `json:"reference"`
+	Depth      int           `json:"depth"`
+	MaxSize    int64         `json:"maxSize"`
+	Status     string        `json:"status"`
+	Chunks     int64         `json:"chunks"`
+	Size       int64         `json:"size"`
+	Truncated  bool          `json:"truncated"`
+	Error      string        `json:"error,omitempty"`
+	StartedAt  time.Time     `json:"startedAt"`
+	FinishedAt *time.Time    `json:"finishedAt,omitempty"`
+}
+
+// warmHandler starts the job which retrieves all chunks of the
+// reference into the local store, so that the following requests
+// for the content are served without the retrieval latency.
+func (s *Service) warmHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_warm").Build()
+
+	if s.warmer == nil {
+		jsonhttp.NotImplemented(w, "warming is not available")
+		return
+	}
+
+	paths := struct {
+		Reference swarm.Address `map:"reference,resolve" validate:"required"`
+	}{}
+	if response := s.mapStructureContext(r.Context(), mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	queries := struct {
+		Depth uint   `map:"depth"`
+		Size  uint64 `map:"size"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	job, err := s.warmer.Warm(paths.Reference, warmer.Limits{
+		Depth: int(queries.Depth),
+		Size:  int64(queries.Size),
+	})
+	if err != nil {
+		if errors.Is(err, warmer.ErrTooManyJobs) {
+			logger.Debug("warm: too many jobs", "reference", paths.Reference)
+			jsonhttp.TooManyRequests(w, "too many running warm jobs")
+			return
+		}
+		logger.Debug("warm failed", "reference", paths.Reference, "error", err)
+		logger.Error(nil, "warm failed")
+		jsonhttp.InternalServerError(w, "warm failed")
+		return
+	}
+
+	jsonhttp.Accepted(w, newWarmJobResponse(job))
+}
+
+// getWarmJobHandler returns the progress of the warm job.
+func (s *Service) getWarmJobHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_warm_job").Build()
+
+	if s.warmer == nil {
+		jsonhttp.NotImplemented(w, "warming is not available")
+		return
+	}
+
+	paths := struct {
+		ID uint64 `map:"id" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	job, err := s.warmer.Job(paths.ID)
+	if err != nil {
+		if errors.Is(err, warmer.ErrJobNotFound) {
+			logger.Debug("warm job not found", "id", paths.ID)
+			jsonhttp.NotFound(w, "warm job not found")
+			return
+		}
+		logger.Debug("get warm job failed", "id", paths.ID, "error", err)
+		logger.Error(nil, "get warm job failed")
+		jsonhttp.InternalServerError(w, "get warm job failed")
+		return
+	}
+
+	jsonhttp.OK(w, newWarmJobResponse(job))
+}
+
+func newWarmJobResponse(j warmer.Job) warmJobResponse {
+	res := warmJobResponse{
+		ID:        j.ID,
+		Reference: j.Reference,
+		Depth:     j.Limits.Depth,
+		MaxSize:   j.Limits.Size,
+		Status:    string(j.Status),
+		Chunks:    j.Chunks,
+		Size:      j.Size,
+		Truncated: j.Truncated,
+		Error:     j.Error,
+		StartedAt: j.StartedAt,
+	}
+	if !j.FinishedAt.IsZero() {
+		res.FinishedAt = &j.FinishedAt
+	}
+	return res
+}
@@ -0,0 +1,127 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/jobs"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/traversal"
+	"github.com/ethersphere/bee/pkg/util/testutil"
+	"github.com/ethersphere/bee/pkg/warmer"
+)
+
+func TestWarm(t *testing.T) {
+	t.Parallel()
+
+	storer := smock.NewStorer()
+	m, err := jobs.New(statestore.NewStateStore(), log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = m.Close() })
+	w := warmer.New(storer, traversal.New(storer), m, log.Noop)
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: storer,
+		Logger: log.Noop,
+		Warmer: w,
+	})
+
+	ctx := context.Background()
+	pipe := builder.NewPipelineBuilder(ctx, storer, storage.ModePutUpload, false)
+	addr, err := builder.FeedPipeline(ctx, pipe, bytes.NewReader(testutil.RandBytes(t, 10*swarm.ChunkSize)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("warm", func(t *testing.T) {
+		t.Parallel()
+
+		var res api.WarmJobResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/warm/"+addr.String()+"?depth=2&size=1000000", http.StatusAccepted,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if !res.Reference.Equal(addr) {
+			t.Fatalf("got reference %s, want %s", res.Reference, addr)
+		}
+		if res.Depth != 2 || res.MaxSize != 1000000 {
+			t.Fatalf("got limits %d and %d, want %d and %d", res.Depth, res.MaxSize, 2, 1000000)
+		}
+
+		for deadline := time.Now().Add(5 * time.Second); res.Status == string(warmer.StatusRunning); time.Sleep(10 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatal("timed out waiting for the job")
+			}
+			jsonhttptest.Request(t, client, http.MethodGet, "/warm/jobs/"+strconv.FormatUint(res.ID, 10), http.StatusOK,
+				jsonhttptest.WithUnmarshalJSONResponse(&res),
+			)
+		}
+		if res.Status != string(warmer.StatusDone) {
+			t.Fatalf("got status %q, want %q", res.Status, warmer.StatusDone)
+		}
+		if res.Chunks == 0 || res.FinishedAt == nil {
+			t.Fatalf("unexpected finished job %+v", res)
+		}
+	})
+
+	t.Run("job not found", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/warm/jobs/1000", http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "warm job not found",
+				Code:    http.StatusNotFound,
+			}),
+		)
+	})
+
+	t.Run("invalid query params", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/warm/"+addr.String()+"?depth=-1", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusBadRequest,
+				Message: "invalid query params",
+				Reasons: []jsonhttp.Reason{
+					{
+						Field: "depth",
+						Error: strconv.ErrSyntax.Error(),
+					},
+				},
+			}),
+		)
+	})
+}
+
+func TestWarmNotAvailable(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: smock.NewStorer(),
+		Logger: log.Noop,
+	})
+
+	jsonhttptest.Request(t, client, http.MethodPost, "/warm/"+swarm.RandAddress(t).String(), http.StatusNotImplemented,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "warming is not available",
+			Code:    http.StatusNotImplemented,
+		}),
+	)
+}
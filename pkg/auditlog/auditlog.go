@@ -0,0 +1,160 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auditlog
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "auditlog"
+
+const queueSize = 1024
+
+// Record is a single audited API operation.
+type Record struct {
+	Time      time.Time `json:"time"`
+	Caller    string    `json:"caller"`
+	Method    string    `json:"method"`
+	Route     string    `json:"route"`
+	Reference string    `json:"reference,omitempty"`
+	BatchID   string    `json:"batchID,omitempty"`
+	Status    int       `json:"status"`
+	BytesIn   int64     `json:"bytesIn"`
+	BytesOut  int64     `json:"bytesOut"`
+	Duration  float64   `json:"durationSeconds"`
+}
+
+// Sink writes the records to the storage of the audit log.
+type Sink interface {
+	Write(Record) error
+	io.Closer
+}
+
+// Options are the redaction options of the audit log. The redacted
+// fields are replaced with pseudonyms, which are stable during the
+// lifetime of the logger, so that the records of the same caller
+// or reference can still be correlated.
+type Options struct {
+	RedactCaller    bool
+	RedactReference bool
+	RedactBatch     bool
+}
+
+// Logger queues the records and writes them to the sink,
+// so that the API requests are not blocked by the sink.
+type Logger struct {
+	logger  log.Logger
+	sink    Sink
+	opts    Options
+	key     []byte
+	metrics metrics
+
+	queue chan Record
+	quit  chan struct{}
+	wg    sync.WaitGroup
+	once  sync.Once
+}
+
+// New returns a new Logger which writes the records to the sink.
+func New(sink Sink, opts Options, logger log.Logger) (*Logger, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("pseudonym key: %w", err)
+	}
+
+	l := &Logger{
+		logger:  logger.WithName(loggerName).Register(),
+		sink:    sink,
+		opts:    opts,
+		key:     key,
+		metrics: newMetrics(),
+		queue:   make(chan Record, queueSize),
+		quit:    make(chan struct{}),
+	}
+
+	l.wg.Add(1)
+	go l.run()
+
+	return l, nil
+}
+
+// Log redacts the record and queues it to be written to the sink.
+// The record is dropped if the queue is full.
+func (l *Logger) Log(r Record) {
+	if l.opts.RedactCaller && r.Caller != "" {
+		r.Caller = l.pseudonym(r.Caller)
+	}
+	if l.opts.RedactReference && r.Reference != "" {
+		r.Reference = l.pseudonym(r.Reference)
+	}
+	if l.opts.RedactBatch && r.BatchID != "" {
+		r.BatchID = l.pseudonym(r.BatchID)
+	}
+
+	select {
+	case l.queue <- r:
+	case <-l.quit:
+	default:
+		l.metrics.Dropped.Inc()
+	}
+}
+
+// Close writes the queued records and closes the sink.
+func (l *Logger) Close() error {
+	l.once.Do(func() {
+		close(l.quit)
+	})
+	l.wg.Wait()
+	return l.sink.Close()
+}
+
+// pseudonym returns the keyed hash of the value, which can not be
+// reversed by enumerating the small value spaces, such as the IPv4
+// addresses, without the key.
+func (l *Logger) pseudonym(v string) string {
+	h := hmac.New(sha256.New, l.key)
+	_, _ = h.Write([]byte(v))
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+func (l *Logger) run() {
+	defer l.wg.Done()
+
+	for {
+		select {
+		case r := <-l.queue:
+			l.write(r)
+		case <-l.quit:
+			// drain the queued records
+			for {
+				select {
+				case r := <-l.queue:
+					l.write(r)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (l *Logger) write(r Record) {
+	if err := l.sink.Write(r); err != nil {
+		l.metrics.Errors.Inc()
+		l.logger.Debug("write audit record failed", "error", err)
+		return
+	}
+	l.metrics.Written.Inc()
+}
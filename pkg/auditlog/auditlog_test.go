@@ -0,0 +1,187 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auditlog_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/auditlog"
+	"github.com/ethersphere/bee/pkg/log"
+)
+
+type sink struct {
+	mtx     sync.Mutex
+	records []auditlog.Record
+}
+
+func (s *sink) Write(r auditlog.Record) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.records = append(s.records, r)
+	return nil
+}
+
+func (s *sink) Close() error { return nil }
+
+func TestLogger(t *testing.T) {
+	t.Parallel()
+
+	record := auditlog.Record{
+		Time:      time.Now(),
+		Caller:    "10.0.0.1",
+		Method:    http.MethodPost,
+		Route:     "/bzz",
+		Reference: "36b7efd913ca4cf880b8eeac5093fa27b0825906c600685b6abdd6566e6cfe8f",
+		BatchID:   "8a4e9f1d5d0e3c1a6b0e4f1e1d6c5a2a8a4e9f1d5d0e3c1a6b0e4f1e1d6c5a2a",
+		Status:    http.StatusCreated,
+		BytesIn:   42,
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		t.Parallel()
+
+		s := new(sink)
+		l, err := auditlog.New(s, auditlog.Options{}, log.Noop)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l.Log(record)
+		if err := l.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(s.records) != 1 {
+			t.Fatalf("got %d records, want 1", len(s.records))
+		}
+		if got := s.records[0]; got.Caller != record.Caller || got.Reference != record.Reference || got.BatchID != record.BatchID || got.BytesIn != record.BytesIn {
+			t.Fatalf("got record %+v, want %+v", got, record)
+		}
+	})
+
+	t.Run("redacted", func(t *testing.T) {
+		t.Parallel()
+
+		s := new(sink)
+		l, err := auditlog.New(s, auditlog.Options{RedactCaller: true, RedactReference: true}, log.Noop)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l.Log(record)
+		l.Log(record)
+		if err := l.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(s.records) != 2 {
+			t.Fatalf("got %d records, want 2", len(s.records))
+		}
+		got := s.records[0]
+		if got.Caller == record.Caller || got.Reference == record.Reference {
+			t.Fatalf("got unredacted record %+v", got)
+		}
+		if got.BatchID != record.BatchID {
+			t.Fatalf("got batch ID %s, want %s", got.BatchID, record.BatchID)
+		}
+		// the pseudonyms are stable
+		if s.records[1].Caller != got.Caller || s.records[1].Reference != got.Reference {
+			t.Fatalf("got pseudonyms %+v, want %+v", s.records[1], got)
+		}
+	})
+}
+
+func TestFileSink(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	record := auditlog.Record{Caller: "10.0.0.1", Method: http.MethodGet, Route: "/bytes/{address}", Status: http.StatusOK}
+	line, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// rotate after every second record
+	s, err := auditlog.NewFileSink(path, int64(2*(len(line)+1)), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 7; i++ {
+		if err := s.Write(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]int{
+		path:        1,
+		path + ".1": 2,
+		path + ".2": 2,
+	} {
+		if got := countLines(t, name); got != want {
+			t.Errorf("%s: got %d records, want %d", name, got, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("got backup over the limit, error %v", err)
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	t.Parallel()
+
+	gotC := make(chan auditlog.Record, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var rec auditlog.Record
+		if err := json.NewDecoder(r.Body).Decode(&rec); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		gotC <- rec
+	}))
+	defer srv.Close()
+
+	s, err := auditlog.NewSink(srv.URL, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	want := auditlog.Record{Caller: "10.0.0.1", Method: http.MethodPost, Route: "/stamps/{amount}/{depth}", Status: http.StatusCreated}
+	if err := s.Write(want); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-gotC; got.Route != want.Route || got.Caller != want.Caller || got.Status != want.Status {
+		t.Fatalf("got record %+v, want %+v", got, want)
+	}
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	n := 0
+	for sc := bufio.NewScanner(f); sc.Scan(); {
+		var r auditlog.Record
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	return n
+}
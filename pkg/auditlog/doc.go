@@ -0,0 +1,10 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package auditlog records the operations of the API, such as who
+// uploaded or downloaded which reference with how many bytes and with
+// which postage batch, to a rotating file or an external HTTP sink.
+// The personally identifiable information can be redacted from the
+// records by replacing it with pseudonyms.
+package auditlog
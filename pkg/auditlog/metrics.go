@@ -0,0 +1,45 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auditlog
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	Written prometheus.Counter // number of records written to the sink
+	Dropped prometheus.Counter // number of records dropped as the queue was full
+	Errors  prometheus.Counter // number of records the sink failed to write
+}
+
+func newMetrics() metrics {
+	subsystem := "auditlog"
+
+	return metrics{
+		Written: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "records_written",
+			Help:      "Number of audit records written to the sink.",
+		}),
+		Dropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "records_dropped",
+			Help:      "Number of audit records dropped as the queue was full.",
+		}),
+		Errors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "write_errors",
+			Help:      "Number of audit records the sink failed to write.",
+		}),
+	}
+}
+
+func (l *Logger) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(l.metrics)
+}
@@ -0,0 +1,164 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auditlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const httpSinkTimeout = 10 * time.Second
+
+var (
+	_ Sink = (*FileSink)(nil)
+	_ Sink = (*HTTPSink)(nil)
+)
+
+// NewSink returns the HTTP sink if the target is an HTTP URL
+// and the rotating file sink with the target path otherwise.
+func NewSink(target string, maxSize int64, maxBackups int) (Sink, error) {
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+		return NewHTTPSink(target), nil
+	}
+	return NewFileSink(target, maxSize, maxBackups)
+}
+
+// FileSink writes the records as JSON lines to a file. When the size of
+// the file would exceed the maximal size, the file is rotated: it is renamed
+// with the .1 suffix, the older backups are shifted and the oldest
+// backup over the maximal number of backups is removed.
+type FileSink struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	file *os.File
+	size int64
+}
+
+// NewFileSink opens the file for appending the records. The file is not
+// rotated if maxSize is zero.
+func NewFileSink(path string, maxSize int64, maxBackups int) (*FileSink, error) {
+	s := &FileSink{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Write implements Sink.Write method.
+func (s *FileSink) Write(r Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(b)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return fmt.Errorf("rotate: %w", err)
+		}
+	}
+
+	n, err := s.file.Write(b)
+	s.size += int64(n)
+	return err
+}
+
+// Close implements Sink.Close method.
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+func (s *FileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	s.file = f
+	s.size = fi.Size()
+	return nil
+}
+
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+
+	backup := func(i int) string {
+		return fmt.Sprintf("%s.%d", s.path, i)
+	}
+	if s.maxBackups > 0 {
+		if err := os.Remove(backup(s.maxBackups)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		for i := s.maxBackups - 1; i > 0; i-- {
+			if err := os.Rename(backup(i), backup(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+		if err := os.Rename(s.path, backup(1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(s.path); err != nil {
+		return err
+	}
+
+	return s.open()
+}
+
+// HTTPSink posts each record as JSON to the URL of an external collector.
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPSink returns a new HTTPSink.
+func NewHTTPSink(url string) *HTTPSink {
+	return &HTTPSink{
+		url:    url,
+		client: &http.Client{Timeout: httpSinkTimeout},
+	}
+}
+
+// Write implements Sink.Write method.
+func (s *HTTPSink) Write(r Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.client.Post(s.url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
+
+// Close implements Sink.Close method.
+func (s *HTTPSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
@@ -244,13 +244,19 @@ func applyPolicies(e *casbin.Enforcer) error {
 	_, err := e.AddPolicies([][]string{
 		{"consumer", "/bytes/*", "GET"},
 		{"creator", "/bytes", "POST"},
+		{"consumer", "/hash", "POST"},
 		{"consumer", "/chunks/*", "GET"},
 		{"creator", "/chunks", "POST"},
+		{"consumer", "/chunks/batch", "POST"},
 		{"consumer", "/bzz/*", "GET"},
 		{"creator", "/bzz/*", "PATCH"},
 		{"creator", "/bzz", "POST"},
 		{"creator", "/bzz?*", "POST"},
+		{"creator", "/bzz/sessions", "POST"},
+		{"creator", "/bzz/sessions?*", "POST"},
+		{"creator", "/bzz/sessions/*", "(POST)|(DELETE)"},
 		{"consumer", "/bzz/*/*", "GET"},
+		{"consumer", "/manifests/*", "GET"},
 		{"creator", "/tags", "GET"},
 		{"creator", "/tags?*", "GET"},
 		{"creator", "/tags", "POST"},
@@ -259,14 +265,27 @@ func applyPolicies(e *casbin.Enforcer) error {
 		{"maintainer", "/pins", "GET"},
 		{"creator", "/pss/send/*", "POST"},
 		{"consumer", "/pss/subscribe/*", "GET"},
+		{"creator", "/pss/sessions", "(GET)|(POST)"},
+		{"creator", "/pss/sessions/*", "(GET)|(POST)|(DELETE)"},
+		{"creator", "/broadcast/*", "POST"},
+		{"consumer", "/broadcast/subscribe/*", "GET"},
+		{"creator", "/ipfs/*", "POST"},
+		{"creator", "/legacy/*", "POST"},
+		{"creator", "/act/grantees/*", "(GET)|(PATCH)"},
+		{"consumer", "/challenge", "GET"},
+		{"consumer", "/challenge", "POST"},
 		{"creator", "/soc/*/*", "POST"},
+		{"creator", "/envelope/*", "POST"},
+		{"consumer", "/soc/verify", "POST"},
 		{"creator", "/feeds/*/*", "POST"},
 		{"consumer", "/feeds/*/*", "GET"},
 		{"maintainer", "/stamps", "GET"},
 		{"maintainer", "/stamps/*", "GET"},
+		{"maintainer", "/stamps/verify", "POST"},
 		{"maintainer", "/stamps/*/*", "POST"},
 		{"maintainer", "/stamps/topup/*/*", "PATCH"},
 		{"maintainer", "/stamps/dilute/*/*", "PATCH"},
+		{"maintainer", "/ens/*/*", "POST"},
 		{"maintainer", "/stake", "(GET)|(DELETE)"},
 		{"maintainer", "/stake/*", "POST"},
 		{"maintainer", "/addresses", "GET"},
@@ -275,7 +294,12 @@ func applyPolicies(e *casbin.Enforcer) error {
 		{"maintainer", "/peers", "GET"},
 		{"maintainer", "/peers/*", "DELETE"},
 		{"maintainer", "/pingpong/*", "POST"},
+		{"maintainer", "/retrieval/*", "GET"},
 		{"maintainer", "/topology", "GET"},
+		{"maintainer", "/topology/graph", "GET"},
+		{"maintainer", "/faults", "(GET)|(DELETE)"},
+		{"maintainer", "/faults/*", "(PUT)|(DELETE)"},
+		{"maintainer", "/download-limits", "(GET)|(PUT)"},
 		{"maintainer", "/welcome-message", "(GET)|(POST)"},
 		{"maintainer", "/balances", "GET"},
 		{"maintainer", "/balances/*", "GET"},
@@ -293,6 +317,15 @@ func applyPolicies(e *casbin.Enforcer) error {
 		{"maintainer", "/wallet", "GET"},
 		{"maintainer", "/chunks/*", "(GET)|(DELETE)"},
 		{"maintainer", "/reservestate", "GET"},
+		{"maintainer", "/reserve/*", "DELETE"},
+		{"maintainer", "/spam/batches", "GET"},
+		{"maintainer", "/depthmonitor", "GET"},
+		{"maintainer", "/replica", "GET"},
+		{"maintainer", "/replica/state", "GET"},
+		{"maintainer", "/replica/promote", "POST"},
+		{"maintainer", "/maintenance", "GET"},
+		{"maintainer", "/events", "GET"},
+		{"maintainer", "/graphql", "POST"},
 		{"maintainer", "/chainstate", "GET"},
 		{"maintainer", "/settlements/*", "GET"},
 		{"maintainer", "/settlements", "GET"},
@@ -302,9 +335,19 @@ func applyPolicies(e *casbin.Enforcer) error {
 		{"consumer", "/consumed", "GET"},
 		{"consumer", "/consumed/*", "GET"},
 		{"consumer", "/chunks/stream", "GET"},
+		{"consumer", "/chunks/stream/download", "GET"},
 		{"creator", "/stewardship/*", "GET"},
 		{"consumer", "/stewardship/*", "PUT"},
+		{"creator", "/warm/*", "POST"},
+		{"creator", "/warm/jobs/*", "GET"},
+		{"creator", "/jobs", "GET"},
+		{"creator", "/jobs/*", "(GET)|(DELETE)"},
+		{"creator", "/deploys/*", "(GET)|(POST)"},
+		{"creator", "/crdt/*", "(GET)|(POST)"},
+		{"creator", "/aliases", "GET"},
+		{"creator", "/aliases/*", "(GET)|(PUT)|(DELETE)"},
 		{"maintainer", "/redistributionstate", "GET"},
+		{"maintainer", "/debug/analytics", "GET"},
 	})
 
 	if err != nil {
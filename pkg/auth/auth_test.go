@@ -109,6 +109,20 @@ func TestEnforce(t *testing.T) {
 			action:   "POST",
 			expected: true,
 		},
+		{
+			desc:     "upload session commit",
+			role:     "creator",
+			resource: "/bzz/sessions/00112233445566778899aabbccddeeff/commit",
+			action:   "POST",
+			expected: true,
+		},
+		{
+			desc:     "upload session delete",
+			role:     "creator",
+			resource: "/bzz/sessions/00112233445566778899aabbccddeeff",
+			action:   "DELETE",
+			expected: true,
+		},
 		{
 			desc:     "bad role",
 			role:     "consumer",
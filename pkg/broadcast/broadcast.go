@@ -0,0 +1,376 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package broadcast provides the protocol which floods small messages to
+// the peers within the neighborhood depth of the node, for the coordination
+// of the nodes of a neighborhood, like local announcements and diagnostics.
+//
+// Every message carries a random id and a time to live, which is the number
+// of hops the message is forwarded. A node delivers a message to its local
+// handlers and forwards it to its own neighbors only the first time it sees
+// the id, so the message reaches the neighbors which are not connected with
+// the origin directly while every node processes it once.
+package broadcast
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/broadcast/pb"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/protobuf"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "broadcast"
+
+const (
+	protocolName    = "broadcast"
+	protocolVersion = "1.0.0"
+	streamName      = "broadcast"
+)
+
+const (
+	// IDSize is the size of the message id.
+	IDSize = 32
+	// MaxPayloadSize is the maximum size of the payload of a message.
+	MaxPayloadSize = 4096
+	// MaxTTL is the maximum number of hops a message is forwarded.
+	MaxTTL = 8
+	// DefaultTTL is the number of hops which reaches the neighbors
+	// connected with the neighbors of the origin.
+	DefaultTTL = 2
+
+	// seenCacheSize is the number of the recently seen message ids.
+	seenCacheSize = 10000
+	sendTimeout   = 5 * time.Second
+)
+
+var (
+	// ErrPayloadTooLarge is returned when the payload exceeds MaxPayloadSize.
+	ErrPayloadTooLarge = errors.New("payload too large")
+	// ErrInvalidTTL is returned when the time to live is zero or exceeds MaxTTL.
+	ErrInvalidTTL = errors.New("invalid ttl")
+	// ErrNoNeighbors is returned when the message is not sent to any neighbor.
+	ErrNoNeighbors = errors.New("no connected neighbors")
+
+	errInvalidMessage = errors.New("invalid message")
+)
+
+// Topic is the topic of the messages.
+type Topic [32]byte
+
+// NewTopic creates a new Topic from an input string by taking its hash.
+func NewTopic(text string) Topic {
+	bytes, _ := crypto.LegacyKeccak256([]byte(text))
+	var topic Topic
+	copy(topic[:], bytes[:32])
+	return topic
+}
+
+// ID is the id of a message.
+type ID [IDSize]byte
+
+func (id ID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// Message is a message received from the neighborhood.
+type Message struct {
+	ID      ID
+	Origin  swarm.Address // overlay of the node which broadcast the message, as claimed by the node
+	Peer    swarm.Address // overlay of the neighbor which delivered the message
+	Payload []byte
+}
+
+// Handler handles the messages of a topic.
+type Handler func(context.Context, Message)
+
+// Topology provides the connected peers and the neighborhood depth.
+type Topology interface {
+	topology.PeerIterator
+	topology.NeighborhoodDepther
+}
+
+type Service struct {
+	streamer   p2p.Streamer
+	topology   Topology
+	overlay    swarm.Address
+	logger     log.Logger
+	metrics    metrics
+	seen       *lru.Cache // ids of the recently seen messages
+	handlersMu sync.Mutex
+	handlers   map[Topic][]*Handler
+	quit       chan struct{}
+	wg         sync.WaitGroup
+}
+
+func New(streamer p2p.Streamer, topologyDriver Topology, overlay swarm.Address, logger log.Logger) (*Service, error) {
+	seen, err := lru.New(seenCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		streamer: streamer,
+		topology: topologyDriver,
+		overlay:  overlay,
+		logger:   logger.WithName(loggerName).Register(),
+		metrics:  newMetrics(),
+		seen:     seen,
+		handlers: make(map[Topic][]*Handler),
+		quit:     make(chan struct{}),
+	}, nil
+}
+
+func (s *Service) Protocol() p2p.ProtocolSpec {
+	return p2p.ProtocolSpec{
+		Name:    protocolName,
+		Version: protocolVersion,
+		StreamSpecs: []p2p.StreamSpec{
+			{
+				Name:    streamName,
+				Handler: s.handler,
+			},
+		},
+	}
+}
+
+// Broadcast sends the payload to the connected neighbors, which forward it to
+// their neighbors until the ttl number of hops is reached. It returns the id
+// of the message and the number of the neighbors it was sent to.
+func (s *Service) Broadcast(ctx context.Context, topic Topic, payload []byte, ttl uint8) (ID, int, error) {
+	var id ID
+	if len(payload) > MaxPayloadSize {
+		return id, 0, ErrPayloadTooLarge
+	}
+	if ttl == 0 || ttl > MaxTTL {
+		return id, 0, ErrInvalidTTL
+	}
+	if _, err := rand.Read(id[:]); err != nil {
+		return id, 0, err
+	}
+	s.seen.Add(id, nil)
+
+	msg := &pb.Message{
+		ID:      id[:],
+		Origin:  s.overlay.Bytes(),
+		Topic:   topic[:],
+		TTL:     uint32(ttl),
+		Payload: payload,
+	}
+	sent, err := s.forward(ctx, msg)
+	if err != nil {
+		return id, 0, err
+	}
+	s.metrics.Broadcast.Inc()
+	if sent == 0 {
+		return id, 0, ErrNoNeighbors
+	}
+	return id, sent, nil
+}
+
+// Register registers the handler of the messages of the topic.
+func (s *Service) Register(topic Topic, handler Handler) (cleanup func()) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+
+	s.handlers[topic] = append(s.handlers[topic], &handler)
+
+	return func() {
+		s.handlersMu.Lock()
+		defer s.handlersMu.Unlock()
+
+		h := s.handlers[topic]
+		for i := 0; i < len(h); i++ {
+			if h[i] == &handler {
+				s.handlers[topic] = append(h[:i], h[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (s *Service) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream) (err error) {
+	loggerV1 := s.logger.V(1).Register()
+
+	r := protobuf.NewReader(stream)
+	defer func() {
+		if err != nil {
+			_ = stream.Reset()
+		} else {
+			_ = stream.FullClose()
+		}
+	}()
+
+	var msg pb.Message
+	if err := r.ReadMsgWithContext(ctx, &msg); err != nil {
+		return fmt.Errorf("read message from peer %v: %w", p.Address, err)
+	}
+	if len(msg.ID) != IDSize || len(msg.Origin) != swarm.HashSize || len(msg.Topic) != len(Topic{}) ||
+		len(msg.Payload) > MaxPayloadSize || msg.TTL == 0 || msg.TTL > MaxTTL {
+		s.metrics.Invalid.Inc()
+		return fmt.Errorf("message from peer %v: %w", p.Address, errInvalidMessage)
+	}
+
+	// only the neighbors take part in the broadcast of the neighborhood
+	if swarm.Proximity(s.overlay.Bytes(), p.Address.Bytes()) < s.topology.NeighborhoodDepth() {
+		s.metrics.NotNeighbor.Inc()
+		loggerV1.Debug("message from peer outside of the neighborhood ignored", "peer_address", p.Address)
+		return nil
+	}
+
+	var id ID
+	copy(id[:], msg.ID)
+	if ok, _ := s.seen.ContainsOrAdd(id, nil); ok {
+		s.metrics.Duplicate.Inc()
+		return nil
+	}
+	s.metrics.Received.Inc()
+
+	var topic Topic
+	copy(topic[:], msg.Topic)
+	s.deliver(topic, Message{
+		ID:      id,
+		Origin:  swarm.NewAddress(msg.Origin),
+		Peer:    p.Address,
+		Payload: msg.Payload,
+	})
+
+	if msg.TTL == 1 {
+		return nil
+	}
+	msg.TTL--
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ctx, cancel := s.context()
+		defer cancel()
+
+		if _, err := s.forward(ctx, &msg, p.Address); err != nil {
+			loggerV1.Debug("forward message failed", "message_id", id, "error", err)
+		}
+	}()
+	return nil
+}
+
+// deliver calls the handlers of the topic with the message.
+func (s *Service) deliver(topic Topic, msg Message) {
+	s.handlersMu.Lock()
+	handlers := make([]Handler, 0, len(s.handlers[topic]))
+	for _, h := range s.handlers[topic] {
+		handlers = append(handlers, *h)
+	}
+	s.handlersMu.Unlock()
+
+	for _, h := range handlers {
+		s.wg.Add(1)
+		go func(h Handler) {
+			defer s.wg.Done()
+
+			ctx, cancel := s.context()
+			defer cancel()
+			h(ctx, msg)
+		}(h)
+	}
+}
+
+// forward sends the message to the connected neighbors, except the
+// origin and the skipped peers, and returns the number of the neighbors
+// the message was sent to.
+func (s *Service) forward(ctx context.Context, msg *pb.Message, skip ...swarm.Address) (int, error) {
+	depth := s.topology.NeighborhoodDepth()
+	origin := swarm.NewAddress(msg.Origin)
+
+	var neighbors []swarm.Address
+	err := s.topology.EachConnectedPeer(func(addr swarm.Address, po uint8) (bool, bool, error) {
+		// peers are iterated from the closest bin
+		if po < depth {
+			return true, false, nil
+		}
+		if addr.Equal(origin) || swarm.ContainsAddress(skip, addr) {
+			return false, false, nil
+		}
+		neighbors = append(neighbors, addr)
+		return false, false, nil
+	}, topology.Filter{})
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		mu   sync.Mutex
+		sent int
+		wg   sync.WaitGroup
+	)
+	for _, addr := range neighbors {
+		wg.Add(1)
+		go func(addr swarm.Address) {
+			defer wg.Done()
+
+			if err := s.send(ctx, addr, msg); err != nil {
+				s.metrics.SendErrors.Inc()
+				s.logger.V(1).Register().Debug("send message failed", "peer_address", addr, "error", err)
+				return
+			}
+			s.metrics.Sent.Inc()
+			mu.Lock()
+			sent++
+			mu.Unlock()
+		}(addr)
+	}
+	wg.Wait()
+	return sent, nil
+}
+
+func (s *Service) send(ctx context.Context, peer swarm.Address, msg *pb.Message) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	stream, err := s.streamer.NewStream(ctx, peer, nil, protocolName, protocolVersion, streamName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = stream.Reset()
+		} else {
+			_ = stream.FullClose()
+		}
+	}()
+
+	w := protobuf.NewWriter(stream)
+	return w.WriteMsgWithContext(ctx, msg)
+}
+
+// context returns a context which is cancelled when the service is closed.
+func (s *Service) context() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-s.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+func (s *Service) Close() error {
+	close(s.quit)
+	s.wg.Wait()
+	return nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package broadcast_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/broadcast"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/streamtest"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+)
+
+var topic = broadcast.NewTopic("test")
+
+// peers is the topology of a node with the connected peers.
+type peers struct {
+	base  swarm.Address
+	depth uint8
+	peers []swarm.Address
+}
+
+func (p *peers) EachConnectedPeer(f topology.EachPeerFunc, _ topology.Filter) error {
+	sorted := append([]swarm.Address(nil), p.peers...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return swarm.Proximity(p.base.Bytes(), sorted[i].Bytes()) > swarm.Proximity(p.base.Bytes(), sorted[j].Bytes())
+	})
+	for _, addr := range sorted {
+		stop, _, err := f(addr, swarm.Proximity(p.base.Bytes(), addr.Bytes()))
+		if err != nil || stop {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *peers) EachConnectedPeerRev(topology.EachPeerFunc, topology.Filter) error {
+	return errors.New("not implemented")
+}
+
+func (p *peers) NeighborhoodDepth() uint8 { return p.depth }
+
+type testNode struct {
+	svc      *broadcast.Service
+	topology *peers
+	messages chan broadcast.Message
+}
+
+// newNetwork creates the nodes with the overlays and connects them
+// as listed in the links, where all nodes have the depth.
+func newNetwork(t *testing.T, depth uint8, overlays []swarm.Address, links [][2]int) []*testNode {
+	t.Helper()
+
+	protocols := make([]map[string]p2p.ProtocolSpec, len(overlays))
+	topologies := make([]*peers, len(overlays))
+	for i, addr := range overlays {
+		protocols[i] = make(map[string]p2p.ProtocolSpec)
+		topologies[i] = &peers{base: addr, depth: depth}
+	}
+	for _, l := range links {
+		topologies[l[0]].peers = append(topologies[l[0]].peers, overlays[l[1]])
+		topologies[l[1]].peers = append(topologies[l[1]].peers, overlays[l[0]])
+	}
+
+	nodes := make([]*testNode, len(overlays))
+	for i, addr := range overlays {
+		recorder := streamtest.New(streamtest.WithBaseAddr(addr), streamtest.WithPeerProtocols(protocols[i]))
+		svc, err := broadcast.New(recorder, topologies[i], addr, log.Noop)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = svc.Close() })
+
+		n := &testNode{svc: svc, topology: topologies[i], messages: make(chan broadcast.Message, 10)}
+		svc.Register(topic, func(_ context.Context, m broadcast.Message) { n.messages <- m })
+		nodes[i] = n
+	}
+	// the streams to a peer are handled by the protocol of the peer
+	for _, l := range links {
+		protocols[l[0]][overlays[l[1]].String()] = nodes[l[1]].svc.Protocol()
+		protocols[l[1]][overlays[l[0]].String()] = nodes[l[0]].svc.Protocol()
+	}
+	return nodes
+}
+
+func (n *testNode) expect(t *testing.T, id broadcast.ID, origin, peer swarm.Address, payload []byte) {
+	t.Helper()
+
+	select {
+	case m := <-n.messages:
+		if m.ID != id || !m.Origin.Equal(origin) || !m.Peer.Equal(peer) || !bytes.Equal(m.Payload, payload) {
+			t.Fatalf("got message %+v, want id %s from origin %s through peer %s", m, id, origin, peer)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not received")
+	}
+}
+
+func (n *testNode) expectNone(t *testing.T) {
+	t.Helper()
+
+	select {
+	case m := <-n.messages:
+		t.Fatalf("got unexpected message %+v", m)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	t.Parallel()
+
+	var (
+		a = swarm.RandAddress(t)
+		b = swarm.RandAddressAt(t, a, 4)
+		c = swarm.RandAddressAt(t, b, 6)
+		d = swarm.RandAddressAt(t, a, 1)
+	)
+
+	t.Run("ttl", func(t *testing.T) {
+		t.Parallel()
+
+		// a - b - c and d outside of the neighborhood
+		nodes := newNetwork(t, 3, []swarm.Address{a, b, c, d}, [][2]int{{0, 1}, {1, 2}, {0, 3}})
+
+		payload := []byte("hello")
+		id, sent, err := nodes[0].svc.Broadcast(context.Background(), topic, payload, broadcast.DefaultTTL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sent != 1 {
+			t.Fatalf("got %d neighbors, want 1", sent)
+		}
+		nodes[1].expect(t, id, a, a, payload)
+		nodes[2].expect(t, id, a, b, payload)
+		nodes[0].expectNone(t)
+		nodes[3].expectNone(t)
+
+		// the message is not forwarded beyond its ttl
+		id, _, err = nodes[0].svc.Broadcast(context.Background(), topic, payload, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes[1].expect(t, id, a, a, payload)
+		nodes[2].expectNone(t)
+	})
+
+	t.Run("dedup", func(t *testing.T) {
+		t.Parallel()
+
+		// all nodes are connected with each other
+		nodes := newNetwork(t, 3, []swarm.Address{a, b, c}, [][2]int{{0, 1}, {1, 2}, {0, 2}})
+
+		payload := []byte("once")
+		id, sent, err := nodes[0].svc.Broadcast(context.Background(), topic, payload, broadcast.MaxTTL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sent != 2 {
+			t.Fatalf("got %d neighbors, want 2", sent)
+		}
+		nodes[1].expect(t, id, a, a, payload)
+		nodes[2].expect(t, id, a, a, payload)
+		for _, n := range nodes {
+			n.expectNone(t)
+		}
+	})
+
+	t.Run("outside of neighborhood", func(t *testing.T) {
+		t.Parallel()
+
+		// d considers a its neighbor at depth 0, but a does not
+		nodes := newNetwork(t, 3, []swarm.Address{a, d}, [][2]int{{0, 1}})
+		nodes[1].topology.depth = 0
+
+		if _, _, err := nodes[1].svc.Broadcast(context.Background(), topic, []byte("hi"), 1); err != nil {
+			t.Fatal(err)
+		}
+		nodes[0].expectNone(t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		nodes := newNetwork(t, 3, []swarm.Address{a}, nil)
+		svc := nodes[0].svc
+
+		if _, _, err := svc.Broadcast(context.Background(), topic, make([]byte, broadcast.MaxPayloadSize+1), 1); !errors.Is(err, broadcast.ErrPayloadTooLarge) {
+			t.Fatalf("got error %v, want %v", err, broadcast.ErrPayloadTooLarge)
+		}
+		for _, ttl := range []uint8{0, broadcast.MaxTTL + 1} {
+			if _, _, err := svc.Broadcast(context.Background(), topic, nil, ttl); !errors.Is(err, broadcast.ErrInvalidTTL) {
+				t.Fatalf("got error %v, want %v", err, broadcast.ErrInvalidTTL)
+			}
+		}
+		if _, _, err := svc.Broadcast(context.Background(), topic, nil, 1); !errors.Is(err, broadcast.ErrNoNeighbors) {
+			t.Fatalf("got error %v, want %v", err, broadcast.ErrNoNeighbors)
+		}
+	})
+}
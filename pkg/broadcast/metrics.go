@@ -0,0 +1,73 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package broadcast
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	Broadcast   prometheus.Counter // number of messages broadcast by the node
+	Received    prometheus.Counter // number of new messages received
+	Duplicate   prometheus.Counter // number of already seen messages received
+	Invalid     prometheus.Counter // number of invalid messages received
+	NotNeighbor prometheus.Counter // number of messages from peers outside of the neighborhood
+	Sent        prometheus.Counter // number of messages sent to the neighbors
+	SendErrors  prometheus.Counter // number of failed sends
+}
+
+func newMetrics() metrics {
+	subsystem := "broadcast"
+
+	return metrics{
+		Broadcast: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "broadcast",
+			Help:      "Total messages broadcast by the node.",
+		}),
+		Received: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "received",
+			Help:      "Total messages received from the neighbors.",
+		}),
+		Duplicate: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "duplicate",
+			Help:      "Total duplicate messages received.",
+		}),
+		Invalid: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "invalid",
+			Help:      "Total invalid messages received.",
+		}),
+		NotNeighbor: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "not_neighbor",
+			Help:      "Total messages received from peers outside of the neighborhood.",
+		}),
+		Sent: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "sent",
+			Help:      "Total messages sent to the neighbors.",
+		}),
+		SendErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "send_errors",
+			Help:      "Total errors sending messages to the neighbors.",
+		}),
+	}
+}
+
+func (s *Service) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(s.metrics)
+}
@@ -0,0 +1,128 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cgroup reads the CPU and memory limits of the control group of the
+// process, so that the node running in a container sizes its resources to the
+// limits of the container instead of the resources of the whole machine. Both
+// the cgroup v1 and v2 hierarchies are supported.
+package cgroup
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// root is the mount point of the cgroup file system.
+const root = "/sys/fs/cgroup"
+
+// unlimitedV1 is the smallest limit which cgroup v1 reports for the
+// unlimited memory, which is the largest int64 rounded down to the page size.
+const unlimitedV1 = 1 << 62
+
+// MemoryLimit returns the memory limit of the cgroup of the process in bytes,
+// or zero if the memory is not limited or the cgroup file system is not
+// available, as on the systems other than Linux.
+func MemoryLimit() (int64, error) {
+	return memoryLimit(root)
+}
+
+// CPULimit returns the number of the CPUs the cgroup of the process is limited
+// to, which can be fractional, or zero if the CPU time is not limited or the
+// cgroup file system is not available.
+func CPULimit() (float64, error) {
+	return cpuLimit(root)
+}
+
+func memoryLimit(root string) (int64, error) {
+	// cgroup v2
+	v, ok, err := readFile(filepath.Join(root, "memory.max"))
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		if v == "max" {
+			return 0, nil
+		}
+		return parseInt(v)
+	}
+
+	// cgroup v1
+	v, ok, err = readFile(filepath.Join(root, "memory", "memory.limit_in_bytes"))
+	if err != nil || !ok {
+		return 0, err
+	}
+	limit, err := parseInt(v)
+	if err != nil || limit >= unlimitedV1 {
+		return 0, err
+	}
+	return limit, nil
+}
+
+func cpuLimit(root string) (float64, error) {
+	// cgroup v2, the quota and the period in one file
+	v, ok, err := readFile(filepath.Join(root, "cpu.max"))
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		fields := strings.Fields(v)
+		if len(fields) != 2 {
+			return 0, fmt.Errorf("invalid cpu limit %q", v)
+		}
+		if fields[0] == "max" {
+			return 0, nil
+		}
+		return quota(fields[0], fields[1])
+	}
+
+	// cgroup v1
+	q, ok, err := readFile(filepath.Join(root, "cpu", "cpu.cfs_quota_us"))
+	if err != nil || !ok || q == "-1" {
+		return 0, err
+	}
+	p, ok, err := readFile(filepath.Join(root, "cpu", "cpu.cfs_period_us"))
+	if err != nil || !ok {
+		return 0, err
+	}
+	return quota(q, p)
+}
+
+// quota returns the number of the CPUs of the quota of the CPU time in the period.
+func quota(q, p string) (float64, error) {
+	quota, err := parseInt(q)
+	if err != nil {
+		return 0, err
+	}
+	period, err := parseInt(p)
+	if err != nil || period == 0 {
+		return 0, fmt.Errorf("invalid cpu period %q", p)
+	}
+	return float64(quota) / float64(period), nil
+}
+
+// readFile returns the trimmed content of the file and
+// false if the file does not exist.
+func readFile(path string) (string, bool, error) {
+	v, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return strings.TrimSpace(string(v)), true, nil
+}
+
+func parseInt(s string) (int64, error) {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid limit %q", s)
+	}
+	return v, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cgroup_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/cgroup"
+)
+
+// writeFiles writes the files of the cgroup file system to a temporary
+// directory and returns its path.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	for name, v := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(v), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestMemoryLimit(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name  string
+		files map[string]string
+		want  int64
+	}{
+		{"none", nil, 0},
+		{"v2", map[string]string{"memory.max": "536870912\n"}, 512 << 20},
+		{"v2 unlimited", map[string]string{"memory.max": "max\n"}, 0},
+		{"v1", map[string]string{"memory/memory.limit_in_bytes": "1073741824\n"}, 1 << 30},
+		{"v1 unlimited", map[string]string{"memory/memory.limit_in_bytes": "9223372036854771712\n"}, 0},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := cgroup.ReadMemoryLimit(writeFiles(t, tc.files))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Fatalf("got limit %d, want %d", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := cgroup.ReadMemoryLimit(writeFiles(t, map[string]string{"memory.max": "lots"})); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestCPULimit(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name  string
+		files map[string]string
+		want  float64
+	}{
+		{"none", nil, 0},
+		{"v2", map[string]string{"cpu.max": "150000 100000\n"}, 1.5},
+		{"v2 unlimited", map[string]string{"cpu.max": "max 100000\n"}, 0},
+		{"v1", map[string]string{"cpu/cpu.cfs_quota_us": "200000\n", "cpu/cpu.cfs_period_us": "100000\n"}, 2},
+		{"v1 unlimited", map[string]string{"cpu/cpu.cfs_quota_us": "-1\n", "cpu/cpu.cfs_period_us": "100000\n"}, 0},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := cgroup.ReadCPULimit(writeFiles(t, tc.files))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Fatalf("got limit %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := cgroup.ReadCPULimit(writeFiles(t, map[string]string{"cpu.max": "100000 0"})); err == nil {
+		t.Fatal("expected error")
+	}
+}
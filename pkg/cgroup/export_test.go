@@ -0,0 +1,10 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cgroup
+
+var (
+	ReadMemoryLimit = memoryLimit
+	ReadCPULimit    = cpuLimit
+)
@@ -0,0 +1,358 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package challenge protects the anonymous download endpoints of the public
+// gateways against scraping and denial of service with challenges.
+//
+// A client without a valid pass token is given a challenge which it redeems
+// for the token. In the proof-of-work mode the challenge is redeemed only
+// with a nonce which hashes together with the challenge to the required
+// number of leading zero bits, and in the token mode the challenge is
+// redeemed without work, so that only the clients which follow the
+// handshake are served. The requests with a token are rate limited by a
+// token bucket per token, and the tokens expire, so they rotate.
+//
+// The challenges and the tokens are authenticated with a secret, so that no
+// state is kept for them other than the redeemed challenges and the buckets.
+package challenge
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"net"
+	"strings"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// ModePoW requires the proof of work to redeem a challenge.
+	ModePoW = "pow"
+	// ModeToken redeems the challenges without work.
+	ModeToken = "token"
+)
+
+const (
+	// DefaultDifficulty is the default number of the leading zero
+	// bits of the proof of work, which takes about a second to find.
+	DefaultDifficulty = 20
+	// DefaultTokenTTL is the default time a pass token is valid.
+	DefaultTokenTTL = time.Hour
+	// DefaultRate is the default number of requests per second with a token.
+	DefaultRate = 10
+	// DefaultBurst is the default number of requests with a token at once.
+	DefaultBurst = 100
+
+	// ChallengeTTL is the time a challenge can be redeemed.
+	ChallengeTTL = 5 * time.Minute
+
+	// MaxDifficulty is the maximal number of the leading zero bits.
+	MaxDifficulty = 32
+
+	secretSize  = 32
+	nonceSize   = 16
+	macSize     = 16
+	maxSolution = 32
+	cacheSize   = 100000 // number of the tracked tokens and redeemed challenges
+
+	challengeSize = 8 + 1 + nonceSize + macSize // expiry, difficulty, nonce and mac
+	tokenSize     = 8 + nonceSize + macSize     // expiry, nonce and mac
+)
+
+var (
+	// ErrInvalidMode is returned by New for an unknown mode.
+	ErrInvalidMode = errors.New("invalid challenge mode")
+	// ErrInvalidChallenge is returned if the challenge was not
+	// issued by the service, is expired or was already redeemed.
+	ErrInvalidChallenge = errors.New("invalid challenge")
+	// ErrInsufficientWork is returned if the solution
+	// does not have the work required by the challenge.
+	ErrInsufficientWork = errors.New("insufficient work")
+	// ErrInvalidToken is returned if the token was not
+	// issued by the service or is expired.
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrRateLimited is returned if the token has no requests left.
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// Options are the options of the Service.
+type Options struct {
+	// Mode is either ModePoW or ModeToken.
+	Mode string
+	// Difficulty is the number of the leading zero bits of the proof of work.
+	Difficulty uint8
+	// TokenTTL is the time a pass token is valid.
+	TokenTTL time.Duration
+	// Rate is the number of requests per second with a token.
+	Rate float64
+	// Burst is the number of requests with a token at once.
+	Burst int
+	// Routes are the names of the protected routes.
+	Routes []string
+	// Exempt are the IP addresses and the CIDR networks of the clients
+	// which are not challenged.
+	Exempt []string
+	// Secret authenticates the challenges and the tokens. It must be shared
+	// by the API frontends of a gateway. A random secret is used if it is
+	// empty, which invalidates the tokens on restart.
+	Secret []byte
+}
+
+// Challenge is a challenge issued to a client.
+type Challenge struct {
+	Value      string
+	Difficulty uint8
+	Expires    time.Time
+}
+
+// Service issues the challenges and the pass tokens.
+type Service struct {
+	mode       string
+	difficulty uint8
+	tokenTTL   time.Duration
+	rate       rate.Limit
+	burst      int
+	routes     map[string]struct{}
+	exempt     []*net.IPNet
+	secret     []byte
+	redeemed   *lru.Cache // nonces of the redeemed challenges
+	buckets    *lru.Cache // rate limiters of the tokens by their nonces
+	metrics    metrics
+}
+
+// New returns the Service with the options.
+func New(o Options) (*Service, error) {
+	if o.Mode != ModePoW && o.Mode != ModeToken {
+		return nil, fmt.Errorf("%w %q", ErrInvalidMode, o.Mode)
+	}
+	if o.Difficulty == 0 {
+		o.Difficulty = DefaultDifficulty
+	}
+	if o.Difficulty > MaxDifficulty {
+		return nil, fmt.Errorf("difficulty %d exceeds %d", o.Difficulty, MaxDifficulty)
+	}
+	if o.Mode == ModeToken {
+		o.Difficulty = 0
+	}
+	if o.TokenTTL <= 0 {
+		o.TokenTTL = DefaultTokenTTL
+	}
+	if o.Rate <= 0 {
+		o.Rate = DefaultRate
+	}
+	if o.Burst <= 0 {
+		o.Burst = DefaultBurst
+	}
+	if len(o.Secret) == 0 {
+		o.Secret = make([]byte, secretSize)
+		if _, err := rand.Read(o.Secret); err != nil {
+			return nil, err
+		}
+	}
+
+	s := &Service{
+		mode:       o.Mode,
+		difficulty: o.Difficulty,
+		tokenTTL:   o.TokenTTL,
+		rate:       rate.Limit(o.Rate),
+		burst:      o.Burst,
+		routes:     make(map[string]struct{}),
+		secret:     o.Secret,
+		metrics:    newMetrics(),
+	}
+	for _, r := range o.Routes {
+		s.routes[r] = struct{}{}
+	}
+	for _, e := range o.Exempt {
+		if !strings.Contains(e, "/") {
+			ip := net.ParseIP(e)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid exempt address %q", e)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			e = fmt.Sprintf("%s/%d", e, bits)
+		}
+		_, n, err := net.ParseCIDR(e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exempt network %q: %w", e, err)
+		}
+		s.exempt = append(s.exempt, n)
+	}
+
+	var err error
+	if s.redeemed, err = lru.New(cacheSize); err != nil {
+		return nil, err
+	}
+	if s.buckets, err = lru.New(cacheSize); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Mode returns the mode of the service.
+func (s *Service) Mode() string {
+	return s.mode
+}
+
+// Protects reports whether the route with the name is protected.
+func (s *Service) Protects(route string) bool {
+	_, ok := s.routes[route]
+	return ok
+}
+
+// Exempt reports whether the client with the IP address is not challenged.
+func (s *Service) Exempt(ip net.IP) bool {
+	for _, n := range s.exempt {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Issue returns a new challenge.
+func (s *Service) Issue() (Challenge, error) {
+	expires := time.Now().Add(ChallengeTTL).Truncate(time.Second)
+
+	b := make([]byte, challengeSize-macSize, challengeSize)
+	binary.BigEndian.PutUint64(b, uint64(expires.Unix()))
+	b[8] = s.difficulty
+	if _, err := rand.Read(b[9:]); err != nil {
+		return Challenge{}, err
+	}
+	b = append(b, s.mac("challenge", b)...)
+
+	s.metrics.Issued.Inc()
+	return Challenge{
+		Value:      hex.EncodeToString(b),
+		Difficulty: s.difficulty,
+		Expires:    expires,
+	}, nil
+}
+
+// Redeem verifies the solution of the challenge and returns a new pass token
+// with its expiry. The solution is ignored in the token mode.
+func (s *Service) Redeem(challenge, solution string) (string, time.Time, error) {
+	b, err := hex.DecodeString(challenge)
+	if err != nil || len(b) != challengeSize {
+		s.metrics.Rejected.Inc()
+		return "", time.Time{}, ErrInvalidChallenge
+	}
+	payload, mac := b[:challengeSize-macSize], b[challengeSize-macSize:]
+	if !hmac.Equal(mac, s.mac("challenge", payload)) || time.Now().Unix() > int64(binary.BigEndian.Uint64(payload)) {
+		s.metrics.Rejected.Inc()
+		return "", time.Time{}, ErrInvalidChallenge
+	}
+
+	if difficulty := payload[8]; difficulty > 0 {
+		nonce, err := hex.DecodeString(solution)
+		if err != nil || len(nonce) > maxSolution || leadingZeros(b, nonce) < int(difficulty) {
+			s.metrics.Rejected.Inc()
+			return "", time.Time{}, ErrInsufficientWork
+		}
+	}
+
+	// every challenge is redeemed once, so that one
+	// proof of work is not redeemed for many tokens
+	if ok, _ := s.redeemed.ContainsOrAdd(string(payload[9:]), struct{}{}); ok {
+		s.metrics.Rejected.Inc()
+		return "", time.Time{}, ErrInvalidChallenge
+	}
+
+	expires := time.Now().Add(s.tokenTTL).Truncate(time.Second)
+	t := make([]byte, tokenSize-macSize, tokenSize)
+	binary.BigEndian.PutUint64(t, uint64(expires.Unix()))
+	if _, err := rand.Read(t[8:]); err != nil {
+		return "", time.Time{}, err
+	}
+	t = append(t, s.mac("token", t)...)
+
+	s.metrics.Redeemed.Inc()
+	return hex.EncodeToString(t), expires, nil
+}
+
+// Allow verifies the pass token and takes a request from its bucket.
+func (s *Service) Allow(token string) error {
+	t, err := hex.DecodeString(token)
+	if err != nil || len(t) != tokenSize {
+		s.metrics.Challenged.Inc()
+		return ErrInvalidToken
+	}
+	payload, mac := t[:tokenSize-macSize], t[tokenSize-macSize:]
+	if !hmac.Equal(mac, s.mac("token", payload)) || time.Now().Unix() > int64(binary.BigEndian.Uint64(payload)) {
+		s.metrics.Challenged.Inc()
+		return ErrInvalidToken
+	}
+
+	key := string(payload[8:])
+	v, ok := s.buckets.Get(key)
+	if !ok {
+		v = rate.NewLimiter(s.rate, s.burst)
+		if exists, _ := s.buckets.ContainsOrAdd(key, v); exists {
+			// the bucket was added concurrently
+			if existing, ok := s.buckets.Get(key); ok {
+				v = existing
+			}
+		}
+	}
+	if !v.(*rate.Limiter).Allow() {
+		s.metrics.RateLimited.Inc()
+		return ErrRateLimited
+	}
+	return nil
+}
+
+func (s *Service) mac(domain string, b []byte) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	_, _ = h.Write([]byte(domain))
+	_, _ = h.Write(b)
+	return h.Sum(nil)[:macSize]
+}
+
+// Solve finds the solution of the proof of work of the challenge.
+func Solve(ctx context.Context, challenge string, difficulty uint8) (string, error) {
+	b, err := hex.DecodeString(challenge)
+	if err != nil {
+		return "", ErrInvalidChallenge
+	}
+
+	nonce := make([]byte, 8)
+	for i := uint64(0); ; i++ {
+		if i%4096 == 0 && ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		binary.BigEndian.PutUint64(nonce, i)
+		if leadingZeros(b, nonce) >= int(difficulty) {
+			return hex.EncodeToString(nonce), nil
+		}
+	}
+}
+
+// leadingZeros returns the number of the leading zero
+// bits of the hash of the challenge and the nonce.
+func leadingZeros(challenge, nonce []byte) int {
+	h := sha256.New()
+	_, _ = h.Write(challenge)
+	_, _ = h.Write(nonce)
+	n := 0
+	for _, b := range h.Sum(nil) {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+		n += 8
+	}
+	return n
+}
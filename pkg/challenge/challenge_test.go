@@ -0,0 +1,172 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package challenge_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/challenge"
+)
+
+func newService(t *testing.T, o challenge.Options) *challenge.Service {
+	t.Helper()
+
+	s, err := challenge.New(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestProofOfWork(t *testing.T) {
+	t.Parallel()
+
+	s := newService(t, challenge.Options{Mode: challenge.ModePoW, Difficulty: 8})
+
+	c, err := s.Issue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Difficulty != 8 {
+		t.Fatalf("got difficulty %d, want %d", c.Difficulty, 8)
+	}
+
+	if _, _, err := s.Redeem(c.Value, "00"); !errors.Is(err, challenge.ErrInsufficientWork) {
+		t.Fatalf("got error %v, want %v", err, challenge.ErrInsufficientWork)
+	}
+
+	solution, err := challenge.Solve(context.Background(), c.Value, c.Difficulty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _, err := s.Redeem(c.Value, solution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Allow(token); err != nil {
+		t.Fatal(err)
+	}
+
+	// the same work is not redeemed twice
+	if _, _, err := s.Redeem(c.Value, solution); !errors.Is(err, challenge.ErrInvalidChallenge) {
+		t.Fatalf("got error %v, want %v", err, challenge.ErrInvalidChallenge)
+	}
+}
+
+func TestToken(t *testing.T) {
+	t.Parallel()
+
+	s := newService(t, challenge.Options{Mode: challenge.ModeToken})
+
+	c, err := s.Issue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Difficulty != 0 {
+		t.Fatalf("got difficulty %d, want %d", c.Difficulty, 0)
+	}
+	token, _, err := s.Redeem(c.Value, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Allow(token); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	t.Parallel()
+
+	s := newService(t, challenge.Options{Mode: challenge.ModeToken})
+	other := newService(t, challenge.Options{Mode: challenge.ModeToken})
+
+	c, err := other.Issue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.Redeem(c.Value, ""); !errors.Is(err, challenge.ErrInvalidChallenge) {
+		t.Fatalf("got error %v, want %v", err, challenge.ErrInvalidChallenge)
+	}
+	if _, _, err := s.Redeem("not-a-challenge", ""); !errors.Is(err, challenge.ErrInvalidChallenge) {
+		t.Fatalf("got error %v, want %v", err, challenge.ErrInvalidChallenge)
+	}
+
+	token, _, err := other.Redeem(c.Value, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Allow(token); !errors.Is(err, challenge.ErrInvalidToken) {
+		t.Fatalf("got error %v, want %v", err, challenge.ErrInvalidToken)
+	}
+	// a challenge is not a token
+	if err := other.Allow(c.Value); !errors.Is(err, challenge.ErrInvalidToken) {
+		t.Fatalf("got error %v, want %v", err, challenge.ErrInvalidToken)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	s := newService(t, challenge.Options{Mode: challenge.ModeToken, Rate: 0.001, Burst: 2})
+
+	c, err := s.Issue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _, err := s.Redeem(c.Value, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := s.Allow(token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Allow(token); !errors.Is(err, challenge.ErrRateLimited) {
+		t.Fatalf("got error %v, want %v", err, challenge.ErrRateLimited)
+	}
+}
+
+func TestRoutesAndExempt(t *testing.T) {
+	t.Parallel()
+
+	s := newService(t, challenge.Options{
+		Mode:   challenge.ModeToken,
+		Routes: []string{"bytes", "bzz"},
+		Exempt: []string{"10.0.0.0/8", "192.168.1.1", "::1"},
+	})
+
+	if !s.Protects("bzz") || s.Protects("chunks") {
+		t.Fatal("unexpected protected routes")
+	}
+	for ip, want := range map[string]bool{
+		"10.1.2.3":    true,
+		"192.168.1.1": true,
+		"192.168.1.2": false,
+		"::1":         true,
+		"8.8.8.8":     false,
+	} {
+		if got := s.Exempt(net.ParseIP(ip)); got != want {
+			t.Fatalf("ip %s: got exempt %v, want %v", ip, got, want)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	if _, err := challenge.New(challenge.Options{Mode: "captcha"}); !errors.Is(err, challenge.ErrInvalidMode) {
+		t.Fatalf("got error %v, want %v", err, challenge.ErrInvalidMode)
+	}
+	if _, err := challenge.New(challenge.Options{Mode: challenge.ModePoW, Difficulty: challenge.MaxDifficulty + 1}); err == nil {
+		t.Fatal("expected error for difficulty")
+	}
+	if _, err := challenge.New(challenge.Options{Mode: challenge.ModePoW, Exempt: []string{"not-an-ip"}}); err == nil {
+		t.Fatal("expected error for exempt address")
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package challenge
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	Issued      prometheus.Counter // number of issued challenges
+	Redeemed    prometheus.Counter // number of challenges redeemed for tokens
+	Rejected    prometheus.Counter // number of rejected challenge solutions
+	Challenged  prometheus.Counter // number of requests without a valid token
+	RateLimited prometheus.Counter // number of requests over the rate of their token
+}
+
+func newMetrics() metrics {
+	subsystem := "challenge"
+
+	return metrics{
+		Issued: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "issued",
+			Help:      "Total challenges issued.",
+		}),
+		Redeemed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "redeemed",
+			Help:      "Total challenges redeemed for tokens.",
+		}),
+		Rejected: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "rejected",
+			Help:      "Total rejected challenge solutions.",
+		}),
+		Challenged: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "challenged",
+			Help:      "Total requests without a valid token.",
+		}),
+		RateLimited: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "rate_limited",
+			Help:      "Total requests over the rate of their token.",
+		}),
+	}
+}
+
+func (s *Service) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(s.metrics)
+}
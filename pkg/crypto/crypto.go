@@ -5,6 +5,7 @@
 package crypto
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
@@ -58,6 +59,55 @@ func NewOverlayFromEthereumAddress(ethAddr []byte, networkID uint64, nonce []byt
 	return swarm.NewAddress(h[:]), nil
 }
 
+// ErrInvalidPrefix is returned when the overlay prefix is not a string of bits.
+var ErrInvalidPrefix = errors.New("invalid overlay prefix")
+
+// MineOverlay searches for a nonce which gives an overlay address for the
+// public key with the prefix, provided as a string of bits, e.g. "0110".
+// The expected number of tried nonces doubles with every bit of the prefix.
+func MineOverlay(ctx context.Context, p ecdsa.PublicKey, networkID uint64, prefix string) (swarm.Address, []byte, error) {
+	if len(prefix) > int(swarm.MaxBins) {
+		return swarm.ZeroAddress, nil, ErrInvalidPrefix
+	}
+	for _, b := range prefix {
+		if b != '0' && b != '1' {
+			return swarm.ZeroAddress, nil, ErrInvalidPrefix
+		}
+	}
+
+	ethAddr, err := NewEthereumAddress(p)
+	if err != nil {
+		return swarm.ZeroAddress, nil, err
+	}
+
+	nonce := make([]byte, 32)
+	for i := uint64(0); ; i++ {
+		if i%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return swarm.ZeroAddress, nil, err
+			}
+		}
+		binary.BigEndian.PutUint64(nonce[24:], i)
+		addr, err := NewOverlayFromEthereumAddress(ethAddr, networkID, nonce)
+		if err != nil {
+			return swarm.ZeroAddress, nil, err
+		}
+		if hasPrefix(addr.Bytes(), prefix) {
+			return addr, nonce, nil
+		}
+	}
+}
+
+// hasPrefix reports whether the most significant bits of b are the prefix.
+func hasPrefix(b []byte, prefix string) bool {
+	for i, c := range prefix {
+		if bit := b[i/8] >> (7 - i%8) & 1; bit != uint8(c-'0') {
+			return false
+		}
+	}
+	return true
+}
+
 // GenerateSecp256k1Key generates an ECDSA private key using
 // secp256k1 elliptic curve.
 func GenerateSecp256k1Key() (*ecdsa.PrivateKey, error) {
@@ -6,8 +6,10 @@ package crypto_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -81,6 +83,46 @@ func TestNewAddress(t *testing.T) {
 	}
 }
 
+func TestMineOverlay(t *testing.T) {
+	t.Parallel()
+
+	k, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, prefix := range []string{"", "1", "0110", "10101011"} {
+		a, nonce, err := crypto.MineOverlay(context.Background(), k.PublicKey, 1, prefix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := crypto.NewOverlayAddress(k.PublicKey, 1, nonce)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !a.Equal(want) {
+			t.Fatalf("prefix %q: got address %s, want %s", prefix, a, want)
+		}
+		for i, c := range prefix {
+			if bit := a.Bytes()[i/8] >> (7 - i%8) & 1; bit != uint8(c-'0') {
+				t.Fatalf("prefix %q: address %s has bit %d set to %d", prefix, a, i, bit)
+			}
+		}
+	}
+
+	for _, prefix := range []string{"012", "x", strings.Repeat("0", 33)} {
+		if _, _, err := crypto.MineOverlay(context.Background(), k.PublicKey, 1, prefix); !errors.Is(err, crypto.ErrInvalidPrefix) {
+			t.Fatalf("prefix %q: expected %v, got %v", prefix, crypto.ErrInvalidPrefix, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := crypto.MineOverlay(ctx, k.PublicKey, 1, strings.Repeat("1", 32)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
 func TestEncodeSecp256k1PrivateKey(t *testing.T) {
 	t.Parallel()
 
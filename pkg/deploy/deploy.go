@@ -0,0 +1,250 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package deploy publishes the versions of the websites to the sequence
+// feeds owned by the node. A version is published only after all of its
+// chunks are verified to be retrievable from the network, and the
+// published versions are recorded, so that the feed can be rolled back
+// to the previous version.
+package deploy
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const (
+	keyPrefix = "deploy_"
+
+	// maxHistory is the number of the recorded versions of a feed.
+	maxHistory = 100
+)
+
+var (
+	// ErrNotRetrievable is returned if the content is not
+	// retrievable from the network and is not published.
+	ErrNotRetrievable = errors.New("content not retrievable")
+	// ErrNoPrevious is returned on rollback if there is
+	// no previous version of the feed.
+	ErrNoPrevious = errors.New("no previous version")
+)
+
+// RetrievalChecker checks whether all chunks of the
+// content are retrievable from the network.
+type RetrievalChecker interface {
+	IsRetrievable(ctx context.Context, root swarm.Address) (bool, error)
+}
+
+// Record is a published version of the feed.
+type Record struct {
+	Reference swarm.Address `json:"reference"`
+	Index     uint64        `json:"index"`
+	Timestamp int64         `json:"timestamp"`
+}
+
+// state is the stored history of a feed. The next index is kept apart from
+// the records, as the rollbacks remove the records but the feed updates
+// are never removed. It is only the lower bound of the index of the next
+// update, which is looked up in the feed.
+type state struct {
+	Next    uint64   `json:"next"`
+	History []Record `json:"history"`
+}
+
+// Service publishes the versions of the websites.
+type Service struct {
+	stateStore storage.StateStorer
+	checker    RetrievalChecker
+	signer     crypto.Signer
+	factory    feeds.Factory
+	now        func() time.Time
+
+	mu      sync.Mutex // serializes the updates of the feeds
+	metrics metrics
+}
+
+// New returns a new deploy Service which signs the feed updates with the
+// signer and looks up the feeds with the lookups created by the factory.
+func New(stateStore storage.StateStorer, checker RetrievalChecker, signer crypto.Signer, factory feeds.Factory) *Service {
+	return &Service{
+		stateStore: stateStore,
+		checker:    checker,
+		signer:     signer,
+		factory:    factory,
+		now:        time.Now,
+		metrics:    newMetrics(),
+	}
+}
+
+// Owner returns the owner of the feeds.
+func (s *Service) Owner() (common.Address, error) {
+	return s.signer.EthereumAddress()
+}
+
+// Publish verifies that the root is retrievable and publishes it as the next
+// update of the feed with the topic. The update chunk is stored by the putter.
+func (s *Service) Publish(ctx context.Context, putter storage.Putter, topic []byte, root swarm.Address) (Record, error) {
+	if err := s.checkRetrievable(ctx, root); err != nil {
+		return Record{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, err := s.state(topic)
+	if err != nil {
+		return Record{}, err
+	}
+
+	rec, err := s.publish(ctx, putter, topic, st, root)
+	if err != nil {
+		return Record{}, err
+	}
+	st.History = append(st.History, rec)
+	if len(st.History) > maxHistory {
+		st.History = st.History[len(st.History)-maxHistory:]
+	}
+	if err := s.stateStore.Put(key(topic), st); err != nil {
+		return Record{}, fmt.Errorf("save state: %w", err)
+	}
+
+	s.metrics.Deploys.Inc()
+	return rec, nil
+}
+
+// Rollback publishes the previous version of the feed with the topic as its
+// next update, and removes the current version from the history, so that
+// the successive rollbacks go back through the history.
+func (s *Service) Rollback(ctx context.Context, putter storage.Putter, topic []byte) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, err := s.state(topic)
+	if err != nil {
+		return Record{}, err
+	}
+	if len(st.History) < 2 {
+		return Record{}, ErrNoPrevious
+	}
+	previous := st.History[len(st.History)-2]
+
+	// the previous content may have been garbage collected since
+	if err := s.checkRetrievable(ctx, previous.Reference); err != nil {
+		return Record{}, err
+	}
+
+	rec, err := s.publish(ctx, putter, topic, st, previous.Reference)
+	if err != nil {
+		return Record{}, err
+	}
+	st.History = append(st.History[:len(st.History)-2], rec)
+	if err := s.stateStore.Put(key(topic), st); err != nil {
+		return Record{}, fmt.Errorf("save state: %w", err)
+	}
+
+	s.metrics.Rollbacks.Inc()
+	return rec, nil
+}
+
+// History returns the recorded versions of the feed
+// with the topic, the current one being the last.
+func (s *Service) History(topic []byte) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, err := s.state(topic)
+	if err != nil {
+		return nil, err
+	}
+	return st.History, nil
+}
+
+// publish puts the update of the root at the next index
+// of the feed and advances the index of the state.
+func (s *Service) publish(ctx context.Context, putter storage.Putter, topic []byte, st *state, root swarm.Address) (Record, error) {
+	p, err := feeds.NewPutter(putter, s.signer, topic)
+	if err != nil {
+		return Record{}, fmt.Errorf("feed putter: %w", err)
+	}
+
+	// the feed may have been updated by the other nodes of the owner,
+	// or before the state of the node was lost
+	next, err := s.nextIndex(ctx, p.Feed)
+	if err != nil {
+		return Record{}, err
+	}
+	if next > st.Next {
+		st.Next = next
+	}
+
+	rec := Record{
+		Reference: root,
+		Index:     st.Next,
+		Timestamp: s.now().Unix(),
+	}
+	if err := p.Put(ctx, sequence.NewIndex(rec.Index), rec.Timestamp, root.Bytes()); err != nil {
+		return Record{}, fmt.Errorf("put feed update: %w", err)
+	}
+	st.Next++
+	return rec, nil
+}
+
+// nextIndex looks up the index following the latest update of the feed.
+func (s *Service) nextIndex(ctx context.Context, feed *feeds.Feed) (uint64, error) {
+	lookup, err := s.factory.NewLookup(feeds.Sequence, feed)
+	if err != nil {
+		return 0, fmt.Errorf("feed lookup: %w", err)
+	}
+	// the latest update regardless of the clocks of the updaters
+	_, _, next, err := lookup.At(ctx, math.MaxInt64, 0)
+	if err != nil {
+		return 0, fmt.Errorf("lookup feed: %w", err)
+	}
+	if next == nil {
+		return 0, nil
+	}
+	b, err := next.MarshalBinary()
+	if err != nil || len(b) != 8 {
+		return 0, fmt.Errorf("invalid feed index %s", next)
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+func (s *Service) checkRetrievable(ctx context.Context, root swarm.Address) error {
+	ok, err := s.checker.IsRetrievable(ctx, root)
+	if err != nil {
+		return fmt.Errorf("check retrievable: %w", err)
+	}
+	if !ok {
+		s.metrics.NotRetrievable.Inc()
+		return ErrNotRetrievable
+	}
+	return nil
+}
+
+func (s *Service) state(topic []byte) (*state, error) {
+	st := new(state)
+	err := s.stateStore.Get(key(topic), st)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("load state: %w", err)
+	}
+	return st, nil
+}
+
+func key(topic []byte) string {
+	return keyPrefix + hex.EncodeToString(topic)
+}
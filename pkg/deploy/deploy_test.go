@@ -0,0 +1,168 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package deploy_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/deploy"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/factory"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// checker reports the content as retrievable unless it is marked otherwise.
+type checker struct {
+	mu             sync.Mutex
+	notRetrievable map[string]bool
+}
+
+func (c *checker) IsRetrievable(_ context.Context, root swarm.Address) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.notRetrievable[root.ByteString()], nil
+}
+
+func (c *checker) set(root swarm.Address, retrievable bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.notRetrievable == nil {
+		c.notRetrievable = make(map[string]bool)
+	}
+	c.notRetrievable[root.ByteString()] = !retrievable
+}
+
+func TestService(t *testing.T) {
+	t.Parallel()
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		ctx     = context.Background()
+		signer  = crypto.NewDefaultSigner(pk)
+		storer  = smock.NewStorer()
+		chk     = new(checker)
+		svc     = deploy.New(statestore.NewStateStore(), chk, signer, factory.New(storer))
+		topic   = []byte("website")
+		v1      = swarm.MustParseHexAddress("aa00000000000000000000000000000000000000000000000000000000000000")
+		v2      = swarm.MustParseHexAddress("bb00000000000000000000000000000000000000000000000000000000000000")
+		v3      = swarm.MustParseHexAddress("cc00000000000000000000000000000000000000000000000000000000000000")
+		history = func(want ...swarm.Address) {
+			t.Helper()
+			got, err := svc.History(topic)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("got %d records, want %d", len(got), len(want))
+			}
+			for i := range want {
+				if !got[i].Reference.Equal(want[i]) {
+					t.Fatalf("record %d: got reference %s, want %s", i, got[i].Reference, want[i])
+				}
+			}
+		}
+	)
+
+	owner, err := svc.Owner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// latest checks the reference and the index of the latest feed update
+	latest := func(want swarm.Address, wantIndex string) {
+		t.Helper()
+		ch, cur, _, err := sequence.NewFinder(storer, feeds.New(topic, owner)).At(ctx, time.Now().Unix()+1, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ch == nil {
+			t.Fatal("no feed update")
+		}
+		_, payload, err := feeds.FromChunk(ch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := swarm.NewAddress(payload); !got.Equal(want) || cur.String() != wantIndex {
+			t.Fatalf("got feed update %s at index %s, want %s at index %s", got, cur, want, wantIndex)
+		}
+	}
+
+	if _, err := svc.Rollback(ctx, storer, topic); !errors.Is(err, deploy.ErrNoPrevious) {
+		t.Fatalf("got error %v, want %v", err, deploy.ErrNoPrevious)
+	}
+
+	for i, v := range []swarm.Address{v1, v2} {
+		rec, err := svc.Publish(ctx, storer, topic, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.Index != uint64(i) || !rec.Reference.Equal(v) {
+			t.Fatalf("got record %+v, want index %d of %s", rec, i, v)
+		}
+	}
+	history(v1, v2)
+	latest(v2, "1")
+
+	chk.set(v3, false)
+	if _, err := svc.Publish(ctx, storer, topic, v3); !errors.Is(err, deploy.ErrNotRetrievable) {
+		t.Fatalf("got error %v, want %v", err, deploy.ErrNotRetrievable)
+	}
+	history(v1, v2)
+	latest(v2, "1")
+
+	rec, err := svc.Rollback(ctx, storer, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Index != 2 || !rec.Reference.Equal(v1) {
+		t.Fatalf("got record %+v, want index 2 of %s", rec, v1)
+	}
+	history(v1)
+	latest(v1, "2")
+
+	if _, err := svc.Rollback(ctx, storer, topic); !errors.Is(err, deploy.ErrNoPrevious) {
+		t.Fatalf("got error %v, want %v", err, deploy.ErrNoPrevious)
+	}
+
+	// the index continues after the rollback
+	chk.set(v3, true)
+	if rec, err = svc.Publish(ctx, storer, topic, v3); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Index != 3 {
+		t.Fatalf("got index %d, want 3", rec.Index)
+	}
+	history(v1, v3)
+	latest(v3, "3")
+
+	// the index continues after the updates not recorded in the state
+	fresh := deploy.New(statestore.NewStateStore(), chk, signer, factory.New(storer))
+	if rec, err = fresh.Publish(ctx, storer, topic, v2); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Index != 4 {
+		t.Fatalf("got index %d, want 4", rec.Index)
+	}
+	latest(v2, "4")
+
+	// the other topics are independent
+	other, err := svc.History([]byte("other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(other) != 0 {
+		t.Fatalf("got %d records of other topic, want none", len(other))
+	}
+}
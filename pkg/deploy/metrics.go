@@ -0,0 +1,46 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package deploy
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	Deploys        prometheus.Counter
+	Rollbacks      prometheus.Counter
+	NotRetrievable prometheus.Counter
+}
+
+func newMetrics() metrics {
+	subsystem := "deploy"
+
+	return metrics{
+		Deploys: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "deploys_total",
+			Help:      "Total number of the published versions.",
+		}),
+		Rollbacks: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "rollbacks_total",
+			Help:      "Total number of the rollbacks to the previous versions.",
+		}),
+		NotRetrievable: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "not_retrievable_total",
+			Help:      "Total number of the versions not published as not retrievable.",
+		}),
+	}
+}
+
+// Metrics returns the prometheus collectors of the service.
+func (s *Service) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(s.metrics)
+}
@@ -0,0 +1,128 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dnslink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const cloudflareEndpoint = "https://api.cloudflare.com/client/v4"
+
+// cloudflare updates the records of a zone through the Cloudflare API.
+type cloudflare struct {
+	endpoint string
+	token    string
+	zone     string
+	ttl      time.Duration
+	client   *http.Client
+}
+
+func newCloudflare(u *url.URL, ttl time.Duration, client *http.Client) (*cloudflare, error) {
+	token := u.User.Username()
+	if token == "" {
+		return nil, errors.New("cloudflare: missing api token")
+	}
+	endpoint := cloudflareEndpoint
+	if v := u.Query().Get("endpoint"); v != "" {
+		endpoint = strings.TrimSuffix(v, "/")
+	}
+	return &cloudflare{
+		endpoint: endpoint,
+		token:    token,
+		zone:     u.Host,
+		ttl:      ttl,
+		client:   client,
+	}, nil
+}
+
+type cloudflareRecord struct {
+	ID      string `json:"id,omitempty"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	TTL     int    `json:"ttl"`
+}
+
+type cloudflareResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+	Result json.RawMessage `json:"result"`
+}
+
+// SetTXT implements the Provider interface. The existing record
+// is updated in place and the new one is created otherwise.
+func (c *cloudflare) SetTXT(ctx context.Context, name, value string) error {
+	records := fmt.Sprintf("%s/zones/%s/dns_records", c.endpoint, url.PathEscape(c.zone))
+
+	var existing []cloudflareRecord
+	query := url.Values{"type": {"TXT"}, "name": {name}}
+	if err := c.do(ctx, http.MethodGet, records+"?"+query.Encode(), nil, &existing); err != nil {
+		return fmt.Errorf("cloudflare: list records: %w", err)
+	}
+
+	record := cloudflareRecord{
+		Type:    "TXT",
+		Name:    name,
+		Content: value,
+		TTL:     int(c.ttl / time.Second),
+	}
+	if len(existing) == 0 {
+		if err := c.do(ctx, http.MethodPost, records, record, nil); err != nil {
+			return fmt.Errorf("cloudflare: create record: %w", err)
+		}
+		return nil
+	}
+	if err := c.do(ctx, http.MethodPut, records+"/"+url.PathEscape(existing[0].ID), record, nil); err != nil {
+		return fmt.Errorf("cloudflare: update record: %w", err)
+	}
+	return nil
+}
+
+func (c *cloudflare) do(ctx context.Context, method, url string, body, result interface{}) error {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var r cloudflareResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return fmt.Errorf("decode response with status %s: %w", resp.Status, err)
+	}
+	if !r.Success {
+		if len(r.Errors) > 0 {
+			return fmt.Errorf("status %s: %d: %s", resp.Status, r.Errors[0].Code, r.Errors[0].Message)
+		}
+		return fmt.Errorf("status %s", resp.Status)
+	}
+	if result != nil {
+		return json.Unmarshal(r.Result, result)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dnslink keeps the DNSLink TXT records of the conventional domains
+// pointed at the latest content of the feeds bound to them. When a feed is
+// updated, the reference of its latest update is published as the
+// dnslink=/swarm/<reference> value of the _dnslink.<domain> TXT record through
+// the API of the DNS provider, which is Cloudflare, Amazon Route 53 or any name
+// server accepting the RFC 2136 dynamic updates.
+package dnslink
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const (
+	// RecordPrefix is the label prepended to the domain
+	// to get the name of its DNSLink TXT record.
+	RecordPrefix = "_dnslink."
+
+	defaultTTL     = time.Minute
+	defaultTimeout = 30 * time.Second
+)
+
+var (
+	// ErrUnsupportedProvider is returned by NewProvider if the
+	// scheme of the target is not a supported DNS provider.
+	ErrUnsupportedProvider = errors.New("unsupported dns provider")
+	// ErrInvalidTarget is returned by NewProvider if the target is not
+	// a valid URL. The target is not included in the error, as it
+	// carries the credentials of the provider.
+	ErrInvalidTarget = errors.New("invalid dns provider target")
+	// ErrInvalidBinding is returned by ParseBinding if the
+	// binding of the feed to the domain is malformed.
+	ErrInvalidBinding = errors.New("invalid feed binding")
+)
+
+// Provider updates the TXT records through the API of a DNS provider.
+type Provider interface {
+	// SetTXT replaces the values of the TXT record with the given name,
+	// creating the record if it does not exist.
+	SetTXT(ctx context.Context, name, value string) error
+}
+
+// NewProvider returns the provider updating the records at the target URL.
+// The supported targets are:
+//
+//	cloudflare://<api-token>@<zone-id>
+//	route53://<access-key-id>:<secret-access-key>@<hosted-zone-id>
+//	rfc2136://[<tsig-key-name>:<base64-tsig-secret>@]<host>[:<port>][?zone=<zone>&alg=<tsig-algorithm>]
+//
+// The ttl query parameter sets the time to live of the records in seconds
+// and the endpoint one overrides the API URL of the cloudflare and route53
+// providers.
+func NewProvider(target string) (Provider, error) {
+	// the parse error is not wrapped, as it quotes the target
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed url", ErrInvalidTarget)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("%w: missing host", ErrInvalidTarget)
+	}
+
+	ttl := defaultTTL
+	if v := u.Query().Get("ttl"); v != "" {
+		s, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || s == 0 {
+			return nil, fmt.Errorf("invalid ttl %q", v)
+		}
+		ttl = time.Duration(s) * time.Second
+	}
+	client := &http.Client{Timeout: defaultTimeout}
+
+	switch u.Scheme {
+	case "cloudflare":
+		return newCloudflare(u, ttl, client)
+	case "route53":
+		return newRoute53(u, ttl, client)
+	case "rfc2136":
+		return newRFC2136(u, ttl)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedProvider, u.Scheme)
+	}
+}
+
+// Binding binds the feed to the domain whose record points at its content.
+type Binding struct {
+	Owner  common.Address
+	Topic  []byte
+	Domain string
+}
+
+// ParseBinding parses the binding given as <owner>:<topic>=<domain>, where
+// the owner is the hex encoded ethereum address and the topic is the hex
+// encoded feed topic, as in the feeds API.
+func ParseBinding(s string) (Binding, error) {
+	feed, domain, ok := strings.Cut(s, "=")
+	if !ok {
+		return Binding{}, fmt.Errorf("%w: %q: missing domain", ErrInvalidBinding, s)
+	}
+	owner, topic, ok := strings.Cut(feed, ":")
+	if !ok {
+		return Binding{}, fmt.Errorf("%w: %q: missing topic", ErrInvalidBinding, s)
+	}
+
+	if !common.IsHexAddress(owner) {
+		return Binding{}, fmt.Errorf("%w: %q: invalid owner", ErrInvalidBinding, s)
+	}
+	t, err := hex.DecodeString(strings.TrimPrefix(topic, "0x"))
+	if err != nil || len(t) == 0 {
+		return Binding{}, fmt.Errorf("%w: %q: invalid topic", ErrInvalidBinding, s)
+	}
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if domain == "" || strings.ContainsAny(domain, " /") {
+		return Binding{}, fmt.Errorf("%w: %q: invalid domain", ErrInvalidBinding, s)
+	}
+
+	return Binding{
+		Owner:  common.HexToAddress(owner),
+		Topic:  t,
+		Domain: domain,
+	}, nil
+}
+
+// RecordName returns the name of the DNSLink TXT record of the domain.
+func RecordName(domain string) string {
+	return RecordPrefix + domain
+}
+
+// RecordValue returns the DNSLink value pointing at the reference.
+func RecordValue(reference swarm.Address) string {
+	return "dnslink=/swarm/" + reference.String()
+}
@@ -0,0 +1,269 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dnslink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "dnslink"
+
+// defaultRetryDelay is the delay after which the
+// failed lookups and record updates are retried.
+const defaultRetryDelay = time.Minute
+
+var (
+	// ErrNoUpdate is returned by the Resolver if the feed was never updated.
+	ErrNoUpdate = errors.New("no feed update")
+	// ErrInvalidUpdate is returned by the Resolver if the
+	// payload of the feed update is not a reference.
+	ErrInvalidUpdate = errors.New("invalid feed update")
+)
+
+// Resolver returns the reference of the latest update of the feed.
+type Resolver func(ctx context.Context, owner common.Address, topic []byte) (swarm.Address, error)
+
+// FeedResolver returns the Resolver looking up the latest
+// updates of the sequence feeds created by the factory.
+func FeedResolver(factory feeds.Factory) Resolver {
+	return func(ctx context.Context, owner common.Address, topic []byte) (swarm.Address, error) {
+		lookup, err := factory.NewLookup(feeds.Sequence, feeds.New(topic, owner))
+		if err != nil {
+			return swarm.ZeroAddress, fmt.Errorf("new lookup: %w", err)
+		}
+		ch, err := feeds.Latest(ctx, lookup, 0)
+		if err != nil {
+			return swarm.ZeroAddress, fmt.Errorf("lookup: %w", err)
+		}
+		// the lookup returns no chunk if the feed was never updated
+		if ch == nil {
+			return swarm.ZeroAddress, ErrNoUpdate
+		}
+		_, payload, err := feeds.FromChunk(ch)
+		if err != nil {
+			return swarm.ZeroAddress, fmt.Errorf("%w: %v", ErrInvalidUpdate, err)
+		}
+		// the payload is the plain or the encrypted reference
+		if len(payload) != swarm.HashSize && len(payload) != swarm.HashSize*2 {
+			return swarm.ZeroAddress, fmt.Errorf("%w: payload of size %d", ErrInvalidUpdate, len(payload))
+		}
+		return swarm.NewAddress(payload), nil
+	}
+}
+
+// Hook updates the records of the domains on the updates of the feeds bound
+// to them. The feeds of an owner are looked up after the owner uploads a
+// single owner chunk, which may be an update of its feeds, and periodically,
+// if the interval is set, to follow the feeds updated through other nodes.
+// The records are updated only when the reference changes; the failed
+// lookups and updates are retried after the retry delay.
+type Hook struct {
+	provider   Provider
+	resolve    Resolver
+	bindings   []Binding
+	interval   time.Duration
+	retryDelay time.Duration
+	logger     log.Logger
+	metrics    metrics
+
+	mu        sync.Mutex
+	pending   map[common.Address]struct{}
+	published map[string]swarm.Address // by domain
+
+	trigger chan struct{}
+	quit    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewHook returns the Hook which keeps the records of the bound domains
+// pointed at the latest content of the feeds. The hook does nothing
+// until it is started.
+func NewHook(provider Provider, resolve Resolver, bindings []Binding, interval time.Duration, logger log.Logger) *Hook {
+	return &Hook{
+		provider:   provider,
+		resolve:    resolve,
+		bindings:   bindings,
+		interval:   interval,
+		retryDelay: defaultRetryDelay,
+		logger:     logger.WithName(loggerName).Register(),
+		metrics:    newMetrics(),
+		pending:    make(map[common.Address]struct{}),
+		published:  make(map[string]swarm.Address),
+		trigger:    make(chan struct{}, 1),
+		quit:       make(chan struct{}),
+	}
+}
+
+// Start starts the hook and looks up all the feeds, so that the records
+// are brought up to date. It is called once the node is connected to the
+// network, as the lookups made before would find no updates.
+func (h *Hook) Start() {
+	select {
+	case <-h.quit:
+		return
+	default:
+	}
+
+	h.wg.Add(1)
+	go h.run()
+
+	h.notifyAll()
+}
+
+// Notify schedules the lookup of the feeds of the owner bound to the domains.
+// It does not block, so that it can be called on every single owner chunk
+// upload.
+func (h *Hook) Notify(owner common.Address) {
+	for _, b := range h.bindings {
+		if b.Owner == owner {
+			h.mu.Lock()
+			h.pending[owner] = struct{}{}
+			h.mu.Unlock()
+			h.signal()
+			return
+		}
+	}
+}
+
+// Published returns the reference the record of the domain was last
+// updated with by the hook.
+func (h *Hook) Published(domain string) (swarm.Address, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ref, ok := h.published[domain]
+	return ref, ok
+}
+
+// Close stops the hook and waits for the running update to finish.
+func (h *Hook) Close() error {
+	close(h.quit)
+	h.wg.Wait()
+	return nil
+}
+
+func (h *Hook) notifyAll() {
+	h.mu.Lock()
+	for _, b := range h.bindings {
+		h.pending[b.Owner] = struct{}{}
+	}
+	h.mu.Unlock()
+	h.signal()
+}
+
+func (h *Hook) signal() {
+	select {
+	case h.trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (h *Hook) run() {
+	defer h.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-h.quit
+		cancel()
+	}()
+
+	var tick <-chan time.Time
+	if h.interval > 0 {
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	// the owners whose feeds failed to be looked up or whose
+	// records failed to be updated are notified again after the delay
+	var (
+		retry  <-chan time.Time
+		failed = make(map[common.Address]struct{})
+	)
+
+	for {
+		select {
+		case <-h.quit:
+			return
+		case <-tick:
+			h.notifyAll()
+		case <-retry:
+			retry = nil
+			h.mu.Lock()
+			for owner := range failed {
+				h.pending[owner] = struct{}{}
+			}
+			h.mu.Unlock()
+			failed = make(map[common.Address]struct{})
+			h.signal()
+		case <-h.trigger:
+			h.mu.Lock()
+			pending := h.pending
+			h.pending = make(map[common.Address]struct{})
+			h.mu.Unlock()
+
+			for _, b := range h.bindings {
+				if _, ok := pending[b.Owner]; ok && !h.update(ctx, b) {
+					failed[b.Owner] = struct{}{}
+				}
+			}
+			if len(failed) > 0 && retry == nil {
+				retry = time.After(h.retryDelay)
+			}
+		}
+	}
+}
+
+// update points the record of the domain at the latest content of the feed,
+// if it is not pointed at it already. It reports whether the record is up to
+// date, that is false if the lookup or the update failed.
+func (h *Hook) update(ctx context.Context, b Binding) bool {
+	loggerV1 := h.logger.V(1).Register()
+
+	ref, err := h.resolve(ctx, b.Owner, b.Topic)
+	if errors.Is(err, ErrNoUpdate) {
+		loggerV1.Debug("feed not updated yet", "domain", b.Domain, "owner", b.Owner)
+		return true
+	}
+	if err != nil {
+		h.metrics.FailedUpdates.Inc()
+		h.logger.Debug("feed lookup failed", "domain", b.Domain, "owner", b.Owner, "error", err)
+		h.logger.Error(nil, "feed lookup failed", "domain", b.Domain)
+		return false
+	}
+
+	h.mu.Lock()
+	last, ok := h.published[b.Domain]
+	h.mu.Unlock()
+	if ok && last.Equal(ref) {
+		return true
+	}
+
+	if err := h.provider.SetTXT(ctx, RecordName(b.Domain), RecordValue(ref)); err != nil {
+		h.metrics.FailedUpdates.Inc()
+		h.logger.Debug("record update failed", "domain", b.Domain, "reference", ref, "error", err)
+		h.logger.Error(nil, "record update failed", "domain", b.Domain)
+		return false
+	}
+
+	h.mu.Lock()
+	h.published[b.Domain] = ref
+	h.mu.Unlock()
+
+	h.metrics.RecordUpdates.Inc()
+	h.logger.Info("record updated", "domain", b.Domain, "reference", ref)
+	return true
+}
@@ -0,0 +1,247 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dnslink_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/dnslink"
+	"github.com/ethersphere/bee/pkg/feeds/factory"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+type record struct {
+	name, value string
+}
+
+type provider struct {
+	records chan record
+	mu      sync.Mutex
+	err     error
+}
+
+func (p *provider) SetTXT(_ context.Context, name, value string) error {
+	p.mu.Lock()
+	err := p.err
+	p.mu.Unlock()
+	if err == nil {
+		p.records <- record{name, value}
+	}
+	return err
+}
+
+func (p *provider) fail(err error) {
+	p.mu.Lock()
+	p.err = err
+	p.mu.Unlock()
+}
+
+type feeds struct {
+	mu   sync.Mutex
+	refs map[common.Address]swarm.Address
+}
+
+func (f *feeds) set(owner common.Address, ref swarm.Address) {
+	f.mu.Lock()
+	f.refs[owner] = ref
+	f.mu.Unlock()
+}
+
+func (f *feeds) resolve(_ context.Context, owner common.Address, _ []byte) (swarm.Address, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ref, ok := f.refs[owner]
+	if !ok {
+		return swarm.ZeroAddress, dnslink.ErrNoUpdate
+	}
+	return ref, nil
+}
+
+func expectRecord(t *testing.T, p *provider, want record) {
+	t.Helper()
+
+	select {
+	case got := <-p.records:
+		if got != want {
+			t.Fatalf("got record %+v, want %+v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for record %+v", want)
+	}
+}
+
+func expectNoRecord(t *testing.T, p *provider) {
+	t.Helper()
+
+	select {
+	case got := <-p.records:
+		t.Fatalf("got unexpected record %+v", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestHook(t *testing.T) {
+	t.Parallel()
+
+	var (
+		owner1 = common.HexToAddress("01")
+		owner2 = common.HexToAddress("02")
+		ref1   = swarm.RandAddress(t)
+		ref2   = swarm.RandAddress(t)
+		p      = &provider{records: make(chan record, 10)}
+		f      = &feeds{refs: map[common.Address]swarm.Address{owner1: ref1}}
+	)
+
+	h := dnslink.NewHook(p, f.resolve, []dnslink.Binding{
+		{Owner: owner1, Topic: []byte("topic"), Domain: "example.org"},
+		{Owner: owner2, Topic: []byte("topic"), Domain: "example.com"},
+	}, 0, log.Noop)
+	defer h.Close()
+	h.Start()
+
+	// the records are brought up to date at the start
+	// and the feed which was never updated is skipped
+	expectRecord(t, p, record{"_dnslink.example.org", "dnslink=/swarm/" + ref1.String()})
+	expectNoRecord(t, p)
+
+	// the unchanged reference is not published again
+	h.Notify(owner1)
+	expectNoRecord(t, p)
+
+	f.set(owner2, ref2)
+	h.Notify(owner2)
+	expectRecord(t, p, record{"_dnslink.example.com", "dnslink=/swarm/" + ref2.String()})
+	if got, ok := h.Published("example.com"); !ok || !got.Equal(ref2) {
+		t.Fatalf("got published reference %s, want %s", got, ref2)
+	}
+
+	// the owners without bindings are ignored
+	h.Notify(common.HexToAddress("03"))
+	expectNoRecord(t, p)
+
+	// the failed update is retried at the next notification
+	p.fail(errors.New("provider unavailable"))
+	f.set(owner1, ref2)
+	h.Notify(owner1)
+	expectNoRecord(t, p)
+	if got, _ := h.Published("example.org"); !got.Equal(ref1) {
+		t.Fatalf("got published reference %s, want %s", got, ref1)
+	}
+	p.fail(nil)
+	h.Notify(owner1)
+	expectRecord(t, p, record{"_dnslink.example.org", "dnslink=/swarm/" + ref2.String()})
+}
+
+func TestHookInterval(t *testing.T) {
+	t.Parallel()
+
+	var (
+		owner = common.HexToAddress("01")
+		ref1  = swarm.RandAddress(t)
+		ref2  = swarm.RandAddress(t)
+		p     = &provider{records: make(chan record, 10)}
+		f     = &feeds{refs: map[common.Address]swarm.Address{owner: ref1}}
+	)
+
+	h := dnslink.NewHook(p, f.resolve, []dnslink.Binding{
+		{Owner: owner, Topic: []byte("topic"), Domain: "example.org"},
+	}, 50*time.Millisecond, log.Noop)
+	defer h.Close()
+	h.Start()
+
+	expectRecord(t, p, record{"_dnslink.example.org", "dnslink=/swarm/" + ref1.String()})
+
+	// the feed updated through another node is followed without the notification
+	f.set(owner, ref2)
+	expectRecord(t, p, record{"_dnslink.example.org", "dnslink=/swarm/" + ref2.String()})
+}
+
+func TestHookRetry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		owner = common.HexToAddress("01")
+		ref   = swarm.RandAddress(t)
+		p     = &provider{records: make(chan record, 10)}
+		f     = &feeds{refs: map[common.Address]swarm.Address{owner: ref}}
+	)
+	p.fail(errors.New("provider unavailable"))
+
+	h := dnslink.NewHook(p, f.resolve, []dnslink.Binding{
+		{Owner: owner, Topic: []byte("topic"), Domain: "example.org"},
+	}, 0, log.Noop)
+	defer h.Close()
+	h.SetRetryDelay(50 * time.Millisecond)
+
+	// nothing is looked up before the start
+	p.fail(nil)
+	expectNoRecord(t, p)
+
+	p.fail(errors.New("provider unavailable"))
+	h.Start()
+	expectNoRecord(t, p)
+
+	// the failed update is retried without the notification or the interval
+	p.fail(nil)
+	expectRecord(t, p, record{"_dnslink.example.org", "dnslink=/swarm/" + ref.String()})
+}
+
+func TestFeedResolver(t *testing.T) {
+	t.Parallel()
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic := []byte("website")
+
+	storer := mock.NewStorer()
+	resolve := dnslink.FeedResolver(factory.New(storer))
+	ctx := context.Background()
+
+	if _, err := resolve(ctx, owner, topic); !errors.Is(err, dnslink.ErrNoUpdate) {
+		t.Fatalf("got error %v, want %v", err, dnslink.ErrNoUpdate)
+	}
+
+	updater, err := sequence.NewUpdater(storer, signer, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		ref := swarm.RandAddress(t)
+		if err := updater.Update(ctx, time.Now().Unix(), ref.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		got, err := resolve(ctx, owner, topic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(ref) {
+			t.Fatalf("got reference %s, want %s", got, ref)
+		}
+	}
+
+	if err := updater.Update(ctx, time.Now().Unix(), []byte("not a reference")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resolve(ctx, owner, topic); !errors.Is(err, dnslink.ErrInvalidUpdate) {
+		t.Fatalf("got error %v, want %v", err, dnslink.ErrInvalidUpdate)
+	}
+}
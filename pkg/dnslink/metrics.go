@@ -0,0 +1,39 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dnslink
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	RecordUpdates prometheus.Counter
+	FailedUpdates prometheus.Counter
+}
+
+func newMetrics() metrics {
+	subsystem := "dnslink"
+
+	return metrics{
+		RecordUpdates: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "record_updates_total",
+			Help:      "Total number of the updated records.",
+		}),
+		FailedUpdates: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "failed_updates_total",
+			Help:      "Total number of the failed feed lookups and record updates.",
+		}),
+	}
+}
+
+// Metrics returns the prometheus collectors of the hook.
+func (h *Hook) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(h.metrics)
+}
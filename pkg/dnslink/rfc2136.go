@@ -0,0 +1,99 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dnslink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const tsigFudge = 300
+
+// rfc2136 updates the records with the dynamic updates sent to the
+// primary name server of the zone, as in RFC 2136, which are signed with
+// the TSIG key, if configured.
+type rfc2136 struct {
+	server  string
+	zone    string
+	keyName string
+	alg     string
+	client  *dns.Client
+	ttl     time.Duration
+}
+
+func newRFC2136(u *url.URL, ttl time.Duration) (*rfc2136, error) {
+	server := u.Host
+	if u.Port() == "" {
+		server = net.JoinHostPort(u.Hostname(), "53")
+	}
+
+	r := &rfc2136{
+		server: server,
+		client: &dns.Client{Net: "udp", Timeout: defaultTimeout},
+		ttl:    ttl,
+	}
+	if zone := u.Query().Get("zone"); zone != "" {
+		r.zone = dns.Fqdn(strings.ToLower(zone))
+	}
+
+	if u.User != nil {
+		secret, _ := u.User.Password()
+		if u.User.Username() == "" || secret == "" {
+			return nil, errors.New("rfc2136: missing tsig key")
+		}
+		r.keyName = dns.Fqdn(strings.ToLower(u.User.Username()))
+		r.alg = dns.HmacSHA256
+		if alg := u.Query().Get("alg"); alg != "" {
+			r.alg = dns.Fqdn(strings.ToLower(alg))
+		}
+		r.client.TsigSecret = map[string]string{r.keyName: secret}
+	}
+	return r, nil
+}
+
+// SetTXT implements the Provider interface. The record set is deleted and
+// inserted in the same update, which is applied atomically by the server.
+// Without the configured zone, the record is updated in the zone of its
+// parent domain.
+func (r *rfc2136) SetTXT(ctx context.Context, name, value string) error {
+	name = dns.Fqdn(strings.ToLower(name))
+	zone := r.zone
+	if zone == "" {
+		labels := dns.SplitDomainName(name)
+		if len(labels) < 2 {
+			return fmt.Errorf("rfc2136: no zone of the record %q", name)
+		}
+		zone = dns.Fqdn(strings.Join(labels[1:], "."))
+	}
+
+	m := new(dns.Msg)
+	m.SetUpdate(zone)
+	m.RemoveRRset([]dns.RR{&dns.TXT{
+		Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeTXT, Class: dns.ClassINET},
+	}})
+	m.Insert([]dns.RR{&dns.TXT{
+		Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: uint32(r.ttl / time.Second)},
+		Txt: []string{value},
+	}})
+	if r.keyName != "" {
+		m.SetTsig(r.keyName, r.alg, tsigFudge, time.Now().Unix())
+	}
+
+	resp, _, err := r.client.ExchangeContext(ctx, m, r.server)
+	if err != nil {
+		return fmt.Errorf("rfc2136: update: %w", err)
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("rfc2136: update: %s", dns.RcodeToString[resp.Rcode])
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dnslink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	route53Endpoint = "https://route53.amazonaws.com"
+	route53Region   = "us-east-1"
+	route53Service  = "route53"
+	route53XMLNS    = "https://route53.amazonaws.com/doc/2013-04-01/"
+)
+
+// route53 updates the records of a hosted zone through the Amazon
+// Route 53 API. The requests are signed with the signature version 4.
+type route53 struct {
+	endpoint  string
+	accessKey string
+	secretKey string
+	zone      string
+	ttl       time.Duration
+	client    *http.Client
+	now       func() time.Time
+}
+
+func newRoute53(u *url.URL, ttl time.Duration, client *http.Client) (*route53, error) {
+	secret, _ := u.User.Password()
+	if u.User.Username() == "" || secret == "" {
+		return nil, errors.New("route53: missing access key")
+	}
+	endpoint := route53Endpoint
+	if v := u.Query().Get("endpoint"); v != "" {
+		endpoint = strings.TrimSuffix(v, "/")
+	}
+	return &route53{
+		endpoint:  endpoint,
+		accessKey: u.User.Username(),
+		secretKey: secret,
+		zone:      u.Host,
+		ttl:       ttl,
+		client:    client,
+		now:       time.Now,
+	}, nil
+}
+
+type route53ChangeRequest struct {
+	XMLName xml.Name        `xml:"ChangeResourceRecordSetsRequest"`
+	XMLNS   string          `xml:"xmlns,attr"`
+	Changes []route53Change `xml:"ChangeBatch>Changes>Change"`
+}
+
+type route53Change struct {
+	Action string           `xml:"Action"`
+	Set    route53RecordSet `xml:"ResourceRecordSet"`
+}
+
+type route53RecordSet struct {
+	Name   string   `xml:"Name"`
+	Type   string   `xml:"Type"`
+	TTL    int      `xml:"TTL"`
+	Values []string `xml:"ResourceRecords>ResourceRecord>Value"`
+}
+
+// SetTXT implements the Provider interface. The record
+// is created or replaced with the single UPSERT change.
+func (r *route53) SetTXT(ctx context.Context, name, value string) error {
+	body, err := xml.Marshal(route53ChangeRequest{
+		XMLNS: route53XMLNS,
+		Changes: []route53Change{{
+			Action: "UPSERT",
+			Set: route53RecordSet{
+				Name:   name,
+				Type:   "TXT",
+				TTL:    int(r.ttl / time.Second),
+				Values: []string{quoteTXT(value)},
+			},
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("route53: marshal change: %w", err)
+	}
+	body = append([]byte(xml.Header), body...)
+
+	target := fmt.Sprintf("%s/2013-04-01/hostedzone/%s/rrset", r.endpoint, url.PathEscape(r.zone))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("route53: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/xml")
+	r.sign(req, body)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("route53: change records: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("route53: change records: status %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// sign adds the signature version 4 authorization header to the request.
+func (r *route53) sign(req *http.Request, body []byte) {
+	now := r.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	payloadHash := sha256.Sum256(body)
+	signedHeaders := "content-type;host;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"content-type:" + req.Header.Get("Content-Type") + "\n" +
+			"host:" + req.URL.Host + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + route53Region + "/" + route53Service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+r.secretKey), date)
+	key = hmacSHA256(key, route53Region)
+	key = hmacSHA256(key, route53Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		r.accessKey, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// quoteTXT returns the value as the quoted character string of a TXT record.
+func quoteTXT(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
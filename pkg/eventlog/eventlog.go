@@ -0,0 +1,250 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package eventlog provides the append-only log of the state changes of the
+// node, such as the synced chunks, the postage batch events, the settlements
+// and the topology changes. Every event gets a sequence number which is used
+// as the cursor by the external consumers, so that they can build their state
+// from the log without missing the updates which happened between the polls.
+// The most recent events are persisted in the state store and survive the
+// restart of the node.
+package eventlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "eventlog"
+
+const keyPrefix = "eventlog_"
+
+// DefaultRetention is the default number of the most recent events kept.
+const DefaultRetention = 10000
+
+// persistQueueSize is the number of the changes of the persisted events
+// which can wait for the state store.
+const persistQueueSize = 1000
+
+// The types of the events.
+const (
+	TypeChunksSynced = "chunks-synced"
+	TypeBatch        = "batch"
+	TypeSettlement   = "settlement"
+	TypeTopology     = "topology"
+)
+
+// Event is an entry of the log.
+type Event struct {
+	Seq       uint64          `json:"seq"`
+	Type      string          `json:"type"`
+	Timestamp int64           `json:"timestamp"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Log is the append-only log of the events.
+type Log struct {
+	store     storage.StateStorer
+	retention int
+	logger    log.Logger
+	metrics   metrics
+
+	mu      sync.Mutex
+	events  []Event // ordered by the sequence number
+	lastSeq uint64
+
+	persistC chan persistOp
+	quit     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// persistOp is the change of the persisted events, which either stores
+// or deletes the event.
+type persistOp struct {
+	event  Event
+	delete bool
+}
+
+// New returns a new Log which loads the persisted events from the store
+// and keeps at most retention most recent events.
+func New(store storage.StateStorer, retention int, logger log.Logger) (*Log, error) {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	l := &Log{
+		store:     store,
+		retention: retention,
+		logger:    logger.WithName(loggerName).Register(),
+		metrics:   newMetrics(),
+		persistC:  make(chan persistOp, persistQueueSize),
+		quit:      make(chan struct{}),
+	}
+
+	err := store.Iterate(keyPrefix, func(_, value []byte) (bool, error) {
+		var e Event
+		if err := json.Unmarshal(value, &e); err != nil {
+			return true, err
+		}
+		l.events = append(l.events, e)
+		return false, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load events: %w", err)
+	}
+	sort.Slice(l.events, func(i, k int) bool { return l.events[i].Seq < l.events[k].Seq })
+	if n := len(l.events); n > 0 {
+		l.lastSeq = l.events[n-1].Seq
+	}
+	for _, e := range l.trim() {
+		if err := store.Delete(eventKey(e.Seq)); err != nil {
+			return nil, fmt.Errorf("delete event: %w", err)
+		}
+	}
+
+	l.wg.Add(1)
+	go l.persist()
+
+	return l, nil
+}
+
+// Append adds the event of the type with the data marshaled to json.
+// The event is persisted asynchronously, so that the callers, which may
+// hold their locks, never wait for the state store.
+func (l *Log) Append(typ string, data interface{}) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		l.logger.Error(err, "marshal event failed", "type", typ)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.lastSeq++
+	e := Event{
+		Seq:       l.lastSeq,
+		Type:      typ,
+		Timestamp: time.Now().Unix(),
+		Data:      raw,
+	}
+	l.events = append(l.events, e)
+	l.enqueue(persistOp{event: e})
+	for _, e := range l.trim() {
+		l.enqueue(persistOp{event: e, delete: true})
+	}
+
+	l.metrics.AppendedEvents.WithLabelValues(typ).Inc()
+}
+
+// enqueue queues the change for the persisting, it must be called with the
+// mutex locked, so that the changes are applied in the order of the events.
+// The change is dropped if the queue is full.
+func (l *Log) enqueue(op persistOp) {
+	select {
+	case l.persistC <- op:
+	default:
+		l.metrics.DroppedPersists.Inc()
+		l.logger.Debug("persist queue full, event not persisted", "seq", op.event.Seq, "delete", op.delete)
+	}
+}
+
+// persist applies the queued changes to the state store
+// until the log is closed.
+func (l *Log) persist() {
+	defer l.wg.Done()
+
+	for {
+		select {
+		case op := <-l.persistC:
+			l.apply(op)
+		case <-l.quit:
+			// apply the changes queued before the closing
+			for {
+				select {
+				case op := <-l.persistC:
+					l.apply(op)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (l *Log) apply(op persistOp) {
+	key := eventKey(op.event.Seq)
+	if op.delete {
+		if err := l.store.Delete(key); err != nil {
+			l.logger.Error(err, "delete event failed", "seq", op.event.Seq)
+		}
+		return
+	}
+	if err := l.store.Put(key, op.event); err != nil {
+		l.logger.Error(err, "persist event failed", "seq", op.event.Seq)
+	}
+}
+
+// Events returns at most limit events following the cursor, which is the
+// sequence number of the last event seen by the consumer, zero for none,
+// and the cursor of the next request. The returned truncated flag is set if
+// some of the events following the cursor are no longer retained.
+func (l *Log) Events(cursor uint64, limit int) (events []Event, next uint64, truncated bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	i := sort.Search(len(l.events), func(i int) bool { return l.events[i].Seq > cursor })
+	if i < len(l.events) {
+		truncated = l.events[i].Seq > cursor+1
+	}
+	end := len(l.events)
+	if limit > 0 && i+limit < end {
+		end = i + limit
+	}
+	events = append([]Event(nil), l.events[i:end]...)
+
+	next = cursor
+	if n := len(events); n > 0 {
+		next = events[n-1].Seq
+	}
+	return events, next, truncated
+}
+
+// LastSeq returns the sequence number of the last event.
+func (l *Log) LastSeq() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.lastSeq
+}
+
+// Close stops the watching of the sources of the events
+// and persists the queued events.
+func (l *Log) Close() error {
+	close(l.quit)
+	l.wg.Wait()
+	return nil
+}
+
+// trim removes the events over the retention and returns them,
+// it must be called with the mutex locked.
+func (l *Log) trim() []Event {
+	n := len(l.events) - l.retention
+	if n <= 0 {
+		return nil
+	}
+	removed := l.events[:n]
+	l.events = l.events[n:]
+	return removed
+}
+
+func eventKey(seq uint64) string {
+	return fmt.Sprintf("%s%020d", keyPrefix, seq)
+}
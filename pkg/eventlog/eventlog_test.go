@@ -0,0 +1,222 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eventlog_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/accounting"
+	"github.com/ethersphere/bee/pkg/eventlog"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage/events"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestLog(t *testing.T) {
+	t.Parallel()
+
+	l := newLog(t, statestore.NewStateStore(), 0)
+	for i := 1; i <= 5; i++ {
+		l.Append(eventlog.TypeTopology, eventlog.Topology{Connected: i})
+	}
+
+	got, next, truncated := l.Events(0, 2)
+	if len(got) != 2 || got[0].Seq != 1 || got[1].Seq != 2 || next != 2 || truncated {
+		t.Fatalf("got events %+v, next %d, truncated %v", got, next, truncated)
+	}
+	var data eventlog.Topology
+	if err := json.Unmarshal(got[1].Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Connected != 2 || got[1].Type != eventlog.TypeTopology {
+		t.Fatalf("got event %+v", got[1])
+	}
+
+	got, next, _ = l.Events(next, 0)
+	if len(got) != 3 || got[0].Seq != 3 || next != 5 {
+		t.Fatalf("got events %+v, next %d", got, next)
+	}
+
+	got, next, _ = l.Events(next, 0)
+	if len(got) != 0 || next != 5 {
+		t.Fatalf("got events %+v, next %d", got, next)
+	}
+}
+
+func TestLogRetention(t *testing.T) {
+	t.Parallel()
+
+	store := statestore.NewStateStore()
+
+	l, err := eventlog.New(store, 3, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 5; i++ {
+		l.Append(eventlog.TypeTopology, eventlog.Topology{Connected: i})
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	l = newLog(t, store, 3)
+	if seq := l.LastSeq(); seq != 5 {
+		t.Fatalf("got last seq %d, want 5", seq)
+	}
+
+	got, next, truncated := l.Events(1, 0)
+	if len(got) != 3 || got[0].Seq != 3 || next != 5 || !truncated {
+		t.Fatalf("got events %+v, next %d, truncated %v", got, next, truncated)
+	}
+
+	l.Append(eventlog.TypeTopology, eventlog.Topology{Connected: 6})
+	if got, _, _ = l.Events(0, 0); len(got) != 3 || got[0].Seq != 4 || got[2].Seq != 6 {
+		t.Fatalf("got events %+v", got)
+	}
+}
+
+func TestLogPersistAsync(t *testing.T) {
+	t.Parallel()
+
+	store := &blockingStore{StateStorer: statestore.NewStateStore(), release: make(chan struct{})}
+	l, err := eventlog.New(store, 0, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appended := make(chan struct{})
+	go func() {
+		for i := 1; i <= 3; i++ {
+			l.Append(eventlog.TypeTopology, eventlog.Topology{Connected: i})
+		}
+		close(appended)
+	}()
+	select {
+	case <-appended:
+	case <-time.After(5 * time.Second):
+		t.Fatal("append waits for the state store")
+	}
+
+	close(store.release)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	l = newLog(t, store, 0)
+	if got, _, _ := l.Events(0, 0); len(got) != 3 || got[2].Seq != 3 {
+		t.Fatalf("got events %+v", got)
+	}
+}
+
+func TestSources(t *testing.T) {
+	t.Parallel()
+
+	t.Run("batch events", func(t *testing.T) {
+		t.Parallel()
+
+		s := &subscriber{c: make(chan events.Event, 1)}
+		l := newLog(t, statestore.NewStateStore(), 0)
+		l.WatchBatchEvents(s)
+
+		s.c <- events.Event{Type: events.TypeCreated, BatchID: "ab"}
+		e := waitEvent(t, l)
+		var data events.Event
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			t.Fatal(err)
+		}
+		if e.Type != eventlog.TypeBatch || data.Type != events.TypeCreated || data.BatchID != "ab" {
+			t.Fatalf("got event %+v", e)
+		}
+	})
+
+	t.Run("synced chunks", func(t *testing.T) {
+		t.Parallel()
+
+		var synced uint64 = 10
+		l := newLog(t, statestore.NewStateStore(), 0)
+		l.WatchSyncedChunks(func() uint64 { return atomic.LoadUint64(&synced) }, 10*time.Millisecond)
+
+		atomic.AddUint64(&synced, 5)
+		e := waitEvent(t, l)
+		var data eventlog.ChunksSynced
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			t.Fatal(err)
+		}
+		if e.Type != eventlog.TypeChunksSynced || data.Chunks != 5 || data.Total != 15 {
+			t.Fatalf("got event %+v", e)
+		}
+	})
+
+	t.Run("settlement", func(t *testing.T) {
+		t.Parallel()
+
+		l := newLog(t, statestore.NewStateStore(), 0)
+		peer := swarm.RandAddress(t)
+		var f accounting.SettlementFunc = l.Settlement
+		f(peer, accounting.SettlementPaymentReceived, big.NewInt(100))
+
+		e := waitEvent(t, l)
+		var data eventlog.Settlement
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			t.Fatal(err)
+		}
+		if e.Type != eventlog.TypeSettlement || !data.Peer.Equal(peer) ||
+			data.Kind != accounting.SettlementPaymentReceived || data.Amount.Cmp(big.NewInt(100)) != 0 {
+			t.Fatalf("got event %+v", e)
+		}
+	})
+}
+
+// blockingStore blocks the puts until it is released.
+type blockingStore struct {
+	storage.StateStorer
+	release chan struct{}
+}
+
+func (s *blockingStore) Put(key string, i interface{}) error {
+	<-s.release
+	return s.StateStorer.Put(key, i)
+}
+
+type subscriber struct {
+	c chan events.Event
+}
+
+func (s *subscriber) SubscribeBatchEvents() (<-chan events.Event, func()) {
+	return s.c, func() {}
+}
+
+func newLog(t *testing.T, store storage.StateStorer, retention int) *eventlog.Log {
+	t.Helper()
+
+	l, err := eventlog.New(store, retention, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := l.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return l
+}
+
+func waitEvent(t *testing.T, l *eventlog.Log) eventlog.Event {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if got, _, _ := l.Events(0, 1); len(got) > 0 {
+			return got[0]
+		}
+	}
+	t.Fatal("timed out waiting for the event")
+	return eventlog.Event{}
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eventlog
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	AppendedEvents  *prometheus.CounterVec
+	DroppedPersists prometheus.Counter
+}
+
+func newMetrics() metrics {
+	subsystem := "eventlog"
+
+	return metrics{
+		AppendedEvents: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "appended_events_total",
+				Help:      "Total number of appended events by type.",
+			},
+			[]string{"type"},
+		),
+		DroppedPersists: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "dropped_persists_total",
+				Help:      "Total number of changes of the persisted events dropped as the queue was full.",
+			},
+		),
+	}
+}
+
+// Metrics returns the prometheus collectors of the log.
+func (l *Log) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(l.metrics)
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eventlog
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/bigint"
+	"github.com/ethersphere/bee/pkg/postage/events"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+)
+
+// DefaultSyncedInterval is the default interval of the synced chunks events.
+const DefaultSyncedInterval = time.Minute
+
+// ChunksSynced is the data of the synced chunks event.
+type ChunksSynced struct {
+	Chunks uint64 `json:"chunks"` // synced since the previous event
+	Total  uint64 `json:"total"`  // synced since the start of the node
+}
+
+// Settlement is the data of the settlement event.
+type Settlement struct {
+	Peer   swarm.Address  `json:"peer"`
+	Kind   string         `json:"kind"`
+	Amount *bigint.BigInt `json:"amount"`
+}
+
+// Topology is the data of the topology event.
+type Topology struct {
+	Depth     uint8 `json:"depth"`
+	Connected int   `json:"connected"`
+}
+
+// TopologyDriver is the part of the topology driver watched for the changes.
+type TopologyDriver interface {
+	SubscribeTopologyChange() (c <-chan struct{}, unsubscribe func())
+	NeighborhoodDepth() uint8
+	EachConnectedPeer(topology.EachPeerFunc, topology.Filter) error
+}
+
+// WatchBatchEvents appends the postage batch events of the subscriber.
+func (l *Log) WatchBatchEvents(s events.Subscriber) {
+	c, unsubscribe := s.SubscribeBatchEvents()
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		defer unsubscribe()
+
+		for {
+			select {
+			case <-l.quit:
+				return
+			case e, ok := <-c:
+				if !ok {
+					return
+				}
+				l.Append(TypeBatch, e)
+			}
+		}
+	}()
+}
+
+// WatchTopology appends the changes of the depth
+// and of the number of the connected peers.
+func (l *Log) WatchTopology(t TopologyDriver) {
+	c, unsubscribe := t.SubscribeTopologyChange()
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		defer unsubscribe()
+
+		var last Topology
+		for {
+			select {
+			case <-l.quit:
+				return
+			case <-c:
+			}
+
+			current := Topology{Depth: t.NeighborhoodDepth()}
+			_ = t.EachConnectedPeer(func(swarm.Address, uint8) (bool, bool, error) {
+				current.Connected++
+				return false, false, nil
+			}, topology.Filter{})
+			if current != last {
+				last = current
+				l.Append(TypeTopology, current)
+			}
+		}
+	}()
+}
+
+// WatchSyncedChunks appends the number of the chunks synced in the interval,
+// synced returns the total number of the synced chunks.
+func (l *Log) WatchSyncedChunks(synced func() uint64, interval time.Duration) {
+	last := synced()
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-l.quit:
+				return
+			case <-ticker.C:
+			}
+
+			total := synced()
+			if total > last {
+				l.Append(TypeChunksSynced, ChunksSynced{Chunks: total - last, Total: total})
+				last = total
+			}
+		}
+	}()
+}
+
+// Settlement appends the completed settlement with the peer,
+// it is the accounting.SettlementFunc of the log.
+func (l *Log) Settlement(peer swarm.Address, kind string, amount *big.Int) {
+	l.Append(TypeSettlement, Settlement{Peer: peer, Kind: kind, Amount: bigint.Wrap(amount)})
+}
@@ -0,0 +1,18 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !chaos
+
+package faults
+
+import "context"
+
+// Enabled reports whether the binary is built with the fault injection hooks.
+const Enabled = false
+
+// Inject is a no-op without the chaos build tag.
+func Inject(context.Context, Point) error { return nil }
+
+// Corrupt is a no-op without the chaos build tag.
+func Corrupt(_ Point, data []byte) []byte { return data }
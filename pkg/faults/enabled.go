@@ -0,0 +1,22 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build chaos
+
+package faults
+
+import "context"
+
+// Enabled reports whether the binary is built with the fault injection hooks.
+const Enabled = true
+
+// Inject applies the default injector rule for the point.
+func Inject(ctx context.Context, p Point) error {
+	return Default.Inject(ctx, p)
+}
+
+// Corrupt applies the default injector corruption rule for the point.
+func Corrupt(p Point, data []byte) []byte {
+	return Default.Corrupt(p, data)
+}
@@ -0,0 +1,166 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package faults provides the fault injection hooks for the storage and
+// network layers, used to automate the resilience tests against a single
+// binary. The hooks are active only in the binaries built with the chaos
+// build tag, in all other builds they are no-ops.
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Point identifies the place in the code where the faults are injected.
+type Point string
+
+const (
+	StorerGet   Point = "storer-get"
+	StorerPut   Point = "storer-put"
+	StreamRead  Point = "stream-read"
+	StreamWrite Point = "stream-write"
+)
+
+// Points lists all the injection points.
+var Points = []Point{StorerGet, StorerPut, StreamRead, StreamWrite}
+
+var (
+	// ErrInjected is returned by the operations failed by an injected fault.
+	ErrInjected = errors.New("injected fault")
+	// ErrUnknownPoint is returned when a rule targets an unknown injection point.
+	ErrUnknownPoint = errors.New("unknown injection point")
+	// ErrInvalidRate is returned when a rule rate is not in the [0, 1] range.
+	ErrInvalidRate = errors.New("rate must be between 0 and 1")
+)
+
+// Rule describes the faults injected at a point.
+type Rule struct {
+	Point       Point         `json:"point"`
+	Latency     time.Duration `json:"latency"`     // delay added to every operation
+	ErrorRate   float64       `json:"errorRate"`   // probability that the operation fails
+	CorruptRate float64       `json:"corruptRate"` // probability that the data is corrupted
+}
+
+func (r Rule) validate() error {
+	known := false
+	for _, p := range Points {
+		known = known || p == r.Point
+	}
+	if !known {
+		return fmt.Errorf("%w: %q", ErrUnknownPoint, r.Point)
+	}
+	if r.ErrorRate < 0 || r.ErrorRate > 1 || r.CorruptRate < 0 || r.CorruptRate > 1 {
+		return ErrInvalidRate
+	}
+	return nil
+}
+
+// Injector holds the fault rules and decides which faults happen.
+type Injector struct {
+	mu    sync.Mutex
+	rules map[Point]Rule
+	rand  *rand.Rand
+}
+
+// NewInjector creates an injector without any rules
+// which uses the given seed for its decisions.
+func NewInjector(seed int64) *Injector {
+	return &Injector{
+		rules: make(map[Point]Rule),
+		rand:  rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Set adds the rule, replacing the previous rule for the same point.
+func (i *Injector) Set(r Rule) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.rules[r.Point] = r
+	return nil
+}
+
+// Remove removes the rule for the point.
+func (i *Injector) Remove(p Point) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	delete(i.rules, p)
+}
+
+// Reset removes all the rules.
+func (i *Injector) Reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.rules = make(map[Point]Rule)
+}
+
+// Rules returns all the rules ordered by the point.
+func (i *Injector) Rules() []Rule {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	rules := make([]Rule, 0, len(i.rules))
+	for _, r := range i.rules {
+		rules = append(rules, r)
+	}
+	sort.Slice(rules, func(a, b int) bool { return rules[a].Point < rules[b].Point })
+	return rules
+}
+
+// Inject waits for the rule latency and fails with ErrInjected
+// according to the rule error rate.
+func (i *Injector) Inject(ctx context.Context, p Point) error {
+	i.mu.Lock()
+	r, ok := i.rules[p]
+	fail := ok && r.ErrorRate > 0 && i.rand.Float64() < r.ErrorRate
+	i.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	if r.Latency > 0 {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		select {
+		case <-time.After(r.Latency):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if fail {
+		return fmt.Errorf("%s: %w", p, ErrInjected)
+	}
+	return nil
+}
+
+// Corrupt returns the data unchanged or, according to the rule corrupt
+// rate, a copy of the data with one randomly flipped byte.
+func (i *Injector) Corrupt(p Point, data []byte) []byte {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	r, ok := i.rules[p]
+	if !ok || len(data) == 0 || r.CorruptRate == 0 || i.rand.Float64() >= r.CorruptRate {
+		return data
+	}
+	c := make([]byte, len(data))
+	copy(c, data)
+	c[i.rand.Intn(len(c))] ^= 0xff
+	return c
+}
+
+// Default is the injector used by the hooks.
+var Default = NewInjector(time.Now().UnixNano())
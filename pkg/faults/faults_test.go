@@ -0,0 +1,88 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package faults_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/faults"
+)
+
+func TestInjector(t *testing.T) {
+	t.Parallel()
+
+	i := faults.NewInjector(1)
+
+	if err := i.Inject(context.Background(), faults.StorerGet); err != nil {
+		t.Fatalf("no rule: got error %v", err)
+	}
+
+	if err := i.Set(faults.Rule{Point: "unknown"}); !errors.Is(err, faults.ErrUnknownPoint) {
+		t.Fatalf("got error %v, want %v", err, faults.ErrUnknownPoint)
+	}
+	if err := i.Set(faults.Rule{Point: faults.StorerGet, ErrorRate: 1.5}); !errors.Is(err, faults.ErrInvalidRate) {
+		t.Fatalf("got error %v, want %v", err, faults.ErrInvalidRate)
+	}
+
+	if err := i.Set(faults.Rule{Point: faults.StorerGet, ErrorRate: 1, Latency: 10 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if err := i.Inject(context.Background(), faults.StorerGet); !errors.Is(err, faults.ErrInjected) {
+		t.Fatalf("got error %v, want %v", err, faults.ErrInjected)
+	}
+	if d := time.Since(start); d < 10*time.Millisecond {
+		t.Fatalf("latency not injected, took %v", d)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := i.Set(faults.Rule{Point: faults.StorerPut, Latency: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	if err := i.Inject(ctx, faults.StorerPut); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+
+	if rules := i.Rules(); len(rules) != 2 || rules[0].Point != faults.StorerGet || rules[1].Point != faults.StorerPut {
+		t.Fatalf("unexpected rules %v", rules)
+	}
+
+	i.Remove(faults.StorerGet)
+	if err := i.Inject(context.Background(), faults.StorerGet); err != nil {
+		t.Fatalf("removed rule: got error %v", err)
+	}
+
+	i.Reset()
+	if rules := i.Rules(); len(rules) != 0 {
+		t.Fatalf("unexpected rules %v", rules)
+	}
+}
+
+func TestInjectorCorrupt(t *testing.T) {
+	t.Parallel()
+
+	i := faults.NewInjector(1)
+	data := []byte("swarm")
+
+	if got := i.Corrupt(faults.StreamRead, data); !bytes.Equal(got, data) {
+		t.Fatalf("no rule: data corrupted")
+	}
+
+	if err := i.Set(faults.Rule{Point: faults.StreamRead, CorruptRate: 1}); err != nil {
+		t.Fatal(err)
+	}
+	got := i.Corrupt(faults.StreamRead, data)
+	if bytes.Equal(got, data) {
+		t.Fatal("data not corrupted")
+	}
+	if string(data) != "swarm" {
+		t.Fatal("original data modified")
+	}
+}
@@ -0,0 +1,354 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package crdt provides the conflict-free replicated data types on top of
+// the sequence feeds, so that multiple writers update the same value without
+// coordination. Every writer updates only its own feed under the topic of
+// the value, and the readers merge the latest updates of the feeds of the
+// writers they trust:
+//
+//   - the last-writer-wins register holds the value with the greatest
+//     timestamp, the ties being broken by the greater writer address,
+//   - the grow-only counter holds the sum of the counts of the writers,
+//     each of which only increments its own count.
+//
+// The merge is commutative, associative and idempotent, so all readers of
+// the same writers converge to the same value regardless of the order in
+// which the updates are observed.
+package crdt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const (
+	registerTopicPrefix = "crdt-register-"
+	counterTopicPrefix  = "crdt-counter-"
+
+	// anyTime is the time of the lookups of the feeds, which does not depend
+	// on the clocks of the writers, as the latest update is always merged.
+	anyTime = math.MaxInt64
+
+	timestampSize = 8
+	countSize     = 8
+
+	// MaxValueSize is the maximum size of the value of a register, which is
+	// the chunk size less the feed update and the register timestamps.
+	MaxValueSize = swarm.ChunkSize - 8 - timestampSize
+)
+
+var (
+	// ErrValueTooLarge is returned if the value of
+	// a register is larger than the MaxValueSize.
+	ErrValueTooLarge = errors.New("value too large")
+	// ErrInvalidDelta is returned if the counter is incremented by zero.
+	ErrInvalidDelta = errors.New("invalid delta")
+	// ErrOverflow is returned if the count of a counter overflows.
+	ErrOverflow = errors.New("counter overflow")
+	// ErrInvalidUpdate is returned if the feed update
+	// is not a valid update of the data type.
+	ErrInvalidUpdate = errors.New("invalid update")
+)
+
+// Register is the value of a last-writer-wins register.
+type Register struct {
+	Writer    common.Address
+	Timestamp uint64 // in nanoseconds
+	Value     []byte
+}
+
+// Count is the count of a writer of a grow-only counter.
+type Count struct {
+	Writer common.Address
+	Count  uint64
+}
+
+// Counter is the value of a grow-only counter.
+type Counter struct {
+	Value  uint64
+	Counts []Count
+}
+
+// Service reads the data types from the feeds of the writers
+// and updates them in the feeds owned by the node.
+type Service struct {
+	factory feeds.Factory
+	signer  crypto.Signer
+	now     func() time.Time
+
+	mu sync.Mutex // serializes the updates of the feeds
+}
+
+// New returns a new crdt Service which looks up the feeds with
+// the factory and signs the feed updates with the signer.
+func New(factory feeds.Factory, signer crypto.Signer) *Service {
+	return &Service{
+		factory: factory,
+		signer:  signer,
+		now:     time.Now,
+	}
+}
+
+// Owner returns the address of the node as a writer.
+func (s *Service) Owner() (common.Address, error) {
+	return s.signer.EthereumAddress()
+}
+
+// Register returns the merged value of the register with the topic written
+// by the writers. The zero Register is returned if none of them wrote it.
+func (s *Service) Register(ctx context.Context, topic []byte, writers []common.Address) (Register, error) {
+	var registers []Register
+	for _, w := range unique(writers) {
+		r, ok, err := s.register(ctx, topic, w)
+		if err != nil {
+			return Register{}, err
+		}
+		if ok {
+			registers = append(registers, r)
+		}
+	}
+	return MergeRegisters(registers...), nil
+}
+
+// SetRegister writes the value to the register with the topic. The timestamp
+// of the write is greater than the timestamps of the values of the writers,
+// so that it wins over all of them even if the clocks of the writers are
+// skewed. The update chunk is stored by the putter.
+func (s *Service) SetRegister(ctx context.Context, putter storage.Putter, topic, value []byte, writers []common.Address) (Register, error) {
+	if len(value) > MaxValueSize {
+		return Register{}, ErrValueTooLarge
+	}
+
+	owner, err := s.Owner()
+	if err != nil {
+		return Register{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.Register(ctx, topic, append([]common.Address{owner}, writers...))
+	if err != nil {
+		return Register{}, err
+	}
+
+	r := Register{
+		Writer:    owner,
+		Timestamp: uint64(s.now().UnixNano()),
+		Value:     value,
+	}
+	if r.Timestamp <= current.Timestamp {
+		r.Timestamp = current.Timestamp + 1
+	}
+
+	payload := make([]byte, timestampSize, timestampSize+len(value))
+	binary.BigEndian.PutUint64(payload, r.Timestamp)
+	payload = append(payload, value...)
+
+	if err := s.put(ctx, putter, registerTopic(topic), payload); err != nil {
+		return Register{}, err
+	}
+	return r, nil
+}
+
+// Counter returns the merged value of the counter with the topic
+// incremented by the writers, with the counts of each of them.
+func (s *Service) Counter(ctx context.Context, topic []byte, writers []common.Address) (Counter, error) {
+	var counts []Count
+	for _, w := range unique(writers) {
+		c, err := s.count(ctx, topic, w)
+		if err != nil {
+			return Counter{}, err
+		}
+		counts = append(counts, Count{Writer: w, Count: c})
+	}
+	return MergeCounts(counts...)
+}
+
+// Increment increments the count of the node of the counter with the topic
+// by the delta and returns the new count. The update chunk is stored by
+// the putter.
+func (s *Service) Increment(ctx context.Context, putter storage.Putter, topic []byte, delta uint64) (Count, error) {
+	if delta == 0 {
+		return Count{}, ErrInvalidDelta
+	}
+
+	owner, err := s.Owner()
+	if err != nil {
+		return Count{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := s.count(ctx, topic, owner)
+	if err != nil {
+		return Count{}, err
+	}
+	if c > math.MaxUint64-delta {
+		return Count{}, ErrOverflow
+	}
+	c += delta
+
+	payload := make([]byte, countSize)
+	binary.BigEndian.PutUint64(payload, c)
+
+	if err := s.put(ctx, putter, counterTopic(topic), payload); err != nil {
+		return Count{}, err
+	}
+	return Count{Writer: owner, Count: c}, nil
+}
+
+// MergeRegisters returns the register with the greatest timestamp, the
+// ties being broken by the greater writer address.
+func MergeRegisters(registers ...Register) Register {
+	var merged Register
+	for _, r := range registers {
+		if r.Timestamp > merged.Timestamp ||
+			r.Timestamp == merged.Timestamp && bytes.Compare(r.Writer.Bytes(), merged.Writer.Bytes()) > 0 {
+			merged = r
+		}
+	}
+	return merged
+}
+
+// MergeCounts returns the counter with the sum of the greatest counts of
+// each of the writers, as the counts of a writer only grow.
+func MergeCounts(counts ...Count) (Counter, error) {
+	latest := make(map[common.Address]int)
+	counter := Counter{Counts: make([]Count, 0, len(counts))}
+	for _, c := range counts {
+		i, ok := latest[c.Writer]
+		if !ok {
+			latest[c.Writer] = len(counter.Counts)
+			counter.Counts = append(counter.Counts, c)
+			continue
+		}
+		if c.Count > counter.Counts[i].Count {
+			counter.Counts[i] = c
+		}
+	}
+	for _, c := range counter.Counts {
+		if counter.Value > math.MaxUint64-c.Count {
+			return Counter{}, ErrOverflow
+		}
+		counter.Value += c.Count
+	}
+	return counter, nil
+}
+
+// register returns the latest value of the register with the topic
+// written by the writer. The ok result is false if there is none.
+func (s *Service) register(ctx context.Context, topic []byte, writer common.Address) (r Register, ok bool, err error) {
+	payload, ok, err := s.latest(ctx, registerTopic(topic), writer)
+	if err != nil || !ok {
+		return Register{}, false, err
+	}
+	if len(payload) < timestampSize {
+		return Register{}, false, fmt.Errorf("register of %s: %w", writer, ErrInvalidUpdate)
+	}
+	return Register{
+		Writer:    writer,
+		Timestamp: binary.BigEndian.Uint64(payload),
+		Value:     payload[timestampSize:],
+	}, true, nil
+}
+
+// count returns the latest count of the counter with
+// the topic of the writer, which is zero if there is none.
+func (s *Service) count(ctx context.Context, topic []byte, writer common.Address) (uint64, error) {
+	payload, ok, err := s.latest(ctx, counterTopic(topic), writer)
+	if err != nil || !ok {
+		return 0, err
+	}
+	if len(payload) != countSize {
+		return 0, fmt.Errorf("counter of %s: %w", writer, ErrInvalidUpdate)
+	}
+	return binary.BigEndian.Uint64(payload), nil
+}
+
+// latest returns the payload of the latest update of the feed with
+// the topic of the owner. The ok result is false if there is none.
+func (s *Service) latest(ctx context.Context, topic []byte, owner common.Address) ([]byte, bool, error) {
+	lookup, err := s.factory.NewLookup(feeds.Sequence, feeds.New(topic, owner))
+	if err != nil {
+		return nil, false, fmt.Errorf("new lookup: %w", err)
+	}
+	ch, _, _, err := lookup.At(ctx, anyTime, 0)
+	if err != nil {
+		return nil, false, fmt.Errorf("lookup feed of %s: %w", owner, err)
+	}
+	if ch == nil {
+		return nil, false, nil
+	}
+	_, payload, err := feeds.FromChunk(ch)
+	if err != nil {
+		return nil, false, fmt.Errorf("feed update of %s: %w", owner, err)
+	}
+	return payload, true, nil
+}
+
+// put puts the payload as the next update of the feed with the topic
+// owned by the node.
+func (s *Service) put(ctx context.Context, putter storage.Putter, topic, payload []byte) error {
+	p, err := feeds.NewPutter(putter, s.signer, topic)
+	if err != nil {
+		return fmt.Errorf("feed putter: %w", err)
+	}
+	lookup, err := s.factory.NewLookup(feeds.Sequence, p.Feed)
+	if err != nil {
+		return fmt.Errorf("new lookup: %w", err)
+	}
+	_, _, next, err := lookup.At(ctx, anyTime, 0)
+	if err != nil {
+		return fmt.Errorf("lookup own feed: %w", err)
+	}
+	if err := p.Put(ctx, next, s.now().Unix(), payload); err != nil {
+		return fmt.Errorf("put feed update: %w", err)
+	}
+	return nil
+}
+
+// registerTopic and counterTopic return the topics of the feeds of the data
+// types, which differ, so that a register and a counter may share a topic.
+func registerTopic(topic []byte) []byte {
+	return feedTopic(registerTopicPrefix, topic)
+}
+
+func counterTopic(topic []byte) []byte {
+	return feedTopic(counterTopicPrefix, topic)
+}
+
+func feedTopic(prefix string, topic []byte) []byte {
+	h := swarm.NewHasher()
+	_, _ = h.Write([]byte(prefix))
+	_, _ = h.Write(topic)
+	return h.Sum(nil)
+}
+
+func unique(writers []common.Address) []common.Address {
+	seen := make(map[common.Address]struct{}, len(writers))
+	res := make([]common.Address, 0, len(writers))
+	for _, w := range writers {
+		if _, ok := seen[w]; ok {
+			continue
+		}
+		seen[w] = struct{}{}
+		res = append(res, w)
+	}
+	return res
+}
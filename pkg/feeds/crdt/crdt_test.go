@@ -0,0 +1,237 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crdt_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds/crdt"
+	"github.com/ethersphere/bee/pkg/feeds/factory"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+)
+
+// newWriter returns a crdt Service of a new key
+// whose clock is shifted by the skew.
+func newWriter(t *testing.T, storer *smock.MockStorer, skew time.Duration) (*crdt.Service, common.Address) {
+	t.Helper()
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := crdt.New(factory.New(storer), crypto.NewDefaultSigner(pk))
+	svc.SetNow(func() time.Time { return time.Now().Add(skew) })
+	owner, err := svc.Owner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc, owner
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		storer = smock.NewStorer()
+		topic  = []byte("profile")
+	)
+	a, addrA := newWriter(t, storer, 0)
+	// the clock of b is behind
+	b, addrB := newWriter(t, storer, -time.Hour)
+	writers := []common.Address{addrA, addrB}
+
+	r, err := a.Register(ctx, topic, writers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Value != nil || r.Timestamp != 0 {
+		t.Fatalf("got register %+v, want empty", r)
+	}
+
+	if _, err := a.SetRegister(ctx, storer, topic, []byte("a1"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// the write of b which did not observe the write of a loses
+	if _, err := b.SetRegister(ctx, storer, topic, []byte("b1"), nil); err != nil {
+		t.Fatal(err)
+	}
+	checkRegister(t, a, topic, writers, addrA, "a1")
+	checkRegister(t, b, topic, []common.Address{addrB, addrA}, addrA, "a1")
+
+	// the write of b which observed the write of a wins despite the clock
+	if _, err := b.SetRegister(ctx, storer, topic, []byte("b2"), writers); err != nil {
+		t.Fatal(err)
+	}
+	checkRegister(t, a, topic, writers, addrB, "b2")
+
+	// the readers which do not trust b see the value of a
+	checkRegister(t, a, topic, []common.Address{addrA}, addrA, "a1")
+
+	if _, err := a.SetRegister(ctx, storer, topic, []byte("a2"), writers); err != nil {
+		t.Fatal(err)
+	}
+	checkRegister(t, b, topic, writers, addrA, "a2")
+
+	// the counter with the same topic is independent of the register
+	c, err := a.Counter(ctx, topic, writers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Value != 0 {
+		t.Fatalf("got counter value %d, want 0", c.Value)
+	}
+}
+
+func checkRegister(t *testing.T, svc *crdt.Service, topic []byte, writers []common.Address, wantWriter common.Address, wantValue string) {
+	t.Helper()
+
+	r, err := svc.Register(context.Background(), topic, writers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Writer != wantWriter {
+		t.Fatalf("got writer %s, want %s", r.Writer, wantWriter)
+	}
+	if string(r.Value) != wantValue {
+		t.Fatalf("got value %q, want %q", r.Value, wantValue)
+	}
+}
+
+func TestRegisterValueTooLarge(t *testing.T) {
+	t.Parallel()
+
+	storer := smock.NewStorer()
+	svc, _ := newWriter(t, storer, 0)
+
+	value := bytes.Repeat([]byte{1}, crdt.MaxValueSize)
+	if _, err := svc.SetRegister(context.Background(), storer, []byte("topic"), value, nil); err != nil {
+		t.Fatal(err)
+	}
+	_, err := svc.SetRegister(context.Background(), storer, []byte("topic"), append(value, 1), nil)
+	if !errors.Is(err, crdt.ErrValueTooLarge) {
+		t.Fatalf("got error %v, want %v", err, crdt.ErrValueTooLarge)
+	}
+}
+
+func TestCounter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		storer = smock.NewStorer()
+		topic  = []byte("likes")
+	)
+	a, addrA := newWriter(t, storer, 0)
+	// the updates of b with the clock ahead are merged
+	b, addrB := newWriter(t, storer, time.Hour)
+
+	for _, inc := range []struct {
+		svc   *crdt.Service
+		delta uint64
+		want  uint64
+	}{
+		{svc: a, delta: 2, want: 2},
+		{svc: b, delta: 3, want: 3},
+		{svc: a, delta: 1, want: 3},
+		{svc: b, delta: 10, want: 13},
+	} {
+		c, err := inc.svc.Increment(ctx, storer, topic, inc.delta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Count != inc.want {
+			t.Fatalf("got count %d, want %d", c.Count, inc.want)
+		}
+	}
+
+	c, err := a.Counter(ctx, topic, []common.Address{addrA, addrB, addrA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Value != 16 {
+		t.Fatalf("got counter value %d, want 16", c.Value)
+	}
+	want := []crdt.Count{{Writer: addrA, Count: 3}, {Writer: addrB, Count: 13}}
+	if len(c.Counts) != len(want) {
+		t.Fatalf("got %d counts, want %d", len(c.Counts), len(want))
+	}
+	for i := range want {
+		if c.Counts[i] != want[i] {
+			t.Fatalf("got count %+v, want %+v", c.Counts[i], want[i])
+		}
+	}
+
+	if _, err := a.Increment(ctx, storer, topic, 0); !errors.Is(err, crdt.ErrInvalidDelta) {
+		t.Fatalf("got error %v, want %v", err, crdt.ErrInvalidDelta)
+	}
+	if _, err := a.Increment(ctx, storer, topic, math.MaxUint64); !errors.Is(err, crdt.ErrOverflow) {
+		t.Fatalf("got error %v, want %v", err, crdt.ErrOverflow)
+	}
+}
+
+func TestMergeRegisters(t *testing.T) {
+	t.Parallel()
+
+	var (
+		low  = common.HexToAddress("0x01")
+		high = common.HexToAddress("0x02")
+		r1   = crdt.Register{Writer: high, Timestamp: 1, Value: []byte("r1")}
+		r2   = crdt.Register{Writer: low, Timestamp: 2, Value: []byte("r2")}
+		r3   = crdt.Register{Writer: high, Timestamp: 2, Value: []byte("r3")}
+	)
+
+	// the merge does not depend on the order of the registers
+	for _, registers := range [][]crdt.Register{
+		{r1, r2, r3},
+		{r3, r2, r1},
+		{r2, r1, r3, r2},
+	} {
+		if got := crdt.MergeRegisters(registers...); string(got.Value) != "r3" {
+			t.Fatalf("got value %q, want %q", got.Value, "r3")
+		}
+	}
+
+	if got := crdt.MergeRegisters(); got.Value != nil {
+		t.Fatalf("got value %q, want none", got.Value)
+	}
+}
+
+func TestMergeCounts(t *testing.T) {
+	t.Parallel()
+
+	var (
+		a = common.HexToAddress("0x01")
+		b = common.HexToAddress("0x02")
+	)
+
+	c, err := crdt.MergeCounts(
+		crdt.Count{Writer: a, Count: 5},
+		crdt.Count{Writer: b, Count: 2},
+		crdt.Count{Writer: a, Count: 3},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Value != 7 {
+		t.Fatalf("got value %d, want 7", c.Value)
+	}
+
+	_, err = crdt.MergeCounts(
+		crdt.Count{Writer: a, Count: math.MaxUint64},
+		crdt.Count{Writer: b, Count: 1},
+	)
+	if !errors.Is(err, crdt.ErrOverflow) {
+		t.Fatalf("got error %v, want %v", err, crdt.ErrOverflow)
+	}
+}
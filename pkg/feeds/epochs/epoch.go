@@ -27,6 +27,12 @@ type epoch struct {
 	level uint8
 }
 
+// NewIndex returns the index of the epoch with the given start time and level.
+// The start time must be a multiple of the epoch length, that is 2^level.
+func NewIndex(start uint64, level uint8) feeds.Index {
+	return &epoch{start: start, level: level}
+}
+
 func (e *epoch) String() string {
 	return fmt.Sprintf("%d/%d", e.start, e.level)
 }
@@ -40,21 +40,27 @@ func NewPutter(putter storage.Putter, signer crypto.Signer, topic []byte) (*Putt
 
 // Put pushes an update to the feed through the chunk stores
 func (u *Putter) Put(ctx context.Context, i Index, at int64, payload []byte) error {
-	id, err := u.Feed.Update(i).Id()
+	ch, err := NewSignedUpdate(u.signer, u.Topic, i, at, payload)
 	if err != nil {
 		return err
 	}
-	cac, err := toChunk(uint64(at), payload)
+	_, err = u.putter.Put(ctx, storage.ModePutUpload, ch)
+	return err
+}
+
+// NewSignedUpdate constructs the single owner chunk of the feed update
+// with the given topic and index signed by the signer. It has no side
+// effects, so the same arguments always result in the same chunk.
+func NewSignedUpdate(signer crypto.Signer, topic []byte, i Index, at int64, payload []byte) (swarm.Chunk, error) {
+	id, err := Id(topic, i)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	s := soc.New(id, cac)
-	ch, err := s.Sign(u.signer)
+	cac, err := toChunk(uint64(at), payload)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	_, err = u.putter.Put(ctx, storage.ModePutUpload, ch)
-	return err
+	return soc.New(id, cac).Sign(signer)
 }
 
 func toChunk(at uint64, payload []byte) (swarm.Chunk, error) {
@@ -50,6 +50,11 @@ func (i *index) MarshalBinary() ([]byte, error) {
 	return indexBytes, nil
 }
 
+// NewIndex returns the index of the n-th update of a sequence feed.
+func NewIndex(n uint64) feeds.Index {
+	return &index{n}
+}
+
 // Next requires
 func (i *index) Next(last int64, at uint64) feeds.Index {
 	return &index{i.index + 1}
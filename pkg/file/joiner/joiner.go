@@ -16,6 +16,7 @@ import (
 	"github.com/ethersphere/bee/pkg/encryption"
 	"github.com/ethersphere/bee/pkg/encryption/store"
 	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/membudget"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"golang.org/x/sync/errgroup"
@@ -30,9 +31,12 @@ type joiner struct {
 
 	ctx    context.Context
 	getter storage.Getter
+	budget *membudget.Budget
 }
 
 // New creates a new Joiner. A Joiner provides Read, Seek and Size functionalities.
+// The memory of the concurrently retrieved chunks is acquired from the memory
+// budget of the context, if it is set.
 func New(ctx context.Context, getter storage.Getter, address swarm.Address) (file.Joiner, int64, error) {
 	getter = store.New(getter)
 	// retrieve the root chunk to read the total data length the be retrieved
@@ -50,6 +54,7 @@ func New(ctx context.Context, getter storage.Getter, address swarm.Address) (fil
 		refLength: len(address.Bytes()),
 		ctx:       ctx,
 		getter:    getter,
+		budget:    membudget.FromContext(ctx),
 		span:      span,
 		rootData:  chunkData[swarm.SpanSize:],
 	}
@@ -75,7 +80,7 @@ func (j *joiner) ReadAt(buffer []byte, off int64) (read int, err error) {
 		return 0, io.EOF
 	}
 
-	readLen := int64(cap(buffer))
+	readLen := int64(len(buffer))
 	if readLen > j.span-off {
 		readLen = j.span - off
 	}
@@ -139,6 +144,11 @@ func (j *joiner) readAtOffset(b, data []byte, cur, subTrieSize, off, bufferOffse
 
 		func(address swarm.Address, b []byte, cur, subTrieSize, off, bufferOffset, bytesToRead, subtrieSpanLimit int64) {
 			eg.Go(func() error {
+				if err := j.budget.Acquire(j.ctx, swarm.ChunkWithSpanSize); err != nil {
+					return err
+				}
+				defer j.budget.Release(swarm.ChunkWithSpanSize)
+
 				ch, err := j.getter.Get(j.ctx, storage.ModeGetRequest, address)
 				if err != nil {
 					return err
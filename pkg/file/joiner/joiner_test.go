@@ -22,6 +22,7 @@ import (
 	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
 	"github.com/ethersphere/bee/pkg/file/splitter"
 	filetest "github.com/ethersphere/bee/pkg/file/testing"
+	"github.com/ethersphere/bee/pkg/membudget"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/storage/mock"
 	testingc "github.com/ethersphere/bee/pkg/storage/testing"
@@ -681,6 +682,19 @@ func TestJoinerReadAt(t *testing.T) {
 	if !bytes.Equal(b, secondChunk.Data()[8:]) {
 		t.Fatal("data read at offset not equal to expected chunk")
 	}
+
+	// the read is limited by the length of the buffer, not its capacity
+	b = make([]byte, swarm.ChunkSize)
+	n, err := j.ReadAt(b[:10], 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 10 || !bytes.Equal(b[:n], firstChunk.Data()[8+100:8+110]) {
+		t.Fatalf("got %d bytes %x", n, b[:n])
+	}
+	if !bytes.Equal(b[10:], make([]byte, swarm.ChunkSize-10)) {
+		t.Fatal("data read beyond the length of the buffer")
+	}
 }
 
 // TestJoinerOneLevel tests the retrieval of two data chunks immediately
@@ -951,3 +965,35 @@ func TestJoinerIterateChunkAddresses_Encrypted(t *testing.T) {
 		}
 	}
 }
+
+// TestJoinerMemoryBudget tests that the content is joined when the memory
+// budget of the context allows only a few concurrently retrieved chunks.
+func TestJoinerMemoryBudget(t *testing.T) {
+	t.Parallel()
+
+	store := mock.NewStorer()
+	budget := membudget.New(2 * swarm.ChunkWithSpanSize)
+	ctx := membudget.WithBudget(context.Background(), budget)
+
+	data := testutil.RandBytes(t, 300*swarm.ChunkSize+100)
+	pb := builder.NewPipelineBuilder(ctx, store, storage.ModePutUpload, false)
+	addr, err := builder.FeedPipeline(ctx, pb, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, _, err := joiner.New(ctx, store, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("joined data mismatch")
+	}
+	if used := budget.Used(); used != 0 {
+		t.Fatalf("got used memory %d, want 0", used)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"runtime/pprof"
 
 	"github.com/ethersphere/bee/pkg/encryption"
 	"github.com/ethersphere/bee/pkg/file/pipeline"
@@ -17,10 +18,16 @@ import (
 	"github.com/ethersphere/bee/pkg/file/pipeline/feeder"
 	"github.com/ethersphere/bee/pkg/file/pipeline/hashtrie"
 	"github.com/ethersphere/bee/pkg/file/pipeline/store"
+	"github.com/ethersphere/bee/pkg/membudget"
+	"github.com/ethersphere/bee/pkg/profiling"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 )
 
+// pipelineMemory is the memory held by a pipeline, which is mostly the
+// buffers of the feeder and of the hash trie writer.
+const pipelineMemory = 2*swarm.ChunkSize + 9*2*swarm.ChunkWithSpanSize
+
 // NewPipelineBuilder returns the appropriate pipeline according to the specified parameters
 func NewPipelineBuilder(ctx context.Context, s storage.Putter, mode storage.ModePut, encrypt bool) pipeline.Interface {
 	if encrypt {
@@ -72,8 +79,19 @@ func newShortEncryptionPipelineFunc(ctx context.Context, s storage.Putter, mode
 }
 
 // FeedPipeline feeds the pipeline with the given reader until EOF is reached.
-// It returns the cryptographic root hash of the content.
+// It returns the cryptographic root hash of the content. The memory of the
+// pipeline is acquired from the memory budget of the context, if it is set.
 func FeedPipeline(ctx context.Context, pipeline pipeline.Interface, r io.Reader) (addr swarm.Address, err error) {
+	budget := membudget.FromContext(ctx)
+	if err := budget.Acquire(ctx, pipelineMemory); err != nil {
+		return swarm.ZeroAddress, err
+	}
+	defer budget.Release(pipelineMemory)
+
+	// label the profiles of the chunking and hashing of the content
+	pprof.SetGoroutineLabels(pprof.WithLabels(ctx, profiling.Labels("pipeline")))
+	defer pprof.SetGoroutineLabels(ctx)
+
 	data := make([]byte, swarm.ChunkSize)
 	for {
 		c, err := r.Read(data)
@@ -0,0 +1,128 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package wrapper provides the minimal wrapper of the raw bytes uploads
+// which records the content type and the file name of the bytes, so that
+// they can be served with the proper headers without a manifest.
+package wrapper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"unicode"
+
+	"github.com/ethersphere/bee/pkg/encryption"
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// Magic prefixes the serialized wrapper.
+const Magic = "swarm-bytes-wrapper-v1\n"
+
+// encryptedReferenceSize is the size of the reference of the encrypted bytes.
+const encryptedReferenceSize = swarm.HashSize + encryption.KeyLength
+
+// MaxFieldLength is the maximal length of the content type and the file name.
+const MaxFieldLength = 255
+
+var (
+	// ErrNotWrapper is returned when the data is not a wrapper.
+	ErrNotWrapper = errors.New("not a wrapper")
+	// ErrFieldTooLong is returned when the content type or the file name is too long.
+	ErrFieldTooLong = errors.New("wrapper field too long")
+	// ErrInvalidContentType is returned when the content type is not a media type.
+	ErrInvalidContentType = errors.New("invalid content-type")
+	// ErrInvalidFilename is returned when the file name can not be
+	// served in the Content-Disposition header.
+	ErrInvalidFilename = errors.New("invalid filename")
+)
+
+// Wrapper wraps the reference of the raw bytes with their metadata.
+type Wrapper struct {
+	Reference   swarm.Address `json:"reference"`
+	ContentType string        `json:"contentType,omitempty"`
+	Filename    string        `json:"filename,omitempty"`
+}
+
+// Validate checks that the metadata can be served in the headers of the
+// download: the content type is a media type and the file name has no path
+// separators, quotes or control characters.
+func (w *Wrapper) Validate() error {
+	if len(w.ContentType) > MaxFieldLength || len(w.Filename) > MaxFieldLength {
+		return ErrFieldTooLong
+	}
+	if w.ContentType != "" {
+		if _, _, err := mime.ParseMediaType(w.ContentType); err != nil {
+			return ErrInvalidContentType
+		}
+	}
+	if strings.ContainsAny(w.Filename, "/\\\"") || strings.IndexFunc(w.Filename, unicode.IsControl) != -1 {
+		return ErrInvalidFilename
+	}
+	return nil
+}
+
+// MarshalBinary serializes the wrapper, the result always fits into a single chunk.
+func (w *Wrapper) MarshalBinary() ([]byte, error) {
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(Magic), b...), nil
+}
+
+// UnmarshalBinary deserializes the wrapper,
+// ErrNotWrapper is returned if the data is not a wrapper.
+func (w *Wrapper) UnmarshalBinary(data []byte) error {
+	if !bytes.HasPrefix(data, []byte(Magic)) {
+		return ErrNotWrapper
+	}
+	var v Wrapper
+	dec := json.NewDecoder(bytes.NewReader(data[len(Magic):]))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return ErrNotWrapper
+	}
+	if n := len(v.Reference.Bytes()); n != swarm.HashSize && n != encryptedReferenceSize {
+		return ErrNotWrapper
+	}
+	// the wrapper may be crafted, so its metadata is validated as on the upload
+	if err := v.Validate(); err != nil {
+		return ErrNotWrapper
+	}
+	*w = v
+	return nil
+}
+
+// Load returns the wrapper of the reference, ErrNotWrapper is
+// returned if the content of the reference is not a wrapper.
+func Load(ctx context.Context, getter storage.Getter, reference swarm.Address) (*Wrapper, error) {
+	j, size, err := joiner.New(ctx, getter, reference)
+	if err != nil {
+		return nil, err
+	}
+	// a wrapper is always a single chunk
+	if size < int64(len(Magic)) || size > swarm.ChunkSize {
+		return nil, ErrNotWrapper
+	}
+	data, err := io.ReadAll(j)
+	if err != nil {
+		return nil, fmt.Errorf("read wrapper %s: %w", reference, err)
+	}
+	w := new(Wrapper)
+	if err := w.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wrapper_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/file/wrapper"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := mock.NewStorer()
+
+	save := func(t *testing.T, data []byte) swarm.Address {
+		t.Helper()
+		addr, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, store, storage.ModePutUpload, false), bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return addr
+	}
+
+	want := &wrapper.Wrapper{
+		Reference:   swarm.RandAddress(t),
+		ContentType: "text/plain",
+		Filename:    "hello.txt",
+	}
+	data, err := want.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := wrapper.Load(ctx, store, save(t, data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Reference.Equal(want.Reference) || got.ContentType != want.ContentType || got.Filename != want.Filename {
+		t.Fatalf("got wrapper %+v, want %+v", got, want)
+	}
+
+	for _, data := range [][]byte{
+		[]byte("hello world"),
+		[]byte(wrapper.Magic + "{}"),
+		[]byte(wrapper.Magic + `{"reference":"` + want.Reference.String() + `","unknown":"field"}`),
+		// the crafted wrappers whose metadata would be rejected on the upload
+		[]byte(wrapper.Magic + `{"reference":"` + want.Reference.String() + `","filename":"a\"; b.html"}`),
+		[]byte(wrapper.Magic + `{"reference":"` + want.Reference.String() + `","filename":"a\r\nb"}`),
+		[]byte(wrapper.Magic + `{"reference":"` + want.Reference.String() + `","contentType":"text/html;;"}`),
+		bytes.Repeat([]byte{1}, swarm.ChunkSize+1),
+	} {
+		if _, err := wrapper.Load(ctx, store, save(t, data)); !errors.Is(err, wrapper.ErrNotWrapper) {
+			t.Fatalf("got error %v, want %v", err, wrapper.ErrNotWrapper)
+		}
+	}
+}
+
+func TestMarshalFieldTooLong(t *testing.T) {
+	t.Parallel()
+
+	w := &wrapper.Wrapper{
+		Reference: swarm.RandAddress(t),
+		Filename:  strings.Repeat("a", wrapper.MaxFieldLength+1),
+	}
+	if _, err := w.MarshalBinary(); !errors.Is(err, wrapper.ErrFieldTooLong) {
+		t.Fatalf("got error %v, want %v", err, wrapper.ErrFieldTooLong)
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ipfs provides the retrieval of the UnixFS files and directories
+// from an IPFS gateway, so that the content can be imported into Swarm.
+//
+// The blocks of the DAG are requested from the gateway one by one in the raw
+// format of the trustless gateway specification and verified against their
+// CID, so the gateway is trusted only for the availability of the content.
+package ipfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+)
+
+const (
+	// DefaultTimeout is the default timeout of a block request to the gateway.
+	DefaultTimeout = 30 * time.Second
+	// MaxBlockSize is the maximal size of a block accepted from the gateway.
+	MaxBlockSize = 2 * 1024 * 1024
+
+	contentTypeRaw = "application/vnd.ipld.raw"
+)
+
+var (
+	// ErrInvalidGateway is returned by New if the gateway is not an HTTP URL.
+	ErrInvalidGateway = errors.New("invalid gateway")
+	// ErrNotFound is returned if the gateway does not have a block of the DAG.
+	ErrNotFound = errors.New("block not found")
+	// ErrInvalidBlock is returned if the gateway responds with a block
+	// which does not match its CID or which can not be decoded.
+	ErrInvalidBlock = errors.New("invalid block")
+	// ErrUnsupported is returned for the DAGs which are not UnixFS
+	// files or directories, like the HAMT sharded directories.
+	ErrUnsupported = errors.New("unsupported dag")
+)
+
+// Options are the options of the Gateway.
+type Options struct {
+	// Timeout is the timeout of a block request.
+	Timeout time.Duration
+	// Client is the HTTP client of the requests to the gateway.
+	// The http.DefaultClient is used if it is nil.
+	Client *http.Client
+}
+
+// Gateway retrieves the content from an IPFS gateway.
+type Gateway struct {
+	url     *url.URL
+	client  *http.Client
+	timeout time.Duration
+}
+
+// New returns the Gateway which requests the blocks from the gateway URL.
+func New(gateway string, o Options) (*Gateway, error) {
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.Client == nil {
+		o.Client = http.DefaultClient
+	}
+
+	u, err := url.Parse(gateway)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidGateway, gateway, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w %q: http or https url required", ErrInvalidGateway, gateway)
+	}
+	return &Gateway{
+		url:     u,
+		client:  o.Client,
+		timeout: o.Timeout,
+	}, nil
+}
+
+// File is a file of the imported DAG.
+type File struct {
+	// Path is the slash separated path of the file in the root
+	// directory. It is empty if the root of the DAG is a file.
+	Path string
+	// Name is the last element of the Path.
+	Name string
+	// Size is the size of the file content.
+	Size int64
+	// Reader reads the file content, retrieving its blocks on demand.
+	Reader io.Reader
+}
+
+// Walker iterates over the files of a DAG.
+type Walker struct {
+	gateway *Gateway
+	ctx     context.Context
+	dir     bool
+	root    *node
+	stack   []entry // directory entries to visit, the next one is the last
+}
+
+type entry struct {
+	path string
+	cid  cid.Cid
+}
+
+// Walk returns the Walker over the files of the DAG with the root CID.
+func (g *Gateway) Walk(ctx context.Context, root cid.Cid) (*Walker, error) {
+	n, err := g.get(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Walker{gateway: g, ctx: ctx}
+	switch n.typ {
+	case typeFile:
+		w.root = n
+	case typeDirectory:
+		w.dir = true
+		w.push("", n)
+	default:
+		return nil, fmt.Errorf("%w: unixfs type %d of %s", ErrUnsupported, n.typ, root)
+	}
+	return w, nil
+}
+
+// Dir reports whether the root of the DAG is a directory.
+func (w *Walker) Dir() bool {
+	return w.dir
+}
+
+// Next returns the next file of the DAG in the order the files are listed
+// in their directories, or io.EOF if there are no more files. The file must
+// be read before the next call of Next.
+func (w *Walker) Next() (*File, error) {
+	if w.root != nil {
+		n := w.root
+		w.root = nil
+		return w.file("", n), nil
+	}
+
+	for len(w.stack) > 0 {
+		e := w.stack[len(w.stack)-1]
+		w.stack = w.stack[:len(w.stack)-1]
+
+		n, err := w.gateway.get(w.ctx, e.cid)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.path, err)
+		}
+		switch n.typ {
+		case typeFile:
+			return w.file(e.path, n), nil
+		case typeDirectory:
+			w.push(e.path, n)
+		case typeSymlink:
+			// symlinks can not be represented in the manifests
+		default:
+			return nil, fmt.Errorf("%s: %w: unixfs type %d", e.path, ErrUnsupported, n.typ)
+		}
+	}
+	return nil, io.EOF
+}
+
+// push adds the entries of the directory to the stack, so that
+// they are visited in the order they are listed in the directory.
+func (w *Walker) push(dir string, n *node) {
+	for i := len(n.links) - 1; i >= 0; i-- {
+		l := n.links[i]
+		w.stack = append(w.stack, entry{path: path.Join(dir, l.name), cid: l.cid})
+	}
+}
+
+func (w *Walker) file(p string, n *node) *File {
+	return &File{
+		Path:   p,
+		Name:   path.Base(p),
+		Size:   int64(n.size),
+		Reader: &fileReader{ctx: w.ctx, gateway: w.gateway, data: n.data, stack: reversed(n.links)},
+	}
+}
+
+// fileReader reads the content of a file from the blocks
+// of its DAG, which are retrieved in the depth first order.
+type fileReader struct {
+	ctx     context.Context
+	gateway *Gateway
+	data    []byte
+	stack   []link
+}
+
+func (r *fileReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		if len(r.stack) == 0 {
+			return 0, io.EOF
+		}
+		l := r.stack[len(r.stack)-1]
+		r.stack = r.stack[:len(r.stack)-1]
+
+		n, err := r.gateway.get(r.ctx, l.cid)
+		if err != nil {
+			return 0, err
+		}
+		if n.typ != typeFile {
+			return 0, fmt.Errorf("%w: unixfs type %d in file", ErrInvalidBlock, n.typ)
+		}
+		r.data = n.data
+		r.stack = append(r.stack, reversed(n.links)...)
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func reversed(links []link) []link {
+	r := make([]link, len(links))
+	for i, l := range links {
+		r[len(links)-1-i] = l
+	}
+	return r
+}
+
+// get retrieves the block with the CID, verifies and decodes it.
+func (g *Gateway) get(ctx context.Context, c cid.Cid) (*node, error) {
+	data, err := g.block(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.Type() {
+	case cid.Raw:
+		return &node{typ: typeFile, data: data, size: uint64(len(data))}, nil
+	case cid.DagProtobuf:
+		n, err := decodeNode(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %v", ErrInvalidBlock, c, err)
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("%w: codec %#x of %s", ErrUnsupported, c.Type(), c)
+	}
+}
+
+// block retrieves the data of the block with the CID from the gateway.
+func (g *Gateway) block(ctx context.Context, c cid.Cid) ([]byte, error) {
+	prefix := c.Prefix()
+	if prefix.MhType == multihash.IDENTITY {
+		// the data is inlined in the CID
+		mh, err := multihash.Decode(c.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %v", ErrInvalidBlock, c, err)
+		}
+		return mh.Digest, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+
+	u := g.url.JoinPath("ipfs", c.String())
+	u.RawQuery = "format=raw"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", contentTypeRaw)
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, c)
+	default:
+		return nil, fmt.Errorf("block %s: unexpected response status %s", c, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxBlockSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxBlockSize {
+		return nil, fmt.Errorf("%w %s: too large", ErrInvalidBlock, c)
+	}
+	sum, err := prefix.Sum(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %v", ErrUnsupported, c, err)
+	}
+	if !sum.Equals(c) {
+		return nil, fmt.Errorf("%w %s: hash mismatch", ErrInvalidBlock, c)
+	}
+	return data, nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ipfs_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/ipfs"
+	"github.com/ethersphere/bee/pkg/ipfs/ipfstest"
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+)
+
+func newGateway(t *testing.T) (*ipfstest.Gateway, *ipfs.Gateway) {
+	t.Helper()
+
+	srv := ipfstest.NewGateway(t)
+	g, err := ipfs.New(srv.URL, ipfs.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv, g
+}
+
+func readAll(t *testing.T, w *ipfs.Walker) map[string][]byte {
+	t.Helper()
+
+	files := make(map[string][]byte)
+	for {
+		f, err := w.Next()
+		if errors.Is(err, io.EOF) {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(f.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Path] = data
+	}
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+
+		srv, g := newGateway(t)
+		data := bytes.Repeat([]byte("swarm "), 1000)
+		root := srv.AddFile(data, 1024)
+
+		w, err := g.Walk(context.Background(), root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.Dir() {
+			t.Fatal("got directory, want file")
+		}
+		f, err := w.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Path != "" || f.Size != int64(len(data)) {
+			t.Fatalf("got file %q of size %d, want root file of size %d", f.Path, f.Size, len(data))
+		}
+		got, err := io.ReadAll(f.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatal("got different file content")
+		}
+		if _, err := w.Next(); !errors.Is(err, io.EOF) {
+			t.Fatalf("got error %v, want %v", err, io.EOF)
+		}
+	})
+
+	t.Run("nested file", func(t *testing.T) {
+		t.Parallel()
+
+		// the file node with data and the links to
+		// the intermediate node and to the raw leaf
+		srv, g := newGateway(t)
+		inner := srv.AddNode(ipfstest.TypeFile, []byte("b"), ipfstest.Link{CID: srv.AddRaw([]byte("c"))})
+		root := srv.AddNode(ipfstest.TypeFile, []byte("a"), ipfstest.Link{CID: inner}, ipfstest.Link{CID: srv.AddRaw([]byte("d"))})
+
+		w, err := g.Walk(context.Background(), root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := readAll(t, w)[""]; string(got) != "abcd" {
+			t.Fatalf("got content %q, want %q", got, "abcd")
+		}
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		t.Parallel()
+
+		srv, g := newGateway(t)
+		files := map[string][]byte{
+			"index.html":    []byte("<html></html>"),
+			"img/logo.png":  bytes.Repeat([]byte{1, 2, 3}, 500),
+			"img/empty.txt": {},
+		}
+		img := srv.AddDir(
+			ipfstest.Link{Name: "empty.txt", CID: srv.AddFile(files["img/empty.txt"], 256)},
+			ipfstest.Link{Name: "logo.png", CID: srv.AddFile(files["img/logo.png"], 256)},
+		)
+		root := srv.AddDir(
+			ipfstest.Link{Name: "img", CID: img},
+			ipfstest.Link{Name: "index.html", CID: srv.AddFile(files["index.html"], 256)},
+		)
+
+		w, err := g.Walk(context.Background(), root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !w.Dir() {
+			t.Fatal("got file, want directory")
+		}
+		got := readAll(t, w)
+		if len(got) != len(files) {
+			t.Fatalf("got %d files, want %d", len(got), len(files))
+		}
+		for p, data := range files {
+			if !bytes.Equal(got[p], data) {
+				t.Fatalf("file %s: got content %q, want %q", p, got[p], data)
+			}
+		}
+	})
+
+	t.Run("inlined", func(t *testing.T) {
+		t.Parallel()
+
+		_, g := newGateway(t)
+		root, err := cid.Prefix{Version: 1, Codec: cid.Raw, MhType: multihash.IDENTITY, MhLength: -1}.Sum([]byte("inlined"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w, err := g.Walk(context.Background(), root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := readAll(t, w)[""]; string(got) != "inlined" {
+			t.Fatalf("got content %q, want %q", got, "inlined")
+		}
+	})
+}
+
+func TestWalkErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		srv, g := newGateway(t)
+		leaf := srv.AddRaw([]byte("missing"))
+		root := srv.AddDir(ipfstest.Link{Name: "missing.txt", CID: leaf})
+		srv.Remove(leaf)
+
+		w, err := g.Walk(context.Background(), root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Next(); !errors.Is(err, ipfs.ErrNotFound) {
+			t.Fatalf("got error %v, want %v", err, ipfs.ErrNotFound)
+		}
+	})
+
+	t.Run("invalid block", func(t *testing.T) {
+		t.Parallel()
+
+		srv, g := newGateway(t)
+		root := srv.AddRaw([]byte("original"))
+		srv.Put(root, []byte("tampered"))
+
+		if _, err := g.Walk(context.Background(), root); !errors.Is(err, ipfs.ErrInvalidBlock) {
+			t.Fatalf("got error %v, want %v", err, ipfs.ErrInvalidBlock)
+		}
+	})
+
+	t.Run("invalid entry name", func(t *testing.T) {
+		t.Parallel()
+
+		srv, g := newGateway(t)
+		root := srv.AddDir(ipfstest.Link{Name: "../escape", CID: srv.AddRaw([]byte("data"))})
+
+		if _, err := g.Walk(context.Background(), root); !errors.Is(err, ipfs.ErrInvalidBlock) {
+			t.Fatalf("got error %v, want %v", err, ipfs.ErrInvalidBlock)
+		}
+	})
+
+	t.Run("hamt shard", func(t *testing.T) {
+		t.Parallel()
+
+		srv, g := newGateway(t)
+		root := srv.AddNode(ipfstest.TypeHAMTShard, nil)
+
+		if _, err := g.Walk(context.Background(), root); !errors.Is(err, ipfs.ErrUnsupported) {
+			t.Fatalf("got error %v, want %v", err, ipfs.ErrUnsupported)
+		}
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	for _, g := range []string{"ftp://gateway.example.org", "gateway.example.org", "https://"} {
+		if _, err := ipfs.New(g, ipfs.Options{}); !errors.Is(err, ipfs.ErrInvalidGateway) {
+			t.Fatalf("gateway %q: got error %v, want %v", g, err, ipfs.ErrInvalidGateway)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ipfstest provides an IPFS gateway serving the blocks of
+// the UnixFS DAGs built in the tests.
+package ipfstest
+
+import (
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+)
+
+// The UnixFS data types.
+const (
+	TypeDirectory = 1
+	TypeFile      = 2
+	TypeHAMTShard = 5
+)
+
+// Link is a named link of a dag-pb node.
+type Link struct {
+	Name string
+	CID  cid.Cid
+}
+
+// Gateway is the test server serving the blocks in the raw format.
+type Gateway struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	blocks map[string][]byte
+}
+
+// NewGateway starts the Gateway which is closed at the end of the test.
+func NewGateway(t *testing.T) *Gateway {
+	t.Helper()
+
+	g := &Gateway{blocks: make(map[string][]byte)}
+	g.Server = httptest.NewServer(http.HandlerFunc(g.handle))
+	t.Cleanup(g.Close)
+	return g
+}
+
+func (g *Gateway) handle(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("format") != "raw" {
+		http.Error(w, "unsupported format", http.StatusBadRequest)
+		return
+	}
+
+	g.mu.Lock()
+	data, ok := g.blocks[strings.TrimPrefix(r.URL.Path, "/ipfs/")]
+	g.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/vnd.ipld.raw")
+	_, _ = w.Write(data)
+}
+
+// Put serves the data as the block with the CID, regardless of its hash.
+func (g *Gateway) Put(c cid.Cid, data []byte) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.blocks[c.String()] = data
+}
+
+// Remove stops serving the block with the CID.
+func (g *Gateway) Remove(c cid.Cid) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.blocks, c.String())
+}
+
+// AddRaw adds the raw block with the data.
+func (g *Gateway) AddRaw(data []byte) cid.Cid {
+	return g.add(cid.Raw, data)
+}
+
+// AddNode adds the dag-pb node of the UnixFS type with the data and the links.
+func (g *Gateway) AddNode(typ uint64, data []byte, links ...Link) cid.Cid {
+	var unixfs []byte
+	unixfs = appendVarint(unixfs, 1, typ)
+	if data != nil {
+		unixfs = appendBytes(unixfs, 2, data)
+	}
+
+	var b []byte
+	for _, l := range links {
+		var lb []byte
+		lb = appendBytes(lb, 1, l.CID.Bytes())
+		lb = appendBytes(lb, 2, []byte(l.Name))
+		b = appendBytes(b, 2, lb)
+	}
+	b = appendBytes(b, 1, unixfs)
+	return g.add(cid.DagProtobuf, b)
+}
+
+// AddFile adds the file with the data split into the raw leaves of the
+// blockSize, which are linked from a dag-pb node if there are more of them.
+func (g *Gateway) AddFile(data []byte, blockSize int) cid.Cid {
+	if len(data) <= blockSize {
+		return g.AddRaw(data)
+	}
+
+	var links []Link
+	for i := 0; i < len(data); i += blockSize {
+		end := i + blockSize
+		if end > len(data) {
+			end = len(data)
+		}
+		links = append(links, Link{CID: g.AddRaw(data[i:end])})
+	}
+
+	var unixfs []byte
+	unixfs = appendVarint(unixfs, 1, TypeFile)
+	unixfs = appendVarint(unixfs, 3, uint64(len(data)))
+	var b []byte
+	for _, l := range links {
+		b = appendBytes(b, 2, appendBytes(nil, 1, l.CID.Bytes()))
+	}
+	b = appendBytes(b, 1, unixfs)
+	return g.add(cid.DagProtobuf, b)
+}
+
+// AddDir adds the directory with the links to its entries.
+func (g *Gateway) AddDir(links ...Link) cid.Cid {
+	return g.AddNode(TypeDirectory, nil, links...)
+}
+
+func (g *Gateway) add(codec uint64, data []byte) cid.Cid {
+	c, err := cid.Prefix{
+		Version:  1,
+		Codec:    codec,
+		MhType:   multihash.SHA2_256,
+		MhLength: -1,
+	}.Sum(data)
+	if err != nil {
+		panic(err)
+	}
+	g.Put(c, data)
+	return c
+}
+
+func appendVarint(b []byte, num int, v uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(num)<<3)
+	return binary.AppendUvarint(b, v)
+}
+
+func appendBytes(b []byte, num int, v []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(num)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
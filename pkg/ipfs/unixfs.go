@@ -0,0 +1,172 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ipfs
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+)
+
+// The UnixFS data types.
+const (
+	typeRaw       = 0
+	typeDirectory = 1
+	typeFile      = 2
+	typeMetadata  = 3
+	typeSymlink   = 4
+	typeHAMTShard = 5
+)
+
+var errMalformed = errors.New("malformed protobuf")
+
+// node is a decoded dag-pb block with the UnixFS data.
+type node struct {
+	typ   uint64
+	data  []byte // content of the file block
+	size  uint64 // size of the file content
+	links []link
+}
+
+type link struct {
+	name string
+	cid  cid.Cid
+}
+
+// decodeNode decodes the dag-pb block. The messages are decoded by hand as
+// only a few fields of them are needed:
+//
+//	message PBLink { optional bytes Hash = 1; optional string Name = 2; optional uint64 Tsize = 3; }
+//	message PBNode { repeated PBLink Links = 2; optional bytes Data = 1; }
+//	message Data { required DataType Type = 1; optional bytes Data = 2; optional uint64 filesize = 3; ... }
+func decodeNode(b []byte) (*node, error) {
+	var (
+		n       = new(node)
+		unixfs  []byte
+		hasData bool
+	)
+	err := decodeFields(b, func(num int, _ uint64, v []byte) error {
+		switch num {
+		case 1:
+			unixfs, hasData = v, true
+		case 2:
+			l, err := decodeLink(v)
+			if err != nil {
+				return err
+			}
+			n.links = append(n.links, l)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !hasData {
+		return nil, errors.New("no unixfs data")
+	}
+
+	err = decodeFields(unixfs, func(num int, u uint64, v []byte) error {
+		switch num {
+		case 1:
+			n.typ = u
+		case 2:
+			n.data = v
+		case 3:
+			n.size = u
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.typ {
+	case typeRaw:
+		// the leaves of the files created by the older implementations
+		n.typ = typeFile
+		if n.size == 0 {
+			n.size = uint64(len(n.data))
+		}
+	case typeDirectory:
+		for _, l := range n.links {
+			if l.name == "" || l.name == "." || l.name == ".." || strings.ContainsRune(l.name, '/') {
+				return nil, fmt.Errorf("invalid directory entry name %q", l.name)
+			}
+		}
+	}
+	return n, nil
+}
+
+func decodeLink(b []byte) (l link, err error) {
+	err = decodeFields(b, func(num int, _ uint64, v []byte) error {
+		switch num {
+		case 1:
+			c, err := cid.Cast(v)
+			if err != nil {
+				return err
+			}
+			l.cid = c
+		case 2:
+			l.name = string(v)
+		}
+		return nil
+	})
+	if err == nil && !l.cid.Defined() {
+		err = errors.New("link without hash")
+	}
+	return l, err
+}
+
+// decodeFields calls f with the number and the value of every field of the
+// protobuf message, where the varints are passed as u and the length
+// delimited values as v. The fixed size fields are skipped.
+func decodeFields(b []byte, f func(num int, u uint64, v []byte) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errMalformed
+		}
+		b = b[n:]
+
+		var (
+			u uint64
+			v []byte
+		)
+		switch key & 7 {
+		case 0: // varint
+			if u, n = binary.Uvarint(b); n <= 0 {
+				return errMalformed
+			}
+			b = b[n:]
+		case 1: // fixed64
+			if len(b) < 8 {
+				return errMalformed
+			}
+			b = b[8:]
+			continue
+		case 2: // length delimited
+			l, n := binary.Uvarint(b)
+			if n <= 0 || l > uint64(len(b)-n) {
+				return errMalformed
+			}
+			v, b = b[n:n+int(l)], b[n+int(l):]
+		case 5: // fixed32
+			if len(b) < 4 {
+				return errMalformed
+			}
+			b = b[4:]
+			continue
+		default:
+			return errMalformed
+		}
+		if err := f(int(key>>3), u, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
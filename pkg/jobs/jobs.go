@@ -0,0 +1,473 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package jobs runs the long-running tasks of the node, such as the
+// warming, the pinning and the re-uploading of the content, in the
+// background. The jobs are persisted in the state store with their
+// parameters, progress and state, so that they can be listed and cancelled
+// in a uniform way, and the jobs interrupted by the shutdown of the node
+// are resumed on the next start.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "jobs"
+
+const (
+	keyPrefix       = "jobs_"
+	maxFinishedJobs = 100              // how many finished jobs are kept for the status requests
+	persistInterval = 10 * time.Second // how often the progress of the running jobs is persisted
+)
+
+var (
+	// ErrNotFound is returned if there is no job with the given id.
+	ErrNotFound = errors.New("job not found")
+	// ErrNotRunning is returned by Cancel if the job is already finished.
+	ErrNotRunning = errors.New("job not running")
+	// ErrTooManyJobs is returned by Submit if the maximal
+	// number of the jobs of the kind are already running.
+	ErrTooManyJobs = errors.New("too many running jobs")
+	// ErrUnknownKind is returned by Submit if no runner
+	// is registered for the kind of the job.
+	ErrUnknownKind = errors.New("unknown job kind")
+)
+
+// Status is the status of a job.
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusDone      Status = "done"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Job is the state of a job.
+type Job struct {
+	ID     uint64          `json:"id"`
+	Kind   string          `json:"kind"`
+	Params json.RawMessage `json:"params"`
+	Status Status          `json:"status"`
+	// Done and Total are the progress of the job in the units of its
+	// kind, the Total is zero if it is not known.
+	Done  int64 `json:"done"`
+	Total int64 `json:"total"`
+	// State is the last state of the job set by its runner, from
+	// which the job continues when it is resumed.
+	State      json.RawMessage `json:"state,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Resumed    int             `json:"resumed"` // number of the resumes after the restarts
+	CreatedAt  time.Time       `json:"createdAt"`
+	StartedAt  time.Time       `json:"startedAt"`
+	FinishedAt time.Time       `json:"finishedAt"`
+}
+
+// Runner runs the job of a kind. The context is canceled when the job is
+// cancelled or the node shuts down, in the latter case the job is resumed
+// on the next start and the runner is called again with the same task.
+type Runner func(ctx context.Context, t *Task) error
+
+// Task gives the runner the access to the job which it runs.
+type Task struct {
+	m *Manager
+	e *entry
+}
+
+// ID returns the id of the job.
+func (t *Task) ID() uint64 {
+	return t.e.job.ID
+}
+
+// Params unmarshals the parameters of the job into v.
+func (t *Task) Params(v interface{}) error {
+	return json.Unmarshal(t.e.job.Params, v)
+}
+
+// State unmarshals the last state of the job into v and reports whether
+// there was any, which is the case only for the resumed jobs.
+func (t *Task) State(v interface{}) (bool, error) {
+	t.m.mu.Lock()
+	state := t.e.job.State
+	t.m.mu.Unlock()
+
+	if len(state) == 0 {
+		return false, nil
+	}
+	return true, json.Unmarshal(state, v)
+}
+
+// SetState sets the state of the job, which is persisted
+// with the progress of the job.
+func (t *Task) SetState(v interface{}) error {
+	state, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	t.m.mu.Lock()
+	defer t.m.mu.Unlock()
+
+	t.e.job.State = state
+	t.e.dirty = true
+	return nil
+}
+
+// Progress sets the progress of the job.
+func (t *Task) Progress(done, total int64) {
+	t.m.mu.Lock()
+	defer t.m.mu.Unlock()
+
+	t.e.job.Done = done
+	t.e.job.Total = total
+	t.e.dirty = true
+}
+
+type kind struct {
+	limit   int
+	run     Runner
+	running int
+}
+
+// admits reports whether one more job of the kind can be started.
+func (k *kind) admits() bool {
+	return k.limit <= 0 || k.running < k.limit
+}
+
+type entry struct {
+	job       Job
+	cancel    context.CancelFunc // nil if the job is not started
+	cancelled bool
+	dirty     bool // the job changed since it was persisted
+}
+
+// Manager runs and persists the jobs.
+type Manager struct {
+	store   storage.StateStorer
+	logger  log.Logger
+	metrics metrics
+
+	mu       sync.Mutex
+	kinds    map[string]*kind
+	jobs     map[uint64]*entry
+	finished []uint64 // ids of the finished jobs in the order of finishing
+	lastID   uint64
+	resumed  bool // the interrupted jobs are started as the places free up
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a new Manager which loads the persisted jobs from the store.
+// The running jobs are resumed by Resume after the runners of their kinds
+// are registered.
+func New(store storage.StateStorer, logger log.Logger) (*Manager, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Manager{
+		store:   store,
+		logger:  logger.WithName(loggerName).Register(),
+		metrics: newMetrics(),
+		kinds:   make(map[string]*kind),
+		jobs:    make(map[uint64]*entry),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	var finished []Job
+	err := store.Iterate(keyPrefix, func(_, value []byte) (bool, error) {
+		var j Job
+		if err := json.Unmarshal(value, &j); err != nil {
+			return true, err
+		}
+		s.jobs[j.ID] = &entry{job: j}
+		if j.ID > s.lastID {
+			s.lastID = j.ID
+		}
+		if j.Status != StatusRunning {
+			finished = append(finished, j)
+		}
+		return false, nil
+	})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("load jobs: %w", err)
+	}
+	sort.Slice(finished, func(i, k int) bool { return finished[i].FinishedAt.Before(finished[k].FinishedAt) })
+	for _, j := range finished {
+		s.finished = append(s.finished, j.ID)
+	}
+
+	s.wg.Add(1)
+	go s.persistWorker()
+
+	return s, nil
+}
+
+// Register registers the runner of the jobs of the kind, of which at most
+// limit are running at the same time, unlimited if the limit is zero.
+func (s *Manager) Register(kindName string, limit int, run Runner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.kinds[kindName] = &kind{limit: limit, run: run}
+}
+
+// Resume resumes the jobs interrupted by the last shutdown of the node, it is
+// called once the node is able to run them. The jobs of the kinds which are
+// not registered fail. The jobs over the limit of their kind are resumed as
+// the running jobs of the kind finish.
+func (s *Manager) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx.Err() != nil {
+		// closed
+		return
+	}
+	s.resumed = true
+	for _, e := range s.interrupted("") {
+		if _, ok := s.kinds[e.job.Kind]; !ok {
+			e.job.Error = ErrUnknownKind.Error()
+			s.finish(e, StatusFailed)
+		}
+	}
+	for name, k := range s.kinds {
+		s.resume(name, k)
+	}
+}
+
+// interrupted returns the interrupted jobs which are not resumed yet, of the
+// kind or of all kinds if the kind is empty, sorted by the id. It must be
+// called with the mutex locked.
+func (s *Manager) interrupted(kindName string) []*entry {
+	var entries []*entry
+	for _, e := range s.jobs {
+		if e.job.Status == StatusRunning && e.cancel == nil && (kindName == "" || e.job.Kind == kindName) {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, k int) bool { return entries[i].job.ID < entries[k].job.ID })
+	return entries
+}
+
+// resume starts the interrupted jobs of the kind within its limit,
+// it must be called with the mutex locked.
+func (s *Manager) resume(kindName string, k *kind) {
+	if !s.resumed {
+		return
+	}
+	for _, e := range s.interrupted(kindName) {
+		if !k.admits() {
+			return
+		}
+		e.job.Resumed++
+		s.metrics.ResumedJobs.WithLabelValues(e.job.Kind).Inc()
+		s.logger.Debug("resuming job", "id", e.job.ID, "kind", e.job.Kind)
+		s.start(e, k)
+	}
+}
+
+// Submit starts the job of the kind with the parameters,
+// which are marshaled to JSON. It returns the initial state of the job.
+func (s *Manager) Submit(kindName string, params interface{}) (Job, error) {
+	p, err := json.Marshal(params)
+	if err != nil {
+		return Job{}, fmt.Errorf("marshal params: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k, ok := s.kinds[kindName]
+	if !ok {
+		return Job{}, ErrUnknownKind
+	}
+	if !k.admits() {
+		return Job{}, ErrTooManyJobs
+	}
+
+	e := &entry{job: Job{
+		ID:        s.lastID + 1,
+		Kind:      kindName,
+		Params:    p,
+		Status:    StatusRunning,
+		CreatedAt: time.Now(),
+	}}
+	if err := s.store.Put(key(e.job.ID), e.job); err != nil {
+		return Job{}, fmt.Errorf("persist job: %w", err)
+	}
+	s.lastID++
+	s.jobs[e.job.ID] = e
+	s.start(e, k)
+
+	return e.job, nil
+}
+
+// Job returns the state of the job with the given id.
+func (s *Manager) Job(id uint64) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return e.job, nil
+}
+
+// Jobs returns the states of the jobs of the kind sorted by the id,
+// or of all jobs if the kind is empty.
+func (s *Manager) Jobs(kindName string) []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, e := range s.jobs {
+		if kindName == "" || e.job.Kind == kindName {
+			jobs = append(jobs, e.job)
+		}
+	}
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].ID < jobs[k].ID })
+	return jobs
+}
+
+// Cancel cancels the running job with the given id.
+func (s *Manager) Cancel(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.jobs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if e.job.Status != StatusRunning || e.cancelled {
+		return ErrNotRunning
+	}
+	e.cancelled = true
+	if e.cancel == nil {
+		// the job is not resumed yet
+		s.finish(e, StatusCancelled)
+		return nil
+	}
+	e.cancel()
+	return nil
+}
+
+// Close stops the running jobs, which are resumed on the next start.
+func (s *Manager) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// start runs the job, it must be called with the mutex locked.
+func (s *Manager) start(e *entry, k *kind) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	e.cancel = cancel
+	e.job.StartedAt = time.Now()
+	k.running++
+	s.metrics.RunningJobs.WithLabelValues(e.job.Kind).Inc()
+
+	s.wg.Add(1)
+	go s.run(ctx, e, k)
+}
+
+func (s *Manager) run(ctx context.Context, e *entry, k *kind) {
+	defer s.wg.Done()
+
+	err := k.run(ctx, &Task{m: s, e: e})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e.cancel()
+	k.running--
+	s.metrics.RunningJobs.WithLabelValues(e.job.Kind).Dec()
+	if s.ctx.Err() == nil {
+		defer s.resume(e.job.Kind, k)
+	}
+
+	switch {
+	case e.cancelled:
+		s.finish(e, StatusCancelled)
+	case s.ctx.Err() != nil:
+		// interrupted by the shutdown, the job is resumed on the next start
+		s.persist(e)
+	case err == nil:
+		s.finish(e, StatusDone)
+	default:
+		e.job.Error = err.Error()
+		s.logger.Debug("job failed", "id", e.job.ID, "kind", e.job.Kind, "error", err)
+		s.finish(e, StatusFailed)
+	}
+}
+
+// finish sets the final status of the job and removes the oldest finished
+// jobs over the limit, it must be called with the mutex locked.
+func (s *Manager) finish(e *entry, status Status) {
+	e.job.Status = status
+	e.job.FinishedAt = time.Now()
+	s.persist(e)
+	s.metrics.FinishedJobs.WithLabelValues(e.job.Kind, string(status)).Inc()
+
+	s.finished = append(s.finished, e.job.ID)
+	for len(s.finished) > maxFinishedJobs {
+		id := s.finished[0]
+		s.finished = s.finished[1:]
+		delete(s.jobs, id)
+		if err := s.store.Delete(key(id)); err != nil {
+			s.logger.Error(err, "delete job failed", "id", id)
+		}
+	}
+}
+
+// persist stores the job, it must be called with the mutex locked.
+func (s *Manager) persist(e *entry) {
+	if err := s.store.Put(key(e.job.ID), e.job); err != nil {
+		s.logger.Error(err, "persist job failed", "id", e.job.ID)
+		return
+	}
+	e.dirty = false
+}
+
+// persistWorker periodically persists the progress of the running jobs.
+func (s *Manager) persistWorker() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(persistInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		for _, e := range s.jobs {
+			if e.dirty && e.job.Status == StatusRunning {
+				s.persist(e)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+func key(id uint64) string {
+	return keyPrefix + strconv.FormatUint(id, 10)
+}
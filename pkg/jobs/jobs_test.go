@@ -0,0 +1,290 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jobs"
+	"github.com/ethersphere/bee/pkg/log"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+type params struct {
+	N int64 `json:"n"`
+}
+
+type state struct {
+	Last int64 `json:"last"`
+}
+
+// count counts to the n of the parameters.
+func count(_ context.Context, t *jobs.Task) error {
+	var p params
+	if err := t.Params(&p); err != nil {
+		return err
+	}
+	for i := int64(1); i <= p.N; i++ {
+		t.Progress(i, p.N)
+		if err := t.SetState(state{Last: i}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// block runs until the job is cancelled.
+func block(ctx context.Context, t *jobs.Task) error {
+	if err := t.SetState(state{Last: 1}); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+
+	m := newManager(t, statestore.NewStateStore())
+	m.Register("count", 0, count)
+	m.Register("fail", 0, func(context.Context, *jobs.Task) error { return errors.New("failed") })
+	m.Register("block", 1, block)
+
+	t.Run("done", func(t *testing.T) {
+		t.Parallel()
+
+		j, err := m.Submit("count", params{N: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		j = waitJob(t, m, j.ID)
+		if j.Status != jobs.StatusDone || j.Done != 3 || j.Total != 3 || string(j.State) != `{"last":3}` {
+			t.Fatalf("got job %+v", j)
+		}
+		if err := m.Cancel(j.ID); !errors.Is(err, jobs.ErrNotRunning) {
+			t.Fatalf("got error %v, want %v", err, jobs.ErrNotRunning)
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		t.Parallel()
+
+		j, err := m.Submit("fail", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if j = waitJob(t, m, j.ID); j.Status != jobs.StatusFailed || j.Error != "failed" {
+			t.Fatalf("got job %+v", j)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		j, err := m.Submit("block", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := m.Submit("block", nil); !errors.Is(err, jobs.ErrTooManyJobs) {
+			t.Fatalf("got error %v, want %v", err, jobs.ErrTooManyJobs)
+		}
+		if err := m.Cancel(j.ID); err != nil {
+			t.Fatal(err)
+		}
+		if j = waitJob(t, m, j.ID); j.Status != jobs.StatusCancelled {
+			t.Fatalf("got job %+v", j)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := m.Submit("unknown", nil); !errors.Is(err, jobs.ErrUnknownKind) {
+			t.Fatalf("got error %v, want %v", err, jobs.ErrUnknownKind)
+		}
+		if _, err := m.Job(1000); !errors.Is(err, jobs.ErrNotFound) {
+			t.Fatalf("got error %v, want %v", err, jobs.ErrNotFound)
+		}
+		if err := m.Cancel(1000); !errors.Is(err, jobs.ErrNotFound) {
+			t.Fatalf("got error %v, want %v", err, jobs.ErrNotFound)
+		}
+	})
+}
+
+func TestManagerResume(t *testing.T) {
+	t.Parallel()
+
+	store := statestore.NewStateStore()
+
+	m, err := jobs.New(store, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Register("block", 0, block)
+	j, err := m.Submit("block", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orphan, err := m.Submit("block", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if j, err := m.Job(orphan.ID); err != nil || len(j.State) > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the state")
+		}
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	m = newManager(t, store)
+	resumed := make(chan state, 1)
+	m.Register("block", 0, func(_ context.Context, t *jobs.Task) error {
+		var s state
+		if ok, err := t.State(&s); !ok || err != nil {
+			return errors.New("no state")
+		}
+		resumed <- s
+		return nil
+	})
+
+	if err := m.Cancel(orphan.ID); err != nil {
+		t.Fatal(err)
+	}
+	m.Resume()
+
+	select {
+	case s := <-resumed:
+		if s.Last != 1 {
+			t.Fatalf("got state %+v", s)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("job not resumed")
+	}
+	if j = waitJob(t, m, j.ID); j.Status != jobs.StatusDone || j.Resumed != 1 {
+		t.Fatalf("got job %+v", j)
+	}
+	if j = waitJob(t, m, orphan.ID); j.Status != jobs.StatusCancelled {
+		t.Fatalf("got job %+v", j)
+	}
+}
+
+// TestManagerResumeLimit tests that the resumed jobs are
+// started within the limit of the running jobs of their kind.
+func TestManagerResumeLimit(t *testing.T) {
+	t.Parallel()
+
+	store := statestore.NewStateStore()
+	for id := uint64(1); id <= 3; id++ {
+		if err := store.Put(fmt.Sprintf("jobs_%d", id), jobs.Job{ID: id, Kind: "gate", Status: jobs.StatusRunning}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		running int
+		release = make(chan struct{})
+	)
+	m := newManager(t, store)
+	m.Register("gate", 1, func(ctx context.Context, _ *jobs.Task) error {
+		mu.Lock()
+		running++
+		n := running
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+		if n > 1 {
+			return errors.New("over the limit")
+		}
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	m.Resume()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case release <- struct{}{}:
+		case <-time.After(5 * time.Second):
+			t.Fatal("job not resumed")
+		}
+	}
+	for id := uint64(1); id <= 3; id++ {
+		if j := waitJob(t, m, id); j.Status != jobs.StatusDone || j.Resumed != 1 {
+			t.Fatalf("got job %+v", j)
+		}
+	}
+}
+
+func TestManagerResumeUnknownKind(t *testing.T) {
+	t.Parallel()
+
+	store := statestore.NewStateStore()
+	if err := store.Put("jobs_1", jobs.Job{ID: 1, Kind: "unknown", Status: jobs.StatusRunning}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newManager(t, store)
+	m.Resume()
+
+	j, err := m.Job(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.Status != jobs.StatusFailed || j.Error != jobs.ErrUnknownKind.Error() {
+		t.Fatalf("got job %+v", j)
+	}
+	if j, err := m.Submit("unknown", nil); !errors.Is(err, jobs.ErrUnknownKind) {
+		t.Fatalf("got job %+v, error %v", j, err)
+	}
+}
+
+func newManager(t *testing.T, store storage.StateStorer) *jobs.Manager {
+	t.Helper()
+
+	m, err := jobs.New(store, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := m.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return m
+}
+
+func waitJob(t *testing.T, m *jobs.Manager, id uint64) jobs.Job {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		j, err := m.Job(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if j.Status != jobs.StatusRunning {
+			return j
+		}
+	}
+	t.Fatal("timed out waiting for the job")
+	return jobs.Job{}
+}
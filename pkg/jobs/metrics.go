@@ -0,0 +1,55 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jobs
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	RunningJobs  *prometheus.GaugeVec
+	FinishedJobs *prometheus.CounterVec
+	ResumedJobs  *prometheus.CounterVec
+}
+
+func newMetrics() metrics {
+	subsystem := "jobs"
+
+	return metrics{
+		RunningJobs: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "running_jobs",
+				Help:      "Number of running jobs by kind.",
+			},
+			[]string{"kind"},
+		),
+		FinishedJobs: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "finished_jobs_total",
+				Help:      "Total number of finished jobs by kind and status.",
+			},
+			[]string{"kind", "status"},
+		),
+		ResumedJobs: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "resumed_jobs_total",
+				Help:      "Total number of jobs resumed after the restart by kind.",
+			},
+			[]string{"kind"},
+		),
+	}
+}
+
+// Metrics returns the prometheus collectors of the manager.
+func (s *Manager) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(s.metrics)
+}
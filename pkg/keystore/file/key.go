@@ -65,7 +65,9 @@ type kdfParams struct {
 	Salt  string `json:"salt"`
 }
 
-func encryptKey(k *ecdsa.PrivateKey, password string, edg keystore.EDG) ([]byte, error) {
+// EncryptKey encrypts the private key with the password in the Ethereum JSON
+// v3 key file format.
+func EncryptKey(k *ecdsa.PrivateKey, password string, edg keystore.EDG) ([]byte, error) {
 	data, err := edg.Encode(k)
 	if err != nil {
 		return nil, err
@@ -95,7 +97,9 @@ func encryptKey(k *ecdsa.PrivateKey, password string, edg keystore.EDG) ([]byte,
 	})
 }
 
-func decryptKey(data []byte, password string, edg keystore.EDG) (*ecdsa.PrivateKey, error) {
+// DecryptKey decrypts the private key from the Ethereum JSON v3 key file
+// format with the password.
+func DecryptKey(data []byte, password string, edg keystore.EDG) (*ecdsa.PrivateKey, error) {
 	var k encryptedKey
 	if err := json.Unmarshal(data, &k); err != nil {
 		return nil, err
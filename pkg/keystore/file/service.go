@@ -46,22 +46,28 @@ func (s *Service) SetKey(name, password string, edg keystore.EDG) (*ecdsa.Privat
 		return nil, fmt.Errorf("generate key: %w", err)
 	}
 
-	d, err := encryptKey(pk, password, edg)
-	if err != nil {
+	if err := s.Import(name, password, pk, edg); err != nil {
 		return nil, err
 	}
 
-	filename := s.keyFilename(name)
+	return pk, nil
+}
 
-	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
-		return nil, err
+// Import persists the provided private key with a name, encrypted with the
+// password. An existing key with the same name is replaced.
+func (s *Service) Import(name, password string, pk *ecdsa.PrivateKey, edg keystore.EDG) error {
+	d, err := EncryptKey(pk, password, edg)
+	if err != nil {
+		return err
 	}
 
-	if err := os.WriteFile(filename, d, 0600); err != nil {
-		return nil, err
+	filename := s.keyFilename(name)
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return err
 	}
 
-	return pk, nil
+	return os.WriteFile(filename, d, 0600)
 }
 
 func (s *Service) Key(name, password string, edg keystore.EDG) (pk *ecdsa.PrivateKey, created bool, err error) {
@@ -76,7 +82,7 @@ func (s *Service) Key(name, password string, edg keystore.EDG) (pk *ecdsa.Privat
 		return pk, true, err
 	}
 
-	pk, err = decryptKey(data, password, edg)
+	pk, err = DecryptKey(data, password, edg)
 	if err != nil {
 		return nil, false, err
 	}
@@ -5,8 +5,11 @@
 package file_test
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/keystore"
 	"github.com/ethersphere/bee/pkg/keystore/file"
 	"github.com/ethersphere/bee/pkg/keystore/test"
 )
@@ -18,3 +21,32 @@ func TestService(t *testing.T) {
 
 	test.Service(t, file.New(dir))
 }
+
+func TestServiceImport(t *testing.T) {
+	t.Parallel()
+
+	s := file.New(t.TempDir())
+
+	pk, err := crypto.EDGSecp256_K1.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Import("swarm", "pass", pk, crypto.EDGSecp256_K1); err != nil {
+		t.Fatal(err)
+	}
+
+	got, created, err := s.Key("swarm", "pass", crypto.EDGSecp256_K1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created {
+		t.Fatal("key created, want imported")
+	}
+	if !got.Equal(pk) {
+		t.Fatal("imported key not equal")
+	}
+
+	if _, _, err := s.Key("swarm", "wrong", crypto.EDGSecp256_K1); !errors.Is(err, keystore.ErrInvalidPassword) {
+		t.Fatalf("got error %v, want %v", err, keystore.ErrInvalidPassword)
+	}
+}
@@ -0,0 +1,335 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package legacy reads the content exported from the nodes of the Ethereum
+// Swarm 0.x, so that it can be imported into the current network.
+//
+// The export is the tar archive of the chunks named by their hex addresses,
+// optionally preceded by the .swarm-export-version file. The chunks of the
+// Swarm 0.3 and later are content addressed with the same BMT hash and are
+// encrypted with the same scheme as the current ones, so the files are read
+// with the joiner. The manifests are the JSON documents of the entries, which
+// are flattened into the list of the files of the collection.
+package legacy
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const (
+	// ManifestContentType is the content type of the entries
+	// of the legacy manifests which link to other manifests.
+	ManifestContentType = "application/bzz-manifest+json"
+	// MaxManifestSize is the maximal size of a legacy manifest.
+	MaxManifestSize = 10 * 1024 * 1024
+
+	exportVersionFilename = ".swarm-export-version"
+	maxManifestDepth      = 64
+	defaultIndexName      = "index"
+	defaultIndexHTMLName  = "index.html"
+)
+
+var (
+	// ErrInvalidArchive is returned if the export is not a tar archive of chunks.
+	ErrInvalidArchive = errors.New("invalid archive")
+	// ErrInvalidChunk is returned if a chunk of the archive
+	// does not match its address or is too large.
+	ErrInvalidChunk = errors.New("invalid chunk")
+	// ErrMissingChunk is returned if a chunk of the content is not in the archive.
+	ErrMissingChunk = errors.New("chunk missing in the archive")
+	// ErrUnsupported is returned for the manifest entries which can not
+	// be converted, like the access controlled entries and the feeds.
+	ErrUnsupported = errors.New("unsupported manifest entry")
+)
+
+// Archive holds the chunks of the export in memory.
+type Archive struct {
+	chunks map[string][]byte
+}
+
+// ReadArchive reads the chunks of the export from the tar archive and
+// verifies them against their addresses.
+func ReadArchive(r io.Reader) (*Archive, error) {
+	a := &Archive{chunks: make(map[string][]byte)}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+		}
+		if hdr.Typeflag != tar.TypeReg || path.Base(hdr.Name) == exportVersionFilename {
+			continue
+		}
+
+		addr, err := hex.DecodeString(path.Base(hdr.Name))
+		if err != nil || len(addr) != swarm.HashSize {
+			return nil, fmt.Errorf("%w: file %q is not a chunk", ErrInvalidArchive, hdr.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, swarm.ChunkWithSpanSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+		}
+		if len(data) > swarm.ChunkWithSpanSize {
+			return nil, fmt.Errorf("%w %x: too large", ErrInvalidChunk, addr)
+		}
+		if !cac.Valid(swarm.NewChunk(swarm.NewAddress(addr), data)) {
+			return nil, fmt.Errorf("%w %x: address mismatch", ErrInvalidChunk, addr)
+		}
+		a.chunks[string(addr)] = data
+	}
+	return a, nil
+}
+
+// Len returns the number of the chunks in the archive.
+func (a *Archive) Len() int {
+	return len(a.chunks)
+}
+
+// Get implements the storage.Getter interface.
+func (a *Archive) Get(_ context.Context, _ storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	data, ok := a.chunks[addr.ByteString()]
+	if !ok {
+		return nil, fmt.Errorf("%w %s: %w", ErrMissingChunk, addr, storage.ErrNotFound)
+	}
+	return swarm.NewChunk(addr, data), nil
+}
+
+// manifest is the legacy manifest document.
+type manifest struct {
+	Entries []manifestEntry `json:"entries"`
+}
+
+type manifestEntry struct {
+	Hash        string          `json:"hash"`
+	Path        string          `json:"path"`
+	ContentType string          `json:"contentType"`
+	Mode        int64           `json:"mode"`
+	Size        int64           `json:"size"`
+	ModTime     time.Time       `json:"mod_time"`
+	Status      int             `json:"status"`
+	Access      json.RawMessage `json:"access"`
+	Feed        json.RawMessage `json:"feed"`
+}
+
+// File is a file of the imported content.
+type File struct {
+	// Path is the slash separated path of the file in the collection.
+	// It is empty if the root of the content is not a manifest.
+	Path string
+	// Name is the last element of the Path.
+	Name string
+	// ContentType is the content type of the manifest entry.
+	ContentType string
+	// ModTime is the modification time of the manifest entry.
+	ModTime time.Time
+	// Size is the size of the file content.
+	Size int64
+	// Reader reads the file content.
+	Reader io.Reader
+}
+
+type fileEntry struct {
+	path        string
+	address     swarm.Address
+	contentType string
+	modTime     time.Time
+}
+
+// Walker iterates over the files of the legacy content.
+type Walker struct {
+	ctx     context.Context
+	archive *Archive
+	dir     bool
+	index   string
+	files   []fileEntry
+}
+
+// Walk returns the Walker over the files of the content with the root
+// reference, which is the manifest of the collection or the raw content
+// of a single file. The manifests are decoded on Walk, the content of the
+// files is read from the archive on demand.
+func (a *Archive) Walk(ctx context.Context, root swarm.Address) (*Walker, error) {
+	w := &Walker{ctx: ctx, archive: a}
+
+	m, err := a.manifest(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		w.files = []fileEntry{{address: root}}
+		return w, nil
+	}
+
+	w.dir = true
+	var defaultEntry *fileEntry
+	seen := map[string]bool{root.ByteString(): true}
+	err = a.flatten(ctx, m, "", 0, seen, func(e fileEntry) {
+		if e.path == "" {
+			defaultEntry = &e
+			return
+		}
+		w.files = append(w.files, e)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(w.files, func(i, j int) bool { return w.files[i].path < w.files[j].path })
+
+	if defaultEntry != nil {
+		w.setIndex(*defaultEntry)
+	}
+	return w, nil
+}
+
+// setIndex sets the index document of the collection to the file of the
+// default entry of the legacy manifest, which is served on the root path.
+// The file is added to the collection if it is not already one of its root
+// files, unless its name is taken by another file.
+func (w *Walker) setIndex(e fileEntry) {
+	for _, f := range w.files {
+		if !strings.Contains(f.path, "/") && f.address.Equal(e.address) {
+			w.index = f.path
+			return
+		}
+	}
+
+	name := defaultIndexName
+	if strings.HasPrefix(e.contentType, "text/html") {
+		name = defaultIndexHTMLName
+	}
+	for _, f := range w.files {
+		if f.path == name {
+			return
+		}
+	}
+	e.path = name
+	w.files = append(w.files, e)
+	w.index = name
+}
+
+// flatten calls the fn for the file entries of the manifest and of the
+// manifests it links to, with the paths prefixed by their manifest paths.
+func (a *Archive) flatten(ctx context.Context, m *manifest, prefix string, depth int, seen map[string]bool, fn func(fileEntry)) error {
+	if depth > maxManifestDepth {
+		return fmt.Errorf("%w: manifests nested too deep", ErrUnsupported)
+	}
+	for _, e := range m.Entries {
+		p := prefix + e.Path
+		switch {
+		case len(e.Access) > 0 && string(e.Access) != "null":
+			return fmt.Errorf("%w: access controlled entry %q", ErrUnsupported, p)
+		case len(e.Feed) > 0 && string(e.Feed) != "null":
+			return fmt.Errorf("%w: feed entry %q", ErrUnsupported, p)
+		}
+
+		addr, err := swarm.ParseHexAddress(e.Hash)
+		if err != nil || (len(addr.Bytes()) != swarm.HashSize && len(addr.Bytes()) != swarm.HashSize*2) {
+			return fmt.Errorf("%w: entry %q: invalid hash %q", ErrUnsupported, p, e.Hash)
+		}
+
+		if e.ContentType == ManifestContentType {
+			if seen[addr.ByteString()] {
+				return fmt.Errorf("%w: manifest %s links to itself", ErrUnsupported, addr)
+			}
+			seen[addr.ByteString()] = true
+			sub, err := a.manifest(ctx, addr)
+			if err != nil {
+				return err
+			}
+			if sub == nil {
+				return fmt.Errorf("%w: entry %q is not a manifest", ErrUnsupported, p)
+			}
+			if err := a.flatten(ctx, sub, p, depth+1, seen, fn); err != nil {
+				return err
+			}
+			delete(seen, addr.ByteString())
+			continue
+		}
+
+		fn(fileEntry{
+			path:        strings.TrimPrefix(p, "/"),
+			address:     addr,
+			contentType: e.ContentType,
+			modTime:     e.ModTime,
+		})
+	}
+	return nil
+}
+
+// manifest reads the manifest with the address,
+// it returns nil if the content is not a manifest.
+func (a *Archive) manifest(ctx context.Context, addr swarm.Address) (*manifest, error) {
+	j, size, err := joiner.New(ctx, a, addr)
+	if err != nil {
+		return nil, err
+	}
+	if size > MaxManifestSize {
+		return nil, nil
+	}
+	data, err := io.ReadAll(j)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	m := new(manifest)
+	if err := dec.Decode(m); err != nil || len(m.Entries) == 0 {
+		return nil, nil
+	}
+	return m, nil
+}
+
+// Dir reports whether the root of the content is a manifest.
+func (w *Walker) Dir() bool {
+	return w.dir
+}
+
+// Index returns the path of the index document of the collection, which is
+// the file of the default entry of the legacy manifest, if there is one.
+func (w *Walker) Index() string {
+	return w.index
+}
+
+// Next returns the next file of the content in the order of their paths,
+// or io.EOF if there are no more files.
+func (w *Walker) Next() (*File, error) {
+	if len(w.files) == 0 {
+		return nil, io.EOF
+	}
+	e := w.files[0]
+	w.files = w.files[1:]
+
+	j, size, err := joiner.New(w.ctx, w.archive, e.address)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", e.path, err)
+	}
+	return &File{
+		Path:        e.path,
+		Name:        path.Base(e.path),
+		ContentType: e.contentType,
+		ModTime:     e.modTime,
+		Size:        size,
+		Reader:      j,
+	}, nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package legacy_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/legacy"
+	"github.com/ethersphere/bee/pkg/legacy/legacytest"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func readAll(t *testing.T, w *legacy.Walker) map[string]*legacy.File {
+	t.Helper()
+
+	files := make(map[string]*legacy.File)
+	for {
+		f, err := w.Next()
+		if errors.Is(err, io.EOF) {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(f.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Reader = bytes.NewReader(data)
+		files[f.Path] = f
+	}
+}
+
+func content(t *testing.T, f *legacy.File) []byte {
+	t.Helper()
+
+	data, err := io.ReadAll(f.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func walk(t *testing.T, e *legacytest.Export, root swarm.Address) (*legacy.Walker, error) {
+	t.Helper()
+
+	a, err := legacy.ReadArchive(bytes.NewReader(e.Archive(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a.Walk(context.Background(), root)
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	for _, encrypt := range []bool{false, true} {
+		encrypt := encrypt
+		name := "plain"
+		if encrypt {
+			name = "encrypted"
+		}
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				e       = legacytest.NewExport()
+				index   = []byte("<h1>Swarm</h1>")
+				large   = bytes.Repeat([]byte("swarm "), 2000)
+				modTime = time.Unix(1546300800, 0).UTC()
+			)
+			indexRef := e.AddFile(t, index, encrypt)
+			imgRef := e.AddFile(t, []byte("image"), encrypt)
+			sub := e.AddManifest(t, []legacytest.Entry{
+				{Hash: imgRef.String(), Path: "1.png", ContentType: "image/png"},
+				{Hash: e.AddFile(t, large, encrypt).String(), Path: "data.txt", ContentType: "text/plain"},
+			}, encrypt)
+			root := e.AddManifest(t, []legacytest.Entry{
+				{Hash: indexRef.String(), ContentType: "text/html"},
+				{Hash: indexRef.String(), Path: "index.html", ContentType: "text/html", ModTime: modTime},
+				{Hash: sub.String(), Path: "img/", ContentType: legacy.ManifestContentType},
+			}, encrypt)
+
+			w, err := walk(t, e, root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !w.Dir() {
+				t.Fatal("expected collection")
+			}
+			if w.Index() != "index.html" {
+				t.Fatalf("got index %q, want index.html", w.Index())
+			}
+
+			files := readAll(t, w)
+			if len(files) != 3 {
+				t.Fatalf("got %d files, want 3", len(files))
+			}
+			f := files["index.html"]
+			if f == nil || !bytes.Equal(content(t, f), index) || f.ContentType != "text/html" || !f.ModTime.Equal(modTime) || f.Name != "index.html" {
+				t.Fatalf("got index file %+v", f)
+			}
+			if f := files["img/data.txt"]; f == nil || f.Size != int64(len(large)) || !bytes.Equal(content(t, f), large) || f.Name != "data.txt" {
+				t.Fatalf("got data file %+v", f)
+			}
+			if f := files["img/1.png"]; f == nil || f.ContentType != "image/png" {
+				t.Fatalf("got image file %+v", f)
+			}
+		})
+	}
+
+	t.Run("default entry only", func(t *testing.T) {
+		t.Parallel()
+
+		e := legacytest.NewExport()
+		root := e.AddManifest(t, []legacytest.Entry{
+			{Hash: e.AddFile(t, []byte("<h1>Swarm</h1>"), false).String(), ContentType: "text/html; charset=utf-8"},
+			{Hash: e.AddFile(t, []byte("robots"), false).String(), Path: "robots.txt", ContentType: "text/plain"},
+		}, false)
+
+		w, err := walk(t, e, root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.Index() != "index.html" {
+			t.Fatalf("got index %q, want index.html", w.Index())
+		}
+		files := readAll(t, w)
+		if f := files["index.html"]; f == nil || string(content(t, f)) != "<h1>Swarm</h1>" {
+			t.Fatalf("got index file %+v", f)
+		}
+	})
+
+	t.Run("raw", func(t *testing.T) {
+		t.Parallel()
+
+		e := legacytest.NewExport()
+		data := []byte(`{"entries":"not a manifest"}`)
+		root := e.AddFile(t, data, false)
+
+		w, err := walk(t, e, root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.Dir() {
+			t.Fatal("expected raw content")
+		}
+		files := readAll(t, w)
+		if f := files[""]; f == nil || !bytes.Equal(content(t, f), data) {
+			t.Fatalf("got files %+v", files)
+		}
+	})
+
+	t.Run("missing chunk", func(t *testing.T) {
+		t.Parallel()
+
+		e := legacytest.NewExport()
+		file := e.AddFile(t, []byte("swarm"), false)
+		root := e.AddManifest(t, []legacytest.Entry{{Hash: file.String(), Path: "a.txt"}}, false)
+		e.Remove(file)
+
+		w, err := walk(t, e, root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Next(); !errors.Is(err, legacy.ErrMissingChunk) {
+			t.Fatalf("got error %v, want %v", err, legacy.ErrMissingChunk)
+		}
+	})
+
+	t.Run("access controlled", func(t *testing.T) {
+		t.Parallel()
+
+		e := legacytest.NewExport()
+		root := e.AddManifest(t, []legacytest.Entry{{
+			Hash:   e.AddFile(t, []byte("secret"), false).String(),
+			Path:   "secret.txt",
+			Access: []byte(`{"type":"pass"}`),
+		}}, false)
+
+		if _, err := walk(t, e, root); !errors.Is(err, legacy.ErrUnsupported) {
+			t.Fatalf("got error %v, want %v", err, legacy.ErrUnsupported)
+		}
+	})
+}
+
+func TestReadArchive(t *testing.T) {
+	t.Parallel()
+
+	e := legacytest.NewExport()
+	data := make([]byte, 2*swarm.ChunkSize)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	e.AddFile(t, data, false)
+	archive := e.Archive(t)
+
+	a, err := legacy.ReadArchive(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Len() != 3 {
+		t.Fatalf("got %d chunks, want 3", a.Len())
+	}
+
+	// the first chunk follows the header blocks of the version file and its own
+	corrupted := append([]byte{}, archive...)
+	corrupted[3*512+100]++
+	if _, err := legacy.ReadArchive(bytes.NewReader(corrupted)); !errors.Is(err, legacy.ErrInvalidChunk) {
+		t.Fatalf("got error %v, want %v", err, legacy.ErrInvalidChunk)
+	}
+
+	if _, err := legacy.ReadArchive(bytes.NewReader([]byte("not a tar archive"))); !errors.Is(err, legacy.ErrInvalidArchive) {
+		t.Fatalf("got error %v, want %v", err, legacy.ErrInvalidArchive)
+	}
+}
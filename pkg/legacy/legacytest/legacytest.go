@@ -0,0 +1,123 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package legacytest builds the exports of the Swarm 0.x content in the tests.
+package legacytest
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// Entry is an entry of a legacy manifest.
+type Entry struct {
+	Hash        string          `json:"hash,omitempty"`
+	Path        string          `json:"path,omitempty"`
+	ContentType string          `json:"contentType,omitempty"`
+	Size        int64           `json:"size,omitempty"`
+	ModTime     time.Time       `json:"mod_time,omitempty"`
+	Access      json.RawMessage `json:"access,omitempty"`
+}
+
+// Export holds the chunks of the exported content.
+type Export struct {
+	mu     sync.Mutex
+	chunks map[string][]byte
+}
+
+// NewExport returns the empty Export.
+func NewExport() *Export {
+	return &Export{chunks: make(map[string][]byte)}
+}
+
+// Put implements the storage.Putter interface.
+func (e *Export) Put(_ context.Context, _ storage.ModePut, chs ...swarm.Chunk) ([]bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	exist := make([]bool, len(chs))
+	for i, ch := range chs {
+		_, exist[i] = e.chunks[ch.Address().ByteString()]
+		e.chunks[ch.Address().ByteString()] = ch.Data()
+	}
+	return exist, nil
+}
+
+// AddFile splits the data into the chunks of the export
+// and returns its reference, which is encrypted if requested.
+func (e *Export) AddFile(t *testing.T, data []byte, encrypt bool) swarm.Address {
+	t.Helper()
+
+	ctx := context.Background()
+	ref, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, e, storage.ModePutUpload, encrypt), bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ref
+}
+
+// AddManifest adds the legacy manifest of the entries to the export.
+func (e *Export) AddManifest(t *testing.T, entries []Entry, encrypt bool) swarm.Address {
+	t.Helper()
+
+	data, err := json.Marshal(struct {
+		Entries []Entry `json:"entries"`
+	}{entries})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e.AddFile(t, data, encrypt)
+}
+
+// Remove removes the chunk from the export.
+func (e *Export) Remove(addr swarm.Address) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.chunks, addr.ByteString())
+}
+
+// Archive returns the tar archive of the export in the format of the
+// export command of the Swarm 0.x nodes.
+func (e *Export) Archive(t *testing.T) []byte {
+	t.Helper()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	write := func(name string, data []byte) {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(".swarm-export-version", []byte("1"))
+	addrs := make([]string, 0, len(e.chunks))
+	for addr := range e.chunks {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	for _, addr := range addrs {
+		write(swarm.NewAddress([]byte(addr)).String(), e.chunks[addr])
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
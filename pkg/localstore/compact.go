@@ -0,0 +1,33 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Compact compacts the database range by range of the key prefixes, so that
+// the compaction can be interrupted with the context between the ranges and
+// continued later, as the compaction of the whole database takes long on the
+// large stores.
+func (db *DB) Compact(ctx context.Context) error {
+	start := time.Now()
+	for i := 0; i < 256; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var end []byte
+		if i < 255 {
+			end = []byte{byte(i + 1)}
+		}
+		if err := db.shed.Compact([]byte{byte(i)}, end); err != nil {
+			return fmt.Errorf("compact range %d: %w", i, err)
+		}
+	}
+	db.logger.Debug("database compacted", "elapsed", time.Since(start))
+	return nil
+}
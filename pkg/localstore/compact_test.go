@@ -0,0 +1,42 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+func TestCompact(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t, nil)
+
+	ch := generateTestRandomChunk()
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, ch); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Compact(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := db.Get(context.Background(), storage.ModeGetRequest, ch.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Address().Equal(ch.Address()) {
+		t.Fatalf("got chunk %s, want %s", got.Address(), ch.Address())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := db.Compact(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+}
@@ -42,6 +42,11 @@ var (
 	gcBatchSize uint64 = 10_000
 
 	reserveEvictionBatch uint64 = 200
+
+	// deprioritizedScanLimit limits the number of the entries of the
+	// postage chunks index scanned for the chunks of the deprioritized
+	// batches in a single garbage collection run.
+	deprioritizedScanLimit = 100_000
 )
 
 // collectGarbageWorker is a long running function that waits for
@@ -119,6 +124,21 @@ func (db *DB) collectGarbage() (evicted uint64, done bool, err error) {
 
 	candidates := make([]shed.Item, 0, gcBatchSize)
 
+	retained, err := db.retainedBatches()
+	if err != nil {
+		return 0, false, err
+	}
+	var retainedCount int
+
+	deprioritized, err := db.deprioritizedCandidates(candidates, retained)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, item := range deprioritized {
+		candidates = append(candidates, item)
+	}
+	db.metrics.GCDeprioritizedCounter.Add(float64(len(deprioritized)))
+
 	err = db.gcIndex.Iterate(func(item shed.Item) (stop bool, err error) {
 		if first {
 			totalTimeMetric(db.metrics.TotalTimeGCFirstItem, start)
@@ -129,6 +149,17 @@ func (db *DB) collectGarbage() (evicted uint64, done bool, err error) {
 			return true, nil
 		}
 
+		// skip the chunks of the expired batches in the retention period
+		if _, ok := retained[string(item.BatchID)]; ok {
+			retainedCount++
+			return false, nil
+		}
+
+		// skip the chunks which are already the candidates
+		if _, ok := deprioritized[string(item.Address)]; ok {
+			return false, nil
+		}
+
 		candidates = append(candidates, item)
 
 		return false, nil
@@ -136,6 +167,10 @@ func (db *DB) collectGarbage() (evicted uint64, done bool, err error) {
 	if err != nil {
 		return 0, false, err
 	}
+	db.metrics.GCRetainedCounter.Add(float64(retainedCount))
+	// the gc index is exhausted and the rest of the chunks are retained,
+	// another run would not collect anything until the retention ends
+	exhausted := retainedCount > 0 && len(candidates) < cap(candidates)
 	db.metrics.GCCollectedCounter.Add(float64(len(candidates)))
 	if testHookGCIteratorDone != nil {
 		testHookGCIteratorDone()
@@ -227,6 +262,10 @@ func (db *DB) collectGarbage() (evicted uint64, done bool, err error) {
 		locations = append(locations, loc)
 	}
 
+	if exhausted {
+		done = true
+	}
+
 	db.metrics.GCCommittedCounter.Add(float64(totalChunksEvicted))
 	db.gcSize.PutInBatch(batch, gcSize-totalChunksEvicted)
 
@@ -242,10 +281,94 @@ func (db *DB) collectGarbage() (evicted uint64, done bool, err error) {
 			db.logger.Warning("failed releasing sharky location", "location", loc)
 		}
 	}
+	db.metrics.ChunksEvicted.WithLabelValues("gc").Add(float64(len(locations)))
 
 	return totalChunksEvicted, done, nil
 }
 
+// deprioritizedCandidates returns the chunks of the deprioritized batches
+// in the gc index by their addresses, so that they are garbage collected
+// before the other chunks, regardless of their access time. The number of
+// the returned chunks is limited by the free capacity of the candidates.
+// The scan of the postage chunks index is limited too, and the next run
+// resumes it where it stopped, so that the chunks of the batches in the
+// reserve are not scanned on every run.
+func (db *DB) deprioritizedCandidates(candidates []shed.Item, retained map[string]struct{}) (map[string]shed.Item, error) {
+	deprioritized := make(map[string]shed.Item)
+	if db.deprioritizedBatchesFunc == nil {
+		return deprioritized, nil
+	}
+
+	limit := cap(candidates) - len(candidates)
+	scanned := 0
+	cursors := make(map[string]shed.Item)
+	for _, id := range db.deprioritizedBatchesFunc() {
+		if _, ok := retained[string(id)]; ok {
+			continue
+		}
+		opts := &shed.IterateOptions{Prefix: id}
+		cursor, ok := db.deprioritizedCursors[string(id)]
+		if ok {
+			opts.StartFrom = &cursor
+			opts.SkipStartFromItem = true
+		}
+		if len(deprioritized) >= limit || scanned >= deprioritizedScanLimit {
+			if ok {
+				cursors[string(id)] = cursor
+			}
+			continue
+		}
+
+		stopped := false
+		err := db.postageChunksIndex.Iterate(func(item shed.Item) (bool, error) {
+			if len(deprioritized) >= limit || scanned >= deprioritizedScanLimit {
+				stopped = true
+				return true, nil
+			}
+			scanned++
+			// the key of the item is reused by the iterator
+			cursor = shed.Item{
+				BatchID: append([]byte(nil), item.BatchID...),
+				Address: append([]byte(nil), item.Address...),
+			}
+			ok = true
+			i, err := db.retrievalDataIndex.Get(item)
+			if err != nil {
+				if errors.Is(err, leveldb.ErrNotFound) {
+					return false, nil
+				}
+				return true, err
+			}
+			a, err := db.retrievalAccessIndex.Get(item)
+			if err != nil {
+				if errors.Is(err, leveldb.ErrNotFound) {
+					return false, nil
+				}
+				return true, err
+			}
+			i.AccessTimestamp = a.AccessTimestamp
+			// the reserve chunks are not in the gc index
+			has, err := db.gcIndex.Has(i)
+			if err != nil {
+				return true, err
+			}
+			if has {
+				deprioritized[string(i.Address)] = i
+			}
+			return false, nil
+		}, opts)
+		if err != nil {
+			return nil, err
+		}
+		// the scan of the batch starts over once it reaches the end
+		if stopped && ok {
+			cursors[string(id)] = cursor
+		}
+	}
+	db.deprioritizedCursors = cursors
+	return deprioritized, nil
+}
+
 // gcTarget retruns the absolute value for garbage collection
 // target value, calculated from db.capacity and gcTargetRatio.
 func (db *DB) gcTarget() (target uint64) {
@@ -410,6 +533,7 @@ func (db *DB) evictReserve() (totalEvicted uint64, done bool, err error) {
 	}
 
 	db.metrics.EvictReserveCollectedCounter.Add(float64(totalEvicted))
+	db.metrics.ChunksUnreserved.WithLabelValues("radius").Add(float64(totalEvicted))
 	return totalEvicted, done, nil
 }
 
@@ -26,6 +26,7 @@ import (
 
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/postage"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
 	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -1183,3 +1184,138 @@ func TestReserveEvictionWorker(t *testing.T) {
 		}
 	})
 }
+
+// TestDB_collectGarbageWorker_deprioritized tests that the chunks of the
+// deprioritized batches are garbage collected before the older chunks.
+func TestDB_collectGarbageWorker_deprioritized(t *testing.T) {
+	var closed chan struct{}
+	testHookCollectGarbageChan := make(chan uint64)
+	t.Cleanup(setTestHookCollectGarbage(func(collectedCount uint64) {
+		if collectedCount == 0 {
+			return
+		}
+		select {
+		case testHookCollectGarbageChan <- collectedCount:
+		case <-closed:
+		}
+	}))
+
+	t.Cleanup(setWithinRadiusFunc(func(_ *DB, _ shed.Item) bool { return false }))
+
+	spamID := postagetesting.MustNewID()
+	db := newTestDB(t, &Options{
+		Capacity: 100,
+		DeprioritizedBatchesFunc: func() [][]byte {
+			return [][]byte{spamID}
+		},
+	})
+	closed = db.close
+
+	ctx := context.Background()
+	put := func(ch swarm.Chunk) {
+		t.Helper()
+		unreserveChunkBatch(t, db, 0, ch)
+		if _, err := db.Put(ctx, storage.ModePutRequest, ch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	honest := make([]swarm.Address, 0, 80)
+	for i := 0; i < 80; i++ {
+		ch := generateTestRandomChunk()
+		put(ch)
+		honest = append(honest, ch.Address())
+	}
+	for i := 0; i < 30; i++ {
+		put(generateTestRandomChunk().WithStamp(postagetesting.MustNewBatchStamp(spamID)))
+	}
+
+	gcTarget := db.gcTarget()
+	for {
+		select {
+		case <-testHookCollectGarbageChan:
+		case <-time.After(10 * time.Second):
+			t.Fatal("collect garbage timeout")
+		}
+		gcSize, err := db.gcSize.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gcSize == gcTarget {
+			break
+		}
+	}
+
+	// the older chunks of the other batches are kept
+	for _, addr := range honest {
+		has, err := db.Has(ctx, addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !has {
+			t.Fatalf("chunk %s garbage collected", addr)
+		}
+	}
+
+	t.Run("gc index count", newItemsCountTest(db.gcIndex, int(gcTarget)))
+	t.Run("gc size", newIndexGCSizeTest(db))
+}
+
+// TestDB_deprioritizedCandidates tests that the scan of the chunks of the
+// deprioritized batches is limited and resumed by the next runs.
+func TestDB_deprioritizedCandidates(t *testing.T) {
+	t.Cleanup(setWithinRadiusFunc(func(_ *DB, _ shed.Item) bool { return false }))
+	defer func(limit int) { deprioritizedScanLimit = limit }(deprioritizedScanLimit)
+	deprioritizedScanLimit = 4
+
+	spamID := postagetesting.MustNewID()
+	db := newTestDB(t, &Options{
+		Capacity: 1000,
+		DeprioritizedBatchesFunc: func() [][]byte {
+			return [][]byte{spamID}
+		},
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		ch := generateTestRandomChunk().WithStamp(postagetesting.MustNewBatchStamp(spamID))
+		unreserveChunkBatch(t, db, 0, ch)
+		if _, err := db.Put(ctx, storage.ModePutRequest, ch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	candidates := func(want int) map[string]shed.Item {
+		t.Helper()
+
+		got, err := db.deprioritizedCandidates(make([]shed.Item, 0, gcBatchSize), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != want {
+			t.Fatalf("got %d candidates, want %d", len(got), want)
+		}
+		return got
+	}
+
+	first := candidates(4)
+	seen := make(map[string]struct{})
+	for _, c := range []map[string]shed.Item{first, candidates(4), candidates(2)} {
+		for addr := range c {
+			if _, ok := seen[addr]; ok {
+				t.Fatalf("candidate %x scanned twice", addr)
+			}
+			seen[addr] = struct{}{}
+		}
+	}
+	if len(seen) != 10 {
+		t.Fatalf("got %d scanned candidates, want 10", len(seen))
+	}
+
+	// the scan starts over once it reaches the end
+	for addr := range candidates(4) {
+		if _, ok := first[addr]; !ok {
+			t.Fatalf("candidate %x not scanned first", addr)
+		}
+	}
+}
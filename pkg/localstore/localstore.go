@@ -142,6 +142,10 @@ type DB struct {
 	// postage index index
 	postageIndexIndex shed.Index
 
+	// expired batches index, which keeps the expiry time
+	// of the batches with retained chunks
+	expiredBatchIndex shed.Index
+
 	// field that stores number of items in gc index
 	gcSize shed.Uint64Field
 
@@ -155,8 +159,22 @@ type DB struct {
 	// the size of the reserve in chunks
 	reserveCapacity uint64
 
+	// the chunks of the expired batches are not garbage
+	// collected until the retention period passes
+	expiredBatchRetention time.Duration
+
 	unreserveFunc func(postage.UnreserveIteratorFn) error
 
+	// reports the chunks entering the reserve
+	reserveIngressFunc func(batchID, index []byte)
+
+	// the chunks of the deprioritized batches
+	// are garbage collected first
+	deprioritizedBatchesFunc func() [][]byte
+	// the positions in the postage chunks index at which the scans of
+	// the deprioritized batches resume, only used by the gc worker
+	deprioritizedCursors map[string]shed.Item
+
 	// triggers garbage collection event loop
 	collectGarbageTrigger chan struct{}
 
@@ -207,6 +225,7 @@ type DB struct {
 	metrics         metrics
 	logger          log.Logger
 	validStamp      postage.ValidStampFn
+	sampleWorkers   int
 	// following fields are used to synchronize sampling and reserve eviction
 	samplerStop    *sync.Once
 	samplerSignal  chan struct{}
@@ -237,9 +256,23 @@ type Options struct {
 	DisableSeeksCompaction bool
 	// Stamp validator for reserve sampler
 	ValidStamp postage.ValidStampFn
+	// SampleWorkers is the number of the workers which hash the chunks
+	// of the reserve sample. It follows the number of the CPUs if zero.
+	SampleWorkers int
 	// MetricsPrefix defines a prefix for metrics names.
 	MetricsPrefix string
 	Tags          *tags.Tags
+	// ExpiredBatchRetention is the grace period during which the
+	// chunks of the expired batches are retained in the cache and
+	// are not garbage collected, even if the cache capacity is
+	// exceeded. The retention is disabled if the value is zero.
+	ExpiredBatchRetention time.Duration
+	// ReserveIngressFunc is called with the batch ID and the
+	// stamp index of every chunk entering the reserve.
+	ReserveIngressFunc func(batchID, index []byte)
+	// DeprioritizedBatchesFunc returns the batches whose chunks
+	// are garbage collected before the other chunks.
+	DeprioritizedBatchesFunc func() [][]byte
 }
 
 type memFS struct {
@@ -273,14 +306,17 @@ func New(path string, baseKey []byte, ss storage.StateStorer, o *Options, logger
 	ctx, cancel := context.WithCancel(context.Background())
 
 	db = &DB{
-		stateStore:      ss,
-		cacheCapacity:   o.Capacity,
-		reserveCapacity: o.ReserveCapacity,
-		unreserveFunc:   o.UnreserveFunc,
-		baseKey:         baseKey,
-		tags:            o.Tags,
-		ctx:             ctx,
-		cancel:          cancel,
+		stateStore:               ss,
+		cacheCapacity:            o.Capacity,
+		reserveCapacity:          o.ReserveCapacity,
+		expiredBatchRetention:    o.ExpiredBatchRetention,
+		unreserveFunc:            o.UnreserveFunc,
+		reserveIngressFunc:       o.ReserveIngressFunc,
+		deprioritizedBatchesFunc: o.DeprioritizedBatchesFunc,
+		baseKey:                  baseKey,
+		tags:                     o.Tags,
+		ctx:                      ctx,
+		cancel:                   cancel,
 		// channel collectGarbageTrigger
 		// needs to be buffered with the size of 1
 		// to signal another event if it
@@ -293,6 +329,7 @@ func New(path string, baseKey []byte, ss storage.StateStorer, o *Options, logger
 		metrics:                   newMetrics(),
 		logger:                    logger.WithName(loggerName).Register(),
 		validStamp:                o.ValidStamp,
+		sampleWorkers:             o.SampleWorkers,
 		lock:                      multex.New(),
 	}
 	if db.cacheCapacity == 0 {
@@ -621,6 +658,30 @@ func New(path string, baseKey []byte, ss storage.StateStorer, o *Options, logger
 		return nil, err
 	}
 
+	db.expiredBatchIndex, err = db.shed.NewIndex("BatchID->ExpiryTimestamp", shed.IndexFuncs{
+		EncodeKey: func(fields shed.Item) (key []byte, err error) {
+			key = make([]byte, 32)
+			copy(key[:32], fields.BatchID)
+			return key, nil
+		},
+		DecodeKey: func(key []byte) (e shed.Item, err error) {
+			e.BatchID = key[:32]
+			return e, nil
+		},
+		EncodeValue: func(fields shed.Item) (value []byte, err error) {
+			value = make([]byte, 8)
+			binary.BigEndian.PutUint64(value, uint64(fields.StoreTimestamp))
+			return value, nil
+		},
+		DecodeValue: func(keyItem shed.Item, value []byte) (e shed.Item, err error) {
+			e.StoreTimestamp = int64(binary.BigEndian.Uint64(value))
+			return e, nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	db.postageIndexIndex, err = db.shed.NewIndex("BatchID|BatchIndex->Hash|Timestamp", shed.IndexFuncs{
 		EncodeKey: func(fields shed.Item) (key []byte, err error) {
 			key = make([]byte, 40)
@@ -759,6 +820,7 @@ func (db *DB) DebugIndices() (indexInfo map[string]int, err error) {
 		"postageChunksIndex":   db.postageChunksIndex,
 		"postageRadiusIndex":   db.postageRadiusIndex,
 		"postageIndexIndex":    db.postageIndexIndex,
+		"expiredBatchIndex":    db.expiredBatchIndex,
 	} {
 		indexSize, err := v.Count()
 		if err != nil {
@@ -28,6 +28,8 @@ type metrics struct {
 	GCErrorCounter           prometheus.Counter
 	GCCollectedCounter       prometheus.Counter
 	GCCommittedCounter       prometheus.Counter
+	GCRetainedCounter        prometheus.Counter
+	GCDeprioritizedCounter   prometheus.Counter
 	GCExcludeCounter         prometheus.Counter
 	GCExcludeError           prometheus.Counter
 	GCExcludeWriteBatchError prometheus.Counter
@@ -75,6 +77,10 @@ type metrics struct {
 	SamplerSuccessfulRuns prometheus.Counter
 	SamplerFailedRuns     prometheus.Counter
 	SamplerStopped        prometheus.Counter
+
+	ChunksStored     *prometheus.CounterVec // new chunks by their source
+	ChunksEvicted    *prometheus.CounterVec // chunks removed from the disk by the reason
+	ChunksUnreserved *prometheus.CounterVec // chunks moved from the reserve to the cache by the reason
 }
 
 func newMetrics() metrics {
@@ -177,6 +183,18 @@ func newMetrics() metrics {
 			Name:      "gc_collected_count",
 			Help:      "Number of times the GC_COLLECTED operation is done.",
 		}),
+		GCRetainedCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "gc_retained_count",
+			Help:      "Number of chunks of the expired batches skipped by the GC in the retention period.",
+		}),
+		GCDeprioritizedCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "gc_deprioritized_count",
+			Help:      "Number of chunks of the deprioritized batches collected by the GC before the other chunks.",
+		}),
 		GCCommittedCounter: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: m.Namespace,
 			Subsystem: subsystem,
@@ -437,6 +455,33 @@ func newMetrics() metrics {
 			Name:      "sampler_stopped_count",
 			Help:      "number of times sampler was stopped due to evictions",
 		}),
+		ChunksStored: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "chunks_stored",
+				Help:      "Number of new chunks stored by the source, which is one of upload, pushsync, pullsync or cache.",
+			},
+			[]string{"source"},
+		),
+		ChunksEvicted: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "chunks_evicted",
+				Help:      "Number of chunks removed from the disk by the reason, which is one of gc, overissued or removed.",
+			},
+			[]string{"reason"},
+		),
+		ChunksUnreserved: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "chunks_unreserved",
+				Help:      "Number of chunks moved from the reserve to the cache by the reason, which is one of radius, expired or evicted.",
+			},
+			[]string{"reason"},
+		),
 	}
 }
 
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/ethersphere/bee/pkg/faults"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/sharky"
 	"github.com/ethersphere/bee/pkg/shed"
@@ -44,6 +45,10 @@ func (db *DB) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address)
 		}
 	}()
 
+	if err := faults.Inject(ctx, faults.StorerGet); err != nil {
+		return nil, err
+	}
+
 	out, err := db.get(ctx, mode, addr)
 	if err != nil {
 		if errors.Is(err, leveldb.ErrNotFound) {
@@ -51,7 +56,7 @@ func (db *DB) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address)
 		}
 		return nil, err
 	}
-	return swarm.NewChunk(swarm.NewAddress(out.Address), out.Data).
+	return swarm.NewChunk(swarm.NewAddress(out.Address), faults.Corrupt(faults.StorerGet, out.Data)).
 		WithStamp(postage.NewStamp(out.BatchID, out.Index, out.Timestamp, out.Sig)), nil
 }
 
@@ -24,6 +24,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ethersphere/bee/pkg/faults"
+	"github.com/ethersphere/bee/pkg/sctx"
 	"github.com/ethersphere/bee/pkg/sharky"
 	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/storage"
@@ -45,7 +47,9 @@ func (db *DB) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 	db.metrics.ModePut.Inc()
 	defer totalTimeMetric(db.metrics.TotalTimePut, time.Now())
 
-	exist, err = db.put(ctx, mode, chs...)
+	if err = faults.Inject(ctx, faults.StorerPut); err == nil {
+		exist, err = db.put(ctx, mode, chs...)
+	}
 	if err != nil {
 		db.metrics.ModePutFailure.Inc()
 	}
@@ -82,6 +86,7 @@ func (db *DB) put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 	// to be done after write batch function successfully executes
 	var (
 		gcSizeChange int64 // number to add or subtract from gcSize
+		stored       int   // number of the new chunks
 	)
 	var triggerPushFeed bool                    // signal push feed subscriptions to iterate
 	triggerPullFeed := make(map[uint8]struct{}) // signal pull feed subscriptions to iterate
@@ -183,6 +188,9 @@ func (db *DB) put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 				return nil, fmt.Errorf("put request: %w", err)
 			}
 			exist[i] = exists
+			if !exists {
+				stored++
+			}
 			gcSizeChange += c
 		}
 
@@ -203,6 +211,7 @@ func (db *DB) put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 				// chunk is new so, trigger subscription feeds
 				// after the batch is successfully written
 				triggerPushFeed = true
+				stored++
 			}
 			gcSizeChange += c
 		}
@@ -223,6 +232,7 @@ func (db *DB) put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 				// chunk is new so, trigger pull subscription feed
 				// after the batch is successfully written
 				triggerPullFeed[db.po(ch.Address())] = struct{}{}
+				stored++
 			}
 			gcSizeChange += c
 		}
@@ -252,6 +262,9 @@ func (db *DB) put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 		}
 	}
 
+	db.metrics.ChunksStored.WithLabelValues(putSource(ctx, mode)).Add(float64(stored))
+	db.metrics.ChunksEvicted.WithLabelValues("overissued").Add(float64(len(*releaseLocs)))
+
 	for po := range triggerPullFeed {
 		db.triggerPullSubscriptions(po)
 	}
@@ -261,6 +274,22 @@ func (db *DB) put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 	return exist, nil
 }
 
+// putSource returns the source of the chunks stored with
+// the mode, by which the stored chunks are counted.
+func putSource(ctx context.Context, mode storage.ModePut) string {
+	switch mode {
+	case storage.ModePutUpload, storage.ModePutUploadPin:
+		return "upload"
+	case storage.ModePutSync:
+		if sctx.IsPushSync(ctx) {
+			return "pushsync"
+		}
+		return "pullsync"
+	default:
+		return "cache"
+	}
+}
+
 // checkAndRemoveStampIndex will check if we have the postageIndexIndex already taken
 // for a particular {BatchID, BatchIndex}. If yes and the batch is immutable, we
 // return error, if the batch is not immutable we replace the index to point to the
@@ -379,6 +408,9 @@ func (db *DB) putRequest(
 		if err != nil {
 			return 0, err
 		}
+		if db.reserveIngressFunc != nil {
+			db.reserveIngressFunc(item.BatchID, item.Index)
+		}
 	}
 
 	return db.setPin(batch, item)
@@ -487,6 +519,9 @@ func (db *DB) putSync(
 	if err != nil {
 		return 0, err
 	}
+	if db.reserveIngressFunc != nil {
+		db.reserveIngressFunc(item.BatchID, item.Index)
+	}
 
 	return db.setPin(batch, item)
 }
@@ -28,10 +28,12 @@ import (
 
 	"github.com/ethersphere/bee/pkg/postage"
 	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+	"github.com/ethersphere/bee/pkg/sctx"
 	"github.com/ethersphere/bee/pkg/sharky"
 	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
@@ -861,3 +863,75 @@ func benchmarkPutUpload(b *testing.B, o *Options, count, maxParallelUploads int)
 		}
 	}
 }
+
+// TestModePut_ChunksStoredMetrics validates that the new chunks
+// are counted by their source and the existing ones are not.
+func TestModePut_ChunksStoredMetrics(t *testing.T) {
+	db := newTestDB(t, nil)
+
+	put := func(ctx context.Context, mode storage.ModePut, count int) []swarm.Chunk {
+		t.Helper()
+
+		chunks := generateTestRandomChunks(count)
+		if _, err := db.Put(ctx, mode, chunks...); err != nil {
+			t.Fatal(err)
+		}
+		return chunks
+	}
+
+	uploaded := put(context.Background(), storage.ModePutUpload, 3)
+	put(context.Background(), storage.ModePutUploadPin, 1)
+	put(sctx.SetPushSync(context.Background()), storage.ModePutSync, 2)
+	put(context.Background(), storage.ModePutSync, 4)
+	put(context.Background(), storage.ModePutRequest, 5)
+
+	// the existing chunks are not counted again
+	if _, err := db.Put(context.Background(), storage.ModePutSync, uploaded...); err != nil {
+		t.Fatal(err)
+	}
+
+	for source, want := range map[string]float64{
+		"upload":   4,
+		"pushsync": 2,
+		"pullsync": 4,
+		"cache":    5,
+	} {
+		if got := testutil.ToFloat64(db.metrics.ChunksStored.WithLabelValues(source)); got != want {
+			t.Errorf("source %s: got %v stored chunks, want %v", source, got, want)
+		}
+	}
+
+	if err := db.Set(context.Background(), storage.ModeSetRemove, chunkAddresses(uploaded)...); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.ToFloat64(db.metrics.ChunksEvicted.WithLabelValues("removed")); got != 3 {
+		t.Errorf("got %v removed chunks, want 3", got)
+	}
+}
+
+func TestModePut_ReserveIngress(t *testing.T) {
+	var ingress [][]byte
+	db := newTestDB(t, &Options{
+		ReserveIngressFunc: func(batchID, _ []byte) {
+			ingress = append(ingress, batchID)
+		},
+	})
+
+	chunks := generateTestRandomChunks(4)
+	if _, err := db.Put(context.Background(), storage.ModePutSync, chunks...); err != nil {
+		t.Fatal(err)
+	}
+	// the chunks already in the reserve do not enter it again
+	if _, err := db.Put(context.Background(), storage.ModePutSync, chunks...); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ingress) != len(chunks) {
+		t.Fatalf("got %d chunks entering the reserve, want %d", len(ingress), len(chunks))
+	}
+	for i, ch := range chunks {
+		if !bytes.Equal(ingress[i], ch.Stamp().BatchID()) {
+			t.Fatalf("got batch %x, want %x", ingress[i], ch.Stamp().BatchID())
+		}
+	}
+}
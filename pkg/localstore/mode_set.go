@@ -143,6 +143,9 @@ func (db *DB) set(ctx context.Context, mode storage.ModeSet, addrs ...swarm.Addr
 	if sharkyErr.ErrorOrNil() != nil {
 		return sharkyErr.ErrorOrNil()
 	}
+	if mode == storage.ModeSetRemove {
+		db.metrics.ChunksEvicted.WithLabelValues("removed").Add(float64(len(committedLocations)))
+	}
 
 	for po := range triggerPullFeed {
 		db.triggerPullSubscriptions(po)
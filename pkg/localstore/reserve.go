@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ethersphere/bee/pkg/sharky"
 	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -26,6 +27,12 @@ func (db *DB) EvictBatch(id []byte) error {
 	return nil
 }
 
+// EvictBatchNow evicts all chunks associated with the batch from the reserve
+// before it returns. It is used by the offline pruning of the batch store.
+func (db *DB) EvictBatchNow(id []byte) error {
+	return db.evictBatch(id)
+}
+
 func (db *DB) evictBatch(id []byte) error {
 	db.metrics.BatchEvictCounter.Inc()
 	defer func(start time.Time) {
@@ -45,10 +52,129 @@ func (db *DB) evictBatch(id []byte) error {
 	}
 
 	db.metrics.BatchEvictCollectedCounter.Add(float64(evicted))
+	db.metrics.ChunksUnreserved.WithLabelValues("expired").Add(float64(evicted))
 	db.logger.Debug("evict batch", "batch_id", swarm.NewAddress(id), "evicted_count", evicted)
+
+	if db.expiredBatchRetention > 0 {
+		// the evicted chunks are in the cache now, keep them
+		// there for the retention period, so that they can
+		// be re-stamped before they are garbage collected
+		err = db.expiredBatchIndex.Put(shed.Item{BatchID: id, StoreTimestamp: now()})
+		if err != nil {
+			return fmt.Errorf("retain expired batch: %w", err)
+		}
+	}
 	return nil
 }
 
+// ReserveBatchSize returns the number of the reserve chunks of the batch
+// and their size in bytes. The chunks of the batch which are already in
+// the gc index are not counted.
+func (db *DB) ReserveBatchSize(id []byte) (chunks, size uint64, err error) {
+	err = db.postageChunksIndex.Iterate(func(item shed.Item) (bool, error) {
+		i, err := db.retrievalDataIndex.Get(item)
+		if err != nil {
+			if errors.Is(err, leveldb.ErrNotFound) {
+				return false, nil
+			}
+			return true, err
+		}
+		a, err := db.retrievalAccessIndex.Get(item)
+		switch {
+		case err == nil:
+			i.AccessTimestamp = a.AccessTimestamp
+			gc, err := db.gcIndex.Has(i)
+			if err != nil {
+				return true, err
+			}
+			if gc {
+				return false, nil
+			}
+		case !errors.Is(err, leveldb.ErrNotFound):
+			return true, err
+		}
+		l, err := sharky.LocationFromBinary(i.Location)
+		if err != nil {
+			return true, err
+		}
+		chunks++
+		size += uint64(l.Length)
+		return false, nil
+	}, &shed.IterateOptions{
+		Prefix: id,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return chunks, size, nil
+}
+
+// EvictReserveBatch evicts all chunks associated with the batch from the
+// reserve, regardless of the batch being valid. Unlike the chunks of the
+// expired batches, they are not retained for re-stamping, so that the
+// garbage collection can reclaim the space of the batches which are known to
+// be spam. It returns the number of the evicted chunks and their size in
+// bytes.
+func (db *DB) EvictReserveBatch(id []byte) (chunks, size uint64, err error) {
+	db.lock.Lock(lockKeyGC)
+	defer db.lock.Unlock(lockKeyGC)
+
+	db.stopSamplingIfRunning()
+
+	_, before, err := db.ReserveBatchSize(id)
+	if err != nil {
+		return 0, 0, fmt.Errorf("reserve batch size: %w", err)
+	}
+	chunks, err = db.unreserveBatch(id, swarm.MaxBins)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed evict batch: %w", err)
+	}
+	// the pinned chunks stay out of the gc index
+	_, after, err := db.ReserveBatchSize(id)
+	if err != nil {
+		return 0, 0, fmt.Errorf("reserve batch size: %w", err)
+	}
+	size = before - after
+
+	db.metrics.ChunksUnreserved.WithLabelValues("evicted").Add(float64(chunks))
+	db.logger.Debug("evict reserve batch", "batch_id", swarm.NewAddress(id), "evicted_count", chunks, "evicted_size", size)
+
+	return chunks, size, nil
+}
+
+// retainedBatches returns the IDs of the expired batches whose chunks are
+// still retained in the cache. The batches for which the retention period
+// has passed are removed from the expired batches index.
+func (db *DB) retainedBatches() (map[string]struct{}, error) {
+	retained := make(map[string]struct{})
+	if db.expiredBatchRetention <= 0 {
+		return retained, nil
+	}
+
+	var (
+		batch   = new(leveldb.Batch)
+		cutoff  = now() - db.expiredBatchRetention.Nanoseconds()
+		removed int
+	)
+	err := db.expiredBatchIndex.Iterate(func(item shed.Item) (stop bool, err error) {
+		if item.StoreTimestamp > cutoff {
+			retained[string(item.BatchID)] = struct{}{}
+			return false, nil
+		}
+		removed++
+		return false, db.expiredBatchIndex.DeleteInBatch(batch, item)
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if removed > 0 {
+		if err := db.shed.WriteBatch(batch); err != nil {
+			return nil, err
+		}
+	}
+	return retained, nil
+}
+
 // UnreserveBatch atomically unpins chunks of a batch in proximity order upto and including po.
 // Unpinning will result in all chunks with pincounter 0 to be put in the gc index
 // so if a chunk was only pinned by the reserve, unreserving it  will make it gc-able.
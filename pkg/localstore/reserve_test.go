@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -856,3 +857,167 @@ func TestDB_ReserveGC_EvictBatch(t *testing.T) {
 
 	t.Run("gc size", newIndexGCSizeTest(db))
 }
+
+func TestDB_ReserveGC_EvictBatchRetention(t *testing.T) {
+	chunkCount := 100
+
+	var closed chan struct{}
+	testHookEvictChan := make(chan uint64)
+	t.Cleanup(setTestHookEviction(func(collectedCount uint64) {
+		select {
+		case testHookEvictChan <- collectedCount:
+		case <-closed:
+		}
+	}))
+	testHookCollectGarbageChan := make(chan uint64)
+	t.Cleanup(setTestHookCollectGarbage(func(collectedCount uint64) {
+		select {
+		case testHookCollectGarbageChan <- collectedCount:
+		case <-closed:
+		}
+	}))
+
+	var timestamp int64 = 1
+	t.Cleanup(setNow(func() int64 {
+		return atomic.LoadInt64(&timestamp)
+	}))
+
+	stamp := postagetesting.MustNewStamp()
+
+	db := newTestDB(t, &Options{
+		Capacity:              100,
+		ReserveCapacity:       100,
+		ExpiredBatchRetention: time.Hour,
+	})
+	closed = db.close
+
+	for i := 0; i < chunkCount; i++ {
+		newStamp := postagetesting.MustNewBatchStamp(stamp.BatchID())
+		ch := generateTestRandomChunkAt(t, swarm.NewAddress(db.baseKey), 2).WithBatch(2, 3, 2, false).WithStamp(newStamp)
+		_, err := db.Put(context.Background(), storage.ModePutSync, ch)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := db.EvictBatch(stamp.BatchID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-testHookEvictChan:
+	case <-time.After(10 * time.Second):
+		t.Fatal("reserve eviction timeout")
+	}
+
+	select {
+	case <-testHookCollectGarbageChan:
+	case <-time.After(10 * time.Second):
+		t.Fatal("gc timeout")
+	}
+
+	// the chunks of the expired batch are retained over the cache capacity
+	t.Run("expired batch index count", newItemsCountTest(db.expiredBatchIndex, 1))
+	t.Run("gc index count", newItemsCountTest(db.gcIndex, chunkCount))
+	t.Run("gc size", newIndexGCSizeTest(db))
+
+	// the retention period passes
+	atomic.StoreInt64(&timestamp, time.Hour.Nanoseconds()+2)
+	db.triggerGarbageCollection()
+
+	gcTarget := db.gcTarget()
+	for {
+		select {
+		case <-testHookCollectGarbageChan:
+		case <-time.After(10 * time.Second):
+			t.Fatal("gc timeout")
+		}
+
+		gcSize, err := db.gcSize.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gcSize == gcTarget {
+			break
+		}
+	}
+
+	t.Run("expired batch index count", newItemsCountTest(db.expiredBatchIndex, 0))
+	t.Run("gc index count", newItemsCountTest(db.gcIndex, 90))
+	t.Run("gc size", newIndexGCSizeTest(db))
+}
+
+func TestDB_ReserveGC_EvictReserveBatch(t *testing.T) {
+	chunkCount := 100
+
+	var closed chan struct{}
+	testHookCollectGarbageChan := make(chan uint64)
+	t.Cleanup(setTestHookCollectGarbage(func(collectedCount uint64) {
+		if collectedCount == 0 {
+			return
+		}
+		select {
+		case testHookCollectGarbageChan <- collectedCount:
+		case <-closed:
+		}
+	}))
+
+	stamp := postagetesting.MustNewStamp()
+
+	db := newTestDB(t, &Options{
+		Capacity:              100,
+		ReserveCapacity:       100,
+		ExpiredBatchRetention: time.Hour,
+	})
+	closed = db.close
+
+	var size uint64
+	for i := 0; i < chunkCount; i++ {
+		newStamp := postagetesting.MustNewBatchStamp(stamp.BatchID())
+		ch := generateTestRandomChunkAt(t, swarm.NewAddress(db.baseKey), 2).WithBatch(2, 3, 2, false).WithStamp(newStamp)
+		_, err := db.Put(context.Background(), storage.ModePutSync, ch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		size += uint64(len(ch.Data()))
+	}
+
+	gotChunks, gotSize, err := db.ReserveBatchSize(stamp.BatchID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotChunks != uint64(chunkCount) || gotSize != size {
+		t.Fatalf("got %d chunks of %d bytes, want %d chunks of %d bytes", gotChunks, gotSize, chunkCount, size)
+	}
+
+	evicted, evictedSize, err := db.EvictReserveBatch(stamp.BatchID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evicted != uint64(chunkCount) || evictedSize != size {
+		t.Fatalf("got %d evicted chunks of %d bytes, want %d chunks of %d bytes", evicted, evictedSize, chunkCount, size)
+	}
+
+	// the evicted chunks in the gc index are not counted
+	gotChunks, gotSize, err = db.ReserveBatchSize(stamp.BatchID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotChunks != 0 || gotSize != 0 {
+		t.Fatalf("got %d chunks of %d bytes, want none", gotChunks, gotSize)
+	}
+
+	t.Run("reserve size", reserveSizeTest(db, 0, 0))
+
+	select {
+	case <-testHookCollectGarbageChan:
+	case <-time.After(10 * time.Second):
+		t.Fatal("gc timeout")
+	}
+
+	// the chunks are not retained, unlike the chunks of the expired batches
+	t.Run("expired batch index count", newItemsCountTest(db.expiredBatchIndex, 0))
+	t.Run("postage radius index count", newItemsCountTest(db.postageRadiusIndex, 0))
+	t.Run("gc size", newIndexGCSizeTest(db))
+}
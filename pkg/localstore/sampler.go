@@ -11,6 +11,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"runtime"
 	"sync"
 	"time"
 
@@ -27,6 +28,13 @@ import (
 
 const sampleSize = 8
 
+// maxSampleWorkers and minSampleWorkers bound the number of the
+// workers which read and hash the chunks of the sample.
+const (
+	maxSampleWorkers = 6
+	minSampleWorkers = 2
+)
+
 var errDbClosed = errors.New("database closed")
 var errSamplerStopped = errors.New("sampler stopped due to ongoing evictions")
 
@@ -121,7 +129,10 @@ func (db *DB) ReserveSample(
 
 	// Phase 2: Get the chunk data and calculate transformed hash
 	sampleItemChan := make(chan sampleEntry)
-	const workers = 6
+	workers := db.sampleWorkers
+	if workers <= 0 {
+		workers = sampleWorkers()
+	}
 	for i := 0; i < workers; i++ {
 		g.Go(func() error {
 			hmacr := hmac.New(swarm.NewHasher, anchor)
@@ -266,6 +277,20 @@ func (db *DB) ReserveSample(
 	return sample, nil
 }
 
+// sampleWorkers returns the number of the sampling workers, which follows
+// the number of the CPUs available to the node, as it can be limited by the
+// container. At least two workers overlap the reads of the chunks.
+func sampleWorkers() int {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > maxSampleWorkers {
+		return maxSampleWorkers
+	}
+	if workers < minSampleWorkers {
+		return minSampleWorkers
+	}
+	return workers
+}
+
 // less function uses the byte compare to check for lexicographic ordering
 func le(a, b []byte) bool {
 	return bytes.Compare(a, b) == -1
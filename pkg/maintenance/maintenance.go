@@ -0,0 +1,182 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package maintenance provides the scheduler which confines the heavy
+// background jobs of the node, such as the database compaction, the batch
+// store pruning and the cashouts, to the maintenance windows defined by the
+// operator, so that they do not compete with the serving of the requests in
+// the busy hours.
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "maintenance"
+
+// Job is a heavy background job. The context of the job is canceled when
+// the maintenance window closes, the job should stop then and continue in
+// the next window.
+type Job func(ctx context.Context) error
+
+// JobStatus is the status of a scheduled job.
+type JobStatus struct {
+	Name         string
+	Interval     time.Duration
+	NextRun      time.Time
+	LastRun      time.Time
+	LastDuration time.Duration
+	LastError    string
+	Running      bool
+}
+
+type job struct {
+	run    Job
+	status JobStatus
+}
+
+// Scheduler runs the registered jobs periodically within the maintenance
+// windows.
+type Scheduler struct {
+	windows Windows
+	logger  log.Logger
+	metrics metrics
+
+	mu   sync.Mutex
+	jobs map[string]*job
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a new Scheduler confining the jobs to the windows.
+func New(windows Windows, logger log.Logger) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		windows: windows,
+		logger:  logger.WithName(loggerName).Register(),
+		metrics: newMetrics(),
+		jobs:    make(map[string]*job),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Windows returns the maintenance windows.
+func (s *Scheduler) Windows() Windows {
+	return s.windows
+}
+
+// Register schedules the job to run with the interval within the maintenance
+// windows. The first run is one interval after the registration. The job with
+// the interval which is not positive is not scheduled.
+func (s *Scheduler) Register(name string, interval time.Duration, run Job) {
+	if interval <= 0 {
+		return
+	}
+	j := &job{run: run, status: JobStatus{Name: name, Interval: interval}}
+
+	s.mu.Lock()
+	s.jobs[name] = j
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.schedule(j)
+}
+
+func (s *Scheduler) schedule(j *job) {
+	defer s.wg.Done()
+
+	next := time.Now().Add(j.status.Interval)
+	for {
+		start, end := s.windows.Next(next)
+		if start.IsZero() {
+			s.logger.Warning("no maintenance window, job not scheduled", "job", j.status.Name)
+			return
+		}
+
+		s.mu.Lock()
+		j.status.NextRun = start
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(start))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.runJob(j, end)
+		next = time.Now().Add(j.status.Interval)
+	}
+}
+
+// runJob runs the job until it ends or the window closes at the end.
+func (s *Scheduler) runJob(j *job, end time.Time) {
+	ctx, cancel := s.ctx, context.CancelFunc(func() {})
+	if !end.IsZero() {
+		ctx, cancel = context.WithDeadline(s.ctx, end)
+	}
+	defer cancel()
+
+	s.mu.Lock()
+	j.status.Running = true
+	s.mu.Unlock()
+
+	start := time.Now()
+	err := j.run(ctx)
+	duration := time.Since(start)
+
+	s.mu.Lock()
+	j.status.Running = false
+	j.status.LastRun = start
+	j.status.LastDuration = duration
+	j.status.LastError = ""
+	if err != nil {
+		j.status.LastError = err.Error()
+	}
+	s.mu.Unlock()
+
+	s.metrics.Runs.WithLabelValues(j.status.Name).Inc()
+	s.metrics.Duration.WithLabelValues(j.status.Name).Observe(duration.Seconds())
+	switch {
+	case err == nil:
+		s.logger.Debug("maintenance job done", "job", j.status.Name, "elapsed", duration)
+	case errors.Is(err, context.DeadlineExceeded) && s.ctx.Err() == nil:
+		s.metrics.Interrupted.WithLabelValues(j.status.Name).Inc()
+		s.logger.Debug("maintenance job interrupted by the end of the window", "job", j.status.Name, "elapsed", duration)
+	case s.ctx.Err() == nil:
+		s.metrics.Failures.WithLabelValues(j.status.Name).Inc()
+		s.logger.Error(err, "maintenance job failed", "job", j.status.Name)
+	}
+}
+
+// Jobs returns the status of the scheduled jobs sorted by the name.
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j.status)
+	}
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].Name < jobs[k].Name })
+	return jobs
+}
+
+// Close stops the scheduling and waits for the running jobs to stop.
+func (s *Scheduler) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package maintenance_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/maintenance"
+)
+
+func TestScheduler(t *testing.T) {
+	t.Parallel()
+
+	s := maintenance.New(nil, log.Noop)
+	t.Cleanup(func() { _ = s.Close() })
+
+	runs := make(chan struct{}, 10)
+	s.Register("job", 10*time.Millisecond, func(context.Context) error {
+		select {
+		case runs <- struct{}{}:
+		default:
+		}
+		return errors.New("failed")
+	})
+	s.Register("disabled", 0, func(context.Context) error {
+		t.Error("disabled job run")
+		return nil
+	})
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-runs:
+		case <-time.After(5 * time.Second):
+			t.Fatal("job not run")
+		}
+	}
+
+	jobs := s.Jobs()
+	if len(jobs) != 1 {
+		t.Fatalf("got %d jobs, want 1", len(jobs))
+	}
+	if j := jobs[0]; j.Name != "job" || j.Interval != 10*time.Millisecond || j.LastRun.IsZero() || j.NextRun.IsZero() {
+		t.Fatalf("got job status %+v", j)
+	}
+}
+
+func TestScheduler_Window(t *testing.T) {
+	t.Parallel()
+
+	// the window opens in an hour
+	start := time.Now().Add(time.Hour).Truncate(time.Minute)
+	ws, err := maintenance.ParseWindows([]string{start.Format("15:04") + "-" + start.Add(time.Hour).Format("15:04")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := maintenance.New(ws, log.Noop)
+	t.Cleanup(func() { _ = s.Close() })
+
+	s.Register("job", time.Millisecond, func(context.Context) error {
+		t.Error("job run outside of the window")
+		return nil
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if jobs := s.Jobs(); jobs[0].NextRun.Equal(start) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got next run %v, want %v", s.Jobs()[0].NextRun, start)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package maintenance
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	Runs        *prometheus.CounterVec   // number of the job runs by the job
+	Failures    *prometheus.CounterVec   // number of the failed job runs by the job
+	Interrupted *prometheus.CounterVec   // number of the job runs interrupted by the end of the window
+	Duration    *prometheus.HistogramVec // duration of the job runs by the job
+}
+
+func newMetrics() metrics {
+	subsystem := "maintenance"
+
+	return metrics{
+		Runs: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "job_runs",
+				Help:      "Total runs of the maintenance jobs by the job.",
+			},
+			[]string{"job"},
+		),
+		Failures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "job_failures",
+				Help:      "Total failed runs of the maintenance jobs by the job.",
+			},
+			[]string{"job"},
+		),
+		Interrupted: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "job_interrupted",
+				Help:      "Total runs of the maintenance jobs interrupted by the end of the maintenance window by the job.",
+			},
+			[]string{"job"},
+		),
+		Duration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "job_duration_seconds",
+				Help:      "Duration of the runs of the maintenance jobs by the job.",
+				Buckets:   []float64{1, 10, 60, 300, 900, 3600, 4 * 3600},
+			},
+			[]string{"job"},
+		),
+	}
+}
+
+// Metrics returns the collectors of the scheduler metrics.
+func (s *Scheduler) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(s.metrics)
+}
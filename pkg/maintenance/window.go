@@ -0,0 +1,183 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var errInvalidWindow = errors.New("invalid maintenance window")
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+const allDays = 1<<7 - 1
+
+// Window is the recurring time window of the maintenance on the days of the
+// week. The window whose end is not after its start ends on the next day.
+type Window struct {
+	days  uint8         // bit i is set for the time.Weekday(i)
+	start time.Duration // since the midnight
+	end   time.Duration // since the midnight
+	spec  string
+}
+
+// ParseWindow parses the window in the form [days ]HH:MM-HH:MM in the local
+// time of the node, where the days are the comma separated weekdays or the
+// ranges of the weekdays, as in mon-fri 01:00-05:00 or sat,sun 22:00-06:00.
+// The window without the days recurs every day.
+func ParseWindow(s string) (Window, error) {
+	w := Window{days: allDays, spec: strings.TrimSpace(s)}
+
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+	case 2:
+		days, err := parseDays(strings.ToLower(fields[0]))
+		if err != nil {
+			return Window{}, fmt.Errorf("%w %q: %v", errInvalidWindow, s, err)
+		}
+		w.days = days
+	default:
+		return Window{}, fmt.Errorf("%w %q", errInvalidWindow, s)
+	}
+
+	from, to, ok := strings.Cut(fields[len(fields)-1], "-")
+	if !ok {
+		return Window{}, fmt.Errorf("%w %q: no time range", errInvalidWindow, s)
+	}
+	var err error
+	if w.start, err = parseClock(from); err != nil {
+		return Window{}, fmt.Errorf("%w %q: %v", errInvalidWindow, s, err)
+	}
+	if w.end, err = parseClock(to); err != nil {
+		return Window{}, fmt.Errorf("%w %q: %v", errInvalidWindow, s, err)
+	}
+	return w, nil
+}
+
+func parseDays(s string) (uint8, error) {
+	var days uint8
+	for _, v := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(v, "-")
+		first, ok := weekdays[from]
+		if !ok {
+			return 0, fmt.Errorf("unknown day %q", from)
+		}
+		last := first
+		if isRange {
+			if last, ok = weekdays[to]; !ok {
+				return 0, fmt.Errorf("unknown day %q", to)
+			}
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			days |= 1 << d
+			if d == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// parseClock parses the time of the day in the form HH:MM, where 24:00 is
+// the end of the day.
+func parseClock(s string) (time.Duration, error) {
+	var h, m int
+	if _, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil || len(s) != 5 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// String returns the window as it was parsed.
+func (w Window) String() string {
+	return w.spec
+}
+
+// next returns the start and the end of the occurrence of the window which
+// contains the time t or which starts the first after it.
+func (w Window) next(t time.Time) (start, end time.Time) {
+	// the occurrence of the previous day can end on the day of the time
+	for d := -1; d <= 7; d++ {
+		day := time.Date(t.Year(), t.Month(), t.Day()+d, 0, 0, 0, 0, t.Location())
+		if w.days&(1<<day.Weekday()) == 0 {
+			continue
+		}
+		s := day.Add(w.start)
+		e := day.Add(w.end)
+		if w.end <= w.start {
+			e = e.Add(24 * time.Hour)
+		}
+		if e.After(t) {
+			return s, e
+		}
+	}
+	return time.Time{}, time.Time{}
+}
+
+// Windows are the maintenance windows. No windows do not confine the jobs.
+type Windows []Window
+
+// ParseWindows parses the windows in the form of ParseWindow.
+func ParseWindows(specs []string) (Windows, error) {
+	ws := make(Windows, 0, len(specs))
+	for _, s := range specs {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		w, err := ParseWindow(s)
+		if err != nil {
+			return nil, err
+		}
+		ws = append(ws, w)
+	}
+	return ws, nil
+}
+
+// Next returns the time at or after the time t at which a window is open and
+// the time at which it closes. The zero end is returned for no windows, which
+// are always open.
+func (ws Windows) Next(t time.Time) (start, end time.Time) {
+	if len(ws) == 0 {
+		return t, time.Time{}
+	}
+	for _, w := range ws {
+		s, e := w.next(t)
+		if e.IsZero() {
+			continue
+		}
+		if s.Before(t) {
+			s = t
+		}
+		// the overlapping windows are open until the latest end
+		if start.IsZero() || s.Before(start) || (s.Equal(start) && e.After(end)) {
+			start, end = s, e
+		}
+	}
+	return start, end
+}
+
+// Strings returns the windows as they were parsed.
+func (ws Windows) Strings() []string {
+	s := make([]string, len(ws))
+	for i, w := range ws {
+		s[i] = w.String()
+	}
+	return s
+}
@@ -0,0 +1,129 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package maintenance_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/maintenance"
+)
+
+func TestParseWindow(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{
+		"01:00-05:00",
+		"mon-fri 01:00-05:00",
+		"sat,sun 22:00-06:00",
+		"fri-mon,wed 00:00-24:00",
+	} {
+		w, err := maintenance.ParseWindow(s)
+		if err != nil {
+			t.Fatalf("parse %q: %v", s, err)
+		}
+		if w.String() != s {
+			t.Fatalf("got %q, want %q", w.String(), s)
+		}
+	}
+
+	for _, s := range []string{
+		"",
+		"01:00",
+		"1:00-5:00",
+		"01:00-25:00",
+		"01:60-02:00",
+		"someday 01:00-05:00",
+		"mon-funday 01:00-05:00",
+		"mon 01:00-05:00 extra",
+	} {
+		if _, err := maintenance.ParseWindow(s); err == nil {
+			t.Fatalf("parse %q: expected error", s)
+		}
+	}
+}
+
+func TestWindows_Next(t *testing.T) {
+	t.Parallel()
+
+	// Wednesday
+	date := func(day, hour, min int) time.Time {
+		return time.Date(2023, time.March, day, hour, min, 0, 0, time.UTC)
+	}
+	now := date(15, 12, 0)
+
+	for _, tc := range []struct {
+		name       string
+		windows    []string
+		start, end time.Time
+	}{
+		{
+			name:  "none",
+			start: now,
+		},
+		{
+			name:    "later today",
+			windows: []string{"14:00-16:00"},
+			start:   date(15, 14, 0),
+			end:     date(15, 16, 0),
+		},
+		{
+			name:    "open",
+			windows: []string{"11:00-13:00"},
+			start:   now,
+			end:     date(15, 13, 0),
+		},
+		{
+			name:    "tomorrow",
+			windows: []string{"01:00-05:00"},
+			start:   date(16, 1, 0),
+			end:     date(16, 5, 0),
+		},
+		{
+			name:    "weekend",
+			windows: []string{"sat,sun 01:00-05:00"},
+			start:   date(18, 1, 0),
+			end:     date(18, 5, 0),
+		},
+		{
+			name:    "over midnight of yesterday",
+			windows: []string{"tue 22:00-13:00"},
+			start:   now,
+			end:     date(15, 13, 0),
+		},
+		{
+			name:    "over midnight",
+			windows: []string{"22:00-02:00"},
+			start:   date(15, 22, 0),
+			end:     date(16, 2, 0),
+		},
+		{
+			name:    "earliest",
+			windows: []string{"fri 01:00-05:00", "thu 03:00-04:00"},
+			start:   date(16, 3, 0),
+			end:     date(16, 4, 0),
+		},
+		{
+			name:    "overlapping",
+			windows: []string{"11:00-13:00", "10:00-14:00"},
+			start:   now,
+			end:     date(15, 14, 0),
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ws, err := maintenance.ParseWindows(tc.windows)
+			if err != nil {
+				t.Fatal(err)
+			}
+			start, end := ws.Next(now)
+			if !start.Equal(tc.start) || !end.Equal(tc.end) {
+				t.Fatalf("got window %v - %v, want %v - %v", start, end, tc.start, tc.end)
+			}
+		})
+	}
+}
@@ -20,8 +20,21 @@ const (
 	RootPath                      = "/"
 	WebsiteIndexDocumentSuffixKey = "website-index-document"
 	WebsiteErrorDocumentPathKey   = "website-error-document"
+	WebsiteRedirectsKey           = "website-redirects"
 	EntryMetadataContentTypeKey   = "Content-Type"
 	EntryMetadataFilenameKey      = "Filename"
+	// EntryMetadataModTimeKey is the entry metadata key of the
+	// modification time of the file in seconds since the Unix epoch.
+	EntryMetadataModTimeKey = "Mtime"
+
+	// EntryMetadataEncodingKeyPrefix is the prefix of the entry metadata
+	// keys which link the references of the precompressed variants of the
+	// entry content, followed by the content coding, such as br or gzip.
+	EntryMetadataEncodingKeyPrefix = "Content-Encoding-"
+	// EntryMetadataContentEncodingKey is the entry metadata key of the
+	// content coding, such as zstd, of the stored entry content, which
+	// is decoded for the clients that do not accept it.
+	EntryMetadataContentEncodingKey = "Content-Encoding"
 )
 
 var (
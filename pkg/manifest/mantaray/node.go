@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 )
 
 const (
@@ -150,6 +151,33 @@ func (n *Node) Metadata() map[string]string {
 	return n.metadata
 }
 
+// ObfuscationKey returns the key the persisted node is obfuscated with.
+func (n *Node) ObfuscationKey() []byte {
+	return n.obfuscationKey
+}
+
+// Fork is a fork of the node to the node of the following subpath.
+type Fork struct {
+	Prefix []byte
+	Node   *Node
+}
+
+// Forks returns the forks of the node ordered by their prefixes, loading the
+// node if it is not loaded yet. The nodes of the forks are not loaded.
+func (n *Node) Forks(ctx context.Context, l Loader) ([]Fork, error) {
+	if n.forks == nil {
+		if err := n.load(ctx, l); err != nil {
+			return nil, err
+		}
+	}
+	forks := make([]Fork, 0, len(n.forks))
+	for _, f := range n.forks {
+		forks = append(forks, Fork{Prefix: f.prefix, Node: f.Node})
+	}
+	sort.Slice(forks, func(i, j int) bool { return forks[i].Prefix[0] < forks[j].Prefix[0] })
+	return forks, nil
+}
+
 // LookupNode finds the node for a path or returns error if not found
 func (n *Node) LookupNode(ctx context.Context, path []byte, l Loader) (*Node, error) {
 	select {
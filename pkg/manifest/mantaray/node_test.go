@@ -386,3 +386,44 @@ func TestHasPrefix(t *testing.T) {
 		})
 	}
 }
+
+func TestForks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ls := newMockLoadSaver()
+
+	n := mantaray.New()
+	for _, p := range []string{"index.html", "img/1.png", "img/2.png", "about.html"} {
+		e := append(make([]byte, 32-len(p)), p...)
+		if err := n.Add(ctx, []byte(p), e, map[string]string{"name": p}, ls); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := n.Save(ctx, ls); err != nil {
+		t.Fatal(err)
+	}
+
+	forks, err := mantaray.NewNodeRef(n.Reference()).Forks(ctx, ls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"about.html", "i"}
+	if len(forks) != len(want) {
+		t.Fatalf("got %d forks, want %d", len(forks), len(want))
+	}
+	for i, f := range forks {
+		if string(f.Prefix) != want[i] {
+			t.Errorf("fork %d: got prefix %q, want %q", i, f.Prefix, want[i])
+		}
+		if len(f.Node.Reference()) == 0 {
+			t.Errorf("fork %d: missing reference", i)
+		}
+	}
+	if !forks[0].Node.IsValueType() || forks[0].Node.Metadata()["name"] != "about.html" {
+		t.Errorf("got fork node %v, want value with metadata", forks[0].Node)
+	}
+	if !forks[1].Node.IsEdgeType() {
+		t.Errorf("got fork node %v, want edge", forks[1].Node)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package membudget provides the memory budget shared by the subsystems of the
+// node which hold the most memory, such as the database caches, the buffers of
+// the downloads and uploads and the pss messages, so that the node stays within
+// the memory limit of small instances instead of being killed when it runs out
+// of memory. The subsystems acquire the memory before they allocate it and wait
+// while the budget is exhausted, which applies backpressure to the requests.
+package membudget
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	// DefaultShare is the share of the memory limit of the cgroup
+	// which is used as the budget if the budget is not configured.
+	DefaultShare = 0.5
+
+	// highWatermark is the share of the budget above
+	// which the optional allocations are refused.
+	highWatermark = 0.9
+
+	// maxReservedShare is the inverse of the largest share
+	// of the budget which is granted to a single reservation.
+	maxReservedShare = 4
+)
+
+// Budget is the memory budget. The methods of the nil Budget do not limit
+// the memory, so that the subsystems work the same without the budget.
+type Budget struct {
+	limit   int64
+	metrics metrics
+
+	mu       sync.Mutex
+	used     int64         // acquired and reserved memory
+	reserved int64         // reserved memory
+	released chan struct{} // closed and replaced on every release
+}
+
+// New returns a new Budget of limit bytes.
+func New(limit int64) *Budget {
+	b := &Budget{
+		limit:    limit,
+		metrics:  newMetrics(),
+		released: make(chan struct{}),
+	}
+	b.metrics.Limit.Set(float64(limit))
+	return b
+}
+
+// Limit returns the size of the budget in bytes, or zero for the nil Budget.
+func (b *Budget) Limit() int64 {
+	if b == nil {
+		return 0
+	}
+	return b.limit
+}
+
+// Used returns the number of the acquired and reserved bytes.
+func (b *Budget) Used() int64 {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
+
+// Reserve reserves n bytes for the lifetime of the node, such as for the
+// database caches, and returns the granted size, which is at most a quarter
+// of the budget and at most the memory which is not reserved yet. The nil
+// Budget grants the whole size.
+func (b *Budget) Reserve(n int64) int64 {
+	if b == nil {
+		return n
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if max := b.limit / maxReservedShare; n > max {
+		n = max
+	}
+	if free := b.limit - b.used; n > free {
+		n = free
+	}
+	if n < 0 {
+		n = 0
+	}
+	b.used += n
+	b.reserved += n
+	b.setUsed()
+	return n
+}
+
+// Acquire acquires n bytes, waiting until they are released by the other
+// subsystems if the budget is exhausted, or until the context is done. The
+// size larger than the budget is acquired when no other memory is acquired,
+// so that it does not wait forever.
+func (b *Budget) Acquire(ctx context.Context, n int64) error {
+	if b == nil {
+		return nil
+	}
+	waited := false
+	for {
+		b.mu.Lock()
+		if b.used+n <= b.limit || b.used == b.reserved {
+			b.used += n
+			b.setUsed()
+			b.mu.Unlock()
+			return nil
+		}
+		released := b.released
+		b.mu.Unlock()
+
+		if !waited {
+			waited = true
+			b.metrics.Waits.Inc()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+// TryAcquire acquires n bytes for an optional allocation, which can be
+// dropped under the memory pressure. It returns false without acquiring
+// the memory if the acquired memory would exceed the high watermark of the
+// budget.
+func (b *Budget) TryAcquire(n int64) bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if float64(b.used+n) > highWatermark*float64(b.limit) {
+		b.metrics.Refused.Inc()
+		return false
+	}
+	b.used += n
+	b.setUsed()
+	return true
+}
+
+// Release releases n acquired bytes and wakes up the waiting subsystems.
+func (b *Budget) Release(n int64) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.used -= n
+	b.setUsed()
+	close(b.released)
+	b.released = make(chan struct{})
+}
+
+// setUsed updates the metrics of the used memory. It must be
+// called with the lock held.
+func (b *Budget) setUsed() {
+	b.metrics.Used.Set(float64(b.used))
+}
+
+// budgetContextKey is used to reference the budget as context value.
+type budgetContextKey struct{}
+
+// WithBudget sets the budget to the context, so that the memory
+// allocated for the operations with the context is acquired from it.
+func WithBudget(ctx context.Context, b *Budget) context.Context {
+	return context.WithValue(ctx, budgetContextKey{}, b)
+}
+
+// FromContext returns the budget from the context, or nil if it is not set.
+func FromContext(ctx context.Context) *Budget {
+	b, _ := ctx.Value(budgetContextKey{}).(*Budget)
+	return b
+}
@@ -0,0 +1,135 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package membudget_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/membudget"
+)
+
+func TestBudget_Acquire(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b := membudget.New(100)
+
+	if err := b.Acquire(ctx, 60); err != nil {
+		t.Fatal(err)
+	}
+
+	// the acquisition over the budget waits for the release
+	acquired := make(chan error, 1)
+	go func() { acquired <- b.Acquire(ctx, 60) }()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquired over the budget")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	b.Release(60)
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("not acquired after the release")
+	}
+	if got := b.Used(); got != 60 {
+		t.Fatalf("got used %d, want 60", got)
+	}
+
+	// the waiting acquisition ends with the context
+	cctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := b.Acquire(cctx, 60); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+	b.Release(60)
+
+	// the size larger than the budget is acquired when nothing else is
+	if err := b.Acquire(ctx, 200); err != nil {
+		t.Fatal(err)
+	}
+	b.Release(200)
+	if got := b.Used(); got != 0 {
+		t.Fatalf("got used %d, want 0", got)
+	}
+}
+
+func TestBudget_TryAcquire(t *testing.T) {
+	t.Parallel()
+
+	b := membudget.New(100)
+
+	if !b.TryAcquire(80) {
+		t.Fatal("refused below the high watermark")
+	}
+	if b.TryAcquire(20) {
+		t.Fatal("acquired above the high watermark")
+	}
+	if !b.TryAcquire(10) {
+		t.Fatal("refused at the high watermark")
+	}
+}
+
+func TestBudget_Reserve(t *testing.T) {
+	t.Parallel()
+
+	b := membudget.New(100)
+
+	if got := b.Reserve(10); got != 10 {
+		t.Fatalf("got reserved %d, want 10", got)
+	}
+	// a reservation is granted at most a quarter of the budget
+	if got := b.Reserve(50); got != 25 {
+		t.Fatalf("got reserved %d, want 25", got)
+	}
+
+	// the reserved memory does not prevent the acquisition
+	// of the size larger than the budget
+	if err := b.Acquire(context.Background(), 100); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.Used(); got != 135 {
+		t.Fatalf("got used %d, want 135", got)
+	}
+	if got := b.Reserve(10); got != 0 {
+		t.Fatalf("got reserved %d, want 0", got)
+	}
+}
+
+func TestBudget_Nil(t *testing.T) {
+	t.Parallel()
+
+	var b *membudget.Budget
+
+	if err := b.Acquire(context.Background(), 1<<40); err != nil {
+		t.Fatal(err)
+	}
+	if !b.TryAcquire(1 << 40) {
+		t.Fatal("refused by the nil budget")
+	}
+	b.Release(1 << 40)
+	if got := b.Reserve(1 << 40); got != 1<<40 {
+		t.Fatalf("got reserved %d, want %d", got, int64(1<<40))
+	}
+	if b.Limit() != 0 || b.Used() != 0 {
+		t.Fatal("nil budget is limited")
+	}
+
+	ctx := membudget.WithBudget(context.Background(), membudget.New(1))
+	if membudget.FromContext(ctx).Limit() != 1 {
+		t.Fatal("budget not set to the context")
+	}
+	if membudget.FromContext(context.Background()) != nil {
+		t.Fatal("unexpected budget in the context")
+	}
+}
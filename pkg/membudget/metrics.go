@@ -0,0 +1,53 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package membudget
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	Limit   prometheus.Gauge   // size of the budget
+	Used    prometheus.Gauge   // acquired and reserved memory
+	Waits   prometheus.Counter // number of the acquisitions which waited for the memory
+	Refused prometheus.Counter // number of the refused optional acquisitions
+}
+
+func newMetrics() metrics {
+	subsystem := "membudget"
+
+	return metrics{
+		Limit: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "limit_bytes",
+			Help:      "Size of the memory budget.",
+		}),
+		Used: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "used_bytes",
+			Help:      "Acquired and reserved memory of the budget.",
+		}),
+		Waits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "waits",
+			Help:      "Total acquisitions which waited for the memory to be released.",
+		}),
+		Refused: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "refused",
+			Help:      "Total optional acquisitions refused above the high watermark of the budget.",
+		}),
+	}
+}
+
+// Metrics returns the collectors of the budget metrics.
+func (b *Budget) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(b.metrics)
+}
@@ -13,6 +13,9 @@ type metrics struct {
 	LocalChunksCounter        prometheus.Counter
 	InvalidLocalChunksCounter prometheus.Counter
 	RetrievedChunksCounter    prometheus.Counter
+	SharedCacheHitsCounter    prometheus.Counter
+	SharedCacheMissesCounter  prometheus.Counter
+	SharedCacheErrorsCounter  prometheus.Counter
 }
 
 func newMetrics() metrics {
@@ -37,6 +40,24 @@ func newMetrics() metrics {
 			Name:      "chunks_retrieved_from_network",
 			Help:      "Total no. of chunks retrieved from network.",
 		}),
+		SharedCacheHitsCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "shared_cache_hits",
+			Help:      "Total no. of chunks retrieved from the shared cache.",
+		}),
+		SharedCacheMissesCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "shared_cache_misses",
+			Help:      "Total no. of chunks not found in the shared cache.",
+		}),
+		SharedCacheErrorsCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "shared_cache_errors",
+			Help:      "Total no. of failed shared cache operations.",
+		}),
 	}
 }
 
@@ -19,6 +19,7 @@ import (
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/retrieval"
+	"github.com/ethersphere/bee/pkg/sharedcache"
 	"github.com/ethersphere/bee/pkg/soc"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -29,11 +30,15 @@ const loggerName = "netstore"
 
 const (
 	maxBgPutters int = 16
+	// sharedCacheTimeout is the timeout of the shared cache operations,
+	// which should fail fast so that the retrieval is not delayed.
+	sharedCacheTimeout = time.Second
 )
 
 type store struct {
 	storage.Storer
 	retrieval  retrieval.Interface
+	cache      sharedcache.Cache
 	logger     log.Logger
 	validStamp postage.ValidStampFn
 	bgWorkers  chan struct{}
@@ -47,12 +52,15 @@ var (
 	errInvalidLocalChunk = errors.New("invalid chunk found locally")
 )
 
-// New returns a new NetStore that wraps a given Storer.
-func New(s storage.Storer, validStamp postage.ValidStampFn, r retrieval.Interface, logger log.Logger) storage.Storer {
+// New returns a new NetStore that wraps a given Storer. The shared cache
+// is optional, if it is not nil, it is consulted before the network
+// retrieval and the retrieved chunks are added to it.
+func New(s storage.Storer, validStamp postage.ValidStampFn, r retrieval.Interface, cache sharedcache.Cache, logger log.Logger) storage.Storer {
 	ns := &store{
 		Storer:     s,
 		validStamp: validStamp,
 		retrieval:  r,
+		cache:      cache,
 		logger:     logger.WithName(loggerName).Register(),
 		bgWorkers:  make(chan struct{}, maxBgPutters),
 		metrics:    newMetrics(),
@@ -80,25 +88,68 @@ func (s *store) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Addres
 			s.metrics.InvalidLocalChunksCounter.Inc()
 		}
 	}
+	diagnostics := retrieval.DiagnosticsFromContext(ctx)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, errInvalidLocalChunk) {
+			// request from the shared cache of the cluster
+			if ch, ok := s.cacheGet(ctx, addr); ok {
+				diagnostics.AddSharedCache()
+				s.wg.Add(1)
+				s.put(ch, mode, false)
+				return ch, nil
+			}
+
 			// request from network
+			start := time.Now()
 			ch, err = s.retrieval.RetrieveChunk(ctx, addr, swarm.ZeroAddress)
 			if err != nil {
 				return nil, err
 			}
-			s.wg.Add(1)
-			s.put(ch, mode)
+			diagnostics.AddRetrieved(time.Since(start))
 			s.metrics.RetrievedChunksCounter.Inc()
+			// the chunks without a postage stamp, like the ones
+			// fetched from the fallback gateways, can not be stored
+			if ch.Stamp() == nil {
+				return ch, nil
+			}
+			s.wg.Add(1)
+			s.put(ch, mode, s.cache != nil)
 			return ch, nil
 		}
 		return nil, fmt.Errorf("netstore get: %w", err)
 	}
+	diagnostics.AddLocal()
 	return ch, nil
 }
 
+// cacheGet returns the chunk from the shared cache. The errors of the
+// cache are not returned, as the chunk can still be retrieved from the
+// network.
+func (s *store) cacheGet(ctx context.Context, addr swarm.Address) (swarm.Chunk, bool) {
+	if s.cache == nil {
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sharedCacheTimeout)
+	defer cancel()
+
+	ch, err := s.cache.Get(ctx, addr)
+	switch {
+	case err == nil:
+		s.metrics.SharedCacheHitsCounter.Inc()
+		return ch, true
+	case errors.Is(err, storage.ErrNotFound):
+		s.metrics.SharedCacheMissesCounter.Inc()
+	default:
+		s.metrics.SharedCacheErrorsCounter.Inc()
+		s.logger.Debug("netstore: get chunk from shared cache failed", "chunk_address", addr, "error", err)
+	}
+	return nil, false
+}
+
 // put will store the chunk into storage asynchronously
-func (s *store) put(ch swarm.Chunk, mode storage.ModeGet) {
+// and into the shared cache if toCache is true
+func (s *store) put(ch swarm.Chunk, mode storage.ModeGet, toCache bool) {
 	go func() {
 		defer s.wg.Done()
 
@@ -112,6 +163,15 @@ func (s *store) put(ch swarm.Chunk, mode storage.ModeGet) {
 			<-s.bgWorkers
 		}()
 
+		if toCache {
+			ctx, cancel := context.WithTimeout(s.sCtx, sharedCacheTimeout)
+			if err := s.cache.Put(ctx, ch); err != nil {
+				s.metrics.SharedCacheErrorsCounter.Inc()
+				s.logger.Debug("netstore: put chunk to shared cache failed", "chunk_address", ch.Address(), "error", err)
+			}
+			cancel()
+		}
+
 		stamp, err := ch.Stamp().MarshalBinary()
 		if err != nil {
 			s.logger.Error(err, "failed to marshal stamp from chunk", "chunk_address", ch.Address())
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -16,6 +17,7 @@ import (
 	"github.com/ethersphere/bee/pkg/netstore"
 	"github.com/ethersphere/bee/pkg/postage"
 	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+	"github.com/ethersphere/bee/pkg/retrieval"
 	"github.com/ethersphere/bee/pkg/spinlock"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/storage/mock"
@@ -215,6 +217,68 @@ func waitAndGetChunk(t *testing.T, store storage.Storer, addr swarm.Address, mod
 	return
 }
 
+// TestNetstoreSharedCache verifies that the shared cache is consulted before
+// the network and that the chunks retrieved from the network are cached.
+func TestNetstoreSharedCache(t *testing.T) {
+	t.Parallel()
+
+	cachedChunk := chunktesting.GenerateTestRandomChunk()
+	testChunk := chunktesting.GenerateTestRandomChunk()
+
+	cache := &sharedCacheMock{chunks: map[string]swarm.Chunk{
+		cachedChunk.Address().ByteString(): cachedChunk,
+	}}
+	retrieve := &retrievalMock{chunk: testChunk}
+	store := mock.NewStorer()
+	nstore := netstore.New(store, noopValidStamp, retrieve, cache, log.Noop)
+	testutil.CleanupCloser(t, nstore)
+
+	diagnostics := retrieval.NewDiagnostics()
+	ctx := retrieval.WithDiagnostics(context.Background(), diagnostics)
+
+	d, err := nstore.Get(ctx, storage.ModeGetRequest, cachedChunk.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(d.Data(), cachedChunk.Data()) {
+		t.Fatal("chunk data not equal to expected data")
+	}
+	if retrieve.called {
+		t.Fatal("retrieve request issued for cached chunk")
+	}
+	// the cached chunk is stored locally
+	_ = waitAndGetChunk(t, store, cachedChunk.Address(), storage.ModeGetRequest)
+
+	_, err = nstore.Get(ctx, storage.ModeGetRequest, testChunk.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !retrieve.called {
+		t.Fatal("retrieve request not issued")
+	}
+
+	// the chunk stored locally is not requested from the cache
+	if _, err := nstore.Get(ctx, storage.ModeGetRequest, cachedChunk.Address()); err != nil {
+		t.Fatal(err)
+	}
+
+	report := diagnostics.Report()
+	if report.Chunks != 3 || report.Local != 1 || report.SharedCache != 1 || report.Retrieved != 1 {
+		t.Fatalf("got diagnostics report %+v", report)
+	}
+	if got := report.CacheHitRatio(); got < 0.66 || got > 0.67 {
+		t.Fatalf("got cache hit ratio %v", got)
+	}
+
+	err = spinlock.Wait(3*time.Second, func() bool {
+		_, err := cache.Get(context.Background(), testChunk.Address())
+		return err == nil
+	})
+	if err != nil {
+		t.Fatal("retrieved chunk not put to the shared cache")
+	}
+}
+
 // returns a mock retrieval protocol, a mock local storage and a netstore
 func newRetrievingNetstore(t *testing.T, validStamp postage.ValidStampFn, chunk swarm.Chunk) (*retrievalMock, *mock.MockStorer, storage.Storer) {
 	t.Helper()
@@ -224,7 +288,7 @@ func newRetrievingNetstore(t *testing.T, validStamp postage.ValidStampFn, chunk
 	}
 	store := mock.NewStorer()
 	logger := log.Noop
-	ns := netstore.New(store, validStamp, retrieve, logger)
+	ns := netstore.New(store, validStamp, retrieve, nil, logger)
 	testutil.CleanupCloser(t, ns)
 
 	return retrieve, store, ns
@@ -251,3 +315,29 @@ func (r *retrievalMock) RetrieveChunk(ctx context.Context, addr, sourceAddr swar
 var noopValidStamp = func(c swarm.Chunk, _ []byte) (swarm.Chunk, error) {
 	return c, nil
 }
+
+type sharedCacheMock struct {
+	mu     sync.Mutex
+	chunks map[string]swarm.Chunk
+}
+
+func (c *sharedCacheMock) Get(_ context.Context, addr swarm.Address) (swarm.Chunk, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch, ok := c.chunks[addr.ByteString()]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return ch, nil
+}
+
+func (c *sharedCacheMock) Put(_ context.Context, ch swarm.Chunk) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.chunks[ch.Address().ByteString()] = ch
+	return nil
+}
+
+func (c *sharedCacheMock) Close() error { return nil }
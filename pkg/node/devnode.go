@@ -250,7 +250,7 @@ func NewDevBee(logger log.Logger, o *DevOptions) (b *DevBee, err error) {
 	tagService := tags.NewTags(stateStore, logger)
 	b.tagsCloser = tagService
 
-	pssService := pss.New(mockKey, logger)
+	pssService := pss.New(mockKey, logger, pss.Options{})
 	b.pssCloser = pssService
 
 	pssService.SetPushSyncer(mockPushsync.New(func(ctx context.Context, chunk swarm.Chunk) (*pushsync.Receipt, error) {
@@ -390,6 +390,7 @@ func NewDevBee(logger log.Logger, o *DevOptions) (b *DevBee, err error) {
 		BlockTime:        time.Second * 2,
 		Tags:             tagService,
 		Storer:           storer,
+		StateStore:       stateStore,
 		Resolver:         mockResolver,
 		Pss:              pssService,
 		TraversalService: traversalService,
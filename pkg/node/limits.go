@@ -0,0 +1,86 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package node
+
+import (
+	"time"
+
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/ethersphere/bee/pkg/puller"
+	"github.com/ethersphere/bee/pkg/topology/depthmonitor"
+	"github.com/ethersphere/bee/pkg/topology/kademlia"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// backgroundLimits are the limits of the background work of the node.
+type backgroundLimits struct {
+	HistSyncing          int           // concurrently synced historical intervals, zero is unlimited
+	SampleWorkers        int           // workers hashing the reserve sample, zero follows the CPUs
+	SaturationPeers      int           // peers in a bin at which it is saturated
+	OverSaturationPeers  int           // peers in a bin at which no new peers are accepted
+	DepthMonitorInterval time.Duration // interval of the checks of the reserve size
+	AuditInterval        time.Duration // interval of the checksum audits of the neighbors
+}
+
+var defaultLimits = backgroundLimits{
+	SaturationPeers:      kademlia.DefaultSaturationPeers,
+	OverSaturationPeers:  kademlia.DefaultOverSaturationPeers,
+	DepthMonitorInterval: depthmonitor.DefaultWakeupInterval,
+	AuditInterval:        puller.DefaultAuditInterval,
+}
+
+// lowPowerLimits reduce the background work of the nodes on the Raspberry Pi
+// class hardware, which syncs fewer intervals at once, hashes the reserve
+// sample with fewer workers, keeps fewer connections and checks the reserve
+// and the neighbors less often.
+var lowPowerLimits = backgroundLimits{
+	HistSyncing:          4,
+	SampleWorkers:        2,
+	SaturationPeers:      4,
+	OverSaturationPeers:  10,
+	DepthMonitorInterval: 15 * time.Minute,
+	AuditInterval:        6 * time.Hour,
+}
+
+// limits returns the background limits of the node.
+func limits(lowPower bool) backgroundLimits {
+	if lowPower {
+		return lowPowerLimits
+	}
+	return defaultLimits
+}
+
+// Metrics returns the gauges of the applied limits.
+func (l backgroundLimits) Metrics(lowPower bool) []prometheus.Collector {
+	subsystem := "node"
+
+	applied := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "background_limit",
+			Help:      "Applied limits of the background work by the limit, zero is unlimited or follows the CPUs.",
+		},
+		[]string{"limit"},
+	)
+	applied.WithLabelValues("hist_syncing").Set(float64(l.HistSyncing))
+	applied.WithLabelValues("sample_workers").Set(float64(l.SampleWorkers))
+	applied.WithLabelValues("saturation_peers").Set(float64(l.SaturationPeers))
+	applied.WithLabelValues("oversaturation_peers").Set(float64(l.OverSaturationPeers))
+	applied.WithLabelValues("depth_monitor_interval_seconds").Set(l.DepthMonitorInterval.Seconds())
+	applied.WithLabelValues("audit_interval_seconds").Set(l.AuditInterval.Seconds())
+
+	profile := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: m.Namespace,
+		Subsystem: subsystem,
+		Name:      "low_power",
+		Help:      "Whether the low-power limits of the background work are applied.",
+	})
+	if lowPower {
+		profile.Set(1)
+	}
+
+	return []prometheus.Collector{applied, profile}
+}
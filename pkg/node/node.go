@@ -10,6 +10,7 @@ package node
 import (
 	"context"
 	"crypto/ecdsa"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -24,51 +25,79 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/ethersphere/bee/pkg/cgroup"
 	"github.com/ethersphere/bee/pkg/chainsync"
 	"github.com/ethersphere/bee/pkg/chainsyncer"
+	"github.com/ethersphere/bee/pkg/challenge"
 	"github.com/ethersphere/bee/pkg/status"
 	"github.com/ethersphere/bee/pkg/storageincentives/redistribution"
 	"github.com/ethersphere/bee/pkg/topology/depthmonitor"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee"
+	"github.com/ethersphere/bee/pkg/accesscontrol"
 	"github.com/ethersphere/bee/pkg/accounting"
 	"github.com/ethersphere/bee/pkg/addressbook"
+	"github.com/ethersphere/bee/pkg/alias"
+	"github.com/ethersphere/bee/pkg/analytics"
 	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/auditlog"
 	"github.com/ethersphere/bee/pkg/auth"
+	"github.com/ethersphere/bee/pkg/broadcast"
 	"github.com/ethersphere/bee/pkg/config"
 	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/deploy"
+	"github.com/ethersphere/bee/pkg/dnslink"
+	"github.com/ethersphere/bee/pkg/eventlog"
+	"github.com/ethersphere/bee/pkg/feeds/crdt"
 	"github.com/ethersphere/bee/pkg/feeds/factory"
 	"github.com/ethersphere/bee/pkg/hive"
+	"github.com/ethersphere/bee/pkg/ipfs"
+	"github.com/ethersphere/bee/pkg/jobs"
 	"github.com/ethersphere/bee/pkg/localstore"
 	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/maintenance"
+	"github.com/ethersphere/bee/pkg/membudget"
 	"github.com/ethersphere/bee/pkg/metrics"
 	"github.com/ethersphere/bee/pkg/netstore"
 	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/allowlist"
+	"github.com/ethersphere/bee/pkg/p2p/dnsdisc"
 	"github.com/ethersphere/bee/pkg/p2p/libp2p"
 	"github.com/ethersphere/bee/pkg/pingpong"
 	"github.com/ethersphere/bee/pkg/pinning"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/postage/batchservice"
 	"github.com/ethersphere/bee/pkg/postage/batchstore"
+	"github.com/ethersphere/bee/pkg/postage/events"
 	"github.com/ethersphere/bee/pkg/postage/listener"
 	"github.com/ethersphere/bee/pkg/postage/postagecontract"
 	"github.com/ethersphere/bee/pkg/pricer"
 	"github.com/ethersphere/bee/pkg/pricing"
+	"github.com/ethersphere/bee/pkg/profiling"
 	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/ethersphere/bee/pkg/pss/session"
 	"github.com/ethersphere/bee/pkg/puller"
 	"github.com/ethersphere/bee/pkg/pullsync"
 	"github.com/ethersphere/bee/pkg/pullsync/pullstorage"
 	"github.com/ethersphere/bee/pkg/pusher"
 	"github.com/ethersphere/bee/pkg/pushsync"
+	"github.com/ethersphere/bee/pkg/replica"
+	"github.com/ethersphere/bee/pkg/reservesnapshot"
 	"github.com/ethersphere/bee/pkg/resolver/multiresolver"
 	"github.com/ethersphere/bee/pkg/retrieval"
+	"github.com/ethersphere/bee/pkg/retrieval/fallback"
 	"github.com/ethersphere/bee/pkg/settlement/pseudosettle"
 	"github.com/ethersphere/bee/pkg/settlement/swap"
 	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook"
 	"github.com/ethersphere/bee/pkg/settlement/swap/erc20"
 	"github.com/ethersphere/bee/pkg/settlement/swap/priceoracle"
+	"github.com/ethersphere/bee/pkg/shaping"
+	"github.com/ethersphere/bee/pkg/sharedcache"
 	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/spam"
+	redisstatestore "github.com/ethersphere/bee/pkg/statestore/redis"
 	"github.com/ethersphere/bee/pkg/steward"
 	"github.com/ethersphere/bee/pkg/storageincentives"
 	"github.com/ethersphere/bee/pkg/storageincentives/staking"
@@ -77,11 +106,14 @@ import (
 	"github.com/ethersphere/bee/pkg/topology"
 	"github.com/ethersphere/bee/pkg/topology/kademlia"
 	"github.com/ethersphere/bee/pkg/topology/lightnode"
+	"github.com/ethersphere/bee/pkg/topology/snapshot"
 	"github.com/ethersphere/bee/pkg/tracing"
 	"github.com/ethersphere/bee/pkg/transaction"
+	"github.com/ethersphere/bee/pkg/transform"
 	"github.com/ethersphere/bee/pkg/traversal"
 	"github.com/ethersphere/bee/pkg/util"
 	"github.com/ethersphere/bee/pkg/util/ioutil"
+	"github.com/ethersphere/bee/pkg/warmer"
 	"github.com/hashicorp/go-multierror"
 	ma "github.com/multiformats/go-multiaddr"
 	promc "github.com/prometheus/client_golang/prometheus"
@@ -106,31 +138,47 @@ type Bee struct {
 	stateStoreCloser         io.Closer
 	localstoreCloser         io.Closer
 	nsCloser                 io.Closer
+	jobsCloser               io.Closer
+	sharedCacheCloser        io.Closer
+	sharedStateStoreCloser   io.Closer
 	topologyCloser           io.Closer
 	topologyHalter           topology.Halter
+	topologySnapshotCloser   io.Closer
 	pusherCloser             io.Closer
 	pullerCloser             io.Closer
 	accountingCloser         io.Closer
 	pullSyncCloser           io.Closer
+	snapshotCloser           io.Closer
 	pssCloser                io.Closer
+	broadcastCloser          io.Closer
 	ethClientCloser          func()
 	transactionMonitorCloser io.Closer
 	transactionCloser        io.Closer
 	listenerCloser           io.Closer
 	postageServiceCloser     io.Closer
+	pinExpiryCloser          io.Closer
+	batchEventsCloser        io.Closer
+	auditLogCloser           io.Closer
+	profilerCloser           io.Closer
 	priceOracleCloser        io.Closer
 	hiveCloser               io.Closer
 	chainSyncerCloser        io.Closer
 	depthMonitorCloser       io.Closer
 	storageIncetivesCloser   io.Closer
+	maintenanceCloser        io.Closer
+	eventLogCloser           io.Closer
+	replicaCloser            io.Closer
+	dnsLinkCloser            io.Closer
 	shutdownInProgress       bool
 	shutdownMutex            sync.Mutex
 	syncingStopped           *util.Signaler
+	ready                    chan struct{}
 }
 
 type Options struct {
 	DataDir                       string
 	CacheCapacity                 uint64
+	ExpiredBatchRetention         time.Duration
 	DBOpenFilesLimit              uint64
 	DBWriteBufferSize             uint64
 	DBBlockCacheCapacity          uint64
@@ -179,6 +227,86 @@ type Options struct {
 	AdminPasswordHash             string
 	UsePostageSnapshot            bool
 	EnableStorageIncentives       bool
+	TopologySnapshotDir           string
+	TopologySnapshotInterval      time.Duration
+	ReserveBootstrap              bool
+	ReserveSnapshotServe          bool
+	PinExpiryWebhooks             []string
+	PostageEventWebhooks          []string
+	PostageExpiryWarning          time.Duration
+	PssMinDelay                   time.Duration
+	PssMaxDelay                   time.Duration
+	PssCoverInterval              time.Duration
+	PssCoverBudget                int
+	AllowlistOverlays             []swarm.Address
+	AllowlistUnderlays            []string
+	AllowlistAuthority            string
+	AllowlistAttestation          string
+	AuditLog                      string
+	AuditLogMaxSize               int64
+	AuditLogMaxBackups            int
+	AuditLogRedact                []string
+	AuditLogTrustedProxies        []string
+	ProfilingEndpoint             string
+	ProfilingTypes                []string
+	ProfilingInterval             time.Duration
+	ProfilingLabels               []string
+	SharedCache                   string
+	SharedCacheTTL                time.Duration
+	SharedStateStore              string
+	ImageTransform                bool
+	TransformCacheSize            uint64
+	RetrievalFallbackGateways     []string
+	RetrievalFallbackBudget       time.Duration
+	RetrievalInteractiveWorkers   int
+	RetrievalInteractiveBudget    uint64
+	RetrievalBackgroundWorkers    int
+	RetrievalBackgroundBudget     uint64
+	IPFSGateway                   string
+	ChallengeMode                 string
+	ChallengeDifficulty           uint
+	ChallengeTokenTTL             time.Duration
+	ChallengeRate                 float64
+	ChallengeBurst                int
+	ChallengeRoutes               []string
+	ChallengeExempt               []string
+	ChallengeSecret               string
+	MaxConcurrentDownloads        int
+	ClientConcurrentDownloads     int
+	ReferenceConcurrentDownloads  int
+	DownloadQueueSize             int
+	DownloadQueueTimeout          time.Duration
+	DownloadClientBandwidth       int64
+	DownloadClientBurst           int64
+	MaxDownloadJoiners            int
+	BatchPruneInterval            time.Duration
+	SpamDetection                 bool
+	SpamWindow                    time.Duration
+	SpamMaxRate                   float64
+	SpamMaxSkew                   float64
+	SpamDeprioritize              bool
+	DepthDecreaseThreshold        float64
+	DepthCooldown                 time.Duration
+	DepthConfirmations            int
+	ReplicaPrimaryAPI             string
+	ReplicaInterval               time.Duration
+	ReplicaToken                  string
+	APILookahead                  string
+	APICompressionTypes           string
+	APICompressionMinSize         int64
+	MemoryBudget                  uint64
+	LowPower                      bool
+	MaintenanceWindows            []string
+	DBCompactionInterval          time.Duration
+	CashoutInterval               time.Duration
+	CashoutMinAmount              string
+	GraphQLEnable                 bool
+	DNSLinkProvider               string
+	DNSLinkFeeds                  []string
+	DNSLinkInterval               time.Duration
+	EnableDirListing              bool
+	GatewayDomain                 string
+	GatewaySubdomainOnly          bool
 }
 
 const (
@@ -191,6 +319,7 @@ const (
 	minPaymentThreshold           = 2 * refreshRate           // minimal accepted payment threshold of full nodes
 	maxPaymentThreshold           = 24 * refreshRate          // maximal accepted payment threshold of full nodes
 	mainnetNetworkID              = uint64(1)                 //
+	maxRunningJobs                = 4                         // running pin and re-upload jobs of each kind
 )
 
 func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, networkID uint64, logger log.Logger, libp2pPrivateKey, pssPrivateKey *ecdsa.PrivateKey, o *Options) (b *Bee, err error) {
@@ -229,6 +358,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		errorLogWriter: sink,
 		tracerCloser:   tracerCloser,
 		syncingStopped: util.NewSignaler(),
+		ready:          make(chan struct{}),
 	}
 
 	defer func(b *Bee) {
@@ -240,6 +370,29 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		}
 	}(b)
 
+	var profiler *profiling.Profiler
+	if o.ProfilingEndpoint != "" {
+		labels := map[string]string{"version": bee.Version}
+		for _, l := range o.ProfilingLabels {
+			k, v, ok := strings.Cut(l, "=")
+			if !ok || k == "" {
+				return nil, fmt.Errorf("profiling: invalid label %q", l)
+			}
+			labels[k] = v
+		}
+		profiler, err = profiling.New(profiling.Options{
+			Endpoint: o.ProfilingEndpoint,
+			AppName:  o.TracingServiceName,
+			Labels:   labels,
+			Types:    o.ProfilingTypes,
+			Interval: o.ProfilingInterval,
+		}, logger)
+		if err != nil {
+			return nil, fmt.Errorf("profiling: %w", err)
+		}
+		b.profilerCloser = profiler
+	}
+
 	stateStore, err := InitStateStore(logger, o.DataDir)
 	if err != nil {
 		return nil, err
@@ -263,7 +416,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	// if theres a previous transaction hash, and not a new chequebook deployment on a node starting from scratch
 	// get old overlay
 	// mine nonce that gives similar new overlay
-	nonce, nonceExists, err := overlayNonceExists(stateStore)
+	nonce, nonceExists, err := OverlayNonceExists(stateStore)
 	if err != nil {
 		return nil, fmt.Errorf("check presence of nonce: %w", err)
 	}
@@ -275,7 +428,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	logger.Info("using overlay address", "address", swarmAddress)
 
 	if !nonceExists {
-		err := setOverlayNonce(stateStore, nonce)
+		err := SetOverlayNonce(stateStore, nonce)
 		if err != nil {
 			return nil, fmt.Errorf("statestore: save new overlay nonce: %w", err)
 		}
@@ -517,8 +670,14 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	lightNodes := lightnode.NewContainer(swarmAddress)
 
 	bootnodes := make([]ma.Multiaddr, 0, len(o.Bootnodes))
+	var bootnodeTrees []string
 
 	for _, a := range o.Bootnodes {
+		if dnsdisc.IsURL(a) {
+			bootnodeTrees = append(bootnodeTrees, a)
+			continue
+		}
+
 		addr, err := ma.NewMultiaddr(a)
 		if err != nil {
 			logger.Debug("create bootnode multiaddress from string failed", "string", a, "error", err)
@@ -591,6 +750,38 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		registry = debugService.MetricsRegistry()
 	}
 
+	allowlistOptions := allowlist.Options{
+		Overlays:  o.AllowlistOverlays,
+		Underlays: o.AllowlistUnderlays,
+	}
+	if o.AllowlistAuthority != "" {
+		if !common.IsHexAddress(o.AllowlistAuthority) {
+			return nil, errors.New("malformed allowlist authority address")
+		}
+		authority := common.HexToAddress(o.AllowlistAuthority)
+		allowlistOptions.Authority = &authority
+	}
+	peerAllowlist, err := allowlist.New(networkID, allowlistOptions)
+	if err != nil {
+		return nil, fmt.Errorf("allowlist: %w", err)
+	}
+	if peerAllowlist.Enabled() {
+		logger.Info("peer allowlist enabled", "overlays", len(o.AllowlistOverlays), "underlays", len(o.AllowlistUnderlays), "authority", o.AllowlistAuthority)
+	}
+
+	var attestation []byte
+	if o.AllowlistAttestation != "" {
+		attestation, err = hex.DecodeString(strings.TrimPrefix(o.AllowlistAttestation, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("allowlist attestation: %w", err)
+		}
+		attester, err := allowlist.Verify(attestation, swarmAddress, networkID)
+		if err != nil {
+			return nil, fmt.Errorf("allowlist attestation: %w", err)
+		}
+		logger.Info("advertising allowlist attestation", "authority", attester)
+	}
+
 	p2ps, err := libp2p.New(ctx, signer, networkID, swarmAddress, addr, addressbook, stateStore, lightNodes, logger, tracer, libp2p.Options{
 		PrivateKey:      libp2pPrivateKey,
 		NATAddr:         o.NATAddr,
@@ -599,7 +790,10 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		FullNode:        o.FullNodeMode,
 		Nonce:           nonce,
 		ValidateOverlay: chainEnabled,
+		Allowlist:       peerAllowlist,
+		Attestation:     attestation,
 		Registry:        registry,
+		Capabilities:    []p2p.Capability{pullsync.CapabilityAudit},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("p2p service: %w", err)
@@ -610,6 +804,27 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	b.p2pService = p2ps
 	b.p2pHalter = p2ps
 
+	backgroundLimits := limits(o.LowPower)
+	if o.LowPower {
+		logger.Info("using low-power limits of the background work", "limits", fmt.Sprintf("%+v", backgroundLimits))
+	}
+
+	// the memory budget is shared by the database caches, the buffers of the
+	// downloads and uploads and the pss messages
+	budgetLimit := int64(o.MemoryBudget)
+	if budgetLimit == 0 {
+		limit, err := cgroup.MemoryLimit()
+		if err != nil {
+			logger.Warning("unable to read the memory limit of the cgroup", "error", err)
+		}
+		budgetLimit = int64(float64(limit) * membudget.DefaultShare)
+	}
+	var memoryBudget *membudget.Budget
+	if budgetLimit > 0 {
+		memoryBudget = membudget.New(budgetLimit)
+		logger.Info("using memory budget", "limit_bytes", budgetLimit)
+	}
+
 	// localstore depends on batchstore
 	var path string
 
@@ -625,10 +840,26 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		ReserveCapacity:        uint64(batchstore.Capacity),
 		UnreserveFunc:          batchStore.Unreserve,
 		OpenFilesLimit:         o.DBOpenFilesLimit,
-		BlockCacheCapacity:     o.DBBlockCacheCapacity,
-		WriteBufferSize:        o.DBWriteBufferSize,
+		BlockCacheCapacity:     uint64(memoryBudget.Reserve(int64(o.DBBlockCacheCapacity))),
+		WriteBufferSize:        uint64(memoryBudget.Reserve(int64(o.DBWriteBufferSize))),
 		DisableSeeksCompaction: o.DBDisableSeeksCompaction,
 		ValidStamp:             validStamp,
+		SampleWorkers:          backgroundLimits.SampleWorkers,
+		ExpiredBatchRetention:  o.ExpiredBatchRetention,
+	}
+
+	var spamDetector *spam.Detector
+	if o.SpamDetection {
+		spamDetector = spam.New(spam.Options{
+			Window:        o.SpamWindow,
+			MaxRate:       o.SpamMaxRate,
+			MaxSkew:       o.SpamMaxSkew,
+			StorageRadius: batchStore.StorageRadius,
+		})
+		lo.ReserveIngressFunc = spamDetector.Record
+		if o.SpamDeprioritize {
+			lo.DeprioritizedBatchesFunc = spamDetector.FlaggedBatches
+		}
 	}
 
 	storer, err := localstore.New(path, swarmAddress.Bytes(), stateStore, lo, logger)
@@ -638,12 +869,46 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	b.localstoreCloser = storer
 	evictFn = storer.EvictBatch
 
+	maintenanceWindows, err := maintenance.ParseWindows(o.MaintenanceWindows)
+	if err != nil {
+		return nil, err
+	}
+	maintenanceScheduler := maintenance.New(maintenanceWindows, logger)
+	b.maintenanceCloser = maintenanceScheduler
+	if len(maintenanceWindows) > 0 {
+		logger.Info("heavy background jobs confined to the maintenance windows", "windows", maintenanceWindows.Strings())
+	}
+
+	maintenanceScheduler.Register("db_compaction", o.DBCompactionInterval, storer.Compact)
+	if pruner, ok := batchStore.(batchstore.Pruner); ok {
+		maintenanceScheduler.Register("batch_prune", o.BatchPruneInterval, batchstore.PruneJob(pruner, logger))
+	}
+
+	eventLog, err := eventlog.New(stateStore, eventlog.DefaultRetention, logger)
+	if err != nil {
+		return nil, fmt.Errorf("event log: %w", err)
+	}
+	b.eventLogCloser = eventLog
+
 	post, err := postage.NewService(stateStore, batchStore, chainID)
 	if err != nil {
 		return nil, fmt.Errorf("postage service load: %w", err)
 	}
 	b.postageServiceCloser = post
-	batchStore.SetBatchExpiryHandler(post)
+
+	pinExpiry := pinning.NewExpiryNotifier(storer, stateStore, logger, o.PinExpiryWebhooks)
+	b.pinExpiryCloser = pinExpiry
+	batchEvents := events.New(post, batchStore, logger, events.Options{
+		Webhooks:      o.PostageEventWebhooks,
+		BlockTime:     o.BlockTime,
+		ExpiryWarning: o.PostageExpiryWarning,
+	})
+	b.batchEventsCloser = batchEvents
+	eventLog.WatchBatchEvents(batchEvents)
+	batchStore.SetBatchExpiryHandler(&postage.ExpiryHandlers{
+		BatchExpiryHandler: post,
+		Handlers:           []postage.StampExpiryHandler{pinExpiry, batchEvents},
+	})
 
 	var (
 		postageStampContractService postagecontract.Interface
@@ -690,7 +955,11 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	eventListener = listener.New(b.syncingStopped, logger, chainBackend, postageStampContractAddress, postageStampContractABI, o.BlockTime, postageSyncingStallingTimeout, postageSyncingBackoffTimeout)
 	b.listenerCloser = eventListener
 
-	batchSvc, err = batchservice.New(stateStore, batchStore, logger, eventListener, overlayEthAddress.Bytes(), post, sha3.New256, o.Resync)
+	batchListener := &postage.BatchEventListeners{
+		BatchEventListener: post,
+		Listeners:          []postage.BatchEventListener{batchEvents},
+	}
+	batchSvc, err = batchservice.New(stateStore, batchStore, logger, eventListener, overlayEthAddress.Bytes(), batchListener, sha3.New256, o.Resync)
 	if err != nil {
 		return nil, err
 	}
@@ -740,15 +1009,45 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	}
 
 	kad, err := kademlia.New(swarmAddress, addressbook, hive, p2ps, pingPong, metricsDB, logger,
-		kademlia.Options{Bootnodes: bootnodes, BootnodeMode: o.BootnodeMode, StaticNodes: o.StaticNodes, IgnoreRadius: !chainEnabled})
+		kademlia.Options{
+			Bootnodes:           bootnodes,
+			BootnodeTrees:       bootnodeTrees,
+			BootnodeMode:        o.BootnodeMode,
+			StaticNodes:         o.StaticNodes,
+			IgnoreRadius:        !chainEnabled,
+			SaturationPeers:     &backgroundLimits.SaturationPeers,
+			OverSaturationPeers: &backgroundLimits.OverSaturationPeers,
+		})
 	if err != nil {
 		return nil, fmt.Errorf("unable to create kademlia: %w", err)
 	}
 	b.topologyCloser = kad
 	b.topologyHalter = kad
+	eventLog.WatchTopology(kad)
 	hive.SetAddPeersHandler(kad.AddPeers)
 	p2ps.SetPickyNotifier(kad)
 
+	broadcastService, err := broadcast.New(p2ps, kad, swarmAddress, logger)
+	if err != nil {
+		return nil, fmt.Errorf("broadcast service: %w", err)
+	}
+	b.broadcastCloser = broadcastService
+	if err = p2ps.AddProtocol(broadcastService.Protocol()); err != nil {
+		return nil, fmt.Errorf("broadcast service: %w", err)
+	}
+
+	if o.TopologySnapshotDir != "" {
+		dumper, err := snapshot.New(o.TopologySnapshotDir, o.TopologySnapshotInterval, snapshot.DefaultKeep, func() *topology.KadParams {
+			params := kad.Snapshot()
+			params.LightNodes = lightNodes.PeerInfo()
+			return params
+		}, logger)
+		if err != nil {
+			return nil, fmt.Errorf("topology snapshot: %w", err)
+		}
+		b.topologySnapshotCloser = dumper
+	}
+
 	var (
 		syncErr    atomic.Value
 		syncStatus atomic.Value
@@ -860,6 +1159,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	}
 
 	acc.SetRefreshFunc(pseudosettleService.Pay)
+	acc.SetSettlementFunc(eventLog.Settlement)
 
 	if o.SwapEnable && chainEnabled {
 		var priceOracle priceoracle.Service
@@ -885,18 +1185,76 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		if o.ChequebookEnable {
 			acc.SetPayFunc(swapService.Pay)
 		}
+
+		if o.CashoutInterval > 0 {
+			cashoutMinAmount, ok := new(big.Int).SetString(o.CashoutMinAmount, 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid cashout minimum amount: %s", o.CashoutMinAmount)
+			}
+			maintenanceScheduler.Register("cashout", o.CashoutInterval, swap.CashoutJob(swapService, cashoutMinAmount, logger))
+		}
 	}
 
 	pricing.SetPaymentThresholdObserver(acc)
 
 	retrieve := retrieval.New(swarmAddress, storer, p2ps, kad, logger, acc, pricer, tracer, o.RetrievalCaching, validStamp)
-	tagService := tags.NewTags(stateStore, logger)
+	retrieve.SetPriorityLimits(retrieval.PriorityInteractive, retrieval.PriorityLimits{
+		Workers: o.RetrievalInteractiveWorkers,
+		Budget:  o.RetrievalInteractiveBudget,
+	})
+	retrieve.SetPriorityLimits(retrieval.PriorityBackground, retrieval.PriorityLimits{
+		Workers: o.RetrievalBackgroundWorkers,
+		Budget:  o.RetrievalBackgroundBudget,
+	})
+	// the tags and the idempotent responses are kept in the shared state
+	// store, so that any of the API frontends of a gateway can serve them
+	apiStateStore := stateStore
+	var tagService *tags.Tags
+	if o.SharedStateStore != "" {
+		sharedStateStore, err := redisstatestore.NewStateStore(o.SharedStateStore)
+		if err != nil {
+			return nil, fmt.Errorf("shared statestore: %w", err)
+		}
+		b.sharedStateStoreCloser = sharedStateStore
+		apiStateStore = sharedStateStore
+		tagService = tags.NewSharedTags(sharedStateStore, logger)
+	} else {
+		tagService = tags.NewTags(stateStore, logger)
+	}
 	b.tagsCloser = tagService
 
-	pssService := pss.New(pssPrivateKey, logger)
+	pssService := pss.New(pssPrivateKey, logger, pss.Options{
+		MinDelay:      o.PssMinDelay,
+		MaxDelay:      o.PssMaxDelay,
+		CoverInterval: o.PssCoverInterval,
+		CoverBudget:   o.PssCoverBudget,
+		MemoryBudget:  memoryBudget,
+	})
 	b.pssCloser = pssService
+	pssSessions := session.New(pssPrivateKey, pssService, swarmAddress, logger)
 
-	ns := netstore.New(storer, validStamp, retrieve, logger)
+	var sharedCache sharedcache.Cache
+	if o.SharedCache != "" {
+		sharedCache, err = sharedcache.New(o.SharedCache, sharedcache.Options{TTL: o.SharedCacheTTL})
+		if err != nil {
+			return nil, fmt.Errorf("shared cache: %w", err)
+		}
+		b.sharedCacheCloser = sharedCache
+	}
+
+	var (
+		netRetrieval      retrieval.Interface = retrieve
+		fallbackRetrieval *fallback.Retriever
+	)
+	if len(o.RetrievalFallbackGateways) > 0 {
+		fallbackRetrieval, err = fallback.New(retrieve, o.RetrievalFallbackGateways, logger, fallback.Options{Budget: o.RetrievalFallbackBudget})
+		if err != nil {
+			return nil, fmt.Errorf("retrieval fallback: %w", err)
+		}
+		netRetrieval = fallbackRetrieval
+	}
+
+	ns := netstore.New(storer, validStamp, netRetrieval, sharedCache, logger)
 	b.nsCloser = ns
 
 	traversalService := traversal.New(ns)
@@ -922,10 +1280,15 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 
 	pullSyncProtocol := pullsync.New(p2ps, pullStorage, pssService.TryUnwrap, validStamp, logger, batchStore, swarmAddress)
 	b.pullSyncCloser = pullSyncProtocol
+	eventLog.WatchSyncedChunks(pullSyncProtocol.SyncedChunks, eventlog.DefaultSyncedInterval)
+
+	snapshotService := reservesnapshot.New(p2ps, pullStorage, validStamp, logger, batchStore, swarmAddress, reservesnapshot.Options{Serve: o.ReserveSnapshotServe})
+	b.snapshotCloser = snapshotService
 
 	retrieveProtocolSpec := retrieve.Protocol()
 	pushSyncProtocolSpec := pushSyncProtocol.Protocol()
 	pullSyncProtocolSpec := pullSyncProtocol.Protocol()
+	snapshotProtocolSpec := snapshotService.Protocol()
 
 	if o.FullNodeMode && !o.BootnodeMode {
 		logger.Info("starting in full mode")
@@ -934,6 +1297,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		p2p.WithBlocklistStreams(p2p.DefaultBlocklistTime, retrieveProtocolSpec)
 		p2p.WithBlocklistStreams(p2p.DefaultBlocklistTime, pushSyncProtocolSpec)
 		p2p.WithBlocklistStreams(p2p.DefaultBlocklistTime, pullSyncProtocolSpec)
+		p2p.WithBlocklistStreams(p2p.DefaultBlocklistTime, snapshotProtocolSpec)
 	}
 
 	if err = p2ps.AddProtocol(retrieveProtocolSpec); err != nil {
@@ -945,6 +1309,9 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	if err = p2ps.AddProtocol(pullSyncProtocolSpec); err != nil {
 		return nil, fmt.Errorf("pullsync protocol: %w", err)
 	}
+	if err = p2ps.AddProtocol(snapshotProtocolSpec); err != nil {
+		return nil, fmt.Errorf("reserve snapshot protocol: %w", err)
+	}
 
 	stakingContractAddress := chainCfg.StakingAddress
 	if o.StakingContractAddress != "" {
@@ -963,13 +1330,24 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	var (
 		pullerService *puller.Puller
 		agent         *storageincentives.Agent
+		depthMonitor  *depthmonitor.Service
 	)
 
 	if o.FullNodeMode && !o.BootnodeMode {
-		pullerService = puller.New(stateStore, kad, batchStore, pullSyncProtocol, p2ps, logger, puller.Options{SyncSleepDur: puller.DefaultSyncErrorSleepDur, ShallowBinsWarmupDur: puller.DefaultShallowBinsWarmupDur}, warmupTime)
+		pullerService = puller.New(stateStore, kad, batchStore, pullSyncProtocol, p2ps, logger, puller.Options{
+			SyncSleepDur:         puller.DefaultSyncErrorSleepDur,
+			ShallowBinsWarmupDur: puller.DefaultShallowBinsWarmupDur,
+			AuditInterval:        backgroundLimits.AuditInterval,
+			MaxHistSyncing:       backgroundLimits.HistSyncing,
+			Capabilities:         p2ps,
+		}, warmupTime)
 		b.pullerCloser = pullerService
 
-		depthMonitor := depthmonitor.New(kad, pullSyncProtocol, storer, batchStore, logger, warmupTime, depthmonitor.DefaultWakeupInterval, !batchStoreExists)
+		depthMonitor = depthmonitor.New(kad, pullSyncProtocol, storer, batchStore, logger, warmupTime, backgroundLimits.DepthMonitorInterval, !batchStoreExists, depthmonitor.Options{
+			DecreaseThreshold: o.DepthDecreaseThreshold,
+			Cooldown:          o.DepthCooldown,
+			Confirmations:     o.DepthConfirmations,
+		})
 		b.depthMonitorCloser = depthMonitor
 
 		if o.EnableStorageIncentives {
@@ -1021,15 +1399,153 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	}
 
 	feedFactory := factory.New(ns)
-	steward := steward.New(storer, traversalService, retrieve, pushSyncProtocol)
+	stewardService := steward.New(storer, traversalService, retrieve, pushSyncProtocol)
+
+	jobsManager, err := jobs.New(stateStore, logger)
+	if err != nil {
+		return nil, fmt.Errorf("jobs: %w", err)
+	}
+	b.jobsCloser = jobsManager
+	jobsManager.Register(pinning.JobKind, maxRunningJobs, pinning.NewJob(pinningService))
+	jobsManager.Register(steward.JobKind, maxRunningJobs, steward.NewJob(stewardService))
+	warmerService := warmer.New(ns, traversalService, jobsManager, logger)
+
+	analyticsTracker := analytics.New(analytics.DefaultMaxReferences)
+
+	deployService := deploy.New(stateStore, stewardService, signer, feedFactory)
+	crdtService := crdt.New(feedFactory, signer)
+	aliasRegistry := alias.NewRegistry(stateStore, feedFactory)
+
+	var replicator *replica.Replicator
+	if o.ReplicaPrimaryAPI != "" {
+		replicator = replica.New(pinningService, aliasRegistry, tagService, replica.Options{
+			PrimaryURL: o.ReplicaPrimaryAPI,
+			Interval:   o.ReplicaInterval,
+			Token:      o.ReplicaToken,
+		}, logger)
+		replicator.Start()
+		b.replicaCloser = replicator
+		logger.Info("node in standby mode", "primary", o.ReplicaPrimaryAPI)
+	}
+
+	var dnsLinkHook *dnslink.Hook
+	if o.DNSLinkProvider != "" {
+		provider, err := dnslink.NewProvider(o.DNSLinkProvider)
+		if err != nil {
+			return nil, fmt.Errorf("dnslink provider: %w", err)
+		}
+		bindings := make([]dnslink.Binding, 0, len(o.DNSLinkFeeds))
+		for _, v := range o.DNSLinkFeeds {
+			binding, err := dnslink.ParseBinding(v)
+			if err != nil {
+				return nil, fmt.Errorf("dnslink feeds: %w", err)
+			}
+			bindings = append(bindings, binding)
+		}
+		if len(bindings) == 0 {
+			return nil, errors.New("dnslink feeds: no feeds bound to the domains")
+		}
+		dnsLinkHook = dnslink.NewHook(provider, dnslink.FeedResolver(feedFactory), bindings, o.DNSLinkInterval, logger)
+		b.dnsLinkCloser = dnsLinkHook
+	}
+
+	var transformService *transform.Service
+	if o.ImageTransform {
+		// the derived content is not cached without the data directory
+		var transformCache transform.Cache
+		if o.DataDir != "" {
+			transformCache, err = transform.NewDiskCache(filepath.Join(o.DataDir, "transform"), int64(o.TransformCacheSize))
+			if err != nil {
+				return nil, fmt.Errorf("transform cache: %w", err)
+			}
+		}
+		transformService = transform.New(transformCache, transform.DefaultMaxSourceSize, transform.NewImage())
+	}
+
+	var ipfsGateway *ipfs.Gateway
+	if o.IPFSGateway != "" {
+		ipfsGateway, err = ipfs.New(o.IPFSGateway, ipfs.Options{})
+		if err != nil {
+			return nil, fmt.Errorf("ipfs gateway: %w", err)
+		}
+	}
+
+	var challengeService *challenge.Service
+	if o.ChallengeMode != "" {
+		if o.ChallengeDifficulty > challenge.MaxDifficulty {
+			return nil, fmt.Errorf("challenge difficulty %d exceeds %d", o.ChallengeDifficulty, challenge.MaxDifficulty)
+		}
+		secret, err := hex.DecodeString(o.ChallengeSecret)
+		if err != nil {
+			return nil, fmt.Errorf("challenge secret: %w", err)
+		}
+		challengeService, err = challenge.New(challenge.Options{
+			Mode:       o.ChallengeMode,
+			Difficulty: uint8(o.ChallengeDifficulty),
+			TokenTTL:   o.ChallengeTokenTTL,
+			Rate:       o.ChallengeRate,
+			Burst:      o.ChallengeBurst,
+			Routes:     o.ChallengeRoutes,
+			Exempt:     o.ChallengeExempt,
+			Secret:     secret,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("challenge: %w", err)
+		}
+	}
+
+	var scheduler *shaping.Scheduler
+	if o.MaxConcurrentDownloads > 0 || o.ClientConcurrentDownloads > 0 || o.ReferenceConcurrentDownloads > 0 {
+		scheduler = shaping.New(shaping.Options{
+			MaxConcurrent: o.MaxConcurrentDownloads,
+			PerClient:     o.ClientConcurrentDownloads,
+			PerReference:  o.ReferenceConcurrentDownloads,
+			QueueSize:     o.DownloadQueueSize,
+			QueueTimeout:  o.DownloadQueueTimeout,
+		})
+	}
+
+	// the limits are changed at runtime through the debug API
+	downloadLimiter := api.NewDownloadLimiter(api.DownloadLimits{
+		ClientBandwidth: o.DownloadClientBandwidth,
+		ClientBurst:     o.DownloadClientBurst,
+		MaxDownloads:    o.MaxDownloadJoiners,
+	})
 
 	nodeStatus := status.NewService(logger, p2ps, kad, storer, pullSyncProtocol, batchStore)
 	if err = p2ps.AddProtocol(nodeStatus.Protocol()); err != nil {
 		return nil, fmt.Errorf("status service: %w", err)
 	}
 
+	var auditLog *auditlog.Logger
+	if o.AuditLog != "" {
+		var redact auditlog.Options
+		for _, field := range o.AuditLogRedact {
+			switch field {
+			case "caller":
+				redact.RedactCaller = true
+			case "reference":
+				redact.RedactReference = true
+			case "batch":
+				redact.RedactBatch = true
+			default:
+				return nil, fmt.Errorf("audit log: unknown redacted field %q", field)
+			}
+		}
+		sink, err := auditlog.NewSink(o.AuditLog, o.AuditLogMaxSize, o.AuditLogMaxBackups)
+		if err != nil {
+			return nil, fmt.Errorf("audit log sink: %w", err)
+		}
+		auditLog, err = auditlog.New(sink, redact, logger)
+		if err != nil {
+			return nil, fmt.Errorf("audit log: %w", err)
+		}
+		b.auditLogCloser = auditLog
+	}
+
 	extraOpts := api.ExtraOptions{
 		Pingpong:         pingPong,
+		PeerRetriever:    retrieve,
 		TopologyDriver:   kad,
 		LightNodes:       lightNodes,
 		Accounting:       acc,
@@ -1039,21 +1555,60 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		BlockTime:        o.BlockTime,
 		Tags:             tagService,
 		Storer:           ns,
+		StateStore:       apiStateStore,
 		Resolver:         multiResolver,
 		Pss:              pssService,
+		PssSessions:      pssSessions,
+		Broadcast:        broadcastService,
 		TraversalService: traversalService,
 		Pinning:          pinningService,
+		PinExpiry:        pinExpiry,
+		BatchEvents:      batchEvents,
 		FeedFactory:      feedFactory,
 		Post:             post,
 		PostageContract:  postageStampContractService,
 		Staking:          stakingContract,
-		Steward:          steward,
+		Steward:          stewardService,
+		Warmer:           warmerService,
+		Deploy:           deployService,
+		Crdt:             crdtService,
+		Aliases:          aliasRegistry,
+		Analytics:        analyticsTracker,
+		Transform:        transformService,
+		IPFS:             ipfsGateway,
+		Challenge:        challengeService,
+		Shaping:          scheduler,
+		DownloadLimiter:  downloadLimiter,
 		SyncStatus:       syncStatusFn,
 		IndexDebugger:    storer,
+		ReserveEvicter:   storer,
+		Spam:             spamDetector,
+		DepthMonitor:     depthMonitor,
+		Replica:          replicator,
+		MemoryBudget:     memoryBudget,
+		Maintenance:      maintenanceScheduler,
+		Jobs:             jobsManager,
+		Events:           eventLog,
+		DNSLink:          dnsLinkHook,
+		AccessControl:    accesscontrol.New(pssPrivateKey),
 		NodeStatus:       nodeStatus,
+		AuditLog:         auditLog,
 	}
 
 	if o.APIAddr != "" {
+		lookahead, err := api.ParseLookaheadPolicy(o.APILookahead)
+		if err != nil {
+			return nil, fmt.Errorf("api lookahead: %w", err)
+		}
+		compression, err := api.ParseCompressionPolicy(o.APICompressionTypes, o.APICompressionMinSize)
+		if err != nil {
+			return nil, fmt.Errorf("api compression: %w", err)
+		}
+		trustedProxies, err := api.ParseTrustedProxies(o.AuditLogTrustedProxies)
+		if err != nil {
+			return nil, fmt.Errorf("audit log trusted proxies: %w", err)
+		}
+
 		if apiService == nil {
 			apiService = api.New(*publicKey, pssPrivateKey.PublicKey, overlayEthAddress, logger, transactionService, batchStore, beeNodeMode, o.ChequebookEnable, o.SwapEnable, chainBackend, o.CORSAllowedOrigins)
 			apiService.SetProbe(probe)
@@ -1061,9 +1616,16 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		}
 
 		chunkC := apiService.Configure(signer, authenticator, tracer, api.Options{
-			CORSAllowedOrigins: o.CORSAllowedOrigins,
-			WsPingPeriod:       60 * time.Second,
-			Restricted:         o.Restricted,
+			CORSAllowedOrigins:   o.CORSAllowedOrigins,
+			WsPingPeriod:         60 * time.Second,
+			Restricted:           o.Restricted,
+			Lookahead:            lookahead,
+			Compression:          compression,
+			GraphQL:              o.GraphQLEnable,
+			DirListing:           o.EnableDirListing,
+			GatewayDomain:        o.GatewayDomain,
+			GatewaySubdomainOnly: o.GatewaySubdomainOnly,
+			TrustedProxies:       trustedProxies,
 		}, extraOpts, chainID, erc20Service)
 
 		pusherService.AddFeed(chunkC)
@@ -1107,6 +1669,14 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		debugService.MustRegisterMetrics(storer.Metrics()...)
 		debugService.MustRegisterMetrics(kad.Metrics()...)
 
+		if auditLog != nil {
+			debugService.MustRegisterMetrics(auditLog.Metrics()...)
+		}
+
+		if profiler != nil {
+			debugService.MustRegisterMetrics(profiler.Metrics()...)
+		}
+
 		if pullerService != nil {
 			debugService.MustRegisterMetrics(pullerService.Metrics()...)
 		}
@@ -1118,8 +1688,41 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		debugService.MustRegisterMetrics(pushSyncProtocol.Metrics()...)
 		debugService.MustRegisterMetrics(pusherService.Metrics()...)
 		debugService.MustRegisterMetrics(pullSyncProtocol.Metrics()...)
+		debugService.MustRegisterMetrics(snapshotService.Metrics()...)
+		debugService.MustRegisterMetrics(broadcastService.Metrics()...)
 		debugService.MustRegisterMetrics(pullStorage.Metrics()...)
 		debugService.MustRegisterMetrics(retrieve.Metrics()...)
+		if fallbackRetrieval != nil {
+			debugService.MustRegisterMetrics(fallbackRetrieval.Metrics()...)
+		}
+		debugService.MustRegisterMetrics(warmerService.Metrics()...)
+		debugService.MustRegisterMetrics(jobsManager.Metrics()...)
+		debugService.MustRegisterMetrics(analyticsTracker.Metrics()...)
+		debugService.MustRegisterMetrics(deployService.Metrics()...)
+		if transformService != nil {
+			debugService.MustRegisterMetrics(transformService.Metrics()...)
+		}
+		if challengeService != nil {
+			debugService.MustRegisterMetrics(challengeService.Metrics()...)
+		}
+		if scheduler != nil {
+			debugService.MustRegisterMetrics(scheduler.Metrics()...)
+		}
+		if spamDetector != nil {
+			debugService.MustRegisterMetrics(spamDetector.Metrics()...)
+		}
+		if replicator != nil {
+			debugService.MustRegisterMetrics(replicator.Metrics()...)
+		}
+		if memoryBudget != nil {
+			debugService.MustRegisterMetrics(memoryBudget.Metrics()...)
+		}
+		if dnsLinkHook != nil {
+			debugService.MustRegisterMetrics(dnsLinkHook.Metrics()...)
+		}
+		debugService.MustRegisterMetrics(backgroundLimits.Metrics(o.LowPower)...)
+		debugService.MustRegisterMetrics(maintenanceScheduler.Metrics()...)
+		debugService.MustRegisterMetrics(eventLog.Metrics()...)
 		debugService.MustRegisterMetrics(lightNodes.Metrics()...)
 		debugService.MustRegisterMetrics(hive.Metrics()...)
 
@@ -1156,6 +1759,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 			CORSAllowedOrigins: o.CORSAllowedOrigins,
 			WsPingPeriod:       60 * time.Second,
 			Restricted:         o.Restricted,
+			GraphQL:            o.GraphQLEnable,
 		}, extraOpts, chainID, erc20Service)
 
 		debugService.SetP2P(p2ps)
@@ -1172,13 +1776,96 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		return nil, err
 	}
 
+	var postageSyncStatus func() (bool, error)
+	if batchSvc != nil && chainEnabled {
+		postageSyncStatus = syncStatusFn
+	}
+	go b.signalReady(ctx, logger, warmupTime, postageSyncStatus)
+
+	go func() {
+		// the interrupted jobs retrieve the content from the peers,
+		// which are connected once the node is warmed up
+		select {
+		case <-b.Ready():
+			jobsManager.Resume()
+		case <-ctx.Done():
+		}
+	}()
+
+	if dnsLinkHook != nil {
+		go func() {
+			// the feeds are looked up on the network,
+			// so the hook is started once the node is ready
+			select {
+			case <-b.Ready():
+				dnsLinkHook.Start()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	if o.ReserveBootstrap && pullerService != nil && storer.ReserveSize() == 0 {
+		go func() {
+			// the stamps of the snapshot are validated
+			// against the synced postage contract data
+			select {
+			case <-b.Ready():
+			case <-ctx.Done():
+				return
+			}
+			if err := snapshotService.Bootstrap(ctx, kad, pullerService); err != nil {
+				logger.Warning("reserve bootstrap from snapshot failed, falling back to pull syncing", "error", err)
+			}
+		}()
+	}
+
 	return b, nil
 }
 
+// signalReady closes the ready channel after the warmup time
+// and when the postage contract data is synced, if it is synced.
+// The sync status is checked again after the failed checks.
+func (b *Bee) signalReady(ctx context.Context, logger log.Logger, warmupTime time.Duration, syncStatus func() (bool, error)) {
+	select {
+	case <-time.After(warmupTime):
+	case <-ctx.Done():
+		return
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	warned := false
+	for syncStatus != nil {
+		done, err := syncStatus()
+		if err != nil {
+			logger.Debug("postage sync status check failed", "error", err)
+			if !warned {
+				logger.Warning("postage sync status check failed, retrying")
+				warned = true
+			}
+		}
+		if done {
+			break
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+	close(b.ready)
+}
+
 func (b *Bee) SyncingStopped() chan struct{} {
 	return b.syncingStopped.C
 }
 
+// Ready returns a channel which is closed when the node
+// has warmed up and the postage contract data is synced.
+func (b *Bee) Ready() <-chan struct{} {
+	return b.ready
+}
+
 func (b *Bee) Shutdown() error {
 	var mErr error
 
@@ -1241,7 +1928,7 @@ func (b *Bee) Shutdown() error {
 	}
 
 	var wg sync.WaitGroup
-	wg.Add(7)
+	wg.Add(9)
 	go func() {
 		defer wg.Done()
 		tryClose(b.chainSyncerCloser, "chain syncer")
@@ -1250,6 +1937,10 @@ func (b *Bee) Shutdown() error {
 		defer wg.Done()
 		tryClose(b.pssCloser, "pss")
 	}()
+	go func() {
+		defer wg.Done()
+		tryClose(b.broadcastCloser, "broadcast")
+	}()
 	go func() {
 		defer wg.Done()
 		tryClose(b.pusherCloser, "pusher")
@@ -1268,6 +1959,10 @@ func (b *Bee) Shutdown() error {
 		defer wg.Done()
 		tryClose(b.pullSyncCloser, "pull sync")
 	}()
+	go func() {
+		defer wg.Done()
+		tryClose(b.snapshotCloser, "reserve snapshot")
+	}()
 	go func() {
 		defer wg.Done()
 		tryClose(b.hiveCloser, "hive")
@@ -1278,7 +1973,7 @@ func (b *Bee) Shutdown() error {
 	tryClose(b.p2pService, "p2p server")
 	tryClose(b.priceOracleCloser, "price oracle service")
 
-	wg.Add(3)
+	wg.Add(5)
 	go func() {
 		defer wg.Done()
 		tryClose(b.transactionMonitorCloser, "transaction monitor")
@@ -1292,6 +1987,14 @@ func (b *Bee) Shutdown() error {
 		defer wg.Done()
 		tryClose(b.postageServiceCloser, "postage service")
 	}()
+	go func() {
+		defer wg.Done()
+		tryClose(b.pinExpiryCloser, "pin expiry notifier")
+	}()
+	go func() {
+		defer wg.Done()
+		tryClose(b.batchEventsCloser, "postage batch event notifier")
+	}()
 
 	wg.Wait()
 
@@ -1299,12 +2002,22 @@ func (b *Bee) Shutdown() error {
 		c()
 	}
 
+	tryClose(b.auditLogCloser, "audit log")
+	tryClose(b.profilerCloser, "profiler")
 	tryClose(b.tracerCloser, "tracer")
 	tryClose(b.tagsCloser, "tag persistence")
+	tryClose(b.sharedStateStoreCloser, "shared statestore")
+	tryClose(b.topologySnapshotCloser, "topology snapshot")
 	tryClose(b.topologyCloser, "topology driver")
+	tryClose(b.jobsCloser, "jobs")
 	tryClose(b.nsCloser, "netstore")
+	tryClose(b.sharedCacheCloser, "shared cache")
 	tryClose(b.depthMonitorCloser, "depthmonitor service")
 	tryClose(b.storageIncetivesCloser, "storage incentives agent")
+	tryClose(b.maintenanceCloser, "maintenance scheduler")
+	tryClose(b.eventLogCloser, "event log")
+	tryClose(b.replicaCloser, "replicator")
+	tryClose(b.dnsLinkCloser, "dnslink hook")
 	tryClose(b.stateStoreCloser, "statestore")
 	tryClose(b.localstoreCloser, "localstore")
 	tryClose(b.resolverCloser, "resolver service")
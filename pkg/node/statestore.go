@@ -65,7 +65,9 @@ func SetOverlayInStore(overlay swarm.Address, storer storage.StateStorer) error
 
 const OverlayNonce = "overlayV2_nonce"
 
-func overlayNonceExists(s storage.StateStorer) ([]byte, bool, error) {
+// OverlayNonceExists returns the overlay nonce from the statestore and whether it
+// was set, or a zero nonce if it was not.
+func OverlayNonceExists(s storage.StateStorer) ([]byte, bool, error) {
 	overlayNonce := make([]byte, 32)
 	if err := s.Get(OverlayNonce, &overlayNonce); err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
@@ -76,6 +78,7 @@ func overlayNonceExists(s storage.StateStorer) ([]byte, bool, error) {
 	return overlayNonce, true, nil
 }
 
-func setOverlayNonce(s storage.StateStorer, overlayNonce []byte) error {
+// SetOverlayNonce sets the overlay nonce stored in the statestore.
+func SetOverlayNonce(s storage.StateStorer, overlayNonce []byte) error {
 	return s.Put(OverlayNonce, overlayNonce)
 }
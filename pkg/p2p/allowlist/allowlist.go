@@ -0,0 +1,174 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package allowlist restricts the peers of a node to the configured overlay
+// addresses, underlay networks and the peers attested by an authority, for
+// the deployments of semi-private swarms which still use the public chain.
+//
+// An attestation is the signature of the authority key over the overlay
+// address of a node and the network id. The node advertises its attestation
+// in the handshake, so that the peers which trust the authority accept it
+// without the need to list every node of the swarm.
+package allowlist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/swarm"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+)
+
+// ErrInvalidAttestation is returned if the attestation is
+// not a valid signature over the overlay address.
+var ErrInvalidAttestation = errors.New("invalid attestation")
+
+// Options are the rules of the Allowlist. A peer is allowed if it
+// matches any of them. The Allowlist without rules allows all peers.
+type Options struct {
+	// Overlays are the allowed overlay addresses.
+	Overlays []swarm.Address
+	// Underlays are the allowed IP addresses or CIDR networks of the
+	// peers. The multiaddresses are matched by their IP address.
+	Underlays []string
+	// Authority is the Ethereum address of the key whose
+	// attestations of the overlay addresses are accepted.
+	Authority *common.Address
+}
+
+// Allowlist decides which peers the node connects to.
+type Allowlist struct {
+	networkID uint64
+	overlays  map[string]struct{}
+	networks  []*net.IPNet
+	authority *common.Address
+}
+
+// New creates a new Allowlist for the network with the given rules.
+func New(networkID uint64, o Options) (*Allowlist, error) {
+	a := &Allowlist{
+		networkID: networkID,
+		overlays:  make(map[string]struct{}, len(o.Overlays)),
+		authority: o.Authority,
+	}
+	for _, overlay := range o.Overlays {
+		a.overlays[overlay.ByteString()] = struct{}{}
+	}
+	for _, u := range o.Underlays {
+		n, err := parseNetwork(u)
+		if err != nil {
+			return nil, err
+		}
+		a.networks = append(a.networks, n)
+	}
+	return a, nil
+}
+
+// Enabled reports whether the allowlist has any rules. The
+// nil Allowlist is disabled and allows all peers.
+func (a *Allowlist) Enabled() bool {
+	return a != nil && (len(a.overlays) > 0 || len(a.networks) > 0 || a.authority != nil)
+}
+
+// Allowed reports whether the peer with the overlay address, connected from
+// the remote underlay address, which advertised the attestation is allowed.
+func (a *Allowlist) Allowed(overlay swarm.Address, remote ma.Multiaddr, attestation []byte) bool {
+	if !a.Enabled() {
+		return true
+	}
+
+	if _, ok := a.overlays[overlay.ByteString()]; ok {
+		return true
+	}
+
+	if len(a.networks) > 0 && remote != nil {
+		if ip, err := manet.ToIP(remote); err == nil {
+			for _, n := range a.networks {
+				if n.Contains(ip) {
+					return true
+				}
+			}
+		}
+	}
+
+	if a.authority != nil && len(attestation) > 0 {
+		signer, err := Verify(attestation, overlay, a.networkID)
+		if err == nil && bytes.Equal(signer.Bytes(), a.authority.Bytes()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Attest signs the overlay address of a node in the network with the
+// authority signer. The result is advertised by the node in the handshake.
+func Attest(signer crypto.Signer, overlay swarm.Address, networkID uint64) ([]byte, error) {
+	return signer.Sign(signData(overlay, networkID))
+}
+
+// Verify returns the Ethereum address of the key which signed the
+// attestation of the overlay address of a node in the network.
+func Verify(attestation []byte, overlay swarm.Address, networkID uint64) (common.Address, error) {
+	pk, err := crypto.Recover(attestation, signData(overlay, networkID))
+	if err != nil {
+		return common.Address{}, ErrInvalidAttestation
+	}
+	addr, err := crypto.NewEthereumAddress(*pk)
+	if err != nil {
+		return common.Address{}, ErrInvalidAttestation
+	}
+	return common.BytesToAddress(addr), nil
+}
+
+func signData(overlay swarm.Address, networkID uint64) []byte {
+	networkIDBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(networkIDBytes, networkID)
+	data := append([]byte("bee-allowlist-"), overlay.Bytes()...)
+	return append(data, networkIDBytes...)
+}
+
+// parseNetwork parses the IP address, CIDR network or
+// the multiaddress with the IP address of an underlay.
+func parseNetwork(s string) (*net.IPNet, error) {
+	if strings.HasPrefix(s, "/") {
+		addr, err := ma.NewMultiaddr(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid underlay %q: %w", s, err)
+		}
+		ip, err := manet.ToIP(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid underlay %q: %w", s, err)
+		}
+		return hostNetwork(ip), nil
+	}
+
+	if strings.Contains(s, "/") {
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid underlay %q: %w", s, err)
+		}
+		return n, nil
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid underlay %q", s)
+	}
+	return hostNetwork(ip), nil
+}
+
+func hostNetwork(ip net.IP) *net.IPNet {
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
@@ -0,0 +1,167 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package allowlist_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/p2p/allowlist"
+	"github.com/ethersphere/bee/pkg/swarm"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+const networkID = 10
+
+func TestAllowed(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authoritySigner := crypto.NewDefaultSigner(key)
+	authority, err := authoritySigner.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err = crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSigner := crypto.NewDefaultSigner(key)
+
+	listed := swarm.RandAddress(t)
+	attested := swarm.RandAddress(t)
+	unknown := swarm.RandAddress(t)
+
+	attestation, err := allowlist.Attest(authoritySigner, attested, networkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherAttestation, err := allowlist.Attest(otherSigner, attested, networkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherNetworkAttestation, err := allowlist.Attest(authoritySigner, attested, networkID+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := allowlist.New(networkID, allowlist.Options{
+		Overlays:  []swarm.Address{listed},
+		Underlays: []string{"10.1.0.0/16", "192.168.1.1", "/ip6/2001:db8::1/tcp/1634"},
+		Authority: &authority,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name        string
+		overlay     swarm.Address
+		underlay    string
+		attestation []byte
+		want        bool
+	}{
+		{name: "listed overlay", overlay: listed, underlay: "/ip4/1.2.3.4/tcp/1634", want: true},
+		{name: "network", overlay: unknown, underlay: "/ip4/10.1.2.3/tcp/1634", want: true},
+		{name: "outside network", overlay: unknown, underlay: "/ip4/10.2.2.3/tcp/1634", want: false},
+		{name: "ip", overlay: unknown, underlay: "/ip4/192.168.1.1/tcp/1634", want: true},
+		{name: "other ip", overlay: unknown, underlay: "/ip4/192.168.1.2/tcp/1634", want: false},
+		{name: "multiaddr ip", overlay: unknown, underlay: "/ip6/2001:db8::1/tcp/1635", want: true},
+		{name: "attested", overlay: attested, underlay: "/ip4/1.2.3.4/tcp/1634", attestation: attestation, want: true},
+		{name: "attested by other key", overlay: attested, underlay: "/ip4/1.2.3.4/tcp/1634", attestation: otherAttestation, want: false},
+		{name: "attested for other network", overlay: attested, underlay: "/ip4/1.2.3.4/tcp/1634", attestation: otherNetworkAttestation, want: false},
+		{name: "attestation of other overlay", overlay: unknown, underlay: "/ip4/1.2.3.4/tcp/1634", attestation: attestation, want: false},
+		{name: "unknown", overlay: unknown, underlay: "/ip4/1.2.3.4/tcp/1634", want: false},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := a.Allowed(tc.overlay, ma.StringCast(tc.underlay), tc.attestation); got != tc.want {
+				t.Fatalf("got allowed %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	t.Parallel()
+
+	var nilAllowlist *allowlist.Allowlist
+	if nilAllowlist.Enabled() {
+		t.Fatal("nil allowlist enabled")
+	}
+	if !nilAllowlist.Allowed(swarm.RandAddress(t), nil, nil) {
+		t.Fatal("peer not allowed by nil allowlist")
+	}
+
+	a, err := allowlist.New(networkID, allowlist.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Enabled() {
+		t.Fatal("allowlist without rules enabled")
+	}
+	if !a.Allowed(swarm.RandAddress(t), ma.StringCast("/ip4/1.2.3.4/tcp/1634"), nil) {
+		t.Fatal("peer not allowed by allowlist without rules")
+	}
+
+	authority := common.HexToAddress("0x1")
+	a, err = allowlist.New(networkID, allowlist.Options{Authority: &authority})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.Enabled() {
+		t.Fatal("allowlist with authority disabled")
+	}
+}
+
+func TestInvalidUnderlay(t *testing.T) {
+	t.Parallel()
+
+	for _, u := range []string{"", "10.0.0.0/33", "not an ip", "/dns4/example.org/tcp/1634"} {
+		if _, err := allowlist.New(networkID, allowlist.Options{Underlays: []string{u}}); err == nil {
+			t.Errorf("underlay %q: expected error", u)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(key)
+	want, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	overlay := swarm.RandAddress(t)
+	attestation, err := allowlist.Attest(signer, overlay, networkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := allowlist.Verify(attestation, overlay, networkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("got signer %s, want %s", got, want)
+	}
+
+	if _, err := allowlist.Verify(attestation[1:], overlay, networkID); !errors.Is(err, allowlist.ErrInvalidAttestation) {
+		t.Fatalf("got error %v, want %v", err, allowlist.ErrInvalidAttestation)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"sort"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// Capability is an optional protocol feature which is advertised by the
+// node in the handshake. A feature is used with a peer only if both sides
+// advertised it, otherwise the protocols degrade to the baseline behavior
+// which every node of the same handshake version supports. This allows
+// new features to be rolled out gradually without breaking older nodes.
+type Capability string
+
+// Capabilities is a set of protocol features.
+type Capabilities map[Capability]struct{}
+
+// NewCapabilities creates a set of the given features.
+func NewCapabilities(cc ...Capability) Capabilities {
+	s := make(Capabilities, len(cc))
+	for _, c := range cc {
+		s[c] = struct{}{}
+	}
+	return s
+}
+
+// Has reports whether the feature is in the set.
+func (s Capabilities) Has(c Capability) bool {
+	_, ok := s[c]
+	return ok
+}
+
+// Intersect returns the features which are in both sets,
+// that is the features negotiated between two nodes.
+func (s Capabilities) Intersect(o Capabilities) Capabilities {
+	r := make(Capabilities)
+	for c := range s {
+		if o.Has(c) {
+			r[c] = struct{}{}
+		}
+	}
+	return r
+}
+
+// List returns the features of the set in lexicographical order.
+func (s Capabilities) List() []Capability {
+	l := make([]Capability, 0, len(s))
+	for c := range s {
+		l = append(l, c)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+	return l
+}
+
+// CapabilityChecker reports the features negotiated with connected peers.
+// Protocols consult it before using an optional feature with a peer.
+type CapabilityChecker interface {
+	// Supports reports whether the feature was negotiated with the peer.
+	// It returns false for the peers which are not connected.
+	Supports(overlay swarm.Address, c Capability) bool
+}
@@ -0,0 +1,32 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p2p_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/p2p"
+)
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	local := p2p.NewCapabilities("c", "a", "b")
+	remote := p2p.NewCapabilities("b", "d", "a")
+
+	if !local.Has("c") || local.Has("d") {
+		t.Fatal("unexpected set membership")
+	}
+
+	want := []p2p.Capability{"a", "b"}
+	if got := local.Intersect(remote).List(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if got := local.Intersect(p2p.NewCapabilities()).List(); len(got) != 0 {
+		t.Fatalf("got %v, want no capabilities", got)
+	}
+}
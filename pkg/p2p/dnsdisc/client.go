@@ -0,0 +1,190 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dnsdisc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// maxLinkDepth limits how deep the links to other trees are followed.
+const maxLinkDepth = 4
+
+var errEmptyTree = errors.New("dnsdisc: empty tree")
+
+// Resolver looks up the DNS TXT records. It is satisfied by *net.Resolver.
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// Client resolves the peer addresses published in DNS trees.
+type Client struct {
+	resolver Resolver
+}
+
+// NewClient creates a new client which uses the given resolver to
+// look up the records. If the resolver is nil, the system resolver is used.
+func NewClient(resolver Resolver) *Client {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &Client{resolver: resolver}
+}
+
+// Resolve returns all peer addresses of the tree at the given URL in the
+// enrtree://<key>@<domain> form, including the addresses of all linked
+// trees. Every record of the tree is authenticated against the public
+// key from the URL.
+func (c *Client) Resolve(ctx context.Context, url string) ([]ma.Multiaddr, error) {
+	link, err := parseLink(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		addrs   []ma.Multiaddr
+		visited = make(map[string]bool)
+	)
+	if err := c.resolveTree(ctx, link, 0, visited, &addrs); err != nil {
+		return nil, err
+	}
+	return addrs, nil
+}
+
+func (c *Client) resolveTree(ctx context.Context, link *linkEntry, depth int, visited map[string]bool, addrs *[]ma.Multiaddr) error {
+	if visited[link.domain] || depth > maxLinkDepth {
+		return nil
+	}
+	visited[link.domain] = true
+
+	root, err := c.resolveRoot(ctx, link)
+	if err != nil {
+		return fmt.Errorf("tree %s: %w", link.domain, err)
+	}
+
+	var links []*linkEntry
+	err = c.walk(ctx, link.domain, root.eroot, func(e entry) error {
+		if a, ok := e.(*addrEntry); ok {
+			*addrs = append(*addrs, a.addr)
+			return nil
+		}
+		return fmt.Errorf("%w: unexpected record in addresses subtree", ErrInvalidRecord)
+	})
+	if err != nil {
+		return fmt.Errorf("tree %s: %w", link.domain, err)
+	}
+	err = c.walk(ctx, link.domain, root.lroot, func(e entry) error {
+		if l, ok := e.(*linkEntry); ok {
+			links = append(links, l)
+			return nil
+		}
+		return fmt.Errorf("%w: unexpected record in links subtree", ErrInvalidRecord)
+	})
+	if err != nil {
+		return fmt.Errorf("tree %s: %w", link.domain, err)
+	}
+
+	for _, l := range links {
+		if err := c.resolveTree(ctx, l, depth+1, visited, addrs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveRoot looks up the root record of the tree and verifies its signature.
+func (c *Client) resolveRoot(ctx context.Context, link *linkEntry) (*rootEntry, error) {
+	txts, err := c.resolver.LookupTXT(ctx, link.domain)
+	if err != nil {
+		return nil, err
+	}
+	for _, txt := range txts {
+		if !strings.HasPrefix(txt, rootPrefix) {
+			continue
+		}
+		root, err := parseRoot(txt)
+		if err != nil {
+			return nil, err
+		}
+		if !root.verify(link.pubkey) {
+			return nil, ErrInvalidSignature
+		}
+		return root, nil
+	}
+	return nil, ErrNoRecord
+}
+
+// walk visits all leaf records of the subtree with the given root hash.
+func (c *Client) walk(ctx context.Context, domain, h string, visit func(entry) error) error {
+	e, err := c.resolveEntry(ctx, domain, h)
+	if err != nil {
+		return err
+	}
+	b, ok := e.(*branchEntry)
+	if !ok {
+		return visit(e)
+	}
+	for _, child := range b.children {
+		if err := c.walk(ctx, domain, child, visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveEntry looks up the record with the given hash
+// and checks that its content matches the hash.
+func (c *Client) resolveEntry(ctx context.Context, domain, h string) (entry, error) {
+	name := h + "." + domain
+	txts, err := c.resolver.LookupTXT(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, txt := range txts {
+		th, err := hash(txt)
+		if err != nil {
+			return nil, err
+		}
+		if th != h {
+			continue
+		}
+		return parseEntry(txt)
+	}
+	if len(txts) == 0 {
+		return nil, fmt.Errorf("%s: %w", name, ErrNoRecord)
+	}
+	return nil, fmt.Errorf("%s: %w", name, ErrHashMismatch)
+}
+
+// IsURL reports whether the given string is a tree URL.
+func IsURL(s string) bool {
+	return strings.HasPrefix(s, linkPrefix)
+}
+
+// ResolveAll resolves all given tree URLs and returns the union of their
+// addresses. It fails only if none of the trees could be resolved.
+func (c *Client) ResolveAll(ctx context.Context, urls []string) ([]ma.Multiaddr, error) {
+	var (
+		addrs   []ma.Multiaddr
+		lastErr = errEmptyTree
+	)
+	for _, url := range urls {
+		a, err := c.Resolve(ctx, url)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		addrs = append(addrs, a...)
+	}
+	if len(addrs) == 0 {
+		return nil, lastErr
+	}
+	return addrs, nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dnsdisc implements EIP-1459 style discovery of peers through
+// signed trees of DNS TXT records.
+//
+// The tree is made of a signed root record, placed at the tree domain, which
+// references two subtrees by their hashes: one with the peer underlay
+// multiaddresses and one with links to other trees. Every other record is
+// placed at the subdomain named by its hash, so the whole tree is
+// authenticated by the single root signature. Unlike EIP-1459, whose leaves
+// are Ethereum Node Records, the leaves are bzz multiaddresses and the root
+// is signed in the same way as other bee signatures (EIP-191).
+package dnsdisc
+
+import (
+	"crypto/ecdsa"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/ethersphere/bee/pkg/crypto"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+const (
+	rootPrefix   = "enrtree-root:v1"
+	branchPrefix = "enrtree-branch:"
+	linkPrefix   = "enrtree://"
+	addrPrefix   = "bzz:"
+
+	// hashLength is the number of bytes of the record hash used as a subdomain.
+	hashLength = 16
+)
+
+var (
+	// ErrInvalidRecord is returned when a TXT record can not be parsed.
+	ErrInvalidRecord = errors.New("dnsdisc: invalid record")
+	// ErrInvalidSignature is returned when the root record signature
+	// does not match the public key of the tree.
+	ErrInvalidSignature = errors.New("dnsdisc: invalid signature")
+	// ErrHashMismatch is returned when the record content does not match
+	// the hash it was looked up by.
+	ErrHashMismatch = errors.New("dnsdisc: record hash mismatch")
+	// ErrNoRecord is returned when there is no tree record at the domain.
+	ErrNoRecord = errors.New("dnsdisc: no tree record found")
+)
+
+var b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// entry is a single record of the tree.
+type entry interface {
+	fmt.Stringer
+}
+
+type (
+	rootEntry struct {
+		eroot string
+		lroot string
+		seq   uint
+		sig   []byte
+	}
+	branchEntry struct {
+		children []string
+	}
+	linkEntry struct {
+		str    string
+		domain string
+		pubkey *ecdsa.PublicKey
+	}
+	addrEntry struct {
+		addr ma.Multiaddr
+	}
+)
+
+func (e *rootEntry) signedText() string {
+	return fmt.Sprintf("%s e=%s l=%s seq=%d", rootPrefix, e.eroot, e.lroot, e.seq)
+}
+
+func (e *rootEntry) String() string {
+	return e.signedText() + " sig=" + base64.RawURLEncoding.EncodeToString(e.sig)
+}
+
+// verify checks that the root record was signed by the given public key.
+func (e *rootEntry) verify(pubkey *ecdsa.PublicKey) bool {
+	recovered, err := crypto.Recover(e.sig, []byte(e.signedText()))
+	if err != nil {
+		return false
+	}
+	return recovered.X.Cmp(pubkey.X) == 0 && recovered.Y.Cmp(pubkey.Y) == 0
+}
+
+func (e *branchEntry) String() string {
+	return branchPrefix + strings.Join(e.children, ",")
+}
+
+func (e *linkEntry) String() string {
+	return e.str
+}
+
+func (e *addrEntry) String() string {
+	return addrPrefix + e.addr.String()
+}
+
+// hash returns the subdomain name of the record with the given content.
+func hash(s string) (string, error) {
+	h, err := crypto.LegacyKeccak256([]byte(s))
+	if err != nil {
+		return "", err
+	}
+	return b32.EncodeToString(h[:hashLength]), nil
+}
+
+// newLinkEntry returns the link to the tree at the given
+// domain which is signed by the given public key.
+func newLinkEntry(domain string, pubkey *ecdsa.PublicKey) *linkEntry {
+	key := b32.EncodeToString(crypto.EncodeSecp256k1PublicKey(pubkey))
+	return &linkEntry{
+		str:    linkPrefix + key + "@" + domain,
+		domain: domain,
+		pubkey: pubkey,
+	}
+}
+
+// ParseURL parses the tree URL in the enrtree://<key>@<domain> form
+// and returns the tree domain and its public key.
+func ParseURL(url string) (domain string, pubkey *ecdsa.PublicKey, err error) {
+	e, err := parseLink(url)
+	if err != nil {
+		return "", nil, err
+	}
+	return e.domain, e.pubkey, nil
+}
+
+func parseLink(s string) (*linkEntry, error) {
+	if !strings.HasPrefix(s, linkPrefix) {
+		return nil, fmt.Errorf("%w: missing %s prefix", ErrInvalidRecord, linkPrefix)
+	}
+	key, domain, ok := strings.Cut(strings.TrimPrefix(s, linkPrefix), "@")
+	if !ok || domain == "" {
+		return nil, fmt.Errorf("%w: missing domain in link", ErrInvalidRecord)
+	}
+	keyBytes, err := b32.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid public key encoding: %v", ErrInvalidRecord, err)
+	}
+	pubkey, err := btcec.ParsePubKey(keyBytes, btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid public key: %v", ErrInvalidRecord, err)
+	}
+	return &linkEntry{
+		str:    s,
+		domain: domain,
+		pubkey: (*ecdsa.PublicKey)(pubkey),
+	}, nil
+}
+
+func parseRoot(s string) (*rootEntry, error) {
+	var (
+		e   rootEntry
+		sig string
+	)
+	if _, err := fmt.Sscanf(s, rootPrefix+" e=%s l=%s seq=%d sig=%s", &e.eroot, &e.lroot, &e.seq, &sig); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+	}
+	if !isHash(e.eroot) || !isHash(e.lroot) {
+		return nil, fmt.Errorf("%w: invalid subtree root hash", ErrInvalidRecord)
+	}
+	b, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || len(b) != 65 {
+		return nil, fmt.Errorf("%w: invalid signature encoding", ErrInvalidRecord)
+	}
+	e.sig = b
+	return &e, nil
+}
+
+// parseEntry parses a non-root tree record.
+func parseEntry(s string) (entry, error) {
+	switch {
+	case strings.HasPrefix(s, branchPrefix):
+		var children []string
+		if v := strings.TrimPrefix(s, branchPrefix); v != "" {
+			children = strings.Split(v, ",")
+		}
+		for _, c := range children {
+			if !isHash(c) {
+				return nil, fmt.Errorf("%w: invalid branch child %q", ErrInvalidRecord, c)
+			}
+		}
+		return &branchEntry{children: children}, nil
+	case strings.HasPrefix(s, linkPrefix):
+		return parseLink(s)
+	case strings.HasPrefix(s, addrPrefix):
+		addr, err := ma.NewMultiaddr(strings.TrimPrefix(s, addrPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+		}
+		return &addrEntry{addr: addr}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown record type", ErrInvalidRecord)
+}
+
+func isHash(s string) bool {
+	b, err := b32.DecodeString(s)
+	return err == nil && len(b) == hashLength
+}
@@ -0,0 +1,188 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dnsdisc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/p2p/dnsdisc"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+type mapResolver map[string]string
+
+func (r mapResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	if txt, ok := r[name]; ok {
+		return []string{txt}, nil
+	}
+	return nil, nil
+}
+
+func (r mapResolver) add(t *testing.T, records map[string]string) {
+	t.Helper()
+	for k, v := range records {
+		r[k] = v
+	}
+}
+
+func newSigner(t *testing.T) crypto.Signer {
+	t.Helper()
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crypto.NewDefaultSigner(pk)
+}
+
+func makeAddrs(t *testing.T, n, offset int) []ma.Multiaddr {
+	t.Helper()
+	addrs := make([]ma.Multiaddr, 0, n)
+	for i := 0; i < n; i++ {
+		a, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/10.0.%d.%d/tcp/1634", offset, i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrs = append(addrs, a)
+	}
+	return addrs
+}
+
+func publish(t *testing.T, r mapResolver, domain string, addrs []ma.Multiaddr, links []string) string {
+	t.Helper()
+	tree, err := dnsdisc.MakeTree(1, addrs, links)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url, err := tree.Sign(newSigner(t), domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := tree.Records(domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.add(t, records)
+	return url
+}
+
+func addrStrings(addrs []ma.Multiaddr) []string {
+	s := make([]string, 0, len(addrs))
+	for _, a := range addrs {
+		s = append(s, a.String())
+	}
+	sort.Strings(s)
+	return s
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	r := make(mapResolver)
+
+	linkedAddrs := makeAddrs(t, 3, 1)
+	linkedURL := publish(t, r, "linked.example.org", linkedAddrs, nil)
+
+	// more addresses than fit in a single branch
+	addrs := makeAddrs(t, 40, 0)
+	url := publish(t, r, "nodes.example.org", addrs, []string{linkedURL})
+
+	got, err := dnsdisc.NewClient(r).Resolve(context.Background(), url)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := addrStrings(append(addrs, linkedAddrs...))
+	if have := addrStrings(got); strings.Join(have, ",") != strings.Join(want, ",") {
+		t.Fatalf("addresses mismatch:\nhave %v\nwant %v", have, want)
+	}
+}
+
+func TestResolveInvalid(t *testing.T) {
+	t.Parallel()
+
+	t.Run("wrong key", func(t *testing.T) {
+		t.Parallel()
+
+		r := make(mapResolver)
+		publish(t, r, "nodes.example.org", makeAddrs(t, 2, 0), nil)
+		other := publish(t, make(mapResolver), "nodes.example.org", nil, nil)
+
+		_, err := dnsdisc.NewClient(r).Resolve(context.Background(), other)
+		if !errors.Is(err, dnsdisc.ErrInvalidSignature) {
+			t.Fatalf("want error %v, got %v", dnsdisc.ErrInvalidSignature, err)
+		}
+	})
+
+	t.Run("tampered record", func(t *testing.T) {
+		t.Parallel()
+
+		r := make(mapResolver)
+		url := publish(t, r, "nodes.example.org", makeAddrs(t, 2, 0), nil)
+		for k, v := range r {
+			if strings.HasPrefix(v, "bzz:") {
+				r[k] = "bzz:/ip4/1.1.1.1/tcp/1634"
+				break
+			}
+		}
+
+		_, err := dnsdisc.NewClient(r).Resolve(context.Background(), url)
+		if !errors.Is(err, dnsdisc.ErrHashMismatch) {
+			t.Fatalf("want error %v, got %v", dnsdisc.ErrHashMismatch, err)
+		}
+	})
+
+	t.Run("missing tree", func(t *testing.T) {
+		t.Parallel()
+
+		url := publish(t, make(mapResolver), "nodes.example.org", nil, nil)
+
+		_, err := dnsdisc.NewClient(make(mapResolver)).Resolve(context.Background(), url)
+		if !errors.Is(err, dnsdisc.ErrNoRecord) {
+			t.Fatalf("want error %v, got %v", dnsdisc.ErrNoRecord, err)
+		}
+	})
+}
+
+func TestParseURL(t *testing.T) {
+	t.Parallel()
+
+	signer := newSigner(t)
+	tree, err := dnsdisc.MakeTree(1, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url, err := tree.Sign(signer, "nodes.example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	domain, pubkey, err := dnsdisc.ParseURL(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if domain != "nodes.example.org" {
+		t.Fatalf("domain mismatch: have %q want %q", domain, "nodes.example.org")
+	}
+	want, _ := signer.PublicKey()
+	if !want.Equal(pubkey) {
+		t.Fatal("public key mismatch")
+	}
+
+	for _, invalid := range []string{
+		"nodes.example.org",
+		"enrtree://nodes.example.org",
+		"enrtree://AAAA@nodes.example.org",
+	} {
+		if _, _, err := dnsdisc.ParseURL(invalid); !errors.Is(err, dnsdisc.ErrInvalidRecord) {
+			t.Fatalf("url %q: want error %v, got %v", invalid, dnsdisc.ErrInvalidRecord, err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dnsdisc
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// maxChildren is the maximum number of hashes in a branch record, which
+// keeps the record within the size of a single TXT string.
+const maxChildren = 13
+
+// Tree is a signable tree of DNS TXT records which can be published
+// by the network operators to distribute the bootstrap peers.
+type Tree struct {
+	root    *rootEntry
+	entries map[string]entry
+}
+
+// MakeTree creates a tree with the given sequence number, peer underlay
+// addresses and links to other trees in the enrtree://<key>@<domain> form.
+// The tree must be signed before its records are published.
+func MakeTree(seq uint, addrs []ma.Multiaddr, links []string) (*Tree, error) {
+	t := &Tree{
+		entries: make(map[string]entry),
+	}
+
+	addrEntries := make([]entry, 0, len(addrs))
+	for _, a := range addrs {
+		addrEntries = append(addrEntries, &addrEntry{addr: a})
+	}
+
+	linkEntries := make([]entry, 0, len(links))
+	for _, l := range links {
+		e, err := parseLink(l)
+		if err != nil {
+			return nil, fmt.Errorf("link %q: %w", l, err)
+		}
+		linkEntries = append(linkEntries, e)
+	}
+
+	eroot, err := t.build(addrEntries)
+	if err != nil {
+		return nil, err
+	}
+	lroot, err := t.build(linkEntries)
+	if err != nil {
+		return nil, err
+	}
+
+	t.root = &rootEntry{
+		eroot: eroot,
+		lroot: lroot,
+		seq:   seq,
+	}
+
+	return t, nil
+}
+
+// build adds the entries to the tree and returns
+// the hash of the root record of the subtree.
+func (t *Tree) build(entries []entry) (string, error) {
+	hashes := make([]string, 0, len(entries))
+	for _, e := range entries {
+		h, err := t.add(e)
+		if err != nil {
+			return "", err
+		}
+		hashes = append(hashes, h)
+	}
+	sort.Strings(hashes)
+
+	for len(hashes) > maxChildren {
+		var parents []string
+		for i := 0; i < len(hashes); i += maxChildren {
+			end := i + maxChildren
+			if end > len(hashes) {
+				end = len(hashes)
+			}
+			h, err := t.add(&branchEntry{children: hashes[i:end]})
+			if err != nil {
+				return "", err
+			}
+			parents = append(parents, h)
+		}
+		hashes = parents
+	}
+
+	return t.add(&branchEntry{children: hashes})
+}
+
+func (t *Tree) add(e entry) (string, error) {
+	h, err := hash(e.String())
+	if err != nil {
+		return "", err
+	}
+	t.entries[h] = e
+	return h, nil
+}
+
+// Seq returns the sequence number of the tree.
+func (t *Tree) Seq() uint {
+	return t.root.seq
+}
+
+// Sign signs the tree root with the given signer and returns the
+// URL of the tree at the given domain which clients should be
+// configured with.
+func (t *Tree) Sign(signer crypto.Signer, domain string) (url string, err error) {
+	sig, err := signer.Sign([]byte(t.root.signedText()))
+	if err != nil {
+		return "", fmt.Errorf("sign tree root: %w", err)
+	}
+	pubkey, err := signer.PublicKey()
+	if err != nil {
+		return "", err
+	}
+	t.root.sig = sig
+	return newLinkEntry(domain, pubkey).String(), nil
+}
+
+// Records returns the TXT records of the signed tree mapped by the
+// fully qualified domain names under the given tree domain.
+func (t *Tree) Records(domain string) (map[string]string, error) {
+	if t.root.sig == nil {
+		return nil, errors.New("dnsdisc: tree is not signed")
+	}
+
+	records := make(map[string]string, len(t.entries)+1)
+	records[domain] = t.root.String()
+	for h, e := range t.entries {
+		records[h+"."+domain] = e.String()
+	}
+	return records, nil
+}
@@ -20,6 +20,8 @@ var (
 	ErrDialLightNode = errors.New("target peer is a light node")
 	// ErrPeerBlocklisted is returned if peer is on blocklist
 	ErrPeerBlocklisted = errors.New("peer blocklisted")
+	// ErrPeerNotAllowed is returned if the peer is not on the allowlist
+	ErrPeerNotAllowed = errors.New("peer not allowed")
 )
 
 const (
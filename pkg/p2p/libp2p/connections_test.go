@@ -18,6 +18,7 @@ import (
 	"github.com/ethersphere/bee/pkg/addressbook"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/allowlist"
 	"github.com/ethersphere/bee/pkg/p2p/libp2p"
 	"github.com/ethersphere/bee/pkg/p2p/libp2p/internal/handshake"
 	"github.com/ethersphere/bee/pkg/spinlock"
@@ -84,6 +85,58 @@ func TestConnectDisconnect(t *testing.T) {
 	expectPeersEventually(t, s1)
 }
 
+func TestConnectNegotiatesCapabilities(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const (
+		shared  = p2p.Capability("shared")
+		s1Only  = p2p.Capability("s1-only")
+		unknown = p2p.Capability("unknown")
+	)
+
+	s1, overlay1 := newService(t, 1, libp2pServiceOpts{libp2pOpts: libp2p.Options{
+		FullNode:     true,
+		Capabilities: []p2p.Capability{shared, s1Only},
+	}})
+	s2, overlay2 := newService(t, 1, libp2pServiceOpts{libp2pOpts: libp2p.Options{
+		Capabilities: []p2p.Capability{shared},
+	}})
+
+	addr := serviceUnderlayAddress(t, s1)
+
+	if _, err := s2.Connect(ctx, addr); err != nil {
+		t.Fatal(err)
+	}
+
+	expectPeers(t, s2, overlay1)
+	expectPeersEventually(t, s1, overlay2)
+
+	for _, tc := range []struct {
+		s       *libp2p.Service
+		overlay swarm.Address
+	}{
+		{s1, overlay2},
+		{s2, overlay1},
+	} {
+		if !tc.s.Supports(tc.overlay, shared) {
+			t.Errorf("capability %q not negotiated", shared)
+		}
+		if tc.s.Supports(tc.overlay, s1Only) {
+			t.Errorf("capability %q negotiated", s1Only)
+		}
+		if tc.s.Supports(tc.overlay, unknown) {
+			t.Errorf("capability %q negotiated", unknown)
+		}
+	}
+
+	if s2.Supports(swarm.RandAddress(t), shared) {
+		t.Error("capability negotiated with not connected peer")
+	}
+}
+
 func TestConnectToLightPeer(t *testing.T) {
 	t.Parallel()
 
@@ -598,6 +651,92 @@ func TestBlocklisting(t *testing.T) {
 	expectPeers(t, s2)
 }
 
+func TestAllowlisting(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s2, overlay2 := newService(t, 1, libp2pServiceOpts{libp2pOpts: libp2p.Options{
+		FullNode: true,
+	}})
+	s3, _ := newService(t, 1, libp2pServiceOpts{libp2pOpts: libp2p.Options{
+		FullNode: true,
+	}})
+
+	overlays, err := allowlist.New(1, allowlist.Options{Overlays: []swarm.Address{overlay2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s1, overlay1 := newService(t, 1, libp2pServiceOpts{libp2pOpts: libp2p.Options{
+		FullNode:  true,
+		Allowlist: overlays,
+	}})
+
+	addr1 := serviceUnderlayAddress(t, s1)
+	addr2 := serviceUnderlayAddress(t, s2)
+	addr3 := serviceUnderlayAddress(t, s3)
+
+	if _, err := s1.Connect(ctx, addr2); err != nil {
+		t.Fatal(err)
+	}
+
+	expectPeers(t, s1, overlay2)
+	expectPeersEventually(t, s2, overlay1)
+
+	if _, err := s1.Connect(ctx, addr3); !errors.Is(err, p2p.ErrPeerNotAllowed) {
+		t.Fatalf("got error %v, want %v", err, p2p.ErrPeerNotAllowed)
+	}
+
+	expectPeers(t, s1, overlay2)
+	expectPeersEventually(t, s3)
+
+	// the incoming connections of the peers not in the allowlist are closed
+	_, _ = s3.Connect(ctx, addr1)
+
+	expectPeersEventually(t, s3)
+	expectPeersEventually(t, s1, overlay2)
+
+	for _, tc := range []struct {
+		name      string
+		underlays []string
+		allowed   bool
+	}{
+		{
+			name:      "peer address",
+			underlays: []string{addr3.String()},
+			allowed:   true,
+		},
+		{
+			name:      "other network",
+			underlays: []string{"10.0.0.0/8"},
+			allowed:   false,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			underlays, err := allowlist.New(1, allowlist.Options{Underlays: tc.underlays})
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, _ := newService(t, 1, libp2pServiceOpts{libp2pOpts: libp2p.Options{
+				FullNode:  true,
+				Allowlist: underlays,
+			}})
+
+			_, err = s.Connect(context.Background(), addr3)
+			switch {
+			case tc.allowed && err != nil:
+				t.Fatal(err)
+			case !tc.allowed && !errors.Is(err, p2p.ErrPeerNotAllowed):
+				t.Fatalf("got error %v, want %v", err, p2p.ErrPeerNotAllowed)
+			}
+		})
+	}
+}
+
 func TestTopologyNotifier(t *testing.T) {
 	t.Parallel()
 
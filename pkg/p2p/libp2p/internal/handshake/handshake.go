@@ -74,12 +74,16 @@ type Service struct {
 	libp2pID              libp2ppeer.ID
 	metrics               metrics
 	picker                p2p.Picker
+	capabilities          p2p.Capabilities
+	attestation           []byte
 }
 
 // Info contains the information received from the handshake.
 type Info struct {
-	BzzAddress *bzz.Address
-	FullNode   bool
+	BzzAddress   *bzz.Address
+	FullNode     bool
+	Capabilities p2p.Capabilities // features supported by both sides
+	Attestation  []byte           // authority signature of the overlay, if advertised
 }
 
 func (i *Info) LightString() string {
@@ -107,6 +111,7 @@ func New(signer crypto.Signer, advertisableAddresser AdvertisableAddressResolver
 		libp2pID:              ownPeerID,
 		logger:                logger.WithName(loggerName).Register(),
 		metrics:               newMetrics(),
+		capabilities:          p2p.NewCapabilities(),
 	}
 	svc.welcomeMessage.Store(welcomeMessage)
 
@@ -117,6 +122,19 @@ func (s *Service) SetPicker(n p2p.Picker) {
 	s.picker = n
 }
 
+// SetCapabilities sets the optional protocol features advertised to the
+// peers. It must be called before the service handles any handshake.
+func (s *Service) SetCapabilities(cc ...p2p.Capability) {
+	s.capabilities = p2p.NewCapabilities(cc...)
+}
+
+// SetAttestation sets the authority attestation of the overlay address
+// advertised to the peers. It must be called before the service handles
+// any handshake.
+func (s *Service) SetAttestation(attestation []byte) {
+	s.attestation = attestation
+}
+
 // Handshake initiates a handshake with a peer.
 func (s *Service) Handshake(ctx context.Context, stream p2p.Stream, peerMultiaddr ma.Multiaddr, peerID libp2ppeer.ID) (i *Info, err error) {
 	loggerV1 := s.logger.V(1).Register()
@@ -196,6 +214,8 @@ func (s *Service) Handshake(ctx context.Context, stream p2p.Stream, peerMultiadd
 		NetworkID:      s.networkID,
 		FullNode:       s.fullNode,
 		Nonce:          s.nonce,
+		Capabilities:   s.advertisedCapabilities(),
+		Attestation:    s.attestation,
 		WelcomeMessage: welcomeMessage,
 	}
 
@@ -209,8 +229,10 @@ func (s *Service) Handshake(ctx context.Context, stream p2p.Stream, peerMultiadd
 	}
 
 	return &Info{
-		BzzAddress: remoteBzzAddress,
-		FullNode:   resp.Ack.FullNode,
+		BzzAddress:   remoteBzzAddress,
+		FullNode:     resp.Ack.FullNode,
+		Capabilities: s.negotiate(resp.Ack.Capabilities),
+		Attestation:  resp.Ack.Attestation,
 	}, nil
 }
 
@@ -274,6 +296,8 @@ func (s *Service) Handle(ctx context.Context, stream p2p.Stream, remoteMultiaddr
 			NetworkID:      s.networkID,
 			FullNode:       s.fullNode,
 			Nonce:          s.nonce,
+			Capabilities:   s.advertisedCapabilities(),
+			Attestation:    s.attestation,
 			WelcomeMessage: welcomeMessage,
 		},
 	}); err != nil {
@@ -312,8 +336,10 @@ func (s *Service) Handle(ctx context.Context, stream p2p.Stream, remoteMultiaddr
 	}
 
 	return &Info{
-		BzzAddress: remoteBzzAddress,
-		FullNode:   ack.FullNode,
+		BzzAddress:   remoteBzzAddress,
+		FullNode:     ack.FullNode,
+		Capabilities: s.negotiate(ack.Capabilities),
+		Attestation:  ack.Attestation,
 	}, nil
 }
 
@@ -343,3 +369,27 @@ func (s *Service) parseCheckAck(ack *pb.Ack) (*bzz.Address, error) {
 
 	return bzzAddress, nil
 }
+
+// advertisedCapabilities returns the local features in the wire format.
+func (s *Service) advertisedCapabilities() []string {
+	l := s.capabilities.List()
+	if len(l) == 0 {
+		return nil
+	}
+	cc := make([]string, len(l))
+	for i, c := range l {
+		cc[i] = string(c)
+	}
+	return cc
+}
+
+// negotiate returns the features supported by both the node and the peer.
+// The features unknown to the node are ignored, as are all the features
+// when the peer runs a version which does not advertise any.
+func (s *Service) negotiate(remote []string) p2p.Capabilities {
+	cc := make([]p2p.Capability, len(remote))
+	for i, c := range remote {
+		cc[i] = p2p.Capability(c)
+	}
+	return s.capabilities.Intersect(p2p.NewCapabilities(cc...))
+}
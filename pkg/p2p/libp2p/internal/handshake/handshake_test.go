@@ -175,6 +175,114 @@ func TestHandshake(t *testing.T) {
 		}
 	})
 
+	t.Run("Handshake - capabilities", func(t *testing.T) {
+		handshakeService, err := handshake.New(signer1, aaddresser, node1Info.BzzAddress.Overlay, networkID, true, nonce, "", true, node1AddrInfo.ID, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handshakeService.SetCapabilities("b", "a")
+
+		var buffer1 bytes.Buffer
+		var buffer2 bytes.Buffer
+		stream1 := mock.NewStream(&buffer1, &buffer2)
+		stream2 := mock.NewStream(&buffer2, &buffer1)
+
+		w, r := protobuf.NewWriterAndReader(stream2)
+		if err := w.WriteMsg(&pb.SynAck{
+			Syn: &pb.Syn{
+				ObservedUnderlay: node1maBinary,
+			},
+			Ack: &pb.Ack{
+				Address: &pb.BzzAddress{
+					Underlay:  node2maBinary,
+					Overlay:   node2BzzAddress.Overlay.Bytes(),
+					Signature: node2BzzAddress.Signature,
+				},
+				NetworkID:    networkID,
+				FullNode:     true,
+				Nonce:        nonce,
+				Capabilities: []string{"a", "c"},
+			},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := handshakeService.Handshake(context.Background(), stream1, node2AddrInfo.Addrs[0], node2AddrInfo.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := res.Capabilities.List(); len(got) != 1 || got[0] != "a" {
+			t.Fatalf("got negotiated capabilities %v, want [a]", got)
+		}
+
+		var syn pb.Syn
+		if err := r.ReadMsg(&syn); err != nil {
+			t.Fatal(err)
+		}
+		var ack pb.Ack
+		if err := r.ReadMsg(&ack); err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(ack.Capabilities) != "[a b]" {
+			t.Fatalf("got advertised capabilities %v, want [a b]", ack.Capabilities)
+		}
+	})
+
+	t.Run("Handshake - attestation", func(t *testing.T) {
+		handshakeService, err := handshake.New(signer1, aaddresser, node1Info.BzzAddress.Overlay, networkID, true, nonce, "", true, node1AddrInfo.ID, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handshakeService.SetAttestation([]byte("attestation1"))
+
+		var buffer1 bytes.Buffer
+		var buffer2 bytes.Buffer
+		stream1 := mock.NewStream(&buffer1, &buffer2)
+		stream2 := mock.NewStream(&buffer2, &buffer1)
+
+		w, r := protobuf.NewWriterAndReader(stream2)
+		if err := w.WriteMsg(&pb.SynAck{
+			Syn: &pb.Syn{
+				ObservedUnderlay: node1maBinary,
+			},
+			Ack: &pb.Ack{
+				Address: &pb.BzzAddress{
+					Underlay:  node2maBinary,
+					Overlay:   node2BzzAddress.Overlay.Bytes(),
+					Signature: node2BzzAddress.Signature,
+				},
+				NetworkID:   networkID,
+				FullNode:    true,
+				Nonce:       nonce,
+				Attestation: []byte("attestation2"),
+			},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := handshakeService.Handshake(context.Background(), stream1, node2AddrInfo.Addrs[0], node2AddrInfo.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(res.Attestation) != "attestation2" {
+			t.Fatalf("got attestation %q, want %q", res.Attestation, "attestation2")
+		}
+
+		var syn pb.Syn
+		if err := r.ReadMsg(&syn); err != nil {
+			t.Fatal(err)
+		}
+		var ack pb.Ack
+		if err := r.ReadMsg(&ack); err != nil {
+			t.Fatal(err)
+		}
+		if string(ack.Attestation) != "attestation1" {
+			t.Fatalf("got advertised attestation %q, want %q", ack.Attestation, "attestation1")
+		}
+	})
+
 	t.Run("Handshake - picker error", func(t *testing.T) {
 		handshakeService, err := handshake.New(signer1, aaddresser, node1Info.BzzAddress.Overlay, networkID, true, nonce, "", true, node1AddrInfo.ID, logger)
 		if err != nil {
@@ -71,6 +71,8 @@ type Ack struct {
 	NetworkID      uint64      `protobuf:"varint,2,opt,name=NetworkID,proto3" json:"NetworkID,omitempty"`
 	FullNode       bool        `protobuf:"varint,3,opt,name=FullNode,proto3" json:"FullNode,omitempty"`
 	Nonce          []byte      `protobuf:"bytes,4,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
+	Capabilities   []string    `protobuf:"bytes,5,rep,name=Capabilities,proto3" json:"Capabilities,omitempty"`
+	Attestation    []byte      `protobuf:"bytes,6,opt,name=Attestation,proto3" json:"Attestation,omitempty"`
 	WelcomeMessage string      `protobuf:"bytes,99,opt,name=WelcomeMessage,proto3" json:"WelcomeMessage,omitempty"`
 }
 
@@ -135,6 +137,20 @@ func (m *Ack) GetNonce() []byte {
 	return nil
 }
 
+func (m *Ack) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+func (m *Ack) GetAttestation() []byte {
+	if m != nil {
+		return m.Attestation
+	}
+	return nil
+}
+
 func (m *Ack) GetWelcomeMessage() string {
 	if m != nil {
 		return m.WelcomeMessage
@@ -264,27 +280,30 @@ func init() {
 func init() { proto.RegisterFile("handshake.proto", fileDescriptor_a77305914d5d202f) }
 
 var fileDescriptor_a77305914d5d202f = []byte{
-	// 318 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x91, 0xcd, 0x4a, 0xc3, 0x40,
-	0x14, 0x85, 0x3b, 0x4d, 0xed, 0xcf, 0xb5, 0x54, 0x19, 0x14, 0x82, 0x94, 0x10, 0xb2, 0x90, 0xe0,
-	0xa2, 0xa2, 0x3e, 0x41, 0x8b, 0x08, 0x82, 0xb6, 0x30, 0x41, 0x04, 0x57, 0xa6, 0x99, 0x4b, 0x2b,
-	0x89, 0x33, 0x65, 0xa6, 0xad, 0xa4, 0x4f, 0xe1, 0x93, 0xf8, 0x1c, 0x2e, 0xbb, 0x74, 0x29, 0xed,
-	0x8b, 0x48, 0xa6, 0x3f, 0xd1, 0xba, 0x3c, 0xe7, 0x9e, 0x99, 0xf9, 0xce, 0x1d, 0x38, 0x18, 0x86,
-	0x82, 0xeb, 0x61, 0x18, 0x63, 0x6b, 0xa4, 0xe4, 0x58, 0xd2, 0xda, 0xd6, 0xf0, 0x2e, 0xc0, 0x0a,
-	0x52, 0x41, 0xcf, 0xe0, 0xb0, 0xd7, 0xd7, 0xa8, 0xa6, 0xc8, 0x1f, 0x04, 0x47, 0x95, 0x84, 0xa9,
-	0x4d, 0x5c, 0xe2, 0xd7, 0xd9, 0x3f, 0xdf, 0xfb, 0x20, 0x60, 0xb5, 0xa3, 0x98, 0x9e, 0x43, 0xa5,
-	0xcd, 0xb9, 0x42, 0xad, 0x4d, 0x74, 0xff, 0xf2, 0xb8, 0x95, 0x3f, 0xd4, 0x99, 0xcd, 0xd6, 0x43,
-	0xb6, 0x49, 0xd1, 0x26, 0xd4, 0xba, 0x38, 0x7e, 0x93, 0x2a, 0xbe, 0xbd, 0xb6, 0x8b, 0x2e, 0xf1,
-	0x4b, 0x2c, 0x37, 0xe8, 0x09, 0x54, 0x6f, 0x26, 0x49, 0xd2, 0x95, 0x1c, 0x6d, 0xcb, 0x25, 0x7e,
-	0x95, 0x6d, 0x35, 0x3d, 0x82, 0xbd, 0xae, 0x14, 0x11, 0xda, 0x25, 0xc3, 0xb4, 0x12, 0xf4, 0x14,
-	0x1a, 0x8f, 0x98, 0x44, 0xf2, 0x15, 0xef, 0x51, 0xeb, 0x70, 0x80, 0x76, 0xe4, 0x12, 0xbf, 0xc6,
-	0x76, 0x5c, 0xef, 0x0e, 0xca, 0x41, 0x2a, 0x32, 0x64, 0xd7, 0xb4, 0x5d, 0xe3, 0x36, 0x7e, 0xe1,
-	0x06, 0xa9, 0x60, 0x66, 0x11, 0xae, 0xe9, 0x66, 0xe8, 0xfe, 0x26, 0xda, 0x51, 0xcc, 0xb2, 0x91,
-	0xf7, 0x0c, 0x90, 0x97, 0xcb, 0xa8, 0x77, 0x16, 0xb6, 0xd5, 0x59, 0xdf, 0xe0, 0x65, 0x20, 0xc2,
-	0xf1, 0x44, 0xa1, 0xb9, 0xb1, 0xce, 0x72, 0x83, 0xda, 0x50, 0xe9, 0x4d, 0x57, 0x07, 0x2d, 0x33,
-	0xdb, 0xc8, 0x4e, 0xf3, 0x73, 0xe1, 0x90, 0xf9, 0xc2, 0x21, 0xdf, 0x0b, 0x87, 0xbc, 0x2f, 0x9d,
-	0xc2, 0x7c, 0xe9, 0x14, 0xbe, 0x96, 0x4e, 0xe1, 0xa9, 0x38, 0xea, 0xf7, 0xcb, 0xe6, 0x0f, 0xaf,
-	0x7e, 0x02, 0x00, 0x00, 0xff, 0xff, 0x87, 0xa7, 0x49, 0x00, 0xd6, 0x01, 0x00, 0x00,
+	// 356 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x92, 0x4d, 0x6a, 0xe3, 0x30,
+	0x1c, 0xc5, 0xa3, 0x38, 0x5f, 0x56, 0x42, 0x66, 0x10, 0x33, 0x20, 0x86, 0x60, 0x84, 0x17, 0x83,
+	0x99, 0x45, 0x86, 0xb6, 0x27, 0x70, 0x5a, 0x0a, 0x85, 0x36, 0x01, 0x99, 0x52, 0xe8, 0xaa, 0xb2,
+	0x2d, 0x12, 0x63, 0x57, 0x0e, 0x96, 0x92, 0xe2, 0x9c, 0xa2, 0xc7, 0xea, 0x32, 0xcb, 0x2e, 0x4b,
+	0x72, 0x85, 0x1e, 0xa0, 0x58, 0xf9, 0x70, 0x92, 0x2e, 0xdf, 0xef, 0xff, 0xfe, 0x92, 0xde, 0x43,
+	0xf0, 0xc7, 0x84, 0x89, 0x50, 0x4e, 0x58, 0xcc, 0xfb, 0xd3, 0x2c, 0x55, 0x29, 0x32, 0xf7, 0xc0,
+	0x3e, 0x83, 0x86, 0x97, 0x0b, 0xf4, 0x0f, 0xfe, 0x1c, 0xf9, 0x92, 0x67, 0x73, 0x1e, 0xde, 0x8b,
+	0x90, 0x67, 0x09, 0xcb, 0x31, 0x20, 0xc0, 0xe9, 0xd0, 0x6f, 0xdc, 0xfe, 0x04, 0xd0, 0x70, 0x83,
+	0x18, 0xfd, 0x87, 0x4d, 0x37, 0x0c, 0x33, 0x2e, 0xa5, 0xb6, 0xb6, 0xcf, 0x7f, 0xf7, 0xcb, 0x8b,
+	0x06, 0x8b, 0xc5, 0x76, 0x48, 0x77, 0x2e, 0xd4, 0x83, 0xe6, 0x90, 0xab, 0x97, 0x34, 0x8b, 0x6f,
+	0xae, 0x70, 0x95, 0x00, 0xa7, 0x46, 0x4b, 0x80, 0xfe, 0xc0, 0xd6, 0xf5, 0x2c, 0x49, 0x86, 0x69,
+	0xc8, 0xb1, 0x41, 0x80, 0xd3, 0xa2, 0x7b, 0x8d, 0x7e, 0xc1, 0xfa, 0x30, 0x15, 0x01, 0xc7, 0x35,
+	0xfd, 0xa6, 0x8d, 0x40, 0x36, 0xec, 0x5c, 0xb2, 0x29, 0xf3, 0xa3, 0x24, 0x52, 0x11, 0x97, 0xb8,
+	0x4e, 0x0c, 0xc7, 0xa4, 0x47, 0x0c, 0x11, 0xd8, 0x76, 0x95, 0xe2, 0x52, 0x31, 0x15, 0xa5, 0x02,
+	0x37, 0xf4, 0xfe, 0x21, 0x42, 0x7f, 0x61, 0xf7, 0x81, 0x27, 0x41, 0xfa, 0xcc, 0xef, 0xb8, 0x94,
+	0x6c, 0xcc, 0x71, 0x40, 0x80, 0x63, 0xd2, 0x13, 0x6a, 0xdf, 0xc2, 0x86, 0x97, 0x8b, 0x22, 0x38,
+	0xd1, 0x9d, 0x6d, 0x43, 0x77, 0x0f, 0x42, 0x7b, 0xb9, 0xa0, 0xba, 0x4e, 0xa2, 0x1b, 0xd2, 0x19,
+	0x8f, 0x1d, 0x6e, 0x10, 0xd3, 0x62, 0x64, 0x3f, 0x41, 0x58, 0x56, 0x54, 0x64, 0x3f, 0xa9, 0x7d,
+	0xaf, 0x8b, 0xd6, 0xbc, 0x68, 0x2c, 0x98, 0x9a, 0x65, 0x5c, 0x9f, 0xd8, 0xa1, 0x25, 0x40, 0x18,
+	0x36, 0x47, 0xf3, 0xcd, 0xa2, 0xa1, 0x67, 0x3b, 0x39, 0xe8, 0xbd, 0xad, 0x2c, 0xb0, 0x5c, 0x59,
+	0xe0, 0x63, 0x65, 0x81, 0xd7, 0xb5, 0x55, 0x59, 0xae, 0xad, 0xca, 0xfb, 0xda, 0xaa, 0x3c, 0x56,
+	0xa7, 0xbe, 0xdf, 0xd0, 0x3f, 0xe1, 0xe2, 0x2b, 0x00, 0x00, 0xff, 0xff, 0x79, 0x52, 0xe7, 0x38,
+	0x1c, 0x02, 0x00, 0x00,
 }
 
 func (m *Syn) Marshal() (dAtA []byte, err error) {
@@ -346,6 +365,22 @@ func (m *Ack) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x9a
 	}
+	if len(m.Attestation) > 0 {
+		i -= len(m.Attestation)
+		copy(dAtA[i:], m.Attestation)
+		i = encodeVarintHandshake(dAtA, i, uint64(len(m.Attestation)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.Capabilities) > 0 {
+		for iNdEx := len(m.Capabilities) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Capabilities[iNdEx])
+			copy(dAtA[i:], m.Capabilities[iNdEx])
+			i = encodeVarintHandshake(dAtA, i, uint64(len(m.Capabilities[iNdEx])))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
 	if len(m.Nonce) > 0 {
 		i -= len(m.Nonce)
 		copy(dAtA[i:], m.Nonce)
@@ -518,6 +553,16 @@ func (m *Ack) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovHandshake(uint64(l))
 	}
+	if len(m.Capabilities) > 0 {
+		for _, s := range m.Capabilities {
+			l = len(s)
+			n += 1 + l + sovHandshake(uint64(l))
+		}
+	}
+	l = len(m.Attestation)
+	if l > 0 {
+		n += 1 + l + sovHandshake(uint64(l))
+	}
 	l = len(m.WelcomeMessage)
 	if l > 0 {
 		n += 2 + l + sovHandshake(uint64(l))
@@ -791,6 +836,72 @@ func (m *Ack) Unmarshal(dAtA []byte) error {
 				m.Nonce = []byte{}
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Capabilities", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandshake
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandshake
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthHandshake
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Capabilities = append(m.Capabilities, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attestation", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandshake
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthHandshake
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthHandshake
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attestation = append(m.Attestation[:0], dAtA[iNdEx:postIndex]...)
+			if m.Attestation == nil {
+				m.Attestation = []byte{}
+			}
+			iNdEx = postIndex
 		case 99:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field WelcomeMessage", wireType)
@@ -12,6 +12,7 @@ import (
 	"net"
 	"os"
 	"runtime"
+	"runtime/pprof"
 	"strconv"
 	"strings"
 	"sync"
@@ -23,10 +24,12 @@ import (
 	beecrypto "github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/allowlist"
 	"github.com/ethersphere/bee/pkg/p2p/libp2p/internal/blocklist"
 	"github.com/ethersphere/bee/pkg/p2p/libp2p/internal/breaker"
 	handshake "github.com/ethersphere/bee/pkg/p2p/libp2p/internal/handshake"
 	"github.com/ethersphere/bee/pkg/p2p/libp2p/internal/reacher"
+	"github.com/ethersphere/bee/pkg/profiling"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/topology"
@@ -96,6 +99,7 @@ type Service struct {
 	peers             *peerRegistry
 	connectionBreaker breaker.Interface
 	blocklist         *blocklist.Blocklist
+	allowlist         *allowlist.Allowlist
 	protocols         []p2p.ProtocolSpec
 	notifier          p2p.PickyNotifier
 	logger            log.Logger
@@ -126,6 +130,9 @@ type Options struct {
 	FullNode         bool
 	LightNodeLimit   int
 	WelcomeMessage   string
+	Capabilities     []p2p.Capability     // optional protocol features advertised to the peers
+	Allowlist        *allowlist.Allowlist // optional restriction of the peers
+	Attestation      []byte               // authority attestation of the overlay advertised to the peers
 	Nonce            []byte
 	ValidateOverlay  bool
 	hostFactory      func(...libp2p.Option) (host.Host, error)
@@ -301,6 +308,8 @@ func New(ctx context.Context, signer beecrypto.Signer, networkID uint64, overlay
 	if err != nil {
 		return nil, fmt.Errorf("handshake service: %w", err)
 	}
+	handshakeService.SetCapabilities(o.Capabilities...)
+	handshakeService.SetAttestation(o.Attestation)
 
 	// Create a new dialer for libp2p ping protocol. This ensures that the protocol
 	// uses a different set of keys to do ping. It prevents inconsistencies in peerstore as
@@ -327,6 +336,7 @@ func New(ctx context.Context, signer beecrypto.Signer, networkID uint64, overlay
 		peers:             peerRegistry,
 		addressbook:       ab,
 		blocklist:         blocklist.NewBlocklist(storer),
+		allowlist:         o.Allowlist,
 		logger:            logger.WithName(loggerName).Register(),
 		tracer:            tracer,
 		connectionBreaker: breaker.NewBreaker(breaker.Options{}), // use default options
@@ -430,7 +440,14 @@ func (s *Service) handleIncoming(stream network.Stream) {
 		return
 	}
 
-	if exists := s.peers.addIfNotExists(stream.Conn(), overlay, i.FullNode); exists {
+	if !s.allowlist.Allowed(overlay, stream.Conn().RemoteMultiaddr(), i.Attestation) {
+		s.logger.Debug("stream handler: blocked connection from peer not in allowlist", "peer_address", overlay, "underlay", stream.Conn().RemoteMultiaddr())
+		_ = handshakeStream.Reset()
+		_ = s.host.Network().ClosePeer(peerID)
+		return
+	}
+
+	if exists := s.peers.addIfNotExists(stream.Conn(), overlay, i.FullNode, i.Capabilities); exists {
 		s.logger.Debug("stream handler: peer already exists", "peer_address", overlay)
 		if err = handshakeStream.FullClose(); err != nil {
 			s.logger.Debug("stream handler: could not close stream", "peer_address", overlay, "error", err)
@@ -601,7 +618,12 @@ func (s *Service) AddProtocol(p p2p.ProtocolSpec) (err error) {
 			loggerV1 := logger.V(1).Build()
 
 			s.metrics.HandledStreamCount.Inc()
-			if err := ss.Handler(ctx, p2p.Peer{Address: overlay, FullNode: full}, stream); err != nil {
+
+			// label the profiles of the handler with the protocol name
+			pprof.Do(ctx, profiling.Labels(p.Name, "stream", ss.Name), func(ctx context.Context) {
+				err = ss.Handler(ctx, p2p.Peer{Address: overlay, FullNode: full}, stream)
+			})
+			if err != nil {
 				var de *p2p.DisconnectError
 				if errors.As(err, &de) {
 					loggerV1.Debug("libp2p handler: disconnecting due to disconnect error", "protocol", p.Name, "address", overlay)
@@ -768,7 +790,14 @@ func (s *Service) Connect(ctx context.Context, addr ma.Multiaddr) (address *bzz.
 		return nil, p2p.ErrPeerBlocklisted
 	}
 
-	if exists := s.peers.addIfNotExists(stream.Conn(), overlay, i.FullNode); exists {
+	if !s.allowlist.Allowed(overlay, stream.Conn().RemoteMultiaddr(), i.Attestation) {
+		s.logger.Debug("blocked connection to peer not in allowlist", "peer_id", info.ID, "peer_address", overlay)
+		_ = handshakeStream.Reset()
+		_ = s.host.Network().ClosePeer(info.ID)
+		return nil, p2p.ErrPeerNotAllowed
+	}
+
+	if exists := s.peers.addIfNotExists(stream.Conn(), overlay, i.FullNode, i.Capabilities); exists {
 		if err := handshakeStream.FullClose(); err != nil {
 			_ = s.Disconnect(overlay, "failed closing handshake stream after connect")
 			return nil, fmt.Errorf("peer exists, full close: %w", err)
@@ -898,6 +927,11 @@ func (s *Service) Peers() []p2p.Peer {
 	return s.peers.peers()
 }
 
+// Supports reports whether the protocol feature was negotiated with the peer.
+func (s *Service) Supports(overlay swarm.Address, c p2p.Capability) bool {
+	return s.peers.supports(overlay, c)
+}
+
 func (s *Service) Blocklisted(overlay swarm.Address) (bool, error) {
 	return s.blocklist.Exists(overlay)
 }
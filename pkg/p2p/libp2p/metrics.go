@@ -27,6 +27,8 @@ type metrics struct {
 	KickedOutPeersCount        prometheus.Counter
 	StreamHandlerErrResetCount prometheus.Counter
 	HeadersExchangeDuration    prometheus.Histogram
+	StreamReceivedBytes        prometheus.Counter
+	StreamSentBytes            prometheus.Counter
 }
 
 func newMetrics() metrics {
@@ -117,6 +119,18 @@ func newMetrics() metrics {
 			Name:      "headers_exchange_duration",
 			Help:      "The duration spent exchanging the headers.",
 		}),
+		StreamReceivedBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "stream_received_bytes",
+			Help:      "Number of bytes read from all streams.",
+		}),
+		StreamSentBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "stream_sent_bytes",
+			Help:      "Number of bytes written to all streams.",
+		}),
 	}
 }
 
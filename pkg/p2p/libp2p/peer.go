@@ -21,6 +21,7 @@ type peerRegistry struct {
 	underlays   map[string]libp2ppeer.ID                    // map overlay address to underlay peer id
 	overlays    map[libp2ppeer.ID]swarm.Address             // map underlay peer id to overlay address
 	full        map[libp2ppeer.ID]bool                      // map to track whether a node is full or light node (true=full)
+	features    map[libp2ppeer.ID]p2p.Capabilities          // map to track the protocol features negotiated with the peer
 	connections map[libp2ppeer.ID]map[network.Conn]struct{} // list of connections for safe removal on Disconnect notification
 	streams     map[libp2ppeer.ID]map[network.Stream]context.CancelFunc
 	mu          sync.RWMutex
@@ -39,6 +40,7 @@ func newPeerRegistry() *peerRegistry {
 		underlays:   make(map[string]libp2ppeer.ID),
 		overlays:    make(map[libp2ppeer.ID]swarm.Address),
 		full:        make(map[libp2ppeer.ID]bool),
+		features:    make(map[libp2ppeer.ID]p2p.Capabilities),
 		connections: make(map[libp2ppeer.ID]map[network.Conn]struct{}),
 		streams:     make(map[libp2ppeer.ID]map[network.Stream]context.CancelFunc),
 
@@ -81,6 +83,7 @@ func (r *peerRegistry) Disconnected(_ network.Network, c network.Conn) {
 	}
 	delete(r.streams, peerID)
 	delete(r.full, peerID)
+	delete(r.features, peerID)
 	r.mu.Unlock()
 	r.disconnecter.disconnected(overlay)
 
@@ -131,7 +134,7 @@ func (r *peerRegistry) peers() []p2p.Peer {
 	return peers
 }
 
-func (r *peerRegistry) addIfNotExists(c network.Conn, overlay swarm.Address, full bool) (exists bool) {
+func (r *peerRegistry) addIfNotExists(c network.Conn, overlay swarm.Address, full bool, features p2p.Capabilities) (exists bool) {
 	peerID := c.RemotePeer()
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -151,6 +154,7 @@ func (r *peerRegistry) addIfNotExists(c network.Conn, overlay swarm.Address, ful
 	r.underlays[overlay.ByteString()] = peerID
 	r.overlays[peerID] = overlay
 	r.full[peerID] = full
+	r.features[peerID] = features
 	return false
 
 }
@@ -176,6 +180,18 @@ func (r *peerRegistry) fullnode(peerID libp2ppeer.ID) (bool, bool) {
 	return full, found
 }
 
+// supports reports whether the feature was negotiated with the peer.
+func (r *peerRegistry) supports(overlay swarm.Address, c p2p.Capability) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	peerID, found := r.underlays[overlay.ByteString()]
+	if !found {
+		return false
+	}
+	return r.features[peerID].Has(c)
+}
+
 func (r *peerRegistry) isConnected(peerID libp2ppeer.ID, remoteAddr ma.Multiaddr) (swarm.Address, bool) {
 	if remoteAddr == nil {
 		return swarm.ZeroAddress, false
@@ -217,6 +233,7 @@ func (r *peerRegistry) remove(overlay swarm.Address) (found, full bool, peerID l
 	delete(r.streams, peerID)
 	full = r.full[peerID]
 	delete(r.full, peerID)
+	delete(r.features, peerID)
 	r.mu.Unlock()
 
 	return found, full, peerID
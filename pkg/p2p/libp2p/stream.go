@@ -5,10 +5,12 @@
 package libp2p
 
 import (
+	"context"
 	"errors"
 	"io"
 	"time"
 
+	"github.com/ethersphere/bee/pkg/faults"
 	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/libp2p/go-libp2p/core/network"
 )
@@ -29,6 +31,26 @@ type stream struct {
 func newStream(s network.Stream, metrics metrics) *stream {
 	return &stream{Stream: s, metrics: metrics}
 }
+
+func (s *stream) Read(p []byte) (int, error) {
+	if err := faults.Inject(context.Background(), faults.StreamRead); err != nil {
+		return 0, err
+	}
+	n, err := s.Stream.Read(p)
+	s.metrics.StreamReceivedBytes.Add(float64(n))
+	copy(p[:n], faults.Corrupt(faults.StreamRead, p[:n]))
+	return n, err
+}
+
+func (s *stream) Write(p []byte) (int, error) {
+	if err := faults.Inject(context.Background(), faults.StreamWrite); err != nil {
+		return 0, err
+	}
+	n, err := s.Stream.Write(faults.Corrupt(faults.StreamWrite, p))
+	s.metrics.StreamSentBytes.Add(float64(n))
+	return n, err
+}
+
 func (s *stream) Headers() p2p.Headers {
 	return s.headers
 }
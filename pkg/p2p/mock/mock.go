@@ -27,6 +27,7 @@ type Service struct {
 	setWelcomeMessageFunc func(string) error
 	getWelcomeMessageFunc func() string
 	blocklistFunc         func(swarm.Address, time.Duration, string) error
+	supportsFunc          func(swarm.Address, p2p.Capability) bool
 	welcomeMessage        string
 }
 
@@ -92,6 +93,13 @@ func WithBlocklistFunc(f func(swarm.Address, time.Duration, string) error) Optio
 	})
 }
 
+// WithSupportsFunc sets the mock implementation of the Supports function
+func WithSupportsFunc(f func(swarm.Address, p2p.Capability) bool) Option {
+	return optionFunc(func(s *Service) {
+		s.supportsFunc = f
+	})
+}
+
 // New will create a new mock P2P Service with the given options
 func New(opts ...Option) *Service {
 	s := new(Service)
@@ -134,6 +142,13 @@ func (s *Service) Addresses() ([]ma.Multiaddr, error) {
 	return s.addressesFunc()
 }
 
+func (s *Service) Supports(overlay swarm.Address, c p2p.Capability) bool {
+	if s.supportsFunc == nil {
+		return false
+	}
+	return s.supportsFunc(overlay, c)
+}
+
 func (s *Service) Peers() []p2p.Peer {
 	if s.peersFunc == nil {
 		return nil
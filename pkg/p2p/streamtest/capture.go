@@ -0,0 +1,179 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package streamtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/protobuf"
+)
+
+// Capture is a recorded protocol stream which can be stored in a file
+// and replayed against a protocol handler later, so that the changes of
+// the protocol can be validated against the previously captured traffic.
+type Capture struct {
+	Peer     string `json:"peer"`
+	Protocol string `json:"protocol"`
+	Version  string `json:"version"`
+	Stream   string `json:"stream"`
+	In       []byte `json:"in"`  // bytes written by the stream initiator
+	Out      []byte `json:"out"` // bytes written by the handler
+}
+
+// Captures returns all the streams recorded so far, ordered by the
+// peer and the stream name and in the creation order within the stream.
+func (r *Recorder) Captures() []Capture {
+	r.recordsMu.Lock()
+	ids := make([]string, 0, len(r.records))
+	for id := range r.records {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var records []*Record
+	for _, id := range ids {
+		records = append(records, r.records[id]...)
+	}
+	r.recordsMu.Unlock()
+
+	captures := make([]Capture, 0, len(records))
+	for _, rec := range records {
+		<-rec.done
+		captures = append(captures, Capture{
+			Peer:     rec.peer.String(),
+			Protocol: rec.protocol,
+			Version:  rec.version,
+			Stream:   rec.stream,
+			In:       rec.In(),
+			Out:      rec.Out(),
+		})
+	}
+	return captures
+}
+
+// WriteCaptures stores the captures in the file at the given path.
+func WriteCaptures(path string, captures []Capture) error {
+	b, err := json.MarshalIndent(captures, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal captures: %w", err)
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// ReadCaptures loads the captures from the file at the given path.
+func ReadCaptures(path string) ([]Capture, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var captures []Capture
+	if err := json.Unmarshal(b, &captures); err != nil {
+		return nil, fmt.Errorf("unmarshal captures %s: %w", path, err)
+	}
+	return captures, nil
+}
+
+// Replay runs the handler on a stream which yields the captured input
+// and returns everything the handler wrote to the stream.
+func Replay(ctx context.Context, handler p2p.HandlerFunc, peer p2p.Peer, c Capture) ([]byte, error) {
+	in, out := newRecord(), newRecord()
+	if len(c.In) > 0 {
+		if _, err := in.Write(c.In); err != nil {
+			return nil, err
+		}
+	}
+	in.close()
+
+	// drain the output so that the handler never blocks on writes
+	drained := make(chan []byte)
+	go func() {
+		b, _ := io.ReadAll(out)
+		drained <- b
+	}()
+
+	err := handler(ctx, peer, newStream(out, in))
+	out.close()
+	b := <-drained
+	if err != nil && !errors.Is(err, io.EOF) {
+		return b, err
+	}
+	return b, nil
+}
+
+// SeedCorpus adds the captured inputs of the given stream from all the
+// capture files matching the glob pattern to the fuzzing seed corpus.
+func SeedCorpus(f *testing.F, pattern, protocol, stream string) {
+	f.Helper()
+
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, path := range paths {
+		captures, err := ReadCaptures(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		for _, c := range captures {
+			if c.Protocol == protocol && c.Stream == stream {
+				f.Add(c.In)
+			}
+		}
+	}
+}
+
+// Message is the protocol buffer message which can be encoded and decoded
+// on its own, as generated for the protocols.
+type Message interface {
+	protobuf.Message
+	Marshal() ([]byte, error)
+	Unmarshal([]byte) error
+}
+
+// FuzzDecoder fuzzes the decoding of the delimited messages, returned
+// empty by newMsg, from the stream input. The fuzzing is seeded with the
+// captured inputs of the given stream, as in SeedCorpus, and every decoded
+// message must survive the encoding round trip.
+func FuzzDecoder(f *testing.F, pattern, protocol, stream string, newMsg func() Message) {
+	f.Helper()
+
+	SeedCorpus(f, pattern, protocol, stream)
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := protobuf.NewReader(bytes.NewReader(data))
+		for {
+			msg := newMsg()
+			if err := r.ReadMsg(msg); err != nil {
+				return
+			}
+
+			b, err := msg.Marshal()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := newMsg()
+			if err := got.Unmarshal(b); err != nil {
+				t.Fatal(err)
+			}
+			gotb, err := got.Marshal()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(b, gotb) {
+				t.Fatalf("round trip mismatch: %x != %x", b, gotb)
+			}
+		}
+	})
+}
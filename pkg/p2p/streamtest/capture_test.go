@@ -0,0 +1,87 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package streamtest_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/streamtest"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestCaptureReplay(t *testing.T) {
+	t.Parallel()
+
+	// echoes every line in upper case
+	handler := func(_ context.Context, _ p2p.Peer, stream p2p.Stream) error {
+		defer stream.Close()
+		r := bufio.NewReader(stream)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			if _, err := stream.Write([]byte(strings.ToUpper(line))); err != nil {
+				return err
+			}
+		}
+	}
+
+	recorder := streamtest.New(streamtest.WithProtocols(newTestProtocol(handler)))
+
+	addr := swarm.RandAddress(t)
+	for _, msg := range []string{"hello\nworld\n", "swarm\n"} {
+		stream, err := recorder.NewStream(context.Background(), addr, nil, testProtocolName, testProtocolVersion, testStreamName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stream.Write([]byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+		if err := stream.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "captures.json")
+	if err := streamtest.WriteCaptures(path, recorder.Captures()); err != nil {
+		t.Fatal(err)
+	}
+	captures, err := streamtest.ReadCaptures(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(captures) != 2 {
+		t.Fatalf("got %d captures, want 2", len(captures))
+	}
+	for _, c := range captures {
+		if c.Peer != addr.String() || c.Protocol != testProtocolName || c.Version != testProtocolVersion || c.Stream != testStreamName {
+			t.Fatalf("unexpected capture %+v", c)
+		}
+
+		out, err := streamtest.Replay(context.Background(), handler, p2p.Peer{Address: addr}, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(out, c.Out) {
+			t.Fatalf("replayed output %q, captured %q", out, c.Out)
+		}
+	}
+	if got := string(captures[0].Out); got != "HELLO\nWORLD\n" {
+		t.Fatalf("got captured output %q", got)
+	}
+}
@@ -138,7 +138,15 @@ func (r *Recorder) NewStream(ctx context.Context, addr swarm.Address, h p2p.Head
 	if headler != nil {
 		streamOut.headers = headler(h, addr)
 	}
-	record := &Record{in: recordIn, out: recordOut, done: make(chan struct{})}
+	record := &Record{
+		in:       recordIn,
+		out:      recordOut,
+		done:     make(chan struct{}),
+		peer:     addr,
+		protocol: protocolName,
+		version:  protocolVersion,
+		stream:   streamName,
+	}
 	go func() {
 		defer close(record.done)
 
@@ -210,11 +218,15 @@ func (r *Recorder) WaitRecords(t *testing.T, addr swarm.Address, proto, version,
 }
 
 type Record struct {
-	in    *record
-	out   *record
-	err   error
-	errMu sync.Mutex
-	done  chan struct{}
+	in       *record
+	out      *record
+	err      error
+	errMu    sync.Mutex
+	done     chan struct{}
+	peer     swarm.Address
+	protocol string
+	version  string
+	stream   string
 }
 
 func (r *Record) In() []byte {
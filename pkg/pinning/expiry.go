@@ -0,0 +1,256 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pinning
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/encryption"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/traversal"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "pinning"
+
+const (
+	expiryQueueSize        = 64
+	expirySubscriberBuffer = 16
+	webhookTimeout         = 10 * time.Second
+)
+
+// errStampFound stops the traversal of a pinned reference
+// once a chunk stamped by the expired batch is found.
+var errStampFound = errors.New("stamp found")
+
+// ExpiryEvent lists the locally pinned references with chunks stamped by
+// the expired batch. These chunks are evicted from the reserve of the
+// network, so the references must be re-stamped and re-uploaded in order
+// to stay retrievable by other nodes.
+type ExpiryEvent struct {
+	BatchID    string          `json:"batchID"`
+	References []swarm.Address `json:"references"`
+}
+
+// ExpirySubscriber subscribes to the expiry events of the pinned references.
+type ExpirySubscriber interface {
+	// SubscribeExpiry returns the channel of the expiry events and the
+	// function which cancels the subscription.
+	SubscribeExpiry() (<-chan ExpiryEvent, func())
+}
+
+var _ ExpirySubscriber = (*ExpiryNotifier)(nil)
+
+// ExpiryNotifier handles the expiry of the batches and notifies the
+// subscribers and the webhooks about the affected pinned references.
+type ExpiryNotifier struct {
+	logger   log.Logger
+	pins     *Service
+	webhooks []string
+	client   *http.Client
+
+	mtx         sync.Mutex
+	subscribers map[chan ExpiryEvent]struct{}
+
+	queue  chan []byte
+	quit   chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewExpiryNotifier constructs a new ExpiryNotifier. The expiry events are
+// posted as JSON to the webhooks URLs. Only the locally stored chunks are
+// traversed when the affected references are looked up.
+func NewExpiryNotifier(
+	pinStorage storage.Storer,
+	rhStorage storage.StateStorer,
+	logger log.Logger,
+	webhooks []string,
+) *ExpiryNotifier {
+	ctx, cancel := context.WithCancel(context.Background())
+	n := &ExpiryNotifier{
+		logger:      logger.WithName(loggerName).Register(),
+		pins:        NewService(pinStorage, rhStorage, traversal.New(pinStorage)),
+		webhooks:    webhooks,
+		client:      &http.Client{Timeout: webhookTimeout},
+		subscribers: make(map[chan ExpiryEvent]struct{}),
+		queue:       make(chan []byte, expiryQueueSize),
+		quit:        make(chan struct{}),
+		cancel:      cancel,
+	}
+
+	n.wg.Add(1)
+	go n.run(ctx)
+
+	return n
+}
+
+// HandleStampExpiry implements postage.StampExpiryHandler. The affected
+// references are looked up asynchronously, so the caller is not blocked.
+func (n *ExpiryNotifier) HandleStampExpiry(batchID []byte) {
+	if len(n.webhooks) == 0 && !n.hasSubscribers() {
+		return
+	}
+
+	id := make([]byte, len(batchID))
+	copy(id, batchID)
+
+	select {
+	case n.queue <- id:
+	case <-n.quit:
+	default:
+		n.logger.Warning("expiry queue full, dropping the expiry notification", "batch_id", hex.EncodeToString(id))
+	}
+}
+
+// SubscribeExpiry implements ExpirySubscriber.SubscribeExpiry method.
+func (n *ExpiryNotifier) SubscribeExpiry() (<-chan ExpiryEvent, func()) {
+	c := make(chan ExpiryEvent, expirySubscriberBuffer)
+
+	n.mtx.Lock()
+	n.subscribers[c] = struct{}{}
+	n.mtx.Unlock()
+
+	var once sync.Once
+	return c, func() {
+		once.Do(func() {
+			n.mtx.Lock()
+			delete(n.subscribers, c)
+			n.mtx.Unlock()
+		})
+	}
+}
+
+// Close stops the notifier.
+func (n *ExpiryNotifier) Close() error {
+	close(n.quit)
+	n.cancel()
+	n.wg.Wait()
+	return nil
+}
+
+func (n *ExpiryNotifier) hasSubscribers() bool {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return len(n.subscribers) > 0
+}
+
+func (n *ExpiryNotifier) run(ctx context.Context) {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case <-n.quit:
+			return
+		case id := <-n.queue:
+			refs, err := n.affected(ctx, id)
+			if err != nil {
+				n.logger.Debug("lookup of affected pins failed", "batch_id", hex.EncodeToString(id), "error", err)
+				continue
+			}
+			if len(refs) == 0 {
+				continue
+			}
+			n.notify(ctx, ExpiryEvent{BatchID: hex.EncodeToString(id), References: refs})
+		}
+	}
+}
+
+// affected returns the pinned references with at least
+// one chunk stamped by the batch with the given ID.
+func (n *ExpiryNotifier) affected(ctx context.Context, batchID []byte) ([]swarm.Address, error) {
+	pins, err := n.pins.Pins()
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]swarm.Address, 0)
+	for _, ref := range pins {
+		found := false
+		iterFn := func(leaf swarm.Address) error {
+			if len(leaf.Bytes()) == encryption.ReferenceSize {
+				leaf = swarm.NewAddress(leaf.Bytes()[:swarm.HashSize])
+			}
+			ch, err := n.pins.pinStorage.Get(ctx, storage.ModeGetLookup, leaf)
+			if errors.Is(err, storage.ErrNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if ch.Stamp() != nil && bytes.Equal(ch.Stamp().BatchID(), batchID) {
+				found = true
+				return errStampFound
+			}
+			return nil
+		}
+
+		err := n.pins.traverser.Traverse(ctx, ref, iterFn)
+		switch {
+		case found:
+			refs = append(refs, ref)
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case err != nil:
+			n.logger.Debug("traversal of pinned reference failed", "reference", ref, "error", err)
+		}
+	}
+	return refs, nil
+}
+
+func (n *ExpiryNotifier) notify(ctx context.Context, ev ExpiryEvent) {
+	n.mtx.Lock()
+	for c := range n.subscribers {
+		select {
+		case c <- ev:
+		default:
+			n.logger.Debug("slow expiry subscriber, dropping the event", "batch_id", ev.BatchID)
+		}
+	}
+	n.mtx.Unlock()
+
+	if len(n.webhooks) == 0 {
+		return
+	}
+	body, err := json.Marshal(ev)
+	if err != nil {
+		n.logger.Error(err, "marshal expiry event")
+		return
+	}
+	for _, url := range n.webhooks {
+		if err := n.post(ctx, url, body); err != nil {
+			n.logger.Warning("expiry webhook failed", "url", url, "error", err)
+		}
+	}
+}
+
+func (n *ExpiryNotifier) post(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pinning_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/pinning"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+	statestorem "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	storagem "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/traversal"
+	"github.com/ethersphere/bee/pkg/util/testutil"
+)
+
+// stampPutter stamps the chunks before putting them to the storer.
+type stampPutter struct {
+	storage.Storer
+	stamp swarm.Stamp
+}
+
+func (p stampPutter) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk) ([]bool, error) {
+	for _, ch := range chs {
+		ch.WithStamp(p.stamp)
+	}
+	return p.Storer.Put(ctx, mode, chs...)
+}
+
+func TestExpiryNotifier(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = context.Background()
+		storerMock = storagem.NewStorer()
+		stateStore = statestorem.NewStateStore()
+		service    = pinning.NewService(storerMock, stateStore, traversal.New(storerMock))
+		expired    = postagetesting.MustNewStamp()
+		live       = postagetesting.MustNewStamp()
+	)
+
+	upload := func(stamp swarm.Stamp, content string) swarm.Address {
+		t.Helper()
+
+		pipe := builder.NewPipelineBuilder(ctx, stampPutter{storerMock, stamp}, storage.ModePutUpload, false)
+		ref, err := builder.FeedPipeline(ctx, pipe, strings.NewReader(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := service.CreatePin(ctx, ref, true); err != nil {
+			t.Fatal(err)
+		}
+		return ref
+	}
+	affected := upload(expired, strings.Repeat("expired", 1000))
+	_ = upload(live, strings.Repeat("live", 1000))
+
+	hookC := make(chan pinning.ExpiryEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev pinning.ExpiryEvent
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		hookC <- ev
+	}))
+	t.Cleanup(srv.Close)
+
+	notifier := pinning.NewExpiryNotifier(storerMock, stateStore, log.Noop, []string{srv.URL})
+	testutil.CleanupCloser(t, notifier)
+
+	events, cancel := notifier.SubscribeExpiry()
+	defer cancel()
+
+	// no pinned reference is stamped by the batch
+	notifier.HandleStampExpiry(postagetesting.MustNewID())
+	notifier.HandleStampExpiry(expired.BatchID())
+
+	want := pinning.ExpiryEvent{
+		BatchID:    hex.EncodeToString(expired.BatchID()),
+		References: []swarm.Address{affected},
+	}
+	check := func(name string, c <-chan pinning.ExpiryEvent) {
+		t.Helper()
+
+		select {
+		case ev := <-c:
+			if ev.BatchID != want.BatchID {
+				t.Fatalf("%s: got batch ID %s, want %s", name, ev.BatchID, want.BatchID)
+			}
+			if len(ev.References) != 1 || !ev.References[0].Equal(affected) {
+				t.Fatalf("%s: got references %v, want %v", name, ev.References, want.References)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: timed out waiting for the expiry event", name)
+		}
+	}
+	check("subscription", events)
+	check("webhook", hookC)
+}
@@ -0,0 +1,34 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pinning
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/jobs"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// JobKind is the kind of the jobs which pin the content in the background.
+const JobKind = "pin"
+
+// JobParams are the parameters of the pin job.
+type JobParams struct {
+	Reference swarm.Address `json:"reference"`
+}
+
+// NewJob returns the runner of the pin jobs, which pin the content of the
+// reference with all its chunks. The resumed job pins the content again,
+// which is idempotent.
+func NewJob(s Interface) jobs.Runner {
+	return func(ctx context.Context, t *jobs.Task) error {
+		var p JobParams
+		if err := t.Params(&p); err != nil {
+			return fmt.Errorf("params: %w", err)
+		}
+		return s.CreatePin(ctx, p.Reference, true)
+	}
+}
@@ -14,6 +14,7 @@ type metrics struct {
 	Radius            prometheus.Gauge
 	StorageRadius     prometheus.Gauge
 	UnreserveDuration prometheus.HistogramVec
+	Pruned            prometheus.Counter
 }
 
 func newMetrics() metrics {
@@ -44,6 +45,12 @@ func newMetrics() metrics {
 			Name:      "unreserve_duration",
 			Help:      "Duration in seconds for the Unreserve call.",
 		}, []string{"beforeLock"}),
+		Pruned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "pruned",
+			Help:      "Number of expired batches and stale index entries removed by the pruning.",
+		}),
 	}
 }
 
@@ -0,0 +1,216 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package batchstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+// PruneProgress is the progress of the pruning of the batch store.
+type PruneProgress struct {
+	Checked int // number of the checked batches
+	Expired int // number of the removed expired batches
+	Stale   int // number of the removed value index entries without a batch
+	Rebuilt int // number of the restored value index entries
+}
+
+// Pruner is implemented by the batch store which can be pruned.
+type Pruner interface {
+	Prune(ctx context.Context, progress func(PruneProgress)) (PruneProgress, error)
+}
+
+var _ Pruner = (*store)(nil)
+
+// Prune removes the expired batches, rebuilds the value index from the
+// batches and recomputes the radius. Unlike the cleanup on the chain state
+// updates, which only follows the value index, every batch is checked, so
+// that the batches which are missing from the value index, and therefore
+// never expire, no longer inflate the radius. The candidates are collected
+// first and the store is locked only for each of the changes, which are
+// validated again against the current state, so that the chain state
+// updates are not blocked for the whole pruning. The progress is called
+// after every checked batch.
+func (s *store) Prune(ctx context.Context, progress func(PruneProgress)) (PruneProgress, error) {
+	var p PruneProgress
+
+	values, batches, err := s.pruneCandidates()
+	if err != nil {
+		return p, err
+	}
+
+	for _, b := range batches {
+		if err := ctx.Err(); err != nil {
+			return p, err
+		}
+
+		key := valueKey(b.Value, b.ID)
+		_, indexed := values[key]
+		delete(values, key)
+
+		expired, rebuilt, err := s.pruneBatch(b, indexed)
+		if err != nil {
+			return p, err
+		}
+		if expired {
+			p.Expired++
+		}
+		if rebuilt {
+			p.Rebuilt++
+		}
+
+		p.Checked++
+		if progress != nil {
+			progress(p)
+		}
+	}
+
+	// the remaining value keys are not of any batch, they are left
+	// by the interrupted updates and they break the cleanup
+	for key := range values {
+		if err := ctx.Err(); err != nil {
+			return p, err
+		}
+
+		deleted, err := s.pruneValueKey(key)
+		if err != nil {
+			return p, err
+		}
+		if deleted {
+			p.Stale++
+		}
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if err := s.computeRadius(); err != nil {
+		return p, fmt.Errorf("batchstore: prune adjust radius: %w", err)
+	}
+
+	if s.storageRadiusSetter != nil {
+		s.storageRadiusSetter.SetStorageRadius(s.rs.StorageRadius)
+	}
+
+	s.metrics.StorageRadius.Set(float64(s.rs.StorageRadius))
+	s.metrics.Radius.Set(float64(s.rs.Radius))
+	s.metrics.Pruned.Add(float64(p.Expired + p.Stale))
+
+	return p, nil
+}
+
+// pruneCandidates returns the keys of the value index and all the batches.
+func (s *store) pruneCandidates() (map[string]struct{}, []*postage.Batch, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	values := make(map[string]struct{})
+	err := s.store.Iterate(valueKeyPrefix, func(key, _ []byte) (bool, error) {
+		values[string(key)] = struct{}{}
+		return false, nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("batchstore: prune iterate values: %w", err)
+	}
+
+	var batches []*postage.Batch
+	err = s.store.Iterate(batchKeyPrefix, func(_, value []byte) (bool, error) {
+		b := &postage.Batch{}
+		if err := b.UnmarshalBinary(value); err != nil {
+			return false, err
+		}
+		batches = append(batches, b)
+		return false, nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("batchstore: prune iterate batches: %w", err)
+	}
+
+	return values, batches, nil
+}
+
+// pruneBatch removes the batch if it is expired or restores its value key
+// if it was not indexed when the candidates were collected. The batch is
+// loaded again, as it may have been updated or removed since, and the
+// updates of its value index it again.
+func (s *store) pruneBatch(candidate *postage.Batch, indexed bool) (expired, rebuilt bool, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	b, err := s.get(candidate.ID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("batchstore: prune: %w", err)
+	}
+
+	key := valueKey(b.Value, b.ID)
+	switch {
+	case b.Value.Cmp(s.cs.TotalAmount) <= 0:
+		if err := s.evictFn(b.ID); err != nil {
+			return false, false, fmt.Errorf("batchstore: prune evict batch %x: %w", b.ID, err)
+		}
+		if err := s.store.Delete(key); err != nil {
+			return false, false, fmt.Errorf("batchstore: prune delete value key for batch %x: %w", b.ID, err)
+		}
+		if err := s.store.Delete(batchKey(b.ID)); err != nil {
+			return false, false, fmt.Errorf("batchstore: prune delete batch %x: %w", b.ID, err)
+		}
+		if s.batchExpiry != nil {
+			s.batchExpiry.HandleStampExpiry(b.ID)
+		}
+		return true, false, nil
+	case !indexed && b.Value.Cmp(candidate.Value) == 0:
+		if err := s.store.Put(key, nil); err != nil {
+			return false, false, fmt.Errorf("batchstore: prune restore value key for batch %x: %w", b.ID, err)
+		}
+		return false, true, nil
+	}
+	return false, false, nil
+}
+
+// pruneValueKey deletes the value key unless it has become
+// the value key of its batch since the candidates were collected.
+func (s *store) pruneValueKey(key string) (bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	b, err := s.get(valueKeyToID([]byte(key)))
+	switch {
+	case err == nil:
+		if valueKey(b.Value, b.ID) == key {
+			return false, nil
+		}
+	case !errors.Is(err, storage.ErrNotFound):
+		return false, fmt.Errorf("batchstore: prune: %w", err)
+	}
+
+	if err := s.store.Delete(key); err != nil {
+		return false, fmt.Errorf("batchstore: prune delete stale value key: %w", err)
+	}
+	return true, nil
+}
+
+// PruneJob returns the maintenance job which prunes the batch store.
+func PruneJob(p Pruner, logger log.Logger) func(context.Context) error {
+	logger = logger.WithName(loggerName).Register()
+
+	return func(ctx context.Context) error {
+		start := time.Now()
+		res, err := p.Prune(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("prune batches: %w", err)
+		}
+		logger.Debug("batches pruned", "checked", res.Checked, "expired", res.Expired, "stale", res.Stale, "rebuilt", res.Rebuilt, "elapsed", time.Since(start))
+		return nil
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package batchstore_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/postage/batchstore"
+	postagetest "github.com/ethersphere/bee/pkg/postage/testing"
+	"github.com/ethersphere/bee/pkg/statestore/leveldb"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestBatchStore_Prune(t *testing.T) {
+	var evicted [][]byte
+	evictFn := func(id []byte) error {
+		evicted = append(evicted, id)
+		return nil
+	}
+
+	stateStore := newPruneStateStore(t)
+	batchStore, err := batchstore.New(stateStore, evictFn, swarm.RandAddress(t), log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batchStorePutChainState(t, batchStore, &postage.ChainState{
+		Block:        1,
+		TotalAmount:  big.NewInt(100),
+		CurrentPrice: big.NewInt(1),
+	})
+
+	valid := postagetest.MustNewBatch(postagetest.WithValue(200))
+	if err := batchStore.Save(valid); err != nil {
+		t.Fatal(err)
+	}
+
+	// the batches missing from the value index are never cleaned up
+	expired := postagetest.MustNewBatch(postagetest.WithValue(50))
+	stateStorePut(t, stateStore, batchstore.BatchKey(expired.ID), expired)
+	unindexed := postagetest.MustNewBatch(postagetest.WithValue(300))
+	stateStorePut(t, stateStore, batchstore.BatchKey(unindexed.ID), unindexed)
+	// and a value key without a batch breaks the cleanup
+	stateStorePut(t, stateStore, batchstore.ValueKey(big.NewInt(400), postagetest.MustNewID()), nil)
+
+	var checked int
+	p, err := batchStore.(batchstore.Pruner).Prune(context.Background(), func(p batchstore.PruneProgress) {
+		checked = p.Checked
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := batchstore.PruneProgress{Checked: 3, Expired: 1, Stale: 1, Rebuilt: 1}
+	if p != want {
+		t.Fatalf("got progress %+v, want %+v", p, want)
+	}
+	if checked != 3 {
+		t.Fatalf("got %d checked batches reported, want 3", checked)
+	}
+	if len(evicted) != 1 || !bytes.Equal(evicted[0], expired.ID) {
+		t.Fatalf("got evicted batches %x, want %x", evicted, expired.ID)
+	}
+	if ok, err := batchStore.Exists(expired.ID); err != nil || ok {
+		t.Fatalf("got expired batch exists %v, error %v", ok, err)
+	}
+
+	// the chain state updates clean up again
+	batchStorePutChainState(t, batchStore, &postage.ChainState{
+		Block:        2,
+		TotalAmount:  big.NewInt(250),
+		CurrentPrice: big.NewInt(1),
+	})
+	if ok, err := batchStore.Exists(valid.ID); err != nil || ok {
+		t.Fatalf("got expired batch exists %v, error %v", ok, err)
+	}
+	if ok, err := batchStore.Exists(unindexed.ID); err != nil || !ok {
+		t.Fatalf("got batch exists %v, error %v", ok, err)
+	}
+}
+
+func TestBatchStore_PruneConcurrentUpdate(t *testing.T) {
+	stateStore := newPruneStateStore(t)
+	batchStore, err := batchstore.New(stateStore, func([]byte) error { return nil }, swarm.RandAddress(t), log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batchStorePutChainState(t, batchStore, &postage.ChainState{
+		Block:        1,
+		TotalAmount:  big.NewInt(100),
+		CurrentPrice: big.NewInt(1),
+	})
+
+	// both batches are expired when the pruning starts
+	batches := []*postage.Batch{
+		postagetest.MustNewBatch(postagetest.WithValue(50)),
+		postagetest.MustNewBatch(postagetest.WithValue(60)),
+	}
+	for _, b := range batches {
+		stateStorePut(t, stateStore, batchstore.BatchKey(b.ID), b)
+	}
+
+	// the batch which is not checked yet is topped up during the pruning
+	var toppedUp *postage.Batch
+	p, err := batchStore.(batchstore.Pruner).Prune(context.Background(), func(p batchstore.PruneProgress) {
+		if p.Checked != 1 {
+			return
+		}
+		for _, b := range batches {
+			switch err := batchStore.Update(b, big.NewInt(500), b.Depth); {
+			case err == nil:
+				toppedUp = b
+			case !errors.Is(err, batchstore.ErrNotFound):
+				t.Error(err)
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if toppedUp == nil {
+		t.Fatal("no batch topped up")
+	}
+	want := batchstore.PruneProgress{Checked: 2, Expired: 1}
+	if p != want {
+		t.Fatalf("got progress %+v, want %+v", p, want)
+	}
+	if ok, err := batchStore.Exists(toppedUp.ID); err != nil || !ok {
+		t.Fatalf("got topped up batch exists %v, error %v", ok, err)
+	}
+}
+
+// newPruneStateStore returns the real statestore, as the cleanup on the chain
+// state updates relies on the value keys being iterated in order.
+func newPruneStateStore(t *testing.T) storage.StateStorer {
+	t.Helper()
+
+	stateStore, err := leveldb.NewStateStore(t.TempDir(), log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := stateStore.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return stateStore
+}
@@ -0,0 +1,365 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package events provides the notifications about the lifecycle of the
+// postage batches owned by the node, which are delivered to the subscribers
+// and posted to the webhooks, so that the external automation can react to
+// them without polling the batches.
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/bigint"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "postage-events"
+
+const (
+	queueSize        = 64
+	subscriberBuffer = 16
+	webhookTimeout   = 10 * time.Second
+
+	// DefaultExpiryWarning is the default remaining time to live of
+	// the batches for which the nearing expiry event is emitted.
+	DefaultExpiryWarning = 24 * time.Hour
+	// DefaultCheckInterval is the default interval of the checks
+	// of the remaining time to live of the batches.
+	DefaultCheckInterval = 5 * time.Minute
+)
+
+// The types of the batch events.
+const (
+	TypeCreated       = "created"
+	TypeToppedUp      = "topped-up"
+	TypeDiluted       = "diluted"
+	TypeNearingExpiry = "nearing-expiry"
+	TypeExpired       = "expired"
+)
+
+// Event is the lifecycle event of a batch owned by the node.
+type Event struct {
+	Type      string         `json:"type"`
+	BatchID   string         `json:"batchID"`
+	Amount    *bigint.BigInt `json:"amount,omitempty"`
+	Depth     uint8          `json:"depth,omitempty"`
+	BatchTTL  int64          `json:"batchTTL,omitempty"`
+	Timestamp int64          `json:"timestamp"`
+}
+
+// Subscriber subscribes to the batch events.
+type Subscriber interface {
+	// SubscribeBatchEvents returns the channel of the batch events
+	// and the function which cancels the subscription.
+	SubscribeBatchEvents() (<-chan Event, func())
+}
+
+// Options are the options of the Notifier.
+type Options struct {
+	// Webhooks are the URLs the events are posted to as JSON.
+	Webhooks []string
+	// BlockTime is the duration of a block used for the estimation
+	// of the remaining time to live of the batches.
+	BlockTime time.Duration
+	// ExpiryWarning is the remaining time to live of the batches
+	// for which the nearing expiry event is emitted.
+	ExpiryWarning time.Duration
+	// CheckInterval is the interval of the checks of the
+	// remaining time to live of the batches.
+	CheckInterval time.Duration
+}
+
+var (
+	_ Subscriber                 = (*Notifier)(nil)
+	_ postage.BatchEventListener = (*Notifier)(nil)
+	_ postage.StampExpiryHandler = (*Notifier)(nil)
+)
+
+// Notifier emits the lifecycle events of the batches owned by the node.
+type Notifier struct {
+	logger     log.Logger
+	post       postage.Service
+	batchStore postage.Storer
+	opts       Options
+	client     *http.Client
+
+	mtx         sync.Mutex
+	subscribers map[chan Event]struct{}
+
+	// warned are the IDs of the batches for which the
+	// nearing expiry event is already emitted
+	warned map[string]struct{}
+
+	queue  chan Event
+	quit   chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New constructs a new Notifier. The batches owned by the node are the
+// batches of the stamp issuers of the postage service.
+func New(post postage.Service, batchStore postage.Storer, logger log.Logger, o Options) *Notifier {
+	if o.ExpiryWarning <= 0 {
+		o.ExpiryWarning = DefaultExpiryWarning
+	}
+	if o.CheckInterval <= 0 {
+		o.CheckInterval = DefaultCheckInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n := &Notifier{
+		logger:      logger.WithName(loggerName).Register(),
+		post:        post,
+		batchStore:  batchStore,
+		opts:        o,
+		client:      &http.Client{Timeout: webhookTimeout},
+		subscribers: make(map[chan Event]struct{}),
+		warned:      make(map[string]struct{}),
+		queue:       make(chan Event, queueSize),
+		quit:        make(chan struct{}),
+		cancel:      cancel,
+	}
+
+	n.wg.Add(2)
+	go n.run(ctx)
+	go n.checkExpiry()
+
+	return n
+}
+
+// HandleCreate implements postage.BatchEventListener. It is
+// only called for the batches owned by the node.
+func (n *Notifier) HandleCreate(b *postage.Batch, amount *big.Int) error {
+	n.emit(Event{
+		Type:    TypeCreated,
+		BatchID: hex.EncodeToString(b.ID),
+		Amount:  bigint.Wrap(new(big.Int).Set(amount)),
+		Depth:   b.Depth,
+	})
+	return nil
+}
+
+// HandleTopUp implements postage.BatchEventListener. It is
+// only called for the batches owned by the node.
+func (n *Notifier) HandleTopUp(id []byte, amount *big.Int) {
+	n.mtx.Lock()
+	delete(n.warned, string(id))
+	n.mtx.Unlock()
+
+	n.emit(Event{
+		Type:    TypeToppedUp,
+		BatchID: hex.EncodeToString(id),
+		Amount:  bigint.Wrap(new(big.Int).Set(amount)),
+	})
+}
+
+// HandleDepthIncrease implements postage.BatchEventListener. It
+// is only called for the batches owned by the node.
+func (n *Notifier) HandleDepthIncrease(id []byte, depth uint8) {
+	n.emit(Event{
+		Type:    TypeDiluted,
+		BatchID: hex.EncodeToString(id),
+		Depth:   depth,
+	})
+}
+
+// HandleStampExpiry implements postage.StampExpiryHandler. The
+// expiry of the batches not owned by the node is ignored.
+func (n *Notifier) HandleStampExpiry(id []byte) {
+	if !n.owned(id) {
+		return
+	}
+
+	n.mtx.Lock()
+	delete(n.warned, string(id))
+	n.mtx.Unlock()
+
+	n.emit(Event{
+		Type:    TypeExpired,
+		BatchID: hex.EncodeToString(id),
+	})
+}
+
+// SubscribeBatchEvents implements Subscriber.SubscribeBatchEvents method.
+func (n *Notifier) SubscribeBatchEvents() (<-chan Event, func()) {
+	c := make(chan Event, subscriberBuffer)
+
+	n.mtx.Lock()
+	n.subscribers[c] = struct{}{}
+	n.mtx.Unlock()
+
+	var once sync.Once
+	return c, func() {
+		once.Do(func() {
+			n.mtx.Lock()
+			delete(n.subscribers, c)
+			n.mtx.Unlock()
+		})
+	}
+}
+
+// Close stops the notifier.
+func (n *Notifier) Close() error {
+	close(n.quit)
+	n.cancel()
+	n.wg.Wait()
+	return nil
+}
+
+// emit queues the event, so that the caller is not blocked.
+func (n *Notifier) emit(ev Event) {
+	ev.Timestamp = time.Now().Unix()
+
+	select {
+	case n.queue <- ev:
+	case <-n.quit:
+	default:
+		n.logger.Warning("event queue full, dropping the batch event", "type", ev.Type, "batch_id", ev.BatchID)
+	}
+}
+
+func (n *Notifier) owned(id []byte) bool {
+	for _, issuer := range n.post.StampIssuers() {
+		if bytes.Equal(id, issuer.ID()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *Notifier) run(ctx context.Context) {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case <-n.quit:
+			return
+		case ev := <-n.queue:
+			n.notify(ctx, ev)
+		}
+	}
+}
+
+// checkExpiry periodically emits the nearing expiry events of the
+// batches with the remaining time to live below the warning.
+func (n *Notifier) checkExpiry() {
+	defer n.wg.Done()
+
+	ticker := time.NewTicker(n.opts.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.quit:
+			return
+		case <-ticker.C:
+		}
+
+		for _, issuer := range n.post.StampIssuers() {
+			if issuer.Expired() {
+				continue
+			}
+			batch, err := n.batchStore.Get(issuer.ID())
+			if err != nil {
+				n.logger.Debug("get batch failed", "batch_id", hex.EncodeToString(issuer.ID()), "error", err)
+				continue
+			}
+			ttl, ok := n.ttl(batch)
+			if !ok {
+				continue
+			}
+
+			key := string(issuer.ID())
+			n.mtx.Lock()
+			_, warned := n.warned[key]
+			if ttl >= n.opts.ExpiryWarning {
+				// the batch was topped up or the price decreased
+				delete(n.warned, key)
+			} else if !warned {
+				n.warned[key] = struct{}{}
+			}
+			n.mtx.Unlock()
+
+			if ttl < n.opts.ExpiryWarning && !warned {
+				n.emit(Event{
+					Type:     TypeNearingExpiry,
+					BatchID:  hex.EncodeToString(issuer.ID()),
+					BatchTTL: int64(ttl / time.Second),
+				})
+			}
+		}
+	}
+}
+
+// ttl estimates the remaining time to live of the batch. The
+// false result signals that the batch never expires.
+func (n *Notifier) ttl(batch *postage.Batch) (time.Duration, bool) {
+	state := n.batchStore.GetChainState()
+	if n.opts.BlockTime <= 0 || state == nil || state.CurrentPrice == nil || len(state.CurrentPrice.Bits()) == 0 {
+		return 0, false
+	}
+
+	blocks := new(big.Int).Sub(batch.Value, state.TotalAmount)
+	blocks = blocks.Div(blocks, state.CurrentPrice)
+	if blocks.Sign() < 0 {
+		return 0, true
+	}
+	return time.Duration(blocks.Int64()) * n.opts.BlockTime, true
+}
+
+func (n *Notifier) notify(ctx context.Context, ev Event) {
+	n.mtx.Lock()
+	for c := range n.subscribers {
+		select {
+		case c <- ev:
+		default:
+			n.logger.Debug("slow batch event subscriber, dropping the event", "type", ev.Type, "batch_id", ev.BatchID)
+		}
+	}
+	n.mtx.Unlock()
+
+	if len(n.opts.Webhooks) == 0 {
+		return
+	}
+	body, err := json.Marshal(ev)
+	if err != nil {
+		n.logger.Error(err, "marshal batch event")
+		return
+	}
+	for _, url := range n.opts.Webhooks {
+		if err := n.postWebhook(ctx, url, body); err != nil {
+			n.logger.Warning("batch event webhook failed", "url", url, "error", err)
+		}
+	}
+}
+
+func (n *Notifier) postWebhook(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
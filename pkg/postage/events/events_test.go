@@ -0,0 +1,131 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package events_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	mockbatchstore "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
+	"github.com/ethersphere/bee/pkg/postage/events"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+	"github.com/ethersphere/bee/pkg/util/testutil"
+)
+
+func receive(t *testing.T, c <-chan events.Event, wantType, wantBatchID string) events.Event {
+	t.Helper()
+
+	select {
+	case ev := <-c:
+		if ev.Type != wantType || ev.BatchID != wantBatchID {
+			t.Fatalf("got %s event of batch %s, want %s event of batch %s", ev.Type, ev.BatchID, wantType, wantBatchID)
+		}
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for the %s event", wantType)
+	}
+	return events.Event{}
+}
+
+func TestNotifier(t *testing.T) {
+	t.Parallel()
+
+	var (
+		batch  = postagetesting.MustNewBatch()
+		id     = hex.EncodeToString(batch.ID)
+		issuer = postage.NewStampIssuer("", "", batch.ID, big.NewInt(3), batch.Depth, batch.BucketDepth, 1000, true)
+		hookC  = make(chan events.Event, 8)
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev events.Event
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		hookC <- ev
+	}))
+	t.Cleanup(srv.Close)
+
+	notifier := events.New(mockpost.New(mockpost.WithIssuer(issuer)), mockbatchstore.New(), log.Noop, events.Options{
+		Webhooks: []string{srv.URL},
+	})
+	testutil.CleanupCloser(t, notifier)
+
+	c, cancel := notifier.SubscribeBatchEvents()
+	defer cancel()
+
+	if err := notifier.HandleCreate(batch, big.NewInt(10)); err != nil {
+		t.Fatal(err)
+	}
+	notifier.HandleTopUp(batch.ID, big.NewInt(20))
+	notifier.HandleDepthIncrease(batch.ID, batch.Depth+1)
+	// the expiry of the batches of the other owners is ignored
+	notifier.HandleStampExpiry(postagetesting.MustNewID())
+	notifier.HandleStampExpiry(batch.ID)
+
+	for _, src := range []<-chan events.Event{c, hookC} {
+		if ev := receive(t, src, events.TypeCreated, id); ev.Amount.Cmp(big.NewInt(10)) != 0 || ev.Depth != batch.Depth {
+			t.Fatalf("got created event %+v, want amount 10 and depth %d", ev, batch.Depth)
+		}
+		if ev := receive(t, src, events.TypeToppedUp, id); ev.Amount.Cmp(big.NewInt(20)) != 0 {
+			t.Fatalf("got topped up event %+v, want amount 20", ev)
+		}
+		if ev := receive(t, src, events.TypeDiluted, id); ev.Depth != batch.Depth+1 {
+			t.Fatalf("got diluted event %+v, want depth %d", ev, batch.Depth+1)
+		}
+		receive(t, src, events.TypeExpired, id)
+	}
+}
+
+func TestNotifierNearingExpiry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		batch  = postagetesting.MustNewBatch()
+		issuer = postage.NewStampIssuer("", "", batch.ID, big.NewInt(3), batch.Depth, batch.BucketDepth, 1000, true)
+		state  = &postage.ChainState{
+			Block:        1000,
+			TotalAmount:  big.NewInt(100),
+			CurrentPrice: big.NewInt(10),
+		}
+	)
+	// the batch lives for 10 more blocks
+	batch.Value = big.NewInt(200)
+
+	notifier := events.New(
+		mockpost.New(mockpost.WithIssuer(issuer)),
+		mockbatchstore.New(mockbatchstore.WithBatch(batch), mockbatchstore.WithChainState(state), mockbatchstore.WithAcceptAllExistsFunc()),
+		log.Noop,
+		events.Options{
+			BlockTime:     5 * time.Second,
+			ExpiryWarning: time.Minute,
+			CheckInterval: 10 * time.Millisecond,
+		},
+	)
+	testutil.CleanupCloser(t, notifier)
+
+	c, cancel := notifier.SubscribeBatchEvents()
+	defer cancel()
+
+	if ev := receive(t, c, events.TypeNearingExpiry, hex.EncodeToString(batch.ID)); ev.BatchTTL != 50 {
+		t.Fatalf("got batch ttl %d, want 50", ev.BatchTTL)
+	}
+
+	// the event is emitted only once
+	select {
+	case ev := <-c:
+		t.Fatalf("got unexpected event %+v", ev)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -125,7 +125,63 @@ type BatchEventListener interface {
 	HandleDepthIncrease(id []byte, newDepth uint8)
 }
 
+// BatchEventListeners is a BatchEventListener which also notifies
+// the other listeners about the batch events.
+type BatchEventListeners struct {
+	BatchEventListener
+	Listeners []BatchEventListener
+}
+
+// HandleCreate implements BatchEventListener.HandleCreate method.
+func (el *BatchEventListeners) HandleCreate(b *Batch, amount *big.Int) error {
+	if err := el.BatchEventListener.HandleCreate(b, amount); err != nil {
+		return err
+	}
+	for _, l := range el.Listeners {
+		if err := l.HandleCreate(b, amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleTopUp implements BatchEventListener.HandleTopUp method.
+func (el *BatchEventListeners) HandleTopUp(id []byte, amount *big.Int) {
+	el.BatchEventListener.HandleTopUp(id, amount)
+	for _, l := range el.Listeners {
+		l.HandleTopUp(id, amount)
+	}
+}
+
+// HandleDepthIncrease implements BatchEventListener.HandleDepthIncrease method.
+func (el *BatchEventListeners) HandleDepthIncrease(id []byte, newDepth uint8) {
+	el.BatchEventListener.HandleDepthIncrease(id, newDepth)
+	for _, l := range el.Listeners {
+		l.HandleDepthIncrease(id, newDepth)
+	}
+}
+
 type BatchExpiryHandler interface {
 	HandleStampExpiry([]byte)
 	SetExpired() error
 }
+
+// StampExpiryHandler is notified about the IDs of the expired batches.
+type StampExpiryHandler interface {
+	HandleStampExpiry([]byte)
+}
+
+// ExpiryHandlers is a BatchExpiryHandler which also notifies
+// the stamp expiry handlers about the expired batches.
+type ExpiryHandlers struct {
+	BatchExpiryHandler
+	Handlers []StampExpiryHandler
+}
+
+// HandleStampExpiry implements BatchExpiryHandler.HandleStampExpiry method.
+func (eh *ExpiryHandlers) HandleStampExpiry(id []byte) {
+	eh.BatchExpiryHandler.HandleStampExpiry(id)
+	for _, h := range eh.Handlers {
+		h.HandleStampExpiry(id)
+	}
+}
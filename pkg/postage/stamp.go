@@ -57,6 +57,11 @@ func (s *Stamp) Index() []byte {
 	return s.index
 }
 
+// BucketIndex returns the collision bucket and the within-bucket index of the stamp.
+func (s *Stamp) BucketIndex() (bucket, index uint32) {
+	return bytesToIndex(s.index)
+}
+
 // Sig returns the signature of the stamp by the user
 func (s *Stamp) Sig() []byte {
 	return s.sig
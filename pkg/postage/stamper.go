@@ -7,6 +7,8 @@ package postage
 import (
 	"encoding/binary"
 	"errors"
+	"runtime"
+	"sync"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/crypto"
@@ -23,6 +25,27 @@ type Stamper interface {
 	Stamp(swarm.Address) (*Stamp, error)
 }
 
+// BatchStamper issues the stamps of many chunks at once. The stamps are
+// reserved one by one, so that the full bucket is reported for the chunk
+// which does not fit in it, and are signed later all together.
+type BatchStamper interface {
+	Stamper
+	// Reserve returns the stamp of the chunk with the address,
+	// which is not signed yet.
+	Reserve(swarm.Address) (*Stamp, error)
+	// Sign signs the reserved stamps of the chunks with the addresses
+	// by a pool of workers.
+	Sign(addrs []swarm.Address, stamps []*Stamp) error
+	// Release returns the indices of the reserved stamps which are not
+	// signed or not used. An index is returned only if no later index was
+	// issued from its collision bucket, so that the stamps are released
+	// in the reverse order of their reservations.
+	Release(stamps []*Stamp)
+}
+
+// signWorkers is the number of the workers signing the stamps at once.
+var signWorkers = runtime.NumCPU()
+
 // stamper connects a stampissuer with a signer.
 // A stamper is created for each upload session.
 type stamper struct {
@@ -38,20 +61,88 @@ func NewStamper(st *StampIssuer, signer crypto.Signer) Stamper {
 // Stamp takes chunk, see if the chunk can included in the batch and
 // signs it with the owner of the batch of this Stamp issuer.
 func (st *stamper) Stamp(addr swarm.Address) (*Stamp, error) {
-	index, err := st.issuer.inc(addr)
+	stamp, err := st.Reserve(addr)
 	if err != nil {
 		return nil, err
 	}
-	ts := timestamp()
-	toSign, err := toSignDigest(addr.Bytes(), st.issuer.data.BatchID, index, ts)
+	if err := st.sign(addr, stamp); err != nil {
+		return nil, err
+	}
+	return stamp, nil
+}
+
+// Reserve implements the BatchStamper interface.
+func (st *stamper) Reserve(addr swarm.Address) (*Stamp, error) {
+	index, err := st.issuer.inc(addr)
 	if err != nil {
 		return nil, err
 	}
+	return NewStamp(st.issuer.data.BatchID, index, timestamp(), nil), nil
+}
+
+// Sign implements the BatchStamper interface.
+func (st *stamper) Sign(addrs []swarm.Address, stamps []*Stamp) error {
+	workers := signWorkers
+	if len(addrs) < workers {
+		workers = len(addrs)
+	}
+	if workers <= 1 {
+		for i, addr := range addrs {
+			if err := st.sign(addr, stamps[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var (
+		wg    sync.WaitGroup
+		next  = make(chan int)
+		errMu sync.Mutex
+		err   error
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				if e := st.sign(addrs[i], stamps[i]); e != nil {
+					errMu.Lock()
+					if err == nil {
+						err = e
+					}
+					errMu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range addrs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return err
+}
+
+// Release implements the BatchStamper interface.
+func (st *stamper) Release(stamps []*Stamp) {
+	for i := len(stamps) - 1; i >= 0; i-- {
+		st.issuer.dec(stamps[i].index)
+	}
+}
+
+// sign signs the reserved stamp of the chunk with the address.
+func (st *stamper) sign(addr swarm.Address, stamp *Stamp) error {
+	toSign, err := toSignDigest(addr.Bytes(), stamp.batchID, stamp.index, stamp.timestamp)
+	if err != nil {
+		return err
+	}
 	sig, err := st.signer.Sign(toSign)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return NewStamp(st.issuer.data.BatchID, index, ts, sig), nil
+	stamp.sig = sig
+	return nil
 }
 
 func timestamp() []byte {
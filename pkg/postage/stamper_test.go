@@ -103,6 +103,63 @@ func TestStamperStamping(t *testing.T) {
 		}
 	})
 
+	// tests that the reserved stamps are valid once signed in a batch
+	t.Run("batch signing", func(t *testing.T) {
+		st := newTestStampIssuer(t, 1000)
+		stamper := postage.NewStamper(st, signer).(postage.BatchStamper)
+
+		addrs := make([]swarm.Address, 100)
+		stamps := make([]*postage.Stamp, len(addrs))
+		for i := range addrs {
+			addrs[i] = swarm.RandAddress(t)
+			stamps[i], err = stamper.Reserve(addrs[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := stamper.Sign(addrs, stamps); err != nil {
+			t.Fatal(err)
+		}
+		for i, stamp := range stamps {
+			if err := stamp.Valid(addrs[i], owner, 12, 8, true); err != nil {
+				t.Fatalf("stamp %d: expected no error, got %v", i, err)
+			}
+		}
+	})
+
+	// tests that the released stamps are reserved again
+	t.Run("release", func(t *testing.T) {
+		st := newTestStampIssuer(t, 1000)
+		stamper := postage.NewStamper(st, signer).(postage.BatchStamper)
+
+		addr := swarm.RandAddress(t)
+		reserve := func() *postage.Stamp {
+			t.Helper()
+			stamp, err := stamper.Reserve(addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return stamp
+		}
+		first, second := reserve(), reserve()
+
+		// the earlier index is not released while the later one is issued
+		stamper.Release([]*postage.Stamp{first})
+		if _, index := reserve().BucketIndex(); index != 2 {
+			t.Fatalf("got index %d, want 2", index)
+		}
+
+		third := reserve()
+		stamper.Release([]*postage.Stamp{first, second, third})
+		bucket, _ := first.BucketIndex()
+		if got := st.Buckets()[bucket]; got != 3 {
+			t.Fatalf("got bucket count %d, want 3", got)
+		}
+		if _, index := reserve().BucketIndex(); index != 3 {
+			t.Fatalf("got index %d, want 3", index)
+		}
+	})
+
 	// tests return with ErrOwnerMismatch
 	t.Run("owner mismatch", func(t *testing.T) {
 		owner[0] ^= 0xff // bitflip the owner first byte, this case must come last!
@@ -85,6 +85,28 @@ func (si *StampIssuer) inc(addr swarm.Address) ([]byte, error) {
 	return indexToBytes(b, bucketCount), nil
 }
 
+// dec returns the index of the stamp which was not used to its collision
+// bucket, if it is the last index issued from the bucket.
+func (si *StampIssuer) dec(index []byte) {
+	si.bucketMu.Lock()
+	defer si.bucketMu.Unlock()
+
+	b, i := bytesToIndex(index)
+	if si.data.Buckets[b] != i+1 {
+		return
+	}
+	si.data.Buckets[b]--
+
+	if si.data.Buckets[b]+1 == si.data.MaxBucketCount {
+		si.data.MaxBucketCount = 0
+		for _, c := range si.data.Buckets {
+			if c > si.data.MaxBucketCount {
+				si.data.MaxBucketCount = c
+			}
+		}
+	}
+}
+
 // toBucket calculates the index of the collision bucket for a swarm address
 // bucket index := collision bucket depth number of bits as bigendian uint32
 func toBucket(depth uint8, addr swarm.Address) uint32 {
@@ -0,0 +1,13 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package profiling periodically collects the CPU, heap and mutex profiles
+// of the node and pushes them to a continuous profiling server using the
+// ingestion API of Pyroscope. The pprof goroutine labels, such as the
+// subsystem label set by the protocol handlers and the upload pipeline, are
+// preserved in the CPU profiles, so that the regressions of the particular
+// subsystems can be tracked over the versions. Parca and other pull based
+// servers can scrape the same profiles from the pprof endpoints of the
+// debug API.
+package profiling
@@ -0,0 +1,21 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package profiling
+
+import (
+	"runtime/pprof"
+)
+
+// SubsystemLabel is the pprof label key of the subsystem
+// which is executed by the goroutine.
+const SubsystemLabel = "subsystem"
+
+// Labels returns the pprof label set with the subsystem label and the
+// additional key value pairs. It is meant to be used with pprof.Do, so
+// that the CPU profile samples of the goroutine and of the goroutines
+// started by it are labeled.
+func Labels(subsystem string, args ...string) pprof.LabelSet {
+	return pprof.Labels(append([]string{SubsystemLabel, subsystem}, args...)...)
+}
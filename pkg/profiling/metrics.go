@@ -0,0 +1,38 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package profiling
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	Uploaded     *prometheus.CounterVec // number of profiles uploaded per type
+	UploadErrors *prometheus.CounterVec // number of failed profile uploads per type
+}
+
+func newMetrics() metrics {
+	subsystem := "profiling"
+
+	return metrics{
+		Uploaded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "profiles_uploaded",
+			Help:      "Number of profiles uploaded to the profiling server.",
+		}, []string{"type"}),
+		UploadErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "upload_errors",
+			Help:      "Number of profiles which were not collected or uploaded.",
+		}, []string{"type"}),
+	}
+}
+
+func (p *Profiler) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(p.metrics)
+}
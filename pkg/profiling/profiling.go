@@ -0,0 +1,294 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "profiling"
+
+// Profile types which can be collected.
+const (
+	ProfileCPU   = "cpu"
+	ProfileHeap  = "heap"
+	ProfileMutex = "mutex"
+)
+
+const (
+	defaultInterval             = 15 * time.Second
+	defaultMutexProfileFraction = 5
+	uploadTimeout               = 10 * time.Second
+	cpuSampleRate               = 100 // the default sampling rate of the runtime in Hz
+)
+
+// ErrUnknownProfile is returned by New when the options contain
+// an unsupported profile type.
+var ErrUnknownProfile = errors.New("unknown profile type")
+
+// Options are the options of the Profiler.
+type Options struct {
+	// Endpoint is the URL of the profiling server.
+	Endpoint string
+	// AppName is the application name under which the profiles are stored.
+	AppName string
+	// Labels are the static labels of the profiles, such as the version.
+	Labels map[string]string
+	// Types are the profile types to collect. All types are
+	// collected if none is provided.
+	Types []string
+	// Interval is the duration of the CPU profiles and
+	// the period of the heap and mutex profiles.
+	Interval time.Duration
+	// MutexProfileFraction is the rate of the reported mutex contention
+	// events, which is set on the runtime when the mutex profile is collected.
+	MutexProfileFraction int
+}
+
+// Profiler collects the profiles in the configured intervals
+// and uploads them to the profiling server.
+type Profiler struct {
+	logger  log.Logger
+	client  *http.Client
+	url     string
+	name    string
+	opts    Options
+	cpu     bool
+	heap    bool
+	mutex   bool
+	metrics metrics
+
+	// previous snapshots of the cumulative profiles, which are sent along
+	// the current ones, so that the server can compute the deltas
+	prevHeap  []byte
+	prevMutex []byte
+
+	prevMutexFraction int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a new Profiler which starts collecting the profiles.
+func New(o Options, logger log.Logger) (*Profiler, error) {
+	u, err := url.Parse(o.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("endpoint: unsupported scheme %q", u.Scheme)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/ingest"
+
+	if o.Interval <= 0 {
+		o.Interval = defaultInterval
+	}
+	if o.MutexProfileFraction <= 0 {
+		o.MutexProfileFraction = defaultMutexProfileFraction
+	}
+	if o.AppName == "" {
+		o.AppName = "bee"
+	}
+	if len(o.Types) == 0 {
+		o.Types = []string{ProfileCPU, ProfileHeap, ProfileMutex}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Profiler{
+		logger:  logger.WithName(loggerName).Register(),
+		client:  &http.Client{Timeout: uploadTimeout},
+		url:     u.String(),
+		name:    appName(o.AppName, o.Labels),
+		opts:    o,
+		metrics: newMetrics(),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	for _, t := range o.Types {
+		switch t {
+		case ProfileCPU:
+			p.cpu = true
+		case ProfileHeap:
+			p.heap = true
+		case ProfileMutex:
+			p.mutex = true
+		default:
+			cancel()
+			return nil, fmt.Errorf("%w: %s", ErrUnknownProfile, t)
+		}
+	}
+
+	if p.mutex {
+		p.prevMutexFraction = runtime.SetMutexProfileFraction(o.MutexProfileFraction)
+	}
+
+	p.wg.Add(1)
+	go p.run()
+
+	return p, nil
+}
+
+// Close stops the collection of the profiles.
+func (p *Profiler) Close() error {
+	p.cancel()
+	p.wg.Wait()
+	p.client.CloseIdleConnections()
+
+	if p.mutex {
+		runtime.SetMutexProfileFraction(p.prevMutexFraction)
+	}
+	return nil
+}
+
+func (p *Profiler) run() {
+	defer p.wg.Done()
+
+	for {
+		from := time.Now()
+
+		cpu := new(bytes.Buffer)
+		cpuStarted := false
+		if p.cpu {
+			// the CPU profile can not be collected while another one
+			// is in progress, for example on the pprof debug endpoint
+			if err := pprof.StartCPUProfile(cpu); err != nil {
+				p.metrics.UploadErrors.WithLabelValues(ProfileCPU).Inc()
+				p.logger.Debug("start cpu profile failed", "error", err)
+			} else {
+				cpuStarted = true
+			}
+		}
+
+		select {
+		case <-p.ctx.Done():
+			if cpuStarted {
+				pprof.StopCPUProfile()
+			}
+			return
+		case <-time.After(p.opts.Interval):
+		}
+
+		until := time.Now()
+		if cpuStarted {
+			pprof.StopCPUProfile()
+			p.upload(ProfileCPU, from, until, cpu.Bytes(), nil)
+		}
+		if p.heap {
+			p.prevHeap = p.snapshot(ProfileHeap, "heap", from, until, p.prevHeap)
+		}
+		if p.mutex {
+			p.prevMutex = p.snapshot(ProfileMutex, "mutex", from, until, p.prevMutex)
+		}
+	}
+}
+
+// snapshot writes the cumulative runtime profile and uploads it together
+// with the previous snapshot. It returns the current snapshot.
+func (p *Profiler) snapshot(typ, name string, from, until time.Time, prev []byte) []byte {
+	buf := new(bytes.Buffer)
+	if err := pprof.Lookup(name).WriteTo(buf, 0); err != nil {
+		p.metrics.UploadErrors.WithLabelValues(typ).Inc()
+		p.logger.Debug("write profile failed", "type", typ, "error", err)
+		return prev
+	}
+	p.upload(typ, from, until, buf.Bytes(), prev)
+	return buf.Bytes()
+}
+
+// upload posts the profile in the pprof format to the ingestion endpoint.
+func (p *Profiler) upload(typ string, from, until time.Time, profile, prev []byte) {
+	if err := p.post(from, until, profile, prev); err != nil {
+		p.metrics.UploadErrors.WithLabelValues(typ).Inc()
+		p.logger.Debug("upload profile failed", "type", typ, "error", err)
+		return
+	}
+	p.metrics.Uploaded.WithLabelValues(typ).Inc()
+}
+
+func (p *Profiler) post(from, until time.Time, profile, prev []byte) error {
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+	if err := writePart(w, "profile", profile); err != nil {
+		return err
+	}
+	if prev != nil {
+		if err := writePart(w, "prev_profile", prev); err != nil {
+			return err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	q := url.Values{}
+	q.Set("name", p.name)
+	q.Set("from", strconv.FormatInt(from.Unix(), 10))
+	q.Set("until", strconv.FormatInt(until.Unix(), 10))
+	q.Set("format", "pprof")
+	q.Set("spyName", "gospy")
+	q.Set("sampleRate", strconv.Itoa(cpuSampleRate))
+
+	req, err := http.NewRequestWithContext(p.ctx, http.MethodPost, p.url+"?"+q.Encode(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
+
+func writePart(w *multipart.Writer, name string, data []byte) error {
+	part, err := w.CreateFormFile(name, name+".pprof")
+	if err != nil {
+		return err
+	}
+	_, err = part.Write(data)
+	return err
+}
+
+// appName returns the application name with the labels in
+// the form app{key=value,...}, which is the ingestion format.
+func appName(app string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return app
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+labels[k])
+	}
+	return app + "{" + strings.Join(pairs, ",") + "}"
+}
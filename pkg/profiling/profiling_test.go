@@ -0,0 +1,127 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package profiling_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime/pprof"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/profiling"
+)
+
+type upload struct {
+	name     string
+	hasPrev  bool
+	profiles int
+}
+
+func TestProfiler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		uploads []upload
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ingest" || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("format") != "pprof" || q.Get("from") == "" || q.Get("until") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		uploads = append(uploads, upload{
+			name:     q.Get("name"),
+			hasPrev:  len(r.MultipartForm.File["prev_profile"]) > 0,
+			profiles: len(r.MultipartForm.File["profile"]),
+		})
+		mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+
+	p, err := profiling.New(profiling.Options{
+		Endpoint: srv.URL,
+		AppName:  "bee",
+		Labels:   map[string]string{"version": "1.0.0", "network": "test"},
+		Types:    []string{profiling.ProfileHeap, profiling.ProfileMutex},
+		Interval: 50 * time.Millisecond,
+	}, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		n := len(uploads)
+		mu.Unlock()
+		if n >= 4 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d uploads, want at least 4", n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for i, u := range uploads {
+		if want := "bee{network=test,version=1.0.0}"; u.name != want {
+			t.Errorf("upload %d: got name %q, want %q", i, u.name, want)
+		}
+		if u.profiles != 1 {
+			t.Errorf("upload %d: got %d profiles, want 1", i, u.profiles)
+		}
+		// the first heap and mutex snapshots have no previous snapshots
+		if wantPrev := i >= 2; u.hasPrev != wantPrev {
+			t.Errorf("upload %d: got previous profile %v, want %v", i, u.hasPrev, wantPrev)
+		}
+	}
+}
+
+func TestProfilerUnknownType(t *testing.T) {
+	t.Parallel()
+
+	_, err := profiling.New(profiling.Options{
+		Endpoint: "http://localhost:4040",
+		Types:    []string{"goroutine"},
+	}, log.Noop)
+	if !errors.Is(err, profiling.ErrUnknownProfile) {
+		t.Fatalf("got error %v, want %v", err, profiling.ErrUnknownProfile)
+	}
+}
+
+func TestLabels(t *testing.T) {
+	t.Parallel()
+
+	pprof.Do(context.Background(), profiling.Labels("pushsync", "stream", "pushsync"), func(ctx context.Context) {
+		if v, ok := pprof.Label(ctx, profiling.SubsystemLabel); !ok || v != "pushsync" {
+			t.Errorf("got subsystem label %q, want %q", v, "pushsync")
+		}
+		if v, ok := pprof.Label(ctx, "stream"); !ok || v != "pushsync" {
+			t.Errorf("got stream label %q, want %q", v, "pushsync")
+		}
+	})
+}
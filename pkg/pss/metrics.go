@@ -12,6 +12,11 @@ import (
 type metrics struct {
 	TotalMessagesSentCounter prometheus.Counter
 	MessageMiningDuration    prometheus.Gauge
+
+	CoverMessagesSentCounter    prometheus.Counter
+	CoverMessagesSkippedCounter prometheus.Counter
+
+	ReceivedMessagesDroppedCounter prometheus.Counter
 }
 
 func newMetrics() metrics {
@@ -30,6 +35,24 @@ func newMetrics() metrics {
 			Name:      "mining_duration",
 			Help:      "Time duration to mine a message.",
 		}),
+		CoverMessagesSentCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_cover_message_sent",
+			Help:      "Total cover messages sent.",
+		}),
+		CoverMessagesSkippedCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_cover_message_skipped",
+			Help:      "Total cover messages skipped because of no sent messages, the exhausted budget or the memory pressure.",
+		}),
+		ReceivedMessagesDroppedCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_received_message_dropped",
+			Help:      "Total received messages dropped under the memory pressure.",
+		}),
 	}
 }
 
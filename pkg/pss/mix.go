@@ -0,0 +1,198 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pss
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/membudget"
+	"github.com/ethersphere/bee/pkg/postage"
+)
+
+// DefaultCoverBudget is the default maximum number
+// of the cover messages sent in an hour.
+const DefaultCoverBudget = 60
+
+// coverBudgetPeriod is the period of the cover messages budget.
+const coverBudgetPeriod = time.Hour
+
+var errClosed = errors.New("pss closed")
+
+// Options are the options of the protection of the sent messages against the
+// traffic analysis and of the memory use. The zero value disables the delays,
+// the cover traffic and the memory budget.
+type Options struct {
+	// MinDelay and MaxDelay bound the uniformly random delay
+	// of the delivery of every sent message.
+	MinDelay time.Duration
+	MaxDelay time.Duration
+	// CoverInterval is the mean interval of the cover messages, which are
+	// sent at the exponentially distributed intervals. Zero disables the
+	// cover traffic.
+	CoverInterval time.Duration
+	// CoverBudget is the maximum number of the cover messages sent in an
+	// hour, which bounds the bandwidth and the postage used by them.
+	CoverBudget int
+	// MemoryBudget is the memory budget from which the memory of the mining
+	// and the unwrapping of the messages is acquired. The sent messages wait
+	// for the memory, the received and the cover messages are dropped under
+	// the memory pressure.
+	MemoryBudget *membudget.Budget
+}
+
+// cover holds the state of the cover traffic. The cover messages are stamped
+// with the stamper of the most recent message and mined for the targets of the
+// same length, so that they are not distinguishable from the real messages by
+// the batch of the stamp or the mining effort.
+type cover struct {
+	mu          sync.Mutex
+	stamper     postage.Stamper
+	targetLen   int
+	sent        int
+	periodStart time.Time
+}
+
+// mimic records the properties of the sent message.
+func (c *cover) mimic(stamper postage.Stamper, targets Targets) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stamper = stamper
+	if len(targets) > 0 {
+		c.targetLen = len(targets[0])
+	}
+}
+
+// take consumes the budget of a cover message and returns the stamper and
+// the target length of it. False is returned if no message was sent yet or
+// the budget of the period is exhausted.
+func (c *cover) take(budget int, now time.Time) (postage.Stamper, int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stamper == nil || c.targetLen == 0 {
+		return nil, 0, false
+	}
+	if now.Sub(c.periodStart) >= coverBudgetPeriod {
+		c.periodStart = now
+		c.sent = 0
+	}
+	if c.sent >= budget {
+		return nil, 0, false
+	}
+	c.sent++
+	return c.stamper, c.targetLen, true
+}
+
+// delay delays the delivery of a message by a random duration
+// between the minimal and the maximal delay.
+func (p *pss) delay(ctx context.Context) error {
+	d := p.opts.MinDelay
+	if span := p.opts.MaxDelay - p.opts.MinDelay; span > 0 {
+		d += time.Duration(randUint64() % uint64(span+1))
+	}
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.quit:
+		return errClosed
+	}
+}
+
+// coverLoop sends the cover messages at the exponentially distributed
+// intervals with the configured mean, so that the cover messages form a
+// Poisson process.
+func (p *pss) coverLoop() {
+	defer p.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-p.quit
+		cancel()
+	}()
+
+	for {
+		// the uniform variate in (0, 1]
+		u := float64(randUint64()>>11+1) / (1 << 53)
+		timer := time.NewTimer(time.Duration(-math.Log(u) * float64(p.opts.CoverInterval)))
+
+		select {
+		case <-p.quit:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := p.sendCover(ctx); err != nil {
+			p.logger.Debug("send cover message failed", "error", err)
+		}
+	}
+}
+
+// sendCover sends a cover message, which is not decryptable by anyone,
+// to a random target.
+func (p *pss) sendCover(ctx context.Context) error {
+	stamper, targetLen, ok := p.cover.take(p.opts.CoverBudget, time.Now())
+	if !ok {
+		p.metrics.CoverMessagesSkippedCounter.Inc()
+		return nil
+	}
+	if !p.opts.MemoryBudget.TryAcquire(messageMemory) {
+		p.metrics.CoverMessagesSkippedCounter.Inc()
+		return nil
+	}
+	defer p.opts.MemoryBudget.Release(messageMemory)
+
+	target := make([]byte, targetLen)
+	if _, err := rand.Read(target); err != nil {
+		return err
+	}
+	var topic Topic
+	if _, err := rand.Read(topic[:]); err != nil {
+		return err
+	}
+	key, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		return err
+	}
+
+	tc, err := Wrap(ctx, topic, nil, &key.PublicKey, Targets{target})
+	if err != nil {
+		return err
+	}
+	stamp, err := stamper.Stamp(tc.Address())
+	if err != nil {
+		return err
+	}
+	if _, err := p.pusher.PushChunkToClosest(ctx, tc.WithStamp(stamp)); err != nil {
+		return err
+	}
+
+	p.metrics.CoverMessagesSentCounter.Inc()
+	return nil
+}
+
+func randUint64() uint64 {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return binary.BigEndian.Uint64(b[:])
+}
@@ -26,6 +26,10 @@ import (
 // loggerName is the tree path name of the logger for this package.
 const loggerName = "pss"
 
+// messageMemory is the memory acquired from the memory budget for the mining
+// of a message by the concurrent workers or for the unwrapping of a message.
+const messageMemory = 8 * swarm.ChunkWithSpanSize
+
 var (
 	_            Interface = (*pss)(nil)
 	ErrNoHandler           = errors.New("no handler found")
@@ -54,22 +58,35 @@ type pss struct {
 	handlersMu sync.Mutex
 	metrics    metrics
 	logger     log.Logger
+	opts       Options
+	cover      cover
+	coverOnce  sync.Once
+	wg         sync.WaitGroup
 	quit       chan struct{}
 }
 
 // New returns a new pss service.
-func New(key *ecdsa.PrivateKey, logger log.Logger) Interface {
+func New(key *ecdsa.PrivateKey, logger log.Logger, o Options) Interface {
+	if o.MaxDelay < o.MinDelay {
+		o.MaxDelay = o.MinDelay
+	}
+	if o.CoverBudget <= 0 {
+		o.CoverBudget = DefaultCoverBudget
+	}
 	return &pss{
 		key:      key,
 		logger:   logger.WithName(loggerName).Register(),
 		handlers: make(map[Topic][]*Handler),
 		metrics:  newMetrics(),
+		opts:     o,
 		quit:     make(chan struct{}),
 	}
 }
 
 func (ps *pss) Close() error {
 	close(ps.quit)
+	ps.wg.Wait()
+
 	ps.handlersMu.Lock()
 	defer ps.handlersMu.Unlock()
 
@@ -78,8 +95,17 @@ func (ps *pss) Close() error {
 	return nil
 }
 
+// SetPushSyncer sets the push syncer used to deliver the messages and starts
+// the generation of the cover traffic if it is enabled.
 func (ps *pss) SetPushSyncer(pushSyncer pushsync.PushSyncer) {
 	ps.pusher = pushSyncer
+
+	if ps.opts.CoverInterval > 0 {
+		ps.coverOnce.Do(func() {
+			ps.wg.Add(1)
+			go ps.coverLoop()
+		})
+	}
 }
 
 // Handler defines code to be executed upon reception of a trojan message.
@@ -91,6 +117,11 @@ type Handler func(context.Context, []byte)
 func (p *pss) Send(ctx context.Context, topic Topic, payload []byte, stamper postage.Stamper, recipient *ecdsa.PublicKey, targets Targets) error {
 	p.metrics.TotalMessagesSentCounter.Inc()
 
+	if err := p.opts.MemoryBudget.Acquire(ctx, messageMemory); err != nil {
+		return err
+	}
+	defer p.opts.MemoryBudget.Release(messageMemory)
+
 	tStart := time.Now()
 
 	tc, err := Wrap(ctx, topic, payload, recipient, targets)
@@ -106,6 +137,13 @@ func (p *pss) Send(ctx context.Context, topic Topic, payload []byte, stamper pos
 
 	p.metrics.MessageMiningDuration.Set(time.Since(tStart).Seconds())
 
+	// the cover messages mimic the most recent message
+	p.cover.mimic(stamper, targets)
+
+	if err := p.delay(ctx); err != nil {
+		return err
+	}
+
 	// push the chunk using push sync so that it reaches it destination in network
 	if _, err = p.pusher.PushChunkToClosest(ctx, tc); err != nil {
 		return err
@@ -152,13 +190,21 @@ func (p *pss) TryUnwrap(c swarm.Chunk) {
 	if len(c.Data()) < swarm.ChunkWithSpanSize {
 		return // chunk not full
 	}
+	if !p.opts.MemoryBudget.TryAcquire(messageMemory) {
+		p.metrics.ReceivedMessagesDroppedCounter.Inc()
+		return // memory pressure
+	}
+	release := func() { p.opts.MemoryBudget.Release(messageMemory) }
+
 	ctx := context.Background()
 	topic, msg, err := Unwrap(ctx, p.key, c, p.topics())
 	if err != nil {
+		release()
 		return // cannot unwrap
 	}
 	h := p.getHandlers(topic)
 	if h == nil {
+		release()
 		return // no handler
 	}
 
@@ -181,6 +227,7 @@ func (p *pss) TryUnwrap(c swarm.Chunk) {
 	}
 	go func() {
 		wg.Wait()
+		release()
 		close(done)
 	}()
 }
@@ -7,16 +7,20 @@ package pss_test
 import (
 	"bytes"
 	"context"
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/membudget"
 	"github.com/ethersphere/bee/pkg/postage"
 	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
 	"github.com/ethersphere/bee/pkg/pss"
 	"github.com/ethersphere/bee/pkg/pushsync"
 	pushsyncmock "github.com/ethersphere/bee/pkg/pushsync/mock"
+	"github.com/ethersphere/bee/pkg/spinlock"
 	"github.com/ethersphere/bee/pkg/swarm"
 )
 
@@ -33,7 +37,7 @@ func TestSend(t *testing.T) {
 		storedChunk = chunk
 		return nil, nil
 	})
-	p := pss.New(nil, log.Noop)
+	p := pss.New(nil, log.Noop, pss.Options{})
 	p.SetPushSyncer(pushSyncService)
 
 	target := pss.Target([]byte{1}) // arbitrary test target
@@ -84,7 +88,7 @@ func TestDeliver(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	p := pss.New(privkey, log.Noop)
+	p := pss.New(privkey, log.Noop, pss.Options{})
 
 	target := pss.Target([]byte{1}) // arbitrary test target
 	targets := pss.Targets([]pss.Target{target})
@@ -130,6 +134,64 @@ func TestDeliver(t *testing.T) {
 	}
 }
 
+// TestDeliverMemoryPressure verifies that the received messages are
+// dropped when the memory budget is exhausted.
+func TestDeliverMemoryPressure(t *testing.T) {
+	t.Parallel()
+
+	privkey, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic := pss.NewTopic("topic")
+	chunk, err := pss.Wrap(context.Background(), topic, []byte("some payload"), &privkey.PublicKey, pss.Targets{pss.Target([]byte{1})})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		limit     int64
+		delivered bool
+	}{
+		{"exhausted", swarm.ChunkWithSpanSize, false},
+		{"available", 1 << 20, true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			budget := membudget.New(tc.limit)
+			p := pss.New(privkey, log.Noop, pss.Options{MemoryBudget: budget})
+
+			msgChan := make(chan struct{}, 1)
+			p.Register(topic, func(context.Context, []byte) { msgChan <- struct{}{} })
+
+			p.TryUnwrap(chunk)
+
+			select {
+			case <-msgChan:
+				if !tc.delivered {
+					t.Fatal("message delivered under the memory pressure")
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tc.delivered {
+					t.Fatal("reached timeout while waiting for message")
+				}
+			}
+
+			// the memory is released once the handlers return
+			deadline := time.Now().Add(time.Second)
+			for budget.Used() != 0 {
+				if time.Now().After(deadline) {
+					t.Fatalf("got used memory %d, want 0", budget.Used())
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
+
 // TestRegister verifies that handler funcs are able to be registered correctly in pss
 func TestRegister(t *testing.T) {
 	t.Parallel()
@@ -140,7 +202,7 @@ func TestRegister(t *testing.T) {
 	}
 	recipient := &privkey.PublicKey
 	var (
-		p       = pss.New(privkey, log.Noop)
+		p       = pss.New(privkey, log.Noop, pss.Options{})
 		h1Calls = 0
 		h2Calls = 0
 		h3Calls = 0
@@ -236,6 +298,106 @@ func ensureCalls(t *testing.T, calls *int, exp int) {
 	}
 }
 
+// TestSendDelay verifies that the delivery of the sent
+// message is delayed by the configured random delay.
+func TestSendDelay(t *testing.T) {
+	t.Parallel()
+
+	const minDelay, maxDelay = 100 * time.Millisecond, 150 * time.Millisecond
+
+	pushSyncService := pushsyncmock.New(func(ctx context.Context, chunk swarm.Chunk) (*pushsync.Receipt, error) {
+		return nil, nil
+	})
+	p := pss.New(nil, log.Noop, pss.Options{MinDelay: minDelay, MaxDelay: maxDelay})
+	t.Cleanup(func() { _ = p.Close() })
+	p.SetPushSyncer(pushSyncService)
+
+	privkey, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := pss.Targets{pss.Target([]byte{1})}
+	topic := pss.NewTopic("topic")
+
+	start := time.Now()
+	if err := p.Send(context.Background(), topic, []byte("some payload"), &stamper{}, &privkey.PublicKey, targets); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < minDelay {
+		t.Fatalf("got send duration %v, want at least %v", d, minDelay)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.Send(ctx, topic, []byte("some payload"), &stamper{}, &privkey.PublicKey, targets); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+// TestCoverTraffic verifies that the cover messages are sent only after a
+// message was sent, that they mimic its targets and that their number is
+// bounded by the budget.
+func TestCoverTraffic(t *testing.T) {
+	t.Parallel()
+
+	const budget = 3
+
+	var (
+		mtx    sync.Mutex
+		chunks []swarm.Chunk
+	)
+	pushed := func() []swarm.Chunk {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return append([]swarm.Chunk(nil), chunks...)
+	}
+	pushSyncService := pushsyncmock.New(func(ctx context.Context, chunk swarm.Chunk) (*pushsync.Receipt, error) {
+		mtx.Lock()
+		chunks = append(chunks, chunk)
+		mtx.Unlock()
+		return nil, nil
+	})
+	p := pss.New(nil, log.Noop, pss.Options{CoverInterval: 10 * time.Millisecond, CoverBudget: budget})
+	t.Cleanup(func() { _ = p.Close() })
+	p.SetPushSyncer(pushSyncService)
+
+	time.Sleep(100 * time.Millisecond)
+	if got := len(pushed()); got != 0 {
+		t.Fatalf("got %d cover messages before a message was sent, want none", got)
+	}
+
+	privkey, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := pss.Targets{pss.Target([]byte{1})}
+	if err := p.Send(context.Background(), pss.NewTopic("topic"), []byte("some payload"), &stamper{}, &privkey.PublicKey, targets); err != nil {
+		t.Fatal(err)
+	}
+
+	err = spinlock.Wait(5*time.Second, func() bool {
+		return len(pushed()) == budget+1
+	})
+	if err != nil {
+		t.Fatalf("got %d pushed messages, want %d", len(pushed()), budget+1)
+	}
+
+	// the budget is exhausted
+	time.Sleep(100 * time.Millisecond)
+	got := pushed()
+	if len(got) != budget+1 {
+		t.Fatalf("got %d pushed messages, want %d", len(got), budget+1)
+	}
+	for _, ch := range got {
+		if len(ch.Data()) != len(got[0].Data()) {
+			t.Fatalf("got cover message size %d, want %d", len(ch.Data()), len(got[0].Data()))
+		}
+		if ch.Stamp() == nil {
+			t.Fatal("cover message not stamped")
+		}
+	}
+}
+
 type stamper struct{}
 
 func (s *stamper) Stamp(_ swarm.Address) (*postage.Stamp, error) {
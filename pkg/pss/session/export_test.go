@@ -0,0 +1,16 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package session
+
+import "time"
+
+const (
+	MaxRequested = maxRequested
+	PendingTTL   = pendingTTL
+)
+
+func (s *Service) SetNow(now func() time.Time) {
+	s.now = now
+}
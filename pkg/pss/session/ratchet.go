@@ -0,0 +1,324 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package session
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	publicKeySize = 33 // compressed secp256k1 public key
+	headerSize    = publicKeySize + 4 + 4
+
+	// maxSkip is the maximum number of the message keys
+	// skipped in a chain and kept for the delayed messages.
+	maxSkip = 1000
+)
+
+var (
+	infoSession = []byte("bee-pss-session")
+	infoRatchet = []byte("bee-pss-session-ratchet")
+	infoMessage = []byte("bee-pss-session-message")
+)
+
+var (
+	errDecrypt     = errors.New("decryption failed")
+	errTooManySkip = errors.New("too many skipped messages")
+)
+
+// header is the header of a message of the double ratchet.
+type header struct {
+	dh *ecdsa.PublicKey // current ratchet public key of the sender
+	pn uint32           // number of the messages in the previous sending chain
+	n  uint32           // number of the message in the sending chain
+}
+
+func (h header) marshal() []byte {
+	b := make([]byte, 0, headerSize)
+	b = append(b, crypto.EncodeSecp256k1PublicKey(h.dh)...)
+	b = binary.BigEndian.AppendUint32(b, h.pn)
+	return binary.BigEndian.AppendUint32(b, h.n)
+}
+
+func unmarshalHeader(b []byte) (header, error) {
+	if len(b) < headerSize {
+		return header{}, errInvalidMessage
+	}
+	dh, err := unmarshalPublicKey(b[:publicKeySize])
+	if err != nil {
+		return header{}, err
+	}
+	return header{
+		dh: dh,
+		pn: binary.BigEndian.Uint32(b[publicKeySize:]),
+		n:  binary.BigEndian.Uint32(b[publicKeySize+4:]),
+	}, nil
+}
+
+type skippedKey struct {
+	dh string // compressed ratchet public key
+	n  uint32
+}
+
+// ratchet is the state of the double ratchet of a session, as specified by
+// https://signal.org/docs/specifications/doubleratchet/ with the ECDH over
+// secp256k1, HKDF and HMAC with SHA-256 and AES-256-GCM.
+type ratchet struct {
+	dhs      *ecdsa.PrivateKey // sending ratchet key
+	dhr      *ecdsa.PublicKey  // receiving ratchet key
+	rk       []byte            // root key
+	cks      []byte            // sending chain key
+	ckr      []byte            // receiving chain key
+	ns, nr   uint32            // message numbers of the sending and receiving chains
+	pn       uint32            // number of the messages in the previous sending chain
+	skipped  map[skippedKey][]byte
+	skippedN []skippedKey // order of the skipped keys, the oldest first
+}
+
+// newRatchet initializes the double ratchet with the shared secret of the
+// handshake. Both parties start with the chain derived from their handshake
+// ephemeral keys, the responder as its sending chain and the initiator as its
+// receiving chain. The initiator then steps its ratchet forward with a new
+// key, so that both parties are able to send right after the handshake.
+func newRatchet(sk []byte, ephemeral *ecdsa.PrivateKey, remote *ecdsa.PublicKey, initiator bool) (*ratchet, error) {
+	dhOut, err := dh(ephemeral, remote)
+	if err != nil {
+		return nil, err
+	}
+	rk, ck, err := kdfRK(sk, dhOut)
+	if err != nil {
+		return nil, err
+	}
+	r := &ratchet{
+		dhs:     ephemeral,
+		dhr:     remote,
+		rk:      rk,
+		skipped: make(map[skippedKey][]byte),
+	}
+	if !initiator {
+		r.cks = ck
+		return r, nil
+	}
+	r.ckr = ck
+	if err := r.stepSending(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// encrypt encrypts the plaintext with the next message key
+// of the sending chain and returns the header of the message.
+func (r *ratchet) encrypt(plaintext, ad []byte) ([]byte, []byte, error) {
+	ck, mk := kdfCK(r.cks)
+	h := header{dh: &r.dhs.PublicKey, pn: r.pn, n: r.ns}
+	hb := h.marshal()
+	ciphertext, err := seal(mk, plaintext, append(append([]byte{}, ad...), hb...))
+	if err != nil {
+		return nil, nil, err
+	}
+	r.cks = ck
+	r.ns++
+	return hb, ciphertext, nil
+}
+
+// decrypt decrypts the message with the header. The state of the ratchet
+// is changed only if the message is authentic.
+func (r *ratchet) decrypt(hb, ciphertext, ad []byte) ([]byte, error) {
+	h, err := unmarshalHeader(hb)
+	if err != nil {
+		return nil, err
+	}
+	ad = append(append([]byte{}, ad...), hb...)
+
+	sk := skippedKey{dh: string(crypto.EncodeSecp256k1PublicKey(h.dh)), n: h.n}
+	if mk, ok := r.skipped[sk]; ok {
+		plaintext, err := open(mk, ciphertext, ad)
+		if err != nil {
+			return nil, err
+		}
+		r.removeSkipped(sk)
+		return plaintext, nil
+	}
+
+	next := r.clone()
+	if !h.dh.Equal(next.dhr) {
+		if err := next.skip(h.pn); err != nil {
+			return nil, err
+		}
+		if err := next.stepReceiving(h.dh); err != nil {
+			return nil, err
+		}
+		if err := next.stepSending(); err != nil {
+			return nil, err
+		}
+	}
+	if err := next.skip(h.n); err != nil {
+		return nil, err
+	}
+	ck, mk := kdfCK(next.ckr)
+	plaintext, err := open(mk, ciphertext, ad)
+	if err != nil {
+		return nil, err
+	}
+	next.ckr = ck
+	next.nr++
+	*r = *next
+	return plaintext, nil
+}
+
+// skip stores the message keys of the receiving chain up to the message number.
+func (r *ratchet) skip(until uint32) error {
+	if r.ckr == nil {
+		return nil
+	}
+	if until > r.nr+maxSkip {
+		return errTooManySkip
+	}
+	dhr := string(crypto.EncodeSecp256k1PublicKey(r.dhr))
+	for r.nr < until {
+		ck, mk := kdfCK(r.ckr)
+		k := skippedKey{dh: dhr, n: r.nr}
+		r.skipped[k] = mk
+		r.skippedN = append(r.skippedN, k)
+		r.ckr = ck
+		r.nr++
+	}
+	// only the most recent skipped keys are kept
+	for len(r.skippedN) > maxSkip {
+		delete(r.skipped, r.skippedN[0])
+		r.skippedN = r.skippedN[1:]
+	}
+	return nil
+}
+
+// stepReceiving derives the receiving chain of the new ratchet key of the peer.
+func (r *ratchet) stepReceiving(remote *ecdsa.PublicKey) error {
+	r.pn = r.ns
+	r.ns, r.nr = 0, 0
+	r.dhr = remote
+	dhOut, err := dh(r.dhs, r.dhr)
+	if err != nil {
+		return err
+	}
+	r.rk, r.ckr, err = kdfRK(r.rk, dhOut)
+	return err
+}
+
+// stepSending derives the sending chain of a new ratchet key.
+func (r *ratchet) stepSending() error {
+	key, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		return err
+	}
+	dhOut, err := dh(key, r.dhr)
+	if err != nil {
+		return err
+	}
+	r.dhs = key
+	r.rk, r.cks, err = kdfRK(r.rk, dhOut)
+	return err
+}
+
+func (r *ratchet) removeSkipped(k skippedKey) {
+	delete(r.skipped, k)
+	for i, s := range r.skippedN {
+		if s == k {
+			r.skippedN = append(r.skippedN[:i:i], r.skippedN[i+1:]...)
+			return
+		}
+	}
+}
+
+func (r *ratchet) clone() *ratchet {
+	c := *r
+	c.skipped = make(map[skippedKey][]byte, len(r.skipped))
+	for k, v := range r.skipped {
+		c.skipped[k] = v
+	}
+	c.skippedN = append([]skippedKey(nil), r.skippedN...)
+	return &c
+}
+
+// dh returns the ECDH shared secret of the private and the public key.
+func dh(key *ecdsa.PrivateKey, pub *ecdsa.PublicKey) ([]byte, error) {
+	return crypto.NewDH(key).SharedKey(pub, nil)
+}
+
+// kdfRK derives the next root key and a chain key from the ECDH output.
+func kdfRK(rk, dhOut []byte) ([]byte, []byte, error) {
+	out := make([]byte, 64)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, dhOut, rk, infoRatchet), out); err != nil {
+		return nil, nil, err
+	}
+	return out[:32], out[32:], nil
+}
+
+// kdfCK derives the next chain key and the message key from the chain key.
+func kdfCK(ck []byte) ([]byte, []byte) {
+	return hmacSHA256(ck, 0x02), hmacSHA256(ck, 0x01)
+}
+
+func hmacSHA256(key []byte, b byte) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte{b})
+	return h.Sum(nil)
+}
+
+// aead returns the cipher and the nonce of the message key. As every
+// message key is used only once, the nonce is derived with the key.
+func aead(mk []byte) (cipher.AEAD, []byte, error) {
+	out := make([]byte, 32+12)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, mk, nil, infoMessage), out); err != nil {
+		return nil, nil, err
+	}
+	block, err := aes.NewCipher(out[:32])
+	if err != nil {
+		return nil, nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return gcm, out[32:], nil
+}
+
+func seal(mk, plaintext, ad []byte) ([]byte, error) {
+	gcm, nonce, err := aead(mk)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nil, nonce, plaintext, ad), nil
+}
+
+func open(mk, ciphertext, ad []byte) ([]byte, error) {
+	gcm, nonce, err := aead(mk)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, ad)
+	if err != nil {
+		return nil, errDecrypt
+	}
+	return plaintext, nil
+}
+
+func unmarshalPublicKey(b []byte) (*ecdsa.PublicKey, error) {
+	pub, err := btcec.ParsePubKey(b, btcec.S256())
+	if err != nil {
+		return nil, errInvalidMessage
+	}
+	return pub.ToECDSA(), nil
+}
@@ -0,0 +1,610 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package session provides the end-to-end encrypted and forward-secret
+// messaging sessions between the pss identities of the nodes.
+//
+// A session is established with an X3DH-like handshake over pss. The
+// initiator sends its identity and an ephemeral public key, and the
+// responder, once the session is accepted, replies with its own ephemeral
+// public key. Both parties derive the shared secret from the Diffie-Hellman
+// exchanges of the identity and ephemeral keys, which authenticates them
+// to each other without signatures. The messages of the session are then
+// encrypted with the keys of a double ratchet, so that the compromise of
+// the keys does not reveal the past messages.
+//
+// The state of the sessions is kept only in memory, as persisting the keys
+// would defeat the forward secrecy.
+package session
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"golang.org/x/crypto/hkdf"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "pss-session"
+
+const (
+	msgInit byte = iota + 1
+	msgAccept
+	msgData
+)
+
+const (
+	// IDSize is the size of the session ID.
+	IDSize = 32
+
+	// MaxMessageSize is the maximum size of a message of a session.
+	MaxMessageSize = pss.MaxPayloadSize - 1 - IDSize - headerSize - 16
+
+	// maxRequested is the maximum number of the incoming sessions which
+	// have not been accepted yet, the oldest of them is discarded first.
+	maxRequested = 100
+
+	// pendingTTL is the time after which the sessions
+	// which have not been accepted are discarded.
+	pendingTTL = 10 * time.Minute
+)
+
+// Topic is the pss topic of the messages of the sessions.
+var Topic = pss.NewTopic("bee-pss-session")
+
+var (
+	// ErrNotFound is returned if the session does not exist.
+	ErrNotFound = errors.New("session not found")
+	// ErrInvalidState is returned if the operation
+	// is not allowed in the state of the session.
+	ErrInvalidState = errors.New("invalid session state")
+	// ErrMessageTooLarge is returned if the message
+	// is larger than the MaxMessageSize.
+	ErrMessageTooLarge = errors.New("message too large")
+
+	errInvalidMessage = errors.New("invalid message")
+)
+
+// ID is the identifier of a session chosen by the initiator.
+type ID [IDSize]byte
+
+// String returns the hex encoded ID.
+func (id ID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// ParseID parses the hex encoded ID.
+func ParseID(s string) (ID, error) {
+	var id ID
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return id, err
+	}
+	if len(b) != IDSize {
+		return id, errors.New("invalid session id length")
+	}
+	copy(id[:], b)
+	return id, nil
+}
+
+// State is the state of a session.
+type State int
+
+const (
+	// StateInitiated is the state of the session initiated
+	// by the node which has not been accepted by the peer.
+	StateInitiated State = iota
+	// StateRequested is the state of the session initiated
+	// by the peer which has not been accepted by the node.
+	StateRequested
+	// StateEstablished is the state of the accepted session.
+	StateEstablished
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case StateInitiated:
+		return "initiated"
+	case StateRequested:
+		return "requested"
+	case StateEstablished:
+		return "established"
+	}
+	return "unknown"
+}
+
+// Info describes a session.
+type Info struct {
+	ID        ID
+	Peer      *ecdsa.PublicKey
+	Initiator bool
+	State     State
+	Created   time.Time
+}
+
+// Handler is called with the decrypted messages of a session.
+type Handler func(context.Context, []byte)
+
+type session struct {
+	Info
+	targets   pss.Targets       // targets of the peer
+	ephemeral *ecdsa.PrivateKey // own handshake key
+	remote    *ecdsa.PublicKey  // handshake key of the peer
+	ad        []byte            // associated data of the messages
+	ratchet   *ratchet
+	handlers  []*Handler
+}
+
+// Service manages the sessions of the pss identity of the node.
+type Service struct {
+	key     *ecdsa.PrivateKey
+	pss     pss.Interface
+	overlay swarm.Address
+	logger  log.Logger
+	now     func() time.Time
+	cleanup func()
+
+	mu       sync.Mutex
+	sessions map[ID]*session
+}
+
+// New returns a new session Service of the pss identity key of the node
+// with the overlay address, which receives the handshakes and messages of
+// the sessions over the pss.
+func New(key *ecdsa.PrivateKey, p pss.Interface, overlay swarm.Address, logger log.Logger) *Service {
+	s := &Service{
+		key:      key,
+		pss:      p,
+		overlay:  overlay,
+		logger:   logger.WithName(loggerName).Register(),
+		now:      time.Now,
+		sessions: make(map[ID]*session),
+	}
+	s.cleanup = p.Register(Topic, s.handle)
+	return s
+}
+
+// Close stops receiving the messages and discards the sessions.
+func (s *Service) Close() error {
+	s.cleanup()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions = make(map[ID]*session)
+	return nil
+}
+
+// Initiate initiates a new session with the peer of the pss public key
+// reachable at the targets. The own target of the node has the same
+// length as the targets of the peer.
+func (s *Service) Initiate(ctx context.Context, stamper postage.Stamper, peer *ecdsa.PublicKey, targets pss.Targets) (Info, error) {
+	if len(targets) == 0 || len(targets[0]) == 0 || len(targets[0]) > len(s.overlay.Bytes()) {
+		return Info{}, pss.ErrEmptyTargets
+	}
+
+	var id ID
+	if _, err := io.ReadFull(rand.Reader, id[:]); err != nil {
+		return Info{}, err
+	}
+	ephemeral, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		return Info{}, err
+	}
+
+	sess := &session{
+		Info: Info{
+			ID:        id,
+			Peer:      peer,
+			Initiator: true,
+			State:     StateInitiated,
+			Created:   s.now(),
+		},
+		targets:   targets,
+		ephemeral: ephemeral,
+	}
+
+	// init: type | id | identity key | ephemeral key | target
+	target := s.overlay.Bytes()[:len(targets[0])]
+	msg := make([]byte, 0, 1+IDSize+2*publicKeySize+len(target))
+	msg = append(msg, msgInit)
+	msg = append(msg, id[:]...)
+	msg = append(msg, crypto.EncodeSecp256k1PublicKey(&s.key.PublicKey)...)
+	msg = append(msg, crypto.EncodeSecp256k1PublicKey(&ephemeral.PublicKey)...)
+	msg = append(msg, target...)
+
+	// the session is known before the peer may accept it
+	info := sess.Info
+	s.mu.Lock()
+	s.expire()
+	s.sessions[id] = sess
+	s.mu.Unlock()
+
+	if err := s.pss.Send(ctx, Topic, msg, stamper, peer, targets); err != nil {
+		_ = s.Remove(id)
+		return Info{}, err
+	}
+	return info, nil
+}
+
+// Accept accepts the session requested by the peer and
+// replies with the handshake which establishes it.
+func (s *Service) Accept(ctx context.Context, stamper postage.Stamper, id ID) (Info, error) {
+	info, targets, msg, err := s.accept(id)
+	if err != nil {
+		return Info{}, err
+	}
+
+	if err := s.pss.Send(ctx, Topic, msg, stamper, info.Peer, targets); err != nil {
+		// the session may be accepted again
+		s.mu.Lock()
+		if sess, ok := s.sessions[id]; ok {
+			sess.State = StateRequested
+			sess.ratchet = nil
+		}
+		s.mu.Unlock()
+		return Info{}, err
+	}
+	return info, nil
+}
+
+// accept establishes the requested session and
+// returns the handshake message of the responder.
+func (s *Service) accept(id ID) (Info, pss.Targets, []byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	sess, ok := s.sessions[id]
+	if !ok {
+		return Info{}, nil, nil, ErrNotFound
+	}
+	if sess.State != StateRequested {
+		return Info{}, nil, nil, ErrInvalidState
+	}
+
+	ephemeral, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		return Info{}, nil, nil, err
+	}
+	sk, err := sharedSecret(
+		exchange{ephemeral, sess.Peer},   // DH(EKb, IKa)
+		exchange{s.key, sess.remote},     // DH(IKb, EKa)
+		exchange{ephemeral, sess.remote}, // DH(EKb, EKa)
+	)
+	if err != nil {
+		return Info{}, nil, nil, err
+	}
+	r, err := newRatchet(sk, ephemeral, sess.remote, false)
+	if err != nil {
+		return Info{}, nil, nil, err
+	}
+	ad := associatedData(id, sess.Peer, &s.key.PublicKey)
+	// the empty first message confirms the shared secret to the initiator
+	hb, confirmation, err := r.encrypt(nil, ad)
+	if err != nil {
+		return Info{}, nil, nil, err
+	}
+	sess.ephemeral = ephemeral
+	sess.ratchet = r
+	sess.ad = ad
+	sess.State = StateEstablished
+
+	// accept: type | id | ephemeral key | header | confirmation
+	msg := make([]byte, 0, 1+IDSize+publicKeySize+len(hb)+len(confirmation))
+	msg = append(msg, msgAccept)
+	msg = append(msg, id[:]...)
+	msg = append(msg, crypto.EncodeSecp256k1PublicKey(&ephemeral.PublicKey)...)
+	msg = append(msg, hb...)
+	msg = append(msg, confirmation...)
+
+	return sess.Info, sess.targets, msg, nil
+}
+
+// Send encrypts the message with the next key of the
+// established session and sends it to the peer.
+func (s *Service) Send(ctx context.Context, stamper postage.Stamper, id ID, message []byte) error {
+	if len(message) > MaxMessageSize {
+		return ErrMessageTooLarge
+	}
+
+	s.mu.Lock()
+	sess, ok := s.sessions[id]
+	if !ok {
+		s.mu.Unlock()
+		return ErrNotFound
+	}
+	if sess.State != StateEstablished {
+		s.mu.Unlock()
+		return ErrInvalidState
+	}
+	hb, ciphertext, err := sess.ratchet.encrypt(message, sess.ad)
+	peer, targets := sess.Peer, sess.targets
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// data: type | id | header | ciphertext
+	msg := make([]byte, 0, 1+IDSize+len(hb)+len(ciphertext))
+	msg = append(msg, msgData)
+	msg = append(msg, id[:]...)
+	msg = append(msg, hb...)
+	msg = append(msg, ciphertext...)
+
+	return s.pss.Send(ctx, Topic, msg, stamper, peer, targets)
+}
+
+// Subscribe registers the handler of the messages of the session.
+// The returned function unregisters it.
+func (s *Service) Subscribe(id ID, h Handler) (func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	sess.handlers = append(sess.handlers, &h)
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		for i, hh := range sess.handlers {
+			if hh == &h {
+				sess.handlers = append(sess.handlers[:i:i], sess.handlers[i+1:]...)
+				return
+			}
+		}
+	}, nil
+}
+
+// Session returns the description of the session.
+func (s *Service) Session(id ID) (Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	sess, ok := s.sessions[id]
+	if !ok {
+		return Info{}, ErrNotFound
+	}
+	return sess.Info, nil
+}
+
+// Sessions returns the descriptions of all sessions.
+func (s *Service) Sessions() []Info {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	infos := make([]Info, 0, len(s.sessions))
+	for _, sess := range s.sessions {
+		infos = append(infos, sess.Info)
+	}
+	return infos
+}
+
+// Remove discards the session with its keys.
+func (s *Service) Remove(id ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sessions[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.sessions, id)
+	return nil
+}
+
+// expire discards the sessions which have not been accepted in
+// the pendingTTL. It must be called with the lock held.
+func (s *Service) expire() {
+	now := s.now()
+	for id, sess := range s.sessions {
+		if sess.State != StateEstablished && now.Sub(sess.Created) >= pendingTTL {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// handle is the pss handler of the messages of the sessions.
+func (s *Service) handle(ctx context.Context, msg []byte) {
+	if len(msg) < 1+IDSize {
+		return
+	}
+	var id ID
+	copy(id[:], msg[1:])
+	body := msg[1+IDSize:]
+
+	var err error
+	switch msg[0] {
+	case msgInit:
+		err = s.handleInit(id, body)
+	case msgAccept:
+		err = s.handleAccept(id, body)
+	case msgData:
+		err = s.handleData(ctx, id, body)
+	default:
+		err = errInvalidMessage
+	}
+	if err != nil {
+		s.logger.Debug("handle session message failed", "session_id", id, "error", err)
+	}
+}
+
+func (s *Service) handleInit(id ID, body []byte) error {
+	if len(body) <= 2*publicKeySize {
+		return errInvalidMessage
+	}
+	peer, err := unmarshalPublicKey(body[:publicKeySize])
+	if err != nil {
+		return err
+	}
+	remote, err := unmarshalPublicKey(body[publicKeySize : 2*publicKeySize])
+	if err != nil {
+		return err
+	}
+	target := append(pss.Target{}, body[2*publicKeySize:]...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	if _, ok := s.sessions[id]; ok {
+		return nil // replayed
+	}
+	var (
+		requested int
+		oldest    *session
+	)
+	for _, sess := range s.sessions {
+		if sess.State != StateRequested {
+			continue
+		}
+		requested++
+		if oldest == nil || sess.Created.Before(oldest.Created) {
+			oldest = sess
+		}
+	}
+	if requested >= maxRequested {
+		delete(s.sessions, oldest.ID)
+	}
+
+	s.sessions[id] = &session{
+		Info: Info{
+			ID:      id,
+			Peer:    peer,
+			State:   StateRequested,
+			Created: s.now(),
+		},
+		targets: pss.Targets{target},
+		remote:  remote,
+	}
+	return nil
+}
+
+func (s *Service) handleAccept(id ID, body []byte) error {
+	if len(body) < publicKeySize+headerSize {
+		return errInvalidMessage
+	}
+	remote, err := unmarshalPublicKey(body[:publicKeySize])
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	sess, ok := s.sessions[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if sess.State != StateInitiated {
+		return ErrInvalidState
+	}
+
+	sk, err := sharedSecret(
+		exchange{s.key, remote},             // DH(IKa, EKb)
+		exchange{sess.ephemeral, sess.Peer}, // DH(EKa, IKb)
+		exchange{sess.ephemeral, remote},    // DH(EKa, EKb)
+	)
+	if err != nil {
+		return err
+	}
+	r, err := newRatchet(sk, sess.ephemeral, remote, true)
+	if err != nil {
+		return err
+	}
+	ad := associatedData(id, &s.key.PublicKey, sess.Peer)
+	// only the peer with the identity key derives the same secret
+	hb := body[publicKeySize : publicKeySize+headerSize]
+	if _, err := r.decrypt(hb, body[publicKeySize+headerSize:], ad); err != nil {
+		return err
+	}
+	sess.remote = remote
+	sess.ratchet = r
+	sess.ad = ad
+	sess.State = StateEstablished
+	return nil
+}
+
+func (s *Service) handleData(ctx context.Context, id ID, body []byte) error {
+	if len(body) < headerSize {
+		return errInvalidMessage
+	}
+
+	s.mu.Lock()
+	sess, ok := s.sessions[id]
+	if !ok {
+		s.mu.Unlock()
+		return ErrNotFound
+	}
+	if sess.State != StateEstablished {
+		s.mu.Unlock()
+		return ErrInvalidState
+	}
+	message, err := sess.ratchet.decrypt(body[:headerSize], body[headerSize:], sess.ad)
+	handlers := append([]*Handler(nil), sess.handlers...)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, h := range handlers {
+		(*h)(ctx, message)
+	}
+	return nil
+}
+
+// exchange is a Diffie-Hellman exchange of the handshake.
+type exchange struct {
+	key *ecdsa.PrivateKey
+	pub *ecdsa.PublicKey
+}
+
+// sharedSecret derives the shared secret of the handshake from the
+// Diffie-Hellman exchanges of the identity and the ephemeral keys, in
+// the order of the exchanges of the initiator.
+func sharedSecret(exchanges ...exchange) ([]byte, error) {
+	// the padding separates the input from the other uses of the keys
+	ikm := bytes.Repeat([]byte{0xff}, 32)
+	for _, e := range exchanges {
+		out, err := dh(e.key, e.pub)
+		if err != nil {
+			return nil, err
+		}
+		ikm = append(ikm, out...)
+	}
+
+	sk := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, ikm, nil, infoSession), sk); err != nil {
+		return nil, err
+	}
+	return sk, nil
+}
+
+// associatedData binds the messages to the session
+// and the identities of the initiator and the responder.
+func associatedData(id ID, initiator, responder *ecdsa.PublicKey) []byte {
+	ad := append([]byte{}, id[:]...)
+	ad = append(ad, crypto.EncodeSecp256k1PublicKey(initiator)...)
+	return append(ad, crypto.EncodeSecp256k1PublicKey(responder)...)
+}
@@ -0,0 +1,451 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package session_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/ethersphere/bee/pkg/pss/session"
+	"github.com/ethersphere/bee/pkg/pushsync"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// network delivers the pss messages between the nodes once they are flushed.
+type network struct {
+	mu       sync.Mutex
+	nodes    map[string]*node
+	messages []message
+}
+
+type message struct {
+	to      *node
+	payload []byte
+}
+
+func newNetwork() *network {
+	return &network{nodes: make(map[string]*node)}
+}
+
+// take returns the sent messages, the oldest first, and forgets them.
+func (n *network) take() []message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	m := n.messages
+	n.messages = nil
+	return m
+}
+
+// flush delivers the sent messages in the order they were sent.
+func (n *network) flush() {
+	for _, m := range n.take() {
+		m.deliver()
+	}
+}
+
+func (m message) deliver() {
+	m.to.mu.Lock()
+	h := m.to.handler
+	m.to.mu.Unlock()
+	if h != nil {
+		h(context.Background(), m.payload)
+	}
+}
+
+// node is the pss of a node of the network.
+type node struct {
+	net     *network
+	mu      sync.Mutex
+	handler pss.Handler
+}
+
+func (n *node) Send(_ context.Context, topic pss.Topic, payload []byte, _ postage.Stamper, recipient *ecdsa.PublicKey, _ pss.Targets) error {
+	if topic != session.Topic {
+		return errors.New("unexpected topic")
+	}
+	n.net.mu.Lock()
+	defer n.net.mu.Unlock()
+
+	to, ok := n.net.nodes[string(crypto.EncodeSecp256k1PublicKey(recipient))]
+	if !ok {
+		return nil // lost
+	}
+	n.net.messages = append(n.net.messages, message{to: to, payload: append([]byte(nil), payload...)})
+	return nil
+}
+
+func (n *node) Register(_ pss.Topic, h pss.Handler) func() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.handler = h
+	return func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		n.handler = nil
+	}
+}
+
+func (n *node) TryUnwrap(swarm.Chunk)             {}
+func (n *node) SetPushSyncer(pushsync.PushSyncer) {}
+func (n *node) Close() error                      { return nil }
+
+// node returns the pss of the node with the public key.
+func (n *network) node(pub *ecdsa.PublicKey) *node {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.nodes[string(crypto.EncodeSecp256k1PublicKey(pub))]
+}
+
+// addNode returns the session Service of a new node of the network.
+func (n *network) addNode(t *testing.T) (*session.Service, *ecdsa.PublicKey) {
+	t.Helper()
+
+	key, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &node{net: n}
+	n.mu.Lock()
+	n.nodes[string(crypto.EncodeSecp256k1PublicKey(&key.PublicKey))] = p
+	n.mu.Unlock()
+
+	svc := session.New(key, p, swarm.RandAddress(t), log.Noop)
+	t.Cleanup(func() { _ = svc.Close() })
+	return svc, &key.PublicKey
+}
+
+// receiver collects the messages of a session.
+type receiver struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (r *receiver) handle(_ context.Context, m []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, string(m))
+}
+
+func (r *receiver) check(t *testing.T, want ...string) {
+	t.Helper()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if fmt.Sprint(r.messages) != fmt.Sprint(want) {
+		t.Fatalf("got messages %q, want %q", r.messages, want)
+	}
+	r.messages = nil
+}
+
+// establish establishes a session between the services and
+// subscribes the receivers to the messages of both sides.
+func establish(t *testing.T, net *network, a, b *session.Service, bPub *ecdsa.PublicKey) (session.ID, *receiver, *receiver) {
+	t.Helper()
+
+	ctx := context.Background()
+	stamper := mockpost.NewStamper()
+
+	info, err := a.Initiate(ctx, stamper, bPub, pss.Targets{{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.State != session.StateInitiated || !info.Initiator {
+		t.Fatalf("got initiated session %+v", info)
+	}
+	net.flush()
+
+	requested, err := b.Session(info.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requested.State != session.StateRequested || requested.Initiator {
+		t.Fatalf("got requested session %+v", requested)
+	}
+
+	if _, err := b.Accept(ctx, stamper, info.ID); err != nil {
+		t.Fatal(err)
+	}
+	net.flush()
+
+	for _, svc := range []*session.Service{a, b} {
+		s, err := svc.Session(info.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.State != session.StateEstablished {
+			t.Fatalf("got state %s, want %s", s.State, session.StateEstablished)
+		}
+	}
+
+	ra, rb := new(receiver), new(receiver)
+	if _, err := a.Subscribe(info.ID, ra.handle); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Subscribe(info.ID, rb.handle); err != nil {
+		t.Fatal(err)
+	}
+	return info.ID, ra, rb
+}
+
+func TestSession(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		stamper = mockpost.NewStamper()
+		net     = newNetwork()
+		a, _    = net.addNode(t)
+		b, bPub = net.addNode(t)
+	)
+	id, ra, rb := establish(t, net, a, b, bPub)
+
+	send := func(svc *session.Service, msg string) {
+		t.Helper()
+		if err := svc.Send(ctx, stamper, id, []byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// both sides send right after the handshake
+	send(b, "b1")
+	send(a, "a1")
+	send(a, "a2")
+	net.flush()
+	ra.check(t, "b1")
+	rb.check(t, "a1", "a2")
+
+	// the ratchet steps with the replies
+	send(b, "b2")
+	net.flush()
+	send(a, "a3")
+	net.flush()
+	send(b, "b3")
+	send(b, "b4")
+	net.flush()
+	ra.check(t, "b2", "b3", "b4")
+	rb.check(t, "a3")
+
+	// the delayed messages are decrypted with the skipped keys
+	send(a, "a4")
+	send(a, "a5")
+	send(a, "a6")
+	msgs := net.take()
+	msgs[2].deliver()
+	msgs[0].deliver()
+	rb.check(t, "a6", "a4")
+	send(b, "b5")
+	net.flush()
+	ra.check(t, "b5")
+	send(a, "a7")
+	net.flush()
+	msgs[1].deliver()
+	rb.check(t, "a7", "a5")
+
+	// the replayed message is not decrypted again
+	msgs[1].deliver()
+	rb.check(t)
+
+	if err := a.Remove(id); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Send(ctx, stamper, id, []byte("gone")); !errors.Is(err, session.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, session.ErrNotFound)
+	}
+}
+
+func TestSessionTampered(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		stamper = mockpost.NewStamper()
+		net     = newNetwork()
+		a, _    = net.addNode(t)
+		b, bPub = net.addNode(t)
+	)
+	id, _, rb := establish(t, net, a, b, bPub)
+
+	if err := a.Send(ctx, stamper, id, []byte("a1")); err != nil {
+		t.Fatal(err)
+	}
+	msgs := net.take()
+	tampered := msgs[0]
+	tampered.payload = append([]byte(nil), tampered.payload...)
+	tampered.payload[len(tampered.payload)-1] ^= 1
+	tampered.deliver()
+	rb.check(t)
+
+	// the tampered message does not change the state of the session
+	msgs[0].deliver()
+	rb.check(t, "a1")
+}
+
+func TestSessionImpersonation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		stamper = mockpost.NewStamper()
+		net     = newNetwork()
+		a, _    = net.addNode(t)
+		_, bPub = net.addNode(t)
+		m, mPub = net.addNode(t)
+	)
+
+	info, err := a.Initiate(ctx, stamper, bPub, pss.Targets{{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs := net.take()
+
+	// the init is delivered to a node with another identity key,
+	// whose accept does not establish the session of the initiator
+	msgs[0].to = net.node(mPub)
+	msgs[0].deliver()
+	if _, err := m.Accept(ctx, stamper, info.ID); err != nil {
+		t.Fatal(err)
+	}
+	net.flush()
+
+	s, err := a.Session(info.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.State != session.StateInitiated {
+		t.Fatalf("got state %s, want %s", s.State, session.StateInitiated)
+	}
+}
+
+func TestSessionStates(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		stamper = mockpost.NewStamper()
+		net     = newNetwork()
+		a, _    = net.addNode(t)
+		_, bPub = net.addNode(t)
+	)
+
+	info, err := a.Initiate(ctx, stamper, bPub, pss.Targets{{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Accept(ctx, stamper, info.ID); !errors.Is(err, session.ErrInvalidState) {
+		t.Fatalf("got error %v, want %v", err, session.ErrInvalidState)
+	}
+	if err := a.Send(ctx, stamper, info.ID, []byte("early")); !errors.Is(err, session.ErrInvalidState) {
+		t.Fatalf("got error %v, want %v", err, session.ErrInvalidState)
+	}
+	if err := a.Send(ctx, stamper, info.ID, make([]byte, session.MaxMessageSize+1)); !errors.Is(err, session.ErrMessageTooLarge) {
+		t.Fatalf("got error %v, want %v", err, session.ErrMessageTooLarge)
+	}
+	if _, err := a.Accept(ctx, stamper, session.ID{}); !errors.Is(err, session.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, session.ErrNotFound)
+	}
+	if got := len(a.Sessions()); got != 1 {
+		t.Fatalf("got %d sessions, want 1", got)
+	}
+	if _, err := a.Initiate(ctx, stamper, bPub, nil); !errors.Is(err, pss.ErrEmptyTargets) {
+		t.Fatalf("got error %v, want %v", err, pss.ErrEmptyTargets)
+	}
+}
+
+// TestSessionPending tests that the sessions which are not accepted expire
+// and that the oldest requested session is discarded over the limit.
+func TestSessionPending(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		stamper = mockpost.NewStamper()
+		net     = newNetwork()
+		a, _    = net.addNode(t)
+		b, bPub = net.addNode(t)
+
+		mu  sync.Mutex
+		now = time.Now()
+	)
+	clock := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(d)
+	}
+	a.SetNow(clock)
+	b.SetNow(clock)
+
+	initiate := func() session.ID {
+		t.Helper()
+
+		advance(time.Second)
+		info, err := a.Initiate(ctx, stamper, bPub, pss.Targets{{1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		net.flush()
+		return info.ID
+	}
+
+	first := initiate()
+	for i := 1; i < session.MaxRequested; i++ {
+		initiate()
+	}
+	if got := len(b.Sessions()); got != session.MaxRequested {
+		t.Fatalf("got %d requested sessions, want %d", got, session.MaxRequested)
+	}
+
+	last := initiate()
+	if got := len(b.Sessions()); got != session.MaxRequested {
+		t.Fatalf("got %d requested sessions, want %d", got, session.MaxRequested)
+	}
+	if _, err := b.Session(first); !errors.Is(err, session.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, session.ErrNotFound)
+	}
+	if _, err := b.Session(last); err != nil {
+		t.Fatal(err)
+	}
+
+	advance(session.PendingTTL)
+	if got := len(a.Sessions()); got != 0 {
+		t.Fatalf("got %d initiated sessions, want 0", got)
+	}
+	if _, err := b.Accept(ctx, stamper, last); !errors.Is(err, session.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, session.ErrNotFound)
+	}
+}
+
+func TestParseID(t *testing.T) {
+	t.Parallel()
+
+	id := session.ID{1, 2, 3}
+	got, err := session.ParseID(id.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got[:], id[:]) {
+		t.Fatalf("got id %s, want %s", got, id)
+	}
+	if _, err := session.ParseID("0102"); err == nil {
+		t.Fatal("expected error")
+	}
+}
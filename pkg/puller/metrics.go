@@ -16,6 +16,8 @@ type metrics struct {
 	LiveWorkerIterCounter prometheus.Counter // counts the number of live syncing iterations
 	LiveWorkerErrCounter  prometheus.Counter // count number of errors
 	MaxUintErrCounter     prometheus.Counter // how many times we got maxuint as topmost
+	AuditCounter          prometheus.Counter // count number of neighbor checksum audits
+	ResyncCounter         prometheus.Counter // count number of bins resynced after diverging audits
 }
 
 func newMetrics() metrics {
@@ -58,6 +60,18 @@ func newMetrics() metrics {
 			Name:      "max_uint_errors",
 			Help:      "Total max uint errors.",
 		}),
+		AuditCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "audits",
+			Help:      "Total neighbor checksum audits.",
+		}),
+		ResyncCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "diverged_bin_resyncs",
+			Help:      "Total bins resynced after diverging audits.",
+		}),
 	}
 }
 
@@ -36,6 +36,7 @@ const (
 
 	DefaultSyncErrorSleepDur    = time.Minute
 	DefaultShallowBinsWarmupDur = time.Hour * 24
+	DefaultAuditInterval        = time.Hour
 
 	recalcPeersDur           = time.Minute * 5
 	histSyncTimeout          = time.Minute * 20
@@ -46,6 +47,16 @@ type Options struct {
 	Bins                 uint8
 	SyncSleepDur         time.Duration
 	ShallowBinsWarmupDur time.Duration
+	// AuditInterval is the interval of the checksum audits
+	// of the neighbors, zero disables the audits.
+	AuditInterval time.Duration
+	// MaxHistSyncing is the maximum number of the concurrently synced
+	// historical intervals, zero does not limit them.
+	MaxHistSyncing int
+	// Capabilities reports the features negotiated with the peers, only the
+	// neighbors which support the audit are audited. All the neighbors are
+	// audited if it is nil.
+	Capabilities p2p.CapabilityChecker
 }
 
 type Puller struct {
@@ -71,6 +82,13 @@ type Puller struct {
 	bins uint8 // how many bins do we support
 
 	activeHistoricalSyncing *atomic.Uint64
+
+	auditInterval time.Duration
+	capabilities  p2p.CapabilityChecker
+	histSyncSem   chan struct{} // bounds the concurrently synced historical intervals if not nil
+	// diverged are the bins of the peers which diverged
+	// in the last audit, the map key is the peer address
+	diverged map[string]map[uint8]struct{}
 }
 
 func New(stateStore storage.StateStorer, topology topology.Driver, reserveState postage.Radius, pullSync pullsync.Interface, blockLister p2p.Blocklister, logger log.Logger, o Options, warmupTime time.Duration) *Puller {
@@ -93,6 +111,12 @@ func New(stateStore storage.StateStorer, topology topology.Driver, reserveState
 		bins:                    bins,
 		activeHistoricalSyncing: atomic.NewUint64(0),
 		blockLister:             blockLister,
+		auditInterval:           o.AuditInterval,
+		capabilities:            o.Capabilities,
+		diverged:                make(map[string]map[uint8]struct{}),
+	}
+	if o.MaxHistSyncing > 0 {
+		p.histSyncSem = make(chan struct{}, o.MaxHistSyncing)
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -102,6 +126,11 @@ func New(stateStore storage.StateStorer, topology topology.Driver, reserveState
 
 	p.wg.Add(1)
 	go p.manage(ctx, warmupTime)
+
+	if p.auditInterval > 0 {
+		p.wg.Add(1)
+		go p.audit(ctx)
+	}
 	return p
 }
 
@@ -315,10 +344,21 @@ func (p *Puller) histSyncWorker(ctx context.Context, peer swarm.Address, bin uin
 			return
 		}
 
+		if p.histSyncSem != nil {
+			select {
+			case p.histSyncSem <- struct{}{}:
+			case <-ctx.Done():
+				loggerV2.Debug("histSyncWorker context cancelled", "peer_address", peer, "bin", bin, "cursor", cur)
+				return
+			}
+		}
 		syncStart := time.Now()
 		ctx, cancel := context.WithTimeout(ctx, histSyncTimeout)
 		top, err := p.syncer.SyncInterval(ctx, peer, bin, s, cur)
 		cancel()
+		if p.histSyncSem != nil {
+			<-p.histSyncSem
+		}
 
 		if top >= s {
 			if err := p.addPeerInterval(peer, bin, s, top); err != nil {
@@ -402,6 +442,89 @@ func (p *Puller) liveSyncWorker(ctx context.Context, peer swarm.Address, bin uin
 	}
 }
 
+// audit periodically compares the checksums of the chunks with the
+// neighbors and resyncs the bins of a neighbor which diverge in two
+// consecutive audits, as the chunks which are being synced at the time
+// of an audit are expected to diverge.
+func (p *Puller) audit(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.auditInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		radius := p.radius.StorageRadius()
+
+		var neighbors []swarm.Address
+		p.syncPeersMtx.Lock()
+		for _, peer := range p.syncPeers {
+			// the peers of the earlier versions do not serve the checksums
+			if p.capabilities != nil && !p.capabilities.Supports(peer.address, pullsync.CapabilityAudit) {
+				continue
+			}
+			if peer.po >= radius {
+				neighbors = append(neighbors, peer.address)
+			}
+		}
+		p.syncPeersMtx.Unlock()
+
+		diverged := make(map[string]map[uint8]struct{})
+		for _, peer := range neighbors {
+			bins, err := p.syncer.Audit(ctx, peer)
+			if err != nil {
+				p.logger.Debug("audit failed", "peer_address", peer, "error", err)
+				continue
+			}
+			p.metrics.AuditCounter.Inc()
+
+			prev := p.diverged[peer.ByteString()]
+			cur := make(map[uint8]struct{}, len(bins))
+			for _, bin := range bins {
+				cur[bin] = struct{}{}
+				if _, ok := prev[bin]; ok {
+					p.resyncPeerBin(ctx, peer, bin)
+				}
+			}
+			if len(cur) > 0 {
+				diverged[peer.ByteString()] = cur
+			}
+		}
+		p.diverged = diverged
+	}
+}
+
+// resyncPeerBin resets the synced intervals of the peer bin and
+// restarts the syncing of the bin, if the bin is being synced.
+func (p *Puller) resyncPeerBin(ctx context.Context, addr swarm.Address, bin uint8) {
+	p.syncPeersMtx.Lock()
+	defer p.syncPeersMtx.Unlock()
+
+	peer, ok := p.syncPeers[addr.ByteString()]
+	if !ok {
+		return
+	}
+	peer.Lock()
+	defer peer.Unlock()
+
+	if !peer.isBinSyncing(bin) || int(bin) >= len(peer.cursors) {
+		return
+	}
+	peer.cancelBin(bin)
+
+	if err := p.statestore.Delete(peerIntervalKey(addr, bin)); err != nil {
+		p.logger.Error(err, "reset peer interval", "peer_address", addr, "bin", bin)
+	}
+	p.metrics.ResyncCounter.Inc()
+	p.logger.Debug("resyncing diverged bin", "peer_address", addr, "bin", bin)
+	p.syncPeerBin(ctx, peer, bin, peer.cursors[bin])
+}
+
 func (p *Puller) Close() error {
 	p.logger.Info("puller shutting down")
 	p.cancel()
@@ -419,6 +542,11 @@ func (p *Puller) Close() error {
 	return nil
 }
 
+// AddPeerInterval marks the interval of the peer bin as synced.
+func (p *Puller) AddPeerInterval(peer swarm.Address, bin uint8, start, end uint64) error {
+	return p.addPeerInterval(peer, bin, start, end)
+}
+
 func (p *Puller) addPeerInterval(peer swarm.Address, bin uint8, start, end uint64) (err error) {
 
 	peerStreamKey := peerIntervalKey(peer, bin)
@@ -13,9 +13,12 @@ import (
 
 	"github.com/ethersphere/bee/pkg/intervalstore"
 	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p"
+	p2pmock "github.com/ethersphere/bee/pkg/p2p/mock"
 	"github.com/ethersphere/bee/pkg/postage"
 	bsMock "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
 	"github.com/ethersphere/bee/pkg/puller"
+	"github.com/ethersphere/bee/pkg/pullsync"
 	mockps "github.com/ethersphere/bee/pkg/pullsync/mock"
 	"github.com/ethersphere/bee/pkg/spinlock"
 	"github.com/ethersphere/bee/pkg/statestore/mock"
@@ -579,6 +582,139 @@ func TestPeerGone(t *testing.T) {
 	checkHistSyncingCount(t, p, 0)
 }
 
+func TestMaxHistSyncing(t *testing.T) {
+	t.Parallel()
+
+	var peers []kadMock.AddrTuple
+	for i := 0; i < 4; i++ {
+		peers = append(peers, kadMock.AddrTuple{Addr: swarm.RandAddress(t), PO: 1})
+	}
+
+	_, _, kad, pullsync := newPuller(t, opts{
+		kad: []kadMock.Option{
+			kadMock.WithEachPeerRevCalls(peers...),
+		},
+		pullSync: []mockps.Option{
+			mockps.WithCursors([]uint64{1000, 1000, 1000}),
+			mockps.WithLiveSyncBlock(),
+			mockps.WithHistSyncDelay(50 * time.Millisecond),
+		},
+		bins:           3,
+		bs:             bsMock.WithReserveState(&postage.ReserveState{StorageRadius: 1}),
+		maxHistSyncing: 2,
+	})
+	time.Sleep(100 * time.Millisecond)
+
+	kad.Trigger()
+
+	for _, p := range peers {
+		waitSyncCalledTimes(t, pullsync, p.Addr, 2)
+	}
+	if got := pullsync.MaxHistSyncing(); got != 2 {
+		t.Fatalf("got %d concurrently synced historical intervals, want 2", got)
+	}
+}
+
+func TestAuditResync(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		diverged []uint8
+		resync   bool
+	}{
+		{name: "no divergence"},
+		{name: "diverged bin", diverged: []uint8{1}, resync: true},
+		{name: "diverged bin not synced", diverged: []uint8{0}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			addr := swarm.RandAddress(t)
+			_, st, kad, pullsync := newPuller(t, opts{
+				kad: []kadMock.Option{
+					kadMock.WithEachPeerRevCalls(kadMock.AddrTuple{Addr: addr, PO: 1}),
+				},
+				pullSync: []mockps.Option{
+					mockps.WithCursors([]uint64{0, 10}),
+					mockps.WithAuditReply(tc.diverged...),
+					mockps.WithLiveSyncBlock(),
+				},
+				bins:          2,
+				bs:            bsMock.WithReserveState(&postage.ReserveState{StorageRadius: 1}),
+				auditInterval: 50 * time.Millisecond,
+			})
+
+			time.Sleep(100 * time.Millisecond)
+			kad.Trigger()
+			waitCheckCalls(t, []c{call(1, 1, 10)}, pullsync.SyncCalls, addr)
+
+			// the bin is resynced after diverging in two consecutive audits
+			err := spinlock.Wait(time.Second, func() bool {
+				return pullsync.AuditCalls(addr) >= 3
+			})
+			if err != nil {
+				t.Fatal("timed out waiting for audits")
+			}
+
+			calls := pullsync.SyncCalls(addr)
+			if got := len(calls) > 1; got != tc.resync {
+				t.Fatalf("got resync %t, want %t, calls %v", got, tc.resync, calls)
+			}
+			for _, call := range calls {
+				if call.Bin != 1 || call.From != 1 {
+					t.Fatalf("unexpected sync call %v", call)
+				}
+			}
+			if !tc.resync {
+				checkIntervals(t, st, addr, "[[1 10]]", 1)
+			}
+		})
+	}
+}
+
+func TestAuditCapability(t *testing.T) {
+	t.Parallel()
+
+	var (
+		addr      = swarm.RandAddress(t)
+		supported = swarm.RandAddress(t)
+	)
+	_, _, kad, ps := newPuller(t, opts{
+		kad: []kadMock.Option{
+			kadMock.WithEachPeerRevCalls(
+				kadMock.AddrTuple{Addr: addr, PO: 1},
+				kadMock.AddrTuple{Addr: supported, PO: 1},
+			),
+		},
+		pullSync: []mockps.Option{
+			mockps.WithCursors([]uint64{0, 10}),
+			mockps.WithLiveSyncBlock(),
+		},
+		bins:          2,
+		bs:            bsMock.WithReserveState(&postage.ReserveState{StorageRadius: 1}),
+		auditInterval: 50 * time.Millisecond,
+		capabilities: p2pmock.New(p2pmock.WithSupportsFunc(func(peer swarm.Address, c p2p.Capability) bool {
+			return peer.Equal(supported) && c == pullsync.CapabilityAudit
+		})),
+	})
+
+	time.Sleep(100 * time.Millisecond)
+	kad.Trigger()
+
+	// only the neighbor which negotiated the audit is audited
+	err := spinlock.Wait(time.Second, func() bool {
+		return ps.AuditCalls(supported) >= 2
+	})
+	if err != nil {
+		t.Fatal("timed out waiting for audits")
+	}
+	if n := ps.AuditCalls(addr); n != 0 {
+		t.Fatalf("got %d audits of the peer without the capability, want none", n)
+	}
+}
+
 func checkHistSyncingCount(t *testing.T, p *puller.Puller, c uint64) {
 	t.Helper()
 	if p.ActiveHistoricalSyncing() != c {
@@ -774,11 +910,14 @@ func waitLiveSyncCalledBins(t *testing.T, ps *mockps.PullSyncMock, addr swarm.Ad
 }
 
 type opts struct {
-	pullSync     []mockps.Option
-	kad          []kadMock.Option
-	bs           bsMock.Option
-	bins         uint8
-	syncSleepDur time.Duration
+	pullSync       []mockps.Option
+	kad            []kadMock.Option
+	bs             bsMock.Option
+	bins           uint8
+	syncSleepDur   time.Duration
+	auditInterval  time.Duration
+	maxHistSyncing int
+	capabilities   p2p.CapabilityChecker
 }
 
 func newPuller(t *testing.T, ops opts) (*puller.Puller, storage.StateStorer, *kadMock.Mock, *mockps.PullSyncMock) {
@@ -791,8 +930,11 @@ func newPuller(t *testing.T, ops opts) (*puller.Puller, storage.StateStorer, *ka
 	logger := log.Noop
 
 	o := puller.Options{
-		Bins:         ops.bins,
-		SyncSleepDur: ops.syncSleepDur,
+		Bins:           ops.bins,
+		SyncSleepDur:   ops.syncSleepDur,
+		AuditInterval:  ops.auditInterval,
+		MaxHistSyncing: ops.maxHistSyncing,
+		Capabilities:   ops.capabilities,
 	}
 	p := puller.New(s, kad, bs, ps, nil, logger, o, 0)
 
@@ -0,0 +1,24 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pullsync_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/p2p/streamtest"
+	"github.com/ethersphere/bee/pkg/pullsync/pb"
+)
+
+// seedCorpus matches the files of the streams captured from the tests.
+var seedCorpus = filepath.Join("testdata", "*.json")
+
+func FuzzGetRangeDecoder(f *testing.F) {
+	streamtest.FuzzDecoder(f, seedCorpus, "pullsync", "pullsync", func() streamtest.Message { return new(pb.GetRange) })
+}
+
+func FuzzWantDecoder(f *testing.F) {
+	streamtest.FuzzDecoder(f, seedCorpus, "pullsync", "pullsync", func() streamtest.Message { return new(pb.Want) })
+}
@@ -16,6 +16,8 @@ type metrics struct {
 	DbOps         prometheus.Counter   // number of db ops
 	DuplicateRuid prometheus.Counter   // number of duplicate RUID requests we got
 	LastReceived  *prometheus.GaugeVec // last timestamp of the received chunks per bin
+	AuditedBins   prometheus.Counter   // number of bins audited with the checksums
+	DivergedBins  prometheus.Counter   // number of audited bins which diverge
 }
 
 func newMetrics() metrics {
@@ -59,6 +61,18 @@ func newMetrics() metrics {
 				Name:      "last_received",
 				Help:      `The last timestamp of the received chunks per bin.`,
 			}, []string{"bin"}),
+		AuditedBins: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "audited_bins",
+			Help:      "Total bins audited with the peer checksums.",
+		}),
+		DivergedBins: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "diverged_bins",
+			Help:      "Total audited bins which diverge from the peer.",
+		}),
 	}
 }
 
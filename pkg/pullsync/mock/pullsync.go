@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"math"
 	"sync"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/pullsync"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -58,6 +59,21 @@ func WithLateSyncReply(r ...SyncReply) Option {
 	})
 }
 
+// WithHistSyncDelay delays the replies to the historical
+// sync requests, so that their concurrency can be observed.
+func WithHistSyncDelay(d time.Duration) Option {
+	return optionFunc(func(p *PullSyncMock) {
+		p.histSyncDelay = d
+	})
+}
+
+// WithAuditReply makes the audits report the diverging bins.
+func WithAuditReply(bins ...uint8) Option {
+	return optionFunc(func(p *PullSyncMock) {
+		p.auditReply = bins
+	})
+}
+
 const limit = 50
 
 type SyncCall struct {
@@ -93,6 +109,11 @@ type PullSyncMock struct {
 	blockLiveSync   bool
 	liveSyncReplies []uint64
 	liveSyncCalls   int
+	auditReply      []uint8
+	auditPeers      []swarm.Address
+	histSyncDelay   time.Duration
+	histSyncing     int
+	maxHistSyncing  int
 
 	lateReply       bool
 	lateCond        *sync.Cond
@@ -113,6 +134,14 @@ func NewPullSync(opts ...Option) *PullSyncMock {
 	return s
 }
 
+// MaxHistSyncing returns the maximum number of the
+// concurrently served historical sync requests.
+func (p *PullSyncMock) MaxHistSyncing() int {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.maxHistSyncing
+}
+
 func (p *PullSyncMock) SyncInterval(ctx context.Context, peer swarm.Address, bin uint8, from, to uint64) (topmost uint64, err error) {
 
 	isLive := to == math.MaxUint64
@@ -132,6 +161,30 @@ func (p *PullSyncMock) SyncInterval(ctx context.Context, peer swarm.Address, bin
 		return 0, p.syncErr
 	}
 
+	if !isLive {
+		p.mtx.Lock()
+		p.histSyncing++
+		if p.histSyncing > p.maxHistSyncing {
+			p.maxHistSyncing = p.histSyncing
+		}
+		p.mtx.Unlock()
+		defer func() {
+			p.mtx.Lock()
+			p.histSyncing--
+			p.mtx.Unlock()
+		}()
+
+		if p.histSyncDelay > 0 {
+			select {
+			case <-p.quit:
+				return 0, context.Canceled
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-time.After(p.histSyncDelay):
+			}
+		}
+	}
+
 	if isLive && p.lateReply {
 		p.lateCond.L.Lock()
 		for !p.lateChange {
@@ -210,6 +263,25 @@ func (p *PullSyncMock) GetCursors(_ context.Context, peer swarm.Address) ([]uint
 	return p.cursors, nil
 }
 
+func (p *PullSyncMock) Audit(_ context.Context, peer swarm.Address) ([]uint8, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.auditPeers = append(p.auditPeers, peer)
+	return p.auditReply, nil
+}
+
+// AuditCalls returns the number of audits of the peer.
+func (p *PullSyncMock) AuditCalls(peer swarm.Address) (n int) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for _, a := range p.auditPeers {
+		if a.Equal(peer) {
+			n++
+		}
+	}
+	return n
+}
+
 func (p *PullSyncMock) SyncCalls(peer swarm.Address) (res []SyncCall) {
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
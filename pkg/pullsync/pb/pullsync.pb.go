@@ -318,6 +318,162 @@ func (m *Delivery) GetStamp() []byte {
 	return nil
 }
 
+type GetChecksums struct {
+	Radius uint32 `protobuf:"varint,1,opt,name=Radius,proto3" json:"Radius,omitempty"`
+}
+
+func (m *GetChecksums) Reset()         { *m = GetChecksums{} }
+func (m *GetChecksums) String() string { return proto.CompactTextString(m) }
+func (*GetChecksums) ProtoMessage()    {}
+func (*GetChecksums) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d1dee042cf9c065c, []int{6}
+}
+func (m *GetChecksums) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetChecksums) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetChecksums.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GetChecksums) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetChecksums.Merge(m, src)
+}
+func (m *GetChecksums) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetChecksums) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetChecksums.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetChecksums proto.InternalMessageInfo
+
+func (m *GetChecksums) GetRadius() uint32 {
+	if m != nil {
+		return m.Radius
+	}
+	return 0
+}
+
+type BinChecksum struct {
+	Bin   uint32 `protobuf:"varint,1,opt,name=Bin,proto3" json:"Bin,omitempty"`
+	Count uint64 `protobuf:"varint,2,opt,name=Count,proto3" json:"Count,omitempty"`
+	Hash  []byte `protobuf:"bytes,3,opt,name=Hash,proto3" json:"Hash,omitempty"`
+}
+
+func (m *BinChecksum) Reset()         { *m = BinChecksum{} }
+func (m *BinChecksum) String() string { return proto.CompactTextString(m) }
+func (*BinChecksum) ProtoMessage()    {}
+func (*BinChecksum) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d1dee042cf9c065c, []int{7}
+}
+func (m *BinChecksum) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BinChecksum) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BinChecksum.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *BinChecksum) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BinChecksum.Merge(m, src)
+}
+func (m *BinChecksum) XXX_Size() int {
+	return m.Size()
+}
+func (m *BinChecksum) XXX_DiscardUnknown() {
+	xxx_messageInfo_BinChecksum.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BinChecksum proto.InternalMessageInfo
+
+func (m *BinChecksum) GetBin() uint32 {
+	if m != nil {
+		return m.Bin
+	}
+	return 0
+}
+
+func (m *BinChecksum) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *BinChecksum) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+type Checksums struct {
+	Radius uint32         `protobuf:"varint,1,opt,name=Radius,proto3" json:"Radius,omitempty"`
+	Bins   []*BinChecksum `protobuf:"bytes,2,rep,name=Bins,proto3" json:"Bins,omitempty"`
+}
+
+func (m *Checksums) Reset()         { *m = Checksums{} }
+func (m *Checksums) String() string { return proto.CompactTextString(m) }
+func (*Checksums) ProtoMessage()    {}
+func (*Checksums) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d1dee042cf9c065c, []int{8}
+}
+func (m *Checksums) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Checksums) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Checksums.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Checksums) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Checksums.Merge(m, src)
+}
+func (m *Checksums) XXX_Size() int {
+	return m.Size()
+}
+func (m *Checksums) XXX_DiscardUnknown() {
+	xxx_messageInfo_Checksums.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Checksums proto.InternalMessageInfo
+
+func (m *Checksums) GetRadius() uint32 {
+	if m != nil {
+		return m.Radius
+	}
+	return 0
+}
+
+func (m *Checksums) GetBins() []*BinChecksum {
+	if m != nil {
+		return m.Bins
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Syn)(nil), "pullsync.Syn")
 	proto.RegisterType((*Ack)(nil), "pullsync.Ack")
@@ -325,30 +481,38 @@ func init() {
 	proto.RegisterType((*Offer)(nil), "pullsync.Offer")
 	proto.RegisterType((*Want)(nil), "pullsync.Want")
 	proto.RegisterType((*Delivery)(nil), "pullsync.Delivery")
+	proto.RegisterType((*GetChecksums)(nil), "pullsync.GetChecksums")
+	proto.RegisterType((*BinChecksum)(nil), "pullsync.BinChecksum")
+	proto.RegisterType((*Checksums)(nil), "pullsync.Checksums")
 }
 
 func init() { proto.RegisterFile("pullsync.proto", fileDescriptor_d1dee042cf9c065c) }
 
 var fileDescriptor_d1dee042cf9c065c = []byte{
-	// 283 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x44, 0x90, 0x4f, 0x4b, 0xf4, 0x30,
-	0x18, 0xc4, 0x37, 0xfd, 0xb3, 0xef, 0xbe, 0x0f, 0x65, 0x91, 0x20, 0xd2, 0xc3, 0x12, 0x4b, 0xf0,
-	0xd0, 0x93, 0x17, 0x4f, 0xde, 0xdc, 0xba, 0xa8, 0x27, 0x85, 0x6c, 0x51, 0xf0, 0x96, 0xed, 0x66,
-	0xb5, 0xd8, 0x36, 0x25, 0xc9, 0x0a, 0xfd, 0x16, 0x7e, 0x2c, 0x8f, 0x7b, 0xf4, 0x28, 0xed, 0x17,
-	0x91, 0xc6, 0x16, 0x6f, 0xf3, 0x1b, 0x92, 0x99, 0xe1, 0x81, 0x79, 0xbd, 0x2f, 0x0a, 0xdd, 0x54,
-	0xd9, 0x79, 0xad, 0xa4, 0x91, 0x78, 0x36, 0x32, 0xf5, 0xc1, 0x5d, 0x37, 0x15, 0x3d, 0x05, 0x77,
-	0x99, 0xbd, 0xe1, 0x10, 0xfe, 0x5d, 0xef, 0x95, 0x96, 0x4a, 0x87, 0x28, 0x72, 0x63, 0x8f, 0x8d,
-	0x48, 0xaf, 0x60, 0x76, 0x2b, 0x0c, 0xe3, 0xd5, 0x8b, 0xc0, 0x47, 0xe0, 0x26, 0x79, 0x15, 0xa2,
-	0x08, 0xc5, 0x3e, 0xeb, 0x25, 0xc6, 0xe0, 0xdd, 0x28, 0x59, 0x86, 0x4e, 0x84, 0x62, 0x8f, 0x59,
-	0x8d, 0xe7, 0xe0, 0xa4, 0x32, 0x74, 0xad, 0xe3, 0xa4, 0x92, 0x5e, 0x82, 0xff, 0xb0, 0xdb, 0x09,
-	0xd5, 0x97, 0xa4, 0xb2, 0x2e, 0xa5, 0x36, 0x36, 0xc2, 0x63, 0x23, 0xe2, 0x13, 0x98, 0xde, 0x71,
-	0xfd, 0x2a, 0xb4, 0x0d, 0x0a, 0xd8, 0x40, 0xf4, 0x0c, 0xbc, 0x27, 0x5e, 0x19, 0xbc, 0x80, 0xff,
-	0x49, 0x6e, 0x1e, 0x45, 0x66, 0xa4, 0xb2, 0x7f, 0x03, 0xf6, 0x67, 0xd0, 0x7b, 0x98, 0xad, 0x44,
-	0x91, 0xbf, 0x0b, 0xd5, 0xf4, 0x1d, 0xcb, 0xed, 0x56, 0x09, 0xad, 0x87, 0x77, 0x23, 0xf6, 0x53,
-	0x57, 0xdc, 0xf0, 0xa1, 0xc1, 0x6a, 0x7c, 0x0c, 0xfe, 0xda, 0xf0, 0xb2, 0xb6, 0x6b, 0x03, 0xf6,
-	0x0b, 0xc9, 0xe2, 0xb3, 0x25, 0xe8, 0xd0, 0x12, 0xf4, 0xdd, 0x12, 0xf4, 0xd1, 0x91, 0xc9, 0xa1,
-	0x23, 0x93, 0xaf, 0x8e, 0x4c, 0x9e, 0x9d, 0x7a, 0xb3, 0x99, 0xda, 0x4b, 0x5e, 0xfc, 0x04, 0x00,
-	0x00, 0xff, 0xff, 0x7b, 0xc0, 0x49, 0xf9, 0x5b, 0x01, 0x00, 0x00,
+	// 367 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x92, 0xbd, 0x8e, 0xda, 0x40,
+	0x14, 0x85, 0xf1, 0x1f, 0x81, 0x8b, 0x41, 0xd1, 0x88, 0x44, 0x2e, 0x90, 0x83, 0x46, 0x51, 0xe4,
+	0x34, 0x14, 0x49, 0x95, 0x2e, 0x18, 0x14, 0x92, 0x86, 0x48, 0x03, 0x4a, 0xa4, 0x74, 0xc6, 0x0c,
+	0xc1, 0x02, 0xcf, 0x58, 0x33, 0xe3, 0x48, 0xbc, 0x45, 0x1e, 0x6b, 0x4b, 0xca, 0x2d, 0x57, 0xf0,
+	0x22, 0xab, 0x19, 0xdb, 0xbb, 0x74, 0xdb, 0x9d, 0xef, 0xea, 0xfa, 0x9e, 0xe3, 0x63, 0xc3, 0xa0,
+	0x28, 0x8f, 0x47, 0x79, 0x62, 0xe9, 0xa4, 0x10, 0x5c, 0x71, 0xd4, 0x69, 0x18, 0x7b, 0xe0, 0xac,
+	0x4e, 0x0c, 0xbf, 0x03, 0x67, 0x9a, 0x1e, 0x50, 0x00, 0xaf, 0x66, 0xa5, 0x90, 0x5c, 0xc8, 0xc0,
+	0x1a, 0x3b, 0x91, 0x4b, 0x1a, 0xc4, 0x5f, 0xa1, 0xb3, 0xa0, 0x8a, 0x24, 0xec, 0x2f, 0x45, 0xaf,
+	0xc1, 0x89, 0x33, 0x16, 0x58, 0x63, 0x2b, 0xf2, 0x88, 0x96, 0x08, 0x81, 0xfb, 0x4d, 0xf0, 0x3c,
+	0xb0, 0xc7, 0x56, 0xe4, 0x12, 0xa3, 0xd1, 0x00, 0xec, 0x35, 0x0f, 0x1c, 0x33, 0xb1, 0xd7, 0x1c,
+	0x7f, 0x01, 0xef, 0xe7, 0x6e, 0x47, 0x85, 0x36, 0x59, 0xf3, 0x22, 0xe7, 0x52, 0x99, 0x13, 0x2e,
+	0x69, 0x10, 0xbd, 0x85, 0xf6, 0xf7, 0x44, 0xee, 0xa9, 0x34, 0x87, 0x7c, 0x52, 0x13, 0x7e, 0x0f,
+	0xee, 0xef, 0x84, 0x29, 0x34, 0x82, 0x6e, 0x9c, 0xa9, 0x5f, 0x34, 0x55, 0x5c, 0x98, 0x67, 0x7d,
+	0xf2, 0x3c, 0xc0, 0x4b, 0xe8, 0xcc, 0xe9, 0x31, 0xfb, 0x47, 0xc5, 0x49, 0x7b, 0x4c, 0xb7, 0x5b,
+	0x41, 0xa5, 0xac, 0xf7, 0x1a, 0xd4, 0x51, 0xe7, 0x89, 0x4a, 0x6a, 0x07, 0xa3, 0xd1, 0x10, 0xbc,
+	0x95, 0x4a, 0xf2, 0xc2, 0xa4, 0xf5, 0x49, 0x05, 0xf8, 0x03, 0xf8, 0x0b, 0xaa, 0x66, 0x7b, 0x9a,
+	0x1e, 0x64, 0x99, 0x4b, 0x9d, 0x8e, 0x24, 0xdb, 0xac, 0xac, 0x4e, 0xf6, 0x49, 0x4d, 0xf8, 0x07,
+	0xf4, 0xe2, 0x8c, 0x35, 0x7b, 0xb7, 0xed, 0xf4, 0xab, 0x76, 0x86, 0xe0, 0xcd, 0x78, 0xc9, 0x54,
+	0x5d, 0x4f, 0x05, 0x3a, 0x88, 0x7e, 0xbd, 0xda, 0xd3, 0x68, 0xbc, 0x84, 0xee, 0x8b, 0x7e, 0xe8,
+	0x23, 0xb8, 0x71, 0xc6, 0x74, 0x47, 0x4e, 0xd4, 0xfb, 0xf4, 0x66, 0xf2, 0xf4, 0x6d, 0x6f, 0x52,
+	0x10, 0xb3, 0x12, 0x8f, 0xee, 0x2e, 0xa1, 0x75, 0xbe, 0x84, 0xd6, 0xc3, 0x25, 0xb4, 0xfe, 0x5f,
+	0xc3, 0xd6, 0xf9, 0x1a, 0xb6, 0xee, 0xaf, 0x61, 0xeb, 0x8f, 0x5d, 0x6c, 0x36, 0x6d, 0xf3, 0x33,
+	0x7c, 0x7e, 0x0c, 0x00, 0x00, 0xff, 0xff, 0x42, 0x4c, 0x42, 0x10, 0x1e, 0x02, 0x00, 0x00,
 }
 
 func (m *Syn) Marshal() (dAtA []byte, err error) {
@@ -562,6 +726,116 @@ func (m *Delivery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *GetChecksums) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetChecksums) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetChecksums) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Radius != 0 {
+		i = encodeVarintPullsync(dAtA, i, uint64(m.Radius))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *BinChecksum) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BinChecksum) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BinChecksum) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintPullsync(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Count != 0 {
+		i = encodeVarintPullsync(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Bin != 0 {
+		i = encodeVarintPullsync(dAtA, i, uint64(m.Bin))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Checksums) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Checksums) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Checksums) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Bins) > 0 {
+		for iNdEx := len(m.Bins) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Bins[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintPullsync(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Radius != 0 {
+		i = encodeVarintPullsync(dAtA, i, uint64(m.Radius))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintPullsync(dAtA []byte, offset int, v uint64) int {
 	offset -= sovPullsync(v)
 	base := offset
@@ -666,6 +940,55 @@ func (m *Delivery) Size() (n int) {
 	return n
 }
 
+func (m *GetChecksums) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Radius != 0 {
+		n += 1 + sovPullsync(uint64(m.Radius))
+	}
+	return n
+}
+
+func (m *BinChecksum) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Bin != 0 {
+		n += 1 + sovPullsync(uint64(m.Bin))
+	}
+	if m.Count != 0 {
+		n += 1 + sovPullsync(uint64(m.Count))
+	}
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovPullsync(uint64(l))
+	}
+	return n
+}
+
+func (m *Checksums) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Radius != 0 {
+		n += 1 + sovPullsync(uint64(m.Radius))
+	}
+	if len(m.Bins) > 0 {
+		for _, e := range m.Bins {
+			l = e.Size()
+			n += 1 + l + sovPullsync(uint64(l))
+		}
+	}
+	return n
+}
+
 func sovPullsync(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -1312,6 +1635,309 @@ func (m *Delivery) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *GetChecksums) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPullsync
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetChecksums: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetChecksums: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Radius", wireType)
+			}
+			m.Radius = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPullsync
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Radius |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPullsync(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPullsync
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPullsync
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BinChecksum) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPullsync
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BinChecksum: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BinChecksum: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bin", wireType)
+			}
+			m.Bin = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPullsync
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Bin |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPullsync
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPullsync
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPullsync
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPullsync
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = append(m.Hash[:0], dAtA[iNdEx:postIndex]...)
+			if m.Hash == nil {
+				m.Hash = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPullsync(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPullsync
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPullsync
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Checksums) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPullsync
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Checksums: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Checksums: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Radius", wireType)
+			}
+			m.Radius = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPullsync
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Radius |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bins", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPullsync
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPullsync
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPullsync
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Bins = append(m.Bins, &BinChecksum{})
+			if err := m.Bins[len(m.Bins)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPullsync(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPullsync
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPullsync
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipPullsync(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
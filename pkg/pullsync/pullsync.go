@@ -7,12 +7,15 @@
 package pullsync
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"math"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/bitvector"
@@ -33,11 +36,12 @@ import (
 const loggerName = "pullsync"
 
 const (
-	protocolName     = "pullsync"
-	protocolVersion  = "1.2.0"
-	streamName       = "pullsync"
-	cursorStreamName = "cursors"
-	cancelStreamName = "cancel"
+	protocolName       = "pullsync"
+	protocolVersion    = "1.2.0"
+	streamName         = "pullsync"
+	cursorStreamName   = "cursors"
+	cancelStreamName   = "cancel"
+	checksumStreamName = "checksums"
 )
 
 const (
@@ -45,6 +49,11 @@ const (
 	DefaultRateDuration = time.Minute * 10
 )
 
+// CapabilityAudit is advertised in the handshake by the nodes which serve
+// the checksums of the audits, so that the peers of the earlier versions
+// are not audited.
+const CapabilityAudit p2p.Capability = "pullsync-audit"
+
 var (
 	ErrUnsolicitedChunk = errors.New("peer sent unsolicited chunk")
 )
@@ -67,6 +76,9 @@ type Interface interface {
 	SyncInterval(ctx context.Context, peer swarm.Address, bin uint8, from, to uint64) (topmost uint64, err error)
 	// GetCursors retrieves all cursors from a downstream peer.
 	GetCursors(ctx context.Context, peer swarm.Address) ([]uint64, error)
+	// Audit compares the checksums of the chunks in the common neighborhood
+	// with the peer and returns the bins of the peer whose chunks diverge.
+	Audit(ctx context.Context, peer swarm.Address) ([]uint8, error)
 }
 
 type Syncer struct {
@@ -81,7 +93,8 @@ type Syncer struct {
 	radius         postage.Radius
 	overlayAddress swarm.Address
 
-	rate *rate.Rate
+	rate   *rate.Rate
+	synced uint64 // total number of chunks stored by syncing, accessed atomically
 
 	Interface
 	io.Closer
@@ -117,6 +130,10 @@ func (s *Syncer) Protocol() p2p.ProtocolSpec {
 				Name:    cursorStreamName,
 				Handler: s.cursorHandler,
 			},
+			{
+				Name:    checksumStreamName,
+				Handler: s.checksumHandler,
+			},
 		},
 	}
 }
@@ -256,6 +273,7 @@ func (s *Syncer) SyncInterval(ctx context.Context, peer swarm.Address, bin uint8
 		if err := s.storage.Put(ctx, storage.ModePutSync, chunksToPut...); err != nil {
 			return 0, errors.Join(chunkErr, fmt.Errorf("delivery put: %w", err))
 		}
+		atomic.AddUint64(&s.synced, uint64(len(chunksToPut)))
 		s.metrics.LastReceived.WithLabelValues(fmt.Sprintf("%d", bin)).Set(float64(time.Now().Unix()))
 	}
 
@@ -267,6 +285,11 @@ func (s *Syncer) SyncRate() float64 {
 	return s.rate.Rate()
 }
 
+// SyncedChunks returns the total number of chunks synced since the start.
+func (s *Syncer) SyncedChunks() uint64 {
+	return atomic.LoadUint64(&s.synced)
+}
+
 // handler handles an incoming request to sync an interval
 func (s *Syncer) handler(streamCtx context.Context, p p2p.Peer, stream p2p.Stream) (err error) {
 	select {
@@ -454,6 +477,178 @@ func (s *Syncer) cursorHandler(ctx context.Context, p p2p.Peer, stream p2p.Strea
 	return nil
 }
 
+// Audit compares the checksums of the chunks in the common neighborhood
+// with the peer and returns the bins of the peer whose chunks diverge.
+func (s *Syncer) Audit(ctx context.Context, peer swarm.Address) (bins []uint8, err error) {
+	loggerV2 := s.logger.V(2).Register()
+
+	stream, err := s.streamer.NewStream(ctx, peer, nil, protocolName, protocolVersion, checksumStreamName)
+	if err != nil {
+		return nil, fmt.Errorf("new stream: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = stream.Reset()
+			loggerV2.Debug("error auditing peer", "peer_address", peer, "error", err)
+		} else {
+			stream.FullClose()
+		}
+	}()
+
+	radius := s.radius.StorageRadius()
+
+	w, r := protobuf.NewWriterAndReader(stream)
+	if err = w.WriteMsgWithContext(ctx, &pb.GetChecksums{Radius: uint32(radius)}); err != nil {
+		return nil, fmt.Errorf("write get checksums: %w", err)
+	}
+
+	var resp pb.Checksums
+	if err = r.ReadMsgWithContext(ctx, &resp); err != nil {
+		return nil, fmt.Errorf("read checksums: %w", err)
+	}
+	if resp.Radius > uint32(swarm.MaxPO) {
+		return nil, fmt.Errorf("invalid radius %d", resp.Radius)
+	}
+
+	own, err := s.checksums(ctx, s.overlayAddress, radius, peer, uint8(resp.Radius))
+	if err != nil {
+		return nil, fmt.Errorf("checksums: %w", err)
+	}
+
+	remote := make(map[uint32]*pb.BinChecksum, len(resp.Bins))
+	for _, c := range resp.Bins {
+		remote[c.Bin] = c
+	}
+
+	var diverged []uint8
+	for bin := uint32(0); bin < uint32(swarm.MaxBins); bin++ {
+		local, rem := own[bin], remote[bin]
+		if local == nil && rem == nil {
+			continue
+		}
+		s.metrics.AuditedBins.Inc()
+		if local != nil && rem != nil && local.Count == rem.Count && bytes.Equal(local.Hash, rem.Hash) {
+			continue
+		}
+		s.metrics.DivergedBins.Inc()
+		loggerV2.Debug("bin diverges", "peer_address", peer, "bin", bin, "local", local, "remote", rem)
+		diverged = append(diverged, uint8(bin))
+	}
+
+	return peerBins(swarm.Proximity(s.overlayAddress.Bytes(), peer.Bytes()), diverged), nil
+}
+
+// peerBins maps the bins relative to the local node to the bins of the
+// peer at the proximity po which hold the chunks of the bins.
+func peerBins(po uint8, bins []uint8) []uint8 {
+	set := make(map[uint8]struct{})
+	for _, bin := range bins {
+		switch {
+		case bin < po:
+			set[bin] = struct{}{}
+		case bin > po:
+			set[po] = struct{}{}
+		default:
+			for b := po + 1; b < swarm.MaxBins; b++ {
+				set[b] = struct{}{}
+			}
+		}
+	}
+	res := make([]uint8, 0, len(set))
+	for bin := range set {
+		res = append(res, bin)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+func (s *Syncer) checksumHandler(ctx context.Context, p p2p.Peer, stream p2p.Stream) (err error) {
+	loggerV2 := s.logger.V(2).Register()
+
+	w, r := protobuf.NewWriterAndReader(stream)
+	defer func() {
+		if err != nil {
+			_ = stream.Reset()
+			loggerV2.Debug("error computing checksums for peer", "peer_address", p.Address, "error", err)
+		} else {
+			_ = stream.FullClose()
+		}
+	}()
+
+	var req pb.GetChecksums
+	if err := r.ReadMsgWithContext(ctx, &req); err != nil {
+		return fmt.Errorf("read get checksums: %w", err)
+	}
+	if req.Radius > uint32(swarm.MaxPO) {
+		return fmt.Errorf("invalid radius %d", req.Radius)
+	}
+
+	radius := s.radius.StorageRadius()
+	checksums, err := s.checksums(ctx, p.Address, uint8(req.Radius), s.overlayAddress, radius)
+	if err != nil {
+		return fmt.Errorf("checksums: %w", err)
+	}
+
+	resp := &pb.Checksums{Radius: uint32(radius)}
+	for bin := uint32(0); bin < uint32(swarm.MaxBins); bin++ {
+		if c, ok := checksums[bin]; ok {
+			resp.Bins = append(resp.Bins, c)
+		}
+	}
+	if err := w.WriteMsgWithContext(ctx, resp); err != nil {
+		return fmt.Errorf("write checksums: %w", err)
+	}
+	return nil
+}
+
+// checksums computes the checksums of the local chunks which are within
+// the radius of both the base and the other address, grouped by the bins
+// relative to the base. One of the addresses must be the local overlay.
+func (s *Syncer) checksums(ctx context.Context, base swarm.Address, baseRadius uint8, other swarm.Address, otherRadius uint8) (map[uint32]*pb.BinChecksum, error) {
+	// the local bins below the local radius have no common chunks
+	start := otherRadius
+	if base.Equal(s.overlayAddress) {
+		start = baseRadius
+	}
+
+	s.metrics.DbOps.Inc()
+	cursors, err := s.storage.Cursors(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	checksums := make(map[uint32]*pb.BinChecksum)
+	for bin := start; bin < swarm.MaxBins && int(bin) < len(cursors); bin++ {
+		for from := uint64(1); from <= cursors[bin]; {
+			s.metrics.DbOps.Inc()
+			addrs, top, err := s.storage.IntervalChunks(ctx, bin, from, cursors[bin], maxPage)
+			if err != nil {
+				return nil, err
+			}
+			for _, addr := range addrs {
+				if swarm.Proximity(addr.Bytes(), base.Bytes()) < baseRadius || swarm.Proximity(addr.Bytes(), other.Bytes()) < otherRadius {
+					continue
+				}
+				b := uint32(swarm.Proximity(addr.Bytes(), base.Bytes()))
+				c, ok := checksums[b]
+				if !ok {
+					c = &pb.BinChecksum{Bin: b, Hash: make([]byte, swarm.HashSize)}
+					checksums[b] = c
+				}
+				c.Count++
+				for i, v := range addr.Bytes() {
+					c.Hash[i] ^= v
+				}
+			}
+			if top < from {
+				break
+			}
+			from = top + 1
+		}
+	}
+	return checksums, nil
+}
+
 func (s *Syncer) Close() error {
 	s.logger.Info("pull syncer shutting down")
 	close(s.quit)
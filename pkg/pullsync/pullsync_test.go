@@ -7,6 +7,7 @@ package pullsync_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"testing"
 
@@ -233,6 +234,65 @@ func TestGetCursorsError(t *testing.T) {
 	}
 }
 
+func TestAudit(t *testing.T) {
+	t.Parallel()
+
+	overlay := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+	cursors := make([]uint64, swarm.MaxBins)
+	cursors[0] = uint64(len(addrs))
+
+	for _, tc := range []struct {
+		name       string
+		clientAddr []swarm.Address
+		want       []uint8
+	}{
+		{
+			name:       "same chunks",
+			clientAddr: addrs,
+		},
+		{
+			name:       "missing chunk",
+			clientAddr: addrs[1:],
+			want:       []uint8{swarm.Proximity(addrs[0].Bytes(), overlay.Bytes())},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ps, _ := newPullSync(nil, mock.WithCursors(cursors), mock.WithIntervalsResp(addrs, cursors[0], nil))
+			recorder := streamtest.New(streamtest.WithProtocols(ps.Protocol()), streamtest.WithBaseAddr(overlay))
+			psClient, _ := newPullSync(recorder, mock.WithCursors(cursors), mock.WithIntervalsResp(tc.clientAddr, cursors[0], nil))
+
+			got, err := psClient.Audit(context.Background(), overlay)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Fatalf("got diverged bins %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPeerBins(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		po   uint8
+		bins []uint8
+		want []uint8
+	}{
+		{po: 3, bins: []uint8{1, 2}, want: []uint8{1, 2}},
+		{po: 3, bins: []uint8{4, 5}, want: []uint8{3}},
+		{po: 29, bins: []uint8{29}, want: []uint8{30, 31}},
+	} {
+		if got := pullsync.PeerBins(tc.po, tc.bins); fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("po %d bins %v: got %v, want %v", tc.po, tc.bins, got, tc.want)
+		}
+	}
+}
+
 func haveChunks(t *testing.T, s *mock.PullStorage, addrs ...swarm.Address) {
 	t.Helper()
 	for _, a := range addrs {
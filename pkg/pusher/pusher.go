@@ -13,6 +13,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"runtime/pprof"
 	"strconv"
 	"sync"
 	"time"
@@ -20,6 +21,7 @@ import (
 	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/profiling"
 	"github.com/ethersphere/bee/pkg/pushsync"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -84,7 +86,12 @@ func New(networkID uint64, storer storage.Storer, pushSyncer pushsync.PushSyncer
 		attempts:          &attempts{retryCount: retryCount, attempts: make(map[string]int)},
 		smuggler:          make(chan OpChan),
 	}
-	go p.chunksWorker(warmupTime, tracer)
+	go func() {
+		// label the profiles of the pushing of the local uploads
+		pprof.Do(context.Background(), profiling.Labels("pusher"), func(context.Context) {
+			p.chunksWorker(warmupTime, tracer)
+		})
+	}()
 	return p
 }
 
@@ -22,6 +22,7 @@ import (
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/pricer"
 	"github.com/ethersphere/bee/pkg/pushsync/pb"
+	"github.com/ethersphere/bee/pkg/sctx"
 	"github.com/ethersphere/bee/pkg/skippeers"
 	"github.com/ethersphere/bee/pkg/soc"
 	"github.com/ethersphere/bee/pkg/storage"
@@ -209,7 +210,7 @@ func (ps *PushSync) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream)
 				return fmt.Errorf("pushsync replication invalid stamp: %w", err)
 			}
 
-			_, err = ps.storer.Put(ctxd, storage.ModePutSync, chunk)
+			_, err = ps.storer.Put(sctx.SetPushSync(ctxd), storage.ModePutSync, chunk)
 			if err != nil {
 				return fmt.Errorf("chunk store: %w", err)
 			}
@@ -246,7 +247,7 @@ func (ps *PushSync) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream)
 				logger.Warning("forwarder, invalid stamp for chunk", "chunk_address", chunkAddress)
 				return
 			}
-			_, err = ps.storer.Put(ctx, storage.ModePutSync, verifiedChunk)
+			_, err = ps.storer.Put(sctx.SetPushSync(ctx), storage.ModePutSync, verifiedChunk)
 			if err != nil {
 				logger.Warning("within depth peer's attempt to store chunk failed", "chunk_address", verifiedChunk.Address(), "error", err)
 			}
@@ -263,7 +264,7 @@ func (ps *PushSync) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream)
 				return fmt.Errorf("pushsync storer invalid stamp: %w", err)
 			}
 
-			_, err = ps.storer.Put(ctx, storage.ModePutSync, chunk)
+			_, err = ps.storer.Put(sctx.SetPushSync(ctx), storage.ModePutSync, chunk)
 			if err != nil {
 				return fmt.Errorf("chunk store: %w", err)
 			}
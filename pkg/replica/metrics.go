@@ -0,0 +1,52 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package replica
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	Syncs      prometheus.Counter // number of the replications of the primary node state
+	SyncErrors prometheus.Counter // number of the failed replications
+	Pinned     prometheus.Counter // number of the replicated pins
+	Promoted   prometheus.Gauge   // set to one once the node is promoted
+}
+
+func newMetrics() metrics {
+	subsystem := "replica"
+
+	return metrics{
+		Syncs: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "syncs",
+			Help:      "Total replications of the primary node state.",
+		}),
+		SyncErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "sync_errors",
+			Help:      "Total failed replications of the primary node state.",
+		}),
+		Pinned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "pinned",
+			Help:      "Total pins replicated from the primary node.",
+		}),
+		Promoted: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "promoted",
+			Help:      "Set to one once the node is promoted from the standby mode.",
+		}),
+	}
+}
+
+func (r *Replicator) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(r.metrics)
+}
@@ -0,0 +1,362 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package replica provides the warm standby mode of the gateway nodes. The
+// standby node replicates the pins, the aliases and the upload tags of the
+// primary node from its debug API, and it is promoted to serve the writes
+// when the primary node fails.
+package replica
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/alias"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/pinning"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "replica"
+
+// DefaultInterval is the default interval of the replication.
+const DefaultInterval = 30 * time.Second
+
+// StatePath is the path of the state of the primary node on its debug API.
+const StatePath = "/replica/state"
+
+// tagsPage is the page size of the listing of the tags.
+const tagsPage = 1000
+
+// Options are the options of the Replicator.
+type Options struct {
+	// PrimaryURL is the base URL of the debug API of the primary node.
+	PrimaryURL string
+	// Interval is the interval of the replication.
+	Interval time.Duration
+	// Token is the bearer token of the restricted debug API of the primary node.
+	Token string
+}
+
+// Tag is the replicated upload tag.
+type Tag struct {
+	Uid       uint32        `json:"uid"`
+	Name      string        `json:"name,omitempty"`
+	Metadata  []byte        `json:"metadata,omitempty"`
+	Address   swarm.Address `json:"address"`
+	StartedAt time.Time     `json:"startedAt"`
+	Total     int64         `json:"total"`
+	Split     int64         `json:"split"`
+	Seen      int64         `json:"seen"`
+	Stored    int64         `json:"stored"`
+	Sent      int64         `json:"sent"`
+	Synced    int64         `json:"synced"`
+}
+
+// State is the replicated state of a node.
+type State struct {
+	Pins    []swarm.Address `json:"pins"`
+	Aliases []alias.Alias   `json:"aliases"`
+	Tags    []Tag           `json:"tags"`
+}
+
+// Snapshot returns the replicated state of the node.
+func Snapshot(ctx context.Context, pins pinning.Interface, aliases *alias.Registry, ts *tags.Tags) (State, error) {
+	var (
+		s   State
+		err error
+	)
+
+	s.Pins, err = pins.Pins()
+	if err != nil {
+		return State{}, fmt.Errorf("pins: %w", err)
+	}
+
+	s.Aliases, err = aliases.List()
+	if err != nil {
+		return State{}, fmt.Errorf("aliases: %w", err)
+	}
+
+	for offset := 0; ; offset += tagsPage {
+		list, err := ts.ListAll(ctx, offset, tagsPage)
+		if err != nil {
+			return State{}, fmt.Errorf("tags: %w", err)
+		}
+		for _, t := range list {
+			s.Tags = append(s.Tags, Tag{
+				Uid:       t.Uid,
+				Name:      t.Name,
+				Metadata:  t.Metadata,
+				Address:   t.Address,
+				StartedAt: t.StartedAt,
+				Total:     t.Get(tags.TotalChunks),
+				Split:     t.Get(tags.StateSplit),
+				Seen:      t.Get(tags.StateSeen),
+				Stored:    t.Get(tags.StateStored),
+				Sent:      t.Get(tags.StateSent),
+				Synced:    t.Get(tags.StateSynced),
+			})
+		}
+		if len(list) < tagsPage {
+			break
+		}
+	}
+
+	return s, nil
+}
+
+// Status is the status of the replication.
+type Status struct {
+	Standby   bool
+	Primary   string
+	LastSync  time.Time
+	LastError string
+	Pins      int
+	Aliases   int
+	Tags      int
+}
+
+// Replicator replicates the state of the primary node while
+// the node is in the standby mode.
+type Replicator struct {
+	pins     pinning.Interface
+	aliases  *alias.Registry
+	tags     *tags.Tags
+	client   *http.Client
+	url      string
+	token    string
+	interval time.Duration
+	logger   log.Logger
+	metrics  metrics
+
+	mu     sync.Mutex
+	status Status
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a new Replicator of the node in the standby mode.
+func New(pins pinning.Interface, aliases *alias.Registry, ts *tags.Tags, o Options, logger log.Logger) *Replicator {
+	if o.Interval <= 0 {
+		o.Interval = DefaultInterval
+	}
+	url := strings.TrimSuffix(o.PrimaryURL, "/")
+	return &Replicator{
+		pins:     pins,
+		aliases:  aliases,
+		tags:     ts,
+		client:   &http.Client{Timeout: o.Interval},
+		url:      url,
+		token:    o.Token,
+		interval: o.Interval,
+		logger:   logger.WithName(loggerName).Register(),
+		metrics:  newMetrics(),
+		status:   Status{Standby: true, Primary: url},
+		cancel:   func() {},
+	}
+}
+
+// Start starts the periodic replication.
+func (r *Replicator) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := r.Sync(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				r.logger.Error(err, "replicate primary node state failed", "primary", r.url)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Sync replicates the state of the primary node. The pins and the aliases
+// which are not on the primary node are removed. The failures of the single
+// pins do not stop the replication, the last one is returned.
+func (r *Replicator) Sync(ctx context.Context) (err error) {
+	defer func() {
+		r.mu.Lock()
+		if err != nil {
+			r.status.LastError = err.Error()
+		} else {
+			r.status.LastError = ""
+			r.status.LastSync = time.Now()
+		}
+		r.mu.Unlock()
+
+		r.metrics.Syncs.Inc()
+		if err != nil {
+			r.metrics.SyncErrors.Inc()
+		}
+	}()
+
+	s, err := r.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	var pinErr error
+	pinned, err := r.pins.Pins()
+	if err != nil {
+		return fmt.Errorf("pins: %w", err)
+	}
+	primary := make(map[string]struct{}, len(s.Pins))
+	for _, ref := range s.Pins {
+		primary[ref.ByteString()] = struct{}{}
+	}
+	for _, ref := range pinned {
+		if _, ok := primary[ref.ByteString()]; ok {
+			delete(primary, ref.ByteString())
+			continue
+		}
+		if err := r.pins.DeletePin(ctx, ref); err != nil {
+			pinErr = fmt.Errorf("delete pin %s: %w", ref, err)
+		}
+	}
+	for _, ref := range s.Pins {
+		if _, ok := primary[ref.ByteString()]; !ok {
+			continue
+		}
+		if err := r.pins.CreatePin(ctx, ref, true); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+			pinErr = fmt.Errorf("create pin %s: %w", ref, err)
+			continue
+		}
+		r.metrics.Pinned.Inc()
+	}
+
+	local, err := r.aliases.List()
+	if err != nil {
+		return fmt.Errorf("aliases: %w", err)
+	}
+	names := make(map[string]struct{}, len(s.Aliases))
+	for _, a := range s.Aliases {
+		names[a.Name] = struct{}{}
+		if err := r.aliases.Set(a); err != nil {
+			return fmt.Errorf("set alias %s: %w", a.Name, err)
+		}
+	}
+	for _, a := range local {
+		if _, ok := names[a.Name]; ok {
+			continue
+		}
+		if err := r.aliases.Delete(a.Name); err != nil && !errors.Is(err, alias.ErrNotFound) {
+			return fmt.Errorf("delete alias %s: %w", a.Name, err)
+		}
+	}
+
+	for _, t := range s.Tags {
+		err := r.tags.Import(&tags.Tag{
+			Uid:       t.Uid,
+			Name:      t.Name,
+			Metadata:  t.Metadata,
+			Address:   t.Address,
+			StartedAt: t.StartedAt,
+			Total:     t.Total,
+			Split:     t.Split,
+			Seen:      t.Seen,
+			Stored:    t.Stored,
+			Sent:      t.Sent,
+			Synced:    t.Synced,
+		})
+		if err != nil {
+			return fmt.Errorf("import tag %d: %w", t.Uid, err)
+		}
+	}
+
+	r.mu.Lock()
+	r.status.Pins = len(s.Pins)
+	r.status.Aliases = len(s.Aliases)
+	r.status.Tags = len(s.Tags)
+	r.mu.Unlock()
+
+	return pinErr
+}
+
+// fetch returns the state of the primary node.
+func (r *Replicator) fetch(ctx context.Context) (State, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url+StatePath, nil)
+	if err != nil {
+		return State{}, err
+	}
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return State{}, fmt.Errorf("fetch state: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return State{}, fmt.Errorf("fetch state: unexpected status %s", resp.Status)
+	}
+
+	var s State
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+		return State{}, fmt.Errorf("decode state: %w", err)
+	}
+	return s, nil
+}
+
+// Standby reports whether the node is in the standby mode.
+func (r *Replicator) Standby() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status.Standby
+}
+
+// Status returns the status of the replication.
+func (r *Replicator) Status() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// Promote stops the replication and ends the standby mode, so that the
+// node serves the writes. Promoting the promoted node has no effect.
+func (r *Replicator) Promote() {
+	r.mu.Lock()
+	if !r.status.Standby {
+		r.mu.Unlock()
+		return
+	}
+	r.status.Standby = false
+	r.mu.Unlock()
+
+	r.cancel()
+	r.wg.Wait()
+
+	r.metrics.Promoted.Set(1)
+	r.logger.Info("node promoted from the standby mode", "primary", r.url)
+}
+
+// Close stops the replication.
+func (r *Replicator) Close() error {
+	r.cancel()
+	r.wg.Wait()
+	return nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package replica_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/alias"
+	"github.com/ethersphere/bee/pkg/log"
+	pinning "github.com/ethersphere/bee/pkg/pinning/mock"
+	"github.com/ethersphere/bee/pkg/replica"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+type node struct {
+	pins    *pinning.ServiceMock
+	aliases *alias.Registry
+	tags    *tags.Tags
+}
+
+func newNode() node {
+	return node{
+		pins:    pinning.NewServiceMock(),
+		aliases: alias.NewRegistry(statestore.NewStateStore(), nil),
+		tags:    tags.NewTags(statestore.NewStateStore(), log.Noop),
+	}
+}
+
+// newPrimary serves the state of the node as its debug API.
+func newPrimary(t *testing.T, n node, token string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != replica.StatePath || (token != "" && r.Header.Get("Authorization") != "Bearer "+token) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		s, err := replica.Snapshot(r.Context(), n.pins, n.aliases, n.tags)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(s)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestReplicator_Sync(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		primary = newNode()
+		standby = newNode()
+		ref1    = swarm.MustParseHexAddress("aa00000000000000000000000000000000000000000000000000000000000000")
+		ref2    = swarm.MustParseHexAddress("bb00000000000000000000000000000000000000000000000000000000000000")
+	)
+
+	srv := newPrimary(t, primary, "secret")
+
+	for _, ref := range []swarm.Address{ref1, ref2} {
+		if err := primary.pins.CreatePin(ctx, ref, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := primary.aliases.Set(alias.Alias{Name: "site", Reference: ref1}); err != nil {
+		t.Fatal(err)
+	}
+	tag, err := primary.tags.CreateNamed(10, "release", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag.Inc(tags.StateStored)
+
+	// the pins and the aliases of the standby node
+	// which are not on the primary node are removed
+	stale := swarm.MustParseHexAddress("cc00000000000000000000000000000000000000000000000000000000000000")
+	if err := standby.pins.CreatePin(ctx, stale, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := standby.aliases.Set(alias.Alias{Name: "stale", Reference: stale}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := replica.New(standby.pins, standby.aliases, standby.tags, replica.Options{PrimaryURL: srv.URL + "/", Token: "secret"}, log.Noop)
+	if err := r.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	pins, err := standby.pins.Pins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pins) != 2 || !pins[0].Equal(ref1) || !pins[1].Equal(ref2) {
+		t.Fatalf("got pins %v, want %v", pins, []swarm.Address{ref1, ref2})
+	}
+	aliases, err := standby.aliases.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aliases) != 1 || aliases[0].Name != "site" || !aliases[0].Reference.Equal(ref1) {
+		t.Fatalf("got aliases %v", aliases)
+	}
+	got, err := standby.tags.Get(tag.Uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "release" || got.Total != 10 || got.Stored != 1 {
+		t.Fatalf("got tag %+v", got)
+	}
+
+	// the removals on the primary node are replicated
+	if err := primary.pins.DeletePin(ctx, ref2); err != nil {
+		t.Fatal(err)
+	}
+	if err := primary.aliases.Delete("site"); err != nil {
+		t.Fatal(err)
+	}
+	tag.Inc(tags.StateStored)
+
+	if err := r.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if pins, _ := standby.pins.Pins(); len(pins) != 1 || !pins[0].Equal(ref1) {
+		t.Fatalf("got pins %v, want %v", pins, []swarm.Address{ref1})
+	}
+	if aliases, _ := standby.aliases.List(); len(aliases) != 0 {
+		t.Fatalf("got aliases %v, want none", aliases)
+	}
+	if got.Stored != 2 {
+		t.Fatalf("got %d stored chunks, want 2", got.Stored)
+	}
+
+	s := r.Status()
+	if !s.Standby || s.LastSync.IsZero() || s.LastError != "" || s.Pins != 1 || s.Aliases != 0 || s.Tags != 1 {
+		t.Fatalf("got status %+v", s)
+	}
+
+	t.Run("unauthorized", func(t *testing.T) {
+		t.Parallel()
+
+		r := replica.New(newNode().pins, standby.aliases, standby.tags, replica.Options{PrimaryURL: srv.URL}, log.Noop)
+		if err := r.Sync(ctx); err == nil {
+			t.Fatal("expected error")
+		}
+		if s := r.Status(); s.LastError == "" || !s.LastSync.IsZero() {
+			t.Fatalf("got status %+v", s)
+		}
+	})
+}
+
+func TestReplicator_Promote(t *testing.T) {
+	t.Parallel()
+
+	primary := newNode()
+	standby := newNode()
+	ref := swarm.MustParseHexAddress("aa00000000000000000000000000000000000000000000000000000000000000")
+	if err := primary.pins.CreatePin(context.Background(), ref, false); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := newPrimary(t, primary, "")
+
+	r := replica.New(standby.pins, standby.aliases, standby.tags, replica.Options{PrimaryURL: srv.URL, Interval: time.Hour}, log.Noop)
+	r.Start()
+	t.Cleanup(func() { _ = r.Close() })
+
+	// the state is replicated once the replication starts
+	deadline := time.Now().Add(5 * time.Second)
+	for r.Status().LastSync.IsZero() {
+		if time.Now().After(deadline) {
+			t.Fatal("state not replicated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if !r.Standby() {
+		t.Fatal("expected standby mode")
+	}
+	r.Promote()
+	if r.Standby() {
+		t.Fatal("expected promoted node")
+	}
+	// promoting the promoted node has no effect
+	r.Promote()
+
+	if pins, _ := standby.pins.Pins(); len(pins) != 1 || !pins[0].Equal(ref) {
+		t.Fatalf("got pins %v, want %v", pins, []swarm.Address{ref})
+	}
+}
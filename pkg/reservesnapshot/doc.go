@@ -0,0 +1,18 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package reservesnapshot contains the protocol which bootstraps the reserve of
+a new node from the reserve snapshot of a neighbor, instead of pulling it chunk
+by chunk with the pullsync protocol.
+
+The downloading node requests the snapshot of its neighborhood at its storage
+radius. A neighbor which consents to serve snapshots responds with the cursors
+of its bins at the time of the snapshot and streams the chunks of the bins up
+to the cursors in pages. The downloading node validates the postage stamps and
+the content of every chunk before storing it. When the snapshot is complete,
+the intervals of the neighbor up to the cursors are marked as synced, so that
+pullsync continues from where the snapshot ends.
+*/
+package reservesnapshot
@@ -0,0 +1,66 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package reservesnapshot
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	Served        prometheus.Counter // number of snapshots served
+	Refused       prometheus.Counter // number of snapshot requests refused
+	ChunksServed  prometheus.Counter // number of chunks served
+	Downloaded    prometheus.Counter // number of snapshots downloaded
+	ChunksStored  prometheus.Counter // number of downloaded chunks stored
+	InvalidStamps prometheus.Counter // number of downloaded chunks with invalid stamps
+}
+
+func newMetrics() metrics {
+	subsystem := "reservesnapshot"
+
+	return metrics{
+		Served: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "served",
+			Help:      "Total snapshots served.",
+		}),
+		Refused: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "refused",
+			Help:      "Total snapshot requests refused.",
+		}),
+		ChunksServed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "chunks_served",
+			Help:      "Total chunks served.",
+		}),
+		Downloaded: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "downloaded",
+			Help:      "Total snapshots downloaded.",
+		}),
+		ChunksStored: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "chunks_stored",
+			Help:      "Total downloaded chunks stored.",
+		}),
+		InvalidStamps: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "invalid_stamps",
+			Help:      "Total downloaded chunks with invalid stamps.",
+		}),
+	}
+}
+
+func (s *Service) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(s.metrics)
+}
@@ -0,0 +1,443 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package reservesnapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/protobuf"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/pullsync/pullstorage"
+	"github.com/ethersphere/bee/pkg/reservesnapshot/pb"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "reservesnapshot"
+
+const (
+	protocolName    = "reservesnapshot"
+	protocolVersion = "1.0.0"
+	streamName      = "snapshot"
+)
+
+var (
+	// ErrRefused is returned when the peer does not serve the snapshot.
+	ErrRefused = errors.New("snapshot refused")
+	// ErrUnsolicitedChunk is returned when the peer sends
+	// a chunk which is outside of the requested neighborhood.
+	ErrUnsolicitedChunk = errors.New("peer sent unsolicited chunk")
+	// ErrNoNeighbors is returned when there are no connected neighbors
+	// to bootstrap the reserve from.
+	ErrNoNeighbors = errors.New("no connected neighbors")
+)
+
+const (
+	// pageSize is the number of chunks in a page, the page
+	// must fit in the maximum size of a protobuf message.
+	pageSize = 16
+	// intervalLimit is the number of chunk addresses
+	// collected from the storage at once.
+	intervalLimit = 250
+	// maxServing is the number of snapshots served at once.
+	maxServing = 1
+
+	pageTimeout = time.Minute
+)
+
+// Options are the snapshot service options.
+type Options struct {
+	// Serve allows the neighbors to download the reserve snapshots.
+	Serve bool
+}
+
+// IntervalAdder marks the intervals of the peer bins as synced.
+type IntervalAdder interface {
+	AddPeerInterval(peer swarm.Address, bin uint8, start, end uint64) error
+}
+
+// Result describes a downloaded snapshot.
+type Result struct {
+	Bin     uint8    // first bin of the peer in the snapshot
+	Cursors []uint64 // bin cursors of the peer at the time of the snapshot
+	Chunks  int      // number of chunks stored
+}
+
+type Service struct {
+	streamer   p2p.Streamer
+	storage    pullstorage.Storer
+	validStamp postage.ValidStampFn
+	radius     postage.Radius
+	overlay    swarm.Address
+	logger     log.Logger
+	metrics    metrics
+	serve      bool
+	serving    chan struct{}
+	quit       chan struct{}
+	wg         sync.WaitGroup
+}
+
+func New(streamer p2p.Streamer, storage pullstorage.Storer, validStamp postage.ValidStampFn, logger log.Logger, radius postage.Radius, overlay swarm.Address, o Options) *Service {
+	return &Service{
+		streamer:   streamer,
+		storage:    storage,
+		validStamp: validStamp,
+		radius:     radius,
+		overlay:    overlay,
+		logger:     logger.WithName(loggerName).Register(),
+		metrics:    newMetrics(),
+		serve:      o.Serve,
+		serving:    make(chan struct{}, maxServing),
+		quit:       make(chan struct{}),
+	}
+}
+
+func (s *Service) Protocol() p2p.ProtocolSpec {
+	return p2p.ProtocolSpec{
+		Name:    protocolName,
+		Version: protocolVersion,
+		StreamSpecs: []p2p.StreamSpec{
+			{
+				Name:    streamName,
+				Handler: s.handler,
+			},
+		},
+	}
+}
+
+// Bootstrap downloads the snapshot of the reserve from the closest neighbor
+// which serves it and marks the intervals of the neighbor up to the cursors
+// of the snapshot as synced.
+func (s *Service) Bootstrap(ctx context.Context, peers topology.PeerIterator, intervals IntervalAdder) error {
+	radius := s.radius.StorageRadius()
+
+	var neighbors []swarm.Address
+	err := peers.EachConnectedPeer(func(addr swarm.Address, po uint8) (bool, bool, error) {
+		// peers are iterated from the closest bin
+		if po < radius {
+			return true, false, nil
+		}
+		neighbors = append(neighbors, addr)
+		return false, false, nil
+	}, topology.Filter{})
+	if err != nil {
+		return err
+	}
+	if len(neighbors) == 0 {
+		return ErrNoNeighbors
+	}
+
+	for _, peer := range neighbors {
+		start := time.Now()
+		res, err := s.Download(ctx, peer, radius)
+		if errors.Is(err, ErrRefused) {
+			s.logger.Debug("snapshot refused", "peer_address", peer)
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.logger.Warning("snapshot download failed", "peer_address", peer, "error", err)
+			continue
+		}
+
+		for bin := int(res.Bin); bin < len(res.Cursors); bin++ {
+			if res.Cursors[bin] == 0 {
+				continue
+			}
+			if err := intervals.AddPeerInterval(peer, uint8(bin), 1, res.Cursors[bin]); err != nil {
+				return fmt.Errorf("add peer interval: %w", err)
+			}
+		}
+		s.logger.Info("reserve bootstrapped from snapshot", "peer_address", peer, "radius", radius, "chunks", res.Chunks, "duration", time.Since(start))
+		return nil
+	}
+
+	return ErrRefused
+}
+
+// Download downloads the snapshot of the neighborhood at the radius
+// from the peer and stores the chunks with valid stamps.
+func (s *Service) Download(ctx context.Context, peer swarm.Address, radius uint8) (res *Result, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	stream, err := s.streamer.NewStream(ctx, peer, nil, protocolName, protocolVersion, streamName)
+	if err != nil {
+		return nil, fmt.Errorf("new stream: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = stream.Reset()
+		} else {
+			_ = stream.FullClose()
+		}
+	}()
+
+	w, r := protobuf.NewWriterAndReader(stream)
+
+	if err := w.WriteMsgWithContext(ctx, &pb.Request{Radius: uint32(radius)}); err != nil {
+		return nil, fmt.Errorf("write request: %w", err)
+	}
+
+	var header pb.Header
+	if err := r.ReadMsgWithContext(ctx, &header); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if !header.Accepted {
+		return nil, ErrRefused
+	}
+	if header.Bin >= uint32(swarm.MaxBins) || len(header.Cursors) != int(swarm.MaxBins) {
+		return nil, errors.New("invalid header")
+	}
+
+	res = &Result{
+		Bin:     uint8(header.Bin),
+		Cursors: header.Cursors,
+	}
+
+	for {
+		var page pb.Page
+		pageCtx, pageCancel := context.WithTimeout(ctx, pageTimeout)
+		err := r.ReadMsgWithContext(pageCtx, &page)
+		pageCancel()
+		if err != nil {
+			return nil, fmt.Errorf("read page: %w", err)
+		}
+
+		chunks := make([]swarm.Chunk, 0, len(page.Chunks))
+		for _, d := range page.Chunks {
+			addr := swarm.NewAddress(d.Address)
+			if swarm.Proximity(addr.Bytes(), s.overlay.Bytes()) < radius {
+				return nil, ErrUnsolicitedChunk
+			}
+			chunk, err := s.validStamp(swarm.NewChunk(addr, d.Data), d.Stamp)
+			if err != nil {
+				// the batch may have expired since the chunk was stored
+				s.metrics.InvalidStamps.Inc()
+				s.logger.Debug("invalid stamp", "peer_address", peer, "chunk_address", addr, "error", err)
+				continue
+			}
+			if !cac.Valid(chunk) && !soc.Valid(chunk) {
+				return nil, swarm.ErrInvalidChunk
+			}
+			chunks = append(chunks, chunk)
+		}
+
+		if len(chunks) > 0 {
+			if err := s.storage.Put(ctx, storage.ModePutSync, chunks...); err != nil {
+				return nil, fmt.Errorf("put: %w", err)
+			}
+			res.Chunks += len(chunks)
+			s.metrics.ChunksStored.Add(float64(len(chunks)))
+		}
+
+		if page.Last {
+			s.metrics.Downloaded.Inc()
+			return res, nil
+		}
+	}
+}
+
+// handler serves the snapshot of the requested neighborhood of the peer.
+func (s *Service) handler(streamCtx context.Context, p p2p.Peer, stream p2p.Stream) (err error) {
+	select {
+	case <-s.quit:
+		return nil
+	default:
+	}
+
+	s.wg.Add(1)
+	defer s.wg.Done()
+
+	ctx, cancel := context.WithCancel(streamCtx)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	w, r := protobuf.NewWriterAndReader(stream)
+	defer func() {
+		if err != nil {
+			_ = stream.Reset()
+		} else {
+			_ = stream.FullClose()
+		}
+	}()
+
+	var req pb.Request
+	if err := r.ReadMsgWithContext(ctx, &req); err != nil {
+		return fmt.Errorf("read request: %w", err)
+	}
+	if req.Radius > uint32(swarm.MaxPO) {
+		return fmt.Errorf("invalid radius %d", req.Radius)
+	}
+	radius := uint8(req.Radius)
+
+	if !s.acquire() {
+		s.metrics.Refused.Inc()
+		if err := w.WriteMsgWithContext(ctx, &pb.Header{}); err != nil {
+			return fmt.Errorf("write header: %w", err)
+		}
+		return nil
+	}
+	defer s.release()
+
+	cursors, err := s.storage.Cursors(ctx)
+	if err != nil {
+		return fmt.Errorf("cursors: %w", err)
+	}
+
+	// The chunks in the neighborhood of the peer are in the bins from the
+	// proximity of the peer, if the peer is not a neighbor at the radius.
+	start := swarm.Proximity(p.Address.Bytes(), s.overlay.Bytes())
+	if start > radius {
+		start = radius
+	}
+
+	if err := w.WriteMsgWithContext(ctx, &pb.Header{Accepted: true, Bin: uint32(start), Cursors: cursors}); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+
+	s.logger.Debug("serving snapshot", "peer_address", p.Address, "radius", radius)
+
+	page := new(pb.Page)
+	flush := func(last bool) error {
+		page.Last = last
+		if err := w.WriteMsgWithContext(ctx, page); err != nil {
+			return fmt.Errorf("write page: %w", err)
+		}
+		s.metrics.ChunksServed.Add(float64(len(page.Chunks)))
+		page = new(pb.Page)
+		return nil
+	}
+
+	for bin := start; bin < swarm.MaxBins; bin++ {
+		for from := uint64(1); from <= cursors[bin]; {
+			addrs, top, err := s.storage.IntervalChunks(ctx, bin, from, cursors[bin], intervalLimit)
+			if err != nil {
+				return fmt.Errorf("interval chunks: %w", err)
+			}
+
+			var want []swarm.Address
+			for _, addr := range addrs {
+				if swarm.Proximity(addr.Bytes(), p.Address.Bytes()) >= radius {
+					want = append(want, addr)
+				}
+			}
+
+			chunks, err := s.chunks(ctx, want)
+			if err != nil {
+				return err
+			}
+			for _, ch := range chunks {
+				stamp, err := ch.Stamp().MarshalBinary()
+				if err != nil {
+					return fmt.Errorf("serialise stamp: %w", err)
+				}
+				page.Chunks = append(page.Chunks, &pb.Delivery{Address: ch.Address().Bytes(), Data: ch.Data(), Stamp: stamp})
+				if len(page.Chunks) == pageSize {
+					if err := flush(false); err != nil {
+						return err
+					}
+				}
+			}
+
+			if top < from {
+				break
+			}
+			from = top + 1
+		}
+	}
+
+	if err := flush(true); err != nil {
+		return err
+	}
+	s.metrics.Served.Inc()
+	return nil
+}
+
+// chunks returns the chunks of the addresses which are still in the storage.
+func (s *Service) chunks(ctx context.Context, addrs []swarm.Address) ([]swarm.Chunk, error) {
+	if len(addrs) == 0 {
+		return nil, nil
+	}
+	chunks, err := s.storage.Get(ctx, storage.ModeGetSync, addrs...)
+	if !errors.Is(err, storage.ErrNotFound) {
+		return chunks, err
+	}
+
+	// some chunks were evicted since the addresses were collected
+	chunks = chunks[:0]
+	for _, addr := range addrs {
+		ch, err := s.storage.Get(ctx, storage.ModeGetSync, addr)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, ch...)
+	}
+	return chunks, nil
+}
+
+func (s *Service) acquire() bool {
+	if !s.serve {
+		return false
+	}
+	select {
+	case s.serving <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Service) release() {
+	<-s.serving
+}
+
+var _ io.Closer = (*Service)(nil)
+
+func (s *Service) Close() error {
+	s.logger.Info("snapshot service shutting down")
+	close(s.quit)
+	cc := make(chan struct{})
+	go func() {
+		defer close(cc)
+		s.wg.Wait()
+	}()
+
+	select {
+	case <-cc:
+	case <-time.After(5 * time.Second):
+		s.logger.Warning("snapshot service shutting down with running goroutines")
+	}
+	return nil
+}
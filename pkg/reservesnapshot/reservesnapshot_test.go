@@ -0,0 +1,292 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package reservesnapshot_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/streamtest"
+	"github.com/ethersphere/bee/pkg/postage"
+	mockbatchstore "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
+	"github.com/ethersphere/bee/pkg/reservesnapshot"
+	"github.com/ethersphere/bee/pkg/storage"
+	testingc "github.com/ethersphere/bee/pkg/storage/testing"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+)
+
+// store is an in-memory pull storage which keeps
+// the bin IDs of the chunks relative to the base.
+type store struct {
+	base swarm.Address
+
+	mtx    sync.Mutex
+	chunks map[string]swarm.Chunk
+	bins   [swarm.MaxBins][]swarm.Address
+}
+
+func newStore(base swarm.Address, chs ...swarm.Chunk) *store {
+	s := &store{base: base, chunks: make(map[string]swarm.Chunk)}
+	_ = s.Put(context.Background(), storage.ModePutSync, chs...)
+	return s
+}
+
+func (s *store) IntervalChunks(_ context.Context, bin uint8, from, to uint64, limit int) ([]swarm.Address, uint64, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var addrs []swarm.Address
+	for id := from; id <= to && id <= uint64(len(s.bins[bin])); id++ {
+		if len(addrs) == limit {
+			return addrs, id - 1, nil
+		}
+		addrs = append(addrs, s.bins[bin][id-1])
+	}
+	return addrs, to, nil
+}
+
+func (s *store) Cursors(context.Context) ([]uint64, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	cursors := make([]uint64, swarm.MaxBins)
+	for i, b := range s.bins {
+		cursors[i] = uint64(len(b))
+	}
+	return cursors, nil
+}
+
+func (s *store) Get(_ context.Context, _ storage.ModeGet, addrs ...swarm.Address) ([]swarm.Chunk, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	chs := make([]swarm.Chunk, 0, len(addrs))
+	for _, a := range addrs {
+		ch, ok := s.chunks[a.ByteString()]
+		if !ok {
+			return nil, storage.ErrNotFound
+		}
+		chs = append(chs, ch)
+	}
+	return chs, nil
+}
+
+func (s *store) Put(_ context.Context, _ storage.ModePut, chs ...swarm.Chunk) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for _, ch := range chs {
+		if _, ok := s.chunks[ch.Address().ByteString()]; ok {
+			continue
+		}
+		s.chunks[ch.Address().ByteString()] = ch
+		bin := swarm.Proximity(ch.Address().Bytes(), s.base.Bytes())
+		s.bins[bin] = append(s.bins[bin], ch.Address())
+	}
+	return nil
+}
+
+func (s *store) Set(context.Context, storage.ModeSet, ...swarm.Address) error {
+	return nil
+}
+
+func (s *store) Has(_ context.Context, addr swarm.Address) (bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	_, ok := s.chunks[addr.ByteString()]
+	return ok, nil
+}
+
+func (s *store) count() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return len(s.chunks)
+}
+
+type peers []swarm.Address
+
+func (ps peers) EachConnectedPeer(f topology.EachPeerFunc, _ topology.Filter) error {
+	for _, p := range ps {
+		if stop, _, err := f(p, 2); err != nil || stop {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ps peers) EachConnectedPeerRev(f topology.EachPeerFunc, filter topology.Filter) error {
+	return ps.EachConnectedPeer(f, filter)
+}
+
+type intervals map[uint8]uint64
+
+func (i intervals) AddPeerInterval(_ swarm.Address, bin uint8, start, end uint64) error {
+	if start != 1 {
+		return errors.New("interval does not start with the first bin ID")
+	}
+	i[bin] = end
+	return nil
+}
+
+func newService(s p2p.Streamer, st *store, radius uint8, serve bool) *reservesnapshot.Service {
+	validStamp := func(ch swarm.Chunk, stamp []byte) (swarm.Chunk, error) {
+		st := new(postage.Stamp)
+		if err := st.UnmarshalBinary(stamp); err != nil {
+			return nil, err
+		}
+		return ch.WithStamp(st), nil
+	}
+	bs := mockbatchstore.New(mockbatchstore.WithReserveState(&postage.ReserveState{StorageRadius: radius}))
+	return reservesnapshot.New(s, st, validStamp, log.Noop, bs, st.base, reservesnapshot.Options{Serve: serve})
+}
+
+func TestDownload(t *testing.T) {
+	t.Parallel()
+
+	var (
+		server = swarm.RandAddress(t)
+		client = swarm.RandAddressAt(t, server, 2)
+		near   []swarm.Chunk
+		chunks []swarm.Chunk
+	)
+
+	// enough chunks in the neighborhood of the client for several pages
+	for i := 0; i < 40; i++ {
+		near = append(near, testingc.GenerateValidRandomChunkAt(client, 0))
+	}
+	chunks = append(chunks, near...)
+	for i := 0; i < 10; i++ {
+		chunks = append(chunks, testingc.GenerateTestRandomChunkAt(t, client, 0))
+	}
+
+	serverStore := newStore(server, chunks...)
+	serverService := newService(nil, serverStore, 0, true)
+	t.Cleanup(func() { _ = serverService.Close() })
+
+	recorder := streamtest.New(streamtest.WithProtocols(serverService.Protocol()), streamtest.WithBaseAddr(client))
+
+	t.Run("download", func(t *testing.T) {
+		t.Parallel()
+
+		clientStore := newStore(client)
+		clientService := newService(recorder, clientStore, 1, false)
+		t.Cleanup(func() { _ = clientService.Close() })
+
+		res, err := clientService.Download(context.Background(), server, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Bin != 1 {
+			t.Fatalf("got first bin %d, want 1", res.Bin)
+		}
+		if res.Chunks != len(near) || clientStore.count() != len(near) {
+			t.Fatalf("got %d chunks, stored %d, want %d", res.Chunks, clientStore.count(), len(near))
+		}
+		for _, ch := range near {
+			if has, _ := clientStore.Has(context.Background(), ch.Address()); !has {
+				t.Fatalf("chunk %s not stored", ch.Address())
+			}
+		}
+		want, _ := serverStore.Cursors(context.Background())
+		for i := range want {
+			if res.Cursors[i] != want[i] {
+				t.Fatalf("got cursors %v, want %v", res.Cursors, want)
+			}
+		}
+	})
+
+	t.Run("unsolicited chunk", func(t *testing.T) {
+		t.Parallel()
+
+		// the overlay of the client differs from the one the server sees
+		other := swarm.RandAddressAt(t, client, 0)
+		clientService := newService(recorder, newStore(other), 1, false)
+		t.Cleanup(func() { _ = clientService.Close() })
+
+		_, err := clientService.Download(context.Background(), server, 1)
+		if !errors.Is(err, reservesnapshot.ErrUnsolicitedChunk) {
+			t.Fatalf("got error %v, want %v", err, reservesnapshot.ErrUnsolicitedChunk)
+		}
+	})
+}
+
+func TestDownloadRefused(t *testing.T) {
+	t.Parallel()
+
+	server := swarm.RandAddress(t)
+	client := swarm.RandAddressAt(t, server, 2)
+
+	serverService := newService(nil, newStore(server), 0, false)
+	t.Cleanup(func() { _ = serverService.Close() })
+	recorder := streamtest.New(streamtest.WithProtocols(serverService.Protocol()), streamtest.WithBaseAddr(client))
+	clientService := newService(recorder, newStore(client), 1, false)
+	t.Cleanup(func() { _ = clientService.Close() })
+
+	_, err := clientService.Download(context.Background(), server, 1)
+	if !errors.Is(err, reservesnapshot.ErrRefused) {
+		t.Fatalf("got error %v, want %v", err, reservesnapshot.ErrRefused)
+	}
+
+	err = clientService.Bootstrap(context.Background(), peers{server}, intervals{})
+	if !errors.Is(err, reservesnapshot.ErrRefused) {
+		t.Fatalf("got error %v, want %v", err, reservesnapshot.ErrRefused)
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	t.Parallel()
+
+	var (
+		server = swarm.RandAddress(t)
+		client = swarm.RandAddressAt(t, server, 2)
+		chunks []swarm.Chunk
+	)
+	for i := 0; i < 10; i++ {
+		chunks = append(chunks, testingc.GenerateValidRandomChunkAt(client, 0))
+	}
+
+	serverStore := newStore(server, chunks...)
+	serverService := newService(nil, serverStore, 0, true)
+	t.Cleanup(func() { _ = serverService.Close() })
+	recorder := streamtest.New(streamtest.WithProtocols(serverService.Protocol()), streamtest.WithBaseAddr(client))
+	clientStore := newStore(client)
+	clientService := newService(recorder, clientStore, 1, false)
+	t.Cleanup(func() { _ = clientService.Close() })
+
+	if err := clientService.Bootstrap(context.Background(), peers{}, intervals{}); !errors.Is(err, reservesnapshot.ErrNoNeighbors) {
+		t.Fatalf("got error %v, want %v", err, reservesnapshot.ErrNoNeighbors)
+	}
+
+	got := make(intervals)
+	if err := clientService.Bootstrap(context.Background(), peers{server}, got); err != nil {
+		t.Fatal(err)
+	}
+	if clientStore.count() != len(chunks) {
+		t.Fatalf("stored %d chunks, want %d", clientStore.count(), len(chunks))
+	}
+
+	cursors, _ := serverStore.Cursors(context.Background())
+	want := make(intervals)
+	for bin := 1; bin < len(cursors); bin++ {
+		if cursors[bin] > 0 {
+			want[uint8(bin)] = cursors[bin]
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got intervals %v, want %v", got, want)
+	}
+	for bin, end := range want {
+		if got[bin] != end {
+			t.Fatalf("got intervals %v, want %v", got, want)
+		}
+	}
+}
@@ -6,16 +6,21 @@ package ens
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	goens "github.com/wealdtech/go-ens/v3"
 
+	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/resolver"
 	"github.com/ethersphere/bee/pkg/resolver/client"
+	"github.com/ethersphere/bee/pkg/sctx"
 	"github.com/ethersphere/bee/pkg/swarm"
 )
 
@@ -30,6 +35,9 @@ type Address = swarm.Address
 // Make sure Client implements the resolver.Client interface.
 var _ client.Interface = (*Client)(nil)
 
+// Make sure Client implements the resolver.Publisher interface.
+var _ resolver.Publisher = (*Client)(nil)
+
 var (
 	// ErrFailedToConnect denotes that the resolver failed to connect to the
 	// provided endpoint.
@@ -38,6 +46,9 @@ var (
 	ErrResolveFailed = errors.New("resolve failed")
 	// ErrNotImplemented denotes that the function has not been implemented.
 	ErrNotImplemented = errors.New("function not implemented")
+	// ErrPublishFailed denotes that the content hash of a name could not be
+	// published.
+	ErrPublishFailed = errors.New("publish failed")
 	// errNameNotRegistered denotes that the name is not registered.
 	errNameNotRegistered = errors.New("name is not registered")
 )
@@ -50,6 +61,7 @@ type Client struct {
 	ethCl        *ethclient.Client
 	connectFn    func(string, string) (*ethclient.Client, *goens.Registry, error)
 	resolveFn    func(*goens.Registry, common.Address, string) (string, error)
+	publishFn    func(context.Context, *ethclient.Client, *goens.Registry, crypto.Signer, string, []byte) (common.Hash, error)
 	registry     *goens.Registry
 }
 
@@ -62,6 +74,7 @@ func NewClient(endpoint string, opts ...Option) (client.Interface, error) {
 		endpoint:  endpoint,
 		connectFn: wrapDial,
 		resolveFn: wrapResolve,
+		publishFn: wrapPublish,
 	}
 
 	// Apply all options to the Client.
@@ -132,6 +145,27 @@ func (c *Client) Resolve(name string) (Address, error) {
 	return addr, nil
 }
 
+// Publish implements the resolver.Publisher interface. It sets the content
+// hash record of the name to the address with the transaction signed by the
+// signer, which must be the owner of the name. The gas price and limit of the
+// transaction are taken from the context, if set.
+func (c *Client) Publish(ctx context.Context, signer crypto.Signer, name string, addr Address) (common.Hash, error) {
+	if c.publishFn == nil {
+		return common.Hash{}, fmt.Errorf("publishFn: %w", ErrNotImplemented)
+	}
+
+	ch, err := goens.StringToContenthash(swarmContentHashPrefix + addr.String())
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("content hash %s: %w", addr, resolver.ErrInvalidContentHash)
+	}
+
+	txHash, err := c.publishFn(ctx, c.ethCl, c.registry, signer, name, ch)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("%w: %w", err, ErrPublishFailed)
+	}
+	return txHash, nil
+}
+
 // Close closes the RPC connection with the client, terminating all unfinished
 // requests. If the connection is already closed, this call is a noop.
 func (c *Client) Close() error {
@@ -197,3 +231,49 @@ func wrapResolve(registry *goens.Registry, _ common.Address, name string) (strin
 
 	return addr, nil
 }
+
+func wrapPublish(ctx context.Context, ethCl *ethclient.Client, registry *goens.Registry, signer crypto.Signer, name string, contenthash []byte) (common.Hash, error) {
+	from, err := signer.EthereumAddress()
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("signer address: %w", err)
+	}
+
+	// Ensure the name is owned by the signer, as the resolver
+	// rejects the records set by anyone else.
+	ownerAddress, err := registry.Owner(name)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("owner: %w: %w", err, resolver.ErrServiceNotAvailable)
+	}
+	if ownerAddress != from {
+		return common.Hash{}, fmt.Errorf("owner %s: %w", ownerAddress, resolver.ErrNotOwner)
+	}
+
+	ensR, err := registry.Resolver(name)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("resolver: %w: %w", err, resolver.ErrServiceNotAvailable)
+	}
+
+	chainID, err := ethCl.ChainID(ctx)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("chain id: %w: %w", err, resolver.ErrServiceNotAvailable)
+	}
+
+	opts := &bind.TransactOpts{
+		From:     from,
+		Context:  ctx,
+		GasPrice: sctx.GetGasPrice(ctx),
+		GasLimit: sctx.GetGasLimit(ctx),
+		Signer: func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if addr != from {
+				return nil, bind.ErrNotAuthorized
+			}
+			return signer.SignTx(tx, chainID)
+		},
+	}
+	tx, err := ensR.SetContenthash(opts, contenthash)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("set contenthash: %w", err)
+	}
+
+	return tx.Hash(), nil
+}
@@ -5,7 +5,9 @@
 package ens_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -13,6 +15,7 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 	goens "github.com/wealdtech/go-ens/v3"
 
+	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/resolver"
 	"github.com/ethersphere/bee/pkg/resolver/client/ens"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -210,3 +213,86 @@ func TestResolve(t *testing.T) {
 		})
 	}
 }
+
+func TestPublish(t *testing.T) {
+	t.Parallel()
+
+	testSwarmAddr := swarm.MustParseHexAddress("aaabbbcc00000000000000000000000000000000000000000000000000000000")
+	testTxHash := common.HexToHash("0x1234")
+
+	testCases := []struct {
+		desc      string
+		publishFn func(context.Context, *ethclient.Client, *goens.Registry, crypto.Signer, string, []byte) (common.Hash, error)
+		wantErr   error
+	}{
+		{
+			desc:      "nil publish function",
+			publishFn: nil,
+			wantErr:   ens.ErrNotImplemented,
+		},
+		{
+			desc: "not owner",
+			publishFn: func(context.Context, *ethclient.Client, *goens.Registry, crypto.Signer, string, []byte) (common.Hash, error) {
+				return common.Hash{}, resolver.ErrNotOwner
+			},
+			wantErr: resolver.ErrNotOwner,
+		},
+		{
+			desc: "publish function internal error",
+			publishFn: func(context.Context, *ethclient.Client, *goens.Registry, crypto.Signer, string, []byte) (common.Hash, error) {
+				return common.Hash{}, errors.New("internal error")
+			},
+			wantErr: ens.ErrPublishFailed,
+		},
+		{
+			desc: "expect swarm content hash",
+			publishFn: func(_ context.Context, _ *ethclient.Client, _ *goens.Registry, _ crypto.Signer, name string, ch []byte) (common.Hash, error) {
+				if name != "example.eth" {
+					return common.Hash{}, errors.New("invalid name")
+				}
+				s, err := goens.ContenthashToString(ch)
+				if err != nil {
+					return common.Hash{}, err
+				}
+				if s != ens.SwarmContentHashPrefix+testSwarmAddr.String() {
+					return common.Hash{}, fmt.Errorf("invalid content hash %s", s)
+				}
+				return testTxHash, nil
+			},
+		},
+	}
+	for _, tC := range testCases {
+		tC := tC
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cl, err := ens.NewClient("example.com",
+				ens.WithConnectFunc(func(endpoint, contractAddr string) (*ethclient.Client, *goens.Registry, error) {
+					return nil, nil, nil
+				}),
+				ens.WithPublishFunc(tC.publishFn),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pk, err := crypto.GenerateSecp256k1Key()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			txHash, err := cl.(resolver.Publisher).Publish(context.Background(), crypto.NewDefaultSigner(pk), "example.eth", testSwarmAddr)
+			if tC.wantErr != nil {
+				if !errors.Is(err, tC.wantErr) {
+					t.Errorf("got %v, want %v", err, tC.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if txHash != testTxHash {
+				t.Errorf("got tx hash %s, want %s", txHash, testTxHash)
+			}
+		})
+	}
+}
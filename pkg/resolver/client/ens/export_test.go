@@ -5,9 +5,13 @@
 package ens
 
 import (
+	"context"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	goens "github.com/wealdtech/go-ens/v3"
+
+	"github.com/ethersphere/bee/pkg/crypto"
 )
 
 const SwarmContentHashPrefix = swarmContentHashPrefix
@@ -25,3 +29,10 @@ func WithResolveFunc(fn func(registry *goens.Registry, addr common.Address, inpu
 		c.resolveFn = fn
 	}
 }
+
+// WithPublishFunc will set the Publish function implementation.
+func WithPublishFunc(fn func(ctx context.Context, ethCl *ethclient.Client, registry *goens.Registry, signer crypto.Signer, name string, contenthash []byte) (common.Hash, error)) Option {
+	return func(c *Client) {
+		c.publishFn = fn
+	}
+}
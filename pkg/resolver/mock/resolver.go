@@ -5,8 +5,11 @@
 package mock
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/resolver"
 	"github.com/ethersphere/bee/pkg/resolver/client/ens"
 )
@@ -14,10 +17,14 @@ import (
 // Assure mock Resolver implements the Resolver interface.
 var _ resolver.Interface = (*Resolver)(nil)
 
+// Assure mock Resolver implements the Publisher interface.
+var _ resolver.Publisher = (*Resolver)(nil)
+
 // Resolver is the mock Resolver implementation.
 type Resolver struct {
 	IsClosed    bool
 	resolveFunc func(string) (resolver.Address, error)
+	publishFunc func(crypto.Signer, string, resolver.Address) (common.Hash, error)
 }
 
 // Option function sets the option on the mock Resolver.
@@ -42,6 +49,13 @@ func WithResolveFunc(f func(string) (resolver.Address, error)) Option {
 	}
 }
 
+// WithPublishFunc will override the Publish function implementation.
+func WithPublishFunc(f func(crypto.Signer, string, resolver.Address) (common.Hash, error)) Option {
+	return func(r *Resolver) {
+		r.publishFunc = f
+	}
+}
+
 // Resolve implements the Resolver interface.
 func (r *Resolver) Resolve(name string) (resolver.Address, error) {
 	if r.resolveFunc != nil {
@@ -50,6 +64,14 @@ func (r *Resolver) Resolve(name string) (resolver.Address, error) {
 	return resolver.Address{}, fmt.Errorf("resolveFunc: %w", ens.ErrNotImplemented)
 }
 
+// Publish implements the Publisher interface.
+func (r *Resolver) Publish(_ context.Context, signer crypto.Signer, name string, addr resolver.Address) (common.Hash, error) {
+	if r.publishFunc != nil {
+		return r.publishFunc(signer, name, addr)
+	}
+	return common.Hash{}, fmt.Errorf("publishFunc: %w", resolver.ErrNoPublisher)
+}
+
 // Close implements the Resolver interface.
 func (r *Resolver) Close() error {
 	r.IsClosed = true
@@ -5,11 +5,14 @@
 package multiresolver
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"path"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/resolver"
 	"github.com/ethersphere/bee/pkg/resolver/cidv1"
@@ -23,6 +26,9 @@ const loggerName = "multiresolver"
 // Ensure MultiResolver implements Resolver interface.
 var _ resolver.Interface = (*MultiResolver)(nil)
 
+// Ensure MultiResolver implements Publisher interface.
+var _ resolver.Publisher = (*MultiResolver)(nil)
+
 var (
 	// ErrTLDTooLong denotes when a TLD in a name exceeds maximum length.
 	ErrTLDTooLong = fmt.Errorf("TLD exceeds maximum length of %d characters", maxTLDLength)
@@ -174,6 +180,30 @@ func (mr *MultiResolver) Resolve(name string) (addr resolver.Address, err error)
 	return addr, errs.ErrorOrNil()
 }
 
+// Publish will publish the address as the content of the name by the first
+// publisher in the resolution chain of the name, selected as for Resolve.
+// The resolver.ErrNoPublisher is returned if the chain has no publisher.
+func (mr *MultiResolver) Publish(ctx context.Context, signer crypto.Signer, name string, addr resolver.Address) (common.Hash, error) {
+	tld := ""
+	if !mr.ForceDefault {
+		tld = getTLD(name)
+	}
+	chain := mr.resolvers[tld]
+
+	// If no resolver chain is found, switch to the default chain.
+	if len(chain) == 0 {
+		chain = mr.resolvers[""]
+	}
+
+	for _, res := range chain {
+		if p, ok := res.(resolver.Publisher); ok {
+			return p.Publish(ctx, signer, name, addr)
+		}
+	}
+
+	return common.Hash{}, fmt.Errorf("tld %q: %w", tld, resolver.ErrNoPublisher)
+}
+
 // Close all will call Close on all resolvers in all resolver chains.
 func (mr *MultiResolver) Close() error {
 	var errs *multierror.Error
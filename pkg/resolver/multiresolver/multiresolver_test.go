@@ -5,11 +5,14 @@
 package multiresolver_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/resolver"
 	"github.com/ethersphere/bee/pkg/resolver/mock"
@@ -273,3 +276,39 @@ func TestResolve(t *testing.T) {
 		}
 	})
 }
+
+func TestPublish(t *testing.T) {
+	t.Parallel()
+
+	addr := newAddr("aaaabbbbccccdddd")
+	txHash := common.HexToHash("0x1234")
+
+	// resolveOnly hides the Publish method of the mock resolver
+	type resolveOnly struct {
+		resolver.Interface
+	}
+
+	mr := multiresolver.NewMultiResolver()
+	mr.PushResolver(".eth", resolveOnly{mock.NewResolver()})
+	mr.PushResolver(".eth", mock.NewResolver(
+		mock.WithPublishFunc(func(_ crypto.Signer, name string, a Address) (common.Hash, error) {
+			if name != "example.eth" || !a.Equal(addr) {
+				return common.Hash{}, errors.New("unexpected publish")
+			}
+			return txHash, nil
+		}),
+	))
+	mr.PushResolver(".none", resolveOnly{mock.NewResolver()})
+
+	got, err := mr.Publish(context.Background(), nil, "example.eth", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != txHash {
+		t.Errorf("got tx hash %s, want %s", got, txHash)
+	}
+
+	if _, err := mr.Publish(context.Background(), nil, "example.none", addr); !errors.Is(err, resolver.ErrNoPublisher) {
+		t.Errorf("got error %v, want %v", err, resolver.ErrNoPublisher)
+	}
+}
@@ -5,9 +5,12 @@
 package resolver
 
 import (
+	"context"
 	"errors"
 	"io"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/swarm"
 )
 
@@ -23,6 +26,10 @@ var (
 	ErrServiceNotAvailable = errors.New("not available")
 	// ErrInvalidContentHash denotes that the value of the response contenthash record is not valid.
 	ErrInvalidContentHash = errors.New("invalid swarm content hash")
+	// ErrNotOwner denotes that the name is not owned by the publisher.
+	ErrNotOwner = errors.New("not owner")
+	// ErrNoPublisher denotes that no name service can publish the name.
+	ErrNoPublisher = errors.New("no publisher")
 )
 
 // Interface can resolve an URL into an associated Ethereum address.
@@ -30,3 +37,9 @@ type Interface interface {
 	Resolve(url string) (Address, error)
 	io.Closer
 }
+
+// Publisher can publish an address as the content of a name with the
+// transaction signed by the owner of the name.
+type Publisher interface {
+	Publish(ctx context.Context, signer crypto.Signer, name string, addr Address) (txHash common.Hash, err error)
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package retrieval
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// Diagnostics collects the statistics of the chunks requested with the same
+// context, such as the chunks of a single download. The methods of the nil
+// Diagnostics do nothing, so that the statistics are collected only if they
+// are set to the context.
+type Diagnostics struct {
+	mu            sync.Mutex
+	local         int
+	sharedCache   int
+	retrieved     int
+	retries       int
+	peers         map[string]struct{}
+	retrievalTime time.Duration
+}
+
+// Report is the report of the collected statistics.
+type Report struct {
+	Chunks        int           // number of the requested chunks
+	Local         int           // number of the chunks found in the local store
+	SharedCache   int           // number of the chunks found in the shared cache
+	Retrieved     int           // number of the chunks retrieved from the network
+	Retries       int           // number of the repeated retrieval requests
+	Peers         int           // number of the peers the chunks were retrieved from
+	RetrievalTime time.Duration // total time of the retrievals from the network
+}
+
+// CacheHitRatio returns the share of the chunks which were
+// not retrieved from the network.
+func (r Report) CacheHitRatio() float64 {
+	if r.Chunks == 0 {
+		return 0
+	}
+	return float64(r.Local+r.SharedCache) / float64(r.Chunks)
+}
+
+// NewDiagnostics returns new empty Diagnostics.
+func NewDiagnostics() *Diagnostics {
+	return &Diagnostics{peers: make(map[string]struct{})}
+}
+
+// AddLocal records the chunk found in the local store.
+func (d *Diagnostics) AddLocal() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	d.local++
+	d.mu.Unlock()
+}
+
+// AddSharedCache records the chunk found in the shared cache.
+func (d *Diagnostics) AddSharedCache() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	d.sharedCache++
+	d.mu.Unlock()
+}
+
+// AddRetrieved records the chunk retrieved from the
+// network and the duration of its retrieval.
+func (d *Diagnostics) AddRetrieved(duration time.Duration) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	d.retrieved++
+	d.retrievalTime += duration
+	d.mu.Unlock()
+}
+
+// addRetrieval records the peer a chunk was retrieved from
+// and the number of the requests which were needed.
+func (d *Diagnostics) addRetrieval(peer swarm.Address, attempts int) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	d.peers[peer.ByteString()] = struct{}{}
+	if attempts > 1 {
+		d.retries += attempts - 1
+	}
+	d.mu.Unlock()
+}
+
+// Report returns the report of the statistics collected so far.
+func (d *Diagnostics) Report() Report {
+	if d == nil {
+		return Report{}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return Report{
+		Chunks:        d.local + d.sharedCache + d.retrieved,
+		Local:         d.local,
+		SharedCache:   d.sharedCache,
+		Retrieved:     d.retrieved,
+		Retries:       d.retries,
+		Peers:         len(d.peers),
+		RetrievalTime: d.retrievalTime,
+	}
+}
+
+// diagnosticsContextKey is used to reference the diagnostics as context value.
+type diagnosticsContextKey struct{}
+
+// WithDiagnostics sets the diagnostics which collect the statistics
+// of the chunks requested with the context.
+func WithDiagnostics(ctx context.Context, d *Diagnostics) context.Context {
+	return context.WithValue(ctx, diagnosticsContextKey{}, d)
+}
+
+// DiagnosticsFromContext returns the diagnostics from the context,
+// or nil if they are not set.
+func DiagnosticsFromContext(ctx context.Context) *Diagnostics {
+	d, _ := ctx.Value(diagnosticsContextKey{}).(*Diagnostics)
+	return d
+}
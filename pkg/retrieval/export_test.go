@@ -18,3 +18,12 @@ func (s *Service) Handler(ctx context.Context, p p2p.Peer, stream p2p.Stream) er
 func (s *Service) ClosestPeer(addr swarm.Address, skipPeers []swarm.Address, allowUpstream bool) (swarm.Address, error) {
 	return s.closestPeer(addr, skipPeers, allowUpstream)
 }
+
+func (s *Service) ClosestForwarder(addr swarm.Address, skipPeers []swarm.Address) (swarm.Address, error) {
+	return s.closestForwarder(addr, skipPeers)
+}
+
+func (s *Service) ReserveBudget(p Priority, price uint64) (release func()) {
+	release, _, _ = s.pool(p).reserve(price, nil)
+	return release
+}
@@ -0,0 +1,209 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package fallback provides the retrieval of the chunks from trusted HTTPS
+// gateways when the retrieval from the network does not succeed within a
+// budget, so that the nodes which can not reach enough peers, like the light
+// nodes behind hostile NATs, remain usable.
+//
+// The chunks fetched from the gateways are verified against their address,
+// so the gateways are trusted only for the availability, not the integrity
+// of the content. As the gateways do not serve the postage stamps of the
+// chunks, the fetched chunks are not stored by the node.
+package fallback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/retrieval"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "retrieval-fallback"
+
+const (
+	// DefaultBudget is the default time the retrieval
+	// from the network is given before the fallback.
+	DefaultBudget = 10 * time.Second
+	// DefaultTimeout is the default timeout of a request to a gateway.
+	DefaultTimeout = 10 * time.Second
+)
+
+var (
+	// ErrInvalidGateway is returned by New if a gateway is not an HTTPS URL.
+	ErrInvalidGateway = errors.New("invalid gateway")
+	// ErrInvalidChunk is returned by Fetch if a gateway responds
+	// with the data which does not match the chunk address.
+	ErrInvalidChunk = errors.New("invalid chunk from gateway")
+)
+
+// Options are the options of the fallback retrieval.
+type Options struct {
+	// Budget is the time the retrieval from the network
+	// is given before the chunk is fetched from the gateways.
+	Budget time.Duration
+	// Timeout is the timeout of a request to a gateway.
+	Timeout time.Duration
+	// Client is the HTTP client of the requests to the gateways.
+	// The http.DefaultClient is used if it is nil.
+	Client *http.Client
+}
+
+// Retriever retrieves the chunks from the network
+// and falls back to the gateways if the retrieval fails.
+type Retriever struct {
+	retrieval retrieval.Interface
+	gateways  []*url.URL
+	client    *http.Client
+	budget    time.Duration
+	timeout   time.Duration
+	next      uint32 // index of the gateway asked first, to spread the load
+	logger    log.Logger
+	metrics   metrics
+}
+
+var _ retrieval.Interface = (*Retriever)(nil)
+
+// New returns the Retriever which falls back to the gateways
+// when the retrieval of the chunk with r fails.
+func New(r retrieval.Interface, gateways []string, logger log.Logger, o Options) (*Retriever, error) {
+	if o.Budget <= 0 {
+		o.Budget = DefaultBudget
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.Client == nil {
+		o.Client = http.DefaultClient
+	}
+
+	f := &Retriever{
+		retrieval: r,
+		client:    o.Client,
+		budget:    o.Budget,
+		timeout:   o.Timeout,
+		logger:    logger.WithName(loggerName).Register(),
+		metrics:   newMetrics(),
+	}
+	for _, g := range gateways {
+		u, err := url.Parse(g)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %v", ErrInvalidGateway, g, err)
+		}
+		if u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("%w %q: https url required", ErrInvalidGateway, g)
+		}
+		f.gateways = append(f.gateways, u)
+	}
+	return f, nil
+}
+
+// RetrieveChunk retrieves the chunk from the network within the budget and
+// fetches it from the gateways if the retrieval fails. The chunks requested
+// for other peers and the chunks requested in the privacy mode, which must
+// not be revealed to the gateways, are retrieved only from the network.
+func (f *Retriever) RetrieveChunk(ctx context.Context, addr, sourcePeerAddr swarm.Address) (swarm.Chunk, error) {
+	if len(f.gateways) == 0 || !sourcePeerAddr.IsZero() || retrieval.ModeFromContext(ctx) == retrieval.ModePrivacy {
+		return f.retrieval.RetrieveChunk(ctx, addr, sourcePeerAddr)
+	}
+
+	rctx, cancel := context.WithTimeout(ctx, f.budget)
+	ch, err := f.retrieval.RetrieveChunk(rctx, addr, sourcePeerAddr)
+	cancel()
+	if err == nil {
+		return ch, nil
+	}
+	if ctx.Err() != nil {
+		return nil, err
+	}
+
+	f.logger.Debug("retrieval failed, falling back to the gateways", "chunk_address", addr, "error", err)
+	fch, ferr := f.Fetch(ctx, addr)
+	if ferr != nil {
+		f.logger.Debug("fetch from the gateways failed", "chunk_address", addr, "error", ferr)
+		return nil, err
+	}
+	return fch, nil
+}
+
+// Fetch fetches the chunk from the gateways, which are asked in turn
+// until one of them responds with the valid chunk.
+func (f *Retriever) Fetch(ctx context.Context, addr swarm.Address) (swarm.Chunk, error) {
+	f.metrics.Requests.Inc()
+
+	err := error(storage.ErrNotFound)
+	start := int(atomic.AddUint32(&f.next, 1))
+	for i := range f.gateways {
+		g := f.gateways[(start+i)%len(f.gateways)]
+
+		ch, ferr := f.fetch(ctx, g, addr)
+		if ferr == nil {
+			f.metrics.Fetched.Inc()
+			return ch, nil
+		}
+		if errors.Is(ferr, ErrInvalidChunk) {
+			f.metrics.InvalidChunks.Inc()
+			f.logger.Warning("gateway responded with an invalid chunk", "gateway", g.Host, "chunk_address", addr)
+		} else {
+			f.metrics.Errors.Inc()
+			f.logger.Debug("fetch from gateway failed", "gateway", g.Host, "chunk_address", addr, "error", ferr)
+		}
+		if !errors.Is(ferr, storage.ErrNotFound) {
+			err = ferr
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, err
+}
+
+func (f *Retriever) fetch(ctx context.Context, gateway *url.URL, addr swarm.Address) (swarm.Chunk, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gateway.JoinPath("chunks", addr.String()).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, storage.ErrNotFound
+	default:
+		return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, swarm.SocMaxChunkSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > swarm.SocMaxChunkSize {
+		return nil, ErrInvalidChunk
+	}
+
+	ch := swarm.NewChunk(addr, data)
+	if !cac.Valid(ch) && !soc.Valid(ch) {
+		return nil, ErrInvalidChunk
+	}
+	return ch, nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fallback_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/retrieval"
+	"github.com/ethersphere/bee/pkg/retrieval/fallback"
+	testingc "github.com/ethersphere/bee/pkg/storage/testing"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+var errNetwork = errors.New("network retrieval failed")
+
+type retrievalFunc func(ctx context.Context, addr, sourcePeerAddr swarm.Address) (swarm.Chunk, error)
+
+func (f retrievalFunc) RetrieveChunk(ctx context.Context, addr, sourcePeerAddr swarm.Address) (swarm.Chunk, error) {
+	return f(ctx, addr, sourcePeerAddr)
+}
+
+// failing fails the retrieval from the network.
+var failing = retrievalFunc(func(context.Context, swarm.Address, swarm.Address) (swarm.Chunk, error) {
+	return nil, errNetwork
+})
+
+// gateway serves the chunks with the data and counts the requests.
+type gateway struct {
+	*httptest.Server
+	requests int32
+}
+
+func newGateway(t *testing.T, chunks map[string][]byte) *gateway {
+	t.Helper()
+
+	g := new(gateway)
+	g.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&g.requests, 1)
+		data, ok := chunks[strings.TrimPrefix(r.URL.Path, "/chunks/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(g.Close)
+	return g
+}
+
+func newRetriever(t *testing.T, r retrieval.Interface, o fallback.Options, gateways ...*gateway) *fallback.Retriever {
+	t.Helper()
+
+	// the client trusts the certificates of all gateways
+	var (
+		urls  []string
+		certs = x509.NewCertPool()
+	)
+	for _, g := range gateways {
+		urls = append(urls, g.URL)
+		certs.AddCert(g.Certificate())
+	}
+	o.Client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: certs}}}
+	f, err := fallback.New(r, urls, log.Noop, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestRetrieveChunk(t *testing.T) {
+	t.Parallel()
+
+	ch := testingc.GenerateTestRandomChunk()
+	chunks := map[string][]byte{ch.Address().String(): ch.Data()}
+
+	t.Run("network", func(t *testing.T) {
+		t.Parallel()
+
+		g := newGateway(t, chunks)
+		f := newRetriever(t, retrievalFunc(func(context.Context, swarm.Address, swarm.Address) (swarm.Chunk, error) {
+			return ch, nil
+		}), fallback.Options{}, g)
+
+		got, err := f.RetrieveChunk(context.Background(), ch.Address(), swarm.ZeroAddress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(ch) {
+			t.Fatal("got different chunk")
+		}
+		if n := atomic.LoadInt32(&g.requests); n != 0 {
+			t.Fatalf("got %d gateway requests, want 0", n)
+		}
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		t.Parallel()
+
+		// the chunk is found on one of the gateways
+		f := newRetriever(t, failing, fallback.Options{}, newGateway(t, nil), newGateway(t, chunks))
+
+		for i := 0; i < 2; i++ {
+			got, err := f.RetrieveChunk(context.Background(), ch.Address(), swarm.ZeroAddress)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got.Data(), ch.Data()) || !got.Address().Equal(ch.Address()) {
+				t.Fatal("got different chunk")
+			}
+		}
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		t.Parallel()
+
+		// the retrieval from the network does not finish within the budget
+		f := newRetriever(t, retrievalFunc(func(ctx context.Context, _, _ swarm.Address) (swarm.Chunk, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}), fallback.Options{Budget: 50 * time.Millisecond}, newGateway(t, chunks))
+
+		if _, err := f.RetrieveChunk(context.Background(), ch.Address(), swarm.ZeroAddress); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("invalid chunk", func(t *testing.T) {
+		t.Parallel()
+
+		tampered := append([]byte(nil), ch.Data()...)
+		tampered[len(tampered)-1] ^= 1
+		f := newRetriever(t, failing, fallback.Options{}, newGateway(t, map[string][]byte{ch.Address().String(): tampered}))
+
+		if _, err := f.RetrieveChunk(context.Background(), ch.Address(), swarm.ZeroAddress); !errors.Is(err, errNetwork) {
+			t.Fatalf("got error %v, want %v", err, errNetwork)
+		}
+		if _, err := f.Fetch(context.Background(), ch.Address()); !errors.Is(err, fallback.ErrInvalidChunk) {
+			t.Fatalf("got error %v, want %v", err, fallback.ErrInvalidChunk)
+		}
+	})
+
+	t.Run("network only", func(t *testing.T) {
+		t.Parallel()
+
+		g := newGateway(t, chunks)
+		f := newRetriever(t, failing, fallback.Options{}, g)
+
+		// the chunks requested for the peers and in the privacy mode
+		// are not requested from the gateways
+		if _, err := f.RetrieveChunk(context.Background(), ch.Address(), swarm.RandAddress(t)); !errors.Is(err, errNetwork) {
+			t.Fatalf("got error %v, want %v", err, errNetwork)
+		}
+		ctx := retrieval.WithMode(context.Background(), retrieval.ModePrivacy)
+		if _, err := f.RetrieveChunk(ctx, ch.Address(), swarm.ZeroAddress); !errors.Is(err, errNetwork) {
+			t.Fatalf("got error %v, want %v", err, errNetwork)
+		}
+		if n := atomic.LoadInt32(&g.requests); n != 0 {
+			t.Fatalf("got %d gateway requests, want 0", n)
+		}
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	for _, g := range []string{"http://gateway.example.org", "gateway.example.org", "https://"} {
+		if _, err := fallback.New(failing, []string{g}, log.Noop, fallback.Options{}); !errors.Is(err, fallback.ErrInvalidGateway) {
+			t.Fatalf("gateway %q: got error %v, want %v", g, err, fallback.ErrInvalidGateway)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fallback
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	Requests      prometheus.Counter // number of chunks requested from the gateways
+	Fetched       prometheus.Counter // number of chunks fetched from the gateways
+	Errors        prometheus.Counter // number of failed requests to the gateways
+	InvalidChunks prometheus.Counter // number of invalid chunks the gateways responded with
+}
+
+func newMetrics() metrics {
+	subsystem := "retrieval_fallback"
+
+	return metrics{
+		Requests: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "requests",
+			Help:      "Total chunks requested from the gateways.",
+		}),
+		Fetched: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "fetched",
+			Help:      "Total chunks fetched from the gateways.",
+		}),
+		Errors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "errors",
+			Help:      "Total failed requests to the gateways.",
+		}),
+		InvalidChunks: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "invalid_chunks",
+			Help:      "Total invalid chunks the gateways responded with.",
+		}),
+	}
+}
+
+func (f *Retriever) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(f.metrics)
+}
@@ -0,0 +1,20 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package retrieval_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/p2p/streamtest"
+	"github.com/ethersphere/bee/pkg/retrieval/pb"
+)
+
+// seedCorpus matches the files of the streams captured from the tests.
+var seedCorpus = filepath.Join("testdata", "*.json")
+
+func FuzzRequestDecoder(f *testing.F) {
+	streamtest.FuzzDecoder(f, seedCorpus, "retrieval", "retrieval", func() streamtest.Message { return new(pb.Request) })
+}
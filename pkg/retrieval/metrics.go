@@ -26,6 +26,7 @@ type metrics struct {
 	ChunkPrice            prometheus.Summary
 	TotalErrors           prometheus.Counter
 	ChunkRetrieveTime     prometheus.Histogram
+	BudgetExceeded        prometheus.Counter
 }
 
 func newMetrics() metrics {
@@ -99,6 +100,12 @@ func newMetrics() metrics {
 			Help:      "Histogram for time taken to retrieve a chunk.",
 		},
 		),
+		BudgetExceeded: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "budget_exceeded_count",
+			Help:      "Number of requests to peers delayed over the accounting budget of their priority class.",
+		}),
 	}
 }
 
@@ -0,0 +1,24 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+type PeerRetriever struct {
+	retrieveFunc func(ctx context.Context, chunkAddr, peer swarm.Address) (swarm.Chunk, time.Duration, error)
+}
+
+func NewPeerRetriever(retrieveFunc func(ctx context.Context, chunkAddr, peer swarm.Address) (swarm.Chunk, time.Duration, error)) *PeerRetriever {
+	return &PeerRetriever{retrieveFunc: retrieveFunc}
+}
+
+func (r *PeerRetriever) RetrieveChunkFromPeer(ctx context.Context, chunkAddr, peer swarm.Address) (swarm.Chunk, time.Duration, error) {
+	return r.retrieveFunc(ctx, chunkAddr, peer)
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package retrieval
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrInvalidMode is returned when the retrieval mode is not known.
+var ErrInvalidMode = errors.New("invalid retrieval mode")
+
+// Mode is the mode of the retrieval of the chunks which originate at the node.
+// It trades off the privacy of the origin of the request against the latency.
+type Mode int
+
+const (
+	// ModeDefault requests the chunks from the closest peers.
+	ModeDefault Mode = iota
+	// ModePrivacy requests the chunks only from the peers outside of the
+	// neighborhood of the chunk, so that the request is forwarded at least
+	// once and the node never reveals itself as the origin to the storers.
+	ModePrivacy
+	// ModePerformance requests the chunks directly from the closest peers and
+	// retries preemptively more often, racing the storers of the chunk.
+	ModePerformance
+)
+
+// performancePreemptiveInterval is the interval of the preemptive retries
+// of the retrieval in the performance mode.
+const performancePreemptiveInterval = 250 * time.Millisecond
+
+// String implements the fmt.Stringer interface.
+func (m Mode) String() string {
+	switch m {
+	case ModePrivacy:
+		return "privacy"
+	case ModePerformance:
+		return "performance"
+	default:
+		return "default"
+	}
+}
+
+// ParseMode parses the string representation of the retrieval mode.
+// The empty string is parsed as ModeDefault.
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "default":
+		return ModeDefault, nil
+	case "privacy":
+		return ModePrivacy, nil
+	case "performance":
+		return ModePerformance, nil
+	}
+	return ModeDefault, ErrInvalidMode
+}
+
+// modeContextKey is used to reference the retrieval mode as context value.
+type modeContextKey struct{}
+
+// WithMode sets the retrieval mode of the chunks requested with the context.
+func WithMode(ctx context.Context, m Mode) context.Context {
+	return context.WithValue(ctx, modeContextKey{}, m)
+}
+
+// ModeFromContext returns the retrieval mode from the context. If the mode is
+// not set, ModeDefault is returned.
+func ModeFromContext(ctx context.Context) Mode {
+	m, ok := ctx.Value(modeContextKey{}).(Mode)
+	if !ok {
+		return ModeDefault
+	}
+	return m
+}
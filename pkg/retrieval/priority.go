@@ -0,0 +1,163 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package retrieval
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidPriority is returned when the retrieval priority is not known.
+var ErrInvalidPriority = errors.New("invalid retrieval priority")
+
+// Priority is the priority class of the retrieval of the chunks which
+// originate at the node. The classes have separate pools of the workers
+// requesting the chunks from the peers and separate accounting budgets,
+// so that the background retrievals do not starve the interactive ones.
+type Priority int
+
+const (
+	// PriorityInteractive is the class of the retrievals for which
+	// the user is waiting, like the page loads on the gateways.
+	PriorityInteractive Priority = iota
+	// PriorityBackground is the class of the bulk retrievals,
+	// like the ones of the sync tools.
+	PriorityBackground
+)
+
+// String implements the fmt.Stringer interface.
+func (p Priority) String() string {
+	switch p {
+	case PriorityBackground:
+		return "background"
+	default:
+		return "interactive"
+	}
+}
+
+// ParsePriority parses the string representation of the retrieval priority.
+// The empty string is parsed as PriorityInteractive.
+func ParsePriority(s string) (Priority, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "interactive":
+		return PriorityInteractive, nil
+	case "background":
+		return PriorityBackground, nil
+	}
+	return PriorityInteractive, ErrInvalidPriority
+}
+
+// priorityContextKey is used to reference the retrieval priority as context value.
+type priorityContextKey struct{}
+
+// WithPriority sets the retrieval priority of the chunks requested with the context.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, p)
+}
+
+// PriorityFromContext returns the retrieval priority from the context. If the
+// priority is not set, PriorityInteractive is returned.
+func PriorityFromContext(ctx context.Context) Priority {
+	p, ok := ctx.Value(priorityContextKey{}).(Priority)
+	if !ok {
+		return PriorityInteractive
+	}
+	return p
+}
+
+// PriorityLimits are the limits of a retrieval priority class.
+// The zero limits are unlimited.
+type PriorityLimits struct {
+	// Workers is the number of the requests to the peers
+	// which are made at once.
+	Workers int
+	// Budget is the sum of the prices of the chunks which are
+	// reserved in the accounting of the peers at once.
+	Budget uint64
+}
+
+// priorityPool is the pool of the workers and the accounting
+// budget of a retrieval priority class.
+type priorityPool struct {
+	workers chan struct{}
+
+	mu       sync.Mutex
+	budget   uint64
+	reserved uint64
+	freed    chan struct{} // closed when a reservation is released
+}
+
+func newPriorityPool(l PriorityLimits) *priorityPool {
+	p := &priorityPool{budget: l.Budget, freed: make(chan struct{})}
+	if l.Workers > 0 {
+		p.workers = make(chan struct{}, l.Workers)
+	}
+	return p
+}
+
+// acquire waits for a free worker of the pool and returns the function which
+// frees it. The ok result is false if the retrieval is done before.
+func (p *priorityPool) acquire(done <-chan struct{}) (release func(), ok bool) {
+	if p.workers == nil {
+		return func() {}, true
+	}
+	select {
+	case p.workers <- struct{}{}:
+		return func() { <-p.workers }, true
+	case <-done:
+		return nil, false
+	}
+}
+
+// reserve waits until the price fits into the budget of the pool, reserves
+// it and returns the function which frees it. The waited result reports
+// whether the budget was exceeded and the ok one is false if the retrieval
+// is done before.
+func (p *priorityPool) reserve(price uint64, done <-chan struct{}) (release func(), waited, ok bool) {
+	for ; ; waited = true {
+		p.mu.Lock()
+		if p.budget == 0 || p.reserved+price <= p.budget {
+			p.reserved += price
+			p.mu.Unlock()
+			break
+		}
+		freed := p.freed
+		p.mu.Unlock()
+
+		select {
+		case <-freed:
+		case <-done:
+			return nil, waited, false
+		}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.mu.Lock()
+			p.reserved -= price
+			close(p.freed)
+			p.freed = make(chan struct{})
+			p.mu.Unlock()
+		})
+	}, waited, true
+}
+
+// SetPriorityLimits sets the limits of the retrieval priority class, which
+// apply to the retrievals started afterwards. It must not be called while
+// the chunks are retrieved.
+func (s *Service) SetPriorityLimits(p Priority, l PriorityLimits) {
+	s.pools[p] = newPriorityPool(l)
+}
+
+// pool returns the pool of the retrieval priority class.
+func (s *Service) pool(p Priority) *priorityPool {
+	if pool, ok := s.pools[p]; ok {
+		return pool
+	}
+	return s.pools[PriorityInteractive]
+}
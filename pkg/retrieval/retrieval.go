@@ -13,6 +13,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/accounting"
@@ -42,7 +43,10 @@ const (
 	streamName      = "retrieval"
 )
 
-var _ Interface = (*Service)(nil)
+var (
+	_ Interface     = (*Service)(nil)
+	_ PeerRetriever = (*Service)(nil)
+)
 
 type Interface interface {
 	// RetrieveChunk retrieves a chunk from the network using the retrieval protocol.
@@ -53,6 +57,15 @@ type Interface interface {
 	RetrieveChunk(ctx context.Context, address, sourcePeerAddr swarm.Address) (chunk swarm.Chunk, err error)
 }
 
+// PeerRetriever retrieves the chunks from the specific peers.
+type PeerRetriever interface {
+	// RetrieveChunkFromPeer retrieves the chunk directly from the peer,
+	// bypassing the selection of the closest peer, and returns the round
+	// trip time of the request. The chunk which is not valid for its
+	// address is returned together with swarm.ErrInvalidChunk error.
+	RetrieveChunkFromPeer(ctx context.Context, chunkAddr, peer swarm.Address) (chunk swarm.Chunk, rtt time.Duration, err error)
+}
+
 type retrievalResult struct {
 	chunk swarm.Chunk
 	peer  swarm.Address
@@ -73,6 +86,7 @@ type Service struct {
 	caching       bool
 	validStamp    postage.ValidStampFn
 	errSkip       *skippeers.List
+	pools         map[Priority]*priorityPool
 }
 
 func New(addr swarm.Address, storer storage.Storer, streamer p2p.Streamer, chunkPeerer topology.ClosestPeerer, logger log.Logger, accounting accounting.Interface, pricer pricer.Interface, tracer *tracing.Tracer, forwarderCaching bool, validStamp postage.ValidStampFn) *Service {
@@ -89,6 +103,10 @@ func New(addr swarm.Address, storer storage.Storer, streamer p2p.Streamer, chunk
 		caching:       forwarderCaching,
 		validStamp:    validStamp,
 		errSkip:       skippeers.NewList(),
+		pools: map[Priority]*priorityPool{
+			PriorityInteractive: newPriorityPool(PriorityLimits{}),
+			PriorityBackground:  newPriorityPool(PriorityLimits{}),
+		},
 	}
 }
 
@@ -127,16 +145,32 @@ func (s *Service) RetrieveChunk(ctx context.Context, chunkAddr, sourcePeerAddr s
 		return nil, fmt.Errorf("invalid address queried")
 	}
 
+	// the mode and the priority apply only to the requests
+	// which originate at the node
+	mode := ModeDefault
+	var pool *priorityPool
 	flightRoute := chunkAddr.String()
 	if origin {
+		mode = ModeFromContext(ctx)
+		priority := PriorityFromContext(ctx)
+		pool = s.pool(priority)
 		flightRoute = chunkAddr.String() + originSuffix
+		if mode != ModeDefault {
+			flightRoute += "_" + mode.String()
+		}
+		// the interactive requests do not wait for the background ones
+		if priority != PriorityInteractive {
+			flightRoute += "_" + priority.String()
+		}
 	}
 
-	totalRetrieveAttempts := 0
+	// the attempts are counted by the flight, which may
+	// outlive the request if its context is canceled
+	var totalRetrieveAttempts atomic.Int64
 	requestStartTime := time.Now()
 	defer func() {
 		s.metrics.RequestDurationTime.Observe(time.Since(requestStartTime).Seconds())
-		s.metrics.RequestAttempts.Observe(float64(totalRetrieveAttempts))
+		s.metrics.RequestAttempts.Observe(float64(totalRetrieveAttempts.Load()))
 	}()
 
 	// topCtx is passing the tracing span to the first singleflight call
@@ -157,7 +191,11 @@ func (s *Service) RetrieveChunk(ctx context.Context, chunkAddr, sourcePeerAddr s
 
 		errorsLeft := 1
 		if origin {
-			ticker := time.NewTicker(preemptiveInterval)
+			interval := preemptiveInterval
+			if mode == ModePerformance {
+				interval = performancePreemptiveInterval
+			}
+			ticker := time.NewTicker(interval)
 			defer ticker.Stop()
 			preemptiveTicker = ticker.C
 			errorsLeft = maxRetrievedErrors
@@ -190,7 +228,7 @@ func (s *Service) RetrieveChunk(ctx context.Context, chunkAddr, sourcePeerAddr s
 				retry()
 			case <-retryC:
 
-				totalRetrieveAttempts++
+				totalRetrieveAttempts.Add(1)
 				s.metrics.PeerRequestCounter.Inc()
 
 				inflight++
@@ -199,7 +237,7 @@ func (s *Service) RetrieveChunk(ctx context.Context, chunkAddr, sourcePeerAddr s
 					ctx := tracing.WithContext(context.Background(), tracing.FromContext(topCtx))
 					span, _, ctx := s.tracer.StartSpanFromContext(ctx, "retrieve-chunk", s.logger, opentracing.Tag{Key: "address", Value: chunkAddr.String()})
 					defer span.Finish()
-					s.retrieveChunk(ctx, chunkAddr, skip, done, resultC, origin)
+					s.retrieveChunk(ctx, chunkAddr, skip, done, resultC, origin, mode, pool)
 				}()
 
 			case res := <-resultC:
@@ -208,6 +246,7 @@ func (s *Service) RetrieveChunk(ctx context.Context, chunkAddr, sourcePeerAddr s
 
 				if res.err == nil {
 					loggerV1.Debug("retrieved chunk", "chunk_address", chunkAddr, "peer_address", res.peer)
+					DiagnosticsFromContext(topCtx).addRetrieval(res.peer, int(totalRetrieveAttempts.Load()))
 					return res.chunk, nil
 				}
 
@@ -262,7 +301,9 @@ func (s *Service) RetrieveChunk(ctx context.Context, chunkAddr, sourcePeerAddr s
 	return v.(swarm.Chunk), nil
 }
 
-func (s *Service) retrieveChunk(ctx context.Context, addr swarm.Address, skip *skippeers.List, done chan struct{}, result chan retrievalResult, isOrigin bool) {
+// retrieveChunk requests the chunk from the closest peer, with a worker and
+// within the accounting budget of the pool of the priority class, if any.
+func (s *Service) retrieveChunk(ctx context.Context, addr swarm.Address, skip *skippeers.List, done chan struct{}, result chan retrievalResult, isOrigin bool, mode Mode, pool *priorityPool) {
 
 	var (
 		startTime = time.Now()
@@ -282,12 +323,29 @@ func (s *Service) retrieveChunk(ctx context.Context, addr swarm.Address, skip *s
 		}
 	}()
 
+	if pool != nil {
+		release, ok := pool.acquire(done)
+		if !ok {
+			err = context.Canceled
+			return
+		}
+		defer release()
+	}
+
 	fullSkip := append(skip.ChunkPeers(addr), s.errSkip.ChunkPeers(addr)...)
 
-	peer, err = s.closestPeer(addr, fullSkip, isOrigin)
-	if err != nil {
-		err = fmt.Errorf("get closest for address %s, allow upstream %v: %w", addr, isOrigin, err)
-		return
+	if mode == ModePrivacy {
+		peer, err = s.closestForwarder(addr, fullSkip)
+		if err != nil {
+			err = fmt.Errorf("get closest forwarder for address %s: %w", addr, err)
+			return
+		}
+	} else {
+		peer, err = s.closestPeer(addr, fullSkip, isOrigin)
+		if err != nil {
+			err = fmt.Errorf("get closest for address %s, allow upstream %v: %w", addr, isOrigin, err)
+			return
+		}
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, retrieveChunkTimeout)
@@ -296,6 +354,20 @@ func (s *Service) retrieveChunk(ctx context.Context, addr swarm.Address, skip *s
 	// compute the peer's price for this chunk for price header
 	chunkPrice := s.pricer.PeerPrice(peer, addr)
 
+	// the peer stays the closest one while the request waits for
+	// the budget, so it is not skipped as the overdrafted peers are
+	if pool != nil {
+		release, waited, ok := pool.reserve(chunkPrice, done)
+		if waited {
+			s.metrics.BudgetExceeded.Inc()
+		}
+		if !ok {
+			err = context.Canceled
+			return
+		}
+		defer release()
+	}
+
 	creditCtx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
@@ -309,11 +381,69 @@ func (s *Service) retrieveChunk(ctx context.Context, addr swarm.Address, skip *s
 
 	skip.Add(addr, peer, maxDuration)
 
-	stream, err := s.streamer.NewStream(ctx, peer, nil, protocolName, protocolVersion, streamName)
+	chunk, err = s.requestChunk(ctx, peer, addr)
+	if err != nil {
+		return
+	}
+	s.metrics.ChunkRetrieveTime.Observe(time.Since(startTime).Seconds())
+	s.metrics.TotalRetrieved.Inc()
+
+	// credit the peer after successful delivery
+	err = creditAction.Apply()
 	if err != nil {
-		err = fmt.Errorf("new stream: %w", err)
 		return
 	}
+	s.metrics.ChunkPrice.Observe(float64(chunkPrice))
+}
+
+// RetrieveChunkFromPeer implements PeerRetriever.RetrieveChunkFromPeer method.
+func (s *Service) RetrieveChunkFromPeer(ctx context.Context, chunkAddr, peer swarm.Address) (swarm.Chunk, time.Duration, error) {
+	if chunkAddr.IsZero() || chunkAddr.IsEmpty() || !chunkAddr.IsValidLength() {
+		return nil, 0, fmt.Errorf("invalid address queried")
+	}
+
+	span, _, ctx := s.tracer.StartSpanFromContext(ctx, "retrieve-chunk-from-peer", s.logger, opentracing.Tag{Key: "address", Value: chunkAddr.String()}, opentracing.Tag{Key: "peer", Value: peer.String()})
+	defer span.Finish()
+
+	ctx, cancel := context.WithTimeout(ctx, retrieveChunkTimeout)
+	defer cancel()
+
+	chunkPrice := s.pricer.PeerPrice(peer, chunkAddr)
+
+	creditCtx, creditCancel := context.WithTimeout(context.Background(), time.Second)
+	defer creditCancel()
+
+	creditAction, err := s.accounting.PrepareCredit(creditCtx, peer, chunkPrice, true)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer creditAction.Cleanup()
+
+	startTime := time.Now()
+	chunk, err := s.requestChunk(ctx, peer, chunkAddr)
+	rtt := time.Since(startTime)
+	if err != nil && !errors.Is(err, swarm.ErrInvalidChunk) {
+		s.metrics.TotalErrors.Inc()
+		return nil, rtt, err
+	}
+
+	// the peer is credited also for the invalid chunk, as it was delivered
+	if err := creditAction.Apply(); err != nil {
+		return nil, rtt, err
+	}
+	s.metrics.ChunkPrice.Observe(float64(chunkPrice))
+
+	return chunk, rtt, err
+}
+
+// requestChunk requests the chunk with the address addr from the peer and
+// validates the delivered chunk. The chunk which is not valid is returned
+// together with swarm.ErrInvalidChunk error.
+func (s *Service) requestChunk(ctx context.Context, peer, addr swarm.Address) (chunk swarm.Chunk, err error) {
+	stream, err := s.streamer.NewStream(ctx, peer, nil, protocolName, protocolVersion, streamName)
+	if err != nil {
+		return nil, fmt.Errorf("new stream: %w", err)
+	}
 
 	defer func() {
 		if err != nil {
@@ -326,40 +456,28 @@ func (s *Service) retrieveChunk(ctx context.Context, addr swarm.Address, skip *s
 	w, r := protobuf.NewWriterAndReader(stream)
 	err = w.WriteMsgWithContext(ctx, &pb.Request{Addr: addr.Bytes()})
 	if err != nil {
-		err = fmt.Errorf("write request: %w peer %s", err, peer.String())
-		return
+		return nil, fmt.Errorf("write request: %w peer %s", err, peer.String())
 	}
 
 	var d pb.Delivery
 	err = r.ReadMsgWithContext(ctx, &d)
 	if err != nil {
-		err = fmt.Errorf("read delivery: %w peer %s", err, peer.String())
-		return
+		return nil, fmt.Errorf("read delivery: %w peer %s", err, peer.String())
 	}
-	s.metrics.ChunkRetrieveTime.Observe(time.Since(startTime).Seconds())
-	s.metrics.TotalRetrieved.Inc()
 
 	stamp := new(postage.Stamp)
 	err = stamp.UnmarshalBinary(d.Stamp)
 	if err != nil {
-		err = fmt.Errorf("stamp unmarshal: %w", err)
-		return
+		return nil, fmt.Errorf("stamp unmarshal: %w", err)
 	}
 	chunk = swarm.NewChunk(addr, d.Data).WithStamp(stamp)
 	if !cac.Valid(chunk) {
 		if !soc.Valid(chunk) {
 			s.metrics.InvalidChunkRetrieved.Inc()
-			err = swarm.ErrInvalidChunk
-			return
+			return chunk, swarm.ErrInvalidChunk
 		}
 	}
-
-	// credit the peer after successful delivery
-	err = creditAction.Apply()
-	if err != nil {
-		return
-	}
-	s.metrics.ChunkPrice.Observe(float64(chunkPrice))
+	return chunk, nil
 }
 
 // closestPeer returns address of the peer that is closest to the chunk with
@@ -389,6 +507,30 @@ func (s *Service) closestPeer(addr swarm.Address, skipPeers []swarm.Address, all
 	return closest, nil
 }
 
+// closestForwarder returns address of the closest peer to the chunk with
+// provided address addr which is outside of the neighborhood of the chunk, so
+// that the peer has to forward the request to the storers of the chunk. This
+// function will ignore peers with addresses provided in skipPeers.
+func (s *Service) closestForwarder(addr swarm.Address, skipPeers []swarm.Address) (swarm.Address, error) {
+	depther, ok := s.peerSuggester.(topology.NeighborhoodDepther)
+	if !ok {
+		return swarm.Address{}, topology.ErrNotFound
+	}
+	depth := depther.NeighborhoodDepth()
+
+	skip := append([]swarm.Address(nil), skipPeers...)
+	for {
+		closest, err := s.peerSuggester.ClosestPeer(addr, false, topology.Filter{Reachable: true}, skip...)
+		if err != nil {
+			return swarm.Address{}, err
+		}
+		if swarm.Proximity(closest.Bytes(), addr.Bytes()) < depth {
+			return closest, nil
+		}
+		skip = append(skip, closest)
+	}
+}
+
 func (s *Service) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream) (err error) {
 	loggerV1 := s.logger.V(1).Register()
 
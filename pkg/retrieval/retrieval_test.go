@@ -82,6 +82,8 @@ func TestDelivery(t *testing.T) {
 	client := retrieval.New(clientAddr, clientMockStorer, recorder, mt, logger, clientMockAccounting, pricerMock, nil, false, noopStampValidator)
 	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
 	defer cancel()
+	diagnostics := retrieval.NewDiagnostics()
+	ctx = retrieval.WithDiagnostics(ctx, diagnostics)
 	v, err := client.RetrieveChunk(ctx, chunk.Address(), swarm.ZeroAddress)
 	if err != nil {
 		t.Fatal(err)
@@ -89,6 +91,9 @@ func TestDelivery(t *testing.T) {
 	if !bytes.Equal(v.Data(), chunk.Data()) {
 		t.Fatalf("request and response data not equal. got %s want %s", v, chunk.Data())
 	}
+	if report := diagnostics.Report(); report.Peers != 1 || report.Retries != 0 {
+		t.Fatalf("got diagnostics report %+v", report)
+	}
 	vstamp, err := v.Stamp().MarshalBinary()
 	if err != nil {
 		t.Fatal(err)
@@ -358,6 +363,71 @@ func TestRetrieveChunk(t *testing.T) {
 	})
 }
 
+func TestRetrieveChunkFromPeer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger        = log.Noop
+		pricer        = pricermock.NewMockService(defaultPrice, defaultPrice)
+		serverAddress = swarm.MustParseHexAddress("03")
+		clientAddress = swarm.MustParseHexAddress("01")
+		chunk         = testingc.FixtureChunk("02c2")
+		// the chunk with the data which does not hash to the address
+		invalidChunk = swarm.NewChunk(swarm.MustParseHexAddress("0025000000000000000000000000000000000000000000000000000000000000"), chunk.Data()).WithStamp(chunk.Stamp())
+	)
+
+	serverStorer := storemock.NewStorer()
+	for _, ch := range []swarm.Chunk{chunk, invalidChunk} {
+		if _, err := serverStorer.Put(context.Background(), storage.ModePutUpload, ch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the closest peers are never used
+	noClosestPeer := topologymock.NewTopologyDriver()
+
+	server := retrieval.New(serverAddress, serverStorer, nil, noClosestPeer, logger, accountingmock.NewAccounting(), pricer, nil, false, noopStampValidator)
+	recorder := streamtest.New(streamtest.WithProtocols(server.Protocol()))
+
+	client := retrieval.New(clientAddress, nil, recorder, noClosestPeer, logger, accountingmock.NewAccounting(), pricer, nil, false, noopStampValidator)
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		got, rtt, err := client.RetrieveChunkFromPeer(context.Background(), chunk.Address(), serverAddress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got.Data(), chunk.Data()) {
+			t.Fatalf("got data %x, want %x", got.Data(), chunk.Data())
+		}
+		if rtt <= 0 {
+			t.Fatalf("got rtt %v, want positive", rtt)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		got, _, err := client.RetrieveChunkFromPeer(context.Background(), invalidChunk.Address(), serverAddress)
+		if !errors.Is(err, swarm.ErrInvalidChunk) {
+			t.Fatalf("got error %v, want %v", err, swarm.ErrInvalidChunk)
+		}
+		if got == nil || !bytes.Equal(got.Data(), invalidChunk.Data()) {
+			t.Fatalf("got chunk %v, want the invalid chunk", got)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := client.RetrieveChunkFromPeer(context.Background(), swarm.MustParseHexAddress("0033000000000000000000000000000000000000000000000000000000000000"), serverAddress)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
 func TestRetrievePreemptiveRetry(t *testing.T) {
 	t.Parallel()
 
@@ -653,6 +723,305 @@ func TestClosestPeer(t *testing.T) {
 	})
 }
 
+func TestClosestForwarder(t *testing.T) {
+	t.Parallel()
+
+	chunkAddr := swarm.MustParseHexAddress("0000000000000000000000000000000000000000000000000000000000000000")
+
+	addr0 := swarm.MustParseHexAddress("8000000000000000000000000000000000000000000000000000000000000000") // po 0
+	addr1 := swarm.MustParseHexAddress("4000000000000000000000000000000000000000000000000000000000000000") // po 1
+	addr7 := swarm.MustParseHexAddress("0100000000000000000000000000000000000000000000000000000000000000") // po 7
+
+	for _, tc := range []struct {
+		name  string
+		depth uint8
+		skip  []swarm.Address
+		want  swarm.Address
+		err   error
+	}{
+		{
+			name:  "closest outside of neighborhood",
+			depth: 2,
+			want:  addr1,
+		},
+		{
+			name:  "skipped",
+			depth: 2,
+			skip:  []swarm.Address{addr1},
+			want:  addr0,
+		},
+		{
+			name:  "shallow neighborhood",
+			depth: 1,
+			want:  addr0,
+		},
+		{
+			name:  "all peers in neighborhood",
+			depth: 0,
+			err:   topology.ErrNotFound,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ret := retrieval.New(swarm.RandAddress(t), nil, nil, topologymock.NewTopologyDriver(
+				topologymock.WithPeers(addr0, addr1, addr7),
+				topologymock.WithNeighborhoodDepth(tc.depth),
+			), log.Noop, nil, nil, nil, false, nil)
+
+			got, err := ret.ClosestForwarder(chunkAddr, tc.skip)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("got error %v, want %v", err, tc.err)
+			}
+			if tc.err == nil && !got.Equal(tc.want) {
+				t.Fatalf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRetrieveChunkMode(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger = log.Noop
+		pricer = pricermock.NewMockService(defaultPrice, defaultPrice)
+		chunk  = testingc.FixtureChunk("0025")
+	)
+
+	for _, tc := range []struct {
+		mode retrieval.Mode
+		// whether the chunk is requested from the storer through the forwarder
+		forwarded bool
+	}{
+		{mode: retrieval.ModeDefault},
+		{mode: retrieval.ModePerformance},
+		{mode: retrieval.ModePrivacy, forwarded: true},
+	} {
+		tc := tc
+		t.Run(tc.mode.String(), func(t *testing.T) {
+			t.Parallel()
+
+			storerAddress := swarm.RandAddressAt(t, chunk.Address(), 8)
+			forwarderAddress := swarm.RandAddressAt(t, chunk.Address(), 1)
+
+			storerStore := storemock.NewStorer()
+			if _, err := storerStore.Put(context.Background(), storage.ModePutUpload, chunk); err != nil {
+				t.Fatal(err)
+			}
+			storer := retrieval.New(storerAddress, storerStore, nil, topologymock.NewTopologyDriver(), logger, accountingmock.NewAccounting(), pricer, nil, false, noopStampValidator)
+			forwarder := retrieval.New(
+				forwarderAddress,
+				storemock.NewStorer(),
+				streamtest.New(streamtest.WithProtocols(storer.Protocol())),
+				topologymock.NewTopologyDriver(topologymock.WithClosestPeer(storerAddress)),
+				logger,
+				accountingmock.NewAccounting(),
+				pricer,
+				nil,
+				false,
+				noopStampValidator,
+			)
+
+			// the streams to both peers are served by the forwarder,
+			// the records show which peer was requested
+			recorder := streamtest.New(streamtest.WithProtocols(forwarder.Protocol()))
+			client := retrieval.New(
+				swarm.RandAddressAt(t, chunk.Address(), 0),
+				nil,
+				recorder,
+				topologymock.NewTopologyDriver(
+					topologymock.WithPeers(storerAddress, forwarderAddress),
+					topologymock.WithNeighborhoodDepth(4),
+				),
+				logger,
+				accountingmock.NewAccounting(),
+				pricer,
+				nil,
+				false,
+				noopStampValidator,
+			)
+
+			ctx := retrieval.WithMode(context.Background(), tc.mode)
+			got, err := client.RetrieveChunk(ctx, chunk.Address(), swarm.ZeroAddress)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got.Data(), chunk.Data()) {
+				t.Fatalf("got data %x, want %x", got.Data(), chunk.Data())
+			}
+
+			want := storerAddress
+			if tc.forwarded {
+				want = forwarderAddress
+				// the storer must never be requested directly
+				if records, _ := recorder.Records(storerAddress, "retrieval", "1.2.0", "retrieval"); len(records) != 0 {
+					t.Fatalf("storer %s requested", storerAddress)
+				}
+			}
+			if records, _ := recorder.Records(want, "retrieval", "1.2.0", "retrieval"); len(records) == 0 {
+				t.Fatalf("peer %s not requested", want)
+			}
+		})
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		value string
+		want  retrieval.Mode
+		err   error
+	}{
+		{value: "", want: retrieval.ModeDefault},
+		{value: "default", want: retrieval.ModeDefault},
+		{value: "Privacy", want: retrieval.ModePrivacy},
+		{value: "performance", want: retrieval.ModePerformance},
+		{value: "fast", err: retrieval.ErrInvalidMode},
+	} {
+		got, err := retrieval.ParseMode(tc.value)
+		if !errors.Is(err, tc.err) {
+			t.Fatalf("%q: got error %v, want %v", tc.value, err, tc.err)
+		}
+		if got != tc.want {
+			t.Fatalf("%q: got mode %v, want %v", tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestRetrieveChunkPriority(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger = log.Noop
+		pricer = pricermock.NewMockService(defaultPrice, defaultPrice)
+	)
+
+	newClient := func(t *testing.T, chunk swarm.Chunk) *retrieval.Service {
+		t.Helper()
+
+		serverAddress := swarm.RandAddressAt(t, chunk.Address(), 8)
+		serverStorer := storemock.NewStorer()
+		if _, err := serverStorer.Put(context.Background(), storage.ModePutUpload, chunk); err != nil {
+			t.Fatal(err)
+		}
+		server := retrieval.New(serverAddress, serverStorer, nil, topologymock.NewTopologyDriver(), logger, accountingmock.NewAccounting(), pricer, nil, false, noopStampValidator)
+
+		return retrieval.New(
+			swarm.RandAddressAt(t, chunk.Address(), 0),
+			nil,
+			streamtest.New(streamtest.WithProtocols(server.Protocol())),
+			topologymock.NewTopologyDriver(topologymock.WithClosestPeer(serverAddress)),
+			logger,
+			accountingmock.NewAccounting(),
+			pricer,
+			nil,
+			false,
+			noopStampValidator,
+		)
+	}
+
+	t.Run("workers", func(t *testing.T) {
+		t.Parallel()
+
+		chunk := testingc.FixtureChunk("0025")
+		client := newClient(t, chunk)
+		client.SetPriorityLimits(retrieval.PriorityBackground, retrieval.PriorityLimits{Workers: 1})
+
+		ctx := retrieval.WithPriority(context.Background(), retrieval.PriorityBackground)
+		got, err := client.RetrieveChunk(ctx, chunk.Address(), swarm.ZeroAddress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got.Data(), chunk.Data()) {
+			t.Fatalf("got data %x, want %x", got.Data(), chunk.Data())
+		}
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		t.Parallel()
+
+		chunk := testingc.FixtureChunk("0025")
+		client := newClient(t, chunk)
+		// the budget of the background class is below the price of the chunk
+		client.SetPriorityLimits(retrieval.PriorityBackground, retrieval.PriorityLimits{Budget: defaultPrice - 1})
+
+		ctx, cancel := context.WithTimeout(retrieval.WithPriority(context.Background(), retrieval.PriorityBackground), 500*time.Millisecond)
+		defer cancel()
+		if _, err := client.RetrieveChunk(ctx, chunk.Address(), swarm.ZeroAddress); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+		}
+
+		// the interactive retrievals are not limited by the background budget
+		got, err := client.RetrieveChunk(context.Background(), chunk.Address(), swarm.ZeroAddress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got.Data(), chunk.Data()) {
+			t.Fatalf("got data %x, want %x", got.Data(), chunk.Data())
+		}
+	})
+
+	t.Run("budget wait", func(t *testing.T) {
+		t.Parallel()
+
+		chunk := testingc.FixtureChunk("0025")
+		client := newClient(t, chunk)
+		client.SetPriorityLimits(retrieval.PriorityBackground, retrieval.PriorityLimits{Budget: defaultPrice})
+		release := client.ReserveBudget(retrieval.PriorityBackground, defaultPrice)
+
+		// the retrieval waits for the budget instead of skipping the only peer
+		errC := make(chan error, 1)
+		go func() {
+			ctx := retrieval.WithPriority(context.Background(), retrieval.PriorityBackground)
+			_, err := client.RetrieveChunk(ctx, chunk.Address(), swarm.ZeroAddress)
+			errC <- err
+		}()
+		select {
+		case err := <-errC:
+			t.Fatalf("retrieval finished over the budget with error %v", err)
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		// the freed budget is taken up right away,
+		// not after the refresh of the skipped peers
+		release()
+		select {
+		case err := <-errC:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(500 * time.Millisecond):
+			t.Fatal("timeout waiting for the retrieval")
+		}
+	})
+}
+
+func TestParsePriority(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		value string
+		want  retrieval.Priority
+		err   error
+	}{
+		{value: "", want: retrieval.PriorityInteractive},
+		{value: "interactive", want: retrieval.PriorityInteractive},
+		{value: "Background", want: retrieval.PriorityBackground},
+		{value: "urgent", err: retrieval.ErrInvalidPriority},
+	} {
+		got, err := retrieval.ParsePriority(tc.value)
+		if !errors.Is(err, tc.err) {
+			t.Fatalf("%q: got error %v, want %v", tc.value, err, tc.err)
+		}
+		if got != tc.want {
+			t.Fatalf("%q: got priority %v, want %v", tc.value, got, tc.want)
+		}
+	}
+}
+
 var noopStampValidator = func(chunk swarm.Chunk, stampBytes []byte) (swarm.Chunk, error) {
 	return chunk, nil
 }
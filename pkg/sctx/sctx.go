@@ -25,6 +25,7 @@ type (
 	tagKey           struct{}
 	gasPriceKey      struct{}
 	gasLimitKey      struct{}
+	pushSyncKey      struct{}
 )
 
 // SetHost sets the http request host in the context
@@ -87,3 +88,15 @@ func GetGasPrice(ctx context.Context) *big.Int {
 	}
 	return nil
 }
+
+// SetPushSync marks the context of the chunks stored by the push sync,
+// so that they are told apart from the chunks stored by the pull sync.
+func SetPushSync(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pushSyncKey{}, true)
+}
+
+// IsPushSync reports whether the chunks are stored by the push sync.
+func IsPushSync(ctx context.Context) bool {
+	v, _ := ctx.Value(pushSyncKey{}).(bool)
+	return v
+}
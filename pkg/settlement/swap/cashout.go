@@ -0,0 +1,54 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package swap
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// CashoutJob returns the maintenance job which cashes the last received
+// cheques of the peers whose uncashed amount is at least the minimum amount.
+// The cheques with a pending cashout are not cashed again.
+func CashoutJob(s Interface, minAmount *big.Int, logger log.Logger) func(context.Context) error {
+	logger = logger.WithName(loggerName).Register()
+
+	return func(ctx context.Context) error {
+		cheques, err := s.LastReceivedCheques()
+		if err != nil {
+			return fmt.Errorf("last received cheques: %w", err)
+		}
+
+		for p := range cheques {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			peer, err := swarm.ParseHexAddress(p)
+			if err != nil {
+				return fmt.Errorf("parse peer address %q: %w", p, err)
+			}
+			status, err := s.CashoutStatus(ctx, peer)
+			if err != nil {
+				logger.Debug("cashout status failed", "peer_address", peer, "error", err)
+				continue
+			}
+			if status.UncashedAmount == nil || status.UncashedAmount.Sign() <= 0 || status.UncashedAmount.Cmp(minAmount) < 0 {
+				continue
+			}
+			txHash, err := s.CashCheque(ctx, peer)
+			if err != nil {
+				logger.Debug("cashout failed", "peer_address", peer, "error", err)
+				continue
+			}
+			logger.Info("cheque cashed out", "peer_address", peer, "amount", status.UncashedAmount, "tx", txHash)
+		}
+		return nil
+	}
+}
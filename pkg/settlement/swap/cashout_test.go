@@ -0,0 +1,58 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package swap_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/settlement/swap"
+	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook"
+	"github.com/ethersphere/bee/pkg/settlement/swap/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestCashoutJob(t *testing.T) {
+	t.Parallel()
+
+	var (
+		above    = swarm.MustParseHexAddress("01")
+		below    = swarm.MustParseHexAddress("02")
+		cashed   = swarm.MustParseHexAddress("03")
+		uncashed = map[string]*big.Int{
+			above.String():  big.NewInt(100),
+			below.String():  big.NewInt(10),
+			cashed.String(): big.NewInt(0),
+		}
+		cashouts []swarm.Address
+	)
+
+	s := mock.New(
+		mock.WithLastReceivedChequesFunc(func() (map[string]*chequebook.SignedCheque, error) {
+			cheques := make(map[string]*chequebook.SignedCheque)
+			for p := range uncashed {
+				cheques[p] = &chequebook.SignedCheque{}
+			}
+			return cheques, nil
+		}),
+		mock.WithCashoutStatusFunc(func(_ context.Context, peer swarm.Address) (*chequebook.CashoutStatus, error) {
+			return &chequebook.CashoutStatus{UncashedAmount: uncashed[peer.String()]}, nil
+		}),
+		mock.WithCashChequeFunc(func(_ context.Context, peer swarm.Address) (common.Hash, error) {
+			cashouts = append(cashouts, peer)
+			return common.Hash{}, nil
+		}),
+	)
+
+	if err := swap.CashoutJob(s, big.NewInt(50), log.Noop)(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(cashouts) != 1 || !cashouts[0].Equal(above) {
+		t.Fatalf("got cashouts %v, want %v", cashouts, []swarm.Address{above})
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shaping
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	Admitted prometheus.Counter // number of admitted requests
+	Rejected prometheus.Counter // number of requests rejected with a full queue or a timeout
+	Active   prometheus.Gauge   // number of requests being served
+	Queued   prometheus.Gauge   // number of waiting requests
+}
+
+func newMetrics() metrics {
+	subsystem := "shaping"
+
+	return metrics{
+		Admitted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "admitted",
+			Help:      "Total admitted requests.",
+		}),
+		Rejected: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "rejected",
+			Help:      "Total requests rejected with a full queue or a timeout.",
+		}),
+		Active: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "active",
+			Help:      "Number of requests being served.",
+		}),
+		Queued: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "queued",
+			Help:      "Number of waiting requests.",
+		}),
+	}
+}
+
+func (s *Scheduler) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(s.metrics)
+}
@@ -0,0 +1,252 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package shaping schedules the download requests of the API fairly, so that
+// a single hot reference or a single client can not take all of the
+// retrieval workers. The requests over the concurrency limits wait in a
+// queue, from which the clients are served in turns, and the requests which
+// can not be queued or which wait for too long are rejected.
+package shaping
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultQueueSize is the default number of the waiting requests.
+	DefaultQueueSize = 1000
+	// DefaultQueueTimeout is the default time a request waits in the queue.
+	DefaultQueueTimeout = 10 * time.Second
+)
+
+var (
+	// ErrQueueFull is returned if the request can not be queued.
+	ErrQueueFull = errors.New("queue full")
+	// ErrQueueTimeout is returned if the request waited in the queue for too long.
+	ErrQueueTimeout = errors.New("queue timeout")
+)
+
+// Options are the options of the Scheduler. The zero limits are unlimited.
+type Options struct {
+	// MaxConcurrent is the number of the requests served at once.
+	MaxConcurrent int
+	// PerClient is the number of the requests of a client served at once.
+	PerClient int
+	// PerReference is the number of the requests for a reference served at once.
+	PerReference int
+	// QueueSize is the number of the waiting requests.
+	QueueSize int
+	// QueueTimeout is the time a request waits in the queue.
+	QueueTimeout time.Duration
+}
+
+// waiter is a queued request.
+type waiter struct {
+	client    string
+	reference string
+	ready     chan struct{} // closed once the request is admitted
+}
+
+// Scheduler admits the requests within the concurrency limits.
+type Scheduler struct {
+	maxConcurrent int
+	perClient     int
+	perReference  int
+	queueSize     int
+	queueTimeout  time.Duration
+
+	mu         sync.Mutex
+	active     int
+	clients    map[string]int       // number of the active requests by the clients
+	references map[string]int       // number of the active requests by the references
+	queues     map[string][]*waiter // waiting requests by the clients
+	turns      []string             // clients with the waiting requests in the order of their turns
+	queued     int
+
+	metrics metrics
+}
+
+// New returns a new Scheduler with the options.
+func New(o Options) *Scheduler {
+	if o.QueueSize <= 0 {
+		o.QueueSize = DefaultQueueSize
+	}
+	if o.QueueTimeout <= 0 {
+		o.QueueTimeout = DefaultQueueTimeout
+	}
+	return &Scheduler{
+		maxConcurrent: o.MaxConcurrent,
+		perClient:     o.PerClient,
+		perReference:  o.PerReference,
+		queueSize:     o.QueueSize,
+		queueTimeout:  o.QueueTimeout,
+		clients:       make(map[string]int),
+		references:    make(map[string]int),
+		queues:        make(map[string][]*waiter),
+		metrics:       newMetrics(),
+	}
+}
+
+// Acquire waits until the request of the client for the reference is
+// admitted, and returns the function which must be called once the request
+// is served. ErrQueueFull or ErrQueueTimeout is returned if the request is
+// rejected.
+func (s *Scheduler) Acquire(ctx context.Context, client, reference string) (release func(), err error) {
+	s.mu.Lock()
+	// the earlier requests of the client are admitted first
+	if len(s.queues[client]) == 0 && s.admissible(client, reference) {
+		s.admit(client, reference)
+		s.mu.Unlock()
+		return s.releaseFunc(client, reference), nil
+	}
+	if s.queued >= s.queueSize {
+		s.mu.Unlock()
+		s.metrics.Rejected.Inc()
+		return nil, ErrQueueFull
+	}
+	w := &waiter{client: client, reference: reference, ready: make(chan struct{})}
+	if len(s.queues[client]) == 0 {
+		s.turns = append(s.turns, client)
+	}
+	s.queues[client] = append(s.queues[client], w)
+	s.queued++
+	s.metrics.Queued.Inc()
+	s.mu.Unlock()
+
+	timer := time.NewTimer(s.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-w.ready:
+		return s.releaseFunc(client, reference), nil
+	case <-timer.C:
+		err = ErrQueueTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-w.ready:
+		// admitted concurrently, so the place is given to the next request
+		s.release(client, reference)
+	default:
+		// the next request of the client may be admissible
+		s.remove(w)
+		s.dispatch()
+	}
+	if errors.Is(err, ErrQueueTimeout) {
+		s.metrics.Rejected.Inc()
+	}
+	return nil, err
+}
+
+// RetryAfter returns the time after which the rejected requests are retried.
+func (s *Scheduler) RetryAfter() time.Duration {
+	return s.queueTimeout
+}
+
+// Queued returns the number of the waiting requests.
+func (s *Scheduler) Queued() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queued
+}
+
+func (s *Scheduler) releaseFunc(client, reference string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.release(client, reference)
+		})
+	}
+}
+
+// admissible reports whether the request is within the limits.
+// It must be called with the lock held.
+func (s *Scheduler) admissible(client, reference string) bool {
+	return (s.maxConcurrent <= 0 || s.active < s.maxConcurrent) &&
+		(s.perClient <= 0 || s.clients[client] < s.perClient) &&
+		(s.perReference <= 0 || s.references[reference] < s.perReference)
+}
+
+// admit must be called with the lock held.
+func (s *Scheduler) admit(client, reference string) {
+	s.active++
+	s.clients[client]++
+	s.references[reference]++
+	s.metrics.Admitted.Inc()
+	s.metrics.Active.Inc()
+}
+
+// release frees the place of the request and admits the waiting requests
+// which are within the limits. It must be called with the lock held.
+func (s *Scheduler) release(client, reference string) {
+	s.active--
+	if s.clients[client]--; s.clients[client] == 0 {
+		delete(s.clients, client)
+	}
+	if s.references[reference]--; s.references[reference] == 0 {
+		delete(s.references, reference)
+	}
+	s.metrics.Active.Dec()
+	s.dispatch()
+}
+
+// dispatch admits the waiting requests, taking the clients in turns, so
+// that the clients with many requests do not delay the others. Only the
+// first request of every client is considered, as the requests of a client
+// are admitted in order. It must be called with the lock held.
+func (s *Scheduler) dispatch() {
+	for i := 0; i < len(s.turns); i++ {
+		if s.maxConcurrent > 0 && s.active >= s.maxConcurrent {
+			return
+		}
+		client := s.turns[i]
+		w := s.queues[client][0]
+		if !s.admissible(w.client, w.reference) {
+			continue
+		}
+		s.admit(w.client, w.reference)
+		close(w.ready)
+		s.remove(w)
+
+		// the client takes its next turn after the others
+		if len(s.queues[client]) > 0 {
+			s.turns = append(append(s.turns[:i], s.turns[i+1:]...), client)
+		}
+		i--
+	}
+}
+
+// remove removes the waiter from the queue.
+// It must be called with the lock held.
+func (s *Scheduler) remove(w *waiter) {
+	q := s.queues[w.client]
+	for i := range q {
+		if q[i] == w {
+			q = append(q[:i], q[i+1:]...)
+			break
+		}
+	}
+	s.queued--
+	s.metrics.Queued.Dec()
+	if len(q) > 0 {
+		s.queues[w.client] = q
+		return
+	}
+	delete(s.queues, w.client)
+	for i, c := range s.turns {
+		if c == w.client {
+			s.turns = append(s.turns[:i], s.turns[i+1:]...)
+			break
+		}
+	}
+}
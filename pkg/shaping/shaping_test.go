@@ -0,0 +1,158 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shaping_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/shaping"
+)
+
+func acquire(t *testing.T, s *shaping.Scheduler, client, reference string) func() {
+	t.Helper()
+
+	release, err := s.Acquire(context.Background(), client, reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return release
+}
+
+// acquireAsync acquires in the background and sends
+// the name of the request once it is admitted.
+func acquireAsync(t *testing.T, s *shaping.Scheduler, name, client, reference string, admitted chan<- string) {
+	t.Helper()
+
+	go func() {
+		release, err := s.Acquire(context.Background(), client, reference)
+		if err != nil {
+			admitted <- err.Error()
+			return
+		}
+		admitted <- name
+		release()
+	}()
+}
+
+// waitQueued waits until the number of queued requests is reached, as the
+// order of the queued requests is given by the order of their Acquire calls.
+func waitQueued(t *testing.T, s *shaping.Scheduler, n int) {
+	t.Helper()
+
+	for i := 0; i < 100; i++ {
+		if s.Queued() == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("got %d queued requests, want %d", s.Queued(), n)
+}
+
+func TestPerReference(t *testing.T) {
+	t.Parallel()
+
+	s := shaping.New(shaping.Options{PerReference: 1})
+
+	release := acquire(t, s, "a", "hot")
+	// other references are not limited by the hot one
+	acquire(t, s, "b", "cold")()
+
+	admitted := make(chan string, 1)
+	acquireAsync(t, s, "second", "b", "hot", admitted)
+	waitQueued(t, s, 1)
+
+	release()
+	if got := <-admitted; got != "second" {
+		t.Fatalf("got %q, want %q", got, "second")
+	}
+}
+
+func TestPerClientFairness(t *testing.T) {
+	t.Parallel()
+
+	s := shaping.New(shaping.Options{MaxConcurrent: 1})
+	release := acquire(t, s, "greedy", "r0")
+
+	admitted := make(chan string, 4)
+	acquireAsync(t, s, "greedy-1", "greedy", "r1", admitted)
+	waitQueued(t, s, 1)
+	acquireAsync(t, s, "greedy-2", "greedy", "r2", admitted)
+	waitQueued(t, s, 2)
+	acquireAsync(t, s, "greedy-3", "greedy", "r3", admitted)
+	waitQueued(t, s, 3)
+	acquireAsync(t, s, "polite-1", "polite", "r4", admitted)
+	waitQueued(t, s, 4)
+
+	release()
+
+	var order []string
+	for i := 0; i < 4; i++ {
+		order = append(order, <-admitted)
+	}
+	// the polite client is served in its turn, before the rest of the greedy requests
+	want := []string{"greedy-1", "polite-1", "greedy-2", "greedy-3"}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("got order %v, want %v", order, want)
+		}
+	}
+}
+
+func TestPerClient(t *testing.T) {
+	t.Parallel()
+
+	s := shaping.New(shaping.Options{PerClient: 1, QueueTimeout: 50 * time.Millisecond})
+	release := acquire(t, s, "a", "r1")
+	defer release()
+
+	if _, err := s.Acquire(context.Background(), "a", "r2"); !errors.Is(err, shaping.ErrQueueTimeout) {
+		t.Fatalf("got error %v, want %v", err, shaping.ErrQueueTimeout)
+	}
+	// other clients are not limited
+	acquire(t, s, "b", "r2")()
+	if got := s.Queued(); got != 0 {
+		t.Fatalf("got %d queued requests, want 0", got)
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	t.Parallel()
+
+	s := shaping.New(shaping.Options{MaxConcurrent: 1, QueueSize: 1})
+	release := acquire(t, s, "a", "r1")
+
+	admitted := make(chan string, 1)
+	acquireAsync(t, s, "queued", "b", "r2", admitted)
+	waitQueued(t, s, 1)
+
+	if _, err := s.Acquire(context.Background(), "c", "r3"); !errors.Is(err, shaping.ErrQueueFull) {
+		t.Fatalf("got error %v, want %v", err, shaping.ErrQueueFull)
+	}
+
+	release()
+	if got := <-admitted; got != "queued" {
+		t.Fatalf("got %q, want %q", got, "queued")
+	}
+}
+
+func TestCancel(t *testing.T) {
+	t.Parallel()
+
+	s := shaping.New(shaping.Options{MaxConcurrent: 1})
+	release := acquire(t, s, "a", "r1")
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.Acquire(ctx, "b", "r2"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if got := s.Queued(); got != 0 {
+		t.Fatalf("got %d queued requests, want 0", got)
+	}
+}
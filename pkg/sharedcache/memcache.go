@@ -0,0 +1,117 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sharedcache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/util/connpool"
+)
+
+// maxMemcacheTTL is the longest expiration time in seconds which memcached
+// interprets as relative, the longer ones are interpreted as unix times.
+const maxMemcacheTTL = 30 * 24 * time.Hour
+
+// memcache is the backend which talks the text protocol to a memcached server.
+type memcache struct {
+	pool *connpool.Pool
+}
+
+func newMemcache(u *url.URL, o Options) *memcache {
+	return &memcache{
+		pool: connpool.New(u.Host, o.PoolSize, o.Timeout, nil),
+	}
+}
+
+func (m *memcache) get(ctx context.Context, key string) (v []byte, err error) {
+	err = m.pool.Do(ctx, func(c *connpool.Conn) error {
+		if _, err := fmt.Fprintf(c.W, "get %s\r\n", key); err != nil {
+			return err
+		}
+		if err := c.W.Flush(); err != nil {
+			return err
+		}
+
+		line, err := c.ReadLine()
+		if err != nil {
+			return err
+		}
+		if line == "END" {
+			return nil
+		}
+
+		// VALUE <key> <flags> <bytes>
+		fields := strings.Fields(line)
+		if len(fields) != 4 || fields[0] != "VALUE" || fields[1] != key {
+			return fmt.Errorf("memcache: unexpected reply %q", line)
+		}
+		n, err := strconv.Atoi(fields[3])
+		if err != nil || n < 0 {
+			return fmt.Errorf("memcache: invalid value length %q", fields[3])
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.R, buf); err != nil {
+			return err
+		}
+		if line, err := c.ReadLine(); err != nil {
+			return err
+		} else if line != "END" {
+			return fmt.Errorf("memcache: unexpected reply %q", line)
+		}
+		v = buf[:n]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, storage.ErrNotFound
+	}
+	return v, nil
+}
+
+func (m *memcache) set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl > maxMemcacheTTL {
+		ttl = maxMemcacheTTL
+	}
+	// zero expiration time means that the value never expires
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	return m.pool.Do(ctx, func(c *connpool.Conn) error {
+		if _, err := fmt.Fprintf(c.W, "set %s 0 %d %d\r\n", key, int64(ttl.Seconds()), len(value)); err != nil {
+			return err
+		}
+		if _, err := c.W.Write(value); err != nil {
+			return err
+		}
+		if _, err := c.W.WriteString("\r\n"); err != nil {
+			return err
+		}
+		if err := c.W.Flush(); err != nil {
+			return err
+		}
+
+		line, err := c.ReadLine()
+		if err != nil {
+			return err
+		}
+		if line != "STORED" {
+			return fmt.Errorf("memcache: %s", line)
+		}
+		return nil
+	})
+}
+
+func (m *memcache) close() error {
+	return m.pool.Close()
+}
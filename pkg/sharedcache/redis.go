@@ -0,0 +1,44 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sharedcache
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/util/resp"
+)
+
+// redis is the backend which talks the RESP protocol to a Redis server.
+type redis struct {
+	client *resp.Client
+}
+
+func newRedis(u *url.URL, o Options) (*redis, error) {
+	client, err := resp.New(u, o.PoolSize, o.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &redis{client: client}, nil
+}
+
+func (r *redis) get(ctx context.Context, key string) ([]byte, error) {
+	v, err := r.client.Get(ctx, key)
+	if errors.Is(err, resp.ErrNil) {
+		return nil, storage.ErrNotFound
+	}
+	return v, err
+}
+
+func (r *redis) set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl)
+}
+
+func (r *redis) close() error {
+	return r.client.Close()
+}
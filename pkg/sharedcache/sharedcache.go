@@ -0,0 +1,157 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sharedcache provides a second level chunk cache which is shared
+// by multiple gateway nodes, so that the nodes of a cluster serving the same
+// popular content do not each retrieve it from the network. The cache is
+// backed by a Redis or a memcached server.
+package sharedcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const (
+	keyPrefix        = "bee:chunk:"
+	defaultTTL       = 24 * time.Hour
+	defaultPoolSize  = 16
+	defaultOpTimeout = time.Second
+)
+
+var (
+	// ErrUnsupportedBackend is returned by New if the scheme
+	// of the target is not a supported cache backend.
+	ErrUnsupportedBackend = errors.New("unsupported cache backend")
+	// ErrInvalidChunk is returned by Get if the cached value is not a valid
+	// chunk with the requested address, which can be the case when the cache
+	// is shared with untrusted nodes.
+	ErrInvalidChunk = errors.New("invalid cached chunk")
+)
+
+// Cache is the chunk cache shared by multiple nodes.
+type Cache interface {
+	// Get returns the cached chunk with its postage stamp or
+	// storage.ErrNotFound if the chunk is not cached.
+	Get(ctx context.Context, addr swarm.Address) (swarm.Chunk, error)
+	// Put caches the chunk with its postage stamp.
+	Put(ctx context.Context, ch swarm.Chunk) error
+	io.Closer
+}
+
+// Options are the options of the shared cache.
+type Options struct {
+	// TTL is the time after which the cached chunks expire.
+	TTL time.Duration
+	// PoolSize is the maximal number of idle connections to the server.
+	PoolSize int
+	// Timeout is the timeout of the operations on the server, which is
+	// applied if the context of the operation has no earlier deadline.
+	Timeout time.Duration
+}
+
+// backend stores the raw values on the server.
+type backend interface {
+	get(ctx context.Context, key string) ([]byte, error)
+	set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	close() error
+}
+
+type cache struct {
+	backend backend
+	ttl     time.Duration
+}
+
+// New returns the cache backed by the server at the target URL. The
+// supported targets are redis://[:password@]host:port[/db] and
+// memcache://host:port.
+func New(target string, o Options) (Cache, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("parse target: %w", err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("target %q has no host", target)
+	}
+
+	if o.TTL <= 0 {
+		o.TTL = defaultTTL
+	}
+	if o.PoolSize <= 0 {
+		o.PoolSize = defaultPoolSize
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = defaultOpTimeout
+	}
+
+	var b backend
+	switch u.Scheme {
+	case "redis":
+		b, err = newRedis(u, o)
+	case "memcache":
+		b = newMemcache(u, o)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedBackend, u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &cache{backend: b, ttl: o.TTL}, nil
+}
+
+// Get implements Cache.Get method.
+func (c *cache) Get(ctx context.Context, addr swarm.Address) (swarm.Chunk, error) {
+	v, err := c.backend.get(ctx, key(addr))
+	if err != nil {
+		return nil, err
+	}
+	if len(v) < postage.StampSize {
+		return nil, ErrInvalidChunk
+	}
+
+	stamp := new(postage.Stamp)
+	if err := stamp.UnmarshalBinary(v[:postage.StampSize]); err != nil {
+		return nil, ErrInvalidChunk
+	}
+	ch := swarm.NewChunk(addr, v[postage.StampSize:]).WithStamp(stamp)
+	if !cac.Valid(ch) && !soc.Valid(ch) {
+		return nil, ErrInvalidChunk
+	}
+	return ch, nil
+}
+
+// Put implements Cache.Put method.
+func (c *cache) Put(ctx context.Context, ch swarm.Chunk) error {
+	if ch.Stamp() == nil {
+		return errors.New("chunk has no stamp")
+	}
+	stamp, err := ch.Stamp().MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("marshal stamp: %w", err)
+	}
+	v := make([]byte, 0, len(stamp)+len(ch.Data()))
+	v = append(v, stamp...)
+	v = append(v, ch.Data()...)
+
+	return c.backend.set(ctx, key(ch.Address()), v, c.ttl)
+}
+
+// Close implements Cache.Close method.
+func (c *cache) Close() error {
+	return c.backend.close()
+}
+
+func key(addr swarm.Address) string {
+	return keyPrefix + addr.String()
+}
@@ -0,0 +1,203 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sharedcache_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/sharedcache"
+	"github.com/ethersphere/bee/pkg/storage"
+	chunktesting "github.com/ethersphere/bee/pkg/storage/testing"
+	"github.com/ethersphere/bee/pkg/util/resp/mock"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name string
+		// setup starts the server and returns the target
+		// and the function which copies the raw values
+		setup func(t *testing.T) (string, func(from, to string))
+	}{
+		{
+			name: "redis",
+			setup: func(t *testing.T) (string, func(from, to string)) {
+				t.Helper()
+
+				server, err := mock.NewServer(mock.WithPassword("secret"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { _ = server.Close() })
+
+				return "redis://:secret@" + server.Addr() + "/2", func(from, to string) {
+					v, _ := server.Get(from)
+					server.Set(to, v)
+				}
+			},
+		},
+		{
+			name: "memcache",
+			setup: func(t *testing.T) (string, func(from, to string)) {
+				t.Helper()
+
+				store := newMemcacheServer(t)
+				return "memcache://" + store.addr, store.copy
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			target, copyValue := tc.setup(t)
+			c, err := sharedcache.New(target, sharedcache.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() {
+				if err := c.Close(); err != nil {
+					t.Error(err)
+				}
+			})
+
+			ctx := context.Background()
+			ch := chunktesting.GenerateTestRandomChunk()
+
+			if _, err := c.Get(ctx, ch.Address()); !errors.Is(err, storage.ErrNotFound) {
+				t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+			}
+
+			if err := c.Put(ctx, ch); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := c.Get(ctx, ch.Address())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(ch) {
+				t.Fatal("cached chunk not equal to the put chunk")
+			}
+
+			// the values of the other nodes are not trusted
+			invalid := chunktesting.GenerateTestRandomChunk()
+			copyValue("bee:chunk:"+ch.Address().String(), "bee:chunk:"+invalid.Address().String())
+			if _, err := c.Get(ctx, invalid.Address()); !errors.Is(err, sharedcache.ErrInvalidChunk) {
+				t.Fatalf("got error %v, want %v", err, sharedcache.ErrInvalidChunk)
+			}
+		})
+	}
+}
+
+func TestUnsupportedBackend(t *testing.T) {
+	t.Parallel()
+
+	_, err := sharedcache.New("mongodb://localhost:27017", sharedcache.Options{})
+	if !errors.Is(err, sharedcache.ErrUnsupportedBackend) {
+		t.Fatalf("got error %v, want %v", err, sharedcache.ErrUnsupportedBackend)
+	}
+}
+
+type fakeStore struct {
+	addr string
+
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func (s *fakeStore) copy(from, to string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[to] = s.values[from]
+}
+
+// newMemcacheServer starts a server which stores the values
+// in memory and talks the memcached text protocol.
+func newMemcacheServer(t *testing.T) *fakeStore {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &fakeStore{addr: l.Addr().String(), values: make(map[string][]byte)}
+
+	var wg sync.WaitGroup
+	t.Cleanup(func() {
+		_ = l.Close()
+		wg.Wait()
+	})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer c.Close()
+				rw := bufio.NewReadWriter(bufio.NewReader(c), bufio.NewWriter(c))
+				for {
+					if err := serveMemcache(rw, store); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	return store
+}
+
+func serveMemcache(rw *bufio.ReadWriter, s *fakeStore) error {
+	line, err := rw.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(line)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch fields[0] {
+	case "get":
+		if v, ok := s.values[fields[1]]; ok {
+			_, _ = fmt.Fprintf(rw, "VALUE %s 0 %d\r\n%s\r\n", fields[1], len(v), v)
+		}
+		_, _ = rw.WriteString("END\r\n")
+	case "set":
+		n, err := strconv.Atoi(fields[4])
+		if err != nil {
+			return err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(rw, buf); err != nil {
+			return err
+		}
+		s.values[fields[1]] = bytes.Clone(buf[:n])
+		_, _ = rw.WriteString("STORED\r\n")
+	default:
+		_, _ = rw.WriteString("ERROR\r\n")
+	}
+
+	return rw.Flush()
+}
@@ -0,0 +1,224 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package simulator runs a cluster of in-memory bee nodes in a single
+// process. The nodes run the real pushsync, retrieval and pullsync
+// protocols over an in-process transport, on top of the in-memory
+// localstore, while the chain, accounting and pricing are mocked. The
+// node identities are derived from a seed, so that the clusters are
+// deterministic and can be used in the integration tests.
+package simulator
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/accounting"
+	accountingmock "github.com/ethersphere/bee/pkg/accounting/mock"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/localstore"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/streamtest"
+	"github.com/ethersphere/bee/pkg/postage"
+	pricermock "github.com/ethersphere/bee/pkg/pricer/mock"
+	"github.com/ethersphere/bee/pkg/pullsync"
+	"github.com/ethersphere/bee/pkg/pullsync/pullstorage"
+	"github.com/ethersphere/bee/pkg/pushsync"
+	"github.com/ethersphere/bee/pkg/retrieval"
+	statestoremock "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/hashicorp/go-multierror"
+)
+
+const networkID = 1
+
+// Options configure the simulated cluster.
+type Options struct {
+	Nodes         int        // number of nodes in the cluster
+	Seed          int64      // seed from which the node identities are derived
+	StorageRadius uint8      // storage radius of all nodes, zero means every node stores every chunk it receives
+	Logger        log.Logger // logger of the nodes, no-op logger if nil
+}
+
+// Node is a single simulated node.
+type Node struct {
+	Overlay    swarm.Address
+	Signer     crypto.Signer
+	Storer     *localstore.DB
+	Accounting accounting.Interface
+	PushSync   *pushsync.PushSync
+	Retrieval  *retrieval.Service
+	PullSync   *pullsync.Syncer
+	Streamer   *streamtest.Recorder
+}
+
+// Cluster is a set of simulated nodes connected in a full mesh.
+type Cluster struct {
+	Nodes []*Node
+}
+
+// New creates a new cluster of simulated nodes.
+func New(o Options) (*Cluster, error) {
+	if o.Nodes <= 0 {
+		return nil, errors.New("simulator: cluster must have at least one node")
+	}
+	logger := o.Logger
+	if logger == nil {
+		logger = log.Noop
+	}
+
+	c := &Cluster{Nodes: make([]*Node, o.Nodes)}
+	overlays := make([]swarm.Address, o.Nodes)
+	for i := range c.Nodes {
+		signer, overlay, err := identity(o.Seed, i)
+		if err != nil {
+			return nil, fmt.Errorf("simulator: node %d identity: %w", i, err)
+		}
+		c.Nodes[i] = &Node{Overlay: overlay, Signer: signer}
+		overlays[i] = overlay
+	}
+
+	// all nodes share the same routing table of the protocols by the peer
+	protocols := make(map[string]p2p.ProtocolSpec, o.Nodes)
+
+	for i, n := range c.Nodes {
+		peers := make([]swarm.Address, 0, o.Nodes-1)
+		peers = append(peers, overlays[:i]...)
+		peers = append(peers, overlays[i+1:]...)
+
+		if err := n.start(peers, protocols, o.StorageRadius, logger); err != nil {
+			_ = c.Close()
+			return nil, fmt.Errorf("simulator: node %d: %w", i, err)
+		}
+	}
+
+	return c, nil
+}
+
+func (n *Node) start(peers []swarm.Address, protocols map[string]p2p.ProtocolSpec, storageRadius uint8, logger log.Logger) error {
+	// the stamps are attached to copies of the chunks, as the protocols may
+	// still be using the originals, but are not validated against a chain
+	validStamp := func(ch swarm.Chunk, stampBytes []byte) (swarm.Chunk, error) {
+		stamp := new(postage.Stamp)
+		if err := stamp.UnmarshalBinary(stampBytes); err != nil {
+			return nil, err
+		}
+		return swarm.NewChunk(ch.Address(), ch.Data()).WithStamp(stamp), nil
+	}
+
+	storer, err := localstore.New("", n.Overlay.Bytes(), nil, &localstore.Options{
+		UnreserveFunc: func(postage.UnreserveIteratorFn) error { return nil },
+		ValidStamp:    validStamp,
+	}, logger)
+	if err != nil {
+		return fmt.Errorf("localstore: %w", err)
+	}
+	n.Storer = storer
+
+	n.Streamer = streamtest.New(
+		streamtest.WithBaseAddr(n.Overlay),
+		streamtest.WithPeerProtocols(protocols),
+	)
+	streamer := streamtest.NewRecorderDisconnecter(n.Streamer)
+	mesh := newFullMesh(n.Overlay, peers)
+	rs := radius{base: n.Overlay, radius: storageRadius}
+	pricer := pricermock.NewMockService(0, 0)
+	n.Accounting = accountingmock.NewAccounting()
+	tagService := tags.NewTags(statestoremock.NewStateStore(), logger)
+
+	n.PushSync = pushsync.New(n.Overlay, nil, streamer, storer, mesh, rs, tagService, true, nil, validStamp, logger, n.Accounting, pricer, n.Signer, nil, 0)
+	n.Retrieval = retrieval.New(n.Overlay, storer, n.Streamer, mesh, logger, n.Accounting, pricer, nil, false, validStamp)
+	n.PullSync = pullsync.New(n.Streamer, pullstorage.New(storer, logger), func(swarm.Chunk) {}, validStamp, logger, rs, n.Overlay)
+
+	protocols[n.Overlay.String()] = merge(n.PushSync.Protocol(), n.Retrieval.Protocol(), n.PullSync.Protocol())
+	return nil
+}
+
+// Close stops all the nodes of the cluster.
+func (c *Cluster) Close() error {
+	var errs *multierror.Error
+	for _, n := range c.Nodes {
+		if n == nil {
+			continue
+		}
+		if n.PullSync != nil {
+			if err := n.PullSync.Close(); err != nil {
+				errs = multierror.Append(errs, err)
+			}
+		}
+		if n.Storer != nil {
+			if err := n.Storer.Close(); err != nil {
+				errs = multierror.Append(errs, err)
+			}
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// Closest returns the node closest to the address.
+func (c *Cluster) Closest(addr swarm.Address) *Node {
+	closest := c.Nodes[0]
+	for _, n := range c.Nodes[1:] {
+		if closer, _ := n.Overlay.Closer(addr, closest.Overlay); closer {
+			closest = n
+		}
+	}
+	return closest
+}
+
+// Node returns the node with the given overlay address or nil
+// if the node is not in the cluster.
+func (c *Cluster) Node(overlay swarm.Address) *Node {
+	for _, n := range c.Nodes {
+		if n.Overlay.Equal(overlay) {
+			return n
+		}
+	}
+	return nil
+}
+
+// Upload pushes the chunk from the node to its closest node in the cluster.
+func (n *Node) Upload(ctx context.Context, ch swarm.Chunk) (*pushsync.Receipt, error) {
+	return n.PushSync.PushChunkToClosest(ctx, ch)
+}
+
+// Download retrieves the chunk from the cluster through the node.
+func (n *Node) Download(ctx context.Context, addr swarm.Address) (swarm.Chunk, error) {
+	return n.Retrieval.RetrieveChunk(ctx, addr, swarm.ZeroAddress)
+}
+
+// identity derives the deterministic key and overlay address of the i-th node.
+func identity(seed int64, i int) (crypto.Signer, swarm.Address, error) {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b, uint64(seed))
+	binary.BigEndian.PutUint64(b[8:], uint64(i))
+	h, err := crypto.LegacyKeccak256(b)
+	if err != nil {
+		return nil, swarm.ZeroAddress, err
+	}
+	key, err := crypto.DecodeSecp256k1PrivateKey(h)
+	if err != nil {
+		return nil, swarm.ZeroAddress, err
+	}
+	overlay, err := crypto.NewOverlayAddress(key.PublicKey, networkID, make([]byte, 32))
+	if err != nil {
+		return nil, swarm.ZeroAddress, err
+	}
+	return crypto.NewDefaultSigner(key), overlay, nil
+}
+
+// merge combines the stream specifications of all the protocols into a
+// single specification, as the in-process transport routes the streams
+// only by the peer and the stream name.
+func merge(specs ...p2p.ProtocolSpec) p2p.ProtocolSpec {
+	var merged p2p.ProtocolSpec
+	for _, s := range specs {
+		merged.StreamSpecs = append(merged.StreamSpecs, s.StreamSpecs...)
+	}
+	return merged
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simulator_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/simulator"
+	"github.com/ethersphere/bee/pkg/storage"
+	testingc "github.com/ethersphere/bee/pkg/storage/testing"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func newCluster(t *testing.T, nodes int) *simulator.Cluster {
+	t.Helper()
+
+	c, err := simulator.New(simulator.Options{Nodes: nodes, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return c
+}
+
+func TestDeterministicIdentities(t *testing.T) {
+	t.Parallel()
+
+	c1 := newCluster(t, 4)
+	c2 := newCluster(t, 4)
+
+	for i := range c1.Nodes {
+		if !c1.Nodes[i].Overlay.Equal(c2.Nodes[i].Overlay) {
+			t.Fatalf("node %d: overlays differ", i)
+		}
+	}
+}
+
+func TestPushRetrieve(t *testing.T) {
+	t.Parallel()
+
+	c := newCluster(t, 8)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ch := testingc.GenerateTestRandomChunk()
+	closest := c.Closest(ch.Address())
+
+	uploader := c.Nodes[0]
+	if uploader == closest {
+		uploader = c.Nodes[1]
+	}
+
+	receipt, err := uploader.Upload(ctx, ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !receipt.Address.Equal(ch.Address()) {
+		t.Fatalf("got receipt for %s, want %s", receipt.Address, ch.Address())
+	}
+
+	has, err := closest.Storer.Has(ctx, ch.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !has {
+		t.Fatal("chunk not stored on the closest node")
+	}
+
+	for _, n := range c.Nodes {
+		if n == closest {
+			continue
+		}
+		got, err := n.Download(ctx, ch.Address())
+		if err != nil {
+			t.Fatalf("node %s: %v", n.Overlay, err)
+		}
+		if !bytes.Equal(got.Data(), ch.Data()) {
+			t.Fatalf("node %s: retrieved data mismatch", n.Overlay)
+		}
+	}
+}
+
+func TestPullSync(t *testing.T) {
+	t.Parallel()
+
+	c := newCluster(t, 2)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	source, target := c.Nodes[0], c.Nodes[1]
+
+	ch := testingc.GenerateTestRandomChunk()
+	if _, err := source.Storer.Put(ctx, storage.ModePutSync, ch); err != nil {
+		t.Fatal(err)
+	}
+	bin := swarm.Proximity(source.Overlay.Bytes(), ch.Address().Bytes())
+
+	cursors, err := target.PullSync.GetCursors(ctx, source.Overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursors[bin] == 0 {
+		t.Fatalf("got cursor 0 for bin %d", bin)
+	}
+
+	if _, err := target.PullSync.SyncInterval(ctx, source.Overlay, bin, 0, cursors[bin]); err != nil {
+		t.Fatal(err)
+	}
+
+	has, err := target.Storer.Has(ctx, ch.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !has {
+		t.Fatal("chunk not synced")
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simulator
+
+import (
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+	"github.com/ethersphere/bee/pkg/topology/mock"
+)
+
+// fullMesh is a topology in which the node is connected to all other
+// nodes of the cluster. Unlike the topology mock, it routes the chunks
+// to the closest node, including the node itself.
+type fullMesh struct {
+	topology.Driver
+	base  swarm.Address
+	peers []swarm.Address
+}
+
+func newFullMesh(base swarm.Address, peers []swarm.Address) *fullMesh {
+	return &fullMesh{
+		Driver: mock.NewTopologyDriver(mock.WithPeers(peers...)),
+		base:   base,
+		peers:  peers,
+	}
+}
+
+// ClosestPeer implements the topology.ClosestPeerer interface.
+func (m *fullMesh) ClosestPeer(addr swarm.Address, includeSelf bool, _ topology.Filter, skipPeers ...swarm.Address) (swarm.Address, error) {
+	closest := swarm.ZeroAddress
+	if includeSelf {
+		closest = m.base
+	}
+	for _, p := range m.peers {
+		if swarm.ContainsAddress(skipPeers, p) {
+			continue
+		}
+		if closest.IsZero() {
+			closest = p
+			continue
+		}
+		if closer, _ := p.Closer(addr, closest); closer {
+			closest = p
+		}
+	}
+
+	switch {
+	case closest.IsZero():
+		return swarm.ZeroAddress, topology.ErrNotFound
+	case closest.Equal(m.base):
+		return swarm.ZeroAddress, topology.ErrWantSelf
+	}
+	return closest, nil
+}
+
+// EachConnectedPeer implements the topology.PeerIterator interface.
+func (m *fullMesh) EachConnectedPeer(f topology.EachPeerFunc, _ topology.Filter) error {
+	for _, p := range m.peers {
+		stop, _, err := f(p, swarm.Proximity(m.base.Bytes(), p.Bytes()))
+		if err != nil {
+			return err
+		}
+		if stop {
+			return nil
+		}
+	}
+	return nil
+}
+
+// EachConnectedPeerRev implements the topology.PeerIterator interface.
+func (m *fullMesh) EachConnectedPeerRev(f topology.EachPeerFunc, _ topology.Filter) error {
+	for i := len(m.peers) - 1; i >= 0; i-- {
+		stop, _, err := f(m.peers[i], swarm.Proximity(m.base.Bytes(), m.peers[i].Bytes()))
+		if err != nil {
+			return err
+		}
+		if stop {
+			return nil
+		}
+	}
+	return nil
+}
+
+// radius reports a fixed storage radius of the node.
+type radius struct {
+	base   swarm.Address
+	radius uint8
+}
+
+func (r radius) StorageRadius() uint8 { return r.radius }
+
+func (r radius) IsWithinStorageRadius(addr swarm.Address) bool {
+	return swarm.Proximity(r.base.Bytes(), addr.Bytes()) >= r.radius
+}
@@ -59,6 +59,21 @@ func (s *SOC) address() (swarm.Address, error) {
 	return CreateAddress(s.id, s.owner)
 }
 
+// ID returns the SOC identifier.
+func (s *SOC) ID() ID {
+	return s.id
+}
+
+// OwnerAddress returns the ethereum address of the SOC owner.
+func (s *SOC) OwnerAddress() []byte {
+	return s.owner
+}
+
+// Signature returns the SOC signature.
+func (s *SOC) Signature() []byte {
+	return s.signature
+}
+
 // WrappedChunk returns the chunk wrapped by the SOC.
 func (s *SOC) WrappedChunk() swarm.Chunk {
 	return s.chunk
@@ -0,0 +1,48 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spam
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	Recorded       prometheus.Counter     // number of the chunks entering the reserve
+	Flagged        *prometheus.CounterVec // number of the flagged batches by the reason
+	FlaggedBatches prometheus.Gauge       // number of the currently flagged batches
+}
+
+func newMetrics() metrics {
+	subsystem := "spam"
+
+	return metrics{
+		Recorded: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "recorded",
+			Help:      "Total chunks entering the reserve.",
+		}),
+		Flagged: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "flagged",
+				Help:      "Total flagged batches by the reason, which is one of rate or skew.",
+			},
+			[]string{"reason"},
+		),
+		FlaggedBatches: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "flagged_batches",
+			Help:      "Number of the currently flagged batches.",
+		}),
+	}
+}
+
+func (d *Detector) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(d.metrics)
+}
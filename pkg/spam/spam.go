@@ -0,0 +1,277 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package spam detects the postage batches whose chunks enter the reserve
+// anomalously. The batches are flagged if too many of their chunks enter the
+// reserve within a window, or if their chunks are skewed towards a single
+// collision bucket, which is not the case for the chunks of the honest
+// uploads, as their addresses and therefore their buckets are uniformly
+// distributed. The chunks of the reserve share the prefix of the
+// neighbourhood of the node, so the skew is measured against the uniform
+// distribution over the buckets of the neighbourhood.
+package spam
+
+import (
+	"encoding/binary"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWindow is the default window of the chunk ingress.
+	DefaultWindow = 10 * time.Minute
+	// DefaultMaxRate is the default rate of the chunk ingress of a batch in
+	// chunks per second above which the batch is flagged.
+	DefaultMaxRate = 50
+	// DefaultMaxSkew is the default share of the chunks of a batch in its
+	// fullest bucket above which the batch is flagged.
+	DefaultMaxSkew = 0.5
+	// DefaultMinChunks is the default number of the chunks of a batch in a
+	// window which are needed to evaluate the bucket skew.
+	DefaultMinChunks = 256
+	// DefaultFlagTTL is the default time a batch stays flagged after its
+	// last anomalous window.
+	DefaultFlagTTL = 24 * time.Hour
+
+	// bucketDepth is the depth of the collision buckets of the batches,
+	// as set by the postage contract.
+	bucketDepth = 16
+)
+
+// Reasons of the flagged batches.
+const (
+	ReasonRate = "rate"
+	ReasonSkew = "skew"
+)
+
+// Options are the options of the Detector.
+type Options struct {
+	// Window is the window of the chunk ingress.
+	Window time.Duration
+	// MaxRate is the rate of the chunk ingress of a batch in chunks
+	// per second above which the batch is flagged.
+	MaxRate float64
+	// MaxSkew is the skew of the chunks of a batch towards its fullest
+	// bucket above which the batch is flagged.
+	MaxSkew float64
+	// MinChunks is the number of the chunks of a batch in a window
+	// which are needed to evaluate the bucket skew.
+	MinChunks int
+	// FlagTTL is the time a batch stays flagged after its last
+	// anomalous window.
+	FlagTTL time.Duration
+	// StorageRadius returns the storage radius of the node, which is the
+	// length of the prefix of the neighbourhood shared by the chunks of
+	// the reserve. The radius is zero if it is not set.
+	StorageRadius func() uint8
+}
+
+// Report is the report of a flagged batch.
+type Report struct {
+	BatchID  []byte
+	Chunks   uint64   // number of the chunks in the anomalous window
+	Rate     float64  // rate of the chunk ingress in chunks per second
+	Skew     float64  // share of the chunks in the fullest bucket above the uniform share, normalised to 1
+	Reasons  []string // reasons of the flagging
+	Flagged  time.Time
+	LastSeen time.Time
+}
+
+// ingress is the chunk ingress of a batch in the current window.
+type ingress struct {
+	chunks  uint64
+	buckets map[uint32]uint64
+	max     uint64 // number of the chunks in the fullest bucket
+}
+
+// Detector flags the batches with the anomalous chunk ingress.
+type Detector struct {
+	window    time.Duration
+	maxRate   float64
+	maxSkew   float64
+	minChunks int
+	flagTTL   time.Duration
+	radius    func() uint8
+	now       func() time.Time
+
+	mu      sync.Mutex
+	start   time.Time           // start of the current window
+	ingress map[string]*ingress // chunk ingress of the batches in the current window
+	flagged map[string]*Report
+
+	metrics metrics
+}
+
+// New returns a new Detector with the options.
+func New(o Options) *Detector {
+	if o.Window <= 0 {
+		o.Window = DefaultWindow
+	}
+	if o.MaxRate <= 0 {
+		o.MaxRate = DefaultMaxRate
+	}
+	if o.MaxSkew <= 0 {
+		o.MaxSkew = DefaultMaxSkew
+	}
+	if o.MinChunks <= 0 {
+		o.MinChunks = DefaultMinChunks
+	}
+	if o.FlagTTL <= 0 {
+		o.FlagTTL = DefaultFlagTTL
+	}
+	if o.StorageRadius == nil {
+		o.StorageRadius = func() uint8 { return 0 }
+	}
+	return &Detector{
+		window:    o.Window,
+		maxRate:   o.MaxRate,
+		maxSkew:   o.MaxSkew,
+		minChunks: o.MinChunks,
+		flagTTL:   o.FlagTTL,
+		radius:    o.StorageRadius,
+		now:       time.Now,
+		start:     time.Now(),
+		ingress:   make(map[string]*ingress),
+		flagged:   make(map[string]*Report),
+		metrics:   newMetrics(),
+	}
+}
+
+// Record records the chunk of the batch with the stamp index
+// entering the reserve.
+func (d *Detector) Record(batchID, index []byte) {
+	d.metrics.Recorded.Inc()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	t := d.now()
+	d.roll(t)
+
+	in, ok := d.ingress[string(batchID)]
+	if !ok {
+		in = &ingress{buckets: make(map[uint32]uint64)}
+		d.ingress[string(batchID)] = in
+	}
+	in.chunks++
+	if len(index) >= 4 {
+		bucket := binary.BigEndian.Uint32(index[:4])
+		in.buckets[bucket]++
+		if in.buckets[bucket] > in.max {
+			in.max = in.buckets[bucket]
+		}
+	}
+
+	// the rate limit is exceeded as soon as there are more chunks
+	// than allowed in the whole window
+	var reasons []string
+	if float64(in.chunks) > d.maxRate*d.window.Seconds() {
+		reasons = append(reasons, ReasonRate)
+	}
+	skew := d.skew(in)
+	if in.chunks >= uint64(d.minChunks) && skew > d.maxSkew {
+		reasons = append(reasons, ReasonSkew)
+	}
+	if len(reasons) == 0 {
+		return
+	}
+
+	r, ok := d.flagged[string(batchID)]
+	if !ok {
+		r = &Report{
+			BatchID: append([]byte(nil), batchID...),
+			Flagged: t,
+		}
+		d.flagged[string(batchID)] = r
+		for _, reason := range reasons {
+			d.metrics.Flagged.WithLabelValues(reason).Inc()
+		}
+		d.metrics.FlaggedBatches.Set(float64(len(d.flagged)))
+	}
+	r.Chunks = in.chunks
+	r.Rate = float64(in.chunks) / d.window.Seconds()
+	r.Skew = skew
+	r.Reasons = reasons
+	r.LastSeen = t
+}
+
+// skew returns the share of the chunks of the ingress in its fullest bucket
+// above the share of the uniform distribution over the buckets of the
+// neighbourhood, normalised to 1, so that it is zero for the uniformly
+// distributed chunks and one for the chunks in a single bucket. It is
+// zero if the neighbourhood is not wider than a single bucket.
+func (d *Detector) skew(in *ingress) float64 {
+	radius := d.radius()
+	if in.chunks == 0 || radius >= bucketDepth {
+		return 0
+	}
+	uniform := 1 / float64(uint64(1)<<(bucketDepth-radius))
+	share := float64(in.max) / float64(in.chunks)
+	if share <= uniform {
+		return 0
+	}
+	return (share - uniform) / (1 - uniform)
+}
+
+// Flagged returns the reports of the flagged batches ordered
+// by the time they were flagged.
+func (d *Detector) Flagged() []Report {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.roll(d.now())
+
+	reports := make([]Report, 0, len(d.flagged))
+	for _, r := range d.flagged {
+		reports = append(reports, *r)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Flagged.Before(reports[j].Flagged)
+	})
+	return reports
+}
+
+// FlaggedBatches returns the IDs of the flagged batches.
+func (d *Detector) FlaggedBatches() [][]byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.roll(d.now())
+
+	ids := make([][]byte, 0, len(d.flagged))
+	for _, r := range d.flagged {
+		ids = append(ids, r.BatchID)
+	}
+	return ids
+}
+
+// IsFlagged reports whether the batch is flagged.
+func (d *Detector) IsFlagged(batchID []byte) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.roll(d.now())
+
+	_, ok := d.flagged[string(batchID)]
+	return ok
+}
+
+// roll starts a new window if the current one is over and unflags the
+// batches which were not anomalous for the flag TTL.
+// It must be called with the lock held.
+func (d *Detector) roll(t time.Time) {
+	if t.Sub(d.start) < d.window {
+		return
+	}
+	d.start = t
+	d.ingress = make(map[string]*ingress)
+
+	for id, r := range d.flagged {
+		if t.Sub(r.LastSeen) > d.flagTTL {
+			delete(d.flagged, id)
+		}
+	}
+	d.metrics.FlaggedBatches.Set(float64(len(d.flagged)))
+}
@@ -0,0 +1,179 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spam_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+	"github.com/ethersphere/bee/pkg/spam"
+)
+
+func index(bucket, i uint32) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b, bucket)
+	binary.BigEndian.PutUint32(b[4:], i)
+	return b
+}
+
+func TestDetector(t *testing.T) {
+	t.Parallel()
+
+	t.Run("honest batch", func(t *testing.T) {
+		t.Parallel()
+
+		d := spam.New(spam.Options{Window: time.Minute, MaxRate: 10, MinChunks: 100})
+		id := postagetesting.MustNewID()
+		for i := uint32(0); i < 600; i++ {
+			d.Record(id, index(i%256, i/256))
+		}
+		if d.IsFlagged(id) {
+			t.Fatal("honest batch flagged")
+		}
+		if got := d.Flagged(); len(got) != 0 {
+			t.Fatalf("got %d flagged batches, want none", len(got))
+		}
+	})
+
+	t.Run("rate", func(t *testing.T) {
+		t.Parallel()
+
+		d := spam.New(spam.Options{Window: time.Minute, MaxRate: 10, MinChunks: 100})
+		id := postagetesting.MustNewID()
+		for i := uint32(0); i < 601; i++ {
+			d.Record(id, index(i%256, i/256))
+		}
+		got := d.Flagged()
+		if len(got) != 1 {
+			t.Fatalf("got %d flagged batches, want 1", len(got))
+		}
+		if !bytes.Equal(got[0].BatchID, id) {
+			t.Fatalf("got flagged batch %x, want %x", got[0].BatchID, id)
+		}
+		if len(got[0].Reasons) != 1 || got[0].Reasons[0] != spam.ReasonRate {
+			t.Fatalf("got reasons %v, want %v", got[0].Reasons, []string{spam.ReasonRate})
+		}
+		if got[0].Chunks != 601 {
+			t.Fatalf("got %d chunks, want 601", got[0].Chunks)
+		}
+	})
+
+	t.Run("skew", func(t *testing.T) {
+		t.Parallel()
+
+		d := spam.New(spam.Options{Window: time.Minute, MaxRate: 10, MaxSkew: 0.5, MinChunks: 100})
+		id := postagetesting.MustNewID()
+		for i := uint32(0); i < 99; i++ {
+			d.Record(id, index(7, i))
+		}
+		// too few chunks to evaluate the skew
+		if d.IsFlagged(id) {
+			t.Fatal("batch flagged")
+		}
+		d.Record(id, index(8, 0))
+		if !d.IsFlagged(id) {
+			t.Fatal("batch not flagged")
+		}
+		got := d.Flagged()
+		if len(got[0].Reasons) != 1 || got[0].Reasons[0] != spam.ReasonSkew {
+			t.Fatalf("got reasons %v, want %v", got[0].Reasons, []string{spam.ReasonSkew})
+		}
+		// the share above the uniform share of the 2^16 buckets
+		if want := (0.99 - 1.0/65536) / (1 - 1.0/65536); math.Abs(got[0].Skew-want) > 1e-9 {
+			t.Fatalf("got skew %v, want %v", got[0].Skew, want)
+		}
+	})
+
+	t.Run("neighbourhood", func(t *testing.T) {
+		t.Parallel()
+
+		// the chunks of the reserve share the first 15 bits of the
+		// neighbourhood, so they fall in two buckets
+		const prefix = 0x1234
+		d := spam.New(spam.Options{
+			Window:        time.Minute,
+			MaxRate:       10,
+			MaxSkew:       0.5,
+			MinChunks:     100,
+			StorageRadius: func() uint8 { return 15 },
+		})
+		honest := postagetesting.MustNewID()
+		for i := uint32(0); i < 200; i++ {
+			d.Record(honest, index(prefix|i%2, i/2))
+		}
+		if d.IsFlagged(honest) {
+			t.Fatal("honest batch flagged")
+		}
+
+		skewed := postagetesting.MustNewID()
+		for i := uint32(0); i < 100; i++ {
+			bucket := uint32(prefix)
+			if i%10 == 0 {
+				bucket |= 1
+			}
+			d.Record(skewed, index(bucket, i))
+		}
+		got := d.Flagged()
+		if len(got) != 1 || !bytes.Equal(got[0].BatchID, skewed) {
+			t.Fatalf("got flagged batches %v, want %x", got, skewed)
+		}
+		if want := 0.8; math.Abs(got[0].Skew-want) > 1e-9 {
+			t.Fatalf("got skew %v, want %v", got[0].Skew, want)
+		}
+
+		// the skew is not measured in a neighbourhood of a single bucket
+		d = spam.New(spam.Options{
+			Window:        time.Minute,
+			MaxRate:       10,
+			MinChunks:     100,
+			StorageRadius: func() uint8 { return 16 },
+		})
+		for i := uint32(0); i < 200; i++ {
+			d.Record(honest, index(0x1234, i))
+		}
+		if d.IsFlagged(honest) {
+			t.Fatal("honest batch flagged")
+		}
+	})
+
+	t.Run("window and flag ttl", func(t *testing.T) {
+		t.Parallel()
+
+		d := spam.New(spam.Options{Window: time.Minute, MaxRate: 1, MinChunks: 1000, FlagTTL: time.Hour})
+		ts := time.Now()
+		d.SetNow(func() time.Time { return ts })
+
+		id := postagetesting.MustNewID()
+		for i := uint32(0); i < 60; i++ {
+			d.Record(id, index(i, 0))
+		}
+		ts = ts.Add(time.Minute)
+		// the chunks of the previous window are not counted
+		for i := uint32(0); i < 60; i++ {
+			d.Record(id, index(i, 0))
+		}
+		if d.IsFlagged(id) {
+			t.Fatal("batch flagged")
+		}
+
+		d.Record(id, index(0, 1))
+		if ids := d.FlaggedBatches(); len(ids) != 1 || !bytes.Equal(ids[0], id) {
+			t.Fatalf("got flagged batches %x, want %x", ids, id)
+		}
+
+		ts = ts.Add(30 * time.Minute)
+		if !d.IsFlagged(id) {
+			t.Fatal("batch unflagged before the flag ttl")
+		}
+		ts = ts.Add(time.Hour)
+		if ids := d.FlaggedBatches(); len(ids) != 0 {
+			t.Fatalf("got flagged batches %x, want none", ids)
+		}
+	})
+}
@@ -0,0 +1,183 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package redis provides the state store backed by a Redis server, which
+// can be shared by multiple nodes, for example by the API frontends of a
+// horizontally scaled gateway.
+package redis
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/util/resp"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const (
+	// keyPrefix is the namespace of the keys of the state
+	// store, so that the server can be used for other data.
+	keyPrefix = "bee:state:"
+
+	schemaNameKey     = "schema_name"
+	schemaNameCurrent = "redis-v1"
+
+	poolSize  = 16
+	opTimeout = 5 * time.Second
+)
+
+var _ storage.StateStorer = (*Store)(nil)
+
+// Store uses a Redis server to store values.
+type Store struct {
+	client *resp.Client
+}
+
+// NewStateStore returns the state store backed by the Redis server
+// at the redis://[:password@]host:port[/db] URL.
+func NewStateStore(target string) (*Store, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("parse target: %w", err)
+	}
+	if u.Scheme != "redis" {
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	client, err := resp.New(u, poolSize, opTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{client: client}
+
+	var name string
+	switch err := s.Get(schemaNameKey, &name); {
+	case errors.Is(err, storage.ErrNotFound):
+		if err := s.Put(schemaNameKey, schemaNameCurrent); err != nil {
+			_ = s.Close()
+			return nil, fmt.Errorf("put schema name: %w", err)
+		}
+	case err != nil:
+		_ = s.Close()
+		return nil, fmt.Errorf("get schema name: %w", err)
+	case name != schemaNameCurrent:
+		_ = s.Close()
+		return nil, fmt.Errorf("unsupported schema %q", name)
+	}
+
+	return s, nil
+}
+
+// Get retrieves a value of the requested key. If no results are found,
+// storage.ErrNotFound will be returned.
+func (s *Store) Get(key string, i interface{}) error {
+	data, err := s.client.Get(context.Background(), keyPrefix+key)
+	if err != nil {
+		if errors.Is(err, resp.ErrNil) {
+			return storage.ErrNotFound
+		}
+		return err
+	}
+
+	if unmarshaler, ok := i.(encoding.BinaryUnmarshaler); ok {
+		return unmarshaler.UnmarshalBinary(data)
+	}
+
+	return json.Unmarshal(data, i)
+}
+
+// Put stores a value for an arbitrary key. BinaryMarshaler
+// interface method will be called on the provided value
+// with fallback to JSON serialization.
+func (s *Store) Put(key string, i interface{}) (err error) {
+	var bytes []byte
+	if marshaler, ok := i.(encoding.BinaryMarshaler); ok {
+		if bytes, err = marshaler.MarshalBinary(); err != nil {
+			return err
+		}
+	} else if bytes, err = json.Marshal(i); err != nil {
+		return err
+	}
+
+	return s.client.Set(context.Background(), keyPrefix+key, bytes, 0)
+}
+
+// Delete removes entries stored under a specific key.
+func (s *Store) Delete(key string) (err error) {
+	return s.client.Del(context.Background(), keyPrefix+key)
+}
+
+// Iterate entries that match the supplied prefix in the order of the keys.
+// The keys are collected before the iteration, so the entries which are
+// added during the iteration are not included.
+func (s *Store) Iterate(prefix string, iterFunc storage.StateIterFunc) (err error) {
+	ctx := context.Background()
+
+	seen := make(map[string]struct{})
+	var keys []string
+	err = s.client.Scan(ctx, escapePattern(keyPrefix+prefix)+"*", func(batch []string) error {
+		for _, k := range batch {
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				keys = append(keys, k)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v, err := s.client.Get(ctx, k)
+		if err != nil {
+			if errors.Is(err, resp.ErrNil) {
+				// deleted after the scan
+				continue
+			}
+			return err
+		}
+		stop, err := iterFunc([]byte(strings.TrimPrefix(k, keyPrefix)), v)
+		if err != nil {
+			return err
+		}
+		if stop {
+			break
+		}
+	}
+	return nil
+}
+
+// DB implements StateStorer.DB method. The store
+// is not backed by leveldb, so it returns nil.
+func (s *Store) DB() *leveldb.DB {
+	return nil
+}
+
+// Close releases the resources used by the store.
+func (s *Store) Close() error {
+	return s.client.Close()
+}
+
+// escapePattern escapes the special characters of the glob-style pattern.
+func escapePattern(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch c {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package redis_test
+
+import (
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/statestore/redis"
+	"github.com/ethersphere/bee/pkg/statestore/test"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/util/resp/mock"
+)
+
+func TestRedisStateStore(t *testing.T) {
+	t.Parallel()
+
+	newStore := func(t *testing.T, server *mock.Server) storage.StateStorer {
+		t.Helper()
+
+		store, err := redis.NewStateStore("redis://:secret@" + server.Addr() + "/1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			if err := store.Close(); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		return store
+	}
+
+	test.Run(t, func(t *testing.T) storage.StateStorer {
+		t.Helper()
+
+		return newStore(t, newServer(t))
+	})
+
+	// the state persists on the server across the sessions
+	server := newServer(t)
+	test.RunPersist(t, func(t *testing.T, _ string) storage.StateStorer {
+		t.Helper()
+
+		return newStore(t, server)
+	})
+}
+
+func TestRedisStateStoreIteratePrefix(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t)
+	store, err := redis.NewStateStore("redis://:secret@" + server.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	// the special characters of the glob patterns match only themselves
+	for _, k := range []string{"a*b1", "a*b2", "axb1", "a*c1"} {
+		if err := store.Put(k, k); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	err = store.Iterate("a*b", func(key, _ []byte) (bool, error) {
+		got = append(got, string(key))
+		return false, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "a*b1" || got[1] != "a*b2" {
+		t.Fatalf("got keys %v, want [a*b1 a*b2]", got)
+	}
+}
+
+func TestRedisStateStoreWrongPassword(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t)
+	if _, err := redis.NewStateStore("redis://:wrong@" + server.Addr()); err == nil {
+		t.Fatal("expected authentication error")
+	}
+}
+
+func newServer(t *testing.T) *mock.Server {
+	t.Helper()
+
+	server, err := mock.NewServer(mock.WithPassword("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = server.Close() })
+
+	return server
+}
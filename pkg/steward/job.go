@@ -0,0 +1,34 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package steward
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/jobs"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// JobKind is the kind of the jobs which re-upload the content in the background.
+const JobKind = "reupload"
+
+// JobParams are the parameters of the re-upload job.
+type JobParams struct {
+	Reference swarm.Address `json:"reference"`
+}
+
+// NewJob returns the runner of the re-upload jobs, which push all chunks of
+// the content of the reference to the network. The resumed job re-uploads
+// the content from the beginning.
+func NewJob(s Interface) jobs.Runner {
+	return func(ctx context.Context, t *jobs.Task) error {
+		var p JobParams
+		if err := t.Params(&p); err != nil {
+			return fmt.Errorf("params: %w", err)
+		}
+		return s.Reupload(ctx, p.Reference)
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package systemd
+
+import (
+	"io"
+
+	"github.com/coreos/go-systemd/v22/journal"
+)
+
+func NewJournalWriterWithSend(send func(message string, priority journal.Priority, vars map[string]string) error) io.Writer {
+	return &journalWriter{send: send}
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package systemd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/journal"
+)
+
+// ErrJournalNotAvailable is returned when the journal socket can not be reached.
+var ErrJournalNotAvailable = errors.New("systemd journal is not available")
+
+const syslogIdentifier = "bee"
+
+type sendFunc func(message string, priority journal.Priority, vars map[string]string) error
+
+// journalWriter sends the log lines in the JSON format to the journal,
+// with every logged key and value as a separate journal field.
+type journalWriter struct {
+	send sendFunc
+}
+
+// NewJournalWriter returns a log sink which sends the log lines, formatted
+// as JSON, to the journal as structured entries.
+func NewJournalWriter() (io.Writer, error) {
+	if !journal.Enabled() {
+		return nil, ErrJournalNotAvailable
+	}
+	return &journalWriter{send: journal.Send}, nil
+}
+
+func (w *journalWriter) Write(p []byte) (int, error) {
+	fields := make(map[string]interface{})
+	d := json.NewDecoder(bytes.NewReader(p))
+	d.UseNumber()
+	if err := d.Decode(&fields); err != nil {
+		// not a structured log line
+		msg := strings.TrimSuffix(string(p), "\n")
+		return len(p), w.send(msg, journal.PriInfo, map[string]string{"SYSLOG_IDENTIFIER": syslogIdentifier})
+	}
+
+	msg, _ := fields["msg"].(string)
+	level, _ := fields["level"].(string)
+	vars := map[string]string{"SYSLOG_IDENTIFIER": syslogIdentifier}
+	for k, v := range fields {
+		switch k {
+		case "msg", "level", "time":
+			// the journal has its own fields for these
+			continue
+		}
+		name := journalFieldName(k)
+		if name == "" {
+			continue
+		}
+		if s, ok := v.(string); ok {
+			vars[name] = s
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+		vars[name] = string(b)
+	}
+	return len(p), w.send(msg, journalPriority(level), vars)
+}
+
+// journalFieldName converts the log key to a valid journal field name,
+// which consists of upper case letters, digits and underscores, and does
+// not start with an underscore, which is reserved for trusted fields.
+func journalFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, key)
+	name = strings.TrimLeft(name, "_")
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return ""
+	}
+	switch name {
+	case "MESSAGE", "PRIORITY", "SYSLOG_IDENTIFIER":
+		return "BEE_" + name
+	}
+	return name
+}
+
+func journalPriority(level string) journal.Priority {
+	switch level {
+	case "error":
+		return journal.PriErr
+	case "warning":
+		return journal.PriWarning
+	case "info":
+		return journal.PriInfo
+	}
+	return journal.PriDebug
+}
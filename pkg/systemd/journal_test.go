@@ -0,0 +1,80 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package systemd_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/coreos/go-systemd/v22/journal"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/systemd"
+)
+
+type journalEntry struct {
+	message  string
+	priority journal.Priority
+	vars     map[string]string
+}
+
+func TestJournalWriter(t *testing.T) {
+	t.Parallel()
+
+	var entries []journalEntry
+	w := systemd.NewJournalWriterWithSend(func(message string, priority journal.Priority, vars map[string]string) error {
+		entries = append(entries, journalEntry{message, priority, vars})
+		return nil
+	})
+
+	logger := log.NewLogger("test", log.WithSink(w), log.WithJSONOutput(), log.WithTimestamp(), log.WithVerbosity(log.VerbosityDebug))
+	logger.Info("using overlay address", "address", "3ebb", "peers", 12, "message", "m", "_trusted", true)
+	logger.Error(nil, "failed")
+	logger.Debug("dial", "peer-address", "/ip4/127.0.0.1")
+	if _, err := w.Write([]byte("plain line\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []journalEntry{
+		{
+			message:  "using overlay address",
+			priority: journal.PriInfo,
+			vars: map[string]string{
+				"SYSLOG_IDENTIFIER": "bee",
+				"LOGGER":            "test",
+				"ADDRESS":           "3ebb",
+				"PEERS":             "12",
+				"BEE_MESSAGE":       "m",
+				"TRUSTED":           "true",
+			},
+		},
+		{
+			message:  "failed",
+			priority: journal.PriErr,
+			vars: map[string]string{
+				"SYSLOG_IDENTIFIER": "bee",
+				"LOGGER":            "test",
+			},
+		},
+		{
+			message:  "dial",
+			priority: journal.PriDebug,
+			vars: map[string]string{
+				"SYSLOG_IDENTIFIER": "bee",
+				"LOGGER":            "test",
+				"PEER_ADDRESS":      "/ip4/127.0.0.1",
+			},
+		},
+		{
+			message:  "plain line",
+			priority: journal.PriInfo,
+			vars: map[string]string{
+				"SYSLOG_IDENTIFIER": "bee",
+			},
+		},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("got entries\n%+v\nwant\n%+v", entries, want)
+	}
+}
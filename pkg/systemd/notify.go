@@ -0,0 +1,88 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package systemd provides the integration with the systemd service manager:
+// the service state notifications, the watchdog and the structured logging
+// to the journal.
+package systemd
+
+import (
+	"sync"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/daemon"
+	"github.com/ethersphere/bee/pkg/log"
+)
+
+// Notifier sends the service state notifications to systemd. The
+// notifications are ignored if the node is not started by systemd
+// with the notify service type.
+type Notifier struct {
+	logger log.Logger
+	quit   chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewNotifier creates a new Notifier and starts sending the watchdog
+// keep-alive pings if the watchdog is enabled for the service.
+func NewNotifier(logger log.Logger) *Notifier {
+	n := &Notifier{
+		logger: logger,
+		quit:   make(chan struct{}),
+	}
+
+	interval, err := daemon.SdWatchdogEnabled(false)
+	if err != nil {
+		logger.Warning("systemd watchdog", "error", err)
+	}
+	if interval > 0 {
+		n.wg.Add(1)
+		go n.watchdog(interval / 2)
+	}
+	return n
+}
+
+func (n *Notifier) watchdog(interval time.Duration) {
+	defer n.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		n.notify(daemon.SdNotifyWatchdog)
+		select {
+		case <-ticker.C:
+		case <-n.quit:
+			return
+		}
+	}
+}
+
+// Status sends the free-form status of the node.
+func (n *Notifier) Status(status string) {
+	n.notify("STATUS=" + status)
+}
+
+// Ready notifies that the node is started.
+func (n *Notifier) Ready() {
+	n.notify(daemon.SdNotifyReady + "\nSTATUS=running")
+}
+
+// Stopping notifies that the node is shutting down.
+func (n *Notifier) Stopping() {
+	n.notify(daemon.SdNotifyStopping + "\nSTATUS=shutting down")
+}
+
+func (n *Notifier) notify(state string) {
+	if _, err := daemon.SdNotify(false, state); err != nil {
+		n.logger.Debug("systemd notify failed", "state", state, "error", err)
+	}
+}
+
+// Close stops the watchdog keep-alive pings.
+func (n *Notifier) Close() error {
+	n.once.Do(func() { close(n.quit) })
+	n.wg.Wait()
+	return nil
+}
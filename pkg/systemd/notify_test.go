@@ -0,0 +1,86 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+
+package systemd_test
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/systemd"
+)
+
+func TestNotifier(t *testing.T) {
+	// the environment is modified, so the test can not run in parallel
+
+	// the unix socket path length is limited
+	dir, err := os.MkdirTemp("", "sd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "notify")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	t.Setenv("NOTIFY_SOCKET", path)
+	t.Setenv("WATCHDOG_USEC", "20000")
+
+	read := func() string {
+		t.Helper()
+
+		b := make([]byte, 1024)
+		if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		n, err := conn.Read(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b[:n])
+	}
+
+	n := systemd.NewNotifier(log.Noop)
+
+	// the watchdog pings are sent every half of the watchdog interval
+	for i := 0; i < 2; i++ {
+		if got := read(); got != "WATCHDOG=1" {
+			t.Fatalf("got %q, want watchdog ping", got)
+		}
+	}
+	if err := n.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// drain the pings sent before the watchdog was stopped
+	_ = conn.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	for {
+		if _, err := conn.Read(make([]byte, 1024)); err != nil {
+			break
+		}
+	}
+
+	n.Status("warming up")
+	if got := read(); got != "STATUS=warming up" {
+		t.Fatalf("got %q", got)
+	}
+	n.Ready()
+	if got := read(); !strings.HasPrefix(got, "READY=1\n") {
+		t.Fatalf("got %q", got)
+	}
+	n.Stopping()
+	if got := read(); !strings.HasPrefix(got, "STOPPING=1\n") {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -32,7 +32,19 @@ import (
 	"github.com/opentracing/opentracing-go"
 )
 
+const (
+	// MaxNameLength is the maximum length of the name of a tag.
+	MaxNameLength = 256
+	// MaxMetadataSize is the maximum size of the metadata of a tag.
+	MaxMetadataSize = 4096
+)
+
 var (
+	// ErrNameTooLong is returned if the name of a tag is longer than MaxNameLength.
+	ErrNameTooLong = fmt.Errorf("tag name longer than %d characters", MaxNameLength)
+	// ErrMetadataTooLarge is returned if the metadata of a tag is larger than MaxMetadataSize.
+	ErrMetadataTooLarge = fmt.Errorf("tag metadata larger than %d bytes", MaxMetadataSize)
+
 	errExists = errors.New("already exists")
 	errNA     = errors.New("not available yet")
 	errNoETA  = errors.New("unable to calculate ETA")
@@ -62,6 +74,8 @@ type Tag struct {
 	Uid       uint32        // a unique identifier for this tag
 	Address   swarm.Address // the associated swarm hash for this tag
 	StartedAt time.Time     // tag started to calculate ETA
+	Name      string        // user defined name of the tag
+	Metadata  []byte        // user defined JSON metadata of the tag
 
 	// end-to-end tag tracing
 	ctx        context.Context     // tracing context
@@ -240,6 +254,17 @@ func (t *Tag) ETA(state State) (time.Time, error) {
 	return t.StartedAt.Add(dur), nil
 }
 
+// update sets the counters and the address of the tag to the other tag.
+func (t *Tag) update(o *Tag) {
+	atomic.StoreInt64(&t.Total, atomic.LoadInt64(&o.Total))
+	atomic.StoreInt64(&t.Split, atomic.LoadInt64(&o.Split))
+	atomic.StoreInt64(&t.Seen, atomic.LoadInt64(&o.Seen))
+	atomic.StoreInt64(&t.Stored, atomic.LoadInt64(&o.Stored))
+	atomic.StoreInt64(&t.Sent, atomic.LoadInt64(&o.Sent))
+	atomic.StoreInt64(&t.Synced, atomic.LoadInt64(&o.Synced))
+	t.Address = o.Address
+}
+
 // MarshalBinary marshals the tag into a byte slice
 func (tag *Tag) MarshalBinary() (data []byte, err error) {
 	buffer := make([]byte, 4)
@@ -260,6 +285,14 @@ func (tag *Tag) MarshalBinary() (data []byte, err error) {
 	buffer = append(buffer, intBuffer[:n]...)
 	buffer = append(buffer, tag.Address.Bytes()...)
 
+	n = binary.PutVarint(intBuffer, int64(len(tag.Name)))
+	buffer = append(buffer, intBuffer[:n]...)
+	buffer = append(buffer, tag.Name...)
+
+	n = binary.PutVarint(intBuffer, int64(len(tag.Metadata)))
+	buffer = append(buffer, intBuffer[:n]...)
+	buffer = append(buffer, tag.Metadata...)
+
 	return buffer, nil
 }
 
@@ -286,6 +319,29 @@ func (tag *Tag) UnmarshalBinary(buffer []byte) error {
 	buffer = buffer[n:]
 	if t > 0 {
 		tag.Address = swarm.NewAddress(buffer[:t])
+		buffer = buffer[t:]
+	}
+
+	// the tags saved by the older versions have no name and metadata
+	if len(buffer) == 0 {
+		return nil
+	}
+
+	t, n = binary.Varint(buffer)
+	buffer = buffer[n:]
+	if t < 0 || int(t) > len(buffer) {
+		return errors.New("invalid tag name length")
+	}
+	tag.Name = string(buffer[:t])
+	buffer = buffer[t:]
+
+	t, n = binary.Varint(buffer)
+	buffer = buffer[n:]
+	if t < 0 || int(t) > len(buffer) {
+		return errors.New("invalid tag metadata length")
+	}
+	if t > 0 {
+		tag.Metadata = append([]byte(nil), buffer[:t]...)
 	}
 
 	return nil
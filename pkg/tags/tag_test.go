@@ -322,3 +322,46 @@ func TestMarshallingNoAddr(t *testing.T) {
 		t.Fatalf("expected tag addresses to be equal length")
 	}
 }
+
+// TestMarshallingNameMetadata tests that the name and the metadata of the tag
+// are marshalled and that the tags saved without them are still unmarshalled
+func TestMarshallingNameMetadata(t *testing.T) {
+	t.Parallel()
+
+	tg := NewTag(context.Background(), 111, 10, nil, nil, log.Noop)
+	tg.Address = swarm.NewAddress([]byte{0, 1, 2, 3, 4, 5, 6})
+	tg.Name = "release"
+	tg.Metadata = []byte(`{"build":42}`)
+
+	b, err := tg.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unmarshalledTag := &Tag{}
+	if err := unmarshalledTag.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if unmarshalledTag.Name != tg.Name {
+		t.Fatalf("got tag name %q, want %q", unmarshalledTag.Name, tg.Name)
+	}
+	if string(unmarshalledTag.Metadata) != string(tg.Metadata) {
+		t.Fatalf("got tag metadata %s, want %s", unmarshalledTag.Metadata, tg.Metadata)
+	}
+	if !unmarshalledTag.Address.Equal(tg.Address) {
+		t.Fatalf("got tag address %v, want %v", unmarshalledTag.Address, tg.Address)
+	}
+
+	// the encoding of the older versions ends with the address
+	legacy := b[:len(b)-len(tg.Name)-len(tg.Metadata)-2]
+	unmarshalledTag = &Tag{}
+	if err := unmarshalledTag.UnmarshalBinary(legacy); err != nil {
+		t.Fatal(err)
+	}
+	if unmarshalledTag.Name != "" || unmarshalledTag.Metadata != nil {
+		t.Fatalf("got tag name %q and metadata %s, want none", unmarshalledTag.Name, unmarshalledTag.Metadata)
+	}
+	if !unmarshalledTag.Address.Equal(tg.Address) {
+		t.Fatalf("got tag address %v, want %v", unmarshalledTag.Address, tg.Address)
+	}
+}
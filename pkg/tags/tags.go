@@ -19,12 +19,14 @@
 package tags
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"math/rand"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -39,6 +41,10 @@ const loggerName = "tags"
 const (
 	maxPage      = 1000 // hard limit of page size
 	tagKeyPrefix = "tags_"
+
+	// sharedFlushInterval is the interval in which the progress
+	// of the tags is saved to the shared state store.
+	sharedFlushInterval = time.Second
 )
 
 var (
@@ -53,6 +59,13 @@ type Tags struct {
 	logger     log.Logger
 	rand       *rand.Rand
 	randM      sync.Mutex
+
+	// shared state store mode
+	shared bool
+	saved  map[uint32][]byte // the last state saved to or loaded from the store
+	savedM sync.Mutex
+	quit   chan struct{}
+	wg     sync.WaitGroup
 }
 
 // NewTags creates a tags object
@@ -66,6 +79,25 @@ func NewTags(stateStore storage.StateStorer, logger log.Logger) *Tags {
 	}
 }
 
+// NewSharedTags creates a tags object backed by the state store which is
+// shared by multiple nodes, so that the API frontends of a gateway can serve
+// the same tags. The tags are saved to the store when they are created and
+// their progress is saved periodically. The tags which were not changed
+// locally since they were saved are refreshed from the store when they are
+// requested. The progress of the uploads of the same tag through different
+// nodes at the same time is not merged, the last saved progress wins.
+func NewSharedTags(stateStore storage.StateStorer, logger log.Logger) *Tags {
+	ts := NewTags(stateStore, logger)
+	ts.shared = true
+	ts.saved = make(map[uint32][]byte)
+	ts.quit = make(chan struct{})
+
+	ts.wg.Add(1)
+	go ts.flushLoop()
+
+	return ts
+}
+
 func (ts *Tags) TagUidFunc() uint32 {
 	ts.randM.Lock()
 	defer ts.randM.Unlock()
@@ -74,6 +106,19 @@ func (ts *Tags) TagUidFunc() uint32 {
 
 // Create creates a new tag, stores it by a not yet in use UID and returns it
 func (ts *Tags) Create(total int64) (*Tag, error) {
+	return ts.CreateNamed(total, "", nil)
+}
+
+// CreateNamed creates a new tag with the user defined name and JSON metadata,
+// which identify the upload of the tag later, stores it by a not yet in use
+// UID and returns it.
+func (ts *Tags) CreateNamed(total int64, name string, metadata []byte) (*Tag, error) {
+	if len(name) > MaxNameLength {
+		return nil, ErrNameTooLong
+	}
+	if len(metadata) > MaxMetadataSize {
+		return nil, ErrMetadataTooLarge
+	}
 
 	exists := true
 
@@ -84,17 +129,62 @@ func (ts *Tags) Create(total int64) (*Tag, error) {
 		if _, loaded := ts.tags.Load(uid); !loaded {
 			exists = false
 		}
+		// the tag may be created by another node
+		if !exists && ts.shared {
+			if _, err := ts.getTagFromStore(uid); err == nil {
+				exists = true
+			}
+		}
 	}
 
 	t := NewTag(context.Background(), uid, total, nil, ts.stateStore, ts.logger)
+	t.Name = name
+	t.Metadata = metadata
 
 	if _, loaded := ts.tags.LoadOrStore(t.Uid, t); loaded {
 		return nil, errExists
 	}
 
+	if ts.shared {
+		if err := ts.save(t); err != nil {
+			ts.tags.Delete(t.Uid)
+			return nil, err
+		}
+	}
+
 	return t, nil
 }
 
+// Import stores the tag of another node by its UID. The progress and the
+// address of the existing tag with the same UID are updated, so that the
+// uploads of a node can be followed on its replica.
+func (ts *Tags) Import(o *Tag) error {
+	if v, ok := ts.tags.Load(o.Uid); ok {
+		v.(*Tag).update(o)
+		return nil
+	}
+
+	t := NewTag(context.Background(), o.Uid, 0, nil, ts.stateStore, ts.logger)
+	t.update(o)
+	t.StartedAt = o.StartedAt
+	t.Name = o.Name
+	t.Metadata = o.Metadata
+
+	if v, loaded := ts.tags.LoadOrStore(t.Uid, t); loaded {
+		v.(*Tag).update(o)
+		return nil
+	}
+
+	if ts.shared {
+		if err := ts.save(t); err != nil {
+			ts.tags.Delete(t.Uid)
+			return err
+		}
+	}
+
+	return nil
+}
+
 // All returns all existing tags in Tags' sync.Map
 // Note that tags are returned in no particular order
 func (ts *Tags) All() (t []*Tag) {
@@ -117,8 +207,14 @@ func (ts *Tags) Get(uid uint32) (*Tag, error) {
 		if err != nil {
 			return nil, ErrNotFound
 		}
-		ts.tags.LoadOrStore(ta.Uid, ta)
-		return ta, nil
+		t, loaded := ts.tags.LoadOrStore(ta.Uid, ta)
+		if ts.shared && !loaded {
+			ts.markSaved(ta)
+		}
+		return t.(*Tag), nil
+	}
+	if ts.shared {
+		ts.refresh(t.(*Tag))
 	}
 	return t.(*Tag), nil
 }
@@ -155,6 +251,12 @@ func (ts *Tags) Delete(k interface{}) {
 	if uid, ok := k.(uint32); ok && uid != 0 {
 		key := tagKey(uid)
 		_ = ts.stateStore.Delete(key)
+
+		if ts.shared {
+			ts.savedM.Lock()
+			delete(ts.saved, uid)
+			ts.savedM.Unlock()
+		}
 	}
 }
 
@@ -200,6 +302,13 @@ func (ts *Tags) ListAll(ctx context.Context, offset, limit int) (t []*Tag, err e
 		limit = maxPage
 	}
 
+	// all tags are in the shared store, which is more
+	// recent than the tags in memory, once they are saved
+	if ts.shared {
+		ts.flush()
+		return ts.listStore(offset, limit, false)
+	}
+
 	// range sync.Map first
 	allTags := ts.All()
 	sort.Slice(allTags, func(i, j int) bool { return allTags[i].Uid < allTags[j].Uid })
@@ -223,6 +332,43 @@ func (ts *Tags) ListAll(ctx context.Context, offset, limit int) (t []*Tag, err e
 	}
 
 	// and then from statestore
+	st, err := ts.listStore(offset, limit, true)
+	return append(t, st...), err
+}
+
+// Count returns the number of the tags, the ones in memory and the ones in
+// the statestore, so that the pages of ListAll can be told from the last one.
+func (ts *Tags) Count() (n int, err error) {
+	skipLoaded := true
+	if ts.shared {
+		ts.flush()
+		skipLoaded = false
+	} else {
+		ts.tags.Range(func(_, _ interface{}) bool {
+			n++
+			return true
+		})
+	}
+
+	err = ts.stateStore.Iterate(tagKeyPrefix, func(key, _ []byte) (stop bool, err error) {
+		if skipLoaded {
+			uid, err := strconv.ParseUint(strings.TrimPrefix(string(key), tagKeyPrefix), 10, 32)
+			if err != nil {
+				return true, err
+			}
+			if _, ok := ts.tags.Load(uint32(uid)); ok {
+				return false, nil
+			}
+		}
+		n++
+		return false, nil
+	})
+	return n, err
+}
+
+// listStore lists the tags from the statestore. If skipLoaded is true, the
+// tags which are in memory are skipped.
+func (ts *Tags) listStore(offset, limit int, skipLoaded bool) (t []*Tag, err error) {
 	err = ts.stateStore.Iterate(tagKeyPrefix, func(key, value []byte) (stop bool, err error) {
 		if offset > 0 {
 			offset--
@@ -235,7 +381,7 @@ func (ts *Tags) ListAll(ctx context.Context, offset, limit int) (t []*Tag, err e
 			return true, err
 		}
 
-		if _, ok := ts.tags.Load(ta.Uid); ok {
+		if _, ok := ts.tags.Load(ta.Uid); ok && skipLoaded {
 			// tag was already returned from sync.Map
 			return false, nil
 		}
@@ -284,8 +430,117 @@ func (ts *Tags) getTagFromStore(uid uint32) (*Tag, error) {
 	return &ta, nil
 }
 
+// save saves the tag to the shared store and marks its state as saved.
+func (ts *Tags) save(t *Tag) error {
+	data, err := t.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := ts.stateStore.Put(tagKey(t.Uid), data); err != nil {
+		return err
+	}
+
+	ts.savedM.Lock()
+	ts.saved[t.Uid] = data
+	ts.savedM.Unlock()
+	return nil
+}
+
+// markSaved marks the current state of the tag as saved.
+func (ts *Tags) markSaved(t *Tag) {
+	data, err := t.MarshalBinary()
+	if err != nil {
+		return
+	}
+
+	ts.savedM.Lock()
+	ts.saved[t.Uid] = data
+	ts.savedM.Unlock()
+}
+
+// refresh updates the tag with the state from the shared store, if the tag
+// was not changed locally since it was saved.
+func (ts *Tags) refresh(t *Tag) {
+	cur, err := t.MarshalBinary()
+	if err != nil {
+		return
+	}
+
+	ts.savedM.Lock()
+	prev, ok := ts.saved[t.Uid]
+	ts.savedM.Unlock()
+	if !ok || !bytes.Equal(cur, prev) {
+		// the local changes are saved by the flush loop
+		return
+	}
+
+	var data []byte
+	if err := ts.stateStore.Get(tagKey(t.Uid), &data); err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			ts.logger.Debug("refresh tag failed", "tag_uid", t.Uid, "error", err)
+		}
+		return
+	}
+	if bytes.Equal(cur, data) {
+		return
+	}
+
+	var stored Tag
+	if err := stored.UnmarshalBinary(data); err != nil {
+		ts.logger.Debug("refresh tag failed", "tag_uid", t.Uid, "error", err)
+		return
+	}
+	t.update(&stored)
+
+	ts.savedM.Lock()
+	ts.saved[t.Uid] = data
+	ts.savedM.Unlock()
+}
+
+// flush saves the tags which were changed locally to the shared store.
+func (ts *Tags) flush() {
+	for _, t := range ts.All() {
+		cur, err := t.MarshalBinary()
+		if err != nil {
+			continue
+		}
+
+		ts.savedM.Lock()
+		prev, ok := ts.saved[t.Uid]
+		ts.savedM.Unlock()
+		if ok && bytes.Equal(cur, prev) {
+			continue
+		}
+
+		if err := ts.save(t); err != nil {
+			ts.logger.Debug("save tag failed", "tag_uid", t.Uid, "error", err)
+		}
+	}
+}
+
+func (ts *Tags) flushLoop() {
+	defer ts.wg.Done()
+
+	ticker := time.NewTicker(sharedFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ts.quit:
+			return
+		case <-ticker.C:
+			ts.flush()
+		}
+	}
+}
+
 // Close is called when the node goes down. This is when all the tags in memory is persisted.
 func (ts *Tags) Close() (err error) {
+	if ts.shared {
+		close(ts.quit)
+		ts.wg.Wait()
+	}
+
 	loggerV1 := ts.logger.V(1).Register()
 	// store all the tags in memory
 	tags := ts.All()
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -143,6 +144,76 @@ func TestListAll(t *testing.T) {
 			t.Fatalf("expected tag %d, but got %d", tagList1[i].Uid, tagList3[i].Uid)
 		}
 	}
+
+	// the saved tags in memory are counted once
+	if n, err := ts1.Count(); err != nil || n != 5 {
+		t.Fatalf("got count %d, %v, want 5", n, err)
+	}
+	if n, err := ts2.Count(); err != nil || n != 10 {
+		t.Fatalf("got count %d, %v, want 10", n, err)
+	}
+}
+
+func TestCreateNamed(t *testing.T) {
+	t.Parallel()
+
+	mockStatestore := statestore.NewStateStore()
+	ts := NewTags(mockStatestore, log.Noop)
+
+	ta, err := ts.CreateNamed(1, "release", []byte(`{"build":42}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ta.DoneSplit(swarm.ZeroAddress); err != nil {
+		t.Fatal(err)
+	}
+
+	// the name and metadata are persisted
+	ts = NewTags(mockStatestore, log.Noop)
+	rcvd, err := ts.Get(ta.Uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rcvd.Name != "release" || string(rcvd.Metadata) != `{"build":42}` {
+		t.Fatalf("got tag name %q and metadata %s", rcvd.Name, rcvd.Metadata)
+	}
+
+	if _, err := ts.CreateNamed(0, strings.Repeat("a", MaxNameLength+1), nil); !errors.Is(err, ErrNameTooLong) {
+		t.Fatalf("got error %v, want %v", err, ErrNameTooLong)
+	}
+	if _, err := ts.CreateNamed(0, "", make([]byte, MaxMetadataSize+1)); !errors.Is(err, ErrMetadataTooLarge) {
+		t.Fatalf("got error %v, want %v", err, ErrMetadataTooLarge)
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	ts := NewTags(statestore.NewStateStore(), log.Noop)
+
+	o := &Tag{Uid: 42, Total: 10, Stored: 4, Name: "release", StartedAt: time.Unix(1000, 0)}
+	if err := ts.Import(o); err != nil {
+		t.Fatal(err)
+	}
+	ta, err := ts.Get(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ta.Total != 10 || ta.Stored != 4 || ta.Name != "release" || !ta.StartedAt.Equal(o.StartedAt) {
+		t.Fatalf("got tag %+v", ta)
+	}
+
+	// the progress of the existing tag is updated
+	addr := swarm.MustParseHexAddress("aabbcc")
+	if err := ts.Import(&Tag{Uid: 42, Total: 10, Stored: 10, Synced: 10, Address: addr}); err != nil {
+		t.Fatal(err)
+	}
+	if ta.Stored != 10 || ta.Synced != 10 || !ta.Address.Equal(addr) {
+		t.Fatalf("got tag %+v", ta)
+	}
+	if len(ts.All()) != 1 {
+		t.Fatalf("got %d tags, want 1", len(ts.All()))
+	}
 }
 
 func TestPersistence(t *testing.T) {
@@ -241,3 +312,82 @@ func TestPersistence(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestSharedTags(t *testing.T) {
+	t.Parallel()
+
+	// two nodes share the same state store
+	mockStatestore := statestore.NewStateStore()
+	logger := log.Noop
+	ts1 := NewSharedTags(mockStatestore, logger)
+	ts2 := NewSharedTags(mockStatestore, logger)
+	t.Cleanup(func() {
+		if err := ts1.Close(); err != nil {
+			t.Error(err)
+		}
+		if err := ts2.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	ta, err := ts1.Create(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the tag is available on the other node right after it is created
+	rcvd, err := ts2.Get(ta.Uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rcvd.TotalCounter() != 10 {
+		t.Fatalf("invalid total: expected %d got %d", 10, rcvd.TotalCounter())
+	}
+
+	if err := ta.IncN(StateStored, 4); err != nil {
+		t.Fatal(err)
+	}
+	ts1.flush()
+
+	// the progress is refreshed on the other node once it is saved
+	rcvd, err = ts2.Get(ta.Uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := rcvd.Get(StateStored); n != 4 {
+		t.Fatalf("invalid stored: expected %d got %d", 4, n)
+	}
+
+	// the local progress is not overwritten before it is saved
+	if err := rcvd.IncN(StateSent, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := ta.IncN(StateStored, 1); err != nil {
+		t.Fatal(err)
+	}
+	ts1.flush()
+	rcvd, err = ts2.Get(ta.Uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := rcvd.Get(StateSent); n != 2 {
+		t.Fatalf("invalid sent: expected %d got %d", 2, n)
+	}
+
+	ts2.flush()
+	all, err := ts1.ListAll(context.Background(), 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("expected length to be 1 got %d", len(all))
+	}
+	if n := all[0].Get(StateSent); n != 2 {
+		t.Fatalf("invalid sent: expected %d got %d", 2, n)
+	}
+
+	ts2.Delete(ta.Uid)
+	if _, err := ts2.Get(ta.Uid); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package testvectors generates deterministic test vectors of the manifest
+// encoding and the feed update construction, so that the client libraries
+// in other languages can verify that they produce the same bytes as bee.
+package testvectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/epochs"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// Version is the version of the test vectors format. It is increased
+// whenever the set of the vectors or their encoding changes.
+const Version = 1
+
+// Vectors is the complete set of the test vectors.
+type Vectors struct {
+	Version   int              `json:"version"`
+	Manifests []ManifestVector `json:"manifests"`
+	Feeds     []FeedVector     `json:"feeds"`
+}
+
+// ManifestEntry is a single path of a manifest.
+type ManifestEntry struct {
+	Path      string            `json:"path"`
+	Reference string            `json:"reference"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// Chunk is a hex encoded chunk.
+type Chunk struct {
+	Address string `json:"address"`
+	Data    string `json:"data"`
+}
+
+// ManifestVector is an unencrypted mantaray manifest with the given
+// entries, its root reference and all the chunks of its trie nodes
+// ordered by the address.
+type ManifestVector struct {
+	Name    string          `json:"name"`
+	Entries []ManifestEntry `json:"entries"`
+	Root    string          `json:"root"`
+	Chunks  []Chunk         `json:"chunks"`
+}
+
+// FeedVector is a single feed update. The Identifier is the
+// single owner chunk identifier derived from the topic and the index.
+type FeedVector struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	PrivateKey string `json:"privateKey"`
+	Owner      string `json:"owner"`
+	Topic      string `json:"topic"`
+	Index      string `json:"index"`
+	Timestamp  int64  `json:"timestamp"`
+	Payload    string `json:"payload"`
+	Identifier string `json:"identifier"`
+	Address    string `json:"address"`
+	Data       string `json:"data"`
+}
+
+// EncodeManifest builds an unencrypted mantaray manifest with the given
+// entries and returns its root reference together with the chunks of all
+// its trie nodes ordered by the address.
+func EncodeManifest(ctx context.Context, entries []ManifestEntry) (swarm.Address, []swarm.Chunk, error) {
+	store := newChunkStore()
+	ls := loadsave.New(store, func() pipeline.Interface {
+		return builder.NewPipelineBuilder(ctx, store, storage.ModePutUpload, false)
+	})
+
+	m, err := manifest.NewMantarayManifest(ls, false)
+	if err != nil {
+		return swarm.ZeroAddress, nil, err
+	}
+	for _, e := range entries {
+		ref, err := swarm.ParseHexAddress(e.Reference)
+		if err != nil {
+			return swarm.ZeroAddress, nil, fmt.Errorf("entry %q reference: %w", e.Path, err)
+		}
+		if err := m.Add(ctx, e.Path, manifest.NewEntry(ref, e.Metadata)); err != nil {
+			return swarm.ZeroAddress, nil, fmt.Errorf("add entry %q: %w", e.Path, err)
+		}
+	}
+	root, err := m.Store(ctx)
+	if err != nil {
+		return swarm.ZeroAddress, nil, fmt.Errorf("store manifest: %w", err)
+	}
+	return root, store.chunks(), nil
+}
+
+// Generate returns the test vectors. The result is the same on every call.
+func Generate(ctx context.Context) (*Vectors, error) {
+	v := &Vectors{Version: Version}
+
+	for _, mc := range manifestCases() {
+		root, chunks, err := EncodeManifest(ctx, mc.entries)
+		if err != nil {
+			return nil, fmt.Errorf("manifest %s: %w", mc.name, err)
+		}
+		mv := ManifestVector{
+			Name:    mc.name,
+			Entries: mc.entries,
+			Root:    root.String(),
+			Chunks:  make([]Chunk, 0, len(chunks)),
+		}
+		for _, ch := range chunks {
+			mv.Chunks = append(mv.Chunks, Chunk{Address: ch.Address().String(), Data: hex.EncodeToString(ch.Data())})
+		}
+		v.Manifests = append(v.Manifests, mv)
+	}
+
+	key, err := crypto.DecodeSecp256k1PrivateKey(keccak("testvectors/key"))
+	if err != nil {
+		return nil, err
+	}
+	keyBytes, err := crypto.EncodeSecp256k1PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	signer := crypto.NewDefaultSigner(key)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		return nil, err
+	}
+	topic := keccak("testvectors/topic")
+
+	for _, fc := range feedCases() {
+		id, err := feeds.Id(topic, fc.index)
+		if err != nil {
+			return nil, fmt.Errorf("feed %s: %w", fc.name, err)
+		}
+		ch, err := feeds.NewSignedUpdate(signer, topic, fc.index, fc.at, fc.payload)
+		if err != nil {
+			return nil, fmt.Errorf("feed %s: %w", fc.name, err)
+		}
+		v.Feeds = append(v.Feeds, FeedVector{
+			Name:       fc.name,
+			Type:       fc.typ.String(),
+			PrivateKey: hex.EncodeToString(keyBytes),
+			Owner:      hex.EncodeToString(owner.Bytes()),
+			Topic:      hex.EncodeToString(topic),
+			Index:      fc.index.String(),
+			Timestamp:  fc.at,
+			Payload:    hex.EncodeToString(fc.payload),
+			Identifier: hex.EncodeToString(id),
+			Address:    ch.Address().String(),
+			Data:       hex.EncodeToString(ch.Data()),
+		})
+	}
+
+	return v, nil
+}
+
+type manifestCase struct {
+	name    string
+	entries []ManifestEntry
+}
+
+func manifestCases() []manifestCase {
+	ref := func(path string) string {
+		return hex.EncodeToString(keccak("testvectors/" + path))
+	}
+	return []manifestCase{
+		{
+			name:    "empty",
+			entries: []ManifestEntry{},
+		},
+		{
+			name: "single-file",
+			entries: []ManifestEntry{
+				{
+					Path:      "hello.txt",
+					Reference: ref("hello.txt"),
+					Metadata: map[string]string{
+						manifest.EntryMetadataContentTypeKey: "text/plain; charset=utf-8",
+						manifest.EntryMetadataFilenameKey:    "hello.txt",
+					},
+				},
+			},
+		},
+		{
+			name: "website",
+			entries: []ManifestEntry{
+				{
+					Path:      manifest.RootPath,
+					Reference: swarm.ZeroAddress.String(),
+					Metadata: map[string]string{
+						manifest.WebsiteIndexDocumentSuffixKey: "index.html",
+						manifest.WebsiteErrorDocumentPathKey:   "404.html",
+					},
+				},
+				{Path: "index.html", Reference: ref("index.html")},
+				{Path: "404.html", Reference: ref("404.html")},
+				{Path: "img/logo.png", Reference: ref("img/logo.png")},
+				{Path: "img/logo-small.png", Reference: ref("img/logo-small.png")},
+				{Path: "docs/index.html", Reference: ref("docs/index.html")},
+			},
+		},
+	}
+}
+
+type feedCase struct {
+	name    string
+	typ     feeds.Type
+	index   feeds.Index
+	at      int64
+	payload []byte
+}
+
+func feedCases() []feedCase {
+	return []feedCase{
+		{name: "sequence-first", typ: feeds.Sequence, index: sequence.NewIndex(0), at: 1600000000, payload: []byte("first")},
+		{name: "sequence-large-index", typ: feeds.Sequence, index: sequence.NewIndex(1 << 40), at: 1600000001, payload: []byte("large")},
+		{name: "sequence-empty-payload", typ: feeds.Sequence, index: sequence.NewIndex(1), at: 0},
+		{name: "epoch-root", typ: feeds.Epoch, index: epochs.NewIndex(0, 32), at: 1600000000, payload: []byte("root")},
+		{name: "epoch-level", typ: feeds.Epoch, index: epochs.NewIndex(1600000000, 10), at: 1600000512, payload: []byte("level")},
+	}
+}
+
+func keccak(s string) []byte {
+	h, _ := crypto.LegacyKeccak256([]byte(s))
+	return h
+}
+
+// chunkStore is an in-memory chunk store which
+// keeps the chunks in the order of the address.
+type chunkStore struct {
+	mu   sync.Mutex
+	data map[string]swarm.Chunk
+}
+
+func newChunkStore() *chunkStore {
+	return &chunkStore{data: make(map[string]swarm.Chunk)}
+}
+
+func (s *chunkStore) Put(_ context.Context, _ storage.ModePut, chs ...swarm.Chunk) ([]bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exist := make([]bool, len(chs))
+	for i, ch := range chs {
+		_, exist[i] = s.data[ch.Address().ByteString()]
+		s.data[ch.Address().ByteString()] = swarm.NewChunk(ch.Address(), ch.Data())
+	}
+	return exist, nil
+}
+
+func (s *chunkStore) Get(_ context.Context, _ storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch, ok := s.data[addr.ByteString()]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return ch, nil
+}
+
+func (s *chunkStore) chunks() []swarm.Chunk {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chs := make([]swarm.Chunk, 0, len(s.data))
+	for _, ch := range s.data {
+		chs = append(chs, ch)
+	}
+	sort.Slice(chs, func(i, j int) bool {
+		return bytes.Compare(chs[i].Address().Bytes(), chs[j].Address().Bytes()) < 0
+	})
+	return chs
+}
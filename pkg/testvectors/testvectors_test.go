@@ -0,0 +1,113 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testvectors_test
+
+import (
+	"context"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/testvectors"
+)
+
+func TestGenerateDeterministic(t *testing.T) {
+	t.Parallel()
+
+	v1, err := testvectors.Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2, err := testvectors.Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v1, v2) {
+		t.Fatal("test vectors differ between runs")
+	}
+}
+
+func TestManifestVectors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	v, err := testvectors.Generate(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mv := range v.Manifests {
+		mv := mv
+		t.Run(mv.Name, func(t *testing.T) {
+			t.Parallel()
+
+			store := mock.NewStorer()
+			for _, c := range mv.Chunks {
+				data, err := hex.DecodeString(c.Data)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := store.Put(ctx, storage.ModePutUpload, swarm.NewChunk(swarm.MustParseHexAddress(c.Address), data)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			m, err := manifest.NewDefaultManifestReference(swarm.MustParseHexAddress(mv.Root), loadsave.NewReadonly(store))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range mv.Entries {
+				got, err := m.Lookup(ctx, e.Path)
+				if err != nil {
+					t.Fatalf("lookup %q: %v", e.Path, err)
+				}
+				if want := swarm.MustParseHexAddress(e.Reference); !got.Reference().Equal(want) {
+					t.Fatalf("lookup %q: got reference %s, want %s", e.Path, got.Reference(), want)
+				}
+				if len(e.Metadata) > 0 && !reflect.DeepEqual(got.Metadata(), e.Metadata) {
+					t.Fatalf("lookup %q: got metadata %v, want %v", e.Path, got.Metadata(), e.Metadata)
+				}
+			}
+		})
+	}
+}
+
+func TestFeedVectors(t *testing.T) {
+	t.Parallel()
+
+	v, err := testvectors.Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fv := range v.Feeds {
+		data, err := hex.DecodeString(fv.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ch := swarm.NewChunk(swarm.MustParseHexAddress(fv.Address), data)
+		if !soc.Valid(ch) {
+			t.Fatalf("%s: invalid single owner chunk", fv.Name)
+		}
+		if got := hex.EncodeToString(data[:swarm.HashSize]); got != fv.Identifier {
+			t.Fatalf("%s: got identifier %s, want %s", fv.Name, got, fv.Identifier)
+		}
+		id, _ := hex.DecodeString(fv.Identifier)
+		owner, _ := hex.DecodeString(fv.Owner)
+		addr, err := soc.CreateAddress(id, owner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !addr.Equal(ch.Address()) {
+			t.Fatalf("%s: got address %s, want %s", fv.Name, addr, ch.Address())
+		}
+	}
+}
@@ -7,6 +7,8 @@ package depthmonitor
 import (
 	"errors"
 	"fmt"
+	"math"
+	"sync"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/log"
@@ -26,6 +28,40 @@ const DefaultWakeupInterval = 5 * time.Minute
 // for the depth monitor minimum radius.
 const defaultMinimumRadius uint8 = 0
 
+// DefaultDecreaseThreshold is the default share of the reserve
+// capacity below which the storage depth is decreased.
+const DefaultDecreaseThreshold = 0.4
+
+// Options are the hysteresis options of the depth monitor, so that the
+// storage depth does not oscillate, as every change of the depth causes the
+// eviction of the chunks or the syncing of them back.
+type Options struct {
+	// DecreaseThreshold is the share of the reserve capacity
+	// below which the storage depth is decreased.
+	DecreaseThreshold float64
+	// Cooldown is the time after a change of the storage depth,
+	// in either direction, during which the depth is not decreased.
+	Cooldown time.Duration
+	// Confirmations is the number of the consecutive wake-ups
+	// meeting the conditions which are needed to decrease the depth.
+	Confirmations int
+}
+
+// Status is the state of the depth monitor with the inputs of
+// the storage depth computation.
+type Status struct {
+	StorageRadius   uint8
+	ReserveRadius   uint8
+	ReserveSize     uint64
+	ReserveCapacity uint64
+	TargetSize      uint64 // reserve size below which the depth is decreased
+	Commitment      int64  // number of the chunks of all batches
+	ConnectedPeers  int
+	SyncRate        float64
+	LastChange      time.Time // time of the last change of the storage depth
+	Confirmations   int       // number of the consecutive wake-ups meeting the conditions of a decrease
+}
+
 // SyncReporter interface needs to be implemented by the syncing component of the node (puller).
 type SyncReporter interface {
 	// Number of active historical syncing jobs.
@@ -65,6 +101,17 @@ type Service struct {
 	stopped       chan struct{} // to signal stopping of bg worker
 	minimumRadius uint8
 	lastRSize     *atomic.Uint64
+
+	decreaseThreshold float64
+	cooldown          time.Duration
+	confirmations     int
+
+	mu         sync.Mutex
+	lastRadius uint8
+	lastChange time.Time
+	confirmed  int
+	peers      int
+	syncRate   float64
 }
 
 // New constructs a new depthmonitor service
@@ -77,8 +124,16 @@ func New(
 	warmupTime time.Duration,
 	wakeupInterval time.Duration,
 	freshNode bool,
+	o Options,
 ) *Service {
 
+	if o.DecreaseThreshold <= 0 {
+		o.DecreaseThreshold = DefaultDecreaseThreshold
+	}
+	if o.Confirmations <= 0 {
+		o.Confirmations = 1
+	}
+
 	s := &Service{
 		topology:      t,
 		syncer:        syncer,
@@ -89,6 +144,11 @@ func New(
 		stopped:       make(chan struct{}),
 		minimumRadius: defaultMinimumRadius,
 		lastRSize:     atomic.NewUint64(0),
+
+		decreaseThreshold: o.DecreaseThreshold,
+		cooldown:          o.Cooldown,
+		confirmations:     o.Confirmations,
+		lastChange:        time.Now(),
 	}
 
 	go s.manage(warmupTime, wakeupInterval, freshNode)
@@ -128,7 +188,11 @@ func (s *Service) manage(warmupTime, wakeupInterval time.Duration, freshNode boo
 
 	s.logger.Info("depthmonitor: warmup period complete, starting worker", "radius", s.bs.StorageRadius())
 
-	targetSize := s.reserve.ReserveCapacity() * 4 / 10 // 40% of the capacity
+	targetSize := s.targetSize()
+
+	s.mu.Lock()
+	s.lastRadius = s.bs.StorageRadius()
+	s.mu.Unlock()
 
 	for {
 		select {
@@ -149,30 +213,91 @@ func (s *Service) manage(warmupTime, wakeupInterval time.Duration, freshNode boo
 		s.lastRSize.Store(currentSize)
 
 		rate := s.syncer.SyncRate()
-		s.logger.Info("depthmonitor: state", "size", currentSize, "radius", radius, "sync_rate", fmt.Sprintf("%.2f ch/s", rate))
+		peers := s.topology.PeersCount(topologyDriver.Filter{})
+		s.logger.Info("depthmonitor: state", "size", currentSize, "radius", radius, "sync_rate", fmt.Sprintf("%.2f ch/s", rate), "peers", peers)
+
+		s.mu.Lock()
+		s.peers = peers
+		s.syncRate = rate
+		// the depth is also increased by the batch store once the reserve is full
+		if radius != s.lastRadius {
+			s.lastRadius = radius
+			s.lastChange = time.Now()
+			s.confirmed = 0
+		}
+		// if historical syncing rate is at zero, we proactively decrease the storage radius to allow nodes to widen their neighbourhoods
+		if currentSize > targetSize || rate != 0 || peers == 0 {
+			s.confirmed = 0
+			s.mu.Unlock()
+			continue
+		}
+		s.confirmed++
+		confirmed, lastChange := s.confirmed, s.lastChange
+		s.mu.Unlock()
 
-		if currentSize > targetSize {
+		if confirmed < s.confirmations || time.Since(lastChange) < s.cooldown {
+			s.logger.Debug("depthmonitor: storage depth decrease postponed", "confirmations", confirmed, "last_change", lastChange)
 			continue
 		}
 
-		// if historical syncing rate is at zero, we proactively decrease the storage radius to allow nodes to widen their neighbourhoods
-		if rate == 0 && s.topology.PeersCount(topologyDriver.Filter{}) != 0 {
-			err = s.bs.SetStorageRadius(func(radius uint8) uint8 {
-				if radius > s.minimumRadius {
-					radius--
-					s.logger.Info("depthmonitor: reducing storage depth", "depth", radius)
-				}
-				return radius
-			})
-			if err != nil {
-				s.logger.Error(err, "depthmonitor: batchstore set storage radius")
+		err = s.bs.SetStorageRadius(func(radius uint8) uint8 {
+			if radius > s.minimumRadius {
+				radius--
+				s.logger.Info("depthmonitor: reducing storage depth", "depth", radius)
 			}
+			return radius
+		})
+		if err != nil {
+			s.logger.Error(err, "depthmonitor: batchstore set storage radius")
+			continue
+		}
+
+		s.mu.Lock()
+		if r := s.bs.StorageRadius(); r != s.lastRadius {
+			s.lastRadius = r
+			s.lastChange = time.Now()
+			s.confirmed = 0
 		}
+		s.mu.Unlock()
 	}
 }
 
+// targetSize returns the reserve size below which the depth is decreased.
+func (s *Service) targetSize() uint64 {
+	return uint64(float64(s.reserve.ReserveCapacity()) * s.decreaseThreshold)
+}
+
 func (s *Service) IsFullySynced() bool {
-	return s.syncer.SyncRate() == 0 && s.lastRSize.Load() > s.reserve.ReserveCapacity()*4/10
+	return s.syncer.SyncRate() == 0 && s.lastRSize.Load() > s.targetSize()
+}
+
+// Status returns the state of the depth monitor as of its last wake-up.
+func (s *Service) Status() (Status, error) {
+	var commitment int64
+	if err := s.bs.Iterate(func(b *postage.Batch) (bool, error) {
+		commitment += int64(math.Pow(2.0, float64(b.Depth)))
+		return false, nil
+	}); err != nil {
+		return Status{}, fmt.Errorf("iterate batches: %w", err)
+	}
+
+	rs := s.bs.GetReserveState()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return Status{
+		StorageRadius:   rs.StorageRadius,
+		ReserveRadius:   rs.Radius,
+		ReserveSize:     s.lastRSize.Load(),
+		ReserveCapacity: s.reserve.ReserveCapacity(),
+		TargetSize:      s.targetSize(),
+		Commitment:      commitment,
+		ConnectedPeers:  s.peers,
+		SyncRate:        s.syncRate,
+		LastChange:      s.lastChange,
+		Confirmations:   s.confirmed,
+	}, nil
 }
 
 func (s *Service) Close() error {
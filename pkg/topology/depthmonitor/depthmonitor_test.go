@@ -29,6 +29,7 @@ func newTestSvc(
 	warmupTime time.Duration,
 	wakeupInterval time.Duration,
 	freshNode bool,
+	o depthmonitor.Options,
 ) *depthmonitor.Service {
 
 	var topo depthmonitor.Topology = &mockTopology{}
@@ -51,7 +52,7 @@ func newTestSvc(
 		batchStore = bs
 	}
 
-	return depthmonitor.New(topo, syncer, reserve, batchStore, log.Noop, warmupTime, wakeupInterval, freshNode)
+	return depthmonitor.New(topo, syncer, reserve, batchStore, log.Noop, warmupTime, wakeupInterval, freshNode, o)
 }
 
 func TestDepthMonitorService_FLAKY(t *testing.T) {
@@ -71,7 +72,7 @@ func TestDepthMonitorService_FLAKY(t *testing.T) {
 	t.Run("stop service within warmup time", func(t *testing.T) {
 		t.Parallel()
 
-		svc := newTestSvc(nil, nil, nil, nil, nil, time.Second, depthmonitor.DefaultWakeupInterval, true)
+		svc := newTestSvc(nil, nil, nil, nil, nil, time.Second, depthmonitor.DefaultWakeupInterval, true, depthmonitor.Options{})
 		err := svc.Close()
 		if err != nil {
 			t.Fatal(err)
@@ -82,7 +83,7 @@ func TestDepthMonitorService_FLAKY(t *testing.T) {
 		t.Parallel()
 
 		bs := mockbatchstore.New(mockbatchstore.WithReserveState(&postage.ReserveState{Radius: 3, StorageRadius: 0}))
-		svc := newTestSvc(nil, nil, nil, nil, bs, 0, depthmonitor.DefaultWakeupInterval, false)
+		svc := newTestSvc(nil, nil, nil, nil, bs, 0, depthmonitor.DefaultWakeupInterval, false, depthmonitor.Options{})
 		waitForDepth(t, svc, 0)
 		err := svc.Close()
 		if err != nil {
@@ -94,7 +95,7 @@ func TestDepthMonitorService_FLAKY(t *testing.T) {
 		t.Parallel()
 
 		bs := mockbatchstore.New(mockbatchstore.WithReserveState(&postage.ReserveState{Radius: 3}))
-		svc := newTestSvc(nil, nil, nil, nil, bs, 0, depthmonitor.DefaultWakeupInterval, true)
+		svc := newTestSvc(nil, nil, nil, nil, bs, 0, depthmonitor.DefaultWakeupInterval, true, depthmonitor.Options{})
 		waitForDepth(t, svc, 3)
 		err := svc.Close()
 		if err != nil {
@@ -111,7 +112,7 @@ func TestDepthMonitorService_FLAKY(t *testing.T) {
 
 		bs := mockbatchstore.New(mockbatchstore.WithReserveState(&postage.ReserveState{Radius: 3}))
 
-		svc := newTestSvc(topo, nil, reserve, nil, bs, 0, depthMonitorWakeUpInterval, true)
+		svc := newTestSvc(topo, nil, reserve, nil, bs, 0, depthMonitorWakeUpInterval, true, depthmonitor.Options{})
 
 		waitForDepth(t, svc, 3)
 		// simulate huge eviction to trigger manage worker
@@ -132,7 +133,7 @@ func TestDepthMonitorService_FLAKY(t *testing.T) {
 		bs := mockbatchstore.New(mockbatchstore.WithReserveState(&postage.ReserveState{Radius: 3}))
 		syncer := &mockSyncReporter{rate: 10}
 
-		svc := newTestSvc(nil, syncer, reserve, nil, bs, 0, depthMonitorWakeUpInterval, true)
+		svc := newTestSvc(nil, syncer, reserve, nil, bs, 0, depthMonitorWakeUpInterval, true, depthmonitor.Options{})
 
 		time.Sleep(time.Second)
 		// ensure that after few cycles of the adaptation period, the depth hasn't changed
@@ -152,7 +153,7 @@ func TestDepthMonitorService_FLAKY(t *testing.T) {
 		reserve := &mockReserve{size: 20001, capacity: 50000}
 		bs := mockbatchstore.New(mockbatchstore.WithReserveState(&postage.ReserveState{Radius: 3}))
 
-		svc := newTestSvc(nil, nil, reserve, nil, bs, 0, depthMonitorWakeUpInterval, true)
+		svc := newTestSvc(nil, nil, reserve, nil, bs, 0, depthMonitorWakeUpInterval, true, depthmonitor.Options{})
 
 		time.Sleep(time.Second)
 		// ensure the depth hasnt changed
@@ -165,6 +166,71 @@ func TestDepthMonitorService_FLAKY(t *testing.T) {
 		}
 	})
 
+	t.Run("depth decrease with threshold and confirmations", func(t *testing.T) {
+		t.Parallel()
+
+		topo := &mockTopology{peers: 1}
+		reserve := &mockReserve{size: 20001, capacity: 50000}
+		bs := mockbatchstore.New(mockbatchstore.WithReserveState(&postage.ReserveState{Radius: 3}))
+
+		svc := newTestSvc(topo, nil, reserve, nil, bs, 0, depthMonitorWakeUpInterval, true, depthmonitor.Options{
+			DecreaseThreshold: 0.1,
+			Confirmations:     3,
+		})
+		waitForDepth(t, svc, 3)
+
+		// under the default threshold, but over the configured one
+		reserve.setSize(10000)
+		time.Sleep(10 * depthMonitorWakeUpInterval)
+		if svc.StorageDepth() != 3 {
+			t.Fatal("found drop in depth")
+		}
+
+		reserve.setSize(1000)
+		waitForDepth(t, svc, 0)
+
+		status, err := svc.Status()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.TargetSize != 5000 || status.ReserveCapacity != 50000 || status.ConnectedPeers != 1 {
+			t.Fatalf("got status %+v", status)
+		}
+
+		if err := svc.Close(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("depth doesnt change during cooldown", func(t *testing.T) {
+		t.Parallel()
+
+		topo := &mockTopology{peers: 1}
+		reserve := &mockReserve{size: 1000, capacity: 50000}
+		bs := mockbatchstore.New(mockbatchstore.WithReserveState(&postage.ReserveState{Radius: 3}))
+
+		svc := newTestSvc(topo, nil, reserve, nil, bs, 0, depthMonitorWakeUpInterval, true, depthmonitor.Options{
+			Cooldown: time.Hour,
+		})
+
+		time.Sleep(time.Second)
+		if svc.StorageDepth() != 3 {
+			t.Fatal("found drop in depth")
+		}
+
+		status, err := svc.Status()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.Confirmations == 0 {
+			t.Fatal("found no confirmations of the decrease")
+		}
+
+		if err := svc.Close(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
 	t.Run("radius setter handler", func(t *testing.T) {
 		t.Parallel()
 
@@ -173,7 +239,7 @@ func TestDepthMonitorService_FLAKY(t *testing.T) {
 		// >40% utilized reserve
 		reserve := &mockReserve{size: 20001, capacity: 50000}
 
-		svc := newTestSvc(topo, nil, reserve, nil, bs, 0, depthMonitorWakeUpInterval, true)
+		svc := newTestSvc(topo, nil, reserve, nil, bs, 0, depthMonitorWakeUpInterval, true, depthmonitor.Options{})
 
 		waitForDepth(t, svc, 3)
 
@@ -0,0 +1,98 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topology
+
+import (
+	"time"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// Graph is a view of the topology as a graph of the node and its peers,
+// suitable for the visualization tools which consume the node-link format.
+type Graph struct {
+	Timestamp time.Time   `json:"timestamp"`
+	Depth     uint8       `json:"depth"`
+	Nodes     []GraphNode `json:"nodes"`
+	Edges     []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a single node of the topology graph.
+type GraphNode struct {
+	ID           string `json:"id"`
+	Self         bool   `json:"self,omitempty"`
+	Bin          uint8  `json:"bin"`
+	Connected    bool   `json:"connected"`
+	LightNode    bool   `json:"lightNode,omitempty"`
+	Reachability string `json:"reachability,omitempty"`
+}
+
+// GraphEdge is a connection between the node and one of its peers.
+type GraphEdge struct {
+	Source    string `json:"source"`
+	Target    string `json:"target"`
+	Bin       uint8  `json:"bin"`
+	Latency   int64  `json:"latency"` // in milliseconds
+	Direction string `json:"direction,omitempty"`
+}
+
+// NewGraph creates the topology graph from the kademlia parameters. All known
+// peers are graph nodes and every connected peer is linked to the base node.
+func NewGraph(p *KadParams) *Graph {
+	g := &Graph{
+		Timestamp: p.Timestamp,
+		Depth:     p.Depth,
+		Nodes: []GraphNode{{
+			ID:           p.Base,
+			Self:         true,
+			Bin:          swarm.MaxPO,
+			Connected:    true,
+			Reachability: p.Reachability,
+		}},
+		Edges: make([]GraphEdge, 0, p.Connected),
+	}
+
+	addPeers := func(bin uint8, peers []*PeerInfo, connected, lightNode bool) {
+		for _, peer := range peers {
+			node := GraphNode{
+				ID:        peer.Address.String(),
+				Bin:       bin,
+				Connected: connected,
+				LightNode: lightNode,
+			}
+			if peer.Metrics != nil {
+				node.Reachability = peer.Metrics.Reachability
+			}
+			g.Nodes = append(g.Nodes, node)
+
+			if !connected {
+				continue
+			}
+			edge := GraphEdge{
+				Source: p.Base,
+				Target: node.ID,
+				Bin:    bin,
+			}
+			if peer.Metrics != nil {
+				edge.Latency = peer.Metrics.LatencyEWMA
+				edge.Direction = peer.Metrics.SessionConnectionDirection
+			}
+			g.Edges = append(g.Edges, edge)
+		}
+	}
+
+	for i, bin := range p.Bins.BinInfos() {
+		addPeers(uint8(i), bin.ConnectedPeers, true, false)
+		addPeers(uint8(i), bin.DisconnectedPeers, false, false)
+	}
+
+	base, _ := swarm.ParseHexAddress(p.Base)
+	for _, peer := range p.LightNodes.ConnectedPeers {
+		bin := swarm.Proximity(base.Bytes(), peer.Address.Bytes())
+		addPeers(bin, []*PeerInfo{peer}, true, true)
+	}
+
+	return g
+}
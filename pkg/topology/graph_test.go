@@ -0,0 +1,73 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topology_test
+
+import (
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+)
+
+func TestNewGraph(t *testing.T) {
+	t.Parallel()
+
+	var (
+		base         = swarm.MustParseHexAddress("0000000000000000000000000000000000000000000000000000000000000000")
+		connected    = swarm.MustParseHexAddress("8000000000000000000000000000000000000000000000000000000000000000")
+		disconnected = swarm.MustParseHexAddress("4000000000000000000000000000000000000000000000000000000000000000")
+		lightNode    = swarm.MustParseHexAddress("2000000000000000000000000000000000000000000000000000000000000000")
+	)
+
+	params := &topology.KadParams{
+		Base:      base.String(),
+		Connected: 1,
+		Depth:     1,
+		Bins: topology.KadBins{
+			Bin0: topology.BinInfo{
+				ConnectedPeers: []*topology.PeerInfo{{
+					Address: connected,
+					Metrics: &topology.MetricSnapshotView{LatencyEWMA: 42, SessionConnectionDirection: "outbound", Reachability: "Public"},
+				}},
+			},
+			Bin1: topology.BinInfo{
+				DisconnectedPeers: []*topology.PeerInfo{{Address: disconnected}},
+			},
+		},
+		LightNodes: topology.BinInfo{
+			ConnectedPeers: []*topology.PeerInfo{{Address: lightNode}},
+		},
+	}
+
+	g := topology.NewGraph(params)
+
+	wantNodes := []topology.GraphNode{
+		{ID: base.String(), Self: true, Bin: swarm.MaxPO, Connected: true},
+		{ID: connected.String(), Bin: 0, Connected: true, Reachability: "Public"},
+		{ID: disconnected.String(), Bin: 1},
+		{ID: lightNode.String(), Bin: 2, Connected: true, LightNode: true},
+	}
+	if len(g.Nodes) != len(wantNodes) {
+		t.Fatalf("nodes length mismatch: have %d want %d", len(g.Nodes), len(wantNodes))
+	}
+	for i, want := range wantNodes {
+		if have := g.Nodes[i]; have != want {
+			t.Fatalf("node %d mismatch: have %+v want %+v", i, have, want)
+		}
+	}
+
+	wantEdges := []topology.GraphEdge{
+		{Source: base.String(), Target: connected.String(), Bin: 0, Latency: 42, Direction: "outbound"},
+		{Source: base.String(), Target: lightNode.String(), Bin: 2},
+	}
+	if len(g.Edges) != len(wantEdges) {
+		t.Fatalf("edges length mismatch: have %d want %d", len(g.Edges), len(wantEdges))
+	}
+	for i, want := range wantEdges {
+		if have := g.Edges[i]; have != want {
+			t.Fatalf("edge %d mismatch: have %+v want %+v", i, have, want)
+		}
+	}
+}
@@ -5,9 +5,13 @@
 package kademlia
 
 import (
+	"context"
+	"time"
+
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/topology"
 	"github.com/ethersphere/bee/pkg/topology/pslice"
+	ma "github.com/multiformats/go-multiaddr"
 )
 
 var (
@@ -15,14 +19,25 @@ var (
 		return k.pruneOversaturatedBins
 	}
 	GenerateCommonBinPrefixes = generateCommonBinPrefixes
+	BestPeerInSlice           = bestPeerInSlice
+	Subnet                    = subnet
 )
 
 const (
-	DefaultBitSuffixLength     = defaultBitSuffixLength
-	DefaultSaturationPeers     = defaultSaturationPeers
-	DefaultOverSaturationPeers = defaultOverSaturationPeers
+	DefaultBitSuffixLength = defaultBitSuffixLength
+	MaxConnAttempts        = maxConnAttempts
 )
 
+var ConnectFunc = func(k *Kad) func(context.Context, swarm.Address, ma.Multiaddr) error {
+	return k.connect
+}
+
+// ConnectRetry returns the number of the failed connect attempts
+// of the peer and the time after which it is tried again.
+func (k *Kad) ConnectRetry(peer swarm.Address) (int, time.Time) {
+	return k.waitNext.Attempts(peer), k.waitNext.TryAfter(peer)
+}
+
 type PeerFilterFunc = peerFilterFunc
 
 func (k *Kad) IsWithinDepth(addr swarm.Address) bool {
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
@@ -138,11 +139,33 @@ func (ss *Snapshot) HasAtMaxOneConnectionAttempt() bool {
 	return ss.LastSeenTimestamp == 0 && ss.SessionConnectionRetry <= 1
 }
 
+// Score returns the quality score of the peer represented by the snapshot.
+// Peers which were never connected have score zero. The score grows
+// logarithmically with the total connection time, is doubled for publicly
+// reachable peers and is penalized by the average latency and by the
+// number of failed connection attempts in the current session.
+func (ss *Snapshot) Score() float64 {
+	if ss.ConnectionTotalDuration <= 0 {
+		return 0
+	}
+
+	score := math.Log1p(ss.ConnectionTotalDuration.Minutes())
+	if ss.Reachability == p2p.ReachabilityStatusPublic {
+		score *= 2
+	}
+	score /= 1 + ss.LatencyEWMA.Seconds()
+	score /= 1 + float64(ss.SessionConnectionRetry)
+
+	return score
+}
+
 // persistentCounters is a helper struct used for persisting selected counters.
 type persistentCounters struct {
-	PeerAddress       swarm.Address `json:"peerAddress"`
-	LastSeenTimestamp int64         `json:"lastSeenTimestamp"`
-	ConnTotalDuration time.Duration `json:"connTotalDuration"`
+	PeerAddress       swarm.Address          `json:"peerAddress"`
+	LastSeenTimestamp int64                  `json:"lastSeenTimestamp"`
+	ConnTotalDuration time.Duration          `json:"connTotalDuration"`
+	LatencyEWMA       time.Duration          `json:"latencyEWMA,omitempty"`
+	Reachability      p2p.ReachabilityStatus `json:"reachability,omitempty"`
 }
 
 // Counters represents a collection of peer metrics
@@ -174,6 +197,8 @@ func (cs *Counters) UnmarshalJSON(b []byte) (err error) {
 	cs.peerAddress = val.PeerAddress
 	cs.lastSeenTimestamp = val.LastSeenTimestamp
 	cs.connTotalDuration = val.ConnTotalDuration
+	cs.latencyEWMA = val.LatencyEWMA
+	cs.ReachabilityStatus = val.Reachability
 	cs.Unlock()
 	return nil
 }
@@ -185,6 +210,8 @@ func (cs *Counters) MarshalJSON() ([]byte, error) {
 		PeerAddress:       cs.peerAddress,
 		LastSeenTimestamp: cs.lastSeenTimestamp,
 		ConnTotalDuration: cs.connTotalDuration,
+		LatencyEWMA:       cs.latencyEWMA,
+		Reachability:      cs.ReachabilityStatus,
 	}
 	cs.Unlock()
 	return json.Marshal(val)
@@ -232,9 +259,11 @@ func NewCollector(db *shed.DB) (*Collector, error) {
 
 	for _, val := range counters {
 		c.counters.Store(val.PeerAddress.ByteString(), &Counters{
-			peerAddress:       val.PeerAddress,
-			lastSeenTimestamp: val.LastSeenTimestamp,
-			connTotalDuration: val.ConnTotalDuration,
+			peerAddress:        val.PeerAddress,
+			lastSeenTimestamp:  val.LastSeenTimestamp,
+			connTotalDuration:  val.ConnTotalDuration,
+			latencyEWMA:        val.LatencyEWMA,
+			ReachabilityStatus: val.Reachability,
 		})
 	}
 
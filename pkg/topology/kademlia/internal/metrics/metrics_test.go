@@ -172,8 +172,47 @@ func TestPeerMetricsCollector(t *testing.T) {
 	want = &metrics.Snapshot{
 		LastSeenTimestamp:       ss.LastSeenTimestamp,
 		ConnectionTotalDuration: 2 * ss.ConnectionTotalDuration, // 2x because we've already logout with t3 and login with t1 again.
+		LatencyEWMA:             ss.LatencyEWMA,
+		Reachability:            ss.Reachability,
 	}
 	if diff := cmp.Diff(have, want); diff != "" {
 		t.Fatalf("unexpected snapshot diffrence:\n%s", diff)
 	}
 }
+
+func TestSnapshotScore(t *testing.T) {
+	t.Parallel()
+
+	never := &metrics.Snapshot{}
+	if have, want := never.Score(), 0.0; have != want {
+		t.Fatalf("Score(): never connected peer score mismatch: have %f; want %f", have, want)
+	}
+
+	good := &metrics.Snapshot{
+		ConnectionTotalDuration: time.Hour,
+		LatencyEWMA:             10 * time.Millisecond,
+		Reachability:            p2p.ReachabilityStatusPublic,
+	}
+	slow := &metrics.Snapshot{
+		ConnectionTotalDuration: time.Hour,
+		LatencyEWMA:             time.Second,
+		Reachability:            p2p.ReachabilityStatusPublic,
+	}
+	private := &metrics.Snapshot{
+		ConnectionTotalDuration: time.Hour,
+		LatencyEWMA:             10 * time.Millisecond,
+		Reachability:            p2p.ReachabilityStatusPrivate,
+	}
+	flapping := &metrics.Snapshot{
+		ConnectionTotalDuration: time.Hour,
+		LatencyEWMA:             10 * time.Millisecond,
+		Reachability:            p2p.ReachabilityStatusPublic,
+		SessionConnectionRetry:  3,
+	}
+
+	for _, ss := range []*metrics.Snapshot{slow, private, flapping} {
+		if good.Score() <= ss.Score() {
+			t.Fatalf("Score(): want %f to be greater than %f", good.Score(), ss.Score())
+		}
+	}
+}
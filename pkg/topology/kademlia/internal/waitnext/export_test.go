@@ -0,0 +1,13 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package waitnext
+
+// FlappingPeers returns the number of the peers with the recorded flaps.
+func (r *WaitNext) FlappingPeers() int {
+	r.Lock()
+	defer r.Unlock()
+
+	return len(r.flaps)
+}
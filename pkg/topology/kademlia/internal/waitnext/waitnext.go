@@ -7,6 +7,7 @@
 package waitnext
 
 import (
+	"math/rand"
 	"sync"
 	"time"
 
@@ -19,16 +20,41 @@ type next struct {
 }
 
 type WaitNext struct {
-	next map[string]*next
+	next  map[string]*next
+	flaps map[string][]time.Time // disconnect times, kept across the reconnects
 	sync.Mutex
 }
 
 func New() *WaitNext {
 	return &WaitNext{
-		next: make(map[string]*next),
+		next:  make(map[string]*next),
+		flaps: make(map[string][]time.Time),
 	}
 }
 
+// Backoff calculates exponentially growing retry delays with a random jitter.
+type Backoff struct {
+	Base   time.Duration // delay after the first failure
+	Max    time.Duration // upper bound of the delay before the jitter is added
+	Jitter float64       // maximal random extension of the delay, relative to the delay
+}
+
+// Delay returns how long to wait before the next attempt
+// after the given number of consecutive failures.
+func (b Backoff) Delay(failures int) time.Duration {
+	d := b.Base
+	for i := 1; i < failures && d < b.Max; i++ {
+		d *= 2
+	}
+	if b.Max > 0 && d > b.Max {
+		d = b.Max
+	}
+	if b.Jitter > 0 {
+		d += time.Duration(rand.Float64() * b.Jitter * float64(d))
+	}
+	return d
+}
+
 func (r *WaitNext) Set(addr swarm.Address, tryAfter time.Time, attempts int) {
 
 	r.Lock()
@@ -58,6 +84,19 @@ func (r *WaitNext) Waiting(addr swarm.Address) bool {
 	return ok && time.Now().Before(info.tryAfter)
 }
 
+// TryAfter returns the time after which the peer can be tried again.
+func (r *WaitNext) TryAfter(addr swarm.Address) time.Time {
+
+	r.Lock()
+	defer r.Unlock()
+
+	if info, ok := r.next[addr.ByteString()]; ok {
+		return info.tryAfter
+	}
+
+	return time.Time{}
+}
+
 func (r *WaitNext) Attempts(addr swarm.Address) int {
 
 	r.Lock()
@@ -77,3 +116,40 @@ func (r *WaitNext) Remove(addr swarm.Address) {
 
 	delete(r.next, addr.ByteString())
 }
+
+// RecordFlap records that the peer disconnected at the given time and
+// returns the number of its disconnects within the window ending at that time.
+// The flaps of the other peers which all fell out of the window are evicted.
+func (r *WaitNext) RecordFlap(addr swarm.Address, t time.Time, window time.Duration) int {
+
+	r.Lock()
+	defer r.Unlock()
+
+	for key, flaps := range r.flaps {
+		if t.Sub(flaps[len(flaps)-1]) >= window {
+			delete(r.flaps, key)
+		}
+	}
+
+	key := addr.ByteString()
+	flaps := r.flaps[key][:0]
+	for _, f := range r.flaps[key] {
+		if t.Sub(f) < window {
+			flaps = append(flaps, f)
+		}
+	}
+	flaps = append(flaps, t)
+	r.flaps[key] = flaps
+
+	return len(flaps)
+}
+
+// Forget removes all the information about the peer, including the recorded flaps.
+func (r *WaitNext) Forget(addr swarm.Address) {
+
+	r.Lock()
+	defer r.Unlock()
+
+	delete(r.next, addr.ByteString())
+	delete(r.flaps, addr.ByteString())
+}
@@ -53,3 +53,94 @@ func TestSet(t *testing.T) {
 		t.Fatalf("want 2, got %d", attempts)
 	}
 }
+
+func TestBackoffDelay(t *testing.T) {
+	t.Parallel()
+
+	b := waitnext.Backoff{Base: time.Second, Max: 10 * time.Second}
+
+	for _, tc := range []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{50, 10 * time.Second},
+	} {
+		if got := b.Delay(tc.failures); got != tc.want {
+			t.Fatalf("failures %d: want %v, got %v", tc.failures, tc.want, got)
+		}
+	}
+
+	b.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := b.Delay(3); got < 4*time.Second || got >= 6*time.Second {
+			t.Fatalf("delay %v out of the jitter range", got)
+		}
+	}
+}
+
+func TestRecordFlap(t *testing.T) {
+	t.Parallel()
+
+	waitNext := waitnext.New()
+
+	addr := swarm.RandAddress(t)
+	now := time.Now()
+
+	if n := waitNext.RecordFlap(addr, now, time.Minute); n != 1 {
+		t.Fatalf("want 1, got %d", n)
+	}
+	if n := waitNext.RecordFlap(addr, now.Add(30*time.Second), time.Minute); n != 2 {
+		t.Fatalf("want 2, got %d", n)
+	}
+
+	// flaps survive the removal of the retry information
+	waitNext.Remove(addr)
+	if n := waitNext.RecordFlap(addr, now.Add(50*time.Second), time.Minute); n != 3 {
+		t.Fatalf("want 3, got %d", n)
+	}
+
+	// the first flap falls out of the window
+	if n := waitNext.RecordFlap(addr, now.Add(70*time.Second), time.Minute); n != 3 {
+		t.Fatalf("want 3, got %d", n)
+	}
+
+	waitNext.Forget(addr)
+	if n := waitNext.RecordFlap(addr, now.Add(80*time.Second), time.Minute); n != 1 {
+		t.Fatalf("want 1, got %d", n)
+	}
+}
+
+func TestRecordFlapEviction(t *testing.T) {
+	t.Parallel()
+
+	waitNext := waitnext.New()
+
+	now := time.Now()
+	addr1, addr2, addr3 := swarm.RandAddress(t), swarm.RandAddress(t), swarm.RandAddress(t)
+
+	waitNext.RecordFlap(addr1, now, time.Minute)
+	waitNext.RecordFlap(addr2, now.Add(30*time.Second), time.Minute)
+	if n := waitNext.FlappingPeers(); n != 2 {
+		t.Fatalf("want 2 flapping peers, got %d", n)
+	}
+
+	// the flaps of the first peer fall out of the window
+	waitNext.RecordFlap(addr3, now.Add(time.Minute), time.Minute)
+	if n := waitNext.FlappingPeers(); n != 2 {
+		t.Fatalf("want 2 flapping peers, got %d", n)
+	}
+
+	// only the peer flapping now is kept
+	if n := waitNext.RecordFlap(addr1, now.Add(3*time.Minute), time.Minute); n != 1 {
+		t.Fatalf("want 1, got %d", n)
+	}
+	if n := waitNext.FlappingPeers(); n != 1 {
+		t.Fatalf("want 1 flapping peer, got %d", n)
+	}
+}
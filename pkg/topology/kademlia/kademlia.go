@@ -11,7 +11,9 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"math/rand"
 	"net"
+	"sort"
 	"sync"
 	"syscall"
 	"time"
@@ -21,6 +23,7 @@ import (
 	"github.com/ethersphere/bee/pkg/discovery"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/dnsdisc"
 	"github.com/ethersphere/bee/pkg/pingpong"
 	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -29,6 +32,7 @@ import (
 	"github.com/ethersphere/bee/pkg/topology/kademlia/internal/waitnext"
 	"github.com/ethersphere/bee/pkg/topology/pslice"
 	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -36,8 +40,10 @@ import (
 const loggerName = "kademlia"
 
 const (
-	maxConnAttempts     = 1 // when there is maxConnAttempts failed connect calls for a given peer it is considered non-connectable
-	maxBootNodeAttempts = 3 // how many attempts to dial to boot-nodes before giving up
+	maxConnAttempts          = 3  // when there is maxConnAttempts failed connect calls for a given peer it is considered non-connectable
+	maxKnownGoodConnAttempts = 6  // same as maxConnAttempts but for peers we have previously been connected to
+	maxBootNodeAttempts      = 3  // how many attempts to dial to boot-nodes before giving up
+	maxKnownGoodPeersDial    = 16 // how many of the best scored previously connected peers to dial before the bootnodes
 
 	addPeerBatchSize = 500
 
@@ -46,20 +52,33 @@ const (
 	// than 15 seconds (empirically verified).
 	peerConnectionAttemptTimeout = 15 * time.Second // timeout for establishing a new connection with peer.
 
+	dnsDiscoveryTimeout = 10 * time.Second // timeout for resolving the bootnode trees
+
+	flapWindow  = 30 * time.Minute // window in which the disconnects of a peer are counted against the flap budget
+	retryJitter = 0.25             // maximal random extension of the retry delay, relative to the delay
+
 	flagTimeout      = 10 * time.Minute // how long before blocking a flagged peer
 	blockDuration    = time.Hour        // how long to blocklist an unresponsive peer for
 	blockWorkerWakup = 30 * time.Second // wake up interval for the blocker worker
 )
 
+// DefaultSaturationPeers and DefaultOverSaturationPeers are the default
+// numbers of the peers in a bin at which the bin is saturated and at which
+// the new peers are no longer accepted to it.
+const (
+	DefaultSaturationPeers     = 8
+	DefaultOverSaturationPeers = 20
+)
+
 // Default option values
 const (
 	defaultBitSuffixLength             = 4 // the number of bits used to create pseudo addresses for balancing
 	defaultLowWaterMark                = 3 // the number of peers in consecutive deepest bins that constitute as nearest neighbours
-	defaultSaturationPeers             = 8
-	defaultOverSaturationPeers         = 20
 	defaultBootNodeOverSaturationPeers = 20
 	defaultShortRetry                  = 30 * time.Second
 	defaultTimeToRetry                 = 2 * defaultShortRetry
+	defaultMaxRetryBackoff             = 30 * time.Minute // upper bound of the exponential retry backoff
+	defaultFlapBudget                  = 3                // disconnects within the flap window before the peer is demoted
 	defaultBroadcastBinSize            = 4
 	defaultPeerPingPollTime            = 5 * time.Minute  // how often to ping a peer
 	defaultPingTimeout                 = 10 * time.Second // timeout for the ping response
@@ -86,6 +105,8 @@ var noopSanctionedPeerFn = func(_ swarm.Address) bool { return false }
 type Options struct {
 	SaturationFunc   binSaturationFunc
 	Bootnodes        []ma.Multiaddr
+	BootnodeTrees    []string         // DNS discovery tree URLs to resolve additional bootnodes from
+	DNSResolver      dnsdisc.Resolver // resolver for the BootnodeTrees, system resolver if nil
 	BootnodeMode     bool
 	PruneFunc        pruneFunc
 	StaticNodes      []swarm.Address
@@ -95,6 +116,8 @@ type Options struct {
 	BitSuffixLength             *int
 	TimeToRetry                 *time.Duration
 	ShortRetry                  *time.Duration
+	MaxRetryBackoff             *time.Duration
+	FlapBudget                  *int
 	SaturationPeers             *int
 	OverSaturationPeers         *int
 	BootnodeOverSaturationPeers *int
@@ -107,6 +130,8 @@ type Options struct {
 type kadOptions struct {
 	SaturationFunc   binSaturationFunc
 	Bootnodes        []ma.Multiaddr
+	BootnodeTrees    []string
+	DNSResolver      dnsdisc.Resolver
 	BootnodeMode     bool
 	PruneFunc        pruneFunc
 	StaticNodes      []swarm.Address
@@ -115,6 +140,8 @@ type kadOptions struct {
 
 	TimeToRetry                 time.Duration
 	ShortRetry                  time.Duration
+	MaxRetryBackoff             time.Duration
+	FlapBudget                  int
 	PeerPingPollTime            time.Duration
 	PeerPingTimeout             time.Duration
 	BitSuffixLength             int // additional depth of common prefix for bin
@@ -130,6 +157,8 @@ func newKadOptions(o Options) kadOptions {
 		// copy values
 		SaturationFunc:   o.SaturationFunc,
 		Bootnodes:        o.Bootnodes,
+		BootnodeTrees:    o.BootnodeTrees,
+		DNSResolver:      o.DNSResolver,
 		BootnodeMode:     o.BootnodeMode,
 		PruneFunc:        o.PruneFunc,
 		StaticNodes:      o.StaticNodes,
@@ -138,11 +167,13 @@ func newKadOptions(o Options) kadOptions {
 		// copy or use default
 		TimeToRetry:                 defaultValDuration(o.TimeToRetry, defaultTimeToRetry),
 		ShortRetry:                  defaultValDuration(o.ShortRetry, defaultShortRetry),
+		MaxRetryBackoff:             defaultValDuration(o.MaxRetryBackoff, defaultMaxRetryBackoff),
+		FlapBudget:                  defaultValInt(o.FlapBudget, defaultFlapBudget),
 		PeerPingPollTime:            defaultValDuration(o.PeerPingPollTime, defaultPeerPingPollTime),
 		PeerPingTimeout:             defaultValDuration(o.PeerPingPollTime, defaultPingTimeout),
 		BitSuffixLength:             defaultValInt(o.BitSuffixLength, defaultBitSuffixLength),
-		SaturationPeers:             defaultValInt(o.SaturationPeers, defaultSaturationPeers),
-		OverSaturationPeers:         defaultValInt(o.OverSaturationPeers, defaultOverSaturationPeers),
+		SaturationPeers:             defaultValInt(o.SaturationPeers, DefaultSaturationPeers),
+		OverSaturationPeers:         defaultValInt(o.OverSaturationPeers, DefaultOverSaturationPeers),
 		BootnodeOverSaturationPeers: defaultValInt(o.BootnodeOverSaturationPeers, defaultBootNodeOverSaturationPeers),
 		BroadcastBinSize:            defaultValInt(o.BroadcastBinSize, defaultBroadcastBinSize),
 		LowWaterMark:                defaultValInt(o.LowWaterMark, defaultLowWaterMark),
@@ -201,6 +232,7 @@ type Kad struct {
 	done              chan struct{} // signal that `manage` has quit
 	wg                sync.WaitGroup
 	waitNext          *waitnext.WaitNext
+	backoff           waitnext.Backoff
 	metrics           metrics
 	pinger            pingpong.Interface
 	staticPeer        staticPeerFunc
@@ -242,6 +274,7 @@ func New(
 		knownPeers:        pslice.New(int(swarm.MaxBins), base),
 		manageC:           make(chan struct{}, 1),
 		waitNext:          waitnext.New(),
+		backoff:           waitnext.Backoff{Base: opt.TimeToRetry, Max: opt.MaxRetryBackoff, Jitter: retryJitter},
 		logger:            logger.WithName(loggerName).Register(),
 		bootnode:          opt.BootnodeMode,
 		collector:         imc,
@@ -296,6 +329,7 @@ func (k *Kad) connectBalanced(wg *sync.WaitGroup, peerConnChan chan<- *peerConnI
 	}
 
 	depth := k.NeighborhoodDepth()
+	ss := k.collector.Snapshot(time.Now())
 
 	for i := range k.commonBinPrefixes {
 
@@ -313,14 +347,15 @@ func (k *Kad) connectBalanced(wg *sync.WaitGroup, peerConnChan chan<- *peerConnI
 		for j := range k.commonBinPrefixes[i] {
 			pseudoAddr := k.commonBinPrefixes[i][j]
 
-			// Connect to closest known peer which we haven't tried connecting to recently.
+			// Connect to the best scored known peer in the balanced slot
+			// which we haven't tried connecting to recently.
 
 			_, exists := nClosePeerInSlice(binConnectedPeers, pseudoAddr, noopSanctionedPeerFn, uint8(i+k.opt.BitSuffixLength+1))
 			if exists {
 				continue
 			}
 
-			closestKnownPeer, exists := nClosePeerInSlice(binPeers, pseudoAddr, skipPeers, uint8(i+k.opt.BitSuffixLength+1))
+			closestKnownPeer, exists := bestPeerInSlice(binPeers, pseudoAddr, skipPeers, uint8(i+k.opt.BitSuffixLength+1), ss)
 			if !exists {
 				continue
 			}
@@ -434,7 +469,7 @@ func (k *Kad) connectionAttemptsHandler(ctx context.Context, wg *sync.WaitGroup,
 		}
 
 		remove := func(peer *peerConnInfo) {
-			k.waitNext.Remove(peer.addr)
+			k.waitNext.Forget(peer.addr)
 			k.knownPeers.Remove(peer.addr)
 			if err := k.addressBook.Remove(peer.addr); err != nil {
 				k.logger.Debug("could not remove peer from addressbook", "peer_address", peer.addr)
@@ -457,9 +492,16 @@ func (k *Kad) connectionAttemptsHandler(ctx context.Context, wg *sync.WaitGroup,
 			k.logger.Debug("peer still in blocklist", "peer_address", bzzAddr)
 			k.logger.Warning("peer still in blocklist")
 			return
+		case errors.Is(err, p2p.ErrPeerNotAllowed):
+			k.logger.Debug("peer not in allowlist", "peer_overlay_address", peer.addr, "peer_underlay_address", bzzAddr.Underlay)
+			remove(peer)
+			return
 		case err != nil:
 			k.logger.Debug("peer not reachable from kademlia", "peer_address", bzzAddr, "error", err)
-			k.logger.Warning("peer not reachable when attempting to connect")
+			// Warn only about the first failure, the peer is backing off afterwards.
+			if k.waitNext.Attempts(peer.addr) <= 1 {
+				k.logger.Warning("peer not reachable when attempting to connect")
+			}
 			return
 		}
 
@@ -625,6 +667,10 @@ func (k *Kad) manage() {
 			}
 
 			oldDepth := k.NeighborhoodDepth()
+			if k.connectedPeers.Length() == 0 {
+				k.connectKnownGoodPeers(&wg, balanceChan)
+				wg.Wait()
+			}
 			k.connectBalanced(&wg, balanceChan)
 			k.connectNeighbours(&wg, neighbourhoodChan)
 			wg.Wait()
@@ -709,9 +755,14 @@ func (k *Kad) recordPeerLatencies(ctx context.Context) {
 }
 
 // pruneOversaturatedBins disconnects out of depth peers from oversaturated bins
-// while maintaining the balance of the bin and favoring peers with longers connections
+// while maintaining the balance of the bin. From each balanced slot the peer
+// sharing its subnet with the most of the other bin peers is disconnected,
+// and among those the one with the lowest quality score, which favors
+// subnet diverse, low latency peers with longer connections.
 func (k *Kad) pruneOversaturatedBins(depth uint8) {
 
+	ss := k.collector.Snapshot(time.Now())
+
 	for i := range k.commonBinPrefixes {
 
 		if i >= int(depth) {
@@ -724,6 +775,7 @@ func (k *Kad) pruneOversaturatedBins(depth uint8) {
 		}
 
 		binPeers := k.connectedPeers.BinPeers(uint8(i))
+		subnets, subnetPeers := k.peerSubnets(binPeers)
 
 		peersToRemove := binPeersCount - k.opt.OverSaturationPeers
 
@@ -736,28 +788,64 @@ func (k *Kad) pruneOversaturatedBins(depth uint8) {
 				continue
 			}
 
-			var smallestDuration time.Duration
-			var newestPeer swarm.Address
+			var (
+				worstPeer     swarm.Address
+				worstSubnet   int
+				worstScore    float64
+				worstDuration time.Duration
+			)
 			for _, peer := range peers {
-				ss := k.collector.Inspect(peer)
-				if ss == nil {
-					continue
+				var (
+					subnet   = subnetPeers[subnets[peer.ByteString()]]
+					score    float64
+					duration time.Duration
+				)
+				if s, ok := ss[peer.ByteString()]; ok {
+					score = s.Score()
+					duration = s.SessionConnectionDuration
 				}
-				duration := ss.SessionConnectionDuration
-				if smallestDuration == 0 || duration < smallestDuration {
-					smallestDuration = duration
-					newestPeer = peer
+
+				switch {
+				case worstPeer.IsZero(),
+					subnet > worstSubnet,
+					subnet == worstSubnet && score < worstScore,
+					subnet == worstSubnet && score == worstScore && duration < worstDuration:
+					worstPeer, worstSubnet, worstScore, worstDuration = peer, subnet, score, duration
 				}
 			}
-			err := k.p2p.Disconnect(newestPeer, "pruned from oversaturated bin")
+			err := k.p2p.Disconnect(worstPeer, "pruned from oversaturated bin")
 			if err != nil {
 				k.logger.Debug("prune disconnect failed", "error", err)
 			}
+			if key := subnets[worstPeer.ByteString()]; key != "" {
+				subnetPeers[key]--
+			}
 			peersToRemove--
 		}
 	}
 }
 
+// peerSubnets returns the subnets of the given peers underlay addresses
+// and the number of peers in each of the subnets. Peers with no known
+// IP underlay address are not assigned to any subnet.
+func (k *Kad) peerSubnets(peers []swarm.Address) (subnets map[string]string, subnetPeers map[string]int) {
+	subnets = make(map[string]string, len(peers))
+	subnetPeers = make(map[string]int)
+
+	for _, peer := range peers {
+		addr, err := k.addressBook.Get(peer)
+		if err != nil {
+			continue
+		}
+		if key := subnet(addr.Underlay); key != "" {
+			subnets[peer.ByteString()] = key
+			subnetPeers[key]++
+		}
+	}
+
+	return subnets, subnetPeers
+}
+
 func (k *Kad) balancedSlotPeers(pseudoAddr swarm.Address, peers []swarm.Address, po int) []swarm.Address {
 
 	var ret []swarm.Address
@@ -814,6 +902,8 @@ func (k *Kad) Start(_ context.Context) error {
 	return nil
 }
 
+// previouslyConnected returns the peers we have been connected
+// to in the past, ordered by their quality score, best first.
 func (k *Kad) previouslyConnected() []swarm.Address {
 	loggerV1 := k.logger.V(1).Register()
 
@@ -821,27 +911,107 @@ func (k *Kad) previouslyConnected() []swarm.Address {
 	ss := k.collector.Snapshot(now)
 	loggerV1.Debug("metrics snapshot taken", "elapsed", time.Since(now))
 
-	var peers []swarm.Address
+	var (
+		peers  []swarm.Address
+		scores = make(map[string]float64)
+	)
 
 	for addr, p := range ss {
-		if p.ConnectionTotalDuration > 0 {
+		if score := p.Score(); score > 0 {
 			peers = append(peers, swarm.NewAddress([]byte(addr)))
+			scores[addr] = score
 		}
 	}
 
+	sort.SliceStable(peers, func(i, j int) bool {
+		return scores[peers[i].ByteString()] > scores[peers[j].ByteString()]
+	})
+
 	return peers
 }
 
+// connectKnownGoodPeers attempts to connect to the best scored previously
+// connected peers. It is used when there are no connected peers, so that
+// the node rebootstraps from its own persisted peers before the bootnodes
+// are tried, which also allows operation when the bootnodes are unreachable.
+func (k *Kad) connectKnownGoodPeers(wg *sync.WaitGroup, peerConnChan chan<- *peerConnInfo) {
+	sent := 0
+	for _, addr := range k.previouslyConnected() {
+		if sent >= maxKnownGoodPeersDial {
+			return
+		}
+
+		if k.connectedPeers.Exists(addr) || k.waitNext.Waiting(addr) {
+			continue
+		}
+
+		blocklisted, err := k.p2p.Blocklisted(addr)
+		if err != nil {
+			k.logger.Warning("peer blocklist check failed", "error", err)
+		}
+		if blocklisted {
+			continue
+		}
+
+		select {
+		case <-k.quit:
+			return
+		default:
+			wg.Add(1)
+			select {
+			case peerConnChan <- &peerConnInfo{
+				po:   swarm.Proximity(k.base.Bytes(), addr.Bytes()),
+				addr: addr,
+			}:
+				k.metrics.TotalKnownGoodPeersConnectionAttempts.Inc()
+				sent++
+			default:
+				k.notifyManageLoop()
+				wg.Done()
+			}
+		}
+	}
+}
+
+// bootnodes returns the configured bootnodes followed by
+// the bootnodes resolved from the DNS discovery trees.
+func (k *Kad) bootnodes(ctx context.Context) []ma.Multiaddr {
+	if len(k.opt.BootnodeTrees) == 0 {
+		return k.opt.Bootnodes
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dnsDiscoveryTimeout)
+	defer cancel()
+
+	addrs, err := dnsdisc.NewClient(k.opt.DNSResolver).ResolveAll(ctx, k.opt.BootnodeTrees)
+	if err != nil {
+		k.logger.Debug("resolve bootnode trees failed", "error", err)
+		k.logger.Warning("resolve bootnode trees failed")
+		return k.opt.Bootnodes
+	}
+	k.metrics.TotalDNSDiscoveredBootnodes.Add(float64(len(addrs)))
+
+	rand.Shuffle(len(addrs), func(i, j int) {
+		addrs[i], addrs[j] = addrs[j], addrs[i]
+	})
+
+	bootnodes := make([]ma.Multiaddr, 0, len(k.opt.Bootnodes)+len(addrs))
+	bootnodes = append(bootnodes, k.opt.Bootnodes...)
+	return append(bootnodes, addrs...)
+}
+
 func (k *Kad) connectBootNodes(ctx context.Context) {
 	loggerV1 := k.logger.V(1).Register()
 
+	bootnodes := k.bootnodes(ctx)
+
 	var attempts, connected int
-	totalAttempts := maxBootNodeAttempts * len(k.opt.Bootnodes)
+	totalAttempts := maxBootNodeAttempts * len(bootnodes)
 
 	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
-	for _, addr := range k.opt.Bootnodes {
+	for _, addr := range bootnodes {
 		if attempts >= totalAttempts || connected >= 3 {
 			return
 		}
@@ -1000,17 +1170,23 @@ func (k *Kad) connect(ctx context.Context, peer swarm.Address, ma ma.Multiaddr)
 		return err
 	case errors.Is(err, p2p.ErrPeerBlocklisted):
 		return err
+	case errors.Is(err, p2p.ErrPeerNotAllowed):
+		return err
 	case err != nil:
 		k.logger.Debug("could not connect to peer", "peer_address", peer, "error", err)
 
-		retryTime := time.Now().Add(k.opt.TimeToRetry)
-		var e *p2p.ConnectionBackoffError
-		failedAttempts := 0
+		// The failed attempts are kept until the peer is connected or
+		// pruned, so that the retry delay grows with every failure.
+		var (
+			e              *p2p.ConnectionBackoffError
+			retryTime      time.Time
+			failedAttempts = k.waitNext.Attempts(peer)
+		)
 		if errors.As(err, &e) {
 			retryTime = e.TryAfter()
 		} else {
-			failedAttempts = k.waitNext.Attempts(peer)
 			failedAttempts++
+			retryTime = time.Now().Add(k.backoff.Delay(failedAttempts))
 		}
 
 		k.metrics.TotalOutboundConnectionFailedAttempts.Inc()
@@ -1018,8 +1194,16 @@ func (k *Kad) connect(ctx context.Context, peer swarm.Address, ma ma.Multiaddr)
 
 		ss := k.collector.Inspect(peer)
 		quickPrune := (ss == nil || ss.HasAtMaxOneConnectionAttempt()) && isNetworkError(err)
-		if (k.connectedPeers.Length() > 0 && quickPrune) || failedAttempts >= maxConnAttempts {
-			k.waitNext.Remove(peer)
+
+		// Peers we have been connected to in the past are given more
+		// chances before being pruned, as they are likely to come back.
+		maxAttempts := maxConnAttempts
+		if ss != nil && ss.Score() > 0 {
+			maxAttempts = maxKnownGoodConnAttempts
+		}
+
+		if (k.connectedPeers.Length() > 0 && quickPrune) || failedAttempts >= maxAttempts {
+			k.waitNext.Forget(peer)
 			k.knownPeers.Remove(peer)
 			if err := k.addressBook.Remove(peer); err != nil {
 				k.logger.Debug("could not remove peer from addressbook", "peer_address", peer)
@@ -1233,7 +1417,16 @@ func (k *Kad) Disconnected(peer p2p.Peer) {
 
 	k.connectedPeers.Remove(peer.Address)
 
-	k.waitNext.SetTryAfter(peer.Address, time.Now().Add(k.opt.TimeToRetry))
+	now := time.Now()
+	retry := k.opt.TimeToRetry
+	// A peer which keeps disconnecting is demoted by backing
+	// off exponentially with every disconnect over the budget.
+	if flaps := k.waitNext.RecordFlap(peer.Address, now, flapWindow); flaps > k.opt.FlapBudget {
+		retry = k.backoff.Delay(flaps - k.opt.FlapBudget + 1)
+		k.metrics.TotalFlappingPeersDemoted.Inc()
+		k.logger.Debug("flapping peer demoted", "peer_address", peer.Address, "disconnects", flaps, "retry_after", retry)
+	}
+	k.waitNext.SetTryAfter(peer.Address, now.Add(retry))
 
 	k.metrics.TotalInboundDisconnections.Inc()
 	k.collector.Record(peer.Address, im.PeerLogOut(time.Now()))
@@ -1275,6 +1468,35 @@ func nClosePeerInSlice(peers []swarm.Address, addr swarm.Address, spf sanctioned
 	return swarm.ZeroAddress, false
 }
 
+// bestPeerInSlice returns the peer with the highest quality score from
+// the peers which are not sanctioned and are at least at minPO proximity
+// to the given address. Among the equally scored peers the first is returned.
+func bestPeerInSlice(peers []swarm.Address, addr swarm.Address, spf sanctionedPeerFunc, minPO uint8, ss map[string]*im.Snapshot) (swarm.Address, bool) {
+	var (
+		best      = swarm.ZeroAddress
+		bestScore float64
+	)
+	for _, peer := range peers {
+		if spf(peer) {
+			continue
+		}
+
+		if swarm.ExtendedProximity(peer.Bytes(), addr.Bytes()) < minPO {
+			continue
+		}
+
+		var score float64
+		if s, ok := ss[peer.ByteString()]; ok {
+			score = s.Score()
+		}
+		if best.IsZero() || score > bestScore {
+			best, bestScore = peer, score
+		}
+	}
+
+	return best, !best.IsZero()
+}
+
 // ClosestPeer returns the closest peer to a given address.
 func (k *Kad) ClosestPeer(addr swarm.Address, includeSelf bool, filter topology.Filter, skipPeers ...swarm.Address) (swarm.Address, error) {
 	if k.connectedPeers.Length() == 0 {
@@ -1639,6 +1861,19 @@ func createMetricsSnapshotView(ss *im.Snapshot) *topology.MetricSnapshotView {
 	}
 }
 
+// subnet returns the /24 IPv4 or /48 IPv6 subnet of
+// the underlay address, or empty string if it has no IP.
+func subnet(addr ma.Multiaddr) string {
+	ip, err := manet.ToIP(addr)
+	if err != nil {
+		return ""
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return ip.Mask(net.CIDRMask(48, 128)).String()
+}
+
 // isNetworkError is checking various conditions that relate to network problems.
 func isNetworkError(err error) bool {
 	var netOpErr *net.OpError
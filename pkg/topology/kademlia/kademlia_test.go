@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"net"
 	"reflect"
 	"sync"
 	"sync/atomic"
@@ -24,6 +25,7 @@ import (
 	"github.com/ethersphere/bee/pkg/discovery/mock"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/dnsdisc"
 	p2pmock "github.com/ethersphere/bee/pkg/p2p/mock"
 	pingpongmock "github.com/ethersphere/bee/pkg/pingpong/mock"
 	"github.com/ethersphere/bee/pkg/shed"
@@ -32,6 +34,7 @@ import (
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/topology"
 	"github.com/ethersphere/bee/pkg/topology/kademlia"
+	im "github.com/ethersphere/bee/pkg/topology/kademlia/internal/metrics"
 	"github.com/ethersphere/bee/pkg/topology/pslice"
 	"github.com/ethersphere/bee/pkg/util/testutil"
 )
@@ -841,10 +844,26 @@ func TestAddressBookPrune(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// add non connectable peer, check connection and failed connection counters
+	// add non connectable peer, it is retried after the growing
+	// backoff until all its connect attempts failed
 	kad.AddPeers(nonConnPeer.Overlay)
+	for i := int32(1); i <= kademlia.MaxConnAttempts; i++ {
+		err := spinlock.Wait(spinLockWaitTime, func() bool {
+			kad.AddPeers() // trigger the retry once the backoff is over
+			return atomic.LoadInt32(&failedConns) == i
+		})
+		if err != nil {
+			t.Fatalf("timed out waiting for failed connect attempt %d", i)
+		}
+		if i == kademlia.MaxConnAttempts {
+			break
+		}
+		if _, err := ab.Get(nonConnPeer.Overlay); err != nil {
+			t.Fatalf("peer pruned after %d failed connect attempts: %v", i, err)
+		}
+	}
 	waitCounter(t, &conns, 0)
-	waitCounter(t, &failedConns, 1)
+	waitCounter(t, &failedConns, kademlia.MaxConnAttempts)
 
 	_, err = ab.Get(nonConnPeer.Overlay)
 	if !errors.Is(err, addressbook.ErrNotFound) {
@@ -888,6 +907,69 @@ func TestAddressBookPrune(t *testing.T) {
 	}
 }
 
+// TestConnectBackoff tests that the delay of the retry of the failing peer
+// grows with every failed connect attempt until the peer is pruned.
+func TestConnectBackoff(t *testing.T) {
+	t.Parallel()
+
+	var (
+		conns, failedConns       int32 // how many connect calls were made to the p2p mock
+		base, kad, ab, _, signer = newTestKademlia(t, &conns, &failedConns, kademlia.Options{
+			TimeToRetry: ptrDuration(time.Minute),
+		})
+	)
+
+	if err := kad.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	testutil.CleanupCloser(t, kad)
+
+	// the peer is not known to kademlia, so that only the test connects to it
+	peer, err := bzz.NewAddress(signer, nonConnectableAddress, swarm.RandAddressAt(t, base, 1), 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ab.Put(peer.Overlay, *peer); err != nil {
+		t.Fatal(err)
+	}
+
+	// the backoff grows only if the failing peer is retried more than once
+	if kademlia.MaxConnAttempts < 3 {
+		t.Fatalf("failing peer pruned after %d connect attempts", kademlia.MaxConnAttempts)
+	}
+
+	var last time.Duration
+	for i := 1; i <= kademlia.MaxConnAttempts; i++ {
+		if err := kademlia.ConnectFunc(kad)(context.Background(), peer.Overlay, peer.Underlay); err == nil {
+			t.Fatal("connected to non connectable peer")
+		}
+		waitCounter(t, &failedConns, 1)
+		attempts, tryAfter := kad.ConnectRetry(peer.Overlay)
+
+		if i == kademlia.MaxConnAttempts {
+			if attempts != 0 {
+				t.Fatalf("got %d failed attempts of pruned peer", attempts)
+			}
+			if _, err := ab.Get(peer.Overlay); !errors.Is(err, addressbook.ErrNotFound) {
+				t.Fatalf("got error %v, want %v", err, addressbook.ErrNotFound)
+			}
+			break
+		}
+
+		if attempts != i {
+			t.Fatalf("got %d failed attempts, want %d", attempts, i)
+		}
+		delay := time.Until(tryAfter)
+		if delay <= last {
+			t.Fatalf("attempt %d: retry delay %v did not grow from %v", i, delay, last)
+		}
+		last = delay
+		if _, err := ab.Get(peer.Overlay); err != nil {
+			t.Fatalf("peer pruned after %d failed connect attempts: %v", i, err)
+		}
+	}
+}
+
 // test pruning addressbook after successive failed connect attempts
 func TestAddressBookQuickPrune(t *testing.T) {
 	t.Parallel()
@@ -1268,6 +1350,118 @@ func TestStart(t *testing.T) {
 		waitCounter(t, &conns, 3)
 		waitCounter(t, &failedConns, 0)
 	})
+
+	t.Run("dns discovery tree", func(t *testing.T) {
+		t.Parallel()
+
+		const domain = "nodes.example.org"
+
+		pk, _ := beeCrypto.GenerateSecp256k1Key()
+		tree, err := dnsdisc.MakeTree(1, bootnodes, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		url, err := tree.Sign(beeCrypto.NewDefaultSigner(pk), domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records, err := tree.Records(domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var conns, failedConns int32 // how many connect calls were made to the p2p mock
+		_, kad, _, _, _ := newTestKademlia(t, &conns, &failedConns, kademlia.Options{
+			BootnodeTrees: []string{url},
+			DNSResolver:   txtResolver(records),
+		})
+
+		if err := kad.Start(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		testutil.CleanupCloser(t, kad)
+
+		waitCounter(t, &conns, 3)
+		waitCounter(t, &failedConns, 0)
+	})
+}
+
+type txtResolver map[string]string
+
+func (r txtResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	if txt, ok := r[name]; ok {
+		return []string{txt}, nil
+	}
+	return nil, nil
+}
+
+// TestStartKnownGoodPeers tests that peers we have been connected to in a
+// previous session are rebootstrapped from the persisted metrics and that
+// they are not pruned from the address book after a single failed dial.
+func TestStartKnownGoodPeers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		conns, failedConns int32 // how many connect calls were made to the p2p mock
+		base               = swarm.RandAddress(t)
+		peer               = swarm.RandAddress(t)
+		pk, _              = beeCrypto.GenerateSecp256k1Key()
+		signer             = beeCrypto.NewDefaultSigner(pk)
+		ab                 = addressbook.New(mockstate.NewStateStore())
+		ppm                = pingpongmock.New(func(_ context.Context, _ swarm.Address, _ ...string) (time.Duration, error) {
+			return 0, nil
+		})
+	)
+
+	metricsDB, err := shed.NewDB("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.CleanupCloser(t, metricsDB)
+
+	bzzAddr, err := bzz.NewAddress(signer, nonConnectableAddress, peer, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ab.Put(peer, *bzzAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	// first session, the peer dials in and the
+	// connection metrics are persisted on close
+	kad, err := kademlia.New(base, ab, mock.NewDiscovery(), p2pMock(t, ab, signer, nil, nil), ppm, metricsDB, log.Noop, kademlia.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := kad.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := kad.Connected(context.Background(), p2p.Peer{Address: peer}, false); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := kad.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// second session, the peer is dialed first but is no longer reachable
+	kad, err = kademlia.New(base, ab, mock.NewDiscovery(), p2pMock(t, ab, signer, &conns, &failedConns), ppm, metricsDB, log.Noop, kademlia.Options{
+		TimeToRetry: ptrDuration(time.Minute),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := kad.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	testutil.CleanupCloser(t, kad)
+
+	waitCounter(t, &failedConns, 1)
+	waitCounter(t, &conns, 0)
+
+	if _, err := ab.Get(peer); err != nil {
+		t.Fatalf("known good peer pruned from the address book: %v", err)
+	}
 }
 
 func TestOutofDepthPrune(t *testing.T) {
@@ -1367,6 +1561,64 @@ func TestOutofDepthPrune(t *testing.T) {
 	waitBalanced(t, kad, 1)
 }
 
+func TestSubnet(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		addr string
+		want string
+	}{
+		{addr: "/ip4/10.1.2.3/tcp/1634", want: "10.1.2.0"},
+		{addr: "/ip4/10.1.2.200/udp/1634/quic", want: "10.1.2.0"},
+		{addr: "/ip6/2001:db8:1:2::1/tcp/1634", want: "2001:db8:1::"},
+		{addr: "/dns/example.org/tcp/1634", want: ""},
+	} {
+		addr, err := ma.NewMultiaddr(tc.addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have := kademlia.Subnet(addr); have != tc.want {
+			t.Fatalf("subnet of %s: have %q want %q", tc.addr, have, tc.want)
+		}
+	}
+}
+
+func TestBestPeerInSlice(t *testing.T) {
+	t.Parallel()
+
+	var (
+		pivot = swarm.MustParseHexAddress("f000000000000000000000000000000000000000000000000000000000000000")
+		far   = swarm.MustParseHexAddress("0000000000000000000000000000000000000000000000000000000000000000")
+		p1    = swarm.MustParseHexAddress("f100000000000000000000000000000000000000000000000000000000000000")
+		p2    = swarm.MustParseHexAddress("f200000000000000000000000000000000000000000000000000000000000000")
+		p3    = swarm.MustParseHexAddress("f300000000000000000000000000000000000000000000000000000000000000")
+		peers = []swarm.Address{far, p1, p2, p3}
+		none  = func(swarm.Address) bool { return false }
+	)
+
+	ss := map[string]*im.Snapshot{
+		far.ByteString(): {ConnectionTotalDuration: 10 * time.Hour},
+		p2.ByteString():  {ConnectionTotalDuration: time.Hour, LatencyEWMA: time.Second},
+		p3.ByteString():  {ConnectionTotalDuration: time.Hour, LatencyEWMA: time.Millisecond},
+	}
+
+	best, ok := kademlia.BestPeerInSlice(peers, pivot, none, 4, ss)
+	if !ok || !best.Equal(p3) {
+		t.Fatalf("want best peer %s, got %s", p3, best)
+	}
+
+	// with no scores the first peer in the slot is selected
+	best, ok = kademlia.BestPeerInSlice(peers, pivot, none, 4, nil)
+	if !ok || !best.Equal(p1) {
+		t.Fatalf("want best peer %s, got %s", p1, best)
+	}
+
+	sanctioned := func(a swarm.Address) bool { return !a.Equal(far) }
+	if _, ok := kademlia.BestPeerInSlice(peers, pivot, sanctioned, 4, ss); ok {
+		t.Fatal("want no peer")
+	}
+}
+
 // TestLatency tests that kademlia polls peers for latency.
 func TestLatency(t *testing.T) {
 	t.Parallel()
@@ -1867,7 +2119,7 @@ func p2pMock(t *testing.T, ab addressbook.Interface, signer beeCrypto.Signer, co
 		p2pmock.WithConnectFunc(func(ctx context.Context, addr ma.Multiaddr) (*bzz.Address, error) {
 			if addr.Equal(nonConnectableAddress) {
 				_ = atomic.AddInt32(failedCounter, 1)
-				return nil, errors.New("non reachable node")
+				return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("non reachable node")}
 			}
 			if counter != nil {
 				_ = atomic.AddInt32(counter, 1)
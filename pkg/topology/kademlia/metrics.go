@@ -26,6 +26,9 @@ type metrics struct {
 	TotalOutboundConnectionAttempts       prometheus.Counter
 	TotalOutboundConnectionFailedAttempts prometheus.Counter
 	TotalBootNodesConnectionAttempts      prometheus.Counter
+	TotalKnownGoodPeersConnectionAttempts prometheus.Counter
+	TotalDNSDiscoveredBootnodes           prometheus.Counter
+	TotalFlappingPeersDemoted             prometheus.Counter
 	StartAddAddressBookOverlaysTime       prometheus.Histogram
 	PeerLatencyEWMA                       prometheus.Histogram
 	Flag                                  prometheus.Counter
@@ -130,6 +133,24 @@ func newMetrics() metrics {
 			Name:      "total_bootnodes_connection_attempts",
 			Help:      "Total boot-nodes connection attempts made.",
 		}),
+		TotalKnownGoodPeersConnectionAttempts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_known_good_peers_connection_attempts",
+			Help:      "Total connection attempts made to previously connected peers while rebootstrapping.",
+		}),
+		TotalDNSDiscoveredBootnodes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_dns_discovered_bootnodes",
+			Help:      "Total bootnode addresses resolved from DNS discovery trees.",
+		}),
+		TotalFlappingPeersDemoted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_flapping_peers_demoted",
+			Help:      "Total disconnects over the flap budget after which the peer was demoted.",
+		}),
 		StartAddAddressBookOverlaysTime: prometheus.NewHistogram(prometheus.HistogramOpts{
 			Namespace: m.Namespace,
 			Subsystem: subsystem,
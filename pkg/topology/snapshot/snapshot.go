@@ -0,0 +1,148 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package snapshot periodically dumps the topology graph
+// to the disk for the offline analysis.
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/topology"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "topology-snapshot"
+
+const (
+	filePrefix = "topology-"
+	fileSuffix = ".json"
+
+	// DefaultKeep is the default number of the most recent snapshots kept on the disk.
+	DefaultKeep = 144
+)
+
+// SourceFunc returns the current topology parameters.
+type SourceFunc func() *topology.KadParams
+
+// Dumper writes the topology graph snapshots to a directory.
+type Dumper struct {
+	dir      string
+	interval time.Duration
+	keep     int
+	source   SourceFunc
+	logger   log.Logger
+	quit     chan struct{}
+	done     chan struct{}
+}
+
+// New creates and starts a new dumper which writes a snapshot to the given
+// directory every interval and removes all but the keep most recent snapshots.
+func New(dir string, interval time.Duration, keep int, source SourceFunc, logger log.Logger) (*Dumper, error) {
+	if interval <= 0 {
+		return nil, errors.New("snapshot interval must be positive")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create snapshot directory: %w", err)
+	}
+	if keep <= 0 {
+		keep = DefaultKeep
+	}
+	d := &Dumper{
+		dir:      dir,
+		interval: interval,
+		keep:     keep,
+		source:   source,
+		logger:   logger.WithName(loggerName).Register(),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go d.run()
+	return d, nil
+}
+
+func (d *Dumper) run() {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.quit:
+			return
+		case <-ticker.C:
+			if err := d.Dump(); err != nil {
+				d.logger.Error(err, "topology snapshot failed")
+			}
+		}
+	}
+}
+
+// Dump writes a single snapshot and prunes the old ones.
+func (d *Dumper) Dump() error {
+	graph := topology.NewGraph(d.source())
+
+	b, err := json.Marshal(graph)
+	if err != nil {
+		return fmt.Errorf("marshal graph: %w", err)
+	}
+
+	name := filePrefix + graph.Timestamp.UTC().Format("20060102T150405.000Z") + fileSuffix
+	tmp := filepath.Join(d.dir, name+".tmp")
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(d.dir, name)); err != nil {
+		return fmt.Errorf("rename snapshot: %w", err)
+	}
+
+	return d.prune()
+}
+
+// prune removes all but the most recent snapshots.
+func (d *Dumper) prune() error {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return fmt.Errorf("read snapshot directory: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if n := e.Name(); strings.HasPrefix(n, filePrefix) && strings.HasSuffix(n, fileSuffix) {
+			names = append(names, n)
+		}
+	}
+	if len(names) <= d.keep {
+		return nil
+	}
+
+	// file names are timestamp ordered
+	sort.Strings(names)
+	for _, n := range names[:len(names)-d.keep] {
+		if err := os.Remove(filepath.Join(d.dir, n)); err != nil {
+			return fmt.Errorf("remove snapshot: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close stops the dumper.
+func (d *Dumper) Close() error {
+	close(d.quit)
+	select {
+	case <-d.done:
+	case <-time.After(5 * time.Second):
+		return errors.New("topology snapshot dumper: timeout")
+	}
+	return nil
+}
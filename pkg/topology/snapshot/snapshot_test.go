@@ -0,0 +1,69 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package snapshot_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+	"github.com/ethersphere/bee/pkg/topology/snapshot"
+	"github.com/ethersphere/bee/pkg/util/testutil"
+)
+
+func TestDump(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir  = t.TempDir()
+		base = swarm.RandAddress(t)
+		now  = time.Now()
+	)
+
+	source := func() *topology.KadParams {
+		now = now.Add(time.Second)
+		return &topology.KadParams{Base: base.String(), Timestamp: now}
+	}
+
+	d, err := snapshot.New(dir, time.Hour, 2, source, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.CleanupCloser(t, d)
+
+	for i := 0; i < 3; i++ {
+		if err := d.Dump(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "topology-*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("want 2 snapshots, got %d", len(files))
+	}
+
+	b, err := os.ReadFile(files[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g topology.Graph
+	if err := json.Unmarshal(b, &g); err != nil {
+		t.Fatal(err)
+	}
+	if !g.Timestamp.Equal(now) {
+		t.Fatalf("want latest snapshot at %s, got %s", now, g.Timestamp)
+	}
+	if len(g.Nodes) != 1 || g.Nodes[0].ID != base.String() {
+		t.Fatalf("unexpected graph nodes %+v", g.Nodes)
+	}
+}
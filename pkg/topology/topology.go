@@ -153,3 +153,13 @@ type SetStorageRadiuser interface {
 type PeersCounter interface {
 	PeersCount(Filter) int
 }
+
+// BinInfos returns the information about all bins ordered by the bin number.
+func (b *KadBins) BinInfos() []BinInfo {
+	return []BinInfo{
+		b.Bin0, b.Bin1, b.Bin2, b.Bin3, b.Bin4, b.Bin5, b.Bin6, b.Bin7,
+		b.Bin8, b.Bin9, b.Bin10, b.Bin11, b.Bin12, b.Bin13, b.Bin14, b.Bin15,
+		b.Bin16, b.Bin17, b.Bin18, b.Bin19, b.Bin20, b.Bin21, b.Bin22, b.Bin23,
+		b.Bin24, b.Bin25, b.Bin26, b.Bin27, b.Bin28, b.Bin29, b.Bin30, b.Bin31,
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/log"
@@ -32,8 +34,16 @@ var (
 // contextKey is used to reference a tracing context span as context value.
 type contextKey struct{}
 
-// LogField is the key in log message field that holds tracing id value.
-const LogField = "traceID"
+// requestIDContextKey is used to reference a request id as context value.
+type requestIDContextKey struct{}
+
+const (
+	// LogField is the key in log message field that holds tracing id value.
+	LogField = "traceID"
+
+	// RequestIDLogField is the key in log message field that holds request id value.
+	RequestIDLogField = "requestID"
+)
 
 const (
 	// TraceContextHeaderName is the http header name used to propagate tracing context.
@@ -41,6 +51,21 @@ const (
 
 	// TraceBaggageHeaderPrefix is the prefix for http headers used to propagate baggage.
 	TraceBaggageHeaderPrefix = "swarmctx-"
+
+	// TraceParentHeaderName is the W3C Trace Context http header name which is
+	// accepted when the tracing context is not propagated in the swarm header.
+	TraceParentHeaderName = "traceparent"
+
+	// RequestIDHeaderName is the http header name of the client supplied
+	// correlation id of the request.
+	RequestIDHeaderName = "X-Request-Id"
+
+	// requestIDBaggageKey is the baggage item key used to propagate the request
+	// id with the tracing span context, also to the other nodes.
+	requestIDBaggageKey = "request-id"
+
+	// requestIDSpanTag is the span tag that holds the request id.
+	requestIDSpanTag = "request.id"
 )
 
 // Tracer connect to a tracing server and handles tracing spans and contexts
@@ -103,8 +128,13 @@ func (t *Tracer) StartSpanFromContext(ctx context.Context, operationName string,
 	} else {
 		span = t.tracer.StartSpan(operationName, opts...)
 	}
+	requestID := RequestIDFromContext(ctx)
+	if requestID != "" {
+		span.SetBaggageItem(requestIDBaggageKey, requestID)
+		span.SetTag(requestIDSpanTag, requestID)
+	}
 	sc := span.Context()
-	return span, loggerWithTraceID(sc, l), WithContext(ctx, sc)
+	return span, loggerWithTraceID(sc, requestID, l), WithContext(ctx, sc)
 }
 
 // AddContextHeader adds a tracing span context to provided p2p Headers from
@@ -188,8 +218,10 @@ func (t *Tracer) AddContextHTTPHeader(ctx context.Context, headers http.Header)
 	return t.tracer.Inject(c, opentracing.HTTPHeaders, carrier)
 }
 
-// FromHTTPHeaders returns tracing span context from HTTP headers. If the tracing
-// span context is not present in go context, ErrContextNotFound is returned.
+// FromHTTPHeaders returns tracing span context from HTTP headers. The W3C
+// traceparent header is used if the swarm tracing header is not present. If the
+// tracing span context is not present in go context, ErrContextNotFound is
+// returned.
 func (t *Tracer) FromHTTPHeaders(headers http.Header) (opentracing.SpanContext, error) {
 	if t == nil {
 		t = noopTracer
@@ -199,6 +231,9 @@ func (t *Tracer) FromHTTPHeaders(headers http.Header) (opentracing.SpanContext,
 	c, err := t.tracer.Extract(opentracing.HTTPHeaders, carrier)
 	if err != nil {
 		if errors.Is(err, opentracing.ErrSpanContextNotFound) {
+			if c, ok := parseTraceParent(headers.Get(TraceParentHeaderName)); ok {
+				return c, nil
+			}
 			return nil, ErrContextNotFound
 		}
 		return nil, err
@@ -238,23 +273,102 @@ func FromContext(ctx context.Context) opentracing.SpanContext {
 	return c
 }
 
-// NewLoggerWithTraceID creates a new log Entry with "traceID" field added if it
-// exists in tracing span context stored from go context.
+// WithRequestID adds the client supplied request id to go context. The tracing
+// spans started from the context propagate the request id as baggage item.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, id)
+}
+
+// RequestIDFromContext returns the request id from go context or from the
+// baggage of the tracing span context stored in it. If the request id is not
+// present, an empty string is returned.
+func RequestIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDContextKey{}).(string); ok && id != "" {
+		return id
+	}
+	return requestIDFromBaggage(FromContext(ctx))
+}
+
+// NewLoggerWithTraceID creates a new log Entry with "traceID" and "requestID"
+// fields added if they exist in go context or the tracing span context stored
+// in it.
 func NewLoggerWithTraceID(ctx context.Context, l log.Logger) log.Logger {
-	return loggerWithTraceID(FromContext(ctx), l)
+	return loggerWithTraceID(FromContext(ctx), RequestIDFromContext(ctx), l)
 }
 
-func loggerWithTraceID(sc opentracing.SpanContext, l log.Logger) log.Logger {
+// TraceID returns the trace ID of the tracing span context. If the span
+// context has no valid trace ID, an empty string is returned.
+func TraceID(sc opentracing.SpanContext) string {
+	jsc, ok := sc.(jaeger.SpanContext)
+	if !ok || !jsc.TraceID().IsValid() {
+		return ""
+	}
+	return jsc.TraceID().String()
+}
+
+func loggerWithTraceID(sc opentracing.SpanContext, requestID string, l log.Logger) log.Logger {
 	if l == nil {
 		return nil
 	}
-	jsc, ok := sc.(jaeger.SpanContext)
-	if !ok {
-		return l
+	if requestID == "" {
+		requestID = requestIDFromBaggage(sc)
+	}
+
+	var fields []interface{}
+	if jsc, ok := sc.(jaeger.SpanContext); ok && jsc.TraceID().IsValid() {
+		fields = append(fields, LogField, jsc.TraceID())
+	}
+	if requestID != "" {
+		fields = append(fields, RequestIDLogField, requestID)
 	}
-	traceID := jsc.TraceID()
-	if !traceID.IsValid() {
+	if len(fields) == 0 {
 		return l
 	}
-	return l.WithValues(LogField, traceID).Build()
+	return l.WithValues(fields...).Build()
+}
+
+func requestIDFromBaggage(sc opentracing.SpanContext) string {
+	if sc == nil {
+		return ""
+	}
+	var id string
+	sc.ForeachBaggageItem(func(k, v string) bool {
+		if k == requestIDBaggageKey {
+			id = v
+			return false
+		}
+		return true
+	})
+	return id
+}
+
+// parseTraceParent parses the value of the W3C traceparent header in the
+// version-traceid-parentid-flags format into the tracing span context.
+func parseTraceParent(v string) (opentracing.SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return nil, false
+	}
+	// the unknown future versions may have more fields, but never the ff version
+	if parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return nil, false
+	}
+	if _, err := strconv.ParseUint(parts[0], 16, 8); err != nil {
+		return nil, false
+	}
+
+	traceID, err := jaeger.TraceIDFromString(parts[1])
+	if err != nil || !traceID.IsValid() {
+		return nil, false
+	}
+	spanID, err := jaeger.SpanIDFromString(parts[2])
+	if err != nil || spanID == 0 {
+		return nil, false
+	}
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return nil, false
+	}
+
+	return jaeger.NewSpanContext(traceID, spanID, 0, flags&1 == 1, nil), true
 }
@@ -8,7 +8,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/ethersphere/bee/pkg/log"
@@ -227,6 +229,147 @@ func TestNewLoggerWithTraceID_nilLogger(t *testing.T) {
 	}
 }
 
+func TestTraceID(t *testing.T) {
+	t.Parallel()
+
+	tracer := newTracer(t)
+
+	span, _, _ := tracer.StartSpanFromContext(context.Background(), "some-operation", nil)
+	defer span.Finish()
+
+	wantTraceID := span.Context().(jaeger.SpanContext).TraceID()
+
+	if got := tracing.TraceID(span.Context()); got != wantTraceID.String() {
+		t.Errorf("got trace id %q, want %q", got, wantTraceID.String())
+	}
+	if got := tracing.TraceID(nil); got != "" {
+		t.Errorf("got trace id %q for nil span context, want empty", got)
+	}
+}
+
+func TestFromHTTPHeaders_traceParent(t *testing.T) {
+	t.Parallel()
+
+	tracer := newTracer(t)
+
+	for _, tc := range []struct {
+		name        string
+		traceParent string
+		wantTraceID string
+	}{
+		{
+			name:        "valid",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:        "future version",
+			traceParent: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:        "invalid version",
+			traceParent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		{
+			name:        "zero trace id",
+			traceParent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		},
+		{
+			name:        "zero parent id",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		},
+		{
+			name:        "malformed",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			headers := make(http.Header)
+			headers.Set(tracing.TraceParentHeaderName, tc.traceParent)
+
+			sc, err := tracer.FromHTTPHeaders(headers)
+			if tc.wantTraceID == "" {
+				if !errors.Is(err, tracing.ErrContextNotFound) {
+					t.Fatalf("got error %v, want %v", err, tracing.ErrContextNotFound)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tracing.TraceID(sc); got != tc.wantTraceID {
+				t.Errorf("got trace id %q, want %q", got, tc.wantTraceID)
+			}
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	tracer := newTracer(t)
+
+	const requestID = "client-request-1"
+
+	ctx := tracing.WithRequestID(context.Background(), requestID)
+	span, _, ctx := tracer.StartSpanFromContext(ctx, "some-operation", nil)
+	defer span.Finish()
+
+	// the request id is propagated to the other
+	// nodes with the span context in p2p headers
+	headers := make(p2p.Headers)
+	if err := tracer.AddContextHeader(ctx, headers); err != nil {
+		t.Fatal(err)
+	}
+	remoteCtx, err := tracer.WithContextFromHeaders(context.Background(), headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tracing.RequestIDFromContext(remoteCtx); got != requestID {
+		t.Fatalf("got request id %q, want %q", got, requestID)
+	}
+
+	buf := new(bytes.Buffer)
+	_, logger, _ := tracer.StartSpanFromContext(remoteCtx, "remote-operation", log.NewLogger("test", log.WithSink(buf), log.WithJSONOutput()))
+
+	logger.Info("msg")
+	data := make(map[string]interface{})
+	if err := json.Unmarshal(buf.Bytes(), &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := data[tracing.RequestIDLogField]; got != requestID {
+		t.Errorf("got log field %q value %v, want %q", tracing.RequestIDLogField, got, requestID)
+	}
+	if got := data[tracing.LogField]; got != tracing.TraceID(span.Context()) {
+		t.Errorf("got log field %q value %v, want %q", tracing.LogField, got, tracing.TraceID(span.Context()))
+	}
+}
+
+func TestNewLoggerWithTraceID_requestIDWithoutSpan(t *testing.T) {
+	t.Parallel()
+
+	buf := new(bytes.Buffer)
+
+	ctx := tracing.WithRequestID(context.Background(), "client-request-2")
+	logger := tracing.NewLoggerWithTraceID(ctx, log.NewLogger("test", log.WithSink(buf), log.WithJSONOutput()))
+
+	logger.Info("msg")
+	data := make(map[string]interface{})
+	if err := json.Unmarshal(buf.Bytes(), &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := data[tracing.RequestIDLogField]; got != "client-request-2" {
+		t.Errorf("got log field %q value %v, want %q", tracing.RequestIDLogField, got, "client-request-2")
+	}
+	if _, ok := data[tracing.LogField]; ok {
+		t.Errorf("unexpected log field %q", tracing.LogField)
+	}
+}
+
 func newTracer(t *testing.T) *tracing.Tracer {
 	t.Helper()
 
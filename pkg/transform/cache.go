@@ -0,0 +1,198 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// ErrNotCached is returned by Cache.Get if there is no cached result.
+var ErrNotCached = errors.New("not cached")
+
+// Cache stores the derived content.
+type Cache interface {
+	// Get returns the result with the key or ErrNotCached.
+	Get(key string) (*Result, error)
+	// Put stores the result under its key.
+	Put(r *Result) error
+}
+
+// DiskCache stores the results in the files of a directory. The total size
+// of the files is bounded, and the least recently used results are removed
+// when it is exceeded.
+type DiskCache struct {
+	dir     string
+	maxSize int64
+
+	mu      sync.Mutex
+	size    int64
+	lru     *list.List // of *diskEntry, the most recently used at the front
+	entries map[string]*list.Element
+}
+
+type diskEntry struct {
+	key  string
+	size int64
+}
+
+// NewDiskCache returns the cache in the directory, which is created if it
+// does not exist. The results which are already in the directory are kept.
+func NewDiskCache(dir string, maxSize int64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &DiskCache{
+		dir:     dir,
+		maxSize: maxSize,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+
+	// the recently modified files are the recently used ones
+	type file struct {
+		key  string
+		size int64
+		mod  int64
+	}
+	var existing []file
+	for _, f := range files {
+		info, err := f.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if filepath.Ext(f.Name()) == ".tmp" {
+			_ = os.Remove(filepath.Join(dir, f.Name()))
+			continue
+		}
+		existing = append(existing, file{key: f.Name(), size: info.Size(), mod: info.ModTime().UnixNano()})
+	}
+	sort.Slice(existing, func(i, j int) bool { return existing[i].mod > existing[j].mod })
+	for _, f := range existing {
+		c.entries[f.key] = c.lru.PushBack(&diskEntry{key: f.key, size: f.size})
+		c.size += f.size
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evict()
+
+	return c, nil
+}
+
+// Get implements Cache.Get method.
+func (c *DiskCache) Get(key string) (*Result, error) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok {
+		c.lru.MoveToFront(e)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil, ErrNotCached
+	}
+
+	f, err := os.Open(c.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			c.remove(key)
+			return nil, ErrNotCached
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	// the file contains the content type line followed by the data
+	r := bufio.NewReader(f)
+	contentType, err := r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("read content type: %w", err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{Key: key, ContentType: contentType[:len(contentType)-1], Data: data}, nil
+}
+
+// Put implements Cache.Put method.
+func (c *DiskCache) Put(r *Result) error {
+	var buf bytes.Buffer
+	buf.WriteString(r.ContentType)
+	buf.WriteByte('\n')
+	buf.Write(r.Data)
+	size := int64(buf.Len())
+	if size > c.maxSize {
+		return nil
+	}
+
+	tmp := c.path(r.Key) + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, c.path(r.Key)); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[r.Key]; ok {
+		c.size -= e.Value.(*diskEntry).size
+		c.lru.Remove(e)
+	}
+	c.entries[r.Key] = c.lru.PushFront(&diskEntry{key: r.Key, size: size})
+	c.size += size
+	c.evict()
+
+	return nil
+}
+
+// evict removes the least recently used results until
+// the size is within the limit. It must be called with
+// the mutex locked.
+func (c *DiskCache) evict() {
+	for c.size > c.maxSize {
+		e := c.lru.Back()
+		if e == nil {
+			return
+		}
+		de := e.Value.(*diskEntry)
+		c.lru.Remove(e)
+		delete(c.entries, de.key)
+		c.size -= de.size
+		_ = os.Remove(c.path(de.key))
+	}
+}
+
+func (c *DiskCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.size -= e.Value.(*diskEntry).size
+		c.lru.Remove(e)
+		delete(c.entries, key)
+	}
+}
+
+func (c *DiskCache) path(key string) string {
+	return filepath.Join(c.dir, key)
+}
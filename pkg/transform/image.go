@@ -0,0 +1,212 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime"
+	"net/url"
+	"strconv"
+)
+
+const (
+	maxImageDimension   = 4096             // the largest requested width or height
+	maxImagePixels      = 64 * 1024 * 1024 // the largest decoded source image
+	defaultImageQuality = 85
+)
+
+// the formats which can be decoded and encoded
+var imageFormats = map[string]string{
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+	"gif":  "image/gif",
+}
+
+// Image resizes and converts the images. It applies to the requests with
+// any of the query parameters:
+//   - w, h: the maximal width and height of the image, which is scaled
+//     down preserving the aspect ratio; the images are not scaled up
+//   - format: the format of the image, one of jpeg, png and gif
+//   - quality: the quality of the jpeg images, from 1 to 100
+//
+// Only the first frame of the animated gif images is kept.
+type Image struct{}
+
+// NewImage returns the image transformer.
+func NewImage() *Image {
+	return &Image{}
+}
+
+// Name implements Transformer.Name method.
+func (*Image) Name() string {
+	return "image"
+}
+
+// Params implements Transformer.Params method.
+func (*Image) Params(contentType string, query url.Values) (Params, bool, error) {
+	if !query.Has("w") && !query.Has("h") && !query.Has("format") && !query.Has("quality") {
+		return nil, false, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false, nil
+	}
+	format := ""
+	for f, t := range imageFormats {
+		if t == mediaType {
+			format = f
+		}
+	}
+	if format == "" {
+		return nil, false, nil
+	}
+
+	p := Params{"w": "0", "h": "0", "format": format}
+	for _, name := range []string{"w", "h"} {
+		v := query.Get(name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > maxImageDimension {
+			return nil, false, fmt.Errorf("%w: %s must be between 0 and %d", ErrInvalidParams, name, maxImageDimension)
+		}
+		p[name] = strconv.Itoa(n)
+	}
+	if v := query.Get("format"); v != "" {
+		if _, ok := imageFormats[v]; !ok {
+			return nil, false, fmt.Errorf("%w: format must be one of jpeg, png and gif", ErrInvalidParams)
+		}
+		p["format"] = v
+	}
+	if p["format"] == "jpeg" {
+		q := defaultImageQuality
+		if v := query.Get("quality"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > 100 {
+				return nil, false, fmt.Errorf("%w: quality must be between 1 and 100", ErrInvalidParams)
+			}
+			q = n
+		}
+		p["quality"] = strconv.Itoa(q)
+	}
+
+	return p, true, nil
+}
+
+// Transform implements Transformer.Transform method.
+func (*Image) Transform(_ context.Context, src []byte, p Params, w io.Writer) (string, error) {
+	// check the size before decoding, so that small
+	// files can not exhaust the memory with huge images
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(src))
+	if err != nil {
+		return "", fmt.Errorf("decode image config: %w", err)
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return "", fmt.Errorf("%w: image of %dx%d pixels", ErrSourceTooLarge, cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(src))
+	if err != nil {
+		return "", fmt.Errorf("decode image: %w", err)
+	}
+
+	maxW, _ := strconv.Atoi(p["w"])
+	maxH, _ := strconv.Atoi(p["h"])
+	img = resize(img, maxW, maxH)
+
+	format := p["format"]
+	switch format {
+	case "jpeg":
+		q, _ := strconv.Atoi(p["quality"])
+		err = jpeg.Encode(w, img, &jpeg.Options{Quality: q})
+	case "png":
+		err = png.Encode(w, img)
+	case "gif":
+		err = gif.Encode(w, img, nil)
+	default:
+		return "", fmt.Errorf("%w: format %q", ErrInvalidParams, format)
+	}
+	if err != nil {
+		return "", fmt.Errorf("encode image: %w", err)
+	}
+	return imageFormats[format], nil
+}
+
+// resize scales the image down to fit into maxW and maxH preserving the
+// aspect ratio. Zero maxW or maxH does not limit that dimension. Every
+// pixel of the result is the average of the source pixels it covers.
+func resize(img image.Image, maxW, maxH int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return img
+	}
+
+	scale := 1.0
+	if maxW > 0 && w > maxW {
+		scale = float64(maxW) / float64(w)
+	}
+	if maxH > 0 && h > maxH {
+		if s := float64(maxH) / float64(h); s < scale {
+			scale = s
+		}
+	}
+	if scale == 1 {
+		return img
+	}
+	dw := int(float64(w)*scale + 0.5)
+	dh := int(float64(h)*scale + 0.5)
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+
+	src := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*h/dh, (y+1)*h/dh
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*w/dw, (x+1)*w/dw
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					// weight the colors by the alpha, so that the
+					// transparent pixels do not darken the edges
+					pa := uint64(src.Pix[i+3])
+					r += uint64(src.Pix[i]) * pa
+					g += uint64(src.Pix[i+1]) * pa
+					bl += uint64(src.Pix[i+2]) * pa
+					a += pa
+					n++
+					i += 4
+				}
+			}
+
+			o := dst.PixOffset(x, y)
+			if a > 0 {
+				dst.Pix[o] = uint8(r / a)
+				dst.Pix[o+1] = uint8(g / a)
+				dst.Pix[o+2] = uint8(bl / a)
+			}
+			dst.Pix[o+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
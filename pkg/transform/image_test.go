@@ -0,0 +1,138 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"net/url"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/transform"
+)
+
+func TestImage(t *testing.T) {
+	t.Parallel()
+
+	src := image.NewNRGBA(image.Rect(0, 0, 100, 50))
+	for y := 0; y < 50; y++ {
+		for x := 0; x < 100; x++ {
+			src.Set(x, y, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+
+	tr := transform.NewImage()
+
+	for _, tc := range []struct {
+		name        string
+		contentType string
+		query       url.Values
+		apply       bool
+		err         error
+		width       int
+		height      int
+		format      string
+	}{
+		{
+			name:        "no params",
+			contentType: "image/png",
+			query:       url.Values{},
+		},
+		{
+			name:        "not an image",
+			contentType: "text/html",
+			query:       url.Values{"w": {"20"}},
+		},
+		{
+			name:        "width",
+			contentType: "image/png",
+			query:       url.Values{"w": {"20"}},
+			apply:       true,
+			width:       20,
+			height:      10,
+			format:      "png",
+		},
+		{
+			name:        "height and format",
+			contentType: "image/png",
+			query:       url.Values{"h": {"10"}, "format": {"jpeg"}},
+			apply:       true,
+			width:       20,
+			height:      10,
+			format:      "jpeg",
+		},
+		{
+			name:        "no upscaling",
+			contentType: "image/png",
+			query:       url.Values{"w": {"1000"}, "format": {"gif"}},
+			apply:       true,
+			width:       100,
+			height:      50,
+			format:      "gif",
+		},
+		{
+			name:        "invalid width",
+			contentType: "image/png",
+			query:       url.Values{"w": {"-1"}},
+			err:         transform.ErrInvalidParams,
+		},
+		{
+			name:        "unsupported format",
+			contentType: "image/png",
+			query:       url.Values{"format": {"webp"}},
+			err:         transform.ErrInvalidParams,
+		},
+		{
+			name:        "invalid quality",
+			contentType: "image/jpeg",
+			query:       url.Values{"quality": {"101"}},
+			err:         transform.ErrInvalidParams,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, ok, err := tr.Params(tc.contentType, tc.query)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("got error %v, want %v", err, tc.err)
+			}
+			if ok != tc.apply {
+				t.Fatalf("got applies %t, want %t", ok, tc.apply)
+			}
+			if !ok {
+				return
+			}
+
+			var out bytes.Buffer
+			contentType, err := tr.Transform(context.Background(), buf.Bytes(), p, &out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := "image/" + tc.format; contentType != want {
+				t.Fatalf("got content type %q, want %q", contentType, want)
+			}
+
+			img, format, err := image.Decode(&out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != tc.format {
+				t.Fatalf("got format %q, want %q", format, tc.format)
+			}
+			if b := img.Bounds(); b.Dx() != tc.width || b.Dy() != tc.height {
+				t.Fatalf("got size %dx%d, want %dx%d", b.Dx(), b.Dy(), tc.width, tc.height)
+			}
+		})
+	}
+}
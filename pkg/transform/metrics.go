@@ -0,0 +1,55 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	CacheHits   *prometheus.CounterVec
+	CacheMisses *prometheus.CounterVec
+	Errors      *prometheus.CounterVec
+}
+
+func newMetrics() metrics {
+	subsystem := "transform"
+
+	return metrics{
+		CacheHits: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "cache_hits_total",
+				Help:      "Total number of the derived contents served from the cache.",
+			},
+			[]string{"transformer"},
+		),
+		CacheMisses: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "cache_misses_total",
+				Help:      "Total number of the derived contents not found in the cache.",
+			},
+			[]string{"transformer"},
+		),
+		Errors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "errors_total",
+				Help:      "Total number of the failed transformations.",
+			},
+			[]string{"transformer"},
+		),
+	}
+}
+
+// Metrics returns the prometheus collectors of the service.
+func (s *Service) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(s.metrics)
+}
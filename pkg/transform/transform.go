@@ -0,0 +1,203 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package transform provides the extension point for the on-the-fly
+// transformations of the downloaded content, such as the thumbnails of
+// the images, which are requested by the query parameters. The derived
+// content is cached locally under a key which is determined by the
+// reference, the transformer and its parameters.
+package transform
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultMaxSourceSize is the default size of the largest
+	// source content which is transformed.
+	DefaultMaxSourceSize = 32 * 1024 * 1024
+
+	// keyVersion is the version of the cache keys, which is changed
+	// when the derived content of the same parameters changes.
+	keyVersion = "v1"
+)
+
+var (
+	// ErrInvalidParams is returned if the query parameters
+	// of the transformation are not valid.
+	ErrInvalidParams = errors.New("invalid transform parameters")
+	// ErrSourceTooLarge is returned if the source content is too
+	// large to be transformed.
+	ErrSourceTooLarge = errors.New("source too large to transform")
+)
+
+// Params are the normalized parameters of a transformation. The same
+// derived content must be produced for the same parameters.
+type Params map[string]string
+
+// Transformer derives a representation of the content.
+type Transformer interface {
+	// Name is the unique name of the transformer, which is a part of
+	// the cache keys.
+	Name() string
+	// Params returns the normalized parameters of the transformation
+	// requested by the query for the content of the given type. It
+	// returns false if the transformer does not apply to the request,
+	// and an error which wraps ErrInvalidParams if the parameters
+	// are not valid.
+	Params(contentType string, query url.Values) (Params, bool, error)
+	// Transform writes the derived content of the source to w and
+	// returns its content type.
+	Transform(ctx context.Context, src []byte, p Params, w io.Writer) (string, error)
+}
+
+// Result is the derived content.
+type Result struct {
+	// Key is the cache key of the content, which can be used as its ETag.
+	Key         string
+	ContentType string
+	Data        []byte
+}
+
+// Source returns the reader of the source content and its size.
+type Source func() (io.Reader, int64, error)
+
+// Service applies the first matching transformer to the content
+// and caches the results.
+type Service struct {
+	transformers  []Transformer
+	cache         Cache
+	maxSourceSize int64
+	group         singleflight.Group
+	metrics       metrics
+}
+
+// New returns a new Service with the transformers. The sources
+// larger than maxSourceSize are not transformed. The results are
+// not cached if the cache is nil.
+func New(cache Cache, maxSourceSize int64, transformers ...Transformer) *Service {
+	return &Service{
+		transformers:  transformers,
+		cache:         cache,
+		maxSourceSize: maxSourceSize,
+		metrics:       newMetrics(),
+	}
+}
+
+// Transform returns the derived content of the reference if a transformer
+// applies to the content type and the query, or nil otherwise.
+func (s *Service) Transform(ctx context.Context, reference swarm.Address, contentType string, query url.Values, src Source) (*Result, error) {
+	for _, t := range s.transformers {
+		p, ok, err := t.Params(contentType, query)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		return s.transform(ctx, reference, t, p, src)
+	}
+	return nil, nil
+}
+
+// Cached returns the cached derived content of the reference if a transformer
+// applies to the content type and the query, or nil otherwise. The content is
+// not transformed, ErrNotCached is returned if it was not transformed yet.
+func (s *Service) Cached(reference swarm.Address, contentType string, query url.Values) (*Result, error) {
+	for _, t := range s.transformers {
+		p, ok, err := t.Params(contentType, query)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if s.cache == nil {
+			return nil, ErrNotCached
+		}
+		return s.cache.Get(Key(reference, t.Name(), p))
+	}
+	return nil, nil
+}
+
+func (s *Service) transform(ctx context.Context, reference swarm.Address, t Transformer, p Params, src Source) (*Result, error) {
+	key := Key(reference, t.Name(), p)
+
+	if s.cache != nil {
+		res, err := s.cache.Get(key)
+		if err == nil {
+			s.metrics.CacheHits.WithLabelValues(t.Name()).Inc()
+			return res, nil
+		}
+		if !errors.Is(err, ErrNotCached) {
+			return nil, fmt.Errorf("cache get: %w", err)
+		}
+	}
+	s.metrics.CacheMisses.WithLabelValues(t.Name()).Inc()
+
+	// the concurrent requests for the same content are transformed once
+	v, err, _ := s.group.Do(key, func() (interface{}, error) {
+		r, size, err := src()
+		if err != nil {
+			return nil, err
+		}
+		if s.maxSourceSize > 0 && size > s.maxSourceSize {
+			return nil, ErrSourceTooLarge
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("read source: %w", err)
+		}
+
+		buf := new(bytes.Buffer)
+		contentType, err := t.Transform(ctx, data, p, buf)
+		if err != nil {
+			s.metrics.Errors.WithLabelValues(t.Name()).Inc()
+			return nil, err
+		}
+
+		res := &Result{Key: key, ContentType: contentType, Data: buf.Bytes()}
+		if s.cache != nil {
+			if err := s.cache.Put(res); err != nil {
+				return nil, fmt.Errorf("cache put: %w", err)
+			}
+		}
+		return res, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Result), nil
+}
+
+// Key returns the deterministic cache key of the content derived from
+// the reference by the named transformer with the parameters.
+func Key(reference swarm.Address, name string, p Params) string {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	q := make([]string, 0, len(keys))
+	for _, k := range keys {
+		q = append(q, url.QueryEscape(k)+"="+url.QueryEscape(p[k]))
+	}
+
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%s\n%s\n%s", keyVersion, reference, name, strings.Join(q, "&"))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,161 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/transform"
+)
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	ref := swarm.RandAddress(t)
+	k1 := transform.Key(ref, "image", transform.Params{"w": "320", "format": "png"})
+	k2 := transform.Key(ref, "image", transform.Params{"format": "png", "w": "320"})
+	if k1 != k2 {
+		t.Fatalf("keys of the same params differ: %s and %s", k1, k2)
+	}
+
+	for _, k := range []string{
+		transform.Key(ref, "image", transform.Params{"w": "321", "format": "png"}),
+		transform.Key(ref, "other", transform.Params{"w": "320", "format": "png"}),
+		transform.Key(swarm.RandAddress(t), "image", transform.Params{"w": "320", "format": "png"}),
+	} {
+		if k == k1 {
+			t.Fatalf("keys of the different transformations are equal: %s", k)
+		}
+	}
+}
+
+func TestService(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cache, err := transform.NewDiskCache(dir, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	upper := &upperTransformer{}
+	s := transform.New(cache, 100, upper)
+
+	ctx := context.Background()
+	ref := swarm.RandAddress(t)
+	src := func(data string) transform.Source {
+		return func() (io.Reader, int64, error) {
+			return strings.NewReader(data), int64(len(data)), nil
+		}
+	}
+
+	// not applicable
+	res, err := s.Transform(ctx, ref, "text/plain", url.Values{}, src("hello"))
+	if err != nil || res != nil {
+		t.Fatalf("got result %v and error %v, want none", res, err)
+	}
+
+	// invalid params
+	_, err = s.Transform(ctx, ref, "text/plain", url.Values{"upper": {"invalid"}}, src("hello"))
+	if !errors.Is(err, transform.ErrInvalidParams) {
+		t.Fatalf("got error %v, want %v", err, transform.ErrInvalidParams)
+	}
+
+	// too large
+	_, err = s.Transform(ctx, ref, "text/plain", url.Values{"upper": {"true"}}, src(strings.Repeat("a", 101)))
+	if !errors.Is(err, transform.ErrSourceTooLarge) {
+		t.Fatalf("got error %v, want %v", err, transform.ErrSourceTooLarge)
+	}
+
+	for i := 0; i < 2; i++ {
+		res, err = s.Transform(ctx, ref, "text/plain", url.Values{"upper": {"true"}}, src("hello"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(res.Data) != "HELLO" || res.ContentType != "text/upper" {
+			t.Fatalf("got %q of type %q, want %q of type %q", res.Data, res.ContentType, "HELLO", "text/upper")
+		}
+	}
+	if n := upper.calls.Load(); n != 1 {
+		t.Fatalf("got %d transformations, want 1", n)
+	}
+
+	// the results are kept in the directory
+	cache, err = transform.NewDiskCache(dir, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := cache.Get(res.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(cached.Data, res.Data) || cached.ContentType != res.ContentType {
+		t.Fatalf("got cached %q of type %q, want %q of type %q", cached.Data, cached.ContentType, res.Data, res.ContentType)
+	}
+}
+
+func TestDiskCacheEviction(t *testing.T) {
+	t.Parallel()
+
+	cache, err := transform.NewDiskCache(t.TempDir(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	put := func(key string) {
+		t.Helper()
+		err := cache.Put(&transform.Result{Key: key, ContentType: "a/b", Data: bytes.Repeat([]byte{1}, 40)})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	put("first")
+	put("second")
+	// the first is used more recently than the second
+	if _, err := cache.Get("first"); err != nil {
+		t.Fatal(err)
+	}
+	put("third")
+
+	if _, err := cache.Get("second"); !errors.Is(err, transform.ErrNotCached) {
+		t.Fatalf("got error %v, want %v", err, transform.ErrNotCached)
+	}
+	for _, key := range []string{"first", "third"} {
+		if _, err := cache.Get(key); err != nil {
+			t.Fatalf("get %s: %v", key, err)
+		}
+	}
+}
+
+// upperTransformer converts the text to upper case.
+type upperTransformer struct {
+	calls atomic.Int64
+}
+
+func (*upperTransformer) Name() string { return "upper" }
+
+func (*upperTransformer) Params(_ string, query url.Values) (transform.Params, bool, error) {
+	switch query.Get("upper") {
+	case "":
+		return nil, false, nil
+	case "true":
+		return transform.Params{"upper": "true"}, true, nil
+	default:
+		return nil, false, transform.ErrInvalidParams
+	}
+}
+
+func (u *upperTransformer) Transform(_ context.Context, src []byte, _ transform.Params, w io.Writer) (string, error) {
+	u.calls.Add(1)
+	_, err := w.Write(bytes.ToUpper(src))
+	return "text/upper", err
+}
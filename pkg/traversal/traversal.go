@@ -15,6 +15,7 @@ import (
 
 	"github.com/ethersphere/bee/pkg/file/joiner"
 	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/wrapper"
 	"github.com/ethersphere/bee/pkg/manifest"
 	"github.com/ethersphere/bee/pkg/manifest/mantaray"
 	"github.com/ethersphere/bee/pkg/soc"
@@ -92,5 +93,16 @@ func (s *service) Traverse(ctx context.Context, addr swarm.Address, iterFn swarm
 	if err := processBytes(addr); err != nil {
 		return fmt.Errorf("traversal: unable to process bytes for %q: %w", addr, err)
 	}
+
+	// The wrapped bytes are traversed along with their wrapper.
+	switch w, err := wrapper.Load(ctx, s.store, addr); {
+	case errors.Is(err, wrapper.ErrNotWrapper):
+	case err != nil:
+		return fmt.Errorf("traversal: unable to load wrapper %q: %w", addr, err)
+	default:
+		if err := processBytes(w.Reference); err != nil {
+			return fmt.Errorf("traversal: unable to process wrapped bytes for %q: %w", w.Reference, err)
+		}
+	}
 	return nil
 }
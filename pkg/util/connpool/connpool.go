@@ -0,0 +1,155 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package connpool provides a pool of the buffered TCP connections to the
+// servers with simple request-response protocols, such as the cache servers.
+package connpool
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const dialTimeout = 2 * time.Second
+
+// ErrClosed is returned by Do if the pool is closed.
+var ErrClosed = errors.New("connection pool closed")
+
+// Conn is a buffered connection to the server.
+type Conn struct {
+	net.Conn
+	R *bufio.Reader
+	W *bufio.Writer
+}
+
+// ReadLine reads a line terminated by CRLF without the terminator.
+func (c *Conn) ReadLine() (string, error) {
+	line, err := c.R.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
+
+// Pool keeps the idle connections to the server for reuse.
+type Pool struct {
+	addr    string
+	timeout time.Duration
+	// init is called on the new connections, for
+	// example to authenticate them to the server
+	init func(*Conn) error
+
+	mu     sync.Mutex
+	idle   []*Conn
+	size   int
+	closed bool
+}
+
+// New returns a new Pool which keeps at most size idle connections to the
+// server address. The timeout is the deadline of the operations, unless the
+// context has an earlier one. The optional init function is called on the
+// new connections before they are used.
+func New(addr string, size int, timeout time.Duration, init func(*Conn) error) *Pool {
+	return &Pool{
+		addr:    addr,
+		timeout: timeout,
+		init:    init,
+		size:    size,
+	}
+}
+
+// Do calls f with a connection which has the deadline of the context
+// or of the operation timeout. The connection is closed if f returns an
+// error, as the state of the protocol on the connection is unknown.
+func (p *Pool) Do(ctx context.Context, f func(*Conn) error) error {
+	c, err := p.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(p.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := c.SetDeadline(deadline); err != nil {
+		_ = c.Close()
+		return err
+	}
+
+	if err := f(c); err != nil {
+		_ = c.Close()
+		return err
+	}
+	p.put(c)
+	return nil
+}
+
+func (p *Pool) get(ctx context.Context) (*Conn, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrClosed
+	}
+	if n := len(p.idle); n > 0 {
+		c := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mu.Unlock()
+		return c, nil
+	}
+	p.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	var d net.Dialer
+	nc, err := d.DialContext(ctx, "tcp", p.addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &Conn{Conn: nc, R: bufio.NewReader(nc), W: bufio.NewWriter(nc)}
+	if p.init != nil {
+		if err := nc.SetDeadline(time.Now().Add(p.timeout)); err != nil {
+			_ = nc.Close()
+			return nil, err
+		}
+		if err := p.init(c); err != nil {
+			_ = nc.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (p *Pool) put(c *Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed || len(p.idle) >= p.size {
+		_ = c.Close()
+		return
+	}
+	p.idle = append(p.idle, c)
+}
+
+// Close closes the idle connections and the connections
+// which are returned to the pool afterwards.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	var errs []error
+	for _, c := range p.idle {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	p.idle = nil
+	return errors.Join(errs...)
+}
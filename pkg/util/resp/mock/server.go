@@ -0,0 +1,239 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package mock provides an in-memory server which speaks the subset of
+// the Redis serialization protocol used by the resp client.
+package mock
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Server is an in-memory Redis server.
+type Server struct {
+	listener net.Listener
+	password string
+
+	mu     sync.Mutex
+	values map[string][]byte
+	wg     sync.WaitGroup
+}
+
+// Option is the option of the Server.
+type Option func(*Server)
+
+// WithPassword requires the clients to authenticate with the password.
+func WithPassword(password string) Option {
+	return func(s *Server) {
+		s.password = password
+	}
+}
+
+// NewServer starts a new Server on a random local port.
+func NewServer(opts ...Option) (*Server, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		listener: l,
+		values:   make(map[string][]byte),
+	}
+	for _, o := range opts {
+		o(s)
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+// Addr returns the address of the server.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Get returns the value of the key.
+func (s *Server) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Set sets the value of the key.
+func (s *Server) Set(key string, value []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+}
+
+// Close stops the server and waits for the connections to be closed.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer c.Close()
+
+			rw := bufio.NewReadWriter(bufio.NewReader(c), bufio.NewWriter(c))
+			authenticated := s.password == ""
+			for {
+				args, err := readCommand(rw.Reader)
+				if err != nil {
+					return
+				}
+				s.handle(rw.Writer, args, &authenticated)
+				if err := rw.Flush(); err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+func (s *Server) handle(w *bufio.Writer, args []string, authenticated *bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cmd := strings.ToUpper(args[0])
+	if cmd == "AUTH" {
+		if len(args) != 2 || args[1] != s.password {
+			_, _ = w.WriteString("-WRONGPASS invalid password\r\n")
+			return
+		}
+		*authenticated = true
+		_, _ = w.WriteString("+OK\r\n")
+		return
+	}
+	if !*authenticated {
+		_, _ = w.WriteString("-NOAUTH Authentication required\r\n")
+		return
+	}
+
+	switch {
+	case cmd == "SELECT" && len(args) == 2:
+		_, _ = w.WriteString("+OK\r\n")
+	case cmd == "GET" && len(args) == 2:
+		if v, ok := s.values[args[1]]; ok {
+			writeBulk(w, v)
+		} else {
+			_, _ = w.WriteString("$-1\r\n")
+		}
+	case cmd == "SET" && len(args) >= 3:
+		s.values[args[1]] = []byte(args[2])
+		_, _ = w.WriteString("+OK\r\n")
+	case cmd == "DEL" && len(args) >= 2:
+		n := 0
+		for _, k := range args[1:] {
+			if _, ok := s.values[k]; ok {
+				delete(s.values, k)
+				n++
+			}
+		}
+		_, _ = fmt.Fprintf(w, ":%d\r\n", n)
+	case cmd == "SCAN" && len(args) >= 2:
+		// all keys are returned at once with the final cursor
+		match := "*"
+		for i := 2; i+1 < len(args); i += 2 {
+			if strings.ToUpper(args[i]) == "MATCH" {
+				match = args[i+1]
+			}
+		}
+		var keys []string
+		for k := range s.values {
+			if matchPattern(match, k) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		_, _ = fmt.Fprintf(w, "*2\r\n$1\r\n0\r\n*%d\r\n", len(keys))
+		for _, k := range keys {
+			writeBulk(w, []byte(k))
+		}
+	default:
+		_, _ = fmt.Fprintf(w, "-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+// matchPattern matches the key against the pattern, which supports the
+// escaped characters and the trailing asterisk only.
+func matchPattern(pattern, key string) bool {
+	var prefix strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			prefix.WriteByte(pattern[i])
+		case c == '*' && i == len(pattern)-1:
+			return strings.HasPrefix(key, prefix.String())
+		default:
+			prefix.WriteByte(c)
+		}
+	}
+	return key == prefix.String()
+}
+
+func writeBulk(w *bufio.Writer, v []byte) {
+	_, _ = fmt.Fprintf(w, "$%d\r\n", len(v))
+	_, _ = w.Write(v)
+	_, _ = w.WriteString("\r\n")
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	n, err := readLength(r, '*')
+	if err != nil {
+		return nil, err
+	}
+	if n < 1 {
+		return nil, fmt.Errorf("invalid command length %d", n)
+	}
+	args := make([]string, n)
+	for i := range args {
+		l, err := readLength(r, '$')
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, l+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:l])
+	}
+	return args, nil
+}
+
+func readLength(r *bufio.Reader, prefix byte) (int, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return 0, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" || line[0] != prefix {
+		return 0, fmt.Errorf("unexpected line %q", line)
+	}
+	return strconv.Atoi(line[1:])
+}
@@ -0,0 +1,248 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package resp provides a minimal client of the Redis
+// serialization protocol, which is spoken by Redis and
+// the compatible servers.
+package resp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/util/connpool"
+)
+
+// ErrNil is returned if the server replies with the nil value,
+// for example when the requested key does not exist.
+var ErrNil = errors.New("resp: nil reply")
+
+// Error is the error reply of the server.
+type Error string
+
+func (e Error) Error() string {
+	return "resp: " + string(e)
+}
+
+// Client sends the commands to the server over the pooled connections.
+type Client struct {
+	pool *connpool.Pool
+}
+
+// New returns a new Client for the redis://[:password@]host:port[/db] URL.
+func New(u *url.URL, poolSize int, timeout time.Duration) (*Client, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("url %q has no host", u.String())
+	}
+
+	var db int
+	if p := strings.Trim(u.Path, "/"); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis database %q", p)
+		}
+		db = n
+	}
+	password, auth := u.User.Password()
+
+	return &Client{
+		pool: connpool.New(u.Host, poolSize, timeout, func(c *connpool.Conn) error {
+			if auth {
+				if err := expectOK(c, "AUTH", password); err != nil {
+					return fmt.Errorf("auth: %w", err)
+				}
+			}
+			if db != 0 {
+				if err := expectOK(c, "SELECT", strconv.Itoa(db)); err != nil {
+					return fmt.Errorf("select: %w", err)
+				}
+			}
+			return nil
+		}),
+	}, nil
+}
+
+// Do sends the command and returns the reply, which is a []byte for the
+// strings, an int64 for the integers and an []interface{} for the arrays.
+// ErrNil is returned for the nil reply and Error for the error reply.
+func (c *Client) Do(ctx context.Context, args ...string) (interface{}, error) {
+	var reply interface{}
+	err := c.pool.Do(ctx, func(conn *connpool.Conn) (err error) {
+		reply, err = do(conn, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, ErrNil
+	}
+	if e, ok := reply.(Error); ok {
+		return nil, e
+	}
+	return reply, nil
+}
+
+// Get returns the value of the key or ErrNil if the key does not exist.
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	r, err := c.Do(ctx, "GET", key)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := r.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("resp: unexpected reply %T", r)
+	}
+	return v, nil
+}
+
+// Set sets the value of the key. The key expires after the ttl,
+// if the ttl is not zero.
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", key, string(value)}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := c.Do(ctx, args...)
+	return err
+}
+
+// Del deletes the key.
+func (c *Client) Del(ctx context.Context, key string) error {
+	_, err := c.Do(ctx, "DEL", key)
+	return err
+}
+
+// Scan calls fn with the batches of the keys which match the pattern until
+// all keys are scanned or fn returns an error. The keys may be returned more
+// than once if the keyspace changes during the scan.
+func (c *Client) Scan(ctx context.Context, match string, fn func(keys []string) error) error {
+	cursor := "0"
+	for {
+		r, err := c.Do(ctx, "SCAN", cursor, "MATCH", match, "COUNT", "1000")
+		if err != nil {
+			return err
+		}
+		a, ok := r.([]interface{})
+		if !ok || len(a) != 2 {
+			return fmt.Errorf("resp: unexpected scan reply %v", r)
+		}
+		next, ok := a[0].([]byte)
+		if !ok {
+			return fmt.Errorf("resp: unexpected scan cursor %v", a[0])
+		}
+		items, ok := a[1].([]interface{})
+		if !ok {
+			return fmt.Errorf("resp: unexpected scan keys %v", a[1])
+		}
+		keys := make([]string, 0, len(items))
+		for _, item := range items {
+			k, ok := item.([]byte)
+			if !ok {
+				return fmt.Errorf("resp: unexpected scan key %v", item)
+			}
+			keys = append(keys, string(k))
+		}
+		if err := fn(keys); err != nil {
+			return err
+		}
+
+		cursor = string(next)
+		if cursor == "0" {
+			return nil
+		}
+	}
+}
+
+// Close closes the connections to the server.
+func (c *Client) Close() error {
+	return c.pool.Close()
+}
+
+func do(c *connpool.Conn, args ...string) (interface{}, error) {
+	if _, err := fmt.Fprintf(c.W, "*%d\r\n", len(args)); err != nil {
+		return nil, err
+	}
+	for _, a := range args {
+		if _, err := fmt.Fprintf(c.W, "$%d\r\n%s\r\n", len(a), a); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.W.Flush(); err != nil {
+		return nil, err
+	}
+	return read(c)
+}
+
+func expectOK(c *connpool.Conn, args ...string) error {
+	r, err := do(c, args...)
+	if err != nil {
+		return err
+	}
+	if e, ok := r.(Error); ok {
+		return e
+	}
+	return nil
+}
+
+func read(c *connpool.Conn) (interface{}, error) {
+	line, err := c.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return nil, errors.New("resp: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return []byte(line[1:]), nil
+	case '-':
+		// the error reply is not a connection error, so
+		// it is returned as a value to keep the connection
+		return Error(line[1:]), nil
+	case ':':
+		n, err := strconv.ParseInt(line[1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("resp: invalid integer %q", line[1:])
+		}
+		return n, nil
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("resp: invalid bulk length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		v := make([]byte, n+2)
+		if _, err := io.ReadFull(c.R, v); err != nil {
+			return nil, err
+		}
+		return v[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("resp: invalid array length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		a := make([]interface{}, n)
+		for i := range a {
+			if a[i], err = read(c); err != nil {
+				return nil, err
+			}
+		}
+		return a, nil
+	default:
+		return nil, fmt.Errorf("resp: unexpected reply %q", line)
+	}
+}
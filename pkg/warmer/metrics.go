@@ -0,0 +1,32 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package warmer
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	RetrievedChunks prometheus.Counter
+}
+
+func newMetrics() metrics {
+	subsystem := "warmer"
+
+	return metrics{
+		RetrievedChunks: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "retrieved_chunks_total",
+			Help:      "Total number of chunks retrieved by the warm jobs.",
+		}),
+	}
+}
+
+// Metrics returns the prometheus collectors of the service.
+func (s *Service) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(s.metrics)
+}
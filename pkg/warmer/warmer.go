@@ -0,0 +1,325 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package warmer retrieves the chunks of the content into the local
+// store ahead of the requests, so that the gateways can pre-warm the
+// content before the announced traffic spikes.
+package warmer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/jobs"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/manifest/mantaray"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/traversal"
+	"golang.org/x/sync/errgroup"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "warmer"
+
+// JobKind is the kind of the warm jobs.
+const JobKind = "warm"
+
+const (
+	parallelRetrieve = 8 // how many chunks of a job are retrieved in parallel
+	maxRunningJobs   = 4 // how many jobs can run at the same time
+)
+
+var (
+	// ErrJobNotFound is returned by Job if there is no job with the given id.
+	ErrJobNotFound = errors.New("warm job not found")
+	// ErrTooManyJobs is returned by Warm if the maximal
+	// number of jobs are already running.
+	ErrTooManyJobs = errors.New("too many running warm jobs")
+
+	errSizeLimit = errors.New("size limit reached")
+)
+
+// Status is the status of a warm job.
+type Status = jobs.Status
+
+const (
+	StatusRunning   = jobs.StatusRunning
+	StatusDone      = jobs.StatusDone
+	StatusFailed    = jobs.StatusFailed
+	StatusCancelled = jobs.StatusCancelled
+)
+
+// Limits bound the content which is retrieved by a job.
+type Limits struct {
+	// Depth is the maximal number of the path segments of the manifest
+	// entries whose content is retrieved. All manifest entries are
+	// retrieved if it is zero, and for the references which are not
+	// manifests it has no effect.
+	Depth int `json:"depth"`
+	// Size is the maximal number of bytes of the chunk
+	// data retrieved, unlimited if it is zero.
+	Size int64 `json:"size"`
+}
+
+// Job is the state of a warm job.
+type Job struct {
+	ID        uint64
+	Reference swarm.Address
+	Limits    Limits
+	Status    Status
+	Chunks    int64
+	Size      int64
+	// Truncated is true if the job stopped on the size limit.
+	Truncated  bool
+	Error      string
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// params are the parameters of the warm job.
+type params struct {
+	Reference swarm.Address `json:"reference"`
+	Limits    Limits        `json:"limits"`
+}
+
+// state is the state of the warm job.
+type state struct {
+	Chunks    int64 `json:"chunks"`
+	Size      int64 `json:"size"`
+	Truncated bool  `json:"truncated"`
+}
+
+// Service runs the warm jobs.
+type Service struct {
+	getter    traversal.PutGetter
+	traverser traversal.Traverser
+	jobs      *jobs.Manager
+	logger    log.Logger
+	metrics   metrics
+}
+
+// New returns a new Service which retrieves the chunks with the getter,
+// which is expected to store the retrieved chunks locally. The warm jobs
+// are run by the jobs manager.
+func New(getter traversal.PutGetter, traverser traversal.Traverser, manager *jobs.Manager, logger log.Logger) *Service {
+	s := &Service{
+		getter:    getter,
+		traverser: traverser,
+		jobs:      manager,
+		logger:    logger.WithName(loggerName).Register(),
+		metrics:   newMetrics(),
+	}
+	manager.Register(JobKind, maxRunningJobs, s.run)
+	return s
+}
+
+// Warm starts the job which retrieves all chunks of the root reference
+// within the limits. It returns the initial state of the job.
+func (s *Service) Warm(root swarm.Address, limits Limits) (Job, error) {
+	j, err := s.jobs.Submit(JobKind, params{Reference: root, Limits: limits})
+	if err != nil {
+		if errors.Is(err, jobs.ErrTooManyJobs) {
+			return Job{}, ErrTooManyJobs
+		}
+		return Job{}, err
+	}
+	return newJob(j)
+}
+
+// Job returns the state of the job with the given id.
+func (s *Service) Job(id uint64) (Job, error) {
+	j, err := s.jobs.Job(id)
+	if errors.Is(err, jobs.ErrNotFound) || (err == nil && j.Kind != JobKind) {
+		return Job{}, ErrJobNotFound
+	}
+	if err != nil {
+		return Job{}, err
+	}
+	return newJob(j)
+}
+
+// run runs the warm job. The resumed job starts the traversal from the
+// beginning, as the chunks which are already retrieved are found locally.
+func (s *Service) run(ctx context.Context, t *jobs.Task) error {
+	var p params
+	if err := t.Params(&p); err != nil {
+		return fmt.Errorf("params: %w", err)
+	}
+
+	var st state
+	err := s.warm(ctx, t, p, &st)
+	if errors.Is(err, errSizeLimit) {
+		st.Truncated = true
+		err = nil
+	}
+	if serr := t.SetState(st); serr != nil && err == nil {
+		err = serr
+	}
+	if err != nil && ctx.Err() == nil {
+		s.logger.Debug("warm job failed", "id", t.ID(), "reference", p.Reference, "error", err)
+	}
+	return err
+}
+
+// warm retrieves the chunks of the job in parallel.
+func (s *Service) warm(ctx context.Context, t *jobs.Task, p params, st *state) error {
+	var (
+		sem      = make(chan struct{}, parallelRetrieve)
+		eg, ectx = errgroup.WithContext(ctx)
+		mu       sync.Mutex
+		seen     = make(map[string]struct{})
+	)
+
+	fn := func(addr swarm.Address) error {
+		// the same chunk can be referenced by multiple files
+		mu.Lock()
+		_, ok := seen[addr.ByteString()]
+		seen[addr.ByteString()] = struct{}{}
+		mu.Unlock()
+		if ok {
+			return nil
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ectx.Done():
+			return ectx.Err()
+		}
+		eg.Go(func() error {
+			defer func() { <-sem }()
+
+			ch, err := s.getter.Get(ectx, storage.ModeGetRequest, addr)
+			if err != nil {
+				return fmt.Errorf("retrieve %s: %w", addr, err)
+			}
+			s.metrics.RetrievedChunks.Inc()
+
+			mu.Lock()
+			st.Chunks++
+			st.Size += int64(len(ch.Data()))
+			size := st.Size
+			t.Progress(st.Chunks, 0)
+			err = t.SetState(st)
+			mu.Unlock()
+			if err != nil {
+				return err
+			}
+
+			if p.Limits.Size > 0 && size >= p.Limits.Size {
+				return errSizeLimit
+			}
+			return nil
+		})
+		return nil
+	}
+
+	var err error
+	if p.Limits.Depth > 0 {
+		err = s.traverseDepth(ectx, p.Reference, p.Limits.Depth, fn)
+	} else {
+		err = s.traverser.Traverse(ectx, p.Reference, fn)
+	}
+
+	// the error of the retrieval is the cause of the failed traversal
+	if werr := eg.Wait(); werr != nil {
+		return werr
+	}
+	return err
+}
+
+// traverseDepth iterates the addresses of the root reference like the
+// traversal.Traverser, but it skips the content of the manifest entries
+// which have more path segments than the depth.
+func (s *Service) traverseDepth(ctx context.Context, root swarm.Address, depth int, fn swarm.AddressIterFunc) error {
+	processBytes := func(ref swarm.Address) error {
+		j, _, err := joiner.New(ctx, s.getter, ref)
+		if err != nil {
+			return fmt.Errorf("joiner error on %q: %w", ref, err)
+		}
+		return j.IterateChunkAddresses(fn)
+	}
+
+	// skip SOC check for encrypted references
+	if root.IsValidLength() {
+		ch, err := s.getter.Get(ctx, storage.ModeGetRequest, root)
+		if err != nil {
+			return fmt.Errorf("get root chunk %s: %w", root, err)
+		}
+		if soc.Valid(ch) {
+			return fn(root)
+		}
+	}
+
+	emptyAddr := swarm.NewAddress([]byte{31: 0})
+	walker := func(path []byte, node *mantaray.Node, err error) error {
+		if err != nil {
+			return err
+		}
+		if node == nil {
+			return nil
+		}
+
+		if ref := node.Reference(); ref != nil {
+			if err := fn(swarm.NewAddress(ref)); err != nil {
+				return err
+			}
+		}
+
+		if node.IsValueType() && len(node.Entry()) > 0 && pathDepth(path) <= depth {
+			entry := swarm.NewAddress(node.Entry())
+			if entry.Equal(emptyAddr) {
+				return nil
+			}
+			return processBytes(entry)
+		}
+		return nil
+	}
+
+	ls := loadsave.NewReadonly(s.getter)
+	err := mantaray.NewNodeRef(root.Bytes()).WalkNode(ctx, []byte{}, ls, walker)
+	if errors.Is(err, mantaray.ErrTooShort) || errors.Is(err, mantaray.ErrInvalidVersionHash) {
+		// not a manifest
+		return processBytes(root)
+	}
+	return err
+}
+
+// pathDepth returns the number of the segments of the manifest path.
+func pathDepth(path []byte) int {
+	return strings.Count(strings.Trim(string(path), "/"), "/") + 1
+}
+
+func newJob(j jobs.Job) (Job, error) {
+	var p params
+	if err := json.Unmarshal(j.Params, &p); err != nil {
+		return Job{}, fmt.Errorf("params: %w", err)
+	}
+	var st state
+	if len(j.State) > 0 {
+		if err := json.Unmarshal(j.State, &st); err != nil {
+			return Job{}, fmt.Errorf("state: %w", err)
+		}
+	}
+	return Job{
+		ID:         j.ID,
+		Reference:  p.Reference,
+		Limits:     p.Limits,
+		Status:     j.Status,
+		Chunks:     st.Chunks,
+		Size:       st.Size,
+		Truncated:  st.Truncated,
+		Error:      j.Error,
+		StartedAt:  j.StartedAt,
+		FinishedAt: j.FinishedAt,
+	}, nil
+}
@@ -0,0 +1,249 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package warmer_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/jobs"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/manifest"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/traversal"
+	"github.com/ethersphere/bee/pkg/util/testutil"
+	"github.com/ethersphere/bee/pkg/warmer"
+)
+
+func TestWarm(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := mock.NewStorer()
+	addr := upload(t, store, testutil.RandBytes(t, 100*swarm.ChunkSize))
+
+	t.Run("all", func(t *testing.T) {
+		t.Parallel()
+
+		getter := newLoggingGetter(store)
+		s := newService(t, getter)
+
+		job, err := s.Warm(addr, warmer.Limits{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		job = waitJob(t, s, job.ID)
+
+		if job.Status != warmer.StatusDone {
+			t.Fatalf("got status %q, want %q", job.Status, warmer.StatusDone)
+		}
+		if job.Truncated {
+			t.Fatal("job should not be truncated")
+		}
+
+		var want []swarm.Address
+		err = traversal.New(store).Traverse(ctx, addr, func(a swarm.Address) error {
+			want = append(want, a)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Chunks != int64(len(want)) {
+			t.Fatalf("got %d chunks, want %d", job.Chunks, len(want))
+		}
+		for _, a := range want {
+			if !getter.has(a) {
+				t.Fatalf("chunk %s not retrieved", a)
+			}
+		}
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		t.Parallel()
+
+		s := newService(t, newLoggingGetter(store))
+
+		job, err := s.Warm(addr, warmer.Limits{Size: 10 * swarm.ChunkSize})
+		if err != nil {
+			t.Fatal(err)
+		}
+		job = waitJob(t, s, job.ID)
+
+		if job.Status != warmer.StatusDone {
+			t.Fatalf("got status %q, want %q", job.Status, warmer.StatusDone)
+		}
+		if !job.Truncated {
+			t.Fatal("job should be truncated")
+		}
+		if job.Size < 10*swarm.ChunkSize || job.Chunks >= 100 {
+			t.Fatalf("got %d chunks of %d bytes", job.Chunks, job.Size)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		s := newService(t, newLoggingGetter(store))
+
+		job, err := s.Warm(swarm.RandAddress(t), warmer.Limits{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		job = waitJob(t, s, job.ID)
+
+		if job.Status != warmer.StatusFailed {
+			t.Fatalf("got status %q, want %q", job.Status, warmer.StatusFailed)
+		}
+		if job.Error == "" {
+			t.Fatal("failed job has no error")
+		}
+
+		if _, err := s.Job(job.ID + 1); !errors.Is(err, warmer.ErrJobNotFound) {
+			t.Fatalf("got error %v, want %v", err, warmer.ErrJobNotFound)
+		}
+	})
+}
+
+func TestWarmDepth(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := mock.NewStorer()
+
+	top := upload(t, store, testutil.RandBytes(t, 3*swarm.ChunkSize))
+	nested := upload(t, store, testutil.RandBytes(t, 3*swarm.ChunkSize))
+
+	ls := loadsave.New(store, func() pipeline.Interface {
+		return builder.NewPipelineBuilder(ctx, store, storage.ModePutRequest, false)
+	})
+	m, err := manifest.NewDefaultManifest(ls, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add(ctx, "index.html", manifest.NewEntry(top, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add(ctx, "assets/img/logo.png", manifest.NewEntry(nested, nil)); err != nil {
+		t.Fatal(err)
+	}
+	addr, err := m.Store(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		depth      int
+		wantNested bool
+	}{
+		{name: "unlimited", depth: 0, wantNested: true},
+		{name: "top level", depth: 1, wantNested: false},
+		{name: "deep enough", depth: 3, wantNested: true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			getter := newLoggingGetter(store)
+			s := newService(t, getter)
+
+			job, err := s.Warm(addr, warmer.Limits{Depth: tc.depth})
+			if err != nil {
+				t.Fatal(err)
+			}
+			job = waitJob(t, s, job.ID)
+			if job.Status != warmer.StatusDone {
+				t.Fatalf("got status %q (%s), want %q", job.Status, job.Error, warmer.StatusDone)
+			}
+
+			if !getter.has(top) {
+				t.Fatal("top level file not retrieved")
+			}
+			if got := getter.has(nested); got != tc.wantNested {
+				t.Fatalf("nested file retrieved %t, want %t", got, tc.wantNested)
+			}
+		})
+	}
+}
+
+func newService(t *testing.T, getter traversal.PutGetter) *warmer.Service {
+	t.Helper()
+
+	m, err := jobs.New(statestore.NewStateStore(), log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := m.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return warmer.New(getter, traversal.New(getter), m, log.Noop)
+}
+
+func upload(t *testing.T, store storage.Storer, data []byte) swarm.Address {
+	t.Helper()
+
+	ctx := context.Background()
+	pipe := builder.NewPipelineBuilder(ctx, store, storage.ModePutUpload, false)
+	addr, err := builder.FeedPipeline(ctx, pipe, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
+
+func waitJob(t *testing.T, s *warmer.Service, id uint64) warmer.Job {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		job, err := s.Job(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Status != warmer.StatusRunning {
+			return job
+		}
+	}
+	t.Fatal("timed out waiting for the job")
+	return warmer.Job{}
+}
+
+// loggingGetter records the addresses of the retrieved chunks.
+type loggingGetter struct {
+	storage.Storer
+
+	mu    sync.Mutex
+	addrs map[string]struct{}
+}
+
+func newLoggingGetter(s storage.Storer) *loggingGetter {
+	return &loggingGetter{Storer: s, addrs: make(map[string]struct{})}
+}
+
+func (g *loggingGetter) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	g.mu.Lock()
+	g.addrs[addr.ByteString()] = struct{}{}
+	g.mu.Unlock()
+	return g.Storer.Get(ctx, mode, addr)
+}
+
+func (g *loggingGetter) has(addr swarm.Address) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, ok := g.addrs[addr.ByteString()]
+	return ok
+}